@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"innominatus/internal/cli"
 	clientpkg "innominatus/internal/client"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -16,6 +18,8 @@ var (
 	watchVerbose bool
 	watchAll     bool
 	timeout      time.Duration
+	deployEnv    string
+	overlayFile  string
 )
 
 var deployCmd = &cobra.Command{
@@ -38,6 +42,9 @@ Examples:
 
   # Deploy with custom timeout
   innominatus-ctl deploy myapp.yaml -w --timeout 10m
+
+  # Deploy with the overlay overlays/prod/myapp.yaml next to myapp.yaml
+  innominatus-ctl deploy myapp.yaml --env prod
 `,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -56,9 +63,24 @@ Examples:
 			return fmt.Errorf("failed to extract app name from spec: %w", err)
 		}
 
+		overlayPath := overlayFile
+		if overlayPath == "" && deployEnv != "" {
+			overlayPath = filepath.Join(filepath.Dir(specFile), "overlays", deployEnv, filepath.Base(specFile))
+		}
+
 		// Submit spec to server
-		fmt.Printf("📤 Submitting Score specification: %s\n", appName)
-		err = client.DeploySpec(specData)
+		if overlayPath == "" {
+			fmt.Printf("📤 Submitting Score specification: %s\n", appName)
+			err = client.DeploySpec(specData)
+		} else {
+			// #nosec G304 - overlayPath is derived from user-provided CLI arguments (expected behavior)
+			overlayData, readErr := os.ReadFile(overlayPath)
+			if readErr != nil {
+				return fmt.Errorf("failed to read overlay: %w", readErr)
+			}
+			fmt.Printf("📤 Submitting Score specification: %s (overlay %s)\n", appName, overlayPath)
+			_, err = client.DeployWithOverlay(specData, overlayData)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to deploy spec: %w", err)
 		}
@@ -81,6 +103,8 @@ func init() {
 	deployCmd.Flags().BoolVar(&watchVerbose, "verbose", false, "Show verbose event details")
 	deployCmd.Flags().BoolVar(&watchAll, "all", false, "Show all events (including internal)")
 	deployCmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute, "Deployment timeout")
+	deployCmd.Flags().StringVar(&deployEnv, "env", "", "Merge the environment overlay overlays/<env>/<score-file> into the spec")
+	deployCmd.Flags().StringVar(&overlayFile, "overlay", "", "Merge this overlay file into the spec (instead of --env)")
 	rootCmd.AddCommand(deployCmd)
 }
 
@@ -184,11 +208,11 @@ func extractAppName(specData []byte) (string, error) {
 	}
 
 	if err := yaml.Unmarshal(specData, &spec); err != nil {
-		return "", fmt.Errorf("failed to parse spec YAML: %w", err)
+		return "", cli.WithExitCode(cli.ExitValidation, fmt.Errorf("failed to parse spec YAML: %w", err))
 	}
 
 	if spec.Metadata.Name == "" {
-		return "", fmt.Errorf("spec metadata.name is required")
+		return "", cli.WithExitCode(cli.ExitValidation, fmt.Errorf("spec metadata.name is required"))
 	}
 
 	return spec.Metadata.Name, nil
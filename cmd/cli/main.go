@@ -1,14 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"innominatus/internal/cli"
 	"innominatus/internal/users"
 	"innominatus/internal/validation"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // NOTE: This file contains numerous fmt.Println/Printf statements that are INTENTIONAL
@@ -16,12 +19,24 @@ import (
 // structured logging. They provide the interactive UX for the CLI commands and are
 // designed for human-readable terminal output.
 
+// Build information - set via ldflags during release builds
 var (
-	serverURL      string
-	details        bool
-	skipValidation bool
-	outputFormat   string
-	client         *cli.Client
+	version = "dev"
+	commit  = "unknown"
+)
+
+var (
+	serverURL        string
+	details          bool
+	skipValidation   bool
+	skipVersionCheck bool
+	outputFormat     string
+	quiet            bool
+	noKeyRotation    bool
+	client           *cli.Client
+
+	// commandStarted is set once cobra accepted the command line; errors before are usage errors
+	commandStarted bool
 )
 
 // Commands that don't require server authentication
@@ -33,6 +48,9 @@ var localCommands = map[string]bool{
 	"demo-nuke":       true,
 	"demo-status":     true,
 	"demo-reset":      true,
+	"doctor":          true, // checks credentials itself instead of prompting for login
+	"test":            true, // provider test checks a local directory
+	"version":         true,
 	"fix-gitea-oauth": true,
 	"login":           true,
 	"logout":          true,
@@ -46,12 +64,27 @@ var localCommands = map[string]bool{
 }
 
 var rootCmd = &cobra.Command{
-	Use:   "innominatus-ctl",
-	Short: "Open Alps CLI",
-	Long:  `Command-line interface for the Open Alps Score-based Platform Orchestration system.`,
+	Use:           "innominatus-ctl",
+	Short:         "Open Alps CLI",
+	Long:          `Command-line interface for the Open Alps Score-based Platform Orchestration system.`,
+	SilenceErrors: true, // Printed by main, with the exit code of the error class
+	SilenceUsage:  true, // Printed by main for usage errors only
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Cobra checks required flags only after this hook
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return err
+		}
+		commandStarted = true
+
+		// Quiet mode prints only results, as JSON unless another format is requested
+		if quiet && !cmd.Flags().Changed("output") {
+			outputFormat = "json"
+		}
+		cli.SetQuiet(quiet)
+
 		// Initialize client with server URL
 		client = cli.NewClient(serverURL)
+		client.SetCLIVersion(version)
 
 		// Set output format
 		switch outputFormat {
@@ -79,17 +112,35 @@ var rootCmd = &cobra.Command{
 		if !skipValidation {
 			summary := validation.ValidateWithMode(validation.ValidationModeFast)
 			if !summary.Valid {
-				fmt.Printf("❌ Configuration validation failed. Run with --skip-validation to bypass.\n")
-				summary.PrintSummary()
-				os.Exit(1)
+				if !quiet {
+					summary.PrintSummary()
+				}
+				return cli.WithExitCode(cli.ExitValidation, fmt.Errorf("configuration validation failed; run with --skip-validation to bypass"))
 			}
 			if summary.WarningCount > 0 && outputFormat != "json" && outputFormat != "yaml" {
 				fmt.Printf("⚠️  Configuration warnings detected (%d warnings)\n", summary.WarningCount)
 			}
 		}
 
+		// Refuse to talk to a server that no longer supports this CLI version
+		if !skipVersionCheck {
+			if err := client.CheckServerCompatibility(); err != nil {
+				return cli.WithExitCode(cli.ExitIncompatible, err)
+			}
+		}
+
 		// Check if API key is already set
 		if client.HasToken() {
+			// Replace a stored API key before it expires, e.g. on a CI runner
+			if !noKeyRotation {
+				rotated, err := client.RotateCredentials()
+				if err != nil && !quiet {
+					fmt.Fprintf(os.Stderr, "⚠️  API key rotation failed: %v\n", err)
+				}
+				if rotated != nil && outputFormat != "json" && outputFormat != "yaml" {
+					fmt.Printf("✓ Rotated API key, new key '%s' expires %s\n", rotated.KeyName, rotated.ExpiresAt.Format("2006-01-02"))
+				}
+			}
 			if outputFormat != "json" && outputFormat != "yaml" {
 				if os.Getenv("IDP_API_KEY") != "" {
 					fmt.Printf("✓ Using API key from environment variable\n")
@@ -100,10 +151,15 @@ var rootCmd = &cobra.Command{
 			return nil
 		}
 
+		// Scripts must not hang on a prompt
+		if quiet {
+			return cli.WithExitCode(cli.ExitAuth, fmt.Errorf("not logged in; set IDP_API_KEY or run 'innominatus-ctl login'"))
+		}
+
 		// Prompt for login for server commands
 		user, err := users.PromptLogin()
 		if err != nil {
-			return fmt.Errorf("authentication failed: %w", err)
+			return cli.WithExitCode(cli.ExitAuth, fmt.Errorf("authentication failed: %w", err))
 		}
 
 		// Login to server
@@ -112,6 +168,16 @@ var rootCmd = &cobra.Command{
 			return fmt.Errorf("server authentication failed: %w", err)
 		}
 
+		// Replace expired stored credentials, so the next command needs no login
+		if !noKeyRotation {
+			renewed, err := client.RenewCredentials(user.Username)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Failed to renew stored API key: %v\n", err)
+			} else if renewed != nil && outputFormat != "json" && outputFormat != "yaml" {
+				fmt.Printf("✓ Replaced expired API key, new key '%s' expires %s\n", renewed.KeyName, renewed.ExpiresAt.Format("2006-01-02"))
+			}
+		}
+
 		return nil
 	},
 }
@@ -121,7 +187,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&serverURL, "server", "http://localhost:8081", "Score orchestrator server URL")
 	rootCmd.PersistentFlags().BoolVar(&details, "details", false, "Show detailed information including URLs and workflow links")
 	rootCmd.PersistentFlags().BoolVar(&skipValidation, "skip-validation", false, "Skip configuration validation")
+	rootCmd.PersistentFlags().BoolVar(&skipVersionCheck, "skip-version-check", false, "Skip the CLI/server version compatibility check")
 	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, or yaml")
+	rootCmd.PersistentFlags().BoolVar(&noKeyRotation, "no-key-rotation", false, "Don't replace a stored API key that expires within 14 days")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Print only machine-readable results (JSON unless --output is set); errors are printed as JSON on stderr")
 }
 
 // Basic commands
@@ -145,14 +214,34 @@ var statusCmd = &cobra.Command{
 var (
 	validateExplain bool
 	validateFormat  string
+	validateLint    bool
+	validateProfile string
 )
 
 var validateCmd = &cobra.Command{
 	Use:   "validate <score-spec.yaml>",
 	Short: "Validate Score spec locally",
 	Args:  cobra.ExactArgs(1),
+	Long: `Validate a Score spec locally.
+
+With --lint the spec is also checked by the server: the checks a deployment runs and the
+organisation's lint rules (naming conventions, required labels, forbidden images, allowed
+resource types). The command fails when the spec would be rejected, so CI can gate merges.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.ValidateCommand(args[0], validateExplain, validateFormat)
+		if validateProfile != "" {
+			validateLint = true
+		}
+		if validateLint && validateFormat == "json" {
+			// Keep the output a single JSON document; the server runs the local checks too
+			return client.LintSpecCommand(args[0], validateProfile, validateFormat)
+		}
+		if err := client.ValidateCommand(args[0], validateExplain, validateFormat); err != nil {
+			return err
+		}
+		if validateLint {
+			return client.LintSpecCommand(args[0], validateProfile, validateFormat)
+		}
+		return nil
 	},
 }
 
@@ -165,6 +254,19 @@ var analyzeCmd = &cobra.Command{
 	},
 }
 
+var previewCmd = &cobra.Command{
+	Use:   "preview <app-name> <score-spec.yaml>",
+	Short: "Preview what deploying a Score spec would change",
+	Long: `Compare a Score spec with the deployed state of an application without deploying it.
+
+Shows which resources would be created, updated or deleted, which workflow steps would
+run and how long the deployment is estimated to take.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.PreviewCommand(args[0], args[1])
+	},
+}
+
 var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "Show platform statistics (apps, workflows, resources, users)",
@@ -181,24 +283,84 @@ var environmentsCmd = &cobra.Command{
 	},
 }
 
+var clustersCmd = &cobra.Command{
+	Use:   "clusters [environment]",
+	Short: "List clusters in the multi-cluster registry (optionally filtered by environment)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		environment := ""
+		if len(args) > 0 {
+			environment = args[0]
+		}
+		return client.ClustersCommand(environment)
+	},
+}
+
+var overviewCmd = &cobra.Command{
+	Use:   "overview",
+	Short: "Show your applications, running workflows, failing resources, pending approvals and expiring API keys",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.OverviewCommand()
+	},
+}
+
 var deleteCmd = &cobra.Command{
-	Use:   "delete <app-name>",
+	Use:   "delete <app-name>...",
 	Short: "Delete application and all resources completely",
-	Args:  cobra.ExactArgs(1),
+	Long: `Delete applications and all their resources completely.
+
+Several applications are deleted by a pool of --parallel workers. Progress is
+printed as each application finishes, followed by a summary table; the command
+fails if any application failed.
+
+Examples:
+  innominatus-ctl delete my-app
+  innominatus-ctl delete app1 app2 app3 --parallel 3`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.DeleteCommand(args[0])
+		return client.DeleteAppsCommand(args, batchParallel)
 	},
 }
 
 var deprovisionCmd = &cobra.Command{
-	Use:   "deprovision <app-name>",
+	Use:   "deprovision <app-name>...",
 	Short: "Deprovision infrastructure (keep audit trail)",
-	Args:  cobra.ExactArgs(1),
+	Long: `Deprovision the infrastructure of applications, keeping their audit trail.
+
+Several applications are deprovisioned by a pool of --parallel workers.
+
+Examples:
+  innominatus-ctl deprovision my-app
+  innominatus-ctl deprovision app1 app2 app3 app4 --parallel 4`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.DeprovisionAppsCommand(args, batchParallel)
+	},
+}
+
+var hibernateCmd = &cobra.Command{
+	Use:   "hibernate <app-name>...",
+	Short: "Scale an application to zero and pause its resources",
+	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return client.DeprovisionCommand(args[0])
+		reason, _ := cmd.Flags().GetString("reason")
+		return client.HibernateAppsCommand(args, reason, batchParallel)
 	},
 }
 
+var resumeCmd = &cobra.Command{
+	Use:   "resume <app-name>...",
+	Short: "Resume a hibernated application",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.ResumeAppsCommand(args, batchParallel)
+	},
+}
+
+// batchParallel is the number of workers of the multi-app commands
+var batchParallel int
+
 // Workflow commands
 var listWorkflowsCmd = &cobra.Command{
 	Use:   "list-workflows [app-name]",
@@ -227,6 +389,64 @@ var workflowDetailCmd = &cobra.Command{
 	},
 }
 
+var bundleFile string
+
+var workflowBundleCmd = &cobra.Command{
+	Use:   "bundle <workflow-id>",
+	Short: "Export a workflow run as a reproducible bundle",
+	Long: `Export a workflow run as a gzipped tar archive containing the resolved workflow
+definition, parameters, Score spec, step logs, artifacts and provenance.
+
+Attach the bundle to a ticket, or replay a golden path run in a test sandbox with the
+command stored in the bundle's manifest.json ("replay").
+
+Examples:
+  innominatus-ctl workflow bundle 42
+  innominatus-ctl workflow bundle 42 --file /tmp/failed-run.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.WorkflowBundleCommand(args[0], bundleFile)
+	},
+}
+
+var workflowReplayCmd = &cobra.Command{
+	Use:   "replay <workflow-id>",
+	Short: "Re-execute a workflow run with identical inputs",
+	Long: `Re-execute a finished workflow run using the workflow definition and parameters
+recorded for it, not the current workflow file on disk. Parameters resolved from
+external sources such as Vault are resolved again at replay time.
+
+The replay is a new execution linked to the original through replay_of_id.
+
+Examples:
+  innominatus-ctl workflow replay 42`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.WorkflowReplayCommand(args[0])
+	},
+}
+
+var rollbackYes bool
+
+var workflowRollbackCmd = &cobra.Command{
+	Use:   "rollback <workflow-id>",
+	Short: "Remove the resources a failed workflow run created",
+	Long: `Remove the resources a failed workflow run created before it failed, such as Helm
+releases, Kubernetes objects, Gitea repositories and ArgoCD applications. Resources are
+removed newest first; each removal is added to the run as a rollback step.
+
+Resources that existed before the run are never removed. Workflows with
+"onFailure: rollback" are rolled back automatically when they fail.
+
+Examples:
+  innominatus-ctl workflow rollback 42
+  innominatus-ctl workflow rollback 42 --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.WorkflowRollbackCommand(args[0], rollbackYes)
+	},
+}
+
 var (
 	logsStep     string
 	logsStepOnly bool
@@ -276,8 +496,9 @@ var retryCmd = &cobra.Command{
 
 // Resource commands
 var (
-	resourceType  string
-	resourceState string
+	resourceType    string
+	resourceState   string
+	resourceDrifted bool
 )
 
 var listResourcesCmd = &cobra.Command{
@@ -289,7 +510,7 @@ var listResourcesCmd = &cobra.Command{
 		if len(args) > 0 {
 			appName = args[0]
 		}
-		return client.ListResourcesCommand(appName, resourceType, resourceState)
+		return client.ListResourcesCommand(appName, resourceType, resourceState, resourceDrifted)
 	},
 }
 
@@ -335,11 +556,21 @@ var listGoldenPathsCmd = &cobra.Command{
 }
 
 var runParams []string
+var runTest bool
+var runDryRun bool
 
 var runCmd = &cobra.Command{
 	Use:   "run <golden-path-name> [score-spec.yaml]",
 	Short: "Run a golden path workflow",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Run a golden path workflow.
+
+With --test the golden path runs in an isolated sandbox (temporary namespace,
+throwaway Gitea organization, mock DNS zone) that is torn down afterwards,
+whether the run succeeds or fails.
+
+With --dry-run nothing runs: the server shows the resources the run would
+create, update or destroy, with provisioner diffs, and the steps it would run.`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		goldenPath := args[0]
 		scoreFile := ""
@@ -352,15 +583,72 @@ var runCmd = &cobra.Command{
 		for _, param := range runParams {
 			parts := strings.SplitN(param, "=", 2)
 			if len(parts) != 2 {
-				return fmt.Errorf("invalid parameter format '%s'. Use key=value", param)
+				return cli.WithExitCode(cli.ExitUsage, fmt.Errorf("invalid parameter format '%s'. Use key=value", param))
 			}
 			paramMap[parts[0]] = parts[1]
 		}
 
+		if runTest && runDryRun {
+			return cli.WithExitCode(cli.ExitUsage, fmt.Errorf("--test and --dry-run cannot be combined"))
+		}
+		if runDryRun {
+			return client.RunGoldenPathDryRunCommand(goldenPath, scoreFile, paramMap)
+		}
+		if runTest {
+			return client.RunGoldenPathTestCommand(goldenPath, scoreFile, paramMap)
+		}
 		return client.RunGoldenPathCommand(goldenPath, scoreFile, paramMap)
 	},
 }
 
+var goldenPathCmd = &cobra.Command{
+	Use:   "golden-path",
+	Short: "Migrate applications off deprecated golden paths",
+}
+
+var goldenPathApplicationsCmd = &cobra.Command{
+	Use:   "applications <golden-path-name>",
+	Short: "List the applications deployed with a golden path",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.GoldenPathApplicationsCommand(args[0])
+	},
+}
+
+var (
+	migrateApps   []string
+	migrateTeam   string
+	migrateDryRun bool
+)
+
+var goldenPathMigrateCmd = &cobra.Command{
+	Use:   "migrate <golden-path-name>",
+	Short: "Re-run applications of a deprecated golden path through its successor",
+	Long: `Re-run the applications of a deprecated golden path through the successor named in
+goldenpaths.yaml. Every application runs with its stored Score spec and the parameters
+of its last run, renamed by the deprecation's parameter_map.
+
+Without --app every application you have access to is migrated.
+
+Examples:
+  innominatus-ctl golden-path migrate deploy-app --dry-run
+  innominatus-ctl golden-path migrate deploy-app --team payments
+  innominatus-ctl golden-path migrate deploy-app --app shop --app checkout`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.MigrateGoldenPathCommand(args[0], migrateApps, migrateTeam, migrateDryRun)
+	},
+}
+
+var goldenPathMigrationsCmd = &cobra.Command{
+	Use:   "migrations <golden-path-name>",
+	Short: "Show the migration progress away from a golden path per team",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.GoldenPathMigrationsCommand(args[0], migrateTeam)
+	},
+}
+
 // Demo commands
 var demoComponent string
 
@@ -413,7 +701,9 @@ var loginCmd = &cobra.Command{
 	Long: `Authenticate with the innominatus server and store credentials locally.
 
 By default, uses username/password authentication. Use the --sso flag for
-browser-based OIDC/Keycloak authentication.
+browser-based OIDC/Keycloak authentication. The API key is stored in the OS
+keychain (macOS Keychain, Windows Credential Manager, Secret Service on Linux)
+if available, otherwise in ~/.innominatus/credentials.
 
 Examples:
   # Password-based login
@@ -423,8 +713,18 @@ Examples:
   innominatus-ctl login --sso
 
   # Specify API key name and expiry
-  innominatus-ctl login --sso --name my-laptop --expiry-days 30`,
+  innominatus-ctl login --sso --name my-laptop --expiry-days 30
+
+  # Store the API key in a file instead of the OS keychain
+  innominatus-ctl login --store file`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// The login commands parse --name, --expiry-days and --store themselves
+		cmd.LocalNonPersistentFlags().Visit(func(f *pflag.Flag) {
+			if f.Name != "sso" {
+				args = append(args, "--"+f.Name+"="+f.Value.String())
+			}
+		})
+
 		sso, _ := cmd.Flags().GetBool("sso")
 		if sso {
 			return client.LoginSSOCommand(args)
@@ -470,6 +770,8 @@ var chatCmd = &cobra.Command{
 var adminCmd = &cobra.Command{
 	Use:   "admin",
 	Short: "Admin commands (requires admin role)",
+	// Subcommands parse their own flags (e.g. admin loadtest --apps 50)
+	DisableFlagParsing: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return client.AdminCommand(args)
 	},
@@ -487,12 +789,211 @@ var teamCmd = &cobra.Command{
 // Provider commands
 var providerCmd = &cobra.Command{
 	Use:   "provider",
-	Short: "Provider management commands",
+	Short: "Provider management commands (list, describe, test, dev, stats, reload, pins, pin, unpin, upgrade-report)",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return client.ProviderCommand(args)
 	},
 }
 
+var providerTestFake bool
+
+var providerTestCmd = &cobra.Command{
+	Use:   "test <path>",
+	Short: "Check a local provider directory before registering it",
+	Long: `Load a provider from a local directory (or its provider.yaml) and check it the way
+the server would when registering it:
+
+  - the manifest is valid and compatible with this version
+  - dependency version ranges parse
+  - every workflow file passes schema validation
+  - docs and example files referenced by provider.yaml exist
+
+With --fake every workflow also runs end to end with all steps faked, using the
+defaults of its parameters and placeholders for required ones. Nothing is deployed.
+
+Examples:
+  innominatus-ctl provider test ./providers/database-team
+  innominatus-ctl provider test ./provider.yaml --fake -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.ProviderTestCommand(args[0], providerTestFake)
+	},
+}
+
+var providerDevInterval time.Duration
+
+var providerDevCmd = &cobra.Command{
+	Use:   "dev <dir>",
+	Short: "Register a local provider with the server and re-register it on every change",
+	Long: `Upload a local provider directory to the server's development channel and keep it
+registered while you edit it. Every time a file changes the provider is validated and
+registered again; validation errors are printed and the previous revision stays
+registered. Ctrl-C unregisters the provider.
+
+Requires admin access and a server started with INNOMINATUS_PROVIDER_DEV=true
+(refused with ENV=production). Providers from admin-config.yaml cannot be replaced.
+
+Examples:
+  innominatus-ctl provider dev ./providers/database-team
+  innominatus-ctl provider dev . --interval 500ms`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.ProviderDevCommand(args[0], providerDevInterval)
+	},
+}
+
+var (
+	pinApplication string
+	pinTeam        string
+	pinReason      string
+)
+
+// pinScope returns the application or team named by --app or --team
+func pinScope() (string, string, error) {
+	switch {
+	case pinApplication != "" && pinTeam != "":
+		return "", "", fmt.Errorf("use either --app or --team")
+	case pinApplication != "":
+		return "application", pinApplication, nil
+	case pinTeam != "":
+		return "team", pinTeam, nil
+	}
+	return "", "", fmt.Errorf("--app or --team is required")
+}
+
+var providerPinsCmd = &cobra.Command{
+	Use:   "pins",
+	Short: "List the provider versions pinned for an application or team",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scope, name, err := pinScope()
+		if err != nil {
+			return err
+		}
+		return client.ProviderPinsCommand(scope, name)
+	},
+}
+
+var providerPinCmd = &cobra.Command{
+	Use:   "pin <provider> <version>",
+	Short: "Keep an application or team on a provider version",
+	Long: `Pin a provider version for an application, or for every application of a team that
+does not pin the provider itself. Resources of pinned applications keep being
+provisioned with the pinned version after the platform registers a newer one.
+
+Only versions loaded by the server can be pinned; see 'provider upgrade-report' for
+what removing a pin would change.
+
+Examples:
+  innominatus-ctl provider pin database-team 1.4.0 --app shop --reason "waiting for PG 16 tests"
+  innominatus-ctl provider pin database-team 1.4.0 --team payments`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scope, name, err := pinScope()
+		if err != nil {
+			return err
+		}
+		return client.ProviderPinCommand(scope, name, args[0], args[1], pinReason)
+	},
+}
+
+var providerUnpinCmd = &cobra.Command{
+	Use:   "unpin <provider>",
+	Short: "Let an application or team follow the registered provider version again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		scope, name, err := pinScope()
+		if err != nil {
+			return err
+		}
+		return client.ProviderUnpinCommand(scope, name, args[0])
+	},
+}
+
+var providerUpgradeReportCmd = &cobra.Command{
+	Use:   "upgrade-report [application]",
+	Short: "Show pinned provider versions and what upgrading them changes",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		application := ""
+		if len(args) > 0 {
+			application = args[0]
+		}
+		return client.ProviderUpgradeReportCommand(application)
+	},
+}
+
+// Approval commands
+var approvalCmd = &cobra.Command{
+	Use:   "approval",
+	Short: "Review workflow approval gates (list, approve, reject)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.ApprovalCommand(args)
+	},
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the local environment and print fixes for problems",
+	Long: `Check the local environment and print an actionable fix for every problem:
+
+  - server reachability and CLI/server version skew
+  - stored API key validity and expiry
+  - binaries used by local commands and workflow steps (kubectl, helm, git, ...)
+  - access to the current kubectl context
+  - demo prerequisites (docker-desktop context, *.localtest.me DNS)
+
+Exits with status 1 if any check failed; warnings do not fail.
+
+Examples:
+  innominatus-ctl doctor
+  innominatus-ctl doctor --server https://innominatus.example.com -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.DoctorCommand(version)
+	},
+}
+
+var verifyInstallParams []string
+var verifyInstallTimeout time.Duration
+
+var verifyInstallCmd = &cobra.Command{
+	Use:   "verify-install",
+	Short: "Verify an installation end to end with the smoke-test golden path",
+	Long: `Run the bundled smoke-test golden path and print a pass/fail matrix of its steps:
+
+  - create a Gitea repository
+  - deploy a sample app (nginx) with an ingress
+  - check the app URL answers
+  - remove the app, namespace and repository again
+
+A failed run rolls back what it created. Run it after installing or upgrading
+the platform. Exits with status 1 if any check failed.
+
+Examples:
+  innominatus-ctl verify-install
+  innominatus-ctl verify-install --param host=smoke.apps.example.com --param url=https://smoke.apps.example.com
+  innominatus-ctl verify-install --timeout 15m -o json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paramMap := make(map[string]string)
+		for _, param := range verifyInstallParams {
+			parts := strings.SplitN(param, "=", 2)
+			if len(parts) != 2 {
+				return cli.WithExitCode(cli.ExitUsage, fmt.Errorf("invalid parameter format '%s'. Use key=value", param))
+			}
+			paramMap[parts[0]] = parts[1]
+		}
+		return client.VerifyInstallCommand(paramMap, verifyInstallTimeout)
+	},
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show CLI and server versions and their compatibility",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return client.VersionCommand(version, commit)
+	},
+}
+
 func init() {
 	// Add flags to specific commands
 
@@ -500,9 +1001,18 @@ func init() {
 	loginCmd.Flags().BoolP("sso", "s", false, "Use SSO (OIDC) authentication instead of password")
 	loginCmd.Flags().String("name", "", "Name for API key (default: cli-<hostname>-<timestamp>)")
 	loginCmd.Flags().Int("expiry-days", 90, "Days until API key expires")
+	loginCmd.Flags().String("store", "", "Credential store: keychain or file (default: keychain if available)")
 
 	validateCmd.Flags().BoolVar(&validateExplain, "explain", false, "Show detailed validation explanations")
 	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "Output format (text, json, simple)")
+	validateCmd.Flags().BoolVar(&validateLint, "lint", false, "Also check the spec against the server's lint profile")
+	validateCmd.Flags().StringVar(&validateProfile, "profile", "", "Lint profile to apply (default: the server's default profile; implies --lint)")
+
+	hibernateCmd.Flags().String("reason", "", "Reason recorded with the hibernation")
+
+	for _, cmd := range []*cobra.Command{deleteCmd, deprovisionCmd, hibernateCmd, resumeCmd} {
+		cmd.Flags().IntVar(&batchParallel, "parallel", 1, "Number of applications to process at a time")
+	}
 
 	workflowLogsCmd.Flags().StringVar(&logsStep, "step", "", "Show logs for specific step name")
 	workflowLogsCmd.Flags().BoolVar(&logsStepOnly, "step-only", false, "Only show step logs, skip workflow header")
@@ -516,18 +1026,43 @@ func init() {
 
 	listResourcesCmd.Flags().StringVar(&resourceType, "type", "", "Filter by resource type (e.g., postgres, redis)")
 	listResourcesCmd.Flags().StringVar(&resourceState, "state", "", "Filter by state (e.g., active, provisioning, failed)")
+	listResourcesCmd.Flags().BoolVar(&resourceDrifted, "drift", false, "Only show resources that drifted from their desired configuration")
 
 	graphExportCmd.Flags().StringVar(&graphFormat, "format", "svg", "Output format (svg, png, dot)")
 	graphExportCmd.Flags().StringVar(&graphOutput, "output", "", "Output file path (default: stdout)")
 
 	runCmd.Flags().StringArrayVar(&runParams, "param", []string{}, "Parameter override (key=value)")
+	verifyInstallCmd.Flags().StringArrayVar(&verifyInstallParams, "param", []string{}, "Smoke test parameter (namespace, host, url) as key=value")
+	verifyInstallCmd.Flags().DurationVar(&verifyInstallTimeout, "timeout", 10*time.Minute, "How long to wait for the smoke test")
+	runCmd.Flags().BoolVar(&runTest, "test", false, "Run in a temporary sandbox that is torn down afterwards")
+	runCmd.Flags().BoolVar(&runDryRun, "dry-run", false, "Show what the run would change without running anything")
+
+	goldenPathMigrateCmd.Flags().StringArrayVar(&migrateApps, "app", []string{}, "Application to migrate (repeatable; default: all)")
+	goldenPathMigrateCmd.Flags().StringVar(&migrateTeam, "team", "", "Only migrate applications of this team")
+	goldenPathMigrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show the successor parameters without running anything")
+	goldenPathMigrationsCmd.Flags().StringVar(&migrateTeam, "team", "", "Only show this team")
+	goldenPathCmd.AddCommand(goldenPathApplicationsCmd, goldenPathMigrateCmd, goldenPathMigrationsCmd)
 
 	demoTimeCmd.Flags().StringVar(&demoComponent, "component", "", "Comma-separated list of components to install")
 
 	demoResetCmd.Flags().BoolVar(&noCheck, "no-check", false, "Skip demo environment check")
 
+	workflowBundleCmd.Flags().StringVarP(&bundleFile, "file", "f", "", "Output file (default: workflow-<id>-bundle.tar.gz)")
+	workflowRollbackCmd.Flags().BoolVarP(&rollbackYes, "yes", "y", false, "Roll back without asking for confirmation")
+
 	// Add workflow subcommands
-	workflowCmd.AddCommand(workflowDetailCmd, workflowLogsCmd)
+	providerTestCmd.Flags().BoolVar(&providerTestFake, "fake", false, "Also run every workflow with faked steps")
+	providerCmd.AddCommand(providerTestCmd)
+	providerDevCmd.Flags().DurationVar(&providerDevInterval, "interval", time.Second, "How often to check the provider files for changes")
+	providerCmd.AddCommand(providerDevCmd)
+	for _, cmd := range []*cobra.Command{providerPinsCmd, providerPinCmd, providerUnpinCmd} {
+		cmd.Flags().StringVar(&pinApplication, "app", "", "Pins of this application")
+		cmd.Flags().StringVar(&pinTeam, "team", "", "Pins of this team")
+	}
+	providerPinCmd.Flags().StringVar(&pinReason, "reason", "", "Why the version is pinned")
+	providerCmd.AddCommand(providerPinsCmd, providerPinCmd, providerUnpinCmd, providerUpgradeReportCmd)
+
+	workflowCmd.AddCommand(workflowDetailCmd, workflowLogsCmd, workflowBundleCmd, workflowReplayCmd, workflowRollbackCmd)
 
 	// Add all commands to root
 	rootCmd.AddCommand(
@@ -535,10 +1070,15 @@ func init() {
 		statusCmd,
 		validateCmd,
 		analyzeCmd,
+		previewCmd,
 		statsCmd,
 		environmentsCmd,
+		clustersCmd,
+		overviewCmd,
 		deleteCmd,
 		deprovisionCmd,
+		hibernateCmd,
+		resumeCmd,
 		listWorkflowsCmd,
 		workflowCmd,
 		logsCmd,
@@ -548,6 +1088,7 @@ func init() {
 		graphExportCmd,
 		graphStatusCmd,
 		listGoldenPathsCmd,
+		goldenPathCmd,
 		runCmd,
 		demoTimeCmd,
 		demoNukeCmd,
@@ -561,12 +1102,46 @@ func init() {
 		adminCmd,
 		teamCmd,
 		providerCmd,
+		approvalCmd,
+		doctorCmd,
+		verifyInstallCmd,
+		versionCmd,
 	)
 }
 
+// Exit codes by error class are documented in docs/cli/exit-codes.md
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
+	}
+
+	code := cli.ExitCode(err)
+	// Cobra rejects unknown commands, flags and arguments before the command starts
+	if code == cli.ExitError && !commandStarted {
+		code = cli.ExitUsage
+	}
+
+	// Cobra parses no flags when it rejects the command itself
+	if !commandStarted {
+		for _, arg := range os.Args[1:] {
+			if arg == "-q" || arg == "--quiet" {
+				quiet = true
+			}
+		}
+	}
+
+	if quiet {
+		report := cli.NewErrorReport(err)
+		report.ExitCode = code
+		report.Class = cli.ExitClass(code)
+		data, _ := json.Marshal(report)
+		fmt.Fprintln(os.Stderr, string(data))
+	} else {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		if code == cli.ExitUsage {
+			fmt.Fprintln(os.Stderr, cmd.UsageString())
+		}
 	}
+	os.Exit(code)
 }
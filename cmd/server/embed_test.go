@@ -22,6 +22,29 @@ func TestEmbeddedMigrationsFS(t *testing.T) {
 		"migrations/009_add_workflow_retry_support.sql",
 		"migrations/010_add_application_labels.sql",
 		"migrations/011_add_resource_workflow_columns.sql",
+		"migrations/012_create_maintenance_windows.sql",
+		"migrations/013_create_workflow_approvals.sql",
+		"migrations/014_create_deployment_provenance.sql",
+		"migrations/015_add_workflow_execution_parameters.sql",
+		"migrations/016_add_workflow_replay.sql",
+		"migrations/017_create_clusters.sql",
+		"migrations/018_create_workflow_compensations.sql",
+		"migrations/019_add_workflow_execution_outputs.sql",
+		"migrations/020_create_user_directory.sql",
+		"migrations/021_create_login_attempts.sql",
+		"migrations/022_create_application_files.sql",
+		"migrations/023_create_application_hibernations.sql",
+		"migrations/024_create_workload_tokens.sql",
+		"migrations/025_create_workflow_schedules.sql",
+		"migrations/026_create_hostname_reservations.sql",
+		"migrations/027_create_golden_path_migrations.sql",
+		"migrations/028_create_dependency_reports.sql",
+		"migrations/029_add_workflow_execution_overrides.sql",
+		"migrations/030_create_workflow_temp_assets.sql",
+		"migrations/031_create_audit_log.sql",
+		"migrations/032_add_resource_drift_state.sql",
+		"migrations/033_create_provider_version_pins.sql",
+		"migrations/034_add_workflow_approval_plan_hash.sql",
 	}
 
 	t.Run("migrations directory exists", func(t *testing.T) {
@@ -3,15 +3,21 @@ package main
 import (
 	"context"
 	"embed"
+	"errors"
 	"flag"
 	"fmt"
 	"innominatus/internal/admin"
 	"innominatus/internal/ai"
+	"innominatus/internal/clock"
+	"innominatus/internal/config"
 	"innominatus/internal/database"
+	"innominatus/internal/devdb"
 	"innominatus/internal/events"
+	"innominatus/internal/faults"
 	"innominatus/internal/logging"
 	"innominatus/internal/metrics"
 	"innominatus/internal/orchestration"
+	"innominatus/internal/provenance"
 	"innominatus/internal/providers"
 	"innominatus/internal/server"
 	"innominatus/internal/tracing"
@@ -21,9 +27,12 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
+
+	zlog "github.com/rs/zerolog/log"
 )
 
 //go:embed migrations/*.sql
@@ -39,8 +48,46 @@ var webUIFS embed.FS
 var (
 	version = "dev"
 	commit  = "unknown"
+	date    = "unknown"
 )
 
+// newDevServer starts the server on the SQLite development database instead of
+// PostgreSQL. Only workflow executions are stored; the features that need PostgreSQL
+// are listed at startup.
+func newDevServer(logger *logging.ZerologAdapter, cfg config.DatabaseConfig, adminConfig *admin.AdminConfig) *server.Server {
+	store, err := devdb.Open(cfg.DevPath)
+	if err != nil {
+		logger.FatalWithFields("Failed to open development database", map[string]interface{}{
+			"error": err.Error(),
+			"path":  cfg.DevPath,
+		})
+	}
+
+	logger.WarnWithFields("Development database mode - PostgreSQL is required in production", map[string]interface{}{
+		"driver": cfg.Dev,
+		"path":   cfg.DevPath,
+	})
+	for _, feature := range devdb.UnsupportedFeatures {
+		logger.WarnWithFields("Unavailable without PostgreSQL", map[string]interface{}{
+			"feature": feature,
+		})
+	}
+
+	return server.NewDevServer(store, adminConfig)
+}
+
+// databaseConfig returns the database connection settings of the server config
+func databaseConfig(cfg *config.Config) database.Config {
+	return database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.Name,
+		SSLMode:  cfg.Database.SSLMode,
+	}
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return !os.IsNotExist(err)
@@ -58,7 +105,7 @@ func loadProvidersFromConfig(logger *logging.ZerologAdapter, adminConfig *admin.
 	})
 
 	fsLoader := providers.NewLoader(version)
-	gitLoader := providers.NewGitLoader("/tmp/innominatus-providers", version)
+	gitLoader := providers.NewGitLoader(adminConfig.ProviderAssets.Dir(), version)
 
 	// Collect loaded providers for sorted output
 	type loadedProvider struct {
@@ -69,6 +116,8 @@ func loadProvidersFromConfig(logger *logging.ZerologAdapter, adminConfig *admin.
 	}
 	var loadedProviders []loadedProvider
 
+	// Load all manifests first so providers can be registered after their dependencies
+	var manifests []*sdk.Provider
 	for _, providerSrc := range adminConfig.Providers {
 		if !providerSrc.Enabled {
 			logger.DebugWithFields("Skipping disabled provider", map[string]interface{}{
@@ -115,8 +164,32 @@ func loadProvidersFromConfig(logger *logging.ZerologAdapter, adminConfig *admin.
 			continue
 		}
 
-		// Register provider
+		manifests = append(manifests, provider)
+	}
+
+	// The newest version of each provider is registered; older versions are kept for pins
+	latest, retained := providers.SplitVersions(manifests)
+
+	ordered, err := providers.OrderByDependencies(latest)
+	if err != nil {
+		logger.WarnWithFields("Failed to order providers", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	for _, provider := range ordered {
+		// Cache and verify workflow files, so missing ones fail here instead of mid-deploy
+		if err := providerRegistry.Assets().Warm(provider); err != nil {
+			logger.WarnWithFields("Provider failed warm-up", map[string]interface{}{
+				"name":  provider.Metadata.Name,
+				"error": err.Error(),
+			})
+			continue
+		}
+
+		// Register provider; fails when a dependency is missing or out of range
 		if err := providerRegistry.RegisterProvider(provider); err != nil {
+			providerRegistry.Assets().Remove(provider.Metadata.Name)
 			logger.WarnWithFields("Failed to register provider", map[string]interface{}{
 				"name":  provider.Metadata.Name,
 				"error": err.Error(),
@@ -133,6 +206,21 @@ func loadProvidersFromConfig(logger *logging.ZerologAdapter, adminConfig *admin.
 		})
 	}
 
+	for _, provider := range retained {
+		if err := providerRegistry.RetainProviderVersion(provider); err != nil {
+			logger.WarnWithFields("Failed to retain provider version", map[string]interface{}{
+				"name":    provider.Metadata.Name,
+				"version": provider.Metadata.Version,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		logger.InfoWithFields("Provider version retained for pins", map[string]interface{}{
+			"name":    provider.Metadata.Name,
+			"version": provider.Metadata.Version,
+		})
+	}
+
 	// Sort providers alphabetically by name
 	sort.Slice(loadedProviders, func(i, j int) bool {
 		return loadedProviders[i].name < loadedProviders[j].name
@@ -157,6 +245,24 @@ func loadProvidersFromConfig(logger *logging.ZerologAdapter, adminConfig *admin.
 	return nil
 }
 
+// gitProviderSources returns the enabled Git providers of the admin config
+func gitProviderSources(adminConfig *admin.AdminConfig) []providers.GitProviderSource {
+	if adminConfig == nil {
+		return nil
+	}
+	var sources []providers.GitProviderSource
+	for _, providerSrc := range adminConfig.Providers {
+		if providerSrc.Enabled && providerSrc.Type == "git" {
+			sources = append(sources, providers.GitProviderSource{
+				Name:       providerSrc.Name,
+				Repository: providerSrc.Repository,
+				Ref:        providerSrc.Ref,
+			})
+		}
+	}
+	return sources
+}
+
 func isStaticAsset(path string) bool {
 	// Check if path starts with common static asset prefixes
 	return strings.HasPrefix(path, "/.next/") ||
@@ -208,18 +314,46 @@ func (w *loggingResponseWriter) Write(b []byte) (int, error) {
 }
 
 func main() {
-	var port = flag.String("port", "8081", "HTTP server port")
-	// PostgreSQL is now required - removed --disable-db flag
-	var skipValidation = flag.Bool("skip-validation", false, "Skip configuration validation on startup")
-	flag.Parse()
+	// Resolve server settings: defaults < --config file < environment < flags
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		log.Fatalf("Invalid server configuration: %v", err)
+	}
+
+	// Load admin configuration first: its logging section decides the format of every logger
+	// unless the server config sets it
+	adminConfig, adminConfigErr := admin.LoadAdminConfig("admin-config.yaml")
+	if adminConfigErr == nil {
+		_ = cfg.Fallback("logging.level", adminConfig.Logging.Level, "admin-config.yaml")
+		_ = cfg.Fallback("logging.format", adminConfig.Logging.Format, "admin-config.yaml")
+	}
+	loggingConfigErr := logging.Configure(cfg.Logging.Level, cfg.Logging.Format)
 
 	// Initialize structured logger for server startup
 	logger := logging.NewStructuredLogger("server")
+	// Packages logging through zerolog's global logger show up in the admin log tail too
+	zlog.Logger = zlog.Output(logging.TeeTail(os.Stderr))
+	if loggingConfigErr != nil {
+		logger.WarnWithFields("Invalid logging configuration, using defaults", map[string]interface{}{
+			"error": loggingConfigErr.Error(),
+		})
+	}
+
+	if cfg.File() != "" {
+		logger.InfoWithFields("Server configuration file loaded", map[string]interface{}{
+			"file": cfg.File(),
+		})
+	}
 
 	// Run configuration validation before starting
-	if !*skipValidation {
+	if cfg.Database.Dev != "" {
+		logger.Info("Skipping configuration validation in development database mode")
+	} else if !cfg.Server.SkipValidation {
 		logger.Info("Running configuration validation")
-		validation.ValidateConfigurationWithExit()
+		validation.ValidateConfigurationWithDatabaseOrExit(databaseConfig(cfg))
 		logger.Info("Configuration validation passed")
 	}
 
@@ -242,11 +376,9 @@ func main() {
 		}()
 	}
 
-	// Load admin configuration
-	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
-	if err != nil {
+	if adminConfigErr != nil {
 		logger.WarnWithFields("Failed to load admin config, continuing without admin configuration", map[string]interface{}{
-			"error": err.Error(),
+			"error": adminConfigErr.Error(),
 		})
 	} else {
 		logger.InfoWithFields("Admin configuration loaded", map[string]interface{}{
@@ -262,40 +394,100 @@ func main() {
 		})
 	}
 
-	// PostgreSQL is required - fail fast if unavailable
-	db, err := database.NewDatabase()
-	if err != nil {
-		logger.FatalWithFields("Failed to connect to PostgreSQL database", map[string]interface{}{
-			"error":         err.Error(),
-			"hint":          "Ensure PostgreSQL is running and DB_* environment variables are set correctly",
-			"required_vars": "DB_HOST, DB_PORT, DB_NAME, DB_USER, DB_PASSWORD",
+	var srv *server.Server
+	if cfg.Database.Dev != "" {
+		srv = newDevServer(logger, cfg.Database, adminConfig)
+	} else {
+		// PostgreSQL is required - fail fast if unavailable
+		db, err := database.NewDatabaseWithConfig(databaseConfig(cfg))
+		if err != nil {
+			logger.FatalWithFields("Failed to connect to PostgreSQL database", map[string]interface{}{
+				"error":         err.Error(),
+				"hint":          "Ensure PostgreSQL is running and the database settings (DB_* environment variables or --config file) are correct",
+				"required_vars": "DB_HOST, DB_PORT, DB_NAME, DB_USER, DB_PASSWORD",
+			})
+		}
+
+		// Initialize schema
+		err = db.InitSchema()
+		if err != nil {
+			logger.FatalWithFields("Failed to initialize database schema", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+
+		logger.Info("Database connected successfully")
+
+		// Set embedded migrations filesystem
+		migrationsSubFS, err := fs.Sub(migrationsFS, "migrations")
+		if err != nil {
+			logger.WarnWithFields("Failed to create migrations sub-filesystem", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			db.SetMigrationsFS(migrationsSubFS)
+			logger.Info("Embedded migrations filesystem configured")
+		}
+
+		// Pass admin config to enable multi-tier workflows
+		srv = server.NewServerWithDBAndAdminConfig(db, adminConfig)
+	}
+	srv.SetBuildInfo(version, commit, date)
+	srv.SetEffectiveConfig(cfg)
+	if adminConfig != nil {
+		srv.SetCLIVersionPolicy(server.CLIVersionPolicy{
+			MinVersion:         adminConfig.CLI.MinVersion,
+			RecommendedVersion: adminConfig.CLI.RecommendedVersion,
 		})
+		// CORS, rate limits, authentication and notifications; POST /api/admin/reload re-applies them
+		if err := srv.ApplyRuntimeConfig(adminConfig); err != nil {
+			logger.Warnf("Invalid server settings in admin-config.yaml, using defaults until reload: %v", err)
+		}
 	}
 
-	// Initialize schema
-	err = db.InitSchema()
-	if err != nil {
-		logger.FatalWithFields("Failed to initialize database schema", map[string]interface{}{
-			"error": err.Error(),
+	// Optional fake clock for TTL and scheduler testing (time travel via /api/admin/debug/clock)
+	if fakeStart := cfg.Server.FakeClock; fakeStart != "" {
+		start := time.Now()
+		if fakeStart != "now" {
+			start, err = time.Parse(time.RFC3339, fakeStart)
+			if err != nil {
+				log.Fatalf("Invalid INNOMINATUS_FAKE_CLOCK %q (use 'now' or RFC3339): %v", fakeStart, err)
+			}
+		}
+		srv.SetClock(clock.NewFake(start))
+		logger.WarnWithFields("Fake clock enabled - server time only moves via /api/admin/debug/clock", map[string]interface{}{
+			"start": start.Format(time.RFC3339),
 		})
 	}
 
-	logger.Info("Database connected successfully")
+	// Optional fault injection for resilience testing (/api/admin/faults), never in production
+	var faultInjector *faults.Injector
+	if cfg.Server.FaultInjection {
+		if os.Getenv("ENV") == "production" {
+			logger.Warn("Fault injection requested but refused because ENV=production")
+		} else {
+			faultInjector = faults.NewInjector()
+			srv.SetFaultInjector(faultInjector)
+			logger.Warn("Fault injection enabled - admins can inject failures via /api/admin/faults")
+		}
+	}
 
-	// Set embedded migrations filesystem
-	migrationsSubFS, err := fs.Sub(migrationsFS, "migrations")
+	// Deployment provenance: builder identity and optional signing key
+	provenanceSigner, err := provenance.LoadSigner()
 	if err != nil {
-		logger.WarnWithFields("Failed to create migrations sub-filesystem", map[string]interface{}{
-			"error": err.Error(),
+		log.Fatalf("Invalid provenance signing key: %v", err)
+	}
+	hostname, _ := os.Hostname()
+	srv.SetProvenance(provenanceSigner, provenance.Builder{
+		ID:      fmt.Sprintf("innominatus/%s", hostname),
+		Version: fmt.Sprintf("%s (%s)", version, commit),
+	})
+	if provenanceSigner != nil {
+		logger.InfoWithFields("Deployment provenance signing enabled", map[string]interface{}{
+			"key_id": provenanceSigner.KeyID(),
 		})
-	} else {
-		db.SetMigrationsFS(migrationsSubFS)
-		logger.Info("Embedded migrations filesystem configured")
 	}
 
-	// Pass admin config to enable multi-tier workflows
-	srv := server.NewServerWithDBAndAdminConfig(db, adminConfig)
-
 	// Set provider registry on server
 	if providerRegistry != nil {
 		srv.SetProviderRegistry(providerRegistry)
@@ -303,9 +495,37 @@ func main() {
 
 		// Create and set resolver for resource type validation
 		providerResolver := orchestration.NewResolver(providerRegistry)
+		providerResolver.SetClock(srv.Clock())
 		srv.SetProviderResolver(providerResolver)
 		logger.Info("Provider resolver configured for resource type validation")
 
+		// Reload Git providers whose ref moved or whose clone was cleaned up
+		var assetConfig admin.ProviderAssetConfig
+		if adminConfig != nil {
+			assetConfig = adminConfig.ProviderAssets
+		}
+		providerRefresher := providers.NewGitRefresher(providers.NewGitLoader(assetConfig.Dir(), version), providerRegistry)
+		providerRefresher.SetSources(gitProviderSources(adminConfig))
+		if interval, err := assetConfig.Interval(); err != nil {
+			logger.WarnWithFields("Provider refresh disabled", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			srv.StartProviderRefresh(context.Background(), providerRefresher, interval)
+		}
+
+		// Optional provider development channel (innominatus-ctl provider dev), never in production
+		var providerDev *providers.DevChannel
+		if cfg.Server.ProviderDev {
+			if os.Getenv("ENV") == "production" {
+				logger.Warn("Provider development requested but refused because ENV=production")
+			} else {
+				providerDev = providers.NewDevChannel(providerRegistry, version, filepath.Join(os.TempDir(), "innominatus-provider-dev"))
+				srv.SetProviderDevChannel(providerDev)
+				logger.Warn("Provider development enabled - admins can register local providers via /api/admin/providers/dev")
+			}
+		}
+
 		// Set up reload callback for hot-reloading providers
 		reloadFunc := func() error {
 			logger.Info("Reloading providers from admin-config.yaml")
@@ -324,6 +544,10 @@ func main() {
 			if err := loadProvidersFromConfig(logger, newAdminConfig, providerRegistry, version); err != nil {
 				return fmt.Errorf("failed to load providers: %w", err)
 			}
+			providerRefresher.SetSources(gitProviderSources(newAdminConfig))
+			if providerDev != nil {
+				providerDev.Restore()
+			}
 
 			return nil
 		}
@@ -368,6 +592,23 @@ func main() {
 
 			// Configure event bus on all components
 			engine.SetEventBus(eventBus)
+			engine.SetClock(srv.Clock())
+			engine.SetFaultInjector(faultInjector)
+			if adminConfig != nil {
+				engine.SetConcurrencyLimits(orchestration.ConcurrencyLimits{
+					MaxConcurrent: adminConfig.Provisioning.MaxConcurrent,
+					ResourceTypes: adminConfig.Provisioning.ResourceTypes,
+					Providers:     adminConfig.Provisioning.Providers,
+				})
+				if interval := adminConfig.Provisioning.DriftCheckInterval; interval != "" {
+					if d, err := time.ParseDuration(interval); err != nil || d < 0 {
+						logger.Warnf("Invalid provisioning.driftCheckInterval '%s', using %s", interval, orchestration.DefaultDriftCheckInterval)
+					} else {
+						engine.SetDriftCheckInterval(d)
+					}
+				}
+			}
+			srv.SetProviderHealth(engine.ProviderHealth())
 			resourceManager := srv.GetResourceManager()
 			if resourceManager != nil {
 				resourceManager.SetEventBus(eventBus)
@@ -382,6 +623,15 @@ func main() {
 			srv.SetSSEBroker(sseBroker)
 			logger.Info("SSE broker created and configured")
 
+			// Notify teams about workflow and resource events
+			srv.StartNotifier(eventBus)
+
+			// Page on-call for failed production deployments and unhealthy resources
+			srv.StartAlerter(context.Background(), eventBus)
+
+			// Keep external CMDBs up to date with resource changes
+			srv.StartCMDBPublisher(context.Background(), eventBus)
+
 			// Start engine in background
 			go func() {
 				ctx := context.Background()
@@ -389,6 +639,15 @@ func main() {
 			}()
 
 			logger.Info("Orchestration engine started successfully")
+
+			// Execute operations deferred to maintenance windows
+			srv.StartMaintenanceScheduler(context.Background())
+
+			// Hibernate idle applications
+			srv.StartHibernationScheduler(context.Background())
+
+			// Run golden paths on their cron schedules
+			srv.StartScheduleRunner(context.Background())
 		}
 	}
 
@@ -405,6 +664,22 @@ func main() {
 		logger.Info("AI assistant service disabled (missing API keys)")
 	}
 
+	// Integrations configured in admin-config.yaml are checked in /health
+	if err := srv.RegisterIntegrationChecks(adminConfig); err != nil {
+		logger.WarnWithFields("Integration health checks not registered", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
+	// Keep Gitea organizations and team memberships aligned with innominatus teams
+	srv.StartGiteaTeamSync(context.Background())
+
+	// Keep an ArgoCD project with restricted destinations per team
+	srv.StartArgoCDProjectSync(context.Background())
+
+	// Remove repository clones and manifests steps left in the temp directory
+	srv.StartTempAssetGC(context.Background())
+
 	// Set embedded swagger files filesystem
 	srv.SetSwaggerFS(swaggerFilesFS)
 	logger.Info("Embedded swagger files filesystem configured")
@@ -420,29 +695,29 @@ func main() {
 		logger.Info("Embedded web-ui filesystem configured")
 	}
 
-	// Helper to apply standard middleware chain (OTel Tracing -> TraceID -> Logging)
+	// Helper to apply standard middleware chain (Metrics -> OTel Tracing -> TraceID -> Logging)
 	withTrace := func(h http.HandlerFunc) http.HandlerFunc {
-		return srv.TracingMiddleware(srv.TraceIDMiddleware(srv.LoggingMiddleware(h)))
+		return srv.MetricsMiddleware(srv.TracingMiddleware(srv.TraceIDMiddleware(srv.LoggingMiddleware(h))))
 	}
 
-	// Helper to apply trace, logging, and CORS
+	// Helper to apply trace, logging, CORS and rate limiting
 	withTraceCORS := func(h http.HandlerFunc) http.HandlerFunc {
-		return srv.TracingMiddleware(srv.TraceIDMiddleware(srv.LoggingMiddleware(srv.CorsMiddleware(h))))
+		return srv.MetricsMiddleware(srv.TracingMiddleware(srv.TraceIDMiddleware(srv.LoggingMiddleware(srv.CorsMiddleware(srv.RateLimitMiddleware(h))))))
 	}
 
 	// Helper to apply trace, logging, and auth
 	withTraceAuth := func(h http.HandlerFunc) http.HandlerFunc {
-		return srv.TracingMiddleware(srv.TraceIDMiddleware(srv.LoggingMiddleware(srv.AuthMiddleware(h))))
+		return srv.MetricsMiddleware(srv.TracingMiddleware(srv.TraceIDMiddleware(srv.LoggingMiddleware(srv.AuthMiddleware(h)))))
 	}
 
-	// Helper to apply full middleware chain (OTel Tracing -> TraceID -> Logging -> CORS -> Auth)
+	// Helper to apply full middleware chain (Metrics -> OTel Tracing -> TraceID -> Logging -> CORS -> Auth -> Rate limit)
 	withTraceCORSAuth := func(h http.HandlerFunc) http.HandlerFunc {
-		return srv.TracingMiddleware(srv.TraceIDMiddleware(srv.LoggingMiddleware(srv.CorsMiddleware(srv.AuthMiddleware(h)))))
+		return srv.MetricsMiddleware(srv.TracingMiddleware(srv.TraceIDMiddleware(srv.LoggingMiddleware(srv.CorsMiddleware(srv.AuthMiddleware(srv.RateLimitMiddleware(h)))))))
 	}
 
 	// Helper to apply full admin middleware chain
 	withTraceCORSAdmin := func(h http.HandlerFunc) http.HandlerFunc {
-		return srv.TracingMiddleware(srv.TraceIDMiddleware(srv.LoggingMiddleware(srv.CorsMiddleware(srv.AdminOnlyMiddleware(h)))))
+		return srv.MetricsMiddleware(srv.TracingMiddleware(srv.TraceIDMiddleware(srv.LoggingMiddleware(srv.CorsMiddleware(srv.AdminOnlyMiddleware(srv.RateLimitMiddleware(h)))))))
 	}
 
 	// Authentication routes (with trace ID and logging)
@@ -455,10 +730,21 @@ func main() {
 	http.HandleFunc("/auth/oidc/login", withTrace(srv.HandleOIDCLogin))
 	http.HandleFunc("/auth/callback", withTrace(srv.HandleOIDCCallback))
 
+	// SCIM 2.0 provisioning from identity providers (bearer token from admin-config.yaml)
+	http.HandleFunc("/scim/v2/", withTrace(srv.HandleSCIM))
+	http.HandleFunc("/api/hibernation/wake/", withTrace(srv.HandleHibernationWake))
+
 	// OIDC CLI authentication routes (for CLI PKCE flow)
 	http.HandleFunc("/api/oidc/config", withTraceCORS(srv.HandleOIDCConfig))
 	http.HandleFunc("/api/oidc/token", withTraceCORS(srv.HandleOIDCTokenExchange))
 
+	// Service account token exchange for in-cluster workloads
+	http.HandleFunc("/api/kubernetes/token", withTrace(srv.HandleKubernetesTokenExchange))
+
+	// Slack app (requests signed with the signing secret from admin-config.yaml)
+	http.HandleFunc("/api/integrations/slack/commands", withTrace(srv.HandleSlackCommands))
+	http.HandleFunc("/api/integrations/slack/interactions", withTrace(srv.HandleSlackInteractions))
+
 	// API routes (with trace ID, logging, CORS, and authentication)
 	// Applications endpoints (preferred)
 	http.HandleFunc("/api/applications", withTraceCORSAuth(srv.HandleApplications))
@@ -467,24 +753,30 @@ func main() {
 	http.HandleFunc("/api/specs", withTraceCORSAuth(srv.HandleSpecsDeprecated))
 	http.HandleFunc("/api/specs/", withTraceCORSAuth(srv.HandleSpecDetailDeprecated))
 
-	// SSE endpoint for real-time event streaming
-	http.HandleFunc("/api/events/stream", func(w http.ResponseWriter, r *http.Request) {
-		// Apply middleware manually but allow SSE to stream without typical middleware interference
-		if srv.GetSSEBroker() != nil {
-			srv.GetSSEBroker().ServeHTTP(w, r)
-		} else {
-			http.Error(w, "Event streaming not available", http.StatusServiceUnavailable)
-		}
-	})
+	// SSE endpoint for real-time event streaming. Skips the logging and tracing middleware,
+	// whose response writers don't support flushing.
+	http.HandleFunc("/api/events/stream", srv.TraceIDMiddleware(srv.CorsMiddleware(srv.AuthMiddleware(srv.HandleEventStream))))
 
 	http.HandleFunc("/api/environments", withTraceCORSAuth(srv.HandleEnvironments))
 	http.HandleFunc("/api/workflows", withTraceCORSAuth(srv.HandleWorkflows))
 	http.HandleFunc("/api/workflows/", withTraceCORSAuth(srv.HandleWorkflowDetail))
 	http.HandleFunc("/api/workflow-analysis", withTraceCORSAuth(srv.HandleWorkflowAnalysis))
 	http.HandleFunc("/api/workflow-analysis/preview", withTraceCORSAuth(srv.HandleWorkflowAnalysisPreview))
+	http.HandleFunc("/api/validate", withTraceCORSAuth(srv.HandleValidate))
 	http.HandleFunc("/api/stats", withTraceCORSAuth(srv.HandleStats))
+	http.HandleFunc("/api/organizations", withTraceCORSAuth(srv.HandleOrganizations))
 	http.HandleFunc("/api/teams", withTraceCORSAdmin(srv.HandleTeams))
-	http.HandleFunc("/api/teams/", withTraceCORSAdmin(srv.HandleTeamDetail))
+	http.HandleFunc("/api/teams/", withTraceCORS(func(w http.ResponseWriter, r *http.Request) {
+		// Team members may read the usage and manage the provider pins of their team;
+		// everything else is admin only
+		if strings.HasSuffix(r.URL.Path, "/usage") {
+			srv.AuthMiddleware(srv.HandleTeamUsage)(w, r)
+		} else if strings.HasSuffix(r.URL.Path, "/provider-pins") {
+			srv.AuthMiddleware(srv.HandleTeamProviderPins)(w, r)
+		} else {
+			srv.AdminOnlyMiddleware(srv.HandleTeamDetail)(w, r)
+		}
+	}))
 
 	// Admin-only impersonation routes
 	http.HandleFunc("/api/impersonate", withTraceCORSAdmin(srv.HandleImpersonate))
@@ -509,6 +801,7 @@ func main() {
 	// Profile management routes (authenticated users only)
 	http.HandleFunc("/api/profile", withTraceCORSAuth(srv.HandleGetProfile))
 	http.HandleFunc("/api/auth/whoami", withTraceCORSAuth(srv.HandleGetProfile)) // Alias for AI assistant
+	http.HandleFunc("/api/me/overview", withTraceCORSAuth(srv.HandleMyOverview))
 	http.HandleFunc("/api/profile/api-keys", withTraceCORSAuth(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
@@ -537,7 +830,27 @@ func main() {
 
 	// Admin configuration routes
 	http.HandleFunc("/api/admin/config", withTraceCORSAdmin(srv.HandleAdminConfig))
+	http.HandleFunc("/api/admin/effective-config", withTraceCORSAdmin(srv.HandleEffectiveConfig))
 	http.HandleFunc("/api/admin/reload", withTraceCORSAdmin(srv.HandleAdminReload))
+	http.HandleFunc("/api/admin/debug/clock", withTraceCORSAdmin(srv.HandleDebugClock))
+	http.HandleFunc("/api/admin/faults", withTraceCORSAdmin(srv.HandleFaults))
+	http.HandleFunc("/api/admin/faults/", withTraceCORSAdmin(srv.HandleFaults))
+	http.HandleFunc("/api/admin/providers/dev", withTraceCORSAdmin(srv.HandleProviderDev))
+	http.HandleFunc("/api/admin/providers/dev/", withTraceCORSAdmin(srv.HandleProviderDev))
+	http.HandleFunc("/api/admin/notifications/", withTraceCORSAdmin(srv.HandleNotifications))
+	http.HandleFunc("/api/admin/loadtest", withTraceCORSAdmin(srv.HandleLoadTests))
+	http.HandleFunc("/api/admin/loadtest/", withTraceCORSAdmin(srv.HandleLoadTestDetail))
+	http.HandleFunc("/api/admin/step-cache", withTraceCORSAdmin(srv.HandleStepCache))
+	http.HandleFunc("/api/admin/temp-assets", withTraceCORSAdmin(srv.HandleAdminTempAssets))
+	http.HandleFunc("/api/admin/observability/bundle", withTraceCORSAdmin(srv.HandleObservabilityBundle))
+	http.HandleFunc("/api/admin/gitea/team-sync", withTraceCORSAdmin(srv.HandleGiteaTeamSync))
+	http.HandleFunc("/api/admin/argocd/project-sync", withTraceCORSAdmin(srv.HandleArgoCDProjectSync))
+	http.HandleFunc("/api/admin/logging", withTraceCORSAdmin(srv.HandleLogLevel))
+	// Log tail streams over SSE and skips the response-wrapping middleware, like /api/events/stream
+	http.HandleFunc("/api/admin/logs/stream", srv.TraceIDMiddleware(srv.CorsMiddleware(srv.AdminOnlyMiddleware(srv.HandleLogStream))))
+	http.HandleFunc("/api/admin/usage", withTraceCORSAdmin(srv.HandleAdminUsage))
+	http.HandleFunc("/api/admin/resources/", withTraceCORSAdmin(srv.HandleAdminResources))
+	http.HandleFunc("/api/admin/audit", withTraceCORSAdmin(srv.HandleAuditLog))
 
 	// Graph API routes (with trace ID, logging, CORS, and authentication)
 	http.HandleFunc("/api/graph", withTraceCORSAuth(srv.HandleGraph))
@@ -557,16 +870,41 @@ func main() {
 	http.HandleFunc("/api/resources", withTraceCORSAuth(srv.HandleResources))
 	http.HandleFunc("/api/resources/", withTraceCORSAuth(srv.HandleResourceDetail))
 
+	// Maintenance window API routes (with trace ID, logging, CORS, and authentication)
+	http.HandleFunc("/api/clusters", withTraceCORSAuth(srv.HandleClusters))
+	http.HandleFunc("/api/clusters/", withTraceCORSAuth(srv.HandleClusterDetail))
+	http.HandleFunc("/api/maintenance-windows", withTraceCORSAuth(srv.HandleMaintenanceWindows))
+	http.HandleFunc("/api/maintenance-windows/", withTraceCORSAuth(srv.HandleMaintenanceWindowDetail))
+	http.HandleFunc("/api/operations/upcoming", withTraceCORSAuth(srv.HandleUpcomingOperations))
+
+	// Hostname availability under the naming convention (with trace ID, logging, CORS, and authentication)
+	http.HandleFunc("/api/naming/availability", withTraceCORSAuth(srv.HandleNamingAvailability))
+
+	// Workflow schedule API routes (with trace ID, logging, CORS, and authentication)
+	http.HandleFunc("/api/schedules", withTraceCORSAuth(srv.HandleSchedules))
+	http.HandleFunc("/api/schedules/", withTraceCORSAuth(srv.HandleScheduleDetail))
+
+	// Workflow approval gate API routes (with trace ID, logging, CORS, and authentication)
+	http.HandleFunc("/api/approvals", withTraceCORSAuth(srv.HandleApprovals))
+	http.HandleFunc("/api/approvals/", withTraceCORSAuth(srv.HandleApprovalDetail))
+	// Page the buttons of approval notifications open; browsers without a session go to /login
+	http.HandleFunc("/approvals/", withTrace(srv.AuthMiddleware(srv.HandleApprovalPage)))
+	http.HandleFunc("/api/concurrency-groups", withTraceCORSAuth(srv.HandleConcurrencyGroups))
+	http.HandleFunc("/api/concurrency-groups/", withTraceCORSAuth(srv.HandleConcurrencyGroups))
+
 	// Golden path API routes (with trace ID, logging, CORS, and authentication)
 	http.HandleFunc("/api/golden-paths", withTraceCORSAuth(srv.HandleGoldenPaths))
 
 	// Provider management API routes (with trace ID, logging, CORS, and authentication)
 	http.HandleFunc("/api/providers", withTraceCORSAuth(srv.HandleListProviders))
 	http.HandleFunc("/api/providers/stats", withTraceCORSAuth(srv.HandleProviderStats))
+	http.HandleFunc("/api/providers/upgrade-report", withTraceCORSAuth(srv.HandleProviderUpgradeReport))
+	http.HandleFunc("/api/providers/", withTraceCORSAuth(srv.HandleProviderDetail))
 	http.HandleFunc("/api/golden-paths/", withTraceCORSAuth(srv.HandleGoldenPaths))
 
 	// Golden path workflow execution API routes (with trace ID, logging, CORS, and authentication)
 	http.HandleFunc("/api/workflows/golden-paths/", withTraceCORSAuth(srv.HandleGoldenPathExecution))
+	http.HandleFunc("/api/queue/tasks/", withTraceCORSAuth(srv.HandleQueueTask))
 
 	// AI Assistant API routes (with trace ID, logging, CORS, and authentication)
 	if aiService != nil && aiService.IsEnabled() {
@@ -585,12 +923,15 @@ func main() {
 	http.HandleFunc("/swagger-user.yaml", withTrace(srv.HandleSwaggerUserYAML))
 
 	// Health check endpoints (with tracing but no auth - for monitoring systems)
-	http.HandleFunc("/health", srv.TracingMiddleware(srv.TraceIDMiddleware(srv.HandleHealth)))
-	http.HandleFunc("/ready", srv.TracingMiddleware(srv.TraceIDMiddleware(srv.HandleReady)))
-	http.HandleFunc("/metrics", srv.TracingMiddleware(srv.TraceIDMiddleware(srv.HandleMetrics)))
+	http.HandleFunc("/health", srv.MetricsMiddleware(srv.TracingMiddleware(srv.TraceIDMiddleware(srv.HandleHealth))))
+	http.HandleFunc("/ready", srv.MetricsMiddleware(srv.TracingMiddleware(srv.TraceIDMiddleware(srv.HandleReady))))
+	http.HandleFunc("/metrics", srv.MetricsMiddleware(srv.TracingMiddleware(srv.TraceIDMiddleware(srv.HandleMetrics))))
 
 	// Auth configuration endpoint (with tracing but no auth - needed before login)
-	http.HandleFunc("/api/auth/config", srv.TracingMiddleware(srv.TraceIDMiddleware(srv.HandleAuthConfig)))
+	http.HandleFunc("/api/auth/config", srv.MetricsMiddleware(srv.TracingMiddleware(srv.TraceIDMiddleware(srv.HandleAuthConfig))))
+
+	// Build info and CLI version negotiation (no auth - the CLI checks compatibility before login)
+	http.HandleFunc("/api/version", withTraceCORS(srv.HandleVersion))
 
 	// Web UI (static files) - no authentication needed for static assets
 	// Use embedded FS if available (production), otherwise use filesystem (development)
@@ -740,29 +1081,43 @@ func main() {
 		})
 	}))
 
-	// Initialize metrics pusher if PUSHGATEWAY_URL is set
-	pushgatewayURL := os.Getenv("PUSHGATEWAY_URL")
-	if pushgatewayURL == "" {
-		pushgatewayURL = "http://pushgateway.localtest.me"
-	}
-
-	var metricsPusher *metrics.MetricsPusher
-	if pushgatewayURL != "" && pushgatewayURL != "disabled" {
-		pushInterval := 15 * time.Second
-		metricsPusher = metrics.NewMetricsPusher(pushgatewayURL, pushInterval, version, commit)
+	// Initialize metrics pusher if a Pushgateway URL is configured
+	if cfg.Metrics.PushEnabled() {
+		instance := cfg.Metrics.PushInstance
+		if instance == "" {
+			instance, _ = os.Hostname()
+		}
+		metricsPusher, err := metrics.NewMetricsPusherWithConfig(metrics.PushConfig{
+			URL:                cfg.Metrics.PushgatewayURL,
+			Interval:           cfg.Metrics.PushInterval,
+			Job:                cfg.Metrics.PushJob,
+			Instance:           instance,
+			Username:           cfg.Metrics.PushUsername,
+			Password:           cfg.Metrics.PushPassword,
+			CAFile:             cfg.Metrics.PushCAFile,
+			CertFile:           cfg.Metrics.PushCertFile,
+			KeyFile:            cfg.Metrics.PushKeyFile,
+			InsecureSkipVerify: cfg.Metrics.PushInsecureSkipVerify,
+		}, version, commit)
+		if err != nil {
+			logger.FatalWithFields("Invalid Pushgateway configuration", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		srv.SetMetricsPusher(metricsPusher)
 		metricsPusher.StartPushing()
 		defer metricsPusher.Stop()
 	}
 
-	addr := ":" + *port
+	addr := ":" + cfg.Server.Port
 
 	// Log server startup with structured logging
 	logger.InfoWithFields("Starting Score Orchestrator server", map[string]interface{}{
 		"version":          version,
 		"commit":           commit,
-		"port":             *port,
+		"port":             cfg.Server.Port,
 		"address":          "http://localhost" + addr,
-		"database_enabled": true, // PostgreSQL is always required
+		"database_enabled": srv.HasDatabase(),
 		"tracing_enabled":  tp.IsEnabled(),
 	})
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"innominatus/internal/admin"
+	"innominatus/internal/config"
 	"innominatus/internal/database"
 	"innominatus/internal/security"
 	"net/http"
@@ -337,25 +338,24 @@ func TestPushgatewayConfiguration(t *testing.T) {
 		shouldInitPusher bool
 	}{
 		{"Disabled", "disabled", false},
-		{"Empty defaults to pushgateway", "", true}, // Empty defaults to a URL, so pusher is created
+		{"Empty disables pushing", "", false},
 		{"Valid URL", "http://localhost:9091", true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// The logic: if pushgatewayURL is empty, it defaults to "http://pushgateway.localtest.me"
-			// if it's "disabled", no pusher is created
-			// otherwise, pusher is created
-
-			url := tt.pushgatewayURL
-			if url == "" {
-				url = "http://pushgateway.localtest.me"
+			cfg, err := config.Load(nil, func(name string) (string, bool) {
+				if name == "PUSHGATEWAY_URL" {
+					return tt.pushgatewayURL, true
+				}
+				return "", false
+			})
+			if err != nil {
+				t.Fatalf("config.Load() error = %v", err)
 			}
 
-			shouldCreate := url != "" && url != "disabled"
-
-			if shouldCreate != tt.shouldInitPusher {
-				t.Errorf("Expected shouldCreate = %v, got %v", tt.shouldInitPusher, shouldCreate)
+			if cfg.Metrics.PushEnabled() != tt.shouldInitPusher {
+				t.Errorf("Expected PushEnabled() = %v, got %v", tt.shouldInitPusher, cfg.Metrics.PushEnabled())
 			}
 		})
 	}
@@ -1,21 +1,57 @@
 package admin
 
 import (
+	"context"
 	"fmt"
+	"innominatus/internal/alerting"
+	"innominatus/internal/argoprojects"
+	"innominatus/internal/auth"
+	"innominatus/internal/changemgmt"
+	"innominatus/internal/cloudcreds"
+	"innominatus/internal/cmdb"
+	"innominatus/internal/giteateams"
+	"innominatus/internal/health"
+	"innominatus/internal/hibernation"
+	"innominatus/internal/naming"
+	"innominatus/internal/notifications"
+	"innominatus/internal/orgs"
+	"innominatus/internal/scim"
+	"innominatus/internal/scorelint"
+	"innominatus/internal/secrets"
 	"innominatus/internal/security"
+	"innominatus/internal/slack"
+	"innominatus/internal/tempassets"
+	"innominatus/internal/usage"
+	"net/url"
 	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// variableKeyPattern matches names usable in ${workflow.NAME} interpolation
+var variableKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 type AdminConfig struct {
 	Admin struct {
 		DefaultCostCenter string `yaml:"defaultCostCenter"`
 		DefaultRuntime    string `yaml:"defaultRuntime"`
 		SplunkIndex       string `yaml:"splunkIndex"`
 	} `yaml:"admin"`
-	Providers           []ProviderSource      `yaml:"providers"`
-	ResourceDefinitions map[string]string `yaml:"resourceDefinitions"`
+	Logging struct {
+		Level  string `yaml:"level"`  // debug, info, warn, error; LOG_LEVEL takes precedence
+		Format string `yaml:"format"` // json, console, pretty; LOG_FORMAT takes precedence
+	} `yaml:"logging"`
+	CLI struct {
+		MinVersion         string `yaml:"minVersion"`         // Older innominatus-ctl versions are refused
+		RecommendedVersion string `yaml:"recommendedVersion"` // Older innominatus-ctl versions get a deprecation warning
+	} `yaml:"cli"`
+	Providers           []ProviderSource    `yaml:"providers"`
+	ProviderAssets      ProviderAssetConfig `yaml:"providerAssets"` // Clones and refresh of Git providers
+	ResourceDefinitions map[string]string   `yaml:"resourceDefinitions"`
 	Policies            struct {
 		EnforceBackups      bool     `yaml:"enforceBackups"`
 		AllowedEnvironments []string `yaml:"allowedEnvironments"`
@@ -26,11 +62,15 @@ type AdminConfig struct {
 		Username    string `yaml:"username"`
 		Password    string `yaml:"password"`
 		OrgName     string `yaml:"orgName"`
+
+		TeamProvisioning giteateams.Config `yaml:"teamProvisioning"` // Organizations and teams per innominatus team
 	} `yaml:"gitea"`
 	ArgoCD struct {
 		URL      string `yaml:"url"`
 		Username string `yaml:"username"`
 		Password string `yaml:"password"`
+
+		TeamProjects argoprojects.Config `yaml:"teamProjects"` // AppProject per innominatus team
 	} `yaml:"argocd"`
 	Vault struct {
 		URL       string `yaml:"url"`
@@ -77,7 +117,54 @@ type AdminConfig struct {
 			AllowedExecutors []string          `yaml:"allowedExecutors"`
 			SecretsAccess    map[string]string `yaml:"secretsAccess"`
 		} `yaml:"security"`
+		ApplicationVariables struct {
+			AllowedKeys []string `yaml:"allowedKeys"`
+		} `yaml:"applicationVariables"`
+		StepEnvironment struct {
+			Inherit []string `yaml:"inherit"` // Server environment variables step processes inherit beyond the defaults
+		} `yaml:"stepEnvironment"`
+		StepLogs struct {
+			FlushBytes    int    `yaml:"flushBytes"`    // Step output buffered before it is written to the database (default 16384)
+			FlushInterval string `yaml:"flushInterval"` // Longest time output stays buffered, i.e. log streaming delay (default 1s)
+			MaxBytes      int    `yaml:"maxBytes"`      // Output stored per step; the middle of longer output is dropped (default 10 MiB)
+			HeadBytes     int    `yaml:"headBytes"`     // Share of maxBytes kept from the start of the output (default half)
+			Archive       struct {
+				Bucket string `yaml:"bucket"` // MinIO bucket full output of truncated steps is uploaded to; empty drops it
+				Prefix string `yaml:"prefix"` // Object key prefix (default step-logs/)
+			} `yaml:"archive"`
+		} `yaml:"stepLogs"`
+		KubernetesIdentity struct {
+			Mode        string `yaml:"mode"`        // server (default), impersonate or kubeconfig
+			ClusterRole string `yaml:"clusterRole"` // Bound to each application's service account in the namespaces it owns (default edit)
+		} `yaml:"kubernetesIdentity"`
+		CloudCredentials cloudcreds.Config `yaml:"cloudCredentials"` // Short-lived cloud credentials for provisioning steps
 	} `yaml:"workflowPolicies"`
+	Provisioning struct {
+		MaxConcurrent int            `yaml:"maxConcurrent"` // Resources the orchestration engine provisions in parallel (default 1)
+		ResourceTypes map[string]int `yaml:"resourceTypes"` // Concurrent provisions per resource type, e.g. postgres: 2
+		Providers     map[string]int `yaml:"providers"`     // Concurrent provisions per provider
+
+		DriftCheckInterval string `yaml:"driftCheckInterval"` // How often active resources are checked for drift (default 10m, "0" turns it off)
+	} `yaml:"provisioning"`
+	ChangeManagement changemgmt.Config    `yaml:"changeManagement"`
+	ScoreLint        scorelint.Config     `yaml:"scoreLint"`
+	Authentication   auth.Config          `yaml:"authentication"`
+	SCIM             scim.Config          `yaml:"scim"`
+	Usage            usage.Config         `yaml:"usage"`
+	HealthChecks     health.Config        `yaml:"healthChecks"`
+	Organizations    orgs.Config          `yaml:"organizations"`
+	Hibernation      hibernation.Config   `yaml:"hibernation"`
+	Naming           naming.Config        `yaml:"naming"`
+	Notifications    notifications.Config `yaml:"notifications"`
+	Slack            slack.Config         `yaml:"slack"`
+	Alerting         alerting.Config      `yaml:"alerting"`
+	CMDB             cmdb.Config          `yaml:"cmdb"`       // Webhooks receiving resource changes
+	TempAssets       tempassets.Config    `yaml:"tempAssets"` // Garbage collection of clones and manifests steps leave in /tmp
+	Secrets          secrets.Config       `yaml:"secrets"`    // Store ${secrets.<path>.<key>} references are resolved from
+	Server           ServerConfig         `yaml:"server"`     // CORS and rate limits, re-applied by POST /api/admin/reload
+	Rego             RegoConfig           `yaml:"rego"`       // Policies every policy step evaluates
+
+	secretFields map[string]bool // Credential fields whose value came from a secret reference
 }
 
 // ProviderSource defines a source for loading providers
@@ -91,6 +178,102 @@ type ProviderSource struct {
 	Enabled    bool   `yaml:"enabled"`              // Whether this provider is enabled
 }
 
+// DefaultProviderCacheDir is where Git providers are cloned unless providerAssets.cacheDir is set
+const DefaultProviderCacheDir = "/tmp/innominatus-providers"
+
+// ProviderAssetConfig configures where Git providers are cloned and how often their refs are checked
+type ProviderAssetConfig struct {
+	CacheDir        string `yaml:"cacheDir" json:"cacheDir"`               // Clone directory (default /tmp/innominatus-providers)
+	RefreshInterval string `yaml:"refreshInterval" json:"refreshInterval"` // e.g. 5m (default); 0 disables refreshing
+}
+
+// Validate checks the refresh interval
+func (c ProviderAssetConfig) Validate() error {
+	if _, err := c.Interval(); err != nil {
+		return fmt.Errorf("providerAssets: %w", err)
+	}
+	return nil
+}
+
+// Interval returns how often Git refs are checked, 5 minutes by default and 0 when disabled
+func (c ProviderAssetConfig) Interval() (time.Duration, error) {
+	switch c.RefreshInterval {
+	case "":
+		return 5 * time.Minute, nil
+	case "0":
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(c.RefreshInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid refreshInterval '%s': %w", c.RefreshInterval, err)
+	}
+	if interval < time.Minute {
+		return 0, fmt.Errorf("refreshInterval must be at least 1m, got %s", c.RefreshInterval)
+	}
+	return interval, nil
+}
+
+// RegoConfig lists the OPA rego policies of policy steps; without any, the policies in
+// policies/workload are used
+type RegoConfig struct {
+	Paths   []string          `yaml:"paths" json:"paths"`     // Rego files or directories
+	Modules map[string]string `yaml:"modules" json:"modules"` // Inline policies by module name
+}
+
+// ServerConfig holds the HTTP server settings that can change without a restart
+type ServerConfig struct {
+	CORS      CORSConfig      `yaml:"cors" json:"cors"`
+	RateLimit RateLimitConfig `yaml:"rateLimit" json:"rateLimit"`
+}
+
+// CORSConfig lists the browser origins allowed to call the API with credentials
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowedOrigins" json:"allowedOrigins"` // e.g. https://idp.example.com; replaces the local development origins
+}
+
+// RateLimitConfig limits API requests per minute; zero limits use the defaults
+type RateLimitConfig struct {
+	Enabled    bool           `yaml:"enabled" json:"enabled"`
+	PerUserRPM int            `yaml:"perUserRPM" json:"perUserRPM"` // Per authenticated user (default 100)
+	PerIPRPM   int            `yaml:"perIPRPM" json:"perIPRPM"`     // Per client IP (default 200)
+	BurstSize  int            `yaml:"burstSize" json:"burstSize"`   // Default 10
+	Endpoints  map[string]int `yaml:"endpoints" json:"endpoints"`   // Limits of exact paths such as /api/login; replaces the default endpoint limits
+}
+
+// Validate checks the allowed origins and the limits
+func (c ServerConfig) Validate() error {
+	for _, origin := range c.CORS.AllowedOrigins {
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			return fmt.Errorf("server.cors: invalid allowed origin '%s', expected scheme://host[:port]", origin)
+		}
+	}
+
+	limit := c.RateLimit
+	if limit.PerUserRPM < 0 || limit.PerIPRPM < 0 || limit.BurstSize < 0 {
+		return fmt.Errorf("server.rateLimit: perUserRPM, perIPRPM and burstSize must not be negative")
+	}
+	paths := make([]string, 0, len(limit.Endpoints))
+	for path := range limit.Endpoints {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if limit.Endpoints[path] <= 0 {
+			return fmt.Errorf("server.rateLimit: limit of endpoint %s must be positive, got %d", path, limit.Endpoints[path])
+		}
+	}
+	return nil
+}
+
+// Dir returns the clone directory of Git providers
+func (c ProviderAssetConfig) Dir() string {
+	if c.CacheDir == "" {
+		return DefaultProviderCacheDir
+	}
+	return c.CacheDir
+}
+
 func LoadAdminConfig(configPath string) (*AdminConfig, error) {
 	// Validate config path to prevent path traversal
 	if err := security.ValidateConfigPath(configPath); err != nil {
@@ -117,9 +300,69 @@ func LoadAdminConfig(configPath string) (*AdminConfig, error) {
 		config.ResourceDefinitions = make(map[string]string)
 	}
 
+	if err := config.resolveSecrets(); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
+// secretResolveTimeout bounds the resolution of the secret references of the config
+const secretResolveTimeout = 30 * time.Second
+
+// credentialFields returns the fields holding credentials, by their path in the config
+func (c *AdminConfig) credentialFields() map[string]*string {
+	return map[string]*string{
+		"gitea.password":         &c.Gitea.Password,
+		"argocd.password":        &c.ArgoCD.Password,
+		"vault.token":            &c.Vault.Token,
+		"keycloak.adminPassword": &c.Keycloak.AdminPassword,
+		"minio.accessKey":        &c.Minio.AccessKey,
+		"minio.secretKey":        &c.Minio.SecretKey,
+		"grafana.password":       &c.Grafana.Password,
+	}
+}
+
+// resolveSecrets replaces ${secrets.<path>.<key>} references in credential fields with
+// the values from the configured secret store
+func (c *AdminConfig) resolveSecrets() error {
+	c.secretFields = make(map[string]bool)
+	var resolver *secrets.Resolver
+	for name, field := range c.credentialFields() {
+		if !secrets.HasReference(*field) {
+			continue
+		}
+		if resolver == nil {
+			var err error
+			if resolver, err = secrets.ResolverFor(c.Secrets); err != nil {
+				return fmt.Errorf("failed to resolve %s: %w", name, err)
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+		value, err := resolver.Interpolate(ctx, *field)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", name, err)
+		}
+		*field = value
+		c.secretFields[name] = true
+	}
+	return nil
+}
+
+// PlaintextCredentials returns the credential fields set in the config file itself rather
+// than referenced from the secret store, sorted
+func (c *AdminConfig) PlaintextCredentials() []string {
+	var names []string
+	for name, field := range c.credentialFields() {
+		if *field != "" && !c.secretFields[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // String returns a formatted string representation of the admin configuration
 func (c *AdminConfig) String() string {
 	var result string
@@ -156,6 +399,26 @@ func (c *AdminConfig) String() string {
 	result += fmt.Sprintf("  Max Concurrent Workflows: %d\n", c.WorkflowPolicies.MaxConcurrentWorkflows)
 	result += fmt.Sprintf("  Max Steps Per Workflow: %d\n", c.WorkflowPolicies.MaxStepsPerWorkflow)
 	result += fmt.Sprintf("  Allowed Step Types: %v\n", c.WorkflowPolicies.AllowedStepTypes)
+	result += fmt.Sprintf("  Allowed Application Variables: %v\n", c.WorkflowPolicies.ApplicationVariables.AllowedKeys)
+	result += fmt.Sprintf("  Inherited Step Environment: %v\n", c.WorkflowPolicies.StepEnvironment.Inherit)
+	result += fmt.Sprintf("  Kubernetes Identity: %s\n", c.WorkflowPolicies.KubernetesIdentity.Mode)
+	result += fmt.Sprintf("  Cloud Credential Grants: %d\n", len(c.WorkflowPolicies.CloudCredentials.Grants))
+
+	result += "Provisioning:\n"
+	result += fmt.Sprintf("  Max Concurrent: %d\n", c.Provisioning.MaxConcurrent)
+	result += fmt.Sprintf("  Resource Type Limits: %v\n", c.Provisioning.ResourceTypes)
+	result += fmt.Sprintf("  Provider Limits: %v\n", c.Provisioning.Providers)
+	if c.Provisioning.DriftCheckInterval != "" {
+		result += fmt.Sprintf("  Drift Check Interval: %s\n", c.Provisioning.DriftCheckInterval)
+	}
+
+	result += "Rego Policies:\n"
+	result += fmt.Sprintf("  Paths: %v\n", c.Rego.Paths)
+	result += fmt.Sprintf("  Inline Modules: %d\n", len(c.Rego.Modules))
+
+	result += "Server:\n"
+	result += fmt.Sprintf("  CORS Allowed Origins: %v\n", c.Server.CORS.AllowedOrigins)
+	result += fmt.Sprintf("  Rate Limiting: %t\n", c.Server.RateLimit.Enabled)
 
 	return result
 }
@@ -166,6 +429,38 @@ func (c *AdminConfig) PrintConfig() {
 	fmt.Print(c.String())
 }
 
+// ValidateApplicationVariables checks Score metadata.variables against workflowPolicies.applicationVariables.allowedKeys.
+// Without allowed keys no application variables are accepted.
+func (c *AdminConfig) ValidateApplicationVariables(variables map[string]string) error {
+	if len(variables) == 0 {
+		return nil
+	}
+
+	allowed := make(map[string]bool, len(c.WorkflowPolicies.ApplicationVariables.AllowedKeys))
+	for _, key := range c.WorkflowPolicies.ApplicationVariables.AllowedKeys {
+		allowed[key] = true
+	}
+
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !variableKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid application variable name '%s': must start with a letter or underscore and contain only letters, digits and underscores", key)
+		}
+		if !allowed[key] {
+			if len(allowed) == 0 {
+				return fmt.Errorf("application variable '%s' is not allowed: no application variables are configured in workflowPolicies.applicationVariables.allowedKeys", key)
+			}
+			return fmt.Errorf("application variable '%s' is not allowed (allowed: %s)", key, strings.Join(c.WorkflowPolicies.ApplicationVariables.AllowedKeys, ", "))
+		}
+	}
+	return nil
+}
+
 func (c *AdminConfig) GetResourceDefinition(resourceType string) (string, bool) {
 	definition, exists := c.ResourceDefinitions[resourceType]
 	return definition, exists
@@ -178,7 +473,16 @@ type MaskedAdminConfig struct {
 		DefaultRuntime    string `json:"defaultRuntime"`
 		SplunkIndex       string `json:"splunkIndex"`
 	} `json:"admin"`
-	ResourceDefinitions map[string]string `json:"resourceDefinitions"`
+	Logging struct {
+		Level  string `json:"level"`
+		Format string `json:"format"`
+	} `json:"logging"`
+	CLI struct {
+		MinVersion         string `json:"minVersion"`
+		RecommendedVersion string `json:"recommendedVersion"`
+	} `json:"cli"`
+	ProviderAssets      ProviderAssetConfig `json:"providerAssets"`
+	ResourceDefinitions map[string]string   `json:"resourceDefinitions"`
 	Policies            struct {
 		EnforceBackups      bool     `json:"enforceBackups"`
 		AllowedEnvironments []string `json:"allowedEnvironments"`
@@ -189,11 +493,15 @@ type MaskedAdminConfig struct {
 		Username    string `json:"username"`
 		Password    string `json:"password"` // Will be "****"
 		OrgName     string `json:"orgName"`
+
+		TeamProvisioning giteateams.Config `json:"teamProvisioning"`
 	} `json:"gitea"`
 	ArgoCD struct {
 		URL      string `json:"url"`
 		Username string `json:"username"`
 		Password string `json:"password"` // Will be "****"
+
+		TeamProjects argoprojects.Config `json:"teamProjects"`
 	} `json:"argocd"`
 	Vault struct {
 		URL       string `json:"url"`
@@ -240,7 +548,41 @@ type MaskedAdminConfig struct {
 			AllowedExecutors []string          `json:"allowedExecutors"`
 			SecretsAccess    map[string]string `json:"secretsAccess"`
 		} `json:"security"`
+		ApplicationVariables struct {
+			AllowedKeys []string `json:"allowedKeys"`
+		} `json:"applicationVariables"`
+		StepEnvironment struct {
+			Inherit []string `json:"inherit"` // Server environment variables step processes inherit beyond the defaults
+		} `json:"stepEnvironment"`
+		KubernetesIdentity struct {
+			Mode        string `json:"mode"`
+			ClusterRole string `json:"clusterRole"`
+		} `json:"kubernetesIdentity"`
+		CloudCredentials cloudcreds.Config `json:"cloudCredentials"` // Holds role and account identifiers only, no secrets
 	} `json:"workflowPolicies"`
+	Provisioning struct {
+		MaxConcurrent int            `json:"maxConcurrent"`
+		ResourceTypes map[string]int `json:"resourceTypes"`
+		Providers     map[string]int `json:"providers"`
+
+		DriftCheckInterval string `json:"driftCheckInterval,omitempty"`
+	} `json:"provisioning"`
+	ChangeManagement changemgmt.Config    `json:"changeManagement"` // Holds only the name of the token variable
+	ScoreLint        scorelint.Config     `json:"scoreLint"`
+	Authentication   auth.Config          `json:"authentication"` // Holds only the name of the bind password variable
+	SCIM             scim.Config          `json:"scim"`           // Holds only the name of the token variable
+	Usage            usage.Config         `json:"usage"`          // Holds only the name of the token variable
+	HealthChecks     health.Config        `json:"healthChecks"`
+	Organizations    orgs.Config          `json:"organizations"`
+	Hibernation      hibernation.Config   `json:"hibernation"`   // Holds only the name of the token variable
+	Notifications    notifications.Config `json:"notifications"` // Webhook URLs are masked
+	Slack            slack.Config         `json:"slack"`         // Holds only the name of the signing secret variable
+	Alerting         alerting.Config      `json:"alerting"`      // Holds only the name of the key variable
+	CMDB             cmdb.Config          `json:"cmdb"`          // Holds only the names of the signing secret variables
+	TempAssets       tempassets.Config    `json:"tempAssets"`
+	Secrets          secrets.Config       `json:"secrets"` // Holds only the location of the secrets
+	Server           ServerConfig         `json:"server"`
+	Rego             RegoConfig           `json:"rego"`
 }
 
 // ToMaskedJSON returns a JSON-serializable version with sensitive data masked
@@ -254,6 +596,33 @@ func (c *AdminConfig) ToMaskedJSON() *MaskedAdminConfig {
 	masked.Admin.DefaultRuntime = c.Admin.DefaultRuntime
 	masked.Admin.SplunkIndex = c.Admin.SplunkIndex
 
+	// Copy logging settings
+	masked.Logging.Level = c.Logging.Level
+	masked.Logging.Format = c.Logging.Format
+	masked.CLI.MinVersion = c.CLI.MinVersion
+	masked.CLI.RecommendedVersion = c.CLI.RecommendedVersion
+	masked.Provisioning.MaxConcurrent = c.Provisioning.MaxConcurrent
+	masked.Provisioning.ResourceTypes = c.Provisioning.ResourceTypes
+	masked.Provisioning.Providers = c.Provisioning.Providers
+	masked.Provisioning.DriftCheckInterval = c.Provisioning.DriftCheckInterval
+	masked.ProviderAssets = c.ProviderAssets
+	masked.ChangeManagement = c.ChangeManagement
+	masked.ScoreLint = c.ScoreLint
+	masked.Authentication = c.Authentication
+	masked.SCIM = c.SCIM
+	masked.Usage = c.Usage
+	masked.HealthChecks = c.HealthChecks
+	masked.Organizations = c.Organizations
+	masked.Hibernation = c.Hibernation
+	masked.TempAssets = c.TempAssets
+	masked.Notifications = c.Notifications.Masked()
+	masked.Slack = c.Slack
+	masked.Alerting = c.Alerting
+	masked.CMDB = c.CMDB
+	masked.Secrets = c.Secrets
+	masked.Server = c.Server
+	masked.Rego = c.Rego
+
 	// Copy policies
 	masked.Policies.EnforceBackups = c.Policies.EnforceBackups
 	masked.Policies.AllowedEnvironments = c.Policies.AllowedEnvironments
@@ -264,11 +633,13 @@ func (c *AdminConfig) ToMaskedJSON() *MaskedAdminConfig {
 	masked.Gitea.Username = c.Gitea.Username
 	masked.Gitea.Password = "****"
 	masked.Gitea.OrgName = c.Gitea.OrgName
+	masked.Gitea.TeamProvisioning = c.Gitea.TeamProvisioning
 
 	// Copy ArgoCD config with masked password
 	masked.ArgoCD.URL = c.ArgoCD.URL
 	masked.ArgoCD.Username = c.ArgoCD.Username
 	masked.ArgoCD.Password = "****"
+	masked.ArgoCD.TeamProjects = c.ArgoCD.TeamProjects
 
 	// Copy Vault config with masked token
 	masked.Vault.URL = c.Vault.URL
@@ -316,5 +687,14 @@ func (c *AdminConfig) ToMaskedJSON() *MaskedAdminConfig {
 	masked.WorkflowPolicies.Security.AllowedExecutors = c.WorkflowPolicies.Security.AllowedExecutors
 	masked.WorkflowPolicies.Security.SecretsAccess = c.WorkflowPolicies.Security.SecretsAccess
 
+	// Copy application variable policy
+	masked.WorkflowPolicies.ApplicationVariables.AllowedKeys = c.WorkflowPolicies.ApplicationVariables.AllowedKeys
+
+	// Copy step environment policy (variable names only)
+	masked.WorkflowPolicies.StepEnvironment.Inherit = c.WorkflowPolicies.StepEnvironment.Inherit
+	masked.WorkflowPolicies.KubernetesIdentity.Mode = c.WorkflowPolicies.KubernetesIdentity.Mode
+	masked.WorkflowPolicies.KubernetesIdentity.ClusterRole = c.WorkflowPolicies.KubernetesIdentity.ClusterRole
+	masked.WorkflowPolicies.CloudCredentials = c.WorkflowPolicies.CloudCredentials
+
 	return masked
 }
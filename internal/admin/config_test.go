@@ -296,6 +296,47 @@ argocd:
 	assert.Equal(t, "argo-secret", config.ArgoCD.Password)
 }
 
+func TestSecretReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "secrets-config.yaml")
+	t.Setenv("TEST_SECRET_GITEA_PASSWORD", "gitea-pw")
+	t.Setenv("TEST_SECRET_PLATFORM_MINIO_SECRET_KEY", "minio-pw")
+
+	configContent := `
+secrets:
+  provider: env
+  env:
+    prefix: TEST_SECRET_
+gitea:
+  username: "platform-admin"
+  password: "${secrets.gitea.password}"
+minio:
+  accessKey: "minioadmin"
+  secretKey: "${secrets.platform/minio.secret_key}"
+grafana:
+  password: "admin"
+`
+	require.NoError(t, os.WriteFile(configFile, []byte(configContent), 0644))
+
+	config, err := LoadAdminConfig(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, "gitea-pw", config.Gitea.Password)
+	assert.Equal(t, "minio-pw", config.Minio.SecretKey)
+	assert.Equal(t, []string{"grafana.password", "minio.accessKey"}, config.PlaintextCredentials())
+
+	configContent = `
+secrets:
+  env:
+    prefix: TEST_SECRET_
+argocd:
+  password: "${secrets.argocd.password}"
+`
+	require.NoError(t, os.WriteFile(configFile, []byte(configContent), 0644))
+	_, err = LoadAdminConfig(configFile)
+	assert.ErrorContains(t, err, "failed to resolve argocd.password")
+	assert.ErrorContains(t, err, "TEST_SECRET_ARGOCD_PASSWORD is not set")
+}
+
 func TestResourceDefinitions(t *testing.T) {
 	tmpDir := t.TempDir()
 	configFile := filepath.Join(tmpDir, "resources-config.yaml")
@@ -356,3 +397,55 @@ policies:
 	assert.Contains(t, config.Policies.AllowedEnvironments, "production")
 	assert.Contains(t, config.Policies.AllowedEnvironments, "preview")
 }
+
+func TestValidateApplicationVariables(t *testing.T) {
+	config := &AdminConfig{}
+	config.WorkflowPolicies.ApplicationVariables.AllowedKeys = []string{"cost_center", "domain_suffix"}
+
+	tests := []struct {
+		name      string
+		config    *AdminConfig
+		variables map[string]string
+		wantErr   string
+	}{
+		{"no variables", config, nil, ""},
+		{"allowed keys", config, map[string]string{"cost_center": "cc-1234", "domain_suffix": "shop.example.com"}, ""},
+		{"unknown key", config, map[string]string{"cost_center": "cc-1234", "owner": "me"}, "application variable 'owner' is not allowed (allowed: cost_center, domain_suffix)"},
+		{"invalid name", config, map[string]string{"cost-center": "cc-1234"}, "invalid application variable name 'cost-center'"},
+		{"no allowed keys configured", &AdminConfig{}, map[string]string{"cost_center": "cc-1234"}, "no application variables are configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.ValidateApplicationVariables(tt.variables)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestApplicationVariablesConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "admin-config.yaml")
+
+	configContent := `
+workflowPolicies:
+  applicationVariables:
+    allowedKeys:
+      - cost_center
+      - domain_suffix
+`
+
+	err := os.WriteFile(configFile, []byte(configContent), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadAdminConfig(configFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"cost_center", "domain_suffix"}, config.WorkflowPolicies.ApplicationVariables.AllowedKeys)
+	assert.Equal(t, []string{"cost_center", "domain_suffix"}, config.ToMaskedJSON().WorkflowPolicies.ApplicationVariables.AllowedKeys)
+}
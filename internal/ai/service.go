@@ -10,6 +10,9 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// ProviderAPIURL is the API of the LLM provider the assistant calls
+const ProviderAPIURL = "https://api.anthropic.com"
+
 // Service provides AI assistance functionality
 type Service struct {
 	sdk     *platformai.SDK
@@ -0,0 +1,180 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/events"
+	"innominatus/internal/logging"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Alerter opens incidents for failed production deployments and resources that stay
+// unhealthy, and resolves them again
+type Alerter struct {
+	config func() Config
+	logger *logging.ZerologAdapter
+
+	mu        sync.Mutex
+	unhealthy map[string]*unhealthyResource // By resource key
+}
+
+// unhealthyResource tracks a resource from the first check that found it unhealthy
+type unhealthyResource struct {
+	since time.Time
+	paged bool
+}
+
+// ResourceHealth is the health of a resource of a production application at a check
+type ResourceHealth struct {
+	App       string
+	Team      string
+	Name      string
+	Type      string
+	Unhealthy bool
+	Reason    string
+}
+
+// NewAlerter creates an alerter. config is called for every event and check, so changes
+// to admin-config.yaml apply without a restart.
+func NewAlerter(config func() Config) *Alerter {
+	return &Alerter{
+		config:    config,
+		logger:    logging.NewStructuredLogger("alerting"),
+		unhealthy: make(map[string]*unhealthyResource),
+	}
+}
+
+// Start subscribes the alerter to the workflow events on bus
+func (a *Alerter) Start(bus events.EventBus) {
+	types := []events.EventType{events.EventTypeWorkflowFailed, events.EventTypeWorkflowCompleted}
+	bus.Subscribe("", types, func(event events.Event) {
+		if err := a.HandleEvent(context.Background(), event); err != nil {
+			a.logger.WarnWithFields("Failed to send alert", map[string]interface{}{
+				"type":     string(event.Type),
+				"app_name": event.AppName,
+				"error":    err.Error(),
+			})
+		}
+	})
+}
+
+// HandleEvent opens an incident when a workflow in a production environment fails and
+// resolves it when a later run for the same application succeeds
+func (a *Alerter) HandleEvent(ctx context.Context, event events.Event) error {
+	config := a.config()
+	environment, _ := event.Data["environment"].(string)
+	if !config.Enabled() || !config.Production(environment) {
+		return nil
+	}
+	workflowName, _ := event.Data["workflow_name"].(string)
+
+	switch event.Type {
+	case events.EventTypeWorkflowFailed:
+		step, _ := event.Data["step_name"].(string)
+		errorMessage, _ := event.Data["error"].(string)
+		if err := Trigger(ctx, config, Alert{
+			Key:     DeploymentKey(event.AppName),
+			Summary: fmt.Sprintf("%s: %s failed in %s at step %s", event.AppName, workflowName, environment, step),
+			Details: map[string]interface{}{
+				"application":  event.AppName,
+				"environment":  environment,
+				"workflow":     workflowName,
+				"execution_id": event.Data["execution_id"],
+				"step":         step,
+				"error":        errorMessage,
+			},
+		}); err != nil {
+			return err
+		}
+		a.logger.InfoWithFields("Opened deployment incident", map[string]interface{}{
+			"app_name":    event.AppName,
+			"environment": environment,
+		})
+
+	case events.EventTypeWorkflowCompleted:
+		return Resolve(ctx, config, DeploymentKey(event.AppName))
+	}
+	return nil
+}
+
+// CheckResources opens an incident for every resource that has been unhealthy for
+// longer than alerting.unhealthyAfter, and resolves incidents of resources that
+// recovered or are no longer listed
+func (a *Alerter) CheckResources(ctx context.Context, resources []ResourceHealth, now time.Time) error {
+	config := a.config()
+	if !config.Enabled() {
+		return nil
+	}
+	unhealthyAfter, _, err := config.Durations()
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var errs []error
+	seen := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		key := ResourceKey(resource.App, resource.Name)
+		if !resource.Unhealthy {
+			continue
+		}
+		seen[key] = true
+
+		tracked, ok := a.unhealthy[key]
+		if !ok {
+			tracked = &unhealthyResource{since: now}
+			a.unhealthy[key] = tracked
+		}
+		if tracked.paged || now.Sub(tracked.since) < unhealthyAfter {
+			continue
+		}
+
+		err := Trigger(ctx, config, Alert{
+			Key:     key,
+			Summary: fmt.Sprintf("%s: %s %s unhealthy for %s", resource.App, resource.Type, resource.Name, now.Sub(tracked.since).Round(time.Minute)),
+			Details: map[string]interface{}{
+				"application":     resource.App,
+				"team":            resource.Team,
+				"resource":        resource.Name,
+				"resource_type":   resource.Type,
+				"unhealthy_since": tracked.since.UTC().Format(time.RFC3339),
+				"reason":          resource.Reason,
+			},
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		tracked.paged = true
+		a.logger.InfoWithFields("Opened resource incident", map[string]interface{}{
+			"app_name": resource.App,
+			"resource": resource.Name,
+		})
+	}
+
+	keys := make([]string, 0, len(a.unhealthy))
+	for key := range a.unhealthy {
+		if !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if a.unhealthy[key].paged {
+			if err := Resolve(ctx, config, key); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		delete(a.unhealthy, key)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d alert(s) failed, first: %w", len(errs), errs[0])
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+// Package alerting opens incidents in PagerDuty or Opsgenie for failed production
+// deployments and for resources that stay unhealthy, and resolves them once the next run
+// succeeds or the resource recovers. Every incident has a deduplication key per
+// application, so repeated failures update one incident instead of paging again.
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultUnhealthyAfter is how long a resource must be unhealthy before an incident is opened
+	DefaultUnhealthyAfter = 15 * time.Minute
+	// DefaultCheckInterval is how often resource health is checked
+	DefaultCheckInterval = time.Minute
+	// DefaultSeverity is the severity of incidents without a configured one
+	DefaultSeverity = "critical"
+
+	// Providers
+	ProviderPagerDuty = "pagerduty"
+	ProviderOpsgenie  = "opsgenie"
+
+	pagerDutyURL   = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieURL    = "https://api.opsgenie.com/v2/alerts"
+	source         = "innominatus"
+	requestTimeout = 10 * time.Second
+)
+
+// DefaultEnvironments are the production environments without a configured list
+var DefaultEnvironments = []string{"production", "prod"}
+
+// severities are PagerDuty's severities, mapped to Opsgenie priorities
+var severities = map[string]string{
+	"critical": "P1",
+	"error":    "P2",
+	"warning":  "P3",
+	"info":     "P5",
+}
+
+// Config is the alerting section of admin-config.yaml
+type Config struct {
+	Provider       string   `yaml:"provider" json:"provider"`             // pagerduty or opsgenie; empty disables alerting
+	KeyEnv         string   `yaml:"keyEnv" json:"keyEnv"`                 // Environment variable holding the PagerDuty routing key or Opsgenie API key
+	URL            string   `yaml:"url" json:"url"`                       // API endpoint, e.g. https://api.eu.opsgenie.com/v2/alerts (default: the provider's)
+	Environments   []string `yaml:"environments" json:"environments"`     // Environments that page (default production, prod)
+	Severity       string   `yaml:"severity" json:"severity"`             // critical, error, warning or info (default critical)
+	UnhealthyAfter string   `yaml:"unhealthyAfter" json:"unhealthyAfter"` // How long a resource is unhealthy before it pages (default 15m)
+	CheckInterval  string   `yaml:"checkInterval" json:"checkInterval"`   // How often resource health is checked (default 1m)
+}
+
+// Enabled reports whether incidents are opened
+func (c Config) Enabled() bool {
+	return c.Provider != ""
+}
+
+// Production reports whether failures in an environment page
+func (c Config) Production(environment string) bool {
+	if environment == "" {
+		return false
+	}
+	environments := c.Environments
+	if len(environments) == 0 {
+		environments = DefaultEnvironments
+	}
+	for _, env := range environments {
+		if strings.EqualFold(env, environment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Durations returns the unhealthy threshold and check interval, applying the defaults
+func (c Config) Durations() (unhealthyAfter, checkInterval time.Duration, err error) {
+	unhealthyAfter, checkInterval = DefaultUnhealthyAfter, DefaultCheckInterval
+	if c.UnhealthyAfter != "" {
+		if unhealthyAfter, err = time.ParseDuration(c.UnhealthyAfter); err != nil || unhealthyAfter < 0 {
+			return 0, 0, fmt.Errorf("invalid alerting.unhealthyAfter '%s'", c.UnhealthyAfter)
+		}
+	}
+	if c.CheckInterval != "" {
+		if checkInterval, err = time.ParseDuration(c.CheckInterval); err != nil || checkInterval <= 0 {
+			return 0, 0, fmt.Errorf("invalid alerting.checkInterval '%s'", c.CheckInterval)
+		}
+	}
+	return unhealthyAfter, checkInterval, nil
+}
+
+// Validate checks the provider, endpoint, severity and durations
+func (c Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Provider != ProviderPagerDuty && c.Provider != ProviderOpsgenie {
+		return fmt.Errorf("alerting.provider must be %s or %s, got '%s'", ProviderPagerDuty, ProviderOpsgenie, c.Provider)
+	}
+	if c.KeyEnv == "" {
+		return fmt.Errorf("alerting.keyEnv is required")
+	}
+	if c.URL != "" {
+		if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("alerting.url '%s' is not an http(s) URL", c.URL)
+		}
+	}
+	if _, ok := severities[c.severity()]; !ok {
+		return fmt.Errorf("alerting.severity must be critical, error, warning or info, got '%s'", c.Severity)
+	}
+	_, _, err := c.Durations()
+	return err
+}
+
+func (c Config) severity() string {
+	if c.Severity == "" {
+		return DefaultSeverity
+	}
+	return c.Severity
+}
+
+// Alert describes an incident
+type Alert struct {
+	Key     string                 // Deduplication key; alerts with the same key update one incident
+	Summary string                 // One-line description
+	Details map[string]interface{} // Shown with the incident
+}
+
+// DeploymentKey is the deduplication key of failed deployments of an application
+func DeploymentKey(appName string) string {
+	return fmt.Sprintf("innominatus/%s/deployment", appName)
+}
+
+// ResourceKey is the deduplication key of an unhealthy resource of an application
+func ResourceKey(appName, resourceName string) string {
+	return fmt.Sprintf("innominatus/%s/resource/%s", appName, resourceName)
+}
+
+// Trigger opens an incident, or updates the open incident with the same key
+func Trigger(ctx context.Context, config Config, alert Alert) error {
+	switch config.Provider {
+	case ProviderPagerDuty:
+		return send(ctx, config, http.MethodPost, endpoint(config, pagerDutyURL), map[string]interface{}{
+			"routing_key":  os.Getenv(config.KeyEnv),
+			"event_action": "trigger",
+			"dedup_key":    alert.Key,
+			"payload": map[string]interface{}{
+				"summary":        alert.Summary,
+				"source":         source,
+				"severity":       config.severity(),
+				"custom_details": alert.Details,
+			},
+		})
+	case ProviderOpsgenie:
+		return send(ctx, config, http.MethodPost, endpoint(config, opsgenieURL), map[string]interface{}{
+			"message":  truncate(alert.Summary, 130),
+			"alias":    alert.Key,
+			"source":   source,
+			"priority": severities[config.severity()],
+			"details":  stringDetails(alert.Details),
+		})
+	}
+	return fmt.Errorf("unknown alerting provider '%s'", config.Provider)
+}
+
+// Resolve resolves the incident with a key. Resolving a key without an open incident is
+// accepted by both providers.
+func Resolve(ctx context.Context, config Config, key string) error {
+	switch config.Provider {
+	case ProviderPagerDuty:
+		return send(ctx, config, http.MethodPost, endpoint(config, pagerDutyURL), map[string]interface{}{
+			"routing_key":  os.Getenv(config.KeyEnv),
+			"event_action": "resolve",
+			"dedup_key":    key,
+		})
+	case ProviderOpsgenie:
+		closeURL := fmt.Sprintf("%s/%s/close?identifierType=alias", endpoint(config, opsgenieURL), url.PathEscape(key))
+		return send(ctx, config, http.MethodPost, closeURL, map[string]interface{}{
+			"source": source,
+		})
+	}
+	return fmt.Errorf("unknown alerting provider '%s'", config.Provider)
+}
+
+func endpoint(config Config, defaultURL string) string {
+	if config.URL != "" {
+		return strings.TrimSuffix(config.URL, "/")
+	}
+	return defaultURL
+}
+
+func send(ctx context.Context, config Config, method, target string, payload map[string]interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if config.Provider == ProviderOpsgenie {
+		req.Header.Set("Authorization", "GenieKey "+os.Getenv(config.KeyEnv))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send alert to %s: %w", config.Provider, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s returned %s: %s", config.Provider, resp.Status, bytes.TrimSpace(text))
+	}
+	return nil
+}
+
+// stringDetails converts details to strings, since Opsgenie only takes string values
+func stringDetails(details map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(details))
+	for k, v := range details {
+		result[k] = fmt.Sprint(v)
+	}
+	return result
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
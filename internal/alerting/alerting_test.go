@@ -0,0 +1,180 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"innominatus/internal/events"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a fake alerting API recording every request
+type recorder struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+type recordedRequest struct {
+	Path          string
+	Authorization string
+	Body          map[string]interface{}
+}
+
+func (rec *recorder) server(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		rec.mu.Lock()
+		rec.requests = append(rec.requests, recordedRequest{Path: r.URL.RequestURI(), Authorization: r.Header.Get("Authorization"), Body: body})
+		rec.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (rec *recorder) take() []recordedRequest {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	requests := rec.requests
+	rec.requests = nil
+	return requests
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{"disabled", Config{}, false},
+		{"pagerduty", Config{Provider: "pagerduty", KeyEnv: "PD_KEY"}, false},
+		{"opsgenie eu", Config{Provider: "opsgenie", KeyEnv: "OG_KEY", URL: "https://api.eu.opsgenie.com/v2/alerts", Severity: "warning"}, false},
+		{"unknown provider", Config{Provider: "victorops", KeyEnv: "KEY"}, true},
+		{"missing key", Config{Provider: "pagerduty"}, true},
+		{"bad severity", Config{Provider: "pagerduty", KeyEnv: "KEY", Severity: "sev1"}, true},
+		{"bad duration", Config{Provider: "pagerduty", KeyEnv: "KEY", UnhealthyAfter: "soon"}, true},
+		{"bad url", Config{Provider: "pagerduty", KeyEnv: "KEY", URL: "events.pagerduty.com"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProduction(t *testing.T) {
+	assert.True(t, Config{}.Production("Production"))
+	assert.False(t, Config{}.Production("staging"))
+	assert.False(t, Config{}.Production(""))
+	assert.True(t, Config{Environments: []string{"live"}}.Production("live"))
+	assert.False(t, Config{Environments: []string{"live"}}.Production("prod"))
+}
+
+func TestTriggerAndResolve(t *testing.T) {
+	t.Setenv("TEST_ALERT_KEY", "k3y")
+	rec := &recorder{}
+	server := rec.server(t)
+	alert := Alert{Key: DeploymentKey("shop"), Summary: "shop failed", Details: map[string]interface{}{"execution_id": int64(7)}}
+
+	pagerDuty := Config{Provider: ProviderPagerDuty, KeyEnv: "TEST_ALERT_KEY", URL: server.URL}
+	require.NoError(t, Trigger(context.Background(), pagerDuty, alert))
+	require.NoError(t, Resolve(context.Background(), pagerDuty, alert.Key))
+	requests := rec.take()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "trigger", requests[0].Body["event_action"])
+	assert.Equal(t, "k3y", requests[0].Body["routing_key"])
+	assert.Equal(t, "innominatus/shop/deployment", requests[0].Body["dedup_key"])
+	assert.Equal(t, "critical", requests[0].Body["payload"].(map[string]interface{})["severity"])
+	assert.Equal(t, "resolve", requests[1].Body["event_action"])
+	assert.Equal(t, "innominatus/shop/deployment", requests[1].Body["dedup_key"])
+
+	opsgenie := Config{Provider: ProviderOpsgenie, KeyEnv: "TEST_ALERT_KEY", URL: server.URL, Severity: "error"}
+	require.NoError(t, Trigger(context.Background(), opsgenie, alert))
+	require.NoError(t, Resolve(context.Background(), opsgenie, alert.Key))
+	requests = rec.take()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "GenieKey k3y", requests[0].Authorization)
+	assert.Equal(t, "innominatus/shop/deployment", requests[0].Body["alias"])
+	assert.Equal(t, "P2", requests[0].Body["priority"])
+	assert.Equal(t, "7", requests[0].Body["details"].(map[string]interface{})["execution_id"])
+	assert.Equal(t, "/innominatus%2Fshop%2Fdeployment/close?identifierType=alias", requests[1].Path)
+}
+
+func TestAlerterHandleEvent(t *testing.T) {
+	rec := &recorder{}
+	server := rec.server(t)
+	alerter := NewAlerter(func() Config {
+		return Config{Provider: ProviderPagerDuty, KeyEnv: "TEST_ALERT_KEY", URL: server.URL}
+	})
+
+	failed := func(environment string) events.Event {
+		return events.NewEvent(events.EventTypeWorkflowFailed, "shop", "test", map[string]interface{}{
+			"workflow_name": "golden-path-deploy-app",
+			"execution_id":  int64(7),
+			"step_name":     "migrate",
+			"error":         "exit status 1",
+			"environment":   environment,
+		})
+	}
+
+	require.NoError(t, alerter.HandleEvent(context.Background(), failed("staging")))
+	assert.Empty(t, rec.take(), "staging does not page")
+
+	require.NoError(t, alerter.HandleEvent(context.Background(), failed("production")))
+	requests := rec.take()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "shop: golden-path-deploy-app failed in production at step migrate", requests[0].Body["payload"].(map[string]interface{})["summary"])
+
+	require.NoError(t, alerter.HandleEvent(context.Background(), events.NewEvent(events.EventTypeWorkflowCompleted, "shop", "test",
+		map[string]interface{}{"workflow_name": "golden-path-deploy-app", "environment": "production"})))
+	requests = rec.take()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "resolve", requests[0].Body["event_action"])
+	assert.Equal(t, "innominatus/shop/deployment", requests[0].Body["dedup_key"])
+}
+
+func TestAlerterCheckResources(t *testing.T) {
+	rec := &recorder{}
+	server := rec.server(t)
+	alerter := NewAlerter(func() Config {
+		return Config{Provider: ProviderPagerDuty, KeyEnv: "TEST_ALERT_KEY", URL: server.URL, UnhealthyAfter: "10m"}
+	})
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	db := ResourceHealth{App: "shop", Team: "payments", Name: "db", Type: "postgres", Unhealthy: true, Reason: "connection refused"}
+
+	require.NoError(t, alerter.CheckResources(ctx, []ResourceHealth{db}, start))
+	require.NoError(t, alerter.CheckResources(ctx, []ResourceHealth{db}, start.Add(5*time.Minute)))
+	assert.Empty(t, rec.take(), "not unhealthy for long enough")
+
+	require.NoError(t, alerter.CheckResources(ctx, []ResourceHealth{db}, start.Add(10*time.Minute)))
+	require.NoError(t, alerter.CheckResources(ctx, []ResourceHealth{db}, start.Add(11*time.Minute)))
+	requests := rec.take()
+	require.Len(t, requests, 1, "paged once")
+	assert.Equal(t, "innominatus/shop/resource/db", requests[0].Body["dedup_key"])
+	assert.Equal(t, "shop: postgres db unhealthy for 10m0s", requests[0].Body["payload"].(map[string]interface{})["summary"])
+
+	db.Unhealthy = false
+	require.NoError(t, alerter.CheckResources(ctx, []ResourceHealth{db}, start.Add(12*time.Minute)))
+	requests = rec.take()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "resolve", requests[0].Body["event_action"])
+
+	// A resource that recovers before the threshold never pages
+	db.Unhealthy = true
+	require.NoError(t, alerter.CheckResources(ctx, []ResourceHealth{db}, start.Add(13*time.Minute)))
+	require.NoError(t, alerter.CheckResources(ctx, nil, start.Add(14*time.Minute)))
+	assert.Empty(t, rec.take())
+}
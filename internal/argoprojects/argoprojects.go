@@ -0,0 +1,441 @@
+// Package argoprojects maintains an ArgoCD AppProject per innominatus team, restricting
+// the team's Applications to its own destinations and source repositories.
+package argoprojects
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSyncInterval is how often the projects are synced to ArgoCD
+	DefaultSyncInterval = 10 * time.Minute
+	// InClusterServer is the API server of the cluster ArgoCD runs in
+	InClusterServer = "https://kubernetes.default.svc"
+
+	requestTimeout = 30 * time.Second
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Config is the argocd.teamProjects section of admin-config.yaml. Destinations and
+// source repositories may use $team, the team ID, and $app, which repeats the entry for
+// every application of the team.
+type Config struct {
+	Enabled          bool                  `yaml:"enabled" json:"enabled"`                   // Create a project per team and assign Applications to it
+	ProjectPrefix    string                `yaml:"projectPrefix" json:"projectPrefix"`       // Prefix of the project names, e.g. team- gives team-payments
+	SyncInterval     string                `yaml:"syncInterval" json:"syncInterval"`         // How often projects are synced (default 10m)
+	Destinations     []Destination         `yaml:"destinations" json:"destinations"`         // Allowed destinations of every team (default: $team-*, $app and $app-* in-cluster)
+	SourceRepos      []string              `yaml:"sourceRepos" json:"sourceRepos"`           // Allowed source repositories of every team (default *)
+	ClusterResources []Resource            `yaml:"clusterResources" json:"clusterResources"` // Cluster-scoped resources teams may sync (default Namespace)
+	Teams            map[string]TeamConfig `yaml:"teams" json:"teams"`                       // Additional destinations and repositories by team ID
+}
+
+// TeamConfig extends the project of one team
+type TeamConfig struct {
+	Destinations []Destination `yaml:"destinations" json:"destinations"`
+	SourceRepos  []string      `yaml:"sourceRepos" json:"sourceRepos"`
+}
+
+// Destination is a cluster and namespace pattern Applications of a project may deploy to
+type Destination struct {
+	Server    string `yaml:"server,omitempty" json:"server,omitempty"` // Cluster API server; Name or Server is required
+	Name      string `yaml:"name,omitempty" json:"name,omitempty"`     // Cluster name in ArgoCD
+	Namespace string `yaml:"namespace" json:"namespace"`               // Namespace, may contain * wildcards
+}
+
+// Resource is a Kubernetes resource kind
+type Resource struct {
+	Group string `yaml:"group" json:"group"`
+	Kind  string `yaml:"kind" json:"kind"`
+}
+
+// Validate checks the configuration
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if _, err := c.Interval(); err != nil {
+		return err
+	}
+	if c.ProjectPrefix != "" && invalidNameChars.MatchString(c.ProjectPrefix) {
+		return fmt.Errorf("argocd.teamProjects.projectPrefix '%s' may only contain lowercase letters, digits and -", c.ProjectPrefix)
+	}
+	if err := validateDestinations("argocd.teamProjects.destinations", c.Destinations); err != nil {
+		return err
+	}
+	for _, r := range c.ClusterResources {
+		if r.Kind == "" {
+			return fmt.Errorf("argocd.teamProjects.clusterResources: kind is required")
+		}
+	}
+	for team, tc := range c.Teams {
+		if err := validateDestinations(fmt.Sprintf("argocd.teamProjects.teams.%s.destinations", team), tc.Destinations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateDestinations(field string, destinations []Destination) error {
+	for i, d := range destinations {
+		if d.Server == "" && d.Name == "" {
+			return fmt.Errorf("%s[%d]: server or name is required", field, i)
+		}
+		if d.Namespace == "" {
+			return fmt.Errorf("%s[%d].namespace is required", field, i)
+		}
+	}
+	return nil
+}
+
+// Interval returns the sync interval, applying the default
+func (c Config) Interval() (time.Duration, error) {
+	if c.SyncInterval == "" {
+		return DefaultSyncInterval, nil
+	}
+	interval, err := time.ParseDuration(c.SyncInterval)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid argocd.teamProjects.syncInterval '%s'", c.SyncInterval)
+	}
+	return interval, nil
+}
+
+// ProjectName returns the AppProject of an innominatus team
+func (c Config) ProjectName(teamID string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(c.ProjectPrefix+teamID), "-")
+	return strings.Trim(name, "-")
+}
+
+// Team is an innominatus team with its applications
+type Team struct {
+	ID           string
+	Applications []string
+}
+
+// ProjectSpec is the part of an AppProject spec managed for a team
+type ProjectSpec struct {
+	Description              string        `json:"description"`
+	SourceRepos              []string      `json:"sourceRepos"`
+	Destinations             []Destination `json:"destinations"`
+	ClusterResourceWhitelist []Resource    `json:"clusterResourceWhitelist"`
+}
+
+// Spec returns the project spec of team
+func (c Config) Spec(team Team) ProjectSpec {
+	destinations := c.Destinations
+	if len(destinations) == 0 {
+		destinations = []Destination{
+			{Server: InClusterServer, Namespace: "$team-*"},
+			{Server: InClusterServer, Namespace: "$app"},
+			{Server: InClusterServer, Namespace: "$app-*"},
+		}
+	}
+	repos := c.SourceRepos
+	if len(repos) == 0 {
+		repos = []string{"*"}
+	}
+	resources := c.ClusterResources
+	if len(resources) == 0 {
+		resources = []Resource{{Group: "", Kind: "Namespace"}}
+	}
+	if tc, ok := c.Teams[team.ID]; ok {
+		destinations = append(append([]Destination{}, destinations...), tc.Destinations...)
+		repos = append(append([]string{}, repos...), tc.SourceRepos...)
+	}
+
+	spec := ProjectSpec{
+		Description:              fmt.Sprintf("Applications of team %s (managed by innominatus)", team.ID),
+		ClusterResourceWhitelist: resources,
+	}
+	seenDestinations := map[Destination]bool{}
+	for _, d := range destinations {
+		for _, namespace := range expand(d.Namespace, team) {
+			expanded := Destination{Server: d.Server, Name: d.Name, Namespace: namespace}
+			if !seenDestinations[expanded] {
+				seenDestinations[expanded] = true
+				spec.Destinations = append(spec.Destinations, expanded)
+			}
+		}
+	}
+	seenRepos := map[string]bool{}
+	for _, r := range repos {
+		for _, repo := range expand(r, team) {
+			if !seenRepos[repo] {
+				seenRepos[repo] = true
+				spec.SourceRepos = append(spec.SourceRepos, repo)
+			}
+		}
+	}
+	return spec
+}
+
+// expand replaces $team in pattern and repeats it for every application if it uses $app
+func expand(pattern string, team Team) []string {
+	pattern = strings.ReplaceAll(pattern, "$team", team.ID)
+	if !strings.Contains(pattern, "$app") {
+		return []string{pattern}
+	}
+	apps := append([]string{}, team.Applications...)
+	sort.Strings(apps)
+	values := make([]string, 0, len(apps))
+	for _, app := range apps {
+		values = append(values, strings.ReplaceAll(pattern, "$app", app))
+	}
+	return values
+}
+
+// Result describes what a sync changed in the project of a team
+type Result struct {
+	Team    string `json:"team"`
+	Project string `json:"project"`
+	Created bool   `json:"created,omitempty"`
+	Updated bool   `json:"updated,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Applications looks up the team of an application and the applications of a team
+type Applications interface {
+	GetApplication(name string) (*database.Application, error)
+	ListApplicationsByTeam(team string) ([]*database.Application, error)
+}
+
+// Provisioner creates and updates the projects through the ArgoCD API
+type Provisioner struct {
+	config   Config
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+	token    string
+}
+
+// NewProvisioner creates a provisioner using an ArgoCD account allowed to manage projects
+func NewProvisioner(config Config, baseURL, username, password string) *Provisioner {
+	return &Provisioner{
+		config:   config,
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// ProjectFor makes sure the project of the application's team is up to date and returns
+// its name. Applications without a team return "", so they keep the default project.
+func (p *Provisioner) ProjectFor(apps Applications, appName string) (string, error) {
+	app, err := apps.GetApplication(appName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get application %s: %w", appName, err)
+	}
+	if app.Team == "" {
+		return "", nil
+	}
+	teamApps, err := apps.ListApplicationsByTeam(app.Team)
+	if err != nil {
+		return "", fmt.Errorf("failed to list applications of team %s: %w", app.Team, err)
+	}
+
+	team := Team{ID: app.Team, Applications: []string{appName}}
+	for _, a := range teamApps {
+		if a.Name != appName {
+			team.Applications = append(team.Applications, a.Name)
+		}
+	}
+	result := p.Sync(team)
+	if result.Error != "" {
+		return "", fmt.Errorf("failed to sync ArgoCD project %s: %s", result.Project, result.Error)
+	}
+	return result.Project, nil
+}
+
+// SyncAll syncs the projects of all teams. A failing team does not stop the others.
+func (p *Provisioner) SyncAll(teams []Team) []Result {
+	results := make([]Result, 0, len(teams))
+	for _, team := range teams {
+		results = append(results, p.Sync(team))
+	}
+	return results
+}
+
+// Sync creates the project of team or makes its destinations, source repositories and
+// cluster resources match the configuration. Other settings of the project, such as
+// roles and sync windows, are kept.
+func (p *Provisioner) Sync(team Team) Result {
+	result := Result{Team: team.ID, Project: p.config.ProjectName(team.ID)}
+	created, updated, err := p.sync(result.Project, p.config.Spec(team))
+	if err != nil {
+		result.Error = err.Error()
+	}
+	result.Created, result.Updated = created, updated
+	return result
+}
+
+func (p *Provisioner) sync(name string, spec ProjectSpec) (bool, bool, error) {
+	status, body, err := p.request("GET", "/api/v1/projects/"+url.PathEscape(name), nil)
+	if err != nil {
+		return false, false, fmt.Errorf("get project %s: %w", name, err)
+	}
+
+	if status == http.StatusNotFound {
+		project := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":   name,
+				"labels": map[string]string{"managed-by": "innominatus"},
+			},
+			"spec": spec,
+		}
+		status, body, err = p.request("POST", "/api/v1/projects", map[string]interface{}{"project": project})
+		if err != nil {
+			return false, false, fmt.Errorf("create project %s: %w", name, err)
+		}
+		if status != http.StatusOK {
+			return false, false, fmt.Errorf("create project %s, status %d: %s", name, status, body)
+		}
+		return true, false, nil
+	}
+	if status != http.StatusOK {
+		return false, false, fmt.Errorf("get project %s, status %d: %s", name, status, body)
+	}
+
+	var project map[string]interface{}
+	if err := json.Unmarshal(body, &project); err != nil {
+		return false, false, fmt.Errorf("parse project %s: %w", name, err)
+	}
+	existing, _ := project["spec"].(map[string]interface{})
+	if existing == nil {
+		existing = map[string]interface{}{}
+	}
+	if sameSpec(existing, spec) {
+		return false, false, nil
+	}
+
+	existing["description"] = spec.Description
+	existing["sourceRepos"] = spec.SourceRepos
+	existing["destinations"] = spec.Destinations
+	existing["clusterResourceWhitelist"] = spec.ClusterResourceWhitelist
+	project["spec"] = existing
+	status, body, err = p.request("PUT", "/api/v1/projects/"+url.PathEscape(name), map[string]interface{}{"project": project})
+	if err != nil {
+		return false, false, fmt.Errorf("update project %s: %w", name, err)
+	}
+	if status != http.StatusOK {
+		return false, false, fmt.Errorf("update project %s, status %d: %s", name, status, body)
+	}
+	return false, true, nil
+}
+
+// sameSpec returns true if the managed fields of an existing project spec match spec,
+// ignoring their order
+func sameSpec(existing map[string]interface{}, spec ProjectSpec) bool {
+	var current ProjectSpec
+	data, err := json.Marshal(existing)
+	if err != nil || json.Unmarshal(data, &current) != nil {
+		return false
+	}
+	return current.Description == spec.Description &&
+		sameStrings(current.SourceRepos, spec.SourceRepos) &&
+		sameStrings(destinationKeys(current.Destinations), destinationKeys(spec.Destinations)) &&
+		sameStrings(resourceKeys(current.ClusterResourceWhitelist), resourceKeys(spec.ClusterResourceWhitelist))
+}
+
+func destinationKeys(destinations []Destination) []string {
+	keys := make([]string, 0, len(destinations))
+	for _, d := range destinations {
+		keys = append(keys, d.Server+"|"+d.Name+"|"+d.Namespace)
+	}
+	return keys
+}
+
+func resourceKeys(resources []Resource) []string {
+	keys := make([]string, 0, len(resources))
+	for _, r := range resources {
+		keys = append(keys, r.Group+"/"+r.Kind)
+	}
+	return keys
+}
+
+// sameStrings returns true if a and b hold the same values in any order
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]string{}, a...), append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// request calls the ArgoCD API, logging in on the first request
+func (p *Provisioner) request(method, path string, payload interface{}) (int, []byte, error) {
+	if p.token == "" {
+		token, err := p.login()
+		if err != nil {
+			return 0, nil, err
+		}
+		p.token = token
+	}
+	return p.do(method, path, payload, p.token)
+}
+
+// login returns a session token of the account
+func (p *Provisioner) login() (string, error) {
+	status, body, err := p.do("POST", "/api/v1/session", map[string]string{"username": p.username, "password": p.password}, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to authenticate with ArgoCD: %w", err)
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("ArgoCD authentication failed, status %d: %s", status, body)
+	}
+	var session struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &session); err != nil || session.Token == "" {
+		return "", fmt.Errorf("ArgoCD authentication returned no token")
+	}
+	return session.Token, nil
+}
+
+func (p *Provisioner) do(method, path string, payload interface{}, token string) (int, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = strings.NewReader(string(data))
+	}
+
+	req, err := http.NewRequest(method, p.baseURL+path, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, body, nil
+}
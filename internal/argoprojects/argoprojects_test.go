@@ -0,0 +1,200 @@
+package argoprojects
+
+import (
+	"encoding/json"
+	"errors"
+	"innominatus/internal/database"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeArgoCD implements the session and project endpoints of the ArgoCD API
+type fakeArgoCD struct {
+	mu       sync.Mutex
+	projects map[string]map[string]interface{}
+	logins   int
+	updates  int
+}
+
+func (f *fakeArgoCD) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/api/v1/session" {
+		f.logins++
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "t0k3n"})
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer t0k3n" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/projects/")
+	switch r.Method {
+	case "GET":
+		project, ok := f.projects[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(project)
+	case "POST", "PUT":
+		var body struct {
+			Project map[string]interface{} `json:"project"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		metadata := body.Project["metadata"].(map[string]interface{})
+		f.projects[metadata["name"].(string)] = body.Project
+		if r.Method == "PUT" {
+			f.updates++
+		}
+		_ = json.NewEncoder(w).Encode(body.Project)
+	}
+}
+
+func (f *fakeArgoCD) spec(t *testing.T, name string) ProjectSpec {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	project, ok := f.projects[name]
+	require.True(t, ok, "project %s exists", name)
+	data, _ := json.Marshal(project["spec"])
+	var spec ProjectSpec
+	require.NoError(t, json.Unmarshal(data, &spec))
+	return spec
+}
+
+func TestSpec(t *testing.T) {
+	config := Config{
+		SourceRepos: []string{"http://gitea/team-$team/*"},
+		Teams: map[string]TeamConfig{
+			"payments": {Destinations: []Destination{{Name: "prod", Namespace: "payments-prod"}}},
+		},
+	}
+	spec := config.Spec(Team{ID: "payments", Applications: []string{"shop", "checkout"}})
+
+	assert.Equal(t, []string{"http://gitea/team-payments/*"}, spec.SourceRepos)
+	assert.Equal(t, []Destination{
+		{Server: InClusterServer, Namespace: "payments-*"},
+		{Server: InClusterServer, Namespace: "checkout"},
+		{Server: InClusterServer, Namespace: "shop"},
+		{Server: InClusterServer, Namespace: "checkout-*"},
+		{Server: InClusterServer, Namespace: "shop-*"},
+		{Name: "prod", Namespace: "payments-prod"},
+	}, spec.Destinations)
+	assert.Equal(t, []Resource{{Kind: "Namespace"}}, spec.ClusterResourceWhitelist)
+
+	spec = config.Spec(Team{ID: "data"})
+	assert.Equal(t, []Destination{{Server: InClusterServer, Namespace: "data-*"}}, spec.Destinations, "$app entries need applications")
+}
+
+func TestSync_CreatesAndUpdatesProject(t *testing.T) {
+	argo := &fakeArgoCD{projects: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(argo)
+	defer server.Close()
+
+	p := NewProvisioner(Config{Enabled: true, ProjectPrefix: "team-"}, server.URL, "admin", "secret")
+	team := Team{ID: "payments", Applications: []string{"shop"}}
+
+	result := p.Sync(team)
+	require.Empty(t, result.Error)
+	assert.Equal(t, "team-payments", result.Project)
+	assert.True(t, result.Created)
+	assert.Equal(t, []string{"*"}, argo.spec(t, "team-payments").SourceRepos)
+
+	// Unchanged teams are not written
+	result = p.Sync(team)
+	assert.False(t, result.Created)
+	assert.False(t, result.Updated)
+	assert.Equal(t, 0, argo.updates)
+
+	// A new application widens the destinations; settings made in ArgoCD are kept
+	argo.projects["team-payments"]["spec"].(map[string]interface{})["roles"] = []interface{}{"ci"}
+	team.Applications = append(team.Applications, "checkout")
+	result = p.Sync(team)
+	assert.True(t, result.Updated)
+	assert.Len(t, argo.spec(t, "team-payments").Destinations, 5)
+	assert.Equal(t, []interface{}{"ci"}, argo.projects["team-payments"]["spec"].(map[string]interface{})["roles"])
+	assert.Equal(t, 1, argo.logins, "the session is reused")
+}
+
+type fakeApplications map[string]string // application -> team
+
+func (f fakeApplications) GetApplication(name string) (*database.Application, error) {
+	team, ok := f[name]
+	if !ok {
+		return nil, errors.New("application not found")
+	}
+	return &database.Application{Name: name, Team: team}, nil
+}
+
+func (f fakeApplications) ListApplicationsByTeam(team string) ([]*database.Application, error) {
+	var apps []*database.Application
+	for name, t := range f {
+		if t == team {
+			apps = append(apps, &database.Application{Name: name, Team: t})
+		}
+	}
+	return apps, nil
+}
+
+func TestProjectFor(t *testing.T) {
+	argo := &fakeArgoCD{projects: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(argo)
+	defer server.Close()
+
+	p := NewProvisioner(Config{Enabled: true}, server.URL, "admin", "secret")
+	apps := fakeApplications{"shop": "payments", "checkout": "payments", "legacy": ""}
+
+	project, err := p.ProjectFor(apps, "shop")
+	require.NoError(t, err)
+	assert.Equal(t, "payments", project)
+	assert.Contains(t, argo.spec(t, "payments").Destinations, Destination{Server: InClusterServer, Namespace: "checkout-*"})
+
+	project, err = p.ProjectFor(apps, "legacy")
+	require.NoError(t, err)
+	assert.Empty(t, project, "applications without a team keep the default project")
+
+	_, err = p.ProjectFor(apps, "missing")
+	assert.Error(t, err)
+}
+
+func TestSync_LoginFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	results := NewProvisioner(Config{Enabled: true}, server.URL, "admin", "wrong").SyncAll([]Team{{ID: "a"}, {ID: "b"}})
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Contains(t, result.Error, "authentication failed")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate(), "disabled config is not checked")
+	assert.NoError(t, Config{Enabled: true}.Validate())
+
+	for name, config := range map[string]Config{
+		"syncInterval":     {Enabled: true, SyncInterval: "-1m"},
+		"projectPrefix":    {Enabled: true, ProjectPrefix: "Team_"},
+		"destination":      {Enabled: true, Destinations: []Destination{{Namespace: "x"}}},
+		"namespace":        {Enabled: true, Destinations: []Destination{{Server: InClusterServer}}},
+		"cluster resource": {Enabled: true, ClusterResources: []Resource{{Group: "rbac.authorization.k8s.io"}}},
+		"team destination": {Enabled: true, Teams: map[string]TeamConfig{"a": {Destinations: []Destination{{Name: "prod"}}}}},
+	} {
+		assert.Error(t, config.Validate(), name)
+	}
+}
+
+func TestProjectName(t *testing.T) {
+	assert.Equal(t, "payments", Config{}.ProjectName("payments"))
+	assert.Equal(t, "team-data-platform", Config{ProjectPrefix: "team-"}.ProjectName("Data Platform"))
+}
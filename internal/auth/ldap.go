@@ -0,0 +1,336 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"innominatus/internal/users"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	defaultLDAPTimeout     = 10 * time.Second
+	defaultLDAPUserFilter  = "(uid={username})"
+	defaultADUserFilter    = "(sAMAccountName={username})"
+	defaultLDAPGroupFilter = "(member={dn})"
+	// adMatchingRuleInChain makes Active Directory resolve nested group membership server-side
+	adMatchingRuleInChain = "1.2.840.113556.1.4.1941"
+	// maxGroupNesting bounds nested group lookups on directories without a chain matching rule
+	maxGroupNesting = 10
+)
+
+// LDAPConfig is the authentication.ldap section of admin-config.yaml
+type LDAPConfig struct {
+	URL                string            `yaml:"url" json:"url"`                               // ldap://host:389 or ldaps://host:636
+	StartTLS           bool              `yaml:"startTLS" json:"startTLS"`                     // Upgrade ldap:// connections with StartTLS
+	CAFile             string            `yaml:"caFile" json:"caFile"`                         // PEM CA bundle of the directory certificate
+	InsecureSkipVerify bool              `yaml:"insecureSkipVerify" json:"insecureSkipVerify"` // Development only
+	BindDN             string            `yaml:"bindDN" json:"bindDN"`                         // Service account that searches users and groups; empty binds anonymously
+	BindPasswordEnv    string            `yaml:"bindPasswordEnv" json:"bindPasswordEnv"`       // Environment variable holding the service account password
+	ActiveDirectory    bool              `yaml:"activeDirectory" json:"activeDirectory"`       // Use sAMAccountName and AD nested group resolution
+	UserBaseDN         string            `yaml:"userBaseDN" json:"userBaseDN"`
+	UserFilter         string            `yaml:"userFilter" json:"userFilter"` // {username} is replaced (default: (uid={username}), AD: (sAMAccountName={username}))
+	GroupBaseDN        string            `yaml:"groupBaseDN" json:"groupBaseDN"`
+	GroupFilter        string            `yaml:"groupFilter" json:"groupFilter"`   // {dn} and {username} are replaced (default: (member={dn}))
+	NestedGroups       bool              `yaml:"nestedGroups" json:"nestedGroups"` // Users inherit the groups their groups are members of
+	GroupTeams         map[string]string `yaml:"groupTeams" json:"groupTeams"`     // Group CN or DN to team
+	AdminGroups        []string          `yaml:"adminGroups" json:"adminGroups"`   // Group CNs or DNs whose members get the admin role
+	DefaultTeam        string            `yaml:"defaultTeam" json:"defaultTeam"`   // Team of users in no mapped group; empty rejects them
+	Timeout            string            `yaml:"timeout" json:"timeout"`           // Connect and request timeout (default: 10s)
+}
+
+// ldapConn is the part of an LDAP connection the provider uses
+type ldapConn interface {
+	Bind(username, password string) error
+	Search(request *ldap.SearchRequest) (*ldap.SearchResult, error)
+	StartTLS(config *tls.Config) error
+	Close() error
+}
+
+// LDAPProvider authenticates users with an LDAP bind and maps their groups to a team
+type LDAPProvider struct {
+	config       LDAPConfig
+	bindPassword string
+	tlsConfig    *tls.Config
+	timeout      time.Duration
+	dial         func() (ldapConn, error)
+}
+
+// NewLDAPProvider validates the config and creates the provider. No connection is
+// opened until the first login.
+func NewLDAPProvider(config LDAPConfig) (*LDAPProvider, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("authentication.ldap.url is required")
+	}
+	if config.UserBaseDN == "" {
+		return nil, fmt.Errorf("authentication.ldap.userBaseDN is required")
+	}
+	serverURL, err := url.Parse(config.URL)
+	if err != nil || (serverURL.Scheme != "ldap" && serverURL.Scheme != "ldaps") {
+		return nil, fmt.Errorf("authentication.ldap.url must be an ldap:// or ldaps:// URL, got '%s'", config.URL)
+	}
+
+	if config.UserFilter == "" {
+		config.UserFilter = defaultLDAPUserFilter
+		if config.ActiveDirectory {
+			config.UserFilter = defaultADUserFilter
+		}
+	}
+	if config.GroupFilter == "" {
+		config.GroupFilter = defaultLDAPGroupFilter
+	}
+	if config.GroupBaseDN == "" {
+		config.GroupBaseDN = config.UserBaseDN
+	}
+
+	timeout := defaultLDAPTimeout
+	if config.Timeout != "" {
+		timeout, err = time.ParseDuration(config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid authentication.ldap.timeout: %w", err)
+		}
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         serverURL.Hostname(),
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.InsecureSkipVerify, // #nosec G402 - opt-in for development directories
+	}
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read authentication.ldap.caFile: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("authentication.ldap.caFile contains no PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	provider := &LDAPProvider{
+		config:    config,
+		tlsConfig: tlsConfig,
+		timeout:   timeout,
+	}
+	if config.BindPasswordEnv != "" {
+		provider.bindPassword = os.Getenv(config.BindPasswordEnv)
+	}
+	provider.dial = provider.dialServer
+	return provider, nil
+}
+
+// Name returns the provider name
+func (p *LDAPProvider) Name() string {
+	return ProviderLDAP
+}
+
+// dialServer connects to the directory, upgrading to TLS when configured
+func (p *LDAPProvider) dialServer() (ldapConn, error) {
+	conn, err := ldap.DialURL(p.config.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: p.timeout}),
+		ldap.DialWithTLSConfig(p.tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(p.timeout)
+
+	if p.config.StartTLS && strings.HasPrefix(p.config.URL, "ldap://") {
+		if err := conn.StartTLS(p.tlsConfig); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("StartTLS failed: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// Authenticate binds as the user and maps the user's groups to a team and role
+func (p *LDAPProvider) Authenticate(ctx context.Context, username, password string) (*users.User, error) {
+	// An empty password is an unauthenticated bind, which many directories accept
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := p.dial()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", p.config.URL, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := p.bindServiceAccount(conn); err != nil {
+		return nil, err
+	}
+
+	userDN, err := p.findUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(userDN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("user bind failed: %w", err)
+	}
+
+	// Groups are searched with the service account; users may not read group entries
+	if err := p.bindServiceAccount(conn); err != nil {
+		return nil, err
+	}
+
+	groups, err := p.userGroups(conn, username, userDN)
+	if err != nil {
+		return nil, err
+	}
+
+	team := p.team(groups)
+	if team == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	role := "user"
+	if matchesAnyGroup(groups, p.config.AdminGroups) {
+		role = "admin"
+	}
+
+	return &users.User{Username: username, Team: team, Role: role}, nil
+}
+
+// bindServiceAccount binds with the configured service account, if any
+func (p *LDAPProvider) bindServiceAccount(conn ldapConn) error {
+	if p.config.BindDN == "" {
+		return nil
+	}
+	if err := conn.Bind(p.config.BindDN, p.bindPassword); err != nil {
+		return fmt.Errorf("service account bind failed: %w", err)
+	}
+	return nil
+}
+
+// findUser returns the DN of the only entry matching the user filter
+func (p *LDAPProvider) findUser(conn ldapConn, username string) (string, error) {
+	filter := strings.ReplaceAll(p.config.UserFilter, "{username}", ldap.EscapeFilter(username))
+	result, err := conn.Search(ldap.NewSearchRequest(
+		p.config.UserBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		2, int(p.timeout.Seconds()), false, filter, []string{"dn"}, nil))
+	if err != nil {
+		return "", fmt.Errorf("user search failed: %w", err)
+	}
+	if len(result.Entries) != 1 {
+		return "", ErrInvalidCredentials
+	}
+	return result.Entries[0].DN, nil
+}
+
+// userGroups returns the DNs of the groups the user is a member of, including
+// inherited groups when nestedGroups is enabled
+func (p *LDAPProvider) userGroups(conn ldapConn, username, userDN string) ([]string, error) {
+	if p.config.NestedGroups && p.config.ActiveDirectory {
+		filter := fmt.Sprintf("(member:%s:=%s)", adMatchingRuleInChain, ldap.EscapeFilter(userDN))
+		return p.searchGroups(conn, filter)
+	}
+
+	groups, err := p.searchGroups(conn, p.groupFilter(username, userDN))
+	if err != nil || !p.config.NestedGroups {
+		return groups, err
+	}
+
+	seen := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		seen[strings.ToLower(group)] = true
+	}
+	pending := groups
+	for depth := 0; depth < maxGroupNesting && len(pending) > 0; depth++ {
+		var next []string
+		for _, group := range pending {
+			parents, err := p.searchGroups(conn, p.groupFilter("", group))
+			if err != nil {
+				return nil, err
+			}
+			for _, parent := range parents {
+				if !seen[strings.ToLower(parent)] {
+					seen[strings.ToLower(parent)] = true
+					groups = append(groups, parent)
+					next = append(next, parent)
+				}
+			}
+		}
+		pending = next
+	}
+	return groups, nil
+}
+
+// groupFilter returns the group filter for a member DN
+func (p *LDAPProvider) groupFilter(username, memberDN string) string {
+	return strings.NewReplacer(
+		"{dn}", ldap.EscapeFilter(memberDN),
+		"{username}", ldap.EscapeFilter(username),
+	).Replace(p.config.GroupFilter)
+}
+
+// searchGroups returns the DNs of the groups matching the filter
+func (p *LDAPProvider) searchGroups(conn ldapConn, filter string) ([]string, error) {
+	result, err := conn.Search(ldap.NewSearchRequest(
+		p.config.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, int(p.timeout.Seconds()), false, filter, []string{"dn"}, nil))
+	if err != nil {
+		return nil, fmt.Errorf("group search failed: %w", err)
+	}
+	groups := make([]string, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		groups = append(groups, entry.DN)
+	}
+	return groups, nil
+}
+
+// team returns the team of the first mapped group in DN order, or the default team
+func (p *LDAPProvider) team(groups []string) string {
+	sorted := append([]string(nil), groups...)
+	sort.Strings(sorted)
+	for _, group := range sorted {
+		for key, team := range p.config.GroupTeams {
+			if groupMatches(group, key) {
+				return team
+			}
+		}
+	}
+	return p.config.DefaultTeam
+}
+
+// matchesAnyGroup reports whether one of the groups matches one of the names
+func matchesAnyGroup(groups, names []string) bool {
+	for _, group := range groups {
+		for _, name := range names {
+			if groupMatches(group, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// groupMatches compares a group DN with a configured group DN or CN, ignoring case
+func groupMatches(groupDN, name string) bool {
+	if strings.EqualFold(groupDN, name) {
+		return true
+	}
+	dn, err := ldap.ParseDN(groupDN)
+	if err != nil || len(dn.RDNs) == 0 {
+		return false
+	}
+	for _, attr := range dn.RDNs[0].Attributes {
+		if strings.EqualFold(attr.Type, "cn") && strings.EqualFold(attr.Value, name) {
+			return true
+		}
+	}
+	return false
+}
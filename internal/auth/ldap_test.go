@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"testing"
+
+	"innominatus/internal/users"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDirectory answers searches by exact filter and checks binds against passwords
+type fakeDirectory struct {
+	passwords map[string]string
+	entries   map[string][]string // filter → DNs
+	searches  []string
+	bound     string
+	closed    bool
+}
+
+func (d *fakeDirectory) Bind(username, password string) error {
+	if d.passwords[username] != password {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	d.bound = username
+	return nil
+}
+
+func (d *fakeDirectory) Search(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	d.searches = append(d.searches, request.Filter)
+	result := &ldap.SearchResult{}
+	for _, dn := range d.entries[request.Filter] {
+		result.Entries = append(result.Entries, ldap.NewEntry(dn, nil))
+	}
+	return result, nil
+}
+
+func (d *fakeDirectory) StartTLS(config *tls.Config) error { return nil }
+
+func (d *fakeDirectory) Close() error {
+	d.closed = true
+	return nil
+}
+
+const (
+	serviceDN = "cn=svc,ou=system,dc=example,dc=com"
+	aliceDN   = "uid=alice,ou=users,dc=example,dc=com"
+)
+
+func newTestLDAPProvider(t *testing.T, config LDAPConfig, directory *fakeDirectory) *LDAPProvider {
+	t.Helper()
+	t.Setenv("TEST_LDAP_BIND_PASSWORD", "svc-secret")
+	config.URL = "ldaps://ldap.example.com"
+	config.UserBaseDN = "ou=users,dc=example,dc=com"
+	config.BindDN = serviceDN
+	config.BindPasswordEnv = "TEST_LDAP_BIND_PASSWORD"
+	provider, err := NewLDAPProvider(config)
+	require.NoError(t, err)
+	provider.dial = func() (ldapConn, error) { return directory, nil }
+	return provider
+}
+
+func TestLDAPProviderAuthenticate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   LDAPConfig
+		entries  map[string][]string
+		password string
+		want     *users.User
+		wantErr  error
+	}{
+		{
+			name:   "direct group mapped to team",
+			config: LDAPConfig{GroupTeams: map[string]string{"payments-devs": "payments"}},
+			entries: map[string][]string{
+				"(uid=alice)":              {aliceDN},
+				"(member=" + aliceDN + ")": {"cn=payments-devs,ou=groups,dc=example,dc=com"},
+			},
+			password: "alice-secret",
+			want:     &users.User{Username: "alice", Team: "payments", Role: "user"},
+		},
+		{
+			name: "nested group grants admin role",
+			config: LDAPConfig{
+				NestedGroups: true,
+				GroupTeams:   map[string]string{"cn=platform,ou=groups,dc=example,dc=com": "platform"},
+				AdminGroups:  []string{"platform-admins"},
+			},
+			entries: map[string][]string{
+				"(uid=alice)":              {aliceDN},
+				"(member=" + aliceDN + ")": {"cn=platform,ou=groups,dc=example,dc=com"},
+				"(member=cn=platform,ou=groups,dc=example,dc=com)":        {"cn=platform-admins,ou=groups,dc=example,dc=com"},
+				"(member=cn=platform-admins,ou=groups,dc=example,dc=com)": {"cn=platform,ou=groups,dc=example,dc=com"},
+			},
+			password: "alice-secret",
+			want:     &users.User{Username: "alice", Team: "platform", Role: "admin"},
+		},
+		{
+			name:   "active directory resolves nested groups in one search",
+			config: LDAPConfig{ActiveDirectory: true, NestedGroups: true, DefaultTeam: "developers"},
+			entries: map[string][]string{
+				"(sAMAccountName=alice)":                            {aliceDN},
+				"(member:1.2.840.113556.1.4.1941:=" + aliceDN + ")": {"cn=all-staff,ou=groups,dc=example,dc=com"},
+			},
+			password: "alice-secret",
+			want:     &users.User{Username: "alice", Team: "developers", Role: "user"},
+		},
+		{
+			name:     "wrong password",
+			config:   LDAPConfig{DefaultTeam: "developers"},
+			entries:  map[string][]string{"(uid=alice)": {aliceDN}},
+			password: "wrong",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "unknown user",
+			config:   LDAPConfig{DefaultTeam: "developers"},
+			password: "alice-secret",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "empty password is never bound",
+			config:   LDAPConfig{DefaultTeam: "developers"},
+			entries:  map[string][]string{"(uid=alice)": {aliceDN}},
+			password: "",
+			wantErr:  ErrInvalidCredentials,
+		},
+		{
+			name:     "user in no mapped group without default team",
+			config:   LDAPConfig{GroupTeams: map[string]string{"payments-devs": "payments"}},
+			entries:  map[string][]string{"(uid=alice)": {aliceDN}},
+			password: "alice-secret",
+			wantErr:  ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			directory := &fakeDirectory{
+				passwords: map[string]string{serviceDN: "svc-secret", aliceDN: "alice-secret"},
+				entries:   tt.entries,
+			}
+			provider := newTestLDAPProvider(t, tt.config, directory)
+
+			user, err := provider.Authenticate(context.Background(), "alice", tt.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, user)
+			assert.Equal(t, serviceDN, directory.bound, "groups are searched with the service account")
+			assert.True(t, directory.closed)
+		})
+	}
+}
+
+func TestLDAPProviderEscapesUsername(t *testing.T) {
+	directory := &fakeDirectory{passwords: map[string]string{serviceDN: "svc-secret"}}
+	provider := newTestLDAPProvider(t, LDAPConfig{DefaultTeam: "developers"}, directory)
+
+	_, err := provider.Authenticate(context.Background(), "*)(uid=*", "secret")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Equal(t, []string{`(uid=\2a\29\28uid=\2a)`}, directory.searches)
+}
+
+func TestNewLDAPProviderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  LDAPConfig
+		wantErr string
+	}{
+		{name: "missing url", config: LDAPConfig{UserBaseDN: "dc=example"}, wantErr: "authentication.ldap.url is required"},
+		{name: "missing base DN", config: LDAPConfig{URL: "ldap://ldap"}, wantErr: "authentication.ldap.userBaseDN is required"},
+		{name: "unsupported scheme", config: LDAPConfig{URL: "https://ldap", UserBaseDN: "dc=example"}, wantErr: "must be an ldap:// or ldaps:// URL"},
+		{name: "invalid timeout", config: LDAPConfig{URL: "ldap://ldap", UserBaseDN: "dc=example", Timeout: "soon"}, wantErr: "invalid authentication.ldap.timeout"},
+		{name: "missing CA file", config: LDAPConfig{URL: "ldaps://ldap", UserBaseDN: "dc=example", CAFile: "/nonexistent/ca.pem"}, wantErr: "failed to read authentication.ldap.caFile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLDAPProvider(tt.config)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+type staticProvider struct {
+	user *users.User
+	err  error
+}
+
+func (p staticProvider) Name() string { return "static" }
+
+func (p staticProvider) Authenticate(ctx context.Context, username, password string) (*users.User, error) {
+	return p.user, p.err
+}
+
+func TestProviderChain(t *testing.T) {
+	bob := &users.User{Username: "bob", Team: "platform", Role: "user"}
+	unreachable := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		chain   ProviderChain
+		want    *users.User
+		wantErr string
+	}{
+		{name: "falls through rejected credentials", chain: ProviderChain{staticProvider{err: ErrInvalidCredentials}, staticProvider{user: bob}}, want: bob},
+		{name: "unreachable provider does not block the next", chain: ProviderChain{staticProvider{err: unreachable}, staticProvider{user: bob}}, want: bob},
+		{name: "all rejected", chain: ProviderChain{staticProvider{err: ErrInvalidCredentials}, staticProvider{err: ErrInvalidCredentials}}, wantErr: "invalid credentials"},
+		{name: "failure reported over rejection", chain: ProviderChain{staticProvider{err: ErrInvalidCredentials}, staticProvider{err: unreachable}}, wantErr: "static authentication failed: connection refused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := tt.chain.Authenticate(context.Background(), "bob", "secret")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, user)
+		})
+	}
+}
+
+func TestNewProviderChain(t *testing.T) {
+	chain, err := NewProviderChain(Config{})
+	require.NoError(t, err)
+	assert.Equal(t, ProviderChain{LocalProvider{}}, chain)
+
+	chain, err = NewProviderChain(Config{
+		Providers: []string{"ldap", "local"},
+		LDAP:      LDAPConfig{URL: "ldap://ldap.example.com", UserBaseDN: "dc=example,dc=com"},
+	})
+	require.NoError(t, err)
+	require.Len(t, chain, 2)
+	assert.Equal(t, ProviderLDAP, chain[0].Name())
+	assert.Equal(t, ProviderLocal, chain[1].Name())
+
+	_, err = NewProviderChain(Config{Providers: []string{"kerberos"}})
+	assert.EqualError(t, err, "unknown authentication provider 'kerberos' (supported: local, ldap)")
+}
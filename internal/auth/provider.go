@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"innominatus/internal/users"
+)
+
+// Password login providers selectable in admin-config.yaml
+const (
+	ProviderLocal = "local"
+	ProviderLDAP  = "ldap"
+)
+
+// ErrInvalidCredentials is returned when a provider does not know the user or the
+// password is wrong. Other errors mean the provider could not be asked.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Config is the authentication section of admin-config.yaml. OIDC is configured
+// separately and works alongside these providers.
+type Config struct {
+	Providers        []string               `yaml:"providers" json:"providers"` // Password login providers in the order they are tried (default: local)
+	LDAP             LDAPConfig             `yaml:"ldap" json:"ldap"`
+	LoginThrottle    ThrottleConfig         `yaml:"loginThrottle" json:"loginThrottle"`
+	WorkloadIdentity WorkloadIdentityConfig `yaml:"workloadIdentity" json:"workloadIdentity"`
+}
+
+// Provider authenticates users with a username and password
+type Provider interface {
+	Name() string
+	Authenticate(ctx context.Context, username, password string) (*users.User, error)
+}
+
+// ProviderChain tries its providers in order until one accepts the credentials
+type ProviderChain []Provider
+
+// NewProviderChain creates the providers listed in the config
+func NewProviderChain(config Config) (ProviderChain, error) {
+	names := config.Providers
+	if len(names) == 0 {
+		names = []string{ProviderLocal}
+	}
+
+	chain := make(ProviderChain, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(name) {
+		case ProviderLocal:
+			chain = append(chain, LocalProvider{})
+		case ProviderLDAP:
+			provider, err := NewLDAPProvider(config.LDAP)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, provider)
+		default:
+			return nil, fmt.Errorf("unknown authentication provider '%s' (supported: %s, %s)", name, ProviderLocal, ProviderLDAP)
+		}
+	}
+	return chain, nil
+}
+
+// Authenticate returns the user of the first provider that accepts the credentials.
+// ErrInvalidCredentials is only returned when every provider rejected them; otherwise
+// the error of the provider that failed is returned.
+func (c ProviderChain) Authenticate(ctx context.Context, username, password string) (*users.User, error) {
+	var failed error
+	for _, provider := range c {
+		user, err := provider.Authenticate(ctx, username, password)
+		if err == nil {
+			return user, nil
+		}
+		if !errors.Is(err, ErrInvalidCredentials) && failed == nil {
+			failed = fmt.Errorf("%s authentication failed: %w", provider.Name(), err)
+		}
+	}
+	if failed != nil {
+		return nil, failed
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// LocalProvider authenticates against users.yaml
+type LocalProvider struct{}
+
+// Name returns the provider name
+func (LocalProvider) Name() string {
+	return ProviderLocal
+}
+
+// Authenticate checks the credentials against users.yaml
+func (LocalProvider) Authenticate(ctx context.Context, username, password string) (*users.User, error) {
+	store, err := users.LoadUsers()
+	if err != nil {
+		return nil, err
+	}
+	user, err := store.Authenticate(username, password)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
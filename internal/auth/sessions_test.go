@@ -58,7 +58,7 @@ func TestSessionManager_CreateSession(t *testing.T) {
 func TestSessionManager_GetSession(t *testing.T) {
 	sm := &SessionManager{
 		sessions:    make(map[string]*Session),
-		sessionFile: "test-sessions.json",
+		sessionFile: filepath.Join(t.TempDir(), "sessions.json"),
 	}
 
 	user := &users.User{Username: "testuser"}
@@ -86,7 +86,7 @@ func TestSessionManager_GetSession(t *testing.T) {
 func TestSessionManager_GetExpiredSession(t *testing.T) {
 	sm := &SessionManager{
 		sessions:    make(map[string]*Session),
-		sessionFile: "test-sessions.json",
+		sessionFile: filepath.Join(t.TempDir(), "sessions.json"),
 	}
 
 	// Create an expired session manually
@@ -135,7 +135,7 @@ func TestSessionManager_DeleteSession(t *testing.T) {
 func TestSessionManager_ExtendSession(t *testing.T) {
 	sm := &SessionManager{
 		sessions:    make(map[string]*Session),
-		sessionFile: "test-sessions.json",
+		sessionFile: filepath.Join(t.TempDir(), "sessions.json"),
 	}
 
 	user := &users.User{Username: "testuser"}
@@ -0,0 +1,320 @@
+package auth
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"innominatus/internal/metrics"
+)
+
+// Login throttle defaults
+const (
+	DefaultMaxAttemptsPerUser = 5
+	DefaultMaxAttemptsPerIP   = 20
+	DefaultBaseLockout        = time.Minute
+	DefaultMaxLockout         = time.Hour
+	DefaultResetAfter         = 24 * time.Hour
+)
+
+// ThrottleConfig is the loginThrottle section of authentication in admin-config.yaml
+type ThrottleConfig struct {
+	MaxAttemptsPerUser int    `yaml:"maxAttemptsPerUser" json:"maxAttemptsPerUser"` // Failed logins of a username before it is locked (default 5)
+	MaxAttemptsPerIP   int    `yaml:"maxAttemptsPerIP" json:"maxAttemptsPerIP"`     // Failed logins from a client IP before it is locked (default 20)
+	BaseLockout        string `yaml:"baseLockout" json:"baseLockout"`               // First lockout, doubled by every further failure (default 1m)
+	MaxLockout         string `yaml:"maxLockout" json:"maxLockout"`                 // Longest lockout (default 1h)
+	ResetAfter         string `yaml:"resetAfter" json:"resetAfter"`                 // Failures are forgotten after this long without a new one (default 24h)
+
+	TrustedProxies []string `yaml:"trustedProxies" json:"trustedProxies"` // Addresses or CIDRs of reverse proxies whose X-Forwarded-For is trusted
+}
+
+// LoginAttemptStore counts failed logins per key. The database implements it so that
+// lockouts survive restarts and hold across replicas.
+type LoginAttemptStore interface {
+	// RecordLoginFailure counts a failure at the given time and returns the number of
+	// failures of the key. Failures before resetBefore are discarded first.
+	RecordLoginFailure(key string, at, resetBefore time.Time) (int, error)
+	// LoginFailures returns the number of failures of a key and the time of the last one
+	LoginFailures(key string) (int, time.Time, error)
+	ResetLoginFailures(key string) error
+}
+
+// MemoryLoginAttemptStore keeps failed logins in memory, for servers without a database
+type MemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	failures map[string]loginFailures
+}
+
+type loginFailures struct {
+	count int
+	last  time.Time
+}
+
+// NewMemoryLoginAttemptStore creates an empty in-memory store
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{failures: make(map[string]loginFailures)}
+}
+
+// RecordLoginFailure counts a failure and drops keys whose failures are older than resetBefore
+func (m *MemoryLoginAttemptStore) RecordLoginFailure(key string, at, resetBefore time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k, entry := range m.failures {
+		if entry.last.Before(resetBefore) {
+			delete(m.failures, k)
+		}
+	}
+	entry := m.failures[key]
+	entry.count++
+	entry.last = at
+	m.failures[key] = entry
+	return entry.count, nil
+}
+
+// LoginFailures returns the failures of a key
+func (m *MemoryLoginAttemptStore) LoginFailures(key string) (int, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.failures[key]
+	return entry.count, entry.last, nil
+}
+
+// ResetLoginFailures forgets the failures of a key
+func (m *MemoryLoginAttemptStore) ResetLoginFailures(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.failures, key)
+	return nil
+}
+
+// LoginThrottle locks usernames and client IPs after repeated failed logins. Each
+// failure past the limit doubles the lockout, up to the configured maximum.
+type LoginThrottle struct {
+	store       LoginAttemptStore
+	maxPerUser  int
+	maxPerIP    int
+	baseLockout time.Duration
+	maxLockout  time.Duration
+	resetAfter  time.Duration
+	proxies     []*net.IPNet
+	now         func() time.Time
+}
+
+// NewLoginThrottle creates a throttle backed by store, applying defaults for unset fields
+func NewLoginThrottle(config ThrottleConfig, store LoginAttemptStore, now func() time.Time) (*LoginThrottle, error) {
+	t := &LoginThrottle{
+		store:       store,
+		maxPerUser:  config.MaxAttemptsPerUser,
+		maxPerIP:    config.MaxAttemptsPerIP,
+		baseLockout: DefaultBaseLockout,
+		maxLockout:  DefaultMaxLockout,
+		resetAfter:  DefaultResetAfter,
+		now:         now,
+	}
+	if t.maxPerUser <= 0 {
+		t.maxPerUser = DefaultMaxAttemptsPerUser
+	}
+	if t.maxPerIP <= 0 {
+		t.maxPerIP = DefaultMaxAttemptsPerIP
+	}
+	if t.now == nil {
+		t.now = time.Now
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		field *time.Duration
+	}{
+		{"baseLockout", config.BaseLockout, &t.baseLockout},
+		{"maxLockout", config.MaxLockout, &t.maxLockout},
+		{"resetAfter", config.ResetAfter, &t.resetAfter},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid loginThrottle.%s '%s'", d.name, d.value)
+		}
+		*d.field = parsed
+	}
+	if t.maxLockout < t.baseLockout {
+		return nil, fmt.Errorf("loginThrottle.maxLockout must not be shorter than baseLockout")
+	}
+	for _, proxy := range config.TrustedProxies {
+		network, err := parseNetwork(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid loginThrottle.trustedProxies entry '%s'", proxy)
+		}
+		t.proxies = append(t.proxies, network)
+	}
+	return t, nil
+}
+
+// parseNetwork parses a CIDR or a single address
+func parseNetwork(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, network, err := net.ParseCIDR(value)
+		return network, err
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid address")
+	}
+	bits := 8 * len(ip.To16())
+	if ip.To4() != nil {
+		ip, bits = ip.To4(), 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+// ClientIP returns the address logins of r are throttled by: the peer address without
+// its port. X-Forwarded-For and X-Real-IP are only used when the peer is a trusted
+// proxy, otherwise clients could pick a new address for every attempt.
+func (t *LoginThrottle) ClientIP(r *http.Request) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+	if !t.trustedProxy(peer) {
+		return peer
+	}
+
+	// The rightmost address that is not a trusted proxy was added by the first of them
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !t.trustedProxy(hop) {
+			return hop
+		}
+	}
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+		return realIP
+	}
+	return peer
+}
+
+func (t *LoginThrottle) trustedProxy(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range t.proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func userKey(username string) string {
+	return "user:" + username
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check returns how long a login of username from ip must wait. Zero means the
+// login may be attempted.
+func (t *LoginThrottle) Check(username, ip string) (time.Duration, error) {
+	var wait time.Duration
+	for _, dimension := range t.dimensions(username, ip) {
+		failures, last, err := t.store.LoginFailures(dimension.key)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read login attempts: %w", err)
+		}
+		if remaining := t.remaining(failures, last, dimension.max); remaining > wait {
+			wait = remaining
+		}
+	}
+	if wait > 0 {
+		metrics.GetGlobal().RecordLoginAttempt("locked")
+	}
+	return wait, nil
+}
+
+// Failure records a failed login of username from ip
+func (t *LoginThrottle) Failure(username, ip string) error {
+	metrics.GetGlobal().RecordLoginAttempt("failure")
+
+	now := t.now()
+	for _, dimension := range t.dimensions(username, ip) {
+		failures, err := t.store.RecordLoginFailure(dimension.key, now, now.Add(-t.resetAfter))
+		if err != nil {
+			return fmt.Errorf("failed to record login attempt: %w", err)
+		}
+		if failures == dimension.max {
+			metrics.GetGlobal().RecordLoginLockout(dimension.name)
+		}
+	}
+	return nil
+}
+
+// Success forgets the failures of username. Failures of the client IP are kept, so a
+// valid login does not unlock guessing of other usernames from the same address.
+func (t *LoginThrottle) Success(username string) error {
+	metrics.GetGlobal().RecordLoginAttempt("success")
+
+	if username == "" {
+		return nil
+	}
+	if err := t.store.ResetLoginFailures(userKey(username)); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
+
+// Lockout returns how long a key is locked after the given number of failures
+func (t *LoginThrottle) Lockout(failures, max int) time.Duration {
+	if failures < max {
+		return 0
+	}
+	lockout := t.baseLockout
+	for i := max; i < failures; i++ {
+		lockout *= 2
+		if lockout >= t.maxLockout {
+			return t.maxLockout
+		}
+	}
+	return lockout
+}
+
+func (t *LoginThrottle) remaining(failures int, last time.Time, max int) time.Duration {
+	if failures == 0 || t.now().Sub(last) >= t.resetAfter {
+		return 0
+	}
+	remaining := last.Add(t.Lockout(failures, max)).Sub(t.now())
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+type throttleDimension struct {
+	name string
+	key  string
+	max  int
+}
+
+func (t *LoginThrottle) dimensions(username, ip string) []throttleDimension {
+	var dimensions []throttleDimension
+	if username != "" {
+		dimensions = append(dimensions, throttleDimension{"username", userKey(username), t.maxPerUser})
+	}
+	if ip != "" {
+		dimensions = append(dimensions, throttleDimension{"ip", ipKey(ip), t.maxPerIP})
+	}
+	return dimensions
+}
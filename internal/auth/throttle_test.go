@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestThrottle(t *testing.T, config ThrottleConfig, now *time.Time) *LoginThrottle {
+	t.Helper()
+	throttle, err := NewLoginThrottle(config, NewMemoryLoginAttemptStore(), func() time.Time { return *now })
+	require.NoError(t, err)
+	return throttle
+}
+
+func TestLoginThrottleLocksUsername(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	throttle := newTestThrottle(t, ThrottleConfig{MaxAttemptsPerUser: 3}, &now)
+
+	for i := 0; i < 3; i++ {
+		wait, err := throttle.Check("alice", "10.0.0.1")
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+		require.NoError(t, throttle.Failure("alice", "10.0.0.1"))
+	}
+
+	wait, err := throttle.Check("alice", "10.0.0.2")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, wait, "locked from any address")
+
+	wait, err = throttle.Check("bob", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Zero(t, wait, "other usernames from the same address are allowed")
+
+	// Every failure past the limit doubles the lockout
+	now = now.Add(time.Minute)
+	require.NoError(t, throttle.Failure("alice", "10.0.0.1"))
+	wait, err = throttle.Check("alice", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, wait)
+
+	require.NoError(t, throttle.Success("alice"))
+	wait, err = throttle.Check("alice", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+}
+
+func TestLoginThrottleLocksIP(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	throttle := newTestThrottle(t, ThrottleConfig{MaxAttemptsPerIP: 2, BaseLockout: "30s"}, &now)
+
+	require.NoError(t, throttle.Failure("alice", "10.0.0.1"))
+	require.NoError(t, throttle.Failure("bob", "10.0.0.1"))
+
+	wait, err := throttle.Check("carol", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, wait)
+
+	// A valid login does not unlock the address
+	require.NoError(t, throttle.Success("carol"))
+	wait, err = throttle.Check("dave", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, wait)
+
+	now = now.Add(30 * time.Second)
+	wait, err = throttle.Check("dave", "10.0.0.1")
+	require.NoError(t, err)
+	assert.Zero(t, wait)
+}
+
+func TestLoginThrottleResetAfter(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	throttle := newTestThrottle(t, ThrottleConfig{MaxAttemptsPerUser: 2, ResetAfter: "1h"}, &now)
+
+	require.NoError(t, throttle.Failure("alice", ""))
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, throttle.Failure("alice", ""))
+
+	wait, err := throttle.Check("alice", "")
+	require.NoError(t, err)
+	assert.Zero(t, wait, "the first failure was forgotten")
+}
+
+func TestLoginThrottleLockout(t *testing.T) {
+	now := time.Now()
+	throttle := newTestThrottle(t, ThrottleConfig{BaseLockout: "1m", MaxLockout: "5m"}, &now)
+
+	assert.Zero(t, throttle.Lockout(4, 5))
+	assert.Equal(t, time.Minute, throttle.Lockout(5, 5))
+	assert.Equal(t, 2*time.Minute, throttle.Lockout(6, 5))
+	assert.Equal(t, 4*time.Minute, throttle.Lockout(7, 5))
+	assert.Equal(t, 5*time.Minute, throttle.Lockout(8, 5))
+	assert.Equal(t, 5*time.Minute, throttle.Lockout(100, 5))
+}
+
+func TestNewLoginThrottleInvalidConfig(t *testing.T) {
+	for _, config := range []ThrottleConfig{
+		{BaseLockout: "soon"},
+		{ResetAfter: "-1h"},
+		{BaseLockout: "2h", MaxLockout: "1h"},
+		{TrustedProxies: []string{"proxy.internal"}},
+	} {
+		_, err := NewLoginThrottle(config, NewMemoryLoginAttemptStore(), nil)
+		assert.Error(t, err, config)
+	}
+}
+
+func TestLoginThrottleClientIP(t *testing.T) {
+	now := time.Now()
+	throttle := newTestThrottle(t, ThrottleConfig{TrustedProxies: []string{"10.0.0.0/8", "192.168.1.5"}}, &now)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{name: "port is stripped", remoteAddr: "203.0.113.7:51234", expected: "203.0.113.7"},
+		{name: "ipv6 port is stripped", remoteAddr: "[2001:db8::1]:443", expected: "2001:db8::1"},
+		{name: "headers of untrusted peers are ignored", remoteAddr: "203.0.113.7:51234", headers: map[string]string{"X-Forwarded-For": "198.51.100.1", "X-Real-IP": "198.51.100.2"}, expected: "203.0.113.7"},
+		{name: "forwarded for by trusted proxy", remoteAddr: "10.1.2.3:8080", headers: map[string]string{"X-Forwarded-For": "198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "rightmost untrusted hop", remoteAddr: "192.168.1.5:8080", headers: map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.1, 10.0.0.9"}, expected: "198.51.100.1"},
+		{name: "real ip from trusted proxy", remoteAddr: "10.1.2.3:8080", headers: map[string]string{"X-Real-IP": "198.51.100.2"}, expected: "198.51.100.2"},
+		{name: "trusted proxy without headers", remoteAddr: "10.1.2.3:8080", expected: "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/login", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			assert.Equal(t, tt.expected, throttle.ClientIP(req))
+		})
+	}
+}
+
+func TestLoginThrottleIgnoresRotatedForwardedFor(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	throttle := newTestThrottle(t, ThrottleConfig{MaxAttemptsPerIP: 3}, &now)
+
+	attempt := func(i int) string {
+		req := httptest.NewRequest("POST", "/api/login", nil)
+		req.RemoteAddr = fmt.Sprintf("203.0.113.7:%d", 40000+i)
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+		return throttle.ClientIP(req)
+	}
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, throttle.Failure(fmt.Sprintf("user%d", i), attempt(i)))
+	}
+
+	wait, err := throttle.Check("user9", attempt(9))
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, wait, "a new port and X-Forwarded-For do not escape the IP lockout")
+}
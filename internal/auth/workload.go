@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"innominatus/internal/users"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// DefaultWorkloadTokenTTL is the lifetime of tokens issued to service accounts
+	DefaultWorkloadTokenTTL = 15 * time.Minute
+	// MaxWorkloadTokenTTL caps workloadIdentity.tokenTTL
+	MaxWorkloadTokenTTL = time.Hour
+
+	serviceAccountPrefix = "system:serviceaccount:"
+)
+
+// WorkloadIdentityConfig is the authentication.workloadIdentity section of admin-config.yaml.
+// In-cluster workloads exchange their service account token for a short-lived innominatus
+// token instead of using a static API key.
+type WorkloadIdentityConfig struct {
+	Enabled         bool                    `yaml:"enabled" json:"enabled"`
+	Kubeconfig      string                  `yaml:"kubeconfig" json:"kubeconfig"`           // Cluster that reviews the tokens; empty uses the in-cluster config
+	Audiences       []string                `yaml:"audiences" json:"audiences"`             // Audiences a token must be issued for; empty accepts the API server's default
+	TokenTTL        string                  `yaml:"tokenTTL" json:"tokenTTL"`               // Lifetime of issued tokens (default 15m, at most 1h)
+	ServiceAccounts []ServiceAccountMapping `yaml:"serviceAccounts" json:"serviceAccounts"` // Service accounts allowed to exchange tokens
+}
+
+// ServiceAccountMapping maps Kubernetes service accounts to an innominatus team and role
+type ServiceAccountMapping struct {
+	Namespace string `yaml:"namespace" json:"namespace"`
+	Name      string `yaml:"name" json:"name"` // Service account name; empty or * matches every account of the namespace
+	Team      string `yaml:"team" json:"team"`
+	Role      string `yaml:"role" json:"role"` // user (default) or admin
+}
+
+// TTL returns the lifetime of issued tokens
+func (c WorkloadIdentityConfig) TTL() (time.Duration, error) {
+	if c.TokenTTL == "" {
+		return DefaultWorkloadTokenTTL, nil
+	}
+	ttl, err := time.ParseDuration(c.TokenTTL)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid workloadIdentity.tokenTTL '%s'", c.TokenTTL)
+	}
+	if ttl > MaxWorkloadTokenTTL {
+		return 0, fmt.Errorf("workloadIdentity.tokenTTL '%s' exceeds %s", c.TokenTTL, MaxWorkloadTokenTTL)
+	}
+	return ttl, nil
+}
+
+// MapServiceAccount returns the user of an authenticated Kubernetes username
+// (system:serviceaccount:<namespace>:<name>). A mapping naming the account wins over one
+// for its whole namespace. Unmapped accounts get ErrInvalidCredentials.
+func (c WorkloadIdentityConfig) MapServiceAccount(username string) (*users.User, error) {
+	namespace, name, ok := strings.Cut(strings.TrimPrefix(username, serviceAccountPrefix), ":")
+	if !strings.HasPrefix(username, serviceAccountPrefix) || !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("%w: '%s' is not a service account", ErrInvalidCredentials, username)
+	}
+
+	var match *ServiceAccountMapping
+	for i, mapping := range c.ServiceAccounts {
+		if mapping.Namespace != namespace {
+			continue
+		}
+		if mapping.Name == name {
+			match = &c.ServiceAccounts[i]
+			break
+		}
+		if (mapping.Name == "" || mapping.Name == "*") && match == nil {
+			match = &c.ServiceAccounts[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w: service account %s/%s is not mapped", ErrInvalidCredentials, namespace, name)
+	}
+	if match.Team == "" {
+		return nil, fmt.Errorf("service account mapping of namespace '%s' has no team", namespace)
+	}
+
+	role := match.Role
+	if role == "" {
+		role = "user"
+	}
+	if role != "user" && role != "admin" {
+		return nil, fmt.Errorf("service account mapping of namespace '%s' has invalid role '%s'", namespace, role)
+	}
+	return &users.User{Username: username, Team: match.Team, Role: role}, nil
+}
+
+// TokenReviewer validates Kubernetes service account tokens
+type TokenReviewer interface {
+	// Review returns the username of the token's service account. Tokens the API server
+	// does not authenticate get ErrInvalidCredentials.
+	Review(ctx context.Context, token string, audiences []string) (string, error)
+}
+
+// KubernetesTokenReviewer validates tokens with the TokenReview API
+type KubernetesTokenReviewer struct {
+	client kubernetes.Interface
+}
+
+// NewKubernetesTokenReviewer connects to the cluster of kubeconfig, or to the cluster the
+// server runs in when kubeconfig is empty. The server's service account needs the
+// system:auth-delegator cluster role.
+func NewKubernetesTokenReviewer(kubeconfig string) (*KubernetesTokenReviewer, error) {
+	var config *rest.Config
+	var err error
+	if kubeconfig == "" {
+		config, err = rest.InClusterConfig()
+	} else {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes config: %w", err)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	return NewKubernetesTokenReviewerWithClient(client), nil
+}
+
+// NewKubernetesTokenReviewerWithClient creates a reviewer on an existing client
+func NewKubernetesTokenReviewerWithClient(client kubernetes.Interface) *KubernetesTokenReviewer {
+	return &KubernetesTokenReviewer{client: client}
+}
+
+// Review implements TokenReviewer
+func (r *KubernetesTokenReviewer) Review(ctx context.Context, token string, audiences []string) (string, error) {
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token, Audiences: audiences},
+	}
+	result, err := r.client.AuthenticationV1().TokenReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("token review failed: %w", err)
+	}
+	if !result.Status.Authenticated {
+		if result.Status.Error != "" {
+			return "", fmt.Errorf("%w: %s", ErrInvalidCredentials, result.Status.Error)
+		}
+		return "", ErrInvalidCredentials
+	}
+	return result.Status.User.Username, nil
+}
+
+// WorkloadTokenStore keeps the tokens issued to service accounts, by hash. The database
+// implements it so that tokens are accepted by every replica.
+type WorkloadTokenStore interface {
+	// SaveWorkloadToken stores a token and drops the tokens that expired before now
+	SaveWorkloadToken(tokenHash string, user *users.User, expiresAt, now time.Time) error
+	// GetWorkloadToken returns the user of a token, or nil when the token is unknown or
+	// expired at now
+	GetWorkloadToken(tokenHash string, now time.Time) (*users.User, time.Time, error)
+}
+
+// MemoryWorkloadTokenStore keeps issued tokens in memory, for servers without a database
+type MemoryWorkloadTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]workloadToken
+}
+
+type workloadToken struct {
+	user      users.User
+	expiresAt time.Time
+}
+
+// NewMemoryWorkloadTokenStore creates an empty in-memory store
+func NewMemoryWorkloadTokenStore() *MemoryWorkloadTokenStore {
+	return &MemoryWorkloadTokenStore{tokens: make(map[string]workloadToken)}
+}
+
+// SaveWorkloadToken stores a token and drops expired ones
+func (m *MemoryWorkloadTokenStore) SaveWorkloadToken(tokenHash string, user *users.User, expiresAt, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for hash, token := range m.tokens {
+		if !now.Before(token.expiresAt) {
+			delete(m.tokens, hash)
+		}
+	}
+	m.tokens[tokenHash] = workloadToken{user: *user, expiresAt: expiresAt}
+	return nil
+}
+
+// GetWorkloadToken returns the user of an unexpired token
+func (m *MemoryWorkloadTokenStore) GetWorkloadToken(tokenHash string, now time.Time) (*users.User, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[tokenHash]
+	if !ok || !now.Before(token.expiresAt) {
+		return nil, time.Time{}, nil
+	}
+	user := token.user
+	return &user, token.expiresAt, nil
+}
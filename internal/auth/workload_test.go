@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"innominatus/internal/users"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestWorkloadIdentityTTL(t *testing.T) {
+	ttl, err := WorkloadIdentityConfig{}.TTL()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultWorkloadTokenTTL, ttl)
+
+	ttl, err = WorkloadIdentityConfig{TokenTTL: "5m"}.TTL()
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, ttl)
+
+	for _, value := range []string{"soon", "-1m", "2h"} {
+		_, err := WorkloadIdentityConfig{TokenTTL: value}.TTL()
+		assert.Error(t, err, value)
+	}
+}
+
+func TestMapServiceAccount(t *testing.T) {
+	config := WorkloadIdentityConfig{ServiceAccounts: []ServiceAccountMapping{
+		{Namespace: "ci", Team: "platform"},
+		{Namespace: "ci", Name: "release-bot", Team: "platform", Role: "admin"},
+		{Namespace: "shop", Name: "deployer", Team: "shop-team"},
+		{Namespace: "broken", Team: "x", Role: "owner"},
+	}}
+
+	tests := []struct {
+		name     string
+		username string
+		want     *users.User
+		invalid  bool
+	}{
+		{name: "namespace mapping", username: "system:serviceaccount:ci:runner", want: &users.User{Username: "system:serviceaccount:ci:runner", Team: "platform", Role: "user"}},
+		{name: "named mapping wins", username: "system:serviceaccount:ci:release-bot", want: &users.User{Username: "system:serviceaccount:ci:release-bot", Team: "platform", Role: "admin"}},
+		{name: "named mapping only", username: "system:serviceaccount:shop:deployer", want: &users.User{Username: "system:serviceaccount:shop:deployer", Team: "shop-team", Role: "user"}},
+		{name: "other account of named mapping", username: "system:serviceaccount:shop:default", invalid: true},
+		{name: "unmapped namespace", username: "system:serviceaccount:kube-system:default", invalid: true},
+		{name: "not a service account", username: "alice", invalid: true},
+		{name: "malformed", username: "system:serviceaccount:ci", invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := config.MapServiceAccount(tt.username)
+			if tt.invalid {
+				assert.ErrorIs(t, err, ErrInvalidCredentials)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, user)
+		})
+	}
+
+	_, err := config.MapServiceAccount("system:serviceaccount:broken:default")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidCredentials, "a broken mapping is a configuration error")
+}
+
+func TestKubernetesTokenReviewer(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token != "valid" {
+			review.Status = authenticationv1.TokenReviewStatus{Error: "token expired"}
+			return true, review, nil
+		}
+		review.Status = authenticationv1.TokenReviewStatus{
+			Authenticated: true,
+			User:          authenticationv1.UserInfo{Username: "system:serviceaccount:ci:runner"},
+			Audiences:     review.Spec.Audiences,
+		}
+		return true, review, nil
+	})
+	reviewer := NewKubernetesTokenReviewerWithClient(client)
+
+	username, err := reviewer.Review(context.Background(), "valid", []string{"innominatus"})
+	require.NoError(t, err)
+	assert.Equal(t, "system:serviceaccount:ci:runner", username)
+
+	_, err = reviewer.Review(context.Background(), "stale", nil)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Contains(t, err.Error(), "token expired")
+}
+
+func TestMemoryWorkloadTokenStore(t *testing.T) {
+	store := NewMemoryWorkloadTokenStore()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	user := &users.User{Username: "system:serviceaccount:ci:runner", Team: "platform", Role: "user"}
+
+	require.NoError(t, store.SaveWorkloadToken("hash-1", user, now.Add(15*time.Minute), now))
+
+	got, expiresAt, err := store.GetWorkloadToken("hash-1", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, user, got)
+	assert.Equal(t, now.Add(15*time.Minute), expiresAt)
+
+	got, _, err = store.GetWorkloadToken("hash-1", now.Add(15*time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, got, "expired")
+
+	got, _, err = store.GetWorkloadToken("unknown", now)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// Saving prunes expired tokens
+	require.NoError(t, store.SaveWorkloadToken("hash-2", user, now.Add(time.Hour), now.Add(20*time.Minute)))
+	assert.Len(t, store.tokens, 1)
+}
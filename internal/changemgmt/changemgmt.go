@@ -0,0 +1,262 @@
+// Package changemgmt opens, gates and closes change tickets in an external service
+// catalog (ServiceNow or Jira) for golden path runs in protected environments.
+package changemgmt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported ticket systems
+const (
+	SystemServiceNow = "servicenow"
+	SystemJira       = "jira"
+)
+
+// Approval states reported by ticket systems
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+const (
+	defaultPollInterval    = 30 * time.Second
+	defaultApprovalTimeout = 24 * time.Hour
+	requestTimeout         = 15 * time.Second
+)
+
+// defaultEnvironments are ticketed when the config lists none
+var defaultEnvironments = []string{"production", "prod"}
+
+// Config is the changeManagement section of admin-config.yaml
+type Config struct {
+	Enabled         bool     `yaml:"enabled" json:"enabled"`
+	System          string   `yaml:"system" json:"system"` // servicenow or jira
+	URL             string   `yaml:"url" json:"url"`
+	Username        string   `yaml:"username" json:"username"`
+	TokenEnv        string   `yaml:"tokenEnv" json:"tokenEnv"`         // Environment variable holding the password or API token
+	Environments    []string `yaml:"environments" json:"environments"` // Environments that need a change ticket (default: production, prod)
+	RequireApproval bool     `yaml:"requireApproval" json:"requireApproval"`
+	PollInterval    string   `yaml:"pollInterval" json:"pollInterval"`       // How often approval state is checked (default: 30s)
+	ApprovalTimeout string   `yaml:"approvalTimeout" json:"approvalTimeout"` // How long a run waits for approval (default: 24h)
+	ExecutionURL    string   `yaml:"executionURL" json:"executionURL"`       // Link to a workflow execution; {id} is replaced by the execution ID
+	Jira            struct {
+		Project          string   `yaml:"project" json:"project"`
+		IssueType        string   `yaml:"issueType" json:"issueType"`               // Default: Change
+		ApprovedStatuses []string `yaml:"approvedStatuses" json:"approvedStatuses"` // Default: Approved
+		RejectedStatuses []string `yaml:"rejectedStatuses" json:"rejectedStatuses"` // Default: Declined, Rejected
+		CloseTransition  string   `yaml:"closeTransition" json:"closeTransition"`   // Default: Done
+	} `yaml:"jira" json:"jira"`
+	ServiceNow struct {
+		AssignmentGroup string `yaml:"assignmentGroup" json:"assignmentGroup"`
+		Category        string `yaml:"category" json:"category"` // Default: Software
+	} `yaml:"servicenow" json:"servicenow"`
+}
+
+// Change describes the golden path run a ticket is opened for
+type Change struct {
+	Application string
+	Team        string
+	GoldenPath  string
+	Environment string
+	RequestedBy string
+}
+
+// Ticket identifies a change ticket in the external system
+type Ticket struct {
+	System string `json:"system"`
+	ID     string `json:"id"`     // sys_id in ServiceNow, issue key in Jira
+	Number string `json:"number"` // Human-readable ticket number
+	URL    string `json:"url"`
+}
+
+// Outcome is recorded on a ticket when it is closed. ExecutionID is 0 when the run
+// never started, e.g. because the change was rejected.
+type Outcome struct {
+	ExecutionID  int64
+	ExecutionURL string
+	Err          error
+}
+
+// Succeeded reports whether the run completed
+func (o Outcome) Succeeded() bool {
+	return o.ExecutionID != 0 && o.Err == nil
+}
+
+// Notes returns the closing notes written to the ticket
+func (o Outcome) Notes() string {
+	var notes string
+	switch {
+	case o.Succeeded():
+		notes = fmt.Sprintf("Workflow execution %d completed successfully.", o.ExecutionID)
+	case o.ExecutionID != 0:
+		notes = fmt.Sprintf("Workflow execution %d failed: %v", o.ExecutionID, o.Err)
+	default:
+		notes = fmt.Sprintf("Change was not implemented: %v", o.Err)
+	}
+	if o.ExecutionURL != "" {
+		notes += "\n" + o.ExecutionURL
+	}
+	return notes
+}
+
+// TicketSystem is an external service catalog that tracks changes
+type TicketSystem interface {
+	Open(ctx context.Context, change Change) (*Ticket, error)
+	ApprovalState(ctx context.Context, ticket *Ticket) (string, error)
+	Close(ctx context.Context, ticket *Ticket, outcome Outcome) error
+}
+
+// Manager applies the change management policy to golden path runs
+type Manager struct {
+	config          Config
+	system          TicketSystem
+	pollInterval    time.Duration
+	approvalTimeout time.Duration
+}
+
+// NewManager creates a manager for an enabled config. It returns nil without error
+// when change management is disabled.
+func NewManager(config Config) (*Manager, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("changeManagement.url is required")
+	}
+
+	token := ""
+	if config.TokenEnv != "" {
+		token = os.Getenv(config.TokenEnv)
+	}
+
+	var system TicketSystem
+	switch strings.ToLower(config.System) {
+	case SystemServiceNow:
+		system = NewServiceNow(config, token)
+	case SystemJira:
+		if config.Jira.Project == "" {
+			return nil, fmt.Errorf("changeManagement.jira.project is required")
+		}
+		system = NewJira(config, token)
+	default:
+		return nil, fmt.Errorf("unknown change management system '%s' (use servicenow or jira)", config.System)
+	}
+
+	return newManager(config, system)
+}
+
+func newManager(config Config, system TicketSystem) (*Manager, error) {
+	m := &Manager{
+		config:          config,
+		system:          system,
+		pollInterval:    defaultPollInterval,
+		approvalTimeout: defaultApprovalTimeout,
+	}
+	if config.PollInterval != "" {
+		d, err := time.ParseDuration(config.PollInterval)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid changeManagement.pollInterval '%s'", config.PollInterval)
+		}
+		m.pollInterval = d
+	}
+	if config.ApprovalTimeout != "" {
+		d, err := time.ParseDuration(config.ApprovalTimeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid changeManagement.approvalTimeout '%s'", config.ApprovalTimeout)
+		}
+		m.approvalTimeout = d
+	}
+	return m, nil
+}
+
+// Applies reports whether runs in the environment need a change ticket
+func (m *Manager) Applies(environment string) bool {
+	if m == nil || environment == "" {
+		return false
+	}
+	environments := m.config.Environments
+	if len(environments) == 0 {
+		environments = defaultEnvironments
+	}
+	for _, env := range environments {
+		if strings.EqualFold(env, environment) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresApproval reports whether runs wait for the ticket to be approved
+func (m *Manager) RequiresApproval() bool {
+	return m != nil && m.config.RequireApproval
+}
+
+// Open creates a change ticket for a run
+func (m *Manager) Open(ctx context.Context, change Change) (*Ticket, error) {
+	ticket, err := m.system.Open(ctx, change)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open change ticket: %w", err)
+	}
+	return ticket, nil
+}
+
+// WaitForApproval blocks until the ticket is approved. It returns an error when the
+// ticket is rejected, the approval timeout passes or ctx is cancelled.
+func (m *Manager) WaitForApproval(ctx context.Context, ticket *Ticket) error {
+	deadline := time.Now().Add(m.approvalTimeout)
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		state, err := m.system.ApprovalState(ctx, ticket)
+		if err != nil {
+			return fmt.Errorf("failed to check approval of change %s: %w", ticket.Number, err)
+		}
+
+		switch state {
+		case ApprovalApproved:
+			return nil
+		case ApprovalRejected:
+			return fmt.Errorf("change %s was rejected", ticket.Number)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("change %s was not approved within %s", ticket.Number, m.approvalTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close records the outcome on the ticket and closes it, linking the workflow execution
+// when the config sets executionURL
+func (m *Manager) Close(ctx context.Context, ticket *Ticket, outcome Outcome) error {
+	if outcome.ExecutionID != 0 && outcome.ExecutionURL == "" && m.config.ExecutionURL != "" {
+		outcome.ExecutionURL = strings.ReplaceAll(m.config.ExecutionURL, "{id}", strconv.FormatInt(outcome.ExecutionID, 10))
+	}
+	if err := m.system.Close(ctx, ticket, outcome); err != nil {
+		return fmt.Errorf("failed to close change ticket %s: %w", ticket.Number, err)
+	}
+	return nil
+}
+
+// describe renders the ticket description shared by all systems
+func describe(change Change) string {
+	return fmt.Sprintf("Golden path '%s' for application '%s' in environment '%s'.\nRequested by %s (team %s) via innominatus.",
+		change.GoldenPath, change.Application, change.Environment, change.RequestedBy, change.Team)
+}
+
+// summarize renders the one-line ticket title shared by all systems
+func summarize(change Change) string {
+	return fmt.Sprintf("Deploy %s to %s (golden path %s)", change.Application, change.Environment, change.GoldenPath)
+}
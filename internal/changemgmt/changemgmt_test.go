@@ -0,0 +1,245 @@
+package changemgmt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testChange = Change{
+	Application: "shop",
+	Team:        "ecommerce",
+	GoldenPath:  "deploy-app",
+	Environment: "production",
+	RequestedBy: "alice",
+}
+
+// scriptedSystem returns approval states in order and records closed outcomes
+type scriptedSystem struct {
+	states []string
+	closed []Outcome
+}
+
+func (s *scriptedSystem) Open(ctx context.Context, change Change) (*Ticket, error) {
+	return &Ticket{System: "test", ID: "1", Number: "CHG0001"}, nil
+}
+
+func (s *scriptedSystem) ApprovalState(ctx context.Context, ticket *Ticket) (string, error) {
+	state := s.states[0]
+	if len(s.states) > 1 {
+		s.states = s.states[1:]
+	}
+	return state, nil
+}
+
+func (s *scriptedSystem) Close(ctx context.Context, ticket *Ticket, outcome Outcome) error {
+	s.closed = append(s.closed, outcome)
+	return nil
+}
+
+func TestNewManager(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantNil bool
+		wantErr string
+	}{
+		{name: "disabled", config: Config{System: SystemJira}, wantNil: true},
+		{name: "servicenow", config: Config{Enabled: true, System: "ServiceNow", URL: "https://example.service-now.com"}},
+		{name: "missing url", config: Config{Enabled: true, System: SystemServiceNow}, wantErr: "url is required"},
+		{name: "jira without project", config: Config{Enabled: true, System: SystemJira, URL: "https://jira.example.com"}, wantErr: "jira.project is required"},
+		{name: "unknown system", config: Config{Enabled: true, System: "remedy", URL: "https://remedy.example.com"}, wantErr: "unknown change management system"},
+		{name: "invalid poll interval", config: Config{Enabled: true, System: SystemServiceNow, URL: "https://x", PollInterval: "soon"}, wantErr: "pollInterval"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, err := NewManager(tt.config)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNil, manager == nil)
+		})
+	}
+}
+
+func TestManagerApplies(t *testing.T) {
+	var disabled *Manager
+	assert.False(t, disabled.Applies("production"))
+	assert.False(t, disabled.RequiresApproval())
+
+	defaults, err := newManager(Config{}, &scriptedSystem{})
+	require.NoError(t, err)
+	assert.True(t, defaults.Applies("production"))
+	assert.True(t, defaults.Applies("PROD"))
+	assert.False(t, defaults.Applies("staging"))
+	assert.False(t, defaults.Applies(""))
+
+	custom, err := newManager(Config{Environments: []string{"live"}}, &scriptedSystem{})
+	require.NoError(t, err)
+	assert.True(t, custom.Applies("live"))
+	assert.False(t, custom.Applies("production"))
+}
+
+func TestWaitForApproval(t *testing.T) {
+	tests := []struct {
+		name    string
+		states  []string
+		timeout string
+		wantErr string
+	}{
+		{name: "approved after pending", states: []string{ApprovalPending, ApprovalPending, ApprovalApproved}},
+		{name: "rejected", states: []string{ApprovalPending, ApprovalRejected}, wantErr: "CHG0001 was rejected"},
+		{name: "timeout", states: []string{ApprovalPending}, timeout: "30ms", wantErr: "not approved within 30ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, err := newManager(Config{PollInterval: "5ms", ApprovalTimeout: tt.timeout}, &scriptedSystem{states: tt.states})
+			require.NoError(t, err)
+
+			ticket, err := manager.Open(context.Background(), testChange)
+			require.NoError(t, err)
+
+			err = manager.WaitForApproval(context.Background(), ticket)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestManagerCloseLinksExecution(t *testing.T) {
+	system := &scriptedSystem{}
+	manager, err := newManager(Config{ExecutionURL: "https://idp.example.com/workflows/{id}"}, system)
+	require.NoError(t, err)
+
+	ticket := &Ticket{ID: "1", Number: "CHG0001"}
+	require.NoError(t, manager.Close(context.Background(), ticket, Outcome{ExecutionID: 42}))
+	require.NoError(t, manager.Close(context.Background(), ticket, Outcome{Err: errors.New("change was rejected")}))
+
+	require.Len(t, system.closed, 2)
+	assert.Equal(t, "https://idp.example.com/workflows/42", system.closed[0].ExecutionURL)
+	assert.Equal(t, "Workflow execution 42 completed successfully.\nhttps://idp.example.com/workflows/42", system.closed[0].Notes())
+	assert.Empty(t, system.closed[1].ExecutionURL)
+	assert.Equal(t, "Change was not implemented: change was rejected", system.closed[1].Notes())
+}
+
+func TestServiceNow(t *testing.T) {
+	var closed map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "svc-idp", user)
+		assert.Equal(t, "secret", password)
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/now/table/change_request":
+			var fields map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&fields))
+			assert.Equal(t, "Deploy shop to production (golden path deploy-app)", fields["short_description"])
+			assert.Equal(t, "CAB", fields["assignment_group"])
+			_, _ = w.Write([]byte(`{"result":{"sys_id":"abc123","number":"CHG0030001"}}`))
+		case r.Method == "GET" && r.URL.Path == "/api/now/table/change_request/abc123":
+			_, _ = w.Write([]byte(`{"result":{"approval":"approved"}}`))
+		case r.Method == "PATCH" && r.URL.Path == "/api/now/table/change_request/abc123":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&closed))
+			_, _ = w.Write([]byte(`{"result":{}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config := Config{URL: server.URL, Username: "svc-idp"}
+	config.ServiceNow.AssignmentGroup = "CAB"
+	system := NewServiceNow(config, "secret")
+
+	ticket, err := system.Open(context.Background(), testChange)
+	require.NoError(t, err)
+	assert.Equal(t, "CHG0030001", ticket.Number)
+	assert.Equal(t, server.URL+"/nav_to.do?uri=change_request.do?sys_id=abc123", ticket.URL)
+
+	state, err := system.ApprovalState(context.Background(), ticket)
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalApproved, state)
+
+	require.NoError(t, system.Close(context.Background(), ticket, Outcome{ExecutionID: 7, Err: errors.New("step deploy failed")}))
+	assert.Equal(t, "3", closed["state"])
+	assert.Equal(t, "unsuccessful", closed["close_code"])
+	assert.Contains(t, closed["close_notes"], "Workflow execution 7 failed")
+}
+
+func TestJira(t *testing.T) {
+	status := "Waiting for approval"
+	var comment, transition string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer pat", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue":
+			var body struct {
+				Fields struct {
+					Project   map[string]string `json:"project"`
+					IssueType map[string]string `json:"issuetype"`
+				} `json:"fields"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "OPS", body.Fields.Project["key"])
+			assert.Equal(t, "Change", body.Fields.IssueType["name"])
+			_, _ = w.Write([]byte(`{"id":"10001","key":"OPS-12"}`))
+		case r.Method == "GET" && r.URL.Path == "/rest/api/2/issue/OPS-12":
+			_, _ = w.Write([]byte(`{"fields":{"status":{"name":"` + status + `"}}}`))
+		case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue/OPS-12/comment":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			comment = body["body"]
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "GET" && r.URL.Path == "/rest/api/2/issue/OPS-12/transitions":
+			_, _ = w.Write([]byte(`{"transitions":[{"id":"11","name":"In Progress"},{"id":"31","name":"Done"}]}`))
+		case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue/OPS-12/transitions":
+			var body struct {
+				Transition map[string]string `json:"transition"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			transition = body.Transition["id"]
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config := Config{URL: server.URL}
+	config.Jira.Project = "OPS"
+	system := NewJira(config, "pat")
+
+	ticket, err := system.Open(context.Background(), testChange)
+	require.NoError(t, err)
+	assert.Equal(t, "OPS-12", ticket.ID)
+	assert.Equal(t, server.URL+"/browse/OPS-12", ticket.URL)
+
+	state, err := system.ApprovalState(context.Background(), ticket)
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalPending, state)
+
+	status = "Declined"
+	state, err = system.ApprovalState(context.Background(), ticket)
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalRejected, state)
+
+	require.NoError(t, system.Close(context.Background(), ticket, Outcome{ExecutionID: 9, ExecutionURL: "https://idp.example.com/workflows/9"}))
+	assert.Equal(t, "Workflow execution 9 completed successfully.\nhttps://idp.example.com/workflows/9", comment)
+	assert.Equal(t, "31", transition)
+}
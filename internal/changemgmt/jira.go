@@ -0,0 +1,177 @@
+package changemgmt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Jira tracks changes as issues whose workflow status carries the approval
+type Jira struct {
+	baseURL  string
+	username string
+	token    string
+	config   Config
+	client   *http.Client
+}
+
+// NewJira creates a Jira client. With a username it authenticates with basic auth
+// (Jira Cloud API tokens), otherwise with a bearer personal access token.
+func NewJira(config Config, token string) *Jira {
+	return &Jira{
+		baseURL:  strings.TrimSuffix(config.URL, "/"),
+		username: config.Username,
+		token:    token,
+		config:   config,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Open creates an issue in the configured project
+func (j *Jira) Open(ctx context.Context, change Change) (*Ticket, error) {
+	issueType := j.config.Jira.IssueType
+	if issueType == "" {
+		issueType = "Change"
+	}
+	body := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": j.config.Jira.Project},
+			"issuetype":   map[string]string{"name": issueType},
+			"summary":     summarize(change),
+			"description": describe(change),
+		},
+	}
+
+	var issue struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}
+	if err := j.do(ctx, "POST", "/rest/api/2/issue", body, &issue); err != nil {
+		return nil, err
+	}
+	return &Ticket{
+		System: SystemJira,
+		ID:     issue.Key,
+		Number: issue.Key,
+		URL:    fmt.Sprintf("%s/browse/%s", j.baseURL, issue.Key),
+	}, nil
+}
+
+// ApprovalState maps the issue status to an approval state
+func (j *Jira) ApprovalState(ctx context.Context, ticket *Ticket) (string, error) {
+	var issue struct {
+		Fields struct {
+			Status struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := j.do(ctx, "GET", fmt.Sprintf("/rest/api/2/issue/%s?fields=status", url.PathEscape(ticket.ID)), nil, &issue); err != nil {
+		return "", err
+	}
+
+	status := issue.Fields.Status.Name
+	approved := j.config.Jira.ApprovedStatuses
+	if len(approved) == 0 {
+		approved = []string{"Approved"}
+	}
+	rejected := j.config.Jira.RejectedStatuses
+	if len(rejected) == 0 {
+		rejected = []string{"Declined", "Rejected"}
+	}
+	switch {
+	case containsFold(approved, status):
+		return ApprovalApproved, nil
+	case containsFold(rejected, status):
+		return ApprovalRejected, nil
+	default:
+		return ApprovalPending, nil
+	}
+}
+
+// Close comments the outcome on the issue and applies the close transition
+func (j *Jira) Close(ctx context.Context, ticket *Ticket, outcome Outcome) error {
+	issuePath := "/rest/api/2/issue/" + url.PathEscape(ticket.ID)
+	if err := j.do(ctx, "POST", issuePath+"/comment", map[string]string{"body": outcome.Notes()}, nil); err != nil {
+		return err
+	}
+
+	name := j.config.Jira.CloseTransition
+	if name == "" {
+		name = "Done"
+	}
+	var transitions struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}
+	if err := j.do(ctx, "GET", issuePath+"/transitions", nil, &transitions); err != nil {
+		return err
+	}
+	for _, transition := range transitions.Transitions {
+		if strings.EqualFold(transition.Name, name) {
+			body := map[string]interface{}{"transition": map[string]string{"id": transition.ID}}
+			return j.do(ctx, "POST", issuePath+"/transitions", body, nil)
+		}
+	}
+	return fmt.Errorf("issue %s has no transition '%s'", ticket.ID, name)
+}
+
+// do sends a REST API request and decodes the response into result
+func (j *Jira) do(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, j.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if j.username != "" {
+		req.SetBasicAuth(j.username, j.token)
+	} else if j.token != "" {
+		req.Header.Set("Authorization", "Bearer "+j.token)
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("jira returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode jira response: %w", err)
+	}
+	return nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
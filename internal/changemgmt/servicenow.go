@@ -0,0 +1,141 @@
+package changemgmt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ServiceNow tracks changes as change_request records via the Table API
+type ServiceNow struct {
+	baseURL  string
+	username string
+	token    string
+	config   Config
+	client   *http.Client
+}
+
+// NewServiceNow creates a ServiceNow client authenticating with basic auth
+func NewServiceNow(config Config, token string) *ServiceNow {
+	return &ServiceNow{
+		baseURL:  strings.TrimSuffix(config.URL, "/"),
+		username: config.Username,
+		token:    token,
+		config:   config,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+type serviceNowRecord struct {
+	SysID    string `json:"sys_id"`
+	Number   string `json:"number"`
+	Approval string `json:"approval"`
+}
+
+// Open creates a normal change request
+func (s *ServiceNow) Open(ctx context.Context, change Change) (*Ticket, error) {
+	category := s.config.ServiceNow.Category
+	if category == "" {
+		category = "Software"
+	}
+	fields := map[string]string{
+		"type":              "normal",
+		"category":          category,
+		"short_description": summarize(change),
+		"description":       describe(change),
+	}
+	if s.config.ServiceNow.AssignmentGroup != "" {
+		fields["assignment_group"] = s.config.ServiceNow.AssignmentGroup
+	}
+
+	var record serviceNowRecord
+	if err := s.do(ctx, "POST", "/api/now/table/change_request", fields, &record); err != nil {
+		return nil, err
+	}
+	return &Ticket{
+		System: SystemServiceNow,
+		ID:     record.SysID,
+		Number: record.Number,
+		URL:    fmt.Sprintf("%s/nav_to.do?uri=change_request.do?sys_id=%s", s.baseURL, url.QueryEscape(record.SysID)),
+	}, nil
+}
+
+// ApprovalState maps the change request's approval field
+func (s *ServiceNow) ApprovalState(ctx context.Context, ticket *Ticket) (string, error) {
+	var record serviceNowRecord
+	path := fmt.Sprintf("/api/now/table/change_request/%s?sysparm_fields=approval", url.PathEscape(ticket.ID))
+	if err := s.do(ctx, "GET", path, nil, &record); err != nil {
+		return "", err
+	}
+	switch record.Approval {
+	case "approved":
+		return ApprovalApproved, nil
+	case "rejected":
+		return ApprovalRejected, nil
+	default:
+		return ApprovalPending, nil
+	}
+}
+
+// Close moves the change request to Closed with a close code for the outcome
+func (s *ServiceNow) Close(ctx context.Context, ticket *Ticket, outcome Outcome) error {
+	closeCode := "unsuccessful"
+	if outcome.Succeeded() {
+		closeCode = "successful"
+	}
+	fields := map[string]string{
+		"state":       "3", // Closed
+		"close_code":  closeCode,
+		"close_notes": outcome.Notes(),
+	}
+	return s.do(ctx, "PATCH", "/api/now/table/change_request/"+url.PathEscape(ticket.ID), fields, nil)
+}
+
+// do sends a Table API request and decodes the "result" object into result
+func (s *ServiceNow) do(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.SetBasicAuth(s.username, s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("servicenow request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("servicenow returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if result == nil {
+		return nil
+	}
+
+	envelope := struct {
+		Result interface{} `json:"result"`
+	}{Result: result}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode servicenow response: %w", err)
+	}
+	return nil
+}
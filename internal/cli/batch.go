@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BatchResult is the outcome of an operation on one application
+type BatchResult struct {
+	App        string `json:"app"`
+	Status     string `json:"status"` // succeeded or failed
+	Error      string `json:"error,omitempty"`
+	ExitCode   int    `json:"exit_code,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	duration   time.Duration
+	err        error
+}
+
+// BatchSummary is the JSON output of a multi-app operation
+type BatchSummary struct {
+	Action    string        `json:"action"`
+	Parallel  int           `json:"parallel"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+}
+
+// runBatch runs op for every app with at most parallel workers. progress is called,
+// one call at a time, as each app finishes. Results are in the order of apps.
+func runBatch(apps []string, parallel int, op func(app string) error, progress func(done int, result BatchResult)) []BatchResult {
+	if parallel > len(apps) {
+		parallel = len(apps)
+	}
+
+	results := make([]BatchResult, len(apps))
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	done := 0
+
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				started := time.Now()
+				err := op(apps[i])
+				duration := time.Since(started)
+				result := BatchResult{App: apps[i], Status: "succeeded", DurationMs: duration.Milliseconds(), duration: duration, err: err}
+				if err != nil {
+					result.Status = "failed"
+					result.Error = err.Error()
+					result.ExitCode = ExitCode(err)
+				}
+
+				mu.Lock()
+				results[i] = result
+				done++
+				if progress != nil {
+					progress(done, result)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range apps {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// uniqueApps removes repeated application names, keeping the first occurrence
+func uniqueApps(apps []string) []string {
+	seen := make(map[string]bool, len(apps))
+	unique := make([]string, 0, len(apps))
+	for _, app := range apps {
+		if !seen[app] {
+			seen[app] = true
+			unique = append(unique, app)
+		}
+	}
+	return unique
+}
+
+// batchCommand runs an application operation on several applications with a worker
+// pool, printing a progress line per finished application and a summary table.
+// action is the past tense shown to the user, e.g. "deprovisioned".
+func (c *Client) batchCommand(action string, apps []string, parallel int, op func(app string) error) error {
+	if parallel < 1 {
+		return usageError("--parallel must be at least 1, got %d", parallel)
+	}
+	apps = uniqueApps(apps)
+
+	text := !c.Formatter.IsJSON() && !c.Formatter.IsYAML()
+	if text {
+		c.Formatter.PrintInfo(fmt.Sprintf("Running on %d applications, %d at a time", len(apps), min(parallel, len(apps))))
+	}
+
+	results := runBatch(apps, parallel, op, func(done int, result BatchResult) {
+		if !text {
+			return
+		}
+		prefix := fmt.Sprintf("[%d/%d]", done, len(apps))
+		if result.err != nil {
+			c.Formatter.PrintItem(0, SymbolError, fmt.Sprintf("%s %s failed: %v", prefix, result.App, result.err))
+		} else {
+			c.Formatter.PrintItem(0, SymbolSuccess, fmt.Sprintf("%s %s %s (%s)", prefix, result.App, action, c.Formatter.FormatDuration(result.duration)))
+		}
+	})
+
+	summary := BatchSummary{Action: action, Parallel: parallel, Results: results}
+	exitCode := 0
+	for _, result := range results {
+		if result.err == nil {
+			summary.Succeeded++
+			continue
+		}
+		summary.Failed++
+		// The exit code is the error class shared by all failures, if there is one
+		if exitCode == 0 {
+			exitCode = result.ExitCode
+		} else if exitCode != result.ExitCode {
+			exitCode = ExitError
+		}
+	}
+
+	switch {
+	case c.Formatter.IsJSON():
+		if err := c.Formatter.PrintJSON(summary); err != nil {
+			return err
+		}
+	case c.Formatter.IsYAML():
+		if err := c.Formatter.PrintYAML(summary); err != nil {
+			return err
+		}
+	default:
+		c.printBatchSummary(summary)
+	}
+
+	if summary.Failed > 0 {
+		return WithExitCode(exitCode, fmt.Errorf("%d of %d applications failed", summary.Failed, len(results)))
+	}
+	return nil
+}
+
+// printBatchSummary prints the result of every application as a table
+func (c *Client) printBatchSummary(summary BatchSummary) {
+	width := len("APPLICATION")
+	for _, result := range summary.Results {
+		width = max(width, len(result.App))
+	}
+	columns := []TableColumn{{Header: "APPLICATION", Width: width}, {Header: "STATUS", Width: 10}, {Header: "DURATION", Width: 10}, {Header: "ERROR", Width: 0}}
+
+	c.Formatter.PrintEmpty()
+	c.Formatter.PrintTableHeader(columns)
+	for _, result := range summary.Results {
+		c.Formatter.PrintTableRow(columns, []string{result.App, result.Status, c.Formatter.FormatDuration(result.duration), result.Error})
+	}
+	c.Formatter.PrintEmpty()
+
+	if summary.Failed == 0 {
+		c.Formatter.PrintSuccess(fmt.Sprintf("All %d applications %s", summary.Succeeded, summary.Action))
+	} else {
+		c.Formatter.PrintWarning(fmt.Sprintf("%d succeeded, %d failed", summary.Succeeded, summary.Failed))
+	}
+}
+
+// DeleteAppsCommand deletes applications, parallel at a time
+func (c *Client) DeleteAppsCommand(apps []string, parallel int) error {
+	if len(apps) == 1 {
+		return c.DeleteCommand(apps[0])
+	}
+	return c.batchCommand("deleted", apps, parallel, c.DeleteApplication)
+}
+
+// DeprovisionAppsCommand deprovisions the infrastructure of applications, parallel at a time
+func (c *Client) DeprovisionAppsCommand(apps []string, parallel int) error {
+	if len(apps) == 1 {
+		return c.DeprovisionCommand(apps[0])
+	}
+	return c.batchCommand("deprovisioned", apps, parallel, c.DeprovisionApplication)
+}
+
+// HibernateAppsCommand hibernates applications, parallel at a time
+func (c *Client) HibernateAppsCommand(apps []string, reason string, parallel int) error {
+	if len(apps) == 1 {
+		return c.HibernateCommand(apps[0], reason)
+	}
+	return c.batchCommand("hibernated", apps, parallel, func(app string) error {
+		return c.HibernateApplication(app, reason)
+	})
+}
+
+// ResumeAppsCommand resumes hibernated applications, parallel at a time
+func (c *Client) ResumeAppsCommand(apps []string, parallel int) error {
+	if len(apps) == 1 {
+		return c.ResumeCommand(apps[0])
+	}
+	return c.batchCommand("resumed", apps, parallel, c.ResumeApplication)
+}
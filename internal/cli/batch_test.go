@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBatch_LimitsWorkers(t *testing.T) {
+	apps := []string{"a", "b", "c", "d", "e", "f"}
+	var running, maxRunning int32
+	var progressCalls []int
+	var mu sync.Mutex
+
+	results := runBatch(apps, 2, func(app string) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if app == "c" {
+			return assert.AnError
+		}
+		return nil
+	}, func(done int, result BatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		progressCalls = append(progressCalls, done)
+	})
+
+	assert.LessOrEqual(t, maxRunning, int32(2))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, progressCalls)
+	require.Len(t, results, len(apps))
+	for i, result := range results {
+		assert.Equal(t, apps[i], result.App, "results keep the order of the apps")
+	}
+	assert.Equal(t, "failed", results[2].Status)
+	assert.Equal(t, assert.AnError.Error(), results[2].Error)
+	assert.Equal(t, "succeeded", results[0].Status)
+}
+
+func TestDeprovisionAppsCommand(t *testing.T) {
+	var mu sync.Mutex
+	var deprovisioned []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		app := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/applications/"), "/deprovision")
+		mu.Lock()
+		deprovisioned = append(deprovisioned, app)
+		mu.Unlock()
+		if strings.HasPrefix(app, "missing") {
+			http.Error(w, "Application not found", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.Formatter.SetFormat(OutputFormatJSON)
+
+	require.NoError(t, client.DeprovisionAppsCommand([]string{"app1", "app2", "app1"}, 4))
+	assert.ElementsMatch(t, []string{"app1", "app2"}, deprovisioned, "repeated apps run once")
+
+	// Failures of one class exit with its code
+	err := client.DeprovisionAppsCommand([]string{"app1", "missing1", "missing2"}, 2)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 of 3 applications failed")
+	assert.Equal(t, ExitNotFound, ExitCode(err))
+
+	err = client.DeprovisionAppsCommand([]string{"app1"}, 0)
+	require.NoError(t, err, "a single app keeps the single-app command")
+
+	err = client.DeprovisionAppsCommand([]string{"app1", "app2"}, 0)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+}
@@ -1,20 +1,30 @@
 package cli
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"innominatus/internal/providers"
+	"innominatus/internal/usage"
+	"innominatus/pkg/sdk"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 )
 
 type Client struct {
-	baseURL   string
-	client    *http.Client
-	token     string
-	http      *HTTPHelper      // HTTP helper for common operations
-	Formatter *OutputFormatter // Output formatter for CLI output
+	baseURL    string
+	client     *http.Client
+	token      string
+	cliVersion string
+	http       *HTTPHelper      // HTTP helper for common operations
+	Formatter  *OutputFormatter // Output formatter for CLI output
+
+	creds        *Credentials // Stored credentials the token was loaded from, nil for IDP_API_KEY
+	expiredCreds *Credentials // Stored credentials that had expired, renewed after an interactive login
 }
 
 func NewClient(baseURL string) *Client {
@@ -23,6 +33,7 @@ func NewClient(baseURL string) *Client {
 	}
 
 	token := ""
+	var stored, expired *Credentials
 	// Priority order for API key:
 	// 1. Environment variable (highest priority - for CI/CD)
 	// 2. Credentials file ($HOME/.innominatus/credentials)
@@ -32,25 +43,40 @@ func NewClient(baseURL string) *Client {
 		// Environment variable takes precedence
 		token = apiKey
 	} else {
-		// Try to load from credentials file
-		creds, err := LoadCredentials()
+		// Try to load from the keychain or credentials file, removing expired credentials
+		// like LoadCredentials
+		creds, err := ReadCredentials()
 		if err == nil && creds != nil {
-			token = creds.APIKey
+			if time.Now().After(creds.ExpiresAt) {
+				expired = creds
+				_ = ClearCredentials()
+			} else {
+				token = creds.APIKey
+				stored = creds
+			}
 		}
 		// If no credentials or error loading, token remains empty
 	}
 
 	client := &Client{
-		baseURL:   baseURL,
-		client:    httpClient,
-		token:     token,
-		http:      newHTTPHelper(baseURL, httpClient, token),
-		Formatter: NewOutputFormatter(),
+		baseURL:      baseURL,
+		client:       httpClient,
+		token:        token,
+		http:         newHTTPHelper(baseURL, httpClient, token),
+		Formatter:    NewOutputFormatter(),
+		creds:        stored,
+		expiredCreds: expired,
 	}
 
 	return client
 }
 
+// SetCLIVersion sets the CLI version sent to the server with every request
+func (c *Client) SetCLIVersion(version string) {
+	c.cliVersion = version
+	c.http.cliVersion = version
+}
+
 // HasToken returns true if the client has an API token loaded
 func (c *Client) HasToken() bool {
 	return c.token != ""
@@ -106,6 +132,9 @@ type ResourceInstance struct {
 	UpdatedAt        time.Time              `json:"updated_at"`
 	LastHealthCheck  *time.Time             `json:"last_health_check,omitempty"`
 	ErrorMessage     *string                `json:"error_message,omitempty"`
+	DriftState       string                 `json:"drift_state,omitempty"`
+	DriftMessage     *string                `json:"drift_message,omitempty"`
+	DriftCheckedAt   *time.Time             `json:"drift_checked_at,omitempty"`
 }
 
 type ProviderSummary struct {
@@ -122,6 +151,61 @@ type ProviderStats struct {
 	Provisioners int `json:"provisioners"`
 }
 
+// ProviderDetail is a provider with its compatibility and the parameters of each workflow
+type ProviderDetail struct {
+	Name          string                    `json:"name" yaml:"name"`
+	Version       string                    `json:"version" yaml:"version"`
+	Category      string                    `json:"category,omitempty" yaml:"category,omitempty"`
+	Description   string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	Compatibility sdk.ProviderCompatibility `json:"compatibility" yaml:"compatibility"`
+	Dependencies  []sdk.ProviderDependency  `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	Capabilities  sdk.ProviderCapabilities  `json:"capabilities" yaml:"capabilities"`
+	Workflows     []struct {
+		sdk.WorkflowMetadata `yaml:",inline"`
+		Parameters           []providers.WorkflowParameter `json:"parameters" yaml:"parameters,omitempty"`
+	} `json:"workflows" yaml:"workflows"`
+	Provisioners  []sdk.ProvisionerMetadata `json:"provisioners,omitempty" yaml:"provisioners,omitempty"`
+	Configuration map[string]interface{}    `json:"configuration,omitempty" yaml:"configuration,omitempty"`
+}
+
+// ProviderDocs is the rendered markdown documentation of a provider
+type ProviderDocs struct {
+	Provider     string `json:"provider" yaml:"provider"`
+	Version      string `json:"version" yaml:"version"`
+	Markdown     string `json:"markdown" yaml:"markdown"`
+	Provisioners []struct {
+		Name      string `json:"name" yaml:"name"`
+		Category  string `json:"category" yaml:"category"`
+		Operation string `json:"operation,omitempty" yaml:"operation,omitempty"`
+		Markdown  string `json:"markdown" yaml:"markdown"`
+	} `json:"provisioners" yaml:"provisioners"`
+}
+
+// ProviderVersionPin keeps an application, or the applications of a team, on a
+// provider version
+type ProviderVersionPin struct {
+	Scope     string    `json:"scope" yaml:"scope"`
+	ScopeName string    `json:"scope_name" yaml:"scope_name"`
+	Provider  string    `json:"provider" yaml:"provider"`
+	Version   string    `json:"version" yaml:"version"`
+	Reason    string    `json:"reason,omitempty" yaml:"reason,omitempty"`
+	PinnedBy  string    `json:"pinned_by" yaml:"pinned_by"`
+	PinnedAt  time.Time `json:"pinned_at" yaml:"pinned_at"`
+}
+
+// ProviderUpgradeReport lists pins with the changes removing them would bring
+type ProviderUpgradeReport struct {
+	GeneratedAt string `json:"generated_at" yaml:"generated_at"`
+	Pins        []struct {
+		ProviderVersionPin `yaml:",inline"`
+		CurrentVersion     string                    `json:"current_version,omitempty" yaml:"current_version,omitempty"`
+		Status             string                    `json:"status" yaml:"status"`
+		Applications       []string                  `json:"applications" yaml:"applications"`
+		Changes            *providers.VersionChanges `json:"changes,omitempty" yaml:"changes,omitempty"`
+		Error              string                    `json:"error,omitempty" yaml:"error,omitempty"`
+	} `json:"pins" yaml:"pins"`
+}
+
 // Stats represents platform statistics from the dashboard
 type Stats struct {
 	Applications int `json:"applications"`
@@ -140,6 +224,70 @@ type GoldenPathInfo struct {
 	Parameters        map[string]interface{} `json:"parameters,omitempty"`
 	RequiredParams    []string               `json:"required_params,omitempty"` // Deprecated
 	OptionalParams    map[string]string      `json:"optional_params,omitempty"` // Deprecated
+	Deprecated        *GoldenPathDeprecation `json:"deprecated,omitempty"`
+}
+
+// GoldenPathDeprecation describes a deprecated golden path and its successor
+type GoldenPathDeprecation struct {
+	Message      string            `json:"message,omitempty"`
+	Successor    string            `json:"successor,omitempty"`
+	Sunset       string            `json:"sunset,omitempty"`
+	ParameterMap map[string]string `json:"parameter_map,omitempty"`
+}
+
+// GoldenPathApplication is an application deployed with a golden path
+type GoldenPathApplication struct {
+	ApplicationName string            `json:"application_name"`
+	Team            string            `json:"team"`
+	ExecutionID     int64             `json:"execution_id"`
+	DeployedAt      time.Time         `json:"deployed_at"`
+	Parameters      map[string]string `json:"parameters"`
+}
+
+// GoldenPathMigrationPlan is the migration of one application to a successor golden path
+type GoldenPathMigrationPlan struct {
+	ApplicationName string            `json:"application_name"`
+	Team            string            `json:"team"`
+	Parameters      map[string]string `json:"parameters"`
+	Error           string            `json:"error,omitempty"`
+	MigrationID     int64             `json:"migration_id,omitempty"`
+}
+
+// GoldenPathMigrateResult is the response to a golden path migration request
+type GoldenPathMigrateResult struct {
+	GoldenPath   string                     `json:"golden_path"`
+	Successor    string                     `json:"successor"`
+	DryRun       bool                       `json:"dry_run"`
+	Applications []*GoldenPathMigrationPlan `json:"applications"`
+}
+
+// GoldenPathMigration is a recorded migration of an application
+type GoldenPathMigration struct {
+	ID              int64     `json:"id"`
+	GoldenPath      string    `json:"golden_path"`
+	Successor       string    `json:"successor"`
+	ApplicationName string    `json:"application_name"`
+	Team            string    `json:"team"`
+	Status          string    `json:"status"`
+	ExecutionID     *int64    `json:"execution_id,omitempty"`
+	ErrorMessage    *string   `json:"error_message,omitempty"`
+	StartedBy       string    `json:"started_by"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// GoldenPathMigrationStatus is the migration progress away from a golden path
+type GoldenPathMigrationStatus struct {
+	GoldenPath string `json:"golden_path"`
+	Successor  string `json:"successor,omitempty"`
+	Teams      []struct {
+		Team      string `json:"team"`
+		Remaining int    `json:"remaining"`
+		Pending   int    `json:"pending"`
+		Running   int    `json:"running"`
+		Succeeded int    `json:"succeeded"`
+		Failed    int    `json:"failed"`
+	} `json:"teams"`
+	Migrations []*GoldenPathMigration `json:"migrations"`
 }
 
 // Login authenticates with the server and stores the token
@@ -169,6 +317,28 @@ func (c *Client) Deploy(yamlContent []byte) (*DeployResponse, error) {
 	return &result, nil
 }
 
+// DeployWithOverlay deploys a Score specification with an environment overlay, which
+// the server merges into the spec
+func (c *Client) DeployWithOverlay(yamlContent, overlay []byte) (*DeployResponse, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("spec", string(yamlContent)); err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %w", err)
+	}
+	if err := writer.WriteField("overlay", string(overlay)); err != nil {
+		return nil, fmt.Errorf("failed to encode overlay: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	var result DeployResponse
+	if err := c.http.doRequest("POST", "/api/applications", &body, writer.FormDataContentType(), &result); err != nil {
+		return nil, fmt.Errorf("failed to deploy spec: %w", err)
+	}
+	return &result, nil
+}
+
 func (c *Client) ListSpecs() (map[string]*SpecResponse, error) {
 	var result map[string]*SpecResponse
 	// Updated to use /api/applications endpoint
@@ -187,6 +357,79 @@ func (c *Client) GetSpec(name string) (*SpecResponse, error) {
 	return &result, nil
 }
 
+// WorkflowExecutionSummary represents a workflow execution without its steps
+type WorkflowExecutionSummary struct {
+	ID              int64      `json:"id"`
+	ApplicationName string     `json:"application_name"`
+	WorkflowName    string     `json:"workflow_name"`
+	Status          string     `json:"status"`
+	StartedAt       time.Time  `json:"started_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	TotalSteps      int        `json:"total_steps"`
+	CompletedSteps  int        `json:"completed_steps"`
+	FailedSteps     int        `json:"failed_steps"`
+}
+
+// ResourceHint is a contextual link or value of a resource, e.g. a dashboard URL
+type ResourceHint struct {
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+	Query string `json:"query,omitempty"`
+}
+
+// ApplicationStatus is the consolidated status of an application
+type ApplicationStatus struct {
+	Name             string `json:"name"`
+	Team             string `json:"team"`
+	LatestDeployment *struct {
+		DeployedAt time.Time                 `json:"deployed_at"`
+		DeployedBy string                    `json:"deployed_by"`
+		Status     string                    `json:"status"`
+		Workflow   *WorkflowExecutionSummary `json:"workflow,omitempty"`
+		Provenance *struct {
+			ID     int64  `json:"id"`
+			Kind   string `json:"kind"`
+			Digest string `json:"digest"`
+		} `json:"provenance,omitempty"`
+	} `json:"latest_deployment"`
+	Resources []struct {
+		ID           int64          `json:"id"`
+		Name         string         `json:"name"`
+		Type         string         `json:"type"`
+		State        string         `json:"state"`
+		HealthStatus string         `json:"health_status,omitempty"`
+		ErrorMessage string         `json:"error_message,omitempty"`
+		Hints        []ResourceHint `json:"hints,omitempty"`
+		UpdatedAt    time.Time      `json:"updated_at"`
+	} `json:"resources"`
+	ActiveWorkflows []WorkflowExecutionSummary `json:"active_workflows"`
+	Health          struct {
+		Status           string `json:"status"`
+		Resources        int    `json:"resources"`
+		Healthy          int    `json:"healthy"`
+		FailingResources int    `json:"failing_resources"`
+	} `json:"health"`
+	RecentEvents []struct {
+		Time     time.Time `json:"time"`
+		Type     string    `json:"type"`
+		Subject  string    `json:"subject"`
+		Message  string    `json:"message"`
+		Severity string    `json:"severity"`
+	} `json:"recent_events"`
+	PendingApprovals []Approval `json:"pending_approvals"`
+	GeneratedAt      time.Time  `json:"generated_at"`
+}
+
+// GetApplicationStatus gets the consolidated status of an application in one call
+func (c *Client) GetApplicationStatus(name string) (*ApplicationStatus, error) {
+	var result ApplicationStatus
+	if err := c.http.GET("/api/applications/"+url.PathEscape(name)+"/status", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 func (c *Client) DeleteSpec(name string) error {
 	// Updated to use /api/applications endpoint
 	return c.http.DELETE("/api/applications/" + name)
@@ -228,6 +471,19 @@ func (c *Client) ListResources(appName string) (map[string][]*ResourceInstance,
 	return result, nil
 }
 
+// ListDriftedResources retrieves the resources that drifted from their desired configuration,
+// grouped by application, optionally for one application only
+func (c *Client) ListDriftedResources(appName string) (map[string][]*ResourceInstance, error) {
+	var result map[string][]*ResourceInstance
+	if err := c.http.GET("/api/resources?drift=true", &result); err != nil {
+		return nil, err
+	}
+	if appName != "" {
+		return map[string][]*ResourceInstance{appName: result[appName]}, nil
+	}
+	return result, nil
+}
+
 // DeleteApplication performs complete application deletion (infrastructure + database records)
 func (c *Client) DeleteApplication(name string) error {
 	return c.http.DELETE("/api/applications/" + name)
@@ -238,6 +494,17 @@ func (c *Client) DeprovisionApplication(name string) error {
 	return c.http.POST("/api/applications/"+name+"/deprovision", nil, nil)
 }
 
+// HibernateApplication scales an application's workloads to zero and pauses its resources
+func (c *Client) HibernateApplication(name, reason string) error {
+	data := map[string]string{"reason": reason}
+	return c.http.POST("/api/applications/"+name+"/hibernate", data, nil)
+}
+
+// ResumeApplication restores a hibernated application
+func (c *Client) ResumeApplication(name string) error {
+	return c.http.POST("/api/applications/"+name+"/resume", nil, nil)
+}
+
 // GetResource retrieves details of a specific resource
 func (c *Client) GetResource(id string) (*ResourceInstance, error) {
 	var result ResourceInstance
@@ -317,6 +584,25 @@ func (c *Client) GetWorkflowDetail(workflowID string) (*WorkflowExecutionDetail,
 	return &result, nil
 }
 
+// QueueTask is the status of a queued workflow task
+type QueueTask struct {
+	ID           string `json:"id"`
+	AppName      string `json:"app_name"`
+	WorkflowName string `json:"workflow_name"`
+	Status       string `json:"status"`
+	ExecutionID  int64  `json:"execution_id,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// GetQueueTask retrieves the status of a queued workflow task
+func (c *Client) GetQueueTask(taskID string) (*QueueTask, error) {
+	var result QueueTask
+	if err := c.http.GET("/api/queue/tasks/"+taskID, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // GraphExportCommand exports the workflow graph for an application
 func (c *Client) GraphExportCommand(appName, format, outputFile string) error {
 	// Make request to graph export endpoint
@@ -344,7 +630,7 @@ func (c *Client) GraphExportCommand(appName, format, outputFile string) error {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+		return newAPIError(resp.StatusCode, body, fmt.Sprintf("server returned status %d", resp.StatusCode))
 	}
 
 	// Read response body
@@ -396,7 +682,7 @@ func (c *Client) GraphStatusCommand(appName string) error {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("server returned status %d: %s", resp.StatusCode, string(body))
+		return newAPIError(resp.StatusCode, body, fmt.Sprintf("server returned status %d", resp.StatusCode))
 	}
 
 	// Parse response
@@ -553,6 +839,20 @@ func (c *Client) GetTeam(teamID string) (*Team, error) {
 	return &team, nil
 }
 
+// GetTeamUsage retrieves the resources, running workflows, storage, quotas and daily
+// trend of a team
+func (c *Client) GetTeamUsage(teamID string, days int) (*usage.TeamUsage, error) {
+	path := fmt.Sprintf("/api/teams/%s/usage", url.PathEscape(teamID))
+	if days > 0 {
+		path += fmt.Sprintf("?days=%d", days)
+	}
+	var teamUsage usage.TeamUsage
+	if err := c.http.GET(path, &teamUsage); err != nil {
+		return nil, err
+	}
+	return &teamUsage, nil
+}
+
 // CreateTeam creates a new team
 func (c *Client) CreateTeam(name, description string) error {
 	data := map[string]string{
@@ -585,6 +885,71 @@ func (c *Client) GetProviderStats() (*ProviderStats, error) {
 	return &stats, nil
 }
 
+// providerPinsPath returns the provider-pins endpoint of an application or team
+func providerPinsPath(scope, name string) string {
+	if scope == "team" {
+		return "/api/teams/" + url.PathEscape(name) + "/provider-pins"
+	}
+	return "/api/applications/" + url.PathEscape(name) + "/provider-pins"
+}
+
+// GetProviderPins retrieves the provider version pins of a team, or those in effect for
+// an application
+func (c *Client) GetProviderPins(scope, name string) ([]ProviderVersionPin, error) {
+	var pins []ProviderVersionPin
+	if err := c.http.GET(providerPinsPath(scope, name), &pins); err != nil {
+		return nil, err
+	}
+	return pins, nil
+}
+
+// PinProviderVersion pins a provider version for an application or team
+func (c *Client) PinProviderVersion(scope, name, provider, version, reason string) (*ProviderVersionPin, error) {
+	data := map[string]string{"provider": provider, "version": version, "reason": reason}
+	var pin ProviderVersionPin
+	if err := c.http.PUT(providerPinsPath(scope, name), data, &pin); err != nil {
+		return nil, err
+	}
+	return &pin, nil
+}
+
+// UnpinProviderVersion removes the pin of a provider from an application or team
+func (c *Client) UnpinProviderVersion(scope, name, provider string) error {
+	return c.http.DELETE(providerPinsPath(scope, name) + "?provider=" + url.QueryEscape(provider))
+}
+
+// GetProviderUpgradeReport retrieves the pins visible to the user, optionally only
+// those affecting one application
+func (c *Client) GetProviderUpgradeReport(application string) (*ProviderUpgradeReport, error) {
+	path := "/api/providers/upgrade-report"
+	if application != "" {
+		path += "?application=" + url.QueryEscape(application)
+	}
+	var report ProviderUpgradeReport
+	if err := c.http.GET(path, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetProvider retrieves a provider with its compatibility and workflow parameters
+func (c *Client) GetProvider(name string) (*ProviderDetail, error) {
+	var provider ProviderDetail
+	if err := c.http.GET(fmt.Sprintf("/api/providers/%s", url.PathEscape(name)), &provider); err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+
+// GetProviderDocs retrieves the rendered documentation of a provider
+func (c *Client) GetProviderDocs(name string) (*ProviderDocs, error) {
+	var docs ProviderDocs
+	if err := c.http.GET(fmt.Sprintf("/api/providers/%s/docs", url.PathEscape(name)), &docs); err != nil {
+		return nil, err
+	}
+	return &docs, nil
+}
+
 // ReloadProviders triggers a reload of providers from admin-config.yaml
 func (c *Client) ReloadProviders() (map[string]interface{}, error) {
 	var response map[string]interface{}
@@ -594,6 +959,259 @@ func (c *Client) ReloadProviders() (map[string]interface{}, error) {
 	return response, nil
 }
 
+// Approval represents a workflow approval gate
+type Approval struct {
+	ID              int64     `json:"id"`
+	ExecutionID     int64     `json:"execution_id"`
+	StepName        string    `json:"step_name"`
+	ApplicationName string    `json:"application_name"`
+	Environment     string    `json:"environment,omitempty"`
+	Summary         string    `json:"summary,omitempty"`
+	Status          string    `json:"status"`
+	RequestedAt     time.Time `json:"requested_at"`
+	DecidedBy       *string   `json:"decided_by,omitempty"`
+	Comment         *string   `json:"comment,omitempty"`
+}
+
+// ListApprovals retrieves workflow approval gates, optionally filtered by status
+func (c *Client) ListApprovals(status string) ([]Approval, error) {
+	var response struct {
+		Approvals []Approval `json:"approvals"`
+	}
+	path := "/api/approvals"
+	if status != "" {
+		path += "?status=" + status
+	}
+	if err := c.http.GET(path, &response); err != nil {
+		return nil, err
+	}
+	return response.Approvals, nil
+}
+
+// DecideApproval approves or rejects a workflow approval gate (action: approve, reject)
+func (c *Client) DecideApproval(id, action, comment string) (*Approval, error) {
+	var approval Approval
+	data := map[string]string{"comment": comment}
+	if err := c.http.POST(fmt.Sprintf("/api/approvals/%s/%s", id, action), data, &approval); err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// Overview is the calling user's aggregated view returned by /api/me/overview
+type Overview struct {
+	Username string `json:"username"`
+	Team     string `json:"team"`
+	Counts   struct {
+		Applications     int `json:"applications"`
+		RunningWorkflows int `json:"running_workflows"`
+		FailingResources int `json:"failing_resources"`
+		PendingApprovals int `json:"pending_approvals"`
+		ExpiringAPIKeys  int `json:"expiring_api_keys"`
+	} `json:"counts"`
+	Applications []struct {
+		Name             string `json:"name"`
+		Resources        int    `json:"resources"`
+		FailingResources int    `json:"failing_resources"`
+	} `json:"applications"`
+	RunningWorkflows []struct {
+		ID              int64     `json:"id"`
+		ApplicationName string    `json:"application_name"`
+		WorkflowName    string    `json:"workflow_name"`
+		StartedAt       time.Time `json:"started_at"`
+		TotalSteps      int       `json:"total_steps"`
+		CompletedSteps  int       `json:"completed_steps"`
+	} `json:"running_workflows"`
+	FailingResources []struct {
+		Application  string `json:"application"`
+		Name         string `json:"name"`
+		Type         string `json:"type"`
+		State        string `json:"state"`
+		HealthStatus string `json:"health_status,omitempty"`
+		ErrorMessage string `json:"error_message,omitempty"`
+	} `json:"failing_resources"`
+	PendingApprovals []Approval `json:"pending_approvals"`
+	ExpiringAPIKeys  []struct {
+		Name      string    `json:"name"`
+		ExpiresAt time.Time `json:"expires_at"`
+		Expired   bool      `json:"expired"`
+		DaysLeft  int       `json:"days_left"`
+	} `json:"expiring_api_keys"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// GetOverview retrieves the calling user's applications, running workflows, failing
+// resources, pending approvals and expiring API keys
+func (c *Client) GetOverview() (*Overview, error) {
+	var overview Overview
+	if err := c.http.GET("/api/me/overview", &overview); err != nil {
+		return nil, err
+	}
+	return &overview, nil
+}
+
+// LintFinding is a violation of an organisation lint rule
+type LintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+// SpecValidation is the server's verdict on a Score spec: deployment checks and lint findings
+type SpecValidation struct {
+	Valid       bool     `json:"valid"`
+	Application string   `json:"application"`
+	Errors      []string `json:"errors"`
+	Warnings    []string `json:"warnings,omitempty"`
+	Lint        *struct {
+		Profile  string        `json:"profile"`
+		Findings []LintFinding `json:"findings"`
+		Errors   int           `json:"errors"`
+		Warnings int           `json:"warnings"`
+	} `json:"lint,omitempty"`
+}
+
+// ValidateSpec checks a Score spec against the server's deployment checks and a lint
+// profile; an empty profile selects the server's default
+func (c *Client) ValidateSpec(yamlContent []byte, profile string) (*SpecValidation, error) {
+	path := "/api/validate"
+	if profile != "" {
+		path += "?profile=" + url.QueryEscape(profile)
+	}
+	var result SpecValidation
+	if err := c.http.doYAMLRequest("POST", path, yamlContent, &result); err != nil {
+		return nil, fmt.Errorf("failed to validate spec: %w", err)
+	}
+	return &result, nil
+}
+
+// ApplicationPreview is what deploying a Score spec would change for an application
+type ApplicationPreview struct {
+	Application string `json:"application"`
+	Exists      bool   `json:"exists"`
+	Resources   []struct {
+		Name    string   `json:"name"`
+		Type    string   `json:"type"`
+		Action  string   `json:"action"` // create, update, delete or unchanged
+		State   string   `json:"state,omitempty"`
+		Changes []string `json:"changes,omitempty"`
+		Diff    string   `json:"diff,omitempty"`
+		Error   string   `json:"error,omitempty"`
+	} `json:"resources"`
+	Steps []struct {
+		Phase             string `json:"phase"`
+		Name              string `json:"name"`
+		Type              string `json:"type"`
+		EstimatedDuration string `json:"estimated_duration"`
+	} `json:"steps"`
+	EstimatedDuration string         `json:"estimated_duration"`
+	EstimatedSeconds  int            `json:"estimated_seconds"`
+	Summary           map[string]int `json:"summary"`
+	Warnings          []string       `json:"warnings,omitempty"`
+	DryRun            bool           `json:"dry_run,omitempty"`
+	GoldenPath        string         `json:"golden_path,omitempty"`
+	ApprovalRequired  bool           `json:"approval_required,omitempty"`
+}
+
+// PreviewApplication compares a Score spec with the deployed state of an application
+// without deploying it
+func (c *Client) PreviewApplication(name string, yamlContent []byte) (*ApplicationPreview, error) {
+	var result ApplicationPreview
+	path := "/api/applications/" + url.PathEscape(name) + "/preview"
+	if err := c.http.doYAMLRequest("POST", path, yamlContent, &result); err != nil {
+		return nil, fmt.Errorf("failed to preview application: %w", err)
+	}
+	return &result, nil
+}
+
+// DryRunGoldenPath previews running a golden path with a Score spec: the resources it
+// would change and the steps it would run. Nothing is run.
+func (c *Client) DryRunGoldenPath(pathName string, yamlContent []byte, params map[string]string) (*ApplicationPreview, error) {
+	query := url.Values{"dry_run": {"true"}}
+	for key, value := range params {
+		query.Set("param."+key, value)
+	}
+
+	var result ApplicationPreview
+	path := "/api/workflows/golden-paths/" + url.PathEscape(pathName) + "/execute?" + query.Encode()
+	if err := c.http.doYAMLRequest("POST", path, yamlContent, &result); err != nil {
+		return nil, fmt.Errorf("failed to dry-run golden path: %w", err)
+	}
+	return &result, nil
+}
+
+// LoadTestConfig describes a synthetic load test run
+type LoadTestConfig struct {
+	Applications    int    `json:"applications"`
+	WorkflowsPerApp int    `json:"workflows_per_app"`
+	Concurrency     int    `json:"concurrency"`
+	Steps           int    `json:"steps"`
+	StepDuration    string `json:"step_duration"`
+	Timeout         string `json:"timeout"`
+	Cleanup         bool   `json:"cleanup"`
+}
+
+// LoadTestLatency holds latency percentiles in milliseconds
+type LoadTestLatency struct {
+	Count  int     `json:"count"`
+	MinMs  float64 `json:"min_ms"`
+	MeanMs float64 `json:"mean_ms"`
+	P50Ms  float64 `json:"p50_ms"`
+	P90Ms  float64 `json:"p90_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// LoadTestReport is the progress and result of a load test run
+type LoadTestReport struct {
+	ID               string          `json:"id"`
+	Status           string          `json:"status"`
+	Error            string          `json:"error,omitempty"`
+	Config           LoadTestConfig  `json:"config"`
+	RequestedBy      string          `json:"requested_by"`
+	StartedAt        time.Time       `json:"started_at"`
+	CompletedAt      *time.Time      `json:"completed_at,omitempty"`
+	Total            int             `json:"total"`
+	Submitted        int             `json:"submitted"`
+	Rejected         int             `json:"rejected"`
+	Succeeded        int             `json:"succeeded"`
+	Failed           int             `json:"failed"`
+	ThroughputPerSec float64         `json:"throughput_per_sec"`
+	SubmitLatency    LoadTestLatency `json:"submit_latency"`
+	QueueWait        LoadTestLatency `json:"queue_wait"`
+	Execution        LoadTestLatency `json:"execution"`
+	EndToEnd         LoadTestLatency `json:"end_to_end"`
+	Queue            struct {
+		Samples               int     `json:"samples"`
+		Workers               int     `json:"workers"`
+		Capacity              int     `json:"capacity"`
+		MaxDepth              int     `json:"max_depth"`
+		AvgDepth              float64 `json:"avg_depth"`
+		PeakBufferUtilization float64 `json:"peak_buffer_utilization"`
+		SaturatedPercent      float64 `json:"saturated_percent"`
+	} `json:"queue"`
+}
+
+// StartLoadTest starts a synthetic load test on the server (admin only)
+func (c *Client) StartLoadTest(cfg LoadTestConfig) (*LoadTestReport, error) {
+	var report LoadTestReport
+	if err := c.http.POST("/api/admin/loadtest", cfg, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetLoadTest retrieves the progress or final report of a load test run
+func (c *Client) GetLoadTest(id string) (*LoadTestReport, error) {
+	var report LoadTestReport
+	if err := c.http.GET("/api/admin/loadtest/"+id, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
 // GetStats retrieves platform statistics (applications, workflows, resources, users)
 func (c *Client) GetStats() (*Stats, error) {
 	var stats Stats
@@ -612,6 +1230,45 @@ func (c *Client) GetGoldenPaths() (map[string]*GoldenPathInfo, error) {
 	return paths, nil
 }
 
+// ListGoldenPathApplications retrieves the applications deployed with a golden path
+func (c *Client) ListGoldenPathApplications(pathName string) ([]*GoldenPathApplication, error) {
+	var response struct {
+		Applications []*GoldenPathApplication `json:"applications"`
+	}
+	if err := c.http.GET("/api/golden-paths/"+url.PathEscape(pathName)+"/applications", &response); err != nil {
+		return nil, err
+	}
+	return response.Applications, nil
+}
+
+// MigrateGoldenPath re-runs applications of a deprecated golden path through its successor.
+// With dryRun the server only reports the parameters the successor would run with.
+func (c *Client) MigrateGoldenPath(pathName string, applications []string, team string, dryRun bool) (*GoldenPathMigrateResult, error) {
+	request := map[string]interface{}{
+		"applications": applications,
+		"team":         team,
+		"dry_run":      dryRun,
+	}
+	var result GoldenPathMigrateResult
+	if err := c.http.POST("/api/golden-paths/"+url.PathEscape(pathName)+"/migrate", request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetGoldenPathMigrations retrieves the migration progress away from a golden path
+func (c *Client) GetGoldenPathMigrations(pathName, team string) (*GoldenPathMigrationStatus, error) {
+	path := "/api/golden-paths/" + url.PathEscape(pathName) + "/migrations"
+	if team != "" {
+		path += "?team=" + url.QueryEscape(team)
+	}
+	var status GoldenPathMigrationStatus
+	if err := c.http.GET(path, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
 // GetProfile retrieves the current authenticated user's profile from the server
 func (c *Client) GetProfile() (*ProfileResponse, error) {
 	var profile ProfileResponse
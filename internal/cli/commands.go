@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -11,6 +12,9 @@ import (
 	"innominatus/internal/errors"
 	"innominatus/internal/goldenpaths"
 	"innominatus/internal/graph"
+	"innominatus/internal/logging"
+	"innominatus/internal/providers"
+	"innominatus/internal/providers/conformance"
 	"innominatus/internal/security"
 	"innominatus/internal/types"
 	"innominatus/internal/users"
@@ -18,6 +22,7 @@ import (
 	"innominatus/internal/workflow"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -158,7 +163,87 @@ func (c *Client) ListCommand(showDetails bool) error {
 	return nil
 }
 
+// StatusCommand shows the consolidated status of an application: latest deployment,
+// resources with their hints, active workflows, health, recent events and pending
+// approvals. Servers that cannot build it (e.g. without a database) get the spec view.
 func (c *Client) StatusCommand(name string) error {
+	status, err := c.GetApplicationStatus(name)
+	if err != nil {
+		return c.specStatus(name)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(status)
+	}
+	if c.Formatter.IsYAML() {
+		return c.Formatter.PrintYAML(status)
+	}
+
+	c.Formatter.PrintHeader(fmt.Sprintf("Application: %s", status.Name))
+	c.Formatter.PrintKeyValue(0, "Team", status.Team)
+	c.Formatter.PrintKeyValue(0, "Health", fmt.Sprintf("%s (%d/%d resources healthy, %d failing)",
+		status.Health.Status, status.Health.Healthy, status.Health.Resources, status.Health.FailingResources))
+	if deployment := status.LatestDeployment; deployment != nil {
+		line := fmt.Sprintf("%s by %s at %s", deployment.Status, deployment.DeployedBy, deployment.DeployedAt.Format(time.RFC3339))
+		if deployment.Workflow != nil {
+			line += fmt.Sprintf(" (workflow #%d %s)", deployment.Workflow.ID, deployment.Workflow.WorkflowName)
+		}
+		c.Formatter.PrintKeyValue(0, "Latest Deployment", line)
+	}
+
+	c.Formatter.PrintSubHeader(fmt.Sprintf("Resources (%d):", len(status.Resources)))
+	for _, resource := range status.Resources {
+		line := fmt.Sprintf("%s (%s): %s", resource.Name, resource.Type, resource.State)
+		if resource.HealthStatus != "" {
+			line += ", " + resource.HealthStatus
+		}
+		c.Formatter.PrintItem(1, SymbolResource, line)
+		if resource.ErrorMessage != "" {
+			c.Formatter.PrintItem(2, SymbolError, resource.ErrorMessage)
+		}
+		for _, hint := range resource.Hints {
+			c.Formatter.PrintKeyValue(2, hint.Label, hint.Value)
+			if hint.Query != "" {
+				c.Formatter.PrintKeyValue(3, "Query", hint.Query)
+			}
+		}
+	}
+
+	if len(status.ActiveWorkflows) > 0 {
+		c.Formatter.PrintSubHeader(fmt.Sprintf("Active Workflows (%d):", len(status.ActiveWorkflows)))
+		for _, wf := range status.ActiveWorkflows {
+			c.Formatter.PrintItem(1, SymbolRunning, fmt.Sprintf("#%d %s: %s (%d/%d steps)",
+				wf.ID, wf.WorkflowName, wf.Status, wf.CompletedSteps, wf.TotalSteps))
+		}
+	}
+
+	if len(status.PendingApprovals) > 0 {
+		c.Formatter.PrintSubHeader(fmt.Sprintf("Pending Approvals (%d):", len(status.PendingApprovals)))
+		for _, approval := range status.PendingApprovals {
+			c.Formatter.PrintItem(1, SymbolWarning, fmt.Sprintf("#%d %s (workflow #%d)", approval.ID, approval.StepName, approval.ExecutionID))
+		}
+	}
+
+	if len(status.RecentEvents) > 0 {
+		c.Formatter.PrintSubHeader("Recent Events:")
+		for _, event := range status.RecentEvents {
+			symbol := SymbolInfo
+			switch event.Severity {
+			case "error":
+				symbol = SymbolError
+			case "warning":
+				symbol = SymbolWarning
+			}
+			c.Formatter.PrintItem(1, symbol, fmt.Sprintf("%s [%s] %s: %s",
+				event.Time.Format(time.RFC3339), event.Type, event.Subject, event.Message))
+		}
+	}
+
+	return nil
+}
+
+// specStatus shows the deployed spec of an application
+func (c *Client) specStatus(name string) error {
 	spec, err := c.GetSpec(name)
 	if err != nil {
 		return err
@@ -231,16 +316,16 @@ func (c *Client) ValidateCommand(filename string, explain bool, format string) e
 	var spec types.ScoreSpec
 	err = yaml.Unmarshal(data, &spec)
 	if err != nil {
-		return fmt.Errorf("invalid YAML: %w", err)
+		return validationError("invalid YAML: %w", err)
 	}
 
 	// Basic validation
 	if spec.Metadata.Name == "" {
-		return fmt.Errorf("validation failed: metadata.name is required")
+		return validationError("validation failed: metadata.name is required")
 	}
 
 	if len(spec.Containers) == 0 {
-		return fmt.Errorf("validation failed: at least one container is required")
+		return validationError("validation failed: at least one container is required")
 	}
 
 	formatter := NewOutputFormatter()
@@ -303,9 +388,161 @@ func (c *Client) ValidateWithExplanation(filename string, format string) error {
 	}
 
 	if hasErrors {
-		return fmt.Errorf("validation failed with %d error(s)", len(validationErrors))
+		return validationError("validation failed with %d error(s)", len(validationErrors))
+	}
+
+	return nil
+}
+
+// LintSpecCommand validates a Score spec on the server, which also applies the
+// organisation's lint profile. It fails when the spec would be rejected on deploy.
+func (c *Client) LintSpecCommand(filename, profile, format string) error {
+	cleanPath, err := filepath.Abs(filename)
+	if err != nil {
+		return fmt.Errorf("invalid file path: %w", err)
+	}
+	if err := security.ValidateFilePath(cleanPath); err != nil {
+		return fmt.Errorf("invalid file path: %w", err)
+	}
+
+	data, err := os.ReadFile(cleanPath) // #nosec G304 - path validated above
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	result, err := c.ValidateSpec(data, profile)
+	if err != nil {
+		return err
+	}
+
+	formatter := NewOutputFormatter()
+	if format == "json" {
+		if err := formatter.PrintJSON(result); err != nil {
+			return err
+		}
+	} else {
+		printSpecValidation(formatter, result)
+	}
+
+	if !result.Valid {
+		return validationError("spec does not meet platform standards")
+	}
+	return nil
+}
+
+func printSpecValidation(formatter *OutputFormatter, result *SpecValidation) {
+	formatter.PrintEmpty()
+	if result.Lint != nil {
+		formatter.PrintHeader(fmt.Sprintf("Platform standards (profile: %s)", result.Lint.Profile))
+	} else {
+		formatter.PrintHeader("Platform standards (no lint profile configured)")
 	}
 
+	for _, problem := range result.Errors {
+		formatter.PrintItem(1, SymbolError, problem)
+	}
+	for _, warning := range result.Warnings {
+		formatter.PrintItem(1, SymbolWarning, warning)
+	}
+	if result.Lint != nil {
+		for _, finding := range result.Lint.Findings {
+			symbol := SymbolError
+			if finding.Severity == "warning" {
+				symbol = SymbolWarning
+			}
+			formatter.PrintItem(1, symbol, fmt.Sprintf("[%s] %s: %s", finding.Rule, finding.Path, finding.Message))
+		}
+	}
+
+	if result.Valid {
+		formatter.PrintSuccess("Spec meets platform standards")
+	}
+}
+
+// PreviewCommand shows what deploying a Score spec would change for an application:
+// the resources created, updated or deleted, the workflow steps that would run and
+// their estimated duration. Nothing is deployed.
+func (c *Client) PreviewCommand(appName, filename string) error {
+	cleanPath, err := filepath.Abs(filename)
+	if err != nil {
+		return fmt.Errorf("invalid file path: %w", err)
+	}
+	if err := security.ValidateFilePath(cleanPath); err != nil {
+		return fmt.Errorf("invalid file path: %w", err)
+	}
+
+	data, err := os.ReadFile(cleanPath) // #nosec G304 - path validated above
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	preview, err := c.PreviewApplication(appName, data)
+	if err != nil {
+		return err
+	}
+
+	return c.printApplicationPreview(preview)
+}
+
+// printApplicationPreview prints the changes and steps of a preview or dry run
+func (c *Client) printApplicationPreview(preview *ApplicationPreview) error {
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(preview)
+	}
+	if c.Formatter.IsYAML() {
+		return c.Formatter.PrintYAML(preview)
+	}
+
+	title := fmt.Sprintf("Preview: %s", preview.Application)
+	if preview.GoldenPath != "" {
+		title = fmt.Sprintf("Dry run: %s with golden path '%s'", preview.Application, preview.GoldenPath)
+	}
+	if !preview.Exists {
+		title += " (new application)"
+	}
+	c.Formatter.PrintHeader(title)
+
+	c.Formatter.PrintSubHeader(fmt.Sprintf("Resources: %d to create, %d to update, %d to delete, %d unchanged",
+		preview.Summary["create"], preview.Summary["update"], preview.Summary["delete"], preview.Summary["unchanged"]))
+	for _, resource := range preview.Resources {
+		symbol := SymbolBullet
+		switch resource.Action {
+		case "create":
+			symbol = "+"
+		case "update":
+			symbol = "~"
+		case "delete":
+			symbol = "-"
+		}
+		line := fmt.Sprintf("%s (%s): %s", resource.Name, resource.Type, resource.Action)
+		if len(resource.Changes) > 0 {
+			line += " [" + strings.Join(resource.Changes, ", ") + "]"
+		}
+		c.Formatter.PrintItem(1, symbol, line)
+		if resource.Error != "" {
+			c.Formatter.PrintItem(2, SymbolWarning, "plan failed: "+resource.Error)
+		}
+		for _, diffLine := range strings.Split(strings.TrimRight(resource.Diff, "\n"), "\n") {
+			if diffLine != "" {
+				fmt.Printf("      %s\n", diffLine)
+			}
+		}
+	}
+
+	c.Formatter.PrintSubHeader(fmt.Sprintf("Workflow steps (%d):", len(preview.Steps)))
+	for _, step := range preview.Steps {
+		c.Formatter.PrintItem(1, SymbolWorkflow, fmt.Sprintf("[%s] %s (%s) - %s", step.Phase, step.Name, step.Type, step.EstimatedDuration))
+	}
+	c.Formatter.PrintEmpty()
+	c.Formatter.PrintKeyValue(0, "Estimated duration", preview.EstimatedDuration)
+
+	if preview.ApprovalRequired {
+		c.Formatter.PrintInfo("The run would wait for plan approval before applying changes")
+	}
+
+	for _, warning := range preview.Warnings {
+		c.Formatter.PrintWarning(warning)
+	}
 	return nil
 }
 
@@ -333,6 +570,97 @@ func (c *Client) EnvironmentsCommand() error {
 	return nil
 }
 
+// ClustersCommand lists the clusters registered by the cluster-bootstrap golden path
+func (c *Client) ClustersCommand(environment string) error {
+	path := "/api/clusters"
+	if environment != "" {
+		path += "?environment=" + url.QueryEscape(environment)
+	}
+
+	var result struct {
+		Clusters []*database.Cluster `json:"clusters"`
+		Count    int                 `json:"count"`
+	}
+	if err := c.http.GET(path, &result); err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(result)
+	}
+
+	if len(result.Clusters) == 0 {
+		c.Formatter.PrintEmptyState("No registered clusters")
+		return nil
+	}
+
+	c.Formatter.PrintHeader(fmt.Sprintf("Registered Clusters (%d):", result.Count))
+	for _, cluster := range result.Clusters {
+		c.Formatter.PrintSection(1, SymbolEnv, fmt.Sprintf("%s (%s)", cluster.Name, cluster.Environment))
+		c.Formatter.PrintKeyValue(2, "API Server", cluster.APIServer)
+		c.Formatter.PrintKeyValue(2, "Status", cluster.Status)
+		c.Formatter.PrintKeyValue(2, "Components", strings.Join(cluster.Components, ", "))
+		c.Formatter.PrintKeyValue(2, "Registered", c.Formatter.FormatTime(cluster.RegisteredAt))
+	}
+
+	return nil
+}
+
+// OverviewCommand shows the calling user's applications and everything that needs attention
+func (c *Client) OverviewCommand() error {
+	overview, err := c.GetOverview()
+	if err != nil {
+		return fmt.Errorf("failed to get overview: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(overview)
+	}
+
+	c.Formatter.PrintHeader(fmt.Sprintf("Overview for %s (team %s)", overview.Username, overview.Team))
+
+	c.Formatter.PrintSection(1, SymbolApp, fmt.Sprintf("Applications (%d)", overview.Counts.Applications))
+	for _, app := range overview.Applications {
+		c.Formatter.PrintKeyValue(2, app.Name, fmt.Sprintf("%d resources, %d failing", app.Resources, app.FailingResources))
+	}
+
+	c.Formatter.PrintSection(1, SymbolRunning, fmt.Sprintf("Running Workflows (%d)", overview.Counts.RunningWorkflows))
+	for _, wf := range overview.RunningWorkflows {
+		c.Formatter.PrintItem(2, SymbolBullet, fmt.Sprintf("#%d %s/%s (%d/%d steps, started %s)",
+			wf.ID, wf.ApplicationName, wf.WorkflowName, wf.CompletedSteps, wf.TotalSteps, c.Formatter.FormatTime(wf.StartedAt)))
+	}
+
+	c.Formatter.PrintSection(1, SymbolResource, fmt.Sprintf("Failing Resources (%d)", overview.Counts.FailingResources))
+	for _, resource := range overview.FailingResources {
+		status := resource.State
+		if resource.HealthStatus != "" {
+			status += ", " + resource.HealthStatus
+		}
+		line := fmt.Sprintf("%s/%s (%s) %s", resource.Application, resource.Name, resource.Type, status)
+		if resource.ErrorMessage != "" {
+			line += ": " + resource.ErrorMessage
+		}
+		c.Formatter.PrintItem(2, SymbolError, line)
+	}
+
+	c.Formatter.PrintSection(1, SymbolWarning, fmt.Sprintf("Pending Approvals (%d)", overview.Counts.PendingApprovals))
+	for _, approval := range overview.PendingApprovals {
+		c.Formatter.PrintItem(2, SymbolBullet, fmt.Sprintf("#%d %s step %s (execution %d)",
+			approval.ID, approval.ApplicationName, approval.StepName, approval.ExecutionID))
+	}
+
+	c.Formatter.PrintSection(1, SymbolInfo, fmt.Sprintf("Expiring API Keys (%d)", overview.Counts.ExpiringAPIKeys))
+	for _, key := range overview.ExpiringAPIKeys {
+		when := fmt.Sprintf("expires in %d days", key.DaysLeft)
+		if key.Expired {
+			when = "expired"
+		}
+		c.Formatter.PrintItem(2, SymbolBullet, fmt.Sprintf("%s: %s (%s)", key.Name, when, c.Formatter.FormatTime(key.ExpiresAt)))
+	}
+
+	return nil
+}
+
 func (c *Client) DeleteCommand(name string) error {
 	formatter := NewOutputFormatter()
 	// Complete application deletion (infrastructure + database records)
@@ -358,6 +686,27 @@ func (c *Client) DeprovisionCommand(name string) error {
 	return nil
 }
 
+func (c *Client) HibernateCommand(name, reason string) error {
+	formatter := NewOutputFormatter()
+	if err := c.HibernateApplication(name, reason); err != nil {
+		return err
+	}
+
+	formatter.PrintSuccess(fmt.Sprintf("Hibernated application '%s'", name))
+	formatter.PrintInfo(fmt.Sprintf("Resume with: ./innominatus-ctl resume %s", name))
+	return nil
+}
+
+func (c *Client) ResumeCommand(name string) error {
+	formatter := NewOutputFormatter()
+	if err := c.ResumeApplication(name); err != nil {
+		return err
+	}
+
+	formatter.PrintSuccess(fmt.Sprintf("Resumed application '%s'", name))
+	return nil
+}
+
 func (c *Client) AdminCommand(args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("admin command requires a subcommand")
@@ -401,9 +750,11 @@ func (c *Client) AdminCommand(args []string) error {
 		return c.userGenerateKeyCommand(args[1:])
 	case "user-revoke-key":
 		return c.userRevokeKeyCommand(args[1:])
+	case "loadtest":
+		return c.loadTestCommand(args[1:])
 
 	default:
-		return fmt.Errorf("unknown admin subcommand '%s'. Available: show, add-user, list-users, delete-user, generate-api-key, list-api-keys, revoke-api-key, user-api-keys, user-generate-key, user-revoke-key", subcommand)
+		return fmt.Errorf("unknown admin subcommand '%s'. Available: show, add-user, list-users, delete-user, generate-api-key, list-api-keys, revoke-api-key, user-api-keys, user-generate-key, user-revoke-key, loadtest", subcommand)
 	}
 }
 
@@ -476,7 +827,7 @@ func (c *Client) deleteUserCommand(username string) error {
 
 func (c *Client) userAPIKeysCommand(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("username is required")
+		return usageError("username is required")
 	}
 	username := args[0]
 
@@ -576,10 +927,138 @@ func (c *Client) userRevokeKeyCommand(args []string) error {
 	return nil
 }
 
+func (c *Client) loadTestCommand(args []string) error {
+	fs := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	apps := fs.Int("apps", 10, "Number of synthetic applications to create")
+	workflows := fs.Int("workflows", 1, "Workflow executions per application")
+	concurrency := fs.Int("concurrency", 5, "Number of concurrent submitters")
+	steps := fs.Int("steps", 3, "Synthetic steps per workflow")
+	stepDuration := fs.String("step-duration", "100ms", "Simulated duration of each step")
+	timeout := fs.String("timeout", "10m", "Maximum time to wait for all executions")
+	cleanup := fs.Bool("cleanup", false, "Delete the generated applications when the run finishes")
+	noWait := fs.Bool("no-wait", false, "Return after starting the run instead of waiting for the report")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := c.StartLoadTest(LoadTestConfig{
+		Applications:    *apps,
+		WorkflowsPerApp: *workflows,
+		Concurrency:     *concurrency,
+		Steps:           *steps,
+		StepDuration:    *stepDuration,
+		Timeout:         *timeout,
+		Cleanup:         *cleanup,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start load test: %w", err)
+	}
+
+	if *noWait {
+		if c.Formatter.IsJSON() {
+			return c.Formatter.PrintJSON(report)
+		}
+		c.Formatter.PrintSuccess(fmt.Sprintf("Load test %s started (%d executions)", report.ID, report.Total))
+		c.Formatter.PrintInfo(fmt.Sprintf("Progress: GET /api/admin/loadtest/%s", report.ID))
+		return nil
+	}
+
+	if !c.Formatter.IsJSON() && !c.Formatter.IsYAML() {
+		c.Formatter.PrintInfo(fmt.Sprintf("Load test %s started: %d application(s) x %d workflow(s), concurrency %d",
+			report.ID, report.Config.Applications, report.Config.WorkflowsPerApp, report.Config.Concurrency))
+	}
+
+	id := report.ID
+	for report.Status == "running" {
+		time.Sleep(2 * time.Second)
+		report, err = c.GetLoadTest(id)
+		if err != nil {
+			return fmt.Errorf("failed to get load test %s: %w", id, err)
+		}
+		if !c.Formatter.IsJSON() && !c.Formatter.IsYAML() {
+			done := report.Succeeded + report.Failed
+			fmt.Printf("   %s %d/%d completed (%d failed, %d rejected)\n", SymbolRunning, done, report.Total, report.Failed, report.Rejected)
+		}
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(report)
+	}
+	if c.Formatter.IsYAML() {
+		return c.Formatter.PrintYAML(report)
+	}
+
+	c.printLoadTestReport(report)
+	if report.Status != "completed" {
+		return fmt.Errorf("load test %s %s", report.ID, report.Status)
+	}
+	return nil
+}
+
+func (c *Client) printLoadTestReport(report *LoadTestReport) {
+	c.Formatter.PrintEmpty()
+	c.Formatter.PrintHeader(fmt.Sprintf("Load Test %s", report.ID))
+	c.Formatter.PrintKeyValue(1, "Status", c.Formatter.PrintStatusBadge(report.Status))
+	if report.Error != "" {
+		c.Formatter.PrintKeyValue(1, "Error", report.Error)
+	}
+	if report.CompletedAt != nil {
+		c.Formatter.PrintKeyValue(1, "Duration", c.Formatter.FormatDuration(report.CompletedAt.Sub(report.StartedAt)))
+	}
+	c.Formatter.PrintKeyValue(1, "Executions", fmt.Sprintf("%d total, %d succeeded, %d failed, %d rejected",
+		report.Total, report.Succeeded, report.Failed, report.Rejected))
+	c.Formatter.PrintKeyValue(1, "Throughput", fmt.Sprintf("%.2f executions/s", report.ThroughputPerSec))
+
+	c.Formatter.PrintEmpty()
+	c.Formatter.PrintSection(0, SymbolWorkflow, "Latency (ms)")
+	columns := []TableColumn{
+		{Header: "PHASE", Width: 12},
+		{Header: "COUNT", Width: 6},
+		{Header: "P50", Width: 10},
+		{Header: "P90", Width: 10},
+		{Header: "P95", Width: 10},
+		{Header: "P99", Width: 10},
+		{Header: "MAX", Width: 10},
+	}
+	c.Formatter.PrintTableHeader(columns)
+	for _, row := range []struct {
+		name  string
+		stats LoadTestLatency
+	}{
+		{"submit", report.SubmitLatency},
+		{"queue wait", report.QueueWait},
+		{"execution", report.Execution},
+		{"end-to-end", report.EndToEnd},
+	} {
+		c.Formatter.PrintTableRow(columns, []string{
+			row.name,
+			strconv.Itoa(row.stats.Count),
+			fmt.Sprintf("%.2f", row.stats.P50Ms),
+			fmt.Sprintf("%.2f", row.stats.P90Ms),
+			fmt.Sprintf("%.2f", row.stats.P95Ms),
+			fmt.Sprintf("%.2f", row.stats.P99Ms),
+			fmt.Sprintf("%.2f", row.stats.MaxMs),
+		})
+	}
+
+	c.Formatter.PrintEmpty()
+	c.Formatter.PrintSection(0, SymbolResource, "Queue Saturation")
+	c.Formatter.PrintKeyValue(1, "Workers", report.Queue.Workers)
+	c.Formatter.PrintKeyValue(1, "Buffer capacity", report.Queue.Capacity)
+	c.Formatter.PrintKeyValue(1, "Max depth", fmt.Sprintf("%d (%.0f%% of buffer)", report.Queue.MaxDepth, report.Queue.PeakBufferUtilization*100))
+	c.Formatter.PrintKeyValue(1, "Avg depth", fmt.Sprintf("%.2f", report.Queue.AvgDepth))
+	c.Formatter.PrintKeyValue(1, "Saturated", fmt.Sprintf("%.1f%% of %d samples", report.Queue.SaturatedPercent, report.Queue.Samples))
+	if report.Queue.SaturatedPercent > 50 {
+		c.Formatter.PrintWarning("Workers were saturated for most of the run; consider raising the worker count")
+	}
+	c.Formatter.PrintEmpty()
+}
+
 // TeamCommand handles team management subcommands
 func (c *Client) TeamCommand(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("team command requires a subcommand (list|get|create|delete)")
+		return fmt.Errorf("team command requires a subcommand (list|get|create|delete|usage)")
 	}
 
 	subcommand := args[0]
@@ -599,6 +1078,8 @@ func (c *Client) TeamCommand(args []string) error {
 			return fmt.Errorf("team delete requires a team ID")
 		}
 		return c.deleteTeamCommand(args[1])
+	case "usage":
+		return c.teamUsageCommand(args[1:])
 	default:
 		return fmt.Errorf("unknown team subcommand: %s", subcommand)
 	}
@@ -633,6 +1114,97 @@ func (c *Client) listTeamsCommand() error {
 	return nil
 }
 
+// teamUsageCommand shows what a team uses: team usage [team-id] [--days N]. Without a
+// team ID, the team of the logged-in user is shown.
+func (c *Client) teamUsageCommand(args []string) error {
+	var teamID string
+	days := 0
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--days" && i+1 < len(args):
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				return usageError("--days must be a positive number")
+			}
+			days = n
+			i++
+		case strings.HasPrefix(args[i], "-"):
+			return usageError("unknown flag: %s", args[i])
+		case teamID == "":
+			teamID = args[i]
+		default:
+			return usageError("team usage takes at most one team ID")
+		}
+	}
+	if teamID == "" {
+		profile, err := c.GetProfile()
+		if err != nil {
+			return fmt.Errorf("failed to determine your team: %w", err)
+		}
+		teamID = profile.Team
+	}
+
+	teamUsage, err := c.GetTeamUsage(teamID, days)
+	if err != nil {
+		return fmt.Errorf("failed to get team usage: %w", err)
+	}
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(teamUsage)
+	}
+
+	c.Formatter.PrintHeader(fmt.Sprintf("Usage of team %s", teamUsage.Team))
+	if teamUsage.Organization != "" {
+		c.Formatter.PrintKeyValue(1, "Organization", teamUsage.Organization)
+	}
+
+	c.Formatter.PrintSection(1, SymbolInfo, "Quotas")
+	for _, quota := range teamUsage.Quotas {
+		limit := "unlimited"
+		if quota.Limit > 0 {
+			limit = fmt.Sprintf("%d (%d%%)", quota.Limit, quota.Used*100/quota.Limit)
+		}
+		c.Formatter.PrintKeyValue(2, quota.Name, fmt.Sprintf("%d of %s", quota.Used, limit))
+	}
+
+	c.Formatter.PrintSection(1, SymbolResource, fmt.Sprintf("Resources (%d)", teamUsage.Resources))
+	for _, resourceType := range teamUsage.ResourcesByType {
+		c.Formatter.PrintKeyValue(2, resourceType.Type, fmt.Sprintf("%d (%d active, %d failed)", resourceType.Total, resourceType.Active, resourceType.Failed))
+	}
+
+	c.Formatter.PrintSection(1, SymbolRunning, fmt.Sprintf("Running Workflows (%d)", len(teamUsage.ActiveWorkflows)))
+	for _, wf := range teamUsage.ActiveWorkflows {
+		c.Formatter.PrintItem(2, SymbolBullet, fmt.Sprintf("#%d %s/%s (%d/%d steps, started %s)",
+			wf.ID, wf.ApplicationName, wf.WorkflowName, wf.CompletedSteps, wf.TotalSteps, c.Formatter.FormatTime(wf.StartedAt)))
+	}
+
+	c.Formatter.PrintSection(1, SymbolInfo, fmt.Sprintf("Storage (%s)", formatByteSize(teamUsage.Storage.TotalBytes)))
+	c.Formatter.PrintKeyValue(2, "Workflow logs", formatByteSize(teamUsage.Storage.LogsBytes))
+	c.Formatter.PrintKeyValue(2, "Workspaces", formatByteSize(teamUsage.Storage.WorkspaceBytes))
+	c.Formatter.PrintKeyValue(2, "Uploaded files", formatByteSize(teamUsage.Storage.FilesBytes))
+
+	c.Formatter.PrintSection(1, SymbolInfo, fmt.Sprintf("Last %d days", len(teamUsage.Trend)))
+	for _, point := range teamUsage.Trend {
+		c.Formatter.PrintKeyValue(2, point.Date, fmt.Sprintf("%d active resources, %.1f resource hours, %d workflow runs (%d failed)",
+			point.ActiveResources, point.ResourceHours, point.WorkflowRuns, point.FailedWorkflowRuns))
+	}
+
+	return nil
+}
+
+// formatByteSize formats a byte count with a binary unit, e.g. 1.5 MiB
+func formatByteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
 // getTeamCommand gets detailed team information
 func (c *Client) getTeamCommand(teamID string) error {
 	team, err := c.GetTeam(teamID)
@@ -669,7 +1241,7 @@ func (c *Client) createTeamCommand(args []string) error {
 	}
 
 	if *name == "" {
-		return fmt.Errorf("--name is required")
+		return usageError("--name is required")
 	}
 
 	if err := c.CreateTeam(*name, *description); err != nil {
@@ -753,6 +1325,18 @@ func (c *Client) ListGoldenPathsCommand() error {
 			c.Formatter.PrintKeyValue(1, "Tags", strings.Join(metadata.Tags, ", "))
 		}
 
+		// Deprecation
+		if d := metadata.Deprecated; d != nil {
+			deprecated := "yes"
+			if d.Successor != "" {
+				deprecated = fmt.Sprintf("use '%s' instead", d.Successor)
+			}
+			if d.Sunset != "" {
+				deprecated += fmt.Sprintf(" (removed after %s)", d.Sunset)
+			}
+			c.Formatter.PrintKeyValue(1, "Deprecated", deprecated)
+		}
+
 		// Required parameters (backward compatibility)
 		if len(metadata.RequiredParams) > 0 {
 			c.Formatter.PrintSection(1, "", "Required Parameters:")
@@ -780,6 +1364,28 @@ func (c *Client) ListGoldenPathsCommand() error {
 
 // RunGoldenPathCommand executes a golden path workflow with parameter overrides
 func (c *Client) RunGoldenPathCommand(pathName string, scoreFile string, params map[string]string) error {
+	return c.runGoldenPath(pathName, scoreFile, params, false, false)
+}
+
+// RunGoldenPathDryRunCommand shows the resources a golden path run would create, update
+// or delete and the steps it would run, without running anything
+func (c *Client) RunGoldenPathDryRunCommand(pathName string, scoreFile string, params map[string]string) error {
+	if scoreFile == "" {
+		return fmt.Errorf("dry run requires a Score spec")
+	}
+	return c.runGoldenPath(pathName, scoreFile, params, false, true)
+}
+
+// RunGoldenPathTestCommand executes a golden path against a temporary sandbox
+// (namespace, Gitea organization, mock DNS zone) that the server tears down afterwards
+func (c *Client) RunGoldenPathTestCommand(pathName string, scoreFile string, params map[string]string) error {
+	if scoreFile == "" {
+		return fmt.Errorf("test mode requires a Score spec")
+	}
+	return c.runGoldenPath(pathName, scoreFile, params, true, false)
+}
+
+func (c *Client) runGoldenPath(pathName string, scoreFile string, params map[string]string, test, dryRun bool) error {
 	formatter := NewOutputFormatter()
 
 	// Load golden paths configuration
@@ -799,6 +1405,10 @@ func (c *Client) RunGoldenPathCommand(pathName string, scoreFile string, params
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	if deprecation := config.GetDeprecation(pathName); deprecation != nil {
+		formatter.PrintWarning(deprecation.Warning(pathName))
+	}
+
 	// Validate required parameters
 	if err := config.ValidateParameters(pathName, params); err != nil {
 		// Check if it's a parameter validation error for better messaging
@@ -828,7 +1438,11 @@ func (c *Client) RunGoldenPathCommand(pathName string, scoreFile string, params
 		return fmt.Errorf("failed to merge parameters: %w", err)
 	}
 
-	formatter.PrintInfo(fmt.Sprintf("Running golden path '%s' with workflow: %s", pathName, metadata.WorkflowFile))
+	if dryRun {
+		formatter.PrintInfo(fmt.Sprintf("Dry run of golden path '%s' with workflow: %s", pathName, metadata.WorkflowFile))
+	} else {
+		formatter.PrintInfo(fmt.Sprintf("Running golden path '%s' with workflow: %s", pathName, metadata.WorkflowFile))
+	}
 
 	// Show active parameters if any
 	if len(finalParams) > 0 {
@@ -839,6 +1453,7 @@ func (c *Client) RunGoldenPathCommand(pathName string, scoreFile string, params
 	}
 
 	// Load and parse the Score spec if provided
+	var scoreData []byte
 	if scoreFile != "" {
 		formatter.PrintInfo(fmt.Sprintf("Using Score spec: %s", scoreFile))
 		// Validate file path
@@ -850,7 +1465,7 @@ func (c *Client) RunGoldenPathCommand(pathName string, scoreFile string, params
 			return fmt.Errorf("invalid file path: %w", err)
 		}
 
-		scoreData, err := os.ReadFile(cleanPath) // #nosec G304 - path validated above
+		scoreData, err = os.ReadFile(cleanPath) // #nosec G304 - path validated above
 		if err != nil {
 			return fmt.Errorf("failed to read Score spec %s: %w", scoreFile, err)
 		}
@@ -864,18 +1479,224 @@ func (c *Client) RunGoldenPathCommand(pathName string, scoreFile string, params
 		formatter.PrintSuccess(fmt.Sprintf("Loaded Score spec for application: %s", spec.Metadata.Name))
 	}
 
+	if test {
+		formatter.PrintInfo("Test mode: running in a sandbox that is torn down afterwards")
+	}
+
+	if dryRun {
+		workflowName := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(metadata.WorkflowFile), ".yaml"), ".yml")
+		preview, err := c.DryRunGoldenPath(workflowName, scoreData, finalParams)
+		if err != nil {
+			return err
+		}
+		return c.printApplicationPreview(preview)
+	}
+
 	// Execute the workflow using the existing RunWorkflow function with golden path parameters
-	err = c.runWorkflow(metadata.WorkflowFile, scoreFile, finalParams)
+	status, err := c.runWorkflow(metadata.WorkflowFile, scoreFile, finalParams, test)
 	if err != nil {
 		return fmt.Errorf("failed to execute golden path workflow: %w", err)
 	}
 
-	formatter.PrintSuccess(fmt.Sprintf("Golden path '%s' completed successfully", pathName))
+	if test {
+		formatter.PrintSuccess(fmt.Sprintf("Golden path '%s' passed in sandbox", pathName))
+		return nil
+	}
+	if status == "completed" {
+		formatter.PrintSuccess(fmt.Sprintf("Golden path '%s' completed successfully", pathName))
+	}
+	return nil
+}
+
+// GoldenPathApplicationsCommand lists the applications deployed with a golden path
+func (c *Client) GoldenPathApplicationsCommand(pathName string) error {
+	applications, err := c.ListGoldenPathApplications(pathName)
+	if err != nil {
+		return fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(applications)
+	}
+	if c.Formatter.IsYAML() {
+		return c.Formatter.PrintYAML(applications)
+	}
+
+	if len(applications) == 0 {
+		c.Formatter.PrintEmptyState(fmt.Sprintf("No applications are deployed with golden path '%s'", pathName))
+		return nil
+	}
+
+	c.Formatter.PrintHeader(fmt.Sprintf("Applications deployed with '%s' (%d):", pathName, len(applications)))
+	columns := []TableColumn{
+		{Header: "APPLICATION", Width: 30},
+		{Header: "TEAM", Width: 20},
+		{Header: "EXECUTION", Width: 10},
+		{Header: "DEPLOYED", Width: 20},
+	}
+	c.Formatter.PrintTableHeader(columns)
+	for _, app := range applications {
+		c.Formatter.PrintTableRow(columns, []string{
+			app.ApplicationName,
+			app.Team,
+			strconv.FormatInt(app.ExecutionID, 10),
+			c.Formatter.FormatTime(app.DeployedAt),
+		})
+	}
+	return nil
+}
+
+// MigrateGoldenPathCommand re-runs the applications of a deprecated golden path through
+// its successor, or with dryRun shows the parameters they would run with
+func (c *Client) MigrateGoldenPathCommand(pathName string, applications []string, team string, dryRun bool) error {
+	result, err := c.MigrateGoldenPath(pathName, applications, team, dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to migrate golden path: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(result)
+	}
+	if c.Formatter.IsYAML() {
+		return c.Formatter.PrintYAML(result)
+	}
+
+	if len(result.Applications) == 0 {
+		c.Formatter.PrintEmptyState(fmt.Sprintf("No applications to migrate from '%s'", pathName))
+		return nil
+	}
+
+	action := "Migrating"
+	if result.DryRun {
+		action = "Dry run: migrating"
+	}
+	c.Formatter.PrintHeader(fmt.Sprintf("%s %d application(s) from '%s' to '%s'", action, len(result.Applications), result.GoldenPath, result.Successor))
+
+	failed := 0
+	for _, plan := range result.Applications {
+		c.Formatter.PrintEmpty()
+		c.Formatter.PrintSection(0, SymbolWorkflow, fmt.Sprintf("%s (%s)", plan.ApplicationName, plan.Team))
+		if plan.MigrationID != 0 {
+			c.Formatter.PrintKeyValue(1, "Migration", plan.MigrationID)
+		}
+		names := make([]string, 0, len(plan.Parameters))
+		for name := range plan.Parameters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			c.Formatter.PrintKeyValue(1, name, plan.Parameters[name])
+		}
+		if plan.Error != "" {
+			failed++
+			c.Formatter.PrintError(plan.Error)
+		}
+	}
+
+	c.Formatter.PrintEmpty()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d application(s) cannot be migrated", failed, len(result.Applications))
+	}
+	if !result.DryRun {
+		c.Formatter.PrintInfo(fmt.Sprintf("Follow progress: ./innominatus-ctl golden-path migrations %s", pathName))
+	}
+	return nil
+}
+
+// GoldenPathMigrationsCommand shows the migration progress away from a golden path per team
+func (c *Client) GoldenPathMigrationsCommand(pathName, team string) error {
+	status, err := c.GetGoldenPathMigrations(pathName, team)
+	if err != nil {
+		return fmt.Errorf("failed to get migrations: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(status)
+	}
+	if c.Formatter.IsYAML() {
+		return c.Formatter.PrintYAML(status)
+	}
+
+	if len(status.Teams) == 0 {
+		c.Formatter.PrintEmptyState(fmt.Sprintf("No applications use or migrated from '%s'", pathName))
+		return nil
+	}
+
+	header := fmt.Sprintf("Migration from '%s'", status.GoldenPath)
+	if status.Successor != "" {
+		header += fmt.Sprintf(" to '%s'", status.Successor)
+	}
+	c.Formatter.PrintHeader(header)
+	columns := []TableColumn{
+		{Header: "TEAM", Width: 20},
+		{Header: "REMAINING", Width: 10},
+		{Header: "PENDING", Width: 8},
+		{Header: "RUNNING", Width: 8},
+		{Header: "SUCCEEDED", Width: 10},
+		{Header: "FAILED", Width: 8},
+	}
+	c.Formatter.PrintTableHeader(columns)
+	for _, t := range status.Teams {
+		c.Formatter.PrintTableRow(columns, []string{
+			t.Team,
+			strconv.Itoa(t.Remaining),
+			strconv.Itoa(t.Pending),
+			strconv.Itoa(t.Running),
+			strconv.Itoa(t.Succeeded),
+			strconv.Itoa(t.Failed),
+		})
+	}
+
+	if len(status.Migrations) > 0 {
+		c.Formatter.PrintEmpty()
+		c.Formatter.PrintSection(0, SymbolWorkflow, "Migrations")
+		for _, m := range status.Migrations {
+			line := fmt.Sprintf("#%d %s (%s): %s", m.ID, m.ApplicationName, m.Team, c.Formatter.PrintStatusBadge(m.Status))
+			if m.ExecutionID != nil {
+				line += fmt.Sprintf(", workflow %d", *m.ExecutionID)
+			}
+			c.Formatter.PrintItem(1, SymbolBullet, line)
+			if m.ErrorMessage != nil {
+				c.Formatter.PrintKeyValue(2, "Error", *m.ErrorMessage)
+			}
+		}
+	}
 	return nil
 }
 
-// runWorkflow executes a workflow via the server API with real resource provisioning
-func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters map[string]string) error {
+// preflightFailure prints the pre-flight results of a rejected golden path run and returns
+// the summary as error
+func preflightFailure(body []byte) error {
+	var response struct {
+		Error     string `json:"error"`
+		Preflight struct {
+			Results []struct {
+				Name    string `json:"name"`
+				Passed  bool   `json:"passed"`
+				Message string `json:"message"`
+			} `json:"results"`
+		} `json:"preflight"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || response.Error == "" {
+		return newAPIError(http.StatusUnprocessableEntity, body, fmt.Sprintf("workflow execution failed (status %d)", http.StatusUnprocessableEntity))
+	}
+
+	formatter := NewOutputFormatter()
+	formatter.PrintHeader("Pre-flight checks")
+	for _, result := range response.Preflight.Results {
+		if result.Passed {
+			formatter.PrintSuccess(fmt.Sprintf("%s: %s", result.Name, result.Message))
+		} else {
+			formatter.PrintError(fmt.Sprintf("%s: %s", result.Name, result.Message))
+		}
+	}
+	return &APIError{StatusCode: http.StatusUnprocessableEntity, Message: response.Error, summary: "pre-flight checks failed"}
+}
+
+// runWorkflow executes a workflow via the server API with real resource provisioning,
+// or inside a server-managed sandbox when test is set. A run the server starts in the
+// background is followed until it finishes. It returns the final status of the run.
+func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters map[string]string, test bool) (string, error) {
 	formatter := NewOutputFormatter()
 
 	// Extract workflow name from file path
@@ -892,33 +1713,36 @@ func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters m
 		// Validate file path
 		cleanPath, err := filepath.Abs(scoreFile)
 		if err != nil {
-			return fmt.Errorf("invalid file path: %w", err)
+			return "", fmt.Errorf("invalid file path: %w", err)
 		}
 		if err := security.ValidateFilePath(cleanPath); err != nil {
-			return fmt.Errorf("invalid file path: %w", err)
+			return "", fmt.Errorf("invalid file path: %w", err)
 		}
 
 		scoreData, err = os.ReadFile(cleanPath) // #nosec G304 - path validated above
 		if err != nil {
-			return fmt.Errorf("failed to read Score file: %w", err)
+			return "", fmt.Errorf("failed to read Score file: %w", err)
 		}
 		formatter.PrintSuccess(fmt.Sprintf("Loaded Score specification: %s", scoreFile))
 	}
 
 	// Ensure we have authentication
 	if c.token == "" {
-		return fmt.Errorf("authentication required: please login first with './innominatus-ctl login'")
+		return "", fmt.Errorf("authentication required: please login first with './innominatus-ctl login'")
 	}
 
 	// Make API request to server for golden path execution
 	url := fmt.Sprintf("%s/api/workflows/golden-paths/%s/execute", c.baseURL, workflowName)
 
 	// Add golden path parameters as query parameters
-	if len(parameters) > 0 {
-		queryParams := make([]string, 0, len(parameters))
-		for key, value := range parameters {
-			queryParams = append(queryParams, fmt.Sprintf("param.%s=%s", key, value))
-		}
+	queryParams := make([]string, 0, len(parameters)+1)
+	for key, value := range parameters {
+		queryParams = append(queryParams, fmt.Sprintf("param.%s=%s", key, value))
+	}
+	if test {
+		queryParams = append(queryParams, "test=true")
+	}
+	if len(queryParams) > 0 {
 		url = url + "?" + strings.Join(queryParams, "&")
 	}
 
@@ -926,13 +1750,13 @@ func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters m
 	if scoreData != nil {
 		req, err = http.NewRequest("POST", url, bytes.NewBuffer(scoreData))
 		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
+			return "", fmt.Errorf("failed to create request: %w", err)
 		}
 		req.Header.Set("Content-Type", "application/yaml")
 	} else {
 		req, err = http.NewRequest("POST", url, nil)
 		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
+			return "", fmt.Errorf("failed to create request: %w", err)
 		}
 	}
 
@@ -943,6 +1767,11 @@ func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters m
 	var resp *http.Response
 	var body []byte
 	client := &http.Client{Timeout: 30 * time.Second}
+	if test {
+		// A test run provisions and tears down a whole sandbox; a retry would start another one
+		maxRetries = 0
+		client.Timeout = 30 * time.Minute
+	}
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
@@ -964,13 +1793,13 @@ func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters m
 			if scoreData != nil {
 				req, err = http.NewRequest("POST", retryURL, bytes.NewBuffer(scoreData))
 				if err != nil {
-					return fmt.Errorf("failed to create retry request: %w", err)
+					return "", fmt.Errorf("failed to create retry request: %w", err)
 				}
 				req.Header.Set("Content-Type", "application/yaml")
 			} else {
 				req, err = http.NewRequest("POST", retryURL, nil)
 				if err != nil {
-					return fmt.Errorf("failed to create retry request: %w", err)
+					return "", fmt.Errorf("failed to create retry request: %w", err)
 				}
 			}
 			req.Header.Set("Authorization", "Bearer "+c.token)
@@ -979,7 +1808,7 @@ func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters m
 		resp, err = client.Do(req)
 		if err != nil {
 			if attempt == maxRetries {
-				return fmt.Errorf("failed to execute workflow after %d retries: %w", maxRetries+1, err)
+				return "", fmt.Errorf("failed to execute workflow after %d retries: %w", maxRetries+1, err)
 			}
 			formatter.PrintWarning(fmt.Sprintf("Request failed: %v", err))
 			continue
@@ -989,23 +1818,31 @@ func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters m
 		_ = resp.Body.Close()
 		if err != nil {
 			if attempt == maxRetries {
-				return fmt.Errorf("failed to read response after %d retries: %w", maxRetries+1, err)
+				return "", fmt.Errorf("failed to read response after %d retries: %w", maxRetries+1, err)
 			}
 			formatter.PrintWarning(fmt.Sprintf("Failed to read response: %v", err))
 			continue
 		}
 
+		// A failed test run reports its sandbox details as JSON
+		if test && resp.StatusCode == http.StatusInternalServerError && len(body) > 0 && body[0] == '{' {
+			break
+		}
+
 		// Check for transient errors (5xx) or JSON parsing issues
 		if resp.StatusCode >= 500 {
 			if attempt == maxRetries {
-				return fmt.Errorf("workflow execution failed (status %d) after %d retries: %s", resp.StatusCode, maxRetries+1, string(body))
+				return "", newAPIError(resp.StatusCode, body, fmt.Sprintf("workflow execution failed (status %d) after %d retries", resp.StatusCode, maxRetries+1))
 			}
 			formatter.PrintWarning(fmt.Sprintf("Server error (status %d), will retry", resp.StatusCode))
 			continue
 		}
 
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("workflow execution failed (status %d): %s", resp.StatusCode, string(body))
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			return "", preflightFailure(body)
+		}
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+			return "", newAPIError(resp.StatusCode, body, fmt.Sprintf("workflow execution failed (status %d)", resp.StatusCode))
 		}
 
 		// Success - break out of retry loop
@@ -1021,7 +1858,7 @@ func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters m
 		if len(truncated) > 500 {
 			truncated = truncated[:500] + "..."
 		}
-		return fmt.Errorf("server returned HTML instead of JSON (possible gateway/server error):\n%s", truncated)
+		return "", fmt.Errorf("server returned HTML instead of JSON (possible gateway/server error):\n%s", truncated)
 	}
 
 	err = json.Unmarshal(body, &response)
@@ -1030,20 +1867,30 @@ func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters m
 		if len(preview) > 200 {
 			preview = preview[:200] + "..."
 		}
-		return fmt.Errorf("failed to parse response: %w\nReceived: %s", err, preview)
+		return "", fmt.Errorf("failed to parse response: %w\nReceived: %s", err, preview)
 	}
 
 	// Display execution results
 	if message, ok := response["message"].(string); ok {
-		formatter.PrintSuccess(message)
+		if status, _ := response["status"].(string); status == "failed" {
+			formatter.PrintError(message)
+		} else {
+			formatter.PrintSuccess(message)
+		}
 	}
 
-	if appName, ok := response["app_name"].(string); ok {
+	if appName, ok := response["application"].(string); ok {
 		formatter.PrintKeyValue(1, "Application", appName)
 	}
 
-	if workflowID, ok := response["workflow_id"].(float64); ok {
-		formatter.PrintKeyValue(1, "Workflow ID", fmt.Sprintf("%.0f", workflowID))
+	executionID, hasExecution := response["execution_id"].(float64)
+	if hasExecution {
+		formatter.PrintKeyValue(1, "Execution ID", fmt.Sprintf("%.0f", executionID))
+	}
+
+	taskID, _ := response["task_id"].(string)
+	if taskID != "" {
+		formatter.PrintKeyValue(1, "Task ID", taskID)
 	}
 
 	if resourcesCreated, ok := response["resources_created"].(float64); ok && resourcesCreated > 0 {
@@ -1054,7 +1901,134 @@ func (c *Client) runWorkflow(workflowFile string, scoreFile string, parameters m
 		formatter.PrintKeyValue(1, "Resources provisioned", fmt.Sprintf("%.0f", resourcesProvisioned))
 	}
 
+	if test {
+		return "completed", printSandboxResult(formatter, response)
+	}
+
+	status, _ := response["status"].(string)
+	switch {
+	case status == "awaiting_approval" && hasExecution:
+		fmt.Printf("   Follow with: innominatus-ctl workflow logs %.0f\n", executionID)
+		return status, nil
+	case hasExecution:
+		return c.waitForWorkflow(int64(executionID))
+	case status == "enqueued" && taskID != "":
+		return c.waitForQueueTask(taskID)
+	case status != "completed":
+		return status, nil
+	}
+
 	formatter.PrintSuccess("Golden path workflow execution completed with resource provisioning")
+	return status, nil
+}
+
+// waitForQueueTask polls a queued task until a worker starts its workflow execution,
+// then follows that execution
+func (c *Client) waitForQueueTask(taskID string) (string, error) {
+	formatter := NewOutputFormatter()
+	formatter.PrintInfo(fmt.Sprintf("%s Waiting for a queue worker...", SymbolRunning))
+
+	for {
+		task, err := c.GetQueueTask(taskID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get queue task %s: %w", taskID, err)
+		}
+
+		switch {
+		case task.ExecutionID != 0:
+			formatter.PrintKeyValue(1, "Execution ID", task.ExecutionID)
+			return c.waitForWorkflow(task.ExecutionID)
+		case task.Status == "failed":
+			return task.Status, fmt.Errorf("queue task %s failed: %s", taskID, task.Error)
+		case task.Status == "completed":
+			formatter.PrintSuccess("Golden path workflow execution completed with resource provisioning")
+			return task.Status, nil
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// waitForWorkflow polls a workflow execution until it finishes, printing each step
+// as its status changes. It returns an error if the execution failed.
+func (c *Client) waitForWorkflow(executionID int64) (string, error) {
+	formatter := NewOutputFormatter()
+	id := strconv.FormatInt(executionID, 10)
+	reported := make(map[int]string)
+
+	for {
+		detail, err := c.GetWorkflowDetail(id)
+		if err != nil {
+			return "", fmt.Errorf("failed to get workflow execution %d: %w", executionID, err)
+		}
+
+		for _, step := range detail.Steps {
+			if step.Status == "pending" || reported[step.StepNumber] == step.Status {
+				continue
+			}
+			reported[step.StepNumber] = step.Status
+			symbol := SymbolRunning
+			switch step.Status {
+			case "completed":
+				symbol = SymbolSuccess
+			case "failed":
+				symbol = SymbolError
+			}
+			formatter.PrintItem(1, symbol, fmt.Sprintf("Step %d/%d %s: %s", step.StepNumber, detail.TotalSteps, step.StepName, step.Status))
+		}
+
+		switch detail.Status {
+		case "completed":
+			formatter.PrintSuccess("Golden path workflow execution completed with resource provisioning")
+			return detail.Status, nil
+		case "failed":
+			message := "unknown error"
+			if detail.ErrorMessage != nil {
+				message = *detail.ErrorMessage
+			}
+			return detail.Status, fmt.Errorf("workflow execution %d failed: %s (see: innominatus-ctl workflow logs %d)", executionID, message, executionID)
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// printSandboxResult prints the sandbox and teardown details of a golden path test run
+func printSandboxResult(formatter *OutputFormatter, response map[string]interface{}) error {
+	if sandbox, ok := response["sandbox"].(map[string]interface{}); ok {
+		formatter.PrintSection(0, "", "Sandbox:")
+		for _, field := range []struct{ key, label string }{
+			{"id", "ID"},
+			{"app_name", "Application"},
+			{"namespace", "Namespace"},
+			{"gitea_org", "Gitea organization"},
+			{"domain", "DNS zone (mock)"},
+		} {
+			if value, ok := sandbox[field.key].(string); ok {
+				formatter.PrintKeyValue(1, field.label, value)
+			}
+		}
+	}
+
+	if durationMs, ok := response["duration_ms"].(float64); ok {
+		formatter.PrintKeyValue(1, "Duration", (time.Duration(durationMs) * time.Millisecond).String())
+	}
+
+	if tornDown, _ := response["torn_down"].(bool); tornDown {
+		formatter.PrintSuccess("Sandbox torn down")
+	} else {
+		formatter.PrintWarning("Sandbox teardown incomplete, clean up manually:")
+		if errs, ok := response["teardown_errors"].([]interface{}); ok {
+			for _, e := range errs {
+				formatter.PrintKeyValue(1, "Error", fmt.Sprint(e))
+			}
+		}
+	}
+
+	if status, _ := response["status"].(string); status == "failed" {
+		errMsg, _ := response["error"].(string)
+		return fmt.Errorf("golden path test failed: %s", errMsg)
+	}
 	return nil
 }
 
@@ -1775,15 +2749,15 @@ func (c *Client) generateAPIKeyCommand(args []string) error {
 	}
 
 	if *username == "" {
-		return fmt.Errorf("--username is required")
+		return usageError("--username is required")
 	}
 
 	if *keyName == "" {
-		return fmt.Errorf("API key name is required")
+		return usageError("API key name is required")
 	}
 
 	if *expiryDays <= 0 {
-		return fmt.Errorf("expiry-days is required and must be greater than 0")
+		return usageError("expiry-days is required and must be greater than 0")
 	}
 
 	store, err := users.LoadUsers()
@@ -1818,7 +2792,7 @@ func (c *Client) listAPIKeysCommand(args []string) error {
 	}
 
 	if *username == "" {
-		return fmt.Errorf("--username is required")
+		return usageError("--username is required")
 	}
 
 	store, err := users.LoadUsers()
@@ -1870,11 +2844,11 @@ func (c *Client) revokeAPIKeyCommand(args []string) error {
 	}
 
 	if *username == "" {
-		return fmt.Errorf("--username is required")
+		return usageError("--username is required")
 	}
 
 	if *keyName == "" {
-		return fmt.Errorf("API key name is required")
+		return usageError("API key name is required")
 	}
 
 	store, err := users.LoadUsers()
@@ -1897,9 +2871,13 @@ func (c *Client) LoginCommand(args []string) error {
 	keyName := fs.String("name", "", "Name for the API key (default: cli-<hostname>-<timestamp>)")
 	expiryDays := fs.Int("expiry-days", 90, "Number of days until API key expiry")
 
+	store := fs.String("store", "", "Where to store the credentials: keychain or file (default: keychain if available)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if err := ValidateCredentialStore(*store); err != nil {
+		return usageError("%v", err)
+	}
 
 	// Prompt for username and password
 	user, err := users.PromptLogin()
@@ -1925,49 +2903,21 @@ func (c *Client) LoginCommand(args []string) error {
 	}
 
 	// Generate API key via the API
-	req := map[string]interface{}{
-		"name":        *keyName,
-		"expiry_days": *expiryDays,
-	}
-
-	var resp map[string]interface{}
-	err = c.http.POST("/api/profile/api-keys", req, &resp)
+	creds, err := c.mintAPIKey(user.Username, *keyName, *expiryDays)
 	if err != nil {
-		return fmt.Errorf("failed to generate API key: %w", err)
-	}
-
-	// Extract API key from response
-	apiKey, ok := resp["key"].(string)
-	if !ok || apiKey == "" {
-		return fmt.Errorf("server did not return API key")
-	}
-
-	// Parse timestamps
-	createdAtStr, _ := resp["created_at"].(string)
-	expiresAtStr, _ := resp["expires_at"].(string)
-
-	createdAt, _ := time.Parse(time.RFC3339, createdAtStr)
-	expiresAt, _ := time.Parse(time.RFC3339, expiresAtStr)
-
-	// Save credentials to file
-	creds := &Credentials{
-		ServerURL: c.baseURL,
-		Username:  user.Username,
-		APIKey:    apiKey,
-		CreatedAt: createdAt,
-		ExpiresAt: expiresAt,
-		KeyName:   *keyName,
+		return err
 	}
+	expiresAt := creds.ExpiresAt
+	creds.Store = *store
 
 	err = SaveCredentials(creds)
 	if err != nil {
 		return fmt.Errorf("failed to save credentials: %w", err)
 	}
 
-	credPath, _ := GetCredentialsPath()
 	fmt.Printf("✓ Generated API key '%s'\n", *keyName)
 	fmt.Printf("✓ Expires: %s\n", expiresAt.Format("2006-01-02 15:04:05"))
-	fmt.Printf("✓ Credentials saved to: %s\n", credPath)
+	fmt.Printf("✓ Credentials saved to: %s\n", CredentialsLocation(creds))
 	fmt.Printf("\nYou can now use the CLI without authentication prompts.\n")
 	fmt.Printf("To logout, run: %s logout\n", os.Args[0])
 
@@ -1980,9 +2930,13 @@ func (c *Client) LoginSSOCommand(args []string) error {
 	keyName := fs.String("name", "", "Name for the API key (default: cli-<hostname>-<timestamp>)")
 	expiryDays := fs.Int("expiry-days", 90, "Number of days until API key expiry")
 
+	store := fs.String("store", "", "Where to store the credentials: keychain or file (default: keychain if available)")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if err := ValidateCredentialStore(*store); err != nil {
+		return usageError("%v", err)
+	}
 
 	fmt.Println("🔐 Starting SSO authentication...")
 
@@ -2062,12 +3016,14 @@ func (c *Client) LoginSSOCommand(args []string) error {
 
 	// 9. Save credentials to file
 	creds := &Credentials{
-		ServerURL: c.baseURL,
-		Username:  username,
-		APIKey:    apiKey,
-		CreatedAt: time.Now(),
-		ExpiresAt: expiresAt,
-		KeyName:   apiKeyName,
+		ServerURL:  c.baseURL,
+		Username:   username,
+		APIKey:     apiKey,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  expiresAt,
+		KeyName:    apiKeyName,
+		ExpiryDays: *expiryDays,
+		Store:      *store,
 	}
 
 	err = SaveCredentials(creds)
@@ -2075,10 +3031,9 @@ func (c *Client) LoginSSOCommand(args []string) error {
 		return fmt.Errorf("failed to save credentials: %w", err)
 	}
 
-	credPath, _ := GetCredentialsPath()
 	fmt.Printf("✓ Generated API key '%s'\n", apiKeyName)
 	fmt.Printf("✓ Expires: %s\n", expiresAt.Format("2006-01-02 15:04:05"))
-	fmt.Printf("✓ Credentials saved to: %s\n", credPath)
+	fmt.Printf("✓ Credentials saved to: %s\n", CredentialsLocation(creds))
 	fmt.Printf("\nYou can now use the CLI without authentication prompts.\n")
 	fmt.Printf("To logout, run: %s logout\n", os.Args[0])
 
@@ -2088,24 +3043,24 @@ func (c *Client) LoginSSOCommand(args []string) error {
 // LogoutCommand removes the locally stored credentials
 func (c *Client) LogoutCommand() error {
 	// Check if credentials exist
-	credPath, err := GetCredentialsPath()
+	creds, err := ReadCredentials()
 	if err != nil {
 		return err
 	}
 
-	if _, err := os.Stat(credPath); os.IsNotExist(err) {
+	if creds == nil {
 		fmt.Println("No credentials found. You are not logged in.")
 		return nil
 	}
 
-	// Remove credentials file
+	// Remove credentials from the keychain and the file
 	err = ClearCredentials()
 	if err != nil {
 		return fmt.Errorf("failed to clear credentials: %w", err)
 	}
 
 	fmt.Println("✓ Logged out successfully")
-	fmt.Printf("✓ Removed credentials from: %s\n", credPath)
+	fmt.Printf("✓ Removed credentials from: %s\n", CredentialsLocation(creds))
 	fmt.Printf("\nTo login again, run: %s login\n", os.Args[0])
 
 	return nil
@@ -2143,8 +3098,14 @@ func (c *Client) filterResources(resources map[string][]*ResourceInstance, resou
 }
 
 // ListResourcesCommand lists all resource instances with optional filtering by application, type, and state
-func (c *Client) ListResourcesCommand(appName, resourceType, state string) error {
-	resources, err := c.ListResources(appName)
+func (c *Client) ListResourcesCommand(appName, resourceType, state string, drifted bool) error {
+	var resources map[string][]*ResourceInstance
+	var err error
+	if drifted {
+		resources, err = c.ListDriftedResources(appName)
+	} else {
+		resources, err = c.ListResources(appName)
+	}
 	if err != nil {
 		return err
 	}
@@ -2179,7 +3140,7 @@ func (c *Client) ListResourcesCommand(appName, resourceType, state string) error
 	}
 
 	// Add filter info to title
-	if resourceType != "" || state != "" {
+	if resourceType != "" || state != "" || drifted {
 		filterParts := []string{}
 		if resourceType != "" {
 			filterParts = append(filterParts, fmt.Sprintf("type=%s", resourceType))
@@ -2187,6 +3148,9 @@ func (c *Client) ListResourcesCommand(appName, resourceType, state string) error
 		if state != "" {
 			filterParts = append(filterParts, fmt.Sprintf("state=%s", state))
 		}
+		if drifted {
+			filterParts = append(filterParts, "drifted")
+		}
 		title += fmt.Sprintf(" [filtered: %s]", strings.Join(filterParts, ", "))
 	}
 
@@ -2271,6 +3235,15 @@ func (c *Client) ListResourcesCommand(appName, resourceType, state string) error
 				fmt.Printf("   Error: %s\n", *resource.ErrorMessage)
 			}
 
+			// Show drift found by the orchestration engine
+			if resource.DriftState == "drifted" {
+				drift := "drifted"
+				if resource.DriftMessage != nil {
+					drift = *resource.DriftMessage
+				}
+				fmt.Printf("   Drift: %s\n", drift)
+			}
+
 			// Show API link
 			fmt.Printf("   🔗 API Link: %s/api/resources/%d\n", c.baseURL, resource.ID)
 		}
@@ -2343,7 +3316,7 @@ func (c *Client) ResourceCommand(args []string) error {
 
 		var config map[string]interface{}
 		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
-			return fmt.Errorf("invalid config JSON: %w", err)
+			return usageError("invalid config JSON: %w", err)
 		}
 
 		if err := c.UpdateResource(resourceID, config); err != nil {
@@ -2670,7 +3643,7 @@ func (c *Client) RetryWorkflowCommand(workflowID, workflowSpecFile string) error
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("retry failed (HTTP %d): %s", resp.StatusCode, string(body))
+		return newAPIError(resp.StatusCode, body, fmt.Sprintf("retry failed (HTTP %d)", resp.StatusCode))
 	}
 
 	// Parse response
@@ -2688,6 +3661,170 @@ func (c *Client) RetryWorkflowCommand(workflowID, workflowSpecFile string) error
 	return nil
 }
 
+// WorkflowBundleCommand downloads a workflow execution as a reproducible bundle
+// (workflow definition, parameters, spec, step logs, artifacts and provenance)
+func (c *Client) WorkflowBundleCommand(workflowID, outputFile string) error {
+	if outputFile == "" {
+		outputFile = fmt.Sprintf("workflow-%s-bundle.tar.gz", workflowID)
+	}
+
+	url := fmt.Sprintf("%s/api/workflows/%s/bundle", c.baseURL, workflowID)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	c.http.setClientHeaders(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download bundle: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return newAPIError(resp.StatusCode, body, fmt.Sprintf("bundle export failed (HTTP %d)", resp.StatusCode))
+	}
+
+	// #nosec G304 -- outputFile is user-provided CLI argument
+	file, err := os.OpenFile(outputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outputFile, err)
+	}
+	size, err := io.Copy(file, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+
+	c.Formatter.PrintSuccess(fmt.Sprintf("Workflow %s bundle written to %s (%d bytes)", workflowID, outputFile, size))
+	fmt.Printf("   Inspect with: tar -xzf %s && cat workflow-%s/manifest.json\n", outputFile, workflowID)
+	return nil
+}
+
+// WorkflowReplayCommand re-executes a past workflow run with its recorded definition
+// and parameters. The server starts the replay in the background.
+func (c *Client) WorkflowReplayCommand(workflowID string) error {
+	var result struct {
+		Message             string `json:"message"`
+		ExecutionID         int64  `json:"execution_id"`
+		OriginalExecutionID int64  `json:"original_execution_id"`
+		AppName             string `json:"app_name"`
+		WorkflowName        string `json:"workflow_name"`
+		Status              string `json:"status"`
+	}
+	path := fmt.Sprintf("/api/workflows/%s/replay", workflowID)
+	if err := c.http.POSTWithStatus(path, nil, http.StatusAccepted, &result); err != nil {
+		return fmt.Errorf("replay failed: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(result)
+	}
+
+	c.Formatter.PrintSuccess(fmt.Sprintf("Replaying workflow execution %d", result.OriginalExecutionID))
+	c.Formatter.PrintKeyValue(1, "Execution ID", result.ExecutionID)
+	c.Formatter.PrintKeyValue(1, "Application", result.AppName)
+	c.Formatter.PrintKeyValue(1, "Workflow", result.WorkflowName)
+	c.Formatter.PrintKeyValue(1, "Status", result.Status)
+	fmt.Printf("   Follow with: innominatus-ctl workflow logs %d\n", result.ExecutionID)
+	return nil
+}
+
+// WorkflowRollback lists the resources a workflow run created and their rollback state
+type WorkflowRollback struct {
+	ExecutionID   int64  `json:"execution_id"`
+	Status        string `json:"status"`
+	Compensations []struct {
+		StepName     string  `json:"step_name"`
+		Kind         string  `json:"kind"`
+		Target       string  `json:"target"`
+		Status       string  `json:"status"`
+		ErrorMessage *string `json:"error_message,omitempty"`
+	} `json:"compensations"`
+	Pending    int    `json:"pending"`
+	RolledBack int    `json:"rolled_back"`
+	Failed     int    `json:"failed"`
+	Error      string `json:"error,omitempty"`
+}
+
+// WorkflowRollbackCommand removes the resources a failed workflow run created, newest
+// first. Unless assumeYes is set, the resources are listed and confirmation is asked.
+func (c *Client) WorkflowRollbackCommand(workflowID string, assumeYes bool) error {
+	path := fmt.Sprintf("/api/workflows/%s/rollback", workflowID)
+
+	var state WorkflowRollback
+	if err := c.http.GET(path, &state); err != nil {
+		return fmt.Errorf("failed to list created resources: %w", err)
+	}
+
+	remaining := state.Pending + state.Failed
+	if !c.Formatter.IsJSON() {
+		c.printWorkflowRollback(&state)
+	}
+	if remaining == 0 {
+		if c.Formatter.IsJSON() {
+			return c.Formatter.PrintJSON(state)
+		}
+		c.Formatter.PrintEmptyState("Nothing to roll back")
+		return nil
+	}
+	if state.Status != "failed" {
+		return fmt.Errorf("workflow %s is %s; only failed workflows can be rolled back", workflowID, state.Status)
+	}
+
+	if !assumeYes {
+		if c.Formatter.IsJSON() {
+			return usageError("--yes is required with JSON output")
+		}
+		fmt.Printf("Roll back %d resource(s)? Type 'yes' to confirm: ", remaining)
+		var confirmation string
+		_, _ = fmt.Scanln(&confirmation) // nolint:errcheck
+		if confirmation != "yes" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	var result WorkflowRollback
+	if err := c.http.POSTWithStatus(path, nil, http.StatusOK, &result); err != nil {
+		// Show what could not be removed
+		if getErr := c.http.GET(path, &result); getErr == nil && !c.Formatter.IsJSON() {
+			c.printWorkflowRollback(&result)
+		}
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(result)
+	}
+	c.Formatter.PrintSuccess(fmt.Sprintf("Rolled back %d resource(s) of workflow %s", result.RolledBack, workflowID))
+	fmt.Printf("   Rollback steps: innominatus-ctl workflow detail %s\n", workflowID)
+	return nil
+}
+
+// printWorkflowRollback lists the tracked resources of a run, newest first as they are rolled back
+func (c *Client) printWorkflowRollback(state *WorkflowRollback) {
+	c.Formatter.PrintHeader(fmt.Sprintf("Resources created by workflow %d (%s):", state.ExecutionID, state.Status))
+	for i := len(state.Compensations) - 1; i >= 0; i-- {
+		compensation := state.Compensations[i]
+		symbol := SymbolBullet
+		switch compensation.Status {
+		case "rolled_back":
+			symbol = SymbolSuccess
+		case "failed":
+			symbol = SymbolError
+		}
+		line := fmt.Sprintf("%s (step %s): %s", compensation.Target, compensation.StepName, compensation.Status)
+		if compensation.ErrorMessage != nil {
+			line += " - " + *compensation.ErrorMessage
+		}
+		c.Formatter.PrintItem(1, symbol, line)
+	}
+}
+
 // displayWorkflowHeader shows workflow execution summary
 func (c *Client) displayWorkflowHeader(workflow *WorkflowExecutionDetail) {
 	statusEmoji := "❓"
@@ -2854,7 +3991,7 @@ func toTitle(s string) string {
 // ProviderCommand handles provider-related subcommands
 func (c *Client) ProviderCommand(args []string) error {
 	if len(args) < 1 {
-		return fmt.Errorf("provider command requires a subcommand (list, stats, reload)")
+		return fmt.Errorf("provider command requires a subcommand (list, describe, test, dev, stats, reload, pins, pin, unpin, upgrade-report)")
 	}
 
 	subcommand := args[0]
@@ -2862,15 +3999,125 @@ func (c *Client) ProviderCommand(args []string) error {
 	switch subcommand {
 	case "list":
 		return c.ListProvidersCommand()
+	case "describe":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: provider describe <provider-name> [provisioner-name]")
+		}
+		provisioner := ""
+		if len(args) > 2 {
+			provisioner = args[2]
+		}
+		return c.DescribeProviderCommand(args[1], provisioner)
+	case "test":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: provider test <path> [--fake]")
+		}
+		return c.ProviderTestCommand(args[1], len(args) > 2 && args[2] == "--fake")
+	case "dev":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: provider dev <dir>")
+		}
+		return c.ProviderDevCommand(args[1], time.Second)
 	case "stats":
 		return c.ProviderStatsCommand()
 	case "reload":
 		return c.ProviderReloadCommand()
+	case "upgrade-report":
+		application := ""
+		if len(args) > 1 {
+			application = args[1]
+		}
+		return c.ProviderUpgradeReportCommand(application)
 	default:
-		return fmt.Errorf("unknown provider subcommand: %s (available: list, stats, reload)", subcommand)
+		return fmt.Errorf("unknown provider subcommand: %s (available: list, describe, test, dev, stats, reload, pins, pin, unpin, upgrade-report)", subcommand)
 	}
 }
 
+// ApprovalCommand handles workflow approval gate subcommands
+func (c *Client) ApprovalCommand(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("approval command requires a subcommand (list, approve, reject)")
+	}
+
+	switch args[0] {
+	case "list":
+		status := "pending"
+		if len(args) > 1 {
+			status = args[1]
+			if status == "all" {
+				status = ""
+			}
+		}
+		return c.ListApprovalsCommand(status)
+	case "approve", "reject":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: approval %s <approval-id> [comment]", args[0])
+		}
+		comment := ""
+		if len(args) > 2 {
+			comment = strings.Join(args[2:], " ")
+		}
+		return c.DecideApprovalCommand(args[1], args[0], comment)
+	default:
+		return fmt.Errorf("unknown approval subcommand: %s (available: list, approve, reject)", args[0])
+	}
+}
+
+// ListApprovalsCommand lists workflow approval gates with their plan summaries
+func (c *Client) ListApprovalsCommand(status string) error {
+	approvals, err := c.ListApprovals(status)
+	if err != nil {
+		return fmt.Errorf("failed to list approvals: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(approvals)
+	}
+	if c.Formatter.IsYAML() {
+		return c.Formatter.PrintYAML(approvals)
+	}
+
+	if len(approvals) == 0 {
+		c.Formatter.PrintEmptyState("No approvals found")
+		return nil
+	}
+
+	c.Formatter.PrintHeader(fmt.Sprintf("Approvals (%d):", len(approvals)))
+
+	for _, approval := range approvals {
+		c.Formatter.PrintEmpty()
+		c.Formatter.PrintSection(0, SymbolWorkflow, fmt.Sprintf("#%d %s / %s", approval.ID, approval.ApplicationName, approval.StepName))
+		c.Formatter.PrintKeyValue(1, "Status", c.Formatter.PrintStatusBadge(approval.Status))
+		c.Formatter.PrintKeyValue(1, "Workflow", fmt.Sprintf("%d", approval.ExecutionID))
+		if approval.Environment != "" {
+			c.Formatter.PrintKeyValue(1, "Environment", approval.Environment)
+		}
+		c.Formatter.PrintKeyValue(1, "Requested", c.Formatter.FormatTime(approval.RequestedAt))
+		if approval.DecidedBy != nil {
+			c.Formatter.PrintKeyValue(1, "Decided by", *approval.DecidedBy)
+		}
+		if approval.Summary != "" {
+			for _, line := range strings.Split(strings.TrimRight(approval.Summary, "\n"), "\n") {
+				fmt.Printf("      %s\n", line)
+			}
+		}
+	}
+
+	c.Formatter.PrintEmpty()
+	return nil
+}
+
+// DecideApprovalCommand approves or rejects a workflow approval gate
+func (c *Client) DecideApprovalCommand(id, action, comment string) error {
+	approval, err := c.DecideApproval(id, action, comment)
+	if err != nil {
+		return fmt.Errorf("failed to %s approval %s: %w", action, id, err)
+	}
+
+	c.Formatter.PrintSuccess(fmt.Sprintf("Approval #%d %s", approval.ID, approval.Status))
+	return nil
+}
+
 // ListProvidersCommand lists all loaded providers
 func (c *Client) ListProvidersCommand() error {
 	providers, err := c.ListProviders()
@@ -2913,6 +4160,273 @@ func (c *Client) ListProvidersCommand() error {
 	return nil
 }
 
+// DescribeProviderCommand shows the provisioners, versions, configuration parameters and
+// compatibility of a provider. With a provisioner name it prints that provisioner's
+// rendered documentation instead.
+func (c *Client) DescribeProviderCommand(name, provisioner string) error {
+	if provisioner != "" {
+		return c.describeProvisioner(name, provisioner)
+	}
+
+	provider, err := c.GetProvider(name)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(provider)
+	}
+	if c.Formatter.IsYAML() {
+		return c.Formatter.PrintYAML(provider)
+	}
+
+	c.Formatter.PrintHeader(fmt.Sprintf("Provider: %s v%s", provider.Name, provider.Version))
+	if provider.Category != "" {
+		c.Formatter.PrintKeyValue(0, "Category", provider.Category)
+	}
+	if provider.Description != "" {
+		c.Formatter.PrintKeyValue(0, "Description", provider.Description)
+	}
+	compatibility := ">= " + provider.Compatibility.MinCoreVersion
+	if provider.Compatibility.MaxCoreVersion != "" {
+		compatibility += ", <= " + provider.Compatibility.MaxCoreVersion
+	}
+	c.Formatter.PrintKeyValue(0, "Core Version", compatibility)
+	if len(provider.Capabilities.ResourceTypes) > 0 {
+		c.Formatter.PrintKeyValue(0, "Resource Types", strings.Join(provider.Capabilities.ResourceTypes, ", "))
+	}
+	if len(provider.Dependencies) > 0 {
+		deps := make([]string, 0, len(provider.Dependencies))
+		for _, dep := range provider.Dependencies {
+			if dep.Version != "" {
+				deps = append(deps, fmt.Sprintf("%s (%s)", dep.Name, dep.Version))
+			} else {
+				deps = append(deps, dep.Name)
+			}
+		}
+		c.Formatter.PrintKeyValue(0, "Depends On", strings.Join(deps, ", "))
+	}
+
+	c.Formatter.PrintSubHeader(fmt.Sprintf("Workflows (%d):", len(provider.Workflows)))
+	for _, wf := range provider.Workflows {
+		title := wf.Name
+		if wf.Version != "" {
+			title += " v" + wf.Version
+		}
+		c.Formatter.PrintEmpty()
+		c.Formatter.PrintSection(0, SymbolWorkflow, title)
+		kind := wf.Category
+		if kind == "" {
+			kind = "provisioner"
+		}
+		if wf.Operation != "" {
+			kind += " (" + wf.Operation + ")"
+		}
+		c.Formatter.PrintKeyValue(1, "Type", kind)
+		if wf.Description != "" {
+			c.Formatter.PrintKeyValue(1, "Description", wf.Description)
+		}
+		if len(wf.Parameters) == 0 {
+			continue
+		}
+		c.Formatter.PrintKeyValue(1, "Parameters", "")
+		for _, param := range wf.Parameters {
+			line := param.Name
+			if param.Type != "" {
+				line += " (" + param.Type + ")"
+			}
+			if param.Required {
+				line += ", required"
+			}
+			if param.Default != nil {
+				line += fmt.Sprintf(", default: %v", param.Default)
+			}
+			if param.Description != "" {
+				line += " - " + param.Description
+			}
+			c.Formatter.PrintItem(2, "", line)
+		}
+	}
+
+	for _, p := range provider.Provisioners {
+		c.Formatter.PrintEmpty()
+		c.Formatter.PrintSection(0, SymbolResource, fmt.Sprintf("%s v%s", p.Name, p.Version))
+		c.Formatter.PrintKeyValue(1, "Type", p.Type)
+		if p.Description != "" {
+			c.Formatter.PrintKeyValue(1, "Description", p.Description)
+		}
+	}
+
+	c.Formatter.PrintEmpty()
+	c.Formatter.PrintInfo(fmt.Sprintf("Documentation of a provisioner: provider describe %s <provisioner>", provider.Name))
+	return nil
+}
+
+// describeProvisioner prints the rendered documentation of one provisioner of a provider
+func (c *Client) describeProvisioner(name, provisioner string) error {
+	docs, err := c.GetProviderDocs(name)
+	if err != nil {
+		return fmt.Errorf("failed to get provider docs: %w", err)
+	}
+
+	for _, p := range docs.Provisioners {
+		if p.Name != provisioner {
+			continue
+		}
+		if c.Formatter.IsJSON() {
+			return c.Formatter.PrintJSON(p)
+		}
+		if c.Formatter.IsYAML() {
+			return c.Formatter.PrintYAML(p)
+		}
+		fmt.Print(p.Markdown)
+		return nil
+	}
+	return fmt.Errorf("provider %s has no provisioner %s", name, provisioner)
+}
+
+// ProviderTestCommand checks a local provider directory before it is registered: the
+// manifest, core compatibility, workflow schemas and the files its docs reference. With
+// fake set, every workflow also runs with faked steps.
+func (c *Client) ProviderTestCommand(path string, fake bool) error {
+	manifest, err := providerManifestPath(path)
+	if err != nil {
+		return err
+	}
+
+	type testReport struct {
+		Provider    string               `json:"provider" yaml:"provider"`
+		Version     string               `json:"version" yaml:"version"`
+		Manifest    string               `json:"manifest" yaml:"manifest"`
+		Passed      bool                 `json:"passed" yaml:"passed"`
+		Error       string               `json:"error,omitempty" yaml:"error,omitempty"`
+		Conformance []conformance.Result `json:"conformance,omitempty" yaml:"conformance,omitempty"`
+	}
+	report := testReport{Manifest: manifest}
+	structured := c.Formatter.IsJSON() || c.Formatter.IsYAML()
+	finish := func() error {
+		if c.Formatter.IsJSON() {
+			if err := c.Formatter.PrintJSON(report); err != nil {
+				return err
+			}
+		} else if c.Formatter.IsYAML() {
+			if err := c.Formatter.PrintYAML(report); err != nil {
+				return err
+			}
+		}
+		if !report.Passed {
+			return fmt.Errorf("provider test failed")
+		}
+		return nil
+	}
+	fail := func(step string, err error) error {
+		report.Error = fmt.Sprintf("%s: %v", step, err)
+		if !structured {
+			c.Formatter.PrintError(report.Error)
+		}
+		return finish()
+	}
+
+	if !structured {
+		c.Formatter.PrintHeader(fmt.Sprintf("Testing provider %s", manifest))
+	}
+
+	// The loader validates the manifest, core compatibility, dependency ranges and the
+	// schema of every workflow file
+	provider, err := providers.NewLoader(c.coreVersion()).LoadFromFile(manifest)
+	if err != nil {
+		return fail("load", err)
+	}
+	report.Provider = provider.Metadata.Name
+	report.Version = provider.Metadata.Version
+	if !structured {
+		c.Formatter.PrintSuccess(fmt.Sprintf("Manifest valid: %s v%s", provider.Metadata.Name, provider.Metadata.Version))
+		if version := c.coreVersion(); version == "dev" || version == "unknown" {
+			c.Formatter.PrintWarning("Core version compatibility not checked by a development build")
+		} else {
+			c.Formatter.PrintSuccess(fmt.Sprintf("Compatible with core version %s", version))
+		}
+		c.Formatter.PrintSuccess(fmt.Sprintf("%d workflow(s) pass schema validation", len(provider.Workflows)))
+	}
+
+	if _, err := providers.RenderDocs(provider); err != nil {
+		return fail("docs", err)
+	}
+	if !structured {
+		c.Formatter.PrintSuccess("Docs and examples readable")
+	}
+
+	report.Passed = true
+	if fake {
+		// Results are reported below; keep the executor's step logs out of the output
+		// unless LOG_LEVEL asks for them
+		if os.Getenv("LOG_LEVEL") == "" {
+			previous := logging.CurrentLevel()
+			logging.SetLevel(logging.FATAL)
+			defer logging.SetLevel(previous)
+		}
+		results, err := conformance.Run(context.Background(), provider)
+		if err != nil {
+			report.Passed = false
+			return fail("conformance", err)
+		}
+		report.Conformance = results
+		if !structured {
+			c.Formatter.PrintSubHeader("Fake provisioning:")
+		}
+		for _, result := range results {
+			report.Passed = report.Passed && result.Passed()
+			if structured {
+				continue
+			}
+			if result.Passed() {
+				c.Formatter.PrintSuccess(fmt.Sprintf("%s (%d steps)", result.Workflow, len(result.Steps)))
+			} else {
+				c.Formatter.PrintError(fmt.Sprintf("%s: %s", result.Workflow, result.Error))
+			}
+			if len(result.Unresolved) > 0 {
+				c.Formatter.PrintItem(1, "", fmt.Sprintf("unresolved outputs: %s", strings.Join(result.Unresolved, ", ")))
+			}
+		}
+	}
+
+	if !structured {
+		c.Formatter.PrintEmpty()
+		if report.Passed {
+			c.Formatter.PrintInfo(fmt.Sprintf("Register %s under providers in admin-config.yaml, then run: provider reload", provider.Metadata.Name))
+		}
+	}
+	return finish()
+}
+
+// providerManifestPath returns the provider.yaml of a provider directory, or path itself
+// when it names a manifest
+func providerManifestPath(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read provider: %w", err)
+	}
+	if !info.IsDir() {
+		return path, nil
+	}
+	for _, name := range []string{"provider.yaml", "provider.yml", "platform.yaml", "platform.yml"} {
+		manifest := filepath.Join(path, name)
+		if _, err := os.Stat(manifest); err == nil {
+			return manifest, nil
+		}
+	}
+	return "", fmt.Errorf("no provider.yaml found in %s", path)
+}
+
+// coreVersion is the core version a local provider is checked against: the server
+// version matching this CLI
+func (c *Client) coreVersion() string {
+	if c.cliVersion == "" {
+		return "dev"
+	}
+	return c.cliVersion
+}
+
 // ProviderStatsCommand shows provider statistics
 func (c *Client) ProviderStatsCommand() error {
 	formatter := NewOutputFormatter()
@@ -2961,6 +4475,110 @@ func (c *Client) ProviderReloadCommand() error {
 	return nil
 }
 
+// ProviderPinsCommand lists the provider version pins of a team, or those in effect for
+// an application including the pins of its team
+func (c *Client) ProviderPinsCommand(scope, name string) error {
+	pins, err := c.GetProviderPins(scope, name)
+	if err != nil {
+		return fmt.Errorf("failed to get provider version pins: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(pins)
+	}
+	if c.Formatter.IsYAML() {
+		return c.Formatter.PrintYAML(pins)
+	}
+
+	c.Formatter.PrintHeader(fmt.Sprintf("Provider Version Pins: %s %s", scope, name))
+	if len(pins) == 0 {
+		c.Formatter.PrintEmptyState("No provider versions pinned; the registered versions are used")
+		return nil
+	}
+	for _, pin := range pins {
+		line := fmt.Sprintf("%s %s (pinned by %s on %s %s)", pin.Provider, pin.Version, pin.PinnedBy, pin.Scope, pin.ScopeName)
+		if pin.Reason != "" {
+			line += ": " + pin.Reason
+		}
+		c.Formatter.PrintItem(0, SymbolBullet, line)
+	}
+	c.Formatter.PrintCount("pin(s)", len(pins))
+	return nil
+}
+
+// ProviderPinCommand pins a provider version for an application or team
+func (c *Client) ProviderPinCommand(scope, name, provider, version, reason string) error {
+	pin, err := c.PinProviderVersion(scope, name, provider, version, reason)
+	if err != nil {
+		return fmt.Errorf("failed to pin provider version: %w", err)
+	}
+	c.Formatter.PrintSuccess(fmt.Sprintf("Pinned %s to %s for %s %s", pin.Provider, pin.Version, pin.Scope, pin.ScopeName))
+	return nil
+}
+
+// ProviderUnpinCommand removes the pin of a provider from an application or team, so it
+// follows the registered version again
+func (c *Client) ProviderUnpinCommand(scope, name, provider string) error {
+	if err := c.UnpinProviderVersion(scope, name, provider); err != nil {
+		return fmt.Errorf("failed to remove provider version pin: %w", err)
+	}
+	c.Formatter.PrintSuccess(fmt.Sprintf("Removed the %s pin of %s %s", provider, scope, name))
+	return nil
+}
+
+// ProviderUpgradeReportCommand shows the pinned provider versions with the applications
+// behind them and what changes when the pins are removed
+func (c *Client) ProviderUpgradeReportCommand(application string) error {
+	report, err := c.GetProviderUpgradeReport(application)
+	if err != nil {
+		return fmt.Errorf("failed to get provider upgrade report: %w", err)
+	}
+
+	if c.Formatter.IsJSON() {
+		return c.Formatter.PrintJSON(report)
+	}
+	if c.Formatter.IsYAML() {
+		return c.Formatter.PrintYAML(report)
+	}
+
+	c.Formatter.PrintHeader("Provider Upgrade Report")
+	if len(report.Pins) == 0 {
+		c.Formatter.PrintEmptyState("No provider versions pinned")
+		return nil
+	}
+	for _, pin := range report.Pins {
+		c.Formatter.PrintSubHeader(fmt.Sprintf("%s %s (%s %s): %s", pin.Provider, pin.Version, pin.Scope, pin.ScopeName, pin.Status))
+		if pin.CurrentVersion != "" {
+			c.Formatter.PrintKeyValue(1, "Registered Version", pin.CurrentVersion)
+		}
+		c.Formatter.PrintKeyValue(1, "Applications", strings.Join(pin.Applications, ", "))
+		if pin.Reason != "" {
+			c.Formatter.PrintKeyValue(1, "Reason", pin.Reason)
+		}
+		if pin.Error != "" {
+			c.Formatter.PrintError(pin.Error)
+		}
+		if changes := pin.Changes; changes != nil {
+			for _, change := range []struct {
+				label string
+				names []string
+			}{
+				{"Resource Types Added", changes.ResourceTypesAdded},
+				{"Resource Types Removed", changes.ResourceTypesRemoved},
+				{"Workflows Added", changes.WorkflowsAdded},
+				{"Workflows Removed", changes.WorkflowsRemoved},
+				{"Workflows Changed", changes.WorkflowsChanged},
+			} {
+				if len(change.names) > 0 {
+					c.Formatter.PrintKeyValue(1, change.label, strings.Join(change.names, ", "))
+				}
+			}
+		}
+	}
+	c.Formatter.PrintCount("pin(s)", len(report.Pins))
+	return nil
+}
+
 // StatsCommand displays platform statistics (applications, workflows, resources, users)
 func (c *Client) StatsCommand() error {
 	formatter := NewOutputFormatter()
@@ -3111,7 +4729,7 @@ func (c *Client) WhoamiCommand() error {
 	} else {
 		creds, _ := LoadCredentials()
 		if creds != nil {
-			formatter.PrintKeyValue(1, "Source", "Credentials file")
+			formatter.PrintKeyValue(1, "Source", CredentialsLocation(creds))
 			formatter.PrintKeyValue(1, "Key Name", creds.KeyName)
 			formatter.PrintKeyValue(1, "Key", maskAPIKey(creds.APIKey))
 			formatter.PrintKeyValue(1, "Expires", creds.ExpiresAt.Format("2006-01-02 15:04:05"))
@@ -3,6 +3,7 @@ package cli
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -176,6 +177,45 @@ func TestStatusCommand(t *testing.T) {
 	assert.Contains(t, err.Error(), "not found")
 }
 
+func TestStatusCommandConsolidated(t *testing.T) {
+	specRequested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/applications/shop/status":
+			_, _ = fmt.Fprint(w, `{"name":"shop","team":"engineering",
+				"latest_deployment":{"deployed_at":"2025-03-01T10:00:00Z","deployed_by":"alice","status":"running",
+					"workflow":{"id":11,"workflow_name":"deploy","status":"running","started_at":"2025-03-01T11:55:00Z"}},
+				"resources":[{"id":1,"name":"db","type":"postgres","state":"active","health_status":"healthy",
+					"hints":[{"type":"url","label":"Console","value":"https://db.example.com"}]}],
+				"active_workflows":[{"id":11,"workflow_name":"deploy","status":"running","started_at":"2025-03-01T11:55:00Z","total_steps":3,"completed_steps":1}],
+				"health":{"status":"healthy","resources":1,"healthy":1,"failing_resources":0},
+				"recent_events":[{"time":"2025-03-01T11:55:00Z","type":"workflow","subject":"deploy","message":"Workflow #11 started","severity":"info"}],
+				"pending_approvals":[{"id":20,"execution_id":11,"step_name":"approve-prod","application_name":"shop","status":"pending"}]}`)
+		case "/api/applications/shop":
+			specRequested = true
+			_, _ = fmt.Fprint(w, `{"metadata": {"APIVersion": "score.dev/v1b1"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	require.NoError(t, client.StatusCommand("shop"))
+	assert.False(t, specRequested, "the consolidated status needs a single call")
+
+	status, err := client.GetApplicationStatus("shop")
+	require.NoError(t, err)
+	assert.Equal(t, "running", status.LatestDeployment.Status)
+	require.Len(t, status.Resources, 1)
+	assert.Equal(t, "Console", status.Resources[0].Hints[0].Label)
+	require.Len(t, status.ActiveWorkflows, 1)
+	assert.Equal(t, 1, status.ActiveWorkflows[0].CompletedSteps)
+	require.Len(t, status.PendingApprovals, 1)
+	assert.Equal(t, "approve-prod", status.PendingApprovals[0].StepName)
+}
+
 func TestValidateCommand(t *testing.T) {
 	// Create temporary test files
 	tmpDir := t.TempDir()
@@ -423,3 +463,528 @@ func TestErrorHandling(t *testing.T) {
 	err = client.StatusCommand("test-app")
 	assert.Error(t, err)
 }
+
+func TestCheckServerCompatibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		response   string
+		wantErr    bool
+		errContain string
+	}{
+		{
+			name:     "compatible",
+			status:   http.StatusOK,
+			response: `{"version":"v1.4.0","cli":{"compatible":true}}`,
+		},
+		{
+			name:     "deprecated version only warns",
+			status:   http.StatusOK,
+			response: `{"version":"v1.4.0","cli":{"recommended_version":"v1.3.0","compatible":true,"warnings":["innominatus-ctl v1.2.0 is deprecated"]}}`,
+		},
+		{
+			name:       "rejected version",
+			status:     http.StatusOK,
+			response:   `{"version":"v1.4.0","cli":{"min_version":"v1.3.0","compatible":false}}`,
+			wantErr:    true,
+			errContain: "minimum v1.3.0",
+		},
+		{
+			name:   "server without version endpoint",
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/version", r.URL.Path)
+				assert.Equal(t, "v1.2.0", r.Header.Get(CLIVersionHeader))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL)
+			client.SetCLIVersion("v1.2.0")
+
+			err := client.CheckServerCompatibility()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContain)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWorkflowBundleCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/workflows/42/bundle" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/gzip")
+		_, _ = w.Write([]byte("bundle-bytes"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	outputFile := filepath.Join(t.TempDir(), "run.tar.gz")
+
+	require.NoError(t, client.WorkflowBundleCommand("42", outputFile))
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "bundle-bytes", string(data))
+
+	err = client.WorkflowBundleCommand("7", filepath.Join(t.TempDir(), "missing.tar.gz"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 404")
+}
+
+func TestWorkflowReplayCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/workflows/42/replay" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "POST", r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"execution_id":57,"original_execution_id":42,"app_name":"demo","workflow_name":"deploy-app","status":"running"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	require.NoError(t, client.WorkflowReplayCommand("42"))
+
+	err := client.WorkflowReplayCommand("7")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestDescribeProviderCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/providers/database-team":
+			_, _ = w.Write([]byte(`{"name":"database-team","version":"1.0.0","compatibility":{"minCoreVersion":"1.0.0","maxCoreVersion":"2.0.0"},
+				"capabilities":{"resourceTypes":["postgres"]},
+				"workflows":[{"name":"provision-postgres","file":"./workflows/provision-postgres.yaml","operation":"create",
+					"parameters":[{"name":"size","type":"string","default":"small"}]}]}`))
+		case "/api/providers/database-team/docs":
+			_, _ = w.Write([]byte(`{"provider":"database-team","version":"1.0.0","markdown":"# database-team v1.0.0\n",
+				"provisioners":[{"name":"provision-postgres","category":"provisioner","operation":"create","markdown":"## provision-postgres\n"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	require.NoError(t, client.DescribeProviderCommand("database-team", ""))
+	require.NoError(t, client.DescribeProviderCommand("database-team", "provision-postgres"))
+
+	provider, err := client.GetProvider("database-team")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", provider.Compatibility.MaxCoreVersion)
+	require.Len(t, provider.Workflows, 1)
+	assert.Equal(t, "create", provider.Workflows[0].Operation)
+	assert.Equal(t, "small", provider.Workflows[0].Parameters[0].Default)
+
+	err = client.DescribeProviderCommand("database-team", "provision-mysql")
+	assert.EqualError(t, err, "provider database-team has no provisioner provision-mysql")
+
+	err = client.DescribeProviderCommand("missing", "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestProviderPinCommands(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+strings.TrimSpace(string(body)))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "PUT" && r.URL.Path == "/api/teams/payments/provider-pins":
+			_, _ = w.Write([]byte(`{"scope":"team","scope_name":"payments","provider":"database-team","version":"1.4.0","pinned_by":"alice"}`))
+		case r.Method == "DELETE" && r.URL.Path == "/api/applications/shop/provider-pins":
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/providers/upgrade-report":
+			_, _ = w.Write([]byte(`{"generated_at":"2026-10-16T12:00:00Z","pins":[{"scope":"team","scope_name":"payments",
+				"provider":"database-team","version":"1.4.0","current_version":"2.0.0","status":"behind","applications":["ledger"],
+				"changes":{"workflows_changed":["provision-postgres"]}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	require.NoError(t, client.ProviderPinCommand("team", "payments", "database-team", "1.4.0", "PG 16 rollout"))
+	require.NoError(t, client.ProviderUnpinCommand("application", "shop", "database-team"))
+	require.NoError(t, client.ProviderUpgradeReportCommand("ledger"))
+
+	report, err := client.GetProviderUpgradeReport("")
+	require.NoError(t, err)
+	require.Len(t, report.Pins, 1)
+	assert.Equal(t, "behind", report.Pins[0].Status)
+	assert.Equal(t, []string{"provision-postgres"}, report.Pins[0].Changes.WorkflowsChanged)
+
+	assert.Equal(t, []string{
+		`PUT /api/teams/payments/provider-pins {"provider":"database-team","reason":"PG 16 rollout","version":"1.4.0"}`,
+		"DELETE /api/applications/shop/provider-pins?provider=database-team ",
+		"GET /api/providers/upgrade-report?application=ledger ",
+		"GET /api/providers/upgrade-report ",
+	}, requests)
+
+	err = client.ProviderUnpinCommand("application", "cart", "database-team")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestPreviewCommand(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/applications/shop/preview" {
+			http.NotFound(w, r)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"application":"shop","exists":true,
+			"resources":[{"name":"cache","type":"redis","action":"update","changes":["size"]},{"name":"db","type":"postgres","action":"unchanged"}],
+			"steps":[{"phase":"deployment","name":"provision-cache","type":"resource-provisioning","estimated_duration":"3m0s"}],
+			"estimated_duration":"3m0s","estimated_seconds":180,"summary":{"create":0,"update":1,"delete":0,"unchanged":1}}`))
+	}))
+	defer server.Close()
+
+	specFile := filepath.Join(t.TempDir(), "score.yaml")
+	require.NoError(t, os.WriteFile(specFile, []byte("metadata:\n  name: shop\n"), 0600))
+
+	client := NewClient(server.URL)
+	require.NoError(t, client.PreviewCommand("shop", specFile))
+	assert.Contains(t, body, "name: shop")
+
+	preview, err := client.PreviewApplication("shop", []byte("metadata:\n  name: shop\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 180, preview.EstimatedSeconds)
+	require.Len(t, preview.Resources, 2)
+	assert.Equal(t, []string{"size"}, preview.Resources[0].Changes)
+
+	err = client.PreviewCommand("other", specFile)
+	assert.ErrorContains(t, err, "failed to preview application")
+}
+
+func TestDryRunGoldenPath(t *testing.T) {
+	var path, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"application":"shop","exists":false,"dry_run":true,"golden_path":"deploy-app","approval_required":true,
+			"resources":[{"name":"db","type":"postgres","action":"create"}],
+			"steps":[{"phase":"deploy-app","name":"infrastructure","type":"terraform","estimated_duration":"5m0s"}],
+			"estimated_duration":"5m0s","estimated_seconds":300,"summary":{"create":1,"update":0,"delete":0,"unchanged":0}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	preview, err := client.DryRunGoldenPath("deploy-app", []byte("metadata:\n  name: shop\n"), map[string]string{"environment": "production"})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/workflows/golden-paths/deploy-app/execute", path)
+	assert.Equal(t, "dry_run=true&param.environment=production", query)
+	assert.True(t, preview.DryRun)
+	assert.True(t, preview.ApprovalRequired)
+	assert.Equal(t, "deploy-app", preview.GoldenPath)
+	require.Len(t, preview.Steps, 1)
+	require.NoError(t, client.printApplicationPreview(preview))
+
+	err = client.RunGoldenPathDryRunCommand("deploy-app", "", nil)
+	assert.EqualError(t, err, "dry run requires a Score spec")
+}
+
+func TestProviderTestCommand(t *testing.T) {
+	client := NewClient("http://localhost:0")
+	require.NoError(t, client.ProviderTestCommand("../../providers/database-team", true))
+	require.NoError(t, client.ProviderTestCommand("../../providers/database-team/provider.yaml", false))
+
+	dir := t.TempDir()
+	err := client.ProviderTestCommand(dir, false)
+	assert.ErrorContains(t, err, "no provider.yaml found")
+
+	manifest := `apiVersion: v1
+kind: Provider
+metadata:
+  name: broken
+  version: 1.0.0
+compatibility:
+  minCoreVersion: 1.0.0
+workflows:
+  - name: provision
+    file: ./workflows/missing.yaml
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "provider.yaml"), []byte(manifest), 0644))
+	err = client.ProviderTestCommand(dir, false)
+	assert.EqualError(t, err, "provider test failed")
+}
+
+func TestClustersCommand(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/clusters" {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"clusters":[{"name":"prod-eu-1","environment":"production","api_server":"https://10.0.0.1:6443","components":["argocd"],"status":"ready"}],"count":1}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	require.NoError(t, client.ClustersCommand("production"))
+	assert.Equal(t, "environment=production", query)
+
+	require.NoError(t, client.ClustersCommand(""))
+	assert.Empty(t, query)
+}
+
+func TestOverviewCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/me/overview" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"username":"alice","team":"engineering",
+			"counts":{"applications":1,"running_workflows":1,"failing_resources":1,"pending_approvals":1,"expiring_api_keys":1},
+			"applications":[{"name":"shop","resources":2,"failing_resources":1}],
+			"running_workflows":[{"id":10,"application_name":"shop","workflow_name":"deploy","total_steps":3,"completed_steps":1}],
+			"failing_resources":[{"application":"shop","name":"cache","type":"redis","state":"failed","error_message":"quota exceeded"}],
+			"pending_approvals":[{"id":20,"execution_id":10,"step_name":"approve-prod","application_name":"shop","status":"pending"}],
+			"expiring_api_keys":[{"name":"ci","expires_at":"2025-03-04T12:00:00Z","days_left":3}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	overview, err := client.GetOverview()
+	require.NoError(t, err)
+	assert.Equal(t, "alice", overview.Username)
+	assert.Equal(t, 1, overview.Counts.FailingResources)
+	require.Len(t, overview.PendingApprovals, 1)
+	assert.Equal(t, "approve-prod", overview.PendingApprovals[0].StepName)
+	require.Len(t, overview.ExpiringAPIKeys, 1)
+	assert.Equal(t, 3, overview.ExpiringAPIKeys[0].DaysLeft)
+
+	require.NoError(t, client.OverviewCommand())
+}
+
+func TestWorkflowRollbackCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      string
+		postStatus int
+		wantPost   bool
+		wantErr    string
+	}{
+		{
+			name:       "rolls back pending resources",
+			state:      `{"execution_id":42,"status":"failed","compensations":[{"step_name":"create-repo","kind":"gitea-repo","target":"gitea repository platform/shop","status":"pending"}],"pending":1}`,
+			postStatus: http.StatusOK,
+			wantPost:   true,
+		},
+		{
+			name:     "nothing to roll back",
+			state:    `{"execution_id":42,"status":"failed","compensations":[{"step_name":"create-repo","kind":"gitea-repo","target":"gitea repository platform/shop","status":"rolled_back"}],"rolled_back":1}`,
+			wantPost: false,
+		},
+		{
+			name:     "workflow did not fail",
+			state:    `{"execution_id":42,"status":"completed","compensations":[{"step_name":"create-repo","kind":"gitea-repo","target":"gitea repository platform/shop","status":"pending"}],"pending":1}`,
+			wantPost: false,
+			wantErr:  "only failed workflows can be rolled back",
+		},
+		{
+			name:       "incomplete rollback",
+			state:      `{"execution_id":42,"status":"failed","compensations":[{"step_name":"create-repo","kind":"gitea-repo","target":"gitea repository platform/shop","status":"pending"}],"pending":1}`,
+			postStatus: http.StatusBadGateway,
+			wantPost:   true,
+			wantErr:    "rollback failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posted := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/workflows/42/rollback" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == "POST" {
+					posted = true
+					w.WriteHeader(tt.postStatus)
+					_, _ = w.Write([]byte(`{"execution_id":42,"status":"failed","compensations":[],"rolled_back":1}`))
+					return
+				}
+				_, _ = w.Write([]byte(tt.state))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL)
+			err := client.WorkflowRollbackCommand("42", true)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantPost, posted)
+		})
+	}
+}
+
+func TestLintSpecCommand(t *testing.T) {
+	specFile := filepath.Join(t.TempDir(), "score.yaml")
+	require.NoError(t, os.WriteFile(specFile, []byte("apiVersion: score.dev/v1b1\nmetadata:\n  name: shop\n"), 0600))
+
+	tests := []struct {
+		name     string
+		profile  string
+		response string
+		wantErr  bool
+	}{
+		{
+			name:     "meets standards",
+			response: `{"valid":true,"application":"shop","errors":[],"lint":{"profile":"standard","findings":[{"rule":"no-latest","severity":"warning","path":"containers.web.image","message":"image 'nginx:latest' is forbidden (*:latest)"}],"errors":0,"warnings":1}}`,
+		},
+		{
+			name:     "violates profile",
+			profile:  "strict",
+			response: `{"valid":false,"application":"shop","errors":[],"lint":{"profile":"strict","findings":[{"rule":"labels","severity":"error","path":"metadata.labels.team","message":"required label 'team' is missing"}],"errors":1,"warnings":0}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "deployment checks fail",
+			response: `{"valid":false,"application":"shop","errors":["Score specification must define at least one container"]}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, "/api/validate", r.URL.Path)
+				assert.Equal(t, tt.profile, r.URL.Query().Get("profile"))
+				body, _ := io.ReadAll(r.Body)
+				assert.Contains(t, string(body), "name: shop")
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			err := NewClient(server.URL).LintSpecCommand(specFile, tt.profile, "text")
+			if tt.wantErr {
+				assert.EqualError(t, err, "spec does not meet platform standards")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRunWorkflowFollowsExecution(t *testing.T) {
+	started := `{"message": "Golden path 'deploy-app' started for application 'shop'", "application": "shop", "execution_id": 7, "status": "running"}`
+	enqueued := `{"message": "Golden path 'deploy-app' enqueued successfully for application 'shop'", "application": "shop", "task_id": "task-1", "status": "enqueued"}`
+
+	tests := []struct {
+		name       string
+		submit     string
+		execution  string
+		wantStatus string
+		wantErr    string
+	}{
+		{
+			name:       "completed",
+			submit:     started,
+			execution:  `{"id": 7, "status": "completed", "total_steps": 1, "steps": [{"step_number": 1, "step_name": "provision", "status": "completed"}]}`,
+			wantStatus: "completed",
+		},
+		{
+			name:       "queued then completed",
+			submit:     enqueued,
+			execution:  `{"id": 7, "status": "completed", "total_steps": 1, "steps": [{"step_number": 1, "step_name": "provision", "status": "completed"}]}`,
+			wantStatus: "completed",
+		},
+		{
+			name:       "failed",
+			submit:     started,
+			execution:  `{"id": 7, "status": "failed", "total_steps": 1, "error_message": "terraform apply failed", "steps": [{"step_number": 1, "step_name": "provision", "status": "failed"}]}`,
+			wantStatus: "failed",
+			wantErr:    "workflow execution 7 failed: terraform apply failed (see: innominatus-ctl workflow logs 7)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/workflows/golden-paths/deploy-app/execute":
+					assert.Equal(t, "POST", r.Method)
+					w.WriteHeader(http.StatusAccepted)
+					_, _ = fmt.Fprint(w, tt.submit)
+				case "/api/queue/tasks/task-1":
+					_, _ = fmt.Fprint(w, `{"id": "task-1", "status": "running", "execution_id": 7}`)
+				case "/api/workflows/7":
+					_, _ = fmt.Fprint(w, tt.execution)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL)
+			client.token = "test-token"
+
+			status, err := client.runWorkflow("workflows/deploy-app.yaml", "", nil, false)
+			assert.Equal(t, tt.wantStatus, status)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestListResourcesCommandDrift(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/resources" {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"shop":[{"id":7,"resource_name":"db","resource_type":"postgres","state":"active","drift_state":"drifted","drift_message":"version: desired \"15\", actual \"14\""}],
+			"blog":[{"id":9,"resource_name":"cache","resource_type":"redis","state":"active","drift_state":"drifted"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	resources, err := client.ListDriftedResources("shop")
+	require.NoError(t, err)
+	assert.Equal(t, "drift=true", query)
+	require.Len(t, resources, 1)
+	require.Len(t, resources["shop"], 1)
+	assert.Equal(t, `version: desired "15", actual "14"`, *resources["shop"][0].DriftMessage)
+
+	require.NoError(t, client.ListResourcesCommand("", "", "", true))
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Credential stores of the CLI
+const (
+	StoreFile     = "file"     // ~/.innominatus/credentials, readable by the user only
+	StoreKeychain = "keychain" // macOS Keychain, Windows Credential Manager or Secret Service on Linux
+)
+
+// Keychain entry of the credentials
+const (
+	keychainService = "innominatus-ctl"
+	keychainAccount = "credentials"
+)
+
+// errKeychainNotFound is returned by keychain backends when no credentials are stored
+var errKeychainNotFound = errors.New("no credentials in keychain")
+
+// credentialBackend is an OS keychain holding the serialized credentials
+type credentialBackend interface {
+	load() ([]byte, error) // errKeychainNotFound if nothing is stored
+	save(data []byte) error
+	clear() error
+	String() string // Name shown to users, e.g. "macOS Keychain"
+}
+
+// keychain is the keychain of the OS, nil if it has none or it can't be used
+// (e.g. no Secret Service on a headless Linux runner)
+var keychain = newKeychain()
+
+// KeychainAvailable returns true if credentials can be stored in an OS keychain
+func KeychainAvailable() bool {
+	return keychain != nil
+}
+
+// DefaultCredentialStore returns the keychain if available, the file otherwise
+func DefaultCredentialStore() string {
+	if KeychainAvailable() {
+		return StoreKeychain
+	}
+	return StoreFile
+}
+
+// ValidateCredentialStore checks a --store value; empty selects the default store
+func ValidateCredentialStore(store string) error {
+	switch store {
+	case "", StoreFile:
+		return nil
+	case StoreKeychain:
+		if !KeychainAvailable() {
+			return fmt.Errorf("no OS keychain available; use --store file")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid credential store '%s': use %s or %s", store, StoreFile, StoreKeychain)
+	}
+}
+
+// CredentialsLocation describes where creds are stored, for messages to the user
+func CredentialsLocation(creds *Credentials) string {
+	if creds.Store == StoreKeychain && keychain != nil {
+		return keychain.String()
+	}
+	credPath, err := GetCredentialsPath()
+	if err != nil {
+		return StoreFile
+	}
+	return credPath
+}
+
+// saveToKeychain stores data in the keychain and removes a credentials file left
+// from the file store, so no plaintext copy of the key remains
+func saveToKeychain(data []byte) error {
+	if err := keychain.save(data); err != nil {
+		return fmt.Errorf("failed to save credentials to %s: %w", keychain, err)
+	}
+	if err := removeCredentialsFile(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+	return nil
+}
+
+// loadFromKeychain returns the credentials in the keychain, nil if there are none
+func loadFromKeychain() ([]byte, error) {
+	if keychain == nil {
+		return nil, nil
+	}
+	data, err := keychain.load()
+	if errors.Is(err, errKeychainNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials from %s: %w", keychain, err)
+	}
+	return data, nil
+}
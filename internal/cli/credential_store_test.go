@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	// Tests must never touch the keychain of the machine they run on
+	keychain = nil
+	os.Exit(m.Run())
+}
+
+// memoryKeychain is a keychain backend for tests
+type memoryKeychain struct {
+	data    []byte
+	saveErr error
+}
+
+func (k *memoryKeychain) String() string { return "test keychain" }
+
+func (k *memoryKeychain) load() ([]byte, error) {
+	if k.data == nil {
+		return nil, errKeychainNotFound
+	}
+	return k.data, nil
+}
+
+func (k *memoryKeychain) save(data []byte) error {
+	if k.saveErr != nil {
+		return k.saveErr
+	}
+	k.data = append([]byte(nil), data...)
+	return nil
+}
+
+func (k *memoryKeychain) clear() error {
+	k.data = nil
+	return nil
+}
+
+func useKeychain(t *testing.T, k *memoryKeychain) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	keychain = k
+	t.Cleanup(func() { keychain = nil })
+}
+
+func testCredentials(store string) *Credentials {
+	return &Credentials{
+		Username:  "alice",
+		APIKey:    "secret-key",
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+		KeyName:   "cli-laptop",
+		Store:     store,
+	}
+}
+
+func credentialsFileExists(t *testing.T) bool {
+	credPath, err := GetCredentialsPath()
+	require.NoError(t, err)
+	_, err = os.Stat(credPath)
+	return err == nil
+}
+
+func TestSaveCredentials_DefaultsToKeychain(t *testing.T) {
+	k := &memoryKeychain{}
+	useKeychain(t, k)
+	assert.Equal(t, StoreKeychain, DefaultCredentialStore())
+
+	creds := testCredentials("")
+	require.NoError(t, SaveCredentials(creds))
+	assert.Equal(t, StoreKeychain, creds.Store)
+	assert.Contains(t, string(k.data), "secret-key")
+	assert.False(t, credentialsFileExists(t), "no plaintext copy")
+
+	stored, err := ReadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "secret-key", stored.APIKey)
+	assert.Equal(t, StoreKeychain, stored.Store)
+	assert.Equal(t, "test keychain", CredentialsLocation(stored))
+}
+
+func TestSaveCredentials_FallsBackToFile(t *testing.T) {
+	k := &memoryKeychain{saveErr: assert.AnError}
+	useKeychain(t, k)
+
+	creds := testCredentials("")
+	require.NoError(t, SaveCredentials(creds))
+	assert.Equal(t, StoreFile, creds.Store)
+	assert.True(t, credentialsFileExists(t))
+
+	// An explicit keychain store fails instead
+	require.Error(t, SaveCredentials(testCredentials(StoreKeychain)))
+}
+
+func TestSaveCredentials_FileStore(t *testing.T) {
+	k := &memoryKeychain{data: []byte(`{"api_key":"old-key"}`)}
+	useKeychain(t, k)
+
+	require.NoError(t, SaveCredentials(testCredentials(StoreFile)))
+	assert.True(t, credentialsFileExists(t))
+	assert.Nil(t, k.data, "the keychain entry would shadow the file")
+
+	stored, err := ReadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "secret-key", stored.APIKey)
+	assert.Equal(t, StoreFile, stored.Store)
+
+	// Moving to the keychain removes the file
+	require.NoError(t, SaveCredentials(testCredentials(StoreKeychain)))
+	assert.False(t, credentialsFileExists(t))
+}
+
+func TestClearCredentials_BothStores(t *testing.T) {
+	k := &memoryKeychain{}
+	useKeychain(t, k)
+	require.NoError(t, SaveCredentials(testCredentials(StoreFile)))
+	k.data = []byte(`{}`)
+
+	require.NoError(t, ClearCredentials())
+	assert.Nil(t, k.data)
+	assert.False(t, credentialsFileExists(t))
+
+	stored, err := ReadCredentials()
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestValidateCredentialStore(t *testing.T) {
+	assert.NoError(t, ValidateCredentialStore(""))
+	assert.NoError(t, ValidateCredentialStore(StoreFile))
+	assert.Error(t, ValidateCredentialStore("vault"))
+	assert.Error(t, ValidateCredentialStore(StoreKeychain), "no keychain in tests")
+
+	useKeychain(t, &memoryKeychain{})
+	assert.NoError(t, ValidateCredentialStore(StoreKeychain))
+}
@@ -16,6 +16,13 @@ type Credentials struct {
 	CreatedAt time.Time `json:"created_at"`
 	ExpiresAt time.Time `json:"expires_at"`
 	KeyName   string    `json:"key_name"`
+
+	// ExpiryDays is the lifetime the key was created with; rotated keys get the same
+	ExpiryDays int `json:"expiry_days,omitempty"`
+
+	// Store is where the credentials are stored (StoreFile or StoreKeychain); empty
+	// saves to the default store
+	Store string `json:"-"`
 }
 
 // GetCredentialsPath returns the path to the credentials file
@@ -29,8 +36,46 @@ func GetCredentialsPath() (string, error) {
 	return filepath.Join(credDir, "credentials"), nil
 }
 
-// SaveCredentials saves the credentials to the credentials file
+// SaveCredentials saves the credentials to their store. Without a store, they are
+// saved to the OS keychain and, if that fails, to the credentials file.
 func SaveCredentials(creds *Credentials) error {
+	// Marshal credentials to JSON
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	switch creds.Store {
+	case StoreKeychain:
+		if keychain == nil {
+			return fmt.Errorf("no OS keychain available")
+		}
+		return saveToKeychain(data)
+	case "":
+		if keychain != nil {
+			err := saveToKeychain(data)
+			if err == nil {
+				creds.Store = StoreKeychain
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "⚠️  %v; using the credentials file\n", err)
+		}
+	}
+
+	if err := saveCredentialsFile(data); err != nil {
+		return err
+	}
+	creds.Store = StoreFile
+
+	// A keychain entry would take precedence over the file
+	if keychain != nil {
+		_ = keychain.clear()
+	}
+	return nil
+}
+
+// saveCredentialsFile writes data to the credentials file
+func saveCredentialsFile(data []byte) error {
 	credPath, err := GetCredentialsPath()
 	if err != nil {
 		return err
@@ -42,12 +87,6 @@ func SaveCredentials(creds *Credentials) error {
 		return fmt.Errorf("failed to create credentials directory: %w", err)
 	}
 
-	// Marshal credentials to JSON
-	data, err := json.MarshalIndent(creds, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal credentials: %w", err)
-	}
-
 	// Write to file with secure permissions (owner read/write only)
 	if err := os.WriteFile(credPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write credentials file: %w", err)
@@ -58,6 +97,50 @@ func SaveCredentials(creds *Credentials) error {
 
 // LoadCredentials loads the credentials from the credentials file
 func LoadCredentials() (*Credentials, error) {
+	creds, err := ReadCredentials()
+	if err != nil || creds == nil {
+		return nil, err
+	}
+
+	// Check if credentials have expired
+	if time.Now().After(creds.ExpiresAt) {
+		// Expired credentials, remove the file
+		_ = ClearCredentials()
+		return nil, fmt.Errorf("API key has expired on %s", creds.ExpiresAt.Format("2006-01-02"))
+	}
+
+	return creds, nil
+}
+
+// ReadCredentials reads the credentials from the OS keychain or, if it holds none,
+// the credentials file, without checking expiry or removing expired credentials. It
+// returns nil, nil if no credentials are stored.
+func ReadCredentials() (*Credentials, error) {
+	data, keychainErr := loadFromKeychain()
+	store := StoreKeychain
+	if data == nil {
+		fileData, err := readCredentialsFile()
+		if err != nil {
+			return nil, err
+		}
+		if fileData == nil {
+			return nil, keychainErr // No credentials, an error only if the keychain failed
+		}
+		data, store = fileData, StoreFile
+	}
+
+	// Unmarshal JSON
+	var creds Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials from %s: %w", store, err)
+	}
+	creds.Store = store
+
+	return &creds, nil
+}
+
+// readCredentialsFile returns the content of the credentials file, nil if it doesn't exist
+func readCredentialsFile() ([]byte, error) {
 	credPath, err := GetCredentialsPath()
 	if err != nil {
 		return nil, err
@@ -74,25 +157,21 @@ func LoadCredentials() (*Credentials, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read credentials file: %w", err)
 	}
+	return data, nil
+}
 
-	// Unmarshal JSON
-	var creds Credentials
-	if err := json.Unmarshal(data, &creds); err != nil {
-		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
-	}
-
-	// Check if credentials have expired
-	if time.Now().After(creds.ExpiresAt) {
-		// Expired credentials, remove the file
-		_ = ClearCredentials()
-		return nil, fmt.Errorf("API key has expired on %s", creds.ExpiresAt.Format("2006-01-02"))
+// ClearCredentials removes the credentials from the OS keychain and the credentials file
+func ClearCredentials() error {
+	if keychain != nil {
+		if err := keychain.clear(); err != nil {
+			return fmt.Errorf("failed to remove credentials from %s: %w", keychain, err)
+		}
 	}
-
-	return &creds, nil
+	return removeCredentialsFile()
 }
 
-// ClearCredentials removes the credentials file
-func ClearCredentials() error {
+// removeCredentialsFile removes the credentials file
+func removeCredentialsFile() error {
 	credPath, err := GetCredentialsPath()
 	if err != nil {
 		return err
@@ -0,0 +1,467 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/demo"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Doctor check statuses
+const (
+	DoctorOK   = "ok"
+	DoctorWarn = "warn"
+	DoctorFail = "fail"
+	DoctorSkip = "skip"
+)
+
+// credentialExpiryWarning is how long before expiry the doctor starts warning about an API key
+const credentialExpiryWarning = 7 * 24 * time.Hour
+
+// DoctorCheck is the result of one doctor check. Fix tells the user what to do when
+// the check did not pass.
+type DoctorCheck struct {
+	Name    string `json:"name" yaml:"name"`
+	Status  string `json:"status" yaml:"status"`
+	Message string `json:"message" yaml:"message"`
+	Fix     string `json:"fix,omitempty" yaml:"fix,omitempty"`
+}
+
+// doctorBinary is an external tool used by local commands or workflow steps
+type doctorBinary struct {
+	name     string
+	required bool
+	usedBy   string
+	install  string
+}
+
+var doctorBinaries = []doctorBinary{
+	{"kubectl", true, "demo commands and kubernetes workflow steps", "https://kubernetes.io/docs/tasks/tools/"},
+	{"helm", true, "demo-time and demo-nuke", "https://helm.sh/docs/intro/install/"},
+	{"git", true, "gitea-repo and git-commit-manifests steps", "https://git-scm.com/downloads"},
+	{"terraform", false, "terraform workflow steps", "https://developer.hashicorp.com/terraform/install"},
+	{"ansible-playbook", false, "ansible workflow steps", "https://docs.ansible.com/ansible/latest/installation_guide/"},
+}
+
+// doctor runs the environment checks. External lookups are fields so tests can
+// replace them.
+type doctor struct {
+	baseURL    string
+	token      string
+	tokenFrom  string // "env", "file" or ""
+	cliVersion string
+	httpClient *http.Client
+	lookPath   func(file string) (string, error)
+	runCommand func(name string, args ...string) (string, error)
+	lookupHost func(host string) ([]string, error)
+	readCreds  func() (*Credentials, error)
+	now        func() time.Time
+}
+
+func newDoctor(c *Client, cliVersion string) *doctor {
+	tokenFrom := ""
+	if os.Getenv("IDP_API_KEY") != "" {
+		tokenFrom = "env"
+	} else if c.token != "" {
+		tokenFrom = "file"
+	}
+
+	return &doctor{
+		baseURL:    c.baseURL,
+		token:      c.token,
+		tokenFrom:  tokenFrom,
+		cliVersion: cliVersion,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		lookPath:   exec.LookPath,
+		runCommand: func(name string, args ...string) (string, error) {
+			output, err := exec.Command(name, args...).CombinedOutput() // #nosec G204 - fixed diagnostic commands
+			return strings.TrimSpace(string(output)), err
+		},
+		lookupHost: net.LookupHost,
+		readCreds:  ReadCredentials,
+		now:        time.Now,
+	}
+}
+
+// run executes all checks in order
+func (d *doctor) run() []DoctorCheck {
+	var checks []DoctorCheck
+
+	serverCheck, serverVersion := d.checkServer()
+	checks = append(checks, serverCheck)
+	checks = append(checks, d.checkVersionSkew(serverCheck.Status != DoctorFail, serverVersion))
+	checks = append(checks, d.checkCredentials(serverCheck.Status != DoctorFail)...)
+	checks = append(checks, d.checkBinaries()...)
+	checks = append(checks, d.checkKubeContext())
+	checks = append(checks, d.checkDemoPrerequisites()...)
+
+	return checks
+}
+
+// checkServer verifies the server answers /health and returns its version
+func (d *doctor) checkServer() (DoctorCheck, string) {
+	check := DoctorCheck{Name: "server"}
+
+	resp, err := d.httpClient.Get(d.baseURL + "/health")
+	if err != nil {
+		check.Status = DoctorFail
+		check.Message = fmt.Sprintf("cannot reach %s: %v", d.baseURL, err)
+		check.Fix = "Start the server (./innominatus) or point the CLI at it with --server <url>"
+		return check, ""
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var health struct {
+		Status  string `json:"status"`
+		Version string `json:"version"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(body, &health); err != nil {
+		check.Status = DoctorFail
+		check.Message = fmt.Sprintf("%s/health did not return a health response (HTTP %d)", d.baseURL, resp.StatusCode)
+		check.Fix = "Check that --server points at an innominatus server, not a proxy or the web UI"
+		return check, ""
+	}
+
+	switch health.Status {
+	case "healthy":
+		check.Status = DoctorOK
+		check.Message = fmt.Sprintf("%s is healthy", d.baseURL)
+	case "degraded":
+		check.Status = DoctorWarn
+		check.Message = fmt.Sprintf("%s is degraded", d.baseURL)
+		check.Fix = fmt.Sprintf("Inspect the failing checks with: curl %s/health", d.baseURL)
+	default:
+		check.Status = DoctorFail
+		check.Message = fmt.Sprintf("%s is %s", d.baseURL, health.Status)
+		check.Fix = fmt.Sprintf("Inspect the failing checks with: curl %s/health (the database is the usual cause)", d.baseURL)
+	}
+	return check, health.Version
+}
+
+// checkVersionSkew applies the server's CLI version policy from /api/version, then
+// compares the CLI and server major.minor versions
+func (d *doctor) checkVersionSkew(serverReachable bool, serverVersion string) DoctorCheck {
+	check := DoctorCheck{Name: "version"}
+
+	if !serverReachable {
+		check.Status = DoctorSkip
+		check.Message = "server not reachable"
+		return check
+	}
+
+	if compat := d.fetchCompatibility(); compat != nil {
+		if !compat.Compatible {
+			check.Status = DoctorFail
+			check.Message = fmt.Sprintf("cli %s is not supported by the server (minimum %s)", d.cliVersion, compat.MinVersion)
+			check.Fix = fmt.Sprintf("Install innominatus-ctl %s or later", compat.MinVersion)
+			return check
+		}
+		if len(compat.Warnings) > 0 {
+			check.Status = DoctorWarn
+			check.Message = strings.Join(compat.Warnings, "; ")
+			check.Fix = fmt.Sprintf("Install innominatus-ctl %s to match the server", orUnknown(serverVersion))
+			if compat.RecommendedVersion != "" {
+				check.Fix = fmt.Sprintf("Install innominatus-ctl %s or later", compat.RecommendedVersion)
+			}
+			return check
+		}
+	}
+	if serverVersion == "" || serverVersion == "dev" || d.cliVersion == "" || d.cliVersion == "dev" {
+		check.Status = DoctorSkip
+		check.Message = fmt.Sprintf("development build (cli %s, server %s)", orUnknown(d.cliVersion), orUnknown(serverVersion))
+		return check
+	}
+
+	if majorMinor(d.cliVersion) != majorMinor(serverVersion) {
+		check.Status = DoctorWarn
+		check.Message = fmt.Sprintf("cli %s, server %s", d.cliVersion, serverVersion)
+		check.Fix = fmt.Sprintf("Install innominatus-ctl %s to match the server", serverVersion)
+		return check
+	}
+
+	check.Status = DoctorOK
+	check.Message = fmt.Sprintf("cli %s, server %s", d.cliVersion, serverVersion)
+	return check
+}
+
+// fetchCompatibility asks the server for its verdict on the CLI version. It returns
+// nil for servers that predate /api/version.
+func (d *doctor) fetchCompatibility() *CLICompatibility {
+	req, err := http.NewRequest("GET", d.baseURL+"/api/version", nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set(CLIVersionHeader, d.cliVersion)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var version ServerVersion
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&version) != nil {
+		return nil
+	}
+	return &version.CLI
+}
+
+// checkCredentials checks the stored API key's expiry and, if the server is
+// reachable, whether the server accepts it
+func (d *doctor) checkCredentials(serverReachable bool) []DoctorCheck {
+	creds := DoctorCheck{Name: "credentials"}
+
+	switch d.tokenFrom {
+	case "env":
+		creds.Status = DoctorOK
+		creds.Message = "API key from IDP_API_KEY"
+	default:
+		stored, err := d.readCreds()
+		switch {
+		case err != nil:
+			creds.Status = DoctorFail
+			creds.Message = err.Error()
+			creds.Fix = "Run innominatus-ctl logout, then innominatus-ctl login"
+			return []DoctorCheck{creds}
+		case stored == nil:
+			creds.Status = DoctorWarn
+			creds.Message = "no stored credentials"
+			creds.Fix = "Run innominatus-ctl login (or login --sso), or set IDP_API_KEY"
+			return []DoctorCheck{creds}
+		case d.now().After(stored.ExpiresAt):
+			creds.Status = DoctorFail
+			creds.Message = fmt.Sprintf("API key %q expired on %s", stored.KeyName, stored.ExpiresAt.Format("2006-01-02"))
+			creds.Fix = "Run innominatus-ctl login to create a new API key"
+			return []DoctorCheck{creds}
+		case stored.ExpiresAt.Sub(d.now()) < credentialExpiryWarning:
+			creds.Status = DoctorWarn
+			creds.Message = fmt.Sprintf("API key %q expires on %s", stored.KeyName, stored.ExpiresAt.Format("2006-01-02"))
+			creds.Fix = "Run any server command to rotate the API key automatically, or innominatus-ctl login"
+		default:
+			creds.Status = DoctorOK
+			creds.Message = fmt.Sprintf("API key %q valid until %s", stored.KeyName, stored.ExpiresAt.Format("2006-01-02"))
+		}
+
+		if stored.ServerURL != "" && strings.TrimSuffix(stored.ServerURL, "/") != strings.TrimSuffix(d.baseURL, "/") {
+			creds.Status = DoctorWarn
+			creds.Message += fmt.Sprintf(" (issued by %s, not %s)", stored.ServerURL, d.baseURL)
+			creds.Fix = fmt.Sprintf("Run innominatus-ctl --server %s login, or use --server %s", d.baseURL, stored.ServerURL)
+		}
+	}
+
+	auth := DoctorCheck{Name: "authentication"}
+	if !serverReachable {
+		auth.Status = DoctorSkip
+		auth.Message = "server not reachable"
+		return []DoctorCheck{creds, auth}
+	}
+
+	req, err := http.NewRequest("GET", d.baseURL+"/api/auth/whoami", nil)
+	if err != nil {
+		auth.Status = DoctorFail
+		auth.Message = err.Error()
+		return []DoctorCheck{creds, auth}
+	}
+	req.Header.Set("Authorization", "Bearer "+d.token)
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		auth.Status = DoctorFail
+		auth.Message = fmt.Sprintf("request failed: %v", err)
+		return []DoctorCheck{creds, auth}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var profile ProfileResponse
+	switch {
+	case resp.StatusCode == http.StatusOK && json.NewDecoder(resp.Body).Decode(&profile) == nil:
+		auth.Status = DoctorOK
+		auth.Message = fmt.Sprintf("authenticated as %s (team %s, role %s)", profile.Username, profile.Team, profile.Role)
+	case resp.StatusCode == http.StatusUnauthorized:
+		auth.Status = DoctorFail
+		auth.Message = "the server rejected the API key"
+		auth.Fix = "The key was revoked or belongs to another server; run innominatus-ctl login"
+	default:
+		auth.Status = DoctorFail
+		auth.Message = fmt.Sprintf("unexpected HTTP %d from /api/auth/whoami", resp.StatusCode)
+	}
+	return []DoctorCheck{creds, auth}
+}
+
+// checkBinaries checks the external tools used by local commands and workflow steps
+func (d *doctor) checkBinaries() []DoctorCheck {
+	checks := make([]DoctorCheck, 0, len(doctorBinaries))
+	for _, bin := range doctorBinaries {
+		check := DoctorCheck{Name: "binary:" + bin.name}
+		if path, err := d.lookPath(bin.name); err == nil {
+			check.Status = DoctorOK
+			check.Message = path
+		} else {
+			check.Status = DoctorWarn
+			if bin.required {
+				check.Status = DoctorFail
+			}
+			check.Message = fmt.Sprintf("not found in PATH (used by %s)", bin.usedBy)
+			check.Fix = fmt.Sprintf("Install %s: %s", bin.name, bin.install)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkKubeContext checks that the current kubectl context answers
+func (d *doctor) checkKubeContext() DoctorCheck {
+	check := DoctorCheck{Name: "kube-context"}
+
+	if _, err := d.lookPath("kubectl"); err != nil {
+		check.Status = DoctorSkip
+		check.Message = "kubectl not installed"
+		return check
+	}
+
+	kubeContext, err := d.runCommand("kubectl", "config", "current-context")
+	if err != nil || kubeContext == "" {
+		check.Status = DoctorFail
+		check.Message = "no current kubectl context"
+		check.Fix = "Select a cluster with: kubectl config use-context <name>"
+		return check
+	}
+
+	if _, err := d.runCommand("kubectl", "--context", kubeContext, "get", "namespaces", "--request-timeout=5s"); err != nil {
+		check.Status = DoctorFail
+		check.Message = fmt.Sprintf("context %q is not reachable or lacks access", kubeContext)
+		check.Fix = fmt.Sprintf("Check the cluster is running and your credentials work: kubectl --context %s get namespaces", kubeContext)
+		return check
+	}
+
+	check.Status = DoctorOK
+	check.Message = fmt.Sprintf("context %q reachable", kubeContext)
+	return check
+}
+
+// checkDemoPrerequisites checks what demo-time needs: the demo kube context and
+// wildcard DNS for the demo ingress hosts. Failures are warnings since the demo is optional.
+func (d *doctor) checkDemoPrerequisites() []DoctorCheck {
+	env := demo.NewDemoEnvironment()
+
+	kube := DoctorCheck{Name: "demo:kube-context"}
+	if _, err := d.lookPath("kubectl"); err != nil {
+		kube.Status = DoctorSkip
+		kube.Message = "kubectl not installed"
+	} else if _, err := d.runCommand("kubectl", "config", "get-contexts", env.KubeContext); err != nil {
+		kube.Status = DoctorWarn
+		kube.Message = fmt.Sprintf("kube context %q not found", env.KubeContext)
+		kube.Fix = "Enable Kubernetes in Docker Desktop (Settings > Kubernetes) to run demo-time"
+	} else {
+		kube.Status = DoctorOK
+		kube.Message = fmt.Sprintf("kube context %q available", env.KubeContext)
+	}
+
+	dns := DoctorCheck{Name: "demo:dns"}
+	host := "gitea." + env.BaseLocalDomain
+	addrs, err := d.lookupHost(host)
+	switch {
+	case err != nil:
+		dns.Status = DoctorWarn
+		dns.Message = fmt.Sprintf("%s does not resolve: %v", host, err)
+		dns.Fix = fmt.Sprintf("Allow public DNS for *.%s (it resolves to 127.0.0.1) or add %s to /etc/hosts", env.BaseLocalDomain, host)
+	case !containsLoopback(addrs):
+		dns.Status = DoctorWarn
+		dns.Message = fmt.Sprintf("%s resolves to %s, not 127.0.0.1", host, strings.Join(addrs, ", "))
+		dns.Fix = "A DNS filter may rewrite *." + env.BaseLocalDomain + "; add the demo hosts to /etc/hosts"
+	default:
+		dns.Status = DoctorOK
+		dns.Message = fmt.Sprintf("%s resolves to 127.0.0.1", host)
+	}
+
+	return []DoctorCheck{kube, dns}
+}
+
+// DoctorCommand checks the local environment and prints a fix for every problem.
+// It returns an error if any check failed.
+func (c *Client) DoctorCommand(cliVersion string) error {
+	checks := newDoctor(c, cliVersion).run()
+
+	failed := 0
+	for _, check := range checks {
+		if check.Status == DoctorFail {
+			failed++
+		}
+	}
+
+	switch {
+	case c.Formatter.IsJSON():
+		if err := c.Formatter.PrintJSON(checks); err != nil {
+			return err
+		}
+	case c.Formatter.IsYAML():
+		if err := c.Formatter.PrintYAML(checks); err != nil {
+			return err
+		}
+	default:
+		printDoctorChecks(c.Formatter, checks)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", failed)
+	}
+	return nil
+}
+
+func printDoctorChecks(formatter *OutputFormatter, checks []DoctorCheck) {
+	formatter.PrintHeader("🩺 innominatus-ctl doctor")
+
+	warnings := 0
+	for _, check := range checks {
+		icon := SymbolSuccess
+		switch check.Status {
+		case DoctorWarn:
+			icon = SymbolWarning
+			warnings++
+		case DoctorFail:
+			icon = SymbolError
+		case DoctorSkip:
+			icon = "-"
+		}
+		formatter.PrintItem(0, icon, fmt.Sprintf("%-20s %s", check.Name, check.Message))
+		if check.Fix != "" {
+			formatter.PrintItem(1, SymbolArrow, check.Fix)
+		}
+	}
+
+	formatter.PrintEmpty()
+	if warnings > 0 {
+		formatter.PrintInfo(fmt.Sprintf("%d warning(s)", warnings))
+	}
+}
+
+// majorMinor returns the major.minor part of a version such as v1.4.2
+func majorMinor(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return parts[0]
+	}
+	return parts[0] + "." + parts[1]
+}
+
+func containsLoopback(addrs []string) bool {
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip != nil && ip.IsLoopback() {
+			return true
+		}
+	}
+	return false
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDoctor(serverURL string) *doctor {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &doctor{
+		baseURL:    serverURL,
+		token:      "test-key",
+		tokenFrom:  "file",
+		cliVersion: "v1.4.0",
+		httpClient: &http.Client{Timeout: time.Second},
+		lookPath:   func(file string) (string, error) { return "/usr/local/bin/" + file, nil },
+		runCommand: func(name string, args ...string) (string, error) { return "docker-desktop", nil },
+		lookupHost: func(host string) ([]string, error) { return []string{"127.0.0.1"}, nil },
+		readCreds: func() (*Credentials, error) {
+			return &Credentials{ServerURL: serverURL, KeyName: "laptop", APIKey: "test-key", ExpiresAt: now.Add(30 * 24 * time.Hour)}, nil
+		},
+		now: func() time.Time { return now },
+	}
+}
+
+func doctorTestServer(t *testing.T, serverVersion string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			_, _ = fmt.Fprintf(w, `{"status":"healthy","version":%q}`, serverVersion)
+		case "/api/version":
+			if r.Header.Get(CLIVersionHeader) == "v1.0.0" {
+				_, _ = fmt.Fprintf(w, `{"version":%q,"cli":{"min_version":"v1.2.0","compatible":false}}`, serverVersion)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"version":%q,"cli":{"compatible":true}}`, serverVersion)
+		case "/api/auth/whoami":
+			if r.Header.Get("Authorization") != "Bearer test-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"username":"alice","team":"platform","role":"user"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func doctorStatuses(checks []DoctorCheck) map[string]string {
+	statuses := make(map[string]string)
+	for _, check := range checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestDoctorAllChecksPass(t *testing.T) {
+	server := doctorTestServer(t, "v1.4.2")
+
+	checks := newTestDoctor(server.URL).run()
+
+	for _, check := range checks {
+		assert.Equal(t, DoctorOK, check.Status, "%s: %s", check.Name, check.Message)
+		assert.Empty(t, check.Fix, check.Name)
+	}
+}
+
+func TestDoctorReportsProblemsWithFixes(t *testing.T) {
+	server := doctorTestServer(t, "v1.5.0")
+
+	d := newTestDoctor(server.URL)
+	d.token = "revoked-key"
+	d.lookPath = func(file string) (string, error) {
+		if file == "helm" || file == "terraform" {
+			return "", fmt.Errorf("not found")
+		}
+		return "/usr/bin/" + file, nil
+	}
+	d.lookupHost = func(host string) ([]string, error) { return []string{"203.0.113.7"}, nil }
+
+	checks := d.run()
+	statuses := doctorStatuses(checks)
+
+	assert.Equal(t, DoctorWarn, statuses["version"])
+	assert.Equal(t, DoctorFail, statuses["authentication"])
+	assert.Equal(t, DoctorFail, statuses["binary:helm"])
+	assert.Equal(t, DoctorWarn, statuses["binary:terraform"], "optional binaries only warn")
+	assert.Equal(t, DoctorWarn, statuses["demo:dns"])
+	for _, check := range checks {
+		if check.Status == DoctorWarn || check.Status == DoctorFail {
+			assert.NotEmpty(t, check.Fix, "%s should suggest a fix", check.Name)
+		}
+	}
+}
+
+func TestDoctorRejectedCLIVersion(t *testing.T) {
+	server := doctorTestServer(t, "v1.4.2")
+
+	d := newTestDoctor(server.URL)
+	d.cliVersion = "v1.0.0"
+	check := d.checkVersionSkew(true, "v1.4.2")
+
+	assert.Equal(t, DoctorFail, check.Status)
+	assert.Contains(t, check.Fix, "v1.2.0")
+}
+
+func TestDoctorServerUnreachable(t *testing.T) {
+	server := doctorTestServer(t, "v1.4.0")
+	url := server.URL
+	server.Close()
+
+	checks := newTestDoctor(url).run()
+	statuses := doctorStatuses(checks)
+
+	assert.Equal(t, DoctorFail, statuses["server"])
+	assert.Equal(t, DoctorSkip, statuses["version"])
+	assert.Equal(t, DoctorSkip, statuses["authentication"])
+}
+
+func TestDoctorCredentialExpiry(t *testing.T) {
+	server := doctorTestServer(t, "dev")
+
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		want      string
+	}{
+		{"valid", 30 * 24 * time.Hour, DoctorOK},
+		{"expiring soon", 2 * 24 * time.Hour, DoctorWarn},
+		{"expired", -time.Hour, DoctorFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDoctor(server.URL)
+			d.readCreds = func() (*Credentials, error) {
+				return &Credentials{ServerURL: server.URL, KeyName: "laptop", ExpiresAt: d.now().Add(tt.expiresIn)}, nil
+			}
+
+			checks := d.checkCredentials(true)
+			require.NotEmpty(t, checks)
+			assert.Equal(t, tt.want, checks[0].Status, checks[0].Message)
+		})
+	}
+}
+
+func TestMajorMinor(t *testing.T) {
+	assert.Equal(t, "1.4", majorMinor("v1.4.2"))
+	assert.Equal(t, "1.4", majorMinor("1.4.0-rc1"))
+	assert.Equal(t, "2", majorMinor("v2"))
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Exit codes of innominatus-ctl by error class, so scripts can tell e.g. an expired
+// API key from a rejected spec. Documented in docs/cli/exit-codes.md.
+const (
+	ExitOK           = 0
+	ExitError        = 1 // Any error not in a class below
+	ExitUsage        = 2 // Unknown command or flag, wrong arguments
+	ExitValidation   = 3 // Invalid input: local validation, or 400/422 from the server
+	ExitAuth         = 4 // Not logged in, invalid credentials or missing permission (401, 403)
+	ExitNotFound     = 5 // The application, workflow or resource does not exist (404)
+	ExitConflict     = 6 // The request conflicts with the current state (409, 412, 423)
+	ExitServer       = 7 // The server failed to handle the request (500 and other 5xx)
+	ExitUnavailable  = 8 // Server unreachable, overloaded or timing out (connection errors, 429, 502, 503, 504); retrying may help
+	ExitIncompatible = 9 // The server does not support this CLI version
+)
+
+// exitClasses names the error class of each exit code
+var exitClasses = map[int]string{
+	ExitOK:           "ok",
+	ExitError:        "error",
+	ExitUsage:        "usage",
+	ExitValidation:   "validation",
+	ExitAuth:         "auth",
+	ExitNotFound:     "not_found",
+	ExitConflict:     "conflict",
+	ExitServer:       "server",
+	ExitUnavailable:  "unavailable",
+	ExitIncompatible: "incompatible",
+}
+
+// ExitClass returns the name of the error class of an exit code
+func ExitClass(code int) string {
+	if class, ok := exitClasses[code]; ok {
+		return class
+	}
+	return "error"
+}
+
+// APIError is an error response of the server
+type APIError struct {
+	StatusCode int
+	Message    string // The error or message field of a JSON body, otherwise the body as text
+	summary    string // How the failed request is described, e.g. "server error (500)"
+}
+
+// newAPIError creates the error of a response with statusCode and body. summary
+// describes the failed request and prefixes the message.
+func newAPIError(statusCode int, body []byte, summary string) *APIError {
+	message := strings.TrimSpace(string(body))
+	var structured struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &structured) == nil {
+		if structured.Error != "" {
+			message = structured.Error
+		} else if structured.Message != "" {
+			message = structured.Message
+		}
+	}
+	return &APIError{StatusCode: statusCode, Message: message, summary: summary}
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return e.summary
+	}
+	return e.summary + ": " + e.Message
+}
+
+// ExitCode returns the exit code of the error class of the response status
+func (e *APIError) ExitCode() int {
+	switch e.StatusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return ExitValidation
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitAuth
+	case http.StatusNotFound, http.StatusGone:
+		return ExitNotFound
+	case http.StatusConflict, http.StatusPreconditionFailed, http.StatusLocked:
+		return ExitConflict
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return ExitUnavailable
+	case http.StatusUpgradeRequired:
+		return ExitIncompatible
+	}
+	if e.StatusCode >= 500 {
+		return ExitServer
+	}
+	return ExitError
+}
+
+// classifiedError assigns an exit code to an error that has no API response
+type classifiedError struct {
+	code int
+	err  error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+func (e *classifiedError) Unwrap() error { return e.err }
+
+// WithExitCode returns err with the exit code of its class. If err wraps a server
+// response, the response status decides the class instead.
+func WithExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{code: code, err: err}
+}
+
+// usageError returns a formatted error of the usage class
+func usageError(format string, args ...interface{}) error {
+	return WithExitCode(ExitUsage, fmt.Errorf(format, args...))
+}
+
+// validationError returns a formatted error of the validation class
+func validationError(format string, args ...interface{}) error {
+	return WithExitCode(ExitValidation, fmt.Errorf(format, args...))
+}
+
+// ExitCode returns the exit code for an error returned by a command
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ExitCode()
+	}
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return classified.code
+	}
+	// Connection errors and timeouts; *url.Error implements net.Error
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ExitUnavailable
+	}
+	return ExitError
+}
+
+// ErrorReport is the machine-readable form of a failed command, printed in quiet mode
+type ErrorReport struct {
+	Error    string `json:"error"`
+	Class    string `json:"class"`
+	ExitCode int    `json:"exit_code"`
+	Status   int    `json:"status,omitempty"` // HTTP status of the server response
+}
+
+// NewErrorReport describes err for scripts
+func NewErrorReport(err error) ErrorReport {
+	code := ExitCode(err)
+	report := ErrorReport{Error: err.Error(), Class: ExitClass(code), ExitCode: code}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		report.Status = apiErr.StatusCode
+	}
+	return report
+}
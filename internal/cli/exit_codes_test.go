@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_ExitCode(t *testing.T) {
+	tests := []struct {
+		status int
+		want   int
+	}{
+		{http.StatusBadRequest, ExitValidation},
+		{http.StatusUnprocessableEntity, ExitValidation},
+		{http.StatusUnauthorized, ExitAuth},
+		{http.StatusForbidden, ExitAuth},
+		{http.StatusNotFound, ExitNotFound},
+		{http.StatusConflict, ExitConflict},
+		{http.StatusTooManyRequests, ExitUnavailable},
+		{http.StatusServiceUnavailable, ExitUnavailable},
+		{http.StatusUpgradeRequired, ExitIncompatible},
+		{http.StatusInternalServerError, ExitServer},
+		{http.StatusTeapot, ExitError},
+	}
+	for _, tt := range tests {
+		err := newAPIError(tt.status, nil, fmt.Sprintf("status %d", tt.status))
+		assert.Equal(t, tt.want, ExitCode(err), "status %d", tt.status)
+		assert.Equal(t, tt.want, ExitCode(fmt.Errorf("wrapped: %w", err)), "wrapped status %d", tt.status)
+	}
+}
+
+func TestNewAPIError_Message(t *testing.T) {
+	err := newAPIError(http.StatusConflict, []byte(`{"error":"workflow already running"}`), "server error (409)")
+	assert.Equal(t, "workflow already running", err.Message)
+	assert.Equal(t, "server error (409): workflow already running", err.Error())
+
+	err = newAPIError(http.StatusBadRequest, []byte(`{"message":"name is required"}`), "bad request")
+	assert.Equal(t, "name is required", err.Message)
+
+	err = newAPIError(http.StatusNotFound, []byte("Application not found\n"), "not found (404)")
+	assert.Equal(t, "not found (404): Application not found", err.Error())
+}
+
+func TestExitCode_Classified(t *testing.T) {
+	assert.Equal(t, ExitOK, ExitCode(nil))
+	assert.Equal(t, ExitError, ExitCode(errors.New("boom")))
+	assert.Equal(t, ExitUsage, ExitCode(usageError("missing argument")))
+	assert.Equal(t, ExitValidation, ExitCode(fmt.Errorf("lint: %w", validationError("invalid spec"))))
+	assert.Nil(t, WithExitCode(ExitAuth, nil))
+
+	// The response status decides over the class of the caller
+	err := WithExitCode(ExitValidation, newAPIError(http.StatusUnauthorized, nil, "unauthorized"))
+	assert.Equal(t, ExitAuth, ExitCode(err))
+}
+
+func TestExitCode_ConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	helper := newHTTPHelper(url, &http.Client{Timeout: time.Second}, "")
+	err := helper.GET("/api/test", nil)
+	require.Error(t, err)
+	assert.Equal(t, ExitUnavailable, ExitCode(err))
+}
+
+func TestExitCode_HTTPHelper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	helper := newHTTPHelper(server.URL, &http.Client{Timeout: 5 * time.Second}, "")
+	err := helper.GET("/api/test", nil)
+	require.Error(t, err)
+	assert.Equal(t, ExitAuth, ExitCode(err))
+
+	report := NewErrorReport(err)
+	assert.Equal(t, "auth", report.Class)
+	assert.Equal(t, ExitAuth, report.ExitCode)
+	assert.Equal(t, http.StatusUnauthorized, report.Status)
+}
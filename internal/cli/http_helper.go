@@ -10,9 +10,10 @@ import (
 
 // HTTPHelper provides common HTTP request functionality for the CLI client
 type HTTPHelper struct {
-	baseURL string
-	client  *http.Client
-	token   string
+	baseURL    string
+	client     *http.Client
+	token      string
+	cliVersion string // Sent in the X-Innominatus-CLI-Version header when set
 }
 
 // newHTTPHelper creates a new HTTP helper instance
@@ -31,6 +32,14 @@ func (h *HTTPHelper) setAuthHeader(req *http.Request) {
 	}
 }
 
+// setClientHeaders adds the CLI version and Authorization headers
+func (h *HTTPHelper) setClientHeaders(req *http.Request) {
+	if h.cliVersion != "" {
+		req.Header.Set(CLIVersionHeader, h.cliVersion)
+	}
+	h.setAuthHeader(req)
+}
+
 // doRequest performs a generic HTTP request and unmarshals the response into result
 // This eliminates the repetitive request/response handling code
 func (h *HTTPHelper) doRequest(method, path string, body io.Reader, contentType string, result interface{}) error {
@@ -46,7 +55,7 @@ func (h *HTTPHelper) doRequest(method, path string, body io.Reader, contentType
 		req.Header.Set("Content-Type", contentType)
 	}
 	req.Header.Set("Accept", "application/json")
-	h.setAuthHeader(req)
+	h.setClientHeaders(req)
 
 	// Execute request
 	resp, err := h.client.Do(req)
@@ -64,9 +73,9 @@ func (h *HTTPHelper) doRequest(method, path string, body io.Reader, contentType
 	// Check for error status codes
 	if resp.StatusCode >= 400 {
 		if resp.StatusCode == http.StatusNotFound {
-			return fmt.Errorf("not found (404): %s", string(respBody))
+			return newAPIError(resp.StatusCode, respBody, "not found (404)")
 		}
-		return fmt.Errorf("server error (%d): %s", resp.StatusCode, string(respBody))
+		return newAPIError(resp.StatusCode, respBody, fmt.Sprintf("server error (%d)", resp.StatusCode))
 	}
 
 	// Unmarshal response if result is provided
@@ -115,7 +124,7 @@ func (h *HTTPHelper) doRequestWithStatus(method, path string, body io.Reader, co
 		req.Header.Set("Content-Type", contentType)
 	}
 	req.Header.Set("Accept", "application/json")
-	h.setAuthHeader(req)
+	h.setClientHeaders(req)
 
 	// Execute request
 	resp, err := h.client.Do(req)
@@ -133,9 +142,9 @@ func (h *HTTPHelper) doRequestWithStatus(method, path string, body io.Reader, co
 	// Check for expected status code
 	if resp.StatusCode != expectedStatus {
 		if resp.StatusCode == http.StatusNotFound {
-			return fmt.Errorf("not found (404): %s", string(respBody))
+			return newAPIError(resp.StatusCode, respBody, "not found (404)")
 		}
-		return fmt.Errorf("unexpected status %d (expected %d): %s", resp.StatusCode, expectedStatus, string(respBody))
+		return newAPIError(resp.StatusCode, respBody, fmt.Sprintf("unexpected status %d (expected %d)", resp.StatusCode, expectedStatus))
 	}
 
 	// Unmarshal response if result is provided
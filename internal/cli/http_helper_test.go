@@ -275,6 +275,17 @@ func TestHTTPHelper_setAuthHeader(t *testing.T) {
 	})
 }
 
+func TestHTTPHelper_setClientHeaders(t *testing.T) {
+	helper := newHTTPHelper("http://test.com", &http.Client{}, "test-token")
+	helper.cliVersion = "v1.4.0"
+
+	req, _ := http.NewRequest("GET", "http://test.com/api/test", nil)
+	helper.setClientHeaders(req)
+
+	assert.Equal(t, "v1.4.0", req.Header.Get(CLIVersionHeader))
+	assert.Equal(t, "Bearer test-token", req.Header.Get("Authorization"))
+}
+
 func TestHTTPHelper_NetworkError(t *testing.T) {
 	t.Run("handles network connection error", func(t *testing.T) {
 		// Use invalid URL that will fail to connect
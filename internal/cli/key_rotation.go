@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// KeyRotationWindow is how long before its expiry the CLI replaces a stored API key
+const KeyRotationWindow = 14 * 24 * time.Hour
+
+// defaultKeyExpiryDays is the lifetime of a minted key when the stored one has none
+const defaultKeyExpiryDays = 90
+
+// rotationLockTimeout is how long a lock file of another CLI process blocks rotation
+const rotationLockTimeout = time.Minute
+
+var keyNameTimestamp = regexp.MustCompile(`-\d{9,}$`)
+
+// errRotationLocked is returned while another CLI process rotates the key
+var errRotationLocked = errors.New("API key rotation in progress")
+
+// NeedsRotation returns true if the API key expires within KeyRotationWindow
+func (creds *Credentials) NeedsRotation(now time.Time) bool {
+	return creds.ExpiresAt.Sub(now) < KeyRotationWindow
+}
+
+// keyExpiryDays returns the lifetime of the stored key, so a replacement lives as long
+func (creds *Credentials) keyExpiryDays() int {
+	if creds.ExpiryDays > 0 {
+		return creds.ExpiryDays
+	}
+	if !creds.CreatedAt.IsZero() && creds.ExpiresAt.After(creds.CreatedAt) {
+		if days := int(creds.ExpiresAt.Sub(creds.CreatedAt).Hours()/24 + 0.5); days > 0 {
+			return days
+		}
+	}
+	return defaultKeyExpiryDays
+}
+
+// rotatedKeyName returns the name of the replacement of key name, e.g.
+// cli-laptop-1700000000 becomes cli-laptop-1707776000
+func rotatedKeyName(name string, now time.Time) string {
+	if name == "" {
+		hostname, _ := os.Hostname()
+		if hostname == "" {
+			hostname = "unknown"
+		}
+		name = "cli-" + hostname
+	}
+	return fmt.Sprintf("%s-%d", keyNameTimestamp.ReplaceAllString(name, ""), now.Unix())
+}
+
+// mintAPIKey creates an API key with the client's current credential and returns
+// the credentials to store for it
+func (c *Client) mintAPIKey(username, keyName string, expiryDays int) (*Credentials, error) {
+	req := map[string]interface{}{
+		"name":        keyName,
+		"expiry_days": expiryDays,
+	}
+
+	var resp map[string]interface{}
+	if err := c.http.POST("/api/profile/api-keys", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	apiKey, ok := resp["key"].(string)
+	if !ok || apiKey == "" {
+		return nil, fmt.Errorf("server did not return API key")
+	}
+
+	createdAtStr, _ := resp["created_at"].(string)
+	expiresAtStr, _ := resp["expires_at"].(string)
+	createdAt, _ := time.Parse(time.RFC3339, createdAtStr)
+	expiresAt, _ := time.Parse(time.RFC3339, expiresAtStr)
+
+	return &Credentials{
+		ServerURL:  c.baseURL,
+		Username:   username,
+		APIKey:     apiKey,
+		CreatedAt:  createdAt,
+		ExpiresAt:  expiresAt,
+		KeyName:    keyName,
+		ExpiryDays: expiryDays,
+	}, nil
+}
+
+// useCredentials makes the client authenticate with creds
+func (c *Client) useCredentials(creds *Credentials) {
+	c.creds = creds
+	c.token = creds.APIKey
+	c.http.token = creds.APIKey
+}
+
+// RotateCredentials replaces the stored API key if it expires within KeyRotationWindow:
+// it mints a new key with the current one, saves it to the credentials file and then
+// revokes the old key. It returns the new credentials, or nil if the client does not
+// use stored credentials of this server or no rotation is due.
+func (c *Client) RotateCredentials() (*Credentials, error) {
+	if c.creds == nil || !c.creds.NeedsRotation(time.Now()) || !sameServer(c.creds.ServerURL, c.baseURL) {
+		return nil, nil
+	}
+
+	unlock, err := lockCredentials()
+	if errors.Is(err, errRotationLocked) {
+		return nil, nil // The other process saves the new key; ours stays valid until expiry
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// Another process may have rotated the key since this one loaded it
+	stored, err := ReadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil && stored.APIKey != c.creds.APIKey && !stored.NeedsRotation(time.Now()) {
+		c.useCredentials(stored)
+		return nil, nil
+	}
+
+	old := c.creds
+	creds, err := c.mintAPIKey(old.Username, rotatedKeyName(old.KeyName, time.Now()), old.keyExpiryDays())
+	if err != nil {
+		return nil, err
+	}
+	creds.Store = old.Store
+	if err := SaveCredentials(creds); err != nil {
+		return nil, fmt.Errorf("failed to save credentials (API key '%s' was created but not stored): %w", creds.KeyName, err)
+	}
+	c.useCredentials(creds)
+
+	// The old key expires soon anyway, so a failed revocation does not fail the rotation
+	if old.KeyName != "" {
+		if err := c.http.DELETE("/api/profile/api-keys/" + url.PathEscape(old.KeyName)); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to revoke old API key '%s': %v\n", old.KeyName, err)
+		}
+	}
+
+	return creds, nil
+}
+
+// RenewCredentials stores a new API key after an interactive login replaced expired
+// stored credentials. It returns nil if the client had no expired credentials.
+func (c *Client) RenewCredentials(username string) (*Credentials, error) {
+	expired := c.expiredCreds
+	if expired == nil || !sameServer(expired.ServerURL, c.baseURL) {
+		return nil, nil
+	}
+
+	creds, err := c.mintAPIKey(username, rotatedKeyName(expired.KeyName, time.Now()), expired.keyExpiryDays())
+	if err != nil {
+		return nil, err
+	}
+	creds.Store = expired.Store
+	if err := SaveCredentials(creds); err != nil {
+		return nil, fmt.Errorf("failed to save credentials: %w", err)
+	}
+	c.expiredCreds = nil
+	c.useCredentials(creds)
+	return creds, nil
+}
+
+// sameServer returns true if credentials issued by stored can be used for baseURL
+func sameServer(stored, baseURL string) bool {
+	return stored == "" || strings.TrimSuffix(stored, "/") == strings.TrimSuffix(baseURL, "/")
+}
+
+// lockCredentials creates a lock file next to the credentials file, so concurrent CLI
+// processes (e.g. parallel CI jobs) don't rotate the same key twice. Lock files older
+// than rotationLockTimeout are left over by crashed processes and are replaced.
+func lockCredentials() (func(), error) {
+	credPath, err := GetCredentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(credPath), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+	lockPath := credPath + ".lock"
+
+	for attempt := 0; attempt < 2; attempt++ {
+		// #nosec G304 - lockPath is constructed from os.UserHomeDir() + fixed path, no user input
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock credentials: %w", err)
+		}
+		info, statErr := os.Stat(lockPath)
+		if statErr != nil || time.Since(info.ModTime()) < rotationLockTimeout {
+			return nil, errRotationLocked
+		}
+		_ = os.Remove(lockPath)
+	}
+	return nil, errRotationLocked
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keyServer fakes the profile API key endpoints
+type keyServer struct {
+	mu      sync.Mutex
+	created []map[string]interface{}
+	revoked []string
+	auth    []string
+}
+
+func (k *keyServer) handler(w http.ResponseWriter, r *http.Request) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.auth = append(k.auth, r.Header.Get("Authorization"))
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/profile/api-keys":
+		var req map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		k.created = append(k.created, req)
+		days := int(req["expiry_days"].(float64))
+		now := time.Now().UTC().Truncate(time.Second)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"key":        "new-key",
+			"name":       req["name"],
+			"created_at": now.Format(time.RFC3339),
+			"expires_at": now.AddDate(0, 0, days).Format(time.RFC3339),
+		})
+	case r.Method == http.MethodDelete:
+		k.revoked = append(k.revoked, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func setupCredentials(t *testing.T, serverURL string, expiresIn time.Duration) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("IDP_API_KEY", "")
+	now := time.Now()
+	require.NoError(t, SaveCredentials(&Credentials{
+		ServerURL: serverURL,
+		Username:  "alice",
+		APIKey:    "old-key",
+		CreatedAt: now.Add(expiresIn).AddDate(0, 0, -30),
+		ExpiresAt: now.Add(expiresIn),
+		KeyName:   "cli-laptop-1700000000",
+	}))
+}
+
+func TestRotateCredentials(t *testing.T) {
+	keys := &keyServer{}
+	server := httptest.NewServer(http.HandlerFunc(keys.handler))
+	defer server.Close()
+	setupCredentials(t, server.URL, 3*24*time.Hour)
+
+	client := NewClient(server.URL)
+	rotated, err := client.RotateCredentials()
+	require.NoError(t, err)
+	require.NotNil(t, rotated)
+
+	// The new key is minted with the old one and keeps its lifetime
+	require.Len(t, keys.created, 1)
+	assert.Equal(t, float64(30), keys.created[0]["expiry_days"])
+	assert.Regexp(t, `^cli-laptop-\d+$`, keys.created[0]["name"])
+	assert.NotEqual(t, "cli-laptop-1700000000", keys.created[0]["name"])
+	assert.Equal(t, "Bearer old-key", keys.auth[0])
+
+	// The old key is revoked with the new one
+	assert.Equal(t, []string{"/api/profile/api-keys/cli-laptop-1700000000"}, keys.revoked)
+	assert.Equal(t, "Bearer new-key", keys.auth[1])
+
+	stored, err := ReadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "new-key", stored.APIKey)
+	assert.Equal(t, "alice", stored.Username)
+	assert.Equal(t, 30, stored.ExpiryDays)
+	assert.False(t, stored.NeedsRotation(time.Now()))
+
+	// The lock file is removed
+	credPath, _ := GetCredentialsPath()
+	_, err = os.Stat(credPath + ".lock")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRotateCredentials_NotDue(t *testing.T) {
+	keys := &keyServer{}
+	server := httptest.NewServer(http.HandlerFunc(keys.handler))
+	defer server.Close()
+
+	t.Run("key valid beyond the window", func(t *testing.T) {
+		setupCredentials(t, server.URL, 60*24*time.Hour)
+		rotated, err := NewClient(server.URL).RotateCredentials()
+		require.NoError(t, err)
+		assert.Nil(t, rotated)
+	})
+
+	t.Run("key issued by another server", func(t *testing.T) {
+		setupCredentials(t, "https://other.example.com", 24*time.Hour)
+		rotated, err := NewClient(server.URL).RotateCredentials()
+		require.NoError(t, err)
+		assert.Nil(t, rotated)
+	})
+
+	t.Run("key from environment", func(t *testing.T) {
+		setupCredentials(t, server.URL, 24*time.Hour)
+		t.Setenv("IDP_API_KEY", "env-key")
+		rotated, err := NewClient(server.URL).RotateCredentials()
+		require.NoError(t, err)
+		assert.Nil(t, rotated)
+	})
+
+	t.Run("another process holds the lock", func(t *testing.T) {
+		setupCredentials(t, server.URL, 24*time.Hour)
+		credPath, _ := GetCredentialsPath()
+		require.NoError(t, os.WriteFile(credPath+".lock", nil, 0600))
+		rotated, err := NewClient(server.URL).RotateCredentials()
+		require.NoError(t, err)
+		assert.Nil(t, rotated)
+	})
+
+	assert.Empty(t, keys.created)
+}
+
+func TestRotateCredentials_StaleLock(t *testing.T) {
+	keys := &keyServer{}
+	server := httptest.NewServer(http.HandlerFunc(keys.handler))
+	defer server.Close()
+	setupCredentials(t, server.URL, 24*time.Hour)
+
+	credPath, _ := GetCredentialsPath()
+	require.NoError(t, os.WriteFile(credPath+".lock", nil, 0600))
+	stale := time.Now().Add(-2 * rotationLockTimeout)
+	require.NoError(t, os.Chtimes(credPath+".lock", stale, stale))
+
+	rotated, err := NewClient(server.URL).RotateCredentials()
+	require.NoError(t, err)
+	require.NotNil(t, rotated)
+}
+
+func TestRenewCredentials(t *testing.T) {
+	keys := &keyServer{}
+	server := httptest.NewServer(http.HandlerFunc(keys.handler))
+	defer server.Close()
+	setupCredentials(t, server.URL, -time.Hour)
+
+	client := NewClient(server.URL)
+	assert.False(t, client.HasToken(), "expired credentials are not used")
+
+	renewed, err := client.RenewCredentials("alice")
+	require.NoError(t, err)
+	require.NotNil(t, renewed)
+	assert.Empty(t, keys.revoked, "expired keys need no revocation")
+
+	stored, err := ReadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "new-key", stored.APIKey)
+	assert.True(t, client.HasToken())
+}
+
+func TestRotatedKeyName(t *testing.T) {
+	now := time.Unix(1800000000, 0)
+	assert.Equal(t, "cli-laptop-1800000000", rotatedKeyName("cli-laptop-1700000000", now))
+	assert.Equal(t, "ci-deployer-1800000000", rotatedKeyName("ci-deployer", now))
+}
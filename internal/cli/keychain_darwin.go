@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// macKeychain stores credentials in the login keychain with the security tool
+type macKeychain struct{}
+
+// securityItemNotFound is the exit code of security when no item matches
+const securityItemNotFound = 44
+
+func newKeychain() credentialBackend {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil
+	}
+	return macKeychain{}
+}
+
+func (macKeychain) String() string { return "macOS Keychain" }
+
+func (macKeychain) load() ([]byte, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w").Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == securityItemNotFound {
+		return nil, errKeychainNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(out), nil
+}
+
+func (macKeychain) save(data []byte) error {
+	// Pass the secret on stdin in interactive mode, so it never appears in the process list
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n", keychainService, keychainAccount, hex.EncodeToString(data)))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (macKeychain) clear() error {
+	err := exec.Command("security", "delete-generic-password", "-s", keychainService, "-a", keychainAccount).Run()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == securityItemNotFound {
+		return nil
+	}
+	return err
+}
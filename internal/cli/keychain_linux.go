@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// secretService stores credentials with the Secret Service API (GNOME Keyring, KWallet)
+// through secret-tool from libsecret
+type secretService struct{}
+
+func newKeychain() credentialBackend {
+	// Headless machines such as CI runners have no session bus and use the file store
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return nil
+	}
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil
+	}
+	return secretService{}
+}
+
+func (secretService) String() string { return "Secret Service" }
+
+func (secretService) attributes() []string {
+	return []string{"service", keychainService, "account", keychainAccount}
+}
+
+func (s secretService) load() ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", append([]string{"lookup"}, s.attributes()...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	// secret-tool exits with 1 and prints nothing if no item matches
+	if _, ok := err.(*exec.ExitError); ok && len(out) == 0 && stderr.Len() == 0 {
+		return nil, errKeychainNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return bytes.TrimSpace(out), nil
+}
+
+func (s secretService) save(data []byte) error {
+	args := append([]string{"store", "--label=innominatus-ctl credentials"}, s.attributes()...)
+	cmd := exec.Command("secret-tool", args...)
+	cmd.Stdin = bytes.NewReader(data)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (s secretService) clear() error {
+	// secret-tool clear succeeds if no item matches
+	return exec.Command("secret-tool", append([]string{"clear"}, s.attributes()...)...).Run()
+}
@@ -0,0 +1,8 @@
+//go:build !darwin && !linux && !windows
+
+package cli
+
+// newKeychain returns nil: credentials are stored in the file on other systems
+func newKeychain() credentialBackend {
+	return nil
+}
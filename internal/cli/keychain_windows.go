@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+// winCredentialManager stores credentials as a generic credential of the Windows
+// Credential Manager
+type winCredentialManager struct{}
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredRead   = advapi32.NewProc("CredReadW")
+	procCredWrite  = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+// credential is the CREDENTIALW structure
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+func newKeychain() credentialBackend {
+	if procCredWrite.Find() != nil {
+		return nil
+	}
+	return winCredentialManager{}
+}
+
+func (winCredentialManager) String() string { return "Windows Credential Manager" }
+
+func (winCredentialManager) target() *uint16 {
+	target, _ := syscall.UTF16PtrFromString(keychainService + ":" + keychainAccount)
+	return target
+}
+
+func (w winCredentialManager) load() ([]byte, error) {
+	var cred *credential
+	r, _, err := procCredRead.Call(uintptr(unsafe.Pointer(w.target())), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		if errors.Is(err, errorNotFound) {
+			return nil, errKeychainNotFound
+		}
+		return nil, err
+	}
+	defer func() { _, _, _ = procCredFree.Call(uintptr(unsafe.Pointer(cred))) }()
+
+	data := make([]byte, cred.CredentialBlobSize)
+	copy(data, unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize))
+	return data, nil
+}
+
+func (w winCredentialManager) save(data []byte) error {
+	user, _ := syscall.UTF16PtrFromString(keychainAccount)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         w.target(),
+		CredentialBlobSize: uint32(len(data)), // #nosec G115 - credentials are a few hundred bytes
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(data) > 0 {
+		cred.CredentialBlob = &data[0]
+	}
+	if r, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return err
+	}
+	return nil
+}
+
+func (w winCredentialManager) clear() error {
+	r, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(w.target())), credTypeGeneric, 0)
+	if r == 0 && !errors.Is(err, errorNotFound) {
+		return err
+	}
+	return nil
+}
@@ -18,6 +18,20 @@ const (
 	OutputFormatYAML OutputFormat = "yaml"
 )
 
+// quiet suppresses the status messages of every formatter, see SetQuiet
+var quiet bool
+
+// SetQuiet turns quiet mode on or off. In quiet mode, success, info, warning and error
+// messages are not printed, so the output only contains the command's result.
+func SetQuiet(enabled bool) {
+	quiet = enabled
+}
+
+// IsQuiet returns true in quiet mode
+func IsQuiet() bool {
+	return quiet
+}
+
 // OutputFormatter provides standardized formatting for CLI output
 type OutputFormatter struct {
 	useEmojis bool
@@ -118,6 +132,9 @@ func (f *OutputFormatter) PrintSubHeader(title string) {
 
 // PrintSuccess prints a success message
 func (f *OutputFormatter) PrintSuccess(message string) {
+	if quiet {
+		return
+	}
 	if f.useEmojis {
 		fmt.Printf("%s %s\n", SymbolSuccess, message)
 	} else {
@@ -127,6 +144,9 @@ func (f *OutputFormatter) PrintSuccess(message string) {
 
 // PrintError prints an error message
 func (f *OutputFormatter) PrintError(message string) {
+	if quiet {
+		return
+	}
 	if f.useEmojis {
 		fmt.Printf("%s %s\n", SymbolError, message)
 	} else {
@@ -136,6 +156,9 @@ func (f *OutputFormatter) PrintError(message string) {
 
 // PrintWarning prints a warning message
 func (f *OutputFormatter) PrintWarning(message string) {
+	if quiet {
+		return
+	}
 	if f.useEmojis {
 		fmt.Printf("%s %s\n", SymbolWarning, message)
 	} else {
@@ -145,6 +168,9 @@ func (f *OutputFormatter) PrintWarning(message string) {
 
 // PrintInfo prints an info message
 func (f *OutputFormatter) PrintInfo(message string) {
+	if quiet {
+		return
+	}
 	if f.useEmojis {
 		fmt.Printf("%s %s\n", SymbolInfo, message)
 	} else {
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DevProvider is a provider registered through the development channel of the server
+type DevProvider struct {
+	Name         string    `json:"name" yaml:"name"`
+	Version      string    `json:"version" yaml:"version"`
+	Provisioners int       `json:"provisioners" yaml:"provisioners"`
+	Workflows    int       `json:"workflows" yaml:"workflows"`
+	Revision     int       `json:"revision" yaml:"revision"`
+	RegisteredBy string    `json:"registered_by" yaml:"registered_by"`
+	RegisteredAt time.Time `json:"registered_at" yaml:"registered_at"`
+}
+
+// RegisterDevProvider uploads the files of a provider, by path relative to its directory,
+// to the development channel of the server. Validation errors are returned as *APIError
+// with status 422.
+func (c *Client) RegisterDevProvider(files map[string][]byte) (*DevProvider, error) {
+	var registration DevProvider
+	request := map[string]interface{}{"files": files}
+	if err := c.http.PUT("/api/admin/providers/dev", request, &registration); err != nil {
+		return nil, err
+	}
+	return &registration, nil
+}
+
+// UnregisterDevProvider removes a provider registered through the development channel
+func (c *Client) UnregisterDevProvider(name string) error {
+	return c.http.DELETE("/api/admin/providers/dev/" + url.PathEscape(name))
+}
+
+// ProviderDevCommand registers the provider in dir with the server and registers it again
+// whenever one of its files changes, printing validation errors as they occur. The
+// provider is unregistered when the command is interrupted.
+func (c *Client) ProviderDevCommand(dir string, interval time.Duration) error {
+	if c.token == "" {
+		return fmt.Errorf("authentication required: please login first with './innominatus-ctl login'")
+	}
+	if _, err := providerManifestPath(dir); err != nil {
+		return err
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c.Formatter.PrintHeader(fmt.Sprintf("Developing provider %s", dir))
+	c.Formatter.PrintInfo("Watching for changes, press Ctrl-C to stop")
+	return c.providerDev(ctx, dir, interval)
+}
+
+// providerDev registers the provider in dir on every change until ctx is done
+func (c *Client) providerDev(ctx context.Context, dir string, interval time.Duration) error {
+	registered := ""
+	fingerprint := ""
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		files, current, err := collectProviderFiles(dir)
+		switch {
+		case err != nil:
+			if current != fingerprint {
+				c.Formatter.PrintError(err.Error())
+			}
+		case current != fingerprint:
+			registration, err := c.RegisterDevProvider(files)
+			var apiErr *APIError
+			switch {
+			case errors.As(err, &apiErr) && apiErr.StatusCode == 422:
+				c.Formatter.PrintError(fmt.Sprintf("%s  Provider invalid, previous revision still registered:\n%s", time.Now().Format("15:04:05"), apiErr.Message))
+			case err != nil:
+				// Retried on the next tick, e.g. while the server restarts
+				c.Formatter.PrintError(fmt.Sprintf("Failed to register provider: %v", err))
+				current = ""
+			default:
+				registered = registration.Name
+				c.Formatter.PrintSuccess(fmt.Sprintf("%s  Registered %s v%s (revision %d): %d workflow(s)",
+					time.Now().Format("15:04:05"), registration.Name, registration.Version, registration.Revision, registration.Workflows))
+			}
+		}
+		fingerprint = current
+
+		select {
+		case <-ctx.Done():
+			if registered == "" {
+				return nil
+			}
+			if err := c.UnregisterDevProvider(registered); err != nil {
+				return fmt.Errorf("failed to unregister provider %s: %w", registered, err)
+			}
+			c.Formatter.PrintInfo(fmt.Sprintf("Unregistered %s", registered))
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectProviderFiles reads the files of a provider directory, skipping hidden files
+// and directories such as .git. The fingerprint changes whenever a file is added,
+// removed or modified.
+func collectProviderFiles(dir string) (map[string][]byte, string, error) {
+	files := map[string][]byte{}
+	var entries []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path) // #nosec G304 - files of the provider being developed
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		files[rel] = data
+		entries = append(entries, fmt.Sprintf("%s %d %d", rel, info.Size(), info.ModTime().UnixNano()))
+		return nil
+	})
+	sort.Strings(entries)
+	fingerprint := strings.Join(entries, "\n")
+	if err != nil {
+		return nil, fingerprint, fmt.Errorf("failed to read provider files: %w", err)
+	}
+	return files, fingerprint, nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderDevReRegistersOnChange(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "provider.yaml"), []byte("metadata:\n  name: demo\n"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0600))
+
+	var mu sync.Mutex
+	var calls []string
+	uploads := make(chan map[string][]byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		revision := len(calls)
+		mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/admin/providers/dev":
+			var req struct {
+				Files map[string][]byte `json:"files"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			uploads <- req.Files
+			if strings.Contains(string(req.Files["provider.yaml"]), "broken") {
+				http.Error(w, "workflow 'deploy': file not found", http.StatusUnprocessableEntity)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"name":"demo","version":"1.0.0","revision":%d}`, revision)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/admin/providers/dev/demo":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- NewClient(server.URL).providerDev(ctx, dir, 10*time.Millisecond) }()
+
+	receive := func() map[string][]byte {
+		t.Helper()
+		select {
+		case files := <-uploads:
+			return files
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for an upload")
+			return nil
+		}
+	}
+
+	files := receive()
+	assert.Equal(t, []string{"provider.yaml"}, devFileNames(files), "hidden directories are not uploaded")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "provider.yaml"), []byte("metadata:\n  name: demo\n# broken\n"), 0600))
+	assert.Contains(t, string(receive()["provider.yaml"]), "broken")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Demo"), 0600))
+	assert.Equal(t, []string{"README.md", "provider.yaml"}, devFileNames(receive()), "added files are uploaded")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "provider.yaml"), []byte("metadata:\n  name: demo\n"), 0600))
+	assert.NotContains(t, string(receive()["provider.yaml"]), "broken")
+
+	cancel()
+	require.NoError(t, <-done)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "DELETE /api/admin/providers/dev/demo", calls[len(calls)-1])
+}
+
+func devFileNames(files map[string][]byte) []string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
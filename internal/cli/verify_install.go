@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SmokeTestGoldenPath is the bundled golden path verify-install runs
+const SmokeTestGoldenPath = "smoke-test"
+
+// smokeTestApplication is the application the smoke test runs as; it is deleted afterwards
+const smokeTestApplication = "innominatus-smoke"
+
+// smokeTestSpec is the Score spec the smoke test runs with
+var smokeTestSpec = []byte(`apiVersion: score.dev/v1b1
+metadata:
+  name: ` + smokeTestApplication + `
+containers:
+  app:
+    image: nginx:1.27-alpine
+`)
+
+// Verify check statuses
+const (
+	VerifyPass = "pass"
+	VerifyFail = "fail"
+	VerifySkip = "skip"
+)
+
+// VerifyCheck is one row of the verify-install matrix
+type VerifyCheck struct {
+	Name     string `json:"name" yaml:"name"`
+	Status   string `json:"status" yaml:"status"`
+	Message  string `json:"message,omitempty" yaml:"message,omitempty"`
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+}
+
+// VerifyInstallResult is the outcome of a verify-install run
+type VerifyInstallResult struct {
+	GoldenPath  string        `json:"golden_path" yaml:"golden_path"`
+	ExecutionID int64         `json:"execution_id,omitempty" yaml:"execution_id,omitempty"`
+	Passed      bool          `json:"passed" yaml:"passed"`
+	Checks      []VerifyCheck `json:"checks" yaml:"checks"`
+}
+
+// GoldenPathRun is the response to starting a golden path run
+type GoldenPathRun struct {
+	Status      string `json:"status"`
+	Message     string `json:"message"`
+	Application string `json:"application"`
+	ExecutionID int64  `json:"execution_id"`
+	TaskID      string `json:"task_id"`
+}
+
+// ExecuteGoldenPath starts a golden path run with a Score spec. The run is usually queued;
+// follow it with GetQueueTask and GetWorkflowDetail.
+func (c *Client) ExecuteGoldenPath(pathName string, yamlContent []byte, params map[string]string) (*GoldenPathRun, error) {
+	query := url.Values{}
+	for key, value := range params {
+		query.Set("param."+key, value)
+	}
+
+	var result GoldenPathRun
+	path := "/api/workflows/golden-paths/" + url.PathEscape(pathName) + "/execute"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	if err := c.http.doYAMLRequest("POST", path, yamlContent, &result); err != nil {
+		return nil, fmt.Errorf("failed to start golden path: %w", err)
+	}
+	return &result, nil
+}
+
+// VerifyInstallCommand runs the smoke-test golden path (create repository, deploy a sample
+// app, check its URL, tear everything down) and prints a pass/fail matrix of its steps
+func (c *Client) VerifyInstallCommand(params map[string]string, timeout time.Duration) error {
+	if c.token == "" {
+		return fmt.Errorf("authentication required: please login first with './innominatus-ctl login'")
+	}
+
+	result := c.verifyInstall(params, timeout, 2*time.Second)
+
+	switch {
+	case c.Formatter.IsJSON():
+		if err := c.Formatter.PrintJSON(result); err != nil {
+			return err
+		}
+	case c.Formatter.IsYAML():
+		if err := c.Formatter.PrintYAML(result); err != nil {
+			return err
+		}
+	default:
+		printVerifyInstall(c.Formatter, result)
+	}
+
+	if !result.Passed {
+		failed := 0
+		for _, check := range result.Checks {
+			if check.Status == VerifyFail {
+				failed++
+			}
+		}
+		return fmt.Errorf("installation verification failed: %d check(s) failed", failed)
+	}
+	return nil
+}
+
+// verifyInstall runs the smoke test and collects its checks
+func (c *Client) verifyInstall(params map[string]string, timeout, interval time.Duration) *VerifyInstallResult {
+	result := &VerifyInstallResult{GoldenPath: SmokeTestGoldenPath}
+	deadline := time.Now().Add(timeout)
+	fail := func(name string, err error) *VerifyInstallResult {
+		result.Checks = append(result.Checks, VerifyCheck{Name: name, Status: VerifyFail, Message: err.Error()})
+		return result
+	}
+
+	if err := c.http.GET("/health", nil); err != nil {
+		return fail("server", err)
+	}
+	result.Checks = append(result.Checks, VerifyCheck{Name: "server", Status: VerifyPass, Message: c.baseURL})
+
+	run, err := c.ExecuteGoldenPath(SmokeTestGoldenPath, smokeTestSpec, params)
+	if err != nil {
+		return fail("start", err)
+	}
+
+	// Queued runs get their execution once a worker picks them up
+	executionID := run.ExecutionID
+	for executionID == 0 && run.TaskID != "" {
+		task, err := c.GetQueueTask(run.TaskID)
+		if err != nil {
+			return fail("start", err)
+		}
+		switch {
+		case task.ExecutionID != 0:
+			executionID = task.ExecutionID
+		case task.Status == "failed":
+			return fail("start", fmt.Errorf("queue task %s failed: %s", run.TaskID, task.Error))
+		case time.Now().After(deadline):
+			return fail("start", fmt.Errorf("no worker started the run within %s", timeout))
+		default:
+			time.Sleep(interval)
+		}
+	}
+	if executionID == 0 {
+		return fail("start", fmt.Errorf("server did not return an execution (status %s)", run.Status))
+	}
+	result.ExecutionID = executionID
+
+	detail, err := c.waitForExecution(executionID, deadline, interval)
+	if detail != nil {
+		result.Checks = append(result.Checks, verifyStepChecks(detail)...)
+	}
+	if err != nil {
+		fail("run", err)
+	}
+
+	// The smoke test removes what it created; only the application record is left
+	if err := c.DeleteApplication(smokeTestApplication); err != nil {
+		fail("cleanup", err)
+	} else {
+		result.Checks = append(result.Checks, VerifyCheck{Name: "cleanup", Status: VerifyPass, Message: "application " + smokeTestApplication + " deleted"})
+	}
+
+	result.Passed = true
+	for _, check := range result.Checks {
+		if check.Status == VerifyFail {
+			result.Passed = false
+		}
+	}
+	return result
+}
+
+// waitForExecution polls an execution until it finished or the deadline passed. The last
+// detail is returned with the error so unfinished steps still show up in the matrix.
+func (c *Client) waitForExecution(executionID int64, deadline time.Time, interval time.Duration) (*WorkflowExecutionDetail, error) {
+	for {
+		detail, err := c.GetWorkflowDetail(strconv.FormatInt(executionID, 10))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get workflow execution %d: %w", executionID, err)
+		}
+		if detail.Status == "completed" || detail.Status == "failed" {
+			return detail, nil
+		}
+		if time.Now().After(deadline) {
+			return detail, fmt.Errorf("execution %d did not finish in time (status %s)", executionID, detail.Status)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// verifyStepChecks turns the steps of the smoke test execution into checks. Steps that did
+// not run are skipped; rollback steps of a failed run are listed after them.
+func verifyStepChecks(detail *WorkflowExecutionDetail) []VerifyCheck {
+	checks := make([]VerifyCheck, 0, len(detail.Steps))
+	for _, step := range detail.Steps {
+		check := VerifyCheck{Name: step.StepName}
+		switch step.Status {
+		case "completed":
+			check.Status = VerifyPass
+		case "failed":
+			check.Status = VerifyFail
+			if step.ErrorMessage != nil {
+				check.Message = *step.ErrorMessage
+			}
+		default:
+			check.Status = VerifySkip
+			check.Message = step.Status
+		}
+		if step.DurationMs != nil {
+			check.Duration = (time.Duration(*step.DurationMs) * time.Millisecond).String()
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func printVerifyInstall(formatter *OutputFormatter, result *VerifyInstallResult) {
+	formatter.PrintHeader("🔎 innominatus-ctl verify-install")
+	if result.ExecutionID != 0 {
+		formatter.PrintKeyValue(0, "Execution", result.ExecutionID)
+	}
+
+	for _, check := range result.Checks {
+		icon := SymbolSuccess
+		switch check.Status {
+		case VerifyFail:
+			icon = SymbolError
+		case VerifySkip:
+			icon = "-"
+		}
+		line := fmt.Sprintf("%-20s %-4s", check.Name, check.Status)
+		if check.Duration != "" {
+			line += " " + check.Duration
+		}
+		formatter.PrintItem(0, icon, line)
+		if check.Message != "" && check.Status != VerifyPass {
+			formatter.PrintItem(1, SymbolArrow, check.Message)
+		}
+	}
+
+	formatter.PrintEmpty()
+	if result.Passed {
+		formatter.PrintSuccess("Installation verified")
+	} else if result.ExecutionID != 0 {
+		formatter.PrintError(fmt.Sprintf("Installation verification failed (see: innominatus-ctl workflow logs %d)", result.ExecutionID))
+	} else {
+		formatter.PrintError("Installation verification failed")
+	}
+}
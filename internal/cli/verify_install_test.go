@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verifyTestServer serves a queued smoke-test run whose execution ends with status and steps
+func verifyTestServer(t *testing.T, status, steps string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/health":
+			_, _ = fmt.Fprint(w, `{"status":"healthy"}`)
+		case r.URL.Path == "/api/workflows/golden-paths/smoke-test/execute":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), "name: innominatus-smoke") || r.URL.Query().Get("param.host") != "smoke.example.com" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = fmt.Fprint(w, `{"status":"enqueued","task_id":"task-1"}`)
+		case r.URL.Path == "/api/queue/tasks/task-1":
+			_, _ = fmt.Fprint(w, `{"id":"task-1","status":"running","execution_id":7}`)
+		case r.URL.Path == "/api/workflows/7":
+			_, _ = fmt.Fprintf(w, `{"id":7,"status":%q,"steps":%s}`, status, steps)
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/applications/innominatus-smoke":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func verifyStatuses(result *VerifyInstallResult) map[string]string {
+	statuses := make(map[string]string)
+	for _, check := range result.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestVerifyInstallPasses(t *testing.T) {
+	server, calls := verifyTestServer(t, "completed", `[
+		{"step_number":1,"step_name":"create-repo","status":"completed","duration_ms":1200},
+		{"step_number":2,"step_name":"verify-url","status":"completed"},
+		{"step_number":3,"step_name":"deprovision","status":"completed"}]`)
+
+	result := NewClient(server.URL).verifyInstall(map[string]string{"host": "smoke.example.com"}, time.Minute, time.Millisecond)
+
+	assert.True(t, result.Passed)
+	assert.Equal(t, int64(7), result.ExecutionID)
+	assert.Equal(t, map[string]string{
+		"server": VerifyPass, "create-repo": VerifyPass, "verify-url": VerifyPass, "deprovision": VerifyPass, "cleanup": VerifyPass,
+	}, verifyStatuses(result))
+	assert.Equal(t, "1.2s", result.Checks[1].Duration)
+	assert.Contains(t, *calls, "DELETE /api/applications/innominatus-smoke")
+}
+
+func TestVerifyInstallReportsFailedSteps(t *testing.T) {
+	server, _ := verifyTestServer(t, "failed", `[
+		{"step_number":1,"step_name":"create-repo","status":"completed"},
+		{"step_number":2,"step_name":"verify-url","status":"failed","error_message":"http-check did not pass within 3m0s"},
+		{"step_number":3,"step_name":"deprovision","status":"pending"},
+		{"step_number":4,"step_name":"rollback-create-repo","status":"completed"}]`)
+
+	result := NewClient(server.URL).verifyInstall(map[string]string{"host": "smoke.example.com"}, time.Minute, time.Millisecond)
+
+	assert.False(t, result.Passed)
+	statuses := verifyStatuses(result)
+	assert.Equal(t, VerifyFail, statuses["verify-url"])
+	assert.Equal(t, VerifySkip, statuses["deprovision"])
+	assert.Equal(t, VerifyPass, statuses["rollback-create-repo"])
+	assert.Equal(t, VerifyPass, statuses["cleanup"], "the application is deleted after a failed run too")
+	assert.Equal(t, "http-check did not pass within 3m0s", result.Checks[2].Message)
+}
+
+func TestVerifyInstallServerDown(t *testing.T) {
+	t.Setenv("IDP_API_KEY", "test-key")
+	client := NewClient("http://127.0.0.1:1")
+
+	err := client.VerifyInstallCommand(nil, time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 check(s) failed")
+}
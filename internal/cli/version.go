@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CLIVersionHeader carries the CLI version on every request so the server can negotiate
+// compatibility
+const CLIVersionHeader = "X-Innominatus-CLI-Version"
+
+// ServerVersion is the /api/version response
+type ServerVersion struct {
+	Version   string           `json:"version"`
+	Commit    string           `json:"commit"`
+	BuildDate string           `json:"build_date"`
+	GoVersion string           `json:"go_version"`
+	CLI       CLICompatibility `json:"cli"`
+}
+
+// CLICompatibility is the server's verdict on this CLI's version
+type CLICompatibility struct {
+	MinVersion         string   `json:"min_version,omitempty"`
+	RecommendedVersion string   `json:"recommended_version,omitempty"`
+	ClientVersion      string   `json:"client_version,omitempty"`
+	Compatible         bool     `json:"compatible"`
+	Warnings           []string `json:"warnings,omitempty"`
+}
+
+// GetServerVersion fetches the server build info and its compatibility verdict for
+// this CLI version
+func (c *Client) GetServerVersion() (*ServerVersion, error) {
+	var version ServerVersion
+	if err := c.http.GET("/api/version", &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// CheckServerCompatibility performs the version handshake before a server command.
+// Warnings are printed to stderr; an error is returned only when the server rejects
+// this CLI version. Servers without /api/version predate the handshake and are
+// accepted silently.
+func (c *Client) CheckServerCompatibility() error {
+	version, err := c.GetServerVersion()
+	if err != nil {
+		return nil
+	}
+
+	for _, warning := range version.CLI.Warnings {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+	}
+
+	if !version.CLI.Compatible {
+		msg := fmt.Sprintf("innominatus-ctl %s is not supported by server %s", orUnknown(c.cliVersion), orUnknown(version.Version))
+		if version.CLI.MinVersion != "" {
+			msg += fmt.Sprintf(" (minimum %s)", version.CLI.MinVersion)
+		}
+		return fmt.Errorf("%s; upgrade innominatus-ctl or rerun with --skip-version-check", msg)
+	}
+
+	return nil
+}
+
+// VersionCommand prints the CLI version and, when reachable, the server build info
+func (c *Client) VersionCommand(cliVersion, commit string) error {
+	server, serverErr := c.GetServerVersion()
+
+	if c.Formatter.IsJSON() || c.Formatter.IsYAML() {
+		output := map[string]interface{}{
+			"cli": map[string]string{"version": cliVersion, "commit": commit},
+		}
+		if serverErr == nil {
+			output["server"] = server
+		}
+		if c.Formatter.IsJSON() {
+			return c.Formatter.PrintJSON(output)
+		}
+		return c.Formatter.PrintYAML(output)
+	}
+
+	c.Formatter.PrintKeyValue(0, "CLI", fmt.Sprintf("%s (commit %s)", cliVersion, commit))
+	if serverErr != nil {
+		c.Formatter.PrintKeyValue(0, "Server", fmt.Sprintf("unavailable (%v)", serverErr))
+		return nil
+	}
+
+	c.Formatter.PrintKeyValue(0, "Server", fmt.Sprintf("%s (commit %s, built %s, %s)",
+		server.Version, orUnknown(server.Commit), orUnknown(server.BuildDate), server.GoVersion))
+	switch {
+	case !server.CLI.Compatible:
+		c.Formatter.PrintKeyValue(0, "Compatibility", fmt.Sprintf("unsupported, minimum %s", server.CLI.MinVersion))
+	case len(server.CLI.Warnings) > 0:
+		c.Formatter.PrintKeyValue(0, "Compatibility", strings.Join(server.CLI.Warnings, "; "))
+	default:
+		c.Formatter.PrintKeyValue(0, "Compatibility", "ok")
+	}
+	return nil
+}
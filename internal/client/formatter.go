@@ -64,6 +64,14 @@ func (f *WatchFormatter) FormatEvent(event Event) string {
 		errorMsg := f.getString(event.Data, "error")
 		message = fmt.Sprintf("Resource failed: %s - %s", resourceName, errorMsg)
 
+	case "resource.terminating":
+		resourceName := f.getString(event.Data, "resource_name")
+		message = fmt.Sprintf("Deprovisioning resource: %s", resourceName)
+
+	case "resource.terminated":
+		resourceName := f.getString(event.Data, "resource_name")
+		message = fmt.Sprintf("Resource deprovisioned: %s", resourceName)
+
 	case "provider.resolved":
 		providerName := f.getString(event.Data, "provider_name")
 		resourceType := f.getString(event.Data, "resource_type")
@@ -83,6 +91,18 @@ func (f *WatchFormatter) FormatEvent(event Event) string {
 		workflowName := f.getString(event.Data, "workflow_name")
 		message = fmt.Sprintf("Workflow completed: %s", workflowName)
 
+	case "workflow.progress":
+		percent := f.getInt(event.Data, "percent")
+		currentStep := f.getString(event.Data, "current_step")
+		message = fmt.Sprintf("Workflow progress: %d%%", percent)
+		if currentStep != "" {
+			message += fmt.Sprintf(" (%s)", currentStep)
+		}
+		if _, ok := event.Data["remaining_seconds"]; ok {
+			remaining := time.Duration(f.getInt(event.Data, "remaining_seconds")) * time.Second
+			message += fmt.Sprintf(", about %s left", remaining)
+		}
+
 	case "workflow.failed":
 		workflowName := f.getString(event.Data, "workflow_name")
 		errorMsg := f.getString(event.Data, "error")
@@ -0,0 +1,60 @@
+// Package clock abstracts time so TTLs, retention and schedulers can be tested
+// deterministically. Production code uses Real(); tests and debugging sessions use a
+// Fake that only moves when told to.
+package clock
+
+import "time"
+
+// Clock provides the current time and timers
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	// After fires once d has elapsed on this clock
+	After(d time.Duration) <-chan time.Time
+	// NewTicker fires every d on this clock. name identifies the ticker in timer listings.
+	NewTicker(name string, d time.Duration) Ticker
+}
+
+// Ticker delivers ticks on C until stopped
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// TimerInfo describes a pending timer or ticker
+type TimerInfo struct {
+	Name     string        `json:"name"`
+	Kind     string        `json:"kind"` // timer or ticker
+	Interval time.Duration `json:"interval_ns,omitempty"`
+	NextFire time.Time     `json:"next_fire"`
+}
+
+type realClock struct{}
+
+// Real returns the wall clock
+func Real() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) NewTicker(_ string, d time.Duration) Ticker {
+	return &realTicker{ticker: time.NewTicker(d)}
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t *realTicker) C() <-chan time.Time { return t.ticker.C }
+func (t *realTicker) Stop()               { t.ticker.Stop() }
+
+// OrReal returns c, or the wall clock if c is nil
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real()
+	}
+	return c
+}
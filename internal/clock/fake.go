@@ -0,0 +1,135 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a manually advanced clock. Timers and tickers fire only when Advance or
+// Set moves the clock past their deadline, which makes time-based behaviour
+// (TTL expiry, retention, scheduler runs) reproducible.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	name     string
+	deadline time.Time
+	interval time.Duration // zero for one-shot timers
+	ch       chan time.Time
+	stopped  bool
+}
+
+// NewFake creates a fake clock set to start
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now returns the fake time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since returns the fake time elapsed since t
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After returns a channel that receives once the clock reaches now+d
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.addWaiter("after", d, 0).ch
+}
+
+// NewTicker returns a ticker that fires each time the clock passes another interval
+func (f *Fake) NewTicker(name string, d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	return &fakeTicker{clock: f, waiter: f.addWaiter(name, d, d)}
+}
+
+// Advance moves the clock forward by d and fires every timer that becomes due
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the clock to t and fires every timer that becomes due. Moving backwards
+// changes Now but fires nothing.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = t
+	remaining := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.stopped {
+			continue
+		}
+		if !w.deadline.After(t) {
+			// Like time.Ticker, drop ticks nobody has consumed instead of blocking
+			select {
+			case w.ch <- w.deadline:
+			default:
+			}
+			if w.interval == 0 {
+				continue
+			}
+			for !w.deadline.After(t) {
+				w.deadline = w.deadline.Add(w.interval)
+			}
+		}
+		remaining = append(remaining, w)
+	}
+	f.waiters = remaining
+}
+
+// Timers lists pending timers and tickers ordered by next fire time
+func (f *Fake) Timers() []TimerInfo {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	timers := make([]TimerInfo, 0, len(f.waiters))
+	for _, w := range f.waiters {
+		if w.stopped {
+			continue
+		}
+		kind := "timer"
+		if w.interval > 0 {
+			kind = "ticker"
+		}
+		timers = append(timers, TimerInfo{Name: w.name, Kind: kind, Interval: w.interval, NextFire: w.deadline})
+	}
+	sort.Slice(timers, func(i, j int) bool { return timers[i].NextFire.Before(timers[j].NextFire) })
+	return timers
+}
+
+func (f *Fake) addWaiter(name string, d, interval time.Duration) *fakeWaiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{name: name, deadline: f.now.Add(d), interval: interval, ch: make(chan time.Time, 1)}
+	if d <= 0 && interval == 0 {
+		w.ch <- f.now
+		return w
+	}
+	f.waiters = append(f.waiters, w)
+	return w
+}
+
+type fakeTicker struct {
+	clock  *Fake
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.waiter.ch }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.waiter.stopped = true
+}
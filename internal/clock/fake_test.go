@@ -0,0 +1,74 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func fired(ch <-chan time.Time) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestFakeAfter(t *testing.T) {
+	c := NewFake(epoch)
+	ch := c.After(time.Hour)
+
+	c.Advance(59 * time.Minute)
+	assert.False(t, fired(ch))
+
+	c.Advance(time.Minute)
+	assert.True(t, fired(ch))
+	assert.Empty(t, c.Timers(), "one-shot timers are removed once fired")
+	assert.Equal(t, epoch.Add(time.Hour), c.Now())
+}
+
+func TestFakeTicker(t *testing.T) {
+	c := NewFake(epoch)
+	ticker := c.NewTicker("scheduler", 10*time.Second)
+
+	c.Advance(10 * time.Second)
+	assert.True(t, fired(ticker.C()))
+	assert.False(t, fired(ticker.C()))
+
+	// A large jump delivers one tick and schedules the next interval after now
+	c.Advance(35 * time.Second)
+	assert.True(t, fired(ticker.C()))
+	assert.False(t, fired(ticker.C()))
+
+	timers := c.Timers()
+	require.Len(t, timers, 1)
+	assert.Equal(t, "scheduler", timers[0].Name)
+	assert.Equal(t, "ticker", timers[0].Kind)
+	assert.Equal(t, epoch.Add(50*time.Second), timers[0].NextFire)
+
+	ticker.Stop()
+	c.Advance(time.Minute)
+	assert.False(t, fired(ticker.C()))
+	assert.Empty(t, c.Timers())
+}
+
+func TestFakeSetBackwardsFiresNothing(t *testing.T) {
+	c := NewFake(epoch)
+	ch := c.After(time.Minute)
+
+	c.Set(epoch.Add(-time.Hour))
+	assert.False(t, fired(ch))
+	assert.Equal(t, epoch.Add(-time.Hour), c.Now())
+	assert.Equal(t, 2*time.Hour, c.Since(epoch.Add(-3*time.Hour)))
+}
+
+func TestFakeAfterNonPositive(t *testing.T) {
+	c := NewFake(epoch)
+	assert.True(t, fired(c.After(0)))
+	assert.Empty(t, c.Timers())
+}
@@ -0,0 +1,281 @@
+// Package cloudcreds brokers short-lived cloud credentials for workflow steps. The server
+// federates its own workload identity (IRSA on EKS, workload identity on GKE, workload
+// identity federation on AKS) into credentials scoped to the provider or resource type a
+// step provisions, so admin-config needs no long-lived cloud keys.
+package cloudcreds
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Clouds credentials can be issued for
+const (
+	CloudAWS   = "aws"
+	CloudGCP   = "gcp"
+	CloudAzure = "azure"
+)
+
+// DefaultDuration is how long issued credentials are valid unless a grant sets duration
+const DefaultDuration = 15 * time.Minute
+
+// Lifetime bounds of the cloud token APIs
+const (
+	minAWSDuration = 15 * time.Minute
+	maxAWSDuration = 12 * time.Hour
+	maxGCPDuration = time.Hour
+)
+
+const requestTimeout = 30 * time.Second
+
+// Config is the workflowPolicies.cloudCredentials section of admin-config.yaml
+type Config struct {
+	Grants []Grant `yaml:"grants" json:"grants"`
+}
+
+// Grant gives the steps provisioning matching providers or resource types credentials of
+// one cloud identity
+type Grant struct {
+	Name  string `yaml:"name" json:"name"`
+	Cloud string `yaml:"cloud" json:"cloud"` // aws, gcp or azure
+
+	// Selection: a step gets the grant when it provisions one of these providers or
+	// resource types and, if stepTypes is set, has one of these types
+	Providers     []string `yaml:"providers" json:"providers,omitempty"`
+	ResourceTypes []string `yaml:"resourceTypes" json:"resourceTypes,omitempty"`
+	StepTypes     []string `yaml:"stepTypes" json:"stepTypes,omitempty"`
+
+	Duration string `yaml:"duration" json:"duration,omitempty"` // Credential lifetime (default 15m); not supported for azure
+
+	// AWS: role assumed with the server's IRSA token
+	RoleARN       string `yaml:"roleArn" json:"roleArn,omitempty"`
+	Region        string `yaml:"region" json:"region,omitempty"`
+	SessionPolicy string `yaml:"sessionPolicy" json:"sessionPolicy,omitempty"` // Inline IAM policy narrowing the role further
+
+	// GCP: service account impersonated with the server's workload identity
+	ServiceAccount string   `yaml:"serviceAccount" json:"serviceAccount,omitempty"`
+	Scopes         []string `yaml:"scopes" json:"scopes,omitempty"` // Default cloud-platform
+	Project        string   `yaml:"project" json:"project,omitempty"`
+
+	// Azure: app registration whose federated credential trusts the server's service account
+	TenantID       string `yaml:"tenantId" json:"tenantId,omitempty"`
+	ClientID       string `yaml:"clientId" json:"clientId,omitempty"`
+	SubscriptionID string `yaml:"subscriptionId" json:"subscriptionId,omitempty"`
+	Scope          string `yaml:"scope" json:"scope,omitempty"` // Default https://management.azure.com/.default
+}
+
+// Validate checks every grant
+func (c Config) Validate() error {
+	names := make(map[string]bool, len(c.Grants))
+	for i, grant := range c.Grants {
+		if grant.Name == "" {
+			return fmt.Errorf("cloudCredentials.grants[%d].name is required", i)
+		}
+		if names[grant.Name] {
+			return fmt.Errorf("cloudCredentials grant '%s' is defined twice", grant.Name)
+		}
+		names[grant.Name] = true
+		if err := grant.validate(); err != nil {
+			return fmt.Errorf("cloudCredentials grant '%s': %w", grant.Name, err)
+		}
+	}
+	return nil
+}
+
+func (g Grant) validate() error {
+	if len(g.Providers) == 0 && len(g.ResourceTypes) == 0 {
+		return fmt.Errorf("providers or resourceTypes is required")
+	}
+
+	switch g.Cloud {
+	case CloudAWS:
+		if g.RoleARN == "" {
+			return fmt.Errorf("roleArn is required for aws")
+		}
+	case CloudGCP:
+		if g.ServiceAccount == "" {
+			return fmt.Errorf("serviceAccount is required for gcp")
+		}
+	case CloudAzure:
+		if g.TenantID == "" || g.ClientID == "" {
+			return fmt.Errorf("tenantId and clientId are required for azure")
+		}
+		if g.Duration != "" {
+			return fmt.Errorf("duration is not supported for azure; Entra ID decides the token lifetime")
+		}
+	default:
+		return fmt.Errorf("cloud must be aws, gcp or azure, got '%s'", g.Cloud)
+	}
+
+	_, err := g.duration()
+	return err
+}
+
+// duration returns the credential lifetime, applying the default and the cloud's bounds
+func (g Grant) duration() (time.Duration, error) {
+	if g.Duration == "" {
+		return DefaultDuration, nil
+	}
+	d, err := time.ParseDuration(g.Duration)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration '%s'", g.Duration)
+	}
+	switch {
+	case g.Cloud == CloudAWS && (d < minAWSDuration || d > maxAWSDuration):
+		return 0, fmt.Errorf("duration must be between %s and %s for aws, got %s", minAWSDuration, maxAWSDuration, d)
+	case g.Cloud == CloudGCP && d > maxGCPDuration:
+		return 0, fmt.Errorf("duration must be at most %s for gcp, got %s", maxGCPDuration, d)
+	}
+	return d, nil
+}
+
+// Target is what a step provisions
+type Target struct {
+	App          string
+	Provider     string
+	ResourceType string
+	StepType     string
+	StepName     string
+}
+
+// matches reports whether the grant applies to a step
+func (g Grant) matches(t Target) bool {
+	if !contains(g.Providers, t.Provider) && !contains(g.ResourceTypes, t.ResourceType) {
+		return false
+	}
+	return len(g.StepTypes) == 0 || contains(g.StepTypes, t.StepType)
+}
+
+func contains(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the grants for a step in config order. Only the first grant of each cloud
+// applies, so a step never receives two conflicting identities of one cloud.
+func (c Config) Match(t Target) []Grant {
+	var grants []Grant
+	clouds := make(map[string]bool)
+	for _, grant := range c.Grants {
+		if clouds[grant.Cloud] || !grant.matches(t) {
+			continue
+		}
+		clouds[grant.Cloud] = true
+		grants = append(grants, grant)
+	}
+	return grants
+}
+
+// Credentials are issued for one step
+type Credentials struct {
+	Grant     string
+	Cloud     string
+	Env       map[string]string // Environment variables of the step's processes
+	ExpiresAt time.Time
+}
+
+// Endpoints of the cloud token APIs; tests point them at local servers
+type Endpoints struct {
+	AWSSTS            string // Default https://sts.<region>.amazonaws.com (https://sts.amazonaws.com without region)
+	GCPMetadata       string // Default http://metadata.google.internal
+	GCPIAMCredentials string // Default https://iamcredentials.googleapis.com
+	AzureLogin        string // Default https://login.microsoftonline.com
+}
+
+// Broker issues credentials for the grants of a Config
+type Broker struct {
+	config         Config
+	endpoints      Endpoints
+	client         *http.Client
+	awsTokenFile   string // Projected service account token exchanged with AWS STS (IRSA)
+	azureTokenFile string // Projected service account token exchanged with Entra ID
+}
+
+// NewBroker validates config and creates a broker that reads the server's identity
+// tokens from AWS_WEB_IDENTITY_TOKEN_FILE and AZURE_FEDERATED_TOKEN_FILE. GCP uses the
+// GKE metadata server.
+func NewBroker(config Config) (*Broker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &Broker{
+		config:         config,
+		client:         &http.Client{Timeout: requestTimeout},
+		awsTokenFile:   os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"),
+		azureTokenFile: os.Getenv("AZURE_FEDERATED_TOKEN_FILE"),
+	}, nil
+}
+
+// SetEndpoints overrides the cloud token APIs
+func (b *Broker) SetEndpoints(endpoints Endpoints) {
+	b.endpoints = endpoints
+}
+
+// SetTokenFiles overrides where the server's AWS and Azure identity tokens are read from
+func (b *Broker) SetTokenFiles(aws, azure string) {
+	b.awsTokenFile, b.azureTokenFile = aws, azure
+}
+
+// Issue returns fresh credentials of every grant matching the step. Steps without a
+// matching grant get none.
+func (b *Broker) Issue(ctx context.Context, t Target) ([]Credentials, error) {
+	if b == nil {
+		return nil, nil
+	}
+	var issued []Credentials
+	for _, grant := range b.config.Match(t) {
+		var creds *Credentials
+		var err error
+		switch grant.Cloud {
+		case CloudAWS:
+			creds, err = b.issueAWS(ctx, grant, t)
+		case CloudGCP:
+			creds, err = b.issueGCP(ctx, grant)
+		case CloudAzure:
+			creds, err = b.issueAzure(ctx, grant)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue %s credentials of grant '%s': %w", grant.Cloud, grant.Name, err)
+		}
+		creds.Grant, creds.Cloud = grant.Name, grant.Cloud
+		issued = append(issued, *creds)
+	}
+	return issued, nil
+}
+
+// sessionNameChars are the characters AWS allows in role session names
+var sessionNameChars = regexp.MustCompile(`[^\w+=,.@-]`)
+
+// sessionName identifies the step in cloud audit logs (CloudTrail)
+func sessionName(t Target) string {
+	name := sessionNameChars.ReplaceAllString(fmt.Sprintf("innominatus-%s-%s", t.App, t.StepName), "-")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// readToken reads a projected service account token
+func readToken(path, env string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("%s is not set; the server has no federated identity", env)
+	}
+	// #nosec G304 - path is the token file projected by the cluster
+	token, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read identity token: %w", err)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
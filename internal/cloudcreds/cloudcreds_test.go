@@ -0,0 +1,187 @@
+package cloudcreds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	valid := Grant{Name: "rds", Cloud: CloudAWS, ResourceTypes: []string{"postgres"}, RoleARN: "arn:aws:iam::123456789012:role/rds"}
+	require.NoError(t, Config{Grants: []Grant{valid}}.Validate())
+
+	invalid := map[string]Grant{
+		"no name":           {Cloud: CloudAWS, ResourceTypes: []string{"postgres"}, RoleARN: "arn"},
+		"no selector":       {Name: "x", Cloud: CloudAWS, RoleARN: "arn"},
+		"unknown cloud":     {Name: "x", Cloud: "oci", Providers: []string{"p"}},
+		"aws without role":  {Name: "x", Cloud: CloudAWS, Providers: []string{"p"}},
+		"gcp without sa":    {Name: "x", Cloud: CloudGCP, Providers: []string{"p"}},
+		"azure without ids": {Name: "x", Cloud: CloudAzure, Providers: []string{"p"}, TenantID: "t"},
+		"azure duration":    {Name: "x", Cloud: CloudAzure, Providers: []string{"p"}, TenantID: "t", ClientID: "c", Duration: "30m"},
+		"aws too short":     {Name: "x", Cloud: CloudAWS, Providers: []string{"p"}, RoleARN: "arn", Duration: "5m"},
+		"gcp too long":      {Name: "x", Cloud: CloudGCP, Providers: []string{"p"}, ServiceAccount: "sa", Duration: "2h"},
+		"bad duration":      {Name: "x", Cloud: CloudAWS, Providers: []string{"p"}, RoleARN: "arn", Duration: "soon"},
+	}
+	for name, grant := range invalid {
+		assert.Error(t, Config{Grants: []Grant{grant}}.Validate(), name)
+	}
+
+	assert.Error(t, Config{Grants: []Grant{valid, valid}}.Validate(), "duplicate names")
+}
+
+func TestConfig_Match(t *testing.T) {
+	config := Config{Grants: []Grant{
+		{Name: "rds", Cloud: CloudAWS, ResourceTypes: []string{"postgres"}, StepTypes: []string{"terraform"}},
+		{Name: "aws-team", Cloud: CloudAWS, Providers: []string{"database-team"}},
+		{Name: "gcs", Cloud: CloudGCP, Providers: []string{"database-team"}},
+	}}
+
+	grants := config.Match(Target{Provider: "database-team", ResourceType: "postgres", StepType: "terraform"})
+	require.Len(t, grants, 2)
+	assert.Equal(t, "rds", grants[0].Name, "first aws grant wins")
+	assert.Equal(t, "gcs", grants[1].Name)
+
+	grants = config.Match(Target{Provider: "database-team", ResourceType: "postgres", StepType: "kubernetes"})
+	require.Len(t, grants, 2)
+	assert.Equal(t, "aws-team", grants[0].Name, "step type filter skips rds")
+
+	assert.Empty(t, config.Match(Target{Provider: "container-team", ResourceType: "namespace"}))
+}
+
+func writeToken(t *testing.T, token string) string {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte(token+"\n"), 0600))
+	return path
+}
+
+func TestBroker_IssueAWS(t *testing.T) {
+	expires := time.Now().Add(15 * time.Minute).UTC().Truncate(time.Second)
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.Form.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/rds", r.Form.Get("RoleArn"))
+		assert.Equal(t, "irsa-token", r.Form.Get("WebIdentityToken"))
+		assert.Equal(t, "1800", r.Form.Get("DurationSeconds"))
+		assert.Equal(t, "innominatus-shop-provision-db", r.Form.Get("RoleSessionName"))
+		fmt.Fprintf(w, `<AssumeRoleWithWebIdentityResponse><AssumeRoleWithWebIdentityResult><Credentials>
+<AccessKeyId>ASIATEST</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>session</SessionToken>
+<Expiration>%s</Expiration></Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`, expires.Format(time.RFC3339))
+	}))
+	defer sts.Close()
+
+	broker, err := NewBroker(Config{Grants: []Grant{{
+		Name: "rds", Cloud: CloudAWS, ResourceTypes: []string{"postgres"},
+		RoleARN: "arn:aws:iam::123456789012:role/rds", Region: "eu-central-1", Duration: "30m",
+	}}})
+	require.NoError(t, err)
+	broker.SetEndpoints(Endpoints{AWSSTS: sts.URL})
+	broker.SetTokenFiles(writeToken(t, "irsa-token"), "")
+
+	issued, err := broker.Issue(context.Background(), Target{App: "shop", ResourceType: "postgres", StepName: "provision db"})
+	require.NoError(t, err)
+	require.Len(t, issued, 1)
+	assert.Equal(t, "rds", issued[0].Grant)
+	assert.Equal(t, "ASIATEST", issued[0].Env["AWS_ACCESS_KEY_ID"])
+	assert.Equal(t, "secret", issued[0].Env["AWS_SECRET_ACCESS_KEY"])
+	assert.Equal(t, "session", issued[0].Env["AWS_SESSION_TOKEN"])
+	assert.Equal(t, "eu-central-1", issued[0].Env["AWS_REGION"])
+	assert.True(t, expires.Equal(issued[0].ExpiresAt))
+}
+
+func TestBroker_IssueGCP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/service-accounts/default/token"):
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "workload-token"})
+		case strings.HasSuffix(r.URL.Path, ":generateAccessToken"):
+			assert.Equal(t, "Bearer workload-token", r.Header.Get("Authorization"))
+			assert.Contains(t, r.URL.Path, "buckets@project.iam.gserviceaccount.com")
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "900s", body["lifetime"])
+			_ = json.NewEncoder(w).Encode(map[string]string{"accessToken": "sa-token", "expireTime": "2030-01-01T00:00:00Z"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	broker, err := NewBroker(Config{Grants: []Grant{{
+		Name: "gcs", Cloud: CloudGCP, Providers: []string{"storage-team"},
+		ServiceAccount: "buckets@project.iam.gserviceaccount.com", Project: "project",
+	}}})
+	require.NoError(t, err)
+	broker.SetEndpoints(Endpoints{GCPMetadata: server.URL, GCPIAMCredentials: server.URL})
+
+	issued, err := broker.Issue(context.Background(), Target{Provider: "storage-team"})
+	require.NoError(t, err)
+	require.Len(t, issued, 1)
+	assert.Equal(t, "sa-token", issued[0].Env["GOOGLE_OAUTH_ACCESS_TOKEN"])
+	assert.Equal(t, "sa-token", issued[0].Env["CLOUDSDK_AUTH_ACCESS_TOKEN"])
+	assert.Equal(t, "project", issued[0].Env["GOOGLE_PROJECT"])
+}
+
+func TestBroker_IssueAzure(t *testing.T) {
+	login := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tenant/oauth2/v2.0/token", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client", r.Form.Get("client_id"))
+		assert.Equal(t, "federated-token", r.Form.Get("client_assertion"))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "entra-token", "expires_in": 3600})
+	}))
+	defer login.Close()
+
+	broker, err := NewBroker(Config{Grants: []Grant{{
+		Name: "aks", Cloud: CloudAzure, Providers: []string{"container-team"},
+		TenantID: "tenant", ClientID: "client", SubscriptionID: "subscription",
+	}}})
+	require.NoError(t, err)
+	broker.SetEndpoints(Endpoints{AzureLogin: login.URL})
+	broker.SetTokenFiles("", writeToken(t, "federated-token"))
+
+	issued, err := broker.Issue(context.Background(), Target{Provider: "container-team"})
+	require.NoError(t, err)
+	require.Len(t, issued, 1)
+	assert.Equal(t, "entra-token", issued[0].Env["AZURE_ACCESS_TOKEN"])
+	assert.Equal(t, "subscription", issued[0].Env["ARM_SUBSCRIPTION_ID"])
+}
+
+func TestBroker_IssueErrors(t *testing.T) {
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "AccessDenied: not authorized to perform sts:AssumeRoleWithWebIdentity", http.StatusForbidden)
+	}))
+	defer sts.Close()
+
+	broker, err := NewBroker(Config{Grants: []Grant{{Name: "rds", Cloud: CloudAWS, Providers: []string{"p"}, RoleARN: "arn"}}})
+	require.NoError(t, err)
+	broker.SetEndpoints(Endpoints{AWSSTS: sts.URL})
+
+	broker.SetTokenFiles("", "")
+	_, err = broker.Issue(context.Background(), Target{Provider: "p"})
+	assert.ErrorContains(t, err, "AWS_WEB_IDENTITY_TOKEN_FILE is not set")
+
+	broker.SetTokenFiles(writeToken(t, "irsa-token"), "")
+	_, err = broker.Issue(context.Background(), Target{Provider: "p"})
+	assert.ErrorContains(t, err, "AccessDenied")
+
+	var nilBroker *Broker
+	issued, err := nilBroker.Issue(context.Background(), Target{Provider: "p"})
+	assert.NoError(t, err)
+	assert.Empty(t, issued)
+}
+
+func TestSessionName(t *testing.T) {
+	assert.Equal(t, "innominatus-shop-apply-terraform-", sessionName(Target{App: "shop", StepName: "apply terraform!"}))
+	assert.Len(t, sessionName(Target{App: strings.Repeat("a", 100)}), 64)
+}
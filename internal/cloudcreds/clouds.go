@@ -0,0 +1,211 @@
+package cloudcreds
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGCPMetadata       = "http://metadata.google.internal"
+	defaultGCPIAMCredentials = "https://iamcredentials.googleapis.com"
+	defaultAzureLogin        = "https://login.microsoftonline.com"
+	defaultGCPScope          = "https://www.googleapis.com/auth/cloud-platform"
+	defaultAzureScope        = "https://management.azure.com/.default"
+)
+
+// do sends a request and decodes a successful response with decode. Error responses are
+// returned with their body, which the token APIs fill with the reason.
+func (b *Broker) do(req *http.Request, decode func(io.Reader) error) error {
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s returned %s: %s", req.URL.Host, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return decode(resp.Body)
+}
+
+// issueAWS assumes the grant's role with the server's IRSA token
+// (sts:AssumeRoleWithWebIdentity, which needs no AWS credentials of its own)
+func (b *Broker) issueAWS(ctx context.Context, grant Grant, t Target) (*Credentials, error) {
+	token, err := readToken(b.awsTokenFile, "AWS_WEB_IDENTITY_TOKEN_FILE")
+	if err != nil {
+		return nil, err
+	}
+	duration, _ := grant.duration()
+
+	endpoint := b.endpoints.AWSSTS
+	if endpoint == "" {
+		endpoint = "https://sts.amazonaws.com"
+		if grant.Region != "" {
+			endpoint = fmt.Sprintf("https://sts.%s.amazonaws.com", grant.Region)
+		}
+	}
+
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {grant.RoleARN},
+		"RoleSessionName":  {sessionName(t)},
+		"WebIdentityToken": {token},
+		"DurationSeconds":  {strconv.Itoa(int(duration.Seconds()))},
+	}
+	if grant.SessionPolicy != "" {
+		form.Set("Policy", grant.SessionPolicy)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint+"/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var response struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+	if err := b.do(req, func(r io.Reader) error { return xml.NewDecoder(r).Decode(&response) }); err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{
+		"AWS_ACCESS_KEY_ID":     response.Credentials.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY": response.Credentials.SecretAccessKey,
+		"AWS_SESSION_TOKEN":     response.Credentials.SessionToken,
+	}
+	if grant.Region != "" {
+		env["AWS_REGION"] = grant.Region
+		env["AWS_DEFAULT_REGION"] = grant.Region
+	}
+	return &Credentials{Env: env, ExpiresAt: response.Credentials.Expiration}, nil
+}
+
+// issueGCP impersonates the grant's service account with the token of the server's
+// workload identity from the GKE metadata server
+func (b *Broker) issueGCP(ctx context.Context, grant Grant) (*Credentials, error) {
+	metadata := b.endpoints.GCPMetadata
+	if metadata == "" {
+		metadata = defaultGCPMetadata
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", metadata+"/computeMetadata/v1/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	var identity struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := b.do(req, func(r io.Reader) error { return json.NewDecoder(r).Decode(&identity) }); err != nil {
+		return nil, fmt.Errorf("failed to get the server's workload identity token: %w", err)
+	}
+
+	duration, _ := grant.duration()
+	scopes := grant.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{defaultGCPScope}
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"scope":    scopes,
+		"lifetime": fmt.Sprintf("%ds", int(duration.Seconds())),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	iam := b.endpoints.GCPIAMCredentials
+	if iam == "" {
+		iam = defaultGCPIAMCredentials
+	}
+	endpoint := fmt.Sprintf("%s/v1/projects/-/serviceAccounts/%s:generateAccessToken", iam, url.PathEscape(grant.ServiceAccount))
+	req, err = http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+identity.AccessToken)
+
+	var response struct {
+		AccessToken string    `json:"accessToken"`
+		ExpireTime  time.Time `json:"expireTime"`
+	}
+	if err := b.do(req, func(r io.Reader) error { return json.NewDecoder(r).Decode(&response) }); err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{
+		"GOOGLE_OAUTH_ACCESS_TOKEN":  response.AccessToken, // Terraform google provider
+		"CLOUDSDK_AUTH_ACCESS_TOKEN": response.AccessToken, // gcloud
+	}
+	if grant.Project != "" {
+		env["GOOGLE_PROJECT"] = grant.Project
+		env["CLOUDSDK_CORE_PROJECT"] = grant.Project
+	}
+	return &Credentials{Env: env, ExpiresAt: response.ExpireTime}, nil
+}
+
+// issueAzure exchanges the server's federated service account token for an Entra ID
+// access token of the grant's app registration (client credentials with a client assertion)
+func (b *Broker) issueAzure(ctx context.Context, grant Grant) (*Credentials, error) {
+	token, err := readToken(b.azureTokenFile, "AZURE_FEDERATED_TOKEN_FILE")
+	if err != nil {
+		return nil, err
+	}
+
+	scope := grant.Scope
+	if scope == "" {
+		scope = defaultAzureScope
+	}
+	form := url.Values{
+		"client_id":             {grant.ClientID},
+		"scope":                 {scope},
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {token},
+	}
+
+	login := b.endpoints.AzureLogin
+	if login == "" {
+		login = defaultAzureLogin
+	}
+	endpoint := fmt.Sprintf("%s/%s/oauth2/v2.0/token", login, url.PathEscape(grant.TenantID))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := b.do(req, func(r io.Reader) error { return json.NewDecoder(r).Decode(&response) }); err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{
+		"AZURE_ACCESS_TOKEN": response.AccessToken,
+		"ARM_TENANT_ID":      grant.TenantID,
+		"ARM_CLIENT_ID":      grant.ClientID,
+	}
+	if grant.SubscriptionID != "" {
+		env["ARM_SUBSCRIPTION_ID"] = grant.SubscriptionID
+	}
+	return &Credentials{Env: env, ExpiresAt: time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)}, nil
+}
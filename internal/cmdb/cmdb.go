@@ -0,0 +1,339 @@
+// Package cmdb keeps external configuration management databases up to date. Every time
+// a resource is created, changes state or is deprovisioned, the resource record and the
+// metadata of its application are posted to the configured webhooks. Requests are signed
+// with HMAC-SHA256 and retried with exponential backoff.
+package cmdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/events"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is how often a delivery is tried without a configured number
+	DefaultMaxAttempts = 5
+	// DefaultBackoff is the wait before the first retry; it doubles with every retry
+	DefaultBackoff = 2 * time.Second
+	// MaxBackoff caps the wait between two attempts
+	MaxBackoff = 5 * time.Minute
+
+	// Headers of a delivery
+	HeaderEvent     = "X-Innominatus-Event"
+	HeaderDelivery  = "X-Innominatus-Delivery"
+	HeaderTimestamp = "X-Innominatus-Timestamp"
+	HeaderSignature = "X-Innominatus-Signature"
+
+	requestTimeout = 10 * time.Second
+)
+
+// Actions a delivery reports on a resource
+const (
+	ActionCreate      = "create"
+	ActionUpdate      = "update"
+	ActionDeprovision = "deprovision"
+)
+
+// actions maps the resource events to the action they report
+var actions = map[events.EventType]string{
+	events.EventTypeResourceCreated:      ActionCreate,
+	events.EventTypeResourceRequested:    ActionUpdate,
+	events.EventTypeResourceProvisioning: ActionUpdate,
+	events.EventTypeResourceActive:       ActionUpdate,
+	events.EventTypeResourceFailed:       ActionUpdate,
+	events.EventTypeResourceHibernated:   ActionUpdate,
+	events.EventTypeResourceTerminating:  ActionUpdate,
+	events.EventTypeResourceTerminated:   ActionDeprovision,
+}
+
+// EventTypes are the resource events deliveries are sent for
+func EventTypes() []events.EventType {
+	types := make([]events.EventType, 0, len(actions))
+	for eventType := range actions {
+		types = append(types, eventType)
+	}
+	return types
+}
+
+// Action returns the action a resource event reports, or "" for other events
+func Action(eventType events.EventType) string {
+	return actions[eventType]
+}
+
+// Config is the cmdb section of admin-config.yaml
+type Config struct {
+	Webhooks    []Webhook `yaml:"webhooks" json:"webhooks"`
+	MaxAttempts int       `yaml:"maxAttempts" json:"maxAttempts"` // Attempts per delivery (default 5)
+	Backoff     string    `yaml:"backoff" json:"backoff"`         // Wait before the first retry, doubled for every further one (default 2s)
+}
+
+// Webhook is an endpoint receiving resource changes
+type Webhook struct {
+	Name          string   `yaml:"name" json:"name"`
+	URL           string   `yaml:"url" json:"url"`
+	SecretEnv     string   `yaml:"secretEnv" json:"secretEnv"`         // Environment variable holding the HMAC signing secret
+	Actions       []string `yaml:"actions" json:"actions"`             // create, update and/or deprovision (default all)
+	ResourceTypes []string `yaml:"resourceTypes" json:"resourceTypes"` // Resource types sent (default all)
+}
+
+// Enabled reports whether any webhook is configured
+func (c Config) Enabled() bool {
+	return len(c.Webhooks) > 0
+}
+
+// Retry returns the number of attempts and the first backoff, applying the defaults
+func (c Config) Retry() (attempts int, backoff time.Duration, err error) {
+	attempts, backoff = DefaultMaxAttempts, DefaultBackoff
+	if c.MaxAttempts != 0 {
+		attempts = c.MaxAttempts
+	}
+	if c.Backoff != "" {
+		if backoff, err = time.ParseDuration(c.Backoff); err != nil || backoff <= 0 {
+			return 0, 0, fmt.Errorf("invalid cmdb.backoff '%s'", c.Backoff)
+		}
+	}
+	return attempts, backoff, nil
+}
+
+// Validate checks the webhooks and the retry settings
+func (c Config) Validate() error {
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("cmdb.maxAttempts must not be negative, got %d", c.MaxAttempts)
+	}
+	if _, _, err := c.Retry(); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(c.Webhooks))
+	for i, webhook := range c.Webhooks {
+		path := fmt.Sprintf("cmdb.webhooks[%d]", i)
+		if webhook.Name == "" {
+			return fmt.Errorf("%s.name is required", path)
+		}
+		if names[webhook.Name] {
+			return fmt.Errorf("%s: duplicate webhook name '%s'", path, webhook.Name)
+		}
+		names[webhook.Name] = true
+
+		u, err := url.Parse(webhook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s.url '%s' is not an http(s) URL", path, webhook.URL)
+		}
+		if webhook.SecretEnv == "" {
+			return fmt.Errorf("%s.secretEnv is required", path)
+		}
+		for _, action := range webhook.Actions {
+			if action != ActionCreate && action != ActionUpdate && action != ActionDeprovision {
+				return fmt.Errorf("%s.actions: unknown action '%s'", path, action)
+			}
+		}
+	}
+	return nil
+}
+
+// Sends reports whether a webhook takes a change of a resource type
+func (w Webhook) Sends(action, resourceType string) bool {
+	return matches(w.Actions, action) && matches(w.ResourceTypes, resourceType)
+}
+
+// matches reports whether value is listed, or whether the list is empty
+func matches(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Application is the metadata of the application owning a resource
+type Application struct {
+	Name        string    `json:"name"`
+	Team        string    `json:"team,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	Labels      []string  `json:"labels,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewApplication returns the metadata of an application
+func NewApplication(app *database.Application) *Application {
+	metadata := &Application{
+		Name:      app.Name,
+		Team:      app.Team,
+		CreatedBy: app.CreatedBy,
+		Labels:    app.Labels,
+		CreatedAt: app.CreatedAt,
+	}
+	if app.ScoreSpec != nil && app.ScoreSpec.Environment != nil {
+		metadata.Environment = app.ScoreSpec.Environment.Type
+	}
+	return metadata
+}
+
+// Payload is the body of a delivery
+type Payload struct {
+	ID          string                     `json:"id"` // ID of the event; the same for every attempt
+	Action      string                     `json:"action"`
+	Event       events.EventType           `json:"event"`
+	Timestamp   time.Time                  `json:"timestamp"`
+	Resource    *database.ResourceInstance `json:"resource"`
+	Application *Application               `json:"application"`
+}
+
+// NewPayload returns the delivery of a resource event. resource is the current record of
+// the resource, or nil if it could not be read, in which case the record is built from the
+// event. Credentials in the provider metadata are redacted.
+func NewPayload(event events.Event, resource *database.ResourceInstance, app *Application) Payload {
+	if resource == nil {
+		resource = resourceFromEvent(event)
+	}
+	record := *resource
+	record.ProviderMetadata = redact(resource.ProviderMetadata)
+	record.StateTransitions = nil
+	if app == nil {
+		app = &Application{Name: event.AppName}
+	}
+
+	return Payload{
+		ID:          event.ID,
+		Action:      Action(event.Type),
+		Event:       event.Type,
+		Timestamp:   event.Timestamp,
+		Resource:    &record,
+		Application: app,
+	}
+}
+
+// resourceFromEvent returns the record of a resource as far as an event describes it
+func resourceFromEvent(event events.Event) *database.ResourceInstance {
+	str := func(key string) string {
+		if v, ok := event.Data[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	resource := &database.ResourceInstance{
+		ApplicationName: event.AppName,
+		ResourceName:    str("resource_name"),
+		ResourceType:    str("resource_type"),
+		State:           database.ResourceLifecycleState(str("new_state")),
+	}
+	if resource.State == "" {
+		resource.State = database.ResourceLifecycleState(str("state"))
+	}
+	switch id := event.Data["resource_id"].(type) {
+	case int64:
+		resource.ID = id
+	case int:
+		resource.ID = int64(id)
+	case float64:
+		resource.ID = int64(id)
+	}
+	return resource
+}
+
+// sensitiveKeys are parts of provider metadata keys whose values are not sent
+var sensitiveKeys = []string{"password", "secret", "token", "credential", "private_key", "connection_string"}
+
+// redact returns a copy of provider metadata with credentials replaced
+func redact(metadata map[string]interface{}) map[string]interface{} {
+	if metadata == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(metadata))
+	for key, value := range metadata {
+		lower := strings.ToLower(key)
+		for _, sensitive := range sensitiveKeys {
+			if strings.Contains(lower, sensitive) {
+				value = "****"
+				break
+			}
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// Sign returns the signature of a delivery: the hex HMAC-SHA256 of "<timestamp>.<body>",
+// prefixed with "sha256=". Receivers recompute it to authenticate the delivery and reject
+// old timestamps to prevent replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DeliveryError is a failed attempt to deliver a payload
+type DeliveryError struct {
+	StatusCode int // 0 if no response was received
+	Err        error
+}
+
+func (e *DeliveryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether a later attempt may succeed: the request failed, was rate
+// limited or hit a server error
+func (e *DeliveryError) Retryable() bool {
+	return e.StatusCode == 0 || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Send posts a payload to a webhook once, signed with the secret from the webhook's
+// environment variable
+func Send(ctx context.Context, webhook Webhook, payload Payload, now time.Time) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode delivery: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create delivery request: %w", err)
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(payload.Event))
+	req.Header.Set(HeaderDelivery, payload.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(os.Getenv(webhook.SecretEnv), timestamp, body))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return &DeliveryError{Err: fmt.Errorf("failed to send to webhook %s: %w", webhook.Name, err)}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &DeliveryError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("webhook %s returned %s: %s", webhook.Name, resp.Status, bytes.TrimSpace(text)),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package cmdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"innominatus/internal/clock"
+	"innominatus/internal/database"
+	"innominatus/internal/events"
+	"innominatus/internal/logging"
+	"sync"
+)
+
+// queueSize is how many deliveries wait per webhook before new ones are dropped
+const queueSize = 1000
+
+// delivery is a payload waiting to be sent to a webhook
+type delivery struct {
+	webhook Webhook
+	payload Payload
+}
+
+// Publisher sends a delivery to every matching webhook for each resource event. Every
+// webhook has its own queue, so a slow or failing CMDB neither delays the others nor
+// reorders the changes of a resource.
+type Publisher struct {
+	config      func() Config
+	resource    func(id int64) (*database.ResourceInstance, error)
+	application func(name string) (*database.Application, error)
+	clock       clock.Clock
+	logger      *logging.ZerologAdapter
+
+	mu     sync.Mutex
+	queues map[string]chan delivery // By webhook name
+}
+
+// NewPublisher creates a publisher. config is called for every event, so changes to the
+// webhooks apply without a restart. resource and application read the current records;
+// either may be nil, and the payload is then built from the event alone.
+func NewPublisher(config func() Config, resource func(id int64) (*database.ResourceInstance, error), application func(name string) (*database.Application, error)) *Publisher {
+	return &Publisher{
+		config:      config,
+		resource:    resource,
+		application: application,
+		clock:       clock.Real(),
+		logger:      logging.NewStructuredLogger("cmdb"),
+		queues:      make(map[string]chan delivery),
+	}
+}
+
+// SetClock replaces the clock that backoffs wait on
+func (p *Publisher) SetClock(c clock.Clock) {
+	p.clock = clock.OrReal(c)
+}
+
+// Start subscribes the publisher to the resource events on bus. Deliveries stop when ctx
+// is cancelled.
+func (p *Publisher) Start(ctx context.Context, bus events.EventBus) {
+	bus.Subscribe("", EventTypes(), func(event events.Event) {
+		p.Publish(ctx, event)
+	})
+}
+
+// Publish queues the delivery of a resource event for every webhook that takes it
+func (p *Publisher) Publish(ctx context.Context, event events.Event) {
+	config := p.config()
+	action := Action(event.Type)
+	if !config.Enabled() || action == "" {
+		return
+	}
+
+	payload := p.payload(event)
+	for _, webhook := range config.Webhooks {
+		if !webhook.Sends(action, payload.Resource.ResourceType) {
+			continue
+		}
+		select {
+		case p.queue(ctx, webhook.Name) <- delivery{webhook: webhook, payload: payload}:
+		default:
+			p.logger.WarnWithFields("CMDB webhook queue is full, dropping delivery", map[string]interface{}{
+				"webhook":  webhook.Name,
+				"event":    string(event.Type),
+				"app_name": event.AppName,
+			})
+		}
+	}
+}
+
+// payload reads the current records of the resource and application of an event
+func (p *Publisher) payload(event events.Event) Payload {
+	var resource *database.ResourceInstance
+	if id := resourceFromEvent(event).ID; id != 0 && p.resource != nil {
+		if current, err := p.resource(id); err == nil {
+			resource = current
+		}
+	}
+	var app *Application
+	if p.application != nil && event.AppName != "" {
+		if current, err := p.application(event.AppName); err == nil && current != nil {
+			app = NewApplication(current)
+		}
+	}
+	return NewPayload(event, resource, app)
+}
+
+// queue returns the queue of a webhook, starting its worker on first use
+func (p *Publisher) queue(ctx context.Context, name string) chan delivery {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	queue, ok := p.queues[name]
+	if !ok {
+		queue = make(chan delivery, queueSize)
+		p.queues[name] = queue
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case d := <-queue:
+					if err := p.Deliver(ctx, d.webhook, d.payload); err != nil {
+						p.logger.WarnWithFields("Failed to deliver resource change to CMDB", map[string]interface{}{
+							"webhook":  d.webhook.Name,
+							"event":    string(d.payload.Event),
+							"delivery": d.payload.ID,
+							"error":    err.Error(),
+						})
+					}
+				}
+			}
+		}()
+	}
+	return queue
+}
+
+// Deliver sends a payload to a webhook, retrying failed requests, rate limits and server
+// errors with exponential backoff. Other client errors are not retried.
+func (p *Publisher) Deliver(ctx context.Context, webhook Webhook, payload Payload) error {
+	attempts, backoff, err := p.config().Retry()
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := Send(ctx, webhook, payload, p.clock.Now())
+		if err == nil {
+			return nil
+		}
+		var deliveryErr *DeliveryError
+		if !errors.As(err, &deliveryErr) || !deliveryErr.Retryable() || attempt >= attempts {
+			return fmt.Errorf("giving up after %d attempt(s): %w", attempt, err)
+		}
+
+		p.logger.InfoWithFields("Retrying CMDB delivery", map[string]interface{}{
+			"webhook": webhook.Name,
+			"attempt": attempt,
+			"backoff": backoff.String(),
+			"error":   err.Error(),
+		})
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.clock.After(backoff):
+		}
+		if backoff *= 2; backoff > MaxBackoff {
+			backoff = MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,368 @@
+// Package config resolves the server's startup configuration from defaults, a YAML
+// config file, environment variables and command-line flags.
+//
+// Precedence, from lowest to highest:
+//
+//	default < config file < environment variable < flag
+//
+// Every resolved value remembers where it came from, so operators can see which source
+// won (GET /api/admin/effective-config).
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of a setting
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// ConfigEnv names the config file when --config is not given
+const ConfigEnv = "INNOMINATUS_CONFIG"
+
+// Config is the resolved server configuration
+type Config struct {
+	Server   ServerConfig
+	Database DatabaseConfig
+	Logging  LoggingConfig
+	Metrics  MetricsConfig
+
+	file     string
+	settings map[string]Setting
+}
+
+// ServerConfig holds HTTP server settings
+type ServerConfig struct {
+	Port           string
+	SkipValidation bool
+	FakeClock      string // "now" or an RFC3339 start time; empty uses the wall clock
+	FaultInjection bool   // Enables the fault injection admin API; refused when ENV=production
+	ProviderDev    bool   // Enables registering local providers with innominatus-ctl provider dev; refused when ENV=production
+}
+
+// DatabaseConfig holds the PostgreSQL connection settings
+type DatabaseConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	SSLMode  string
+	Dev      string // "sqlite" runs without PostgreSQL for local development; empty in production
+	DevPath  string // SQLite file of the dev database
+}
+
+// LoggingConfig holds the log level and format. Empty values leave the logging defaults.
+type LoggingConfig struct {
+	Level  string
+	Format string
+}
+
+// MetricsConfig holds the Prometheus Pushgateway settings
+type MetricsConfig struct {
+	PushgatewayURL         string        // Empty or "disabled" turns pushing off
+	PushInterval           time.Duration // How often metrics are pushed
+	PushJob                string        // job label
+	PushInstance           string        // instance label; defaults to the host name
+	PushUsername           string        // Basic auth
+	PushPassword           string
+	PushCAFile             string // CA bundle for the Pushgateway certificate
+	PushCertFile           string // Client certificate for mutual TLS
+	PushKeyFile            string
+	PushInsecureSkipVerify bool
+}
+
+// PushEnabled reports whether metrics are pushed to a Pushgateway
+func (m MetricsConfig) PushEnabled() bool {
+	return m.PushgatewayURL != "" && m.PushgatewayURL != "disabled"
+}
+
+// Setting is a resolved configuration value and where it came from
+type Setting struct {
+	Key    string `json:"key"`              // Key in the config file, e.g. database.host
+	Value  string `json:"value"`            // Masked for secrets
+	Source string `json:"source"`           // default, file, env or flag
+	Origin string `json:"origin,omitempty"` // File path, variable or flag name that set the value
+	Env    string `json:"env,omitempty"`    // Environment variable for the setting
+	Flag   string `json:"flag,omitempty"`   // Flag for the setting
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// field describes one setting and how it maps onto Config
+type field struct {
+	key    string
+	env    string
+	flag   string
+	def    string
+	usage  string
+	secret bool
+	set    func(c *Config, value string) error
+}
+
+var fields = []field{
+	{key: "server.port", env: "PORT", flag: "port", def: "8081", usage: "HTTP server port",
+		set: func(c *Config, v string) error { c.Server.Port = v; return nil }},
+	{key: "server.skipValidation", env: "INNOMINATUS_SKIP_VALIDATION", flag: "skip-validation", def: "false", usage: "Skip configuration validation on startup",
+		set: func(c *Config, v string) error { return setBool(&c.Server.SkipValidation, v) }},
+	{key: "server.fakeClock", env: "INNOMINATUS_FAKE_CLOCK", usage: "Start a fake clock ('now' or RFC3339)",
+		set: func(c *Config, v string) error { c.Server.FakeClock = v; return nil }},
+	{key: "server.faultInjection", env: "INNOMINATUS_FAULT_INJECTION", flag: "fault-injection", def: "false", usage: "Enable fault injection for resilience testing (/api/admin/faults); not for production",
+		set: func(c *Config, v string) error { return setBool(&c.Server.FaultInjection, v) }},
+	{key: "server.providerDev", env: "INNOMINATUS_PROVIDER_DEV", flag: "provider-dev", def: "false", usage: "Enable the provider development channel (/api/admin/providers/dev); not for production",
+		set: func(c *Config, v string) error { return setBool(&c.Server.ProviderDev, v) }},
+	{key: "database.host", env: "DB_HOST", def: "localhost",
+		set: func(c *Config, v string) error { c.Database.Host = v; return nil }},
+	{key: "database.port", env: "DB_PORT", def: "5432",
+		set: func(c *Config, v string) error { c.Database.Port = v; return nil }},
+	{key: "database.user", env: "DB_USER", def: "postgres",
+		set: func(c *Config, v string) error { c.Database.User = v; return nil }},
+	{key: "database.password", env: "DB_PASSWORD", secret: true,
+		set: func(c *Config, v string) error { c.Database.Password = v; return nil }},
+	{key: "database.name", env: "DB_NAME", def: "idp_orchestrator",
+		set: func(c *Config, v string) error { c.Database.Name = v; return nil }},
+	{key: "database.sslMode", env: "DB_SSLMODE", def: "disable",
+		set: func(c *Config, v string) error { c.Database.SSLMode = v; return nil }},
+	{key: "database.dev", env: "INNOMINATUS_DEV_DB", flag: "dev-db", usage: "Development database instead of PostgreSQL ('sqlite'); not for production",
+		set: func(c *Config, v string) error {
+			if v != "" && v != "sqlite" {
+				return fmt.Errorf("unsupported dev database '%s' (supported: sqlite)", v)
+			}
+			c.Database.Dev = v
+			return nil
+		}},
+	{key: "database.devPath", env: "INNOMINATUS_DEV_DB_PATH", flag: "dev-db-path", def: "data/innominatus-dev.db", usage: "SQLite file of the development database",
+		set: func(c *Config, v string) error { c.Database.DevPath = v; return nil }},
+	{key: "logging.level", env: "LOG_LEVEL",
+		set: func(c *Config, v string) error { c.Logging.Level = v; return nil }},
+	{key: "logging.format", env: "LOG_FORMAT",
+		set: func(c *Config, v string) error { c.Logging.Format = v; return nil }},
+	{key: "metrics.pushgatewayURL", env: "PUSHGATEWAY_URL", flag: "pushgateway-url", usage: "Prometheus Pushgateway URL (empty or 'disabled' turns pushing off)",
+		set: func(c *Config, v string) error { c.Metrics.PushgatewayURL = v; return nil }},
+	{key: "metrics.pushInterval", env: "PUSHGATEWAY_INTERVAL", def: "15s",
+		set: func(c *Config, v string) error { return setDuration(&c.Metrics.PushInterval, v) }},
+	{key: "metrics.pushJob", env: "PUSHGATEWAY_JOB", def: "innominatus",
+		set: func(c *Config, v string) error { c.Metrics.PushJob = v; return nil }},
+	{key: "metrics.pushInstance", env: "PUSHGATEWAY_INSTANCE",
+		set: func(c *Config, v string) error { c.Metrics.PushInstance = v; return nil }},
+	{key: "metrics.pushUsername", env: "PUSHGATEWAY_USERNAME",
+		set: func(c *Config, v string) error { c.Metrics.PushUsername = v; return nil }},
+	{key: "metrics.pushPassword", env: "PUSHGATEWAY_PASSWORD", secret: true,
+		set: func(c *Config, v string) error { c.Metrics.PushPassword = v; return nil }},
+	{key: "metrics.pushCAFile", env: "PUSHGATEWAY_CA_FILE",
+		set: func(c *Config, v string) error { c.Metrics.PushCAFile = v; return nil }},
+	{key: "metrics.pushCertFile", env: "PUSHGATEWAY_CERT_FILE",
+		set: func(c *Config, v string) error { c.Metrics.PushCertFile = v; return nil }},
+	{key: "metrics.pushKeyFile", env: "PUSHGATEWAY_KEY_FILE",
+		set: func(c *Config, v string) error { c.Metrics.PushKeyFile = v; return nil }},
+	{key: "metrics.pushInsecureSkipVerify", env: "PUSHGATEWAY_INSECURE_SKIP_VERIFY", def: "false",
+		set: func(c *Config, v string) error { return setBool(&c.Metrics.PushInsecureSkipVerify, v) }},
+}
+
+// Load resolves the configuration from command-line args (without the program name) and
+// the environment. lookupEnv is usually os.LookupEnv.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	fs := flag.NewFlagSet("innominatus", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Path to the server config file (env "+ConfigEnv+")")
+	flagValues := make(map[string]string)
+	for _, f := range fields {
+		if f.flag == "" {
+			continue
+		}
+		name := f.flag
+		store := func(value string) error { flagValues[name] = value; return nil }
+		if f.def == "false" {
+			fs.BoolFunc(name, f.usage, store)
+		} else {
+			fs.Func(name, f.usage+" (default "+strconv.Quote(f.def)+")", store)
+		}
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage of innominatus:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\n%s", Usage())
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	setFlags := make(map[string]bool)
+	fs.Visit(func(fl *flag.Flag) { setFlags[fl.Name] = true })
+
+	c := &Config{settings: make(map[string]Setting, len(fields))}
+
+	c.file = *configPath
+	if !setFlags["config"] {
+		c.file, _ = lookupEnv(ConfigEnv)
+	}
+	fileValues := map[string]string{}
+	if c.file != "" {
+		var err error
+		if fileValues, err = readFile(c.file); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range fields {
+		s := Setting{Key: f.key, Value: f.def, Source: SourceDefault, Env: f.env, Secret: f.secret}
+		if f.flag != "" {
+			s.Flag = "--" + f.flag
+		}
+		if v, ok := fileValues[f.key]; ok {
+			s.Value, s.Source, s.Origin = v, SourceFile, c.file
+			delete(fileValues, f.key)
+		}
+		if v, ok := lookupEnv(f.env); ok && f.env != "" {
+			s.Value, s.Source, s.Origin = v, SourceEnv, f.env
+		}
+		if v, ok := flagValues[f.flag]; ok && f.flag != "" {
+			s.Value, s.Source, s.Origin = v, SourceFlag, s.Flag
+		}
+		c.settings[f.key] = s
+	}
+	if len(fileValues) > 0 {
+		return nil, fmt.Errorf("%s: unknown setting '%s'", c.file, sortedKeys(fileValues)[0])
+	}
+
+	if err := c.apply(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Fallback sets key to value from another source, e.g. the logging section of
+// admin-config.yaml, unless a file, the environment or a flag already set it
+func (c *Config) Fallback(key, value, origin string) error {
+	s, ok := c.settings[key]
+	if !ok {
+		return fmt.Errorf("unknown setting '%s'", key)
+	}
+	if s.Source != SourceDefault || value == "" {
+		return nil
+	}
+	s.Value, s.Source, s.Origin = value, SourceFile, origin
+	c.settings[key] = s
+	return c.apply()
+}
+
+// File returns the config file that was read, or "" when none was given
+func (c *Config) File() string {
+	return c.file
+}
+
+// Settings returns every setting with its source, sorted by key. Secret values are masked.
+func (c *Config) Settings() []Setting {
+	result := make([]Setting, 0, len(c.settings))
+	for _, key := range sortedKeys(c.settings) {
+		s := c.settings[key]
+		if s.Secret && s.Value != "" {
+			s.Value = "****"
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// apply copies the resolved values into the typed structs
+func (c *Config) apply() error {
+	for _, f := range fields {
+		s := c.settings[f.key]
+		if err := f.set(c, s.Value); err != nil {
+			if s.Origin != "" {
+				return fmt.Errorf("%s (from %s): %w", f.key, s.Origin, err)
+			}
+			return fmt.Errorf("%s: %w", f.key, err)
+		}
+	}
+	return nil
+}
+
+// readFile reads a YAML config file into a map of dotted keys, e.g. database.host
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is given by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	values := make(map[string]string)
+	flatten("", doc, values)
+	return values, nil
+}
+
+func flatten(prefix string, doc map[string]interface{}, values map[string]string) {
+	for k, v := range doc {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case map[string]interface{}:
+			flatten(key, v, values)
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+func setBool(target *bool, value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid boolean '%s'", value)
+	}
+	*target = parsed
+	return nil
+}
+
+func setDuration(target *time.Duration, value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		return fmt.Errorf("invalid duration '%s'", value)
+	}
+	*target = parsed
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Usage describes the settings for --help output
+func Usage() string {
+	var b strings.Builder
+	b.WriteString("Settings (config file key, environment variable, flag):\n")
+	for _, f := range fields {
+		line := "  " + f.key
+		if f.env != "" {
+			line += "  $" + f.env
+		}
+		if f.flag != "" {
+			line += "  --" + f.flag
+		}
+		if f.def != "" {
+			line += fmt.Sprintf("  (default %s)", f.def)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := values[name]
+		return v, ok
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "innominatus.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load(nil, env(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, "8081", cfg.Server.Port)
+	assert.False(t, cfg.Server.SkipValidation)
+	assert.Equal(t, "localhost", cfg.Database.Host)
+	assert.Equal(t, "idp_orchestrator", cfg.Database.Name)
+	assert.Empty(t, cfg.Database.Dev, "PostgreSQL is the default database")
+	assert.Equal(t, 15*time.Second, cfg.Metrics.PushInterval)
+	assert.False(t, cfg.Metrics.PushEnabled(), "metrics are only pushed when a Pushgateway is configured")
+	assert.Empty(t, cfg.File())
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeConfigFile(t, `
+server:
+  port: "9000"
+database:
+  host: db.internal
+  user: innominatus
+  password: from-file
+metrics:
+  pushgatewayURL: http://pushgateway:9091
+  pushInterval: 30s
+`)
+
+	tests := []struct {
+		name       string
+		args       []string
+		env        map[string]string
+		key        string
+		wantValue  string
+		wantSource string
+		wantOrigin string
+	}{
+		{"default", []string{"--config", file}, nil, "database.port", "5432", SourceDefault, ""},
+		{"file over default", []string{"--config", file}, nil, "database.host", "db.internal", SourceFile, file},
+		{"env over file", []string{"--config", file}, map[string]string{"DB_HOST": "db.env"}, "database.host", "db.env", SourceEnv, "DB_HOST"},
+		{"flag over env", []string{"--config", file, "--port", "7000"}, map[string]string{"PORT": "8000"}, "server.port", "7000", SourceFlag, "--port"},
+		{"config file from env", nil, map[string]string{ConfigEnv: file}, "server.port", "9000", SourceFile, file},
+		{"boolean flag", []string{"--skip-validation"}, nil, "server.skipValidation", "true", SourceFlag, "--skip-validation"},
+		{"secret masked", []string{"--config", file}, nil, "database.password", "****", SourceFile, file},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(tt.args, env(tt.env))
+			require.NoError(t, err)
+
+			var setting *Setting
+			for _, s := range cfg.Settings() {
+				if s.Key == tt.key {
+					setting = &s
+					break
+				}
+			}
+			require.NotNil(t, setting, tt.key)
+			assert.Equal(t, tt.wantValue, setting.Value)
+			assert.Equal(t, tt.wantSource, setting.Source)
+			assert.Equal(t, tt.wantOrigin, setting.Origin)
+		})
+	}
+}
+
+func TestLoadTypedValues(t *testing.T) {
+	file := writeConfigFile(t, "metrics:\n  pushgatewayURL: http://pushgateway:9091\n  pushInterval: 30s\ndatabase:\n  password: from-file\n")
+
+	cfg, err := Load([]string{"--config", file, "--skip-validation"}, env(map[string]string{"PUSHGATEWAY_URL": "disabled"}))
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Server.SkipValidation)
+	assert.Equal(t, "from-file", cfg.Database.Password, "secrets are only masked in Settings")
+	assert.Equal(t, 30*time.Second, cfg.Metrics.PushInterval)
+	assert.Equal(t, "disabled", cfg.Metrics.PushgatewayURL)
+	assert.False(t, cfg.Metrics.PushEnabled())
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		args    []string
+		env     map[string]string
+		wantErr string
+	}{
+		{"unknown key", "server:\n  prot: 9000\n", nil, nil, "unknown setting 'server.prot'"},
+		{"invalid duration", "metrics:\n  pushInterval: soon\n", nil, nil, "metrics.pushInterval (from "},
+		{"invalid boolean", "", nil, map[string]string{"INNOMINATUS_SKIP_VALIDATION": "maybe"}, "server.skipValidation (from INNOMINATUS_SKIP_VALIDATION): invalid boolean 'maybe'"},
+		{"missing file", "", []string{"--config", "/nonexistent/innominatus.yaml"}, nil, "failed to read config file"},
+		{"unknown flag", "", []string{"--prot", "9000"}, nil, "flag provided but not defined: -prot"},
+		{"unsupported dev database", "", []string{"--dev-db", "mysql"}, nil, "unsupported dev database 'mysql'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"--config", writeConfigFile(t, tt.file)}, args...)
+			}
+			_, err := Load(args, env(tt.env))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestFallback(t *testing.T) {
+	cfg, err := Load(nil, env(map[string]string{"LOG_FORMAT": "json"}))
+	require.NoError(t, err)
+
+	require.NoError(t, cfg.Fallback("logging.level", "debug", "admin-config.yaml"))
+	require.NoError(t, cfg.Fallback("logging.format", "console", "admin-config.yaml"))
+
+	assert.Equal(t, "debug", cfg.Logging.Level)
+	assert.Equal(t, "json", cfg.Logging.Format, "the environment wins over the fallback")
+	assert.Error(t, cfg.Fallback("logging.colour", "auto", "admin-config.yaml"))
+}
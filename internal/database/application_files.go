@@ -0,0 +1,76 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// ApplicationFile is an auxiliary file uploaded together with a Score spec, e.g. a
+// values.yaml or an .env template
+type ApplicationFile struct {
+	ID              int64     `json:"id"`
+	ApplicationName string    `json:"application_name"`
+	Name            string    `json:"name"`
+	Content         string    `json:"-"`
+	Size            int       `json:"size"`
+	Digest          string    `json:"digest"`
+	SpecDigest      string    `json:"spec_digest"` // Score spec revision the file was uploaded with
+	UploadedBy      string    `json:"uploaded_by"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ReplaceApplicationFiles stores the files of a new spec revision. Files of earlier
+// revisions are removed, so an application only has the files of its current spec.
+func (d *Database) ReplaceApplicationFiles(appName string, files []*ApplicationFile) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM application_files WHERE application_name = $1`, appName); err != nil {
+		return fmt.Errorf("failed to clear application files: %w", err)
+	}
+	for _, file := range files {
+		err := tx.QueryRow(`
+			INSERT INTO application_files (application_name, name, content, digest, spec_digest, uploaded_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at
+		`, appName, file.Name, file.Content, file.Digest, file.SpecDigest, file.UploadedBy).Scan(&file.ID, &file.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to store application file %s: %w", file.Name, err)
+		}
+		file.ApplicationName = appName
+		file.Size = len(file.Content)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit application files: %w", err)
+	}
+	return nil
+}
+
+// ListApplicationFiles returns the files of an application's current spec revision, sorted by name
+func (d *Database) ListApplicationFiles(appName string) ([]*ApplicationFile, error) {
+	rows, err := d.db.Query(`
+		SELECT id, application_name, name, content, digest, spec_digest, uploaded_by, created_at
+		FROM application_files
+		WHERE application_name = $1
+		ORDER BY name
+	`, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query application files: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	files := []*ApplicationFile{}
+	for rows.Next() {
+		file := &ApplicationFile{}
+		if err := rows.Scan(&file.ID, &file.ApplicationName, &file.Name, &file.Content,
+			&file.Digest, &file.SpecDigest, &file.UploadedBy, &file.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan application file: %w", err)
+		}
+		file.Size = len(file.Content)
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// WorkflowApproval is an approval gate blocking a workflow step until a reviewer decides
+type WorkflowApproval struct {
+	ID              int64      `json:"id"`
+	ExecutionID     int64      `json:"execution_id"`
+	StepID          int64      `json:"step_id"`
+	StepName        string     `json:"step_name"`
+	ApplicationName string     `json:"application_name"`
+	Environment     string     `json:"environment,omitempty"`
+	Summary         string     `json:"summary,omitempty"`
+	ArtifactPath    string     `json:"artifact_path,omitempty"`
+	PlanHash        string     `json:"plan_hash,omitempty"`
+	Status          string     `json:"status"`
+	RequestedAt     time.Time  `json:"requested_at"`
+	DecidedBy       *string    `json:"decided_by,omitempty"`
+	DecidedAt       *time.Time `json:"decided_at,omitempty"`
+	Comment         *string    `json:"comment,omitempty"`
+}
+
+// Approval status constants
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+	ApprovalStatusExpired  = "expired"
+)
+
+// CreateWorkflowApproval records a new pending approval gate
+func (d *Database) CreateWorkflowApproval(approval *WorkflowApproval) error {
+	query := `
+		INSERT INTO workflow_approvals (execution_id, step_id, step_name, application_name, environment, summary, artifact_path, plan_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, status, requested_at
+	`
+
+	err := d.db.QueryRow(query,
+		approval.ExecutionID, approval.StepID, approval.StepName, approval.ApplicationName,
+		approval.Environment, approval.Summary, approval.ArtifactPath, approval.PlanHash,
+	).Scan(&approval.ID, &approval.Status, &approval.RequestedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create workflow approval: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorkflowApproval retrieves an approval gate by ID
+func (d *Database) GetWorkflowApproval(id int64) (*WorkflowApproval, error) {
+	query := `
+		SELECT id, execution_id, step_id, step_name, application_name, environment, summary, artifact_path, plan_hash,
+		       status, requested_at, decided_by, decided_at, comment
+		FROM workflow_approvals
+		WHERE id = $1
+	`
+
+	approvals, err := d.queryWorkflowApprovals(query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvals) == 0 {
+		return nil, fmt.Errorf("workflow approval not found")
+	}
+
+	return approvals[0], nil
+}
+
+// ListWorkflowApprovals returns approval gates, optionally filtered by status
+func (d *Database) ListWorkflowApprovals(status string) ([]*WorkflowApproval, error) {
+	query := `
+		SELECT id, execution_id, step_id, step_name, application_name, environment, summary, artifact_path, plan_hash,
+		       status, requested_at, decided_by, decided_at, comment
+		FROM workflow_approvals
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY requested_at DESC
+	`
+
+	return d.queryWorkflowApprovals(query, status)
+}
+
+// DecideWorkflowApproval approves, rejects or expires a pending approval gate.
+// Returns an error if the approval has already been decided.
+func (d *Database) DecideWorkflowApproval(id int64, status, decidedBy, comment string) error {
+	query := `
+		UPDATE workflow_approvals
+		SET status = $2, decided_by = $3, decided_at = NOW(), comment = NULLIF($4, '')
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	result, err := d.db.Exec(query, id, status, decidedBy, comment)
+	if err != nil {
+		return fmt.Errorf("failed to update workflow approval: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("workflow approval %d not found or already decided", id)
+	}
+
+	return nil
+}
+
+func (d *Database) queryWorkflowApprovals(query string, arg interface{}) ([]*WorkflowApproval, error) {
+	rows, err := d.db.Query(query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workflow approvals: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	approvals := []*WorkflowApproval{}
+	for rows.Next() {
+		var a WorkflowApproval
+		var decidedBy, comment sql.NullString
+		var decidedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.ExecutionID, &a.StepID, &a.StepName, &a.ApplicationName, &a.Environment,
+			&a.Summary, &a.ArtifactPath, &a.PlanHash, &a.Status, &a.RequestedAt, &decidedBy, &decidedAt, &comment); err != nil {
+			return nil, fmt.Errorf("failed to scan workflow approval: %w", err)
+		}
+		if decidedBy.Valid {
+			a.DecidedBy = &decidedBy.String
+		}
+		if decidedAt.Valid {
+			a.DecidedAt = &decidedAt.Time
+		}
+		if comment.Valid {
+			a.Comment = &comment.String
+		}
+		approvals = append(approvals, &a)
+	}
+
+	return approvals, rows.Err()
+}
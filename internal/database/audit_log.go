@@ -0,0 +1,120 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Audit log actions
+const (
+	AuditActionForceResourceState = "resource.force_state"
+)
+
+// AuditEntry is an administrative action that bypassed the regular workflows
+type AuditEntry struct {
+	ID         int64                  `json:"id"`
+	Actor      string                 `json:"actor"`
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"target_type"` // e.g. resource
+	TargetID   string                 `json:"target_id"`
+	Reason     string                 `json:"reason"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// AuditFilter selects audit log entries; empty fields match all entries
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	Limit      int // Default 100
+}
+
+// queryRower is implemented by *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// insertAuditEntry writes an audit log entry and sets its ID and creation time. Within a
+// transaction, the entry is only kept if the action it records is.
+func insertAuditEntry(q queryRower, entry *AuditEntry) error {
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+	if entry.Details == nil {
+		details = []byte("{}")
+	}
+	err = q.QueryRow(`
+		INSERT INTO audit_log (actor, action, target_type, target_id, reason, details)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, entry.Actor, entry.Action, entry.TargetType, entry.TargetID, entry.Reason, details).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// RecordAudit writes an audit log entry
+func (d *Database) RecordAudit(entry *AuditEntry) error {
+	return insertAuditEntry(d.db, entry)
+}
+
+// ListAuditEntries returns the audit log entries matching filter, newest first
+func (d *Database) ListAuditEntries(filter AuditFilter) ([]*AuditEntry, error) {
+	var conditions []string
+	var args []interface{}
+	for _, field := range []struct {
+		column string
+		value  string
+	}{
+		{"actor", filter.Actor},
+		{"action", filter.Action},
+		{"target_type", filter.TargetType},
+		{"target_id", filter.TargetID},
+	} {
+		if field.value != "" {
+			args = append(args, field.value)
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", field.column, len(args)))
+		}
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	args = append(args, limit)
+
+	query := `SELECT id, actor, action, target_type, target_id, reason, details, created_at FROM audit_log`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	entries := []*AuditEntry{}
+	for rows.Next() {
+		var entry AuditEntry
+		var details []byte
+		if err := rows.Scan(&entry.ID, &entry.Actor, &entry.Action, &entry.TargetType, &entry.TargetID,
+			&entry.Reason, &details, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &entry.Details); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal audit details: %w", err)
+			}
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Cluster status constants
+const (
+	ClusterStatusReady          = "ready"
+	ClusterStatusDegraded       = "degraded"
+	ClusterStatusDecommissioned = "decommissioned"
+)
+
+// Cluster is a Kubernetes cluster in the multi-cluster registry
+type Cluster struct {
+	ID           int64             `json:"id"`
+	Name         string            `json:"name"`
+	Environment  string            `json:"environment"`
+	APIServer    string            `json:"api_server"`
+	KubeContext  string            `json:"kube_context,omitempty"`
+	Labels       map[string]string `json:"labels"`
+	Components   []string          `json:"components"`
+	Status       string            `json:"status"`
+	ExecutionID  *int64            `json:"execution_id,omitempty"`
+	RegisteredAt time.Time         `json:"registered_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// RegisterCluster adds a cluster to the registry. Registering an existing name updates
+// the entry, so bootstrapping a cluster again is idempotent.
+func (d *Database) RegisterCluster(cluster *Cluster) error {
+	labels := cluster.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labelsJSON, err := json.Marshal(labels)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster labels: %w", err)
+	}
+
+	components := cluster.Components
+	if components == nil {
+		components = []string{}
+	}
+	status := cluster.Status
+	if status == "" {
+		status = ClusterStatusReady
+	}
+
+	query := `
+		INSERT INTO clusters (name, environment, api_server, kube_context, labels, components, status, execution_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (name) DO UPDATE SET
+			environment = EXCLUDED.environment,
+			api_server = EXCLUDED.api_server,
+			kube_context = EXCLUDED.kube_context,
+			labels = EXCLUDED.labels,
+			components = EXCLUDED.components,
+			status = EXCLUDED.status,
+			execution_id = EXCLUDED.execution_id,
+			updated_at = NOW()
+		RETURNING id, registered_at, updated_at
+	`
+
+	err = d.db.QueryRow(query,
+		cluster.Name, cluster.Environment, cluster.APIServer, cluster.KubeContext,
+		labelsJSON, pq.Array(components), status, cluster.ExecutionID,
+	).Scan(&cluster.ID, &cluster.RegisteredAt, &cluster.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to register cluster: %w", err)
+	}
+	cluster.Labels = labels
+	cluster.Components = components
+	cluster.Status = status
+
+	return nil
+}
+
+// GetCluster returns a registered cluster by name
+func (d *Database) GetCluster(name string) (*Cluster, error) {
+	query := `
+		SELECT id, name, environment, api_server, kube_context, labels, components, status, execution_id, registered_at, updated_at
+		FROM clusters
+		WHERE name = $1
+	`
+
+	cluster, err := scanCluster(d.db.QueryRow(query, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("cluster not found: %s", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+	return cluster, nil
+}
+
+// ListClusters returns registered clusters, optionally filtered by environment
+func (d *Database) ListClusters(environment string) ([]*Cluster, error) {
+	query := `
+		SELECT id, name, environment, api_server, kube_context, labels, components, status, execution_id, registered_at, updated_at
+		FROM clusters
+		WHERE ($1 = '' OR environment = $1)
+		ORDER BY environment, name
+	`
+
+	rows, err := d.db.Query(query, environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query clusters: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	clusters := []*Cluster{}
+	for rows.Next() {
+		cluster, err := scanCluster(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cluster: %w", err)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters, rows.Err()
+}
+
+// RegisterCluster adds a cluster to the registry on behalf of a workflow step
+func (r *WorkflowRepository) RegisterCluster(cluster *Cluster) error {
+	return r.db.RegisterCluster(cluster)
+}
+
+type clusterScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCluster(row clusterScanner) (*Cluster, error) {
+	var c Cluster
+	var labelsJSON []byte
+	var executionID sql.NullInt64
+	if err := row.Scan(&c.ID, &c.Name, &c.Environment, &c.APIServer, &c.KubeContext, &labelsJSON,
+		pq.Array(&c.Components), &c.Status, &executionID, &c.RegisteredAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(labelsJSON, &c.Labels); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cluster labels: %w", err)
+	}
+	if executionID.Valid {
+		c.ExecutionID = &executionID.Int64
+	}
+	return &c, nil
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Compensation status constants
+const (
+	CompensationStatusPending    = "pending"
+	CompensationStatusRolledBack = "rolled_back"
+	CompensationStatusFailed     = "failed"
+)
+
+// StepTypeCompensation is the step type of the step records that roll back a failed run
+const StepTypeCompensation = "compensation"
+
+// WorkflowCompensation is a resource a workflow execution created, with what is needed to
+// remove it again when the run fails
+type WorkflowCompensation struct {
+	ID            int64             `json:"id"`
+	ExecutionID   int64             `json:"execution_id"`
+	StepName      string            `json:"step_name"`
+	Kind          string            `json:"kind"`
+	Target        string            `json:"target"`
+	Params        map[string]string `json:"params"`
+	Status        string            `json:"status"`
+	ErrorMessage  *string           `json:"error_message,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	CompensatedAt *time.Time        `json:"compensated_at,omitempty"`
+}
+
+// RecordCompensation stores a resource created by a workflow step
+func (d *Database) RecordCompensation(compensation *WorkflowCompensation) error {
+	params := compensation.Params
+	if params == nil {
+		params = map[string]string{}
+	}
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal compensation params: %w", err)
+	}
+
+	query := `
+		INSERT INTO workflow_compensations (execution_id, step_name, kind, target, params, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err = d.db.QueryRow(query,
+		compensation.ExecutionID, compensation.StepName, compensation.Kind, compensation.Target,
+		paramsJSON, CompensationStatusPending,
+	).Scan(&compensation.ID, &compensation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record compensation: %w", err)
+	}
+	compensation.Params = params
+	compensation.Status = CompensationStatusPending
+
+	return nil
+}
+
+// ListCompensations returns the resources created by an execution in creation order
+func (d *Database) ListCompensations(executionID int64) ([]*WorkflowCompensation, error) {
+	query := `
+		SELECT id, execution_id, step_name, kind, target, params, status, error_message, created_at, compensated_at
+		FROM workflow_compensations
+		WHERE execution_id = $1
+		ORDER BY id
+	`
+
+	rows, err := d.db.Query(query, executionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query compensations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	compensations := []*WorkflowCompensation{}
+	for rows.Next() {
+		var c WorkflowCompensation
+		var paramsJSON []byte
+		if err := rows.Scan(&c.ID, &c.ExecutionID, &c.StepName, &c.Kind, &c.Target, &paramsJSON,
+			&c.Status, &c.ErrorMessage, &c.CreatedAt, &c.CompensatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan compensation: %w", err)
+		}
+		if err := json.Unmarshal(paramsJSON, &c.Params); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal compensation params: %w", err)
+		}
+		compensations = append(compensations, &c)
+	}
+
+	return compensations, rows.Err()
+}
+
+// UpdateCompensationStatus records the outcome of rolling back a resource
+func (d *Database) UpdateCompensationStatus(id int64, status string, errorMessage *string) error {
+	query := `
+		UPDATE workflow_compensations
+		SET status = $2, error_message = $3, compensated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := d.db.Exec(query, id, status, errorMessage)
+	if err != nil {
+		return fmt.Errorf("failed to update compensation: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("compensation not found: %d", id)
+	}
+	return nil
+}
+
+// RecordCompensation stores a resource created by a workflow step
+func (r *WorkflowRepository) RecordCompensation(compensation *WorkflowCompensation) error {
+	return r.db.RecordCompensation(compensation)
+}
+
+// ListCompensations returns the resources created by an execution in creation order
+func (r *WorkflowRepository) ListCompensations(executionID int64) ([]*WorkflowCompensation, error) {
+	return r.db.ListCompensations(executionID)
+}
+
+// UpdateCompensationStatus records the outcome of rolling back a resource
+func (r *WorkflowRepository) UpdateCompensationStatus(id int64, status string, errorMessage *string) error {
+	return r.db.UpdateCompensationStatus(id, status, errorMessage)
+}
@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"sync/atomic"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -16,8 +17,10 @@ import (
 
 // Database wraps the SQL database connection
 type Database struct {
-	db           *sql.DB
-	migrationsFS fs.FS // Optional: embedded migrations filesystem
+	db                *sql.DB
+	config            Config      // Connection settings, also used by psql migrations
+	migrationsFS      fs.FS       // Optional: embedded migrations filesystem
+	migrationsApplied atomic.Bool // Set once RunMigrations completed successfully
 }
 
 // Config holds database configuration
@@ -30,11 +33,14 @@ type Config struct {
 	SSLMode  string
 }
 
-// NewDatabase creates a new database connection
+// NewDatabase creates a new database connection configured by the DB_* environment variables
 func NewDatabase() (*Database, error) {
-	logger := logging.NewStructuredLogger("database")
+	return NewDatabaseWithConfig(ConfigFromEnv())
+}
 
-	config := Config{
+// ConfigFromEnv reads the connection settings from the DB_* environment variables
+func ConfigFromEnv() Config {
+	return Config{
 		Host:     getEnvWithDefault("DB_HOST", "localhost"),
 		Port:     getEnvWithDefault("DB_PORT", "5432"),
 		User:     getEnvWithDefault("DB_USER", "postgres"),
@@ -42,6 +48,11 @@ func NewDatabase() (*Database, error) {
 		DBName:   getEnvWithDefault("DB_NAME", "idp_orchestrator"),
 		SSLMode:  getEnvWithDefault("DB_SSLMODE", "disable"),
 	}
+}
+
+// NewDatabaseWithConfig creates a new database connection with custom config
+func NewDatabaseWithConfig(config Config) (*Database, error) {
+	logger := logging.NewStructuredLogger("database")
 
 	// Build connection string - omit password if empty to avoid lib/pq default behavior
 	connStr := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=%s",
@@ -84,35 +95,7 @@ func NewDatabase() (*Database, error) {
 		})
 	}
 
-	return &Database{db: db}, nil
-}
-
-// NewDatabaseWithConfig creates a new database connection with custom config
-func NewDatabaseWithConfig(config Config) (*Database, error) {
-	// Build connection string - omit password if empty to avoid lib/pq default behavior
-	connStr := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.DBName, config.SSLMode)
-	if config.Password != "" {
-		connStr = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-			config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
-	}
-
-	db, err := sql.Open("postgres", connStr)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database connection: %w", err)
-	}
-
-	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
-	db.SetConnMaxLifetime(5 * time.Minute)
-
-	// Test the connection
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
-	return &Database{db: db}, nil
+	return &Database{db: db, config: config}, nil
 }
 
 // Close closes the database connection
@@ -138,6 +121,11 @@ func (d *Database) SetMigrationsFS(fsys fs.FS) {
 	d.migrationsFS = fsys
 }
 
+// MigrationsApplied reports whether RunMigrations has completed successfully
+func (d *Database) MigrationsApplied() bool {
+	return d != nil && d.migrationsApplied.Load()
+}
+
 // Ping tests the database connection
 func (d *Database) Ping() error {
 	if d == nil || d.db == nil {
@@ -318,7 +306,7 @@ CREATE TRIGGER update_resource_instances_updated_at
 -- Check constraint for valid resource states
 ALTER TABLE resource_instances DROP CONSTRAINT IF EXISTS chk_resource_state;
 ALTER TABLE resource_instances ADD CONSTRAINT chk_resource_state
-    CHECK (state IN ('requested', 'provisioning', 'active', 'scaling', 'updating', 'degraded', 'terminating', 'terminated', 'failed'));
+    CHECK (state IN ('requested', 'provisioning', 'active', 'scaling', 'updating', 'degraded', 'terminating', 'terminated', 'failed', 'hibernated'));
 
 -- Check constraint for valid health status
 ALTER TABLE resource_instances DROP CONSTRAINT IF EXISTS chk_health_status;
@@ -401,6 +389,7 @@ func (d *Database) RunMigrations() error {
 		})
 	}
 
+	d.migrationsApplied.Store(true)
 	return nil
 }
 
@@ -413,16 +402,16 @@ func (d *Database) runFilesystemMigration(logger *logging.ZerologAdapter, file s
 
 	// Execute migration using psql directly for proper multi-statement support
 	psqlCmd := fmt.Sprintf("psql -d %s -f %s",
-		getEnvWithDefault("DB_NAME", "idp_orchestrator"),
+		d.config.DBName,
 		file,
 	)
 
 	// Set environment variables for psql connection
 	cmd := fmt.Sprintf("PGHOST=%s PGPORT=%s PGUSER=%s PGPASSWORD=%s %s",
-		getEnvWithDefault("DB_HOST", "localhost"),
-		getEnvWithDefault("DB_PORT", "5432"),
-		getEnvWithDefault("DB_USER", "postgres"),
-		getEnvWithDefault("DB_PASSWORD", ""),
+		d.config.Host,
+		d.config.Port,
+		d.config.User,
+		d.config.Password,
 		psqlCmd,
 	)
 
@@ -631,6 +620,15 @@ func (d *Database) DeleteAPIKey(username, keyName string) error {
 	return nil
 }
 
+// DeleteUserAPIKeys removes all API keys of a user
+func (d *Database) DeleteUserAPIKeys(username string) (int64, error) {
+	result, err := d.db.Exec(`DELETE FROM user_api_keys WHERE username = $1`, username)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete user API keys: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // GetUserByAPIKeyHash retrieves user information by API key hash
 func (d *Database) GetUserByAPIKeyHash(keyHash string) (username string, team string, role string, err error) {
 	// First check if key exists and is not expired
@@ -361,3 +361,52 @@ func TestDatabaseLifecycle(t *testing.T) {
 	err = testDB.DB.Ping()
 	assert.Error(t, err)
 }
+
+func TestDirectoryUsersAndTeams(t *testing.T) {
+	testDB := SetupTestDatabase(t)
+	defer func() { _ = testDB.Close() }()
+	db := testDB.DB
+
+	alice := &DirectoryUser{Username: "alice", Email: "alice@example.com", Active: true}
+	bob := &DirectoryUser{Username: "bob", Active: true}
+	for _, user := range []*DirectoryUser{alice, bob} {
+		if err := db.CreateDirectoryUser(user); err != nil {
+			t.Fatalf("CreateDirectoryUser(%s) error = %v", user.Username, err)
+		}
+	}
+	if err := db.CreateDirectoryUser(&DirectoryUser{Username: "alice"}); err != ErrDirectoryConflict {
+		t.Errorf("CreateDirectoryUser(duplicate) error = %v, want ErrDirectoryConflict", err)
+	}
+
+	team := &DirectoryTeam{Name: "payments", Members: []DirectoryMember{{UserID: bob.ID}, {UserID: alice.ID}}}
+	if err := db.CreateDirectoryTeam(team); err != nil {
+		t.Fatalf("CreateDirectoryTeam() error = %v", err)
+	}
+	assert.Equal(t, []DirectoryMember{{UserID: alice.ID, Username: "alice"}, {UserID: bob.ID, Username: "bob"}}, team.Members)
+
+	got, err := db.GetDirectoryUserByUsername("alice")
+	if err != nil {
+		t.Fatalf("GetDirectoryUserByUsername() error = %v", err)
+	}
+	assert.Equal(t, []string{"payments"}, got.Teams)
+
+	users, total, err := db.ListDirectoryUsers("", 1, 10)
+	if err != nil {
+		t.Fatalf("ListDirectoryUsers() error = %v", err)
+	}
+	assert.Equal(t, 2, total)
+	assert.Len(t, users, 1)
+
+	if err := db.DeleteDirectoryUser(alice.ID); err != nil {
+		t.Fatalf("DeleteDirectoryUser() error = %v", err)
+	}
+	team, err = db.GetDirectoryTeam(team.ID)
+	if err != nil {
+		t.Fatalf("GetDirectoryTeam() error = %v", err)
+	}
+	assert.Equal(t, []DirectoryMember{{UserID: bob.ID, Username: "bob"}}, team.Members)
+
+	if _, err := db.GetDirectoryUser(alice.ID); err != ErrDirectoryNotFound {
+		t.Errorf("GetDirectoryUser(deleted) error = %v, want ErrDirectoryNotFound", err)
+	}
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/licenses"
+	"time"
+)
+
+// DependencyReport is the SBOM a build or scan step recorded for an application version
+type DependencyReport struct {
+	ID              int64                `json:"id"`
+	ApplicationName string               `json:"application_name"`
+	Version         string               `json:"version"`
+	ExecutionID     *int64               `json:"execution_id,omitempty"`
+	StepName        string               `json:"step_name"`
+	Format          string               `json:"format"`
+	Image           string               `json:"image,omitempty"`
+	BaseImage       string               `json:"base_image,omitempty"`
+	Components      []licenses.Component `json:"components"`
+	CreatedAt       time.Time            `json:"created_at"`
+}
+
+// CreateDependencyReport stores the SBOM of a step
+func (d *Database) CreateDependencyReport(r *DependencyReport) error {
+	components, err := json.Marshal(r.Components)
+	if err != nil {
+		return fmt.Errorf("failed to marshal components: %w", err)
+	}
+
+	err = d.db.QueryRow(`
+		INSERT INTO dependency_reports (application_name, version, execution_id, step_name, format, image, base_image, components)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, r.ApplicationName, r.Version, r.ExecutionID, r.StepName, r.Format, r.Image, r.BaseImage, components,
+	).Scan(&r.ID, &r.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create dependency report: %w", err)
+	}
+	return nil
+}
+
+// ListDependencyReportVersions returns the versions of an application with dependency
+// reports, most recently recorded first
+func (d *Database) ListDependencyReportVersions(appName string) ([]string, error) {
+	rows, err := d.db.Query(`
+		SELECT version
+		FROM dependency_reports
+		WHERE application_name = $1
+		GROUP BY version
+		ORDER BY MAX(created_at) DESC, version
+	`, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dependency report versions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	versions := []string{}
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency report version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// ListDependencyReports returns the dependency reports of an application version, oldest first
+func (d *Database) ListDependencyReports(appName, version string) ([]*DependencyReport, error) {
+	rows, err := d.db.Query(`
+		SELECT id, application_name, version, execution_id, step_name, format, image, base_image, components, created_at
+		FROM dependency_reports
+		WHERE application_name = $1 AND version = $2
+		ORDER BY created_at, id
+	`, appName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dependency reports: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	reports := []*DependencyReport{}
+	for rows.Next() {
+		var r DependencyReport
+		var executionID sql.NullInt64
+		var components []byte
+		if err := rows.Scan(&r.ID, &r.ApplicationName, &r.Version, &executionID, &r.StepName, &r.Format,
+			&r.Image, &r.BaseImage, &components, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency report: %w", err)
+		}
+		if executionID.Valid {
+			r.ExecutionID = &executionID.Int64
+		}
+		if err := json.Unmarshal(components, &r.Components); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal components of dependency report %d: %w", r.ID, err)
+		}
+		reports = append(reports, &r)
+	}
+	return reports, rows.Err()
+}
@@ -0,0 +1,346 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Directory errors
+var (
+	ErrDirectoryNotFound = errors.New("directory entry not found")
+	ErrDirectoryConflict = errors.New("directory entry already exists")
+)
+
+// DirectoryUser is a user provisioned by the identity provider
+type DirectoryUser struct {
+	ID          int64     `json:"id"`
+	Username    string    `json:"username"`
+	ExternalID  string    `json:"external_id,omitempty"`
+	DisplayName string    `json:"display_name,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Active      bool      `json:"active"`
+	Teams       []string  `json:"teams"` // Names of the teams the user is a member of, sorted
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DirectoryTeam is a team provisioned by the identity provider
+type DirectoryTeam struct {
+	ID         int64             `json:"id"`
+	Name       string            `json:"name"`
+	ExternalID string            `json:"external_id,omitempty"`
+	Members    []DirectoryMember `json:"members"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// DirectoryMember is a member of a directory team
+type DirectoryMember struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// directoryError maps unique violations to ErrDirectoryConflict
+func directoryError(action string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDirectoryConflict
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+// CreateDirectoryUser provisions a user
+func (d *Database) CreateDirectoryUser(user *DirectoryUser) error {
+	query := `
+		INSERT INTO directory_users (username, external_id, display_name, email, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	err := d.db.QueryRow(query, user.Username, user.ExternalID, user.DisplayName, user.Email, user.Active).
+		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return directoryError("create directory user", err)
+	}
+	user.Teams = []string{}
+	return nil
+}
+
+// UpdateDirectoryUser replaces the attributes of a provisioned user
+func (d *Database) UpdateDirectoryUser(user *DirectoryUser) error {
+	query := `
+		UPDATE directory_users
+		SET username = $1, external_id = $2, display_name = $3, email = $4, active = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING created_at, updated_at
+	`
+	err := d.db.QueryRow(query, user.Username, user.ExternalID, user.DisplayName, user.Email, user.Active, user.ID).
+		Scan(&user.CreatedAt, &user.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrDirectoryNotFound
+	}
+	if err != nil {
+		return directoryError("update directory user", err)
+	}
+	return nil
+}
+
+// GetDirectoryUser returns a provisioned user by ID
+func (d *Database) GetDirectoryUser(id int64) (*DirectoryUser, error) {
+	return d.getDirectoryUser(`WHERE u.id = $1`, id)
+}
+
+// GetDirectoryUserByUsername returns a provisioned user by username
+func (d *Database) GetDirectoryUserByUsername(username string) (*DirectoryUser, error) {
+	return d.getDirectoryUser(`WHERE u.username = $1`, username)
+}
+
+func (d *Database) getDirectoryUser(where string, arg interface{}) (*DirectoryUser, error) {
+	users, _, err := d.queryDirectoryUsers(where, []interface{}{arg}, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, ErrDirectoryNotFound
+	}
+	return users[0], nil
+}
+
+// ListDirectoryUsers returns provisioned users ordered by ID, optionally filtered by
+// username, with the total number of matches
+func (d *Database) ListDirectoryUsers(username string, offset, limit int) ([]*DirectoryUser, int, error) {
+	return d.queryDirectoryUsers(`WHERE ($1 = '' OR u.username = $1)`, []interface{}{username}, offset, limit)
+}
+
+func (d *Database) queryDirectoryUsers(where string, args []interface{}, offset, limit int) ([]*DirectoryUser, int, error) {
+	var total int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM directory_users u `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count directory users: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT u.id, u.username, u.external_id, u.display_name, u.email, u.active, u.created_at, u.updated_at,
+		       COALESCE(ARRAY(
+		           SELECT t.name FROM directory_teams t
+		           JOIN directory_team_members m ON m.team_id = t.id
+		           WHERE m.user_id = u.id ORDER BY t.name
+		       ), '{}')
+		FROM directory_users u
+		%s
+		ORDER BY u.id
+		OFFSET $%d LIMIT $%d
+	`, where, len(args)+1, len(args)+2)
+
+	rows, err := d.db.Query(query, append(args, offset, limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query directory users: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	users := []*DirectoryUser{}
+	for rows.Next() {
+		var u DirectoryUser
+		if err := rows.Scan(&u.ID, &u.Username, &u.ExternalID, &u.DisplayName, &u.Email, &u.Active,
+			&u.CreatedAt, &u.UpdatedAt, pq.Array(&u.Teams)); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan directory user: %w", err)
+		}
+		users = append(users, &u)
+	}
+	return users, total, rows.Err()
+}
+
+// DeleteDirectoryUser deprovisions a user and removes it from its teams
+func (d *Database) DeleteDirectoryUser(id int64) error {
+	result, err := d.db.Exec(`DELETE FROM directory_users WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete directory user: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrDirectoryNotFound
+	}
+	return nil
+}
+
+// CreateDirectoryTeam provisions a team with its members
+func (d *Database) CreateDirectoryTeam(team *DirectoryTeam) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = tx.QueryRow(`
+		INSERT INTO directory_teams (name, external_id) VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`, team.Name, team.ExternalID).Scan(&team.ID, &team.CreatedAt, &team.UpdatedAt)
+	if err != nil {
+		return directoryError("create directory team", err)
+	}
+	if err := addDirectoryTeamMembers(tx, team.ID, memberIDs(team.Members)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit directory team: %w", err)
+	}
+
+	created, err := d.GetDirectoryTeam(team.ID)
+	if err != nil {
+		return err
+	}
+	*team = *created
+	return nil
+}
+
+// UpdateDirectoryTeam renames a team and replaces its members
+func (d *Database) UpdateDirectoryTeam(team *DirectoryTeam) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.Exec(`UPDATE directory_teams SET name = $1, external_id = $2, updated_at = NOW() WHERE id = $3`,
+		team.Name, team.ExternalID, team.ID)
+	if err != nil {
+		return directoryError("update directory team", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrDirectoryNotFound
+	}
+	if _, err := tx.Exec(`DELETE FROM directory_team_members WHERE team_id = $1`, team.ID); err != nil {
+		return fmt.Errorf("failed to clear directory team members: %w", err)
+	}
+	if err := addDirectoryTeamMembers(tx, team.ID, memberIDs(team.Members)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit directory team: %w", err)
+	}
+
+	updated, err := d.GetDirectoryTeam(team.ID)
+	if err != nil {
+		return err
+	}
+	*team = *updated
+	return nil
+}
+
+// GetDirectoryTeam returns a provisioned team with its members
+func (d *Database) GetDirectoryTeam(id int64) (*DirectoryTeam, error) {
+	teams, _, err := d.queryDirectoryTeams(`WHERE t.id = $1`, []interface{}{id}, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return nil, ErrDirectoryNotFound
+	}
+	return teams[0], nil
+}
+
+// ListDirectoryTeams returns provisioned teams ordered by ID, optionally filtered by
+// name, with the total number of matches
+func (d *Database) ListDirectoryTeams(name string, offset, limit int) ([]*DirectoryTeam, int, error) {
+	return d.queryDirectoryTeams(`WHERE ($1 = '' OR t.name = $1)`, []interface{}{name}, offset, limit)
+}
+
+func (d *Database) queryDirectoryTeams(where string, args []interface{}, offset, limit int) ([]*DirectoryTeam, int, error) {
+	var total int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM directory_teams t `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count directory teams: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.id, t.name, t.external_id, t.created_at, t.updated_at
+		FROM directory_teams t
+		%s
+		ORDER BY t.id
+		OFFSET $%d LIMIT $%d
+	`, where, len(args)+1, len(args)+2)
+
+	rows, err := d.db.Query(query, append(args, offset, limit)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query directory teams: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	teams := []*DirectoryTeam{}
+	for rows.Next() {
+		var t DirectoryTeam
+		if err := rows.Scan(&t.ID, &t.Name, &t.ExternalID, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan directory team: %w", err)
+		}
+		teams = append(teams, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	for _, team := range teams {
+		members, err := d.directoryTeamMembers(team.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		team.Members = members
+	}
+	return teams, total, nil
+}
+
+func (d *Database) directoryTeamMembers(teamID int64) ([]DirectoryMember, error) {
+	rows, err := d.db.Query(`
+		SELECT u.id, u.username FROM directory_users u
+		JOIN directory_team_members m ON m.user_id = u.id
+		WHERE m.team_id = $1
+		ORDER BY u.username
+	`, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query directory team members: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	members := []DirectoryMember{}
+	for rows.Next() {
+		var m DirectoryMember
+		if err := rows.Scan(&m.UserID, &m.Username); err != nil {
+			return nil, fmt.Errorf("failed to scan directory team member: %w", err)
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// DeleteDirectoryTeam deprovisions a team; its members remain provisioned
+func (d *Database) DeleteDirectoryTeam(id int64) error {
+	result, err := d.db.Exec(`DELETE FROM directory_teams WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete directory team: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrDirectoryNotFound
+	}
+	return nil
+}
+
+func addDirectoryTeamMembers(tx *sql.Tx, teamID int64, userIDs []int64) error {
+	for _, userID := range userIDs {
+		_, err := tx.Exec(`
+			INSERT INTO directory_team_members (team_id, user_id)
+			SELECT $1, id FROM directory_users WHERE id = $2
+			ON CONFLICT DO NOTHING
+		`, teamID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to add directory team member %d: %w", userID, err)
+		}
+	}
+	return nil
+}
+
+func memberIDs(members []DirectoryMember) []int64 {
+	ids := make([]int64, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.UserID)
+	}
+	return ids
+}
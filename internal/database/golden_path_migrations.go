@@ -0,0 +1,160 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Golden path migration statuses
+const (
+	MigrationStatusPending   = "pending"
+	MigrationStatusRunning   = "running"
+	MigrationStatusSucceeded = "succeeded"
+	MigrationStatusFailed    = "failed"
+)
+
+// GoldenPathApplication is an application whose latest successful golden path run used a given path
+type GoldenPathApplication struct {
+	ApplicationName string            `json:"application_name"`
+	Team            string            `json:"team"`
+	ExecutionID     int64             `json:"execution_id"`
+	DeployedAt      time.Time         `json:"deployed_at"`
+	Parameters      map[string]string `json:"parameters"`
+}
+
+// GoldenPathMigration tracks the re-run of an application from a deprecated golden path
+// through its successor
+type GoldenPathMigration struct {
+	ID              int64             `json:"id"`
+	GoldenPath      string            `json:"golden_path"`
+	Successor       string            `json:"successor"`
+	ApplicationName string            `json:"application_name"`
+	Team            string            `json:"team"`
+	Status          string            `json:"status"`
+	ExecutionID     *int64            `json:"execution_id,omitempty"`
+	Parameters      map[string]string `json:"parameters"`
+	ErrorMessage    *string           `json:"error_message,omitempty"`
+	StartedBy       string            `json:"started_by"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// ListGoldenPathApplications returns the applications whose latest completed golden path
+// execution ran the given golden path, sorted by team and name. Applications that moved
+// to another golden path since are not included.
+func (d *Database) ListGoldenPathApplications(goldenPath string) ([]*GoldenPathApplication, error) {
+	rows, err := d.db.Query(`
+		SELECT a.name, a.team, e.id, e.started_at, e.parameters
+		FROM applications a
+		JOIN LATERAL (
+			SELECT id, workflow_name, started_at, parameters
+			FROM workflow_executions
+			WHERE application_name = a.name AND workflow_name LIKE 'golden-path-%' AND status = $2
+			ORDER BY started_at DESC, id DESC
+			LIMIT 1
+		) e ON TRUE
+		WHERE e.workflow_name = $1
+		ORDER BY a.team, a.name
+	`, "golden-path-"+goldenPath, WorkflowStatusCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query golden path applications: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	applications := []*GoldenPathApplication{}
+	for rows.Next() {
+		var app GoldenPathApplication
+		var parameters []byte
+		if err := rows.Scan(&app.ApplicationName, &app.Team, &app.ExecutionID, &app.DeployedAt, &parameters); err != nil {
+			return nil, fmt.Errorf("failed to scan golden path application: %w", err)
+		}
+		app.Parameters = make(map[string]string)
+		if err := json.Unmarshal(parameters, &app.Parameters); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal parameters of %s: %w", app.ApplicationName, err)
+		}
+		applications = append(applications, &app)
+	}
+	return applications, rows.Err()
+}
+
+// CreateGoldenPathMigration records a pending migration of an application
+func (d *Database) CreateGoldenPathMigration(m *GoldenPathMigration) error {
+	parameters, err := json.Marshal(m.Parameters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal parameters: %w", err)
+	}
+	if m.Status == "" {
+		m.Status = MigrationStatusPending
+	}
+
+	err = d.db.QueryRow(`
+		INSERT INTO golden_path_migrations (golden_path, successor, application_name, team, status, parameters, started_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`, m.GoldenPath, m.Successor, m.ApplicationName, m.Team, m.Status, parameters, m.StartedBy,
+	).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create golden path migration: %w", err)
+	}
+	return nil
+}
+
+// UpdateGoldenPathMigration sets the status of a migration, the execution that runs it
+// (0 keeps the current one) and the error it failed with
+func (d *Database) UpdateGoldenPathMigration(id int64, status string, executionID int64, errorMessage *string) error {
+	var execution *int64
+	if executionID != 0 {
+		execution = &executionID
+	}
+	_, err := d.db.Exec(`
+		UPDATE golden_path_migrations
+		SET status = $2, execution_id = COALESCE($3, execution_id), error_message = $4, updated_at = NOW()
+		WHERE id = $1
+	`, id, status, execution, errorMessage)
+	if err != nil {
+		return fmt.Errorf("failed to update golden path migration: %w", err)
+	}
+	return nil
+}
+
+// ListGoldenPathMigrations returns the migrations away from a golden path, optionally
+// filtered by team, newest first
+func (d *Database) ListGoldenPathMigrations(goldenPath, team string) ([]*GoldenPathMigration, error) {
+	rows, err := d.db.Query(`
+		SELECT id, golden_path, successor, application_name, team, status, execution_id, parameters,
+			error_message, started_by, created_at, updated_at
+		FROM golden_path_migrations
+		WHERE golden_path = $1 AND ($2 = '' OR team = $2)
+		ORDER BY created_at DESC, id DESC
+	`, goldenPath, team)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query golden path migrations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	migrations := []*GoldenPathMigration{}
+	for rows.Next() {
+		var m GoldenPathMigration
+		var executionID sql.NullInt64
+		var errorMessage sql.NullString
+		var parameters []byte
+		if err := rows.Scan(&m.ID, &m.GoldenPath, &m.Successor, &m.ApplicationName, &m.Team, &m.Status,
+			&executionID, &parameters, &errorMessage, &m.StartedBy, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan golden path migration: %w", err)
+		}
+		if executionID.Valid {
+			m.ExecutionID = &executionID.Int64
+		}
+		if errorMessage.Valid {
+			m.ErrorMessage = &errorMessage.String
+		}
+		m.Parameters = make(map[string]string)
+		if err := json.Unmarshal(parameters, &m.Parameters); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal migration parameters: %w", err)
+		}
+		migrations = append(migrations, &m)
+	}
+	return migrations, rows.Err()
+}
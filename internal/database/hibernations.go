@@ -0,0 +1,109 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ApplicationHibernation records an application whose workloads were scaled to zero and
+// whose resources were paused, with what is needed to resume it
+type ApplicationHibernation struct {
+	ApplicationName string         `json:"application_name"`
+	Reason          string         `json:"reason"`
+	HibernatedBy    string         `json:"hibernated_by"`
+	Namespace       string         `json:"namespace"`
+	Replicas        map[string]int `json:"replicas"`     // Replicas per workload (kind/name) before hibernation
+	ResourceIDs     []int64        `json:"resource_ids"` // Resource instances moved to the hibernated state
+	HibernatedAt    time.Time      `json:"hibernated_at"`
+}
+
+// SaveApplicationHibernation stores the hibernation of an application
+func (d *Database) SaveApplicationHibernation(h *ApplicationHibernation) error {
+	replicas, err := json.Marshal(h.Replicas)
+	if err != nil {
+		return fmt.Errorf("failed to marshal replicas: %w", err)
+	}
+	resourceIDs, err := json.Marshal(h.ResourceIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal resource ids: %w", err)
+	}
+
+	err = d.db.QueryRow(`
+		INSERT INTO application_hibernations (application_name, reason, hibernated_by, namespace, replicas, resource_ids)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (application_name) DO UPDATE SET
+			reason = EXCLUDED.reason, hibernated_by = EXCLUDED.hibernated_by, namespace = EXCLUDED.namespace,
+			replicas = EXCLUDED.replicas, resource_ids = EXCLUDED.resource_ids, hibernated_at = NOW()
+		RETURNING hibernated_at
+	`, h.ApplicationName, h.Reason, h.HibernatedBy, h.Namespace, replicas, resourceIDs).Scan(&h.HibernatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save application hibernation: %w", err)
+	}
+	return nil
+}
+
+// GetApplicationHibernation returns the hibernation of an application, or nil if it is not hibernated
+func (d *Database) GetApplicationHibernation(appName string) (*ApplicationHibernation, error) {
+	row := d.db.QueryRow(`
+		SELECT application_name, reason, hibernated_by, namespace, replicas, resource_ids, hibernated_at
+		FROM application_hibernations
+		WHERE application_name = $1
+	`, appName)
+	h, err := scanApplicationHibernation(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return h, err
+}
+
+// ListApplicationHibernations returns all hibernated applications, sorted by name
+func (d *Database) ListApplicationHibernations() ([]*ApplicationHibernation, error) {
+	rows, err := d.db.Query(`
+		SELECT application_name, reason, hibernated_by, namespace, replicas, resource_ids, hibernated_at
+		FROM application_hibernations
+		ORDER BY application_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query application hibernations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	hibernations := []*ApplicationHibernation{}
+	for rows.Next() {
+		h, err := scanApplicationHibernation(rows)
+		if err != nil {
+			return nil, err
+		}
+		hibernations = append(hibernations, h)
+	}
+	return hibernations, rows.Err()
+}
+
+// DeleteApplicationHibernation removes the hibernation of a resumed application
+func (d *Database) DeleteApplicationHibernation(appName string) error {
+	if _, err := d.db.Exec(`DELETE FROM application_hibernations WHERE application_name = $1`, appName); err != nil {
+		return fmt.Errorf("failed to delete application hibernation: %w", err)
+	}
+	return nil
+}
+
+func scanApplicationHibernation(row interface{ Scan(...interface{}) error }) (*ApplicationHibernation, error) {
+	var h ApplicationHibernation
+	var replicas, resourceIDs []byte
+	if err := row.Scan(&h.ApplicationName, &h.Reason, &h.HibernatedBy, &h.Namespace, &replicas, &resourceIDs, &h.HibernatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan application hibernation: %w", err)
+	}
+	if err := json.Unmarshal(replicas, &h.Replicas); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal replicas: %w", err)
+	}
+	if err := json.Unmarshal(resourceIDs, &h.ResourceIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resource ids: %w", err)
+	}
+	return &h, nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrHostnameTaken is returned when a hostname is reserved by another application
+var ErrHostnameTaken = errors.New("hostname is reserved by another application")
+
+// HostnameReservation is a hostname owned by an application in an environment
+type HostnameReservation struct {
+	Hostname        string    `json:"hostname"`
+	URL             string    `json:"url"`
+	ApplicationName string    `json:"application_name"`
+	Environment     string    `json:"environment"`
+	Cluster         string    `json:"cluster,omitempty"`
+	ReservedBy      string    `json:"reserved_by"`
+	ReservedAt      time.Time `json:"reserved_at"`
+}
+
+// ReserveHostname reserves a hostname for an application in an environment, replacing the
+// hostname it held there before. It returns ErrHostnameTaken if another application or
+// environment holds the hostname.
+func (d *Database) ReserveHostname(r *HostnameReservation) error {
+	err := d.db.QueryRow(`
+		INSERT INTO hostname_reservations (hostname, url, application_name, environment, cluster, reserved_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (application_name, environment) DO UPDATE SET
+			hostname = EXCLUDED.hostname, url = EXCLUDED.url, cluster = EXCLUDED.cluster,
+			reserved_by = EXCLUDED.reserved_by, reserved_at = NOW()
+		RETURNING reserved_at
+	`, r.Hostname, r.URL, r.ApplicationName, r.Environment, r.Cluster, r.ReservedBy).Scan(&r.ReservedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrHostnameTaken
+		}
+		return fmt.Errorf("failed to reserve hostname: %w", err)
+	}
+	return nil
+}
+
+// GetHostnameReservation returns the reservation of a hostname, or nil if it is available
+func (d *Database) GetHostnameReservation(hostname string) (*HostnameReservation, error) {
+	row := d.db.QueryRow(`
+		SELECT hostname, url, application_name, environment, cluster, reserved_by, reserved_at
+		FROM hostname_reservations
+		WHERE hostname = $1
+	`, hostname)
+
+	var r HostnameReservation
+	err := row.Scan(&r.Hostname, &r.URL, &r.ApplicationName, &r.Environment, &r.Cluster, &r.ReservedBy, &r.ReservedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hostname reservation: %w", err)
+	}
+	return &r, nil
+}
+
+// ListHostnameReservations returns the hostnames of an application, sorted by environment
+func (d *Database) ListHostnameReservations(appName string) ([]*HostnameReservation, error) {
+	rows, err := d.db.Query(`
+		SELECT hostname, url, application_name, environment, cluster, reserved_by, reserved_at
+		FROM hostname_reservations
+		WHERE application_name = $1
+		ORDER BY environment
+	`, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hostname reservations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	reservations := []*HostnameReservation{}
+	for rows.Next() {
+		var r HostnameReservation
+		if err := rows.Scan(&r.Hostname, &r.URL, &r.ApplicationName, &r.Environment, &r.Cluster, &r.ReservedBy, &r.ReservedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan hostname reservation: %w", err)
+		}
+		reservations = append(reservations, &r)
+	}
+	return reservations, rows.Err()
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RecordLoginFailure counts a failed login of a throttle key and returns its failures.
+// Counts whose last failure is before resetBefore start over, and stale rows of other
+// keys are removed.
+func (d *Database) RecordLoginFailure(key string, at, resetBefore time.Time) (int, error) {
+	if d.db == nil {
+		return 0, fmt.Errorf("database connection is nil")
+	}
+
+	if _, err := d.db.Exec(`DELETE FROM login_attempts WHERE last_failure_at < $1 AND key <> $2`, resetBefore, key); err != nil {
+		return 0, fmt.Errorf("failed to prune login attempts: %w", err)
+	}
+
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failure_at < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING failures
+	`
+	var failures int
+	if err := d.db.QueryRow(query, key, at, resetBefore).Scan(&failures); err != nil {
+		return 0, fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	return failures, nil
+}
+
+// LoginFailures returns the failed logins of a throttle key and the time of the last one
+func (d *Database) LoginFailures(key string) (int, time.Time, error) {
+	if d.db == nil {
+		return 0, time.Time{}, fmt.Errorf("database connection is nil")
+	}
+
+	var failures int
+	var last time.Time
+	err := d.db.QueryRow(`SELECT failures, last_failure_at FROM login_attempts WHERE key = $1`, key).Scan(&failures, &last)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+	return failures, last, nil
+}
+
+// ResetLoginFailures removes the failed logins of a throttle key
+func (d *Database) ResetLoginFailures(key string) error {
+	if d.db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+
+	if _, err := d.db.Exec(`DELETE FROM login_attempts WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
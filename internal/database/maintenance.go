@@ -0,0 +1,204 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"innominatus/internal/maintenance"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DeferredOperation is a disruptive resource operation queued until a maintenance window opens
+type DeferredOperation struct {
+	ID              int64      `json:"id"`
+	ApplicationName string     `json:"application_name"`
+	ResourceID      int64      `json:"resource_id"`
+	Operation       string     `json:"operation"`
+	TargetState     string     `json:"target_state"`
+	Reason          string     `json:"reason"`
+	RequestedBy     string     `json:"requested_by"`
+	RequestedAt     time.Time  `json:"requested_at"`
+	ScheduledFor    time.Time  `json:"scheduled_for"`
+	Status          string     `json:"status"`
+	ExecutedAt      *time.Time `json:"executed_at,omitempty"`
+	ErrorMessage    *string    `json:"error_message,omitempty"`
+}
+
+// Deferred operation status constants
+const (
+	DeferredStatusPending   = "pending"
+	DeferredStatusExecuted  = "executed"
+	DeferredStatusFailed    = "failed"
+	DeferredStatusCancelled = "cancelled"
+)
+
+// CreateMaintenanceWindow stores a new maintenance window for an application
+func (d *Database) CreateMaintenanceWindow(window *maintenance.Window) error {
+	query := `
+		INSERT INTO maintenance_windows (application_name, name, days, start_time, duration_minutes, timezone, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	timezone := window.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	err := d.db.QueryRow(query,
+		window.ApplicationName, window.Name, pq.Array(window.Days), window.StartTime,
+		window.DurationMinutes, timezone, window.CreatedBy,
+	).Scan(&window.ID, &window.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+	window.Timezone = timezone
+
+	return nil
+}
+
+// ListMaintenanceWindows returns maintenance windows, optionally filtered by application
+func (d *Database) ListMaintenanceWindows(appName string) ([]*maintenance.Window, error) {
+	query := `
+		SELECT id, application_name, name, days, start_time, duration_minutes, timezone, created_by, created_at
+		FROM maintenance_windows
+		WHERE ($1 = '' OR application_name = $1)
+		ORDER BY application_name, name
+	`
+
+	rows, err := d.db.Query(query, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query maintenance windows: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	windows := []*maintenance.Window{}
+	for rows.Next() {
+		var w maintenance.Window
+		if err := rows.Scan(&w.ID, &w.ApplicationName, &w.Name, pq.Array(&w.Days), &w.StartTime,
+			&w.DurationMinutes, &w.Timezone, &w.CreatedBy, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, &w)
+	}
+
+	return windows, rows.Err()
+}
+
+// DeleteMaintenanceWindow removes a maintenance window by ID
+func (d *Database) DeleteMaintenanceWindow(id int64) error {
+	result, err := d.db.Exec(`DELETE FROM maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("maintenance window not found")
+	}
+
+	return nil
+}
+
+// CreateDeferredOperation queues a disruptive operation for later execution
+func (d *Database) CreateDeferredOperation(op *DeferredOperation) error {
+	query := `
+		INSERT INTO deferred_operations (application_name, resource_id, operation, target_state, reason, requested_by, requested_at, scheduled_for)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, requested_at, status
+	`
+
+	if op.RequestedAt.IsZero() {
+		op.RequestedAt = time.Now()
+	}
+	err := d.db.QueryRow(query,
+		op.ApplicationName, op.ResourceID, op.Operation, op.TargetState, op.Reason, op.RequestedBy, op.RequestedAt, op.ScheduledFor,
+	).Scan(&op.ID, &op.RequestedAt, &op.Status)
+	if err != nil {
+		return fmt.Errorf("failed to create deferred operation: %w", err)
+	}
+
+	return nil
+}
+
+// ListUpcomingOperations returns pending deferred operations ordered by schedule
+func (d *Database) ListUpcomingOperations(appName string) ([]*DeferredOperation, error) {
+	query := `
+		SELECT id, application_name, resource_id, operation, target_state, reason, requested_by,
+		       requested_at, scheduled_for, status, executed_at, error_message
+		FROM deferred_operations
+		WHERE status = 'pending' AND ($1 = '' OR application_name = $1)
+		ORDER BY scheduled_for ASC
+	`
+
+	return d.queryDeferredOperations(query, appName)
+}
+
+// ListDueOperations returns pending deferred operations scheduled at or before the given time
+func (d *Database) ListDueOperations(now time.Time) ([]*DeferredOperation, error) {
+	query := `
+		SELECT id, application_name, resource_id, operation, target_state, reason, requested_by,
+		       requested_at, scheduled_for, status, executed_at, error_message
+		FROM deferred_operations
+		WHERE status = 'pending' AND scheduled_for <= $1
+		ORDER BY scheduled_for ASC
+	`
+
+	return d.queryDeferredOperations(query, now)
+}
+
+// UpdateDeferredOperationStatus records the outcome of a deferred operation; at is when it
+// executed or failed
+func (d *Database) UpdateDeferredOperationStatus(id int64, status string, errorMessage *string, at time.Time) error {
+	query := `
+		UPDATE deferred_operations
+		SET status = $2, error_message = $3,
+		    executed_at = CASE WHEN $4 THEN $5 ELSE executed_at END
+		WHERE id = $1
+	`
+
+	finished := status == DeferredStatusExecuted || status == DeferredStatusFailed
+
+	var errMsg sql.NullString
+	if errorMessage != nil {
+		errMsg = sql.NullString{String: *errorMessage, Valid: true}
+	}
+
+	if _, err := d.db.Exec(query, id, status, errMsg, finished, at); err != nil {
+		return fmt.Errorf("failed to update deferred operation: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) queryDeferredOperations(query string, arg interface{}) ([]*DeferredOperation, error) {
+	rows, err := d.db.Query(query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deferred operations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	operations := []*DeferredOperation{}
+	for rows.Next() {
+		var op DeferredOperation
+		var errMsg sql.NullString
+		var executedAt sql.NullTime
+		if err := rows.Scan(&op.ID, &op.ApplicationName, &op.ResourceID, &op.Operation, &op.TargetState,
+			&op.Reason, &op.RequestedBy, &op.RequestedAt, &op.ScheduledFor, &op.Status, &executedAt, &errMsg); err != nil {
+			return nil, fmt.Errorf("failed to scan deferred operation: %w", err)
+		}
+		if executedAt.Valid {
+			op.ExecutedAt = &executedAt.Time
+		}
+		if errMsg.Valid {
+			op.ErrorMessage = &errMsg.String
+		}
+		operations = append(operations, &op)
+	}
+
+	return operations, rows.Err()
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"encoding/json"
+	"innominatus/internal/types"
 	"time"
 )
 
@@ -10,23 +11,47 @@ import (
 // Workflow definitions/templates are stored as YAML files (e.g., workflows/deploy-app.yaml)
 // while executions are runtime instances stored in the database.
 type WorkflowExecution struct {
-	ID                int64      `json:"id" db:"id"`
-	ApplicationName   string     `json:"application_name" db:"application_name"`
-	WorkflowName      string     `json:"workflow_name" db:"workflow_name"` // References the template name
-	Status            string     `json:"status" db:"status"`
-	StartedAt         time.Time  `json:"started_at" db:"started_at"`
-	CompletedAt       *time.Time `json:"completed_at,omitempty" db:"completed_at"`
-	ErrorMessage      *string    `json:"error_message,omitempty" db:"error_message"`
-	TotalSteps        int        `json:"total_steps" db:"total_steps"`
-	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
-	ParentExecutionID *int64     `json:"parent_execution_id,omitempty" db:"parent_execution_id"` // References original execution when retrying
-	RetryCount        int        `json:"retry_count" db:"retry_count"`                           // Number of retry attempts
-	IsRetry           bool       `json:"is_retry" db:"is_retry"`                                 // True if this is a retry
-	ResumeFromStep    *int       `json:"resume_from_step,omitempty" db:"resume_from_step"`       // Step number to resume from (NULL = start from beginning)
+	ID                int64                `json:"id" db:"id"`
+	ApplicationName   string               `json:"application_name" db:"application_name"`
+	WorkflowName      string               `json:"workflow_name" db:"workflow_name"` // References the template name
+	Status            string               `json:"status" db:"status"`
+	StartedAt         time.Time            `json:"started_at" db:"started_at"`
+	CompletedAt       *time.Time           `json:"completed_at,omitempty" db:"completed_at"`
+	ErrorMessage      *string              `json:"error_message,omitempty" db:"error_message"`
+	TotalSteps        int                  `json:"total_steps" db:"total_steps"`
+	CreatedAt         time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at" db:"updated_at"`
+	ParentExecutionID *int64               `json:"parent_execution_id,omitempty" db:"parent_execution_id"` // References original execution when retrying
+	RetryCount        int                  `json:"retry_count" db:"retry_count"`                           // Number of retry attempts
+	IsRetry           bool                 `json:"is_retry" db:"is_retry"`                                 // True if this is a retry
+	ResumeFromStep    *int                 `json:"resume_from_step,omitempty" db:"resume_from_step"`       // Step number to resume from (NULL = start from beginning)
+	ReplayOfID        *int64               `json:"replay_of_id,omitempty" db:"replay_of_id"`               // References the original execution when replaying
+	Outputs           []WorkflowOutput     `json:"outputs,omitempty" db:"outputs"`                         // Declared outputs resolved when the run completed
+	Overrides         *types.StepOverrides `json:"overrides,omitempty" db:"overrides"`                     // Admin step overrides the run executed with
 
 	// Related data (not stored in DB directly)
-	Steps []*WorkflowStepExecution `json:"steps,omitempty"`
+	Steps    []*WorkflowStepExecution `json:"steps,omitempty"`
+	Progress *WorkflowProgress        `json:"progress,omitempty"` // Estimated from past runs of the same steps
+}
+
+// WorkflowProgress estimates how far an execution is, weighting each step by its
+// median duration in recent successful runs
+type WorkflowProgress struct {
+	Percent          int        `json:"percent"`
+	CompletedSteps   int        `json:"completed_steps"` // Completed or skipped
+	TotalSteps       int        `json:"total_steps"`
+	CurrentStep      string     `json:"current_step,omitempty"`
+	ElapsedSeconds   int64      `json:"elapsed_seconds"`
+	RemainingSeconds *int64     `json:"remaining_seconds,omitempty"` // Unset once the execution ended
+	ETA              *time.Time `json:"eta,omitempty"`
+	Basis            string     `json:"basis"` // history, partial or defaults: where the step estimates come from
+}
+
+// StepDurationStats are the median durations in milliseconds of steps that completed
+// in recent successful runs
+type StepDurationStats struct {
+	ByName map[string]int64 // By step name, in runs of the same workflow
+	ByType map[string]int64 // By step type, in runs of any workflow
 }
 
 // WorkflowStepExecution represents the execution of a single step within a workflow execution.
@@ -127,12 +152,43 @@ func (c *WorkflowStepConfigJSON) Scan(value interface{}) error {
 	}
 }
 
+// WorkflowOutput is a declared workflow output resolved from the step outputs of an execution
+type WorkflowOutput struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // "url", "dashboard", "connection_string", "secret_ref", "namespace", "text", "grafana_panel", "log_query", "metrics_query"
+	Value       string `json:"value"`
+	Query       string `json:"query,omitempty"` // Log or metrics query of log_query and metrics_query outputs
+	Description string `json:"description,omitempty"`
+}
+
+// Hint converts the output to a resource hint
+func (o WorkflowOutput) Hint() ResourceHint {
+	hint := ResourceHint{Type: o.Type, Label: o.Name, Value: o.Value, Query: o.Query}
+	switch o.Type {
+	case "url", "dashboard":
+		hint.Icon = "external-link"
+	case "connection_string":
+		hint.Icon = "database"
+	case "secret_ref":
+		hint.Icon = "lock"
+	case "namespace":
+		hint.Icon = "terminal"
+	case "grafana_panel", "metrics_query":
+		hint.Icon = "chart"
+	case "log_query":
+		hint.Icon = "logs"
+	}
+	return hint
+}
+
 // ResourceHint represents a contextual hint for a resource (URL, connection string, etc.)
 type ResourceHint struct {
-	Type  string `json:"type"`           // "url", "connection_string", "dashboard", "docs", "api_endpoint", "git_clone", "command"
-	Label string `json:"label"`          // Display name: "Repository URL", "Admin Dashboard", etc.
-	Value string `json:"value"`          // Actual value: URL, connection string, command, etc.
-	Icon  string `json:"icon,omitempty"` // Optional icon: "external-link", "database", "lock", "terminal", "git-branch"
+	Type   string `json:"type"`             // "url", "connection_string", "dashboard", "docs", "api_endpoint", "git_clone", "command", "grafana_panel", "log_query", "metrics_query"
+	Label  string `json:"label"`            // Display name: "Repository URL", "Admin Dashboard", etc.
+	Value  string `json:"value"`            // Actual value: URL, connection string, command, etc.
+	Icon   string `json:"icon,omitempty"`   // Optional icon: "external-link", "database", "lock", "terminal", "git-branch", "chart", "logs"
+	Query  string `json:"query,omitempty"`  // Log or metrics query shown next to log_query and metrics_query links
+	Height int    `json:"height,omitempty"` // Height in pixels of embedded grafana_panel hints
 }
 
 // ResourceInstance represents a managed resource with lifecycle tracking
@@ -160,6 +216,9 @@ type ResourceInstance struct {
 	UpdatedAt           time.Time              `json:"updated_at" db:"updated_at"`
 	LastHealthCheck     *time.Time             `json:"last_health_check,omitempty" db:"last_health_check"`
 	ErrorMessage        *string                `json:"error_message,omitempty" db:"error_message"`
+	DriftState          string                 `json:"drift_state,omitempty" db:"drift_state"`           // unknown, in_sync or drifted
+	DriftMessage        *string                `json:"drift_message,omitempty" db:"drift_message"`       // What differs from the desired configuration
+	DriftCheckedAt      *time.Time             `json:"drift_checked_at,omitempty" db:"drift_checked_at"` // Last drift check
 
 	// Related data (not stored in DB directly)
 	Dependencies     []string                   `json:"dependencies,omitempty"`
@@ -179,6 +238,7 @@ const (
 	ResourceStateTerminating  ResourceLifecycleState = "terminating"
 	ResourceStateTerminated   ResourceLifecycleState = "terminated"
 	ResourceStateFailed       ResourceLifecycleState = "failed"
+	ResourceStateHibernated   ResourceLifecycleState = "hibernated" // Paused while its application is idle
 )
 
 // Resource type constants
@@ -197,6 +257,13 @@ const (
 	ExternalStateUnknown          = "Unknown"          // External state is unknown
 )
 
+// Drift state constants for active resources
+const (
+	DriftStateUnknown = "unknown" // Not checked yet, or the provisioner could not report the status
+	DriftStateInSync  = "in_sync" // The actual state matches the desired configuration
+	DriftStateDrifted = "drifted" // The resource was changed or removed outside of innominatus
+)
+
 // ResourceStateTransition tracks state changes for audit trail
 type ResourceStateTransition struct {
 	ID                 int64                  `json:"id" db:"id"`
@@ -230,6 +297,17 @@ type ResourceDependency struct {
 	CreatedAt          time.Time `json:"created_at" db:"created_at"`
 }
 
+// IsResourceLifecycleState reports whether state is a known resource lifecycle state
+func IsResourceLifecycleState(state ResourceLifecycleState) bool {
+	switch state {
+	case ResourceStateRequested, ResourceStateProvisioning, ResourceStateActive, ResourceStateScaling,
+		ResourceStateUpdating, ResourceStateDegraded, ResourceStateTerminating, ResourceStateTerminated,
+		ResourceStateFailed, ResourceStateHibernated:
+		return true
+	}
+	return false
+}
+
 // Resource lifecycle state validation
 var ValidResourceStateTransitions = map[ResourceLifecycleState][]ResourceLifecycleState{
 	ResourceStateRequested: {
@@ -246,6 +324,7 @@ var ValidResourceStateTransitions = map[ResourceLifecycleState][]ResourceLifecyc
 		ResourceStateDegraded,
 		ResourceStateTerminating,
 		ResourceStateFailed,
+		ResourceStateHibernated,
 	},
 	ResourceStateScaling: {
 		ResourceStateActive,
@@ -259,6 +338,7 @@ var ValidResourceStateTransitions = map[ResourceLifecycleState][]ResourceLifecyc
 		ResourceStateActive,
 		ResourceStateTerminating,
 		ResourceStateFailed,
+		ResourceStateHibernated,
 	},
 	ResourceStateTerminating: {
 		ResourceStateTerminated,
@@ -268,6 +348,10 @@ var ValidResourceStateTransitions = map[ResourceLifecycleState][]ResourceLifecyc
 		ResourceStateProvisioning,
 		ResourceStateTerminating,
 	},
+	ResourceStateHibernated: {
+		ResourceStateActive,
+		ResourceStateTerminating,
+	},
 }
 
 // IsValidStateTransition checks if a state transition is valid
@@ -368,6 +368,19 @@ func TestResourceInstance_IsValidStateTransition(t *testing.T) {
 	}
 }
 
+func TestIsResourceLifecycleState(t *testing.T) {
+	for _, state := range []ResourceLifecycleState{ResourceStateRequested, ResourceStateActive, ResourceStateTerminated, ResourceStateHibernated} {
+		if !IsResourceLifecycleState(state) {
+			t.Errorf("IsResourceLifecycleState(%v) = false, want true", state)
+		}
+	}
+	for _, state := range []ResourceLifecycleState{"", "deleted", "ACTIVE"} {
+		if IsResourceLifecycleState(state) {
+			t.Errorf("IsResourceLifecycleState(%q) = true, want false", state)
+		}
+	}
+}
+
 // ===== Constant Tests =====
 
 func TestWorkflowStatusConstants(t *testing.T) {
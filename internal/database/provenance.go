@@ -0,0 +1,64 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeploymentProvenance is an immutable provenance record of one deployment.
+// Document holds the exact bytes that Digest and Signature cover.
+type DeploymentProvenance struct {
+	ID              int64     `json:"id"`
+	ApplicationName string    `json:"application_name"`
+	Kind            string    `json:"kind"`
+	Document        string    `json:"-"`
+	Digest          string    `json:"digest"`
+	Signature       string    `json:"signature,omitempty"`
+	KeyID           string    `json:"key_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// CreateDeploymentProvenance appends a provenance record; records cannot be updated or deleted
+func (d *Database) CreateDeploymentProvenance(record *DeploymentProvenance) error {
+	query := `
+		INSERT INTO deployment_provenance (application_name, kind, document, digest, signature, key_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := d.db.QueryRow(query,
+		record.ApplicationName, record.Kind, record.Document, record.Digest, record.Signature, record.KeyID,
+	).Scan(&record.ID, &record.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create deployment provenance: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeploymentProvenance returns an application's provenance records, newest first
+func (d *Database) ListDeploymentProvenance(appName string) ([]*DeploymentProvenance, error) {
+	query := `
+		SELECT id, application_name, kind, document, digest, signature, key_id, created_at
+		FROM deployment_provenance
+		WHERE application_name = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := d.db.Query(query, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deployment provenance: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	records := []*DeploymentProvenance{}
+	for rows.Next() {
+		var p DeploymentProvenance
+		if err := rows.Scan(&p.ID, &p.ApplicationName, &p.Kind, &p.Document, &p.Digest, &p.Signature, &p.KeyID, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan deployment provenance: %w", err)
+		}
+		records = append(records, &p)
+	}
+
+	return records, rows.Err()
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// Scopes of provider version pins
+const (
+	ProviderPinScopeApplication = "application"
+	ProviderPinScopeTeam        = "team"
+)
+
+// ProviderVersionPin keeps an application, or all applications of a team, on a version
+// of a provider after the platform registered a newer one
+type ProviderVersionPin struct {
+	Scope     string    `json:"scope"`      // application or team
+	ScopeName string    `json:"scope_name"` // Application or team name
+	Provider  string    `json:"provider"`
+	Version   string    `json:"version"`
+	Reason    string    `json:"reason,omitempty"`
+	PinnedBy  string    `json:"pinned_by"`
+	PinnedAt  time.Time `json:"pinned_at"`
+}
+
+// SaveProviderVersionPin creates or replaces the pin of a provider for an application or team
+func (d *Database) SaveProviderVersionPin(pin *ProviderVersionPin) error {
+	err := d.db.QueryRow(`
+		INSERT INTO provider_version_pins (scope, scope_name, provider_name, version, reason, pinned_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (scope, scope_name, provider_name) DO UPDATE SET
+			version = EXCLUDED.version, reason = EXCLUDED.reason, pinned_by = EXCLUDED.pinned_by, pinned_at = NOW()
+		RETURNING pinned_at
+	`, pin.Scope, pin.ScopeName, pin.Provider, pin.Version, pin.Reason, pin.PinnedBy).Scan(&pin.PinnedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save provider version pin: %w", err)
+	}
+	return nil
+}
+
+// DeleteProviderVersionPin removes the pin of a provider and reports whether there was one
+func (d *Database) DeleteProviderVersionPin(scope, scopeName, provider string) (bool, error) {
+	result, err := d.db.Exec(`
+		DELETE FROM provider_version_pins WHERE scope = $1 AND scope_name = $2 AND provider_name = $3
+	`, scope, scopeName, provider)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete provider version pin: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete provider version pin: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// ListProviderVersionPins returns the pins of an application or team, or all pins when
+// scope is empty, sorted by scope, name and provider
+func (d *Database) ListProviderVersionPins(scope, scopeName string) ([]*ProviderVersionPin, error) {
+	return d.queryProviderVersionPins(`
+		SELECT scope, scope_name, provider_name, version, reason, pinned_by, pinned_at
+		FROM provider_version_pins
+		WHERE ($1 = '' OR (scope = $1 AND scope_name = $2))
+		ORDER BY scope, scope_name, provider_name
+	`, scope, scopeName)
+}
+
+// ApplicationProviderPins returns the pins in effect for an application by provider: its
+// own pins, and the pins of its team for the other providers
+func (d *Database) ApplicationProviderPins(appName string) (map[string]*ProviderVersionPin, error) {
+	pins, err := d.queryProviderVersionPins(`
+		SELECT scope, scope_name, provider_name, version, reason, pinned_by, pinned_at
+		FROM provider_version_pins
+		WHERE (scope = 'application' AND scope_name = $1)
+		   OR (scope = 'team' AND scope_name = (SELECT team FROM applications WHERE name = $1))
+	`, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := make(map[string]*ProviderVersionPin, len(pins))
+	for _, pin := range pins {
+		if existing, ok := effective[pin.Provider]; ok && existing.Scope == ProviderPinScopeApplication {
+			continue
+		}
+		effective[pin.Provider] = pin
+	}
+	return effective, nil
+}
+
+func (d *Database) queryProviderVersionPins(query string, args ...interface{}) ([]*ProviderVersionPin, error) {
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provider version pins: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	pins := []*ProviderVersionPin{}
+	for rows.Next() {
+		var pin ProviderVersionPin
+		if err := rows.Scan(&pin.Scope, &pin.ScopeName, &pin.Provider, &pin.Version, &pin.Reason, &pin.PinnedBy, &pin.PinnedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan provider version pin: %w", err)
+		}
+		pins = append(pins, &pin)
+	}
+	return pins, rows.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"innominatus/internal/types"
 	"time"
 )
 
@@ -44,6 +45,96 @@ func (r *WorkflowRepository) CreateWorkflowExecution(appName, workflowName strin
 	return execution, nil
 }
 
+// SetWorkflowExecutionParameters records the parameters a workflow execution started with
+// and the external sources of parameters whose values are not stored
+func (r *WorkflowRepository) SetWorkflowExecutionParameters(id int64, parameters map[string]string, sources map[string]types.ParameterSource) error {
+	parametersJSON, err := json.Marshal(parameters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow parameters: %w", err)
+	}
+	if sources == nil {
+		sources = map[string]types.ParameterSource{}
+	}
+	sourcesJSON, err := json.Marshal(sources)
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow parameter sources: %w", err)
+	}
+
+	_, err = r.db.db.Exec(`UPDATE workflow_executions SET parameters = $1, parameter_sources = $2 WHERE id = $3`, parametersJSON, sourcesJSON, id)
+	if err != nil {
+		return fmt.Errorf("failed to set workflow execution parameters: %w", err)
+	}
+
+	return nil
+}
+
+// GetWorkflowExecutionParameters returns the parameters a workflow execution started with
+// and the external sources of the parameters that were not stored
+func (r *WorkflowRepository) GetWorkflowExecutionParameters(id int64) (map[string]string, map[string]types.ParameterSource, error) {
+	var parametersJSON, sourcesJSON []byte
+	err := r.db.db.QueryRow(`SELECT parameters, parameter_sources FROM workflow_executions WHERE id = $1`, id).Scan(&parametersJSON, &sourcesJSON)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, fmt.Errorf("workflow execution not found")
+		}
+		return nil, nil, fmt.Errorf("failed to get workflow execution parameters: %w", err)
+	}
+
+	parameters := make(map[string]string)
+	if err := json.Unmarshal(parametersJSON, &parameters); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal workflow parameters: %w", err)
+	}
+	sources := make(map[string]types.ParameterSource)
+	if err := json.Unmarshal(sourcesJSON, &sources); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal workflow parameter sources: %w", err)
+	}
+
+	return parameters, sources, nil
+}
+
+// SetWorkflowExecutionOutputs records the declared outputs a workflow execution produced
+func (r *WorkflowRepository) SetWorkflowExecutionOutputs(id int64, outputs []WorkflowOutput) error {
+	if outputs == nil {
+		outputs = []WorkflowOutput{}
+	}
+	outputsJSON, err := json.Marshal(outputs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow outputs: %w", err)
+	}
+
+	_, err = r.db.db.Exec(`UPDATE workflow_executions SET outputs = $1 WHERE id = $2`, outputsJSON, id)
+	if err != nil {
+		return fmt.Errorf("failed to set workflow execution outputs: %w", err)
+	}
+
+	return nil
+}
+
+// SetWorkflowExecutionOverrides records the admin step overrides a workflow execution runs with
+func (r *WorkflowRepository) SetWorkflowExecutionOverrides(id int64, overrides *types.StepOverrides) error {
+	overridesJSON, err := json.Marshal(overrides)
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow overrides: %w", err)
+	}
+
+	_, err = r.db.db.Exec(`UPDATE workflow_executions SET overrides = $1 WHERE id = $2`, overridesJSON, id)
+	if err != nil {
+		return fmt.Errorf("failed to set workflow execution overrides: %w", err)
+	}
+
+	return nil
+}
+
+// MarkWorkflowExecutionReplay links a workflow execution to the original run it replays
+func (r *WorkflowRepository) MarkWorkflowExecutionReplay(id, originalID int64) error {
+	_, err := r.db.db.Exec(`UPDATE workflow_executions SET replay_of_id = $1 WHERE id = $2`, originalID, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark workflow execution as replay: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateWorkflowExecution updates the workflow execution status
 func (r *WorkflowRepository) UpdateWorkflowExecution(id int64, status string, errorMessage *string) error {
 	var query string
@@ -169,12 +260,13 @@ func (r *WorkflowRepository) AddWorkflowStepLogs(stepID int64, logs string) erro
 func (r *WorkflowRepository) GetWorkflowExecution(id int64) (*WorkflowExecution, error) {
 	query := `
 		SELECT id, application_name, workflow_name, status, started_at, completed_at,
-		       error_message, total_steps, created_at, updated_at
+		       error_message, total_steps, created_at, updated_at, replay_of_id, outputs, overrides
 		FROM workflow_executions
 		WHERE id = $1
 	`
 
 	execution := &WorkflowExecution{}
+	var outputsJSON, overridesJSON []byte
 	err := r.db.db.QueryRow(query, id).Scan(
 		&execution.ID,
 		&execution.ApplicationName,
@@ -186,6 +278,9 @@ func (r *WorkflowRepository) GetWorkflowExecution(id int64) (*WorkflowExecution,
 		&execution.TotalSteps,
 		&execution.CreatedAt,
 		&execution.UpdatedAt,
+		&execution.ReplayOfID,
+		&outputsJSON,
+		&overridesJSON,
 	)
 
 	if err != nil {
@@ -195,6 +290,17 @@ func (r *WorkflowRepository) GetWorkflowExecution(id int64) (*WorkflowExecution,
 		return nil, fmt.Errorf("failed to get workflow execution: %w", err)
 	}
 
+	if len(outputsJSON) > 0 {
+		if err := json.Unmarshal(outputsJSON, &execution.Outputs); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal workflow outputs: %w", err)
+		}
+	}
+	if len(overridesJSON) > 0 {
+		if err := json.Unmarshal(overridesJSON, &execution.Overrides); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal workflow overrides: %w", err)
+		}
+	}
+
 	// Load steps
 	steps, err := r.GetWorkflowSteps(id)
 	if err != nil {
@@ -262,6 +368,65 @@ func (r *WorkflowRepository) GetWorkflowSteps(workflowID int64) ([]*WorkflowStep
 	return steps, nil
 }
 
+// StepDurationStats returns the median step durations of the last workflowRuns successful
+// runs of a workflow, by step name, and of the last allRuns successful runs of any
+// workflow, by step type
+func (r *WorkflowRepository) StepDurationStats(workflowName string, workflowRuns, allRuns int) (*StepDurationStats, error) {
+	byName, err := r.medianStepDurations(`
+		SELECT s.step_name, percentile_cont(0.5) WITHIN GROUP (ORDER BY s.duration_ms)
+		FROM workflow_step_executions s
+		JOIN (
+			SELECT id FROM workflow_executions
+			WHERE workflow_name = $1 AND status = 'completed'
+			ORDER BY started_at DESC
+			LIMIT $2
+		) we ON we.id = s.workflow_execution_id
+		WHERE s.status = 'completed' AND s.duration_ms IS NOT NULL
+		GROUP BY s.step_name
+	`, workflowName, workflowRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	byType, err := r.medianStepDurations(`
+		SELECT s.step_type, percentile_cont(0.5) WITHIN GROUP (ORDER BY s.duration_ms)
+		FROM workflow_step_executions s
+		JOIN (
+			SELECT id FROM workflow_executions
+			WHERE status = 'completed'
+			ORDER BY started_at DESC
+			LIMIT $1
+		) we ON we.id = s.workflow_execution_id
+		WHERE s.status = 'completed' AND s.duration_ms IS NOT NULL
+		GROUP BY s.step_type
+	`, allRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StepDurationStats{ByName: byName, ByType: byType}, nil
+}
+
+// medianStepDurations runs a query returning a key and a median duration per row
+func (r *WorkflowRepository) medianStepDurations(query string, args ...interface{}) (map[string]int64, error) {
+	rows, err := r.db.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query step durations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	durations := map[string]int64{}
+	for rows.Next() {
+		var key string
+		var median float64
+		if err := rows.Scan(&key, &median); err != nil {
+			return nil, fmt.Errorf("failed to scan step duration: %w", err)
+		}
+		durations[key] = int64(median)
+	}
+	return durations, rows.Err()
+}
+
 // CountWorkflowExecutions counts total workflow executions matching filters
 func (r *WorkflowRepository) CountWorkflowExecutions(appName, workflowName, status string) (int64, error) {
 	query := `
@@ -458,15 +623,16 @@ func (r *WorkflowRepository) CreateRetryExecution(parentID int64, appName, workf
 // ReconstructWorkflowFromExecution reconstructs a workflow specification from stored step executions
 // This allows retrying a workflow without requiring the original workflow file
 func (r *WorkflowRepository) ReconstructWorkflowFromExecution(executionID int64) (map[string]interface{}, error) {
-	// Get all steps for this execution, ordered by step number
+	// Get the workflow's own steps for this execution, ordered by step number; rollback
+	// steps added after a failure are not part of the definition
 	query := `
 		SELECT step_number, step_name, step_type, step_config
 		FROM workflow_step_executions
-		WHERE workflow_execution_id = $1
+		WHERE workflow_execution_id = $1 AND step_type <> $2
 		ORDER BY step_number ASC
 	`
 
-	rows, err := r.db.db.Query(query, executionID)
+	rows, err := r.db.db.Query(query, executionID, StepTypeCompensation)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query workflow steps: %w", err)
 	}
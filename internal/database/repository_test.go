@@ -1,6 +1,8 @@
 package database
 
 import (
+	"innominatus/internal/types"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -289,6 +291,80 @@ func TestWorkflowRepository_AddWorkflowStepLogs(t *testing.T) {
 	}
 }
 
+func TestWorkflowRepository_WorkflowExecutionParameters(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	exec, _ := repo.CreateWorkflowExecution("test-app", "deploy", 1)
+
+	// New executions start without parameters
+	params, sources, err := repo.GetWorkflowExecutionParameters(exec.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowExecutionParameters() error = %v", err)
+	}
+	if len(params) != 0 || len(sources) != 0 {
+		t.Errorf("parameters = %v, sources = %v, want empty", params, sources)
+	}
+
+	vaultSource := types.ParameterSource{Type: "vault", Path: "secret/data/db", Key: "password"}
+	err = repo.SetWorkflowExecutionParameters(exec.ID, map[string]string{"environment": "staging"}, map[string]types.ParameterSource{"db_password": vaultSource})
+	if err != nil {
+		t.Fatalf("SetWorkflowExecutionParameters() error = %v", err)
+	}
+
+	params, sources, _ = repo.GetWorkflowExecutionParameters(exec.ID)
+	if params["environment"] != "staging" {
+		t.Errorf("parameters[environment] = %q, want staging", params["environment"])
+	}
+	if sources["db_password"] != vaultSource {
+		t.Errorf("sources[db_password] = %+v, want %+v", sources["db_password"], vaultSource)
+	}
+
+	if _, _, err := repo.GetWorkflowExecutionParameters(999999); err == nil {
+		t.Error("GetWorkflowExecutionParameters() expected error for unknown execution")
+	}
+}
+
+func TestWorkflowRepository_MarkWorkflowExecutionReplay(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	original, _ := repo.CreateWorkflowExecution("test-app", "deploy", 1)
+	replay, _ := repo.CreateWorkflowExecution("test-app", "deploy", 1)
+
+	if err := repo.MarkWorkflowExecutionReplay(replay.ID, original.ID); err != nil {
+		t.Fatalf("MarkWorkflowExecutionReplay() error = %v", err)
+	}
+
+	got, err := repo.GetWorkflowExecution(replay.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowExecution() error = %v", err)
+	}
+	if got.ReplayOfID == nil || *got.ReplayOfID != original.ID {
+		t.Errorf("ReplayOfID = %v, want %d", got.ReplayOfID, original.ID)
+	}
+}
+
+func TestWorkflowRepository_SetWorkflowExecutionOutputs(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	exec, _ := repo.CreateWorkflowExecution("test-app", "deploy", 1)
+	outputs := []WorkflowOutput{
+		{Name: "endpoint", Type: "url", Value: "https://test-app.example.com"},
+		{Name: "namespace", Type: "namespace", Value: "test-app"},
+	}
+
+	if err := repo.SetWorkflowExecutionOutputs(exec.ID, outputs); err != nil {
+		t.Fatalf("SetWorkflowExecutionOutputs() error = %v", err)
+	}
+
+	got, err := repo.GetWorkflowExecution(exec.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowExecution() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Outputs, outputs) {
+		t.Errorf("Outputs = %v, want %v", got.Outputs, outputs)
+	}
+}
+
 func TestWorkflowRepository_GetWorkflowExecution(t *testing.T) {
 	repo := setupTestRepo(t)
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Common errors
@@ -22,6 +23,16 @@ func NewResourceRepository(db *Database) *ResourceRepository {
 	return &ResourceRepository{db: db}
 }
 
+// GetApplication returns the application resources belong to
+func (r *ResourceRepository) GetApplication(name string) (*Application, error) {
+	return r.db.GetApplication(name)
+}
+
+// ListApplicationsByTeam returns the applications of a team
+func (r *ResourceRepository) ListApplicationsByTeam(team string) ([]*Application, error) {
+	return r.db.ListApplicationsByTeam(team)
+}
+
 // CreateResourceInstance creates a new resource instance
 func (r *ResourceRepository) CreateResourceInstance(applicationName, resourceName, resourceType string, config map[string]interface{}) (*ResourceInstance, error) {
 	configJSON, err := json.Marshal(config)
@@ -59,7 +70,8 @@ func (r *ResourceRepository) GetResourceInstance(id int64) (*ResourceInstance, e
 	query := `
 		SELECT id, application_name, resource_name, resource_type, state, health_status,
 		       configuration, provider_id, provider_metadata, type, provider, reference_url,
-		       external_state, last_sync, created_at, updated_at, last_health_check, error_message, hints
+		       external_state, last_sync, created_at, updated_at, last_health_check, error_message, hints,
+		       drift_state, drift_message, drift_checked_at
 		FROM resource_instances WHERE id = $1`
 
 	var resource ResourceInstance
@@ -72,7 +84,8 @@ func (r *ResourceRepository) GetResourceInstance(id int64) (*ResourceInstance, e
 		&resource.Type, &resource.Provider, &resource.ReferenceURL,
 		&resource.ExternalState, &resource.LastSync,
 		&resource.CreatedAt, &resource.UpdatedAt, &resource.LastHealthCheck,
-		&resource.ErrorMessage, &hintsJSON)
+		&resource.ErrorMessage, &hintsJSON,
+		&resource.DriftState, &resource.DriftMessage, &resource.DriftCheckedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -151,7 +164,8 @@ func (r *ResourceRepository) ListResourceInstances(applicationName string) ([]*R
 	query := `
 		SELECT id, application_name, resource_name, resource_type, state, health_status,
 		       configuration, provider_id, provider_metadata, type, provider, reference_url,
-		       external_state, last_sync, workflow_execution_id, created_at, updated_at, last_health_check, error_message, hints
+		       external_state, last_sync, workflow_execution_id, created_at, updated_at, last_health_check, error_message, hints,
+		       drift_state, drift_message, drift_checked_at
 		FROM resource_instances WHERE application_name = $1 ORDER BY created_at ASC`
 
 	rows, err := r.db.db.Query(query, applicationName)
@@ -172,7 +186,8 @@ func (r *ResourceRepository) ListResourceInstances(applicationName string) ([]*R
 			&resource.Type, &resource.Provider, &resource.ReferenceURL,
 			&resource.ExternalState, &resource.LastSync, &resource.WorkflowExecutionID,
 			&resource.CreatedAt, &resource.UpdatedAt, &resource.LastHealthCheck,
-			&resource.ErrorMessage, &hintsJSON)
+			&resource.ErrorMessage, &hintsJSON,
+			&resource.DriftState, &resource.DriftMessage, &resource.DriftCheckedAt)
 
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan resource instance: %w", err)
@@ -240,6 +255,62 @@ func (r *ResourceRepository) UpdateResourceInstanceState(id int64, newState Reso
 	return tx.Commit()
 }
 
+// ForceResourceInstanceState sets the state of a resource instance without checking the
+// transition, for resources an admin has to repair by hand. The state history and the
+// audit log record the change in the same transaction. It returns the previous state.
+func (r *ResourceRepository) ForceResourceInstanceState(id int64, newState ResourceLifecycleState, reason, forcedBy string) (ResourceLifecycleState, error) {
+	tx, err := r.db.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // Ignore rollback error as commit supersedes it
+
+	var appName, resourceName, currentState string
+	err = tx.QueryRow("SELECT application_name, resource_name, state FROM resource_instances WHERE id = $1 FOR UPDATE", id).
+		Scan(&appName, &resourceName, &currentState)
+	if err == sql.ErrNoRows {
+		return "", ErrResourceNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get current state: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE resource_instances SET state = $1, updated_at = NOW() WHERE id = $2", string(newState), id); err != nil {
+		return "", fmt.Errorf("failed to update resource state: %w", err)
+	}
+
+	metadataJSON, _ := json.Marshal(map[string]interface{}{"forced": true})
+	if _, err := tx.Exec(`
+		INSERT INTO resource_state_transitions
+		(resource_instance_id, from_state, to_state, reason, transitioned_by, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		id, currentState, string(newState), reason, forcedBy, metadataJSON); err != nil {
+		return "", fmt.Errorf("failed to create state transition record: %w", err)
+	}
+
+	err = insertAuditEntry(tx, &AuditEntry{
+		Actor:      forcedBy,
+		Action:     AuditActionForceResourceState,
+		TargetType: "resource",
+		TargetID:   fmt.Sprintf("%d", id),
+		Reason:     reason,
+		Details: map[string]interface{}{
+			"application_name": appName,
+			"resource_name":    resourceName,
+			"from_state":       currentState,
+			"to_state":         string(newState),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit forced state transition: %w", err)
+	}
+	return ResourceLifecycleState(currentState), nil
+}
+
 // UpdateResourceInstanceHealth updates the health status of a resource instance
 func (r *ResourceRepository) UpdateResourceInstanceHealth(id int64, healthStatus string, errorMessage *string) error {
 	query := `
@@ -255,6 +326,65 @@ func (r *ResourceRepository) UpdateResourceInstanceHealth(id int64, healthStatus
 	return nil
 }
 
+// ListResourceInstancesInState lists the resource instances of all applications in a
+// lifecycle state, oldest first
+func (r *ResourceRepository) ListResourceInstancesInState(state ResourceLifecycleState) ([]*ResourceInstance, error) {
+	query := `
+		SELECT id, application_name, resource_name, resource_type, state, health_status,
+		       configuration, provider_id, provider_metadata, created_at, updated_at, drift_state
+		FROM resource_instances WHERE state = $1 ORDER BY created_at ASC`
+
+	rows, err := r.db.db.Query(query, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource instances: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var resources []*ResourceInstance
+	for rows.Next() {
+		var resource ResourceInstance
+		var configJSON, providerMetadataJSON []byte
+
+		if err := rows.Scan(
+			&resource.ID, &resource.ApplicationName, &resource.ResourceName,
+			&resource.ResourceType, &resource.State, &resource.HealthStatus,
+			&configJSON, &resource.ProviderID, &providerMetadataJSON,
+			&resource.CreatedAt, &resource.UpdatedAt, &resource.DriftState); err != nil {
+			return nil, fmt.Errorf("failed to scan resource instance: %w", err)
+		}
+
+		if len(configJSON) > 0 {
+			if err := json.Unmarshal(configJSON, &resource.Configuration); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
+			}
+		}
+		if len(providerMetadataJSON) > 0 {
+			if err := json.Unmarshal(providerMetadataJSON, &resource.ProviderMetadata); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal provider metadata: %w", err)
+			}
+		}
+
+		resources = append(resources, &resource)
+	}
+
+	return resources, rows.Err()
+}
+
+// UpdateResourceDrift records the result of a drift check of a resource instance
+func (r *ResourceRepository) UpdateResourceDrift(id int64, driftState string, message *string, checkedAt time.Time) error {
+	query := `
+		UPDATE resource_instances
+		SET drift_state = $1, drift_message = $2, drift_checked_at = $3
+		WHERE id = $4`
+
+	_, err := r.db.db.Exec(query, driftState, message, checkedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update resource drift: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateResourceHints updates the hints for a resource instance
 func (r *ResourceRepository) UpdateResourceHints(id int64, hints []ResourceHint) error {
 	hintsJSON, err := json.Marshal(hints)
@@ -333,6 +463,82 @@ func (r *ResourceRepository) GetResourceStateTransitions(resourceID int64, limit
 	return transitions, nil
 }
 
+// ResourceLifecycle is a resource instance with the team owning its application and its
+// state history, oldest transition first
+type ResourceLifecycle struct {
+	Resource    *ResourceInstance
+	Team        string
+	Transitions []*ResourceStateTransition
+}
+
+// ListResourceLifecycles returns the resource instances created before until together with
+// the state transitions that happened before it
+func (r *ResourceRepository) ListResourceLifecycles(until time.Time) ([]*ResourceLifecycle, error) {
+	rows, err := r.db.db.Query(`
+		SELECT ri.id, ri.application_name, ri.resource_name, ri.resource_type, ri.state,
+		       ri.configuration, ri.provider, ri.created_at, COALESCE(a.team, '')
+		FROM resource_instances ri
+		LEFT JOIN applications a ON a.name = ri.application_name
+		WHERE ri.created_at < $1
+		ORDER BY ri.id`, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource lifecycles: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var lifecycles []*ResourceLifecycle
+	byID := make(map[int64]*ResourceLifecycle)
+	for rows.Next() {
+		var resource ResourceInstance
+		var configJSON []byte
+		var provider sql.NullString
+		lifecycle := &ResourceLifecycle{Resource: &resource}
+
+		if err := rows.Scan(&resource.ID, &resource.ApplicationName, &resource.ResourceName, &resource.ResourceType,
+			&resource.State, &configJSON, &provider, &resource.CreatedAt, &lifecycle.Team); err != nil {
+			return nil, fmt.Errorf("failed to scan resource lifecycle: %w", err)
+		}
+		if err := json.Unmarshal(configJSON, &resource.Configuration); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
+		}
+		if provider.Valid {
+			resource.Provider = &provider.String
+		}
+
+		lifecycles = append(lifecycles, lifecycle)
+		byID[resource.ID] = lifecycle
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list resource lifecycles: %w", err)
+	}
+
+	transitionRows, err := r.db.db.Query(`
+		SELECT id, resource_instance_id, from_state, to_state, transitioned_at
+		FROM resource_state_transitions
+		WHERE transitioned_at < $1
+		ORDER BY resource_instance_id, transitioned_at, id`, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state transitions: %w", err)
+	}
+	defer func() { _ = transitionRows.Close() }()
+
+	for transitionRows.Next() {
+		var transition ResourceStateTransition
+		if err := transitionRows.Scan(&transition.ID, &transition.ResourceInstanceID, &transition.FromState,
+			&transition.ToState, &transition.TransitionedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan state transition: %w", err)
+		}
+		if lifecycle, ok := byID[transition.ResourceInstanceID]; ok {
+			lifecycle.Transitions = append(lifecycle.Transitions, &transition)
+		}
+	}
+	if err := transitionRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list state transitions: %w", err)
+	}
+
+	return lifecycles, nil
+}
+
 // DeleteResourceInstance deletes a resource instance and all related data
 func (r *ResourceRepository) DeleteResourceInstance(id int64) error {
 	query := "DELETE FROM resource_instances WHERE id = $1"
@@ -439,7 +645,8 @@ func (r *ResourceRepository) FilterResourcesByType(applicationName, resourceType
 		query = `
 			SELECT id, application_name, resource_name, resource_type, state, health_status,
 			       configuration, provider_id, provider_metadata, type, provider, reference_url,
-			       external_state, last_sync, created_at, updated_at, last_health_check, error_message
+			       external_state, last_sync, created_at, updated_at, last_health_check, error_message,
+			       drift_state, drift_message, drift_checked_at
 			FROM resource_instances
 			WHERE application_name = $1 AND type = $2
 			ORDER BY created_at ASC`
@@ -448,7 +655,8 @@ func (r *ResourceRepository) FilterResourcesByType(applicationName, resourceType
 		query = `
 			SELECT id, application_name, resource_name, resource_type, state, health_status,
 			       configuration, provider_id, provider_metadata, type, provider, reference_url,
-			       external_state, last_sync, created_at, updated_at, last_health_check, error_message
+			       external_state, last_sync, created_at, updated_at, last_health_check, error_message,
+			       drift_state, drift_message, drift_checked_at
 			FROM resource_instances
 			WHERE type = $1
 			ORDER BY created_at ASC`
@@ -473,7 +681,8 @@ func (r *ResourceRepository) FilterResourcesByType(applicationName, resourceType
 			&resource.Type, &resource.Provider, &resource.ReferenceURL,
 			&resource.ExternalState, &resource.LastSync,
 			&resource.CreatedAt, &resource.UpdatedAt, &resource.LastHealthCheck,
-			&resource.ErrorMessage)
+			&resource.ErrorMessage,
+			&resource.DriftState, &resource.DriftMessage, &resource.DriftCheckedAt)
 
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan resource instance: %w", err)
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"innominatus/internal/schedule"
+	"time"
+)
+
+const scheduleColumns = `id, name, application_name, golden_path, cron, timezone, parameters, enabled,
+		created_by, created_at, last_run_at, next_run_at`
+
+// CreateSchedule stores a new workflow schedule
+func (d *Database) CreateSchedule(s *schedule.Schedule) error {
+	parameters, err := json.Marshal(s.Parameters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal parameters: %w", err)
+	}
+
+	err = d.db.QueryRow(`
+		INSERT INTO workflow_schedules (name, application_name, golden_path, cron, timezone, parameters, enabled, created_by, next_run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`, s.Name, s.ApplicationName, s.GoldenPath, s.Cron, s.Timezone, parameters, s.Enabled, s.CreatedBy, s.NextRunAt,
+	).Scan(&s.ID, &s.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+	return nil
+}
+
+// GetSchedule returns a workflow schedule by ID, or nil if it does not exist
+func (d *Database) GetSchedule(id int64) (*schedule.Schedule, error) {
+	row := d.db.QueryRow(`SELECT `+scheduleColumns+` FROM workflow_schedules WHERE id = $1`, id)
+	s, err := scanSchedule(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return s, err
+}
+
+// ListSchedules returns workflow schedules, optionally filtered by application
+func (d *Database) ListSchedules(appName string) ([]*schedule.Schedule, error) {
+	return d.querySchedules(`
+		SELECT `+scheduleColumns+`
+		FROM workflow_schedules
+		WHERE ($1 = '' OR application_name = $1)
+		ORDER BY application_name, name
+	`, appName)
+}
+
+// ListDueSchedules returns enabled schedules whose next run is at or before the given time
+func (d *Database) ListDueSchedules(now time.Time) ([]*schedule.Schedule, error) {
+	return d.querySchedules(`
+		SELECT `+scheduleColumns+`
+		FROM workflow_schedules
+		WHERE enabled AND next_run_at <= $1
+		ORDER BY next_run_at ASC
+	`, now)
+}
+
+// ClaimScheduleRun records that the run of a schedule due at scheduledFor starts and
+// moves the schedule on to nextRun. It returns false when another server replica
+// already claimed the run, so every run starts once.
+func (d *Database) ClaimScheduleRun(id int64, scheduledFor time.Time, nextRun *time.Time) (bool, error) {
+	result, err := d.db.Exec(`
+		UPDATE workflow_schedules
+		SET last_run_at = $2, next_run_at = $3
+		WHERE id = $1 AND next_run_at = $2
+	`, id, scheduledFor, nextRun)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim schedule run: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected == 1, nil
+}
+
+// UpdateSchedule stores the name, cron expression, timezone, parameters, enabled flag and
+// next run of a workflow schedule
+func (d *Database) UpdateSchedule(s *schedule.Schedule) error {
+	parameters, err := json.Marshal(s.Parameters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal parameters: %w", err)
+	}
+
+	result, err := d.db.Exec(`
+		UPDATE workflow_schedules
+		SET name = $2, cron = $3, timezone = $4, parameters = $5, enabled = $6, next_run_at = $7
+		WHERE id = $1
+	`, s.ID, s.Name, s.Cron, s.Timezone, parameters, s.Enabled, s.NextRunAt)
+	if err != nil {
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("schedule not found")
+	}
+	return nil
+}
+
+// DeleteSchedule removes a workflow schedule by ID
+func (d *Database) DeleteSchedule(id int64) error {
+	result, err := d.db.Exec(`DELETE FROM workflow_schedules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("schedule not found")
+	}
+	return nil
+}
+
+func (d *Database) querySchedules(query string, arg interface{}) ([]*schedule.Schedule, error) {
+	rows, err := d.db.Query(query, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schedules: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	schedules := []*schedule.Schedule{}
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+	return schedules, rows.Err()
+}
+
+func scanSchedule(row interface{ Scan(...interface{}) error }) (*schedule.Schedule, error) {
+	var s schedule.Schedule
+	var parameters []byte
+	var lastRunAt, nextRunAt sql.NullTime
+	if err := row.Scan(&s.ID, &s.Name, &s.ApplicationName, &s.GoldenPath, &s.Cron, &s.Timezone, &parameters,
+		&s.Enabled, &s.CreatedBy, &s.CreatedAt, &lastRunAt, &nextRunAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan schedule: %w", err)
+	}
+	if err := json.Unmarshal(parameters, &s.Parameters); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal parameters: %w", err)
+	}
+	if lastRunAt.Valid {
+		s.LastRunAt = &lastRunAt.Time
+	}
+	if nextRunAt.Valid {
+		s.NextRunAt = &nextRunAt.Time
+	}
+	return &s, nil
+}
@@ -182,6 +182,15 @@ func (d *Database) DeleteSession(sessionID string) error {
 	return nil
 }
 
+// DeleteUserSessions ends all sessions of a user, e.g. when the user is deprovisioned
+func (d *Database) DeleteUserSessions(username string) (int64, error) {
+	result, err := d.db.Exec(`DELETE FROM sessions WHERE user_data->'user'->>'Username' = $1`, username)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete user sessions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // CleanupExpiredSessions removes all expired sessions
 func (d *Database) CleanupExpiredSessions() (int64, error) {
 	query := `DELETE FROM sessions WHERE expires_at <= NOW()`
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DailyWorkflowRuns counts the workflow executions started on one UTC day
+type DailyWorkflowRuns struct {
+	Date   string `json:"date"` // YYYY-MM-DD
+	Runs   int    `json:"runs"`
+	Failed int    `json:"failed"`
+}
+
+// WorkflowRunsPerDay counts the executions of the workflows of applications started
+// since since, per UTC day. Days without executions are left out.
+func (r *WorkflowRepository) WorkflowRunsPerDay(applications []string, since time.Time) ([]DailyWorkflowRuns, error) {
+	rows, err := r.db.db.Query(`
+		SELECT to_char(started_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE status = 'failed')
+		FROM workflow_executions
+		WHERE application_name = ANY($1) AND started_at >= $2
+		GROUP BY day
+		ORDER BY day
+	`, pq.Array(applications), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count workflow runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	days := []DailyWorkflowRuns{}
+	for rows.Next() {
+		var day DailyWorkflowRuns
+		if err := rows.Scan(&day.Date, &day.Runs, &day.Failed); err != nil {
+			return nil, fmt.Errorf("failed to scan workflow runs: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count workflow runs: %w", err)
+	}
+	return days, nil
+}
+
+// StepLogBytes returns the size of the step logs stored for the workflows of applications
+func (r *WorkflowRepository) StepLogBytes(applications []string) (int64, error) {
+	var size int64
+	err := r.db.db.QueryRow(`
+		SELECT COALESCE(SUM(octet_length(s.output_logs)), 0)
+		FROM workflow_step_executions s
+		JOIN workflow_executions we ON we.id = s.workflow_execution_id
+		WHERE we.application_name = ANY($1)
+	`, pq.Array(applications)).Scan(&size)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum step log sizes: %w", err)
+	}
+	return size, nil
+}
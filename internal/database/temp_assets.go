@@ -0,0 +1,70 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// TempAsset is a file or directory a workflow step wrote to the temp directory
+type TempAsset struct {
+	ID              int64     `json:"id"`
+	Path            string    `json:"path"`
+	ExecutionID     *int64    `json:"execution_id,omitempty"`
+	ExecutionStatus string    `json:"execution_status,omitempty"` // Empty when the execution was deleted
+	ApplicationName string    `json:"application_name,omitempty"`
+	StepName        string    `json:"step_name,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	LastUsedAt      time.Time `json:"last_used_at"`
+}
+
+// RecordTempAsset records that a step of an execution uses path. A path that is already
+// tracked moves to the new execution.
+func (r *WorkflowRepository) RecordTempAsset(path string, executionID *int64, appName, stepName string) error {
+	_, err := r.db.db.Exec(`
+		INSERT INTO workflow_temp_assets (path, execution_id, application_name, step_name)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (path) DO UPDATE
+		SET execution_id = EXCLUDED.execution_id,
+		    application_name = EXCLUDED.application_name,
+		    step_name = EXCLUDED.step_name,
+		    last_used_at = NOW()
+	`, path, executionID, appName, stepName)
+	if err != nil {
+		return fmt.Errorf("failed to record temp asset: %w", err)
+	}
+	return nil
+}
+
+// ListTempAssets returns the tracked temp assets with the status of their execution,
+// least recently used first
+func (r *WorkflowRepository) ListTempAssets() ([]*TempAsset, error) {
+	rows, err := r.db.db.Query(`
+		SELECT a.id, a.path, a.execution_id, COALESCE(we.status, ''), a.application_name, a.step_name, a.created_at, a.last_used_at
+		FROM workflow_temp_assets a
+		LEFT JOIN workflow_executions we ON we.id = a.execution_id
+		ORDER BY a.last_used_at, a.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query temp assets: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	assets := []*TempAsset{}
+	for rows.Next() {
+		var asset TempAsset
+		if err := rows.Scan(&asset.ID, &asset.Path, &asset.ExecutionID, &asset.ExecutionStatus,
+			&asset.ApplicationName, &asset.StepName, &asset.CreatedAt, &asset.LastUsedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan temp asset: %w", err)
+		}
+		assets = append(assets, &asset)
+	}
+	return assets, rows.Err()
+}
+
+// DeleteTempAsset stops tracking path
+func (r *WorkflowRepository) DeleteTempAsset(path string) error {
+	if _, err := r.db.db.Exec(`DELETE FROM workflow_temp_assets WHERE path = $1`, path); err != nil {
+		return fmt.Errorf("failed to delete temp asset: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"innominatus/internal/users"
+)
+
+// SaveWorkloadToken stores a token issued to a Kubernetes service account and removes the
+// tokens that expired before now
+func (d *Database) SaveWorkloadToken(tokenHash string, user *users.User, expiresAt, now time.Time) error {
+	if d.db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+
+	if _, err := d.db.Exec(`DELETE FROM workload_tokens WHERE expires_at <= $1`, now); err != nil {
+		return fmt.Errorf("failed to prune workload tokens: %w", err)
+	}
+
+	query := `
+		INSERT INTO workload_tokens (token_hash, username, team, role, issued_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := d.db.Exec(query, tokenHash, user.Username, user.Team, user.Role, now, expiresAt); err != nil {
+		return fmt.Errorf("failed to save workload token: %w", err)
+	}
+	return nil
+}
+
+// GetWorkloadToken returns the user of a workload token, or nil when the token is unknown
+// or expired at now
+func (d *Database) GetWorkloadToken(tokenHash string, now time.Time) (*users.User, time.Time, error) {
+	if d.db == nil {
+		return nil, time.Time{}, fmt.Errorf("database connection is nil")
+	}
+
+	user := &users.User{}
+	var expiresAt time.Time
+	err := d.db.QueryRow(`
+		SELECT username, team, role, expires_at FROM workload_tokens
+		WHERE token_hash = $1 AND expires_at > $2
+	`, tokenHash, now).Scan(&user.Username, &user.Team, &user.Role, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get workload token: %w", err)
+	}
+	return user, expiresAt, nil
+}
@@ -0,0 +1,537 @@
+// Package devdb stores workflow executions in a local SQLite file, so the server can
+// run on a laptop without PostgreSQL. It is meant for development and demos only:
+// everything besides workflow history needs PostgreSQL.
+package devdb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"innominatus/internal/database"
+
+	_ "modernc.org/sqlite" // SQLite driver without cgo
+)
+
+// Driver is the --dev-db value that selects this store
+const Driver = "sqlite"
+
+// DefaultPath is where the database file is created when no path is configured
+const DefaultPath = "data/innominatus-dev.db"
+
+// UnsupportedFeatures lists what needs PostgreSQL and is unavailable in dev mode
+var UnsupportedFeatures = []string{
+	"application storage (deployed Score specs are not kept)",
+	"resources and orchestration engine",
+	"approvals and change tickets",
+	"API keys (sessions and login lockouts do not survive restarts)",
+	"graph tracking and provenance records",
+	"queue persistence (queued workflows are lost on restart)",
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS workflow_executions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	application_name TEXT NOT NULL,
+	workflow_name TEXT NOT NULL,
+	status TEXT NOT NULL,
+	started_at DATETIME NOT NULL,
+	completed_at DATETIME,
+	error_message TEXT,
+	total_steps INTEGER NOT NULL DEFAULT 0,
+	parent_execution_id INTEGER REFERENCES workflow_executions(id),
+	retry_count INTEGER NOT NULL DEFAULT 0,
+	is_retry BOOLEAN NOT NULL DEFAULT 0,
+	resume_from_step INTEGER,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_dev_workflow_executions_app ON workflow_executions(application_name);
+
+CREATE TABLE IF NOT EXISTS workflow_step_executions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	workflow_execution_id INTEGER NOT NULL REFERENCES workflow_executions(id) ON DELETE CASCADE,
+	step_number INTEGER NOT NULL,
+	step_name TEXT NOT NULL,
+	step_type TEXT NOT NULL,
+	status TEXT NOT NULL,
+	started_at DATETIME,
+	completed_at DATETIME,
+	duration_ms INTEGER,
+	error_message TEXT,
+	step_config TEXT,
+	output_logs TEXT,
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_dev_workflow_steps_execution ON workflow_step_executions(workflow_execution_id);
+`
+
+// Store implements the workflow repository over SQLite
+type Store struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// Open opens or creates the SQLite database at path and ensures its schema
+func Open(path string) (*Store, error) {
+	if path == "" {
+		path = DefaultPath
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory for dev database: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dev database: %w", err)
+	}
+	// SQLite allows one writer; a single connection serializes step updates
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create dev database schema: %w", err)
+	}
+	return &Store{db: db, now: time.Now}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// CreateWorkflowExecution creates a new workflow execution record
+func (s *Store) CreateWorkflowExecution(appName, workflowName string, totalSteps int) (*database.WorkflowExecution, error) {
+	now := s.now().UTC()
+	result, err := s.db.Exec(`
+		INSERT INTO workflow_executions (application_name, workflow_name, status, total_steps, started_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, appName, workflowName, database.WorkflowStatusRunning, totalSteps, now, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workflow execution: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workflow execution: %w", err)
+	}
+
+	return &database.WorkflowExecution{
+		ID:              id,
+		ApplicationName: appName,
+		WorkflowName:    workflowName,
+		Status:          database.WorkflowStatusRunning,
+		StartedAt:       now,
+		TotalSteps:      totalSteps,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}, nil
+}
+
+// CreateRetryExecution creates a new workflow execution as a retry of a previous execution
+func (s *Store) CreateRetryExecution(parentID int64, appName, workflowName string, totalSteps, resumeFromStep int) (*database.WorkflowExecution, error) {
+	parent, err := s.getExecution(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent execution: %w", err)
+	}
+
+	now := s.now().UTC()
+	retryCount := parent.RetryCount + 1
+	result, err := s.db.Exec(`
+		INSERT INTO workflow_executions (
+			application_name, workflow_name, status, total_steps, started_at,
+			parent_execution_id, retry_count, is_retry, resume_from_step, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?, ?)
+	`, appName, workflowName, database.WorkflowStatusRunning, totalSteps, now, parentID, retryCount, resumeFromStep, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create retry execution: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create retry execution: %w", err)
+	}
+
+	return &database.WorkflowExecution{
+		ID:                id,
+		ApplicationName:   appName,
+		WorkflowName:      workflowName,
+		Status:            database.WorkflowStatusRunning,
+		StartedAt:         now,
+		TotalSteps:        totalSteps,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		ParentExecutionID: &parentID,
+		RetryCount:        retryCount,
+		IsRetry:           true,
+		ResumeFromStep:    &resumeFromStep,
+	}, nil
+}
+
+// UpdateWorkflowExecution sets the status of an execution; final statuses set completed_at
+func (s *Store) UpdateWorkflowExecution(execID int64, status string, errorMessage *string) error {
+	now := s.now().UTC()
+	var err error
+	if status == database.WorkflowStatusCompleted || status == database.WorkflowStatusFailed {
+		_, err = s.db.Exec(`
+			UPDATE workflow_executions SET status = ?, completed_at = ?, error_message = ?, updated_at = ?
+			WHERE id = ?
+		`, status, now, errorMessage, now, execID)
+	} else {
+		_, err = s.db.Exec(`
+			UPDATE workflow_executions SET status = ?, error_message = ?, updated_at = ?
+			WHERE id = ?
+		`, status, errorMessage, now, execID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update workflow execution: %w", err)
+	}
+	return nil
+}
+
+// CreateWorkflowStep creates a new pending step record
+func (s *Store) CreateWorkflowStep(execID int64, stepNumber int, stepName, stepType string, config map[string]interface{}) (*database.WorkflowStepExecution, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal step config: %w", err)
+	}
+
+	now := s.now().UTC()
+	result, err := s.db.Exec(`
+		INSERT INTO workflow_step_executions (workflow_execution_id, step_number, step_name, step_type, status, step_config, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, execID, stepNumber, stepName, stepType, database.StepStatusPending, string(configJSON), now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workflow step: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workflow step: %w", err)
+	}
+
+	return &database.WorkflowStepExecution{
+		ID:                  id,
+		WorkflowExecutionID: execID,
+		StepNumber:          stepNumber,
+		StepName:            stepName,
+		StepType:            stepType,
+		Status:              database.StepStatusPending,
+		StepConfig:          config,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}, nil
+}
+
+// UpdateWorkflowStepStatus sets the status of a step. Running sets started_at, final
+// statuses set completed_at and the duration.
+func (s *Store) UpdateWorkflowStepStatus(stepID int64, status string, errorMessage *string) error {
+	now := s.now().UTC()
+	var err error
+	switch status {
+	case database.StepStatusRunning:
+		_, err = s.db.Exec(`
+			UPDATE workflow_step_executions SET status = ?, started_at = ?, error_message = ?, updated_at = ?
+			WHERE id = ?
+		`, status, now, errorMessage, now, stepID)
+	case database.StepStatusCompleted, database.StepStatusFailed:
+		var startedAt sql.NullTime
+		if err := s.db.QueryRow(`SELECT started_at FROM workflow_step_executions WHERE id = ?`, stepID).Scan(&startedAt); err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to update workflow step status: %w", err)
+		}
+		var duration *int64
+		if startedAt.Valid {
+			ms := now.Sub(startedAt.Time).Milliseconds()
+			duration = &ms
+		}
+		_, err = s.db.Exec(`
+			UPDATE workflow_step_executions SET status = ?, completed_at = ?, error_message = ?, duration_ms = ?, updated_at = ?
+			WHERE id = ?
+		`, status, now, errorMessage, duration, now, stepID)
+	default:
+		_, err = s.db.Exec(`
+			UPDATE workflow_step_executions SET status = ?, error_message = ?, updated_at = ?
+			WHERE id = ?
+		`, status, errorMessage, now, stepID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update workflow step status: %w", err)
+	}
+	return nil
+}
+
+// AddWorkflowStepLogs appends output to the logs of a step
+func (s *Store) AddWorkflowStepLogs(stepID int64, logs string) error {
+	_, err := s.db.Exec(`
+		UPDATE workflow_step_executions SET output_logs = COALESCE(output_logs, '') || ?
+		WHERE id = ?
+	`, logs, stepID)
+	if err != nil {
+		return fmt.Errorf("failed to add workflow step logs: %w", err)
+	}
+	return nil
+}
+
+const executionColumns = `id, application_name, workflow_name, status, started_at, completed_at,
+	error_message, total_steps, created_at, updated_at,
+	parent_execution_id, retry_count, is_retry, resume_from_step`
+
+func scanExecution(row interface{ Scan(...interface{}) error }) (*database.WorkflowExecution, error) {
+	execution := &database.WorkflowExecution{}
+	var completedAt sql.NullTime
+	err := row.Scan(
+		&execution.ID,
+		&execution.ApplicationName,
+		&execution.WorkflowName,
+		&execution.Status,
+		&execution.StartedAt,
+		&completedAt,
+		&execution.ErrorMessage,
+		&execution.TotalSteps,
+		&execution.CreatedAt,
+		&execution.UpdatedAt,
+		&execution.ParentExecutionID,
+		&execution.RetryCount,
+		&execution.IsRetry,
+		&execution.ResumeFromStep,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if completedAt.Valid {
+		execution.CompletedAt = &completedAt.Time
+	}
+	return execution, nil
+}
+
+func (s *Store) getExecution(id int64) (*database.WorkflowExecution, error) {
+	execution, err := scanExecution(s.db.QueryRow(`SELECT `+executionColumns+` FROM workflow_executions WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("workflow execution not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow execution: %w", err)
+	}
+	return execution, nil
+}
+
+// GetWorkflowExecution returns an execution with its steps
+func (s *Store) GetWorkflowExecution(id int64) (*database.WorkflowExecution, error) {
+	execution, err := s.getExecution(id)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := s.getSteps(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load workflow steps: %w", err)
+	}
+	execution.Steps = steps
+	return execution, nil
+}
+
+func (s *Store) getSteps(execID int64) ([]*database.WorkflowStepExecution, error) {
+	rows, err := s.db.Query(`
+		SELECT id, workflow_execution_id, step_number, step_name, step_type, status,
+		       started_at, completed_at, duration_ms, error_message, step_config, output_logs,
+		       created_at, updated_at
+		FROM workflow_step_executions
+		WHERE workflow_execution_id = ?
+		ORDER BY step_number ASC
+	`, execID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workflow steps: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var steps []*database.WorkflowStepExecution
+	for rows.Next() {
+		step := &database.WorkflowStepExecution{}
+		var startedAt, completedAt sql.NullTime
+		var stepConfig sql.NullString
+		if err := rows.Scan(
+			&step.ID,
+			&step.WorkflowExecutionID,
+			&step.StepNumber,
+			&step.StepName,
+			&step.StepType,
+			&step.Status,
+			&startedAt,
+			&completedAt,
+			&step.DurationMs,
+			&step.ErrorMessage,
+			&stepConfig,
+			&step.OutputLogs,
+			&step.CreatedAt,
+			&step.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan workflow step: %w", err)
+		}
+		if startedAt.Valid {
+			step.StartedAt = &startedAt.Time
+		}
+		if completedAt.Valid {
+			step.CompletedAt = &completedAt.Time
+		}
+		if stepConfig.Valid {
+			var config map[string]interface{}
+			if err := json.Unmarshal([]byte(stepConfig.String), &config); err == nil {
+				step.StepConfig = config
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps, rows.Err()
+}
+
+// executionFilter matches executions the way the PostgreSQL repository does; SQLite's
+// LIKE is already case-insensitive for ASCII
+const executionFilter = `
+	WHERE (?1 = '' OR we.application_name = ?1)
+	  AND (?2 = '' OR we.workflow_name LIKE '%' || ?2 || '%')
+	  AND (?3 = '' OR we.status = ?3)`
+
+// CountWorkflowExecutions counts executions matching the filters
+func (s *Store) CountWorkflowExecutions(appName, workflowName, status string) (int64, error) {
+	var count int64
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM workflow_executions we`+executionFilter, appName, workflowName, status).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count workflow executions: %w", err)
+	}
+	return count, nil
+}
+
+// ListWorkflowExecutions lists executions matching the filters, newest first
+func (s *Store) ListWorkflowExecutions(appName, workflowName, status string, limit, offset int) ([]*database.WorkflowExecutionSummary, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+	rows, err := s.db.Query(`
+		SELECT we.id, we.application_name, we.workflow_name, we.status, we.started_at,
+		       we.completed_at, we.total_steps,
+		       COALESCE(SUM(CASE WHEN ws.status = 'completed' THEN 1 ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN ws.status = 'failed' THEN 1 ELSE 0 END), 0)
+		FROM workflow_executions we
+		LEFT JOIN workflow_step_executions ws ON ws.workflow_execution_id = we.id`+executionFilter+`
+		GROUP BY we.id
+		ORDER BY we.started_at DESC, we.id DESC
+		LIMIT ?4 OFFSET ?5
+	`, appName, workflowName, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workflow executions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var executions []*database.WorkflowExecutionSummary
+	for rows.Next() {
+		exec := &database.WorkflowExecutionSummary{}
+		var completedAt sql.NullTime
+		if err := rows.Scan(
+			&exec.ID,
+			&exec.ApplicationName,
+			&exec.WorkflowName,
+			&exec.Status,
+			&exec.StartedAt,
+			&completedAt,
+			&exec.TotalSteps,
+			&exec.CompletedSteps,
+			&exec.FailedSteps,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan workflow execution: %w", err)
+		}
+		if completedAt.Valid {
+			exec.CompletedAt = &completedAt.Time
+			duration := completedAt.Time.Sub(exec.StartedAt).Milliseconds()
+			exec.Duration = &duration
+		}
+		executions = append(executions, exec)
+	}
+	return executions, rows.Err()
+}
+
+// GetLatestWorkflowExecution returns the newest execution of a workflow, or nil if it never ran
+func (s *Store) GetLatestWorkflowExecution(appName, workflowName string) (*database.WorkflowExecution, error) {
+	execution, err := scanExecution(s.db.QueryRow(`
+		SELECT `+executionColumns+` FROM workflow_executions
+		WHERE application_name = ? AND workflow_name = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`, appName, workflowName))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest workflow execution: %w", err)
+	}
+	return execution, nil
+}
+
+// GetFirstFailedStepNumber returns the number of the first failed step of an execution
+func (s *Store) GetFirstFailedStepNumber(executionID int64) (int, error) {
+	var stepNumber int
+	err := s.db.QueryRow(`
+		SELECT step_number FROM workflow_step_executions
+		WHERE workflow_execution_id = ? AND status = ?
+		ORDER BY step_number ASC
+		LIMIT 1
+	`, executionID, database.StepStatusFailed).Scan(&stepNumber)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("no failed step found")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get first failed step: %w", err)
+	}
+	return stepNumber, nil
+}
+
+// ReconstructWorkflowFromExecution rebuilds the workflow of an execution from its stored
+// steps, leaving out compensation steps
+func (s *Store) ReconstructWorkflowFromExecution(executionID int64) (map[string]interface{}, error) {
+	rows, err := s.db.Query(`
+		SELECT step_number, step_name, step_type, step_config
+		FROM workflow_step_executions
+		WHERE workflow_execution_id = ? AND step_type <> ?
+		ORDER BY step_number ASC
+	`, executionID, database.StepTypeCompensation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workflow steps: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var steps []map[string]interface{}
+	for rows.Next() {
+		var stepNumber int
+		var stepName, stepType string
+		var stepConfig sql.NullString
+		if err := rows.Scan(&stepNumber, &stepName, &stepType, &stepConfig); err != nil {
+			return nil, fmt.Errorf("failed to scan step row: %w", err)
+		}
+
+		var config map[string]interface{}
+		if stepConfig.Valid && stepConfig.String != "" {
+			if err := json.Unmarshal([]byte(stepConfig.String), &config); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal step config for step %d: %w", stepNumber, err)
+			}
+		}
+
+		step := map[string]interface{}{"name": stepName, "type": stepType}
+		for k, v := range config {
+			step[k] = v
+		}
+		steps = append(steps, step)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating step rows: %w", err)
+	}
+
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no steps found for workflow execution %d", executionID)
+	}
+	return map[string]interface{}{"steps": steps}, nil
+}
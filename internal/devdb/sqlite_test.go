@@ -0,0 +1,137 @@
+package devdb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"innominatus/internal/database"
+	"innominatus/internal/workflow"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ workflow.WorkflowRepositoryInterface = (*Store)(nil)
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "dev", "innominatus.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestWorkflowExecutionLifecycle(t *testing.T) {
+	store := openStore(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	exec, err := store.CreateWorkflowExecution("demo-app", "deploy", 2)
+	require.NoError(t, err)
+	assert.Equal(t, database.WorkflowStatusRunning, exec.Status)
+
+	step, err := store.CreateWorkflowStep(exec.ID, 1, "provision", "terraform", map[string]interface{}{"path": "./tf"})
+	require.NoError(t, err)
+	require.NoError(t, store.UpdateWorkflowStepStatus(step.ID, database.StepStatusRunning, nil))
+	require.NoError(t, store.AddWorkflowStepLogs(step.ID, "init\n"))
+	require.NoError(t, store.AddWorkflowStepLogs(step.ID, "apply\n"))
+
+	now = now.Add(1500 * time.Millisecond)
+	message := "exit status 1"
+	require.NoError(t, store.UpdateWorkflowStepStatus(step.ID, database.StepStatusFailed, &message))
+	require.NoError(t, store.UpdateWorkflowExecution(exec.ID, database.WorkflowStatusFailed, &message))
+
+	got, err := store.GetWorkflowExecution(exec.ID)
+	require.NoError(t, err)
+	assert.Equal(t, database.WorkflowStatusFailed, got.Status)
+	require.NotNil(t, got.CompletedAt)
+	require.Len(t, got.Steps, 1)
+	assert.Equal(t, "init\napply\n", *got.Steps[0].OutputLogs)
+	require.NotNil(t, got.Steps[0].DurationMs)
+	assert.Equal(t, int64(1500), *got.Steps[0].DurationMs)
+	assert.Equal(t, "./tf", got.Steps[0].StepConfig["path"])
+
+	failed, err := store.GetFirstFailedStepNumber(exec.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+
+	_, err = store.GetWorkflowExecution(exec.ID + 100)
+	assert.EqualError(t, err, "workflow execution not found")
+}
+
+func TestListWorkflowExecutions(t *testing.T) {
+	store := openStore(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	first, err := store.CreateWorkflowExecution("demo-app", "Deploy-App", 1)
+	require.NoError(t, err)
+	step, err := store.CreateWorkflowStep(first.ID, 1, "apply", "kubernetes", nil)
+	require.NoError(t, err)
+	require.NoError(t, store.UpdateWorkflowStepStatus(step.ID, database.StepStatusCompleted, nil))
+	now = now.Add(2 * time.Second)
+	require.NoError(t, store.UpdateWorkflowExecution(first.ID, database.WorkflowStatusCompleted, nil))
+
+	second, err := store.CreateWorkflowExecution("other-app", "cleanup", 1)
+	require.NoError(t, err)
+
+	executions, err := store.ListWorkflowExecutions("", "", "", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, executions, 2)
+	assert.Equal(t, second.ID, executions[0].ID, "newest execution first")
+	assert.Equal(t, 1, executions[1].CompletedSteps)
+	require.NotNil(t, executions[1].Duration)
+	assert.Equal(t, int64(2000), *executions[1].Duration)
+
+	executions, err = store.ListWorkflowExecutions("", "deploy", "", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, executions, 1, "the name filter ignores case")
+	assert.Equal(t, first.ID, executions[0].ID)
+
+	count, err := store.CountWorkflowExecutions("other-app", "", database.WorkflowStatusRunning)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	latest, err := store.GetLatestWorkflowExecution("demo-app", "Deploy-App")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, latest.ID)
+
+	latest, err = store.GetLatestWorkflowExecution("demo-app", "missing")
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+}
+
+func TestRetryAndReconstruct(t *testing.T) {
+	store := openStore(t)
+
+	exec, err := store.CreateWorkflowExecution("demo-app", "deploy", 2)
+	require.NoError(t, err)
+	_, err = store.CreateWorkflowStep(exec.ID, 1, "render", "render", map[string]interface{}{"template": "app.yaml"})
+	require.NoError(t, err)
+	_, err = store.CreateWorkflowStep(exec.ID, 2, "undo-render", database.StepTypeCompensation, nil)
+	require.NoError(t, err)
+
+	workflow, err := store.ReconstructWorkflowFromExecution(exec.ID)
+	require.NoError(t, err)
+	steps := workflow["steps"].([]map[string]interface{})
+	require.Len(t, steps, 1, "compensation steps are not part of the workflow")
+	assert.Equal(t, "render", steps[0]["name"])
+	assert.Equal(t, "app.yaml", steps[0]["template"])
+
+	retry, err := store.CreateRetryExecution(exec.ID, "demo-app", "deploy", 2, 2)
+	require.NoError(t, err)
+	assert.True(t, retry.IsRetry)
+	assert.Equal(t, 1, retry.RetryCount)
+
+	latest, err := store.GetLatestWorkflowExecution("demo-app", "deploy")
+	require.NoError(t, err)
+	assert.Equal(t, retry.ID, latest.ID)
+	require.NotNil(t, latest.ParentExecutionID)
+	assert.Equal(t, exec.ID, *latest.ParentExecutionID)
+	require.NotNil(t, latest.ResumeFromStep)
+	assert.Equal(t, 2, *latest.ResumeFromStep)
+
+	_, err = store.ReconstructWorkflowFromExecution(retry.ID)
+	assert.ErrorContains(t, err, "no steps found")
+}
@@ -0,0 +1,137 @@
+package events
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Topics group event types by the entity they describe
+const (
+	TopicApp      = "app"      // spec.* and deployment.*
+	TopicWorkflow = "workflow" // workflow.* and step.*
+	TopicResource = "resource" // resource.* and provider.*
+)
+
+// TopicOf returns the topic an event type belongs to, or "" for types outside any topic
+func TopicOf(eventType EventType) string {
+	prefix, _, _ := strings.Cut(string(eventType), ".")
+	switch prefix {
+	case "spec", "deployment":
+		return TopicApp
+	case "workflow", "step":
+		return TopicWorkflow
+	case "resource", "provider":
+		return TopicResource
+	default:
+		return ""
+	}
+}
+
+// Filter selects the events delivered to a stream subscriber. Empty fields match everything.
+type Filter struct {
+	Apps       []string
+	Topics     []string
+	Types      []string // Exact event types or prefix patterns such as "workflow.*"
+	WorkflowID string   // Matches the execution_id or workflow_execution_id of an event
+	ResourceID string   // Matches the resource_id of an event
+
+	// Authorize decides whether the subscriber may see events of an application.
+	// It is called for every candidate event; nil allows all applications.
+	Authorize func(appName string) bool
+}
+
+// ParseFilter reads a filter from the query parameters app, topics, types, workflow_id
+// and resource_id. List parameters are comma-separated.
+func ParseFilter(query url.Values) (Filter, error) {
+	filter := Filter{
+		Apps:       splitList(query.Get("app")),
+		Topics:     splitList(query.Get("topics")),
+		Types:      splitList(query.Get("types")),
+		WorkflowID: query.Get("workflow_id"),
+		ResourceID: query.Get("resource_id"),
+	}
+
+	for _, topic := range filter.Topics {
+		switch topic {
+		case TopicApp, TopicWorkflow, TopicResource:
+		default:
+			return Filter{}, fmt.Errorf("unknown topic %q (use %s, %s or %s)", topic, TopicApp, TopicWorkflow, TopicResource)
+		}
+	}
+
+	return filter, nil
+}
+
+// SingleApp returns the application name when the filter is limited to exactly one
+func (f Filter) SingleApp() string {
+	if len(f.Apps) == 1 {
+		return f.Apps[0]
+	}
+	return ""
+}
+
+// Matches reports whether the event passes the filter, including authorization
+func (f Filter) Matches(event Event) bool {
+	if len(f.Apps) > 0 && !contains(f.Apps, event.AppName) {
+		return false
+	}
+	if len(f.Topics) > 0 && !contains(f.Topics, TopicOf(event.Type)) {
+		return false
+	}
+	if len(f.Types) > 0 && !f.matchesType(event.Type) {
+		return false
+	}
+	if f.WorkflowID != "" && !dataEquals(event.Data, f.WorkflowID, "execution_id", "workflow_execution_id") {
+		return false
+	}
+	if f.ResourceID != "" && !dataEquals(event.Data, f.ResourceID, "resource_id") {
+		return false
+	}
+	if f.Authorize != nil && !f.Authorize(event.AppName) {
+		return false
+	}
+	return true
+}
+
+func (f Filter) matchesType(eventType EventType) bool {
+	for _, pattern := range f.Types {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(string(eventType), prefix) {
+				return true
+			}
+		} else if string(eventType) == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// dataEquals compares event data values of any type with the string form of want
+func dataEquals(data map[string]interface{}, want string, keys ...string) bool {
+	for _, key := range keys {
+		if value, ok := data[key]; ok && value != nil && fmt.Sprint(value) == want {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func contains(items []string, value string) bool {
+	for _, item := range items {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ParseFilter(url.Values{
+		"app":         {"shop, cart"},
+		"topics":      {"workflow,resource"},
+		"types":       {"step.*"},
+		"workflow_id": {"42"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shop", "cart"}, filter.Apps)
+	assert.Equal(t, []string{"workflow", "resource"}, filter.Topics)
+	assert.Equal(t, []string{"step.*"}, filter.Types)
+	assert.Equal(t, "42", filter.WorkflowID)
+	assert.Empty(t, filter.SingleApp())
+
+	_, err = ParseFilter(url.Values{"topics": {"secrets"}})
+	assert.Error(t, err)
+
+	filter, err = ParseFilter(url.Values{"app": {"shop"}})
+	require.NoError(t, err)
+	assert.Equal(t, "shop", filter.SingleApp())
+}
+
+func TestFilterMatches(t *testing.T) {
+	workflowStarted := NewEvent(EventTypeWorkflowStarted, "shop", "workflow-executor", map[string]interface{}{"execution_id": int64(42)})
+	stepFailed := NewEvent(EventTypeStepFailed, "shop", "workflow-executor", map[string]interface{}{"execution_id": int64(7)})
+	resourceActive := NewEvent(EventTypeResourceActive, "cart", "orchestration-engine", map[string]interface{}{"resource_id": int64(17)})
+	specCreated := NewEvent(EventTypeSpecCreated, "billing", "api", nil)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []Event
+	}{
+		{"empty filter", Filter{}, []Event{workflowStarted, stepFailed, resourceActive, specCreated}},
+		{"apps", Filter{Apps: []string{"shop", "billing"}}, []Event{workflowStarted, stepFailed, specCreated}},
+		{"workflow topic", Filter{Topics: []string{TopicWorkflow}}, []Event{workflowStarted, stepFailed}},
+		{"app topic", Filter{Topics: []string{TopicApp}}, []Event{specCreated}},
+		{"type pattern", Filter{Types: []string{"step.*", "resource.active"}}, []Event{stepFailed, resourceActive}},
+		{"workflow id", Filter{WorkflowID: "42"}, []Event{workflowStarted}},
+		{"resource id", Filter{ResourceID: "17"}, []Event{resourceActive}},
+		{"authorized apps only", Filter{Authorize: func(app string) bool { return app == "cart" }}, []Event{resourceActive}},
+		{"combined", Filter{Apps: []string{"shop"}, Topics: []string{TopicResource}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Event
+			for _, event := range []Event{workflowStarted, stepFailed, resourceActive, specCreated} {
+				if tt.filter.Matches(event) {
+					got = append(got, event)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
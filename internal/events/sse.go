@@ -14,6 +14,7 @@ type SSEClient struct {
 	ID             string
 	AppName        string // Empty string means all apps
 	EventTypes     []EventType
+	Filter         Filter
 	MessageChan    chan Event
 	CloseChan      chan struct{}
 	subscriptionID string
@@ -36,13 +37,23 @@ func NewSSEBroker(eventBus EventBus) *SSEBroker {
 	}
 }
 
-// ServeHTTP handles SSE connections
+// ServeHTTP handles SSE connections filtered by query parameters (see ParseFilter).
+// It performs no authorization; use Serve with Filter.Authorize for multi-tenant streams.
 func (b *SSEBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b.Serve(w, r, filter)
+}
+
+// Serve streams the events matching filter until the client disconnects
+func (b *SSEBroker) Serve(w http.ResponseWriter, r *http.Request, filter Filter) {
 	// Set headers for SSE
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	// Create flusher
 	flusher, ok := w.(http.Flusher)
@@ -51,20 +62,14 @@ func (b *SSEBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get query parameters
-	appName := r.URL.Query().Get("app")
-	eventTypesParam := r.URL.Query().Get("types")
-
-	// Parse event types filter
-	var eventTypes []EventType
-	// TODO: Parse eventTypesParam when needed
-	_ = eventTypesParam // Placeholder for future implementation
+	// Let the event bus pre-filter when the stream covers a single app
+	appName := filter.SingleApp()
 
 	// Create client
 	client := &SSEClient{
 		ID:          fmt.Sprintf("client-%d", time.Now().UnixNano()),
 		AppName:     appName,
-		EventTypes:  eventTypes,
+		Filter:      filter,
 		MessageChan: make(chan Event, 100),
 		CloseChan:   make(chan struct{}),
 	}
@@ -75,7 +80,10 @@ func (b *SSEBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	b.clientMutex.Unlock()
 
 	// Subscribe to event bus
-	client.subscriptionID = b.eventBus.Subscribe(appName, eventTypes, func(event Event) {
+	client.subscriptionID = b.eventBus.Subscribe(appName, nil, func(event Event) {
+		if !client.Filter.Matches(event) {
+			return
+		}
 		select {
 		case client.MessageChan <- event:
 		case <-client.CloseChan:
@@ -18,14 +18,20 @@ const (
 	EventTypeResourceCreated      EventType = "resource.created"
 	EventTypeResourceRequested    EventType = "resource.requested"
 	EventTypeResourceProvisioning EventType = "resource.provisioning"
+	EventTypeResourcePlanned      EventType = "resource.planned"
 	EventTypeResourceActive       EventType = "resource.active"
 	EventTypeResourceFailed       EventType = "resource.failed"
+	EventTypeResourceHibernated   EventType = "resource.hibernated"
+	EventTypeResourceDrifted      EventType = "resource.drifted"
+	EventTypeResourceTerminating  EventType = "resource.terminating"
+	EventTypeResourceTerminated   EventType = "resource.terminated"
 
 	// Workflow lifecycle events
 	EventTypeWorkflowCreated   EventType = "workflow.created"
 	EventTypeWorkflowStarted   EventType = "workflow.started"
 	EventTypeWorkflowCompleted EventType = "workflow.completed"
 	EventTypeWorkflowFailed    EventType = "workflow.failed"
+	EventTypeWorkflowProgress  EventType = "workflow.progress"
 
 	// Step execution events
 	EventTypeStepStarted   EventType = "step.started"
@@ -33,6 +39,9 @@ const (
 	EventTypeStepFailed    EventType = "step.failed"
 	EventTypeStepProgress  EventType = "step.progress"
 
+	// Approval gates
+	EventTypeApprovalRequested EventType = "approval.requested"
+
 	// Provider resolution
 	EventTypeProviderResolved EventType = "provider.resolved"
 
@@ -0,0 +1,273 @@
+// Package faults injects failures, delays and crashes into provisioning and workflow
+// steps so platform teams can exercise retry, compensation and alerting paths. The
+// injector is only created when fault injection is enabled outside production.
+package faults
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Kind is what an injected fault does
+type Kind string
+
+const (
+	KindError Kind = "error" // Fail before the target runs
+	KindDelay Kind = "delay" // Wait before the target runs, then run it
+	KindCrash Kind = "crash" // Run the target, then fail as if it crashed halfway
+)
+
+// Scope is where a fault is injected
+type Scope string
+
+const (
+	ScopeProvisioner Scope = "provisioner" // Provisioning of a resource by the orchestration engine
+	ScopeStep        Scope = "step"        // A single workflow step
+)
+
+// MaxDelay bounds injected delays so a forgotten fault cannot stall workflows for hours
+const MaxDelay = 30 * time.Minute
+
+// Fault is an injection rule. Empty target fields match everything.
+type Fault struct {
+	ID    string `json:"id"`
+	Scope Scope  `json:"scope"`
+	Kind  Kind   `json:"kind"`
+
+	// Targets
+	App          string `json:"app,omitempty"`
+	Provider     string `json:"provider,omitempty"`      // Provisioner scope
+	ResourceType string `json:"resource_type,omitempty"` // Provisioner scope
+	Workflow     string `json:"workflow,omitempty"`      // Provisioner scope
+	StepType     string `json:"step_type,omitempty"`     // Step scope
+	StepName     string `json:"step_name,omitempty"`     // Step scope
+
+	Delay       string  `json:"delay,omitempty"`       // Duration of delay faults, e.g. "30s"
+	Message     string  `json:"message,omitempty"`     // Error message of error and crash faults
+	Probability float64 `json:"probability,omitempty"` // Chance to fire per match (0 < p <= 1); 0 always fires
+	Count       int     `json:"count,omitempty"`       // Fire at most this often, then expire; 0 is unlimited
+
+	Hits      int       `json:"hits"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	delay time.Duration
+}
+
+// Target describes the provisioning or step a fault may be injected into
+type Target struct {
+	Scope        Scope
+	App          string
+	Provider     string
+	ResourceType string
+	Workflow     string
+	StepType     string
+	StepName     string
+}
+
+// validate checks the fault and parses its delay
+func (f *Fault) validate() error {
+	switch f.Scope {
+	case ScopeProvisioner:
+		if f.StepType != "" || f.StepName != "" {
+			return fmt.Errorf("step_type and step_name only apply to step faults")
+		}
+	case ScopeStep:
+		if f.Provider != "" || f.ResourceType != "" || f.Workflow != "" {
+			return fmt.Errorf("provider, resource_type and workflow only apply to provisioner faults")
+		}
+	default:
+		return fmt.Errorf("scope must be '%s' or '%s', got '%s'", ScopeProvisioner, ScopeStep, f.Scope)
+	}
+
+	switch f.Kind {
+	case KindDelay:
+		d, err := time.ParseDuration(f.Delay)
+		if err != nil || d <= 0 || d > MaxDelay {
+			return fmt.Errorf("delay must be a duration between 0 and %s, got '%s'", MaxDelay, f.Delay)
+		}
+		f.delay = d
+	case KindError, KindCrash:
+		if f.Delay != "" {
+			return fmt.Errorf("delay only applies to delay faults")
+		}
+	default:
+		return fmt.Errorf("kind must be '%s', '%s' or '%s', got '%s'", KindError, KindDelay, KindCrash, f.Kind)
+	}
+
+	if f.Probability < 0 || f.Probability > 1 {
+		return fmt.Errorf("probability must be between 0 and 1, got %g", f.Probability)
+	}
+	if f.Count < 0 {
+		return fmt.Errorf("count must not be negative, got %d", f.Count)
+	}
+	return nil
+}
+
+// matches reports whether the fault targets t
+func (f *Fault) matches(t Target) bool {
+	return f.Scope == t.Scope &&
+		matchField(f.App, t.App) &&
+		matchField(f.Provider, t.Provider) &&
+		matchField(f.ResourceType, t.ResourceType) &&
+		matchField(f.Workflow, t.Workflow) &&
+		matchField(f.StepType, t.StepType) &&
+		matchField(f.StepName, t.StepName)
+}
+
+func matchField(want, got string) bool {
+	return want == "" || want == got
+}
+
+// Error is returned for injected error and crash faults
+type Error struct {
+	FaultID string
+	Kind    Kind
+	Message string
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" {
+		message = "fault injected for resilience testing"
+	}
+	return fmt.Sprintf("injected %s (fault %s): %s", e.Kind, e.FaultID, message)
+}
+
+// Injector holds the active faults
+type Injector struct {
+	mu     sync.Mutex
+	faults map[string]*Fault
+	nextID int
+	now    func() time.Time
+	random func() float64
+}
+
+// NewInjector creates an injector without faults
+func NewInjector() *Injector {
+	return &Injector{
+		faults: make(map[string]*Fault),
+		now:    time.Now,
+		random: rand.Float64,
+	}
+}
+
+// Add validates and activates a fault, assigning its ID
+func (i *Injector) Add(f Fault) (Fault, error) {
+	if err := f.validate(); err != nil {
+		return Fault{}, err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.nextID++
+	f.ID = strconv.Itoa(i.nextID)
+	f.Hits = 0
+	f.CreatedAt = i.now()
+	i.faults[f.ID] = &f
+	return f, nil
+}
+
+// List returns the active faults in creation order
+func (i *Injector) List() []Fault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	faults := make([]Fault, 0, len(i.faults))
+	for _, f := range i.faults {
+		faults = append(faults, *f)
+	}
+	sort.Slice(faults, func(a, b int) bool {
+		idA, _ := strconv.Atoi(faults[a].ID)
+		idB, _ := strconv.Atoi(faults[b].ID)
+		return idA < idB
+	})
+	return faults
+}
+
+// Remove deactivates a fault and reports whether it existed
+func (i *Injector) Remove(id string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	_, exists := i.faults[id]
+	delete(i.faults, id)
+	return exists
+}
+
+// Clear deactivates all faults
+func (i *Injector) Clear() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = make(map[string]*Fault)
+}
+
+// fire returns the first matching fault (by ID) that fires, recording the hit and
+// expiring faults whose count is used up
+func (i *Injector) fire(t Target) *Fault {
+	if i == nil {
+		return nil
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ids := make([]int, 0, len(i.faults))
+	for id := range i.faults {
+		n, _ := strconv.Atoi(id)
+		ids = append(ids, n)
+	}
+	sort.Ints(ids)
+
+	for _, n := range ids {
+		f := i.faults[strconv.Itoa(n)]
+		if !f.matches(t) {
+			continue
+		}
+		if f.Probability > 0 && i.random() >= f.Probability {
+			continue
+		}
+		f.Hits++
+		if f.Count > 0 && f.Hits >= f.Count {
+			delete(i.faults, f.ID)
+		}
+		fired := *f
+		return &fired
+	}
+	return nil
+}
+
+// Run runs fn for target, injecting the first matching fault: an error fault fails
+// without running fn, a delay fault waits first and a crash fault fails after fn ran
+// successfully. onFire is called with each fault that fires, e.g. to log it. A nil
+// injector runs fn unchanged.
+func (i *Injector) Run(ctx context.Context, t Target, onFire func(Fault), fn func() error) error {
+	f := i.fire(t)
+	if f == nil {
+		return fn()
+	}
+	if onFire != nil {
+		onFire(*f)
+	}
+
+	switch f.Kind {
+	case KindError:
+		return &Error{FaultID: f.ID, Kind: f.Kind, Message: f.Message}
+	case KindDelay:
+		timer := time.NewTimer(f.delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return fn()
+	default: // KindCrash
+		if err := fn(); err != nil {
+			return err
+		}
+		return &Error{FaultID: f.ID, Kind: f.Kind, Message: f.Message}
+	}
+}
@@ -0,0 +1,138 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjector_AddValidates(t *testing.T) {
+	injector := NewInjector()
+
+	invalid := []Fault{
+		{Scope: "cluster", Kind: KindError},
+		{Scope: ScopeStep, Kind: "explode"},
+		{Scope: ScopeStep, Kind: KindError, Provider: "database-team"},
+		{Scope: ScopeProvisioner, Kind: KindError, StepType: "terraform"},
+		{Scope: ScopeStep, Kind: KindDelay},
+		{Scope: ScopeStep, Kind: KindDelay, Delay: "2h"},
+		{Scope: ScopeStep, Kind: KindError, Delay: "1s"},
+		{Scope: ScopeStep, Kind: KindError, Probability: 1.5},
+		{Scope: ScopeStep, Kind: KindError, Count: -1},
+	}
+	for _, f := range invalid {
+		_, err := injector.Add(f)
+		assert.Error(t, err, "fault %+v", f)
+	}
+
+	added, err := injector.Add(Fault{Scope: ScopeProvisioner, Kind: KindError, Provider: "database-team"})
+	require.NoError(t, err)
+	assert.Equal(t, "1", added.ID)
+	assert.Len(t, injector.List(), 1)
+}
+
+func TestInjector_Run(t *testing.T) {
+	ctx := context.Background()
+	step := Target{Scope: ScopeStep, App: "shop", StepType: "terraform", StepName: "provision"}
+
+	t.Run("no matching fault runs the target", func(t *testing.T) {
+		injector := NewInjector()
+		_, err := injector.Add(Fault{Scope: ScopeStep, Kind: KindError, StepType: "kubernetes"})
+		require.NoError(t, err)
+
+		ran := false
+		require.NoError(t, injector.Run(ctx, step, nil, func() error { ran = true; return nil }))
+		assert.True(t, ran)
+	})
+
+	t.Run("error fault fails without running the target", func(t *testing.T) {
+		injector := NewInjector()
+		_, err := injector.Add(Fault{Scope: ScopeStep, Kind: KindError, App: "shop", Message: "disk full"})
+		require.NoError(t, err)
+
+		ran := false
+		var fired []Fault
+		err = injector.Run(ctx, step, func(f Fault) { fired = append(fired, f) }, func() error { ran = true; return nil })
+
+		var injected *Error
+		require.True(t, errors.As(err, &injected))
+		assert.Equal(t, "injected error (fault 1): disk full", err.Error())
+		assert.False(t, ran)
+		require.Len(t, fired, 1)
+		assert.Equal(t, 1, injector.List()[0].Hits)
+	})
+
+	t.Run("crash fault fails after the target ran", func(t *testing.T) {
+		injector := NewInjector()
+		_, err := injector.Add(Fault{Scope: ScopeStep, Kind: KindCrash, StepName: "provision"})
+		require.NoError(t, err)
+
+		ran := false
+		err = injector.Run(ctx, step, nil, func() error { ran = true; return nil })
+		assert.Error(t, err)
+		assert.True(t, ran)
+	})
+
+	t.Run("delay fault waits, then runs the target", func(t *testing.T) {
+		injector := NewInjector()
+		_, err := injector.Add(Fault{Scope: ScopeStep, Kind: KindDelay, Delay: "20ms"})
+		require.NoError(t, err)
+
+		started := time.Now()
+		require.NoError(t, injector.Run(ctx, step, nil, func() error { return nil }))
+		assert.GreaterOrEqual(t, time.Since(started), 20*time.Millisecond)
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.ErrorIs(t, injector.Run(cancelled, step, nil, func() error { return nil }), context.Canceled)
+	})
+
+	t.Run("count expires the fault", func(t *testing.T) {
+		injector := NewInjector()
+		_, err := injector.Add(Fault{Scope: ScopeStep, Kind: KindError, Count: 2})
+		require.NoError(t, err)
+
+		assert.Error(t, injector.Run(ctx, step, nil, func() error { return nil }))
+		assert.Error(t, injector.Run(ctx, step, nil, func() error { return nil }))
+		assert.NoError(t, injector.Run(ctx, step, nil, func() error { return nil }))
+		assert.Empty(t, injector.List())
+	})
+
+	t.Run("probability", func(t *testing.T) {
+		injector := NewInjector()
+		_, err := injector.Add(Fault{Scope: ScopeStep, Kind: KindError, Probability: 0.5})
+		require.NoError(t, err)
+
+		injector.random = func() float64 { return 0.7 }
+		assert.NoError(t, injector.Run(ctx, step, nil, func() error { return nil }))
+		injector.random = func() float64 { return 0.2 }
+		assert.Error(t, injector.Run(ctx, step, nil, func() error { return nil }))
+	})
+
+	t.Run("nil injector runs the target", func(t *testing.T) {
+		var injector *Injector
+		assert.NoError(t, injector.Run(ctx, step, nil, func() error { return nil }))
+	})
+}
+
+func TestInjector_RemoveAndClear(t *testing.T) {
+	injector := NewInjector()
+	for i := 0; i < 3; i++ {
+		_, err := injector.Add(Fault{Scope: ScopeStep, Kind: KindError})
+		require.NoError(t, err)
+	}
+
+	assert.True(t, injector.Remove("2"))
+	assert.False(t, injector.Remove("2"))
+	list := injector.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, "1", list[0].ID)
+	assert.Equal(t, "3", list[1].ID)
+
+	injector.Clear()
+	assert.Empty(t, injector.List())
+}
@@ -0,0 +1,433 @@
+// Package giteateams provisions a Gitea organization for every innominatus team, with
+// Gitea teams and permissions from a template, and keeps their membership in sync.
+package giteateams
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSyncInterval is how often team membership is synced to Gitea
+	DefaultSyncInterval = 10 * time.Minute
+	// DefaultVisibility is the visibility of provisioned organizations
+	DefaultVisibility = "private"
+
+	requestTimeout = 30 * time.Second
+	pageSize       = 50
+)
+
+// DefaultUnits are the repository units a template team has access to
+var DefaultUnits = []string{"repo.code", "repo.issues", "repo.pulls", "repo.releases", "repo.actions"}
+
+var (
+	invalidOrgChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+	permissions     = map[string]bool{"read": true, "write": true, "admin": true}
+	visibilities    = map[string]bool{"public": true, "limited": true, "private": true}
+)
+
+// Config is the gitea.teamProvisioning section of admin-config.yaml
+type Config struct {
+	Enabled      bool           `yaml:"enabled" json:"enabled"`           // Provision organizations for teams and sync their members
+	OrgPrefix    string         `yaml:"orgPrefix" json:"orgPrefix"`       // Prefix of the organization names, e.g. team- gives team-payments
+	Visibility   string         `yaml:"visibility" json:"visibility"`     // public, limited or private (default private)
+	SyncInterval string         `yaml:"syncInterval" json:"syncInterval"` // How often membership is synced (default 10m)
+	Teams        []TeamTemplate `yaml:"teams" json:"teams"`               // Gitea teams of every organization (default: developers with write access)
+}
+
+// TeamTemplate is a Gitea team created in every provisioned organization
+type TeamTemplate struct {
+	Name       string   `yaml:"name" json:"name"`
+	Permission string   `yaml:"permission" json:"permission"` // read, write or admin
+	Units      []string `yaml:"units" json:"units"`           // Repository units (default DefaultUnits)
+	Roles      []string `yaml:"roles" json:"roles"`           // innominatus roles of the members that join, empty for all members
+}
+
+// Validate checks the configuration
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Visibility != "" && !visibilities[c.Visibility] {
+		return fmt.Errorf("gitea.teamProvisioning.visibility must be public, limited or private, got '%s'", c.Visibility)
+	}
+	if invalidOrgChars.MatchString(c.OrgPrefix) {
+		return fmt.Errorf("gitea.teamProvisioning.orgPrefix '%s' may only contain letters, digits and _.-", c.OrgPrefix)
+	}
+	if _, err := c.Interval(); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for i, t := range c.Teams {
+		if t.Name == "" {
+			return fmt.Errorf("gitea.teamProvisioning.teams[%d].name is required", i)
+		}
+		if strings.EqualFold(t.Name, "owners") {
+			return fmt.Errorf("gitea.teamProvisioning.teams[%d]: the Owners team is managed by Gitea", i)
+		}
+		if seen[strings.ToLower(t.Name)] {
+			return fmt.Errorf("gitea.teamProvisioning.teams[%d]: duplicate team '%s'", i, t.Name)
+		}
+		seen[strings.ToLower(t.Name)] = true
+		if !permissions[t.Permission] {
+			return fmt.Errorf("gitea.teamProvisioning.teams[%d].permission must be read, write or admin, got '%s'", i, t.Permission)
+		}
+	}
+	return nil
+}
+
+// Interval returns the sync interval, applying the default
+func (c Config) Interval() (time.Duration, error) {
+	if c.SyncInterval == "" {
+		return DefaultSyncInterval, nil
+	}
+	interval, err := time.ParseDuration(c.SyncInterval)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid gitea.teamProvisioning.syncInterval '%s'", c.SyncInterval)
+	}
+	return interval, nil
+}
+
+// Templates returns the team templates, applying the default
+func (c Config) Templates() []TeamTemplate {
+	if len(c.Teams) == 0 {
+		return []TeamTemplate{{Name: "developers", Permission: "write"}}
+	}
+	return c.Teams
+}
+
+// OrgName returns the Gitea organization of an innominatus team
+func (c Config) OrgName(teamID string) string {
+	name := invalidOrgChars.ReplaceAllString(c.OrgPrefix+teamID, "-")
+	return strings.Trim(name, "-.")
+}
+
+func (c Config) visibility() string {
+	if c.Visibility == "" {
+		return DefaultVisibility
+	}
+	return c.Visibility
+}
+
+// Team is an innominatus team with its members
+type Team struct {
+	ID          string
+	Name        string
+	Description string
+	Members     []Member
+}
+
+// Member is a member of a team; Username is the Gitea login
+type Member struct {
+	Username string
+	Role     string
+}
+
+// members returns the logins of the members with one of roles, all members without roles
+func (t Team) members(roles []string) []string {
+	var logins []string
+	for _, m := range t.Members {
+		if len(roles) == 0 || contains(roles, m.Role) {
+			logins = append(logins, m.Username)
+		}
+	}
+	sort.Strings(logins)
+	return logins
+}
+
+// Result describes what a sync changed in the organization of a team
+type Result struct {
+	Team           string   `json:"team"`
+	Organization   string   `json:"organization"`
+	CreatedOrg     bool     `json:"created_org,omitempty"`
+	CreatedTeams   []string `json:"created_teams,omitempty"`
+	UpdatedTeams   []string `json:"updated_teams,omitempty"`
+	AddedMembers   []string `json:"added_members,omitempty"`   // team/login
+	RemovedMembers []string `json:"removed_members,omitempty"` // team/login
+	Warnings       []string `json:"warnings,omitempty"`        // e.g. members without a Gitea account
+	Error          string   `json:"error,omitempty"`
+}
+
+// Provisioner creates and syncs the organizations through the Gitea API
+type Provisioner struct {
+	config   Config
+	baseURL  string
+	username string // Admin user; owns the organizations and is never removed from teams
+	password string
+	client   *http.Client
+}
+
+// NewProvisioner creates a provisioner using the Gitea admin account
+func NewProvisioner(config Config, baseURL, username, password string) *Provisioner {
+	return &Provisioner{
+		config:   config,
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// SyncAll syncs the organizations of all teams. A failing team does not stop the others.
+func (p *Provisioner) SyncAll(teams []Team) []Result {
+	results := make([]Result, 0, len(teams))
+	for _, team := range teams {
+		results = append(results, p.Sync(team))
+	}
+	return results
+}
+
+// Sync creates the organization and template teams of team if they are missing, fixes
+// their permissions and makes the Gitea team members match the innominatus members
+func (p *Provisioner) Sync(team Team) Result {
+	result := Result{Team: team.ID, Organization: p.config.OrgName(team.ID)}
+	if err := p.sync(team, &result); err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (p *Provisioner) sync(team Team, result *Result) error {
+	org := result.Organization
+	created, err := p.ensureOrg(org, team)
+	if err != nil {
+		return err
+	}
+	result.CreatedOrg = created
+
+	var existing []giteaTeam
+	if !created {
+		if err := p.getJSON(fmt.Sprintf("/api/v1/orgs/%s/teams?limit=%d", url.PathEscape(org), pageSize), &existing); err != nil {
+			return fmt.Errorf("list teams of %s: %w", org, err)
+		}
+	}
+
+	for _, template := range p.config.Templates() {
+		gt, action, err := p.ensureTeam(org, template, existing)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case "created":
+			result.CreatedTeams = append(result.CreatedTeams, template.Name)
+		case "updated":
+			result.UpdatedTeams = append(result.UpdatedTeams, template.Name)
+		}
+		if err := p.syncMembers(gt, template, team.members(template.Roles), action == "created", result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// giteaTeam is a team in the Gitea API
+type giteaTeam struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Permission string   `json:"permission"`
+	Units      []string `json:"units"`
+}
+
+// ensureOrg creates the organization unless it exists and returns whether it was created
+func (p *Provisioner) ensureOrg(org string, team Team) (bool, error) {
+	status, body, err := p.request("GET", "/api/v1/orgs/"+url.PathEscape(org), nil)
+	if err != nil {
+		return false, fmt.Errorf("get organization %s: %w", org, err)
+	}
+	if status == http.StatusOK {
+		return false, nil
+	}
+	if status != http.StatusNotFound {
+		return false, fmt.Errorf("get organization %s, status %d: %s", org, status, body)
+	}
+
+	description := team.Description
+	if description == "" {
+		description = fmt.Sprintf("Repositories of team %s", team.Name)
+	}
+	status, body, err = p.request("POST", "/api/v1/orgs", map[string]interface{}{
+		"username":    org,
+		"full_name":   team.Name,
+		"description": description,
+		"visibility":  p.config.visibility(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("create organization %s: %w", org, err)
+	}
+	if status != http.StatusCreated {
+		return false, fmt.Errorf("create organization %s, status %d: %s", org, status, body)
+	}
+	return true, nil
+}
+
+// ensureTeam creates the template team or fixes its permission and units. It returns
+// the team and "created", "updated" or "".
+func (p *Provisioner) ensureTeam(org string, template TeamTemplate, existing []giteaTeam) (giteaTeam, string, error) {
+	units := template.Units
+	if len(units) == 0 {
+		units = DefaultUnits
+	}
+	payload := map[string]interface{}{
+		"name":                      template.Name,
+		"permission":                template.Permission,
+		"units":                     units,
+		"includes_all_repositories": true,
+	}
+
+	for _, gt := range existing {
+		if !strings.EqualFold(gt.Name, template.Name) {
+			continue
+		}
+		if gt.Permission == template.Permission && sameSet(gt.Units, units) {
+			return gt, "", nil
+		}
+		status, body, err := p.request("PATCH", fmt.Sprintf("/api/v1/teams/%d", gt.ID), payload)
+		if err != nil {
+			return gt, "", fmt.Errorf("update team %s/%s: %w", org, template.Name, err)
+		}
+		if status != http.StatusOK {
+			return gt, "", fmt.Errorf("update team %s/%s, status %d: %s", org, template.Name, status, body)
+		}
+		return gt, "updated", nil
+	}
+
+	status, body, err := p.request("POST", fmt.Sprintf("/api/v1/orgs/%s/teams", url.PathEscape(org)), payload)
+	if err != nil {
+		return giteaTeam{}, "", fmt.Errorf("create team %s/%s: %w", org, template.Name, err)
+	}
+	if status != http.StatusCreated {
+		return giteaTeam{}, "", fmt.Errorf("create team %s/%s, status %d: %s", org, template.Name, status, body)
+	}
+	var gt giteaTeam
+	if err := json.Unmarshal(body, &gt); err != nil {
+		return giteaTeam{}, "", fmt.Errorf("parse team %s/%s: %w", org, template.Name, err)
+	}
+	return gt, "created", nil
+}
+
+// syncMembers adds the missing members to a Gitea team and removes the others, except
+// the admin user
+func (p *Provisioner) syncMembers(gt giteaTeam, template TeamTemplate, want []string, created bool, result *Result) error {
+	current := map[string]bool{}
+	if !created {
+		var members []struct {
+			Login string `json:"login"`
+		}
+		if err := p.getJSON(fmt.Sprintf("/api/v1/teams/%d/members?limit=%d", gt.ID, pageSize), &members); err != nil {
+			return fmt.Errorf("list members of team %s: %w", template.Name, err)
+		}
+		for _, m := range members {
+			current[strings.ToLower(m.Login)] = true
+		}
+	}
+
+	wanted := map[string]bool{}
+	for _, login := range want {
+		wanted[strings.ToLower(login)] = true
+		if current[strings.ToLower(login)] {
+			continue
+		}
+		status, body, err := p.request("PUT", fmt.Sprintf("/api/v1/teams/%d/members/%s", gt.ID, url.PathEscape(login)), nil)
+		if err != nil {
+			return fmt.Errorf("add %s to team %s: %w", login, template.Name, err)
+		}
+		switch status {
+		case http.StatusNoContent:
+			result.AddedMembers = append(result.AddedMembers, template.Name+"/"+login)
+		case http.StatusNotFound:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s has no Gitea account and was not added to team %s", login, template.Name))
+		default:
+			return fmt.Errorf("add %s to team %s, status %d: %s", login, template.Name, status, body)
+		}
+	}
+
+	var extra []string
+	for login := range current {
+		if !wanted[login] && login != strings.ToLower(p.username) {
+			extra = append(extra, login)
+		}
+	}
+	sort.Strings(extra)
+	for _, login := range extra {
+		status, body, err := p.request("DELETE", fmt.Sprintf("/api/v1/teams/%d/members/%s", gt.ID, url.PathEscape(login)), nil)
+		if err != nil {
+			return fmt.Errorf("remove %s from team %s: %w", login, template.Name, err)
+		}
+		if status != http.StatusNoContent && status != http.StatusNotFound {
+			return fmt.Errorf("remove %s from team %s, status %d: %s", login, template.Name, status, body)
+		}
+		result.RemovedMembers = append(result.RemovedMembers, template.Name+"/"+login)
+	}
+	return nil
+}
+
+// getJSON decodes the response of a GET request that must succeed
+func (p *Provisioner) getJSON(path string, out interface{}) error {
+	status, body, err := p.request("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("status %d: %s", status, body)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// request calls the Gitea API with the admin credentials
+func (p *Provisioner) request(method, path string, payload interface{}) (int, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = strings.NewReader(string(data))
+	}
+
+	req, err := http.NewRequest(method, p.baseURL+path, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(p.username, p.password)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, body, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSet returns true if a and b hold the same values in any order
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, v := range b {
+		if !contains(a, v) {
+			return false
+		}
+	}
+	return true
+}
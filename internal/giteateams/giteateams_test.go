@@ -0,0 +1,213 @@
+package giteateams
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitea implements the organization and team endpoints of the Gitea API
+type fakeGitea struct {
+	mu      sync.Mutex
+	orgs    map[string]bool
+	teams   map[int64]*fakeTeam
+	users   map[string]bool
+	nextID  int64
+	patched int
+}
+
+type fakeTeam struct {
+	giteaTeam
+	org     string
+	members map[string]bool
+}
+
+func newFakeGitea(users ...string) *fakeGitea {
+	f := &fakeGitea{orgs: map[string]bool{}, teams: map[int64]*fakeTeam{}, users: map[string]bool{"admin": true}}
+	for _, u := range users {
+		f.users[u] = true
+	}
+	return f
+}
+
+func (f *fakeGitea) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/")
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/api/v1/orgs":
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.orgs[body["username"].(string)] = true
+		w.WriteHeader(http.StatusCreated)
+	case parts[0] == "orgs" && len(parts) == 2:
+		if !f.orgs[parts[1]] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	case parts[0] == "orgs" && len(parts) == 3 && r.Method == "GET":
+		teams := []giteaTeam{}
+		for _, t := range f.teams {
+			if t.org == parts[1] {
+				teams = append(teams, t.giteaTeam)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(teams)
+	case parts[0] == "orgs" && len(parts) == 3 && r.Method == "POST":
+		var gt giteaTeam
+		_ = json.NewDecoder(r.Body).Decode(&gt)
+		f.nextID++
+		gt.ID = f.nextID
+		f.teams[gt.ID] = &fakeTeam{giteaTeam: gt, org: parts[1], members: map[string]bool{}}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(gt)
+	case parts[0] == "teams":
+		var id int64
+		_, _ = fmt.Sscan(parts[1], &id)
+		team := f.teams[id]
+		switch {
+		case r.Method == "PATCH":
+			_ = json.NewDecoder(r.Body).Decode(&team.giteaTeam)
+			f.patched++
+			w.WriteHeader(http.StatusOK)
+		case len(parts) == 3:
+			members := []map[string]string{}
+			for login := range team.members {
+				members = append(members, map[string]string{"login": login})
+			}
+			_ = json.NewEncoder(w).Encode(members)
+		case r.Method == "PUT":
+			if !f.users[parts[3]] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			team.members[parts[3]] = true
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "DELETE":
+			delete(team.members, parts[3])
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeGitea) team(org, name string) *fakeTeam {
+	for _, t := range f.teams {
+		if t.org == org && t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func TestSync_CreatesOrganizationAndTeams(t *testing.T) {
+	gitea := newFakeGitea("alice", "bob")
+	server := httptest.NewServer(gitea)
+	defer server.Close()
+
+	config := Config{Enabled: true, OrgPrefix: "team-", Teams: []TeamTemplate{
+		{Name: "developers", Permission: "write"},
+		{Name: "maintainers", Permission: "admin", Roles: []string{"admin"}},
+	}}
+	p := NewProvisioner(config, server.URL, "admin", "secret")
+
+	result := p.Sync(Team{ID: "payments", Name: "Payments", Members: []Member{
+		{Username: "alice", Role: "admin"},
+		{Username: "bob", Role: "user"},
+		{Username: "carol", Role: "user"},
+	}})
+
+	require.Empty(t, result.Error)
+	assert.Equal(t, "team-payments", result.Organization)
+	assert.True(t, result.CreatedOrg)
+	assert.Equal(t, []string{"developers", "maintainers"}, result.CreatedTeams)
+	assert.ElementsMatch(t, []string{"developers/alice", "developers/bob", "maintainers/alice"}, result.AddedMembers)
+	require.Len(t, result.Warnings, 1, "carol has no Gitea account")
+	assert.Contains(t, result.Warnings[0], "carol")
+
+	developers := gitea.team("team-payments", "developers")
+	require.NotNil(t, developers)
+	assert.Equal(t, DefaultUnits, developers.Units)
+	assert.Equal(t, map[string]bool{"alice": true, "bob": true}, developers.members)
+}
+
+func TestSync_AlignsExistingTeams(t *testing.T) {
+	gitea := newFakeGitea("alice", "bob", "dave")
+	server := httptest.NewServer(gitea)
+	defer server.Close()
+
+	p := NewProvisioner(Config{Enabled: true}, server.URL, "admin", "secret")
+	team := Team{ID: "payments", Name: "Payments", Members: []Member{{Username: "alice"}, {Username: "bob"}}}
+	require.Empty(t, p.Sync(team).Error)
+
+	// Gitea drifted: dave was added by hand, the permission was lowered
+	developers := gitea.team("payments", "developers")
+	developers.members["dave"] = true
+	developers.members["admin"] = true
+	developers.Permission = "read"
+	team.Members = team.Members[:1]
+
+	result := p.Sync(team)
+	require.Empty(t, result.Error)
+	assert.False(t, result.CreatedOrg)
+	assert.Empty(t, result.CreatedTeams)
+	assert.Equal(t, []string{"developers"}, result.UpdatedTeams)
+	assert.Equal(t, []string{"developers/bob", "developers/dave"}, result.RemovedMembers)
+	assert.Equal(t, "write", developers.Permission)
+	assert.Equal(t, map[string]bool{"alice": true, "admin": true}, developers.members, "the admin user stays")
+
+	// Nothing to do on the next sync
+	result = p.Sync(team)
+	assert.Empty(t, result.UpdatedTeams)
+	assert.Empty(t, result.AddedMembers)
+	assert.Empty(t, result.RemovedMembers)
+	assert.Equal(t, 1, gitea.patched)
+}
+
+func TestSyncAll_ContinuesAfterFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	p := NewProvisioner(Config{Enabled: true}, server.URL, "admin", "secret")
+	results := p.SyncAll([]Team{{ID: "a"}, {ID: "b"}})
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Contains(t, result.Error, "status 500")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate(), "disabled config is not checked")
+	assert.NoError(t, Config{Enabled: true}.Validate())
+
+	for name, config := range map[string]Config{
+		"visibility":     {Enabled: true, Visibility: "secret"},
+		"orgPrefix":      {Enabled: true, OrgPrefix: "team/"},
+		"syncInterval":   {Enabled: true, SyncInterval: "often"},
+		"missing name":   {Enabled: true, Teams: []TeamTemplate{{Permission: "read"}}},
+		"owners":         {Enabled: true, Teams: []TeamTemplate{{Name: "Owners", Permission: "admin"}}},
+		"duplicate team": {Enabled: true, Teams: []TeamTemplate{{Name: "dev", Permission: "read"}, {Name: "Dev", Permission: "write"}}},
+		"permission":     {Enabled: true, Teams: []TeamTemplate{{Name: "dev", Permission: "owner"}}},
+	} {
+		assert.Error(t, config.Validate(), name)
+	}
+}
+
+func TestConfigOrgName(t *testing.T) {
+	assert.Equal(t, "payments", Config{}.OrgName("payments"))
+	assert.Equal(t, "team-payments", Config{OrgPrefix: "team-"}.OrgName("payments"))
+	assert.Equal(t, "team-data-platform", Config{OrgPrefix: "team-"}.OrgName("data platform"))
+	assert.Equal(t, "x", Config{}.OrgName("..x--"))
+}
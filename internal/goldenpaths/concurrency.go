@@ -0,0 +1,265 @@
+package goldenpaths
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Concurrency puts the runs of a golden path into a concurrency group
+type Concurrency struct {
+	Group string `yaml:"group"`
+	// Environments limits the group to runs for these environments; empty means all runs
+	Environments []string `yaml:"environments"`
+}
+
+// ConcurrencyGroup bounds the parallel runs of all golden paths in the group, across applications
+type ConcurrencyGroup struct {
+	MaxParallel int `yaml:"max_parallel"`
+}
+
+// Run states in a concurrency group
+const (
+	RunQueued  = "queued"
+	RunRunning = "running"
+)
+
+var (
+	// ErrRunCancelled is returned by Wait when a queued run was cancelled
+	ErrRunCancelled = errors.New("run was cancelled while queued")
+	// ErrRunNotQueued is returned by Cancel for runs that already started or do not exist
+	ErrRunNotQueued = errors.New("run is not queued")
+)
+
+// ConcurrencyGroup returns the concurrency group of a run of a golden path in an
+// environment, and the group's limit
+func (c *GoldenPathsConfig) ConcurrencyGroup(pathName, environment string) (string, int, bool) {
+	metadata, exists := c.paths[pathName]
+	if !exists || metadata.Concurrency == nil {
+		return "", 0, false
+	}
+	if len(metadata.Concurrency.Environments) > 0 {
+		matched := false
+		for _, env := range metadata.Concurrency.Environments {
+			if strings.EqualFold(env, environment) {
+				matched = true
+			}
+		}
+		if !matched {
+			return "", 0, false
+		}
+	}
+	group := c.ConcurrencyGroups[metadata.Concurrency.Group]
+	return metadata.Concurrency.Group, group.MaxParallel, true
+}
+
+// validateConcurrency checks that every golden path refers to a declared group with a limit
+func (c *GoldenPathsConfig) validateConcurrency() error {
+	for name, group := range c.ConcurrencyGroups {
+		if group.MaxParallel < 1 {
+			return fmt.Errorf("concurrency group '%s' needs max_parallel of at least 1", name)
+		}
+	}
+	for pathName, metadata := range c.paths {
+		if metadata.Concurrency == nil {
+			continue
+		}
+		if _, exists := c.ConcurrencyGroups[metadata.Concurrency.Group]; !exists {
+			return fmt.Errorf("concurrency group '%s' of golden path '%s' is not declared in concurrency_groups", metadata.Concurrency.Group, pathName)
+		}
+	}
+	return nil
+}
+
+// GroupRun is a golden path run in a concurrency group
+type GroupRun struct {
+	ID          string     `json:"id"`
+	Group       string     `json:"group"`
+	GoldenPath  string     `json:"golden_path"`
+	Application string     `json:"application"`
+	Team        string     `json:"team"`
+	Environment string     `json:"environment,omitempty"`
+	RequestedBy string     `json:"requested_by"`
+	State       string     `json:"state"` // queued or running
+	QueuedAt    time.Time  `json:"queued_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+
+	started chan error // Receives nil when the run may start, or ErrRunCancelled
+}
+
+// GroupStatus is the state of a concurrency group
+type GroupStatus struct {
+	Name        string     `json:"name"`
+	MaxParallel int        `json:"max_parallel"`
+	Active      int        `json:"active"`  // Runs holding a slot
+	Waiting     int        `json:"waiting"` // Runs in the queue
+	Running     []GroupRun `json:"running"`
+	Queued      []GroupRun `json:"queued"` // In start order
+}
+
+// ConcurrencyGroups admits golden path runs to their concurrency groups in arrival order
+type ConcurrencyGroups struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	groups map[string]*groupState
+	runs   map[string]*GroupRun
+	nextID int64
+}
+
+type groupState struct {
+	maxParallel int
+	running     []*GroupRun
+	queued      []*GroupRun
+}
+
+// NewConcurrencyGroups creates an empty set of groups. now is the time source of run timestamps.
+func NewConcurrencyGroups(now func() time.Time) *ConcurrencyGroups {
+	return &ConcurrencyGroups{
+		now:    now,
+		groups: make(map[string]*groupState),
+		runs:   make(map[string]*GroupRun),
+	}
+}
+
+// Enqueue adds a run to the end of its group's queue. The run starts right away if the
+// group has a free slot; otherwise Wait blocks until it does. maxParallel is the
+// group's current limit.
+func (g *ConcurrencyGroups) Enqueue(run GroupRun, maxParallel int) GroupRun {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state, ok := g.groups[run.Group]
+	if !ok {
+		state = &groupState{}
+		g.groups[run.Group] = state
+	}
+	state.maxParallel = maxParallel
+
+	g.nextID++
+	queued := run
+	queued.ID = fmt.Sprintf("%s-%d", run.Group, g.nextID)
+	queued.State = RunQueued
+	queued.QueuedAt = g.now()
+	queued.started = make(chan error, 1)
+	state.queued = append(state.queued, &queued)
+	g.runs[queued.ID] = &queued
+
+	g.admit(state)
+	return queued
+}
+
+// Wait blocks until the run may start. If ctx ends first, the run is cancelled.
+func (g *ConcurrencyGroups) Wait(ctx context.Context, id string) error {
+	g.mu.Lock()
+	run, ok := g.runs[id]
+	g.mu.Unlock()
+	if !ok {
+		return ErrRunNotQueued
+	}
+
+	select {
+	case err := <-run.started:
+		return err
+	case <-ctx.Done():
+		if g.Cancel(id) == nil {
+			return ctx.Err()
+		}
+		// The run started at the same time; it holds a slot until released
+		return <-run.started
+	}
+}
+
+// Release frees the slot of a finished run and starts the next queued runs
+func (g *ConcurrencyGroups) Release(id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	run, ok := g.runs[id]
+	if !ok || run.State != RunRunning {
+		return
+	}
+	delete(g.runs, id)
+	state := g.groups[run.Group]
+	state.running = remove(state.running, run)
+	g.admit(state)
+}
+
+// Cancel removes a queued run; its Wait returns ErrRunCancelled
+func (g *ConcurrencyGroups) Cancel(id string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	run, ok := g.runs[id]
+	if !ok || run.State != RunQueued {
+		return ErrRunNotQueued
+	}
+	delete(g.runs, id)
+	state := g.groups[run.Group]
+	state.queued = remove(state.queued, run)
+	run.started <- ErrRunCancelled
+	return nil
+}
+
+// Get returns a run that is queued or running
+func (g *ConcurrencyGroups) Get(id string) (GroupRun, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	run, ok := g.runs[id]
+	if !ok {
+		return GroupRun{}, false
+	}
+	return *run, true
+}
+
+// Status returns every group with runs, by name
+func (g *ConcurrencyGroups) Status() []GroupStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	statuses := make([]GroupStatus, 0, len(g.groups))
+	for name, state := range g.groups {
+		status := GroupStatus{
+			Name:        name,
+			MaxParallel: state.maxParallel,
+			Active:      len(state.running),
+			Waiting:     len(state.queued),
+			Running:     []GroupRun{},
+			Queued:      []GroupRun{},
+		}
+		for _, run := range state.running {
+			status.Running = append(status.Running, *run)
+		}
+		for _, run := range state.queued {
+			status.Queued = append(status.Queued, *run)
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// admit starts queued runs in order while the group has free slots
+func (g *ConcurrencyGroups) admit(state *groupState) {
+	for len(state.queued) > 0 && len(state.running) < state.maxParallel {
+		run := state.queued[0]
+		state.queued = state.queued[1:]
+		now := g.now()
+		run.State = RunRunning
+		run.StartedAt = &now
+		state.running = append(state.running, run)
+		run.started <- nil
+	}
+}
+
+func remove(runs []*GroupRun, run *GroupRun) []*GroupRun {
+	for i, r := range runs {
+		if r == run {
+			return append(runs[:i:i], runs[i+1:]...)
+		}
+	}
+	return runs
+}
@@ -0,0 +1,135 @@
+package goldenpaths
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGoldenPaths_Concurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		errorMsg string
+	}{
+		{
+			name: "valid group",
+			content: `concurrency_groups:
+  prod-deploys:
+    max_parallel: 2
+goldenpaths:
+  deploy-app:
+    workflow: ./workflows/deploy-app.yaml
+    concurrency:
+      group: prod-deploys
+      environments: [production]
+`,
+		},
+		{
+			name: "undeclared group",
+			content: `goldenpaths:
+  deploy-app:
+    workflow: ./workflows/deploy-app.yaml
+    concurrency:
+      group: prod-deploys
+`,
+			errorMsg: "concurrency group 'prod-deploys' of golden path 'deploy-app' is not declared",
+		},
+		{
+			name: "no limit",
+			content: `concurrency_groups:
+  prod-deploys: {}
+goldenpaths:
+  deploy-app: ./workflows/deploy-app.yaml
+`,
+			errorMsg: "needs max_parallel of at least 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeToTempDir(t)
+			require.NoError(t, os.WriteFile("goldenpaths.yaml", []byte(tt.content), 0600))
+
+			config, err := LoadGoldenPaths()
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			group, maxParallel, ok := config.ConcurrencyGroup("deploy-app", "Production")
+			assert.True(t, ok)
+			assert.Equal(t, "prod-deploys", group)
+			assert.Equal(t, 2, maxParallel)
+
+			_, _, ok = config.ConcurrencyGroup("deploy-app", "staging")
+			assert.False(t, ok, "environment not in the group")
+			_, _, ok = config.ConcurrencyGroup("unknown", "production")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestConcurrencyGroups(t *testing.T) {
+	groups := NewConcurrencyGroups(time.Now)
+	ctx := context.Background()
+
+	first := groups.Enqueue(GroupRun{Group: "prod-deploys", GoldenPath: "deploy-app", Application: "shop"}, 1)
+	second := groups.Enqueue(GroupRun{Group: "prod-deploys", GoldenPath: "deploy-app", Application: "cart"}, 1)
+	third := groups.Enqueue(GroupRun{Group: "prod-deploys", GoldenPath: "deploy-app", Application: "search"}, 1)
+	assert.Equal(t, RunRunning, first.State)
+	assert.Equal(t, RunQueued, second.State)
+	require.NoError(t, groups.Wait(ctx, first.ID))
+
+	status := groups.Status()
+	require.Len(t, status, 1)
+	assert.Equal(t, 1, status[0].MaxParallel)
+	assert.Len(t, status[0].Running, 1)
+	require.Len(t, status[0].Queued, 2)
+	assert.Equal(t, "cart", status[0].Queued[0].Application, "queued in arrival order")
+
+	// Cancelled runs leave the queue; running runs cannot be cancelled
+	assert.ErrorIs(t, groups.Cancel(first.ID), ErrRunNotQueued)
+	require.NoError(t, groups.Cancel(second.ID))
+	assert.ErrorIs(t, groups.Wait(ctx, second.ID), ErrRunNotQueued, "cancelled run is gone")
+
+	waited := make(chan error, 1)
+	go func() { waited <- groups.Wait(ctx, third.ID) }()
+	select {
+	case <-waited:
+		t.Fatal("third run started while the group was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	groups.Release(first.ID)
+	select {
+	case err := <-waited:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("third run did not start after the slot was released")
+	}
+	run, ok := groups.Get(third.ID)
+	require.True(t, ok)
+	assert.Equal(t, RunRunning, run.State)
+	assert.NotNil(t, run.StartedAt)
+}
+
+func TestConcurrencyGroups_WaitCancelledContext(t *testing.T) {
+	groups := NewConcurrencyGroups(time.Now)
+	running := groups.Enqueue(GroupRun{Group: "g"}, 1)
+	queued := groups.Enqueue(GroupRun{Group: "g"}, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, groups.Wait(ctx, queued.ID), context.Canceled)
+	_, ok := groups.Get(queued.ID)
+	assert.False(t, ok, "run left the queue")
+	_, ok = groups.Get(running.ID)
+	assert.True(t, ok)
+}
@@ -30,13 +30,22 @@ type GoldenPathMetadata struct {
 	WorkflowFile      string                      `yaml:"workflow"`
 	Category          string                      `yaml:"category"`
 	EstimatedDuration string                      `yaml:"estimated_duration"`
+	// ApprovalEnvironments lists environments where terraform apply waits for plan approval.
+	// When empty, the admin config's workflowPolicies.security.requireApproval applies.
+	ApprovalEnvironments []string `yaml:"approval_environments"`
+	// Deprecated marks the golden path as deprecated; executions warn and applications
+	// can be migrated to the successor path
+	Deprecated *Deprecation `yaml:"deprecated"`
+	// Concurrency puts runs into a concurrency group whose limit applies across applications
+	Concurrency *Concurrency `yaml:"concurrency"`
 }
 
 // GoldenPathsConfig defines the configuration for available golden paths
 // Supports both simple string format (backward compatible) and metadata format
 type GoldenPathsConfig struct {
-	GoldenPaths map[string]interface{}         `yaml:"goldenpaths"`
-	paths       map[string]*GoldenPathMetadata // Parsed metadata cache
+	GoldenPaths       map[string]interface{}         `yaml:"goldenpaths"`
+	ConcurrencyGroups map[string]ConcurrencyGroup    `yaml:"concurrency_groups"` // Limits of the concurrency groups of golden paths
+	paths             map[string]*GoldenPathMetadata // Parsed metadata cache
 }
 
 // LoadGoldenPaths loads the golden paths configuration from goldenpaths.yaml
@@ -64,6 +73,13 @@ func LoadGoldenPaths() (*GoldenPathsConfig, error) {
 		config.paths[pathName] = metadata
 	}
 
+	if err := config.validateDeprecations(); err != nil {
+		return nil, err
+	}
+	if err := config.validateConcurrency(); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
 
@@ -229,3 +245,25 @@ func (c *GoldenPathsConfig) GetParametersWithDefaults(pathName string, params ma
 
 	return result, nil
 }
+
+// RequiresApproval reports whether executing the golden path in the given environment
+// must pause for plan approval. fallback is used when the golden path does not configure
+// its own approval environments.
+func (c *GoldenPathsConfig) RequiresApproval(pathName, environment string, fallback []string) (bool, error) {
+	metadata, err := c.GetMetadata(pathName)
+	if err != nil {
+		return false, err
+	}
+
+	protected := metadata.ApprovalEnvironments
+	if len(protected) == 0 {
+		protected = fallback
+	}
+
+	for _, env := range protected {
+		if env == environment {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -775,6 +775,45 @@ func TestGoldenPathsConfig_GetParametersWithDefaults(t *testing.T) {
 	}
 }
 
+func TestGoldenPathsConfig_RequiresApproval(t *testing.T) {
+	config := &GoldenPathsConfig{
+		paths: map[string]*GoldenPathMetadata{
+			"protected-path": {
+				ApprovalEnvironments: []string{"production", "staging"},
+			},
+			"default-path": {},
+		},
+	}
+	fallback := []string{"production"}
+
+	tests := []struct {
+		name        string
+		pathName    string
+		environment string
+		expected    bool
+		expectError bool
+	}{
+		{name: "golden path protects staging", pathName: "protected-path", environment: "staging", expected: true},
+		{name: "golden path allows development", pathName: "protected-path", environment: "development", expected: false},
+		{name: "fallback protects production", pathName: "default-path", environment: "production", expected: true},
+		{name: "fallback allows staging", pathName: "default-path", environment: "staging", expected: false},
+		{name: "path does not exist", pathName: "non-existent", environment: "production", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := config.RequiresApproval(tt.pathName, tt.environment, fallback)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func TestGoldenPathsConfig_parsePathMetadata(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,94 @@
+package goldenpaths
+
+import (
+	"fmt"
+	"time"
+)
+
+// sunsetLayout is the date format of Deprecation.Sunset
+const sunsetLayout = "2006-01-02"
+
+// Deprecation marks a golden path as deprecated and describes how its applications move
+// to the successor path
+type Deprecation struct {
+	Message   string `yaml:"message" json:"message,omitempty"`     // Why the path is deprecated
+	Successor string `yaml:"successor" json:"successor,omitempty"` // Golden path that replaces this one
+	Sunset    string `yaml:"sunset" json:"sunset,omitempty"`       // Date (YYYY-MM-DD) after which the path may be removed
+	// ParameterMap renames parameters of this path to the successor's parameters when
+	// applications are migrated. A parameter mapped to "" is dropped; unmapped
+	// parameters are passed on unchanged.
+	ParameterMap map[string]string `yaml:"parameter_map" json:"parameter_map,omitempty"`
+}
+
+// GetDeprecation returns the deprecation of a golden path, or nil if it is not deprecated
+func (c *GoldenPathsConfig) GetDeprecation(pathName string) *Deprecation {
+	metadata, exists := c.paths[pathName]
+	if !exists {
+		return nil
+	}
+	return metadata.Deprecated
+}
+
+// validateDeprecations checks that successors exist and sunset dates parse
+func (c *GoldenPathsConfig) validateDeprecations() error {
+	for pathName, metadata := range c.paths {
+		d := metadata.Deprecated
+		if d == nil {
+			continue
+		}
+		if d.Successor != "" {
+			if d.Successor == pathName {
+				return fmt.Errorf("golden path '%s' cannot be its own successor", pathName)
+			}
+			if _, exists := c.paths[d.Successor]; !exists {
+				return fmt.Errorf("successor '%s' of golden path '%s' not found", d.Successor, pathName)
+			}
+		}
+		if d.Sunset != "" {
+			if _, err := time.Parse(sunsetLayout, d.Sunset); err != nil {
+				return fmt.Errorf("sunset of golden path '%s' must be a date (YYYY-MM-DD): %s", pathName, d.Sunset)
+			}
+		}
+		if len(d.ParameterMap) > 0 && d.Successor == "" {
+			return fmt.Errorf("golden path '%s' has a parameter_map but no successor", pathName)
+		}
+	}
+	return nil
+}
+
+// Warning returns the warning shown when the deprecated golden path is executed
+func (d *Deprecation) Warning(pathName string) string {
+	warning := fmt.Sprintf("golden path '%s' is deprecated", pathName)
+	if d.Sunset != "" {
+		warning += fmt.Sprintf(" and will be removed after %s", d.Sunset)
+	}
+	if d.Successor != "" {
+		warning += fmt.Sprintf("; use '%s' instead", d.Successor)
+	}
+	if d.Message != "" {
+		warning += fmt.Sprintf(": %s", d.Message)
+	}
+	return warning
+}
+
+// MapParameters translates the parameters of the deprecated path to the successor's
+// through the parameter map. A renamed parameter does not override a value the
+// successor's parameter already has.
+func (d *Deprecation) MapParameters(params map[string]string) map[string]string {
+	result := make(map[string]string, len(params))
+	for name, value := range params {
+		if _, mapped := d.ParameterMap[name]; !mapped {
+			result[name] = value
+		}
+	}
+	for name, value := range params {
+		target, mapped := d.ParameterMap[name]
+		if !mapped || target == "" {
+			continue
+		}
+		if _, exists := result[target]; !exists {
+			result[target] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package goldenpaths
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadGoldenPaths_Deprecation(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		errorMsg string
+	}{
+		{
+			name: "valid deprecation",
+			content: `goldenpaths:
+  deploy-app:
+    workflow: ./workflows/deploy-app.yaml
+    deprecated:
+      message: Replaced by the GitOps pipeline
+      successor: deploy-app-v2
+      sunset: "2026-12-31"
+      parameter_map:
+        replicas: replica_count
+  deploy-app-v2:
+    workflow: ./workflows/deploy-app-v2.yaml
+`,
+		},
+		{
+			name: "unknown successor",
+			content: `goldenpaths:
+  deploy-app:
+    workflow: ./workflows/deploy-app.yaml
+    deprecated:
+      successor: deploy-app-v3
+`,
+			errorMsg: "successor 'deploy-app-v3' of golden path 'deploy-app' not found",
+		},
+		{
+			name: "invalid sunset",
+			content: `goldenpaths:
+  deploy-app:
+    workflow: ./workflows/deploy-app.yaml
+    deprecated:
+      sunset: end of year
+`,
+			errorMsg: "must be a date",
+		},
+		{
+			name: "parameter map without successor",
+			content: `goldenpaths:
+  deploy-app:
+    workflow: ./workflows/deploy-app.yaml
+    deprecated:
+      parameter_map:
+        replicas: replica_count
+`,
+			errorMsg: "has a parameter_map but no successor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeToTempDir(t)
+			require.NoError(t, os.WriteFile("goldenpaths.yaml", []byte(tt.content), 0600))
+
+			config, err := LoadGoldenPaths()
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			deprecation := config.GetDeprecation("deploy-app")
+			require.NotNil(t, deprecation)
+			assert.Equal(t, "deploy-app-v2", deprecation.Successor)
+			assert.Nil(t, config.GetDeprecation("deploy-app-v2"))
+			assert.Nil(t, config.GetDeprecation("unknown"))
+		})
+	}
+}
+
+func TestDeprecation_Warning(t *testing.T) {
+	assert.Equal(t, "golden path 'deploy-app' is deprecated", (&Deprecation{}).Warning("deploy-app"))
+
+	d := &Deprecation{Message: "Replaced by the GitOps pipeline", Successor: "deploy-app-v2", Sunset: "2026-12-31"}
+	assert.Equal(t,
+		"golden path 'deploy-app' is deprecated and will be removed after 2026-12-31; use 'deploy-app-v2' instead: Replaced by the GitOps pipeline",
+		d.Warning("deploy-app"))
+}
+
+func TestDeprecation_MapParameters(t *testing.T) {
+	d := &Deprecation{ParameterMap: map[string]string{
+		"replicas":   "replica_count",
+		"legacy_dns": "",
+		"namespace":  "target_namespace",
+	}}
+
+	mapped := d.MapParameters(map[string]string{
+		"replicas":         "3",
+		"legacy_dns":       "true",
+		"environment":      "staging",
+		"namespace":        "old",
+		"target_namespace": "new",
+	})
+
+	assert.Equal(t, map[string]string{
+		"replica_count":    "3",
+		"environment":      "staging",
+		"target_namespace": "new",
+	}, mapped)
+}
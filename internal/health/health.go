@@ -3,7 +3,12 @@ package health
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -27,9 +32,49 @@ type Check struct {
 	Timestamp time.Time     `json:"timestamp"`
 }
 
+// checkJSON is the wire format of Check, with the latency in milliseconds
+type checkJSON struct {
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	Message   string    `json:"message,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Latency   float64   `json:"latency_ms"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// MarshalJSON reports the latency in milliseconds
+func (c Check) MarshalJSON() ([]byte, error) {
+	return json.Marshal(checkJSON{
+		Name:      c.Name,
+		Status:    c.Status,
+		Message:   c.Message,
+		Error:     c.Error,
+		Latency:   float64(c.Latency.Microseconds()) / 1000,
+		Timestamp: c.Timestamp,
+	})
+}
+
+// UnmarshalJSON reads a check written by MarshalJSON
+func (c *Check) UnmarshalJSON(data []byte) error {
+	var wire checkJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	*c = Check{
+		Name:      wire.Name,
+		Status:    wire.Status,
+		Message:   wire.Message,
+		Error:     wire.Error,
+		Latency:   time.Duration(wire.Latency * float64(time.Millisecond)),
+		Timestamp: wire.Timestamp,
+	}
+	return nil
+}
+
 // HealthResponse represents the overall health status
 type HealthResponse struct {
 	Status    Status           `json:"status"`
+	Version   string           `json:"version,omitempty"`
 	Timestamp time.Time        `json:"timestamp"`
 	Uptime    time.Duration    `json:"uptime_seconds"`
 	Checks    map[string]Check `json:"checks"`
@@ -37,9 +82,10 @@ type HealthResponse struct {
 
 // ReadinessResponse represents the readiness status
 type ReadinessResponse struct {
-	Ready     bool      `json:"ready"`
-	Timestamp time.Time `json:"timestamp"`
-	Message   string    `json:"message,omitempty"`
+	Ready     bool             `json:"ready"`
+	Timestamp time.Time        `json:"timestamp"`
+	Message   string           `json:"message,omitempty"`
+	Checks    map[string]Check `json:"checks"`
 }
 
 // Checker defines the interface for health checks
@@ -50,9 +96,11 @@ type Checker interface {
 
 // HealthChecker manages multiple health checks
 type HealthChecker struct {
-	checkers  []Checker
-	startTime time.Time
-	mu        sync.RWMutex
+	checkers          []Checker
+	readinessCheckers []Checker // Only evaluated by IsReady
+	startTime         time.Time
+	version           string
+	mu                sync.RWMutex
 }
 
 // NewHealthChecker creates a new health checker
@@ -70,13 +118,44 @@ func (h *HealthChecker) Register(checker Checker) {
 	h.checkers = append(h.checkers, checker)
 }
 
+// SetVersion sets the server version reported by CheckAll, so clients can detect
+// version skew
+func (h *HealthChecker) SetVersion(version string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.version = version
+}
+
+// RegisterReadiness adds a checker that gates readiness only. Use it for startup
+// dependencies (providers, workers, migrations) that should keep traffic away
+// without failing the liveness endpoint and restarting the process.
+func (h *HealthChecker) RegisterReadiness(checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readinessCheckers = append(h.readinessCheckers, checker)
+}
+
 // CheckAll runs all registered health checks
 func (h *HealthChecker) CheckAll(ctx context.Context) HealthResponse {
 	h.mu.RLock()
 	checkers := make([]Checker, len(h.checkers))
 	copy(checkers, h.checkers)
+	version := h.version
 	h.mu.RUnlock()
 
+	checks, overallStatus := runChecks(ctx, checkers)
+
+	return HealthResponse{
+		Status:    overallStatus,
+		Version:   version,
+		Timestamp: time.Now(),
+		Uptime:    time.Since(h.startTime),
+		Checks:    checks,
+	}
+}
+
+// runChecks runs checkers in parallel and returns their results with the worst status
+func runChecks(ctx context.Context, checkers []Checker) (map[string]Check, Status) {
 	checks := make(map[string]Check)
 	overallStatus := StatusHealthy
 
@@ -111,31 +190,42 @@ func (h *HealthChecker) CheckAll(ctx context.Context) HealthResponse {
 		}
 	}
 
-	return HealthResponse{
-		Status:    overallStatus,
-		Timestamp: time.Now(),
-		Uptime:    time.Since(h.startTime),
-		Checks:    checks,
-	}
+	return checks, overallStatus
 }
 
-// IsReady checks if the service is ready to serve traffic
+// IsReady checks if the service is ready to serve traffic, running the health checks
+// and the readiness-only checks. The result includes every check so probes and
+// operators can see which dependency is holding the service back.
 func (h *HealthChecker) IsReady(ctx context.Context) ReadinessResponse {
-	response := h.CheckAll(ctx)
+	h.mu.RLock()
+	checkers := make([]Checker, 0, len(h.checkers)+len(h.readinessCheckers))
+	checkers = append(checkers, h.checkers...)
+	checkers = append(checkers, h.readinessCheckers...)
+	h.mu.RUnlock()
+
+	checks, status := runChecks(ctx, checkers)
 
 	// Service is ready if all critical checks are healthy
 	// For now, we consider degraded as ready (can still serve traffic)
-	ready := response.Status != StatusUnhealthy
+	ready := status != StatusUnhealthy
 
 	message := "Service is ready"
 	if !ready {
-		message = "Service is not ready - critical dependencies are unhealthy"
+		var notReady []string
+		for name, check := range checks {
+			if check.Status == StatusUnhealthy {
+				notReady = append(notReady, name)
+			}
+		}
+		sort.Strings(notReady)
+		message = "Service is not ready - unhealthy: " + strings.Join(notReady, ", ")
 	}
 
 	return ReadinessResponse{
 		Ready:     ready,
 		Timestamp: time.Now(),
 		Message:   message,
+		Checks:    checks,
 	}
 }
 
@@ -224,3 +314,126 @@ func (c *AlwaysHealthyChecker) Check(ctx context.Context) Check {
 		Latency:   0,
 	}
 }
+
+// FuncChecker adapts a function reporting a status and message to the Checker interface
+type FuncChecker struct {
+	name string
+	fn   func(ctx context.Context) (Status, string)
+}
+
+// NewFuncChecker creates a checker that calls fn on every check
+func NewFuncChecker(name string, fn func(ctx context.Context) (Status, string)) *FuncChecker {
+	return &FuncChecker{name: name, fn: fn}
+}
+
+// Name returns the checker name
+func (c *FuncChecker) Name() string {
+	return c.name
+}
+
+// Check calls the wrapped function. Unhealthy results carry the message as the error.
+func (c *FuncChecker) Check(ctx context.Context) Check {
+	start := time.Now()
+	status, message := c.fn(ctx)
+
+	check := Check{
+		Name:      c.name,
+		Status:    status,
+		Timestamp: start,
+		Latency:   time.Since(start),
+	}
+	if status == StatusUnhealthy {
+		check.Error = message
+	} else {
+		check.Message = message
+	}
+	return check
+}
+
+// HTTPChecker probes a dependency over HTTP. A dependency that is down makes an
+// optional check degraded and a required one unhealthy.
+type HTTPChecker struct {
+	name     string
+	url      string
+	required bool
+	client   *http.Client
+	healthy  func(statusCode int) bool
+}
+
+// NewHTTPChecker creates a checker that expects a 2xx response from url within timeout
+func NewHTTPChecker(name, url string, required bool, timeout time.Duration) *HTTPChecker {
+	return &HTTPChecker{
+		name:     name,
+		url:      url,
+		required: required,
+		client:   &http.Client{Timeout: timeout},
+		healthy:  func(statusCode int) bool { return statusCode >= 200 && statusCode < 300 },
+	}
+}
+
+// WithHealthyStatus replaces the check of the response status code, for endpoints
+// that report health with other codes (e.g. Vault standbys answer 429)
+func (c *HTTPChecker) WithHealthyStatus(healthy func(statusCode int) bool) *HTTPChecker {
+	c.healthy = healthy
+	return c
+}
+
+// Name returns the checker name
+func (c *HTTPChecker) Name() string {
+	return c.name
+}
+
+// Check requests the URL and reports the status code and latency
+func (c *HTTPChecker) Check(ctx context.Context) Check {
+	start := time.Now()
+	check := Check{
+		Name:      c.name,
+		Status:    StatusHealthy,
+		Timestamp: start,
+	}
+
+	statusCode, err := c.get(ctx)
+	check.Latency = time.Since(start)
+	switch {
+	case err != nil:
+		check.Error = fmt.Sprintf("%s unreachable: %v", c.url, err)
+	case !c.healthy(statusCode):
+		check.Error = fmt.Sprintf("%s returned HTTP %d", c.url, statusCode)
+	default:
+		check.Message = fmt.Sprintf("HTTP %d", statusCode)
+		return check
+	}
+
+	check.Status = StatusDegraded
+	if c.required {
+		check.Status = StatusUnhealthy
+	}
+	return check
+}
+
+func (c *HTTPChecker) get(ctx context.Context) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "innominatus-health-check")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, nil
+}
+
+// DefaultIntegrationTimeout bounds each integration check when no timeout is configured
+const DefaultIntegrationTimeout = 5 * time.Second
+
+// Config is the healthChecks section of admin-config.yaml. Integrations are optional
+// unless listed as required: an outage degrades /health instead of failing it.
+type Config struct {
+	Timeout  string   `yaml:"timeout" json:"timeout"`   // Timeout of each integration check (default 5s)
+	Required []string `yaml:"required" json:"required"` // Integrations whose outage makes the server unhealthy
+	Disabled []string `yaml:"disabled" json:"disabled"` // Integrations that are not checked
+}
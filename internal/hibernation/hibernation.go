@@ -0,0 +1,252 @@
+// Package hibernation detects idle applications from their traffic in Prometheus and
+// scales their workloads to zero until they are resumed.
+package hibernation
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultIdleAfter is how long an application must be without traffic before it hibernates
+	DefaultIdleAfter = 72 * time.Hour
+	// DefaultCheckInterval is how often applications are checked for inactivity
+	DefaultCheckInterval = 15 * time.Minute
+	// DefaultActivityQuery counts the requests an application served in the idle window
+	DefaultActivityQuery = `sum(increase(http_requests_total{namespace="$namespace"}[$window]))`
+	// DefaultNamespace is the namespace template of an application's workloads
+	DefaultNamespace = "$app"
+
+	requestTimeout = 30 * time.Second
+)
+
+// workloadKinds are the workloads scaled to zero on hibernation
+var workloadKinds = []string{"deployment", "statefulset"}
+
+// Config is the hibernation section of admin-config.yaml
+type Config struct {
+	Enabled         bool              `yaml:"enabled" json:"enabled"`                 // Hibernate idle applications automatically
+	IdleAfter       string            `yaml:"idleAfter" json:"idleAfter"`             // Time without traffic or deployments (default 72h)
+	CheckInterval   string            `yaml:"checkInterval" json:"checkInterval"`     // How often to look for idle applications (default 15m)
+	Selector        map[string]string `yaml:"selector" json:"selector"`               // metadata.labels an application needs to hibernate automatically
+	ActivityQuery   string            `yaml:"activityQuery" json:"activityQuery"`     // PromQL returning the traffic of $app/$namespace in $window
+	Namespace       string            `yaml:"namespace" json:"namespace"`             // Namespace of an application's workloads (default $app)
+	WebhookTokenEnv string            `yaml:"webhookTokenEnv" json:"webhookTokenEnv"` // Environment variable holding the bearer token of the wake webhook
+}
+
+// Durations returns the idle period and check interval, applying the defaults
+func (c Config) Durations() (idleAfter, checkInterval time.Duration, err error) {
+	idleAfter, checkInterval = DefaultIdleAfter, DefaultCheckInterval
+	if c.IdleAfter != "" {
+		if idleAfter, err = time.ParseDuration(c.IdleAfter); err != nil || idleAfter <= 0 {
+			return 0, 0, fmt.Errorf("invalid hibernation.idleAfter '%s'", c.IdleAfter)
+		}
+	}
+	if c.CheckInterval != "" {
+		if checkInterval, err = time.ParseDuration(c.CheckInterval); err != nil || checkInterval <= 0 {
+			return 0, 0, fmt.Errorf("invalid hibernation.checkInterval '%s'", c.CheckInterval)
+		}
+	}
+	return idleAfter, checkInterval, nil
+}
+
+// Eligible reports whether an application with these labels hibernates automatically.
+// Without a selector no application does, so production is never paused by accident.
+func (c Config) Eligible(labels map[string]string) bool {
+	if len(c.Selector) == 0 {
+		return false
+	}
+	for key, value := range c.Selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// NamespaceOf returns the namespace of an application's workloads
+func (c Config) NamespaceOf(app string) string {
+	template := c.Namespace
+	if template == "" {
+		template = DefaultNamespace
+	}
+	return strings.ReplaceAll(template, "$app", app)
+}
+
+// Query returns the activity query of an application over the idle window
+func (c Config) Query(app string, window time.Duration) string {
+	query := c.ActivityQuery
+	if query == "" {
+		query = DefaultActivityQuery
+	}
+	return strings.NewReplacer(
+		"$namespace", c.NamespaceOf(app),
+		"$window", strconv.Itoa(int(window.Seconds()))+"s",
+		"$app", app,
+	).Replace(query)
+}
+
+// AuthorizeWebhook checks the bearer token of a wake webhook request. The webhook is
+// unavailable when no token is configured.
+func (c Config) AuthorizeWebhook(r *http.Request) bool {
+	if c.WebhookTokenEnv == "" {
+		return false
+	}
+	expected := os.Getenv(c.WebhookTokenEnv)
+	if expected == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// IsIdle reports whether an application had neither traffic nor platform activity (deploys,
+// workflow runs) within idleAfter
+func IsIdle(traffic float64, lastActivity, now time.Time, idleAfter time.Duration) bool {
+	return traffic <= 0 && now.Sub(lastActivity) >= idleAfter
+}
+
+// QueryActivity runs an instant PromQL query and returns the sum of the returned samples.
+// A query without results means no traffic.
+func QueryActivity(ctx context.Context, prometheusURL, query string) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	endpoint := strings.TrimSuffix(prometheusURL, "/") + "/api/v1/query?query=" + url.QueryEscape(query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create prometheus request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var result struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			Result []struct {
+				Value []interface{} `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read prometheus response: %w", err)
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, fmt.Errorf("prometheus returned %s: invalid response", resp.Status)
+	}
+	if result.Status != "success" {
+		return 0, fmt.Errorf("prometheus query failed: %s", result.Error)
+	}
+
+	var total float64
+	for _, sample := range result.Data.Result {
+		if len(sample.Value) != 2 {
+			continue
+		}
+		text, _ := sample.Value[1].(string)
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid sample value %q", text)
+		}
+		total += value
+	}
+	return total, nil
+}
+
+// Scaler scales the workloads of a namespace
+type Scaler interface {
+	// ScaleToZero scales every workload to zero and returns the previous replicas per kind/name
+	ScaleToZero(ctx context.Context, namespace string) (map[string]int, error)
+	// Restore scales workloads back to the recorded replicas
+	Restore(ctx context.Context, namespace string, replicas map[string]int) error
+}
+
+// KubectlScaler scales workloads with kubectl in the server's kube context
+type KubectlScaler struct{}
+
+// ScaleToZero implements Scaler
+func (KubectlScaler) ScaleToZero(ctx context.Context, namespace string) (map[string]int, error) {
+	output, err := kubectl(ctx, "get", strings.Join(workloadKinds, ","), "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Spec struct {
+				Replicas *int `json:"replicas"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse workloads of %s: %w", namespace, err)
+	}
+
+	replicas := make(map[string]int)
+	for _, item := range list.Items {
+		count := 1
+		if item.Spec.Replicas != nil {
+			count = *item.Spec.Replicas
+		}
+		if count == 0 {
+			continue
+		}
+		workload := strings.ToLower(item.Kind) + "/" + item.Metadata.Name
+		if _, err := kubectl(ctx, "scale", workload, "--replicas=0", "-n", namespace); err != nil {
+			return replicas, err
+		}
+		replicas[workload] = count
+	}
+	return replicas, nil
+}
+
+// Restore implements Scaler
+func (KubectlScaler) Restore(ctx context.Context, namespace string, replicas map[string]int) error {
+	workloads := make([]string, 0, len(replicas))
+	for workload := range replicas {
+		workloads = append(workloads, workload)
+	}
+	sort.Strings(workloads)
+
+	for _, workload := range workloads {
+		if _, err := kubectl(ctx, "scale", workload, fmt.Sprintf("--replicas=%d", replicas[workload]), "-n", namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func kubectl(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "kubectl", args...) // #nosec G204 - kubectl with namespace and workload names from the cluster
+	output, err := cmd.Output()
+	if err != nil {
+		var stderr string
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("kubectl %s failed: %v %s", strings.Join(args, " "), err, stderr)
+	}
+	return output, nil
+}
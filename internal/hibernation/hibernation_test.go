@@ -0,0 +1,97 @@
+package hibernation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurations(t *testing.T) {
+	idleAfter, interval, err := Config{}.Durations()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultIdleAfter, idleAfter)
+	assert.Equal(t, DefaultCheckInterval, interval)
+
+	idleAfter, interval, err = Config{IdleAfter: "8h", CheckInterval: "5m"}.Durations()
+	require.NoError(t, err)
+	assert.Equal(t, 8*time.Hour, idleAfter)
+	assert.Equal(t, 5*time.Minute, interval)
+
+	_, _, err = Config{IdleAfter: "a while"}.Durations()
+	assert.ErrorContains(t, err, "invalid hibernation.idleAfter")
+	_, _, err = Config{CheckInterval: "-1m"}.Durations()
+	assert.ErrorContains(t, err, "invalid hibernation.checkInterval")
+}
+
+func TestEligible(t *testing.T) {
+	config := Config{Selector: map[string]string{"environment": "dev"}}
+	assert.True(t, config.Eligible(map[string]string{"environment": "dev", "team": "shop"}))
+	assert.False(t, config.Eligible(map[string]string{"environment": "prod"}))
+	assert.False(t, config.Eligible(nil))
+	assert.False(t, Config{}.Eligible(map[string]string{"environment": "dev"}), "without selector nothing hibernates automatically")
+}
+
+func TestQuery(t *testing.T) {
+	assert.Equal(t, `sum(increase(http_requests_total{namespace="shop"}[259200s]))`, Config{}.Query("shop", DefaultIdleAfter))
+
+	config := Config{
+		Namespace:     "dev-$app",
+		ActivityQuery: `sum(rate(nginx_ingress_controller_requests{exported_namespace="$namespace",service="$app"}[$window]))`,
+	}
+	assert.Equal(t, "dev-shop", config.NamespaceOf("shop"))
+	assert.Equal(t, `sum(rate(nginx_ingress_controller_requests{exported_namespace="dev-shop",service="shop"}[3600s]))`, config.Query("shop", time.Hour))
+}
+
+func TestIsIdle(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	assert.True(t, IsIdle(0, now.Add(-73*time.Hour), now, DefaultIdleAfter))
+	assert.False(t, IsIdle(3, now.Add(-73*time.Hour), now, DefaultIdleAfter), "traffic keeps an application awake")
+	assert.False(t, IsIdle(0, now.Add(-time.Hour), now, DefaultIdleAfter), "a recent deployment keeps an application awake")
+}
+
+func TestAuthorizeWebhook(t *testing.T) {
+	t.Setenv("TEST_WAKE_TOKEN", "s3cret")
+	config := Config{WebhookTokenEnv: "TEST_WAKE_TOKEN"}
+
+	req := httptest.NewRequest("POST", "/api/hibernation/wake/shop", nil)
+	assert.False(t, config.AuthorizeWebhook(req))
+	req.Header.Set("Authorization", "Bearer guess")
+	assert.False(t, config.AuthorizeWebhook(req))
+	req.Header.Set("Authorization", "Bearer s3cret")
+	assert.True(t, config.AuthorizeWebhook(req))
+	assert.False(t, Config{}.AuthorizeWebhook(req), "webhook is disabled without a token")
+}
+
+func TestQueryActivity(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		switch query {
+		case "traffic":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1760529600,"12.5"]},{"metric":{},"value":[1760529600,"0.5"]}]}}`))
+		case "none":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","error":"parse error"}`))
+		}
+	}))
+	defer server.Close()
+
+	traffic, err := QueryActivity(context.Background(), server.URL+"/", "traffic")
+	require.NoError(t, err)
+	assert.Equal(t, 13.0, traffic)
+	assert.Equal(t, "traffic", query)
+
+	traffic, err = QueryActivity(context.Background(), server.URL, "none")
+	require.NoError(t, err)
+	assert.Zero(t, traffic, "no series means no traffic")
+
+	_, err = QueryActivity(context.Background(), server.URL, "invalid{")
+	assert.ErrorContains(t, err, "parse error")
+}
@@ -0,0 +1,272 @@
+// Package licenses reads the software bills of materials (CycloneDX or SPDX JSON) that build
+// and scan steps write, and aggregates the licenses of everything shipped in an application
+// version: declared dependencies and the packages of the container base image.
+package licenses
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SBOM formats
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+)
+
+// Unknown is reported for components whose SBOM entry declares no license
+const Unknown = "UNKNOWN"
+
+// Component is a package found in an SBOM
+type Component struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version,omitempty"`
+	Type     string   `json:"type,omitempty"` // e.g. library, operating-system
+	PURL     string   `json:"purl,omitempty"`
+	Licenses []string `json:"licenses"` // SPDX identifiers or expressions; Unknown when none is declared
+}
+
+// key identifies a component across SBOMs
+func (c Component) key() string {
+	if c.PURL != "" {
+		return c.PURL
+	}
+	return c.Name + "@" + c.Version
+}
+
+// SBOM is the parsed content of a bill of materials
+type SBOM struct {
+	Format     string
+	Image      string // Image the SBOM describes, if it describes one
+	BaseImage  string // Operating system of the image's base layers, e.g. "alpine 3.19.1"
+	Components []Component
+}
+
+// Parse reads a CycloneDX or SPDX JSON document
+func Parse(data []byte) (*SBOM, error) {
+	var probe struct {
+		BOMFormat   string `json:"bomFormat"`
+		SPDXVersion string `json:"spdxVersion"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("SBOM is not valid JSON: %w", err)
+	}
+
+	switch {
+	case probe.BOMFormat == "CycloneDX":
+		return parseCycloneDX(data)
+	case probe.SPDXVersion != "":
+		return parseSPDX(data)
+	default:
+		return nil, fmt.Errorf("unsupported SBOM format (expected CycloneDX or SPDX JSON)")
+	}
+}
+
+func parseCycloneDX(data []byte) (*SBOM, error) {
+	type license struct {
+		License struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	}
+	type component struct {
+		Type     string    `json:"type"`
+		Name     string    `json:"name"`
+		Version  string    `json:"version"`
+		PURL     string    `json:"purl"`
+		Licenses []license `json:"licenses"`
+	}
+	var doc struct {
+		Metadata struct {
+			Component component `json:"component"`
+		} `json:"metadata"`
+		Components []component `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid CycloneDX document: %w", err)
+	}
+
+	sbom := &SBOM{Format: FormatCycloneDX}
+	if subject := doc.Metadata.Component; subject.Type == "container" {
+		sbom.Image = reference(subject.Name, subject.Version)
+	}
+	for _, c := range doc.Components {
+		var declared []string
+		for _, l := range c.Licenses {
+			switch {
+			case l.Expression != "":
+				declared = append(declared, l.Expression)
+			case l.License.ID != "":
+				declared = append(declared, l.License.ID)
+			case l.License.Name != "":
+				declared = append(declared, l.License.Name)
+			}
+		}
+		if c.Type == "operating-system" && sbom.BaseImage == "" {
+			sbom.BaseImage = strings.TrimSpace(c.Name + " " + c.Version)
+		}
+		sbom.Components = append(sbom.Components, newComponent(c.Name, c.Version, c.Type, c.PURL, declared))
+	}
+	return sbom, nil
+}
+
+func parseSPDX(data []byte) (*SBOM, error) {
+	var doc struct {
+		Packages []struct {
+			Name             string `json:"name"`
+			VersionInfo      string `json:"versionInfo"`
+			Purpose          string `json:"primaryPackagePurpose"`
+			LicenseConcluded string `json:"licenseConcluded"`
+			LicenseDeclared  string `json:"licenseDeclared"`
+			ExternalRefs     []struct {
+				Type    string `json:"referenceType"`
+				Locator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid SPDX document: %w", err)
+	}
+
+	sbom := &SBOM{Format: FormatSPDX}
+	for _, p := range doc.Packages {
+		purpose := strings.ToLower(p.Purpose)
+		switch purpose {
+		case "container":
+			if sbom.Image == "" {
+				sbom.Image = reference(p.Name, p.VersionInfo)
+			}
+			continue
+		case "operating-system":
+			if sbom.BaseImage == "" {
+				sbom.BaseImage = strings.TrimSpace(p.Name + " " + p.VersionInfo)
+			}
+		}
+
+		var purl string
+		for _, ref := range p.ExternalRefs {
+			if ref.Type == "purl" {
+				purl = ref.Locator
+				break
+			}
+		}
+		var declared []string
+		for _, l := range []string{p.LicenseConcluded, p.LicenseDeclared} {
+			if l != "" && l != "NOASSERTION" && l != "NONE" {
+				declared = append(declared, l)
+				break
+			}
+		}
+		sbom.Components = append(sbom.Components, newComponent(p.Name, p.VersionInfo, purpose, purl, declared))
+	}
+	return sbom, nil
+}
+
+func newComponent(name, version, kind, purl string, declared []string) Component {
+	if len(declared) == 0 {
+		declared = []string{Unknown}
+	}
+	return Component{Name: name, Version: version, Type: kind, PURL: purl, Licenses: declared}
+}
+
+// reference joins an image name and tag unless the name already carries one
+func reference(name, version string) string {
+	if version == "" || strings.Contains(name[strings.LastIndex(name, "/")+1:], ":") || strings.Contains(name, "@") {
+		return name
+	}
+	return name + ":" + version
+}
+
+// Tag returns the tag of an image reference, or "" for untagged and digest references
+func Tag(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
+
+// Source is one SBOM recorded for an application version
+type Source struct {
+	StepName    string      `json:"step_name"`
+	ExecutionID int64       `json:"execution_id,omitempty"`
+	Format      string      `json:"format"`
+	Image       string      `json:"image,omitempty"`
+	BaseImage   string      `json:"base_image,omitempty"`
+	Components  []Component `json:"-"`
+	RecordedAt  time.Time   `json:"recorded_at"`
+}
+
+// License lists the components distributed under one license
+type License struct {
+	License    string   `json:"license"`
+	Count      int      `json:"count"`
+	Components []string `json:"components"` // name@version
+}
+
+// Report aggregates the licenses of all SBOMs of an application version
+type Report struct {
+	Application string      `json:"application"`
+	Version     string      `json:"version"`
+	Sources     []Source    `json:"sources"`
+	Licenses    []License   `json:"licenses"`
+	Unknown     int         `json:"unknown"` // Components without a declared license
+	Components  []Component `json:"components"`
+}
+
+// Aggregate merges the components of the sources, counting each component once even when
+// several SBOMs list it, and groups them by license, most used first
+func Aggregate(application, version string, sources []Source) *Report {
+	report := &Report{
+		Application: application,
+		Version:     version,
+		Sources:     sources,
+		Licenses:    []License{},
+		Components:  []Component{},
+	}
+
+	seen := make(map[string]bool)
+	byLicense := make(map[string][]string)
+	for _, source := range sources {
+		for _, c := range source.Components {
+			if seen[c.key()] {
+				continue
+			}
+			seen[c.key()] = true
+			report.Components = append(report.Components, c)
+
+			name := c.Name
+			if c.Version != "" {
+				name += "@" + c.Version
+			}
+			for _, l := range c.Licenses {
+				byLicense[l] = append(byLicense[l], name)
+			}
+			if len(c.Licenses) == 1 && c.Licenses[0] == Unknown {
+				report.Unknown++
+			}
+		}
+	}
+
+	for license, components := range byLicense {
+		sort.Strings(components)
+		report.Licenses = append(report.Licenses, License{License: license, Count: len(components), Components: components})
+	}
+	sort.Slice(report.Licenses, func(i, j int) bool {
+		if report.Licenses[i].Count != report.Licenses[j].Count {
+			return report.Licenses[i].Count > report.Licenses[j].Count
+		}
+		return report.Licenses[i].License < report.Licenses[j].License
+	})
+	sort.Slice(report.Components, func(i, j int) bool {
+		return report.Components[i].key() < report.Components[j].key()
+	})
+	return report
+}
@@ -0,0 +1,89 @@
+package licenses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const cycloneDX = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "metadata": {"component": {"type": "container", "name": "registry.example.com/shop", "version": "1.4.2"}},
+  "components": [
+    {"type": "operating-system", "name": "alpine", "version": "3.19.1", "licenses": [{"license": {"name": "GPL-2.0-only"}}]},
+    {"type": "library", "name": "musl", "version": "1.2.4", "purl": "pkg:apk/alpine/musl@1.2.4", "licenses": [{"license": {"id": "MIT"}}]},
+    {"type": "library", "name": "github.com/spf13/cobra", "version": "v1.8.0", "purl": "pkg:golang/github.com/spf13/cobra@v1.8.0", "licenses": [{"expression": "Apache-2.0"}]},
+    {"type": "library", "name": "left-pad", "version": "1.3.0"}
+  ]
+}`
+
+const spdx = `{
+  "spdxVersion": "SPDX-2.3",
+  "name": "shop",
+  "packages": [
+    {"name": "registry.example.com/shop", "versionInfo": "1.4.2", "primaryPackagePurpose": "CONTAINER"},
+    {"name": "musl", "versionInfo": "1.2.4", "licenseConcluded": "NOASSERTION", "licenseDeclared": "MIT",
+     "externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:apk/alpine/musl@1.2.4"}]},
+    {"name": "express", "versionInfo": "4.18.2", "licenseConcluded": "MIT"},
+    {"name": "mystery", "versionInfo": "0.1.0", "licenseConcluded": "NOASSERTION", "licenseDeclared": "NONE"}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	t.Run("cyclonedx", func(t *testing.T) {
+		sbom, err := Parse([]byte(cycloneDX))
+		require.NoError(t, err)
+		assert.Equal(t, FormatCycloneDX, sbom.Format)
+		assert.Equal(t, "registry.example.com/shop:1.4.2", sbom.Image)
+		assert.Equal(t, "alpine 3.19.1", sbom.BaseImage)
+		require.Len(t, sbom.Components, 4)
+		assert.Equal(t, []string{"MIT"}, sbom.Components[1].Licenses)
+		assert.Equal(t, []string{"Apache-2.0"}, sbom.Components[2].Licenses)
+		assert.Equal(t, []string{Unknown}, sbom.Components[3].Licenses)
+	})
+
+	t.Run("spdx", func(t *testing.T) {
+		sbom, err := Parse([]byte(spdx))
+		require.NoError(t, err)
+		assert.Equal(t, FormatSPDX, sbom.Format)
+		assert.Equal(t, "registry.example.com/shop:1.4.2", sbom.Image)
+		require.Len(t, sbom.Components, 3, "the image itself is not a component")
+		assert.Equal(t, "pkg:apk/alpine/musl@1.2.4", sbom.Components[0].PURL)
+		assert.Equal(t, []string{"MIT"}, sbom.Components[0].Licenses, "declared license when none was concluded")
+		assert.Equal(t, []string{Unknown}, sbom.Components[2].Licenses)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := Parse([]byte(`{"packages": []}`))
+		assert.Error(t, err)
+		_, err = Parse([]byte(`not json`))
+		assert.Error(t, err)
+	})
+}
+
+func TestAggregate(t *testing.T) {
+	image, err := Parse([]byte(cycloneDX))
+	require.NoError(t, err)
+	scan, err := Parse([]byte(spdx))
+	require.NoError(t, err)
+
+	report := Aggregate("shop", "1.4.2", []Source{
+		{StepName: "build", Components: image.Components},
+		{StepName: "scan", Components: scan.Components},
+	})
+
+	// musl is listed by both SBOMs with the same purl and counted once
+	assert.Len(t, report.Components, 6)
+	assert.Equal(t, 2, report.Unknown)
+	require.NotEmpty(t, report.Licenses)
+	assert.Equal(t, License{License: "MIT", Count: 2, Components: []string{"express@4.18.2", "musl@1.2.4"}}, report.Licenses[0])
+	assert.Equal(t, Unknown, report.Licenses[1].License)
+}
+
+func TestTag(t *testing.T) {
+	assert.Equal(t, "1.4.2", Tag("registry.example.com:5000/shop:1.4.2"))
+	assert.Equal(t, "", Tag("registry.example.com:5000/shop"))
+	assert.Equal(t, "", Tag("shop@sha256:abc"))
+}
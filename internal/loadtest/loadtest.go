@@ -0,0 +1,450 @@
+// Package loadtest generates synthetic applications and workflow executions against
+// the async workflow queue and reports latency percentiles and queue saturation, so
+// operators can size a deployment before rollout.
+package loadtest
+
+import (
+	"fmt"
+	"innominatus/internal/queue"
+	"innominatus/internal/types"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Limits protect the server from accidental overload
+const (
+	MaxApplications    = 1000
+	MaxWorkflowsPerApp = 100
+	MaxExecutions      = 10000
+	MaxConcurrency     = 100
+	MaxSteps           = 20
+	MaxStepDuration    = time.Minute
+)
+
+// Run states
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusTimedOut  = "timed_out"
+	StatusFailed    = "failed"
+)
+
+// SyntheticWorkflowName is the workflow name recorded for generated executions
+const SyntheticWorkflowName = "loadtest-synthetic"
+
+// Config describes the synthetic workload
+type Config struct {
+	Applications    int    `json:"applications"`
+	WorkflowsPerApp int    `json:"workflows_per_app"`
+	Concurrency     int    `json:"concurrency"`   // Parallel submitters
+	Steps           int    `json:"steps"`         // Synthetic steps per workflow
+	StepDuration    string `json:"step_duration"` // Go duration each step sleeps
+	Timeout         string `json:"timeout"`       // Stop waiting for completions after this
+	Cleanup         bool   `json:"cleanup"`       // Delete generated applications afterwards
+}
+
+// WithDefaults fills unset fields
+func (c Config) WithDefaults() Config {
+	if c.Applications == 0 {
+		c.Applications = 10
+	}
+	if c.WorkflowsPerApp == 0 {
+		c.WorkflowsPerApp = 1
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = 5
+	}
+	if c.Steps == 0 {
+		c.Steps = 3
+	}
+	if c.StepDuration == "" {
+		c.StepDuration = "100ms"
+	}
+	if c.Timeout == "" {
+		c.Timeout = "10m"
+	}
+	return c
+}
+
+// Validate checks the configuration against the server limits
+func (c Config) Validate() error {
+	switch {
+	case c.Applications < 1 || c.Applications > MaxApplications:
+		return fmt.Errorf("applications must be between 1 and %d", MaxApplications)
+	case c.WorkflowsPerApp < 1 || c.WorkflowsPerApp > MaxWorkflowsPerApp:
+		return fmt.Errorf("workflows_per_app must be between 1 and %d", MaxWorkflowsPerApp)
+	case c.Applications*c.WorkflowsPerApp > MaxExecutions:
+		return fmt.Errorf("applications x workflows_per_app must not exceed %d", MaxExecutions)
+	case c.Concurrency < 1 || c.Concurrency > MaxConcurrency:
+		return fmt.Errorf("concurrency must be between 1 and %d", MaxConcurrency)
+	case c.Steps < 1 || c.Steps > MaxSteps:
+		return fmt.Errorf("steps must be between 1 and %d", MaxSteps)
+	}
+
+	stepDuration, err := time.ParseDuration(c.StepDuration)
+	if err != nil || stepDuration < 0 || stepDuration > MaxStepDuration {
+		return fmt.Errorf("step_duration must be a duration between 0 and %s", MaxStepDuration)
+	}
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil || timeout <= 0 {
+		return fmt.Errorf("timeout must be a positive duration")
+	}
+	return nil
+}
+
+// Report is the progress and result of a load test run
+type Report struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	Config      Config     `json:"config"`
+	RequestedBy string     `json:"requested_by"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	Total     int `json:"total"`
+	Submitted int `json:"submitted"`
+	Rejected  int `json:"rejected"` // Enqueue failed (queue full or storage error)
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+
+	ThroughputPerSec float64 `json:"throughput_per_sec"`
+
+	SubmitLatency LatencyStats    `json:"submit_latency"` // Time spent in Enqueue
+	QueueWait     LatencyStats    `json:"queue_wait"`     // Enqueue until a worker picks the task up
+	Execution     LatencyStats    `json:"execution"`      // Workflow execution time
+	EndToEnd      LatencyStats    `json:"end_to_end"`     // Queue wait + execution
+	Queue         SaturationStats `json:"queue"`
+}
+
+// Queue is the subset of the workflow queue used by load tests
+type Queue interface {
+	Enqueue(appName, workflowName string, workflow types.Workflow, metadata map[string]interface{}) (string, error)
+	AddCompletionListener(listener queue.CompletionListener)
+	GetQueueStats() map[string]interface{}
+}
+
+// ApplicationStore registers and removes the generated applications
+type ApplicationStore interface {
+	AddApplication(name string, spec *types.ScoreSpec, team string, createdBy string) error
+	DeleteApplication(name string) error
+}
+
+// Manager runs one load test at a time and keeps the reports of past runs
+type Manager struct {
+	queue          Queue
+	apps           ApplicationStore
+	sampleInterval time.Duration
+
+	mu     sync.Mutex
+	runs   map[string]*run
+	active *run
+}
+
+type run struct {
+	mu         sync.Mutex
+	report     Report
+	submit     []time.Duration
+	queueWait  []time.Duration
+	execution  []time.Duration
+	endToEnd   []time.Duration
+	samples    []QueueSample
+	finished   int
+	doneSignal chan struct{}
+}
+
+// NewManager creates a manager and subscribes it to queue completions
+func NewManager(q Queue, apps ApplicationStore) *Manager {
+	m := &Manager{
+		queue:          q,
+		apps:           apps,
+		sampleInterval: 100 * time.Millisecond,
+		runs:           make(map[string]*run),
+	}
+	q.AddCompletionListener(m.onTaskComplete)
+	return m
+}
+
+// Start validates cfg and launches a run in the background
+func (m *Manager) Start(cfg Config, team, requestedBy string) (*Report, error) {
+	cfg = cfg.WithDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if m.active != nil {
+		activeID := m.active.report.ID
+		m.mu.Unlock()
+		return nil, fmt.Errorf("load test %s is still running", activeID)
+	}
+
+	now := time.Now()
+	r := &run{
+		report: Report{
+			ID:          fmt.Sprintf("lt-%d", now.UnixNano()),
+			Status:      StatusRunning,
+			Config:      cfg,
+			RequestedBy: requestedBy,
+			StartedAt:   now,
+			Total:       cfg.Applications * cfg.WorkflowsPerApp,
+		},
+		doneSignal: make(chan struct{}),
+	}
+	m.runs[r.report.ID] = r
+	m.active = r
+	m.mu.Unlock()
+
+	go m.execute(r, team)
+
+	report := r.snapshot()
+	return &report, nil
+}
+
+// Get returns the current report of a run
+func (m *Manager) Get(id string) (*Report, bool) {
+	m.mu.Lock()
+	r, ok := m.runs[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	report := r.snapshot()
+	return &report, true
+}
+
+// List returns all run reports, newest first
+func (m *Manager) List() []Report {
+	m.mu.Lock()
+	runs := make([]*run, 0, len(m.runs))
+	for _, r := range m.runs {
+		runs = append(runs, r)
+	}
+	m.mu.Unlock()
+
+	reports := make([]Report, 0, len(runs))
+	for _, r := range runs {
+		reports = append(reports, r.snapshot())
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].StartedAt.After(reports[j].StartedAt) })
+	return reports
+}
+
+func (m *Manager) execute(r *run, team string) {
+	cfg := r.report.Config
+	id := r.report.ID
+	timeout, _ := time.ParseDuration(cfg.Timeout)
+
+	defer func() {
+		m.mu.Lock()
+		m.active = nil
+		m.mu.Unlock()
+	}()
+
+	appNames := make([]string, cfg.Applications)
+	for i := range appNames {
+		appNames[i] = fmt.Sprintf("%s-%03d", id, i)
+		spec := &types.ScoreSpec{
+			APIVersion: "score.dev/v1b1",
+			Metadata:   types.Metadata{Name: appNames[i]},
+			Containers: map[string]types.Container{"main": {Image: "innominatus/loadtest:synthetic"}},
+		}
+		if err := m.apps.AddApplication(appNames[i], spec, team, r.report.RequestedBy); err != nil {
+			r.fail(fmt.Sprintf("failed to create synthetic application %s: %v", appNames[i], err))
+			m.cleanup(cfg, appNames[:i])
+			return
+		}
+	}
+
+	stopSampling := make(chan struct{})
+	samplingDone := make(chan struct{})
+	go m.sample(r, stopSampling, samplingDone)
+
+	workflow := syntheticWorkflow(cfg)
+	jobs := make(chan string)
+	var submitters sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		submitters.Add(1)
+		go func() {
+			defer submitters.Done()
+			for appName := range jobs {
+				started := time.Now()
+				_, err := m.queue.Enqueue(appName, SyntheticWorkflowName, workflow, map[string]interface{}{
+					"source":       "loadtest",
+					"loadtest_run": id,
+					"user":         r.report.RequestedBy,
+				})
+				r.recordSubmit(time.Since(started), err)
+			}
+		}()
+	}
+
+	for w := 0; w < cfg.WorkflowsPerApp; w++ {
+		for _, appName := range appNames {
+			jobs <- appName
+		}
+	}
+	close(jobs)
+	submitters.Wait()
+
+	status := StatusCompleted
+	select {
+	case <-r.doneSignal:
+	case <-time.After(timeout):
+		status = StatusTimedOut
+	}
+
+	close(stopSampling)
+	<-samplingDone
+
+	m.cleanup(cfg, appNames)
+	r.finish(status)
+}
+
+// sample records queue depth and worker usage until stop is closed
+func (m *Manager) sample(r *run, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(m.sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			stats := m.queue.GetQueueStats()
+			r.recordSample(QueueSample{
+				Depth:    intStat(stats, "queue_size"),
+				Active:   intStat(stats, "active_tasks"),
+				Workers:  intStat(stats, "workers"),
+				Capacity: intStat(stats, "queue_capacity"),
+			})
+		}
+	}
+}
+
+func (m *Manager) cleanup(cfg Config, appNames []string) {
+	if !cfg.Cleanup {
+		return
+	}
+	for _, name := range appNames {
+		_ = m.apps.DeleteApplication(name)
+	}
+}
+
+// onTaskComplete routes queue completions to the run that submitted them
+func (m *Manager) onTaskComplete(result queue.TaskResult) {
+	id, _ := result.Task.Metadata["loadtest_run"].(string)
+	if id == "" {
+		return
+	}
+
+	m.mu.Lock()
+	r, ok := m.runs[id]
+	m.mu.Unlock()
+	if ok {
+		r.recordCompletion(result)
+	}
+}
+
+// syntheticWorkflow builds a workflow of sleeping steps
+func syntheticWorkflow(cfg Config) types.Workflow {
+	steps := make([]types.Step, cfg.Steps)
+	for i := range steps {
+		steps[i] = types.Step{
+			Name:   fmt.Sprintf("synthetic-%d", i+1),
+			Type:   "synthetic",
+			Config: map[string]interface{}{"duration": cfg.StepDuration},
+		}
+	}
+	return types.Workflow{Steps: steps}
+}
+
+func intStat(stats map[string]interface{}, key string) int {
+	switch v := stats[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	}
+	return 0
+}
+
+func (r *run) recordSubmit(latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.submit = append(r.submit, latency)
+	if err != nil {
+		r.report.Rejected++
+		r.finishedLocked()
+		return
+	}
+	r.report.Submitted++
+}
+
+func (r *run) recordCompletion(result queue.TaskResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queueWait = append(r.queueWait, result.QueueTime)
+	r.execution = append(r.execution, result.ExecutionTime)
+	r.endToEnd = append(r.endToEnd, result.QueueTime+result.ExecutionTime)
+	if result.Err != nil {
+		r.report.Failed++
+	} else {
+		r.report.Succeeded++
+	}
+	r.finishedLocked()
+}
+
+// finishedLocked counts a task as settled and signals once all are
+func (r *run) finishedLocked() {
+	r.finished++
+	if r.finished == r.report.Total {
+		close(r.doneSignal)
+	}
+}
+
+func (r *run) recordSample(sample QueueSample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = append(r.samples, sample)
+}
+
+func (r *run) fail(message string) {
+	r.mu.Lock()
+	r.report.Error = message
+	r.mu.Unlock()
+	r.finish(StatusFailed)
+}
+
+func (r *run) finish(status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.report.Status = status
+	r.report.CompletedAt = &now
+}
+
+// snapshot computes the report from the samples collected so far
+func (r *run) snapshot() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := r.report
+	report.SubmitLatency = ComputeLatency(r.submit)
+	report.QueueWait = ComputeLatency(r.queueWait)
+	report.Execution = ComputeLatency(r.execution)
+	report.EndToEnd = ComputeLatency(r.endToEnd)
+	report.Queue = ComputeSaturation(r.samples)
+
+	end := time.Now()
+	if report.CompletedAt != nil {
+		end = *report.CompletedAt
+	}
+	if elapsed := end.Sub(report.StartedAt).Seconds(); elapsed > 0 {
+		report.ThroughputPerSec = math.Round(float64(report.Succeeded+report.Failed)/elapsed*100) / 100
+	}
+	return report
+}
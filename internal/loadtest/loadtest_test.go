@@ -0,0 +1,215 @@
+package loadtest
+
+import (
+	"errors"
+	"fmt"
+	"innominatus/internal/queue"
+	"innominatus/internal/types"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQueue completes every task asynchronously, failing apps listed in failApps
+type fakeQueue struct {
+	mu        sync.Mutex
+	listeners []queue.CompletionListener
+	enqueued  []string
+	failApps  map[string]bool
+	rejectAll bool
+}
+
+func (q *fakeQueue) Enqueue(appName, workflowName string, workflow types.Workflow, metadata map[string]interface{}) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.rejectAll {
+		return "", errors.New("queue is full, task rejected")
+	}
+	q.enqueued = append(q.enqueued, appName)
+	task := &queue.WorkflowTask{ID: fmt.Sprintf("task-%d", len(q.enqueued)), AppName: appName, WorkflowName: workflowName, Workflow: workflow, Metadata: metadata}
+
+	var err error
+	if q.failApps[appName] {
+		err = errors.New("step failed")
+	}
+	listeners := q.listeners
+	go func() {
+		for _, l := range listeners {
+			l(queue.TaskResult{Task: task, QueueTime: time.Millisecond, ExecutionTime: 5 * time.Millisecond, Err: err})
+		}
+	}()
+	return task.ID, nil
+}
+
+func (q *fakeQueue) AddCompletionListener(listener queue.CompletionListener) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.listeners = append(q.listeners, listener)
+}
+
+func (q *fakeQueue) GetQueueStats() map[string]interface{} {
+	return map[string]interface{}{"queue_size": 0, "active_tasks": 1, "workers": 5, "queue_capacity": 100}
+}
+
+type fakeApps struct {
+	mu      sync.Mutex
+	apps    map[string]string // name -> team
+	deleted []string
+}
+
+func (a *fakeApps) AddApplication(name string, spec *types.ScoreSpec, team string, createdBy string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.apps[name] = team
+	return nil
+}
+
+func (a *fakeApps) DeleteApplication(name string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.apps, name)
+	a.deleted = append(a.deleted, name)
+	return nil
+}
+
+func waitForRun(t *testing.T, m *Manager, id string) *Report {
+	t.Helper()
+	var report *Report
+	require.Eventually(t, func() bool {
+		var ok bool
+		report, ok = m.Get(id)
+		return ok && report.Status != StatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return report
+}
+
+func TestManagerRun(t *testing.T) {
+	q := &fakeQueue{failApps: map[string]bool{}}
+	apps := &fakeApps{apps: map[string]string{}}
+	m := NewManager(q, apps)
+
+	started, err := m.Start(Config{Applications: 4, WorkflowsPerApp: 3, Concurrency: 2, Steps: 2, StepDuration: "0s", Cleanup: true}, "platform", "admin")
+	require.NoError(t, err)
+	assert.Equal(t, 12, started.Total)
+
+	report := waitForRun(t, m, started.ID)
+	assert.Equal(t, StatusCompleted, report.Status)
+	assert.Equal(t, 12, report.Submitted)
+	assert.Equal(t, 12, report.Succeeded)
+	assert.Equal(t, 0, report.Failed)
+	assert.Equal(t, 12, report.EndToEnd.Count)
+	assert.Equal(t, 6.0, report.EndToEnd.P99Ms)
+	assert.Equal(t, 1.0, report.QueueWait.P50Ms)
+	assert.Len(t, q.enqueued, 12)
+
+	// Generated applications are removed when cleanup is requested
+	assert.Empty(t, apps.apps)
+	assert.Len(t, apps.deleted, 4)
+}
+
+func TestManagerCountsFailuresAndRejections(t *testing.T) {
+	t.Run("failed executions", func(t *testing.T) {
+		q := &fakeQueue{failApps: map[string]bool{}}
+		m := NewManager(q, &fakeApps{apps: map[string]string{}})
+
+		started, err := m.Start(Config{Applications: 2, StepDuration: "0s"}, "platform", "admin")
+		require.NoError(t, err)
+		q.mu.Lock()
+		q.failApps[started.ID+"-001"] = true
+		q.mu.Unlock()
+
+		report := waitForRun(t, m, started.ID)
+		assert.Equal(t, report.Total, report.Succeeded+report.Failed)
+	})
+
+	t.Run("rejected submissions", func(t *testing.T) {
+		q := &fakeQueue{rejectAll: true}
+		apps := &fakeApps{apps: map[string]string{}}
+		m := NewManager(q, apps)
+
+		started, err := m.Start(Config{Applications: 3}, "platform", "admin")
+		require.NoError(t, err)
+
+		report := waitForRun(t, m, started.ID)
+		assert.Equal(t, StatusCompleted, report.Status)
+		assert.Equal(t, 3, report.Rejected)
+		assert.Equal(t, 0, report.Submitted)
+		// Without cleanup the generated applications remain for inspection
+		assert.Len(t, apps.apps, 3)
+	})
+}
+
+func TestManagerSingleActiveRun(t *testing.T) {
+	q := &fakeQueue{rejectAll: true}
+	m := NewManager(q, &fakeApps{apps: map[string]string{}})
+	m.active = &run{report: Report{ID: "lt-busy"}}
+
+	_, err := m.Start(Config{}, "platform", "admin")
+	assert.EqualError(t, err, "load test lt-busy is still running")
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{"defaults are valid", Config{}, ""},
+		{"too many applications", Config{Applications: MaxApplications + 1}, "applications must be between"},
+		{"too many executions", Config{Applications: 1000, WorkflowsPerApp: 11}, "must not exceed"},
+		{"too much concurrency", Config{Concurrency: 101}, "concurrency must be between"},
+		{"invalid step duration", Config{StepDuration: "soon"}, "step_duration must be"},
+		{"step duration too long", Config{StepDuration: "2m"}, "step_duration must be"},
+		{"invalid timeout", Config{Timeout: "0s"}, "timeout must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.WithDefaults().Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestComputeLatency(t *testing.T) {
+	samples := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	stats := ComputeLatency(samples)
+	assert.Equal(t, 100, stats.Count)
+	assert.Equal(t, 1.0, stats.MinMs)
+	assert.Equal(t, 50.5, stats.MeanMs)
+	assert.Equal(t, 50.0, stats.P50Ms)
+	assert.Equal(t, 90.0, stats.P90Ms)
+	assert.Equal(t, 95.0, stats.P95Ms)
+	assert.Equal(t, 99.0, stats.P99Ms)
+	assert.Equal(t, 100.0, stats.MaxMs)
+
+	assert.Equal(t, LatencyStats{}, ComputeLatency(nil))
+}
+
+func TestComputeSaturation(t *testing.T) {
+	samples := []QueueSample{
+		{Depth: 0, Active: 2, Workers: 5, Capacity: 100},
+		{Depth: 20, Active: 5, Workers: 5, Capacity: 100},
+		{Depth: 50, Active: 5, Workers: 5, Capacity: 100},
+		{Depth: 10, Active: 4, Workers: 5, Capacity: 100},
+	}
+
+	stats := ComputeSaturation(samples)
+	assert.Equal(t, 4, stats.Samples)
+	assert.Equal(t, 50, stats.MaxDepth)
+	assert.Equal(t, 20.0, stats.AvgDepth)
+	assert.Equal(t, 0.5, stats.PeakBufferUtilization)
+	assert.Equal(t, 50.0, stats.SaturatedPercent)
+}
@@ -0,0 +1,108 @@
+package loadtest
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// LatencyStats summarises a set of durations in milliseconds
+type LatencyStats struct {
+	Count  int     `json:"count"`
+	MinMs  float64 `json:"min_ms"`
+	MeanMs float64 `json:"mean_ms"`
+	P50Ms  float64 `json:"p50_ms"`
+	P90Ms  float64 `json:"p90_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// ComputeLatency returns min, mean, max and nearest-rank percentiles
+func ComputeLatency(samples []time.Duration) LatencyStats {
+	if len(samples) == 0 {
+		return LatencyStats{}
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	return LatencyStats{
+		Count:  len(sorted),
+		MinMs:  toMs(sorted[0]),
+		MeanMs: toMs(total / time.Duration(len(sorted))),
+		P50Ms:  toMs(percentile(sorted, 50)),
+		P90Ms:  toMs(percentile(sorted, 90)),
+		P95Ms:  toMs(percentile(sorted, 95)),
+		P99Ms:  toMs(percentile(sorted, 99)),
+		MaxMs:  toMs(sorted[len(sorted)-1]),
+	}
+}
+
+// percentile uses the nearest-rank method on an ascending slice
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func toMs(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*100) / 100
+}
+
+// QueueSample is a point-in-time observation of the workflow queue
+type QueueSample struct {
+	Depth    int // Tasks buffered and waiting for a worker
+	Active   int // Tasks being executed
+	Workers  int
+	Capacity int // Buffer size; enqueues block once Depth reaches it
+}
+
+// SaturationStats summarises queue samples taken during a run
+type SaturationStats struct {
+	Samples  int     `json:"samples"`
+	Workers  int     `json:"workers"`
+	Capacity int     `json:"capacity"`
+	MaxDepth int     `json:"max_depth"`
+	AvgDepth float64 `json:"avg_depth"`
+	// PeakBufferUtilization is MaxDepth / Capacity (1.0 means enqueues started blocking)
+	PeakBufferUtilization float64 `json:"peak_buffer_utilization"`
+	// SaturatedPercent is the share of samples where every worker was busy and tasks were waiting
+	SaturatedPercent float64 `json:"saturated_percent"`
+}
+
+// ComputeSaturation summarises queue samples
+func ComputeSaturation(samples []QueueSample) SaturationStats {
+	stats := SaturationStats{Samples: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	totalDepth, saturated := 0, 0
+	for _, s := range samples {
+		stats.Workers = s.Workers
+		stats.Capacity = s.Capacity
+		totalDepth += s.Depth
+		if s.Depth > stats.MaxDepth {
+			stats.MaxDepth = s.Depth
+		}
+		if s.Workers > 0 && s.Active >= s.Workers && s.Depth > 0 {
+			saturated++
+		}
+	}
+
+	stats.AvgDepth = math.Round(float64(totalDepth)/float64(len(samples))*100) / 100
+	stats.SaturatedPercent = math.Round(float64(saturated)/float64(len(samples))*10000) / 100
+	if stats.Capacity > 0 {
+		stats.PeakBufferUtilization = math.Round(float64(stats.MaxDepth)/float64(stats.Capacity)*100) / 100
+	}
+	return stats
+}
@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	// currentLevel is the process-wide minimum level. It applies to every logger that
+	// was not given an explicit level with WithLevel, including loggers created earlier.
+	currentLevel atomic.Int32
+
+	formatMu         sync.RWMutex
+	configuredFormat LogFormat
+)
+
+func init() {
+	SetLevel(getLogLevelFromEnv())
+}
+
+// ParseLevel parses a level name (debug, info, warn/warning, error, fatal), case-insensitively
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "DEBUG":
+		return DEBUG, nil
+	case "INFO":
+		return INFO, nil
+	case "WARN", "WARNING":
+		return WARN, nil
+	case "ERROR":
+		return ERROR, nil
+	case "FATAL":
+		return FATAL, nil
+	default:
+		return INFO, fmt.Errorf("unknown log level %q (use debug, info, warn, error or fatal)", name)
+	}
+}
+
+// ParseFormat parses a log format name (json, console, pretty)
+func ParseFormat(name string) (LogFormat, error) {
+	switch format := LogFormat(strings.ToLower(strings.TrimSpace(name))); format {
+	case FormatJSON, FormatConsole, FormatPretty:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown log format %q (use json, console or pretty)", name)
+	}
+}
+
+// SetLevel changes the minimum level of all loggers at runtime, including zerolog's
+// global logger
+func SetLevel(level LogLevel) {
+	currentLevel.Store(int32(level))
+	zerolog.SetGlobalLevel(mapLogLevelToZerolog(level))
+}
+
+// CurrentLevel returns the process-wide minimum level
+func CurrentLevel() LogLevel {
+	return LogLevel(currentLevel.Load())
+}
+
+// CurrentFormat returns the format used for newly created loggers
+func CurrentFormat() LogFormat {
+	return getLogFormatFromEnv()
+}
+
+// Configure applies level and format settings from configuration such as the logging
+// section of admin-config.yaml. The LOG_LEVEL and LOG_FORMAT environment variables take
+// precedence, and empty values keep the defaults. The format only affects loggers
+// created afterwards, so call Configure before creating long-lived loggers.
+func Configure(level, format string) error {
+	if format != "" {
+		parsed, err := ParseFormat(format)
+		if err != nil {
+			return err
+		}
+		formatMu.Lock()
+		configuredFormat = parsed
+		formatMu.Unlock()
+	}
+
+	if level != "" {
+		parsed, err := ParseLevel(level)
+		if err != nil {
+			return err
+		}
+		if os.Getenv("LOG_LEVEL") == "" {
+			SetLevel(parsed)
+		}
+	}
+	return nil
+}
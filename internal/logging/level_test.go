@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// restoreLevel resets the process-wide level and configured format after a test
+func restoreLevel(t *testing.T) {
+	previous := CurrentLevel()
+	t.Cleanup(func() {
+		SetLevel(previous)
+		formatMu.Lock()
+		configuredFormat = ""
+		formatMu.Unlock()
+	})
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    LogLevel
+		wantErr bool
+	}{
+		{"debug", DEBUG, false},
+		{"INFO", INFO, false},
+		{" warning ", WARN, false},
+		{"error", ERROR, false},
+		{"verbose", INFO, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseLevel(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetLevelAppliesToExistingLoggers(t *testing.T) {
+	restoreLevel(t)
+	SetLevel(INFO)
+
+	var legacyOut, structuredOut bytes.Buffer
+	legacy := NewLogger("test").WithOutput(&legacyOut).WithColor(false)
+	structured := NewStructuredLogger("test").WithOutput(&structuredOut)
+
+	legacy.Debug("hidden")
+	structured.Debug("hidden")
+	if legacyOut.Len() != 0 || structuredOut.Len() != 0 {
+		t.Fatalf("debug written at INFO level: %q %q", legacyOut.String(), structuredOut.String())
+	}
+
+	SetLevel(DEBUG)
+	legacy.Debug("visible")
+	structured.Debug("visible")
+	if !strings.Contains(legacyOut.String(), "visible") || !strings.Contains(structuredOut.String(), "visible") {
+		t.Errorf("debug not written after SetLevel(DEBUG): %q %q", legacyOut.String(), structuredOut.String())
+	}
+
+	// An explicit level on a legacy logger wins over the process-wide level
+	legacyOut.Reset()
+	legacy.WithLevel(ERROR).Warn("suppressed")
+	if legacyOut.Len() != 0 {
+		t.Errorf("warn written by logger with explicit ERROR level: %q", legacyOut.String())
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	restoreLevel(t)
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+	SetLevel(INFO)
+
+	if err := Configure("debug", "console"); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if CurrentLevel() != DEBUG {
+		t.Errorf("CurrentLevel() = %v, want DEBUG", CurrentLevel())
+	}
+	if CurrentFormat() != FormatConsole {
+		t.Errorf("CurrentFormat() = %v, want console", CurrentFormat())
+	}
+
+	// Environment variables take precedence over configuration
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_FORMAT", "json")
+	if err := Configure("error", "pretty"); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if CurrentLevel() != DEBUG {
+		t.Errorf("CurrentLevel() = %v, want DEBUG (LOG_LEVEL set)", CurrentLevel())
+	}
+	if CurrentFormat() != FormatJSON {
+		t.Errorf("CurrentFormat() = %v, want json (LOG_FORMAT set)", CurrentFormat())
+	}
+
+	if err := Configure("loud", ""); err == nil {
+		t.Error("Configure() with unknown level should fail")
+	}
+	if err := Configure("", "xml"); err == nil {
+		t.Error("Configure() with unknown format should fail")
+	}
+}
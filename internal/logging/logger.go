@@ -79,6 +79,7 @@ func (l LogLevel) Icon() string {
 type Logger struct {
 	component    string
 	minLevel     LogLevel
+	levelSet     bool // minLevel was set with WithLevel; otherwise CurrentLevel applies
 	output       io.Writer
 	colorEnabled bool
 	fields       map[string]interface{}
@@ -96,9 +97,10 @@ func NewLogger(component string) *Logger {
 	}
 }
 
-// WithLevel sets the minimum log level
+// WithLevel sets the minimum log level, overriding the process-wide level
 func (l *Logger) WithLevel(level LogLevel) *Logger {
 	l.minLevel = level
+	l.levelSet = true
 	return l
 }
 
@@ -134,14 +136,19 @@ func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
 
 // log is the internal logging function
 func (l *Logger) log(level LogLevel, message string, fields map[string]interface{}) {
-	if level < l.minLevel {
+	minLevel := CurrentLevel()
+	if l.levelSet {
+		minLevel = l.minLevel
+	}
+	if level < minLevel {
 		return
 	}
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
+	now := time.Now()
+	timestamp := now.Format("2006-01-02 15:04:05.000")
 
 	// Build the log message
 	var b strings.Builder
@@ -216,6 +223,16 @@ func (l *Logger) log(level LogLevel, message string, fields map[string]interface
 
 	// Write to output
 	_, _ = fmt.Fprint(l.output, b.String())
+
+	if tailing() {
+		publish(Entry{
+			Time:      now,
+			Level:     strings.ToLower(level.String()),
+			Component: l.component,
+			Message:   message,
+			Fields:    allFields,
+		})
+	}
 }
 
 // Debug logs a debug message
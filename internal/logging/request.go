@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	teamKey       contextKey = "team"
+	appKey        contextKey = "app"
+	workflowIDKey contextKey = "workflow_id"
+)
+
+// WithTeam adds the requesting user's team to the context
+func WithTeam(ctx context.Context, team string) context.Context {
+	return context.WithValue(ctx, teamKey, team)
+}
+
+// GetTeam retrieves the team from context
+func GetTeam(ctx context.Context) string {
+	if team, ok := ctx.Value(teamKey).(string); ok {
+		return team
+	}
+	return ""
+}
+
+// WithApp adds the application being operated on to the context
+func WithApp(ctx context.Context, appName string) context.Context {
+	return context.WithValue(ctx, appKey, appName)
+}
+
+// GetApp retrieves the application name from context
+func GetApp(ctx context.Context) string {
+	if appName, ok := ctx.Value(appKey).(string); ok {
+		return appName
+	}
+	return ""
+}
+
+// WithWorkflowID adds a workflow execution ID to the context
+func WithWorkflowID(ctx context.Context, workflowID int64) context.Context {
+	return context.WithValue(ctx, workflowIDKey, workflowID)
+}
+
+// GetWorkflowID retrieves the workflow execution ID from context, or 0 if unset
+func GetWorkflowID(ctx context.Context) int64 {
+	if workflowID, ok := ctx.Value(workflowIDKey).(int64); ok {
+		return workflowID
+	}
+	return 0
+}
+
+// ContextFields returns the request-scoped fields carried by ctx: trace_id, user,
+// team, app and workflow_id. Unset values are omitted.
+func ContextFields(ctx context.Context) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if ctx == nil {
+		return fields
+	}
+	if traceID := GetTraceID(ctx); traceID != "" {
+		fields["trace_id"] = traceID
+	}
+	if user := GetUserID(ctx); user != "" {
+		fields["user"] = user
+	}
+	if team := GetTeam(ctx); team != "" {
+		fields["team"] = team
+	}
+	if appName := GetApp(ctx); appName != "" {
+		fields["app"] = appName
+	}
+	if workflowID := GetWorkflowID(ctx); workflowID != 0 {
+		fields["workflow_id"] = workflowID
+	}
+	return fields
+}
+
+// FromContext returns a structured logger for component that tags every entry with
+// the request-scoped fields of ctx, so log lines of one request or workflow run can
+// be correlated.
+func FromContext(ctx context.Context, component string) *ZerologAdapter {
+	return NewStructuredLogger(component).WithFields(ContextFields(ctx))
+}
+
+// Debugf logs a formatted debug message
+func (z *ZerologAdapter) Debugf(format string, args ...interface{}) {
+	z.Debug(fmt.Sprintf(format, args...))
+}
+
+// Infof logs a formatted info message
+func (z *ZerologAdapter) Infof(format string, args ...interface{}) {
+	z.Info(fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a formatted warning message
+func (z *ZerologAdapter) Warnf(format string, args ...interface{}) {
+	z.Warn(fmt.Sprintf(format, args...))
+}
+
+// Errorf logs a formatted error message
+func (z *ZerologAdapter) Errorf(format string, args ...interface{}) {
+	z.Error(fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestContextFields(t *testing.T) {
+	if fields := ContextFields(context.Background()); len(fields) != 0 {
+		t.Errorf("ContextFields() of empty context = %v, want none", fields)
+	}
+
+	ctx := WithTraceID(context.Background(), "trace-1")
+	ctx = WithUserID(ctx, "alice")
+	ctx = WithTeam(ctx, "ecommerce")
+	ctx = WithApp(ctx, "shop")
+	ctx = WithWorkflowID(ctx, 42)
+
+	want := map[string]interface{}{
+		"trace_id":    "trace-1",
+		"user":        "alice",
+		"team":        "ecommerce",
+		"app":         "shop",
+		"workflow_id": int64(42),
+	}
+	fields := ContextFields(ctx)
+	if len(fields) != len(want) {
+		t.Fatalf("ContextFields() = %v, want %v", fields, want)
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("ContextFields()[%q] = %v, want %v", key, fields[key], value)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "json")
+
+	ctx := WithApp(WithTeam(WithTraceID(context.Background(), "trace-1"), "ecommerce"), "shop")
+	ctx = WithWorkflowID(ctx, 7)
+
+	var buf bytes.Buffer
+	FromContext(ctx, "workflow").WithOutput(&buf).Infof("Step %d/%d completed", 1, 3)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log output is not JSON: %v (%s)", err, buf.String())
+	}
+
+	want := map[string]interface{}{
+		"message":     "Step 1/3 completed",
+		"component":   "workflow",
+		"trace_id":    "trace-1",
+		"team":        "ecommerce",
+		"app":         "shop",
+		"workflow_id": float64(7),
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("entry[%q] = %v, want %v", key, entry[key], value)
+		}
+	}
+	if _, ok := entry["user"]; ok {
+		t.Errorf("unset user should be omitted, got %v", entry["user"])
+	}
+}
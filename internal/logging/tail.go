@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Entry is a log line as delivered to tail subscribers
+type Entry struct {
+	Time      time.Time              `json:"time"`
+	Level     string                 `json:"level"`
+	Component string                 `json:"component,omitempty"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// TailFilter selects the entries a tail subscriber receives
+type TailFilter struct {
+	MinLevel   LogLevel
+	Components []string // Empty matches every component
+}
+
+// Matches reports whether entry passes the filter
+func (f TailFilter) Matches(entry Entry) bool {
+	level, err := ParseLevel(entry.Level)
+	if err == nil && level < f.MinLevel {
+		return false
+	}
+	if len(f.Components) == 0 {
+		return true
+	}
+	for _, component := range f.Components {
+		if strings.EqualFold(component, entry.Component) {
+			return true
+		}
+	}
+	return false
+}
+
+type tailSubscriber struct {
+	filter  TailFilter
+	entries chan Entry
+	dropped atomic.Int64
+}
+
+var (
+	tailMu          sync.RWMutex
+	tailSubscribers = make(map[*tailSubscriber]struct{})
+	tailActive      atomic.Int32
+)
+
+// Subscribe registers a live tail of everything written by the structured loggers from
+// now on. Entries are dropped rather than blocking the logger when the subscriber falls
+// more than buffer entries behind; dropped reports how many were lost so far. Call
+// cancel to stop receiving; it closes the channel.
+func Subscribe(filter TailFilter, buffer int) (entries <-chan Entry, dropped func() int64, cancel func()) {
+	sub := &tailSubscriber{filter: filter, entries: make(chan Entry, buffer)}
+
+	tailMu.Lock()
+	tailSubscribers[sub] = struct{}{}
+	tailActive.Store(int32(len(tailSubscribers)))
+	tailMu.Unlock()
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			tailMu.Lock()
+			delete(tailSubscribers, sub)
+			tailActive.Store(int32(len(tailSubscribers)))
+			close(sub.entries)
+			tailMu.Unlock()
+		})
+	}
+	return sub.entries, sub.dropped.Load, cancel
+}
+
+// tailing reports whether anyone subscribed, so loggers skip building entries otherwise
+func tailing() bool {
+	return tailActive.Load() > 0
+}
+
+// publish hands entry to every matching subscriber without blocking
+func publish(entry Entry) {
+	tailMu.RLock()
+	defer tailMu.RUnlock()
+	for sub := range tailSubscribers {
+		if !sub.filter.Matches(entry) {
+			continue
+		}
+		select {
+		case sub.entries <- entry:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// TeeTail returns a writer that writes zerolog JSON output to w and publishes each event
+// to tail subscribers. Use it for zerolog loggers created outside this package, such as
+// the global github.com/rs/zerolog/log logger.
+func TeeTail(w io.Writer) io.Writer {
+	return io.MultiWriter(w, tailWriter{})
+}
+
+// tailWriter publishes zerolog JSON events; zerolog writes one event per Write call
+type tailWriter struct{}
+
+func (tailWriter) Write(p []byte) (int, error) {
+	if !tailing() {
+		return len(p), nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(p, &fields); err != nil {
+		// Not a zerolog event; nothing to tail
+		return len(p), nil
+	}
+
+	entry := Entry{Level: "info", Time: time.Now()}
+	if level, ok := fields["level"].(string); ok {
+		entry.Level = level
+	}
+	if message, ok := fields["message"].(string); ok {
+		entry.Message = message
+	}
+	if component, ok := fields["component"].(string); ok {
+		entry.Component = component
+	}
+	if ts, ok := fields["time"].(string); ok {
+		if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
+			entry.Time = parsed
+		}
+	}
+	for _, key := range []string{"level", "message", "component", "time"} {
+		delete(fields, key)
+	}
+	if len(fields) > 0 {
+		entry.Fields = fields
+	}
+
+	publish(entry)
+	return len(p), nil
+}
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receiveEntry(t *testing.T, entries <-chan Entry) Entry {
+	t.Helper()
+	select {
+	case entry := <-entries:
+		return entry
+	case <-time.After(time.Second):
+		t.Fatal("no log entry received")
+		return Entry{}
+	}
+}
+
+func TestSubscribe_Logger(t *testing.T) {
+	entries, _, cancel := Subscribe(TailFilter{MinLevel: WARN, Components: []string{"Providers"}}, 10)
+	defer cancel()
+
+	providers := NewLogger("providers").WithOutput(io.Discard).WithLevel(DEBUG)
+	providers.Info("below the filter level")
+	NewLogger("server").WithOutput(io.Discard).Warn("other component")
+	providers.WarnWithFields("Failed to load provider", map[string]interface{}{"provider": "database-team"})
+
+	entry := receiveEntry(t, entries)
+	assert.Equal(t, "warn", entry.Level)
+	assert.Equal(t, "providers", entry.Component)
+	assert.Equal(t, "Failed to load provider", entry.Message)
+	assert.Equal(t, "database-team", entry.Fields["provider"])
+	assert.Empty(t, entries)
+}
+
+func TestSubscribe_ZerologAdapter(t *testing.T) {
+	entries, _, cancel := Subscribe(TailFilter{MinLevel: DEBUG}, 10)
+	defer cancel()
+
+	var out bytes.Buffer
+	NewZerologLogger("engine").WithOutput(&out).ErrorWithFields("Workflow failed", map[string]interface{}{"workflow_id": 42})
+
+	entry := receiveEntry(t, entries)
+	assert.Equal(t, "error", entry.Level)
+	assert.Equal(t, "engine", entry.Component)
+	assert.Equal(t, "Workflow failed", entry.Message)
+	assert.EqualValues(t, 42, entry.Fields["workflow_id"])
+	assert.NotContains(t, entry.Fields, "message")
+	assert.Contains(t, out.String(), "Workflow failed", "the logger's own output is unchanged")
+}
+
+func TestSubscribe_DropsWhenFull(t *testing.T) {
+	entries, dropped, cancel := Subscribe(TailFilter{}, 1)
+
+	logger := NewLogger("server").WithOutput(io.Discard).WithLevel(DEBUG)
+	logger.Info("first")
+	logger.Info("second")
+	logger.Info("third")
+
+	assert.Equal(t, "first", receiveEntry(t, entries).Message)
+	assert.EqualValues(t, 2, dropped())
+
+	cancel()
+	cancel()
+	_, open := <-entries
+	require.False(t, open, "cancel closes the channel")
+}
@@ -31,7 +31,6 @@ type ZerologAdapter struct {
 // NewZerologLogger creates a new zerolog-based logger with configurable format
 func NewZerologLogger(component string) *ZerologAdapter {
 	format := getLogFormatFromEnv()
-	level := getLogLevelFromEnv()
 
 	var writer io.Writer = os.Stdout
 	var zlog zerolog.Logger
@@ -61,8 +60,11 @@ func NewZerologLogger(component string) *ZerologAdapter {
 		zlog = zerolog.New(writer).With().Timestamp().Logger()
 	}
 
-	// Set log level
-	zlog = zlog.Level(mapLogLevelToZerolog(level))
+	// Publish every event to live tails (see Subscribe)
+	zlog = zlog.Output(TeeTail(writer))
+
+	// No per-logger level: the process-wide level (see SetLevel) decides what is written,
+	// so it can be changed at runtime
 
 	// Add component if provided
 	if component != "" {
@@ -73,12 +75,13 @@ func NewZerologLogger(component string) *ZerologAdapter {
 		zlogger:   zlog,
 		component: component,
 		format:    format,
-		minLevel:  level,
+		minLevel:  CurrentLevel(),
 		fields:    make(map[string]interface{}),
 	}
 }
 
-// getLogFormatFromEnv reads LOG_FORMAT environment variable
+// getLogFormatFromEnv reads LOG_FORMAT environment variable, falling back to the
+// format set with Configure
 func getLogFormatFromEnv() LogFormat {
 	format := os.Getenv("LOG_FORMAT")
 	switch strings.ToLower(format) {
@@ -89,6 +92,12 @@ func getLogFormatFromEnv() LogFormat {
 	case "pretty":
 		return FormatPretty
 	default:
+		formatMu.RLock()
+		configured := configuredFormat
+		formatMu.RUnlock()
+		if configured != "" {
+			return configured
+		}
 		// Default to pretty for development, json for production
 		if os.Getenv("ENV") == "production" {
 			return FormatJSON
@@ -134,7 +143,8 @@ func mapLogLevelToZerolog(level LogLevel) zerolog.Level {
 	}
 }
 
-// WithLevel sets the minimum log level
+// WithLevel sets the minimum log level of this logger. It can only raise the level
+// above the process-wide one, which zerolog applies to all loggers.
 func (z *ZerologAdapter) WithLevel(level LogLevel) *ZerologAdapter {
 	z.minLevel = level
 	z.zlogger = z.zlogger.Level(mapLogLevelToZerolog(level))
@@ -143,7 +153,7 @@ func (z *ZerologAdapter) WithLevel(level LogLevel) *ZerologAdapter {
 
 // WithOutput sets the output writer
 func (z *ZerologAdapter) WithOutput(output io.Writer) *ZerologAdapter {
-	z.zlogger = z.zlogger.Output(output)
+	z.zlogger = z.zlogger.Output(TeeTail(output))
 	return z
 }
 
@@ -0,0 +1,212 @@
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Disruptive operation names that must run inside a maintenance window
+const (
+	OperationResize              = "resize"
+	OperationUpgrade             = "upgrade"
+	OperationCertificateRotation = "certificate-rotation"
+)
+
+// disruptiveOperations lists operations gated by maintenance windows
+var disruptiveOperations = map[string]bool{
+	OperationResize:              true,
+	OperationUpgrade:             true,
+	OperationCertificateRotation: true,
+}
+
+// IsDisruptive returns true if the operation must be deferred outside a maintenance window
+func IsDisruptive(operation string) bool {
+	return disruptiveOperations[strings.ToLower(operation)]
+}
+
+// weekdays maps short and long day names to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Window defines a weekly recurring maintenance window for an application
+type Window struct {
+	ID              int64     `json:"id"`
+	ApplicationName string    `json:"application_name"`
+	Name            string    `json:"name"`
+	Days            []string  `json:"days"`             // e.g. ["sat", "sun"]
+	StartTime       string    `json:"start_time"`       // "HH:MM" in Timezone
+	DurationMinutes int       `json:"duration_minutes"` // Length of the window
+	Timezone        string    `json:"timezone"`         // IANA name, defaults to UTC
+	CreatedBy       string    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Validate checks that the window definition is well-formed
+func (w *Window) Validate() error {
+	if w.ApplicationName == "" {
+		return fmt.Errorf("application_name is required")
+	}
+	if len(w.Days) == 0 {
+		return fmt.Errorf("at least one day is required")
+	}
+	for _, day := range w.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid day '%s' (use mon, tue, wed, thu, fri, sat, sun)", day)
+		}
+	}
+	if _, _, err := parseClock(w.StartTime); err != nil {
+		return err
+	}
+	if w.DurationMinutes <= 0 || w.DurationMinutes > 24*60 {
+		return fmt.Errorf("duration_minutes must be between 1 and 1440")
+	}
+	if _, err := w.location(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsOpen reports whether the window is open at the given time
+func (w *Window) IsOpen(now time.Time) bool {
+	start, ok := w.lastStart(now)
+	if !ok {
+		return false
+	}
+	end := start.Add(time.Duration(w.DurationMinutes) * time.Minute)
+	return !now.Before(start) && now.Before(end)
+}
+
+// NextOpening returns the next time the window opens at or after now.
+// If the window is currently open, now is returned.
+func (w *Window) NextOpening(now time.Time) (time.Time, bool) {
+	if w.IsOpen(now) {
+		return now, true
+	}
+
+	loc, err := w.location()
+	if err != nil {
+		return time.Time{}, false
+	}
+	hour, minute, err := parseClock(w.StartTime)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	for offset := 0; offset <= 7; offset++ {
+		day := local.AddDate(0, 0, offset)
+		if !w.runsOn(day.Weekday()) {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+		if !start.Before(now) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// lastStart returns the most recent window start at or before now
+func (w *Window) lastStart(now time.Time) (time.Time, bool) {
+	loc, err := w.location()
+	if err != nil {
+		return time.Time{}, false
+	}
+	hour, minute, err := parseClock(w.StartTime)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	for offset := 0; offset <= 7; offset++ {
+		day := local.AddDate(0, 0, -offset)
+		if !w.runsOn(day.Weekday()) {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+		if !start.After(now) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (w *Window) runsOn(day time.Weekday) bool {
+	for _, d := range w.Days {
+		if wd, ok := weekdays[strings.ToLower(d)]; ok && wd == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Window) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone '%s': %w", w.Timezone, err)
+	}
+	return loc, nil
+}
+
+// parseClock parses "HH:MM" into hour and minute
+func parseClock(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start_time '%s' (expected HH:MM)", value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// Decision is the result of checking an operation against maintenance windows
+type Decision struct {
+	Allowed      bool      `json:"allowed"`
+	ScheduledFor time.Time `json:"scheduled_for,omitempty"`
+	Window       *Window   `json:"window,omitempty"`
+	Reason       string    `json:"reason"`
+}
+
+// Evaluate decides whether a disruptive operation can run now.
+// Applications without windows are never restricted. Otherwise the operation
+// is allowed when any window is open and deferred to the earliest opening.
+func Evaluate(windows []*Window, operation string, now time.Time) Decision {
+	if !IsDisruptive(operation) {
+		return Decision{Allowed: true, Reason: "operation is not disruptive"}
+	}
+	if len(windows) == 0 {
+		return Decision{Allowed: true, Reason: "no maintenance windows defined"}
+	}
+
+	var next *Window
+	var nextAt time.Time
+	for _, window := range windows {
+		if window.IsOpen(now) {
+			return Decision{Allowed: true, Window: window, Reason: fmt.Sprintf("maintenance window '%s' is open", window.Name)}
+		}
+		if at, ok := window.NextOpening(now); ok && (next == nil || at.Before(nextAt)) {
+			next = window
+			nextAt = at
+		}
+	}
+
+	if next == nil {
+		return Decision{Allowed: true, Reason: "no upcoming maintenance window found"}
+	}
+
+	return Decision{
+		Allowed:      false,
+		ScheduledFor: nextAt,
+		Window:       next,
+		Reason:       fmt.Sprintf("deferred until maintenance window '%s' opens", next.Name),
+	}
+}
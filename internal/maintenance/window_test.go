@@ -0,0 +1,127 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func saturdayWindow() *Window {
+	return &Window{
+		ApplicationName: "my-app",
+		Name:            "weekend",
+		Days:            []string{"sat"},
+		StartTime:       "02:00",
+		DurationMinutes: 120,
+		Timezone:        "UTC",
+	}
+}
+
+func TestWindowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(w *Window)
+		wantErr string
+	}{
+		{name: "valid window", modify: func(w *Window) {}},
+		{name: "missing app", modify: func(w *Window) { w.ApplicationName = "" }, wantErr: "application_name"},
+		{name: "no days", modify: func(w *Window) { w.Days = nil }, wantErr: "at least one day"},
+		{name: "invalid day", modify: func(w *Window) { w.Days = []string{"funday"} }, wantErr: "invalid day"},
+		{name: "invalid start", modify: func(w *Window) { w.StartTime = "25:00" }, wantErr: "invalid start_time"},
+		{name: "zero duration", modify: func(w *Window) { w.DurationMinutes = 0 }, wantErr: "duration_minutes"},
+		{name: "invalid timezone", modify: func(w *Window) { w.Timezone = "Mars/Base" }, wantErr: "invalid timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := saturdayWindow()
+			tt.modify(w)
+			err := w.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestWindowIsOpen(t *testing.T) {
+	w := saturdayWindow()
+
+	// 2026-10-17 is a Saturday
+	assert.True(t, w.IsOpen(time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC)))
+	assert.True(t, w.IsOpen(time.Date(2026, 10, 17, 3, 59, 0, 0, time.UTC)))
+	assert.False(t, w.IsOpen(time.Date(2026, 10, 17, 4, 0, 0, 0, time.UTC)))
+	assert.False(t, w.IsOpen(time.Date(2026, 10, 16, 2, 30, 0, 0, time.UTC)))
+}
+
+func TestWindowIsOpenAcrossMidnight(t *testing.T) {
+	w := saturdayWindow()
+	w.StartTime = "23:00"
+
+	assert.True(t, w.IsOpen(time.Date(2026, 10, 18, 0, 30, 0, 0, time.UTC)))
+	assert.False(t, w.IsOpen(time.Date(2026, 10, 18, 1, 0, 0, 0, time.UTC)))
+}
+
+func TestWindowNextOpening(t *testing.T) {
+	w := saturdayWindow()
+
+	next, ok := w.NextOpening(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC), next)
+
+	// After this week's window closed, the next opening is a week later
+	next, ok = w.NextOpening(time.Date(2026, 10, 17, 5, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 10, 24, 2, 0, 0, 0, time.UTC), next)
+}
+
+func TestWindowTimezone(t *testing.T) {
+	w := saturdayWindow()
+	w.Timezone = "Europe/Zurich"
+
+	// 02:00 in Zurich (CEST, UTC+2) is 00:00 UTC
+	assert.True(t, w.IsOpen(time.Date(2026, 10, 17, 0, 30, 0, 0, time.UTC)))
+	assert.False(t, w.IsOpen(time.Date(2026, 10, 17, 2, 30, 0, 0, time.UTC)))
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("non-disruptive operations always allowed", func(t *testing.T) {
+		d := Evaluate([]*Window{saturdayWindow()}, "health-check", now)
+		assert.True(t, d.Allowed)
+	})
+
+	t.Run("no windows means unrestricted", func(t *testing.T) {
+		d := Evaluate(nil, OperationResize, now)
+		assert.True(t, d.Allowed)
+	})
+
+	t.Run("outside window defers to next opening", func(t *testing.T) {
+		d := Evaluate([]*Window{saturdayWindow()}, OperationUpgrade, now)
+		assert.False(t, d.Allowed)
+		assert.Equal(t, time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC), d.ScheduledFor)
+		assert.Equal(t, "weekend", d.Window.Name)
+	})
+
+	t.Run("picks earliest window", func(t *testing.T) {
+		friday := saturdayWindow()
+		friday.Name = "friday-night"
+		friday.Days = []string{"fri"}
+		friday.StartTime = "22:00"
+
+		d := Evaluate([]*Window{saturdayWindow(), friday}, OperationCertificateRotation, now)
+		assert.False(t, d.Allowed)
+		assert.Equal(t, "friday-night", d.Window.Name)
+	})
+
+	t.Run("inside window is allowed", func(t *testing.T) {
+		d := Evaluate([]*Window{saturdayWindow()}, OperationResize, time.Date(2026, 10, 17, 2, 30, 0, 0, time.UTC))
+		assert.True(t, d.Allowed)
+	})
+}
@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// httpDurationBuckets are the upper bounds, in seconds, of the request duration histogram
+var httpDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type httpRouteKey struct {
+	method      string
+	route       string
+	statusClass string
+}
+
+type httpDurationKey struct {
+	method string
+	route  string
+}
+
+// durationHistogram holds non-cumulative bucket counts; Export accumulates them
+type durationHistogram struct {
+	buckets []int64 // one per httpDurationBuckets entry, plus +Inf
+	sum     float64
+	count   int64
+}
+
+// StatusClass groups a status code into its class (2xx, 4xx, ...) so status labels
+// stay bounded
+func StatusClass(statusCode int) string {
+	if statusCode < 100 || statusCode > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
+}
+
+// normalizeMethod maps non-standard methods to a single label value
+func normalizeMethod(method string) string {
+	switch method {
+	case "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS":
+		return method
+	default:
+		return "OTHER"
+	}
+}
+
+// IncHTTPInFlight marks a request to route as started
+func (m *Metrics) IncHTTPInFlight(route string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.httpInFlight == nil {
+		m.httpInFlight = make(map[string]int64)
+	}
+	m.httpInFlight[route]++
+}
+
+// DecHTTPInFlight marks a request to route as finished
+func (m *Metrics) DecHTTPInFlight(route string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.httpInFlight[route] > 0 {
+		m.httpInFlight[route]--
+	}
+}
+
+// ObserveHTTPRequest records a finished request by route template. route must be a
+// template such as /api/workflows/{id}, never a raw path, to keep label cardinality bounded.
+func (m *Metrics) ObserveHTTPRequest(method, route string, statusCode int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.httpRouteRequests == nil {
+		m.httpRouteRequests = make(map[httpRouteKey]int64)
+		m.httpRouteDurations = make(map[httpDurationKey]*durationHistogram)
+	}
+
+	method = normalizeMethod(method)
+	m.httpRouteRequests[httpRouteKey{method: method, route: route, statusClass: StatusClass(statusCode)}]++
+
+	key := httpDurationKey{method: method, route: route}
+	h := m.httpRouteDurations[key]
+	if h == nil {
+		h = &durationHistogram{buckets: make([]int64, len(httpDurationBuckets)+1)}
+		m.httpRouteDurations[key] = h
+	}
+
+	seconds := duration.Seconds()
+	i := sort.SearchFloat64s(httpDurationBuckets, seconds)
+	h.buckets[i]++
+	h.sum += seconds
+	h.count++
+}
+
+// exportHTTPRoutes renders the per-route HTTP metrics. The caller must hold m.mu.
+func (m *Metrics) exportHTTPRoutes() string {
+	var output string
+
+	output += "# HELP innominatus_http_route_requests_total HTTP requests by route template and status class\n"
+	output += "# TYPE innominatus_http_route_requests_total counter\n"
+	routeKeys := make([]httpRouteKey, 0, len(m.httpRouteRequests))
+	for key := range m.httpRouteRequests {
+		routeKeys = append(routeKeys, key)
+	}
+	sort.Slice(routeKeys, func(i, j int) bool {
+		a, b := routeKeys[i], routeKeys[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.statusClass < b.statusClass
+	})
+	for _, key := range routeKeys {
+		output += fmt.Sprintf("innominatus_http_route_requests_total{method=\"%s\",route=\"%s\",status_class=\"%s\"} %d\n",
+			key.method, key.route, key.statusClass, m.httpRouteRequests[key])
+	}
+	output += "\n"
+
+	output += "# HELP innominatus_http_request_duration_seconds HTTP request latency by route template\n"
+	output += "# TYPE innominatus_http_request_duration_seconds histogram\n"
+	durationKeys := make([]httpDurationKey, 0, len(m.httpRouteDurations))
+	for key := range m.httpRouteDurations {
+		durationKeys = append(durationKeys, key)
+	}
+	sort.Slice(durationKeys, func(i, j int) bool {
+		a, b := durationKeys[i], durationKeys[j]
+		if a.route != b.route {
+			return a.route < b.route
+		}
+		return a.method < b.method
+	})
+	for _, key := range durationKeys {
+		h := m.httpRouteDurations[key]
+		labels := fmt.Sprintf("method=\"%s\",route=\"%s\"", key.method, key.route)
+		var cumulative int64
+		for i, bound := range httpDurationBuckets {
+			cumulative += h.buckets[i]
+			output += fmt.Sprintf("innominatus_http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		output += fmt.Sprintf("innominatus_http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		output += fmt.Sprintf("innominatus_http_request_duration_seconds_sum{%s} %g\n", labels, h.sum)
+		output += fmt.Sprintf("innominatus_http_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+	output += "\n"
+
+	output += "# HELP innominatus_http_requests_in_flight HTTP requests currently being served by route template\n"
+	output += "# TYPE innominatus_http_requests_in_flight gauge\n"
+	routes := make([]string, 0, len(m.httpInFlight))
+	for route := range m.httpInFlight {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		output += fmt.Sprintf("innominatus_http_requests_in_flight{route=\"%s\"} %d\n", route, m.httpInFlight[route])
+	}
+	output += "\n"
+
+	return output
+}
@@ -14,12 +14,21 @@ type Metrics struct {
 	httpRequestErrors map[string]int64            // path -> error count
 	startTime         time.Time
 
+	// Per-route HTTP metrics, labeled by route template
+	httpRouteRequests  map[httpRouteKey]int64
+	httpRouteDurations map[httpDurationKey]*durationHistogram
+	httpInFlight       map[string]int64 // route -> requests being served
+
 	// Workflow metrics
 	workflowsExecuted  int64
 	workflowsSucceeded int64
 	workflowsFailed    int64
 	workflowDurations  []time.Duration // For calculating average
 
+	// Workflow queue metrics, set when metrics are scraped
+	workflowQueueDepth  int64 // Tasks waiting for a worker
+	workflowQueueActive int64 // Tasks being executed
+
 	// Database metrics
 	dbQueriesTotal int64
 	dbQueryErrors  int64
@@ -31,6 +40,19 @@ type Metrics struct {
 	resourcesExternalHealthy int64
 	resourcesExternalFailed  int64
 	gitopsWaitDurations      []time.Duration // For calculating average GitOps wait time
+
+	// Step cache metrics
+	stepCacheHits   map[string]int64 // step type -> hits
+	stepCacheMisses map[string]int64 // step type -> misses
+
+	// Provider metrics
+	providerProvisions  map[string]map[string]int64 // provider -> status (success|failure) -> count
+	providerDurations   map[string][]time.Duration  // provider -> last 100 provisioning durations
+	providerLastSuccess map[string]time.Time        // provider -> last successful provision
+
+	// Login metrics
+	loginAttempts map[string]int64 // outcome (success|failure|locked) -> count
+	loginLockouts map[string]int64 // dimension (username|ip) -> count
 }
 
 // Global metrics instance
@@ -39,6 +61,8 @@ var global = &Metrics{
 	httpRequestErrors: make(map[string]int64),
 	startTime:         time.Now(),
 	workflowDurations: make([]time.Duration, 0, 100), // Keep last 100
+	stepCacheHits:     make(map[string]int64),
+	stepCacheMisses:   make(map[string]int64),
 }
 
 // GetGlobal returns the global metrics instance
@@ -51,6 +75,7 @@ func (m *Metrics) RecordHTTPRequest(method, path string, statusCode int) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	method = normalizeMethod(method)
 	if m.httpRequestsTotal[method] == nil {
 		m.httpRequestsTotal[method] = make(map[string]int64)
 	}
@@ -81,6 +106,15 @@ func (m *Metrics) RecordWorkflowExecution(success bool, duration time.Duration)
 	m.workflowDurations = append(m.workflowDurations, duration)
 }
 
+// SetWorkflowQueueDepth records the tasks waiting in the workflow queue and the tasks being executed
+func (m *Metrics) SetWorkflowQueueDepth(queued, active int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.workflowQueueDepth = int64(queued)
+	m.workflowQueueActive = int64(active)
+}
+
 // RecordDBQuery records a database query
 func (m *Metrics) RecordDBQuery(err error) {
 	m.mu.Lock()
@@ -128,6 +162,73 @@ func (m *Metrics) RecordGitOpsWaitDuration(duration time.Duration) {
 	m.gitopsWaitDurations = append(m.gitopsWaitDurations, duration)
 }
 
+// RecordStepCacheLookup records a step cache hit or miss for a step type
+func (m *Metrics) RecordStepCacheLookup(stepType string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stepCacheHits == nil {
+		m.stepCacheHits = make(map[string]int64)
+		m.stepCacheMisses = make(map[string]int64)
+	}
+	if hit {
+		m.stepCacheHits[stepType]++
+	} else {
+		m.stepCacheMisses[stepType]++
+	}
+}
+
+// RecordProviderProvision records the outcome and duration of a provider's provisioning workflow
+func (m *Metrics) RecordProviderProvision(provider string, success bool, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.providerProvisions == nil {
+		m.providerProvisions = make(map[string]map[string]int64)
+		m.providerDurations = make(map[string][]time.Duration)
+		m.providerLastSuccess = make(map[string]time.Time)
+	}
+	if m.providerProvisions[provider] == nil {
+		m.providerProvisions[provider] = make(map[string]int64)
+	}
+
+	status := "failure"
+	if success {
+		status = "success"
+		m.providerLastSuccess[provider] = time.Now()
+	}
+	m.providerProvisions[provider][status]++
+
+	// Keep last 100 durations for average calculation
+	durations := m.providerDurations[provider]
+	if len(durations) >= 100 {
+		durations = durations[1:]
+	}
+	m.providerDurations[provider] = append(durations, duration)
+}
+
+// RecordLoginAttempt records a password login by outcome: success, failure or locked
+func (m *Metrics) RecordLoginAttempt(outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.loginAttempts == nil {
+		m.loginAttempts = make(map[string]int64)
+	}
+	m.loginAttempts[outcome]++
+}
+
+// RecordLoginLockout records a failed login that locked a username or client IP
+func (m *Metrics) RecordLoginLockout(dimension string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.loginLockouts == nil {
+		m.loginLockouts = make(map[string]int64)
+	}
+	m.loginLockouts[dimension]++
+}
+
 // Export exports metrics in Prometheus format
 func (m *Metrics) Export() string {
 	m.mu.RLock()
@@ -168,6 +269,8 @@ func (m *Metrics) Export() string {
 	}
 	output += "\n"
 
+	output += m.exportHTTPRoutes()
+
 	// Workflow metrics
 	output += "# HELP innominatus_workflows_executed_total Total workflow executions\n"
 	output += "# TYPE innominatus_workflows_executed_total counter\n"
@@ -197,6 +300,16 @@ func (m *Metrics) Export() string {
 		output += "\n"
 	}
 
+	output += "# HELP innominatus_workflow_queue_depth Workflow tasks waiting for a queue worker\n"
+	output += "# TYPE innominatus_workflow_queue_depth gauge\n"
+	output += fmt.Sprintf("innominatus_workflow_queue_depth %d\n", m.workflowQueueDepth)
+	output += "\n"
+
+	output += "# HELP innominatus_workflow_queue_active Workflow tasks being executed by queue workers\n"
+	output += "# TYPE innominatus_workflow_queue_active gauge\n"
+	output += fmt.Sprintf("innominatus_workflow_queue_active %d\n", m.workflowQueueActive)
+	output += "\n"
+
 	// Database metrics
 	output += "# HELP innominatus_db_queries_total Total database queries\n"
 	output += "# TYPE innominatus_db_queries_total counter\n"
@@ -239,6 +352,71 @@ func (m *Metrics) Export() string {
 		output += "\n"
 	}
 
+	// Step cache metrics
+	if len(m.stepCacheHits) > 0 || len(m.stepCacheMisses) > 0 {
+		output += "# HELP innominatus_step_cache_hits_total Step results reused from the step cache\n"
+		output += "# TYPE innominatus_step_cache_hits_total counter\n"
+		for stepType, count := range m.stepCacheHits {
+			output += fmt.Sprintf("innominatus_step_cache_hits_total{step_type=\"%s\"} %d\n", stepType, count)
+		}
+		output += "\n"
+
+		output += "# HELP innominatus_step_cache_misses_total Cached steps that had to run\n"
+		output += "# TYPE innominatus_step_cache_misses_total counter\n"
+		for stepType, count := range m.stepCacheMisses {
+			output += fmt.Sprintf("innominatus_step_cache_misses_total{step_type=\"%s\"} %d\n", stepType, count)
+		}
+		output += "\n"
+	}
+
+	// Provider metrics
+	if len(m.providerProvisions) > 0 {
+		output += "# HELP innominatus_provider_provisions_total Provisioning workflows executed per provider\n"
+		output += "# TYPE innominatus_provider_provisions_total counter\n"
+		for provider, statuses := range m.providerProvisions {
+			for status, count := range statuses {
+				output += fmt.Sprintf("innominatus_provider_provisions_total{provider=\"%s\",status=\"%s\"} %d\n", provider, status, count)
+			}
+		}
+		output += "\n"
+
+		output += "# HELP innominatus_provider_provision_duration_seconds_avg Average provisioning duration per provider (last 100)\n"
+		output += "# TYPE innominatus_provider_provision_duration_seconds_avg gauge\n"
+		for provider, durations := range m.providerDurations {
+			var total time.Duration
+			for _, d := range durations {
+				total += d
+			}
+			output += fmt.Sprintf("innominatus_provider_provision_duration_seconds_avg{provider=\"%s\"} %.2f\n", provider, (total / time.Duration(len(durations))).Seconds())
+		}
+		output += "\n"
+
+		output += "# HELP innominatus_provider_last_success_timestamp_seconds Unix time of the last successful provision per provider\n"
+		output += "# TYPE innominatus_provider_last_success_timestamp_seconds gauge\n"
+		for provider, at := range m.providerLastSuccess {
+			output += fmt.Sprintf("innominatus_provider_last_success_timestamp_seconds{provider=\"%s\"} %d\n", provider, at.Unix())
+		}
+		output += "\n"
+	}
+
+	// Login metrics
+	if len(m.loginAttempts) > 0 {
+		output += "# HELP innominatus_login_attempts_total Password logins by outcome\n"
+		output += "# TYPE innominatus_login_attempts_total counter\n"
+		for outcome, count := range m.loginAttempts {
+			output += fmt.Sprintf("innominatus_login_attempts_total{outcome=\"%s\"} %d\n", outcome, count)
+		}
+		output += "\n"
+	}
+	if len(m.loginLockouts) > 0 {
+		output += "# HELP innominatus_login_lockouts_total Failed logins that locked a username or client IP\n"
+		output += "# TYPE innominatus_login_lockouts_total counter\n"
+		for dimension, count := range m.loginLockouts {
+			output += fmt.Sprintf("innominatus_login_lockouts_total{dimension=\"%s\"} %d\n", dimension, count)
+		}
+		output += "\n"
+	}
+
 	// Go runtime metrics
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
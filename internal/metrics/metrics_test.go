@@ -323,6 +323,50 @@ func TestRecordGitOpsWaitDuration(t *testing.T) {
 	}
 }
 
+func TestRecordStepCacheLookup(t *testing.T) {
+	m := &Metrics{
+		httpRequestsTotal: make(map[string]map[string]int64),
+		httpRequestErrors: make(map[string]int64),
+		startTime:         time.Now(),
+	}
+
+	m.RecordStepCacheLookup("terraform-generate", true)
+	m.RecordStepCacheLookup("terraform-generate", true)
+	m.RecordStepCacheLookup("terraform-generate", false)
+
+	output := m.Export()
+	if !strings.Contains(output, `innominatus_step_cache_hits_total{step_type="terraform-generate"} 2`) {
+		t.Error("Export should contain step cache hits")
+	}
+	if !strings.Contains(output, `innominatus_step_cache_misses_total{step_type="terraform-generate"} 1`) {
+		t.Error("Export should contain step cache misses")
+	}
+}
+
+func TestRecordProviderProvision(t *testing.T) {
+	m := &Metrics{
+		httpRequestsTotal: make(map[string]map[string]int64),
+		httpRequestErrors: make(map[string]int64),
+		startTime:         time.Now(),
+	}
+
+	m.RecordProviderProvision("database-team", true, 2*time.Second)
+	m.RecordProviderProvision("database-team", false, 4*time.Second)
+
+	output := m.Export()
+	expected := []string{
+		`innominatus_provider_provisions_total{provider="database-team",status="success"} 1`,
+		`innominatus_provider_provisions_total{provider="database-team",status="failure"} 1`,
+		`innominatus_provider_provision_duration_seconds_avg{provider="database-team"} 3.00`,
+		`innominatus_provider_last_success_timestamp_seconds{provider="database-team"}`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Export should contain %q", line)
+		}
+	}
+}
+
 func TestExport(t *testing.T) {
 	m := &Metrics{
 		httpRequestsTotal:   make(map[string]map[string]int64),
@@ -524,3 +568,88 @@ func TestConcurrentExport(t *testing.T) {
 		<-done
 	}
 }
+
+func TestObserveHTTPRequest(t *testing.T) {
+	m := &Metrics{startTime: time.Now()}
+
+	m.IncHTTPInFlight("/api/workflows/{id}")
+	m.ObserveHTTPRequest("GET", "/api/workflows/{id}", 200, 30*time.Millisecond)
+	m.ObserveHTTPRequest("GET", "/api/workflows/{id}", 503, 2*time.Second)
+	m.ObserveHTTPRequest("BREW", "/api/workflows/{id}", 405, time.Millisecond)
+
+	output := m.exportHTTPRoutes()
+
+	expected := []string{
+		`innominatus_http_route_requests_total{method="GET",route="/api/workflows/{id}",status_class="2xx"} 1`,
+		`innominatus_http_route_requests_total{method="GET",route="/api/workflows/{id}",status_class="5xx"} 1`,
+		`innominatus_http_route_requests_total{method="OTHER",route="/api/workflows/{id}",status_class="4xx"} 1`,
+		`innominatus_http_request_duration_seconds_bucket{method="GET",route="/api/workflows/{id}",le="0.025"} 0`,
+		`innominatus_http_request_duration_seconds_bucket{method="GET",route="/api/workflows/{id}",le="0.05"} 1`,
+		`innominatus_http_request_duration_seconds_bucket{method="GET",route="/api/workflows/{id}",le="2.5"} 2`,
+		`innominatus_http_request_duration_seconds_bucket{method="GET",route="/api/workflows/{id}",le="+Inf"} 2`,
+		`innominatus_http_request_duration_seconds_count{method="GET",route="/api/workflows/{id}"} 2`,
+		`innominatus_http_requests_in_flight{route="/api/workflows/{id}"} 1`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("exportHTTPRoutes() missing %q\n%s", want, output)
+		}
+	}
+
+	m.DecHTTPInFlight("/api/workflows/{id}")
+	m.DecHTTPInFlight("/api/workflows/{id}")
+	if got := m.httpInFlight["/api/workflows/{id}"]; got != 0 {
+		t.Errorf("Expected in-flight = 0, got %d", got)
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := map[int]string{200: "2xx", 204: "2xx", 301: "3xx", 404: "4xx", 500: "5xx", 0: "unknown", 600: "unknown"}
+	for code, want := range tests {
+		if got := StatusClass(code); got != want {
+			t.Errorf("StatusClass(%d) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestRecordLoginAttempt(t *testing.T) {
+	m := &Metrics{
+		httpRequestsTotal: make(map[string]map[string]int64),
+		httpRequestErrors: make(map[string]int64),
+		startTime:         time.Now(),
+	}
+
+	m.RecordLoginAttempt("failure")
+	m.RecordLoginAttempt("failure")
+	m.RecordLoginAttempt("locked")
+	m.RecordLoginLockout("username")
+
+	output := m.Export()
+	if !strings.Contains(output, `innominatus_login_attempts_total{outcome="failure"} 2`) {
+		t.Error("Export should contain failed logins")
+	}
+	if !strings.Contains(output, `innominatus_login_attempts_total{outcome="locked"} 1`) {
+		t.Error("Export should contain locked logins")
+	}
+	if !strings.Contains(output, `innominatus_login_lockouts_total{dimension="username"} 1`) {
+		t.Error("Export should contain lockouts")
+	}
+}
+
+func TestSetWorkflowQueueDepth(t *testing.T) {
+	m := &Metrics{
+		httpRequestsTotal: make(map[string]map[string]int64),
+		httpRequestErrors: make(map[string]int64),
+		startTime:         time.Now(),
+	}
+
+	m.SetWorkflowQueueDepth(7, 3)
+
+	output := m.Export()
+	if !strings.Contains(output, "innominatus_workflow_queue_depth 7") {
+		t.Error("Export should contain the queue depth")
+	}
+	if !strings.Contains(output, "innominatus_workflow_queue_active 3") {
+		t.Error("Export should contain the active queue tasks")
+	}
+}
@@ -1,8 +1,13 @@
 package metrics
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net/http"
+	"os"
 	"runtime"
+	"sync"
 	"time"
 
 	"innominatus/internal/logging"
@@ -12,19 +17,66 @@ import (
 	"github.com/prometheus/client_golang/prometheus/push"
 )
 
+// PushConfig configures pushing to a Prometheus Pushgateway
+type PushConfig struct {
+	URL      string
+	Interval time.Duration
+	Job      string // job label; defaults to "innominatus"
+	Instance string // instance grouping label; empty pushes without one
+
+	// Basic auth
+	Username string
+	Password string
+
+	// TLS
+	CAFile             string // CA bundle for verifying the Pushgateway certificate
+	CertFile           string // Client certificate for mutual TLS
+	KeyFile            string // Client key for mutual TLS
+	InsecureSkipVerify bool
+}
+
+// PushStatus is the outcome of the most recent pushes
+type PushStatus struct {
+	LastAttemptAt       time.Time `json:"last_attempt_at,omitempty"`
+	LastSuccessAt       time.Time `json:"last_success_at,omitempty"`
+	LastError           string    `json:"last_error,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+}
+
 // MetricsPusher pushes metrics to Prometheus Pushgateway
 type MetricsPusher struct {
 	pushgatewayURL string
 	pushInterval   time.Duration
 	jobName        string
+	instance       string
+	username       string
+	password       string
+	httpClient     *http.Client
 	stopChan       chan struct{}
 	metrics        *Metrics
 	registry       *prometheus.Registry
 	buildInfo      *prometheus.GaugeVec
+	statusMu       sync.RWMutex
+	status         PushStatus
 }
 
 // NewMetricsPusher creates a new metrics pusher with runtime collectors
 func NewMetricsPusher(pushgatewayURL string, pushInterval time.Duration, version, commit string) *MetricsPusher {
+	pusher, _ := NewMetricsPusherWithConfig(PushConfig{URL: pushgatewayURL, Interval: pushInterval}, version, commit)
+	return pusher
+}
+
+// NewMetricsPusherWithConfig creates a metrics pusher with job and instance labels,
+// credentials and TLS settings. It fails if the TLS files cannot be loaded.
+func NewMetricsPusherWithConfig(cfg PushConfig, version, commit string) (*MetricsPusher, error) {
+	httpClient, err := cfg.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Job == "" {
+		cfg.Job = "innominatus"
+	}
+
 	// Create a new registry for all collectors
 	registry := prometheus.NewRegistry()
 
@@ -56,14 +108,78 @@ func NewMetricsPusher(pushgatewayURL string, pushInterval time.Duration, version
 	registry.MustRegister(buildInfo)
 
 	return &MetricsPusher{
-		pushgatewayURL: pushgatewayURL,
-		pushInterval:   pushInterval,
-		jobName:        "innominatus",
+		pushgatewayURL: cfg.URL,
+		pushInterval:   cfg.Interval,
+		jobName:        cfg.Job,
+		instance:       cfg.Instance,
+		username:       cfg.Username,
+		password:       cfg.Password,
+		httpClient:     httpClient,
 		stopChan:       make(chan struct{}),
 		metrics:        GetGlobal(),
 		registry:       registry,
 		buildInfo:      buildInfo,
+	}, nil
+}
+
+// httpClient returns the client for pushing, or nil for the default client when no TLS
+// settings are given
+func (c PushConfig) httpClient() (*http.Client, error) {
+	if c.CAFile == "" && c.CertFile == "" && c.KeyFile == "" && !c.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify, // #nosec G402 - explicit opt-in for self-signed test setups
+	}
+	if c.CAFile != "" {
+		ca, err := os.ReadFile(c.CAFile) // #nosec G304 - path is given by the operator
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pushgateway CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("pushgateway CA file %s contains no PEM certificates", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("pushgateway client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load pushgateway client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport, Timeout: 10 * time.Second}, nil
+}
+
+// Status returns the outcome of the most recent pushes
+func (p *MetricsPusher) Status() PushStatus {
+	p.statusMu.RLock()
+	defer p.statusMu.RUnlock()
+	return p.status
+}
+
+// recordPush records the outcome of a push attempt
+func (p *MetricsPusher) recordPush(at time.Time, err error) {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+	p.status.LastAttemptAt = at
+	if err != nil {
+		p.status.LastError = err.Error()
+		p.status.ConsecutiveFailures++
+		return
 	}
+	p.status.LastSuccessAt = at
+	p.status.LastError = ""
+	p.status.ConsecutiveFailures = 0
 }
 
 // StartPushing starts pushing metrics to the Pushgateway in a background goroutine
@@ -73,6 +189,9 @@ func (p *MetricsPusher) StartPushing() {
 	logger.InfoWithFields("Started pushing metrics", map[string]interface{}{
 		"pushgateway_url": p.pushgatewayURL,
 		"interval":        p.pushInterval.String(),
+		"job":             p.jobName,
+		"instance":        p.instance,
+		"basic_auth":      p.username != "",
 	})
 }
 
@@ -91,14 +210,14 @@ func (p *MetricsPusher) pushLoop() {
 	logger := logging.NewLogger("metrics")
 
 	// Push immediately on start
-	if err := p.pushMetrics(); err != nil {
+	if err := p.push(); err != nil {
 		logger.ErrorWithError("Failed to push metrics", err)
 	}
 
 	for {
 		select {
 		case <-ticker.C:
-			if err := p.pushMetrics(); err != nil {
+			if err := p.push(); err != nil {
 				logger.ErrorWithError("Failed to push metrics", err)
 			}
 		case <-p.stopChan:
@@ -107,12 +226,28 @@ func (p *MetricsPusher) pushLoop() {
 	}
 }
 
+// push pushes all metrics and records the outcome for the health check
+func (p *MetricsPusher) push() error {
+	err := p.pushMetrics()
+	p.recordPush(time.Now(), err)
+	return err
+}
+
 // pushMetrics pushes all metrics to the Pushgateway
 func (p *MetricsPusher) pushMetrics() error {
 	p.metrics.mu.RLock()
 	defer p.metrics.mu.RUnlock()
 
 	pusher := push.New(p.pushgatewayURL, p.jobName)
+	if p.instance != "" {
+		pusher.Grouping("instance", p.instance)
+	}
+	if p.username != "" {
+		pusher.BasicAuth(p.username, p.password)
+	}
+	if p.httpClient != nil {
+		pusher.Client(p.httpClient)
+	}
 
 	// Add the registry containing Go runtime and process collectors
 	pusher.Gatherer(p.registry)
@@ -1,6 +1,11 @@
 package metrics
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	dto "github.com/prometheus/client_model/go"
@@ -254,3 +259,121 @@ func TestBuildInfoMetric(t *testing.T) {
 		t.Errorf("expected build info metric value to be 1, got %f", metrics[0].GetGauge().GetValue())
 	}
 }
+
+// TestMetricsPusher_PushConfig tests labels, basic auth and the recorded push status
+func TestMetricsPusher_PushConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       PushConfig
+		responseCode int
+		wantPath     string
+		wantAuth     bool
+		wantFailures int
+	}{
+		{
+			name:         "job and instance labels",
+			config:       PushConfig{Job: "idp", Instance: "innominatus-0"},
+			responseCode: http.StatusOK,
+			wantPath:     "/metrics/job/idp/instance/innominatus-0",
+		},
+		{
+			name:         "default job without instance",
+			config:       PushConfig{},
+			responseCode: http.StatusOK,
+			wantPath:     "/metrics/job/innominatus",
+		},
+		{
+			name:         "basic auth",
+			config:       PushConfig{Username: "pusher", Password: "s3cret"},
+			responseCode: http.StatusOK,
+			wantPath:     "/metrics/job/innominatus",
+			wantAuth:     true,
+		},
+		{
+			name:         "rejected push",
+			config:       PushConfig{},
+			responseCode: http.StatusUnauthorized,
+			wantPath:     "/metrics/job/innominatus",
+			wantFailures: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			var gotAuth bool
+			gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				user, pass, ok := r.BasicAuth()
+				gotAuth = ok && user == "pusher" && pass == "s3cret"
+				w.WriteHeader(tt.responseCode)
+			}))
+			defer gateway.Close()
+
+			tt.config.URL = gateway.URL
+			pusher, err := NewMetricsPusherWithConfig(tt.config, "v1.0.0", "abc123")
+			if err != nil {
+				t.Fatalf("NewMetricsPusherWithConfig() error = %v", err)
+			}
+
+			pushErr := pusher.push()
+			if (pushErr != nil) != (tt.wantFailures > 0) {
+				t.Fatalf("push() error = %v", pushErr)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("expected path %s, got %s", tt.wantPath, gotPath)
+			}
+			if gotAuth != tt.wantAuth {
+				t.Errorf("expected basic auth = %v, got %v", tt.wantAuth, gotAuth)
+			}
+
+			status := pusher.Status()
+			if status.LastAttemptAt.IsZero() {
+				t.Error("expected the push attempt to be recorded")
+			}
+			if status.ConsecutiveFailures != tt.wantFailures {
+				t.Errorf("expected %d consecutive failures, got %d", tt.wantFailures, status.ConsecutiveFailures)
+			}
+			if (status.LastError != "") != (tt.wantFailures > 0) {
+				t.Errorf("unexpected last error %q", status.LastError)
+			}
+		})
+	}
+}
+
+// TestPushConfig_TLS tests that invalid TLS settings are rejected up front
+func TestPushConfig_TLS(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  PushConfig
+		wantErr string
+	}{
+		{"no TLS settings", PushConfig{}, ""},
+		{"skip verify only", PushConfig{InsecureSkipVerify: true}, ""},
+		{"missing CA file", PushConfig{CAFile: filepath.Join(dir, "missing.pem")}, "failed to read pushgateway CA file"},
+		{"CA file without certificates", PushConfig{CAFile: notPEM}, "contains no PEM certificates"},
+		{"certificate without key", PushConfig{CertFile: notPEM}, "certificate and key must be set together"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.URL = "https://pushgateway.example.com"
+			_, err := NewMetricsPusherWithConfig(tt.config, "", "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package naming generates the DNS hostnames and URLs of applications from the naming
+// convention configured by the platform team, e.g. {app}.{team}.{env}.example.com.
+package naming
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultScheme is the scheme of generated URLs
+	DefaultScheme = "https"
+
+	maxLabelLength    = 63
+	maxHostnameLength = 253
+)
+
+// placeholderPattern matches the placeholders of a naming pattern
+var placeholderPattern = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// invalidLabelChars matches everything a DNS label may not contain
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Config is the naming section of admin-config.yaml
+type Config struct {
+	Pattern  string            `yaml:"pattern" json:"pattern"`   // Hostname pattern, e.g. {app}.{team}.{env}.example.com; empty disables naming
+	Clusters map[string]string `yaml:"clusters" json:"clusters"` // Pattern per cluster name, overriding pattern for applications on that cluster
+	Scheme   string            `yaml:"scheme" json:"scheme"`     // Scheme of generated URLs (default https)
+}
+
+// Values are the placeholder values of an application
+type Values struct {
+	App     string `json:"app"`
+	Team    string `json:"team"`
+	Env     string `json:"env"`
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// Enabled reports whether a naming convention is configured
+func (c Config) Enabled() bool {
+	return c.Pattern != "" || len(c.Clusters) > 0
+}
+
+// Validate checks that every pattern only uses known placeholders
+func (c Config) Validate() error {
+	if err := validatePattern("naming.pattern", c.Pattern); err != nil {
+		return err
+	}
+	for cluster, pattern := range c.Clusters {
+		if err := validatePattern(fmt.Sprintf("naming.clusters.%s", cluster), pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePattern(field, pattern string) error {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(pattern, -1) {
+		switch match[1] {
+		case "app", "team", "env", "cluster":
+		default:
+			return fmt.Errorf("%s: unknown placeholder '%s' (use {app}, {team}, {env} or {cluster})", field, match[0])
+		}
+	}
+	return nil
+}
+
+// Hostname generates the hostname of an application. Placeholder values are lowercased
+// and characters a DNS label may not contain become dashes.
+func (c Config) Hostname(values Values) (string, error) {
+	pattern := c.Pattern
+	if clusterPattern, ok := c.Clusters[values.Cluster]; ok && values.Cluster != "" {
+		pattern = clusterPattern
+	}
+	if pattern == "" {
+		return "", fmt.Errorf("no naming pattern is configured")
+	}
+	if err := validatePattern("naming pattern", pattern); err != nil {
+		return "", err
+	}
+
+	var missing []string
+	hostname := placeholderPattern.ReplaceAllStringFunc(pattern, func(placeholder string) string {
+		var value string
+		switch placeholder {
+		case "{app}":
+			value = values.App
+		case "{team}":
+			value = values.Team
+		case "{env}":
+			value = values.Env
+		case "{cluster}":
+			value = values.Cluster
+		}
+		value = sanitizeLabel(value)
+		if value == "" {
+			missing = append(missing, placeholder)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("naming pattern '%s' needs a value for %s", pattern, strings.Join(missing, ", "))
+	}
+
+	hostname = strings.ToLower(hostname)
+	if err := ValidateHostname(hostname); err != nil {
+		return "", err
+	}
+	return hostname, nil
+}
+
+// URL returns the URL of a hostname
+func (c Config) URL(hostname string) string {
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = DefaultScheme
+	}
+	return fmt.Sprintf("%s://%s", scheme, hostname)
+}
+
+// ValidateHostname checks that a hostname is a valid DNS name
+func ValidateHostname(hostname string) error {
+	if hostname == "" {
+		return fmt.Errorf("hostname is required")
+	}
+	if len(hostname) > maxHostnameLength {
+		return fmt.Errorf("hostname '%s' is longer than %d characters", hostname, maxHostnameLength)
+	}
+	for _, label := range strings.Split(hostname, ".") {
+		if label == "" {
+			return fmt.Errorf("hostname '%s' has an empty label", hostname)
+		}
+		if len(label) > maxLabelLength {
+			return fmt.Errorf("label '%s' of hostname '%s' is longer than %d characters", label, hostname, maxLabelLength)
+		}
+		if invalidLabelChars.MatchString(label) || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("label '%s' of hostname '%s' is not a valid DNS label", label, hostname)
+		}
+	}
+	return nil
+}
+
+// sanitizeLabel turns a placeholder value into a DNS label
+func sanitizeLabel(value string) string {
+	value = invalidLabelChars.ReplaceAllString(strings.ToLower(value), "-")
+	return strings.Trim(value, "-")
+}
@@ -0,0 +1,68 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostname(t *testing.T) {
+	config := Config{
+		Pattern:  "{app}.{team}.{env}.example.com",
+		Clusters: map[string]string{"prod-eu": "{app}.{team}.eu.example.com"},
+	}
+
+	tests := []struct {
+		name    string
+		values  Values
+		want    string
+		wantErr string
+	}{
+		{name: "pattern", values: Values{App: "shop", Team: "payments", Env: "staging"}, want: "shop.payments.staging.example.com"},
+		{name: "sanitized values", values: Values{App: "Shop_API", Team: "Team Payments", Env: "dev"}, want: "shop-api.team-payments.dev.example.com"},
+		{name: "cluster override", values: Values{App: "shop", Team: "payments", Env: "production", Cluster: "prod-eu"}, want: "shop.payments.eu.example.com"},
+		{name: "unknown cluster uses pattern", values: Values{App: "shop", Team: "payments", Env: "dev", Cluster: "dev-1"}, want: "shop.payments.dev.example.com"},
+		{name: "missing value", values: Values{App: "shop", Team: "payments"}, wantErr: "needs a value for {env}"},
+		{name: "label too long", values: Values{App: strings.Repeat("a", 64), Team: "payments", Env: "dev"}, wantErr: "longer than 63"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostname, err := config.Hostname(tt.values)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, hostname)
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{Pattern: "{app}.{cluster}.example.com"}.Validate())
+
+	err := Config{Pattern: "{app}.{region}.example.com"}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown placeholder '{region}'")
+
+	err = Config{Clusters: map[string]string{"prod": "{service}.example.com"}}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "naming.clusters.prod")
+}
+
+func TestURL(t *testing.T) {
+	assert.Equal(t, "https://shop.example.com", Config{}.URL("shop.example.com"))
+	assert.Equal(t, "http://shop.localtest.me", Config{Scheme: "http"}.URL("shop.localtest.me"))
+}
+
+func TestValidateHostname(t *testing.T) {
+	assert.NoError(t, ValidateHostname("shop.payments.example.com"))
+	assert.Error(t, ValidateHostname(""))
+	assert.Error(t, ValidateHostname("shop..example.com"))
+	assert.Error(t, ValidateHostname("-shop.example.com"))
+	assert.Error(t, ValidateHostname("shop_api.example.com"))
+}
@@ -0,0 +1,466 @@
+// Package notifications renders notifications about workflows and resources from Go
+// templates and posts them to team webhooks. Every notification type has a built-in
+// template that admin-config.yaml can replace globally or per team.
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/events"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Types are the notification types, named after the events they are sent for
+var Types = []events.EventType{
+	events.EventTypeWorkflowCompleted,
+	events.EventTypeWorkflowFailed,
+	events.EventTypeResourceActive,
+	events.EventTypeResourceFailed,
+	events.EventTypeResourceHibernated,
+	events.EventTypeApprovalRequested,
+}
+
+// Webhook payload formats
+const (
+	FormatJSON  = "json"  // The Message as JSON (default)
+	FormatTeams = "teams" // A Microsoft Teams message with an adaptive card
+)
+
+// Template is the subject and body of a notification type, both Go templates rendered
+// with a Context
+type Template struct {
+	Subject string `yaml:"subject" json:"subject"`
+	Body    string `yaml:"body" json:"body"`
+}
+
+// DefaultTemplates are used for notification types without a configured template
+var DefaultTemplates = map[events.EventType]Template{
+	events.EventTypeWorkflowCompleted: {
+		Subject: `[{{ .App.Name }}] Workflow {{ .Workflow.Name }} completed`,
+		Body:    `Workflow {{ .Workflow.Name }} (execution {{ .Workflow.ExecutionID }}) of {{ .App.Name }} completed at {{ .Time.Format "2006-01-02 15:04 MST" }}.`,
+	},
+	events.EventTypeWorkflowFailed: {
+		Subject: `[{{ .App.Name }}] Workflow {{ .Workflow.Name }} failed`,
+		Body: `Workflow {{ .Workflow.Name }} (execution {{ .Workflow.ExecutionID }}) of {{ .App.Name }} failed` +
+			`{{ with .Workflow.Step }} at step {{ . }}{{ end }}.{{ with .Workflow.Error }}` + "\n\n" + `Error: {{ . }}{{ end }}`,
+	},
+	events.EventTypeResourceActive: {
+		Subject: `[{{ .App.Name }}] {{ .Resource.Type }} {{ .Resource.Name }} is ready`,
+		Body:    `Resource {{ .Resource.Name }} ({{ .Resource.Type }}) of {{ .App.Name }} is active.`,
+	},
+	events.EventTypeResourceFailed: {
+		Subject: `[{{ .App.Name }}] {{ .Resource.Type }} {{ .Resource.Name }} failed`,
+		Body: `Resource {{ .Resource.Name }} ({{ .Resource.Type }}) of {{ .App.Name }} failed to provision.` +
+			`{{ with .Resource.Error }}` + "\n\n" + `Error: {{ . }}{{ end }}`,
+	},
+	events.EventTypeResourceHibernated: {
+		Subject: `[{{ .App.Name }}] {{ .Resource.Name }} hibernated`,
+		Body:    `Resource {{ .Resource.Name }} ({{ .Resource.Type }}) of {{ .App.Name }} was hibernated{{ with .Resource.Reason }}: {{ . }}{{ end }}.`,
+	},
+	events.EventTypeApprovalRequested: {
+		Subject: `[{{ .App.Name }}] Approval #{{ .Approval.ID }} requested for {{ .Approval.Step }}`,
+		Body: `Step {{ .Approval.Step }} of {{ .App.Name }}{{ with .Approval.Environment }} in {{ . }}{{ end }} waits for approval.` +
+			`{{ with .Approval.Summary }}` + "\n\n" + `{{ . }}{{ end }}`,
+	},
+}
+
+// Config is the notifications section of admin-config.yaml
+type Config struct {
+	WebhookURL string                `yaml:"webhookURL" json:"webhookURL"` // Receives notifications of teams without their own webhook
+	TokenEnv   string                `yaml:"tokenEnv" json:"tokenEnv"`     // Environment variable holding the webhook bearer token
+	Format     string                `yaml:"format" json:"format"`         // Payload sent to the webhook: json (default) or teams
+	Types      []string              `yaml:"types" json:"types"`           // Notification types sent (default all)
+	Templates  map[string]Template   `yaml:"templates" json:"templates"`   // Replace the built-in templates, per notification type
+	Teams      map[string]TeamConfig `yaml:"teams" json:"teams"`
+	PublicURL  string                `yaml:"publicURL" json:"publicURL"` // Server address users open; enables the buttons of approval notifications
+}
+
+// TeamConfig customizes the notifications of one team
+type TeamConfig struct {
+	WebhookURL string              `yaml:"webhookURL" json:"webhookURL"` // Replaces the default webhook for this team
+	TokenEnv   string              `yaml:"tokenEnv" json:"tokenEnv"`
+	Format     string              `yaml:"format" json:"format"`       // Payload sent to the team's webhook
+	Types      []string            `yaml:"types" json:"types"`         // Replaces the notification types sent
+	Templates  map[string]Template `yaml:"templates" json:"templates"` // Replace templates for this team
+}
+
+// Masked returns a copy with webhook URLs replaced, since chat webhook URLs embed their credentials
+func (c Config) Masked() Config {
+	mask := func(url string) string {
+		if url == "" {
+			return ""
+		}
+		return "****"
+	}
+	masked := c
+	masked.WebhookURL = mask(c.WebhookURL)
+	masked.Teams = make(map[string]TeamConfig, len(c.Teams))
+	for team, config := range c.Teams {
+		config.WebhookURL = mask(config.WebhookURL)
+		masked.Teams[team] = config
+	}
+	return masked
+}
+
+// Channel is where the notifications of a team are posted
+type Channel struct {
+	WebhookURL string
+	TokenEnv   string
+	Format     string
+	Types      []string
+}
+
+// Sends reports whether the channel takes notifications of a type
+func (c Channel) Sends(notificationType events.EventType) bool {
+	if c.WebhookURL == "" {
+		return false
+	}
+	if len(c.Types) == 0 {
+		return true
+	}
+	for _, t := range c.Types {
+		if t == string(notificationType) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsType reports whether name is a notification type
+func IsType(name string) bool {
+	for _, t := range Types {
+		if string(t) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks notification types, webhook URLs and that every template parses
+func (c Config) Validate() error {
+	if err := validateChannel("notifications", c.WebhookURL, c.Format, c.Types); err != nil {
+		return err
+	}
+	if c.PublicURL != "" {
+		if err := validateURL("notifications.publicURL", c.PublicURL); err != nil {
+			return err
+		}
+	}
+	if err := validateTemplates("notifications.templates", c.Templates); err != nil {
+		return err
+	}
+
+	teams := make([]string, 0, len(c.Teams))
+	for team := range c.Teams {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+	for _, team := range teams {
+		config := c.Teams[team]
+		if err := validateChannel("notifications.teams."+team, config.WebhookURL, config.Format, config.Types); err != nil {
+			return err
+		}
+		if err := validateTemplates("notifications.teams."+team+".templates", config.Templates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateChannel(path, webhookURL, format string, types []string) error {
+	if webhookURL != "" {
+		if err := validateURL(path+".webhookURL", webhookURL); err != nil {
+			return err
+		}
+	}
+	if format != "" && format != FormatJSON && format != FormatTeams {
+		return fmt.Errorf("%s.format: unknown format '%s' (json, teams)", path, format)
+	}
+	for _, t := range types {
+		if !IsType(t) {
+			return fmt.Errorf("%s.types: unknown notification type '%s'", path, t)
+		}
+	}
+	return nil
+}
+
+func validateURL(path, value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s '%s' is not an http(s) URL", path, value)
+	}
+	return nil
+}
+
+func validateTemplates(path string, templates map[string]Template) error {
+	for name, tpl := range templates {
+		if !IsType(name) {
+			return fmt.Errorf("%s: unknown notification type '%s'", path, name)
+		}
+		if _, err := parse(tpl.Subject); err != nil {
+			return fmt.Errorf("%s.%s.subject: %w", path, name, err)
+		}
+		if _, err := parse(tpl.Body); err != nil {
+			return fmt.Errorf("%s.%s.body: %w", path, name, err)
+		}
+	}
+	return nil
+}
+
+// Template returns the template of a notification type for a team. Subject and body are
+// resolved separately: the team's, then the admin-config default, then the built-in one.
+func (c Config) Template(team string, notificationType events.EventType) Template {
+	tpl := DefaultTemplates[notificationType]
+	for _, override := range []Template{c.Templates[string(notificationType)], c.Teams[team].Templates[string(notificationType)]} {
+		if override.Subject != "" {
+			tpl.Subject = override.Subject
+		}
+		if override.Body != "" {
+			tpl.Body = override.Body
+		}
+	}
+	return tpl
+}
+
+// Channel returns where the notifications of a team are posted
+func (c Config) Channel(team string) Channel {
+	channel := Channel{WebhookURL: c.WebhookURL, TokenEnv: c.TokenEnv, Format: c.Format, Types: c.Types}
+	teamConfig := c.Teams[team]
+	if teamConfig.WebhookURL != "" {
+		channel.WebhookURL, channel.TokenEnv, channel.Format = teamConfig.WebhookURL, teamConfig.TokenEnv, teamConfig.Format
+	}
+	if len(teamConfig.Types) > 0 {
+		channel.Types = teamConfig.Types
+	}
+	return channel
+}
+
+// App is the application a notification is about
+type App struct {
+	Name string
+	Team string
+}
+
+// Workflow is the workflow run a notification is about
+type Workflow struct {
+	Name        string
+	ExecutionID int64
+	Step        string // Failed step
+	Error       string
+}
+
+// Resource is the resource a notification is about
+type Resource struct {
+	ID     int64
+	Name   string
+	Type   string
+	State  string
+	Reason string
+	Error  string
+}
+
+// Approval is the approval gate a notification is about
+type Approval struct {
+	ID          int64
+	Step        string
+	Environment string
+	Summary     string
+}
+
+// Context is what templates are rendered with
+type Context struct {
+	Type     events.EventType
+	Time     time.Time
+	App      App
+	Workflow Workflow
+	Resource Resource
+	Approval Approval
+	Data     map[string]interface{} // All data of the event
+}
+
+// NewContext returns the template context of an event of an application owned by team
+func NewContext(event events.Event, team string) Context {
+	data := event.Data
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	str := func(key string) string {
+		if v, ok := data[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	num := func(keys ...string) int64 {
+		for _, key := range keys {
+			switch v := data[key].(type) {
+			case int64:
+				return v
+			case int:
+				return int64(v)
+			case float64:
+				return int64(v)
+			}
+		}
+		return 0
+	}
+
+	return Context{
+		Type: event.Type,
+		Time: event.Timestamp,
+		App:  App{Name: event.AppName, Team: team},
+		Workflow: Workflow{
+			Name:        str("workflow_name"),
+			ExecutionID: num("execution_id", "workflow_execution_id"),
+			Step:        str("step_name"),
+			Error:       str("error"),
+		},
+		Resource: Resource{
+			ID:     num("resource_id"),
+			Name:   str("resource_name"),
+			Type:   str("resource_type"),
+			State:  str("new_state"),
+			Reason: str("reason"),
+			Error:  str("error"),
+		},
+		Approval: Approval{
+			ID:          num("approval_id"),
+			Step:        str("step_name"),
+			Environment: str("environment"),
+			Summary:     str("summary"),
+		},
+		Data: data,
+	}
+}
+
+// Message is a rendered notification
+type Message struct {
+	Type    events.EventType `json:"type"`
+	App     string           `json:"app"`
+	Team    string           `json:"team"`
+	Subject string           `json:"subject"`
+	Body    string           `json:"body"`
+	Text    string           `json:"text"`              // Subject and body, for Slack and Teams incoming webhooks
+	Actions []Action         `json:"actions,omitempty"` // Links to act on the notification
+}
+
+// Action is a link shown as a button with a notification
+type Action struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Style string `json:"style,omitempty"` // positive or destructive
+}
+
+// Actions returns the buttons of a notification: approve and reject for approval
+// requests, opening the approval page of the server. Without a publicURL there are none.
+func (c Config) Actions(ctx Context) []Action {
+	if c.PublicURL == "" || ctx.Type != events.EventTypeApprovalRequested || ctx.Approval.ID == 0 {
+		return nil
+	}
+	page := fmt.Sprintf("%s/approvals/%d", strings.TrimRight(c.PublicURL, "/"), ctx.Approval.ID)
+	return []Action{
+		{Title: "Approve", URL: page + "?decision=approve", Style: "positive"},
+		{Title: "Reject", URL: page + "?decision=reject", Style: "destructive"},
+	}
+}
+
+// funcs are the functions available to notification templates
+var funcs = template.FuncMap{
+	"default": func(def, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return def
+		}
+		return value
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"truncate": func(n int, value string) string {
+		if len(value) <= n {
+			return value
+		}
+		return value[:n] + "..."
+	},
+}
+
+func parse(text string) (*template.Template, error) {
+	return template.New("notification").Funcs(funcs).Option("missingkey=zero").Parse(text)
+}
+
+// Render renders a template with a context
+func Render(tpl Template, ctx Context) (Message, error) {
+	render := func(part, text string) (string, error) {
+		t, err := parse(text)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s template: %w", part, err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, ctx); err != nil {
+			return "", fmt.Errorf("failed to render %s: %w", part, err)
+		}
+		return strings.TrimSpace(buf.String()), nil
+	}
+
+	subject, err := render("subject", tpl.Subject)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := render("body", tpl.Body)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Type:    ctx.Type,
+		App:     ctx.App.Name,
+		Team:    ctx.App.Team,
+		Subject: subject,
+		Body:    body,
+		Text:    strings.TrimSpace(subject + "\n\n" + body),
+	}, nil
+}
+
+// Send posts a message to a channel's webhook, as JSON or in the channel's format
+func Send(ctx context.Context, channel Channel, message Message) error {
+	var payload interface{} = message
+	if channel.Format == FormatTeams {
+		payload = TeamsMessage(message)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if channel.TokenEnv != "" {
+		if token := os.Getenv(channel.TokenEnv); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("notification webhook returned %s: %s", resp.Status, bytes.TrimSpace(text))
+	}
+	return nil
+}
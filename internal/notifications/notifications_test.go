@@ -0,0 +1,171 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"innominatus/internal/events"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Template(t *testing.T) {
+	config := Config{
+		Templates: map[string]Template{
+			"workflow.failed": {Subject: "Deployment of {{ .App.Name }} failed"},
+		},
+		Teams: map[string]TeamConfig{
+			"payments": {Templates: map[string]Template{
+				"workflow.failed": {Body: "{{ .Workflow.Step }}: {{ .Workflow.Error }}"},
+			}},
+		},
+	}
+
+	tpl := config.Template("payments", events.EventTypeWorkflowFailed)
+	assert.Equal(t, "Deployment of {{ .App.Name }} failed", tpl.Subject, "admin default")
+	assert.Equal(t, "{{ .Workflow.Step }}: {{ .Workflow.Error }}", tpl.Body, "team override")
+
+	tpl = config.Template("ecommerce", events.EventTypeWorkflowFailed)
+	assert.Equal(t, DefaultTemplates[events.EventTypeWorkflowFailed].Body, tpl.Body, "built-in default")
+
+	assert.Equal(t, DefaultTemplates[events.EventTypeResourceActive], config.Template("payments", events.EventTypeResourceActive))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+
+	invalid := []Config{
+		{WebhookURL: "hooks.slack.com/services/x"},
+		{Types: []string{"workflow.exploded"}},
+		{Templates: map[string]Template{"workflow.exploded": {Subject: "x"}}},
+		{Templates: map[string]Template{"workflow.failed": {Subject: "{{ .App.Name "}}},
+		{Teams: map[string]TeamConfig{"payments": {Templates: map[string]Template{"resource.failed": {Body: "{{ end }}"}}}}},
+		{Format: "adaptive-card"},
+		{PublicURL: "innominatus.example.com"},
+		{Teams: map[string]TeamConfig{"payments": {WebhookURL: "https://example.webhook.office.com/x", Format: "msteams"}}},
+	}
+	for _, config := range invalid {
+		assert.Error(t, config.Validate(), "%+v", config)
+	}
+}
+
+func TestConfig_ChannelAndMasked(t *testing.T) {
+	config := Config{
+		WebhookURL: "https://hooks.example.com/platform",
+		Types:      []string{"workflow.failed", "resource.failed"},
+		Teams: map[string]TeamConfig{
+			"payments": {WebhookURL: "https://hooks.example.com/payments"},
+			"quiet":    {Types: []string{"resource.failed"}},
+		},
+	}
+
+	assert.Equal(t, "https://hooks.example.com/platform", config.Channel("ecommerce").WebhookURL)
+	assert.Equal(t, "https://hooks.example.com/payments", config.Channel("payments").WebhookURL)
+	assert.True(t, config.Channel("payments").Sends(events.EventTypeWorkflowFailed))
+	assert.False(t, config.Channel("payments").Sends(events.EventTypeWorkflowCompleted))
+	assert.False(t, config.Channel("quiet").Sends(events.EventTypeWorkflowFailed))
+	assert.False(t, Config{}.Channel("payments").Sends(events.EventTypeWorkflowFailed), "no webhook")
+
+	masked := config.Masked()
+	assert.Equal(t, "****", masked.WebhookURL)
+	assert.Equal(t, "****", masked.Teams["payments"].WebhookURL)
+	assert.Equal(t, "", masked.Teams["quiet"].WebhookURL)
+	assert.Equal(t, "https://hooks.example.com/payments", config.Teams["payments"].WebhookURL, "original unchanged")
+}
+
+func TestRender(t *testing.T) {
+	event := SampleEvent(events.EventTypeWorkflowFailed, "shop")
+	message, err := Render(DefaultTemplates[events.EventTypeWorkflowFailed], NewContext(event, "ecommerce"))
+	require.NoError(t, err)
+	assert.Equal(t, "[shop] Workflow deploy-app failed", message.Subject)
+	assert.Contains(t, message.Body, "execution 42")
+	assert.Contains(t, message.Body, "at step provision-database")
+	assert.Contains(t, message.Body, "Error: terraform apply failed: quota exceeded")
+	assert.Equal(t, "ecommerce", message.Team)
+
+	message, err = Render(Template{
+		Subject: `{{ .Resource.Type | upper }} {{ default "unknown" .Data.region }}`,
+		Body:    `{{ truncate 8 .Resource.Error }}`,
+	}, NewContext(SampleEvent(events.EventTypeResourceFailed, "shop"), ""))
+	require.NoError(t, err)
+	assert.Equal(t, "POSTGRES unknown", message.Subject)
+	assert.Equal(t, "provisio...", message.Body)
+
+	_, err = Render(Template{Subject: "{{ .Nope }}"}, NewContext(event, ""))
+	assert.Error(t, err)
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	var received []Message
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		received = append(received, message)
+	}))
+	defer webhook.Close()
+
+	config := Config{Teams: map[string]TeamConfig{
+		"payments": {WebhookURL: webhook.URL, Templates: map[string]Template{
+			"resource.active": {Subject: "{{ .Resource.Name }} for {{ .App.Team }} is up"},
+		}},
+	}}
+	teams := map[string]string{"checkout": "payments", "blog": "content"}
+	notifier := NewNotifier(func() Config { return config }, func(app string) string { return teams[app] })
+
+	require.NoError(t, notifier.Notify(context.Background(), SampleEvent(events.EventTypeResourceActive, "checkout")))
+	require.NoError(t, notifier.Notify(context.Background(), SampleEvent(events.EventTypeResourceActive, "blog")))
+
+	require.Len(t, received, 1, "teams without a webhook get nothing")
+	assert.Equal(t, "db for payments is up", received[0].Subject)
+	assert.Equal(t, "checkout", received[0].App)
+	assert.Contains(t, received[0].Text, "db for payments is up\n\nResource db (postgres)")
+}
+
+func TestNotifier_NotifyTeams(t *testing.T) {
+	var received TeamsPayload
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer webhook.Close()
+
+	config := Config{
+		WebhookURL: "https://hooks.slack.com/services/T000/B000/XXXX",
+		PublicURL:  "https://innominatus.example.com/",
+		Teams: map[string]TeamConfig{
+			"payments": {WebhookURL: webhook.URL, Format: FormatTeams},
+		},
+	}
+	assert.Equal(t, "", config.Channel("ecommerce").Format, "the team webhook brings its format")
+
+	notifier := NewNotifier(func() Config { return config }, func(string) string { return "payments" })
+	require.NoError(t, notifier.Notify(context.Background(), SampleEvent(events.EventTypeApprovalRequested, "checkout")))
+
+	require.Len(t, received.Attachments, 1)
+	assert.Equal(t, "message", received.Type)
+	card := received.Attachments[0].Content
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", received.Attachments[0].ContentType)
+	assert.Equal(t, "AdaptiveCard", card.Type)
+	assert.Equal(t, "[checkout] Approval #7 requested for terraform-apply", card.Body[0]["text"])
+	assert.Equal(t, "Warning", card.Body[0]["color"])
+	assert.Contains(t, card.Body[1]["text"], "Step terraform-apply of checkout in production waits for approval.")
+	assert.Contains(t, card.Body[1]["text"], "Plan: 2 to add")
+
+	require.Len(t, card.Actions, 2)
+	assert.Equal(t, "Action.OpenUrl", card.Actions[0]["type"])
+	assert.Equal(t, "Approve", card.Actions[0]["title"])
+	assert.Equal(t, "https://innominatus.example.com/approvals/7?decision=approve", card.Actions[0]["url"])
+	assert.Equal(t, "destructive", card.Actions[1]["style"])
+
+	// Without a publicURL the card has no buttons
+	config.PublicURL = ""
+	received = TeamsPayload{}
+	require.NoError(t, notifier.Notify(context.Background(), SampleEvent(events.EventTypeApprovalRequested, "checkout")))
+	assert.Empty(t, received.Attachments[0].Content.Actions)
+
+	received = TeamsPayload{}
+	require.NoError(t, notifier.Notify(context.Background(), SampleEvent(events.EventTypeWorkflowFailed, "checkout")))
+	assert.Equal(t, "Attention", received.Attachments[0].Content.Body[0]["color"])
+}
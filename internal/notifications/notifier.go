@@ -0,0 +1,92 @@
+package notifications
+
+import (
+	"context"
+	"innominatus/internal/events"
+	"innominatus/internal/logging"
+	"time"
+)
+
+// Notifier sends a notification for every event of a notification type
+type Notifier struct {
+	config func() Config
+	teamOf func(appName string) string
+	logger *logging.ZerologAdapter
+}
+
+// NewNotifier creates a notifier. config is called for every event, so changes to the
+// templates apply without a restart. teamOf returns the team owning an application.
+func NewNotifier(config func() Config, teamOf func(appName string) string) *Notifier {
+	return &Notifier{config: config, teamOf: teamOf, logger: logging.NewStructuredLogger("notifications")}
+}
+
+// Start subscribes the notifier to the notification types on bus
+func (n *Notifier) Start(bus events.EventBus) {
+	bus.Subscribe("", Types, func(event events.Event) {
+		if err := n.Notify(context.Background(), event); err != nil {
+			n.logger.WarnWithFields("Failed to send notification", map[string]interface{}{
+				"type":     string(event.Type),
+				"app_name": event.AppName,
+				"error":    err.Error(),
+			})
+		}
+	})
+}
+
+// Notify renders the notification of an event with the template of the application's team
+// and posts it to the team's channel. Events the channel does not take are ignored.
+func (n *Notifier) Notify(ctx context.Context, event events.Event) error {
+	team := n.teamOf(event.AppName)
+	config := n.config()
+	channel := config.Channel(team)
+	if !channel.Sends(event.Type) {
+		return nil
+	}
+
+	notificationCtx := NewContext(event, team)
+	message, err := Render(config.Template(team, event.Type), notificationCtx)
+	if err != nil {
+		return err
+	}
+	message.Actions = config.Actions(notificationCtx)
+	return Send(ctx, channel, message)
+}
+
+// SampleEvent returns an event of a notification type with sample data, for previews
+func SampleEvent(notificationType events.EventType, appName string) events.Event {
+	data := map[string]interface{}{}
+	switch notificationType {
+	case events.EventTypeWorkflowCompleted, events.EventTypeWorkflowFailed:
+		data["workflow_name"] = "deploy-app"
+		data["execution_id"] = int64(42)
+		data["total_steps"] = 4
+		if notificationType == events.EventTypeWorkflowFailed {
+			data["step_name"] = "provision-database"
+			data["error"] = "terraform apply failed: quota exceeded"
+		}
+	case events.EventTypeApprovalRequested:
+		data["approval_id"] = int64(7)
+		data["execution_id"] = int64(42)
+		data["step_name"] = "terraform-apply"
+		data["environment"] = "production"
+		data["summary"] = "Plan: 2 to add, 1 to change, 0 to destroy."
+	default:
+		data["resource_id"] = int64(17)
+		data["resource_name"] = "db"
+		data["resource_type"] = "postgres"
+		switch notificationType {
+		case events.EventTypeResourceActive:
+			data["new_state"] = "active"
+		case events.EventTypeResourceFailed:
+			data["new_state"] = "failed"
+			data["error"] = "provisioner timed out after 10m"
+		case events.EventTypeResourceHibernated:
+			data["new_state"] = "hibernated"
+			data["reason"] = "idle for 72h"
+		}
+	}
+
+	event := events.NewEvent(notificationType, appName, "preview", data)
+	event.Timestamp = event.Timestamp.Truncate(time.Second)
+	return event
+}
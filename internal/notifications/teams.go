@@ -0,0 +1,96 @@
+package notifications
+
+import (
+	"innominatus/internal/events"
+	"strings"
+)
+
+// Microsoft Teams takes adaptive cards as attachments of a message. Workflow webhooks
+// and the older incoming webhooks of channels both accept this payload.
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+)
+
+// TeamsPayload is a Microsoft Teams message with one adaptive card
+type TeamsPayload struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// TeamsAttachment carries an adaptive card
+type TeamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     AdaptiveCard `json:"content"`
+}
+
+// AdaptiveCard is the subset of the adaptive card schema notifications use
+type AdaptiveCard struct {
+	Schema  string                   `json:"$schema"`
+	Type    string                   `json:"type"`
+	Version string                   `json:"version"`
+	Body    []map[string]interface{} `json:"body"`
+	Actions []map[string]interface{} `json:"actions,omitempty"`
+}
+
+// TeamsMessage renders a notification as an adaptive card: the subject as title, colored
+// by outcome, the body, the application and team as facts, and its actions as buttons
+func TeamsMessage(message Message) TeamsPayload {
+	title := map[string]interface{}{
+		"type":   "TextBlock",
+		"text":   message.Subject,
+		"weight": "Bolder",
+		"size":   "Medium",
+		"wrap":   true,
+	}
+	if color := cardColor(message.Type); color != "" {
+		title["color"] = color
+	}
+
+	body := []map[string]interface{}{title}
+	if message.Body != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": message.Body, "wrap": true})
+	}
+	facts := []map[string]string{{"title": "Application", "value": message.App}}
+	if message.Team != "" {
+		facts = append(facts, map[string]string{"title": "Team", "value": message.Team})
+	}
+	body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+
+	var actions []map[string]interface{}
+	for _, action := range message.Actions {
+		button := map[string]interface{}{"type": "Action.OpenUrl", "title": action.Title, "url": action.URL}
+		if action.Style != "" {
+			button["style"] = action.Style
+		}
+		actions = append(actions, button)
+	}
+
+	return TeamsPayload{
+		Type: "message",
+		Attachments: []TeamsAttachment{{
+			ContentType: adaptiveCardContentType,
+			Content: AdaptiveCard{
+				Schema:  adaptiveCardSchema,
+				Type:    "AdaptiveCard",
+				Version: adaptiveCardVersion,
+				Body:    body,
+				Actions: actions,
+			},
+		}},
+	}
+}
+
+// cardColor returns the adaptive card color of a notification type's title
+func cardColor(notificationType events.EventType) string {
+	switch {
+	case strings.HasSuffix(string(notificationType), ".failed"):
+		return "Attention"
+	case notificationType == events.EventTypeApprovalRequested:
+		return "Warning"
+	case notificationType == events.EventTypeWorkflowCompleted || notificationType == events.EventTypeResourceActive:
+		return "Good"
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+// Package objectstore uploads objects to S3-compatible storage such as MinIO. Requests
+// are signed with AWS Signature Version 4; payloads are sent unsigned so large objects
+// are streamed without hashing them first.
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultRegion is used when no region is configured; MinIO accepts any region
+const DefaultRegion = "us-east-1"
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// Client uploads objects with path-style URLs (<endpoint>/<bucket>/<key>)
+type Client struct {
+	Endpoint   string // e.g. http://minio.minio-system.svc.cluster.local:9000
+	Region     string
+	AccessKey  string
+	SecretKey  string
+	HTTPClient *http.Client
+	now        func() time.Time
+}
+
+// NewClient creates a client for an S3-compatible endpoint
+func NewClient(endpoint, accessKey, secretKey string) *Client {
+	return &Client{
+		Endpoint:  strings.TrimSuffix(endpoint, "/"),
+		Region:    DefaultRegion,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}
+}
+
+// PutObject uploads size bytes of body to bucket/key
+func (c *Client) PutObject(ctx context.Context, bucket, key string, body io.Reader, size int64, contentType string) error {
+	objectURL, err := url.Parse(fmt.Sprintf("%s/%s/%s", c.Endpoint, bucket, escapeKey(key)))
+	if err != nil {
+		return fmt.Errorf("invalid object URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), body)
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	c.sign(req)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s/%s: %w", bucket, key, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload of %s/%s returned %s: %s", bucket, key, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 headers to a request
+func (c *Client) sign(req *http.Request) {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	region := c.Region
+	if region == "" {
+		region = DefaultRegion
+	}
+
+	timestamp := now().UTC()
+	amzDate := timestamp.Format("20060102T150405Z")
+	date := timestamp.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, unsignedPayload, amzDate)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, region)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hexSHA256(canonicalRequest)}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.SecretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.AccessKey, scope, signedHeaders, signature))
+}
+
+// escapeKey escapes each segment of an object key, keeping the slashes
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func hexSHA256(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
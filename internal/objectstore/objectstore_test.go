@@ -0,0 +1,69 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutObject(t *testing.T) {
+	var request *http.Request
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		request, body = r, string(content)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "minioadmin", "minioadmin")
+	client.now = func() time.Time { return time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) }
+
+	err := client.PutObject(context.Background(), "logs", "step-logs/run 1.log", strings.NewReader("hello"), 5, "text/plain")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPut, request.Method)
+	assert.Equal(t, "/logs/step-logs/run%201.log", request.URL.EscapedPath())
+	assert.Equal(t, "hello", body)
+	assert.Equal(t, int64(5), request.ContentLength)
+	assert.Equal(t, "text/plain", request.Header.Get("Content-Type"))
+	assert.Equal(t, "20261015T120000Z", request.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "UNSIGNED-PAYLOAD", request.Header.Get("X-Amz-Content-Sha256"))
+	assert.Regexp(t, `^AWS4-HMAC-SHA256 Credential=minioadmin/20261015/us-east-1/s3/aws4_request, `+
+		`SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`, request.Header.Get("Authorization"))
+}
+
+func TestPutObjectRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<Error><Code>NoSuchBucket</Code></Error>", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, "key", "secret").PutObject(context.Background(), "missing", "a.log", strings.NewReader(""), 0, "")
+	assert.ErrorContains(t, err, "upload of missing/a.log returned 404 Not Found: <Error><Code>NoSuchBucket</Code></Error>")
+}
+
+func TestSignature(t *testing.T) {
+	// Signing is deterministic for a fixed time, key and request
+	client := NewClient("http://minio:9000", "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	client.now = func() time.Time { return time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) }
+
+	sign := func(secret string) string {
+		client.SecretKey = secret
+		req, err := http.NewRequest(http.MethodPut, "http://minio:9000/logs/1.log", nil)
+		require.NoError(t, err)
+		client.sign(req)
+		return req.Header.Get("Authorization")
+	}
+
+	first := sign("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	assert.Equal(t, first, sign("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"))
+	assert.NotEqual(t, first, sign("other"))
+}
@@ -0,0 +1,174 @@
+// Package observability generates Prometheus rules and Grafana dashboards for the
+// metrics the server exports on /metrics.
+package observability
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Metric names exported by the metrics package that the rules and dashboards use
+const (
+	MetricWorkflowsExecuted     = "innominatus_workflows_executed_total"
+	MetricWorkflowsFailed       = "innominatus_workflows_failed_total"
+	MetricWorkflowDurationAvg   = "innominatus_workflow_duration_seconds_avg"
+	MetricWorkflowQueueDepth    = "innominatus_workflow_queue_depth"
+	MetricWorkflowQueueActive   = "innominatus_workflow_queue_active"
+	MetricProviderProvisions    = "innominatus_provider_provisions_total"
+	MetricProviderDurationAvg   = "innominatus_provider_provision_duration_seconds_avg"
+	MetricProviderLastSuccess   = "innominatus_provider_last_success_timestamp_seconds"
+	MetricHTTPRouteRequests     = "innominatus_http_route_requests_total"
+	MetricHTTPRequestDuration   = "innominatus_http_request_duration_seconds_bucket"
+	MetricHTTPRequestsInFlight  = "innominatus_http_requests_in_flight"
+	MetricStepCacheHits         = "innominatus_step_cache_hits_total"
+	MetricStepCacheMisses       = "innominatus_step_cache_misses_total"
+	MetricLoginAttempts         = "innominatus_login_attempts_total"
+	MetricResourcesExternalFail = "innominatus_resources_external_failed_total"
+	MetricUptime                = "innominatus_uptime_seconds"
+)
+
+// Files of the bundle
+const (
+	RulesFile              = "prometheus-rules.yaml"
+	PlatformDashboardFile  = "innominatus-platform-kpis.json"
+	APIDashboardFile       = "innominatus-api.json"
+	recordingRuleGroupName = "innominatus.kpis"
+	alertingRuleGroupName  = "innominatus.alerts"
+)
+
+var jobPattern = regexp.MustCompile(`^[A-Za-z0-9_:./-]+$`)
+
+// Options adapts the bundle to an installation
+type Options struct {
+	Job string // Prometheus job label of the server; empty matches every job
+}
+
+// Validate checks that Job can be used as a label value in PromQL
+func (o Options) Validate() error {
+	if o.Job != "" && !jobPattern.MatchString(o.Job) {
+		return fmt.Errorf("invalid job '%s': only letters, digits and _:./- are allowed", o.Job)
+	}
+	return nil
+}
+
+// Bundle holds ready-to-import Prometheus rules and Grafana dashboards
+type Bundle struct {
+	PrometheusRules string                            `json:"prometheus_rules"`
+	Dashboards      map[string]map[string]interface{} `json:"dashboards"` // By file name
+}
+
+// Generate builds the bundle for options
+func Generate(options Options) (*Bundle, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	rules, err := yaml.Marshal(prometheusRules(options))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal prometheus rules: %w", err)
+	}
+	return &Bundle{
+		PrometheusRules: string(rules),
+		Dashboards: map[string]map[string]interface{}{
+			PlatformDashboardFile: platformDashboard(options),
+			APIDashboardFile:      apiDashboard(options),
+		},
+	}, nil
+}
+
+// selector returns the label matchers of options with extra matchers, e.g. {job="innominatus",status="failure"}
+func (o Options) selector(matchers ...string) string {
+	if o.Job != "" {
+		matchers = append([]string{fmt.Sprintf(`job="%s"`, o.Job)}, matchers...)
+	}
+	if len(matchers) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(matchers, ",") + "}"
+}
+
+// ruleFile is a Prometheus rule file
+type ruleFile struct {
+	Groups []ruleGroup `yaml:"groups"`
+}
+
+type ruleGroup struct {
+	Name     string `yaml:"name"`
+	Interval string `yaml:"interval,omitempty"`
+	Rules    []rule `yaml:"rules"`
+}
+
+type rule struct {
+	Record      string            `yaml:"record,omitempty"`
+	Alert       string            `yaml:"alert,omitempty"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Recording rules the dashboards and alerts build on
+const (
+	RecordWorkflowRate          = "innominatus:workflows_executed:rate5m"
+	RecordWorkflowFailureRatio  = "innominatus:workflow_failure_ratio:rate15m"
+	RecordProviderFailureRatio  = "innominatus:provider_failure_ratio:rate15m"
+	RecordHTTPErrorRatio        = "innominatus:http_error_ratio:rate5m"
+	RecordHTTPLatencyP95        = "innominatus:http_request_duration_seconds:p95_5m"
+	RecordStepCacheHitRatio     = "innominatus:step_cache_hit_ratio:rate1h"
+	RecordWorkflowQueueDepthMax = "innominatus:workflow_queue_depth:max"
+)
+
+// prometheusRules returns the recording rules for the platform KPIs and alerts on them
+func prometheusRules(o Options) ruleFile {
+	failed := o.selector(`status="failure"`)
+	all := o.selector()
+	serverErrors := o.selector(`status_class="5xx"`)
+
+	recording := []rule{
+		{Record: RecordWorkflowRate, Expr: fmt.Sprintf("sum(rate(%s%s[5m]))", MetricWorkflowsExecuted, all)},
+		{Record: RecordWorkflowFailureRatio, Expr: fmt.Sprintf("sum(rate(%s%s[15m])) / clamp_min(sum(rate(%s%s[15m])), 1e-9)", MetricWorkflowsFailed, all, MetricWorkflowsExecuted, all)},
+		{Record: RecordProviderFailureRatio, Expr: fmt.Sprintf("sum by (provider) (rate(%s%s[15m])) / clamp_min(sum by (provider) (rate(%s%s[15m])), 1e-9)", MetricProviderProvisions, failed, MetricProviderProvisions, all)},
+		{Record: RecordHTTPErrorRatio, Expr: fmt.Sprintf("sum(rate(%s%s[5m])) / clamp_min(sum(rate(%s%s[5m])), 1e-9)", MetricHTTPRouteRequests, serverErrors, MetricHTTPRouteRequests, all)},
+		{Record: RecordHTTPLatencyP95, Expr: fmt.Sprintf("histogram_quantile(0.95, sum by (le, route) (rate(%s%s[5m])))", MetricHTTPRequestDuration, all)},
+		{Record: RecordStepCacheHitRatio, Expr: fmt.Sprintf("sum(rate(%s%s[1h])) / clamp_min(sum(rate(%s%s[1h])) + sum(rate(%s%s[1h])), 1e-9)", MetricStepCacheHits, all, MetricStepCacheHits, all, MetricStepCacheMisses, all)},
+		{Record: RecordWorkflowQueueDepthMax, Expr: fmt.Sprintf("max(%s%s)", MetricWorkflowQueueDepth, all)},
+	}
+
+	alerting := []rule{
+		{
+			Alert:       "InnominatusWorkflowFailureRatioHigh",
+			Expr:        fmt.Sprintf("%s > 0.25 and %s > 0", RecordWorkflowFailureRatio, RecordWorkflowRate),
+			For:         "15m",
+			Labels:      map[string]string{"severity": "warning"},
+			Annotations: map[string]string{"summary": "More than 25% of workflow runs failed in the last 15 minutes"},
+		},
+		{
+			Alert:       "InnominatusWorkflowQueueBacklog",
+			Expr:        fmt.Sprintf("%s > 20", RecordWorkflowQueueDepthMax),
+			For:         "10m",
+			Labels:      map[string]string{"severity": "warning"},
+			Annotations: map[string]string{"summary": "More than 20 workflow tasks have been waiting for a queue worker for 10 minutes"},
+		},
+		{
+			Alert:       "InnominatusProviderFailing",
+			Expr:        fmt.Sprintf("%s > 0.5", RecordProviderFailureRatio),
+			For:         "15m",
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": "Most provisioning workflows of provider {{ $labels.provider }} fail"},
+		},
+		{
+			Alert:       "InnominatusAPIErrorRatioHigh",
+			Expr:        fmt.Sprintf("%s > 0.05", RecordHTTPErrorRatio),
+			For:         "10m",
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": "More than 5% of API requests fail with a server error"},
+		},
+	}
+
+	return ruleFile{Groups: []ruleGroup{
+		{Name: recordingRuleGroupName, Interval: "1m", Rules: recording},
+		{Name: alertingRuleGroupName, Rules: alerting},
+	}}
+}
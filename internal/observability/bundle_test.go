@@ -0,0 +1,72 @@
+package observability
+
+import (
+	"encoding/json"
+	"innominatus/internal/metrics"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMetricNamesAreExported(t *testing.T) {
+	m := metrics.GetGlobal()
+	m.RecordWorkflowExecution(true, time.Second)
+	m.RecordProviderProvision("database-team", false, time.Second)
+	m.RecordProviderProvision("database-team", true, time.Second)
+	m.RecordStepCacheLookup("terraform", true)
+	m.RecordLoginAttempt("success")
+	m.ObserveHTTPRequest("GET", "/api/specs", 200, time.Millisecond)
+	m.IncHTTPInFlight("/api/specs")
+	defer m.DecHTTPInFlight("/api/specs")
+	exported := m.Export()
+
+	for _, name := range []string{
+		MetricWorkflowsExecuted, MetricWorkflowsFailed, MetricWorkflowDurationAvg,
+		MetricWorkflowQueueDepth, MetricWorkflowQueueActive,
+		MetricProviderProvisions, MetricProviderDurationAvg, MetricProviderLastSuccess,
+		MetricHTTPRouteRequests, MetricHTTPRequestDuration, MetricHTTPRequestsInFlight,
+		MetricStepCacheHits, MetricStepCacheMisses, MetricLoginAttempts,
+		MetricResourcesExternalFail, MetricUptime,
+	} {
+		assert.Contains(t, exported, "# TYPE "+strings.TrimSuffix(name, "_bucket")+" ", "metric %s is not exported", name)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	bundle, err := Generate(Options{Job: "innominatus"})
+	require.NoError(t, err)
+
+	var rules ruleFile
+	require.NoError(t, yaml.Unmarshal([]byte(bundle.PrometheusRules), &rules))
+	require.Len(t, rules.Groups, 2)
+	for _, group := range rules.Groups {
+		for _, r := range group.Rules {
+			assert.NotEmpty(t, r.Expr)
+			if r.Record != "" {
+				assert.Contains(t, r.Expr, `job="innominatus"`, r.Record)
+			}
+		}
+	}
+
+	require.Contains(t, bundle.Dashboards, PlatformDashboardFile)
+	require.Contains(t, bundle.Dashboards, APIDashboardFile)
+	data, err := json.Marshal(bundle.Dashboards[PlatformDashboardFile])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"uid":"${DS_PROMETHEUS}"`)
+	assert.Contains(t, string(data), RecordWorkflowFailureRatio)
+	assert.Contains(t, string(data), `innominatus_workflow_queue_depth{job=\"innominatus\"}`)
+
+	bundle, err = Generate(Options{})
+	require.NoError(t, err)
+	assert.False(t, strings.Contains(bundle.PrometheusRules, "job="), "no job selector by default")
+}
+
+func TestGenerate_InvalidJob(t *testing.T) {
+	_, err := Generate(Options{Job: `x"} or vector(1)`})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid job")
+}
@@ -0,0 +1,105 @@
+package observability
+
+import "fmt"
+
+// datasource is the Prometheus data source of the dashboards; Grafana asks for it on import
+var datasource = map[string]interface{}{"type": "prometheus", "uid": "${DS_PROMETHEUS}"}
+
+// panel is a dashboard panel with its PromQL queries by legend
+type panel struct {
+	title   string
+	kind    string // stat or timeseries
+	unit    string
+	targets [][2]string // expr, legend
+}
+
+// platformDashboard shows workflow, queue and provider KPIs
+func platformDashboard(o Options) map[string]interface{} {
+	all := o.selector()
+	failed := o.selector(`status="failure"`)
+	return dashboard("innominatus-platform-kpis", "innominatus Platform KPIs", []panel{
+		{title: "Workflow runs (24h)", kind: "stat", unit: "short", targets: [][2]string{{fmt.Sprintf("sum(increase(%s%s[24h]))", MetricWorkflowsExecuted, all), "runs"}}},
+		{title: "Workflow failure ratio", kind: "stat", unit: "percentunit", targets: [][2]string{{RecordWorkflowFailureRatio, "failure ratio"}}},
+		{title: "Queued workflow tasks", kind: "stat", unit: "short", targets: [][2]string{{RecordWorkflowQueueDepthMax, "queued"}}},
+		{title: "Workflow runs", kind: "timeseries", unit: "ops", targets: [][2]string{
+			{RecordWorkflowRate, "executed"},
+			{fmt.Sprintf("sum(rate(%s%s[5m]))", MetricWorkflowsFailed, all), "failed"},
+		}},
+		{title: "Average workflow duration", kind: "timeseries", unit: "s", targets: [][2]string{{fmt.Sprintf("max(%s%s)", MetricWorkflowDurationAvg, all), "average (last 100 runs)"}}},
+		{title: "Workflow queue", kind: "timeseries", unit: "short", targets: [][2]string{
+			{fmt.Sprintf("sum(%s%s)", MetricWorkflowQueueDepth, all), "queued"},
+			{fmt.Sprintf("sum(%s%s)", MetricWorkflowQueueActive, all), "running"},
+		}},
+		{title: "Provider errors", kind: "timeseries", unit: "short", targets: [][2]string{{fmt.Sprintf("sum by (provider) (increase(%s%s[1h]))", MetricProviderProvisions, failed), "{{provider}}"}}},
+		{title: "Provider failure ratio", kind: "timeseries", unit: "percentunit", targets: [][2]string{{RecordProviderFailureRatio, "{{provider}}"}}},
+		{title: "Provisioning duration by provider", kind: "timeseries", unit: "s", targets: [][2]string{{fmt.Sprintf("max by (provider) (%s%s)", MetricProviderDurationAvg, all), "{{provider}}"}}},
+		{title: "Time since last successful provision", kind: "timeseries", unit: "s", targets: [][2]string{{fmt.Sprintf("time() - max by (provider) (%s%s)", MetricProviderLastSuccess, all), "{{provider}}"}}},
+		{title: "Failed external resources", kind: "timeseries", unit: "short", targets: [][2]string{{fmt.Sprintf("sum(%s%s)", MetricResourcesExternalFail, all), "failed"}}},
+		{title: "Step cache hit ratio", kind: "timeseries", unit: "percentunit", targets: [][2]string{{RecordStepCacheHitRatio, "hit ratio"}}},
+	})
+}
+
+// apiDashboard shows API traffic, latency, errors and logins
+func apiDashboard(o Options) map[string]interface{} {
+	all := o.selector()
+	return dashboard("innominatus-api", "innominatus API", []panel{
+		{title: "Server error ratio", kind: "stat", unit: "percentunit", targets: [][2]string{{RecordHTTPErrorRatio, "5xx ratio"}}},
+		{title: "Requests in flight", kind: "stat", unit: "short", targets: [][2]string{{fmt.Sprintf("sum(%s%s)", MetricHTTPRequestsInFlight, all), "in flight"}}},
+		{title: "Uptime", kind: "stat", unit: "s", targets: [][2]string{{fmt.Sprintf("min(%s%s)", MetricUptime, all), "uptime"}}},
+		{title: "Requests by status class", kind: "timeseries", unit: "reqps", targets: [][2]string{{fmt.Sprintf("sum by (status_class) (rate(%s%s[5m]))", MetricHTTPRouteRequests, all), "{{status_class}}"}}},
+		{title: "p95 latency by route", kind: "timeseries", unit: "s", targets: [][2]string{{fmt.Sprintf("topk(10, %s)", RecordHTTPLatencyP95), "{{route}}"}}},
+		{title: "Busiest routes", kind: "timeseries", unit: "reqps", targets: [][2]string{{fmt.Sprintf("topk(10, sum by (route) (rate(%s%s[5m])))", MetricHTTPRouteRequests, all), "{{route}}"}}},
+		{title: "Logins by outcome", kind: "timeseries", unit: "short", targets: [][2]string{{fmt.Sprintf("sum by (outcome) (increase(%s%s[1h]))", MetricLoginAttempts, all), "{{outcome}}"}}},
+	})
+}
+
+// dashboard builds an importable Grafana dashboard with panels three to a row
+func dashboard(uid, title string, panels []panel) map[string]interface{} {
+	const width, height = 8, 8
+	built := make([]map[string]interface{}, 0, len(panels))
+	for i, p := range panels {
+		targets := make([]map[string]interface{}, 0, len(p.targets))
+		for j, t := range p.targets {
+			targets = append(targets, map[string]interface{}{
+				"datasource":   datasource,
+				"expr":         t[0],
+				"legendFormat": t[1],
+				"refId":        string(rune('A' + j)),
+			})
+		}
+		built = append(built, map[string]interface{}{
+			"id":         i + 1,
+			"title":      p.title,
+			"type":       p.kind,
+			"datasource": datasource,
+			"targets":    targets,
+			"gridPos": map[string]interface{}{
+				"h": height,
+				"w": width,
+				"x": (i % 3) * width,
+				"y": (i / 3) * height,
+			},
+			"fieldConfig": map[string]interface{}{
+				"defaults":  map[string]interface{}{"unit": p.unit},
+				"overrides": []interface{}{},
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"__inputs": []map[string]interface{}{{
+			"name":     "DS_PROMETHEUS",
+			"label":    "Prometheus",
+			"type":     "datasource",
+			"pluginId": "prometheus",
+		}},
+		"uid":           uid,
+		"title":         title,
+		"tags":          []string{"innominatus"},
+		"timezone":      "browser",
+		"schemaVersion": 39,
+		"refresh":       "1m",
+		"time":          map[string]interface{}{"from": "now-24h", "to": "now"},
+		"panels":        built,
+	}
+}
@@ -0,0 +1,91 @@
+package orchestration
+
+import (
+	"fmt"
+	"sync"
+)
+
+// HealthWaitingForCapacity is the health status of a pending resource that is queued
+// because a provisioning concurrency limit is reached
+const HealthWaitingForCapacity = "waiting_for_capacity"
+
+// ConcurrencyLimits bounds how many resources the engine provisions at the same time.
+// A resource type or provider without a positive limit is unlimited; MaxConcurrent
+// defaults to 1.
+type ConcurrencyLimits struct {
+	MaxConcurrent int            // Provisions across all resource types and providers
+	ResourceTypes map[string]int // Per resource type, e.g. postgres: 2
+	Providers     map[string]int // Per provider, e.g. database-team: 3
+}
+
+// ConcurrencyLimiter counts in-flight provisions per resource type and provider
+type ConcurrencyLimiter struct {
+	mu          sync.Mutex
+	limits      ConcurrencyLimits
+	total       int
+	perType     map[string]int
+	perProvider map[string]int
+}
+
+// NewConcurrencyLimiter creates a limiter enforcing limits
+func NewConcurrencyLimiter(limits ConcurrencyLimits) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{
+		perType:     make(map[string]int),
+		perProvider: make(map[string]int),
+	}
+	l.SetLimits(limits)
+	return l
+}
+
+// SetLimits replaces the limits. Provisions already in flight keep their slots.
+func (l *ConcurrencyLimiter) SetLimits(limits ConcurrencyLimits) {
+	if limits.MaxConcurrent <= 0 {
+		limits.MaxConcurrent = 1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = limits
+}
+
+// TryAcquire takes a slot for provisioning a resource of resourceType with provider.
+// When a limit is reached it returns false and the reason.
+func (l *ConcurrencyLimiter) TryAcquire(resourceType, provider string) (bool, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit := l.limits.ResourceTypes[resourceType]; limit > 0 && l.perType[resourceType] >= limit {
+		return false, fmt.Sprintf("waiting for capacity: %d of %d concurrent %s provisions in progress", l.perType[resourceType], limit, resourceType)
+	}
+	if limit := l.limits.Providers[provider]; limit > 0 && l.perProvider[provider] >= limit {
+		return false, fmt.Sprintf("waiting for capacity: %d of %d concurrent provisions by provider %s in progress", l.perProvider[provider], limit, provider)
+	}
+	if l.total >= l.limits.MaxConcurrent {
+		return false, fmt.Sprintf("waiting for capacity: %d of %d concurrent provisions in progress", l.total, l.limits.MaxConcurrent)
+	}
+
+	l.total++
+	l.perType[resourceType]++
+	l.perProvider[provider]++
+	return true, ""
+}
+
+// Release returns a slot taken by TryAcquire
+func (l *ConcurrencyLimiter) Release(resourceType, provider string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.total--
+	if l.perType[resourceType]--; l.perType[resourceType] <= 0 {
+		delete(l.perType, resourceType)
+	}
+	if l.perProvider[provider]--; l.perProvider[provider] <= 0 {
+		delete(l.perProvider, provider)
+	}
+}
+
+// InFlight returns the number of provisions in progress
+func (l *ConcurrencyLimiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.total
+}
@@ -0,0 +1,97 @@
+package orchestration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	limits := ConcurrencyLimits{
+		MaxConcurrent: 4,
+		ResourceTypes: map[string]int{"postgres": 2},
+		Providers:     map[string]int{"storage-team": 1},
+	}
+
+	type acquire struct {
+		resourceType string
+		provider     string
+		wantOK       bool
+		wantReason   string
+	}
+
+	tests := []struct {
+		name     string
+		limits   ConcurrencyLimits
+		acquires []acquire
+	}{
+		{
+			name:   "resource type limit",
+			limits: limits,
+			acquires: []acquire{
+				{"postgres", "database-team", true, ""},
+				{"postgres", "database-team", true, ""},
+				{"postgres", "database-team", false, "waiting for capacity: 2 of 2 concurrent postgres provisions in progress"},
+				{"redis", "database-team", true, ""},
+			},
+		},
+		{
+			name:   "provider limit",
+			limits: limits,
+			acquires: []acquire{
+				{"s3-bucket", "storage-team", true, ""},
+				{"volume", "storage-team", false, "waiting for capacity: 1 of 1 concurrent provisions by provider storage-team in progress"},
+			},
+		},
+		{
+			name:   "total limit",
+			limits: limits,
+			acquires: []acquire{
+				{"redis", "database-team", true, ""},
+				{"route", "network-team", true, ""},
+				{"namespace", "container-team", true, ""},
+				{"kafka", "messaging-team", true, ""},
+				{"vault-space", "security-team", false, "waiting for capacity: 4 of 4 concurrent provisions in progress"},
+			},
+		},
+		{
+			name:   "serial by default",
+			limits: ConcurrencyLimits{},
+			acquires: []acquire{
+				{"postgres", "database-team", true, ""},
+				{"redis", "database-team", false, "waiting for capacity: 1 of 1 concurrent provisions in progress"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewConcurrencyLimiter(tt.limits)
+			acquired := 0
+			for i, a := range tt.acquires {
+				ok, reason := limiter.TryAcquire(a.resourceType, a.provider)
+				assert.Equal(t, a.wantOK, ok, "acquire %d", i+1)
+				assert.Equal(t, a.wantReason, reason, "acquire %d", i+1)
+				if ok {
+					acquired++
+				}
+			}
+			assert.Equal(t, acquired, limiter.InFlight())
+		})
+	}
+}
+
+func TestConcurrencyLimiterRelease(t *testing.T) {
+	limiter := NewConcurrencyLimiter(ConcurrencyLimits{MaxConcurrent: 10, ResourceTypes: map[string]int{"postgres": 1}})
+
+	ok, _ := limiter.TryAcquire("postgres", "database-team")
+	assert.True(t, ok)
+	ok, _ = limiter.TryAcquire("postgres", "database-team")
+	assert.False(t, ok)
+
+	limiter.Release("postgres", "database-team")
+	assert.Equal(t, 0, limiter.InFlight())
+
+	ok, _ = limiter.TryAcquire("postgres", "database-team")
+	assert.True(t, ok, "a released slot is available to the next queued resource")
+}
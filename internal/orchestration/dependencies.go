@@ -0,0 +1,188 @@
+package orchestration
+
+import (
+	"fmt"
+	"innominatus/internal/database"
+	"sort"
+	"strings"
+)
+
+// UpstreamStatus reports whether an upstream application is ready for its dependents
+// to be deployed
+type UpstreamStatus struct {
+	Application string `json:"application"`
+	Ready       bool   `json:"ready"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// applicationGetter looks up deployed applications (implemented by *database.Database)
+type applicationGetter interface {
+	GetApplication(name string) (*database.Application, error)
+}
+
+// resourceLister lists an application's resources (implemented by *database.ResourceRepository)
+type resourceLister interface {
+	ListResourceInstances(applicationName string) ([]*database.ResourceInstance, error)
+}
+
+// UpstreamChecker verifies that the applications a spec declares in dependsOn are
+// deployed and healthy
+type UpstreamChecker struct {
+	apps      applicationGetter
+	resources resourceLister
+}
+
+// NewUpstreamChecker creates a checker; resources may be nil, in which case only
+// the existence of upstream applications is verified
+func NewUpstreamChecker(apps applicationGetter, resources resourceLister) *UpstreamChecker {
+	return &UpstreamChecker{apps: apps, resources: resources}
+}
+
+// Check returns the status of each upstream application, in the given order
+func (c *UpstreamChecker) Check(upstreams []string) []UpstreamStatus {
+	statuses := make([]UpstreamStatus, 0, len(upstreams))
+	for _, name := range upstreams {
+		statuses = append(statuses, c.check(name))
+	}
+	return statuses
+}
+
+func (c *UpstreamChecker) check(name string) UpstreamStatus {
+	if _, err := c.apps.GetApplication(name); err != nil {
+		return UpstreamStatus{Application: name, Reason: "not deployed"}
+	}
+	if c.resources == nil {
+		return UpstreamStatus{Application: name, Ready: true}
+	}
+
+	resources, err := c.resources.ListResourceInstances(name)
+	if err != nil {
+		return UpstreamStatus{Application: name, Reason: fmt.Sprintf("failed to list resources: %v", err)}
+	}
+	for _, resource := range resources {
+		if reason := resourceNotReady(resource); reason != "" {
+			return UpstreamStatus{Application: name, Reason: reason}
+		}
+	}
+	return UpstreamStatus{Application: name, Ready: true}
+}
+
+// resourceNotReady explains why a resource blocks its application's dependents, or
+// returns "" when it does not. Resources being scaled or updated stay usable.
+func resourceNotReady(resource *database.ResourceInstance) string {
+	switch resource.State {
+	case database.ResourceStateActive, database.ResourceStateScaling, database.ResourceStateUpdating:
+	default:
+		return fmt.Sprintf("resource '%s' is %s", resource.ResourceName, resource.State)
+	}
+	if resource.HealthStatus == "unhealthy" {
+		return fmt.Sprintf("resource '%s' is unhealthy", resource.ResourceName)
+	}
+	return ""
+}
+
+// BlockingUpstreams returns the statuses that are not ready
+func BlockingUpstreams(statuses []UpstreamStatus) []UpstreamStatus {
+	var blocking []UpstreamStatus
+	for _, status := range statuses {
+		if !status.Ready {
+			blocking = append(blocking, status)
+		}
+	}
+	return blocking
+}
+
+// ApplicationDependencies maps each application to the upstream applications its spec
+// declares in dependsOn
+func ApplicationDependencies(apps []*database.Application) map[string][]string {
+	deps := make(map[string][]string, len(apps))
+	for _, app := range apps {
+		if app.ScoreSpec != nil {
+			deps[app.Name] = app.ScoreSpec.DependsOn
+		} else {
+			deps[app.Name] = nil
+		}
+	}
+	return deps
+}
+
+// DeploymentOrder sorts applications so that every application follows its upstreams.
+// Applications without an ordering constraint are sorted by name. Upstreams that are
+// not keys of deps are included. A dependency cycle is an error naming the cycle.
+func DeploymentOrder(deps map[string][]string) ([]string, error) {
+	names := make(map[string]bool, len(deps))
+	for app, upstreams := range deps {
+		names[app] = true
+		for _, upstream := range upstreams {
+			names[upstream] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(sorted))
+	order := make([]string, 0, len(sorted))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for i, p := range path {
+				if p == name {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), name)
+			return fmt.Errorf("application dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		upstreams := append([]string{}, deps[name]...)
+		sort.Strings(upstreams)
+		for _, upstream := range upstreams {
+			if err := visit(upstream); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range sorted {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Downstreams returns the applications that declare app in dependsOn, sorted by name
+func Downstreams(deps map[string][]string, app string) []string {
+	var downstreams []string
+	for name, upstreams := range deps {
+		for _, upstream := range upstreams {
+			if upstream == app {
+				downstreams = append(downstreams, name)
+				break
+			}
+		}
+	}
+	sort.Strings(downstreams)
+	return downstreams
+}
@@ -0,0 +1,140 @@
+package orchestration
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"innominatus/internal/database"
+	"innominatus/internal/types"
+)
+
+func TestDeploymentOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		deps    map[string][]string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "independent applications sorted by name",
+			deps: map[string][]string{"web": nil, "api": nil},
+			want: []string{"api", "web"},
+		},
+		{
+			name: "chain",
+			deps: map[string][]string{"frontend": {"api"}, "api": {"database"}, "database": nil},
+			want: []string{"database", "api", "frontend"},
+		},
+		{
+			name: "diamond",
+			deps: map[string][]string{"web": {"auth", "api"}, "auth": {"db"}, "api": {"db"}},
+			want: []string{"db", "api", "auth", "web"},
+		},
+		{
+			name:    "cycle",
+			deps:    map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}},
+			wantErr: "application dependency cycle: a -> b -> c -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeploymentOrder(tt.deps)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("DeploymentOrder() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DeploymentOrder() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeploymentOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownstreams(t *testing.T) {
+	deps := map[string][]string{"web": {"api"}, "worker": {"api", "queue"}, "api": nil}
+
+	if got := Downstreams(deps, "api"); !reflect.DeepEqual(got, []string{"web", "worker"}) {
+		t.Errorf("Downstreams(api) = %v", got)
+	}
+	if got := Downstreams(deps, "web"); len(got) != 0 {
+		t.Errorf("Downstreams(web) = %v, want none", got)
+	}
+}
+
+type fakeApplications map[string]*database.Application
+
+func (f fakeApplications) GetApplication(name string) (*database.Application, error) {
+	if app, ok := f[name]; ok {
+		return app, nil
+	}
+	return nil, fmt.Errorf("application not found")
+}
+
+type fakeResources map[string][]*database.ResourceInstance
+
+func (f fakeResources) ListResourceInstances(applicationName string) ([]*database.ResourceInstance, error) {
+	return f[applicationName], nil
+}
+
+func TestUpstreamChecker(t *testing.T) {
+	apps := fakeApplications{
+		"database": {Name: "database", ScoreSpec: &types.ScoreSpec{}},
+		"api":      {Name: "api", ScoreSpec: &types.ScoreSpec{DependsOn: []string{"database"}}},
+		"cache":    {Name: "cache", ScoreSpec: &types.ScoreSpec{}},
+		"queue":    {Name: "queue", ScoreSpec: &types.ScoreSpec{}},
+	}
+	resources := fakeResources{
+		"database": {{ResourceName: "db", State: database.ResourceStateActive, HealthStatus: "healthy"}},
+		"api":      {{ResourceName: "route", State: database.ResourceStateUpdating}},
+		"cache":    {{ResourceName: "redis", State: database.ResourceStateProvisioning}},
+		"queue":    {{ResourceName: "broker", State: database.ResourceStateActive, HealthStatus: "unhealthy"}},
+	}
+
+	statuses := NewUpstreamChecker(apps, resources).Check([]string{"database", "api", "cache", "queue", "search"})
+
+	want := []struct {
+		ready  bool
+		reason string
+	}{
+		{ready: true},
+		{ready: true},
+		{reason: "resource 'redis' is provisioning"},
+		{reason: "resource 'broker' is unhealthy"},
+		{reason: "not deployed"},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("Check() returned %d statuses, want %d", len(statuses), len(want))
+	}
+	for i, w := range want {
+		if statuses[i].Ready != w.ready || !strings.Contains(statuses[i].Reason, w.reason) {
+			t.Errorf("status %s = %+v, want ready=%v reason=%q", statuses[i].Application, statuses[i], w.ready, w.reason)
+		}
+	}
+
+	blocking := BlockingUpstreams(statuses)
+	if len(blocking) != 3 || blocking[0].Application != "cache" {
+		t.Errorf("BlockingUpstreams() = %+v", blocking)
+	}
+
+	// Without a resource lister only the existence of upstreams is checked
+	statuses = NewUpstreamChecker(apps, nil).Check([]string{"cache", "search"})
+	if !statuses[0].Ready || statuses[1].Ready {
+		t.Errorf("Check() without resources = %+v", statuses)
+	}
+}
+
+func TestEngineUpstreamsReadyWithoutDatabase(t *testing.T) {
+	engine := &Engine{}
+
+	if !engine.upstreamsReady(make(map[string]bool), "web") {
+		t.Error("expected resources to be provisioned when no database is configured")
+	}
+}
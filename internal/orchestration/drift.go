@@ -0,0 +1,151 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/events"
+	"innominatus/pkg/sdk"
+	"strings"
+	"time"
+)
+
+// DefaultDriftCheckInterval is how often active resources are compared with the status
+// their provisioner reports
+const DefaultDriftCheckInterval = 10 * time.Minute
+
+// SetDriftCheckInterval sets how often active resources are checked for drift; 0 turns
+// drift detection off. Call before Start.
+func (e *Engine) SetDriftCheckInterval(interval time.Duration) {
+	e.driftInterval = interval
+}
+
+// detectDrift asks the provisioners of all active resources for their status and records
+// whether it still matches the desired configuration. Resource types provisioned only by
+// workflows report no status and are skipped.
+func (e *Engine) detectDrift(ctx context.Context) {
+	if e.resourceRepo == nil {
+		return
+	}
+
+	resources, err := e.resourceRepo.ListResourceInstancesInState(database.ResourceStateActive)
+	if err != nil {
+		e.logger.ErrorWithFields("Failed to list active resources for drift detection", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	for _, resource := range resources {
+		if !e.registry.HasProvisioner(resource.ResourceType) {
+			continue
+		}
+
+		state, message := e.checkDrift(ctx, resource)
+		var messagePtr *string
+		if message != "" {
+			messagePtr = &message
+		}
+		if err := e.resourceRepo.UpdateResourceDrift(resource.ID, state, messagePtr, e.clock.Now()); err != nil {
+			e.logger.ErrorWithFields("Failed to record resource drift", map[string]interface{}{
+				"resource_id": resource.ID,
+				"error":       err.Error(),
+			})
+			continue
+		}
+
+		if state == database.DriftStateDrifted && resource.DriftState != database.DriftStateDrifted {
+			e.logger.WarnWithFields("Resource drifted from its desired configuration", map[string]interface{}{
+				"app_name":      resource.ApplicationName,
+				"resource_name": resource.ResourceName,
+				"drift":         message,
+			})
+			if e.eventBus != nil {
+				e.eventBus.Publish(events.NewEvent(
+					events.EventTypeResourceDrifted,
+					resource.ApplicationName,
+					"orchestration-engine",
+					map[string]interface{}{
+						"resource_id":   resource.ID,
+						"resource_name": resource.ResourceName,
+						"resource_type": resource.ResourceType,
+						"drift":         message,
+					},
+				))
+			}
+		}
+	}
+}
+
+// checkDrift compares the status the provisioner of a resource reports with the resource's
+// desired configuration and returns the drift state with a message
+func (e *Engine) checkDrift(ctx context.Context, resource *database.ResourceInstance) (string, string) {
+	provisioner, err := e.registry.GetProvisioner(resource.ResourceType)
+	if err != nil {
+		return database.DriftStateUnknown, err.Error()
+	}
+	status, err := provisioner.GetStatus(ctx, SDKResource(resource))
+	if err != nil {
+		return database.DriftStateUnknown, fmt.Sprintf("failed to get status: %v", err)
+	}
+	return compareDrift(resource.Configuration, status)
+}
+
+// compareDrift reports a resource as drifted when its provisioner reports it terminated,
+// when a configuration key that is also in the status metadata has a different value, or
+// when the reported labels lack a deployment label. Keys missing from the metadata are not
+// compared, since provisioners only report what they observe.
+func compareDrift(desired map[string]interface{}, status *sdk.ResourceStatus) (string, string) {
+	if status == nil {
+		return database.DriftStateUnknown, "provisioner reported no status"
+	}
+	if status.State == sdk.ResourceStateTerminated {
+		message := "resource no longer exists"
+		if status.Message != "" {
+			message += ": " + status.Message
+		}
+		return database.DriftStateDrifted, message
+	}
+
+	var differences []string
+	for _, change := range sdk.DiffConfig(desired, status.Metadata) {
+		if change.Action != sdk.ChangeActionChange {
+			continue
+		}
+		differences = append(differences, fmt.Sprintf("%s: desired %s, actual %s", change.Path, driftValue(change.Before), driftValue(change.After)))
+	}
+	if labels, ok := observedLabels(status.Metadata); ok {
+		if missing := sdk.MissingDeploymentLabels(labels); len(missing) > 0 {
+			differences = append(differences, "missing deployment labels: "+strings.Join(missing, ", "))
+		}
+	}
+	if len(differences) > 0 {
+		return database.DriftStateDrifted, strings.Join(differences, "; ")
+	}
+	return database.DriftStateInSync, ""
+}
+
+// observedLabels returns the labels or tags a provisioner reports in the "labels" key of
+// its status metadata. Provisioners that report none are not checked for deployment labels.
+func observedLabels(metadata map[string]interface{}) (map[string]string, bool) {
+	switch labels := metadata["labels"].(type) {
+	case map[string]string:
+		return labels, true
+	case map[string]interface{}:
+		result := make(map[string]string, len(labels))
+		for key, value := range labels {
+			result[key] = fmt.Sprintf("%v", value)
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+func driftValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
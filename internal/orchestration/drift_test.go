@@ -0,0 +1,112 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"innominatus/internal/database"
+	"innominatus/internal/providers"
+	"innominatus/pkg/sdk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusProvisioner reports a fixed status
+type statusProvisioner struct {
+	fakeProvisioner
+	status *sdk.ResourceStatus
+	err    error
+}
+
+func (p *statusProvisioner) GetStatus(ctx context.Context, resource *sdk.Resource) (*sdk.ResourceStatus, error) {
+	return p.status, p.err
+}
+
+func TestCompareDrift(t *testing.T) {
+	desired := map[string]interface{}{
+		"replicas": 3,
+		"version":  "15",
+		"storage":  map[string]interface{}{"size": "10Gi"},
+	}
+
+	tests := []struct {
+		name        string
+		status      *sdk.ResourceStatus
+		wantState   string
+		wantMessage string
+	}{
+		{
+			name:      "matching metadata",
+			status:    &sdk.ResourceStatus{State: sdk.ResourceStateActive, Metadata: map[string]interface{}{"replicas": 3.0, "version": "15", "endpoint": "db:5432"}},
+			wantState: database.DriftStateInSync,
+		},
+		{
+			name:      "keys not reported",
+			status:    &sdk.ResourceStatus{State: sdk.ResourceStateActive},
+			wantState: database.DriftStateInSync,
+		},
+		{
+			name:        "changed values",
+			status:      &sdk.ResourceStatus{State: sdk.ResourceStateActive, Metadata: map[string]interface{}{"replicas": 1, "storage": map[string]interface{}{"size": "20Gi"}}},
+			wantState:   database.DriftStateDrifted,
+			wantMessage: `replicas: desired 3, actual 1; storage.size: desired "10Gi", actual "20Gi"`,
+		},
+		{
+			name: "deployment labels present",
+			status: &sdk.ResourceStatus{State: sdk.ResourceStateActive, Metadata: map[string]interface{}{
+				"labels": sdk.DeploymentLabels("shop", "payments", "production", 7),
+			}},
+			wantState: database.DriftStateInSync,
+		},
+		{
+			name: "deployment labels removed",
+			status: &sdk.ResourceStatus{State: sdk.ResourceStateActive, Metadata: map[string]interface{}{
+				"labels": map[string]interface{}{"app": "shop", "managed-by": "innominatus", "workflow-execution-id": 7},
+			}},
+			wantState:   database.DriftStateDrifted,
+			wantMessage: "missing deployment labels: team, environment",
+		},
+		{
+			name:        "removed outside innominatus",
+			status:      &sdk.ResourceStatus{State: sdk.ResourceStateTerminated, Message: "namespace not found"},
+			wantState:   database.DriftStateDrifted,
+			wantMessage: "resource no longer exists: namespace not found",
+		},
+		{
+			name:        "no status",
+			wantState:   database.DriftStateUnknown,
+			wantMessage: "provisioner reported no status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, message := compareDrift(desired, tt.status)
+			assert.Equal(t, tt.wantState, state)
+			assert.Equal(t, tt.wantMessage, message)
+		})
+	}
+}
+
+func TestCheckDrift(t *testing.T) {
+	registry := providers.NewRegistry()
+	for _, provisioner := range []sdk.Provisioner{
+		&statusProvisioner{fakeProvisioner: fakeProvisioner{resourceType: "postgres"}, status: &sdk.ResourceStatus{Metadata: map[string]interface{}{"version": "14"}}},
+		&statusProvisioner{fakeProvisioner: fakeProvisioner{resourceType: "redis"}, err: errors.New("cluster unreachable")},
+	} {
+		require.NoError(t, registry.RegisterProvisioner(provisioner))
+	}
+	engine := &Engine{registry: registry}
+
+	state, message := engine.checkDrift(context.Background(), &database.ResourceInstance{
+		ResourceType: "postgres", Configuration: map[string]interface{}{"version": "15"},
+	})
+	assert.Equal(t, database.DriftStateDrifted, state)
+	assert.Equal(t, `version: desired "15", actual "14"`, message)
+
+	state, message = engine.checkDrift(context.Background(), &database.ResourceInstance{ResourceType: "redis"})
+	assert.Equal(t, database.DriftStateUnknown, state)
+	assert.Equal(t, "failed to get status: cluster unreachable", message)
+}
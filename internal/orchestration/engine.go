@@ -3,9 +3,12 @@ package orchestration
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"innominatus/internal/clock"
 	"innominatus/internal/database"
 	"innominatus/internal/events"
+	"innominatus/internal/faults"
 	"innominatus/internal/graph"
 	"innominatus/internal/logging"
 	"innominatus/internal/providers"
@@ -15,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	graphSDK "github.com/philipsahli/innominatus-graph/pkg/graph"
@@ -24,18 +28,23 @@ import (
 // Engine is the event-driven orchestration engine
 // It polls for pending resources and automatically triggers provider workflows
 type Engine struct {
-	db           *database.Database
-	registry     *providers.Registry
-	resolver     *Resolver
-	resourceRepo *database.ResourceRepository
-	workflowRepo *database.WorkflowRepository
-	workflowExec *workflow.WorkflowExecutor
-	graphAdapter *graph.Adapter
-	eventBus     events.EventBus
-	providersDir string
-	pollInterval time.Duration
-	stopChan     chan struct{}
-	logger       *logging.ZerologAdapter
+	db            *database.Database
+	registry      *providers.Registry
+	resolver      *Resolver
+	resourceRepo  *database.ResourceRepository
+	workflowRepo  *database.WorkflowRepository
+	workflowExec  *workflow.WorkflowExecutor
+	graphAdapter  *graph.Adapter
+	eventBus      events.EventBus
+	providersDir  string
+	pollInterval  time.Duration
+	driftInterval time.Duration
+	clock         clock.Clock
+	health        *ProviderHealthTracker
+	limiter       *ConcurrencyLimiter
+	faults        *faults.Injector
+	stopChan      chan struct{}
+	logger        *logging.ZerologAdapter
 }
 
 // NewEngine creates a new orchestration engine
@@ -49,17 +58,21 @@ func NewEngine(
 	providersDir string,
 ) *Engine {
 	return &Engine{
-		db:           db,
-		registry:     registry,
-		resolver:     NewResolver(registry),
-		workflowRepo: workflowRepo,
-		resourceRepo: resourceRepo,
-		workflowExec: workflowExec,
-		graphAdapter: graphAdapter,
-		providersDir: providersDir,
-		pollInterval: 5 * time.Second,
-		stopChan:     make(chan struct{}),
-		logger:       logging.NewStructuredLogger("orchestration"),
+		db:            db,
+		registry:      registry,
+		resolver:      NewResolver(registry),
+		workflowRepo:  workflowRepo,
+		resourceRepo:  resourceRepo,
+		workflowExec:  workflowExec,
+		graphAdapter:  graphAdapter,
+		providersDir:  providersDir,
+		pollInterval:  5 * time.Second,
+		driftInterval: DefaultDriftCheckInterval,
+		clock:         clock.Real(),
+		health:        NewProviderHealthTracker(),
+		limiter:       NewConcurrencyLimiter(ConcurrencyLimits{}),
+		stopChan:      make(chan struct{}),
+		logger:        logging.NewStructuredLogger("orchestration"),
 	}
 }
 
@@ -69,15 +82,51 @@ func (e *Engine) SetEventBus(bus events.EventBus) {
 	e.logger.Info("Event bus configured for orchestration engine")
 }
 
+// SetClock replaces the wall clock, e.g. with a fake clock in tests
+func (e *Engine) SetClock(c clock.Clock) {
+	e.clock = clock.OrReal(c)
+	e.resolver.SetClock(c)
+}
+
+// SetConcurrencyLimits bounds how many resources are provisioned at the same time
+// (provisioning in admin-config.yaml)
+func (e *Engine) SetConcurrencyLimits(limits ConcurrencyLimits) {
+	e.limiter.SetLimits(limits)
+	e.logger.InfoWithFields("Provisioning concurrency limits configured", map[string]interface{}{
+		"max_concurrent": limits.MaxConcurrent,
+		"resource_types": limits.ResourceTypes,
+		"providers":      limits.Providers,
+	})
+}
+
+// SetFaultInjector enables injecting provisioner faults for resilience testing. nil
+// turns injection off.
+func (e *Engine) SetFaultInjector(injector *faults.Injector) {
+	e.faults = injector
+}
+
+// ProviderHealth returns the per-provider provisioning health collected by the engine
+func (e *Engine) ProviderHealth() *ProviderHealthTracker {
+	return e.health
+}
+
 // Start begins the orchestration engine polling loop
 func (e *Engine) Start(ctx context.Context) {
 	e.logger.InfoWithFields("Starting orchestration engine", map[string]interface{}{
 		"poll_interval": e.pollInterval.String(),
 	})
 
-	ticker := time.NewTicker(e.pollInterval)
+	ticker := e.clock.NewTicker("orchestration-engine", e.pollInterval)
 	defer ticker.Stop()
 
+	// Drift detection runs on its own, much slower, interval
+	var driftTicks <-chan time.Time
+	if e.driftInterval > 0 {
+		driftTicker := e.clock.NewTicker("drift-detection", e.driftInterval)
+		defer driftTicker.Stop()
+		driftTicks = driftTicker.C()
+	}
+
 	// Initial poll on startup
 	e.poll(ctx)
 
@@ -89,8 +138,10 @@ func (e *Engine) Start(ctx context.Context) {
 		case <-e.stopChan:
 			e.logger.Info("Orchestration engine stopped")
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			e.poll(ctx)
+		case <-driftTicks:
+			e.detectDrift(ctx)
 		}
 	}
 }
@@ -117,7 +168,7 @@ func (e *Engine) pollPendingResources(ctx context.Context) {
 	// Query for pending resources without workflow execution
 	query := `
 		SELECT id, application_name, resource_name, resource_type, state,
-		       configuration, provider_id, workflow_execution_id,
+		       health_status, configuration, provider_id, workflow_execution_id,
 		       created_at, updated_at
 		FROM resource_instances
 		WHERE state IN ('requested', 'pending')
@@ -148,6 +199,7 @@ func (e *Engine) pollPendingResources(ctx context.Context) {
 			&resource.ResourceName,
 			&resource.ResourceType,
 			&resource.State,
+			&resource.HealthStatus,
 			&configJSON,
 			&providerID,
 			&workflowExecutionID,
@@ -195,69 +247,206 @@ func (e *Engine) pollPendingResources(ctx context.Context) {
 		"count": len(resources),
 	})
 
-	// Process each pending resource
+	// Resolve a provider for each pending resource; resources of applications whose
+	// upstreams are not ready yet stay pending until a later poll
+	upstreamsReady := make(map[string]bool)
+	var queue []*pendingProvision
 	for _, resource := range resources {
-		err := e.processResource(ctx, resource)
+		if !e.upstreamsReady(upstreamsReady, resource.ApplicationName) {
+			continue
+		}
+
+		provider, workflowMeta, err := e.resolveProvider(resource)
 		if err != nil {
-			e.logger.ErrorWithFields("Failed to process resource", map[string]interface{}{
-				"resource_id":   resource.ID,
-				"resource_name": resource.ResourceName,
-				"resource_type": resource.ResourceType,
-				"app_name":      resource.ApplicationName,
-				"error":         err.Error(),
-			})
+			e.failResource(resource, err)
+			continue
+		}
+		queue = append(queue, &pendingProvision{resource: resource, provider: provider, workflow: workflowMeta})
+	}
+
+	e.provisionQueued(ctx, queue)
+}
+
+// pendingProvision is a pending resource whose provider and workflow are resolved
+type pendingProvision struct {
+	resource *database.ResourceInstance
+	provider *sdk.Provider
+	workflow *sdk.WorkflowMetadata
+}
 
-			// Publish resource failed event
-			if e.eventBus != nil {
-				e.eventBus.Publish(events.NewEvent(
-					events.EventTypeResourceFailed,
-					resource.ApplicationName,
-					"orchestration-engine",
-					map[string]interface{}{
-						"resource_id":   resource.ID,
-						"resource_name": resource.ResourceName,
-						"resource_type": resource.ResourceType,
-						"error":         err.Error(),
-					},
-				))
+// provisionQueued provisions the queued resources in order, in parallel as far as the
+// concurrency limits allow. Resources over a limit are marked as waiting for capacity
+// and start as soon as a provision holding a matching slot finishes. It returns when
+// every queued resource has been provisioned.
+func (e *Engine) provisionQueued(ctx context.Context, queue []*pendingProvision) {
+	var wg sync.WaitGroup
+	finished := make(chan struct{}, len(queue))
+
+	for len(queue) > 0 {
+		var waiting []*pendingProvision
+		for _, item := range queue {
+			resourceType, providerName := item.resource.ResourceType, item.provider.Metadata.Name
+			ok, reason := e.limiter.TryAcquire(resourceType, providerName)
+			if !ok {
+				e.markWaitingForCapacity(item.resource, reason)
+				waiting = append(waiting, item)
+				continue
 			}
 
-			// Update resource to failed state
-			errorMsg := err.Error()
-			_ = e.resourceRepo.UpdateResourceInstanceState(
-				resource.ID,
-				database.ResourceStateFailed,
-				fmt.Sprintf("Failed to provision: %s", errorMsg),
-				"orchestration-engine",
-				nil,
-			)
+			if item.resource.HealthStatus == HealthWaitingForCapacity {
+				e.setResourceHealth(item.resource, "unknown", nil)
+			}
+
+			wg.Add(1)
+			go func(item *pendingProvision) {
+				defer wg.Done()
+				defer func() {
+					e.limiter.Release(resourceType, providerName)
+					finished <- struct{}{}
+				}()
+				if err := e.provisionResource(ctx, item.resource, item.provider, item.workflow); err != nil {
+					e.failResource(item.resource, err)
+				}
+			}(item)
+		}
+
+		queue = waiting
+		if len(queue) > 0 {
+			select {
+			case <-finished:
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			}
 		}
 	}
+
+	wg.Wait()
 }
 
-// processResource handles a single pending resource
-func (e *Engine) processResource(ctx context.Context, resource *database.ResourceInstance) error {
-	e.logger.InfoWithFields("Processing pending resource", map[string]interface{}{
+// markWaitingForCapacity records on a queued resource why its provisioning has not started
+func (e *Engine) markWaitingForCapacity(resource *database.ResourceInstance, reason string) {
+	if resource.HealthStatus == HealthWaitingForCapacity {
+		return
+	}
+	e.logger.InfoWithFields("Deferring provisioning until capacity is available", map[string]interface{}{
 		"resource_id":   resource.ID,
 		"resource_name": resource.ResourceName,
 		"resource_type": resource.ResourceType,
 		"app_name":      resource.ApplicationName,
+		"reason":        reason,
 	})
+	e.setResourceHealth(resource, HealthWaitingForCapacity, &reason)
+}
 
-	// Publish resource provisioning event
+// setResourceHealth updates the health status of a resource in the database and in memory
+func (e *Engine) setResourceHealth(resource *database.ResourceInstance, status string, message *string) {
+	resource.HealthStatus = status
+	if e.resourceRepo == nil {
+		return
+	}
+	if err := e.resourceRepo.UpdateResourceInstanceHealth(resource.ID, status, message); err != nil {
+		e.logger.WarnWithFields("Failed to update resource health", map[string]interface{}{
+			"resource_id": resource.ID,
+			"error":       err.Error(),
+		})
+	}
+}
+
+// failResource publishes a failure event and moves a resource that could not be
+// provisioned to the failed state
+func (e *Engine) failResource(resource *database.ResourceInstance, err error) {
+	e.logger.ErrorWithFields("Failed to process resource", map[string]interface{}{
+		"resource_id":   resource.ID,
+		"resource_name": resource.ResourceName,
+		"resource_type": resource.ResourceType,
+		"app_name":      resource.ApplicationName,
+		"error":         err.Error(),
+	})
+
+	// Publish resource failed event
 	if e.eventBus != nil {
 		e.eventBus.Publish(events.NewEvent(
-			events.EventTypeResourceProvisioning,
+			events.EventTypeResourceFailed,
 			resource.ApplicationName,
 			"orchestration-engine",
 			map[string]interface{}{
 				"resource_id":   resource.ID,
 				"resource_name": resource.ResourceName,
 				"resource_type": resource.ResourceType,
+				"error":         err.Error(),
 			},
 		))
 	}
 
+	// Update resource to failed state
+	errorMsg := err.Error()
+	_ = e.resourceRepo.UpdateResourceInstanceState(
+		resource.ID,
+		database.ResourceStateFailed,
+		fmt.Sprintf("Failed to provision: %s", errorMsg),
+		"orchestration-engine",
+		nil,
+	)
+}
+
+// upstreamsReady reports whether the applications appName declares in dependsOn are
+// deployed and healthy. Results are cached per poll in cache.
+func (e *Engine) upstreamsReady(cache map[string]bool, appName string) bool {
+	if ready, ok := cache[appName]; ok {
+		return ready
+	}
+	if e.db == nil {
+		return true
+	}
+
+	ready := true
+	if app, err := e.db.GetApplication(appName); err == nil && app.ScoreSpec != nil && len(app.ScoreSpec.DependsOn) > 0 {
+		var checker *UpstreamChecker
+		if e.resourceRepo != nil {
+			checker = NewUpstreamChecker(e.db, e.resourceRepo)
+		} else {
+			checker = NewUpstreamChecker(e.db, nil)
+		}
+		if blocking := BlockingUpstreams(checker.Check(app.ScoreSpec.DependsOn)); len(blocking) > 0 {
+			ready = false
+			e.logger.InfoWithFields("Deferring provisioning until upstream applications are ready", map[string]interface{}{
+				"app_name":   appName,
+				"upstream":   blocking[0].Application,
+				"reason":     blocking[0].Reason,
+				"blocked_by": len(blocking),
+			})
+		}
+	}
+	cache[appName] = ready
+	return ready
+}
+
+// providerPins returns the provider versions an application pinned, by provider name
+func (e *Engine) providerPins(appName string) (map[string]string, error) {
+	if e.db == nil {
+		return nil, nil
+	}
+	pins, err := e.db.ApplicationProviderPins(appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load provider version pins: %w", err)
+	}
+	versions := make(map[string]string, len(pins))
+	for provider, pin := range pins {
+		versions[provider] = pin.Version
+	}
+	return versions, nil
+}
+
+// resolveProvider determines the provider and workflow that provision a pending resource
+func (e *Engine) resolveProvider(resource *database.ResourceInstance) (*sdk.Provider, *sdk.WorkflowMetadata, error) {
+	e.logger.InfoWithFields("Processing pending resource", map[string]interface{}{
+		"resource_id":   resource.ID,
+		"resource_name": resource.ResourceName,
+		"resource_type": resource.ResourceType,
+		"app_name":      resource.ApplicationName,
+	})
+
 	// Step 1: Determine operation (create, update, delete)
 	operation := "create" // Default operation
 	if resource.DesiredOperation != nil && *resource.DesiredOperation != "" {
@@ -277,10 +466,15 @@ func (e *Engine) processResource(ctx context.Context, resource *database.Resourc
 		"tags":          tags,
 	})
 
+	// Applications that pinned provider versions keep running them
+	pins, err := e.providerPins(resource.ApplicationName)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Step 2: Check for explicit workflow override
 	var provider *sdk.Provider
 	var workflowMeta *sdk.WorkflowMetadata
-	var err error
 
 	if resource.WorkflowOverride != nil && *resource.WorkflowOverride != "" {
 		// User explicitly specified which workflow to use
@@ -290,31 +484,61 @@ func (e *Engine) processResource(ctx context.Context, resource *database.Resourc
 		})
 
 		// Still need to resolve provider for this resource type
-		provider, _, err = e.resolver.ResolveWorkflowForOperation(resource.ResourceType, operation, tags)
+		provider, _, err = e.resolver.ResolvePinnedWorkflow(resource.ResourceType, operation, tags, pins)
 		if err != nil {
-			return fmt.Errorf("failed to resolve provider for workflow override: %w", err)
+			return nil, nil, fmt.Errorf("failed to resolve provider for workflow override: %w", err)
 		}
 
 		// Find the specified workflow in the provider
 		workflowMeta = e.resolver.FindWorkflowByName(provider, workflowName)
 		if workflowMeta == nil {
-			return fmt.Errorf("workflow override '%s' not found in provider '%s'", workflowName, provider.Metadata.Name)
+			return nil, nil, fmt.Errorf("workflow override '%s' not found in provider '%s'", workflowName, provider.Metadata.Name)
 		}
 	} else {
 		// Standard resolution based on operation
-		provider, workflowMeta, err = e.resolver.ResolveWorkflowForOperation(resource.ResourceType, operation, tags)
+		provider, workflowMeta, err = e.resolver.ResolvePinnedWorkflow(resource.ResourceType, operation, tags, pins)
 		if err != nil {
-			return fmt.Errorf("failed to resolve provider: %w", err)
+			return nil, nil, fmt.Errorf("failed to resolve provider: %w", err)
 		}
 	}
 
 	e.logger.InfoWithFields("Resolved provider for resource", map[string]interface{}{
-		"resource_type": resource.ResourceType,
-		"operation":     operation,
-		"provider_name": provider.Metadata.Name,
-		"workflow_name": workflowMeta.Name,
+		"resource_type":    resource.ResourceType,
+		"operation":        operation,
+		"provider_name":    provider.Metadata.Name,
+		"provider_version": provider.Metadata.Version,
+		"pinned":           pins[provider.Metadata.Name] != "",
+		"workflow_name":    workflowMeta.Name,
 	})
 
+	if deprecation := provider.ResourceTypeDeprecation(resource.ResourceType); deprecation != nil {
+		e.logger.WarnWithFields(deprecation.Notice(resource.ResourceType), map[string]interface{}{
+			"resource_id":   resource.ID,
+			"resource_type": resource.ResourceType,
+			"provider_name": provider.Metadata.Name,
+		})
+	}
+
+	return provider, workflowMeta, nil
+}
+
+// provisionResource runs the provider workflow of a pending resource and records the
+// workflow execution on it
+func (e *Engine) provisionResource(ctx context.Context, resource *database.ResourceInstance, provider *sdk.Provider, workflowMeta *sdk.WorkflowMetadata) error {
+	// Publish resource provisioning event
+	if e.eventBus != nil {
+		e.eventBus.Publish(events.NewEvent(
+			events.EventTypeResourceProvisioning,
+			resource.ApplicationName,
+			"orchestration-engine",
+			map[string]interface{}{
+				"resource_id":   resource.ID,
+				"resource_name": resource.ResourceName,
+				"resource_type": resource.ResourceType,
+			},
+		))
+	}
+
 	// Publish provider resolved event
 	if e.eventBus != nil {
 		e.eventBus.Publish(events.NewEvent(
@@ -331,6 +555,9 @@ func (e *Engine) processResource(ctx context.Context, resource *database.Resourc
 		))
 	}
 
+	// Preview updates with provisioners that support planning
+	e.planUpdate(ctx, resource)
+
 	// Step 2: Load the workflow YAML
 	workflowDef, err := e.loadWorkflowFromProvider(provider, workflowMeta)
 	if err != nil {
@@ -341,12 +568,35 @@ func (e *Engine) processResource(ctx context.Context, resource *database.Resourc
 	workflowInputs := e.buildWorkflowInputs(resource, workflowDef)
 
 	// Step 4: Execute workflow
-	err = e.workflowExec.ExecuteWorkflowWithName(
-		resource.ApplicationName,
-		workflowMeta.Name,
-		*workflowDef,
-		workflowInputs,
-	)
+	startedAt := e.clock.Now()
+	target := faults.Target{
+		Scope:        faults.ScopeProvisioner,
+		App:          resource.ApplicationName,
+		Provider:     provider.Metadata.Name,
+		ResourceType: resource.ResourceType,
+		Workflow:     workflowMeta.Name,
+	}
+	err = e.faults.Run(ctx, target, func(f faults.Fault) {
+		e.logger.WarnWithFields("Injecting fault into provisioning", map[string]interface{}{
+			"fault_id":      f.ID,
+			"kind":          string(f.Kind),
+			"resource_id":   resource.ID,
+			"resource_name": resource.ResourceName,
+			"provider_name": provider.Metadata.Name,
+		})
+	}, func() error {
+		// Steps get the cloud credentials granted to the provider and resource type.
+		// Stopping the engine does not cancel a running provisioning workflow.
+		runCtx := workflow.WithProvisioning(context.WithoutCancel(ctx), provider.Metadata.Name, resource.ResourceType)
+		return e.workflowExec.ExecuteWorkflowWithContext(
+			runCtx,
+			resource.ApplicationName,
+			workflowMeta.Name,
+			*workflowDef,
+			workflowInputs,
+		)
+	})
+	e.health.Record(provider.Metadata.Name, e.clock.Now(), e.clock.Since(startedAt), err)
 	if err != nil {
 		return fmt.Errorf("failed to execute workflow: %w", err)
 	}
@@ -443,7 +693,7 @@ func (e *Engine) updateResourceWithProvisioningInfo(
 		database.ResourceStateProvisioning,
 		providerID,
 		workflowExecutionID,
-		time.Now(),
+		e.clock.Now(),
 		resource.ID,
 	)
 
@@ -510,18 +760,19 @@ func (e *Engine) updateGraphWithProvider(
 	return nil
 }
 
-// loadWorkflowFromProvider loads a workflow YAML file from a provider
+// loadWorkflowFromProvider loads a workflow YAML file from a provider. Files cached when
+// the provider was registered are preferred, so a cleaned-up clone does not fail the run.
 func (e *Engine) loadWorkflowFromProvider(provider *sdk.Provider, workflowMeta *sdk.WorkflowMetadata) (*types.Workflow, error) {
-	// Construct workflow file path
-	// Workflow file path is relative to provider directory
-	providerDir := filepath.Join(e.providersDir, provider.Metadata.Name)
-	workflowPath := filepath.Join(providerDir, workflowMeta.File)
-
-	// Read workflow file
-	// #nosec G304 -- workflow path is constructed from validated provider config
-	data, err := os.ReadFile(workflowPath)
+	var data []byte
+	err := providers.ErrAssetNotCached
+	if e.registry != nil {
+		data, err = e.registry.WorkflowAsset(provider, workflowMeta.File)
+	}
+	if errors.Is(err, providers.ErrAssetNotCached) {
+		data, err = e.readProviderWorkflow(provider, workflowMeta)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read workflow file %s: %w", workflowPath, err)
+		return nil, err
 	}
 
 	// Parse workflow YAML
@@ -533,6 +784,23 @@ func (e *Engine) loadWorkflowFromProvider(provider *sdk.Provider, workflowMeta *
 	return &workflow, nil
 }
 
+// readProviderWorkflow reads a workflow file of a provider that has no cached assets
+func (e *Engine) readProviderWorkflow(provider *sdk.Provider, workflowMeta *sdk.WorkflowMetadata) ([]byte, error) {
+	// Workflow file path is relative to the directory provider.yaml was loaded from
+	providerDir := provider.Dir
+	if providerDir == "" {
+		providerDir = filepath.Join(e.providersDir, provider.Metadata.Name)
+	}
+	workflowPath := filepath.Join(providerDir, workflowMeta.File)
+
+	// #nosec G304 -- workflow path is constructed from validated provider config
+	data, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow file %s: %w", workflowPath, err)
+	}
+	return data, nil
+}
+
 // recoverOrphanedResources recovers resources stuck in provisioning state without workflow_execution_id
 // This can happen if a resource was transitioned to provisioning but the workflow never started
 func (e *Engine) recoverOrphanedResources(ctx context.Context) {
@@ -791,35 +1059,17 @@ func (e *Engine) pollProvisioningResources(ctx context.Context) {
 					"resource_id": rws.resourceID,
 					"error":       err.Error(),
 				})
-			} else {
-				// Load workflow definition to get outputs
-				// We need to find the provider and workflow for this execution
-				// For now, we'll try to load the workflow by name from all providers
-				workflowDef, err := e.findWorkflowDefinition(execution.ApplicationName, execution.WorkflowName)
-				if err != nil {
-					e.logger.WarnWithFields("Failed to load workflow definition for hint conversion", map[string]interface{}{
-						"resource_id":   rws.resourceID,
-						"workflow_name": execution.WorkflowName,
-						"error":         err.Error(),
+			} else if hints := e.executionHints(execution); len(hints) > 0 {
+				if err := e.resourceRepo.UpdateResourceHints(rws.resourceID, hints); err != nil {
+					e.logger.WarnWithFields("Failed to update resource hints", map[string]interface{}{
+						"resource_id": rws.resourceID,
+						"error":       err.Error(),
+					})
+				} else {
+					e.logger.InfoWithFields("Updated resource hints from workflow outputs", map[string]interface{}{
+						"resource_id": rws.resourceID,
+						"hint_count":  len(hints),
 					})
-				} else if len(workflowDef.Outputs) > 0 {
-					// Convert workflow outputs to hints
-					hints := e.convertWorkflowOutputsToHints(workflowDef.Outputs)
-
-					if len(hints) > 0 {
-						// Update resource hints
-						if err := e.resourceRepo.UpdateResourceHints(rws.resourceID, hints); err != nil {
-							e.logger.WarnWithFields("Failed to update resource hints", map[string]interface{}{
-								"resource_id": rws.resourceID,
-								"error":       err.Error(),
-							})
-						} else {
-							e.logger.InfoWithFields("Updated resource hints from workflow outputs", map[string]interface{}{
-								"resource_id": rws.resourceID,
-								"hint_count":  len(hints),
-							})
-						}
-					}
 				}
 			}
 		}
@@ -866,16 +1116,52 @@ func formatLabel(key string) string {
 	return strings.Join(words, " ")
 }
 
+// executionHints returns the resource hints for a completed workflow execution. Outputs
+// recorded with the execution are used as declared; workflows that recorded none fall
+// back to the outputs of their definition.
+func (e *Engine) executionHints(execution *database.WorkflowExecution) []database.ResourceHint {
+	if len(execution.Outputs) > 0 {
+		hints := make([]database.ResourceHint, 0, len(execution.Outputs))
+		for _, output := range execution.Outputs {
+			hint := output.Hint()
+			hint.Label = formatLabel(output.Name)
+			hints = append(hints, hint)
+		}
+		return hints
+	}
+
+	// We need to find the provider and workflow for this execution
+	// For now, we'll try to load the workflow by name from all providers
+	workflowDef, err := e.findWorkflowDefinition(execution.ApplicationName, execution.WorkflowName)
+	if err != nil {
+		e.logger.WarnWithFields("Failed to load workflow definition for hint conversion", map[string]interface{}{
+			"workflow_name": execution.WorkflowName,
+			"error":         err.Error(),
+		})
+		return nil
+	}
+
+	return e.convertWorkflowOutputsToHints(workflowDef.Outputs)
+}
+
 // convertWorkflowOutputsToHints converts workflow outputs to resource hints
-// Maps output keys to appropriate hint types based on naming conventions
-func (e *Engine) convertWorkflowOutputsToHints(outputs map[string]string) []database.ResourceHint {
+// Uses the declared output type, otherwise maps output keys to hint types based on naming conventions
+func (e *Engine) convertWorkflowOutputsToHints(outputs map[string]types.WorkflowOutput) []database.ResourceHint {
 	var hints []database.ResourceHint
 
-	for key, value := range outputs {
+	for key, output := range outputs {
+		value := output.Value
 		if value == "" {
 			continue // Skip empty values
 		}
 
+		if output.Type != "" {
+			hint := database.WorkflowOutput{Name: key, Type: output.Type, Value: value, Query: output.Query}.Hint()
+			hint.Label = formatLabel(key)
+			hints = append(hints, hint)
+			continue
+		}
+
 		var hintType string
 		label := formatLabel(key)
 
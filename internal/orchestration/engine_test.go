@@ -121,3 +121,58 @@ func TestEngineCreation(t *testing.T) {
 		t.Error("Expected logger to be initialized")
 	}
 }
+
+func TestExecutionHints(t *testing.T) {
+	engine := &Engine{}
+
+	execution := &database.WorkflowExecution{
+		Outputs: []database.WorkflowOutput{
+			{Name: "external_endpoint", Type: "url", Value: "https://shop.example.com"},
+			{Name: "db_password", Type: "secret_ref", Value: "vault://secret/data/shop#password"},
+		},
+	}
+
+	hints := engine.executionHints(execution)
+	want := []database.ResourceHint{
+		{Type: "url", Label: "External Endpoint", Value: "https://shop.example.com", Icon: "external-link"},
+		{Type: "secret_ref", Label: "Db Password", Value: "vault://secret/data/shop#password", Icon: "lock"},
+	}
+	if len(hints) != len(want) {
+		t.Fatalf("Expected %d hints, got %d", len(want), len(hints))
+	}
+	for i := range want {
+		if hints[i] != want[i] {
+			t.Errorf("hints[%d] = %+v, want %+v", i, hints[i], want[i])
+		}
+	}
+}
+
+func TestConvertWorkflowOutputsToHints(t *testing.T) {
+	engine := &Engine{}
+
+	tests := []struct {
+		name   string
+		output types.WorkflowOutput
+		want   database.ResourceHint
+	}{
+		{
+			name:   "grafana_dashboard",
+			output: types.WorkflowOutput{Value: "https://grafana.example.com/d/shop"},
+			want:   database.ResourceHint{Type: "dashboard", Label: "Grafana Dashboard", Value: "https://grafana.example.com/d/shop"},
+		},
+		{
+			name:   "namespace",
+			output: types.WorkflowOutput{Value: "shop", Type: "namespace"},
+			want:   database.ResourceHint{Type: "namespace", Label: "Namespace", Value: "shop", Icon: "terminal"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hints := engine.convertWorkflowOutputsToHints(map[string]types.WorkflowOutput{tt.name: tt.output})
+			if len(hints) != 1 || hints[0] != tt.want {
+				t.Errorf("convertWorkflowOutputsToHints() = %+v, want %+v", hints, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+
+	"innominatus/internal/database"
+	"innominatus/internal/events"
+	"innominatus/pkg/sdk"
+)
+
+// PlanResource previews applying desired to resource with the provisioner registered for
+// its type. It returns a nil plan when no provisioner for the type implements sdk.Planner.
+func (r *Resolver) PlanResource(ctx context.Context, resource *sdk.Resource, desired map[string]interface{}) (*sdk.Plan, error) {
+	provisioner, err := r.registry.GetProvisioner(resource.ResourceType)
+	if err != nil {
+		return nil, nil // Workflow-only resource types have nothing to plan with
+	}
+	planner, ok := provisioner.(sdk.Planner)
+	if !ok {
+		return nil, nil
+	}
+
+	plan, err := planner.Plan(ctx, resource, sdk.NewMapConfig(desired))
+	if err != nil {
+		return nil, fmt.Errorf("provisioner %s failed to plan %s: %w", provisioner.Name(), resource.ResourceName, err)
+	}
+	return plan, nil
+}
+
+// SDKResource converts a resource instance to the form passed to provisioners
+func SDKResource(instance *database.ResourceInstance) *sdk.Resource {
+	resource := &sdk.Resource{
+		ID:               instance.ID,
+		ApplicationName:  instance.ApplicationName,
+		ResourceName:     instance.ResourceName,
+		ResourceType:     instance.ResourceType,
+		State:            sdk.ResourceState(instance.State),
+		HealthStatus:     instance.HealthStatus,
+		Configuration:    sdk.NewMapConfig(instance.Configuration),
+		ProviderMetadata: instance.ProviderMetadata,
+		CreatedAt:        instance.CreatedAt,
+		UpdatedAt:        instance.UpdatedAt,
+	}
+	if instance.ProviderID != nil {
+		resource.ProviderID = *instance.ProviderID
+	}
+	if instance.ErrorMessage != nil {
+		resource.ErrorMessage = *instance.ErrorMessage
+	}
+	return resource
+}
+
+// planUpdate asks the provisioner of an updated resource for a plan and publishes its diff
+// before the update workflow runs. Planning failures do not block the update.
+func (e *Engine) planUpdate(ctx context.Context, resource *database.ResourceInstance) {
+	if resource.DesiredOperation == nil || *resource.DesiredOperation != "update" {
+		return
+	}
+
+	plan, err := e.resolver.PlanResource(ctx, SDKResource(resource), resource.Configuration)
+	if err != nil {
+		e.logger.WarnWithFields("Failed to plan resource update", map[string]interface{}{
+			"resource_id": resource.ID,
+			"error":       err.Error(),
+		})
+		return
+	}
+	if plan == nil {
+		return
+	}
+
+	e.logger.InfoWithFields("Planned resource update", map[string]interface{}{
+		"resource_id":   resource.ID,
+		"resource_name": resource.ResourceName,
+		"action":        plan.Action,
+		"changes":       len(plan.Changes),
+		"diff":          plan.String(),
+	})
+	if e.eventBus != nil {
+		e.eventBus.Publish(events.NewEvent(
+			events.EventTypeResourcePlanned,
+			resource.ApplicationName,
+			"orchestration-engine",
+			map[string]interface{}{
+				"resource_id":   resource.ID,
+				"resource_name": resource.ResourceName,
+				"resource_type": resource.ResourceType,
+				"plan":          plan,
+				"diff":          plan.String(),
+			},
+		))
+	}
+}
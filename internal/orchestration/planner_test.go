@@ -0,0 +1,86 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"innominatus/internal/database"
+	"innominatus/internal/providers"
+	"innominatus/pkg/sdk"
+)
+
+// fakeProvisioner provisions nothing
+type fakeProvisioner struct {
+	resourceType string
+}
+
+func (p *fakeProvisioner) Name() string    { return "fake-" + p.resourceType }
+func (p *fakeProvisioner) Type() string    { return p.resourceType }
+func (p *fakeProvisioner) Version() string { return "1.0.0" }
+func (p *fakeProvisioner) Provision(ctx context.Context, resource *sdk.Resource, config sdk.Config) error {
+	return nil
+}
+func (p *fakeProvisioner) Deprovision(ctx context.Context, resource *sdk.Resource) error { return nil }
+func (p *fakeProvisioner) GetStatus(ctx context.Context, resource *sdk.Resource) (*sdk.ResourceStatus, error) {
+	return &sdk.ResourceStatus{}, nil
+}
+func (p *fakeProvisioner) GetHints(ctx context.Context, resource *sdk.Resource) ([]sdk.Hint, error) {
+	return nil, nil
+}
+
+// fakePlanner plans by diffing the stored configuration
+type fakePlanner struct {
+	fakeProvisioner
+	err error
+}
+
+func (p *fakePlanner) Plan(ctx context.Context, resource *sdk.Resource, config sdk.Config) (*sdk.Plan, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return sdk.NewPlan(resource.Configuration, config), nil
+}
+
+func TestResolverPlanResource(t *testing.T) {
+	registry := providers.NewRegistry()
+	for _, provisioner := range []sdk.Provisioner{
+		&fakePlanner{fakeProvisioner: fakeProvisioner{resourceType: "postgres"}},
+		&fakePlanner{fakeProvisioner: fakeProvisioner{resourceType: "redis"}, err: errors.New("cluster unreachable")},
+		&fakeProvisioner{resourceType: "s3"},
+	} {
+		if err := registry.RegisterProvisioner(provisioner); err != nil {
+			t.Fatalf("Failed to register provisioner: %v", err)
+		}
+	}
+	resolver := NewResolver(registry)
+	desired := map[string]interface{}{"size": "20Gi"}
+
+	instance := &database.ResourceInstance{
+		ID:              7,
+		ApplicationName: "shop",
+		ResourceName:    "db",
+		ResourceType:    "postgres",
+		Configuration:   map[string]interface{}{"size": "10Gi"},
+	}
+	plan, err := resolver.PlanResource(context.Background(), SDKResource(instance), desired)
+	if err != nil {
+		t.Fatalf("PlanResource() error = %v", err)
+	}
+	if plan == nil || plan.Action != sdk.PlanActionUpdate || len(plan.Changes) != 1 || plan.Changes[0].Path != "size" {
+		t.Errorf("Expected size update, got %+v", plan)
+	}
+
+	_, err = resolver.PlanResource(context.Background(), &sdk.Resource{ResourceName: "cache", ResourceType: "redis"}, desired)
+	if err == nil || !strings.Contains(err.Error(), "cluster unreachable") {
+		t.Errorf("Expected planner error, got %v", err)
+	}
+
+	for _, resourceType := range []string{"s3", "namespace"} {
+		plan, err := resolver.PlanResource(context.Background(), &sdk.Resource{ResourceType: resourceType}, desired)
+		if plan != nil || err != nil {
+			t.Errorf("Expected no plan for %s, got %+v, %v", resourceType, plan, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package orchestration
+
+import (
+	"innominatus/internal/metrics"
+	"math"
+	"sync"
+	"time"
+)
+
+// Provider health statuses
+const (
+	ProviderStatusUnknown  = "unknown"  // No provisioning attempts since startup
+	ProviderStatusHealthy  = "healthy"  // Recent provisions succeed
+	ProviderStatusDegraded = "degraded" // Some recent provisions failed
+	ProviderStatusFailing  = "failing"  // Consecutive provisions failed
+)
+
+const (
+	// healthWindow is the number of recent outcomes used for error rate and latency
+	healthWindow = 20
+	// failingThreshold consecutive failures mark a provider as failing
+	failingThreshold = 3
+	// degradedErrorRate in the recent window marks a provider as degraded
+	degradedErrorRate = 0.2
+)
+
+// ProviderHealth summarises provisioning outcomes of one provider
+type ProviderHealth struct {
+	Provider            string     `json:"provider"`
+	Status              string     `json:"status"`
+	TotalProvisions     int64      `json:"total_provisions"`
+	Succeeded           int64      `json:"succeeded"`
+	Failed              int64      `json:"failed"`
+	ErrorRate           float64    `json:"error_rate"`        // Over all provisions since startup
+	RecentErrorRate     float64    `json:"recent_error_rate"` // Over the last 20 provisions
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	AvgLatencyMs        float64    `json:"avg_latency_ms"` // Over the last 20 provisions
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+type providerOutcome struct {
+	failed  bool
+	latency time.Duration
+}
+
+type providerRecord struct {
+	health ProviderHealth
+	recent []providerOutcome
+}
+
+// ProviderHealthTracker collects provisioning outcomes per provider
+type ProviderHealthTracker struct {
+	mu      sync.RWMutex
+	records map[string]*providerRecord
+}
+
+// NewProviderHealthTracker creates an empty tracker
+func NewProviderHealthTracker() *ProviderHealthTracker {
+	return &ProviderHealthTracker{records: make(map[string]*providerRecord)}
+}
+
+// Record stores the outcome of a provisioning workflow that finished at the given time
+// and updates Prometheus metrics
+func (t *ProviderHealthTracker) Record(provider string, at time.Time, latency time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record, ok := t.records[provider]
+	if !ok {
+		record = &providerRecord{health: ProviderHealth{Provider: provider}}
+		t.records[provider] = record
+	}
+
+	h := &record.health
+	h.TotalProvisions++
+	if err != nil {
+		h.Failed++
+		h.ConsecutiveFailures++
+		h.LastFailureAt = &at
+		h.LastError = err.Error()
+	} else {
+		h.Succeeded++
+		h.ConsecutiveFailures = 0
+		h.LastSuccessAt = &at
+	}
+
+	record.recent = append(record.recent, providerOutcome{failed: err != nil, latency: latency})
+	if len(record.recent) > healthWindow {
+		record.recent = record.recent[len(record.recent)-healthWindow:]
+	}
+
+	metrics.GetGlobal().RecordProviderProvision(provider, err == nil, latency)
+}
+
+// Health returns the health of a provider; providers without attempts report status unknown
+func (t *ProviderHealthTracker) Health(provider string) ProviderHealth {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	record, ok := t.records[provider]
+	if !ok {
+		return ProviderHealth{Provider: provider, Status: ProviderStatusUnknown}
+	}
+	return record.summary()
+}
+
+func (r *providerRecord) summary() ProviderHealth {
+	h := r.health
+
+	if h.TotalProvisions > 0 {
+		h.ErrorRate = round2(float64(h.Failed) / float64(h.TotalProvisions))
+	}
+
+	if len(r.recent) > 0 {
+		failed := 0
+		var total time.Duration
+		for _, o := range r.recent {
+			if o.failed {
+				failed++
+			}
+			total += o.latency
+		}
+		h.RecentErrorRate = round2(float64(failed) / float64(len(r.recent)))
+		h.AvgLatencyMs = round2(float64(total/time.Duration(len(r.recent))) / float64(time.Millisecond))
+	}
+
+	switch {
+	case h.TotalProvisions == 0:
+		h.Status = ProviderStatusUnknown
+	case h.ConsecutiveFailures >= failingThreshold:
+		h.Status = ProviderStatusFailing
+	case h.RecentErrorRate >= degradedErrorRate:
+		h.Status = ProviderStatusDegraded
+	default:
+		h.Status = ProviderStatusHealthy
+	}
+	return h
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
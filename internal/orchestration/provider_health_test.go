@@ -0,0 +1,74 @@
+package orchestration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderHealthTracker(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	failure := errors.New("terraform apply failed")
+
+	tests := []struct {
+		name            string
+		outcomes        []error
+		wantStatus      string
+		wantErrorRate   float64
+		wantConsecutive int
+	}{
+		{"no attempts", nil, ProviderStatusUnknown, 0, 0},
+		{"all succeed", []error{nil, nil, nil}, ProviderStatusHealthy, 0, 0},
+		{"occasional failure", []error{nil, nil, nil, nil, failure, nil, nil, nil, nil, nil}, ProviderStatusHealthy, 0.1, 0},
+		{"frequent failures", []error{nil, failure, nil, failure, nil}, ProviderStatusDegraded, 0.4, 0},
+		{"consecutive failures", []error{nil, failure, failure, failure}, ProviderStatusFailing, 0.75, 3},
+		{"recovered", []error{failure, failure, failure, nil}, ProviderStatusDegraded, 0.75, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewProviderHealthTracker()
+			for i, err := range tt.outcomes {
+				tracker.Record("database-team", start.Add(time.Duration(i)*time.Minute), 100*time.Millisecond, err)
+			}
+
+			health := tracker.Health("database-team")
+			assert.Equal(t, "database-team", health.Provider)
+			assert.Equal(t, tt.wantStatus, health.Status)
+			assert.Equal(t, tt.wantErrorRate, health.ErrorRate)
+			assert.Equal(t, tt.wantConsecutive, health.ConsecutiveFailures)
+			assert.Equal(t, int64(len(tt.outcomes)), health.TotalProvisions)
+		})
+	}
+}
+
+func TestProviderHealthTrackerDetails(t *testing.T) {
+	tracker := NewProviderHealthTracker()
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tracker.Record("database-team", start, 100*time.Millisecond, nil)
+	tracker.Record("database-team", start.Add(time.Minute), 300*time.Millisecond, errors.New("timeout"))
+
+	health := tracker.Health("database-team")
+	require.NotNil(t, health.LastSuccessAt)
+	require.NotNil(t, health.LastFailureAt)
+	assert.Equal(t, start, *health.LastSuccessAt)
+	assert.Equal(t, start.Add(time.Minute), *health.LastFailureAt)
+	assert.Equal(t, "timeout", health.LastError)
+	assert.Equal(t, 200.0, health.AvgLatencyMs)
+
+	// Only the last 20 outcomes count towards the recent error rate and latency
+	for i := 0; i < 20; i++ {
+		tracker.Record("database-team", start.Add(time.Hour), 50*time.Millisecond, nil)
+	}
+	health = tracker.Health("database-team")
+	assert.Equal(t, 0.0, health.RecentErrorRate)
+	assert.Equal(t, 50.0, health.AvgLatencyMs)
+	assert.Equal(t, ProviderStatusHealthy, health.Status)
+
+	// Other providers are unaffected
+	assert.Equal(t, ProviderStatusUnknown, tracker.Health("storage-team").Status)
+}
@@ -3,6 +3,7 @@ package orchestration
 import (
 	"fmt"
 
+	"innominatus/internal/clock"
 	"innominatus/internal/providers"
 	"innominatus/pkg/sdk"
 )
@@ -10,15 +11,35 @@ import (
 // Resolver matches resource types to providers and their workflows
 type Resolver struct {
 	registry *providers.Registry
+	clock    clock.Clock // Decides whether the grace period of deprecated resource types ended
 }
 
 // NewResolver creates a new resolver instance
 func NewResolver(registry *providers.Registry) *Resolver {
 	return &Resolver{
 		registry: registry,
+		clock:    clock.Real(),
 	}
 }
 
+// SetClock replaces the wall clock, e.g. with a fake clock in tests
+func (r *Resolver) SetClock(c clock.Clock) {
+	r.clock = clock.OrReal(c)
+}
+
+// DeprecationNotice returns the deprecation notice of a resource type that still
+// resolves, or "" if the type is not deprecated or does not resolve
+func (r *Resolver) DeprecationNotice(resourceType string) string {
+	provider, _, err := r.ResolveProviderForResource(resourceType)
+	if err != nil {
+		return ""
+	}
+	if deprecation := provider.ResourceTypeDeprecation(resourceType); deprecation != nil {
+		return deprecation.Notice(resourceType)
+	}
+	return ""
+}
+
 // ResolveProviderForResource finds the provider and workflow for a given resource type
 // Returns the provider, provisioner workflow, and any error
 // Defaults to CREATE operation for backward compatibility
@@ -35,6 +56,24 @@ func (r *Resolver) ResolveProviderForResource(resourceType string) (*sdk.Provide
 //
 // Returns the provider, workflow metadata, and any error
 func (r *Resolver) ResolveWorkflowForOperation(resourceType, operation string, tags []string) (*sdk.Provider, *sdk.WorkflowMetadata, error) {
+	return r.ResolvePinnedWorkflow(resourceType, operation, tags, nil)
+}
+
+// ResolvePinnedWorkflow is ResolveWorkflowForOperation for an application that pinned
+// provider versions: pins maps provider names to the version to use instead of the
+// registered one. A pinned version that is no longer loaded fails the resolution
+// rather than silently running the newer provider.
+func (r *Resolver) ResolvePinnedWorkflow(resourceType, operation string, tags []string, pins map[string]string) (*sdk.Provider, *sdk.WorkflowMetadata, error) {
+	provider, err := r.ResolvePinnedProvider(resourceType, pins)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.resolveProviderWorkflow(provider, resourceType, operation, tags)
+}
+
+// ResolvePinnedProvider finds the provider for a resource type, in the version pinned
+// in pins if the provider is pinned
+func (r *Resolver) ResolvePinnedProvider(resourceType string, pins map[string]string) (*sdk.Provider, error) {
 	allProviders := r.registry.ListProviders()
 
 	var matchedProviders []*sdk.Provider
@@ -48,7 +87,7 @@ func (r *Resolver) ResolveWorkflowForOperation(resourceType, operation string, t
 
 	// Error if no provider found
 	if len(matchedProviders) == 0 {
-		return nil, nil, fmt.Errorf("no provider found for resource type '%s'", resourceType)
+		return nil, fmt.Errorf("no provider found for resource type '%s'", resourceType)
 	}
 
 	// Error if multiple providers claim the same resource type
@@ -57,11 +96,31 @@ func (r *Resolver) ResolveWorkflowForOperation(resourceType, operation string, t
 		for i, p := range matchedProviders {
 			providerNames[i] = p.Metadata.Name
 		}
-		return nil, nil, fmt.Errorf("multiple providers claim resource type '%s': %v (disambiguation needed)", resourceType, providerNames)
+		return nil, fmt.Errorf("multiple providers claim resource type '%s': %v (disambiguation needed)", resourceType, providerNames)
 	}
 
 	// Found exactly one provider
 	provider := matchedProviders[0]
+	if version, pinned := pins[provider.Metadata.Name]; pinned && version != provider.Metadata.Version {
+		pinnedProvider, err := r.registry.GetProviderVersion(provider.Metadata.Name, version)
+		if err != nil {
+			return nil, fmt.Errorf("provider version pin: %w", err)
+		}
+		provider = pinnedProvider
+	}
+	return provider, nil
+}
+
+// resolveProviderWorkflow finds the workflow of provider for a resource type and operation
+func (r *Resolver) resolveProviderWorkflow(provider *sdk.Provider, resourceType, operation string, tags []string) (*sdk.Provider, *sdk.WorkflowMetadata, error) {
+	// Deprecated names keep resolving until their grace period ends
+	if deprecation := provider.ResourceTypeDeprecation(resourceType); deprecation != nil && deprecation.Expired(r.clock.Now()) {
+		err := fmt.Errorf("resource type '%s' of provider '%s' was removed after %s", resourceType, provider.Metadata.Name, deprecation.RemoveAfter)
+		if deprecation.Replacement != "" {
+			err = fmt.Errorf("%w; use '%s' instead", err, deprecation.Replacement)
+		}
+		return nil, nil, err
+	}
 
 	// Check if provider supports the requested operation
 	if !provider.SupportsOperation(resourceType, operation) {
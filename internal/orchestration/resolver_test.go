@@ -1,8 +1,13 @@
 package orchestration
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"innominatus/internal/clock"
 	"innominatus/internal/providers"
 	"innominatus/pkg/sdk"
 )
@@ -258,3 +263,122 @@ func TestProviderGetProvisionerWorkflow(t *testing.T) {
 		})
 	}
 }
+
+func TestResolverDeprecatedResourceType(t *testing.T) {
+	registry := providers.NewRegistry()
+	dbProvider := &sdk.Provider{
+		APIVersion: "v1",
+		Kind:       "Provider",
+		Metadata:   sdk.ProviderMetadata{Name: "database-team", Version: "1.0.0"},
+		Capabilities: sdk.ProviderCapabilities{
+			ResourceTypeCapabilities: []sdk.ResourceTypeCapability{
+				{
+					Type:       "postgresql",
+					Operations: map[string]sdk.OperationWorkflow{"create": {Workflow: "provision-postgres"}},
+				},
+				{
+					Type:       "postgres",
+					AliasFor:   "postgresql",
+					Deprecated: &sdk.ResourceTypeDeprecation{Message: "renamed", RemoveAfter: "2026-12-31"},
+				},
+			},
+		},
+		Workflows: []sdk.WorkflowMetadata{
+			{Name: "provision-postgres", File: "./workflows/provision-postgres.yaml", Category: "provisioner"},
+		},
+	}
+	if err := registry.RegisterProvider(dbProvider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	resolver := NewResolver(registry)
+	fake := clock.NewFake(time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC))
+	resolver.SetClock(fake)
+
+	// During the grace period the old name resolves to the new type's workflow
+	_, workflow, err := resolver.ResolveProviderForResource("postgres")
+	if err != nil {
+		t.Fatalf("Expected deprecated type to resolve during grace period: %v", err)
+	}
+	if workflow.Name != "provision-postgres" {
+		t.Errorf("Expected workflow provision-postgres, got %s", workflow.Name)
+	}
+	want := "resource type 'postgres' is deprecated and stops resolving after 2026-12-31; use 'postgresql' instead: renamed"
+	if notice := resolver.DeprecationNotice("postgres"); notice != want {
+		t.Errorf("Expected notice %q, got %q", want, notice)
+	}
+	if notice := resolver.DeprecationNotice("postgresql"); notice != "" {
+		t.Errorf("Expected no notice for the new type, got %q", notice)
+	}
+
+	// Once the grace period ended the old name is rejected
+	fake.Advance(time.Minute)
+	_, _, err = resolver.ResolveProviderForResource("postgres")
+	if err == nil || !strings.Contains(err.Error(), "was removed after 2026-12-31; use 'postgresql' instead") {
+		t.Errorf("Expected removal error, got %v", err)
+	}
+	if notice := resolver.DeprecationNotice("postgres"); notice != "" {
+		t.Errorf("Expected no notice for a removed type, got %q", notice)
+	}
+	if _, _, err := resolver.ResolveProviderForResource("postgresql"); err != nil {
+		t.Errorf("Expected new type to resolve: %v", err)
+	}
+}
+
+func TestResolverResolvePinnedWorkflow(t *testing.T) {
+	dbProvider := func(version, workflow string) *sdk.Provider {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, "workflows"), 0750); err != nil {
+			t.Fatalf("Failed to create workflows directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "workflows", workflow+".yaml"), []byte("steps: []\n"), 0600); err != nil {
+			t.Fatalf("Failed to write workflow: %v", err)
+		}
+		return &sdk.Provider{
+			Metadata:     sdk.ProviderMetadata{Name: "database-team", Version: version},
+			Capabilities: sdk.ProviderCapabilities{ResourceTypes: []string{"postgres"}},
+			Workflows:    []sdk.WorkflowMetadata{{Name: workflow, File: "./workflows/" + workflow + ".yaml", Category: "provisioner"}},
+			Dir:          dir,
+		}
+	}
+
+	registry := providers.NewRegistry()
+	if err := registry.RegisterProvider(dbProvider("2.0.0", "provision-postgres-v2")); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	if err := registry.RetainProviderVersion(dbProvider("1.4.0", "provision-postgres")); err != nil {
+		t.Fatalf("Failed to retain provider version: %v", err)
+	}
+	resolver := NewResolver(registry)
+
+	tests := []struct {
+		name         string
+		pins         map[string]string
+		wantVersion  string
+		wantWorkflow string
+		wantErr      string
+	}{
+		{"unpinned uses the registered version", nil, "2.0.0", "provision-postgres-v2", ""},
+		{"pinned to the registered version", map[string]string{"database-team": "2.0.0"}, "2.0.0", "provision-postgres-v2", ""},
+		{"pinned to a retained version", map[string]string{"database-team": "1.4.0"}, "1.4.0", "provision-postgres", ""},
+		{"pin of another provider", map[string]string{"network-team": "1.0.0"}, "2.0.0", "provision-postgres-v2", ""},
+		{"pinned version not loaded", map[string]string{"database-team": "1.3.0"}, "", "", "no version 1.3.0 loaded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, workflow, err := resolver.ResolvePinnedWorkflow("postgres", "create", nil, tt.pins)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if provider.Metadata.Version != tt.wantVersion || workflow.Name != tt.wantWorkflow {
+				t.Errorf("Resolved %s %s, want %s %s", provider.Metadata.Version, workflow.Name, tt.wantVersion, tt.wantWorkflow)
+			}
+		})
+	}
+}
@@ -570,7 +570,7 @@ func (s *ValidationIntegrationTestSuite) TestFeature_WorkflowStepValidation() {
 		// Then I should get validation error
 		s.Greater(len(errors), 0)
 		errorStr := errors[0].Error()
-		s.Contains(errorStr, "kubernetes step requires 'manifest' or 'namespace'")
+		s.Contains(errorStr, "kubernetes step requires 'manifest', 'source' or 'namespace'")
 	})
 }
 
@@ -0,0 +1,191 @@
+// Package orgs groups teams into organizations (departments, business units). Teams
+// inherit the quotas and policies of their organization, and organization admins manage
+// the applications of every team in it.
+package orgs
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Config is the organizations section of admin-config.yaml, keyed by organization name
+type Config map[string]Organization
+
+// Organization is a department or business unit owning a set of teams
+type Organization struct {
+	Description string                  `yaml:"description" json:"description,omitempty"`
+	Admins      []string                `yaml:"admins" json:"admins,omitempty"` // Usernames with admin rights over the organization's teams
+	Quotas      Quotas                  `yaml:"quotas" json:"quotas"`           // Defaults for each team of the organization
+	Policies    Policies                `yaml:"policies" json:"policies"`       // Defaults for each team of the organization
+	Teams       map[string]TeamSettings `yaml:"teams" json:"teams"`
+}
+
+// TeamSettings overrides the quotas and policies a team inherits from its organization
+type TeamSettings struct {
+	Quotas   Quotas   `yaml:"quotas" json:"quotas"`
+	Policies Policies `yaml:"policies" json:"policies"`
+}
+
+// Quotas limit what a team can deploy; zero means unlimited
+type Quotas struct {
+	MaxApplications int `yaml:"maxApplications" json:"maxApplications,omitempty"`
+	MaxResources    int `yaml:"maxResources" json:"maxResources,omitempty"` // Score resources across all applications of the team
+}
+
+// Policies restrict the Score specs a team can deploy; empty lists allow everything
+type Policies struct {
+	AllowedEnvironments  []string `yaml:"allowedEnvironments" json:"allowedEnvironments,omitempty"`
+	AllowedResourceTypes []string `yaml:"allowedResourceTypes" json:"allowedResourceTypes,omitempty"`
+}
+
+// Settings are the quotas and policies in effect for a team
+type Settings struct {
+	Organization string   `json:"organization,omitempty"`
+	Quotas       Quotas   `json:"quotas"`
+	Policies     Policies `json:"policies"`
+}
+
+// Hierarchy resolves teams to their organization
+type Hierarchy struct {
+	config Config
+	orgOf  map[string]string
+}
+
+// New builds the hierarchy of a configuration. A team can belong to one organization only.
+func New(config Config) (*Hierarchy, error) {
+	h := &Hierarchy{config: config, orgOf: make(map[string]string)}
+
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for team := range config[name].Teams {
+			if other, ok := h.orgOf[team]; ok {
+				return nil, fmt.Errorf("team '%s' belongs to organizations '%s' and '%s'", team, other, name)
+			}
+			h.orgOf[team] = name
+		}
+	}
+	return h, nil
+}
+
+// Organizations returns the organization names, sorted
+func (h *Hierarchy) Organizations() []string {
+	names := make([]string, 0, len(h.config))
+	for name := range h.config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Organization returns an organization by name
+func (h *Hierarchy) Organization(name string) (Organization, bool) {
+	org, ok := h.config[name]
+	return org, ok
+}
+
+// Teams returns the teams of an organization, sorted
+func (h *Hierarchy) Teams(org string) []string {
+	teams := make([]string, 0, len(h.config[org].Teams))
+	for team := range h.config[org].Teams {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+	return teams
+}
+
+// OrgOf returns the organization of a team, or "" for teams outside any organization
+func (h *Hierarchy) OrgOf(team string) string {
+	return h.orgOf[team]
+}
+
+// Settings returns the quotas and policies of a team: those set for the team, falling back
+// to its organization's
+func (h *Hierarchy) Settings(team string) Settings {
+	name := h.orgOf[team]
+	if name == "" {
+		return Settings{}
+	}
+
+	org := h.config[name]
+	own := org.Teams[team]
+	settings := Settings{Organization: name, Quotas: org.Quotas, Policies: org.Policies}
+	if own.Quotas.MaxApplications != 0 {
+		settings.Quotas.MaxApplications = own.Quotas.MaxApplications
+	}
+	if own.Quotas.MaxResources != 0 {
+		settings.Quotas.MaxResources = own.Quotas.MaxResources
+	}
+	if len(own.Policies.AllowedEnvironments) > 0 {
+		settings.Policies.AllowedEnvironments = own.Policies.AllowedEnvironments
+	}
+	if len(own.Policies.AllowedResourceTypes) > 0 {
+		settings.Policies.AllowedResourceTypes = own.Policies.AllowedResourceTypes
+	}
+	return settings
+}
+
+// AdminOrgs returns the organizations a user administers, sorted
+func (h *Hierarchy) AdminOrgs(username string) []string {
+	var names []string
+	for _, name := range h.Organizations() {
+		if slices.Contains(h.config[name].Admins, username) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// IsOrgAdmin reports whether a user administers the organization of a team
+func (h *Hierarchy) IsOrgAdmin(username, team string) bool {
+	name := h.orgOf[team]
+	return name != "" && slices.Contains(h.config[name].Admins, username)
+}
+
+// ManagedTeams returns the teams of the organizations a user administers, sorted
+func (h *Hierarchy) ManagedTeams(username string) []string {
+	var teams []string
+	for _, name := range h.AdminOrgs(username) {
+		teams = append(teams, h.Teams(name)...)
+	}
+	sort.Strings(teams)
+	return teams
+}
+
+// CheckQuotas returns an error when a team would exceed its quotas with the given number of
+// applications and resources
+func (s Settings) CheckQuotas(team string, applications, resources int) error {
+	if limit := s.Quotas.MaxApplications; limit > 0 && applications > limit {
+		return fmt.Errorf("team '%s' would have %d applications, quota of organization '%s' allows %d", team, applications, s.Organization, limit)
+	}
+	if limit := s.Quotas.MaxResources; limit > 0 && resources > limit {
+		return fmt.Errorf("team '%s' would have %d resources, quota of organization '%s' allows %d", team, resources, s.Organization, limit)
+	}
+	return nil
+}
+
+// CheckSpec returns an error when an environment or resource type is not allowed by the
+// policies. An empty environment is not checked.
+func (s Settings) CheckSpec(environment string, resourceTypes []string) error {
+	allowed := s.Policies.AllowedEnvironments
+	if environment != "" && len(allowed) > 0 && !slices.Contains(allowed, environment) {
+		return fmt.Errorf("environment '%s' is not allowed in organization '%s' (allowed: %s)", environment, s.Organization, strings.Join(allowed, ", "))
+	}
+
+	allowed = s.Policies.AllowedResourceTypes
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, resourceType := range resourceTypes {
+		if !slices.Contains(allowed, resourceType) {
+			return fmt.Errorf("resource type '%s' is not allowed in organization '%s' (allowed: %s)", resourceType, s.Organization, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package orgs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	return Config{
+		"payments": {
+			Description: "Payments business unit",
+			Admins:      []string{"alice"},
+			Quotas:      Quotas{MaxApplications: 10, MaxResources: 30},
+			Policies:    Policies{AllowedEnvironments: []string{"kubernetes"}, AllowedResourceTypes: []string{"postgres", "redis"}},
+			Teams: map[string]TeamSettings{
+				"checkout": {Quotas: Quotas{MaxApplications: 2}},
+				"billing":  {Policies: Policies{AllowedResourceTypes: []string{"postgres"}}},
+			},
+		},
+		"retail": {
+			Admins: []string{"bob"},
+			Teams:  map[string]TeamSettings{"storefront": {}},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	h, err := New(testConfig())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payments", "retail"}, h.Organizations())
+	assert.Equal(t, []string{"billing", "checkout"}, h.Teams("payments"))
+	assert.Equal(t, "payments", h.OrgOf("checkout"))
+	assert.Equal(t, "", h.OrgOf("platform"))
+
+	config := testConfig()
+	config["retail"].Teams["billing"] = TeamSettings{}
+	_, err = New(config)
+	assert.ErrorContains(t, err, "team 'billing' belongs to organizations 'payments' and 'retail'")
+}
+
+func TestSettings(t *testing.T) {
+	h, err := New(testConfig())
+	require.NoError(t, err)
+
+	checkout := h.Settings("checkout")
+	assert.Equal(t, "payments", checkout.Organization)
+	assert.Equal(t, Quotas{MaxApplications: 2, MaxResources: 30}, checkout.Quotas, "team overrides one quota and inherits the other")
+	assert.Equal(t, []string{"postgres", "redis"}, checkout.Policies.AllowedResourceTypes)
+
+	billing := h.Settings("billing")
+	assert.Equal(t, []string{"postgres"}, billing.Policies.AllowedResourceTypes)
+	assert.Equal(t, []string{"kubernetes"}, billing.Policies.AllowedEnvironments)
+
+	assert.Equal(t, Settings{}, h.Settings("platform"))
+}
+
+func TestOrgAdmins(t *testing.T) {
+	h, err := New(testConfig())
+	require.NoError(t, err)
+
+	assert.True(t, h.IsOrgAdmin("alice", "checkout"))
+	assert.False(t, h.IsOrgAdmin("alice", "storefront"))
+	assert.False(t, h.IsOrgAdmin("alice", "platform"))
+	assert.Equal(t, []string{"payments"}, h.AdminOrgs("alice"))
+	assert.Equal(t, []string{"billing", "checkout"}, h.ManagedTeams("alice"))
+	assert.Empty(t, h.ManagedTeams("carol"))
+}
+
+func TestCheckQuotas(t *testing.T) {
+	h, err := New(testConfig())
+	require.NoError(t, err)
+	settings := h.Settings("checkout")
+
+	assert.NoError(t, settings.CheckQuotas("checkout", 2, 30))
+	assert.ErrorContains(t, settings.CheckQuotas("checkout", 3, 1), "team 'checkout' would have 3 applications, quota of organization 'payments' allows 2")
+	assert.ErrorContains(t, settings.CheckQuotas("checkout", 1, 31), "would have 31 resources")
+	assert.NoError(t, h.Settings("storefront").CheckQuotas("storefront", 100, 1000), "zero quotas are unlimited")
+}
+
+func TestCheckSpec(t *testing.T) {
+	h, err := New(testConfig())
+	require.NoError(t, err)
+	settings := h.Settings("billing")
+
+	assert.NoError(t, settings.CheckSpec("kubernetes", []string{"postgres"}))
+	assert.NoError(t, settings.CheckSpec("", nil))
+	assert.ErrorContains(t, settings.CheckSpec("docker", nil), "environment 'docker' is not allowed in organization 'payments' (allowed: kubernetes)")
+	assert.ErrorContains(t, settings.CheckSpec("kubernetes", []string{"postgres", "redis"}), "resource type 'redis' is not allowed")
+}
@@ -0,0 +1,165 @@
+package paramsources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/types"
+	"innominatus/internal/vault"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VaultFetcher reads a field from a Vault KV v2 secret
+type VaultFetcher struct {
+	client *vault.Client
+}
+
+// NewVaultFetcherFromEnv creates a Vault fetcher using VAULT_ADDR and VAULT_TOKEN
+func NewVaultFetcherFromEnv() *VaultFetcher {
+	address := os.Getenv("VAULT_ADDR")
+	if address == "" {
+		address = "http://vault.vault.svc.cluster.local:8200"
+	}
+	return &VaultFetcher{client: vault.NewClient(address, os.Getenv("VAULT_TOKEN"))}
+}
+
+// Fetch returns source.Key from the secret at source.Path
+func (f *VaultFetcher) Fetch(ctx context.Context, source types.ParameterSource) (string, error) {
+	if source.Path == "" || source.Key == "" {
+		return "", fmt.Errorf("vault source requires 'path' and 'key'")
+	}
+
+	data, err := f.client.ReadKV(source.Path)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := data[source.Key]
+	if !ok {
+		return "", fmt.Errorf("key '%s' not found in vault secret '%s'", source.Key, source.Path)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+// HTTPFetcher reads a value from a JSON HTTP endpoint
+type HTTPFetcher struct {
+	client *http.Client
+}
+
+// NewHTTPFetcher creates an HTTP fetcher with a conservative timeout
+func NewHTTPFetcher() *HTTPFetcher {
+	return &HTTPFetcher{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Fetch GETs source.URL and extracts the dotted source.Key path (e.g. regions.0.name)
+func (f *HTTPFetcher) Fetch(ctx context.Context, source types.ParameterSource) (string, error) {
+	if source.URL == "" {
+		return "", fmt.Errorf("http source requires 'url'")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", source.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("request to %s failed with status %d: %s", source.URL, resp.StatusCode, string(body))
+	}
+
+	var document interface{}
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber() // Keep numbers as written (no float formatting)
+	if err := decoder.Decode(&document); err != nil {
+		return "", fmt.Errorf("failed to decode JSON from %s: %w", source.URL, err)
+	}
+
+	return lookupJSONPath(document, source.Key)
+}
+
+// ConfigMapFetcher reads a key from a Kubernetes ConfigMap via kubectl
+type ConfigMapFetcher struct{}
+
+// Fetch returns data[source.Key] of ConfigMap source.Namespace/source.Name
+func (f *ConfigMapFetcher) Fetch(ctx context.Context, source types.ParameterSource) (string, error) {
+	if source.Name == "" || source.Key == "" {
+		return "", fmt.Errorf("configmap source requires 'name' and 'key'")
+	}
+	namespace := source.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	cmd := exec.CommandContext(ctx, "kubectl", "get", "configmap", source.Name, "-n", namespace, "-o", "json") // #nosec G204 - kubectl with arguments from workflow metadata
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read configmap %s/%s: %w", namespace, source.Name, err)
+	}
+
+	var configMap struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(output, &configMap); err != nil {
+		return "", fmt.Errorf("failed to parse configmap %s/%s: %w", namespace, source.Name, err)
+	}
+
+	value, ok := configMap.Data[source.Key]
+	if !ok {
+		return "", fmt.Errorf("key '%s' not found in configmap %s/%s", source.Key, namespace, source.Name)
+	}
+	return value, nil
+}
+
+// lookupJSONPath walks a decoded JSON document along a dotted path.
+// Numeric segments index into arrays. An empty path returns the whole document.
+func lookupJSONPath(document interface{}, path string) (string, error) {
+	current := document
+	if path != "" {
+		for _, segment := range strings.Split(path, ".") {
+			switch node := current.(type) {
+			case map[string]interface{}:
+				value, ok := node[segment]
+				if !ok {
+					return "", fmt.Errorf("path '%s' not found (missing '%s')", path, segment)
+				}
+				current = value
+			case []interface{}:
+				index, err := strconv.Atoi(segment)
+				if err != nil || index < 0 || index >= len(node) {
+					return "", fmt.Errorf("path '%s' not found (invalid index '%s')", path, segment)
+				}
+				current = node[index]
+			default:
+				return "", fmt.Errorf("path '%s' not found ('%s' is not an object or array)", path, segment)
+			}
+		}
+	}
+
+	switch value := current.(type) {
+	case string:
+		return value, nil
+	case nil:
+		return "", fmt.Errorf("path '%s' is null", path)
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	default:
+		return fmt.Sprintf("%v", value), nil
+	}
+}
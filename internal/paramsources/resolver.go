@@ -0,0 +1,146 @@
+package paramsources
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/types"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long resolved values are cached when a source sets no ttl
+const DefaultTTL = 5 * time.Minute
+
+// Failure modes for sources that cannot be resolved
+const (
+	OnFailureFail    = "fail"
+	OnFailureDefault = "default"
+	OnFailureSkip    = "skip"
+)
+
+// Fetcher retrieves a single value from an external system
+type Fetcher interface {
+	Fetch(ctx context.Context, source types.ParameterSource) (string, error)
+}
+
+type cacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Resolver resolves workflow parameters from external sources with caching
+type Resolver struct {
+	fetchers map[string]Fetcher
+	cache    map[string]cacheEntry
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+// NewResolver creates a resolver with the vault, http and configmap fetchers registered
+func NewResolver() *Resolver {
+	r := &Resolver{
+		fetchers: make(map[string]Fetcher),
+		cache:    make(map[string]cacheEntry),
+		now:      time.Now,
+	}
+	r.RegisterFetcher("vault", NewVaultFetcherFromEnv())
+	r.RegisterFetcher("http", NewHTTPFetcher())
+	r.RegisterFetcher("configmap", &ConfigMapFetcher{})
+	return r
+}
+
+// RegisterFetcher adds or replaces the fetcher for a source type
+func (r *Resolver) RegisterFetcher(sourceType string, fetcher Fetcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fetchers[sourceType] = fetcher
+}
+
+// Resolve returns values for all declared sources. Parameters already present in
+// provided (e.g. passed on the command line) take precedence and are not fetched.
+func (r *Resolver) Resolve(ctx context.Context, sources map[string]types.ParameterSource, provided map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string)
+
+	// Resolve in a stable order so errors are deterministic
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := provided[name]; ok {
+			continue
+		}
+
+		source := sources[name]
+		value, err := r.resolveOne(ctx, source)
+		if err == nil {
+			resolved[name] = value
+			continue
+		}
+
+		switch strings.ToLower(source.OnFailure) {
+		case OnFailureDefault:
+			resolved[name] = source.Default
+		case OnFailureSkip:
+			// Leave unset; workflow defaults or validation decide what happens
+		case "", OnFailureFail:
+			return nil, fmt.Errorf("failed to resolve parameter '%s' from %s: %w", name, source.Type, err)
+		default:
+			return nil, fmt.Errorf("parameter '%s' has invalid onFailure '%s' (use fail, default or skip)", name, source.OnFailure)
+		}
+	}
+
+	return resolved, nil
+}
+
+func (r *Resolver) resolveOne(ctx context.Context, source types.ParameterSource) (string, error) {
+	ttl, err := sourceTTL(source)
+	if err != nil {
+		return "", err
+	}
+
+	key := cacheKey(source)
+	r.mu.Lock()
+	entry, cached := r.cache[key]
+	fetcher, known := r.fetchers[strings.ToLower(source.Type)]
+	r.mu.Unlock()
+
+	if cached && r.now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+	if !known {
+		return "", fmt.Errorf("unknown parameter source type '%s' (use vault, http or configmap)", source.Type)
+	}
+
+	value, err := fetcher.Fetch(ctx, source)
+	if err != nil {
+		return "", err
+	}
+
+	if ttl > 0 {
+		r.mu.Lock()
+		r.cache[key] = cacheEntry{value: value, expiresAt: r.now().Add(ttl)}
+		r.mu.Unlock()
+	}
+
+	return value, nil
+}
+
+func sourceTTL(source types.ParameterSource) (time.Duration, error) {
+	if source.TTL == "" {
+		return DefaultTTL, nil
+	}
+	ttl, err := time.ParseDuration(source.TTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ttl '%s': %w", source.TTL, err)
+	}
+	return ttl, nil
+}
+
+func cacheKey(source types.ParameterSource) string {
+	return strings.Join([]string{strings.ToLower(source.Type), source.Path, source.URL, source.Namespace, source.Name, source.Key}, "|")
+}
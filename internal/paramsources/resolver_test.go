@@ -0,0 +1,149 @@
+package paramsources
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/types"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingFetcher returns a fixed value and counts calls
+type countingFetcher struct {
+	value string
+	err   error
+	calls int
+}
+
+func (f *countingFetcher) Fetch(ctx context.Context, source types.ParameterSource) (string, error) {
+	f.calls++
+	return f.value, f.err
+}
+
+func newTestResolver(fetcher Fetcher) (*Resolver, *time.Time) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	r := &Resolver{
+		fetchers: map[string]Fetcher{"fake": fetcher},
+		cache:    make(map[string]cacheEntry),
+		now:      func() time.Time { return now },
+	}
+	return r, &now
+}
+
+func TestResolveCachesValues(t *testing.T) {
+	fetcher := &countingFetcher{value: "eu-west-1"}
+	r, now := newTestResolver(fetcher)
+	sources := map[string]types.ParameterSource{"region": {Type: "fake", Key: "region", TTL: "1m"}}
+
+	for i := 0; i < 3; i++ {
+		values, err := r.Resolve(context.Background(), sources, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "eu-west-1", values["region"])
+	}
+	assert.Equal(t, 1, fetcher.calls)
+
+	*now = now.Add(2 * time.Minute)
+	_, err := r.Resolve(context.Background(), sources, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fetcher.calls)
+}
+
+func TestResolveZeroTTLDisablesCache(t *testing.T) {
+	fetcher := &countingFetcher{value: "v"}
+	r, _ := newTestResolver(fetcher)
+	sources := map[string]types.ParameterSource{"p": {Type: "fake", TTL: "0s"}}
+
+	_, _ = r.Resolve(context.Background(), sources, nil)
+	_, _ = r.Resolve(context.Background(), sources, nil)
+	assert.Equal(t, 2, fetcher.calls)
+}
+
+func TestResolveProvidedParametersTakePrecedence(t *testing.T) {
+	fetcher := &countingFetcher{value: "from-source"}
+	r, _ := newTestResolver(fetcher)
+	sources := map[string]types.ParameterSource{"region": {Type: "fake"}}
+
+	values, err := r.Resolve(context.Background(), sources, map[string]string{"region": "cli"})
+	require.NoError(t, err)
+	assert.NotContains(t, values, "region")
+	assert.Equal(t, 0, fetcher.calls)
+}
+
+func TestResolveFailureSemantics(t *testing.T) {
+	failing := &countingFetcher{err: fmt.Errorf("connection refused")}
+
+	tests := []struct {
+		name      string
+		source    types.ParameterSource
+		wantErr   string
+		wantValue *string
+	}{
+		{name: "fail by default", source: types.ParameterSource{Type: "fake"}, wantErr: "connection refused"},
+		{name: "use default", source: types.ParameterSource{Type: "fake", OnFailure: "default", Default: "3"}, wantValue: strPtr("3")},
+		{name: "skip", source: types.ParameterSource{Type: "fake", OnFailure: "skip"}},
+		{name: "invalid mode", source: types.ParameterSource{Type: "fake", OnFailure: "ignore"}, wantErr: "invalid onFailure"},
+		{name: "unknown type", source: types.ParameterSource{Type: "etcd"}, wantErr: "unknown parameter source type"},
+		{name: "invalid ttl", source: types.ParameterSource{Type: "fake", TTL: "soon"}, wantErr: "invalid ttl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newTestResolver(failing)
+			values, err := r.Resolve(context.Background(), map[string]types.ParameterSource{"p": tt.source}, nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantValue == nil {
+				assert.NotContains(t, values, "p")
+			} else {
+				assert.Equal(t, *tt.wantValue, values["p"])
+			}
+		})
+	}
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"defaults": {"replicas": 3, "regions": ["eu-west-1", "eu-central-1"], "limits": {"cpu": "500m"}}}`))
+	}))
+	defer server.Close()
+
+	fetcher := NewHTTPFetcher()
+	ctx := context.Background()
+
+	value, err := fetcher.Fetch(ctx, types.ParameterSource{URL: server.URL, Key: "defaults.replicas"})
+	require.NoError(t, err)
+	assert.Equal(t, "3", value)
+
+	value, err = fetcher.Fetch(ctx, types.ParameterSource{URL: server.URL, Key: "defaults.regions.1"})
+	require.NoError(t, err)
+	assert.Equal(t, "eu-central-1", value)
+
+	value, err = fetcher.Fetch(ctx, types.ParameterSource{URL: server.URL, Key: "defaults.limits"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"cpu": "500m"}`, value)
+
+	_, err = fetcher.Fetch(ctx, types.ParameterSource{URL: server.URL, Key: "defaults.unknown"})
+	assert.Error(t, err)
+
+	_, err = fetcher.Fetch(ctx, types.ParameterSource{URL: server.URL + "/missing", Key: "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
+
+func strPtr(s string) *string {
+	return &s
+}
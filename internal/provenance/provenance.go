@@ -0,0 +1,174 @@
+// Package provenance builds SLSA-style provenance documents for deployments: which Score
+// spec was deployed, by which workflow definitions and provider versions, by whom and when.
+// Documents are sealed with a SHA-256 digest and optionally signed with an Ed25519 server key.
+package provenance
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// PredicateType identifies the provenance document format
+const PredicateType = "https://innominatus.io/provenance/deployment/v1"
+
+// SigningKeyEnv names the environment variable holding the base64-encoded Ed25519 seed
+// (32 bytes) or private key (64 bytes). Records are unsigned when it is not set.
+const SigningKeyEnv = "PROVENANCE_SIGNING_KEY"
+
+// Document describes one deployment
+type Document struct {
+	PredicateType string     `json:"predicate_type"`
+	Application   string     `json:"application"`
+	Team          string     `json:"team,omitempty"`
+	Kind          string     `json:"kind"`                  // "deploy" or "golden-path"
+	GoldenPath    string     `json:"golden_path,omitempty"` // Set for golden path deployments
+	SpecDigest    string     `json:"spec_digest"`
+	Workflows     []Workflow `json:"workflows"`
+	Providers     []Provider `json:"providers"`
+	Builder       Builder    `json:"builder"`
+	TriggeredBy   string     `json:"triggered_by"`
+	StartedAt     time.Time  `json:"started_at"`
+	FinishedAt    time.Time  `json:"finished_at"`
+}
+
+// Workflow references a workflow definition by content digest
+type Workflow struct {
+	Name     string `json:"name"`
+	Source   string `json:"source,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	Digest   string `json:"digest,omitempty"` // Empty when the definition could not be read
+}
+
+// Provider records the version of a provider involved in the deployment
+type Provider struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Builder identifies the server instance that performed the deployment
+type Builder struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+// Envelope is a sealed document: the exact payload bytes, their digest and an optional signature
+type Envelope struct {
+	Payload   []byte
+	Digest    string
+	Signature string // Base64 Ed25519 signature over Payload; empty when unsigned
+	KeyID     string
+}
+
+// Digest returns the "sha256:<hex>" digest of data
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// WorkflowFromFile references a workflow definition file; the digest is left empty
+// when the file cannot be read
+func WorkflowFromFile(name, provider, path string) Workflow {
+	wf := Workflow{Name: name, Source: path, Provider: provider}
+	if data, err := os.ReadFile(path); err == nil { // #nosec G304 - path comes from provider or golden path config
+		wf.Digest = Digest(data)
+	}
+	return wf
+}
+
+// Signer signs provenance payloads with an Ed25519 key
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+// NewSigner creates a signer for the given private key
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{key: key, keyID: KeyID(key.Public().(ed25519.PublicKey))}
+}
+
+// LoadSigner creates a signer from SigningKeyEnv; it returns nil without error when the
+// variable is not set
+func LoadSigner() (*Signer, error) {
+	encoded := os.Getenv(SigningKeyEnv)
+	if encoded == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid base64: %w", SigningKeyEnv, err)
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return NewSigner(ed25519.NewKeyFromSeed(raw)), nil
+	case ed25519.PrivateKeySize:
+		return NewSigner(ed25519.PrivateKey(raw)), nil
+	default:
+		return nil, fmt.Errorf("%s must be a %d-byte seed or %d-byte private key, got %d bytes",
+			SigningKeyEnv, ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+}
+
+// KeyID returns the identifier of the signing key
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// PublicKey returns the key auditors use to verify signatures
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// KeyID derives a short key identifier from a public key
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Seal serializes the document and signs it when a signer is given
+func Seal(doc *Document, signer *Signer) (*Envelope, error) {
+	if doc.PredicateType == "" {
+		doc.PredicateType = PredicateType
+	}
+
+	payload, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal provenance: %w", err)
+	}
+
+	envelope := &Envelope{Payload: payload, Digest: Digest(payload)}
+	if signer != nil {
+		envelope.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(signer.key, payload))
+		envelope.KeyID = signer.keyID
+	}
+	return envelope, nil
+}
+
+// Verify checks that payload matches the digest and, when signed, the signature
+func Verify(payload []byte, digest, signature string, pub ed25519.PublicKey) error {
+	if Digest(payload) != digest {
+		return fmt.Errorf("digest mismatch")
+	}
+	if signature == "" {
+		return nil
+	}
+	if pub == nil {
+		return fmt.Errorf("no public key to verify signature")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if !ed25519.Verify(pub, payload, sig) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
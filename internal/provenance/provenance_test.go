@@ -0,0 +1,115 @@
+package provenance
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDocument() *Document {
+	started := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	return &Document{
+		Application: "shop",
+		Team:        "ecommerce",
+		Kind:        "deploy",
+		SpecDigest:  Digest([]byte("apiVersion: score.dev/v1b1")),
+		Workflows:   []Workflow{{Name: "provision-postgres", Provider: "database-team", Digest: Digest([]byte("steps: []"))}},
+		Providers:   []Provider{{Name: "database-team", Version: "1.2.0"}},
+		Builder:     Builder{ID: "innominatus/host-1", Version: "v1.0.0"},
+		TriggeredBy: "alice",
+		StartedAt:   started,
+		FinishedAt:  started.Add(time.Minute),
+	}
+}
+
+func TestSeal(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	signer := NewSigner(ed25519.NewKeyFromSeed(seed))
+
+	tests := []struct {
+		name   string
+		signer *Signer
+	}{
+		{"unsigned", nil},
+		{"signed", signer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := Seal(testDocument(), tt.signer)
+			require.NoError(t, err)
+
+			var decoded Document
+			require.NoError(t, json.Unmarshal(envelope.Payload, &decoded))
+			assert.Equal(t, PredicateType, decoded.PredicateType)
+			assert.Equal(t, "shop", decoded.Application)
+			assert.Equal(t, Digest(envelope.Payload), envelope.Digest)
+
+			var pub ed25519.PublicKey
+			if tt.signer != nil {
+				pub = tt.signer.PublicKey()
+				assert.NotEmpty(t, envelope.Signature)
+				assert.Equal(t, tt.signer.KeyID(), envelope.KeyID)
+			} else {
+				assert.Empty(t, envelope.Signature)
+			}
+			assert.NoError(t, Verify(envelope.Payload, envelope.Digest, envelope.Signature, pub))
+
+			// Any change to the stored document is detected
+			tampered := append([]byte{}, envelope.Payload...)
+			tampered[len(tampered)-2] = 'X'
+			assert.Error(t, Verify(tampered, envelope.Digest, envelope.Signature, pub))
+			if tt.signer != nil {
+				assert.Error(t, Verify(tampered, Digest(tampered), envelope.Signature, pub))
+			}
+		})
+	}
+}
+
+func TestLoadSigner(t *testing.T) {
+	t.Setenv(SigningKeyEnv, "")
+	signer, err := LoadSigner()
+	require.NoError(t, err)
+	assert.Nil(t, signer)
+
+	seed := make([]byte, ed25519.SeedSize)
+	seed[0] = 1
+	t.Setenv(SigningKeyEnv, base64.StdEncoding.EncodeToString(seed))
+	signer, err = LoadSigner()
+	require.NoError(t, err)
+	require.NotNil(t, signer)
+	assert.Len(t, signer.KeyID(), 16)
+
+	// The full private key yields the same signer
+	t.Setenv(SigningKeyEnv, base64.StdEncoding.EncodeToString(ed25519.NewKeyFromSeed(seed)))
+	fromKey, err := LoadSigner()
+	require.NoError(t, err)
+	assert.Equal(t, signer.KeyID(), fromKey.KeyID())
+
+	t.Setenv(SigningKeyEnv, base64.StdEncoding.EncodeToString([]byte("short")))
+	_, err = LoadSigner()
+	assert.Error(t, err)
+
+	t.Setenv(SigningKeyEnv, "not base64!")
+	_, err = LoadSigner()
+	assert.Error(t, err)
+}
+
+func TestWorkflowFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "provision.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("steps: []"), 0600))
+
+	wf := WorkflowFromFile("provision-postgres", "database-team", path)
+	assert.Equal(t, Digest([]byte("steps: []")), wf.Digest)
+	assert.Equal(t, path, wf.Source)
+
+	missing := WorkflowFromFile("missing", "", filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Empty(t, missing.Digest)
+}
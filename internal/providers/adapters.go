@@ -87,10 +87,12 @@ func (a *GiteaAdapter) GetHints(ctx context.Context, resource *sdk.Resource) ([]
 	hints := make([]sdk.Hint, len(dbResource.Hints))
 	for i, dbHint := range dbResource.Hints {
 		hints[i] = sdk.Hint{
-			Type:  dbHint.Type,
-			Label: dbHint.Label,
-			Value: dbHint.Value,
-			Icon:  dbHint.Icon,
+			Type:   dbHint.Type,
+			Label:  dbHint.Label,
+			Value:  dbHint.Value,
+			Icon:   dbHint.Icon,
+			Query:  dbHint.Query,
+			Height: dbHint.Height,
 		}
 	}
 
@@ -162,10 +164,12 @@ func (a *KubernetesAdapter) GetHints(ctx context.Context, resource *sdk.Resource
 	hints := make([]sdk.Hint, len(dbResource.Hints))
 	for i, dbHint := range dbResource.Hints {
 		hints[i] = sdk.Hint{
-			Type:  dbHint.Type,
-			Label: dbHint.Label,
-			Value: dbHint.Value,
-			Icon:  dbHint.Icon,
+			Type:   dbHint.Type,
+			Label:  dbHint.Label,
+			Value:  dbHint.Value,
+			Icon:   dbHint.Icon,
+			Query:  dbHint.Query,
+			Height: dbHint.Height,
 		}
 	}
 
@@ -237,10 +241,12 @@ func (a *ArgoCDAdapter) GetHints(ctx context.Context, resource *sdk.Resource) ([
 	hints := make([]sdk.Hint, len(dbResource.Hints))
 	for i, dbHint := range dbResource.Hints {
 		hints[i] = sdk.Hint{
-			Type:  dbHint.Type,
-			Label: dbHint.Label,
-			Value: dbHint.Value,
-			Icon:  dbHint.Icon,
+			Type:   dbHint.Type,
+			Label:  dbHint.Label,
+			Value:  dbHint.Value,
+			Icon:   dbHint.Icon,
+			Query:  dbHint.Query,
+			Height: dbHint.Height,
 		}
 	}
 
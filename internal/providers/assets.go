@@ -0,0 +1,144 @@
+package providers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"innominatus/internal/types"
+	"innominatus/pkg/sdk"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrAssetNotCached is returned for workflow files of providers that were never warmed up
+var ErrAssetNotCached = errors.New("provider asset not cached")
+
+// Asset is a workflow file of a registered provider. Its contents are kept in memory,
+// so runs do not depend on the provider directory (e.g. a Git clone in /tmp) still
+// being on disk.
+type Asset struct {
+	File     string    `json:"file"`
+	Digest   string    `json:"digest"` // sha256:<hex> of the contents
+	Size     int       `json:"size"`
+	CachedAt time.Time `json:"cached_at"`
+	data     []byte
+}
+
+// AssetCache holds the workflow files of registered providers, verified at warm-up
+type AssetCache struct {
+	mu     sync.RWMutex
+	assets map[string]map[string]*Asset // provider -> workflow file -> asset
+	now    func() time.Time
+}
+
+// NewAssetCache creates an empty asset cache
+func NewAssetCache() *AssetCache {
+	return &AssetCache{
+		assets: make(map[string]map[string]*Asset),
+		now:    time.Now,
+	}
+}
+
+// Warm reads every workflow file of provider from its directory, checks that it parses
+// and replaces the cached assets of the provider. Nothing is replaced when a file is
+// missing or invalid, so broken references fail at registration instead of mid-deploy.
+func (c *AssetCache) Warm(provider *sdk.Provider) error {
+	return c.warm(provider.Metadata.Name, provider)
+}
+
+// warm caches the workflow files of provider under key
+func (c *AssetCache) warm(key string, provider *sdk.Provider) error {
+	if provider.Dir == "" {
+		return fmt.Errorf("provider %s has no directory to read workflows from", provider.Metadata.Name)
+	}
+
+	assets := make(map[string]*Asset, len(provider.Workflows))
+	var missing []string
+	for _, workflowMeta := range provider.Workflows {
+		// #nosec G304 -- workflow path is constructed from the provider directory and provider.yaml metadata
+		data, err := os.ReadFile(filepath.Join(provider.Dir, workflowMeta.File))
+		if err != nil {
+			missing = append(missing, fmt.Sprintf("workflow '%s': %v", workflowMeta.Name, err))
+			continue
+		}
+		var wf types.Workflow
+		if err := yaml.Unmarshal(data, &wf); err != nil {
+			missing = append(missing, fmt.Sprintf("workflow '%s': failed to parse YAML: %v", workflowMeta.Name, err))
+			continue
+		}
+		assets[cleanAssetPath(workflowMeta.File)] = &Asset{
+			File:     workflowMeta.File,
+			Digest:   digest(data),
+			Size:     len(data),
+			CachedAt: c.now(),
+			data:     data,
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("provider %s failed warm-up:\n  %s", provider.Metadata.Name, strings.Join(missing, "\n  "))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.assets[key] = assets
+	return nil
+}
+
+// Workflow returns the cached contents of a workflow file of provider. It fails when
+// the contents no longer match the digest taken at warm-up.
+func (c *AssetCache) Workflow(provider, file string) ([]byte, error) {
+	c.mu.RLock()
+	asset, ok := c.assets[provider][cleanAssetPath(file)]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrAssetNotCached, provider, file)
+	}
+	if got := digest(asset.data); got != asset.Digest {
+		return nil, fmt.Errorf("workflow %s of provider %s failed integrity check: expected %s, got %s", file, provider, asset.Digest, got)
+	}
+	return asset.data, nil
+}
+
+// Assets returns the cached assets of provider sorted by file
+func (c *AssetCache) Assets(provider string) []Asset {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	assets := make([]Asset, 0, len(c.assets[provider]))
+	for _, asset := range c.assets[provider] {
+		assets = append(assets, *asset)
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i].File < assets[j].File })
+	return assets
+}
+
+// Remove drops the cached assets of provider
+func (c *AssetCache) Remove(provider string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.assets, provider)
+}
+
+// Clear drops all cached assets
+func (c *AssetCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.assets = make(map[string]map[string]*Asset)
+}
+
+// cleanAssetPath makes "./workflows/a.yaml" and "workflows/a.yaml" the same key
+func cleanAssetPath(file string) string {
+	return filepath.ToSlash(filepath.Clean(file))
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,173 @@
+package providers_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"innominatus/internal/providers"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// providerRepo creates a Git repository with the test-team provider on branch master
+func providerRepo(t *testing.T) (string, *git.Repository) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("Failed to init repository: %v", err)
+	}
+	for _, file := range []string{"provider.yaml", "workflows/provision-test-db.yaml"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "providers", "test-team", file))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		writeFile(t, filepath.Join(dir, file), string(data))
+	}
+	commitAll(t, repo, "Add provider")
+	return dir, repo
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func commitAll(t *testing.T, repo *git.Repository, message string) {
+	t.Helper()
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	if err := worktree.AddGlob("."); err != nil {
+		t.Fatalf("Failed to stage files: %v", err)
+	}
+	_, err = worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+}
+
+func TestAssetCacheWarmAndRead(t *testing.T) {
+	dir, _ := providerRepo(t)
+	provider, err := providers.NewLoader("1.5.0").LoadFromFile(filepath.Join(dir, "provider.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load provider: %v", err)
+	}
+
+	cache := providers.NewAssetCache()
+	if err := cache.Warm(provider); err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+	assets := cache.Assets("test-team")
+	if len(assets) != 1 || !strings.HasPrefix(assets[0].Digest, "sha256:") {
+		t.Fatalf("Expected one asset with a sha256 digest, got %+v", assets)
+	}
+
+	// The cached workflow survives the provider directory being cleaned up
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("Failed to remove provider directory: %v", err)
+	}
+	data, err := cache.Workflow("test-team", "workflows/provision-test-db.yaml")
+	if err != nil {
+		t.Fatalf("Expected cached workflow, got %v", err)
+	}
+	if !strings.Contains(string(data), "steps") {
+		t.Errorf("Expected workflow contents, got %q", data)
+	}
+
+	if _, err := cache.Workflow("test-team", "./workflows/other.yaml"); !errors.Is(err, providers.ErrAssetNotCached) {
+		t.Errorf("Expected ErrAssetNotCached, got %v", err)
+	}
+}
+
+func TestAssetCacheWarmFailsOnMissingWorkflow(t *testing.T) {
+	dir, _ := providerRepo(t)
+	provider, err := providers.NewLoader("1.5.0").LoadFromFile(filepath.Join(dir, "provider.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load provider: %v", err)
+	}
+	cache := providers.NewAssetCache()
+	if err := cache.Warm(provider); err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "workflows", "provision-test-db.yaml")); err != nil {
+		t.Fatalf("Failed to remove workflow: %v", err)
+	}
+	err = cache.Warm(provider)
+	if err == nil || !strings.Contains(err.Error(), "provision-test-db") {
+		t.Fatalf("Expected warm-up to name the missing workflow, got %v", err)
+	}
+
+	// A failed warm-up keeps the previously verified assets
+	if _, err := cache.Workflow("test-team", "./workflows/provision-test-db.yaml"); err != nil {
+		t.Errorf("Expected previous assets to be kept, got %v", err)
+	}
+}
+
+func TestGitRefresher(t *testing.T) {
+	remoteDir, remoteRepo := providerRepo(t)
+	source := providers.GitProviderSource{Name: "test-team", Repository: remoteDir, Ref: "master"}
+	cacheDir := t.TempDir()
+	loader := providers.NewGitLoader(cacheDir, "1.5.0")
+
+	registry := providers.NewRegistry()
+	provider, err := loader.LoadFromGit(source)
+	if err != nil {
+		t.Fatalf("LoadFromGit failed: %v", err)
+	}
+	if err := registry.Assets().Warm(provider); err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+	if err := registry.RegisterProvider(provider); err != nil {
+		t.Fatalf("RegisterProvider failed: %v", err)
+	}
+	digest := registry.Assets().Assets("test-team")[0].Digest
+
+	refresher := providers.NewGitRefresher(loader, registry)
+	refresher.SetSources([]providers.GitProviderSource{source})
+
+	results := refresher.RefreshAll()
+	if len(results) != 1 || results[0].Refreshed || results[0].Error != "" {
+		t.Fatalf("Expected no refresh while the ref is unchanged, got %+v", results)
+	}
+
+	// A new commit on the ref reloads the provider and its assets
+	workflowPath := filepath.Join(remoteDir, "workflows", "provision-test-db.yaml")
+	data, err := os.ReadFile(workflowPath)
+	if err != nil {
+		t.Fatalf("Failed to read workflow: %v", err)
+	}
+	writeFile(t, workflowPath, string(data)+"\n# changed\n")
+	commitAll(t, remoteRepo, "Change workflow")
+
+	result := refresher.Refresh(source)
+	if !result.Refreshed || result.Error != "" || !strings.Contains(result.Reason, "moved") {
+		t.Fatalf("Expected refresh after the ref moved, got %+v", result)
+	}
+	if registry.Assets().Assets("test-team")[0].Digest == digest {
+		t.Error("Expected the cached workflow to be updated")
+	}
+
+	// A cleaned-up clone is cloned again
+	if err := os.RemoveAll(cacheDir); err != nil {
+		t.Fatalf("Failed to remove clone: %v", err)
+	}
+	result = refresher.Refresh(source)
+	if !result.Refreshed || result.Reason != "clone missing" {
+		t.Fatalf("Expected refresh of the missing clone, got %+v", result)
+	}
+}
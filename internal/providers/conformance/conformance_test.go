@@ -0,0 +1,119 @@
+package conformance_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"innominatus/internal/providers"
+	"innominatus/internal/providers/conformance"
+	"innominatus/pkg/sdk"
+)
+
+const providerYAML = `apiVersion: v1
+kind: Provider
+metadata:
+  name: cache-team
+  version: 1.0.0
+compatibility:
+  minCoreVersion: 1.0.0
+  maxCoreVersion: 2.0.0
+workflows:
+  - name: provision-redis
+    file: ./workflows/provision-redis.yaml
+  - name: scale-redis
+    file: ./workflows/scale-redis.yaml
+`
+
+const provisionYAML = `parameters:
+  - name: app_name
+    required: true
+  - name: memory
+    default: 256Mi
+steps:
+  - name: create-instance
+    type: kubernetes
+    config:
+      operation: apply
+      manifest: redis.yaml
+  - name: store-credentials
+    type: policy
+    config:
+      script: echo stored
+outputs:
+  memory: ${workflow.memory}
+  endpoint: ${create-instance.endpoint}
+`
+
+const scaleYAML = `inputs:
+  - name: replicas
+    required: true
+steps:
+  - name: scale
+    type: kubernetes
+    config:
+      operation: apply
+      manifest: redis.yaml
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"provider.yaml":                  providerYAML,
+		"workflows/provision-redis.yaml": provisionYAML,
+		"workflows/scale-redis.yaml":     scaleYAML,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	provider, err := providers.NewLoader("dev").LoadFromFile(filepath.Join(dir, "provider.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load provider: %v", err)
+	}
+
+	results, err := conformance.Run(context.Background(), provider)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	provision := results[0]
+	if !provision.Passed() {
+		t.Errorf("Expected provision-redis to pass, got error: %s", provision.Error)
+	}
+	if want := []string{"create-instance", "store-credentials"}; !reflect.DeepEqual(provision.Steps, want) {
+		t.Errorf("Expected steps %v, got %v", want, provision.Steps)
+	}
+	if want := map[string]string{"app_name": "conformance-app_name", "memory": "256Mi"}; !reflect.DeepEqual(provision.Parameters, want) {
+		t.Errorf("Expected parameters %v, got %v", want, provision.Parameters)
+	}
+
+	if want := []string{"endpoint"}; !reflect.DeepEqual(provision.Unresolved, want) {
+		t.Errorf("Expected unresolved outputs %v, got %v", want, provision.Unresolved)
+	}
+
+	scale := results[1]
+	if !scale.Passed() {
+		t.Errorf("Expected scale-redis to pass, got error: %s", scale.Error)
+	}
+	if scale.Parameters["replicas"] != "conformance-replicas" {
+		t.Errorf("Expected a placeholder for the required input, got %v", scale.Parameters)
+	}
+}
+
+func TestRunRequiresProviderDirectory(t *testing.T) {
+	provider := &sdk.Provider{Metadata: sdk.ProviderMetadata{Name: "inline"}}
+	if _, err := conformance.Run(context.Background(), provider); err == nil {
+		t.Error("Expected an error for a provider without a directory")
+	}
+}
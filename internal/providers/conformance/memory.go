@@ -0,0 +1,237 @@
+package conformance
+
+import (
+	"fmt"
+	"innominatus/internal/database"
+	"sync"
+	"time"
+)
+
+// memoryRepository keeps the executions of a conformance run in memory
+type memoryRepository struct {
+	mu         sync.Mutex
+	nextID     int64
+	executions map[int64]*database.WorkflowExecution
+	steps      map[int64]*database.WorkflowStepExecution
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		executions: make(map[int64]*database.WorkflowExecution),
+		steps:      make(map[int64]*database.WorkflowStepExecution),
+	}
+}
+
+func (m *memoryRepository) id() int64 {
+	m.nextID++
+	return m.nextID
+}
+
+func (m *memoryRepository) CreateWorkflowExecution(appName, workflowName string, totalSteps int) (*database.WorkflowExecution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	exec := &database.WorkflowExecution{
+		ID:              m.id(),
+		ApplicationName: appName,
+		WorkflowName:    workflowName,
+		Status:          database.WorkflowStatusRunning,
+		StartedAt:       now,
+		TotalSteps:      totalSteps,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	m.executions[exec.ID] = exec
+	copied := *exec
+	return &copied, nil
+}
+
+func (m *memoryRepository) CreateRetryExecution(parentID int64, appName, workflowName string, totalSteps, resumeFromStep int) (*database.WorkflowExecution, error) {
+	m.mu.Lock()
+	parent, ok := m.executions[parentID]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("workflow execution not found")
+	}
+	exec, err := m.CreateWorkflowExecution(appName, workflowName, totalSteps)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := m.executions[exec.ID]
+	stored.ParentExecutionID = &parentID
+	stored.RetryCount = parent.RetryCount + 1
+	stored.IsRetry = true
+	stored.ResumeFromStep = &resumeFromStep
+	copied := *stored
+	return &copied, nil
+}
+
+func (m *memoryRepository) UpdateWorkflowExecution(execID int64, status string, errorMessage *string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exec, ok := m.executions[execID]
+	if !ok {
+		return fmt.Errorf("workflow execution not found")
+	}
+	exec.Status = status
+	exec.ErrorMessage = errorMessage
+	exec.UpdatedAt = time.Now()
+	if status == database.WorkflowStatusCompleted || status == database.WorkflowStatusFailed {
+		completedAt := exec.UpdatedAt
+		exec.CompletedAt = &completedAt
+	}
+	return nil
+}
+
+func (m *memoryRepository) SetWorkflowExecutionOutputs(execID int64, outputs []database.WorkflowOutput) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exec, ok := m.executions[execID]
+	if !ok {
+		return fmt.Errorf("workflow execution not found")
+	}
+	exec.Outputs = outputs
+	return nil
+}
+
+func (m *memoryRepository) CreateWorkflowStep(execID int64, stepNumber int, stepName, stepType string, config map[string]interface{}) (*database.WorkflowStepExecution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	step := &database.WorkflowStepExecution{
+		ID:                  m.id(),
+		WorkflowExecutionID: execID,
+		StepNumber:          stepNumber,
+		StepName:            stepName,
+		StepType:            stepType,
+		Status:              database.StepStatusPending,
+		StepConfig:          config,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	m.steps[step.ID] = step
+	copied := *step
+	return &copied, nil
+}
+
+func (m *memoryRepository) UpdateWorkflowStepStatus(stepID int64, status string, errorMessage *string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	step, ok := m.steps[stepID]
+	if !ok {
+		return fmt.Errorf("workflow step not found")
+	}
+	step.Status = status
+	step.ErrorMessage = errorMessage
+	step.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *memoryRepository) AddWorkflowStepLogs(stepID int64, logs string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	step, ok := m.steps[stepID]
+	if !ok {
+		return fmt.Errorf("workflow step not found")
+	}
+	output := logs
+	if step.OutputLogs != nil {
+		output = *step.OutputLogs + logs
+	}
+	step.OutputLogs = &output
+	return nil
+}
+
+func (m *memoryRepository) GetWorkflowExecution(id int64) (*database.WorkflowExecution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exec, ok := m.executions[id]
+	if !ok {
+		return nil, fmt.Errorf("workflow execution not found")
+	}
+	copied := *exec
+	copied.Steps = m.stepsOf(id)
+	return &copied, nil
+}
+
+// stepsOf returns copies of the steps of an execution in step order
+func (m *memoryRepository) stepsOf(execID int64) []*database.WorkflowStepExecution {
+	var steps []*database.WorkflowStepExecution
+	for id := int64(1); id <= m.nextID; id++ {
+		if step, ok := m.steps[id]; ok && step.WorkflowExecutionID == execID {
+			copied := *step
+			steps = append(steps, &copied)
+		}
+	}
+	return steps
+}
+
+func (m *memoryRepository) CountWorkflowExecutions(appName, workflowName, status string) (int64, error) {
+	summaries, err := m.ListWorkflowExecutions(appName, workflowName, status, 0, 0)
+	return int64(len(summaries)), err
+}
+
+func (m *memoryRepository) ListWorkflowExecutions(appName, workflowName, status string, limit, offset int) ([]*database.WorkflowExecutionSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var summaries []*database.WorkflowExecutionSummary
+	for id := m.nextID; id > 0; id-- {
+		exec, ok := m.executions[id]
+		if !ok || (appName != "" && exec.ApplicationName != appName) ||
+			(workflowName != "" && exec.WorkflowName != workflowName) || (status != "" && exec.Status != status) {
+			continue
+		}
+		summary := &database.WorkflowExecutionSummary{
+			ID:              exec.ID,
+			ApplicationName: exec.ApplicationName,
+			WorkflowName:    exec.WorkflowName,
+			Status:          exec.Status,
+			StartedAt:       exec.StartedAt,
+			CompletedAt:     exec.CompletedAt,
+			TotalSteps:      exec.TotalSteps,
+		}
+		for _, step := range m.stepsOf(id) {
+			switch step.Status {
+			case database.StepStatusCompleted:
+				summary.CompletedSteps++
+			case database.StepStatusFailed:
+				summary.FailedSteps++
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	if offset > len(summaries) {
+		offset = len(summaries)
+	}
+	summaries = summaries[offset:]
+	if limit > 0 && limit < len(summaries) {
+		summaries = summaries[:limit]
+	}
+	return summaries, nil
+}
+
+func (m *memoryRepository) GetLatestWorkflowExecution(appName, workflowName string) (*database.WorkflowExecution, error) {
+	summaries, err := m.ListWorkflowExecutions(appName, workflowName, "", 1, 0)
+	if err != nil || len(summaries) == 0 {
+		return nil, err
+	}
+	return m.GetWorkflowExecution(summaries[0].ID)
+}
+
+func (m *memoryRepository) GetFirstFailedStepNumber(executionID int64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, step := range m.stepsOf(executionID) {
+		if step.Status == database.StepStatusFailed {
+			return step.StepNumber, nil
+		}
+	}
+	return 0, fmt.Errorf("no failed step found")
+}
+
+func (m *memoryRepository) ReconstructWorkflowFromExecution(executionID int64) (map[string]interface{}, error) {
+	return nil, fmt.Errorf("retries are not part of a conformance run")
+}
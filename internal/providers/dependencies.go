@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"fmt"
+	"innominatus/pkg/sdk"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// checkDependencyConstraints verifies the version ranges of a provider's dependencies parse
+func checkDependencyConstraints(provider *sdk.Provider) error {
+	for _, dep := range provider.Dependencies {
+		if dep.Version == "" {
+			continue
+		}
+		if _, err := semver.NewConstraint(dep.Version); err != nil {
+			return fmt.Errorf("dependency %s: invalid version range %q: %w", dep.Name, dep.Version, err)
+		}
+	}
+	return nil
+}
+
+// checkDependencies verifies every dependency of provider is registered in a version within
+// its range. Callers hold r.mu.
+func (r *Registry) checkDependencies(provider *sdk.Provider) error {
+	for _, dep := range provider.Dependencies {
+		required, exists := r.providers[dep.Name]
+		if !exists {
+			return fmt.Errorf("provider %s requires provider %s, which is not registered", provider.Metadata.Name, dep.Name)
+		}
+		if dep.Version == "" {
+			continue
+		}
+
+		constraint, err := semver.NewConstraint(dep.Version)
+		if err != nil {
+			return fmt.Errorf("provider %s: invalid version range %q for %s: %w", provider.Metadata.Name, dep.Version, dep.Name, err)
+		}
+		version, err := semver.NewVersion(required.Metadata.Version)
+		if err != nil {
+			return fmt.Errorf("provider %s requires %s %s, but its version %q is not a semantic version",
+				provider.Metadata.Name, dep.Name, dep.Version, required.Metadata.Version)
+		}
+		if !constraint.Check(version) {
+			return fmt.Errorf("provider %s requires %s %s, but %s is registered",
+				provider.Metadata.Name, dep.Name, dep.Version, required.Metadata.Version)
+		}
+	}
+	return nil
+}
+
+// OrderByDependencies sorts providers so each comes after the providers it depends on,
+// keeping the given order otherwise. Providers on or behind a dependency cycle are left
+// out and named in the error; dependencies missing from the list are left to registration
+// to report.
+func OrderByDependencies(providers []*sdk.Provider) ([]*sdk.Provider, error) {
+	index := make(map[string]int, len(providers))
+	for i, provider := range providers {
+		index[provider.Metadata.Name] = i
+	}
+
+	// pending counts the unordered dependencies of each provider
+	pending := make([]int, len(providers))
+	dependents := make([][]int, len(providers))
+	for i, provider := range providers {
+		for _, dep := range provider.Dependencies {
+			if j, ok := index[dep.Name]; ok {
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	ordered := make([]*sdk.Provider, 0, len(providers))
+	done := make([]bool, len(providers))
+	for progress := true; progress; {
+		progress = false
+		for i, provider := range providers {
+			if done[i] || pending[i] > 0 {
+				continue
+			}
+			done[i] = true
+			progress = true
+			ordered = append(ordered, provider)
+			for _, j := range dependents[i] {
+				pending[j]--
+			}
+			// Restart so earlier providers unblocked by this one keep their position
+			break
+		}
+	}
+
+	if len(ordered) < len(providers) {
+		var cyclic []string
+		for i, provider := range providers {
+			if !done[i] {
+				cyclic = append(cyclic, provider.Metadata.Name)
+			}
+		}
+		return ordered, fmt.Errorf("providers not loaded because of a dependency cycle: %s", strings.Join(cyclic, ", "))
+	}
+	return ordered, nil
+}
@@ -0,0 +1,146 @@
+package providers_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"innominatus/internal/providers"
+	"innominatus/pkg/sdk"
+)
+
+func dependentProvider(name, version string, deps ...sdk.ProviderDependency) *sdk.Provider {
+	return &sdk.Provider{
+		APIVersion:    "innominatus.io/v1",
+		Kind:          "Provider",
+		Metadata:      sdk.ProviderMetadata{Name: name, Version: version},
+		Compatibility: sdk.ProviderCompatibility{MinCoreVersion: "1.0.0", MaxCoreVersion: "2.0.0"},
+		Dependencies:  deps,
+	}
+}
+
+func providerNames(list []*sdk.Provider) []string {
+	names := make([]string, 0, len(list))
+	for _, provider := range list {
+		names = append(names, provider.Metadata.Name)
+	}
+	return names
+}
+
+func TestOrderByDependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []*sdk.Provider
+		want      []string
+		wantErr   string
+	}{
+		{
+			name: "independent providers keep their order",
+			providers: []*sdk.Provider{
+				dependentProvider("kubernetes", "1.0.0"),
+				dependentProvider("database", "1.0.0"),
+			},
+			want: []string{"kubernetes", "database"},
+		},
+		{
+			name: "dependencies come first",
+			providers: []*sdk.Provider{
+				dependentProvider("gitops", "1.0.0", sdk.ProviderDependency{Name: "gitea"}, sdk.ProviderDependency{Name: "argocd"}),
+				dependentProvider("argocd", "1.0.0", sdk.ProviderDependency{Name: "gitea"}),
+				dependentProvider("database", "1.0.0"),
+				dependentProvider("gitea", "1.0.0"),
+			},
+			want: []string{"database", "gitea", "argocd", "gitops"},
+		},
+		{
+			name: "missing dependencies are left to registration",
+			providers: []*sdk.Provider{
+				dependentProvider("gitops", "1.0.0", sdk.ProviderDependency{Name: "gitea"}),
+			},
+			want: []string{"gitops"},
+		},
+		{
+			name: "cycles are left out",
+			providers: []*sdk.Provider{
+				dependentProvider("a", "1.0.0", sdk.ProviderDependency{Name: "b"}),
+				dependentProvider("b", "1.0.0", sdk.ProviderDependency{Name: "a"}),
+				dependentProvider("c", "1.0.0", sdk.ProviderDependency{Name: "a"}),
+				dependentProvider("database", "1.0.0"),
+			},
+			want:    []string{"database"},
+			wantErr: "a, b, c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered, err := providers.OrderByDependencies(tt.providers)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("OrderByDependencies() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("OrderByDependencies() error = %v, want it to name %s", err, tt.wantErr)
+			}
+			if got := providerNames(ordered); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OrderByDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryChecksDependencies(t *testing.T) {
+	registry := providers.NewRegistry()
+	gitops := dependentProvider("gitops", "1.0.0", sdk.ProviderDependency{Name: "gitea", Version: ">= 1.2.0, < 2.0.0"})
+
+	err := registry.RegisterProvider(gitops)
+	if err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Fatalf("Expected missing dependency error, got %v", err)
+	}
+
+	if err := registry.RegisterProvider(dependentProvider("gitea", "1.1.0")); err != nil {
+		t.Fatalf("Failed to register gitea: %v", err)
+	}
+	err = registry.RegisterProvider(gitops)
+	if err == nil || !strings.Contains(err.Error(), "requires gitea >= 1.2.0, < 2.0.0, but 1.1.0 is registered") {
+		t.Fatalf("Expected version range error, got %v", err)
+	}
+
+	registry.Clear()
+	if err := registry.RegisterProvider(dependentProvider("gitea", "1.4.2")); err != nil {
+		t.Fatalf("Failed to register gitea: %v", err)
+	}
+	if err := registry.RegisterProvider(gitops); err != nil {
+		t.Errorf("Expected gitops to register after gitea 1.4.2, got %v", err)
+	}
+}
+
+func TestLoaderRejectsInvalidDependencyRange(t *testing.T) {
+	tmpDir := t.TempDir()
+	manifest := `apiVersion: innominatus.io/v1
+kind: Provider
+metadata:
+  name: gitops
+  version: 1.0.0
+compatibility:
+  minCoreVersion: "1.0.0"
+  maxCoreVersion: "2.0.0"
+dependencies:
+  - name: gitea
+    version: "not a range"
+provisioners:
+  - name: repo
+    type: gitops-repo
+    version: 1.0.0
+`
+	path := filepath.Join(tmpDir, "provider.yaml")
+	if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write provider.yaml: %v", err)
+	}
+
+	_, err := providers.NewLoader("1.5.0").LoadFromFile(path)
+	if err == nil || !strings.Contains(err.Error(), "invalid version range") {
+		t.Errorf("Expected invalid version range error, got %v", err)
+	}
+}
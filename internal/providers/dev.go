@@ -0,0 +1,227 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits of a provider uploaded to the development channel
+const (
+	MaxDevProviderFiles = 500
+	MaxDevProviderBytes = 10 * 1024 * 1024
+)
+
+// DevProvider is a provider registered through the development channel
+type DevProvider struct {
+	Name         string    `json:"name"`
+	Version      string    `json:"version"`
+	Provisioners int       `json:"provisioners"`
+	Workflows    int       `json:"workflows"`
+	Revision     int       `json:"revision"` // Registrations since the first one
+	RegisteredBy string    `json:"registered_by"`
+	RegisteredAt time.Time `json:"registered_at"`
+	dir          string    // Upload directory
+	manifest     string    // provider.yaml in dir
+}
+
+// DevChannel registers providers that authors upload from their machine with
+// `innominatus-ctl provider dev`. Uploads are validated like configured providers and
+// replace the previous upload of the same provider; providers from admin-config.yaml
+// cannot be replaced.
+type DevChannel struct {
+	registry *Registry
+	loader   *Loader
+	dir      string
+	mu       sync.Mutex
+	dev      map[string]*DevProvider
+	now      func() time.Time
+}
+
+// NewDevChannel creates a development channel that keeps uploads below dir
+func NewDevChannel(registry *Registry, coreVersion, dir string) *DevChannel {
+	return &DevChannel{
+		registry: registry,
+		loader:   NewLoader(coreVersion),
+		dir:      dir,
+		dev:      make(map[string]*DevProvider),
+		now:      time.Now,
+	}
+}
+
+// Register validates the uploaded files of a provider, by path relative to the provider
+// directory, and registers it. Nothing changes when the provider is invalid.
+func (d *DevChannel) Register(files map[string][]byte, username string) (*DevProvider, error) {
+	if err := checkDevFiles(files); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(d.dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create development provider directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(d.dir, "provider-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create development provider directory: %w", err)
+	}
+	keep := false
+	defer func() {
+		if !keep {
+			_ = os.RemoveAll(dir)
+		}
+	}()
+
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	manifest := ""
+	for _, name := range []string{"provider.yaml", "provider.yml", "platform.yaml", "platform.yml"} {
+		if _, ok := files[name]; ok {
+			manifest = filepath.Join(dir, name)
+			break
+		}
+	}
+	if manifest == "" {
+		return nil, fmt.Errorf("no provider.yaml in the uploaded files")
+	}
+
+	provider, err := d.loader.LoadFromFile(manifest)
+	if err != nil {
+		return nil, err
+	}
+	name := provider.Metadata.Name
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	previous := d.dev[name]
+	if previous == nil {
+		if _, err := d.registry.GetProvider(name); err == nil {
+			return nil, fmt.Errorf("provider %s is registered from admin-config.yaml; rename it in provider.yaml to develop it", name)
+		}
+	}
+
+	// Workflow files are read into the asset cache, so a failed warm-up keeps the
+	// previous upload serving
+	if err := d.registry.Assets().Warm(provider); err != nil {
+		return nil, err
+	}
+	if err := d.registry.ReplaceProvider(provider); err != nil {
+		if previous == nil {
+			d.registry.Assets().Remove(name)
+		} else if prev, getErr := d.registry.GetProvider(name); getErr == nil {
+			_ = d.registry.Assets().Warm(prev)
+		}
+		return nil, err
+	}
+	keep = true
+
+	registration := &DevProvider{
+		Name:         name,
+		Version:      provider.Metadata.Version,
+		Provisioners: len(provider.Provisioners),
+		Workflows:    len(provider.Workflows),
+		Revision:     1,
+		RegisteredBy: username,
+		RegisteredAt: d.now(),
+		dir:          dir,
+		manifest:     manifest,
+	}
+	if previous != nil {
+		registration.Revision = previous.Revision + 1
+		_ = os.RemoveAll(previous.dir)
+	}
+	d.dev[name] = registration
+
+	result := *registration
+	return &result, nil
+}
+
+// Unregister removes a provider registered through the development channel
+func (d *DevChannel) Unregister(name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	registration, ok := d.dev[name]
+	if !ok {
+		return fmt.Errorf("provider %s is not registered for development", name)
+	}
+	d.registry.RemoveProvider(name)
+	delete(d.dev, name)
+	_ = os.RemoveAll(registration.dir)
+	return nil
+}
+
+// Restore registers the development providers again after the registry was reloaded
+// from admin-config.yaml. Providers that are now configured, or whose upload no longer
+// loads, are dropped.
+func (d *DevChannel) Restore() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for name, registration := range d.dev {
+		if _, err := d.registry.GetProvider(name); err == nil {
+			delete(d.dev, name)
+			_ = os.RemoveAll(registration.dir)
+			continue
+		}
+		provider, err := d.loader.LoadFromFile(registration.manifest)
+		if err == nil {
+			err = d.registry.Assets().Warm(provider)
+		}
+		if err == nil {
+			err = d.registry.RegisterProvider(provider)
+		}
+		if err != nil {
+			d.registry.Assets().Remove(name)
+			delete(d.dev, name)
+			_ = os.RemoveAll(registration.dir)
+		}
+	}
+}
+
+// List returns the providers registered through the development channel by name
+func (d *DevChannel) List() []DevProvider {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list := make([]DevProvider, 0, len(d.dev))
+	for _, registration := range d.dev {
+		list = append(list, *registration)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// checkDevFiles rejects uploads that are too large or whose paths leave the provider
+// directory
+func checkDevFiles(files map[string][]byte) error {
+	if len(files) == 0 {
+		return fmt.Errorf("no files uploaded")
+	}
+	if len(files) > MaxDevProviderFiles {
+		return fmt.Errorf("too many files: %d (at most %d)", len(files), MaxDevProviderFiles)
+	}
+	total := 0
+	for name, data := range files {
+		cleaned := filepath.ToSlash(filepath.Clean(filepath.FromSlash(name)))
+		if name == "" || cleaned != name || filepath.IsAbs(name) || strings.HasPrefix(name, "/") || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return fmt.Errorf("invalid file path '%s': must be relative to the provider directory", name)
+		}
+		total += len(data)
+	}
+	if total > MaxDevProviderBytes {
+		return fmt.Errorf("upload too large: %d bytes (at most %d)", total, MaxDevProviderBytes)
+	}
+	return nil
+}
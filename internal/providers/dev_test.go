@@ -0,0 +1,118 @@
+package providers_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"innominatus/internal/providers"
+)
+
+// testTeamFiles returns the files of the test-team provider as uploaded by the CLI
+func testTeamFiles(t *testing.T) map[string][]byte {
+	t.Helper()
+	files := map[string][]byte{}
+	for _, file := range []string{"provider.yaml", "workflows/provision-test-db.yaml"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "providers", "test-team", file))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		files[file] = data
+	}
+	return files
+}
+
+func TestDevChannelRegister(t *testing.T) {
+	registry := providers.NewRegistry()
+	channel := providers.NewDevChannel(registry, "1.5.0", t.TempDir())
+
+	files := testTeamFiles(t)
+	registration, err := channel.Register(files, "alice")
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if registration.Name != "test-team" || registration.Revision != 1 || registration.Workflows != 1 || registration.RegisteredBy != "alice" {
+		t.Fatalf("Unexpected registration: %+v", registration)
+	}
+	if _, err := registry.GetProvider("test-team"); err != nil {
+		t.Fatalf("Provider not registered: %v", err)
+	}
+	if _, err := registry.Assets().Workflow("test-team", "workflows/provision-test-db.yaml"); err != nil {
+		t.Fatalf("Workflow not cached: %v", err)
+	}
+
+	// A broken workflow is reported and the previous upload keeps serving
+	broken := testTeamFiles(t)
+	broken["workflows/provision-test-db.yaml"] = []byte("steps: [")
+	if _, err := channel.Register(broken, "alice"); err == nil || !strings.Contains(err.Error(), "provision-test-db") {
+		t.Fatalf("Expected workflow validation error, got %v", err)
+	}
+	if _, err := registry.Assets().Workflow("test-team", "workflows/provision-test-db.yaml"); err != nil {
+		t.Fatalf("Previous workflow no longer cached: %v", err)
+	}
+
+	registration, err = channel.Register(files, "alice")
+	if err != nil {
+		t.Fatalf("Re-register failed: %v", err)
+	}
+
+	// A reload from admin-config.yaml clears the registry; Restore brings the upload back
+	registry.Clear()
+	channel.Restore()
+	if _, err := registry.GetProvider("test-team"); err != nil {
+		t.Fatalf("Provider not restored: %v", err)
+	}
+	if registration.Revision != 2 {
+		t.Fatalf("Expected revision 2, got %d", registration.Revision)
+	}
+	if list := channel.List(); len(list) != 1 || list[0].Revision != 2 {
+		t.Fatalf("Unexpected list: %+v", list)
+	}
+
+	if err := channel.Unregister("test-team"); err != nil {
+		t.Fatalf("Unregister failed: %v", err)
+	}
+	if _, err := registry.GetProvider("test-team"); err == nil {
+		t.Fatal("Provider still registered after Unregister")
+	}
+	if err := channel.Unregister("test-team"); err == nil {
+		t.Fatal("Expected error unregistering an unknown provider")
+	}
+}
+
+func TestDevChannelRejectsConfiguredProvider(t *testing.T) {
+	dir, _ := providerRepo(t)
+	provider, err := providers.NewLoader("1.5.0").LoadFromFile(filepath.Join(dir, "provider.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load provider: %v", err)
+	}
+	registry := providers.NewRegistry()
+	if err := registry.RegisterProvider(provider); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	channel := providers.NewDevChannel(registry, "1.5.0", t.TempDir())
+	if _, err := channel.Register(testTeamFiles(t), "alice"); err == nil || !strings.Contains(err.Error(), "admin-config.yaml") {
+		t.Fatalf("Expected configured provider to be protected, got %v", err)
+	}
+}
+
+func TestDevChannelRejectsInvalidUploads(t *testing.T) {
+	channel := providers.NewDevChannel(providers.NewRegistry(), "1.5.0", t.TempDir())
+
+	tests := map[string]map[string][]byte{
+		"no files":      {},
+		"parent path":   {"../provider.yaml": []byte("x")},
+		"absolute path": {"/etc/provider.yaml": []byte("x")},
+		"no manifest":   {"workflows/a.yaml": []byte("x")},
+		"bad manifest":  {"provider.yaml": []byte("kind: Provider\n")},
+	}
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := channel.Register(files, "alice"); err == nil {
+				t.Fatal("Expected error")
+			}
+		})
+	}
+}
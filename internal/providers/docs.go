@@ -0,0 +1,294 @@
+package providers
+
+import (
+	"fmt"
+	"innominatus/internal/types"
+	"innominatus/pkg/sdk"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderDocs is the rendered markdown documentation of a provider
+type ProviderDocs struct {
+	Provider     string            `json:"provider"`
+	Version      string            `json:"version"`
+	Markdown     string            `json:"markdown"` // Readme followed by the sections of all provisioners
+	Provisioners []ProvisionerDocs `json:"provisioners"`
+}
+
+// ProvisionerDocs is the rendered markdown documentation of one provider workflow
+type ProvisionerDocs struct {
+	Name      string `json:"name"`
+	Category  string `json:"category"`
+	Operation string `json:"operation,omitempty"`
+	Markdown  string `json:"markdown"`
+}
+
+// workflowInterface is the part of a workflow file its callers need to know
+type workflowInterface struct {
+	Parameters []WorkflowParameter             `yaml:"parameters,omitempty"` // Provider workflow format
+	Inputs     []types.WorkflowInput           `yaml:"inputs,omitempty"`
+	Outputs    map[string]types.WorkflowOutput `yaml:"outputs,omitempty"`
+}
+
+// WorkflowParameter is a configuration parameter a provider workflow accepts
+type WorkflowParameter struct {
+	Name        string      `yaml:"name" json:"name"`
+	Type        string      `yaml:"type,omitempty" json:"type,omitempty"`
+	Required    bool        `yaml:"required,omitempty" json:"required,omitempty"`
+	Default     interface{} `yaml:"default,omitempty" json:"default,omitempty"`
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// WorkflowParameters returns the parameters a provider workflow declares, from either
+// the provider workflow parameters or the inputs of a workflow contract. Providers
+// registered without a directory have no workflow file to read and return none.
+func WorkflowParameters(provider *sdk.Provider, wf sdk.WorkflowMetadata) ([]WorkflowParameter, error) {
+	iface, err := readWorkflowInterface(provider, wf)
+	if err != nil {
+		return nil, err
+	}
+	return iface.parameters(), nil
+}
+
+func readWorkflowInterface(provider *sdk.Provider, wf sdk.WorkflowMetadata) (workflowInterface, error) {
+	var iface workflowInterface
+	source, err := readProviderFile(provider, wf.File)
+	if err != nil {
+		return iface, fmt.Errorf("failed to read workflow file: %w", err)
+	}
+	if err := yaml.Unmarshal([]byte(source), &iface); err != nil {
+		return iface, fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+	return iface, nil
+}
+
+func (w workflowInterface) parameters() []WorkflowParameter {
+	params := w.Parameters
+	for _, input := range w.Inputs {
+		param := WorkflowParameter{Name: input.Name, Required: input.Required, Description: input.Description}
+		if input.Default != "" {
+			param.Default = input.Default
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// RenderDocs renders the documentation of a provider: its readme and, per workflow, the
+// docs file, the configuration parameters and outputs declared in the workflow file and
+// the example files listed in provider.yaml. Files are read from the provider directory,
+// so providers registered without one only get the sections provider.yaml describes.
+func RenderDocs(provider *sdk.Provider) (*ProviderDocs, error) {
+	docs := &ProviderDocs{
+		Provider:     provider.Metadata.Name,
+		Version:      provider.Metadata.Version,
+		Provisioners: make([]ProvisionerDocs, 0, len(provider.Workflows)+len(provider.Provisioners)),
+	}
+
+	var md strings.Builder
+	fmt.Fprintf(&md, "# %s v%s\n\n", provider.Metadata.Name, provider.Metadata.Version)
+	if provider.Metadata.Description != "" {
+		fmt.Fprintf(&md, "%s\n\n", provider.Metadata.Description)
+	}
+	readme, err := readProviderFile(provider, provider.Readme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read readme: %w", err)
+	}
+	if readme != "" {
+		fmt.Fprintf(&md, "%s\n\n", strings.TrimSpace(readme))
+	}
+
+	for _, wf := range provider.Workflows {
+		section, err := renderWorkflowDocs(provider, wf)
+		if err != nil {
+			return nil, fmt.Errorf("workflow '%s': %w", wf.Name, err)
+		}
+		category := wf.Category
+		if category == "" {
+			category = "provisioner"
+		}
+		docs.Provisioners = append(docs.Provisioners, ProvisionerDocs{
+			Name:      wf.Name,
+			Category:  category,
+			Operation: wf.Operation,
+			Markdown:  section,
+		})
+		md.WriteString(section)
+	}
+
+	// Deprecated provisioners have no workflow file, only their metadata is known
+	for _, p := range provider.Provisioners {
+		var section strings.Builder
+		fmt.Fprintf(&section, "## %s\n\n", p.Name)
+		if p.Description != "" {
+			fmt.Fprintf(&section, "%s\n\n", p.Description)
+		}
+		fmt.Fprintf(&section, "**Type:** %s · **Version:** %s\n\n", p.Type, p.Version)
+		docs.Provisioners = append(docs.Provisioners, ProvisionerDocs{
+			Name:     p.Name,
+			Category: "provisioner",
+			Markdown: section.String(),
+		})
+		md.WriteString(section.String())
+	}
+
+	docs.Markdown = strings.TrimRight(md.String(), "\n") + "\n"
+	return docs, nil
+}
+
+// renderWorkflowDocs renders the markdown section of one workflow
+func renderWorkflowDocs(provider *sdk.Provider, wf sdk.WorkflowMetadata) (string, error) {
+	var md strings.Builder
+	fmt.Fprintf(&md, "## %s\n\n", wf.Name)
+	if wf.Description != "" {
+		fmt.Fprintf(&md, "%s\n\n", wf.Description)
+	}
+
+	var facts []string
+	if wf.Category != "" {
+		facts = append(facts, "**Category:** "+wf.Category)
+	}
+	if wf.Operation != "" {
+		facts = append(facts, "**Operation:** "+wf.Operation)
+	}
+	if wf.Version != "" {
+		facts = append(facts, "**Version:** "+wf.Version)
+	}
+	if len(wf.Tags) > 0 {
+		facts = append(facts, "**Tags:** "+strings.Join(wf.Tags, ", "))
+	}
+	if len(facts) > 0 {
+		fmt.Fprintf(&md, "%s\n\n", strings.Join(facts, " · "))
+	}
+
+	text, err := readProviderFile(provider, wf.Docs)
+	if err != nil {
+		return "", fmt.Errorf("failed to read docs: %w", err)
+	}
+	if text != "" {
+		fmt.Fprintf(&md, "%s\n\n", strings.TrimSpace(text))
+	}
+
+	iface, err := readWorkflowInterface(provider, wf)
+	if err != nil {
+		return "", err
+	}
+	renderParameters(&md, iface.parameters())
+	renderOutputs(&md, iface.Outputs)
+
+	if len(wf.Examples) > 0 {
+		md.WriteString("### Examples\n\n")
+		for _, example := range wf.Examples {
+			content, err := readProviderFile(provider, example)
+			if err != nil {
+				return "", fmt.Errorf("failed to read example: %w", err)
+			}
+			if content == "" {
+				continue
+			}
+			fmt.Fprintf(&md, "#### %s\n\n```%s\n%s\n```\n\n", filepath.Base(example), fenceLanguage(example), strings.TrimRight(content, "\n"))
+		}
+	}
+
+	return md.String(), nil
+}
+
+// renderParameters writes the parameters table of a workflow
+func renderParameters(md *strings.Builder, params []WorkflowParameter) {
+	if len(params) == 0 {
+		return
+	}
+
+	md.WriteString("### Parameters\n\n")
+	md.WriteString("| Name | Type | Required | Default | Description |\n")
+	md.WriteString("|------|------|----------|---------|-------------|\n")
+	for _, p := range params {
+		required := "no"
+		if p.Required {
+			required = "yes"
+		}
+		paramType := p.Type
+		if paramType == "" {
+			paramType = "string"
+		}
+		def := ""
+		if p.Default != nil {
+			def = "`" + tableCell(fmt.Sprint(p.Default)) + "`"
+		}
+		fmt.Fprintf(md, "| `%s` | %s | %s | %s | %s |\n", p.Name, paramType, required, def, tableCell(p.Description))
+	}
+	md.WriteString("\n")
+}
+
+// renderOutputs writes the outputs table of a workflow, sorted by name
+func renderOutputs(md *strings.Builder, outputs map[string]types.WorkflowOutput) {
+	if len(outputs) == 0 {
+		return
+	}
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	md.WriteString("### Outputs\n\n")
+	md.WriteString("| Name | Type | Description |\n")
+	md.WriteString("|------|------|-------------|\n")
+	for _, name := range names {
+		out := outputs[name]
+		outputType := out.Type
+		if outputType == "" {
+			outputType = "text"
+		}
+		description := tableCell(out.Description)
+		if description == "" && out.Value != "" {
+			description = "`" + tableCell(out.Value) + "`"
+		}
+		fmt.Fprintf(md, "| `%s` | %s | %s |\n", name, outputType, description)
+	}
+	md.WriteString("\n")
+}
+
+// readProviderFile reads a file referenced by provider.yaml. It returns an empty string
+// when there is no file to read or the provider was not loaded from a directory.
+func readProviderFile(provider *sdk.Provider, rel string) (string, error) {
+	if rel == "" || provider.Dir == "" {
+		return "", nil
+	}
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%s is outside the provider directory", rel)
+	}
+	// #nosec G304 -- rel is checked to stay inside the provider directory
+	data, err := os.ReadFile(filepath.Join(provider.Dir, rel))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// tableCell keeps text on one line of a markdown table
+func tableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// fenceLanguage returns the code fence language of an example file
+func fenceLanguage(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	case ".sh":
+		return "bash"
+	case ".tf":
+		return "hcl"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,137 @@
+package providers_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"innominatus/internal/providers"
+	"innominatus/pkg/sdk"
+)
+
+func TestRenderDocs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"provider.yaml": `apiVersion: v1
+kind: Provider
+metadata:
+  name: cache-team
+  version: 1.2.0
+  description: Redis caches
+readme: ./README.md
+compatibility:
+  minCoreVersion: 1.0.0
+  maxCoreVersion: 2.0.0
+workflows:
+  - name: provision-redis
+    file: ./workflows/provision-redis.yaml
+    description: Create a Redis instance
+    category: provisioner
+    operation: create
+    docs: ./docs/provision-redis.md
+    examples: [./examples/app.yaml]
+`,
+		"README.md":               "Caches are shared per team.\n",
+		"docs/provision-redis.md": "Instances are evicted with allkeys-lru.\n",
+		"examples/app.yaml":       "resources:\n  cache:\n    type: redis\n",
+		"workflows/provision-redis.yaml": `parameters:
+  - name: memory
+    type: string
+    required: false
+    default: 256Mi
+    description: Memory limit | per replica
+  - name: app_name
+    required: true
+    description: Application name
+steps:
+  - name: create
+    type: policy
+    config:
+      script: echo created
+outputs:
+  host: "{{ .parameters.app_name }}-redis"
+  url:
+    value: redis://cache
+    type: url
+    description: Connection URL
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	provider, err := providers.NewLoader("dev").LoadFromFile(filepath.Join(dir, "provider.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load provider: %v", err)
+	}
+
+	docs, err := providers.RenderDocs(provider)
+	if err != nil {
+		t.Fatalf("Failed to render docs: %v", err)
+	}
+	if len(docs.Provisioners) != 1 || docs.Provisioners[0].Operation != "create" {
+		t.Fatalf("Expected one create provisioner, got %+v", docs.Provisioners)
+	}
+
+	section := docs.Provisioners[0].Markdown
+	for _, want := range []string{
+		"## provision-redis",
+		"Instances are evicted with allkeys-lru.",
+		"| `memory` | string | no | `256Mi` | Memory limit \\| per replica |",
+		"| `app_name` | string | yes |  | Application name |",
+		"| `host` | text | `{{ .parameters.app_name }}-redis` |",
+		"| `url` | url | Connection URL |",
+		"#### app.yaml\n\n```yaml\nresources:\n  cache:\n    type: redis\n```",
+	} {
+		if !strings.Contains(section, want) {
+			t.Errorf("Expected provisioner docs to contain %q, got:\n%s", want, section)
+		}
+	}
+
+	if !strings.HasPrefix(docs.Markdown, "# cache-team v1.2.0\n\nRedis caches\n\nCaches are shared per team.\n") {
+		t.Errorf("Expected provider docs to start with the readme, got:\n%s", docs.Markdown)
+	}
+	if !strings.Contains(docs.Markdown, strings.TrimSpace(section)) {
+		t.Error("Expected provider docs to contain the provisioner section")
+	}
+}
+
+func TestRenderDocsWithoutProviderDirectory(t *testing.T) {
+	provider := &sdk.Provider{
+		Metadata: sdk.ProviderMetadata{Name: "inline", Version: "1.0.0"},
+		Readme:   "./README.md",
+		Workflows: []sdk.WorkflowMetadata{
+			{Name: "deploy", File: "./deploy.yaml", Description: "Deploy the app"},
+		},
+	}
+
+	docs, err := providers.RenderDocs(provider)
+	if err != nil {
+		t.Fatalf("Failed to render docs: %v", err)
+	}
+	if !strings.Contains(docs.Markdown, "## deploy\n\nDeploy the app\n") {
+		t.Errorf("Expected docs from provider.yaml metadata, got:\n%s", docs.Markdown)
+	}
+	if docs.Provisioners[0].Category != "provisioner" {
+		t.Errorf("Expected default category 'provisioner', got '%s'", docs.Provisioners[0].Category)
+	}
+}
+
+func TestRenderDocsMissingFile(t *testing.T) {
+	provider := &sdk.Provider{
+		Metadata: sdk.ProviderMetadata{Name: "broken", Version: "1.0.0"},
+		Readme:   "./README.md",
+		Dir:      t.TempDir(),
+	}
+
+	if _, err := providers.RenderDocs(provider); err == nil {
+		t.Error("Expected an error for a missing readme")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
 )
 
 // GitProviderSource defines a Git repository source for a provider
@@ -74,9 +75,7 @@ func (g *GitLoader) LoadFromGit(source GitProviderSource) (*sdk.Provider, error)
 
 // cloneOrPull clones the repository if it doesn't exist, or pulls if it does
 func (g *GitLoader) cloneOrPull(source GitProviderSource) (string, error) {
-	// Sanitize repository name for directory
-	repoName := sanitizeRepoName(source.Repository)
-	localPath := filepath.Join(g.cacheDir, source.Name, repoName)
+	localPath := g.localPath(source)
 
 	// Check if repository already exists
 	if _, err := os.Stat(filepath.Join(localPath, ".git")); err == nil {
@@ -163,6 +162,52 @@ func (g *GitLoader) cloneOrPull(source GitProviderSource) (string, error) {
 	return localPath, nil
 }
 
+// RemoteRef returns the hash the ref of source points to in the remote repository
+func (g *GitLoader) RemoteRef(source GitProviderSource) (string, error) {
+	checkoutOpts, err := g.getCheckoutOptions(source.Ref)
+	if err != nil {
+		return "", err
+	}
+
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{source.Repository},
+	})
+	refs, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list remote refs: %w", err)
+	}
+	for _, ref := range refs {
+		if ref.Name() == checkoutOpts.Branch {
+			return ref.Hash().String(), nil
+		}
+	}
+	return "", fmt.Errorf("ref %s not found in %s", source.Ref, source.Repository)
+}
+
+// LocalRef returns the hash the ref of source points to in the local clone, or an
+// empty string when there is no clone (e.g. /tmp was cleaned up)
+func (g *GitLoader) LocalRef(source GitProviderSource) string {
+	repo, err := git.PlainOpen(g.localPath(source))
+	if err != nil {
+		return ""
+	}
+	checkoutOpts, err := g.getCheckoutOptions(source.Ref)
+	if err != nil {
+		return ""
+	}
+	ref, err := repo.Reference(checkoutOpts.Branch, false)
+	if err != nil {
+		return ""
+	}
+	return ref.Hash().String()
+}
+
+// localPath returns the directory source is cloned to
+func (g *GitLoader) localPath(source GitProviderSource) string {
+	return filepath.Join(g.cacheDir, source.Name, sanitizeRepoName(source.Repository))
+}
+
 // getCheckoutOptions determines checkout options based on ref (tag or branch)
 func (g *GitLoader) getCheckoutOptions(ref string) (*git.CheckoutOptions, error) {
 	// Try as a tag first
@@ -53,11 +53,17 @@ func (l *Loader) LoadFromFile(path string) (*sdk.Provider, error) {
 		return nil, fmt.Errorf("provider compatibility check failed: %w", err)
 	}
 
+	// Check dependency version ranges
+	if err := checkDependencyConstraints(&provider); err != nil {
+		return nil, fmt.Errorf("invalid provider manifest: %w", err)
+	}
+
 	// Validate all workflow files (use directory of provider.yaml as base)
 	providerDir := filepath.Dir(path)
 	if err := l.validateProviderWorkflows(providerDir, &provider); err != nil {
 		return nil, fmt.Errorf("provider workflow validation failed: %w", err)
 	}
+	provider.Dir = providerDir
 
 	return &provider, nil
 }
@@ -108,6 +114,12 @@ func (l *Loader) LoadFromDirectory(dirPath string) ([]*sdk.Provider, error) {
 		return nil, fmt.Errorf("provider conflicts detected: %w", err)
 	}
 
+	// Dependencies come before their dependents
+	providers, err = OrderByDependencies(providers)
+	if err != nil {
+		return nil, err
+	}
+
 	return providers, nil
 }
 
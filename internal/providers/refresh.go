@@ -0,0 +1,117 @@
+package providers
+
+import (
+	"fmt"
+	"innominatus/internal/logging"
+	"sync"
+)
+
+// RefreshResult describes the refresh of one Git provider
+type RefreshResult struct {
+	Provider  string `json:"provider"`
+	Commit    string `json:"commit,omitempty"` // Hash the ref points to
+	Refreshed bool   `json:"refreshed"`        // The provider was reloaded
+	Error     string `json:"error,omitempty"`  // The provider keeps serving its previous assets
+	Reason    string `json:"reason,omitempty"` // Why the provider was reloaded
+}
+
+// GitRefresher reloads Git-sourced providers whose ref moved to another commit, or
+// whose clone disappeared, and swaps them into the registry once their assets pass
+// warm-up
+type GitRefresher struct {
+	loader   *GitLoader
+	registry *Registry
+	mu       sync.Mutex
+	sources  []GitProviderSource
+	logger   *logging.ZerologAdapter
+}
+
+// NewGitRefresher creates a refresher for the providers of registry cloned by loader
+func NewGitRefresher(loader *GitLoader, registry *Registry) *GitRefresher {
+	return &GitRefresher{
+		loader:   loader,
+		registry: registry,
+		logger:   logging.NewStructuredLogger("providers.git"),
+	}
+}
+
+// SetSources replaces the Git providers to refresh, e.g. after the admin config was reloaded
+func (r *GitRefresher) SetSources(sources []GitProviderSource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources = sources
+}
+
+// RefreshAll refreshes every Git provider
+func (r *GitRefresher) RefreshAll() []RefreshResult {
+	r.mu.Lock()
+	sources := append([]GitProviderSource(nil), r.sources...)
+	r.mu.Unlock()
+
+	results := make([]RefreshResult, 0, len(sources))
+	for _, source := range sources {
+		results = append(results, r.Refresh(source))
+	}
+	return results
+}
+
+// Refresh reloads source when its ref moved or its clone is gone. A provider that fails
+// to load or warm up keeps serving the assets cached before.
+func (r *GitRefresher) Refresh(source GitProviderSource) RefreshResult {
+	result := RefreshResult{Provider: source.Name}
+
+	remote, err := r.loader.RemoteRef(source)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Commit = remote
+
+	switch local := r.loader.LocalRef(source); {
+	case local == "":
+		result.Reason = "clone missing"
+	case local != remote:
+		result.Reason = fmt.Sprintf("ref %s moved from %s", source.Ref, shortHash(local))
+	default:
+		return result
+	}
+
+	provider, err := r.loader.LoadFromGit(source)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Provider = provider.Metadata.Name
+	if r.registry.HasRetainedVersion(provider.Metadata.Name, provider.Metadata.Version) {
+		// A version kept for pins, e.g. a moved release branch
+		if err := r.registry.RetainProviderVersion(provider); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	} else {
+		if err := r.registry.Assets().Warm(provider); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		if err := r.registry.ReplaceProvider(provider); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+	result.Refreshed = true
+
+	r.logger.InfoWithFields("Provider refreshed from Git", map[string]interface{}{
+		"name":    provider.Metadata.Name,
+		"version": provider.Metadata.Version,
+		"commit":  shortHash(remote),
+		"reason":  result.Reason,
+	})
+	return result
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -9,18 +9,27 @@ import (
 // Registry manages loaded providers and their provisioners
 type Registry struct {
 	mu           sync.RWMutex
-	providers    map[string]*sdk.Provider   // name -> provider
-	provisioners map[string]sdk.Provisioner // type -> provisioner
+	providers    map[string]*sdk.Provider            // name -> provider
+	versions     map[string]map[string]*sdk.Provider // name -> version -> provider kept for pins
+	provisioners map[string]sdk.Provisioner          // type -> provisioner
+	assets       *AssetCache                         // workflow files of the providers
 }
 
 // NewRegistry creates a new provider registry
 func NewRegistry() *Registry {
 	return &Registry{
 		providers:    make(map[string]*sdk.Provider),
+		versions:     make(map[string]map[string]*sdk.Provider),
 		provisioners: make(map[string]sdk.Provisioner),
+		assets:       NewAssetCache(),
 	}
 }
 
+// Assets returns the cache of the providers' workflow files
+func (r *Registry) Assets() *AssetCache {
+	return r.assets
+}
+
 // RegisterProvider registers a provider in the registry
 func (r *Registry) RegisterProvider(provider *sdk.Provider) error {
 	r.mu.Lock()
@@ -31,10 +40,43 @@ func (r *Registry) RegisterProvider(provider *sdk.Provider) error {
 		return fmt.Errorf("provider %s is already registered", provider.Metadata.Name)
 	}
 
+	// Dependencies must be registered first
+	if err := r.checkDependencies(provider); err != nil {
+		return err
+	}
+
 	r.providers[provider.Metadata.Name] = provider
 	return nil
 }
 
+// ReplaceProvider registers provider in place of a registered provider of the same name,
+// e.g. after its Git ref moved
+func (r *Registry) ReplaceProvider(provider *sdk.Provider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkDependencies(provider); err != nil {
+		return err
+	}
+
+	r.providers[provider.Metadata.Name] = provider
+	return nil
+}
+
+// RemoveProvider removes a provider, its retained versions and their cached workflow
+// files from the registry
+func (r *Registry) RemoveProvider(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.providers, name)
+	r.assets.Remove(name)
+	for version := range r.versions[name] {
+		r.assets.Remove(versionKey(name, version))
+	}
+	delete(r.versions, name)
+}
+
 // RegisterProvisioner registers a provisioner in the registry
 func (r *Registry) RegisterProvisioner(provisioner sdk.Provisioner) error {
 	r.mu.Lock()
@@ -144,5 +186,7 @@ func (r *Registry) Clear() {
 	defer r.mu.Unlock()
 
 	r.providers = make(map[string]*sdk.Provider)
+	r.versions = make(map[string]map[string]*sdk.Provider)
 	r.provisioners = make(map[string]sdk.Provisioner)
+	r.assets.Clear()
 }
@@ -0,0 +1,252 @@
+package providers
+
+import (
+	"fmt"
+	"innominatus/pkg/sdk"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// versionKey is the asset cache key of a retained provider version
+func versionKey(name, version string) string {
+	return name + "@" + version
+}
+
+// RetainProviderVersion keeps another version of a registered provider loaded, so
+// applications that pinned it keep running it after the provider was upgraded. The
+// workflow files of the version are cached at once; a retained version of the same
+// number is replaced, e.g. after its Git ref moved.
+func (r *Registry) RetainProviderVersion(provider *sdk.Provider) error {
+	name, version := provider.Metadata.Name, provider.Metadata.Version
+	if version == "" {
+		return fmt.Errorf("provider %s: versions without a number cannot be retained", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, exists := r.providers[name]
+	if !exists {
+		return fmt.Errorf("provider %s must be registered before other versions are retained", name)
+	}
+	if current.Metadata.Version == version {
+		return fmt.Errorf("provider %s %s is already the registered version", name, version)
+	}
+	if err := r.checkDependencies(provider); err != nil {
+		return err
+	}
+	if err := r.assets.warm(versionKey(name, version), provider); err != nil {
+		return err
+	}
+
+	if r.versions[name] == nil {
+		r.versions[name] = make(map[string]*sdk.Provider)
+	}
+	r.versions[name][version] = provider
+	return nil
+}
+
+// HasRetainedVersion reports whether version of a provider is kept next to its
+// registered version
+func (r *Registry) HasRetainedVersion(name, version string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.versions[name][version]
+	return exists
+}
+
+// GetProviderVersion returns a loaded version of a provider, registered or retained
+func (r *Registry) GetProviderVersion(name, version string) (*sdk.Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	current, exists := r.providers[name]
+	if !exists {
+		return nil, fmt.Errorf("provider %s not found", name)
+	}
+	if current.Metadata.Version == version {
+		return current, nil
+	}
+	if provider, ok := r.versions[name][version]; ok {
+		return provider, nil
+	}
+	return nil, fmt.Errorf("provider %s has no version %s loaded (loaded: %v)", name, version, r.providerVersions(name))
+}
+
+// ProviderVersions returns the loaded versions of a provider, oldest first
+func (r *Registry) ProviderVersions(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.providerVersions(name)
+}
+
+// providerVersions returns the loaded versions of a provider. Callers hold r.mu.
+func (r *Registry) providerVersions(name string) []string {
+	var versions []string
+	if current, exists := r.providers[name]; exists {
+		versions = append(versions, current.Metadata.Version)
+	}
+	for version := range r.versions[name] {
+		versions = append(versions, version)
+	}
+	sortVersions(versions)
+	return versions
+}
+
+// WorkflowAsset returns the cached contents of a workflow file of a registered or
+// retained provider version
+func (r *Registry) WorkflowAsset(provider *sdk.Provider, file string) ([]byte, error) {
+	r.mu.RLock()
+	key := provider.Metadata.Name
+	if r.providers[key] != provider {
+		if _, retained := r.versions[key][provider.Metadata.Version]; retained {
+			key = versionKey(key, provider.Metadata.Version)
+		}
+	}
+	r.mu.RUnlock()
+
+	return r.assets.Workflow(key, file)
+}
+
+// VersionChanges describes what changes for an application moving from one version
+// of a provider to another
+type VersionChanges struct {
+	ResourceTypesAdded   []string `json:"resource_types_added,omitempty"`
+	ResourceTypesRemoved []string `json:"resource_types_removed,omitempty"`
+	WorkflowsAdded       []string `json:"workflows_added,omitempty"`
+	WorkflowsRemoved     []string `json:"workflows_removed,omitempty"`
+	WorkflowsChanged     []string `json:"workflows_changed,omitempty"` // Same name, different version or file contents
+}
+
+// CompareVersions returns the changes between two loaded versions of a provider
+func (r *Registry) CompareVersions(name, from, to string) (*VersionChanges, error) {
+	fromProvider, err := r.GetProviderVersion(name, from)
+	if err != nil {
+		return nil, err
+	}
+	toProvider, err := r.GetProviderVersion(name, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &VersionChanges{}
+	changes.ResourceTypesAdded, changes.ResourceTypesRemoved = diffNames(resourceTypes(fromProvider), resourceTypes(toProvider))
+
+	fromWorkflows := workflowsByName(fromProvider)
+	toWorkflows := workflowsByName(toProvider)
+	changes.WorkflowsAdded, changes.WorkflowsRemoved = diffNames(mapKeys(fromWorkflows), mapKeys(toWorkflows))
+	for _, workflowName := range mapKeys(toWorkflows) {
+		before, ok := fromWorkflows[workflowName]
+		if !ok {
+			continue
+		}
+		after := toWorkflows[workflowName]
+		if before.Version != after.Version || r.workflowDigest(fromProvider, before.File) != r.workflowDigest(toProvider, after.File) {
+			changes.WorkflowsChanged = append(changes.WorkflowsChanged, workflowName)
+		}
+	}
+	return changes, nil
+}
+
+// workflowDigest returns the digest of a cached workflow file, or "" if it is not cached
+func (r *Registry) workflowDigest(provider *sdk.Provider, file string) string {
+	data, err := r.WorkflowAsset(provider, file)
+	if err != nil {
+		return ""
+	}
+	return digest(data)
+}
+
+// resourceTypes returns the resource types a provider can provision
+func resourceTypes(provider *sdk.Provider) []string {
+	names := append([]string(nil), provider.Capabilities.ResourceTypes...)
+	for _, capability := range provider.Capabilities.ResourceTypeCapabilities {
+		names = append(names, capability.Type)
+	}
+	return names
+}
+
+func workflowsByName(provider *sdk.Provider) map[string]sdk.WorkflowMetadata {
+	workflows := make(map[string]sdk.WorkflowMetadata, len(provider.Workflows))
+	for _, workflowMeta := range provider.Workflows {
+		workflows[workflowMeta.Name] = workflowMeta
+	}
+	return workflows
+}
+
+func mapKeys(m map[string]sdk.WorkflowMetadata) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffNames returns the sorted names only in after (added) and only in before (removed)
+func diffNames(before, after []string) (added, removed []string) {
+	inBefore := make(map[string]bool, len(before))
+	for _, name := range before {
+		inBefore[name] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, name := range after {
+		inAfter[name] = true
+		if !inBefore[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range before {
+		if !inAfter[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// CompareVersionNumbers orders two provider versions: semantic versions by precedence,
+// anything else as strings
+func CompareVersionNumbers(a, b string) int {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA == nil && errB == nil {
+		return va.Compare(vb)
+	}
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func sortVersions(versions []string) {
+	sort.Slice(versions, func(i, j int) bool { return CompareVersionNumbers(versions[i], versions[j]) < 0 })
+}
+
+// SplitVersions separates loaded manifests into the providers to register, the newest
+// version of each name, and the older versions to retain for pins. The order of the
+// newest versions is kept.
+func SplitVersions(manifests []*sdk.Provider) (latest, retained []*sdk.Provider) {
+	newest := make(map[string]*sdk.Provider)
+	for _, provider := range manifests {
+		name := provider.Metadata.Name
+		if current, ok := newest[name]; !ok || CompareVersionNumbers(provider.Metadata.Version, current.Metadata.Version) > 0 {
+			newest[name] = provider
+		}
+	}
+	for _, provider := range manifests {
+		if newest[provider.Metadata.Name] == provider {
+			latest = append(latest, provider)
+		} else {
+			retained = append(retained, provider)
+		}
+	}
+	return latest, retained
+}
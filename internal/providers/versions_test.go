@@ -0,0 +1,123 @@
+package providers_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"innominatus/internal/providers"
+	"innominatus/pkg/sdk"
+)
+
+// testTeamVersion writes the test-team provider as version with the given resource types
+// and a marker comment in its workflow, and loads it
+func testTeamVersion(t *testing.T, version, marker, resourceTypes string) *sdk.Provider {
+	t.Helper()
+	dir := t.TempDir()
+	manifest, err := os.ReadFile(filepath.Join("..", "..", "providers", "test-team", "provider.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read provider.yaml: %v", err)
+	}
+	content := strings.Replace(string(manifest), "version: 1.0.0\n  category", "version: "+version+"\n  category", 1)
+	content = strings.Replace(content, "[test-db, test-database]", resourceTypes, 1)
+	writeFile(t, filepath.Join(dir, "provider.yaml"), content)
+	workflow, err := os.ReadFile(filepath.Join("..", "..", "providers", "test-team", "workflows", "provision-test-db.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read workflow: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "workflows", "provision-test-db.yaml"), string(workflow)+"\n# "+marker+"\n")
+
+	provider, err := providers.NewLoader("1.5.0").LoadFromFile(filepath.Join(dir, "provider.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load provider: %v", err)
+	}
+	return provider
+}
+
+func TestRegistryRetainsProviderVersions(t *testing.T) {
+	v1 := testTeamVersion(t, "1.0.0", "v1", "[test-db, test-cache]")
+	v2 := testTeamVersion(t, "2.0.0", "v2", "[test-db, test-queue]")
+
+	latest, retained := providers.SplitVersions([]*sdk.Provider{v1, v2})
+	if len(latest) != 1 || latest[0] != v2 || len(retained) != 1 || retained[0] != v1 {
+		t.Fatalf("SplitVersions() = %v, %v; want 2.0.0 registered and 1.0.0 retained", latest, retained)
+	}
+
+	registry := providers.NewRegistry()
+	if err := registry.RetainProviderVersion(v1); err == nil {
+		t.Fatal("Expected retaining a version of an unregistered provider to fail")
+	}
+	if err := registry.Assets().Warm(v2); err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+	if err := registry.RegisterProvider(v2); err != nil {
+		t.Fatalf("RegisterProvider failed: %v", err)
+	}
+	if err := registry.RetainProviderVersion(v2); err == nil {
+		t.Fatal("Expected retaining the registered version to fail")
+	}
+	if err := registry.RetainProviderVersion(v1); err != nil {
+		t.Fatalf("RetainProviderVersion failed: %v", err)
+	}
+
+	if versions := registry.ProviderVersions("test-team"); !reflect.DeepEqual(versions, []string{"1.0.0", "2.0.0"}) {
+		t.Errorf("ProviderVersions() = %v", versions)
+	}
+	if current, _ := registry.GetProvider("test-team"); current != v2 {
+		t.Error("Expected the newest version to stay the registered provider")
+	}
+	pinned, err := registry.GetProviderVersion("test-team", "1.0.0")
+	if err != nil || pinned != v1 {
+		t.Fatalf("GetProviderVersion(1.0.0) = %v, %v", pinned, err)
+	}
+	if _, err := registry.GetProviderVersion("test-team", "1.5.0"); err == nil || !strings.Contains(err.Error(), "[1.0.0 2.0.0]") {
+		t.Errorf("Expected an error listing the loaded versions, got %v", err)
+	}
+
+	// Each version serves its own workflow files
+	data, err := registry.WorkflowAsset(v1, "workflows/provision-test-db.yaml")
+	if err != nil || !strings.Contains(string(data), "# v1") {
+		t.Errorf("WorkflowAsset(v1) = %q, %v", data, err)
+	}
+	data, err = registry.WorkflowAsset(v2, "workflows/provision-test-db.yaml")
+	if err != nil || !strings.Contains(string(data), "# v2") {
+		t.Errorf("WorkflowAsset(v2) = %q, %v", data, err)
+	}
+
+	changes, err := registry.CompareVersions("test-team", "1.0.0", "2.0.0")
+	if err != nil {
+		t.Fatalf("CompareVersions failed: %v", err)
+	}
+	want := &providers.VersionChanges{
+		ResourceTypesAdded:   []string{"test-queue"},
+		ResourceTypesRemoved: []string{"test-cache"},
+		WorkflowsChanged:     []string{"provision-test-db"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("CompareVersions() = %+v, want %+v", changes, want)
+	}
+
+	registry.RemoveProvider("test-team")
+	if registry.HasRetainedVersion("test-team", "1.0.0") {
+		t.Error("Expected retained versions to be removed with the provider")
+	}
+}
+
+func TestCompareVersionNumbers(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "1.10.0", -1},
+		{"2.0.0", "2.0.0-rc.1", 1},
+		{"1.0.0", "1.0.0", 0},
+		{"main", "develop", 1},
+	}
+	for _, tt := range tests {
+		if got := providers.CompareVersionNumbers(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersionNumbers(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
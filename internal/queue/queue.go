@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"innominatus/internal/clock"
 	"innominatus/internal/database"
 	"innominatus/internal/logging"
 	"innominatus/internal/types"
+	"innominatus/internal/workflow"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,6 +23,9 @@ type WorkflowTask struct {
 	EnqueuedAt   time.Time              `json:"enqueued_at"`
 	Metadata     map[string]interface{} `json:"metadata,omitempty"`
 	Parameters   map[string]string      `json:"parameters,omitempty"` // Golden path parameters
+
+	ctx        context.Context    // Runs the workflow; carries the submitter's log fields
+	onComplete CompletionListener // Called once the task finishes, before the queue listeners
 }
 
 // TaskStatus represents the status of a task
@@ -32,11 +38,44 @@ const (
 	TaskStatusFailed    TaskStatus = "failed"
 )
 
+// TaskInfo is the state of a task, without its workflow definition and parameters
+type TaskInfo struct {
+	ID           string     `json:"id"`
+	AppName      string     `json:"app_name"`
+	WorkflowName string     `json:"workflow_name"`
+	Status       TaskStatus `json:"status"`
+	ExecutionID  int64      `json:"execution_id,omitempty"` // Set once a worker has started the workflow
+	Error        string     `json:"error,omitempty"`
+	EnqueuedAt   time.Time  `json:"enqueued_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+// finishedTaskRetention is how long GetTask reports a task after it finished
+const finishedTaskRetention = time.Hour
+
+// TaskResult describes a finished task
+type TaskResult struct {
+	Task          *WorkflowTask
+	ExecutionID   int64 // Workflow execution started for the task, 0 if none was recorded
+	QueueTime     time.Duration
+	ExecutionTime time.Duration
+	Err           error
+}
+
+// CompletionListener is called after every task finishes, from the worker goroutine
+type CompletionListener func(result TaskResult)
+
 // WorkflowExecutor defines the interface for executing workflows
 type WorkflowExecutor interface {
 	ExecuteWorkflowWithName(appName, workflowName string, workflow types.Workflow, goldenPathParams ...map[string]string) error
 }
 
+// ContextExecutor is implemented by executors that run a workflow with the context of
+// the task, which lets the queue learn the ID of the workflow execution
+type ContextExecutor interface {
+	ExecuteWorkflowWithContext(ctx context.Context, appName, workflowName string, workflow types.Workflow, goldenPathParams ...map[string]string) error
+}
+
 // Queue represents an async task queue for workflow execution
 type Queue struct {
 	tasks            chan *WorkflowTask
@@ -49,8 +88,12 @@ type Queue struct {
 	cancel           context.CancelFunc
 	mu               sync.RWMutex
 	activeTasks      map[string]*WorkflowTask
+	taskInfos        map[string]*TaskInfo
 	taskStatusChan   chan taskStatusUpdate
 	metricsCollector *MetricsCollector
+	clock            clock.Clock
+	listeners        []CompletionListener
+	running          atomic.Bool
 }
 
 type taskStatusUpdate struct {
@@ -82,13 +125,27 @@ func NewQueue(workers int, executor WorkflowExecutor, db *database.Database) *Qu
 		ctx:              ctx,
 		cancel:           cancel,
 		activeTasks:      make(map[string]*WorkflowTask),
+		taskInfos:        make(map[string]*TaskInfo),
 		taskStatusChan:   make(chan taskStatusUpdate, 100),
 		metricsCollector: &MetricsCollector{},
+		clock:            clock.Real(),
 	}
 
 	return q
 }
 
+// SetClock replaces the wall clock used for task timestamps and durations
+func (q *Queue) SetClock(c clock.Clock) {
+	q.clock = clock.OrReal(c)
+}
+
+// AddCompletionListener registers a callback for finished tasks
+func (q *Queue) AddCompletionListener(listener CompletionListener) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.listeners = append(q.listeners, listener)
+}
+
 // Start starts the queue workers
 func (q *Queue) Start() {
 	q.logger.InfoWithFields("Starting queue workers", map[string]interface{}{
@@ -105,11 +162,23 @@ func (q *Queue) Start() {
 		q.wg.Add(1)
 		go q.worker(i)
 	}
+	q.running.Store(true)
+}
+
+// IsRunning reports whether the workers have been started and not stopped
+func (q *Queue) IsRunning() bool {
+	return q.running.Load()
+}
+
+// Workers returns the number of worker goroutines
+func (q *Queue) Workers() int {
+	return q.workers
 }
 
 // Stop gracefully stops the queue workers
 func (q *Queue) Stop() {
 	q.logger.Info("Stopping queue workers...")
+	q.running.Store(false)
 
 	// Cancel context to signal workers to stop
 	q.cancel()
@@ -139,6 +208,12 @@ func (q *Queue) Stop() {
 
 // Enqueue adds a workflow task to the queue
 func (q *Queue) Enqueue(appName, workflowName string, workflow types.Workflow, metadata map[string]interface{}) (string, error) {
+	return q.EnqueueWithContext(context.Background(), appName, workflowName, workflow, metadata, nil)
+}
+
+// EnqueueWithContext adds a workflow task to the queue. The workflow runs with ctx, and
+// onComplete, if not nil, is called from the worker goroutine once the task finishes.
+func (q *Queue) EnqueueWithContext(ctx context.Context, appName, workflowName string, workflow types.Workflow, metadata map[string]interface{}, onComplete CompletionListener) (string, error) {
 	// Extract parameters from metadata if present
 	var parameters map[string]string
 	if params, ok := metadata["parameters"].(map[string]string); ok {
@@ -150,9 +225,11 @@ func (q *Queue) Enqueue(appName, workflowName string, workflow types.Workflow, m
 		AppName:      appName,
 		WorkflowName: workflowName,
 		Workflow:     workflow,
-		EnqueuedAt:   time.Now(),
+		EnqueuedAt:   q.clock.Now(),
 		Metadata:     metadata,
 		Parameters:   parameters,
+		ctx:          ctx,
+		onComplete:   onComplete,
 	}
 
 	// Store task in database for persistence
@@ -160,7 +237,11 @@ func (q *Queue) Enqueue(appName, workflowName string, workflow types.Workflow, m
 		return "", fmt.Errorf("failed to store task: %w", err)
 	}
 
-	// Enqueue task (non-blocking with timeout)
+	// Track the task before a worker can update its status
+	q.trackTask(task)
+
+	// Enqueue task (non-blocking with timeout). Backpressure timeouts stay on wall
+	// time so a frozen fake clock cannot block callers forever.
 	select {
 	case q.tasks <- task:
 		q.metricsCollector.incrementEnqueued()
@@ -172,6 +253,9 @@ func (q *Queue) Enqueue(appName, workflowName string, workflow types.Workflow, m
 		})
 		return task.ID, nil
 	case <-time.After(5 * time.Second):
+		q.mu.Lock()
+		delete(q.taskInfos, task.ID)
+		q.mu.Unlock()
 		return "", fmt.Errorf("queue is full, task rejected")
 	}
 }
@@ -206,7 +290,7 @@ func (q *Queue) worker(id int) {
 
 // processTask executes a workflow task
 func (q *Queue) processTask(workerID int, task *WorkflowTask) {
-	startTime := time.Now()
+	startTime := q.clock.Now()
 	queueTime := startTime.Sub(task.EnqueuedAt)
 
 	// Mark task as active
@@ -227,14 +311,23 @@ func (q *Queue) processTask(workerID int, task *WorkflowTask) {
 
 	// Execute workflow with golden path parameters if provided
 	var err error
-	if len(task.Parameters) > 0 {
+	if executor, ok := q.executor.(ContextExecutor); ok {
+		ctx := task.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx = workflow.WithExecutionStarted(ctx, func(executionID int64) {
+			q.setExecutionID(task.ID, executionID)
+		})
+		err = executor.ExecuteWorkflowWithContext(ctx, task.AppName, task.WorkflowName, task.Workflow, task.Parameters)
+	} else if len(task.Parameters) > 0 {
 		err = q.executor.ExecuteWorkflowWithName(task.AppName, task.WorkflowName, task.Workflow, task.Parameters)
 	} else {
 		err = q.executor.ExecuteWorkflowWithName(task.AppName, task.WorkflowName, task.Workflow)
 	}
 
 	// Calculate execution time
-	executionTime := time.Since(startTime)
+	executionTime := q.clock.Since(startTime)
 
 	// Update metrics
 	q.metricsCollector.recordTaskCompletion(queueTime, executionTime, err == nil)
@@ -242,6 +335,7 @@ func (q *Queue) processTask(workerID int, task *WorkflowTask) {
 	// Remove from active tasks
 	q.mu.Lock()
 	delete(q.activeTasks, task.ID)
+	listeners := q.listeners
 	q.mu.Unlock()
 
 	// Update task status
@@ -265,10 +359,34 @@ func (q *Queue) processTask(workerID int, task *WorkflowTask) {
 			"execution_time_ms": executionTime.Milliseconds(),
 		})
 	}
+
+	result := TaskResult{Task: task, QueueTime: queueTime, ExecutionTime: executionTime, Err: err}
+	if info, ok := q.GetTask(task.ID); ok {
+		result.ExecutionID = info.ExecutionID
+	}
+	if task.onComplete != nil {
+		task.onComplete(result)
+	}
+	for _, listener := range listeners {
+		listener(result)
+	}
 }
 
-// updateTaskStatus sends a status update to the channel
+// updateTaskStatus records the status for GetTask and sends it to the channel for persistence
 func (q *Queue) updateTaskStatus(taskID string, status TaskStatus, err error) {
+	q.mu.Lock()
+	if info, ok := q.taskInfos[taskID]; ok {
+		info.Status = status
+		if err != nil {
+			info.Error = err.Error()
+		}
+		if status == TaskStatusCompleted || status == TaskStatusFailed {
+			now := q.clock.Now()
+			info.CompletedAt = &now
+		}
+	}
+	q.mu.Unlock()
+
 	select {
 	case q.taskStatusChan <- taskStatusUpdate{taskID: taskID, status: status, err: err}:
 	case <-q.ctx.Done():
@@ -342,7 +460,7 @@ func (q *Queue) persistTaskStatus(taskID string, status TaskStatus, taskErr erro
 	}
 
 	if status == TaskStatusCompleted || status == TaskStatusFailed {
-		now := time.Now()
+		now := q.clock.Now()
 		completedAt = &now
 	}
 
@@ -368,12 +486,57 @@ func (q *Queue) GetQueueStats() map[string]interface{} {
 
 	stats := q.metricsCollector.getStats()
 	stats["queue_size"] = len(q.tasks)
+	stats["queue_capacity"] = cap(q.tasks)
 	stats["active_tasks"] = activeCount
 	stats["workers"] = q.workers
 
 	return stats
 }
 
+// trackTask starts reporting a task through GetTask and forgets tasks that finished
+// more than finishedTaskRetention ago
+func (q *Queue) trackTask(task *WorkflowTask) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cutoff := q.clock.Now().Add(-finishedTaskRetention)
+	for id, info := range q.taskInfos {
+		if info.CompletedAt != nil && info.CompletedAt.Before(cutoff) {
+			delete(q.taskInfos, id)
+		}
+	}
+
+	q.taskInfos[task.ID] = &TaskInfo{
+		ID:           task.ID,
+		AppName:      task.AppName,
+		WorkflowName: task.WorkflowName,
+		Status:       TaskStatusPending,
+		EnqueuedAt:   task.EnqueuedAt,
+	}
+}
+
+// setExecutionID records the workflow execution a task started
+func (q *Queue) setExecutionID(taskID string, executionID int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if info, ok := q.taskInfos[taskID]; ok && info.ExecutionID == 0 {
+		info.ExecutionID = executionID
+	}
+}
+
+// GetTask returns the state of a pending, running or recently finished task
+func (q *Queue) GetTask(taskID string) (*TaskInfo, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	info, ok := q.taskInfos[taskID]
+	if !ok {
+		return nil, false
+	}
+	snapshot := *info
+	return &snapshot, true
+}
+
 // GetActiveTasks returns currently executing tasks
 func (q *Queue) GetActiveTasks() []*WorkflowTask {
 	q.mu.RLock()
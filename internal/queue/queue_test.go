@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"context"
 	"innominatus/internal/types"
 	"sync"
 	"testing"
@@ -238,3 +239,80 @@ func TestQueue_StopGracefully(t *testing.T) {
 		t.Errorf("Expected 1 execution before shutdown, got %d", len(executions))
 	}
 }
+
+type submitterKey struct{}
+
+// ContextMockExecutor implements ContextExecutor and records the submitter carried by the task context
+type ContextMockExecutor struct {
+	MockExecutor
+	submitters chan interface{}
+}
+
+func (m *ContextMockExecutor) ExecuteWorkflowWithContext(ctx context.Context, appName, workflowName string, workflow types.Workflow, goldenPathParams ...map[string]string) error {
+	m.submitters <- ctx.Value(submitterKey{})
+	return m.ExecuteWorkflowWithName(appName, workflowName, workflow, goldenPathParams...)
+}
+
+func TestQueue_EnqueueWithContext(t *testing.T) {
+	tests := []struct {
+		name       string
+		shouldFail bool
+		wantStatus TaskStatus
+		wantError  string
+	}{
+		{name: "completed", wantStatus: TaskStatusCompleted},
+		{name: "failed", shouldFail: true, wantStatus: TaskStatusFailed, wantError: "workflow execution failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &ContextMockExecutor{MockExecutor: MockExecutor{shouldFail: tt.shouldFail}, submitters: make(chan interface{}, 1)}
+			q := NewQueue(1, executor, nil)
+			q.Start()
+			defer q.Stop()
+
+			results := make(chan TaskResult, 1)
+			ctx := context.WithValue(context.Background(), submitterKey{}, "alice")
+			taskID, err := q.EnqueueWithContext(ctx, "test-app", "test-workflow", types.Workflow{}, nil, func(result TaskResult) {
+				results <- result
+			})
+			if err != nil {
+				t.Fatalf("Failed to enqueue task: %v", err)
+			}
+
+			select {
+			case result := <-results:
+				if result.Task.ID != taskID {
+					t.Errorf("Expected result for task %s, got %s", taskID, result.Task.ID)
+				}
+				if (result.Err != nil) != tt.shouldFail {
+					t.Errorf("Unexpected task error: %v", result.Err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Completion callback was not called")
+			}
+
+			if submitter := <-executor.submitters; submitter != "alice" {
+				t.Errorf("Expected the task context to reach the executor, got submitter %v", submitter)
+			}
+
+			task, ok := q.GetTask(taskID)
+			if !ok {
+				t.Fatalf("Task %s not found", taskID)
+			}
+			if task.Status != tt.wantStatus {
+				t.Errorf("Expected status %s, got %s", tt.wantStatus, task.Status)
+			}
+			if task.Error != tt.wantError {
+				t.Errorf("Expected error %q, got %q", tt.wantError, task.Error)
+			}
+			if task.CompletedAt == nil {
+				t.Error("Expected CompletedAt to be set")
+			}
+
+			if _, ok := q.GetTask("task-unknown"); ok {
+				t.Error("Expected unknown task to be missing")
+			}
+		})
+	}
+}
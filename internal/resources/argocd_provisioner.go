@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"innominatus/internal/admin"
+	"innominatus/internal/argoprojects"
 	"innominatus/internal/database"
 	"innominatus/internal/security"
 	"io"
@@ -83,6 +84,22 @@ func (ap *ArgoCDProvisioner) Provision(resource *database.ResourceInstance, conf
 	fmt.Printf("   Repository: %s\n", repoURL)
 	fmt.Printf("   Namespace: %s\n", namespace)
 
+	// An explicit project wins; otherwise Applications of a team go to the team's project
+	project := "default"
+	if projectParam, ok := config["project"].(string); ok && projectParam != "" {
+		project = projectParam
+	} else if adminConfig.ArgoCD.TeamProjects.Enabled && ap.repo != nil {
+		provisioner := argoprojects.NewProvisioner(adminConfig.ArgoCD.TeamProjects, adminConfig.ArgoCD.URL, adminConfig.ArgoCD.Username, adminConfig.ArgoCD.Password)
+		teamProject, err := provisioner.ProjectFor(ap.repo, resource.ApplicationName)
+		if err != nil {
+			return err
+		}
+		if teamProject != "" {
+			project = teamProject
+			fmt.Printf("   Project: %s\n", project)
+		}
+	}
+
 	// Authenticate with ArgoCD
 	token, err := ap.authenticateArgoCD(adminConfig.ArgoCD.URL, adminConfig.ArgoCD.Username, adminConfig.ArgoCD.Password)
 	if err != nil {
@@ -98,7 +115,7 @@ func (ap *ArgoCDProvisioner) Provision(resource *database.ResourceInstance, conf
 			"namespace": "argocd",
 		},
 		"spec": map[string]interface{}{
-			"project": "default",
+			"project": project,
 			"source": map[string]interface{}{
 				"repoURL":        repoURL,
 				"targetRevision": "HEAD",
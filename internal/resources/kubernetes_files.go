@@ -0,0 +1,303 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"innominatus/internal/types"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ResourceOutputsKey is the provisioner config entry holding the outputs of the
+// application's resources, keyed by resource name and output name
+const ResourceOutputsKey = "resource_outputs"
+
+// filesChecksumAnnotation is the pod template annotation holding the checksum of the
+// rendered container files. Files are mounted with subPath, which never sees updates, so
+// a changed checksum rolls the pods instead.
+const filesChecksumAnnotation = "innominatus.io/files-checksum"
+
+// secretOutputKeys are parts of output names whose values are credentials. Files
+// referencing them are mounted from a Secret instead of a ConfigMap.
+var secretOutputKeys = []string{"password", "secret", "token", "credential", "private_key", "connection_string"}
+
+// containerFile is a container file with its placeholders resolved
+type containerFile struct {
+	key     string // Key in the ConfigMap or Secret
+	target  string
+	mode    *int64
+	content string
+}
+
+// fileVolume is a ConfigMap or Secret holding the files of a container
+type fileVolume struct {
+	name      string // Volume name in the pod spec
+	object    string // Name of the ConfigMap or Secret
+	container string
+	secret    bool
+	files     []containerFile
+}
+
+// resourceOutputs returns the outputs of an application's resources: the scalar provider
+// metadata of its resource instances, overridden by the outputs workflows pass in config.
+func (kp *KubernetesProvisioner) resourceOutputs(appName string, config map[string]interface{}) map[string]map[string]string {
+	outputs := make(map[string]map[string]string)
+	if kp.repo != nil {
+		if instances, err := kp.repo.ListResourceInstances(appName); err == nil {
+			for _, instance := range instances {
+				for key, value := range instance.ProviderMetadata {
+					switch value.(type) {
+					case string, bool, float64, int, int64:
+						if outputs[instance.ResourceName] == nil {
+							outputs[instance.ResourceName] = make(map[string]string)
+						}
+						outputs[instance.ResourceName][key] = fmt.Sprint(value)
+					}
+				}
+			}
+		}
+	}
+
+	if configured, ok := config[ResourceOutputsKey].(map[string]map[string]string); ok {
+		for resource, values := range configured {
+			if outputs[resource] == nil {
+				outputs[resource] = make(map[string]string)
+			}
+			for key, value := range values {
+				outputs[resource][key] = value
+			}
+		}
+	}
+	return outputs
+}
+
+// podFiles renders the files of the containers and init containers of a spec. Plain
+// files of a container go into a ConfigMap, files referencing credentials into a Secret.
+func podFiles(appName string, scoreSpec *types.ScoreSpec, outputs map[string]map[string]string) ([]fileVolume, error) {
+	if scoreSpec == nil {
+		return nil, nil
+	}
+
+	var volumes []fileVolume
+	render := func(kind, containerName string, container types.Container) error {
+		plain := fileVolume{
+			name:      podVolumeName("files-" + containerName),
+			object:    fmt.Sprintf("%s-%s-files", appName, podVolumeName(containerName)),
+			container: containerName,
+		}
+		secret := plain
+		secret.name = podVolumeName("secret-files-" + containerName)
+		secret.secret = true
+
+		for i, file := range container.Files {
+			if !path.IsAbs(file.Target) {
+				return fmt.Errorf("%s '%s' has file '%s'; the target must be an absolute path", kind, containerName, file.Target)
+			}
+			if file.Source != "" && file.Content == "" {
+				return fmt.Errorf("%s '%s' reads file '%s' from source '%s'; only inline content is supported", kind, containerName, file.Target, file.Source)
+			}
+
+			rendered := containerFile{key: fmt.Sprintf("file-%d", i), target: file.Target, content: file.Content}
+			if file.Mode != "" {
+				mode, err := strconv.ParseInt(file.Mode, 8, 32)
+				if err != nil {
+					return fmt.Errorf("%s '%s' has file '%s' with invalid mode '%s'; use an octal mode such as 0644", kind, containerName, file.Target, file.Mode)
+				}
+				rendered.mode = &mode
+			}
+
+			sensitive := false
+			if !file.NoExpand {
+				content, usesSecrets, err := resolvePlaceholders(file.Content, scoreSpec, outputs)
+				if err != nil {
+					return fmt.Errorf("%s '%s' has file '%s': %w", kind, containerName, file.Target, err)
+				}
+				rendered.content, sensitive = content, usesSecrets
+			}
+			if sensitive {
+				secret.files = append(secret.files, rendered)
+			} else {
+				plain.files = append(plain.files, rendered)
+			}
+		}
+
+		for _, volume := range []fileVolume{plain, secret} {
+			if len(volume.files) > 0 {
+				volumes = append(volumes, volume)
+			}
+		}
+		return nil
+	}
+	for _, name := range sortedContainerNames(scoreSpec.InitContainers) {
+		if err := render("init container", name, scoreSpec.InitContainers[name]); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range sortedContainerNames(scoreSpec.Containers) {
+		if err := render("container", name, scoreSpec.Containers[name]); err != nil {
+			return nil, err
+		}
+	}
+	return volumes, nil
+}
+
+// resolvePlaceholders replaces ${metadata.name} and ${resources.NAME.KEY} in content.
+// $$ escapes a dollar sign. It also reports whether a credential output was used.
+func resolvePlaceholders(content string, scoreSpec *types.ScoreSpec, outputs map[string]map[string]string) (string, bool, error) {
+	var b strings.Builder
+	sensitive := false
+	for i := 0; i < len(content); i++ {
+		if content[i] != '$' || i+1 == len(content) {
+			b.WriteByte(content[i])
+			continue
+		}
+		switch content[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(content[i:], '}')
+			if end < 0 {
+				return "", false, fmt.Errorf("unterminated placeholder '%s'", content[i:])
+			}
+			placeholder := content[i+2 : i+end]
+			value, secret, err := resolvePlaceholder(placeholder, scoreSpec, outputs)
+			if err != nil {
+				return "", false, err
+			}
+			b.WriteString(value)
+			sensitive = sensitive || secret
+			i += end
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String(), sensitive, nil
+}
+
+// resolvePlaceholder returns the value of a single placeholder without ${ and }
+func resolvePlaceholder(placeholder string, scoreSpec *types.ScoreSpec, outputs map[string]map[string]string) (string, bool, error) {
+	parts := strings.SplitN(placeholder, ".", 3)
+	switch {
+	case len(parts) == 2 && parts[0] == "metadata" && parts[1] == "name":
+		return scoreSpec.Metadata.Name, false, nil
+	case len(parts) == 3 && parts[0] == "resources":
+		resource, key := parts[1], parts[2]
+		if _, ok := scoreSpec.Resources[resource]; !ok {
+			return "", false, fmt.Errorf("placeholder '${%s}' refers to unknown resource '%s'", placeholder, resource)
+		}
+		value, ok := outputs[resource][key]
+		if !ok {
+			return "", false, fmt.Errorf("placeholder '${%s}' refers to output '%s' that resource '%s' does not provide", placeholder, key, resource)
+		}
+		return value, isSecretOutput(key), nil
+	}
+	return "", false, fmt.Errorf("unsupported placeholder '${%s}'; use ${metadata.name} or ${resources.NAME.KEY}", placeholder)
+}
+
+// isSecretOutput reports whether an output holds a credential
+func isSecretOutput(key string) bool {
+	lower := strings.ToLower(key)
+	for _, secret := range secretOutputKeys {
+		if strings.Contains(lower, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// containerFileVolumes returns the file volumes of a container
+func containerFileVolumes(volumes []fileVolume, containerName string) []fileVolume {
+	var result []fileVolume
+	for _, volume := range volumes {
+		if volume.container == containerName {
+			result = append(result, volume)
+		}
+	}
+	return result
+}
+
+// filesChecksum returns the checksum of the rendered files, or "" without files
+func filesChecksum(volumes []fileVolume) string {
+	if len(volumes) == 0 {
+		return ""
+	}
+	hash := sha256.New()
+	for _, volume := range volumes {
+		for _, file := range volume.files {
+			_, _ = fmt.Fprintf(hash, "%s\x00%s\x00%s\x00", volume.object, file.target, file.content)
+			if file.mode != nil {
+				_, _ = fmt.Fprintf(hash, "%o\x00", *file.mode)
+			}
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// generateFilesManifest creates the ConfigMap or Secret holding the files of a container
+func (kp *KubernetesProvisioner) generateFilesManifest(appName, namespace string, volume fileVolume) string {
+	kind, apiField, extra := "ConfigMap", "data", ""
+	if volume.secret {
+		kind, apiField, extra = "Secret", "stringData", "\ntype: Opaque"
+	}
+
+	files := append([]containerFile(nil), volume.files...)
+	sort.Slice(files, func(i, j int) bool { return files[i].key < files[j].key })
+	var entries []string
+	for _, file := range files {
+		entries = append(entries, fmt.Sprintf("  %s: %q", file.key, file.content))
+	}
+
+	return fmt.Sprintf(`apiVersion: v1
+kind: %s
+metadata:
+  name: %s
+  namespace: %s
+  labels:
+    app: %s
+    managed-by: innominatus%s
+%s:
+%s`,
+		kind, volume.object, namespace, appName, extra, apiField, strings.Join(entries, "\n"))
+}
+
+// generateFileVolumesSection creates the volumes of the pod spec that hold container files
+func (kp *KubernetesProvisioner) generateFileVolumesSection(volumes []fileVolume) []string {
+	var lines []string
+	for _, volume := range volumes {
+		lines = append(lines, fmt.Sprintf("      - name: %s", volume.name))
+		if volume.secret {
+			lines = append(lines, "        secret:", fmt.Sprintf("          secretName: %s", volume.object))
+		} else {
+			lines = append(lines, "        configMap:", fmt.Sprintf("          name: %s", volume.object))
+		}
+		lines = append(lines, "          items:")
+		for _, file := range volume.files {
+			lines = append(lines,
+				fmt.Sprintf("          - key: %s", file.key),
+				fmt.Sprintf("            path: %s", file.key))
+			if file.mode != nil {
+				lines = append(lines, fmt.Sprintf("            mode: %d", *file.mode))
+			}
+		}
+	}
+	return lines
+}
+
+// generateFileMountsSection creates the volume mounts of a container's files
+func (kp *KubernetesProvisioner) generateFileMountsSection(volumes []fileVolume) []string {
+	var lines []string
+	for _, volume := range volumes {
+		for _, file := range volume.files {
+			lines = append(lines,
+				fmt.Sprintf("        - name: %s", volume.name),
+				fmt.Sprintf("          mountPath: %s", file.target),
+				fmt.Sprintf("          subPath: %s", file.key),
+				"          readOnly: true")
+		}
+	}
+	return lines
+}
@@ -40,6 +40,13 @@ func (kp *KubernetesProvisioner) Provision(resource *database.ResourceInstance,
 		}
 	}
 
+	// Hostname reserved by the naming convention, the default host of route resources
+	hostname, _ := config["hostname"].(string)
+	url, _ := config["url"].(string)
+
+	// Outputs of the application's resources, for placeholders in container files
+	outputs := kp.resourceOutputs(appName, config)
+
 	fmt.Printf("🔧 Provisioning Kubernetes deployment for '%s' in namespace '%s'\n", appName, namespace)
 
 	// Step 1: Create namespace
@@ -49,7 +56,7 @@ func (kp *KubernetesProvisioner) Provision(resource *database.ResourceInstance,
 	fmt.Printf("   ✅ Namespace '%s' created\n", namespace)
 
 	// Step 2: Generate manifests
-	manifests, err := kp.generateManifests(appName, namespace, scoreSpec)
+	manifests, err := kp.generateManifests(appName, namespace, hostname, scoreSpec, outputs)
 	if err != nil {
 		return fmt.Errorf("failed to generate manifests: %w", err)
 	}
@@ -107,6 +114,14 @@ func (kp *KubernetesProvisioner) Provision(resource *database.ResourceInstance,
 			Icon:  "terminal",
 		},
 	}
+	if url != "" {
+		hints = append([]database.ResourceHint{{
+			Type:  "url",
+			Label: "Application URL",
+			Value: url,
+			Icon:  "external-link",
+		}}, hints...)
+	}
 
 	// Update resource hints in database
 	if err := kp.repo.UpdateResourceHints(resource.ID, hints); err != nil {
@@ -190,12 +205,38 @@ func (kp *KubernetesProvisioner) deleteNamespace(namespace string) error {
 	return nil
 }
 
-// generateManifests generates Kubernetes manifests from Score spec
-func (kp *KubernetesProvisioner) generateManifests(appName string, namespace string, scoreSpec *types.ScoreSpec) (string, error) {
+// generateManifests generates Kubernetes manifests from Score spec. Route resources
+// without a host use hostname, the name reserved by the naming convention. Placeholders
+// in container files are resolved from outputs, keyed by resource name.
+func (kp *KubernetesProvisioner) generateManifests(appName string, namespace string, hostname string, scoreSpec *types.ScoreSpec, outputs map[string]map[string]string) (string, error) {
 	var manifests []string
 
+	// Volume mounts must refer to volume or storage resources
+	volumes, err := podVolumes(scoreSpec)
+	if err != nil {
+		return "", err
+	}
+
+	// Container files must resolve all their placeholders
+	files, err := podFiles(appName, scoreSpec, outputs)
+	if err != nil {
+		return "", err
+	}
+
+	// Generate a PersistentVolumeClaim per mounted storage resource
+	for _, volume := range volumes {
+		if volume.storage {
+			manifests = append(manifests, kp.generatePersistentVolumeClaim(appName, namespace, volume.resource, volume.params))
+		}
+	}
+
+	// Generate a ConfigMap or Secret per container with files
+	for _, volume := range files {
+		manifests = append(manifests, kp.generateFilesManifest(appName, namespace, volume))
+	}
+
 	// Generate Deployment
-	deployment := kp.generateDeployment(appName, namespace, scoreSpec)
+	deployment := kp.generateDeployment(appName, namespace, scoreSpec, files)
 	manifests = append(manifests, deployment)
 
 	// Generate Service
@@ -206,7 +247,7 @@ func (kp *KubernetesProvisioner) generateManifests(appName string, namespace str
 	if scoreSpec != nil && scoreSpec.Resources != nil {
 		for _, resource := range scoreSpec.Resources {
 			if resource.Type == "route" {
-				ingress := kp.generateIngress(appName, namespace, resource.Params)
+				ingress := kp.generateIngress(appName, namespace, hostname, resource.Params)
 				manifests = append(manifests, ingress)
 			}
 		}
@@ -215,29 +256,37 @@ func (kp *KubernetesProvisioner) generateManifests(appName string, namespace str
 	return strings.Join(manifests, "\n---\n"), nil
 }
 
-// generateDeployment creates a Kubernetes Deployment manifest
-func (kp *KubernetesProvisioner) generateDeployment(appName string, namespace string, scoreSpec *types.ScoreSpec) string {
+// generateDeployment creates a Kubernetes Deployment manifest that mounts the rendered
+// container files
+func (kp *KubernetesProvisioner) generateDeployment(appName string, namespace string, scoreSpec *types.ScoreSpec, files []fileVolume) string {
 	// Default container configuration
 	containerName := "web"
 	containerImage := "nginx:1.25"
 	containerPort := 80
-	var containerVars map[string]string
+	var container types.Container
 
 	// Extract from Score spec if available
 	if scoreSpec != nil && scoreSpec.Containers != nil {
-		for name, container := range scoreSpec.Containers {
+		for name, c := range scoreSpec.Containers {
 			containerName = name
+			container = c
 			if container.Image != "" {
 				containerImage = container.Image
 			}
-			// Extract environment variables
-			containerVars = container.Variables
 			// Note: Ports are not yet in the Container type definition
 			// Using default port 80 for now
 			break // Use first container
 		}
 	}
 
+	// Mounts of unknown resources are rejected by generateManifests
+	volumes, _ := podVolumes(scoreSpec)
+
+	annotations := ""
+	if checksum := filesChecksum(files); checksum != "" {
+		annotations = fmt.Sprintf("\n      annotations:\n        %s: %s", filesChecksumAnnotation, checksum)
+	}
+
 	manifest := fmt.Sprintf(`apiVersion: apps/v1
 kind: Deployment
 metadata:
@@ -254,16 +303,20 @@ spec:
   template:
     metadata:
       labels:
-        app: %s
-    spec:
+        app: %s%s
+    spec:%s
       containers:
       - name: %s
-        image: %s
+        image: %s%s
         ports:
         - containerPort: %d
-          protocol: TCP%s`,
-		appName, namespace, appName, appName, appName, containerName, containerImage, containerPort,
-		kp.generateEnvSection(containerVars))
+          protocol: TCP%s%s%s`,
+		appName, namespace, appName, appName, appName, annotations,
+		kp.generateInitContainersSection(scoreSpec, files),
+		containerName, containerImage, kp.generateCommandSection(container), containerPort,
+		kp.generateEnvSection(container.Variables),
+		kp.generateVolumeMountsSection(container.Volumes, containerFileVolumes(files, containerName)),
+		kp.generateVolumesSection(appName, volumes, files))
 
 	return manifest
 }
@@ -313,8 +366,11 @@ spec:
 }
 
 // generateIngress creates a Kubernetes Ingress manifest
-func (kp *KubernetesProvisioner) generateIngress(appName string, namespace string, params map[string]interface{}) string {
+func (kp *KubernetesProvisioner) generateIngress(appName string, namespace string, hostname string, params map[string]interface{}) string {
 	host := "example.local"
+	if hostname != "" {
+		host = hostname
+	}
 	port := 80
 
 	// Extract host from params
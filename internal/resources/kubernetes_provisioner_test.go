@@ -1,9 +1,15 @@
 package resources
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"innominatus/internal/types"
+	"io"
 	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestGenerateDeploymentWithEnvironmentVariables(t *testing.T) {
@@ -25,7 +31,7 @@ func TestGenerateDeploymentWithEnvironmentVariables(t *testing.T) {
 		},
 	}
 
-	manifest := kp.generateDeployment("test-app", "test-namespace", scoreSpec)
+	manifest := kp.generateDeployment("test-app", "test-namespace", scoreSpec, nil)
 
 	// Check that env section exists
 	if !strings.Contains(manifest, "env:") {
@@ -67,7 +73,7 @@ func TestGenerateDeploymentWithoutEnvironmentVariables(t *testing.T) {
 		},
 	}
 
-	manifest := kp.generateDeployment("test-app", "test-namespace", scoreSpec)
+	manifest := kp.generateDeployment("test-app", "test-namespace", scoreSpec, nil)
 
 	// Check that env section does NOT exist when no variables
 	if strings.Contains(manifest, "env:") {
@@ -143,3 +149,272 @@ func TestGenerateEnvSection(t *testing.T) {
 		})
 	}
 }
+
+// decodeManifests parses the documents of generated manifests by kind
+func decodeManifests(t *testing.T, manifests string) map[string]map[string]interface{} {
+	t.Helper()
+	byKind := make(map[string]map[string]interface{})
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(manifests)))
+	for {
+		var doc map[string]interface{}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Generated manifests are not valid YAML: %v\n%s", err, manifests)
+		}
+		byKind[doc["kind"].(string)] = doc
+	}
+	return byKind
+}
+
+func TestGenerateManifestsWithVolumesAndInitContainers(t *testing.T) {
+	kp := &KubernetesProvisioner{}
+
+	scoreSpec := &types.ScoreSpec{
+		Metadata: types.Metadata{Name: "shop"},
+		InitContainers: map[string]types.Container{
+			"migrate": {
+				Image:     "shop-migrations:1.2",
+				Command:   []string{"sh", "-c"},
+				Args:      []string{"migrate up"},
+				Variables: map[string]string{"DB_URL": "postgres://db"},
+				Volumes:   []types.VolumeMount{{Source: "${resources.data}", Target: "/data"}},
+			},
+		},
+		Containers: map[string]types.Container{
+			"web": {
+				Image: "shop:1.2",
+				Volumes: []types.VolumeMount{
+					{Source: "${resources.data}", Target: "/var/lib/shop", Path: "uploads"},
+					{Source: "cache", Target: "/cache", ReadOnly: true},
+				},
+			},
+		},
+		Resources: map[string]types.Resource{
+			"data":  {Type: "storage", Params: map[string]interface{}{"size": "5Gi", "storageClass": "fast"}},
+			"cache": {Type: "volume", Params: map[string]interface{}{"medium": "Memory"}},
+		},
+	}
+
+	manifests, err := kp.generateManifests("shop", "shop-dev", "", scoreSpec, nil)
+	if err != nil {
+		t.Fatalf("generateManifests() error = %v", err)
+	}
+	docs := decodeManifests(t, manifests)
+
+	pvc, ok := docs["PersistentVolumeClaim"]
+	if !ok {
+		t.Fatalf("Expected a PersistentVolumeClaim for the storage resource:\n%s", manifests)
+	}
+	if name := pvc["metadata"].(map[string]interface{})["name"]; name != "shop-data" {
+		t.Errorf("PVC name = %v, want shop-data", name)
+	}
+	pvcSpec := pvc["spec"].(map[string]interface{})
+	if pvcSpec["storageClassName"] != "fast" {
+		t.Errorf("storageClassName = %v, want fast", pvcSpec["storageClassName"])
+	}
+	if size := pvcSpec["resources"].(map[string]interface{})["requests"].(map[string]interface{})["storage"]; size != "5Gi" {
+		t.Errorf("storage request = %v, want 5Gi", size)
+	}
+
+	podSpec := docs["Deployment"]["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+
+	initContainers := podSpec["initContainers"].([]interface{})
+	if len(initContainers) != 1 {
+		t.Fatalf("Expected 1 init container, got %d", len(initContainers))
+	}
+	migrate := initContainers[0].(map[string]interface{})
+	if migrate["name"] != "migrate" || migrate["image"] != "shop-migrations:1.2" {
+		t.Errorf("Unexpected init container: %v", migrate)
+	}
+	if args := migrate["args"].([]interface{}); len(args) != 1 || args[0] != "migrate up" {
+		t.Errorf("Init container args = %v, want [migrate up]", args)
+	}
+	if _, ok := migrate["env"]; !ok {
+		t.Error("Expected init container to have env")
+	}
+
+	web := podSpec["containers"].([]interface{})[0].(map[string]interface{})
+	mounts := web["volumeMounts"].([]interface{})
+	if len(mounts) != 2 {
+		t.Fatalf("Expected 2 volume mounts, got %v", mounts)
+	}
+	data := mounts[0].(map[string]interface{})
+	if data["name"] != "data" || data["mountPath"] != "/var/lib/shop" || data["subPath"] != "uploads" {
+		t.Errorf("Unexpected data mount: %v", data)
+	}
+	if cache := mounts[1].(map[string]interface{}); cache["readOnly"] != true {
+		t.Errorf("Expected cache mount to be read-only: %v", cache)
+	}
+
+	volumes := podSpec["volumes"].([]interface{})
+	if len(volumes) != 2 {
+		t.Fatalf("Expected 2 volumes, got %v", volumes)
+	}
+	cacheVolume := volumes[0].(map[string]interface{})
+	if cacheVolume["emptyDir"].(map[string]interface{})["medium"] != "Memory" {
+		t.Errorf("Expected cache to be an in-memory emptyDir: %v", cacheVolume)
+	}
+	dataVolume := volumes[1].(map[string]interface{})
+	if claim := dataVolume["persistentVolumeClaim"].(map[string]interface{})["claimName"]; claim != "shop-data" {
+		t.Errorf("data claimName = %v, want shop-data", claim)
+	}
+}
+
+func TestGenerateManifestsRejectsInvalidVolumeMounts(t *testing.T) {
+	kp := &KubernetesProvisioner{}
+
+	tests := []struct {
+		name  string
+		mount types.VolumeMount
+		want  string
+	}{
+		{"unknown resource", types.VolumeMount{Source: "${resources.missing}", Target: "/data"}, "unknown resource"},
+		{"not a volume", types.VolumeMount{Source: "db", Target: "/data"}, "only volume and storage"},
+		{"relative target", types.VolumeMount{Source: "tmp", Target: "data"}, "absolute path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scoreSpec := &types.ScoreSpec{
+				Containers: map[string]types.Container{"web": {Image: "shop:1.2", Volumes: []types.VolumeMount{tt.mount}}},
+				Resources: map[string]types.Resource{
+					"db":  {Type: "postgres"},
+					"tmp": {Type: "volume"},
+				},
+			}
+			_, err := kp.generateManifests("shop", "shop-dev", "", scoreSpec, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("generateManifests() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateDeploymentWithoutVolumes(t *testing.T) {
+	kp := &KubernetesProvisioner{}
+
+	manifest := kp.generateDeployment("test-app", "test-namespace", nil, nil)
+	for _, section := range []string{"initContainers:", "volumes:", "volumeMounts:", "command:"} {
+		if strings.Contains(manifest, section) {
+			t.Errorf("Expected manifest without volumes or init containers to NOT contain '%s'", section)
+		}
+	}
+	decodeManifests(t, manifest)
+}
+
+func TestGenerateManifestsWithContainerFiles(t *testing.T) {
+	kp := &KubernetesProvisioner{}
+
+	scoreSpec := &types.ScoreSpec{
+		Metadata: types.Metadata{Name: "shop"},
+		Containers: map[string]types.Container{
+			"web": {
+				Image: "shop:1.2",
+				Files: []types.ContainerFile{
+					{Target: "/etc/shop/app.conf", Mode: "0640", Content: "name=${metadata.name}\ndb=${resources.db.host}:${resources.db.port}\nprice=$$5\n"},
+					{Target: "/etc/shop/db.env", Content: "DB_PASSWORD=${resources.db.password}\n"},
+					{Target: "/etc/shop/raw.tmpl", Content: "${resources.db.host}", NoExpand: true},
+				},
+			},
+		},
+		Resources: map[string]types.Resource{"db": {Type: "postgres"}},
+	}
+	outputs := map[string]map[string]string{"db": {"host": "db.shop", "port": "5432", "password": "s3cret"}}
+
+	manifests, err := kp.generateManifests("shop", "shop-dev", "", scoreSpec, outputs)
+	if err != nil {
+		t.Fatalf("generateManifests() error = %v", err)
+	}
+	docs := decodeManifests(t, manifests)
+
+	configMap, ok := docs["ConfigMap"]
+	if !ok {
+		t.Fatalf("Expected a ConfigMap for the plain files:\n%s", manifests)
+	}
+	if name := configMap["metadata"].(map[string]interface{})["name"]; name != "shop-web-files" {
+		t.Errorf("ConfigMap name = %v, want shop-web-files", name)
+	}
+	data := configMap["data"].(map[string]interface{})
+	if data["file-0"] != "name=shop\ndb=db.shop:5432\nprice=$5\n" {
+		t.Errorf("Rendered file-0 = %q", data["file-0"])
+	}
+	if data["file-2"] != "${resources.db.host}" {
+		t.Errorf("Expected noExpand content to be kept, got %q", data["file-2"])
+	}
+
+	secret, ok := docs["Secret"]
+	if !ok {
+		t.Fatalf("Expected a Secret for the file with a password:\n%s", manifests)
+	}
+	if content := secret["stringData"].(map[string]interface{})["file-1"]; content != "DB_PASSWORD=s3cret\n" {
+		t.Errorf("Rendered file-1 = %q", content)
+	}
+	if strings.Contains(fmt.Sprint(configMap), "s3cret") {
+		t.Error("Expected the password not to be in the ConfigMap")
+	}
+
+	template := docs["Deployment"]["spec"].(map[string]interface{})["template"].(map[string]interface{})
+	annotations := template["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})
+	if annotations[filesChecksumAnnotation] == "" {
+		t.Error("Expected the pod template to carry the files checksum")
+	}
+	podSpec := template["spec"].(map[string]interface{})
+
+	web := podSpec["containers"].([]interface{})[0].(map[string]interface{})
+	mounts := web["volumeMounts"].([]interface{})
+	if len(mounts) != 3 {
+		t.Fatalf("Expected 3 file mounts, got %v", mounts)
+	}
+	conf := mounts[0].(map[string]interface{})
+	if conf["name"] != "files-web" || conf["mountPath"] != "/etc/shop/app.conf" || conf["subPath"] != "file-0" || conf["readOnly"] != true {
+		t.Errorf("Unexpected app.conf mount: %v", conf)
+	}
+	if env := mounts[2].(map[string]interface{}); env["name"] != "secret-files-web" || env["subPath"] != "file-1" {
+		t.Errorf("Unexpected db.env mount: %v", env)
+	}
+
+	volumes := podSpec["volumes"].([]interface{})
+	if len(volumes) != 2 {
+		t.Fatalf("Expected 2 file volumes, got %v", volumes)
+	}
+	items := volumes[0].(map[string]interface{})["configMap"].(map[string]interface{})["items"].([]interface{})
+	if mode := items[0].(map[string]interface{})["mode"]; mode != 0640 {
+		t.Errorf("file-0 mode = %v, want 0640", mode)
+	}
+	if name := volumes[1].(map[string]interface{})["secret"].(map[string]interface{})["secretName"]; name != "shop-web-files" {
+		t.Errorf("secretName = %v, want shop-web-files", name)
+	}
+}
+
+func TestGenerateManifestsRejectsInvalidContainerFiles(t *testing.T) {
+	kp := &KubernetesProvisioner{}
+
+	tests := []struct {
+		name string
+		file types.ContainerFile
+		want string
+	}{
+		{"relative target", types.ContainerFile{Target: "app.conf", Content: "x"}, "absolute path"},
+		{"source only", types.ContainerFile{Target: "/app.conf", Source: "./app.conf"}, "only inline content"},
+		{"invalid mode", types.ContainerFile{Target: "/app.conf", Mode: "rw", Content: "x"}, "invalid mode"},
+		{"unknown resource", types.ContainerFile{Target: "/app.conf", Content: "${resources.cache.host}"}, "unknown resource 'cache'"},
+		{"missing output", types.ContainerFile{Target: "/app.conf", Content: "${resources.db.user}"}, "does not provide"},
+		{"unsupported placeholder", types.ContainerFile{Target: "/app.conf", Content: "${env.HOME}"}, "unsupported placeholder"},
+		{"unterminated placeholder", types.ContainerFile{Target: "/app.conf", Content: "${resources.db.host"}, "unterminated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scoreSpec := &types.ScoreSpec{
+				Containers: map[string]types.Container{"web": {Image: "shop:1.2", Files: []types.ContainerFile{tt.file}}},
+				Resources:  map[string]types.Resource{"db": {Type: "postgres"}},
+			}
+			outputs := map[string]map[string]string{"db": {"host": "db.shop"}}
+			_, err := kp.generateManifests("shop", "shop-dev", "", scoreSpec, outputs)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("generateManifests() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/types"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Resource types containers can mount
+const (
+	volumeResourceType  = "volume"  // emptyDir, lives as long as the pod
+	storageResourceType = "storage" // PersistentVolumeClaim, survives restarts and redeploys
+)
+
+// podVolume is a volume of the generated pod, backed by a volume or storage resource
+type podVolume struct {
+	name     string // Volume name in the pod spec
+	resource string
+	params   map[string]interface{}
+	storage  bool
+}
+
+// volumeResourceName returns the resource a volume mount refers to
+func volumeResourceName(source string) string {
+	source = strings.TrimSpace(source)
+	if strings.HasPrefix(source, "${resources.") && strings.HasSuffix(source, "}") {
+		return strings.TrimSuffix(strings.TrimPrefix(source, "${resources."), "}")
+	}
+	return source
+}
+
+// podVolumeName turns a resource name into a valid volume name
+func podVolumeName(resource string) string {
+	return strings.ToLower(strings.ReplaceAll(resource, "_", "-"))
+}
+
+// claimName returns the name of the PersistentVolumeClaim of a storage resource
+func claimName(appName, resource string) string {
+	return fmt.Sprintf("%s-%s", appName, podVolumeName(resource))
+}
+
+// podVolumes returns the volumes the containers and init containers of a spec mount,
+// sorted by name. Mounts must refer to volume or storage resources of the spec.
+func podVolumes(scoreSpec *types.ScoreSpec) ([]podVolume, error) {
+	if scoreSpec == nil {
+		return nil, nil
+	}
+
+	byName := make(map[string]podVolume)
+	check := func(kind, containerName string, container types.Container) error {
+		for _, mount := range container.Volumes {
+			name := volumeResourceName(mount.Source)
+			resource, ok := scoreSpec.Resources[name]
+			if !ok {
+				return fmt.Errorf("%s '%s' mounts unknown resource '%s'", kind, containerName, mount.Source)
+			}
+			if resource.Type != volumeResourceType && resource.Type != storageResourceType {
+				return fmt.Errorf("%s '%s' mounts resource '%s' of type %s; only %s and %s resources can be mounted",
+					kind, containerName, name, resource.Type, volumeResourceType, storageResourceType)
+			}
+			if !path.IsAbs(mount.Target) {
+				return fmt.Errorf("%s '%s' mounts '%s' at '%s'; the target must be an absolute path", kind, containerName, name, mount.Target)
+			}
+			byName[podVolumeName(name)] = podVolume{
+				name:     podVolumeName(name),
+				resource: name,
+				params:   resource.Params,
+				storage:  resource.Type == storageResourceType,
+			}
+		}
+		return nil
+	}
+	for _, name := range sortedContainerNames(scoreSpec.InitContainers) {
+		if err := check("init container", name, scoreSpec.InitContainers[name]); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range sortedContainerNames(scoreSpec.Containers) {
+		if err := check("container", name, scoreSpec.Containers[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	volumes := make([]podVolume, 0, len(byName))
+	for _, volume := range byName {
+		volumes = append(volumes, volume)
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].name < volumes[j].name })
+	return volumes, nil
+}
+
+// sortedContainerNames returns the names of containers in order
+func sortedContainerNames(containers map[string]types.Container) []string {
+	names := make([]string, 0, len(containers))
+	for name := range containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// paramString returns a string parameter of a resource, or fallback when it is not set
+func paramString(params map[string]interface{}, key, fallback string) string {
+	if value, ok := params[key]; ok {
+		if s := fmt.Sprint(value); s != "" {
+			return s
+		}
+	}
+	return fallback
+}
+
+// generateVolumesSection creates the volumes section of the pod spec
+func (kp *KubernetesProvisioner) generateVolumesSection(appName string, volumes []podVolume, files []fileVolume) string {
+	if len(volumes) == 0 && len(files) == 0 {
+		return ""
+	}
+
+	var lines []string
+	for _, volume := range volumes {
+		lines = append(lines, fmt.Sprintf("      - name: %s", volume.name))
+		switch {
+		case volume.storage:
+			lines = append(lines, "        persistentVolumeClaim:",
+				fmt.Sprintf("          claimName: %s", claimName(appName, volume.resource)))
+		case volume.params["medium"] != nil || volume.params["sizeLimit"] != nil:
+			lines = append(lines, "        emptyDir:")
+			if medium := paramString(volume.params, "medium", ""); medium != "" {
+				lines = append(lines, fmt.Sprintf("          medium: %s", medium))
+			}
+			if sizeLimit := paramString(volume.params, "sizeLimit", ""); sizeLimit != "" {
+				lines = append(lines, fmt.Sprintf("          sizeLimit: %s", sizeLimit))
+			}
+		default:
+			lines = append(lines, "        emptyDir: {}")
+		}
+	}
+	lines = append(lines, kp.generateFileVolumesSection(files)...)
+	return "\n      volumes:\n" + strings.Join(lines, "\n")
+}
+
+// generateVolumeMountsSection creates the volumeMounts section of a container
+func (kp *KubernetesProvisioner) generateVolumeMountsSection(mounts []types.VolumeMount, files []fileVolume) string {
+	if len(mounts) == 0 && len(files) == 0 {
+		return ""
+	}
+
+	var lines []string
+	for _, mount := range mounts {
+		lines = append(lines,
+			fmt.Sprintf("        - name: %s", podVolumeName(volumeResourceName(mount.Source))),
+			fmt.Sprintf("          mountPath: %s", mount.Target))
+		if mount.Path != "" {
+			lines = append(lines, fmt.Sprintf("          subPath: %s", mount.Path))
+		}
+		if mount.ReadOnly {
+			lines = append(lines, "          readOnly: true")
+		}
+	}
+	lines = append(lines, kp.generateFileMountsSection(files)...)
+	return "\n        volumeMounts:\n" + strings.Join(lines, "\n")
+}
+
+// generateCommandSection creates the command and args of a container
+func (kp *KubernetesProvisioner) generateCommandSection(container types.Container) string {
+	var section string
+	if len(container.Command) > 0 {
+		command, _ := json.Marshal(container.Command)
+		section += fmt.Sprintf("\n        command: %s", command)
+	}
+	if len(container.Args) > 0 {
+		args, _ := json.Marshal(container.Args)
+		section += fmt.Sprintf("\n        args: %s", args)
+	}
+	return section
+}
+
+// generateInitContainersSection creates the initContainers section of the pod spec
+func (kp *KubernetesProvisioner) generateInitContainersSection(scoreSpec *types.ScoreSpec, files []fileVolume) string {
+	if scoreSpec == nil || len(scoreSpec.InitContainers) == 0 {
+		return ""
+	}
+
+	var containers []string
+	for _, name := range sortedContainerNames(scoreSpec.InitContainers) {
+		container := scoreSpec.InitContainers[name]
+		containers = append(containers, fmt.Sprintf("      - name: %s\n        image: %s%s%s%s",
+			name, container.Image,
+			kp.generateCommandSection(container),
+			kp.generateEnvSection(container.Variables),
+			kp.generateVolumeMountsSection(container.Volumes, containerFileVolumes(files, name))))
+	}
+	return "\n      initContainers:\n" + strings.Join(containers, "\n")
+}
+
+// generatePersistentVolumeClaim creates the PersistentVolumeClaim of a storage resource
+func (kp *KubernetesProvisioner) generatePersistentVolumeClaim(appName, namespace, resource string, params map[string]interface{}) string {
+	storageClass := ""
+	if class := paramString(params, "storageClass", ""); class != "" {
+		storageClass = fmt.Sprintf("\n  storageClassName: %s", class)
+	}
+
+	return fmt.Sprintf(`apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: %s
+  namespace: %s
+  labels:
+    app: %s
+    managed-by: innominatus
+spec:
+  accessModes:
+  - %s%s
+  resources:
+    requests:
+      storage: %s`,
+		claimName(appName, resource), namespace, appName,
+		paramString(params, "accessMode", "ReadWriteOnce"), storageClass,
+		paramString(params, "size", "1Gi"))
+}
@@ -6,6 +6,7 @@ import (
 	"innominatus/internal/events"
 	"innominatus/internal/graph"
 	"innominatus/internal/types"
+	"strings"
 
 	sdk "github.com/philipsahli/innominatus-graph/pkg/graph"
 )
@@ -96,6 +97,38 @@ func (m *Manager) CreateResourceInstance(appName string, resourceName string, re
 	return resourceInstance, nil
 }
 
+// SpecResourceConfig returns the configuration stored for a Score spec resource
+func SpecResourceConfig(appName string, resource types.Resource) map[string]interface{} {
+	// Create configuration from resource type and app_name
+	config := map[string]interface{}{
+		"type":     resource.Type,
+		"app_name": appName,
+	}
+
+	// Add all params as individual keys in configuration (backward compatibility)
+	if resource.Params != nil {
+		for key, value := range resource.Params {
+			config[key] = value
+		}
+	}
+
+	// Add all properties as individual keys in configuration
+	// Properties contain the actual resource configuration (db_name, namespace, team_id, etc.)
+	// This is the standard Score spec format
+	if resource.Properties != nil {
+		for key, value := range resource.Properties {
+			config[key] = value
+		}
+	}
+
+	// For backward compatibility, if no params or properties, add empty params
+	if resource.Params == nil && resource.Properties == nil {
+		config["params"] = nil
+	}
+
+	return config
+}
+
 // CreateResourceFromSpec creates resource instances from a Score specification
 func (m *Manager) CreateResourceFromSpec(appName string, spec *types.ScoreSpec, createdBy string) error {
 	if spec == nil {
@@ -112,32 +145,7 @@ func (m *Manager) CreateResourceFromSpec(appName string, spec *types.ScoreSpec,
 	}
 
 	for resourceName, resource := range spec.Resources {
-		// Create configuration from resource type and app_name
-		config := map[string]interface{}{
-			"type":     resource.Type,
-			"app_name": appName,
-		}
-
-		// Add all params as individual keys in configuration (backward compatibility)
-		if resource.Params != nil {
-			for key, value := range resource.Params {
-				config[key] = value
-			}
-		}
-
-		// Add all properties as individual keys in configuration
-		// Properties contain the actual resource configuration (db_name, namespace, team_id, etc.)
-		// This is the standard Score spec format
-		if resource.Properties != nil {
-			for key, value := range resource.Properties {
-				config[key] = value
-			}
-		}
-
-		// For backward compatibility, if no params or properties, add empty params
-		if resource.Params == nil && resource.Properties == nil {
-			config["params"] = nil
-		}
+		config := SpecResourceConfig(appName, resource)
 
 		// Create resource instance in database
 		resourceInstance, err := m.resourceRepo.CreateResourceInstance(
@@ -230,6 +238,46 @@ func (m *Manager) TransitionResourceState(resourceID int64, newState database.Re
 		return err
 	}
 
+	m.stateChanged(resource, newState, reason, transitionedBy)
+	return nil
+}
+
+// ForceResourceState sets the state of a resource without checking the transition, for
+// resources that are stuck, e.g. because their external system was deleted by hand. The
+// reason is required; the state history and the audit log record who forced the state.
+func (m *Manager) ForceResourceState(resourceID int64, newState database.ResourceLifecycleState, reason, forcedBy string) (*database.ResourceInstance, error) {
+	if err := m.checkRepository(); err != nil {
+		return nil, err
+	}
+	if !database.IsResourceLifecycleState(newState) {
+		return nil, fmt.Errorf("unknown resource state '%s'", newState)
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("a reason is required to force a resource state")
+	}
+
+	resource, err := m.resourceRepo.GetResourceInstance(resourceID)
+	if err != nil {
+		return nil, database.ErrResourceNotFound
+	}
+	if resource.State == newState {
+		return nil, fmt.Errorf("resource is already %s", newState)
+	}
+
+	previous, err := m.resourceRepo.ForceResourceInstanceState(resourceID, newState, reason, forcedBy)
+	if err != nil {
+		return nil, err
+	}
+	resource.State = previous
+	m.stateChanged(resource, newState, reason, forcedBy)
+
+	return m.resourceRepo.GetResourceInstance(resourceID)
+}
+
+// stateChanged publishes the state change of a resource and updates its graph node
+func (m *Manager) stateChanged(resource *database.ResourceInstance, newState database.ResourceLifecycleState, reason, transitionedBy string) {
+	resourceID := resource.ID
+
 	// Publish state transition event
 	if m.eventBus != nil {
 		var eventType events.EventType
@@ -242,6 +290,12 @@ func (m *Manager) TransitionResourceState(resourceID int64, newState database.Re
 			eventType = events.EventTypeResourceActive
 		case database.ResourceStateFailed:
 			eventType = events.EventTypeResourceFailed
+		case database.ResourceStateHibernated:
+			eventType = events.EventTypeResourceHibernated
+		case database.ResourceStateTerminating:
+			eventType = events.EventTypeResourceTerminating
+		case database.ResourceStateTerminated:
+			eventType = events.EventTypeResourceTerminated
 		default:
 			eventType = events.EventTypeResourceRequested
 		}
@@ -287,8 +341,6 @@ func (m *Manager) TransitionResourceState(resourceID int64, newState database.Re
 			fmt.Printf("📊 Updated resource node state: %s -> %s\n", resource.ResourceName, graphState)
 		}
 	}
-
-	return nil
 }
 
 // ProvisionResource provisions a resource instance using registered provisioners
@@ -317,9 +369,19 @@ func (m *Manager) ProvisionResource(resourceID int64, providerID string, provide
 			return fmt.Errorf("provisioning failed: %w", err)
 		}
 
-		// Transition to active state on success
+		// Transition to active state on success. Resource outputs may hold credentials,
+		// so they are handed to the provisioner but not recorded with the transition.
+		transitionMetadata := providerMetadata
+		if _, ok := providerMetadata[ResourceOutputsKey]; ok {
+			transitionMetadata = make(map[string]interface{}, len(providerMetadata))
+			for key, value := range providerMetadata {
+				if key != ResourceOutputsKey {
+					transitionMetadata[key] = value
+				}
+			}
+		}
 		return m.TransitionResourceState(resourceID, database.ResourceStateActive,
-			"Resource provisioned successfully", transitionedBy, providerMetadata)
+			"Resource provisioned successfully", transitionedBy, transitionMetadata)
 	}
 
 	// Fallback to legacy provisioning methods for other resource types
@@ -0,0 +1,152 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// field is the set of values a cron field matches
+type field struct {
+	values []bool // Indexed by value; true when matched
+	any    bool   // The field is * (matters for the day-of-month/day-of-week rule)
+}
+
+func (f field) matches(value int) bool {
+	return value < len(f.values) && f.values[value]
+}
+
+// Expression is a parsed five-field cron expression: minute hour day-of-month month day-of-week
+type Expression struct {
+	minute, hour, dom, month, dow field
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a cron expression. Fields accept *, values, ranges (1-5), steps (*/15,
+// 0-30/10), lists (1,15) and, for month and day-of-week, names (jan, mon). Day-of-week 7 is
+// Sunday. The @hourly, @daily, @weekly, @monthly and @yearly macros are supported.
+func ParseCron(spec string) (*Expression, error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := macros[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("invalid cron expression '%s': expected 5 fields (minute hour day-of-month month day-of-week)", spec)
+	}
+
+	var expr Expression
+	var err error
+	if expr.minute, err = parseField(parts[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if expr.hour, err = parseField(parts[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if expr.dom, err = parseField(parts[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day-of-month field: %w", err)
+	}
+	if expr.month, err = parseField(parts[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	if expr.dow, err = parseField(parts[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("invalid day-of-week field: %w", err)
+	}
+	if expr.dow.values[7] {
+		expr.dow.values[0] = true
+	}
+	return &expr, nil
+}
+
+func parseField(text string, min, max int, names map[string]int) (field, error) {
+	f := field{values: make([]bool, max+1), any: text == "*"}
+	for _, item := range strings.Split(text, ",") {
+		rangeText, stepText, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepText)
+			if err != nil || step <= 0 {
+				return field{}, fmt.Errorf("invalid step '%s'", stepText)
+			}
+		}
+
+		low, high := min, max
+		switch {
+		case rangeText == "*":
+		case strings.Contains(rangeText, "-"):
+			lowText, highText, _ := strings.Cut(rangeText, "-")
+			var err error
+			if low, err = parseValue(lowText, min, max, names); err != nil {
+				return field{}, err
+			}
+			if high, err = parseValue(highText, min, max, names); err != nil {
+				return field{}, err
+			}
+			if low > high {
+				return field{}, fmt.Errorf("invalid range '%s'", rangeText)
+			}
+		default:
+			value, err := parseValue(rangeText, min, max, names)
+			if err != nil {
+				return field{}, err
+			}
+			low = value
+			if !hasStep {
+				high = value
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			f.values[value] = true
+		}
+	}
+	return f, nil
+}
+
+func parseValue(text string, min, max int, names map[string]int) (int, error) {
+	if value, ok := names[strings.ToLower(text)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < min || value > max {
+		return 0, fmt.Errorf("'%s' is not between %d and %d", text, min, max)
+	}
+	return value, nil
+}
+
+// matchesDay applies the cron day rule: when both day-of-month and day-of-week are
+// restricted, a day matching either runs
+func (e *Expression) matchesDay(day, month int, weekday int) bool {
+	if !e.month.matches(month) {
+		return false
+	}
+	switch {
+	case e.dom.any && e.dow.any:
+		return true
+	case e.dom.any:
+		return e.dow.matches(weekday)
+	case e.dow.any:
+		return e.dom.matches(day)
+	default:
+		return e.dom.matches(day) || e.dow.matches(weekday)
+	}
+}
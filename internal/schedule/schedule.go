@@ -0,0 +1,225 @@
+// Package schedule runs golden paths on cron schedules evaluated in an explicit IANA
+// timezone. Schedules follow the wall clock of their timezone across DST transitions:
+// a run in a skipped hour happens when the clock jumps, and a run in a repeated hour
+// happens once, at its first occurrence.
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// searchDays bounds the search for the next run; covers leap days and rare weekday/date
+// combinations such as Friday the 13th
+const searchDays = 8 * 366
+
+// MaxPreviewRuns caps the runs returned by a next-run preview
+const MaxPreviewRuns = 100
+
+// Schedule runs a golden path for an application on a cron schedule
+type Schedule struct {
+	ID              int64             `json:"id"`
+	Name            string            `json:"name"`
+	ApplicationName string            `json:"application_name"`
+	GoldenPath      string            `json:"golden_path"`
+	Cron            string            `json:"cron"`     // e.g. "0 2 * * mon-fri"
+	Timezone        string            `json:"timezone"` // IANA name such as Europe/Zurich; required
+	Parameters      map[string]string `json:"parameters,omitempty"`
+	Enabled         bool              `json:"enabled"`
+	CreatedBy       string            `json:"created_by"`
+	CreatedAt       time.Time         `json:"created_at"`
+	LastRunAt       *time.Time        `json:"last_run_at,omitempty"` // Scheduled time of the last run
+	NextRunAt       *time.Time        `json:"next_run_at,omitempty"`
+}
+
+// Validate checks that the schedule definition is well-formed
+func (s *Schedule) Validate() error {
+	if s.ApplicationName == "" {
+		return fmt.Errorf("application_name is required")
+	}
+	if s.GoldenPath == "" {
+		return fmt.Errorf("golden_path is required")
+	}
+	if _, err := ParseCron(s.Cron); err != nil {
+		return err
+	}
+	_, err := s.location()
+	return err
+}
+
+// Update changes a schedule; nil fields are left as they are
+type Update struct {
+	Name       *string            `json:"name,omitempty"`
+	Cron       *string            `json:"cron,omitempty"`
+	Timezone   *string            `json:"timezone,omitempty"`
+	Parameters *map[string]string `json:"parameters,omitempty"` // Replaces all parameters
+	Enabled    *bool              `json:"enabled,omitempty"`
+}
+
+// Apply applies an update and validates the result. The next run is recomputed from now
+// when the cron expression or timezone changes or a disabled schedule is enabled, so
+// runs missed while it was disabled are not caught up.
+func (s *Schedule) Apply(u Update, now time.Time) error {
+	updated := *s
+	if u.Name != nil {
+		if *u.Name == "" {
+			return fmt.Errorf("name must not be empty")
+		}
+		updated.Name = *u.Name
+	}
+	if u.Cron != nil {
+		updated.Cron = *u.Cron
+	}
+	if u.Timezone != nil {
+		updated.Timezone = *u.Timezone
+	}
+	if u.Parameters != nil {
+		updated.Parameters = *u.Parameters
+	}
+	if u.Enabled != nil {
+		updated.Enabled = *u.Enabled
+	}
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+
+	if updated.Cron != s.Cron || updated.Timezone != s.Timezone || (updated.Enabled && !s.Enabled) || updated.NextRunAt == nil {
+		next, err := updated.Next(now)
+		if err != nil {
+			return err
+		}
+		updated.NextRunAt = &next
+	}
+	*s = updated
+	return nil
+}
+
+func (s *Schedule) location() (*time.Location, error) {
+	return LoadTimezone(s.Timezone)
+}
+
+// LoadTimezone loads an IANA timezone. The timezone must be given explicitly; "UTC" is
+// accepted, an empty name or "Local" is not.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, fmt.Errorf("timezone is required (IANA name such as Europe/Zurich or UTC)")
+	}
+	if name == "Local" {
+		return nil, fmt.Errorf("timezone 'Local' depends on the server; use an IANA name such as Europe/Zurich")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone '%s': %w", name, err)
+	}
+	return loc, nil
+}
+
+// Next returns the first run of the schedule strictly after the given time
+func (s *Schedule) Next(after time.Time) (time.Time, error) {
+	expr, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := s.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	next, ok := expr.Next(after, loc)
+	if !ok {
+		return time.Time{}, fmt.Errorf("cron expression '%s' never runs", s.Cron)
+	}
+	return next, nil
+}
+
+// NextRuns returns up to count runs strictly after the given time, in the schedule's timezone
+func (s *Schedule) NextRuns(after time.Time, count int) ([]time.Time, error) {
+	expr, err := ParseCron(s.Cron)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.location()
+	if err != nil {
+		return nil, err
+	}
+	if count > MaxPreviewRuns {
+		count = MaxPreviewRuns
+	}
+
+	runs := make([]time.Time, 0, count)
+	for len(runs) < count {
+		next, ok := expr.Next(after, loc)
+		if !ok {
+			break
+		}
+		runs = append(runs, next.In(loc))
+		after = next
+	}
+	return runs, nil
+}
+
+// Next returns the first time strictly after the given time at which the expression
+// matches the wall clock of loc
+func (e *Expression) Next(after time.Time, loc *time.Location) (time.Time, bool) {
+	local := after.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < searchDays; i++ {
+		date := day.AddDate(0, 0, i)
+		if !e.matchesDay(date.Day(), int(date.Month()), int(date.Weekday())) {
+			continue
+		}
+
+		// Skipped wall times move to the end of the DST gap, which can put them after
+		// later wall times of the same day; take the earliest instant
+		var best time.Time
+		for hour := 0; hour < 24; hour++ {
+			if !e.hour.matches(hour) {
+				continue
+			}
+			for minute := 0; minute < 60; minute++ {
+				if !e.minute.matches(minute) {
+					continue
+				}
+				at := resolveWallClock(date.Year(), date.Month(), date.Day(), hour, minute, loc)
+				if at.After(after) && (best.IsZero() || at.Before(best)) {
+					best = at
+				}
+			}
+		}
+		if !best.IsZero() {
+			return best, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// resolveWallClock returns the instant a wall clock time occurs in loc. A time skipped by
+// a DST gap resolves to the end of the gap; a time repeated by a DST overlap resolves to
+// its first occurrence.
+func resolveWallClock(year int, month time.Month, day, hour, minute int, loc *time.Location) time.Time {
+	at := time.Date(year, month, day, hour, minute, 0, 0, loc)
+	if at.Day() != day || at.Hour() != hour || at.Minute() != minute {
+		// The wall time does not exist; the zone at the normalized time starts at the gap's end
+		start, _ := at.ZoneBounds()
+		if !start.IsZero() {
+			return start
+		}
+		return at
+	}
+
+	// The wall time may also exist in the preceding zone, e.g. 02:30 when clocks fall back
+	start, _ := at.ZoneBounds()
+	if start.IsZero() {
+		return at
+	}
+	_, previousOffset := start.Add(-time.Nanosecond).Zone()
+	wall := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	earlier := wall.Add(-time.Duration(previousOffset) * time.Second)
+	if earlier.Before(start) {
+		local := earlier.In(loc)
+		if local.Day() == day && local.Hour() == hour && local.Minute() == minute {
+			return earlier
+		}
+	}
+	return at
+}
@@ -0,0 +1,199 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func zurich(t *testing.T) *time.Location {
+	loc, err := time.LoadLocation("Europe/Zurich")
+	require.NoError(t, err)
+	return loc
+}
+
+func nightlySchedule(cron string) *Schedule {
+	return &Schedule{
+		ApplicationName: "my-app",
+		GoldenPath:      "deploy-app",
+		Cron:            cron,
+		Timezone:        "Europe/Zurich",
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	valid := []string{"* * * * *", "*/15 2-4 1,15 jan-mar mon-fri", "0 0 * * 7", "@daily", "@HOURLY"}
+	for _, spec := range valid {
+		_, err := ParseCron(spec)
+		assert.NoError(t, err, spec)
+	}
+
+	invalid := map[string]string{
+		"* * * *":        "expected 5 fields",
+		"60 * * * *":     "invalid minute field",
+		"0 24 * * *":     "invalid hour field",
+		"0 0 0 * *":      "invalid day-of-month field",
+		"0 0 * foo *":    "invalid month field",
+		"0 0 * * 8":      "invalid day-of-week field",
+		"*/0 * * * *":    "invalid step",
+		"0 5-2 * * *":    "invalid range",
+		"0 0 * * funday": "invalid day-of-week field",
+	}
+	for spec, wantErr := range invalid {
+		_, err := ParseCron(spec)
+		require.Error(t, err, spec)
+		assert.Contains(t, err.Error(), wantErr, spec)
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(s *Schedule)
+		wantErr string
+	}{
+		{name: "valid schedule", modify: func(s *Schedule) {}},
+		{name: "UTC", modify: func(s *Schedule) { s.Timezone = "UTC" }},
+		{name: "missing app", modify: func(s *Schedule) { s.ApplicationName = "" }, wantErr: "application_name"},
+		{name: "missing golden path", modify: func(s *Schedule) { s.GoldenPath = "" }, wantErr: "golden_path"},
+		{name: "invalid cron", modify: func(s *Schedule) { s.Cron = "daily" }, wantErr: "invalid cron expression"},
+		{name: "missing timezone", modify: func(s *Schedule) { s.Timezone = "" }, wantErr: "timezone is required"},
+		{name: "local timezone", modify: func(s *Schedule) { s.Timezone = "Local" }, wantErr: "depends on the server"},
+		{name: "invalid timezone", modify: func(s *Schedule) { s.Timezone = "Mars/Base" }, wantErr: "invalid timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := nightlySchedule("0 2 * * *")
+			tt.modify(s)
+			err := s.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestNextUsesScheduleTimezone(t *testing.T) {
+	s := nightlySchedule("0 2 * * *")
+
+	// 02:00 in Zurich is 00:00 UTC in summer
+	next, err := s.Next(time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestNextDayRules(t *testing.T) {
+	loc := time.UTC
+	// 2026-10-15 is a Thursday
+	after := time.Date(2026, 10, 15, 12, 0, 0, 0, loc)
+
+	weekdays, err := ParseCron("0 9 * * mon-fri")
+	require.NoError(t, err)
+	next, ok := weekdays.Next(after, loc)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 10, 16, 9, 0, 0, 0, loc), next)
+	next, _ = weekdays.Next(next, loc)
+	assert.Equal(t, time.Date(2026, 10, 19, 9, 0, 0, 0, loc), next, "skips the weekend")
+
+	// Day-of-month and day-of-week restricted: either matches
+	either, err := ParseCron("0 0 20 * sat")
+	require.NoError(t, err)
+	next, _ = either.Next(after, loc)
+	assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, loc), next)
+	next, _ = either.Next(next, loc)
+	assert.Equal(t, time.Date(2026, 10, 20, 0, 0, 0, 0, loc), next)
+
+	leap, err := ParseCron("0 0 29 feb *")
+	require.NoError(t, err)
+	next, ok = leap.Next(after, loc)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2028, 2, 29, 0, 0, 0, 0, loc), next)
+
+	never, err := ParseCron("0 0 31 feb *")
+	require.NoError(t, err)
+	_, ok = never.Next(after, loc)
+	assert.False(t, ok)
+}
+
+func TestNextAcrossSpringForward(t *testing.T) {
+	loc := zurich(t)
+	// On 2026-03-29 Zurich clocks jump from 02:00 to 03:00
+	s := nightlySchedule("30 2 * * *")
+
+	runs, err := s.NextRuns(time.Date(2026, 3, 28, 12, 0, 0, 0, loc), 3)
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	assert.Equal(t, time.Date(2026, 3, 29, 3, 0, 0, 0, loc), runs[0], "skipped run happens when the clock jumps")
+	assert.Equal(t, time.Date(2026, 3, 30, 2, 30, 0, 0, loc), runs[1])
+
+	// Runs before and in the gap collapse to distinct instants, in order
+	hourly := nightlySchedule("30 * * * *")
+	runs, err = hourly.NextRuns(time.Date(2026, 3, 29, 1, 0, 0, 0, loc), 3)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 29, 1, 30, 0, 0, loc), runs[0])
+	assert.Equal(t, time.Date(2026, 3, 29, 3, 0, 0, 0, loc), runs[1])
+	assert.Equal(t, time.Date(2026, 3, 29, 3, 30, 0, 0, loc), runs[2])
+}
+
+func TestNextAcrossFallBack(t *testing.T) {
+	loc := zurich(t)
+	// On 2026-10-25 Zurich clocks go back from 03:00 to 02:00, so 02:30 occurs twice
+	s := nightlySchedule("30 2 * * *")
+
+	runs, err := s.NextRuns(time.Date(2026, 10, 24, 12, 0, 0, 0, loc), 2)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	// 02:30 CEST is 00:30 UTC; the repeated 02:30 CET (01:30 UTC) does not run again
+	assert.Equal(t, time.Date(2026, 10, 25, 0, 30, 0, 0, time.UTC), runs[0].UTC())
+	assert.Equal(t, time.Date(2026, 10, 26, 2, 30, 0, 0, loc), runs[1])
+
+	hourly := nightlySchedule("0 * * * *")
+	runs, err = hourly.NextRuns(time.Date(2026, 10, 25, 0, 30, 0, 0, time.UTC), 3)
+	require.NoError(t, err)
+	// The repeated 02:00 CET (01:00 UTC) is skipped; the next run is 03:00 CET
+	assert.Equal(t, time.Date(2026, 10, 25, 2, 0, 0, 0, time.UTC), runs[0].UTC())
+	assert.Equal(t, time.Date(2026, 10, 25, 3, 0, 0, 0, time.UTC), runs[1].UTC())
+}
+
+func TestNextRunsCapped(t *testing.T) {
+	s := nightlySchedule("* * * * *")
+	runs, err := s.NextRuns(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), 1000)
+	require.NoError(t, err)
+	assert.Len(t, runs, MaxPreviewRuns)
+}
+
+func TestApplyUpdate(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	s := nightlySchedule("0 2 * * *")
+	s.Enabled = true
+	next, err := s.Next(now)
+	require.NoError(t, err)
+	s.NextRunAt = &next
+
+	// Parameters alone keep the next run
+	parameters := map[string]string{"environment": "staging"}
+	require.NoError(t, s.Apply(Update{Parameters: &parameters}, now.Add(time.Hour)))
+	assert.Equal(t, next, *s.NextRunAt)
+	assert.Equal(t, parameters, s.Parameters)
+
+	cron, timezone := "0 3 * * *", "UTC"
+	require.NoError(t, s.Apply(Update{Cron: &cron, Timezone: &timezone}, now))
+	assert.Equal(t, time.Date(2026, 7, 2, 3, 0, 0, 0, time.UTC), *s.NextRunAt)
+
+	// Enabling a schedule does not catch up on missed runs
+	disabled, enabled := false, true
+	require.NoError(t, s.Apply(Update{Enabled: &disabled}, now))
+	require.NoError(t, s.Apply(Update{Enabled: &enabled}, now.AddDate(0, 0, 3)))
+	assert.Equal(t, time.Date(2026, 7, 5, 3, 0, 0, 0, time.UTC), *s.NextRunAt)
+
+	invalid, empty := "daily", ""
+	assert.ErrorContains(t, s.Apply(Update{Cron: &invalid}, now), "invalid cron expression")
+	assert.ErrorContains(t, s.Apply(Update{Name: &empty}, now), "name must not be empty")
+	assert.Equal(t, "0 3 * * *", s.Cron, "a rejected update leaves the schedule unchanged")
+}
@@ -0,0 +1,180 @@
+// Package scim implements the SCIM 2.0 (RFC 7643/7644) Users and Groups resources
+// so identity providers can provision users and teams into the directory tables.
+package scim
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ContentType is the media type of SCIM requests and responses
+const ContentType = "application/scim+json"
+
+// SCIM schema URNs
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// BasePath is where the SCIM resources are served
+const BasePath = "/scim/v2"
+
+// MaxResults caps the page size of list requests
+const MaxResults = 200
+
+// Config is the scim section of admin-config.yaml
+type Config struct {
+	Enabled  bool   `yaml:"enabled" json:"enabled"`
+	TokenEnv string `yaml:"tokenEnv" json:"tokenEnv"` // Environment variable holding the bearer token of the identity provider
+}
+
+// Authorize checks the bearer token of a SCIM request against the configured token.
+// SCIM is unavailable when no token is configured.
+func (c Config) Authorize(r *http.Request) bool {
+	if !c.Enabled || c.TokenEnv == "" {
+		return false
+	}
+	expected := os.Getenv(c.TokenEnv)
+	if expected == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// User is the SCIM User resource
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	UserName    string   `json:"userName"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Name        *Name    `json:"name,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"` // Defaults to true when omitted
+	Groups      []Ref    `json:"groups,omitempty"` // Read-only, managed through Groups
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// Name is the name of a SCIM user
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email is an email address of a SCIM user
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Group is the SCIM Group resource; groups are innominatus teams
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	ExternalID  string   `json:"externalId,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Ref    `json:"members,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// Ref references a user or group by ID
+type Ref struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// Meta is the resource metadata
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// ListResponse is a page of resources
+type ListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// PatchRequest is a SCIM PATCH request body
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is a single add, remove or replace operation
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Error is a SCIM error response. It is returned by the Service for requests the
+// client has to fix; other errors are server failures.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// StatusCode returns the HTTP status of the error
+func (e *Error) StatusCode() int {
+	var code int
+	_, _ = fmt.Sscanf(e.Status, "%d", &code)
+	return code
+}
+
+// NewError creates a SCIM error; scimType may be empty
+func NewError(status int, scimType, format string, args ...interface{}) *Error {
+	return &Error{
+		Schemas:  []string{SchemaError},
+		Status:   fmt.Sprintf("%d", status),
+		ScimType: scimType,
+		Detail:   fmt.Sprintf(format, args...),
+	}
+}
+
+// ServiceProviderConfig describes the supported SCIM features
+func ServiceProviderConfig() map[string]interface{} {
+	supported := func(ok bool) map[string]bool { return map[string]bool{"supported": ok} }
+	return map[string]interface{}{
+		"schemas":        []string{SchemaServiceProviderConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": MaxResults},
+		"changePassword": supported(false),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "Token configured in admin-config.yaml (scim.tokenEnv)",
+			"primary":     true,
+		}},
+	}
+}
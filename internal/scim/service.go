@@ -0,0 +1,566 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"innominatus/internal/database"
+)
+
+// Store persists provisioned users and teams; implemented by *database.Database
+type Store interface {
+	CreateDirectoryUser(user *database.DirectoryUser) error
+	GetDirectoryUser(id int64) (*database.DirectoryUser, error)
+	ListDirectoryUsers(username string, offset, limit int) ([]*database.DirectoryUser, int, error)
+	UpdateDirectoryUser(user *database.DirectoryUser) error
+	DeleteDirectoryUser(id int64) error
+	CreateDirectoryTeam(team *database.DirectoryTeam) error
+	GetDirectoryTeam(id int64) (*database.DirectoryTeam, error)
+	ListDirectoryTeams(name string, offset, limit int) ([]*database.DirectoryTeam, int, error)
+	UpdateDirectoryTeam(team *database.DirectoryTeam) error
+	DeleteDirectoryTeam(id int64) error
+	DeleteUserSessions(username string) (int64, error)
+	DeleteUserAPIKeys(username string) (int64, error)
+}
+
+// Service maps SCIM resources onto the directory store
+type Service struct {
+	store Store
+}
+
+// NewService creates a SCIM service
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+var (
+	filterPattern       = regexp.MustCompile(`(?i)^\s*(\w+)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+	memberFilterPattern = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+"([^"]*)"\s*\]$`)
+)
+
+// parseFilter supports the equality filters identity providers use to look up a
+// resource before creating it, e.g. userName eq "alice"
+func parseFilter(filter, attribute string) (string, error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", nil
+	}
+	match := filterPattern.FindStringSubmatch(filter)
+	if match == nil || !strings.EqualFold(match[1], attribute) {
+		return "", NewError(http.StatusBadRequest, "invalidFilter", "unsupported filter %q, only '%s eq \"value\"' is supported", filter, attribute)
+	}
+	value, err := strconv.Unquote(`"` + match[2] + `"`)
+	if err != nil {
+		return "", NewError(http.StatusBadRequest, "invalidFilter", "invalid filter value in %q", filter)
+	}
+	return value, nil
+}
+
+// page converts the 1-based startIndex and count of a list request to offset and limit
+func page(startIndex, count int) (int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 || count > MaxResults {
+		count = MaxResults
+	}
+	return startIndex - 1, count
+}
+
+func parseID(id string) (int64, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, NewError(http.StatusNotFound, "", "resource %s not found", id)
+	}
+	return n, nil
+}
+
+// storeError converts directory errors to SCIM errors
+func storeError(err error, resource string) error {
+	switch {
+	case errors.Is(err, database.ErrDirectoryNotFound):
+		return NewError(http.StatusNotFound, "", "%s not found", resource)
+	case errors.Is(err, database.ErrDirectoryConflict):
+		return NewError(http.StatusConflict, "uniqueness", "%s already exists", resource)
+	}
+	return err
+}
+
+// ListUsers returns a page of users; count -1 uses the maximum page size
+func (s *Service) ListUsers(filter string, startIndex, count int) (*ListResponse, error) {
+	username, err := parseFilter(filter, "userName")
+	if err != nil {
+		return nil, err
+	}
+	offset, limit := page(startIndex, count)
+	items, total, err := s.store.ListDirectoryUsers(username, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*User, 0, len(items))
+	for _, item := range items {
+		resources = append(resources, toSCIMUser(item))
+	}
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   offset + 1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+// GetUser returns a user by ID
+func (s *Service) GetUser(id string) (*User, error) {
+	userID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.store.GetDirectoryUser(userID)
+	if err != nil {
+		return nil, storeError(err, "user "+id)
+	}
+	return toSCIMUser(user), nil
+}
+
+// CreateUser provisions a user
+func (s *Service) CreateUser(user User) (*User, error) {
+	record, err := fromSCIMUser(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateDirectoryUser(record); err != nil {
+		return nil, storeError(err, "user "+record.Username)
+	}
+	return toSCIMUser(record), nil
+}
+
+// ReplaceUser replaces all attributes of a user
+func (s *Service) ReplaceUser(id string, user User) (*User, error) {
+	userID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.store.GetDirectoryUser(userID)
+	if err != nil {
+		return nil, storeError(err, "user "+id)
+	}
+	return s.updateUser(current, user)
+}
+
+// PatchUser applies add, replace and remove operations to a user
+func (s *Service) PatchUser(id string, patch PatchRequest) (*User, error) {
+	userID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.store.GetDirectoryUser(userID)
+	if err != nil {
+		return nil, storeError(err, "user "+id)
+	}
+	user := toSCIMUser(current)
+	for _, op := range patch.Operations {
+		if err := patchUser(user, op); err != nil {
+			return nil, err
+		}
+	}
+	return s.updateUser(current, *user)
+}
+
+// updateUser stores the new attributes. Deactivated and renamed users are logged out.
+func (s *Service) updateUser(current *database.DirectoryUser, user User) (*User, error) {
+	record, err := fromSCIMUser(user)
+	if err != nil {
+		return nil, err
+	}
+	record.ID = current.ID
+	if err := s.store.UpdateDirectoryUser(record); err != nil {
+		return nil, storeError(err, "user "+record.Username)
+	}
+	if (current.Active && !record.Active) || current.Username != record.Username {
+		if _, err := s.store.DeleteUserSessions(current.Username); err != nil {
+			return nil, err
+		}
+	}
+	return toSCIMUser(record), nil
+}
+
+// DeleteUser deprovisions a user, ending its sessions and revoking its API keys
+func (s *Service) DeleteUser(id string) error {
+	userID, err := parseID(id)
+	if err != nil {
+		return err
+	}
+	user, err := s.store.GetDirectoryUser(userID)
+	if err != nil {
+		return storeError(err, "user "+id)
+	}
+	if err := s.store.DeleteDirectoryUser(userID); err != nil {
+		return storeError(err, "user "+id)
+	}
+	if _, err := s.store.DeleteUserSessions(user.Username); err != nil {
+		return err
+	}
+	if _, err := s.store.DeleteUserAPIKeys(user.Username); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListGroups returns a page of groups; count -1 uses the maximum page size
+func (s *Service) ListGroups(filter string, startIndex, count int) (*ListResponse, error) {
+	name, err := parseFilter(filter, "displayName")
+	if err != nil {
+		return nil, err
+	}
+	offset, limit := page(startIndex, count)
+	items, total, err := s.store.ListDirectoryTeams(name, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*Group, 0, len(items))
+	for _, item := range items {
+		resources = append(resources, toSCIMGroup(item))
+	}
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   offset + 1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+// GetGroup returns a group by ID
+func (s *Service) GetGroup(id string) (*Group, error) {
+	teamID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.store.GetDirectoryTeam(teamID)
+	if err != nil {
+		return nil, storeError(err, "group "+id)
+	}
+	return toSCIMGroup(team), nil
+}
+
+// CreateGroup provisions a team with its members
+func (s *Service) CreateGroup(group Group) (*Group, error) {
+	team, err := s.fromSCIMGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateDirectoryTeam(team); err != nil {
+		return nil, storeError(err, "group "+team.Name)
+	}
+	return toSCIMGroup(team), nil
+}
+
+// ReplaceGroup replaces the name and members of a team
+func (s *Service) ReplaceGroup(id string, group Group) (*Group, error) {
+	teamID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.fromSCIMGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	team.ID = teamID
+	if err := s.store.UpdateDirectoryTeam(team); err != nil {
+		return nil, storeError(err, "group "+id)
+	}
+	return toSCIMGroup(team), nil
+}
+
+// PatchGroup applies add, replace and remove operations to a team
+func (s *Service) PatchGroup(id string, patch PatchRequest) (*Group, error) {
+	teamID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.store.GetDirectoryTeam(teamID)
+	if err != nil {
+		return nil, storeError(err, "group "+id)
+	}
+	group := toSCIMGroup(current)
+	for _, op := range patch.Operations {
+		if err := patchGroup(group, op); err != nil {
+			return nil, err
+		}
+	}
+	return s.ReplaceGroup(id, *group)
+}
+
+// DeleteGroup deprovisions a team; its members stay provisioned
+func (s *Service) DeleteGroup(id string) error {
+	teamID, err := parseID(id)
+	if err != nil {
+		return err
+	}
+	return storeError(s.store.DeleteDirectoryTeam(teamID), "group "+id)
+}
+
+func toSCIMUser(user *database.DirectoryUser) *User {
+	id := strconv.FormatInt(user.ID, 10)
+	active := user.Active
+	result := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          id,
+		ExternalID:  user.ExternalID,
+		UserName:    user.Username,
+		DisplayName: user.DisplayName,
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     BasePath + "/Users/" + id,
+		},
+	}
+	if user.Email != "" {
+		result.Emails = []Email{{Value: user.Email, Type: "work", Primary: true}}
+	}
+	return result
+}
+
+func fromSCIMUser(user User) (*database.DirectoryUser, error) {
+	if strings.TrimSpace(user.UserName) == "" {
+		return nil, NewError(http.StatusBadRequest, "invalidValue", "userName is required")
+	}
+	record := &database.DirectoryUser{
+		Username:    user.UserName,
+		ExternalID:  user.ExternalID,
+		DisplayName: user.DisplayName,
+		Active:      user.Active == nil || *user.Active,
+	}
+	if record.DisplayName == "" && user.Name != nil {
+		record.DisplayName = user.Name.Formatted
+		if record.DisplayName == "" {
+			record.DisplayName = strings.TrimSpace(user.Name.GivenName + " " + user.Name.FamilyName)
+		}
+	}
+	for i, email := range user.Emails {
+		if email.Primary || i == 0 {
+			record.Email = email.Value
+		}
+	}
+	return record, nil
+}
+
+func toSCIMGroup(team *database.DirectoryTeam) *Group {
+	id := strconv.FormatInt(team.ID, 10)
+	group := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          id,
+		ExternalID:  team.ExternalID,
+		DisplayName: team.Name,
+		Members:     []Ref{},
+		Meta: &Meta{
+			ResourceType: "Group",
+			Created:      team.CreatedAt,
+			LastModified: team.UpdatedAt,
+			Location:     BasePath + "/Groups/" + id,
+		},
+	}
+	for _, member := range team.Members {
+		memberID := strconv.FormatInt(member.UserID, 10)
+		group.Members = append(group.Members, Ref{
+			Value:   memberID,
+			Display: member.Username,
+			Ref:     BasePath + "/Users/" + memberID,
+		})
+	}
+	return group
+}
+
+// fromSCIMGroup converts a group and checks that its members are provisioned
+func (s *Service) fromSCIMGroup(group Group) (*database.DirectoryTeam, error) {
+	if strings.TrimSpace(group.DisplayName) == "" {
+		return nil, NewError(http.StatusBadRequest, "invalidValue", "displayName is required")
+	}
+	team := &database.DirectoryTeam{Name: group.DisplayName, ExternalID: group.ExternalID}
+	for _, member := range group.Members {
+		userID, err := strconv.ParseInt(member.Value, 10, 64)
+		if err == nil {
+			_, err = s.store.GetDirectoryUser(userID)
+		}
+		if err != nil {
+			if errors.Is(err, database.ErrDirectoryNotFound) || errors.As(err, new(*strconv.NumError)) {
+				return nil, NewError(http.StatusBadRequest, "invalidValue", "member %s is not a provisioned user", member.Value)
+			}
+			return nil, err
+		}
+		team.Members = append(team.Members, database.DirectoryMember{UserID: userID})
+	}
+	return team, nil
+}
+
+// patchUser applies one operation. Attributes innominatus does not store, such as
+// addresses or phone numbers, are ignored so identity providers can send full profiles.
+func patchUser(user *User, op PatchOperation) error {
+	operation := strings.ToLower(op.Op)
+	if operation != "add" && operation != "replace" && operation != "remove" {
+		return NewError(http.StatusBadRequest, "invalidSyntax", "unsupported patch operation %q", op.Op)
+	}
+
+	if op.Path == "" {
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attributes); err != nil {
+			return NewError(http.StatusBadRequest, "invalidValue", "patch without path needs an object value")
+		}
+		for path, value := range attributes {
+			if err := patchUser(user, PatchOperation{Op: op.Op, Path: path, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	path := strings.ToLower(op.Path)
+	if operation == "remove" {
+		switch {
+		case path == "displayname":
+			user.DisplayName = ""
+		case path == "externalid":
+			user.ExternalID = ""
+		case strings.HasPrefix(path, "emails"):
+			user.Emails = nil
+		}
+		return nil
+	}
+
+	switch {
+	case path == "active":
+		active, err := parseBool(op.Value)
+		if err != nil {
+			return err
+		}
+		user.Active = &active
+	case path == "username":
+		return unmarshalValue(op, &user.UserName)
+	case path == "displayname":
+		return unmarshalValue(op, &user.DisplayName)
+	case path == "externalid":
+		return unmarshalValue(op, &user.ExternalID)
+	case path == "emails":
+		return unmarshalValue(op, &user.Emails)
+	case strings.HasPrefix(path, "emails[") && strings.HasSuffix(path, ".value"):
+		var email string
+		if err := unmarshalValue(op, &email); err != nil {
+			return err
+		}
+		user.Emails = []Email{{Value: email, Type: "work", Primary: true}}
+	}
+	return nil
+}
+
+// patchGroup applies one operation to the name or members of a group
+func patchGroup(group *Group, op PatchOperation) error {
+	operation := strings.ToLower(op.Op)
+	path := strings.ToLower(op.Path)
+
+	if op.Path == "" && operation != "remove" {
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attributes); err != nil {
+			return NewError(http.StatusBadRequest, "invalidValue", "patch without path needs an object value")
+		}
+		for attribute, value := range attributes {
+			// id is sent by some identity providers alongside the attributes and is immutable
+			if strings.EqualFold(attribute, "id") {
+				continue
+			}
+			if err := patchGroup(group, PatchOperation{Op: op.Op, Path: attribute, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if match := memberFilterPattern.FindStringSubmatch(op.Path); match != nil && operation == "remove" {
+		group.Members = removeMembers(group.Members, []Ref{{Value: match[1]}})
+		return nil
+	}
+
+	switch {
+	case path == "displayname" && (operation == "add" || operation == "replace"):
+		return unmarshalValue(op, &group.DisplayName)
+	case path == "externalid" && (operation == "add" || operation == "replace"):
+		return unmarshalValue(op, &group.ExternalID)
+	case path == "members":
+		var members []Ref
+		if len(op.Value) > 0 {
+			if err := unmarshalValue(op, &members); err != nil {
+				return err
+			}
+		}
+		switch operation {
+		case "add":
+			group.Members = append(removeMembers(group.Members, members), members...)
+		case "replace":
+			group.Members = members
+		case "remove":
+			if len(op.Value) == 0 {
+				group.Members = nil
+			} else {
+				group.Members = removeMembers(group.Members, members)
+			}
+		default:
+			return NewError(http.StatusBadRequest, "invalidSyntax", "unsupported patch operation %q", op.Op)
+		}
+		return nil
+	}
+	return NewError(http.StatusBadRequest, "invalidPath", "unsupported patch %s of %q", op.Op, op.Path)
+}
+
+func removeMembers(members, remove []Ref) []Ref {
+	kept := make([]Ref, 0, len(members))
+	for _, member := range members {
+		removed := false
+		for _, r := range remove {
+			if r.Value == member.Value {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			kept = append(kept, member)
+		}
+	}
+	return kept
+}
+
+func unmarshalValue(op PatchOperation, target interface{}) error {
+	if err := json.Unmarshal(op.Value, target); err != nil {
+		return NewError(http.StatusBadRequest, "invalidValue", "invalid value for %s", op.Path)
+	}
+	return nil
+}
+
+// parseBool accepts JSON booleans and the "True"/"False" strings Azure AD sends
+func parseBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, NewError(http.StatusBadRequest, "invalidValue", "invalid boolean %s", string(value))
+}
+
+// ErrorResponse converts any error to a SCIM error. Server failures get a generic
+// detail; the caller logs the original error.
+func ErrorResponse(err error) *Error {
+	var scimErr *Error
+	if errors.As(err, &scimErr) {
+		return scimErr
+	}
+	return NewError(http.StatusInternalServerError, "", "internal server error")
+}
@@ -0,0 +1,394 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"innominatus/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore keeps the directory in maps and records ended sessions and revoked keys
+type memoryStore struct {
+	users    map[int64]*database.DirectoryUser
+	teams    map[int64]*database.DirectoryTeam
+	nextID   int64
+	sessions []string
+	apiKeys  []string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{users: map[int64]*database.DirectoryUser{}, teams: map[int64]*database.DirectoryTeam{}}
+}
+
+func (m *memoryStore) CreateDirectoryUser(user *database.DirectoryUser) error {
+	for _, u := range m.users {
+		if u.Username == user.Username {
+			return database.ErrDirectoryConflict
+		}
+	}
+	m.nextID++
+	user.ID = m.nextID
+	stored := *user
+	m.users[user.ID] = &stored
+	return nil
+}
+
+func (m *memoryStore) GetDirectoryUser(id int64) (*database.DirectoryUser, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, database.ErrDirectoryNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (m *memoryStore) ListDirectoryUsers(username string, offset, limit int) ([]*database.DirectoryUser, int, error) {
+	var matches []*database.DirectoryUser
+	for _, u := range m.users {
+		if username == "" || u.Username == username {
+			matches = append(matches, u)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	total := len(matches)
+	if offset > len(matches) {
+		offset = len(matches)
+	}
+	matches = matches[offset:]
+	if limit < len(matches) {
+		matches = matches[:limit]
+	}
+	return matches, total, nil
+}
+
+func (m *memoryStore) UpdateDirectoryUser(user *database.DirectoryUser) error {
+	if _, ok := m.users[user.ID]; !ok {
+		return database.ErrDirectoryNotFound
+	}
+	stored := *user
+	m.users[user.ID] = &stored
+	return nil
+}
+
+func (m *memoryStore) DeleteDirectoryUser(id int64) error {
+	if _, ok := m.users[id]; !ok {
+		return database.ErrDirectoryNotFound
+	}
+	delete(m.users, id)
+	return nil
+}
+
+func (m *memoryStore) CreateDirectoryTeam(team *database.DirectoryTeam) error {
+	m.nextID++
+	team.ID = m.nextID
+	return m.UpdateDirectoryTeam(team)
+}
+
+func (m *memoryStore) GetDirectoryTeam(id int64) (*database.DirectoryTeam, error) {
+	team, ok := m.teams[id]
+	if !ok {
+		return nil, database.ErrDirectoryNotFound
+	}
+	copied := *team
+	return &copied, nil
+}
+
+func (m *memoryStore) ListDirectoryTeams(name string, offset, limit int) ([]*database.DirectoryTeam, int, error) {
+	var matches []*database.DirectoryTeam
+	for _, t := range m.teams {
+		if name == "" || t.Name == name {
+			matches = append(matches, t)
+		}
+	}
+	return matches, len(matches), nil
+}
+
+func (m *memoryStore) UpdateDirectoryTeam(team *database.DirectoryTeam) error {
+	var members []database.DirectoryMember
+	for _, member := range team.Members {
+		members = append(members, database.DirectoryMember{UserID: member.UserID, Username: m.users[member.UserID].Username})
+	}
+	team.Members = members
+	stored := *team
+	m.teams[team.ID] = &stored
+	return nil
+}
+
+func (m *memoryStore) DeleteDirectoryTeam(id int64) error {
+	if _, ok := m.teams[id]; !ok {
+		return database.ErrDirectoryNotFound
+	}
+	delete(m.teams, id)
+	return nil
+}
+
+func (m *memoryStore) DeleteUserSessions(username string) (int64, error) {
+	m.sessions = append(m.sessions, username)
+	return 1, nil
+}
+
+func (m *memoryStore) DeleteUserAPIKeys(username string) (int64, error) {
+	m.apiKeys = append(m.apiKeys, username)
+	return 1, nil
+}
+
+func patchRequest(t *testing.T, operations string) PatchRequest {
+	t.Helper()
+	var patch PatchRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"schemas":["`+SchemaPatchOp+`"],"Operations":`+operations+`}`), &patch))
+	return patch
+}
+
+func TestCreateAndFindUser(t *testing.T) {
+	service := NewService(newMemoryStore())
+
+	created, err := service.CreateUser(User{
+		UserName: "alice@example.com",
+		Name:     &Name{GivenName: "Alice", FamilyName: "Smith"},
+		Emails:   []Email{{Value: "alice.private@example.com"}, {Value: "alice@example.com", Primary: true}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1", created.ID)
+	assert.Equal(t, "Alice Smith", created.DisplayName)
+	assert.Equal(t, []Email{{Value: "alice@example.com", Type: "work", Primary: true}}, created.Emails)
+	require.NotNil(t, created.Active)
+	assert.True(t, *created.Active, "users are active unless the identity provider says otherwise")
+	assert.Equal(t, "/scim/v2/Users/1", created.Meta.Location)
+
+	list, err := service.ListUsers(`userName eq "alice@example.com"`, 1, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.TotalResults)
+
+	_, err = service.CreateUser(User{UserName: "alice@example.com"})
+	assertSCIMError(t, err, http.StatusConflict, "uniqueness")
+
+	_, err = service.CreateUser(User{})
+	assertSCIMError(t, err, http.StatusBadRequest, "invalidValue")
+}
+
+func TestListUsersFilter(t *testing.T) {
+	service := NewService(newMemoryStore())
+
+	tests := []struct {
+		name     string
+		filter   string
+		wantErr  bool
+		wantName string
+	}{
+		{name: "no filter", filter: ""},
+		{name: "attribute is case-insensitive", filter: `USERNAME eq "bob"`, wantName: "bob"},
+		{name: "escaped quote", filter: `userName eq "b\"ob"`, wantName: `b"ob`},
+		{name: "unsupported attribute", filter: `emails.value eq "bob@example.com"`, wantErr: true},
+		{name: "unsupported operator", filter: `userName sw "b"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := parseFilter(tt.filter, "userName")
+			if tt.wantErr {
+				assertSCIMError(t, err, http.StatusBadRequest, "invalidFilter")
+				_, err = service.ListUsers(tt.filter, 1, 10)
+				assertSCIMError(t, err, http.StatusBadRequest, "invalidFilter")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
+
+func TestListUsersPaging(t *testing.T) {
+	service := NewService(newMemoryStore())
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := service.CreateUser(User{UserName: name})
+		require.NoError(t, err)
+	}
+
+	list, err := service.ListUsers("", 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, list.TotalResults)
+	assert.Equal(t, 2, list.StartIndex)
+	assert.Equal(t, 1, list.ItemsPerPage)
+	assert.Equal(t, "b", list.Resources.([]*User)[0].UserName)
+}
+
+func TestPatchUser(t *testing.T) {
+	tests := []struct {
+		name          string
+		operations    string
+		wantActive    bool
+		wantDisplay   string
+		wantEmail     string
+		wantLoggedOut bool
+	}{
+		{
+			name:          "azure deactivation with string boolean",
+			operations:    `[{"op":"Replace","path":"active","value":"False"}]`,
+			wantDisplay:   "Bob",
+			wantLoggedOut: true,
+		},
+		{
+			name:          "okta deactivation without path",
+			operations:    `[{"op":"replace","value":{"active":false}}]`,
+			wantDisplay:   "Bob",
+			wantLoggedOut: true,
+		},
+		{
+			name:        "attribute updates keep the user logged in",
+			operations:  `[{"op":"replace","path":"displayName","value":"Bobby"},{"op":"add","path":"emails[type eq \"work\"].value","value":"bobby@example.com"},{"op":"replace","path":"title","value":"Engineer"}]`,
+			wantActive:  true,
+			wantDisplay: "Bobby",
+			wantEmail:   "bobby@example.com",
+		},
+		{
+			name:        "remove email",
+			operations:  `[{"op":"remove","path":"emails"}]`,
+			wantActive:  true,
+			wantDisplay: "Bob",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore()
+			service := NewService(store)
+			created, err := service.CreateUser(User{UserName: "bob", DisplayName: "Bob", Emails: []Email{{Value: "bob@example.com"}}})
+			require.NoError(t, err)
+
+			patched, err := service.PatchUser(created.ID, patchRequest(t, tt.operations))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantActive, *patched.Active)
+			assert.Equal(t, tt.wantDisplay, patched.DisplayName)
+			if tt.wantLoggedOut {
+				assert.Equal(t, []string{"bob"}, store.sessions)
+			} else {
+				assert.Empty(t, store.sessions)
+				if tt.wantEmail == "" {
+					assert.Empty(t, patched.Emails)
+				} else {
+					assert.Equal(t, tt.wantEmail, patched.Emails[0].Value)
+				}
+			}
+		})
+	}
+}
+
+func TestPatchUserInvalid(t *testing.T) {
+	service := NewService(newMemoryStore())
+	created, err := service.CreateUser(User{UserName: "bob"})
+	require.NoError(t, err)
+
+	_, err = service.PatchUser(created.ID, patchRequest(t, `[{"op":"move","path":"active","value":true}]`))
+	assertSCIMError(t, err, http.StatusBadRequest, "invalidSyntax")
+
+	_, err = service.PatchUser(created.ID, patchRequest(t, `[{"op":"replace","path":"active","value":"maybe"}]`))
+	assertSCIMError(t, err, http.StatusBadRequest, "invalidValue")
+
+	_, err = service.PatchUser("42", patchRequest(t, `[]`))
+	assertSCIMError(t, err, http.StatusNotFound, "")
+}
+
+func TestDeleteUserEndsSessionsAndRevokesKeys(t *testing.T) {
+	store := newMemoryStore()
+	service := NewService(store)
+	created, err := service.CreateUser(User{UserName: "carol"})
+	require.NoError(t, err)
+
+	require.NoError(t, service.DeleteUser(created.ID))
+	assert.Equal(t, []string{"carol"}, store.sessions)
+	assert.Equal(t, []string{"carol"}, store.apiKeys)
+
+	_, err = service.GetUser(created.ID)
+	assertSCIMError(t, err, http.StatusNotFound, "")
+	assertSCIMError(t, service.DeleteUser("not-a-number"), http.StatusNotFound, "")
+}
+
+func TestGroupMembership(t *testing.T) {
+	store := newMemoryStore()
+	service := NewService(store)
+	alice, err := service.CreateUser(User{UserName: "alice"})
+	require.NoError(t, err)
+	bob, err := service.CreateUser(User{UserName: "bob"})
+	require.NoError(t, err)
+
+	group, err := service.CreateGroup(Group{DisplayName: "payments", Members: []Ref{{Value: alice.ID}}})
+	require.NoError(t, err)
+	assert.Equal(t, []Ref{{Value: alice.ID, Display: "alice", Ref: "/scim/v2/Users/" + alice.ID}}, group.Members)
+
+	_, err = service.CreateGroup(Group{DisplayName: "ghosts", Members: []Ref{{Value: "99"}}})
+	assertSCIMError(t, err, http.StatusBadRequest, "invalidValue")
+
+	tests := []struct {
+		name        string
+		operations  string
+		wantMembers []string
+		wantName    string
+	}{
+		{name: "add member", operations: `[{"op":"add","path":"members","value":[{"value":"` + bob.ID + `"}]}]`, wantMembers: []string{"alice", "bob"}, wantName: "payments"},
+		{name: "adding twice keeps one membership", operations: `[{"op":"add","path":"members","value":[{"value":"` + bob.ID + `"}]}]`, wantMembers: []string{"alice", "bob"}, wantName: "payments"},
+		{name: "remove member by filter", operations: `[{"op":"remove","path":"members[value eq \"` + alice.ID + `\"]"}]`, wantMembers: []string{"bob"}, wantName: "payments"},
+		{name: "rename without path", operations: `[{"op":"replace","value":{"id":"` + group.ID + `","displayName":"payments-team"}}]`, wantMembers: []string{"bob"}, wantName: "payments-team"},
+		{name: "replace members", operations: `[{"op":"replace","path":"members","value":[{"value":"` + alice.ID + `"}]}]`, wantMembers: []string{"alice"}, wantName: "payments-team"},
+		{name: "remove all members", operations: `[{"op":"remove","path":"members"}]`, wantMembers: nil, wantName: "payments-team"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, err := service.PatchGroup(group.ID, patchRequest(t, tt.operations))
+			require.NoError(t, err)
+			var members []string
+			for _, member := range patched.Members {
+				members = append(members, member.Display)
+			}
+			assert.Equal(t, tt.wantMembers, members)
+			assert.Equal(t, tt.wantName, patched.DisplayName)
+		})
+	}
+
+	_, err = service.PatchGroup(group.ID, patchRequest(t, `[{"op":"replace","path":"owner","value":"x"}]`))
+	assertSCIMError(t, err, http.StatusBadRequest, "invalidPath")
+
+	require.NoError(t, service.DeleteGroup(group.ID))
+	assertSCIMError(t, service.DeleteGroup(group.ID), http.StatusNotFound, "")
+	assert.Empty(t, store.sessions, "membership changes apply at the next login")
+}
+
+func TestConfigAuthorize(t *testing.T) {
+	t.Setenv("TEST_SCIM_TOKEN", "s3cret")
+
+	tests := []struct {
+		name   string
+		config Config
+		header string
+		want   bool
+	}{
+		{name: "valid token", config: Config{Enabled: true, TokenEnv: "TEST_SCIM_TOKEN"}, header: "Bearer s3cret", want: true},
+		{name: "wrong token", config: Config{Enabled: true, TokenEnv: "TEST_SCIM_TOKEN"}, header: "Bearer guess"},
+		{name: "basic auth", config: Config{Enabled: true, TokenEnv: "TEST_SCIM_TOKEN"}, header: "Basic czNjcmV0"},
+		{name: "disabled", config: Config{TokenEnv: "TEST_SCIM_TOKEN"}, header: "Bearer s3cret"},
+		{name: "token variable unset", config: Config{Enabled: true, TokenEnv: "TEST_SCIM_UNSET"}, header: "Bearer "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/scim/v2/Users", nil)
+			r.Header.Set("Authorization", tt.header)
+			assert.Equal(t, tt.want, tt.config.Authorize(r))
+		})
+	}
+}
+
+func assertSCIMError(t *testing.T, err error, status int, scimType string) {
+	t.Helper()
+	var scimErr *Error
+	require.ErrorAs(t, err, &scimErr)
+	assert.Equal(t, status, scimErr.StatusCode())
+	assert.Equal(t, scimType, scimErr.ScimType)
+	assert.Equal(t, []string{SchemaError}, scimErr.Schemas)
+}
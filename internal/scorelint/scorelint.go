@@ -0,0 +1,256 @@
+// Package scorelint checks Score specs against organisation-specific rules that platform
+// admins define in admin-config.yaml, such as naming conventions, required labels,
+// forbidden images and allowed resource types.
+package scorelint
+
+import (
+	"fmt"
+	"innominatus/internal/types"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule types
+const (
+	RuleNaming          = "naming"
+	RuleRequiredLabels  = "required-labels"
+	RuleForbiddenImages = "forbidden-images"
+	RuleResourceTypes   = "resource-types"
+)
+
+// Naming rule targets
+const (
+	TargetApplication = "application"
+	TargetContainer   = "container"
+	TargetResource    = "resource"
+)
+
+// Finding severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Config is the scoreLint section of admin-config.yaml
+type Config struct {
+	DefaultProfile string             `yaml:"defaultProfile" json:"defaultProfile"` // Profile used when none is requested
+	Profiles       map[string]Profile `yaml:"profiles" json:"profiles"`
+}
+
+// Profile is a named set of lint rules, e.g. a stricter one for production
+type Profile struct {
+	Description string `yaml:"description" json:"description"`
+	Rules       []Rule `yaml:"rules" json:"rules"`
+}
+
+// Rule is a single lint rule. Which fields apply depends on the type.
+type Rule struct {
+	Name     string   `yaml:"name" json:"name"`
+	Type     string   `yaml:"type" json:"type"`                 // naming, required-labels, forbidden-images, resource-types
+	Severity string   `yaml:"severity" json:"severity"`         // error (default) or warning
+	Message  string   `yaml:"message" json:"message,omitempty"` // Replaces the generated message
+	Target   string   `yaml:"target" json:"target,omitempty"`   // naming: application, container or resource
+	Pattern  string   `yaml:"pattern" json:"pattern,omitempty"` // naming: regular expression names must match
+	Labels   []string `yaml:"labels" json:"labels,omitempty"`   // required-labels: label keys
+	Images   []string `yaml:"images" json:"images,omitempty"`   // forbidden-images: patterns, * matches any characters
+	Allowed  []string `yaml:"allowed" json:"allowed,omitempty"` // resource-types: allowed resource types
+}
+
+// Finding is a rule violation in a Score spec
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Path     string `json:"path"` // Location in the spec, e.g. containers.web.image
+	Message  string `json:"message"`
+}
+
+// Report is the result of linting a Score spec with a profile
+type Report struct {
+	Profile  string    `json:"profile"`
+	Findings []Finding `json:"findings"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+}
+
+// Passed reports whether the spec has no error findings
+func (r *Report) Passed() bool {
+	return r.Errors == 0
+}
+
+// Validate checks that every profile's rules are well-formed
+func (c Config) Validate() error {
+	if c.DefaultProfile != "" {
+		if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+			return fmt.Errorf("scoreLint.defaultProfile '%s' is not a defined profile", c.DefaultProfile)
+		}
+	}
+	for name, profile := range c.Profiles {
+		for i, rule := range profile.Rules {
+			if err := rule.validate(); err != nil {
+				return fmt.Errorf("scoreLint profile '%s' rule %d (%s): %w", name, i+1, rule.id(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r Rule) validate() error {
+	switch r.Severity {
+	case "", SeverityError, SeverityWarning:
+	default:
+		return fmt.Errorf("severity must be '%s' or '%s'", SeverityError, SeverityWarning)
+	}
+
+	switch r.Type {
+	case RuleNaming:
+		switch r.Target {
+		case TargetApplication, TargetContainer, TargetResource:
+		default:
+			return fmt.Errorf("target must be %s, %s or %s", TargetApplication, TargetContainer, TargetResource)
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil || r.Pattern == "" {
+			return fmt.Errorf("pattern must be a regular expression")
+		}
+	case RuleRequiredLabels:
+		if len(r.Labels) == 0 {
+			return fmt.Errorf("labels must not be empty")
+		}
+	case RuleForbiddenImages:
+		if len(r.Images) == 0 {
+			return fmt.Errorf("images must not be empty")
+		}
+	case RuleResourceTypes:
+		if len(r.Allowed) == 0 {
+			return fmt.Errorf("allowed must not be empty")
+		}
+	default:
+		return fmt.Errorf("unknown rule type '%s'", r.Type)
+	}
+	return nil
+}
+
+// id returns the rule name, or its type when it has none
+func (r Rule) id() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Type
+}
+
+// Lint checks a spec against the named profile, or the default profile when name is
+// empty. It returns nil when no profile applies, i.e. nothing is configured.
+func (c Config) Lint(spec *types.ScoreSpec, name string) (*Report, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return nil, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown lint profile '%s'", name)
+	}
+
+	report := &Report{Profile: name, Findings: []Finding{}}
+	for _, rule := range profile.Rules {
+		for _, finding := range rule.check(spec) {
+			if finding.Severity == SeverityWarning {
+				report.Warnings++
+			} else {
+				report.Errors++
+			}
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+	return report, nil
+}
+
+// check returns the findings of a single rule
+func (r Rule) check(spec *types.ScoreSpec) []Finding {
+	var findings []Finding
+	add := func(path, message string) {
+		severity := r.Severity
+		if severity == "" {
+			severity = SeverityError
+		}
+		if r.Message != "" {
+			message = r.Message
+		}
+		findings = append(findings, Finding{Rule: r.id(), Severity: severity, Path: path, Message: message})
+	}
+
+	switch r.Type {
+	case RuleNaming:
+		pattern, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			add("", fmt.Sprintf("invalid pattern: %v", err))
+			break
+		}
+		switch r.Target {
+		case TargetApplication:
+			if !pattern.MatchString(spec.Metadata.Name) {
+				add("metadata.name", fmt.Sprintf("application name '%s' does not match %s", spec.Metadata.Name, r.Pattern))
+			}
+		case TargetContainer:
+			for _, name := range sortedKeys(spec.Containers) {
+				if !pattern.MatchString(name) {
+					add("containers."+name, fmt.Sprintf("container name '%s' does not match %s", name, r.Pattern))
+				}
+			}
+		case TargetResource:
+			for _, name := range sortedKeys(spec.Resources) {
+				if !pattern.MatchString(name) {
+					add("resources."+name, fmt.Sprintf("resource name '%s' does not match %s", name, r.Pattern))
+				}
+			}
+		}
+
+	case RuleRequiredLabels:
+		for _, label := range r.Labels {
+			if strings.TrimSpace(spec.Metadata.Labels[label]) == "" {
+				add("metadata.labels."+label, fmt.Sprintf("required label '%s' is missing", label))
+			}
+		}
+
+	case RuleForbiddenImages:
+		for _, name := range sortedKeys(spec.Containers) {
+			image := spec.Containers[name].Image
+			for _, forbidden := range r.Images {
+				if matchImage(forbidden, image) {
+					add("containers."+name+".image", fmt.Sprintf("image '%s' is forbidden (%s)", image, forbidden))
+					break
+				}
+			}
+		}
+
+	case RuleResourceTypes:
+		allowed := make(map[string]bool, len(r.Allowed))
+		for _, t := range r.Allowed {
+			allowed[t] = true
+		}
+		for _, name := range sortedKeys(spec.Resources) {
+			if resourceType := spec.Resources[name].Type; !allowed[resourceType] {
+				add("resources."+name+".type", fmt.Sprintf("resource type '%s' is not allowed (allowed: %s)", resourceType, strings.Join(r.Allowed, ", ")))
+			}
+		}
+	}
+
+	return findings
+}
+
+// matchImage matches an image reference against a pattern in which * matches any characters
+func matchImage(pattern, image string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	matched, _ := regexp.MatchString(expr, image)
+	return matched
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
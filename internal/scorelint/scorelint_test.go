@@ -0,0 +1,163 @@
+package scorelint
+
+import (
+	"innominatus/internal/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = Config{
+	DefaultProfile: "standard",
+	Profiles: map[string]Profile{
+		"standard": {Rules: []Rule{
+			{Name: "app-name", Type: RuleNaming, Target: TargetApplication, Pattern: `^[a-z][a-z0-9-]{2,30}$`},
+			{Name: "container-name", Type: RuleNaming, Target: TargetContainer, Pattern: `^[a-z]+$`, Severity: SeverityWarning},
+			{Name: "labels", Type: RuleRequiredLabels, Labels: []string{"team", "cost-center"}},
+			{Name: "no-latest", Type: RuleForbiddenImages, Images: []string{"*:latest", "docker.io/*"}, Message: "pin images to a version from the internal registry"},
+			{Name: "resource-types", Type: RuleResourceTypes, Allowed: []string{"postgres", "route"}},
+		}},
+		"relaxed": {Rules: []Rule{
+			{Type: RuleForbiddenImages, Images: []string{"*:latest"}, Severity: SeverityWarning},
+		}},
+	},
+}
+
+func compliantSpec() *types.ScoreSpec {
+	return &types.ScoreSpec{
+		Metadata: types.Metadata{
+			Name:   "shop",
+			Labels: map[string]string{"team": "ecommerce", "cost-center": "cc-42"},
+		},
+		Containers: map[string]types.Container{"web": {Image: "registry.internal/shop/web:1.4.0"}},
+		Resources:  map[string]types.Resource{"db": {Type: "postgres"}},
+	}
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name         string
+		profile      string
+		modify       func(spec *types.ScoreSpec)
+		wantFindings []Finding
+		wantPassed   bool
+	}{
+		{
+			name:         "compliant spec",
+			modify:       func(spec *types.ScoreSpec) {},
+			wantFindings: []Finding{},
+			wantPassed:   true,
+		},
+		{
+			name: "naming conventions",
+			modify: func(spec *types.ScoreSpec) {
+				spec.Metadata.Name = "Shop_App"
+				spec.Containers = map[string]types.Container{"web-1": {Image: "registry.internal/web:1"}}
+			},
+			wantFindings: []Finding{
+				{Rule: "app-name", Severity: SeverityError, Path: "metadata.name", Message: "application name 'Shop_App' does not match ^[a-z][a-z0-9-]{2,30}$"},
+				{Rule: "container-name", Severity: SeverityWarning, Path: "containers.web-1", Message: "container name 'web-1' does not match ^[a-z]+$"},
+			},
+		},
+		{
+			name:   "missing labels",
+			modify: func(spec *types.ScoreSpec) { spec.Metadata.Labels = map[string]string{"team": "ecommerce"} },
+			wantFindings: []Finding{
+				{Rule: "labels", Severity: SeverityError, Path: "metadata.labels.cost-center", Message: "required label 'cost-center' is missing"},
+			},
+		},
+		{
+			name: "forbidden images",
+			modify: func(spec *types.ScoreSpec) {
+				spec.Containers = map[string]types.Container{
+					"api": {Image: "docker.io/library/nginx:1.25"},
+					"web": {Image: "registry.internal/web:latest"},
+				}
+			},
+			wantFindings: []Finding{
+				{Rule: "no-latest", Severity: SeverityError, Path: "containers.api.image", Message: "pin images to a version from the internal registry"},
+				{Rule: "no-latest", Severity: SeverityError, Path: "containers.web.image", Message: "pin images to a version from the internal registry"},
+			},
+		},
+		{
+			name:   "resource type not allowed",
+			modify: func(spec *types.ScoreSpec) { spec.Resources["cache"] = types.Resource{Type: "redis"} },
+			wantFindings: []Finding{
+				{Rule: "resource-types", Severity: SeverityError, Path: "resources.cache.type", Message: "resource type 'redis' is not allowed (allowed: postgres, route)"},
+			},
+		},
+		{
+			name:    "other profile",
+			profile: "relaxed",
+			modify: func(spec *types.ScoreSpec) {
+				spec.Metadata.Labels = nil
+				spec.Containers = map[string]types.Container{"web": {Image: "nginx:latest"}}
+			},
+			wantFindings: []Finding{
+				{Rule: RuleForbiddenImages, Severity: SeverityWarning, Path: "containers.web.image", Message: "image 'nginx:latest' is forbidden (*:latest)"},
+			},
+			wantPassed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := compliantSpec()
+			tt.modify(spec)
+
+			report, err := testConfig.Lint(spec, tt.profile)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFindings, report.Findings)
+			assert.Equal(t, tt.wantPassed, report.Passed())
+		})
+	}
+}
+
+func TestLintWithoutProfile(t *testing.T) {
+	report, err := Config{}.Lint(compliantSpec(), "")
+	require.NoError(t, err)
+	assert.Nil(t, report)
+
+	_, err = testConfig.Lint(compliantSpec(), "strict")
+	assert.EqualError(t, err, "unknown lint profile 'strict'")
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, testConfig.Validate())
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name:    "unknown default profile",
+			config:  Config{DefaultProfile: "strict"},
+			wantErr: "scoreLint.defaultProfile 'strict' is not a defined profile",
+		},
+		{
+			name:    "invalid pattern",
+			config:  Config{Profiles: map[string]Profile{"p": {Rules: []Rule{{Name: "n", Type: RuleNaming, Target: TargetApplication, Pattern: "("}}}}},
+			wantErr: "rule 1 (n): pattern must be a regular expression",
+		},
+		{
+			name:    "unknown rule type",
+			config:  Config{Profiles: map[string]Profile{"p": {Rules: []Rule{{Type: "max-replicas"}}}}},
+			wantErr: "unknown rule type 'max-replicas'",
+		},
+		{
+			name:    "invalid severity",
+			config:  Config{Profiles: map[string]Profile{"p": {Rules: []Rule{{Type: RuleResourceTypes, Allowed: []string{"postgres"}, Severity: "fatal"}}}}},
+			wantErr: "severity must be 'error' or 'warning'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
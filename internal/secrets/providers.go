@@ -0,0 +1,161 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/vault"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// DefaultEnvPrefix starts the names of the environment variables the env provider reads.
+// Only prefixed variables can be referenced, so workflows cannot read arbitrary server
+// environment variables such as VAULT_TOKEN.
+const DefaultEnvPrefix = "SECRET_"
+
+// EnvProvider reads secrets from the server environment. ${secrets.platform/minio.password}
+// is read from SECRET_PLATFORM_MINIO_PASSWORD.
+type EnvProvider struct {
+	prefix string
+	lookup func(string) (string, bool)
+}
+
+// NewEnvProvider creates an environment provider
+func NewEnvProvider(config EnvConfig) *EnvProvider {
+	prefix := config.Prefix
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	return &EnvProvider{prefix: prefix, lookup: os.LookupEnv}
+}
+
+// Name returns env
+func (p *EnvProvider) Name() string {
+	return ProviderEnv
+}
+
+// Variable returns the environment variable holding key of the secret at path
+func (p *EnvProvider) Variable(path, key string) string {
+	name := strings.ToUpper(path + "_" + key)
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+	return p.prefix + name
+}
+
+// Get returns the value of the environment variable of path and key
+func (p *EnvProvider) Get(ctx context.Context, path, key string) (string, error) {
+	name := p.Variable(path, key)
+	value, ok := p.lookup(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// VaultProvider reads secrets from a Vault KV v2 engine. ${secrets.platform/minio.password}
+// is field password of the secret <mount>/data/platform/minio.
+type VaultProvider struct {
+	address  string
+	tokenEnv string
+	mount    string
+}
+
+// NewVaultProvider creates a Vault provider
+func NewVaultProvider(config VaultConfig) *VaultProvider {
+	provider := &VaultProvider{address: config.Address, tokenEnv: config.TokenEnv, mount: strings.Trim(config.Mount, "/")}
+	if provider.address == "" {
+		provider.address = os.Getenv("VAULT_ADDR")
+	}
+	if provider.address == "" {
+		provider.address = "http://vault.vault.svc.cluster.local:8200"
+	}
+	if provider.tokenEnv == "" {
+		provider.tokenEnv = "VAULT_TOKEN"
+	}
+	if provider.mount == "" {
+		provider.mount = "secret"
+	}
+	return provider
+}
+
+// Name returns vault
+func (p *VaultProvider) Name() string {
+	return ProviderVault
+}
+
+// Get returns field key of the secret at path. The token is read from the environment on
+// every call, so a rotated token is picked up without a restart.
+func (p *VaultProvider) Get(ctx context.Context, path, key string) (string, error) {
+	client := vault.NewClient(p.address, os.Getenv(p.tokenEnv))
+	data, err := client.ReadKV(p.mount + "/data/" + strings.Trim(path, "/"))
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[key]
+	if !ok || value == nil {
+		return "", fmt.Errorf("key '%s' not found in vault secret '%s'", key, path)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+// KubernetesProvider reads Kubernetes Secrets via kubectl. The path is <namespace>/<name>,
+// or just <name> for a Secret in the configured namespace.
+type KubernetesProvider struct {
+	namespace string
+}
+
+// NewKubernetesProvider creates a Kubernetes provider
+func NewKubernetesProvider(config KubernetesConfig) *KubernetesProvider {
+	namespace := config.Namespace
+	if namespace == "" {
+		namespace = os.Getenv("POD_NAMESPACE")
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	return &KubernetesProvider{namespace: namespace}
+}
+
+// Name returns kubernetes
+func (p *KubernetesProvider) Name() string {
+	return ProviderKubernetes
+}
+
+// Get returns the decoded value of data[key] of the Secret at path
+func (p *KubernetesProvider) Get(ctx context.Context, path, key string) (string, error) {
+	namespace, name := p.namespace, path
+	if parts := strings.Split(path, "/"); len(parts) == 2 {
+		namespace, name = parts[0], parts[1]
+	} else if len(parts) > 2 {
+		return "", fmt.Errorf("kubernetes secret path '%s' must be <name> or <namespace>/<name>", path)
+	}
+
+	cmd := exec.CommandContext(ctx, "kubectl", "get", "secret", name, "-n", namespace, "-o", "json") // #nosec G204 - kubectl with arguments from a secret reference
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s/%s: %w", namespace, name, err)
+	}
+
+	var secret struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(output, &secret); err != nil {
+		return "", fmt.Errorf("failed to parse secret %s/%s: %w", namespace, name, err)
+	}
+	encoded, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("key '%s' not found in secret %s/%s", key, namespace, name)
+	}
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode key '%s' of secret %s/%s: %w", key, namespace, name, err)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,303 @@
+// Package secrets resolves ${secrets.<path>.<key>} references against a secret store, so
+// credentials can be kept out of admin-config.yaml, workflow definitions and generated
+// Terraform. The store is Vault (KV v2), Kubernetes Secrets or the server environment,
+// selected in the secrets section of admin-config.yaml.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long resolved secrets are cached when the config sets no ttl
+const DefaultTTL = 5 * time.Minute
+
+// Providers
+const (
+	ProviderEnv        = "env"
+	ProviderVault      = "vault"
+	ProviderKubernetes = "kubernetes"
+)
+
+// SecretProvider reads a single value from a secret store
+type SecretProvider interface {
+	// Name returns the provider name, e.g. vault
+	Name() string
+	// Get returns the value of key in the secret at path
+	Get(ctx context.Context, path, key string) (string, error)
+}
+
+// referencePattern matches ${secrets.<path>.<key>}. The path may contain slashes; the key
+// is the part after the last dot.
+var referencePattern = regexp.MustCompile(`\$\{secrets\.([A-Za-z0-9_\-/]+)\.([A-Za-z0-9_\-]+)\}`)
+
+// Reference is a ${secrets.<path>.<key>} reference
+type Reference struct {
+	Path string
+	Key  string
+}
+
+func (r Reference) String() string {
+	return fmt.Sprintf("${secrets.%s.%s}", r.Path, r.Key)
+}
+
+// ParseReference parses the part of a reference after "secrets.", e.g. platform/minio.password
+func ParseReference(name string) (Reference, bool) {
+	match := referencePattern.FindStringSubmatch("${secrets." + name + "}")
+	if match == nil || match[0] != "${secrets."+name+"}" {
+		return Reference{}, false
+	}
+	return Reference{Path: match[1], Key: match[2]}, true
+}
+
+// References returns the secret references in s
+func References(s string) []Reference {
+	var refs []Reference
+	for _, match := range referencePattern.FindAllStringSubmatch(s, -1) {
+		refs = append(refs, Reference{Path: match[1], Key: match[2]})
+	}
+	return refs
+}
+
+// HasReference reports whether s references a secret
+func HasReference(s string) bool {
+	return referencePattern.MatchString(s)
+}
+
+// Config is the secrets section of admin-config.yaml. It holds no secrets itself, only
+// where to find them.
+type Config struct {
+	Provider   string           `yaml:"provider" json:"provider"` // env (default), vault or kubernetes
+	TTL        string           `yaml:"ttl" json:"ttl"`           // Cache duration (default 5m, 0s disables caching)
+	Vault      VaultConfig      `yaml:"vault" json:"vault"`
+	Kubernetes KubernetesConfig `yaml:"kubernetes" json:"kubernetes"`
+	Env        EnvConfig        `yaml:"env" json:"env"`
+	Workflows  WorkflowAccess   `yaml:"workflows" json:"workflows"` // Paths workflows may reference
+}
+
+// VaultConfig locates the Vault KV v2 engine
+type VaultConfig struct {
+	Address  string `yaml:"address" json:"address"`   // Default VAULT_ADDR, then the in-cluster service
+	TokenEnv string `yaml:"tokenEnv" json:"tokenEnv"` // Environment variable holding the token (default VAULT_TOKEN)
+	Mount    string `yaml:"mount" json:"mount"`       // KV v2 mount (default secret)
+}
+
+// KubernetesConfig locates Kubernetes Secrets
+type KubernetesConfig struct {
+	Namespace string `yaml:"namespace" json:"namespace"` // Namespace of paths without one (default POD_NAMESPACE, then default)
+}
+
+// EnvConfig maps references to server environment variables
+type EnvConfig struct {
+	Prefix string `yaml:"prefix" json:"prefix"` // Prepended to the variable name (default SECRET_)
+}
+
+// DefaultWorkflowPaths are the paths workflows may reference when the config sets none
+var DefaultWorkflowPaths = []string{"applications/{application}", "teams/{team}"}
+
+// WorkflowAccess limits the secrets workflows can reference. Workflows come from Score
+// specs as well as from the admin, and their references are resolved with the server's
+// credentials, so a run may only read secrets at or below one of the paths. {application}
+// and {team} are replaced by the application of the run and its team; paths without them
+// are shared by all workflows.
+type WorkflowAccess struct {
+	Paths []string `yaml:"paths" json:"paths"` // Default applications/{application} and teams/{team}
+}
+
+// Scope is the application and team a workflow runs for
+type Scope struct {
+	Application string
+	Team        string
+}
+
+func (a WorkflowAccess) paths() []string {
+	if len(a.Paths) == 0 {
+		return DefaultWorkflowPaths
+	}
+	return a.Paths
+}
+
+// Validate checks that every path is a secret path with known placeholders
+func (a WorkflowAccess) Validate() error {
+	for _, path := range a.Paths {
+		expanded := strings.NewReplacer("{application}", "x", "{team}", "x").Replace(path)
+		if _, ok := ParseReference(strings.Trim(expanded, "/") + ".key"); !ok {
+			return fmt.Errorf("invalid secrets.workflows.paths entry '%s'", path)
+		}
+	}
+	return nil
+}
+
+// Check returns an error unless a workflow running in scope may read ref. A path with a
+// placeholder does not apply when the scope lacks its value.
+func (a WorkflowAccess) Check(ref Reference, scope Scope) error {
+	path := strings.Trim(ref.Path, "/")
+	for _, allowed := range a.paths() {
+		if (strings.Contains(allowed, "{application}") && scope.Application == "") ||
+			(strings.Contains(allowed, "{team}") && scope.Team == "") {
+			continue
+		}
+		allowed = strings.Trim(strings.NewReplacer("{application}", scope.Application, "{team}", scope.Team).Replace(allowed), "/")
+		if path == allowed || strings.HasPrefix(path, allowed+"/") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is outside the secret paths of application '%s' (secrets.workflows.paths)", ref, scope.Application)
+}
+
+// Validate checks the provider, the cache duration and the workflow paths
+func (c Config) Validate() error {
+	switch c.provider() {
+	case ProviderEnv, ProviderVault, ProviderKubernetes:
+	default:
+		return fmt.Errorf("secrets.provider '%s' is not supported (use env, vault or kubernetes)", c.Provider)
+	}
+	if _, err := c.ttl(); err != nil {
+		return err
+	}
+	return c.Workflows.Validate()
+}
+
+func (c Config) provider() string {
+	if c.Provider == "" {
+		return ProviderEnv
+	}
+	return strings.ToLower(c.Provider)
+}
+
+func (c Config) ttl() (time.Duration, error) {
+	if c.TTL == "" {
+		return DefaultTTL, nil
+	}
+	ttl, err := time.ParseDuration(c.TTL)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid secrets.ttl '%s'", c.TTL)
+	}
+	return ttl, nil
+}
+
+// NewProvider creates the provider selected by a config
+func NewProvider(config Config) (SecretProvider, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	switch config.provider() {
+	case ProviderVault:
+		return NewVaultProvider(config.Vault), nil
+	case ProviderKubernetes:
+		return NewKubernetesProvider(config.Kubernetes), nil
+	default:
+		return NewEnvProvider(config.Env), nil
+	}
+}
+
+type cacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Resolver resolves secret references through a provider, caching values for a TTL
+type Resolver struct {
+	provider SecretProvider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	cache map[Reference]cacheEntry
+}
+
+// NewResolver creates a resolver. A ttl of zero disables caching.
+func NewResolver(provider SecretProvider, ttl time.Duration) *Resolver {
+	return &Resolver{
+		provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+		cache:    make(map[Reference]cacheEntry),
+	}
+}
+
+// storeKey identifies the secret store of a config
+type storeKey struct {
+	provider   string
+	ttl        string
+	vault      VaultConfig
+	kubernetes KubernetesConfig
+	env        EnvConfig
+}
+
+var (
+	resolversMu sync.Mutex
+	resolvers   = make(map[storeKey]*Resolver)
+)
+
+// ResolverFor returns the resolver of a config. Resolvers are shared per store, so the
+// cache survives reloads of admin-config.yaml that leave the store unchanged.
+func ResolverFor(config Config) (*Resolver, error) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+
+	key := storeKey{provider: config.Provider, ttl: config.TTL, vault: config.Vault, kubernetes: config.Kubernetes, env: config.Env}
+	if resolver, ok := resolvers[key]; ok {
+		return resolver, nil
+	}
+	provider, err := NewProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	ttl, _ := config.ttl()
+	resolver := NewResolver(provider, ttl)
+	resolvers[key] = resolver
+	return resolver, nil
+}
+
+// Provider returns the name of the resolver's provider
+func (r *Resolver) Provider() string {
+	return r.provider.Name()
+}
+
+// Resolve returns the value of a reference
+func (r *Resolver) Resolve(ctx context.Context, ref Reference) (string, error) {
+	r.mu.Lock()
+	entry, cached := r.cache[ref]
+	r.mu.Unlock()
+	if cached && r.now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	value, err := r.provider.Get(ctx, ref.Path, ref.Key)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s from %s: %w", ref, r.provider.Name(), err)
+	}
+	if r.ttl > 0 {
+		r.mu.Lock()
+		r.cache[ref] = cacheEntry{value: value, expiresAt: r.now().Add(r.ttl)}
+		r.mu.Unlock()
+	}
+	return value, nil
+}
+
+// Interpolate replaces every secret reference in s with its value. It fails on the first
+// reference that cannot be resolved.
+func (r *Resolver) Interpolate(ctx context.Context, s string) (string, error) {
+	var resolveErr error
+	result := referencePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if resolveErr != nil {
+			return match
+		}
+		ref := References(match)[0]
+		value, err := r.Resolve(ctx, ref)
+		if err != nil {
+			resolveErr = err
+			return match
+		}
+		return value
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return result, nil
+}
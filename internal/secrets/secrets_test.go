@@ -0,0 +1,193 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider serves secrets from a map and counts reads
+type fakeProvider struct {
+	values map[string]string // By path.key
+	reads  int
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) Get(ctx context.Context, path, key string) (string, error) {
+	p.reads++
+	value, ok := p.values[path+"."+key]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return value, nil
+}
+
+func TestReferences(t *testing.T) {
+	refs := References("user=${secrets.platform/minio.user} password=${secrets.platform/minio.password} ${workflow.x}")
+	assert.Equal(t, []Reference{{Path: "platform/minio", Key: "user"}, {Path: "platform/minio", Key: "password"}}, refs)
+	assert.Equal(t, "${secrets.platform/minio.user}", refs[0].String())
+	assert.True(t, HasReference("${secrets.gitea.password}"))
+	assert.False(t, HasReference("${secrets.gitea}"))
+
+	ref, ok := ParseReference("a/b.c.key")
+	assert.False(t, ok, "dots are not allowed in paths")
+	ref, ok = ParseReference("gitea.password")
+	assert.True(t, ok)
+	assert.Equal(t, Reference{Path: "gitea", Key: "password"}, ref)
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{"default", Config{}, ""},
+		{"vault", Config{Provider: "vault", TTL: "1m"}, ""},
+		{"no caching", Config{Provider: "kubernetes", TTL: "0s"}, ""},
+		{"unknown provider", Config{Provider: "aws"}, "secrets.provider 'aws' is not supported"},
+		{"invalid ttl", Config{TTL: "soon"}, "invalid secrets.ttl 'soon'"},
+		{"workflow paths", Config{Workflows: WorkflowAccess{Paths: []string{"teams/{team}", "team-{team}", "platform/minio"}}}, ""},
+		{"invalid workflow path", Config{Workflows: WorkflowAccess{Paths: []string{"platform.minio"}}}, "invalid secrets.workflows.paths entry 'platform.minio'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkflowAccessCheck(t *testing.T) {
+	scope := Scope{Application: "shop", Team: "engineering"}
+
+	tests := []struct {
+		name    string
+		access  WorkflowAccess
+		path    string
+		scope   Scope
+		allowed bool
+	}{
+		{name: "own application", path: "applications/shop/db", scope: scope, allowed: true},
+		{name: "own team", path: "teams/engineering", scope: scope, allowed: true},
+		{name: "other team", path: "teams/payments/db", scope: scope},
+		{name: "application with the same prefix", path: "applications/shopping/db", scope: scope},
+		{name: "platform secret", path: "platform/minio", scope: scope},
+		{name: "shared path", access: WorkflowAccess{Paths: []string{"platform/minio"}}, path: "platform/minio", scope: scope, allowed: true},
+		{name: "kubernetes namespace of the team", access: WorkflowAccess{Paths: []string{"team-{team}"}}, path: "team-engineering/db", scope: scope, allowed: true},
+		{name: "kubernetes namespace of another team", access: WorkflowAccess{Paths: []string{"team-{team}"}}, path: "team-payments/db", scope: scope},
+		{name: "kubernetes secret without namespace", access: WorkflowAccess{Paths: []string{"team-{team}"}}, path: "db", scope: scope},
+		{name: "run without team", path: "teams//db", scope: Scope{Application: "shop"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.access.Check(Reference{Path: tt.path, Key: "password"}, tt.scope)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "is outside the secret paths of application")
+			}
+		})
+	}
+}
+
+func TestResolverCaches(t *testing.T) {
+	provider := &fakeProvider{values: map[string]string{"gitea.password": "s3cret"}}
+	resolver := NewResolver(provider, time.Minute)
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	ref := Reference{Path: "gitea", Key: "password"}
+	for i := 0; i < 2; i++ {
+		value, err := resolver.Resolve(context.Background(), ref)
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", value)
+	}
+	assert.Equal(t, 1, provider.reads)
+
+	now = now.Add(2 * time.Minute)
+	_, err := resolver.Resolve(context.Background(), ref)
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.reads, "expired values are read again")
+
+	_, err = resolver.Resolve(context.Background(), Reference{Path: "gitea", Key: "token"})
+	assert.ErrorContains(t, err, "failed to resolve ${secrets.gitea.token} from fake")
+}
+
+func TestResolverInterpolate(t *testing.T) {
+	provider := &fakeProvider{values: map[string]string{"db.user": "app", "db.password": "pw"}}
+	resolver := NewResolver(provider, 0)
+
+	value, err := resolver.Interpolate(context.Background(), "postgres://${secrets.db.user}:${secrets.db.password}@db/${workflow.name}")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://app:pw@db/${workflow.name}", value)
+
+	_, err = resolver.Interpolate(context.Background(), "${secrets.db.host}")
+	assert.ErrorContains(t, err, "${secrets.db.host}")
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("SECRET_PLATFORM_MINIO_ROOT_PASSWORD", "minio-pw")
+	provider := NewEnvProvider(EnvConfig{})
+
+	assert.Equal(t, "SECRET_PLATFORM_MINIO_ROOT_PASSWORD", provider.Variable("platform/minio-root", "password"))
+	value, err := provider.Get(context.Background(), "platform/minio-root", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "minio-pw", value)
+
+	_, err = provider.Get(context.Background(), "platform/minio-root", "user")
+	assert.ErrorContains(t, err, "SECRET_PLATFORM_MINIO_ROOT_USER is not set")
+
+	assert.Equal(t, "APP_DB_PASSWORD", NewEnvProvider(EnvConfig{Prefix: "APP_"}).Variable("db", "password"))
+}
+
+func TestVaultProvider(t *testing.T) {
+	t.Setenv("TEST_VAULT_TOKEN", "root")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/data/platform/minio" || r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"data": map[string]interface{}{"password": "minio-pw"}},
+		})
+	}))
+	defer server.Close()
+
+	provider := NewVaultProvider(VaultConfig{Address: server.URL, TokenEnv: "TEST_VAULT_TOKEN", Mount: "kv"})
+	value, err := provider.Get(context.Background(), "platform/minio", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "minio-pw", value)
+
+	_, err = provider.Get(context.Background(), "platform/minio", "user")
+	assert.ErrorContains(t, err, "key 'user' not found")
+	_, err = provider.Get(context.Background(), "platform/gitea", "password")
+	assert.ErrorContains(t, err, "status 404")
+}
+
+func TestResolverFor(t *testing.T) {
+	first, err := ResolverFor(Config{Provider: "env", Env: EnvConfig{Prefix: "X_"}})
+	require.NoError(t, err)
+	second, err := ResolverFor(Config{Provider: "env", Env: EnvConfig{Prefix: "X_"}})
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, ProviderEnv, first.Provider())
+
+	_, err = ResolverFor(Config{Provider: "aws"})
+	assert.Error(t, err)
+}
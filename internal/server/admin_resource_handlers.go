@@ -0,0 +1,144 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// forceResourceStateRequest is the body of POST /api/admin/resources/{id}/state
+type forceResourceStateRequest struct {
+	State  string `json:"state"`
+	Reason string `json:"reason"`
+}
+
+// HandleAdminResources repairs stuck resources (admin only).
+//
+// POST /api/admin/resources/{id}/state   force the state of a resource, with a reason
+func (s *Server) HandleAdminResources(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/resources"), "/"), "/")
+	if len(parts) != 2 || parts[1] != "state" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	resourceID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid resource ID '%s'", parts[0]), http.StatusBadRequest)
+		return
+	}
+
+	var req forceResourceStateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	state := database.ResourceLifecycleState(req.State)
+	if !database.IsResourceLifecycleState(state) {
+		http.Error(w, fmt.Sprintf("Unknown resource state '%s'", req.State), http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		http.Error(w, "A reason is required to force a resource state", http.StatusBadRequest)
+		return
+	}
+
+	if s.resourceManager == nil || s.db == nil {
+		http.Error(w, "Resource management requires a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	resource, err := s.resourceManager.GetResource(resourceID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Resource %d not found", resourceID), http.StatusNotFound)
+		return
+	}
+	if resource.State == state {
+		http.Error(w, fmt.Sprintf("Resource %d is already %s", resourceID, state), http.StatusConflict)
+		return
+	}
+
+	username := "admin"
+	if user := s.getUserFromContext(r); user != nil {
+		username = user.Username
+	}
+	previous := resource.State
+	updated, err := s.resourceManager.ForceResourceState(resourceID, state, req.Reason, username)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to force resource state: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	logging.FromContext(r.Context(), "server").WarnWithFields("Resource state forced by admin", map[string]interface{}{
+		"resource_id": resourceID,
+		"app_name":    resource.ApplicationName,
+		"resource":    resource.ResourceName,
+		"from_state":  string(previous),
+		"to_state":    string(state),
+		"forced_by":   username,
+		"reason":      req.Reason,
+	})
+	writeAdminResourcesJSON(w, http.StatusOK, map[string]interface{}{
+		"resource":       updated,
+		"previous_state": previous,
+		"forced_by":      username,
+		"reason":         req.Reason,
+	})
+}
+
+// HandleAuditLog lists administrative actions, newest first (admin only).
+//
+// GET /api/admin/audit?actor=&action=&target_type=&target_id=&limit=
+func (s *Server) HandleAuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.db == nil {
+		http.Error(w, "The audit log requires a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := database.AuditFilter{
+		Actor:      query.Get("actor"),
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		TargetID:   query.Get("target_id"),
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > 1000 {
+			http.Error(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = n
+	}
+
+	entries, err := s.db.ListAuditEntries(filter)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read audit log: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeAdminResourcesJSON(w, http.StatusOK, map[string]interface{}{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// writeAdminResourcesJSON writes body as JSON with status
+func writeAdminResourcesJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"innominatus/internal/admin"
+	"innominatus/internal/alerting"
+	"innominatus/internal/database"
+	"innominatus/internal/events"
+	"innominatus/internal/logging"
+	"innominatus/internal/workflow"
+)
+
+// alertingConfig returns the alerting section of admin-config.yaml
+func (s *Server) alertingConfig() alerting.Config {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return alerting.Config{}
+	}
+	return adminConfig.Alerting
+}
+
+// withDeploymentEnvironment tags the workflow runs started with ctx with their target
+// environment, so failures in production open incidents
+func withDeploymentEnvironment(ctx context.Context, environment string) context.Context {
+	if environment == "" {
+		return ctx
+	}
+	return workflow.WithDeploymentEnvironment(ctx, environment)
+}
+
+// StartAlerter opens incidents for failed production workflows on bus and, with a
+// database, periodically checks the resources of production applications
+func (s *Server) StartAlerter(ctx context.Context, bus events.EventBus) {
+	alerter := alerting.NewAlerter(s.alertingConfig)
+	alerter.Start(bus)
+	if s.db == nil {
+		return
+	}
+
+	logger := logging.NewStructuredLogger("server")
+	_, interval, err := s.alertingConfig().Durations()
+	if err != nil {
+		logger.Warnf("Resource health alerting disabled: %v", err)
+		return
+	}
+
+	go func() {
+		ticker := s.Clock().NewTicker("alerting-health-check", interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				if !s.alertingConfig().Enabled() {
+					continue
+				}
+				resources, err := s.productionResourceHealth()
+				if err == nil {
+					err = alerter.CheckResources(ctx, resources, s.Clock().Now())
+				}
+				if err != nil {
+					logger.Warnf("Resource health alerting failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// productionResourceHealth returns the health of the resources of every application whose
+// Score spec targets a production environment
+func (s *Server) productionResourceHealth() ([]alerting.ResourceHealth, error) {
+	repo := s.GetResourceRepository()
+	if repo == nil {
+		return nil, nil
+	}
+	config := s.alertingConfig()
+
+	apps, err := s.db.ListApplications()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []alerting.ResourceHealth
+	for _, app := range apps {
+		if app.ScoreSpec == nil || app.ScoreSpec.Environment == nil || !config.Production(app.ScoreSpec.Environment.Type) {
+			continue
+		}
+		resources, err := repo.ListResourceInstances(app.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range resources {
+			result = append(result, resourceHealth(app, resource))
+		}
+	}
+	return result, nil
+}
+
+func resourceHealth(app *database.Application, resource *database.ResourceInstance) alerting.ResourceHealth {
+	health := alerting.ResourceHealth{
+		App:       app.Name,
+		Team:      app.Team,
+		Name:      resource.ResourceName,
+		Type:      resource.ResourceType,
+		Unhealthy: resourceFailing(resource),
+	}
+	if resource.ErrorMessage != nil {
+		health.Reason = *resource.ErrorMessage
+	} else if health.Unhealthy {
+		health.Reason = string(resource.State)
+	}
+	return health
+}
@@ -0,0 +1,330 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/resources"
+	"innominatus/internal/types"
+	"innominatus/internal/users"
+	"innominatus/internal/workflow"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Actions a deployment would take on a resource
+const (
+	resourceActionCreate    = "create"
+	resourceActionUpdate    = "update"
+	resourceActionDelete    = "delete"
+	resourceActionUnchanged = "unchanged"
+)
+
+// applicationPreview is what deploying a Score spec would change for an application
+type applicationPreview struct {
+	Application       string           `json:"application"`
+	Exists            bool             `json:"exists"` // Whether the application is deployed already
+	Resources         []resourceChange `json:"resources"`
+	Steps             []previewStep    `json:"steps"`
+	EstimatedDuration string           `json:"estimated_duration"`
+	EstimatedSeconds  int              `json:"estimated_seconds"`
+	Summary           map[string]int   `json:"summary"` // Number of resources per action
+	Warnings          []string         `json:"warnings,omitempty"`
+	DryRun            bool             `json:"dry_run,omitempty"`           // Answer of a deploy or golden path run with dry_run=true
+	GoldenPath        string           `json:"golden_path,omitempty"`       // Golden path whose steps are listed
+	ApprovalRequired  bool             `json:"approval_required,omitempty"` // The run would wait for plan approval
+}
+
+// resourceChange is the action a deployment would take on one resource
+type resourceChange struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Action  string   `json:"action"`
+	State   string   `json:"state,omitempty"`   // Current lifecycle state of a deployed resource
+	Changes []string `json:"changes,omitempty"` // Configuration keys an update changes
+	Diff    string   `json:"diff,omitempty"`    // Dry-run plan of the provisioner, if it supports planning
+	Error   string   `json:"error,omitempty"`
+}
+
+// previewStep is a workflow step a deployment would run
+type previewStep struct {
+	Phase             string `json:"phase"`
+	Name              string `json:"name"`
+	Type              string `json:"type"`
+	EstimatedDuration string `json:"estimated_duration"`
+}
+
+// handleApplicationPreview compares a Score spec with the deployed state of an application
+// and returns the resources a deployment would create, update or delete, the workflow steps
+// it would run and how long that is estimated to take. Nothing is stored.
+func (s *Server) handleApplicationPreview(w http.ResponseWriter, r *http.Request, appName string) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	if len(body) == 0 {
+		http.Error(w, "Request body cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	var spec types.ScoreSpec
+	if err := yaml.Unmarshal(body, &spec); err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing YAML: %v", err), http.StatusBadRequest)
+		return
+	}
+	if spec.Metadata.Name == "" {
+		spec.Metadata.Name = appName
+	}
+	if spec.Metadata.Name != appName {
+		http.Error(w, fmt.Sprintf("Error: metadata.name '%s' does not match application '%s'", spec.Metadata.Name, appName), http.StatusBadRequest)
+		return
+	}
+	if err := s.validateResourceTypes(&spec); err != nil {
+		http.Error(w, fmt.Sprintf("Resource validation failed: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	preview, status, err := s.buildApplicationPreview(r.Context(), user, &spec)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(preview); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// buildApplicationPreview compares a Score spec with the deployed state of its application.
+// On error, it also returns the HTTP status to answer with.
+func (s *Server) buildApplicationPreview(ctx context.Context, user *users.User, spec *types.ScoreSpec) (*applicationPreview, int, error) {
+	appName := spec.Metadata.Name
+	exists := false
+	if s.db != nil {
+		if app, err := s.db.GetApplication(appName); err == nil && app != nil {
+			if !s.canAccessTeam(user, app.Team) {
+				return nil, http.StatusForbidden, fmt.Errorf("access denied")
+			}
+			exists = true
+		}
+	}
+
+	var current []*database.ResourceInstance
+	if exists && s.resourceManager != nil {
+		var err error
+		current, err = s.resourceManager.GetResourcesByApplication(appName)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to get resources: %w", err)
+		}
+	}
+
+	changes := diffResources(spec, current)
+	plans := make(map[string]workflow.ResourcePlan)
+	for _, plan := range s.specResourcePlans(ctx, spec) {
+		plans[plan.Resource] = plan
+	}
+	unchanged := make(map[string]bool)
+	summary := map[string]int{
+		resourceActionCreate:    0,
+		resourceActionUpdate:    0,
+		resourceActionDelete:    0,
+		resourceActionUnchanged: 0,
+	}
+	for i, change := range changes {
+		summary[change.Action]++
+		if change.Action == resourceActionUnchanged {
+			unchanged[change.Name] = true
+			continue
+		}
+		if plan, ok := plans[change.Name]; ok {
+			changes[i].Diff = plan.Diff
+			changes[i].Error = plan.Error
+		}
+	}
+
+	if s.workflowAnalyzer == nil {
+		s.workflowAnalyzer = workflow.NewWorkflowAnalyzer()
+	}
+	analysis, err := s.workflowAnalyzer.AnalyzeSpecChanges(spec, unchanged)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to analyze workflow: %w", err)
+	}
+
+	steps := []previewStep{}
+	for _, phase := range analysis.ExecutionPlan.Phases {
+		for _, group := range phase.ParallelGroups {
+			for _, step := range group.Steps {
+				steps = append(steps, previewStep{
+					Phase:             phase.Name,
+					Name:              step.Name,
+					Type:              step.Type,
+					EstimatedDuration: step.EstimatedTime.String(),
+				})
+			}
+		}
+	}
+
+	return &applicationPreview{
+		Application:       appName,
+		Exists:            exists,
+		Resources:         changes,
+		Steps:             steps,
+		EstimatedDuration: analysis.EstimatedTime.String(),
+		EstimatedSeconds:  int(analysis.EstimatedTime / time.Second),
+		Summary:           summary,
+		Warnings:          append(s.resourceTypeWarnings(spec), analysis.Warnings...),
+	}, http.StatusOK, nil
+}
+
+// writeDryRunPreview answers a deploy or golden path run with dry_run=true. With a golden
+// path, the preview lists its steps and whether the run in environment would wait for
+// plan approval.
+func (s *Server) writeDryRunPreview(w http.ResponseWriter, r *http.Request, user *users.User, spec *types.ScoreSpec, goldenPathName string, wf *types.Workflow, environment string) {
+	preview, status, err := s.buildApplicationPreview(r.Context(), user, spec)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	preview.DryRun = true
+
+	if wf != nil {
+		preview.ApprovalRequired = applyPlanApprovalPolicy(goldenPathName, environment, wf)
+		s.withGoldenPathSteps(preview, goldenPathName, *wf)
+	}
+
+	logging.FromContext(r.Context(), "server").Infof("Dry run for '%s': %d to create, %d to update, %d to delete",
+		spec.Metadata.Name, preview.Summary[resourceActionCreate], preview.Summary[resourceActionUpdate], preview.Summary[resourceActionDelete])
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(preview); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// withGoldenPathSteps replaces the steps of a preview with the steps of a golden path
+// workflow. Steps are assumed to run one after another.
+func (s *Server) withGoldenPathSteps(preview *applicationPreview, goldenPathName string, wf types.Workflow) {
+	if s.workflowAnalyzer == nil {
+		s.workflowAnalyzer = workflow.NewWorkflowAnalyzer()
+	}
+
+	var total time.Duration
+	preview.GoldenPath = goldenPathName
+	preview.Steps = make([]previewStep, 0, len(wf.Steps))
+	for _, step := range wf.Steps {
+		duration := s.workflowAnalyzer.StepDuration(step.Type)
+		total += duration
+		preview.Steps = append(preview.Steps, previewStep{
+			Phase:             goldenPathName,
+			Name:              step.Name,
+			Type:              step.Type,
+			EstimatedDuration: duration.String(),
+		})
+	}
+	preview.EstimatedDuration = total.String()
+	preview.EstimatedSeconds = int(total / time.Second)
+}
+
+// diffResources compares the resources of a spec with the deployed resource instances of
+// the application. Deployed resources missing from the spec are reported as deleted,
+// except for the GitOps pipeline resources a kubernetes environment adds on deploy.
+func diffResources(spec *types.ScoreSpec, current []*database.ResourceInstance) []resourceChange {
+	appName := spec.Metadata.Name
+	deployed := make(map[string]*database.ResourceInstance, len(current))
+	for _, instance := range current {
+		if instance.State == database.ResourceStateTerminated {
+			continue
+		}
+		deployed[instance.ResourceName] = instance
+	}
+
+	names := make([]string, 0, len(spec.Resources))
+	for name := range spec.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changes := make([]resourceChange, 0, len(names)+len(deployed))
+	for _, name := range names {
+		resource := spec.Resources[name]
+		change := resourceChange{Name: name, Type: resource.Type, Action: resourceActionCreate}
+		if instance, ok := deployed[name]; ok {
+			change.State = string(instance.State)
+			change.Changes = configChanges(instance.Configuration, resources.SpecResourceConfig(appName, resource))
+			if len(change.Changes) > 0 {
+				change.Action = resourceActionUpdate
+			} else {
+				change.Action = resourceActionUnchanged
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	pipeline := map[string]bool{}
+	if spec.Environment != nil && spec.Environment.Type == "kubernetes" {
+		pipeline[appName+"-gitea"] = true
+		pipeline[appName+"-k8s"] = true
+		pipeline[appName+"-argocd"] = true
+	}
+	var removed []resourceChange
+	for name, instance := range deployed {
+		if _, ok := spec.Resources[name]; ok || pipeline[name] {
+			continue
+		}
+		removed = append(removed, resourceChange{
+			Name:   name,
+			Type:   instance.ResourceType,
+			Action: resourceActionDelete,
+			State:  string(instance.State),
+		})
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Name < removed[j].Name })
+	return append(changes, removed...)
+}
+
+// configChanges returns the sorted configuration keys whose values differ. Values are
+// compared in their JSON form, as stored configurations come back from the database.
+func configChanges(current, desired map[string]interface{}) []string {
+	keys := make(map[string]bool, len(current)+len(desired))
+	for key := range current {
+		keys[key] = true
+	}
+	for key := range desired {
+		keys[key] = true
+	}
+
+	var changed []string
+	for key := range keys {
+		if current[key] == nil && desired[key] == nil {
+			continue
+		}
+		before, _ := json.Marshal(current[key])
+		after, _ := json.Marshal(desired[key])
+		if string(before) != string(after) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,303 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	// statusWorkflowLimit caps the executions scanned for an application status
+	statusWorkflowLimit = 50
+	// statusTransitionLimit caps the state transitions read per resource
+	statusTransitionLimit = 5
+	// statusEventLimit caps the recent events of an application status
+	statusEventLimit = 20
+)
+
+// ApplicationDeployment is the most recent deployment of an application
+type ApplicationDeployment struct {
+	DeployedAt time.Time                          `json:"deployed_at"` // When the spec was last stored
+	DeployedBy string                             `json:"deployed_by"`
+	Status     string                             `json:"status"` // Status of the latest workflow, or "deployed" without one
+	Workflow   *database.WorkflowExecutionSummary `json:"workflow,omitempty"`
+	Provenance *database.DeploymentProvenance     `json:"provenance,omitempty"`
+}
+
+// ApplicationResource is the state of one resource of an application
+type ApplicationResource struct {
+	ID           int64                           `json:"id"`
+	Name         string                          `json:"name"`
+	Type         string                          `json:"type"`
+	State        database.ResourceLifecycleState `json:"state"`
+	HealthStatus string                          `json:"health_status,omitempty"`
+	ErrorMessage string                          `json:"error_message,omitempty"`
+	Hints        []database.ResourceHint         `json:"hints,omitempty"`
+	UpdatedAt    time.Time                       `json:"updated_at"`
+}
+
+// ApplicationHealth summarizes the health of an application's resources
+type ApplicationHealth struct {
+	Status           string `json:"status"` // healthy, degraded, hibernated or unknown
+	Resources        int    `json:"resources"`
+	Healthy          int    `json:"healthy"`
+	FailingResources int    `json:"failing_resources"`
+}
+
+// ApplicationEvent is an entry of an application's recent activity
+type ApplicationEvent struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"` // workflow, resource or approval
+	Subject  string    `json:"subject"`
+	Message  string    `json:"message"`
+	Severity string    `json:"severity"` // info, warning or error
+}
+
+// ApplicationStatus is the response of GET /api/applications/{name}/status
+type ApplicationStatus struct {
+	Name             string                               `json:"name"`
+	Team             string                               `json:"team"`
+	LatestDeployment *ApplicationDeployment               `json:"latest_deployment"`
+	Resources        []ApplicationResource                `json:"resources"`
+	ActiveWorkflows  []*database.WorkflowExecutionSummary `json:"active_workflows"`
+	Health           ApplicationHealth                    `json:"health"`
+	RecentEvents     []ApplicationEvent                   `json:"recent_events"`
+	PendingApprovals []*database.WorkflowApproval         `json:"pending_approvals"`
+	Hibernation      *database.ApplicationHibernation     `json:"hibernation,omitempty"`
+	GeneratedAt      time.Time                            `json:"generated_at"`
+}
+
+// applicationStatusSources holds the records an application status is built from
+type applicationStatusSources struct {
+	application *database.Application
+	resources   []*database.ResourceInstance
+	transitions map[int64][]*database.ResourceStateTransition
+	executions  []*database.WorkflowExecutionSummary // Most recent first
+	approvals   []*database.WorkflowApproval
+	provenance  []*database.DeploymentProvenance // Most recent first
+	hibernation *database.ApplicationHibernation
+}
+
+// handleApplicationStatus handles GET /api/applications/{name}/status, a consolidated view
+// of an application: latest deployment, resources, active workflows, health, recent
+// events and pending approvals.
+func (s *Server) handleApplicationStatus(w http.ResponseWriter, r *http.Request, appName string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	app, err := s.db.GetApplication(appName)
+	if err != nil || app == nil {
+		http.Error(w, "Application not found", http.StatusNotFound)
+		return
+	}
+	if !s.canAccessTeam(user, app.Team) {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+
+	sources, err := s.loadApplicationStatusSources(app)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to build application status: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	status := buildApplicationStatus(sources, s.Clock().Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// loadApplicationStatusSources reads everything the status of an application needs
+func (s *Server) loadApplicationStatusSources(app *database.Application) (*applicationStatusSources, error) {
+	sources := &applicationStatusSources{
+		application: app,
+		transitions: make(map[int64][]*database.ResourceStateTransition),
+	}
+
+	var err error
+	if repo := s.GetResourceRepository(); repo != nil {
+		sources.resources, err = repo.ListResourceInstances(app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources: %w", err)
+		}
+		for _, resource := range sources.resources {
+			transitions, err := repo.GetResourceStateTransitions(resource.ID, statusTransitionLimit)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list state transitions of %s: %w", resource.ResourceName, err)
+			}
+			sources.transitions[resource.ID] = transitions
+		}
+	}
+
+	if s.workflowRepo != nil {
+		sources.executions, err = s.workflowRepo.ListWorkflowExecutions(app.Name, "", "", statusWorkflowLimit, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflows: %w", err)
+		}
+	}
+
+	sources.approvals, err = s.db.ListWorkflowApprovals(database.ApprovalStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+
+	sources.provenance, err = s.db.ListDeploymentProvenance(app.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provenance: %w", err)
+	}
+
+	sources.hibernation, err = s.db.GetApplicationHibernation(app.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hibernation: %w", err)
+	}
+
+	return sources, nil
+}
+
+// buildApplicationStatus combines the sources into the status of one application
+func buildApplicationStatus(sources *applicationStatusSources, now time.Time) *ApplicationStatus {
+	app := sources.application
+	status := &ApplicationStatus{
+		Name:             app.Name,
+		Team:             app.Team,
+		Resources:        []ApplicationResource{},
+		ActiveWorkflows:  []*database.WorkflowExecutionSummary{},
+		RecentEvents:     []ApplicationEvent{},
+		PendingApprovals: []*database.WorkflowApproval{},
+		GeneratedAt:      now,
+	}
+
+	deployment := &ApplicationDeployment{DeployedAt: app.UpdatedAt, DeployedBy: app.CreatedBy, Status: "deployed"}
+	if len(sources.executions) > 0 {
+		deployment.Workflow = sources.executions[0]
+		deployment.Status = sources.executions[0].Status
+	}
+	if len(sources.provenance) > 0 {
+		deployment.Provenance = sources.provenance[0]
+	}
+	status.LatestDeployment = deployment
+
+	var events []ApplicationEvent
+	for _, resource := range sources.resources {
+		item := ApplicationResource{
+			ID:           resource.ID,
+			Name:         resource.ResourceName,
+			Type:         resource.ResourceType,
+			State:        resource.State,
+			HealthStatus: resource.HealthStatus,
+			Hints:        resource.Hints,
+			UpdatedAt:    resource.UpdatedAt,
+		}
+		if resource.ErrorMessage != nil {
+			item.ErrorMessage = *resource.ErrorMessage
+		}
+		status.Resources = append(status.Resources, item)
+
+		status.Health.Resources++
+		if resourceFailing(resource) {
+			status.Health.FailingResources++
+		} else if resource.State == database.ResourceStateActive {
+			status.Health.Healthy++
+		}
+
+		for _, transition := range sources.transitions[resource.ID] {
+			severity := "info"
+			if transition.ToState == database.ResourceStateFailed || transition.ToState == database.ResourceStateDegraded {
+				severity = "error"
+			}
+			message := fmt.Sprintf("%s → %s", transition.FromState, transition.ToState)
+			if transition.Reason != "" {
+				message += ": " + transition.Reason
+			}
+			events = append(events, ApplicationEvent{
+				Time:     transition.TransitionedAt,
+				Type:     "resource",
+				Subject:  resource.ResourceName,
+				Message:  message,
+				Severity: severity,
+			})
+		}
+	}
+	sort.Slice(status.Resources, func(i, j int) bool {
+		return status.Resources[i].Name < status.Resources[j].Name
+	})
+
+	status.Hibernation = sources.hibernation
+	switch {
+	case sources.hibernation != nil:
+		status.Health.Status = "hibernated"
+	case status.Health.FailingResources > 0:
+		status.Health.Status = "degraded"
+	case status.Health.Resources > 0 && status.Health.Healthy == status.Health.Resources:
+		status.Health.Status = "healthy"
+	default:
+		status.Health.Status = "unknown"
+	}
+
+	for _, execution := range sources.executions {
+		if execution.Status == database.WorkflowStatusRunning || execution.Status == "pending" {
+			status.ActiveWorkflows = append(status.ActiveWorkflows, execution)
+		}
+		events = append(events, ApplicationEvent{
+			Time:     execution.StartedAt,
+			Type:     "workflow",
+			Subject:  execution.WorkflowName,
+			Message:  fmt.Sprintf("Workflow #%d started", execution.ID),
+			Severity: "info",
+		})
+		if execution.CompletedAt != nil {
+			severity := "info"
+			if execution.Status == database.WorkflowStatusFailed {
+				severity = "error"
+			}
+			events = append(events, ApplicationEvent{
+				Time:     *execution.CompletedAt,
+				Type:     "workflow",
+				Subject:  execution.WorkflowName,
+				Message:  fmt.Sprintf("Workflow #%d %s", execution.ID, execution.Status),
+				Severity: severity,
+			})
+		}
+	}
+
+	for _, approval := range sources.approvals {
+		if approval.ApplicationName != app.Name {
+			continue
+		}
+		status.PendingApprovals = append(status.PendingApprovals, approval)
+		events = append(events, ApplicationEvent{
+			Time:     approval.RequestedAt,
+			Type:     "approval",
+			Subject:  approval.StepName,
+			Message:  fmt.Sprintf("Approval #%d requested", approval.ID),
+			Severity: "warning",
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.After(events[j].Time) })
+	if len(events) > statusEventLimit {
+		events = events[:statusEventLimit]
+	}
+	status.RecentEvents = append(status.RecentEvents, events...)
+
+	return status
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/database"
+	"innominatus/internal/goldenpaths"
+	"innominatus/internal/types"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// HandleApprovals lists the workflow approval gates of the applications the user can
+// manage (GET /api/approvals?status=pending)
+func (s *Server) HandleApprovals(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.db == nil {
+		http.Error(w, "Approvals require database connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	approvals, err := s.db.ListWorkflowApprovals(r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list approvals: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	visible := make([]*database.WorkflowApproval, 0, len(approvals))
+	for _, approval := range approvals {
+		if s.canManageApplication(user, approval.ApplicationName) {
+			visible = append(visible, approval)
+		}
+	}
+
+	response := map[string]interface{}{
+		"approvals": visible,
+		"count":     len(visible),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// HandleApprovalDetail handles GET /api/approvals/{id} and
+// POST /api/approvals/{id}/approve or /api/approvals/{id}/reject
+func (s *Server) HandleApprovalDetail(w http.ResponseWriter, r *http.Request) {
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.db == nil {
+		http.Error(w, "Approvals require database connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	pathParts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/approvals/"), "/"), "/")
+	id, err := strconv.ParseInt(pathParts[0], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid approval ID", http.StatusBadRequest)
+		return
+	}
+
+	approval, err := s.db.GetWorkflowApproval(id)
+	if err != nil {
+		http.Error(w, "Approval not found", http.StatusNotFound)
+		return
+	}
+
+	if !s.canManageApplication(user, approval.ApplicationName) {
+		http.Error(w, "Forbidden: only the owning team or an admin can access approvals", http.StatusForbidden)
+		return
+	}
+
+	if len(pathParts) == 1 {
+		if r.Method != "GET" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(approval); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+		}
+		return
+	}
+
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var status string
+	switch pathParts[1] {
+	case "approve":
+		status = database.ApprovalStatusApproved
+	case "reject":
+		status = database.ApprovalStatusRejected
+	default:
+		http.Error(w, fmt.Sprintf("Unknown approval action: %s", pathParts[1]), http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		Comment string `json:"comment"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := s.db.DecideWorkflowApproval(id, status, user.Username, req.Comment); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	approval, err = s.db.GetWorkflowApproval(id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to reload approval: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(approval); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// applyPlanApprovalPolicy marks terraform apply and db-migrate steps as requiring approval
+// when the golden path protects the target environment. Returns true if any step was gated.
+func applyPlanApprovalPolicy(goldenPathName, environment string, workflow *types.Workflow) bool {
+	if environment == "" {
+		return false
+	}
+
+	var fallback []string
+	if adminConfig, err := admin.LoadAdminConfig("admin-config.yaml"); err == nil {
+		fallback = adminConfig.WorkflowPolicies.Security.RequireApproval
+	}
+
+	// Golden paths not listed in goldenpaths.yaml only use the admin policy
+	var required bool
+	config, err := goldenpaths.LoadGoldenPaths()
+	if err == nil {
+		required, err = config.RequiresApproval(goldenPathName, environment, fallback)
+	}
+	if err != nil {
+		for _, env := range fallback {
+			if env == environment {
+				required = true
+			}
+		}
+	}
+	if !required {
+		return false
+	}
+
+	gated := false
+	for i := range workflow.Steps {
+		step := &workflow.Steps[i]
+		isApply := step.Type == "terraform" && (step.Operation == "" || step.Operation == "apply")
+		if !isApply && step.Type != "db-migrate" {
+			continue
+		}
+		if step.Config == nil {
+			step.Config = make(map[string]interface{})
+		}
+		step.Config["require_approval"] = true
+		step.Config["environment"] = environment
+		gated = true
+	}
+	return gated
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// approvalPageTemplate shows an approval gate with a form to decide it. Deciding takes a
+// POST, so chat clients and link previews opening the page do not decide anything.
+var approvalPageTemplate = template.Must(template.New("approval").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Approval #{{ .Approval.ID }} · innominatus</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 46rem; margin: 2rem auto; padding: 0 1rem; color: #1f2933; }
+dt { font-weight: 600; } dd { margin: 0 0 .5rem 0; }
+pre { background: #f5f7fa; padding: .75rem; overflow-x: auto; white-space: pre-wrap; }
+textarea { width: 100%; min-height: 4rem; }
+button { padding: .5rem 1.25rem; margin-right: .5rem; font-size: 1rem; }
+.approve { background: #1f7a3f; color: #fff; } .reject { background: #b42318; color: #fff; }
+.chosen { outline: 3px solid #f0b429; }
+.error { color: #b42318; }
+</style>
+</head>
+<body>
+<h1>Approval #{{ .Approval.ID }}: {{ .Approval.Status }}</h1>
+{{ with .Error }}<p class="error">{{ . }}</p>{{ end }}
+<dl>
+<dt>Application</dt><dd>{{ .Approval.ApplicationName }}</dd>
+<dt>Step</dt><dd>{{ .Approval.StepName }} (workflow execution {{ .Approval.ExecutionID }})</dd>
+{{ with .Approval.Environment }}<dt>Environment</dt><dd>{{ . }}</dd>{{ end }}
+<dt>Requested</dt><dd>{{ .Approval.RequestedAt.Format "2006-01-02 15:04 MST" }}</dd>
+{{ with .Approval.DecidedBy }}<dt>Decided by</dt><dd>{{ . }}</dd>{{ end }}
+{{ with .Approval.Comment }}<dt>Comment</dt><dd>{{ . }}</dd>{{ end }}
+</dl>
+{{ with .Approval.Summary }}<h2>Summary</h2><pre>{{ . }}</pre>{{ end }}
+{{ if .Pending }}
+<form method="post" action="/approvals/{{ .Approval.ID }}">
+<p><label for="comment">Comment</label><br><textarea id="comment" name="comment"></textarea></p>
+<button class="approve{{ if eq .Decision "approve" }} chosen{{ end }}" name="decision" value="approve">Approve</button>
+<button class="reject{{ if eq .Decision "reject" }} chosen{{ end }}" name="decision" value="reject">Reject</button>
+</form>
+{{ end }}
+<p>Deciding as {{ .Username }}.</p>
+</body>
+</html>
+`))
+
+// approvalPage is what approvalPageTemplate is rendered with
+type approvalPage struct {
+	Approval *database.WorkflowApproval
+	Pending  bool
+	Decision string // Button the user came from: approve or reject
+	Username string
+	Error    string
+}
+
+// HandleApprovalPage serves the page the buttons of approval notifications open, e.g. in
+// Microsoft Teams. Users log in with their session like in the web UI and decide with the
+// permissions of the approvals API.
+//
+// GET  /approvals/{id}?decision=approve|reject   show the approval
+// POST /approvals/{id}                           decide with form fields decision and comment
+func (s *Server) HandleApprovalPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.db == nil {
+		http.Error(w, "Approvals require database connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(r.URL.Path, "/approvals/"), "/"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid approval ID", http.StatusBadRequest)
+		return
+	}
+	approval, err := s.db.GetWorkflowApproval(id)
+	if err != nil {
+		http.Error(w, "Approval not found", http.StatusNotFound)
+		return
+	}
+	if !s.canManageApplication(user, approval.ApplicationName) {
+		http.Error(w, "Forbidden: only the owning team or an admin can decide approvals", http.StatusForbidden)
+		return
+	}
+
+	page := approvalPage{Approval: approval, Decision: r.URL.Query().Get("decision"), Username: user.Username}
+	status := http.StatusOK
+	if r.Method == "POST" {
+		decision := r.PostFormValue("decision")
+		var decided string
+		switch decision {
+		case "approve":
+			decided = database.ApprovalStatusApproved
+		case "reject":
+			decided = database.ApprovalStatusRejected
+		default:
+			http.Error(w, fmt.Sprintf("Unknown decision '%s'", decision), http.StatusBadRequest)
+			return
+		}
+
+		if err := s.db.DecideWorkflowApproval(id, decided, user.Username, r.PostFormValue("comment")); err != nil {
+			page.Error, status = err.Error(), http.StatusConflict
+		} else {
+			logging.FromContext(r.Context(), "server").InfoWithFields("Decided approval from approval page", map[string]interface{}{
+				"approval_id": id,
+				"status":      decided,
+				"app_name":    approval.ApplicationName,
+			})
+			http.Redirect(w, r, fmt.Sprintf("/approvals/%d", id), http.StatusSeeOther)
+			return
+		}
+		if page.Approval, err = s.db.GetWorkflowApproval(id); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to reload approval: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+	page.Pending = page.Approval.Status == database.ApprovalStatusPending
+
+	var buf bytes.Buffer
+	if err := approvalPageTemplate.Execute(&buf, page); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to render approval: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if _, err := buf.WriteTo(w); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write response: %v\n", err)
+	}
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/argoprojects"
+	"innominatus/internal/logging"
+	"net/http"
+	"os"
+	"sort"
+)
+
+// argoCDProjectProvisioner returns the provisioner of team projects in ArgoCD; nil when
+// argocd.teamProjects is disabled or ArgoCD is not configured
+func (s *Server) argoCDProjectProvisioner() *argoprojects.Provisioner {
+	if s.argoProjects != nil {
+		return s.argoProjects
+	}
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil || adminConfig.ArgoCD.URL == "" || !adminConfig.ArgoCD.TeamProjects.Enabled {
+		return nil
+	}
+	return argoprojects.NewProvisioner(adminConfig.ArgoCD.TeamProjects, adminConfig.ArgoCD.URL, adminConfig.ArgoCD.Username, adminConfig.ArgoCD.Password)
+}
+
+// argoCDProjectTeams returns every team with its applications. Teams without
+// applications get a project too, so it exists before their first deployment.
+func (s *Server) argoCDProjectTeams() ([]argoprojects.Team, error) {
+	byID := map[string]*argoprojects.Team{}
+	for _, t := range s.giteaTeamMembers() {
+		byID[t.ID] = &argoprojects.Team{ID: t.ID}
+	}
+	if s.db != nil {
+		apps, err := s.db.ListApplications()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list applications: %w", err)
+		}
+		for _, app := range apps {
+			if app.Team == "" {
+				continue
+			}
+			team, ok := byID[app.Team]
+			if !ok {
+				team = &argoprojects.Team{ID: app.Team}
+				byID[app.Team] = team
+			}
+			team.Applications = append(team.Applications, app.Name)
+		}
+	}
+
+	teams := make([]argoprojects.Team, 0, len(byID))
+	for _, t := range byID {
+		teams = append(teams, *t)
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].ID < teams[j].ID })
+	return teams, nil
+}
+
+// teamArgoCDProject returns the project of the application's team, creating or updating
+// it first; "" keeps the default project
+func (s *Server) teamArgoCDProject(appName string) (string, error) {
+	provisioner := s.argoCDProjectProvisioner()
+	if provisioner == nil || s.db == nil {
+		return "", nil
+	}
+	return provisioner.ProjectFor(s.db, appName)
+}
+
+// StartArgoCDProjectSync syncs the projects of all teams at the configured interval
+func (s *Server) StartArgoCDProjectSync(ctx context.Context) {
+	logger := logging.NewStructuredLogger("server")
+	if s.argoCDProjectProvisioner() == nil {
+		return
+	}
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return
+	}
+	interval, err := adminConfig.ArgoCD.TeamProjects.Interval()
+	if err != nil {
+		logger.Warnf("ArgoCD team projects disabled: %v", err)
+		return
+	}
+
+	go func() {
+		ticker := s.Clock().NewTicker("argocd-project-sync", interval)
+		defer ticker.Stop()
+
+		for {
+			s.syncArgoCDProjects()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+		}
+	}()
+}
+
+// syncArgoCDProjects runs one sync of all team projects
+func (s *Server) syncArgoCDProjects() {
+	logger := logging.NewStructuredLogger("server")
+	provisioner := s.argoCDProjectProvisioner()
+	if provisioner == nil {
+		return
+	}
+	teams, err := s.argoCDProjectTeams()
+	if err != nil {
+		logger.Warnf("ArgoCD project sync skipped: %v", err)
+		return
+	}
+	for _, result := range provisioner.SyncAll(teams) {
+		logArgoCDProjectResult(result)
+	}
+}
+
+// logArgoCDProjectResult logs the changes and failures of a project sync
+func logArgoCDProjectResult(result argoprojects.Result) {
+	logger := logging.NewStructuredLogger("server")
+	fields := map[string]interface{}{"team": result.Team, "project": result.Project}
+	switch {
+	case result.Error != "":
+		fields["error"] = result.Error
+		logger.WarnWithFields("ArgoCD project sync failed", fields)
+	case result.Created:
+		logger.InfoWithFields("Created ArgoCD project of team", fields)
+	case result.Updated:
+		logger.InfoWithFields("Updated ArgoCD project of team", fields)
+	}
+}
+
+// HandleArgoCDProjectSync syncs the ArgoCD projects of all teams, or of ?team=<id>, now.
+//
+// POST /api/admin/argocd/project-sync
+func (s *Server) HandleArgoCDProjectSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	provisioner := s.argoCDProjectProvisioner()
+	if provisioner == nil {
+		http.Error(w, "ArgoCD team projects are not enabled (argocd.teamProjects.enabled)", http.StatusNotFound)
+		return
+	}
+
+	all, err := s.argoCDProjectTeams()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	selected := all
+	if id := r.URL.Query().Get("team"); id != "" {
+		selected = nil
+		for _, t := range all {
+			if t.ID == id {
+				selected = append(selected, t)
+			}
+		}
+		if len(selected) == 0 {
+			http.Error(w, fmt.Sprintf("Team '%s' not found", id), http.StatusNotFound)
+			return
+		}
+	}
+
+	results := provisioner.SyncAll(selected)
+	failed := 0
+	for _, result := range results {
+		logArgoCDProjectResult(result)
+		if result.Error != "" {
+			failed++
+		}
+	}
+
+	status := http.StatusOK
+	if failed > 0 {
+		status = http.StatusBadGateway
+	}
+	writeArgoCDProjectsJSON(w, status, map[string]interface{}{
+		"results": results,
+		"synced":  len(results) - failed,
+		"failed":  failed,
+	})
+}
+
+// writeArgoCDProjectsJSON writes body as JSON with status
+func writeArgoCDProjectsJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
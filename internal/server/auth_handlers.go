@@ -4,11 +4,17 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"innominatus/internal/admin"
 	"innominatus/internal/auth"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
 	"innominatus/internal/users"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +33,59 @@ func (s *Server) HandleLogin(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// authProviders returns the password login providers configured in admin-config.yaml,
+// as of the last reload once the config was applied. Without an admin config, users.yaml
+// is the only provider.
+func (s *Server) authProviders() (auth.ProviderChain, error) {
+	if settings := s.runtime.Load(); settings != nil {
+		return settings.authProviders, nil
+	}
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return auth.ProviderChain{auth.LocalProvider{}}, nil
+	}
+	return auth.NewProviderChain(adminConfig.Authentication)
+}
+
+// recordLoginFailure counts a failed login. A store error is logged rather than
+// returned, the login has failed either way.
+func (s *Server) recordLoginFailure(throttle *auth.LoginThrottle, username, clientIP string) {
+	if err := throttle.Failure(username, clientIP); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+	}
+}
+
+// retryAfterText formats a lockout for login error messages, rounded up to seconds
+func retryAfterText(retryAfter time.Duration) string {
+	return (retryAfter + time.Second - 1).Truncate(time.Second).String()
+}
+
+// applyDirectory applies the SCIM-provisioned state of a user: deactivated users are
+// rejected with auth.ErrInvalidCredentials and members of provisioned teams get the
+// first of them by name. Users the identity provider did not provision are unchanged.
+func (s *Server) applyDirectory(user *users.User) (*users.User, error) {
+	store := s.scimStore()
+	if store == nil {
+		return user, nil
+	}
+	entry, err := store.GetDirectoryUserByUsername(user.Username)
+	if errors.Is(err, database.ErrDirectoryNotFound) {
+		return user, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !entry.Active {
+		return nil, auth.ErrInvalidCredentials
+	}
+	if len(entry.Teams) > 0 {
+		provisioned := *user
+		provisioned.Team = entry.Teams[0]
+		return &provisioned, nil
+	}
+	return user, nil
+}
+
 // HandleLogout handles user logout
 func (s *Server) HandleLogout(w http.ResponseWriter, r *http.Request) {
 	// Get session from request
@@ -51,39 +110,61 @@ func (s *Server) showLoginPage(w http.ResponseWriter, r *http.Request) {
 
 // processLogin handles login form submission
 func (s *Server) processLogin(w http.ResponseWriter, r *http.Request) {
-	clientIP := getClientIP(r)
+	username := r.FormValue("username")
+	password := r.FormValue("password")
 
-	// Check rate limiting
-	if s.isRateLimited(clientIP) {
-		http.Redirect(w, r, "/auth/login?error=Too+many+login+attempts.+Please+wait+15+minutes.", http.StatusSeeOther)
+	throttle, err := s.loginThrottle()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		http.Redirect(w, r, "/auth/login?error=System+error%3A+authentication+is+misconfigured", http.StatusSeeOther)
+		return
+	}
+	clientIP := throttle.ClientIP(r)
+	retryAfter, err := throttle.Check(username, clientIP)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		http.Redirect(w, r, "/auth/login?error=System+error%3A+unable+to+authenticate", http.StatusSeeOther)
+		return
+	}
+	if retryAfter > 0 {
+		message := fmt.Sprintf("Too many login attempts. Try again in %s.", retryAfterText(retryAfter))
+		http.Redirect(w, r, "/auth/login?error="+url.QueryEscape(message), http.StatusSeeOther)
 		return
 	}
-
-	username := r.FormValue("username")
-	password := r.FormValue("password")
 
 	if username == "" || password == "" {
-		s.recordLoginAttempt(clientIP)
+		s.recordLoginFailure(throttle, username, clientIP)
 		http.Redirect(w, r, "/auth/login?error=Username+and+password+are+required", http.StatusSeeOther)
 		return
 	}
 
-	// Load users and authenticate
-	store, err := users.LoadUsers()
+	// Authenticate with the configured providers (users.yaml, LDAP)
+	providers, err := s.authProviders()
 	if err != nil {
-		http.Redirect(w, r, "/auth/login?error=System+error%3A+unable+to+load+user+data", http.StatusSeeOther)
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		http.Redirect(w, r, "/auth/login?error=System+error%3A+authentication+is+misconfigured", http.StatusSeeOther)
 		return
 	}
 
-	user, err := store.Authenticate(username, password)
-	if err != nil {
-		s.recordLoginAttempt(clientIP)
+	user, err := providers.Authenticate(r.Context(), username, password)
+	if err == nil {
+		user, err = s.applyDirectory(user)
+	}
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		s.recordLoginFailure(throttle, username, clientIP)
 		http.Redirect(w, r, "/auth/login?error=Invalid+username+or+password", http.StatusSeeOther)
 		return
 	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		http.Redirect(w, r, "/auth/login?error=System+error%3A+unable+to+authenticate", http.StatusSeeOther)
+		return
+	}
 
-	// Clear login attempts on successful authentication
-	s.clearLoginAttempts(clientIP)
+	// Clear the failed logins of the username on successful authentication
+	if err := throttle.Success(username); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+	}
 
 	// Create session
 	session, err := s.sessionManager.CreateSession(user)
@@ -166,6 +247,16 @@ func (s *Server) HandleUserInfo(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// CreateSessionToken starts a session for an already authenticated user and returns
+// its bearer token. Used by embedders such as the integration test harness.
+func (s *Server) CreateSessionToken(user *users.User) (string, error) {
+	session, err := s.sessionManager.CreateSession(user)
+	if err != nil {
+		return "", fmt.Errorf("failed to create session: %w", err)
+	}
+	return session.ID, nil
+}
+
 // HandleAPILogin handles API authentication for CLI clients
 func (s *Server) HandleAPILogin(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
@@ -173,14 +264,6 @@ func (s *Server) HandleAPILogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	clientIP := getClientIP(r)
-
-	// Check rate limiting
-	if s.isRateLimited(clientIP) {
-		http.Error(w, "Too many login attempts. Please wait 15 minutes.", http.StatusTooManyRequests)
-		return
-	}
-
 	var loginReq struct {
 		Username string `json:"username"`
 		Password string `json:"password"`
@@ -191,28 +274,56 @@ func (s *Server) HandleAPILogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	throttle, err := s.loginThrottle()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Authentication is misconfigured: %v", err), http.StatusInternalServerError)
+		return
+	}
+	clientIP := throttle.ClientIP(r)
+	retryAfter, err := throttle.Check(loginReq.Username, clientIP)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		http.Error(w, "System error: unable to authenticate", http.StatusServiceUnavailable)
+		return
+	}
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+		http.Error(w, fmt.Sprintf("Too many login attempts. Try again in %s.", retryAfterText(retryAfter)), http.StatusTooManyRequests)
+		return
+	}
+
 	if loginReq.Username == "" || loginReq.Password == "" {
-		s.recordLoginAttempt(clientIP)
+		s.recordLoginFailure(throttle, loginReq.Username, clientIP)
 		http.Error(w, "Username and password are required", http.StatusBadRequest)
 		return
 	}
 
-	// Load users and authenticate
-	store, err := users.LoadUsers()
+	// Authenticate with the configured providers (users.yaml, LDAP)
+	providers, err := s.authProviders()
 	if err != nil {
-		http.Error(w, "System error: unable to load user data", http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("Authentication is misconfigured: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	user, err := store.Authenticate(loginReq.Username, loginReq.Password)
-	if err != nil {
-		s.recordLoginAttempt(clientIP)
+	user, err := providers.Authenticate(r.Context(), loginReq.Username, loginReq.Password)
+	if err == nil {
+		user, err = s.applyDirectory(user)
+	}
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		s.recordLoginFailure(throttle, loginReq.Username, clientIP)
 		http.Error(w, "Invalid username or password", http.StatusUnauthorized)
 		return
 	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		http.Error(w, "System error: unable to authenticate", http.StatusServiceUnavailable)
+		return
+	}
 
-	// Clear login attempts on successful authentication
-	s.clearLoginAttempts(clientIP)
+	// Clear the failed logins of the username on successful authentication
+	if err := throttle.Success(loginReq.Username); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+	}
 
 	// Create session
 	session, err := s.sessionManager.CreateSession(user)
@@ -896,6 +1007,8 @@ func (s *Server) HandleOIDCLogin(w http.ResponseWriter, r *http.Request) {
 
 // HandleOIDCCallback handles the OAuth2 callback from Keycloak
 func (s *Server) HandleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), "server")
+
 	if s.oidcAuthenticator == nil || !s.oidcAuthenticator.IsEnabled() {
 		http.Error(w, "OIDC authentication not enabled", http.StatusNotFound)
 		return
@@ -965,10 +1078,15 @@ func (s *Server) HandleOIDCCallback(w http.ResponseWriter, r *http.Request) {
 		username = userInfo.Email
 	}
 
-	user := &users.User{
+	user, err := s.applyDirectory(&users.User{
 		Username: username,
 		Team:     "oidc-users",
 		Role:     determineRole(userInfo.Roles),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "OIDC login of %s rejected: %v\n", username, err)
+		http.Redirect(w, r, "/?error=account_disabled", http.StatusSeeOther)
+		return
 	}
 
 	// Create session
@@ -982,7 +1100,7 @@ func (s *Server) HandleOIDCCallback(w http.ResponseWriter, r *http.Request) {
 	// Set session cookie
 	s.sessionManager.SetSessionCookie(w, session)
 
-	fmt.Printf("OIDC login successful for user: %s (role: %s)\n", username, user.Role)
+	logger.Infof("OIDC login successful for user: %s (role: %s)", username, user.Role)
 
 	// Redirect to callback page with session ID so frontend can store it
 	// IMPORTANT: Trailing slash prevents 301 redirect that strips query params
@@ -1087,10 +1205,15 @@ func (s *Server) HandleOIDCTokenExchange(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Create temporary session for API key generation
-	user := &users.User{
+	user, err := s.applyDirectory(&users.User{
 		Username: username,
 		Team:     "oidc-users",
 		Role:     determineRole(userInfo.Roles),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "OIDC login of %s rejected: %v\n", username, err)
+		http.Error(w, "Account is deactivated or cannot be verified", http.StatusUnauthorized)
+		return
 	}
 
 	session, err := s.sessionManager.CreateSession(user)
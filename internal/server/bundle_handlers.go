@@ -0,0 +1,393 @@
+package server
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/provenance"
+	"innominatus/internal/security"
+	"innominatus/internal/types"
+	"innominatus/internal/users"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// workflowBundleFormat is bumped whenever the bundle layout changes
+const workflowBundleFormat = 1
+
+// maxBundleArtifactBytes caps the artifact files copied into a single bundle
+const maxBundleArtifactBytes = 50 << 20
+
+// WorkflowBundleManifest is manifest.json, the last file of a workflow bundle
+type WorkflowBundleManifest struct {
+	Format           int                         `json:"format"`
+	GeneratedAt      time.Time                   `json:"generated_at"`
+	GeneratedBy      string                      `json:"generated_by"`
+	Server           BuildInfo                   `json:"server"`
+	Execution        *database.WorkflowExecution `json:"execution"`
+	SpecRevision     *BundleSpecRevision         `json:"spec_revision,omitempty"`
+	Replay           string                      `json:"replay,omitempty"` // CLI command that reruns a golden path in a test sandbox
+	Files            []BundleFile                `json:"files"`
+	SkippedArtifacts []string                    `json:"skipped_artifacts,omitempty"` // Over the size limit
+}
+
+// BundleSpecRevision identifies the Score spec included in a bundle
+type BundleSpecRevision struct {
+	Digest          string    `json:"digest"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	ChangedSinceRun bool      `json:"changed_since_run"` // Updated after the run started, so it may differ from what ran
+}
+
+// BundleFile is a file of the bundle with its content digest
+type BundleFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Digest string `json:"digest"`
+}
+
+// workflowBundle is everything gathered for one workflow execution
+type workflowBundle struct {
+	execution        *database.WorkflowExecution
+	workflow         *types.Workflow // nil when the step configuration was not stored
+	parameters       map[string]string
+	parameterSources map[string]types.ParameterSource // Parameters whose values were not stored
+	app              *database.Application            // nil when the application was deleted
+	provenance       *provenanceResponse
+	artifacts        []string
+	generatedBy      string
+	server           BuildInfo
+	now              time.Time
+}
+
+// handleWorkflowBundle exports a workflow execution as a gzipped tar archive holding the
+// resolved workflow definition, parameters, Score spec, step logs, artifacts and
+// provenance, so a failed run can be attached to a ticket and replayed elsewhere
+func (s *Server) handleWorkflowBundle(w http.ResponseWriter, r *http.Request, workflowID int64) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	execution, err := s.workflowExecutor.GetWorkflowExecution(workflowID)
+	if err != nil {
+		if err.Error() == "workflow execution not found" {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get workflow: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	bundle := &workflowBundle{
+		execution:   execution,
+		parameters:  map[string]string{},
+		artifacts:   stepArtifacts(execution.Steps),
+		generatedBy: user.Username,
+		server:      s.buildInfo,
+		now:         time.Now().UTC(),
+	}
+
+	app, ok := s.authorizeWorkflowRun(w, user, execution)
+	if !ok {
+		return
+	}
+	bundle.app = app
+
+	if s.db != nil {
+		if records, err := s.db.ListDeploymentProvenance(execution.ApplicationName); err == nil {
+			if record := runProvenance(records, execution); record != nil {
+				verified := s.verifyProvenance(record)
+				bundle.provenance = &verified
+			}
+		}
+	}
+
+	if s.workflowRepo != nil {
+		if parameters, sources, err := s.workflowRepo.GetWorkflowExecutionParameters(workflowID); err == nil {
+			bundle.parameters = parameters
+			bundle.parameterSources = sources
+		}
+		if reconstructed, err := s.workflowRepo.ReconstructWorkflowFromExecution(workflowID); err == nil {
+			bundle.workflow = workflowFromMap(reconstructed)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"workflow-%d-bundle.tar.gz\"", workflowID))
+	if err := writeWorkflowBundle(w, bundle); err != nil {
+		// Headers are already sent; the client sees a truncated archive
+		logging.FromContext(r.Context(), "server").Warnf("Failed to write bundle for workflow %d: %v", workflowID, err)
+	}
+}
+
+// authorizeWorkflowRun returns the application of a workflow execution. Runs carry logs
+// and specs, so like provenance they are team-scoped and only admins can access runs of
+// deleted applications. Writes 403 and returns false when access is denied.
+func (s *Server) authorizeWorkflowRun(w http.ResponseWriter, user *users.User, execution *database.WorkflowExecution) (*database.Application, bool) {
+	if s.db == nil {
+		return nil, true
+	}
+
+	app, err := s.db.GetApplication(execution.ApplicationName)
+	if err != nil {
+		app = nil
+	}
+	if !user.IsAdmin() && (app == nil || !s.canAccessTeam(user, app.Team)) {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return nil, false
+	}
+	return app, true
+}
+
+// writeWorkflowBundle writes the bundle as a gzipped tar archive below workflow-<id>/.
+// manifest.json comes last because it lists the digests of all other files.
+func writeWorkflowBundle(w io.Writer, b *workflowBundle) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	root := fmt.Sprintf("workflow-%d/", b.execution.ID)
+	execution := *b.execution
+	execution.Steps = nil
+	manifest := WorkflowBundleManifest{
+		Format:      workflowBundleFormat,
+		GeneratedAt: b.now,
+		GeneratedBy: b.generatedBy,
+		Server:      b.server,
+		Execution:   &execution,
+		Files:       []BundleFile{},
+	}
+
+	add := func(name string, data []byte) error {
+		header := &tar.Header{
+			Name:    root + name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: b.now,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		manifest.Files = append(manifest.Files, BundleFile{Path: name, Size: int64(len(data)), Digest: provenance.Digest(data)})
+		return nil
+	}
+	addJSON := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		return add(name, append(data, '\n'))
+	}
+	addYAML := func(name string, v interface{}) error {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		return add(name, data)
+	}
+
+	if b.workflow != nil {
+		if err := addYAML("workflow.yaml", b.workflow); err != nil {
+			return err
+		}
+	}
+
+	if err := addJSON("parameters.json", b.parameters); err != nil {
+		return err
+	}
+	if len(b.parameterSources) > 0 {
+		if err := addJSON("parameter-sources.json", b.parameterSources); err != nil {
+			return err
+		}
+	}
+
+	if b.app != nil && b.app.ScoreSpec != nil {
+		specYAML, err := yaml.Marshal(b.app.ScoreSpec)
+		if err != nil {
+			return fmt.Errorf("failed to marshal spec.yaml: %w", err)
+		}
+		if err := add("spec.yaml", specYAML); err != nil {
+			return err
+		}
+		manifest.SpecRevision = &BundleSpecRevision{
+			Digest:          provenance.Digest(specYAML),
+			UpdatedAt:       b.app.UpdatedAt,
+			ChangedSinceRun: b.app.UpdatedAt.After(b.execution.StartedAt),
+		}
+		manifest.Replay = replayCommand(b.execution.WorkflowName, b.parameters)
+	}
+
+	// Step metadata without logs; each step's log is a separate file
+	steps := make([]database.WorkflowStepExecution, 0, len(b.execution.Steps))
+	for _, step := range b.execution.Steps {
+		steps = append(steps, *step)
+		steps[len(steps)-1].OutputLogs = nil
+	}
+	if err := addJSON("steps.json", steps); err != nil {
+		return err
+	}
+	for _, step := range b.execution.Steps {
+		if step.OutputLogs == nil || *step.OutputLogs == "" {
+			continue
+		}
+		name := fmt.Sprintf("logs/%02d-%s.log", step.StepNumber, bundleFileName(step.StepName))
+		if err := add(name, []byte(*step.OutputLogs)); err != nil {
+			return err
+		}
+	}
+
+	if b.provenance != nil {
+		if err := addJSON("provenance.json", b.provenance); err != nil {
+			return err
+		}
+	}
+
+	var artifactBytes int64
+	for _, artifact := range b.artifacts {
+		err := filepath.WalkDir(artifact, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || !entry.Type().IsRegular() {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			if artifactBytes+info.Size() > maxBundleArtifactBytes {
+				manifest.SkippedArtifacts = append(manifest.SkippedArtifacts, filepath.ToSlash(path))
+				return nil
+			}
+			data, err := os.ReadFile(path) // #nosec G304 - validated by stepArtifacts
+			if err != nil {
+				return nil
+			}
+			artifactBytes += int64(len(data))
+			return add("artifacts/"+filepath.ToSlash(path), data)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := addJSON("manifest.json", manifest); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return gz.Close()
+}
+
+// stepArtifacts returns the output files and directories of the steps that exist below
+// the workflow directories. Paths outside them are never exported.
+func stepArtifacts(steps []*database.WorkflowStepExecution) []string {
+	seen := make(map[string]bool)
+	artifacts := []string{}
+	for _, step := range steps {
+		for _, key := range []string{"OutputFile", "OutputDir"} {
+			path, _ := step.StepConfig[key].(string)
+			if path == "" || seen[path] {
+				continue
+			}
+			seen[path] = true
+			if security.ValidateWorkflowPath(path) != nil {
+				continue
+			}
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			artifacts = append(artifacts, filepath.Clean(path))
+		}
+	}
+	sort.Strings(artifacts)
+	return artifacts
+}
+
+// runProvenance returns the provenance record of the run: the first one recorded after
+// the execution started. records are ordered newest first.
+func runProvenance(records []*database.DeploymentProvenance, execution *database.WorkflowExecution) *database.DeploymentProvenance {
+	var match *database.DeploymentProvenance
+	for _, record := range records {
+		if record.CreatedAt.Before(execution.StartedAt) {
+			break
+		}
+		match = record
+	}
+	return match
+}
+
+// workflowFromMap converts a workflow reconstructed from stored step configs
+func workflowFromMap(reconstructed map[string]interface{}) *types.Workflow {
+	data, err := json.Marshal(reconstructed)
+	if err != nil {
+		return nil
+	}
+	var wf types.Workflow
+	if err := json.Unmarshal(data, &wf); err != nil {
+		return nil
+	}
+	return &wf
+}
+
+var (
+	unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	safeShellArg        = regexp.MustCompile(`^[A-Za-z0-9_./:=@,+-]+$`)
+)
+
+// bundleFileName turns a step name into a file name
+func bundleFileName(name string) string {
+	name = strings.Trim(unsafeFileNameChars.ReplaceAllString(name, "-"), "-")
+	if name == "" {
+		return "step"
+	}
+	return name
+}
+
+// replayCommand returns the CLI command that reruns a golden path execution in a test
+// sandbox with the bundle's spec.yaml and parameters
+func replayCommand(workflowName string, parameters map[string]string) string {
+	goldenPath := strings.TrimPrefix(workflowName, "golden-path-")
+	if goldenPath == workflowName || goldenPath == "" {
+		return ""
+	}
+
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := []string{"innominatus-ctl", "run", shellQuote(goldenPath), "spec.yaml", "--test"}
+	for _, name := range names {
+		args = append(args, "--param", shellQuote(name+"="+parameters[name]))
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote single-quotes arg unless it only contains characters that are safe in a shell
+func shellQuote(arg string) string {
+	if safeShellArg.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
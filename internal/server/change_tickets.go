@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"innominatus/internal/admin"
+	"innominatus/internal/changemgmt"
+	"innominatus/internal/logging"
+	"innominatus/internal/types"
+	"innominatus/internal/workflow"
+)
+
+// changeManager loads the change management policy from admin-config.yaml. It returns
+// nil when there is no admin config or change management is disabled.
+func (s *Server) changeManager() (*changemgmt.Manager, error) {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return nil, nil
+	}
+	return changemgmt.NewManager(adminConfig.ChangeManagement)
+}
+
+// executeGoldenPathWorkflow runs a golden path workflow. When the run has a change ticket,
+// the ticket is closed afterwards with the outcome and a link to the execution.
+func (s *Server) executeGoldenPathWorkflow(ctx context.Context, changes *changemgmt.Manager, ticket *changemgmt.Ticket, appName, workflowName string, wf types.Workflow, parameters map[string]string) error {
+	if ticket == nil {
+		return s.workflowExecutor.ExecuteWorkflowWithContext(ctx, appName, workflowName, wf, parameters)
+	}
+
+	var executionID int64
+	ctx = workflow.WithExecutionStarted(ctx, func(id int64) { executionID = id })
+	err := s.workflowExecutor.ExecuteWorkflowWithContext(ctx, appName, workflowName, wf, parameters)
+	s.closeChangeTicket(ctx, changes, ticket, changemgmt.Outcome{ExecutionID: executionID, Err: err})
+	return err
+}
+
+// closeChangeTicket closes a ticket; failures are logged since the run itself is over
+func (s *Server) closeChangeTicket(ctx context.Context, changes *changemgmt.Manager, ticket *changemgmt.Ticket, outcome changemgmt.Outcome) {
+	logger := logging.FromContext(ctx, "server")
+	if err := changes.Close(ctx, ticket, outcome); err != nil {
+		logger.Warnf("%v", err)
+		return
+	}
+	logger.Infof("Closed change %s", ticket.Number)
+}
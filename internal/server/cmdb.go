@@ -0,0 +1,34 @@
+package server
+
+import (
+	"context"
+	"innominatus/internal/admin"
+	"innominatus/internal/cmdb"
+	"innominatus/internal/database"
+	"innominatus/internal/events"
+)
+
+// cmdbConfig returns the cmdb section of admin-config.yaml
+func (s *Server) cmdbConfig() cmdb.Config {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return cmdb.Config{}
+	}
+	return adminConfig.CMDB
+}
+
+// StartCMDBPublisher posts the resource changes on bus to the CMDB webhooks
+func (s *Server) StartCMDBPublisher(ctx context.Context, bus events.EventBus) {
+	var resource func(id int64) (*database.ResourceInstance, error)
+	if s.resourceManager != nil {
+		resource = s.resourceManager.GetResource
+	}
+	var application func(name string) (*database.Application, error)
+	if s.db != nil {
+		application = s.db.GetApplication
+	}
+
+	publisher := cmdb.NewPublisher(s.cmdbConfig, resource, application)
+	publisher.SetClock(s.Clock())
+	publisher.Start(ctx, bus)
+}
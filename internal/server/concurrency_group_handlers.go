@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"innominatus/internal/goldenpaths"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// goldenPathConcurrency returns the concurrency groups shared by all golden path runs
+func (s *Server) goldenPathConcurrency() *goldenpaths.ConcurrencyGroups {
+	s.concurrencyGroupsOnce.Do(func() {
+		s.concurrencyGroups = goldenpaths.NewConcurrencyGroups(s.Clock().Now)
+	})
+	return s.concurrencyGroups
+}
+
+// goldenPathConcurrencyGroup returns the concurrency group and limit of a golden path run
+func goldenPathConcurrencyGroup(goldenPathName, environment string) (string, int, bool) {
+	config, err := goldenpaths.LoadGoldenPaths()
+	if err != nil {
+		return "", 0, false
+	}
+	return config.ConcurrencyGroup(goldenPathName, environment)
+}
+
+// HandleConcurrencyGroups handles the concurrency groups of golden paths.
+//
+// GET    /api/concurrency-groups            groups with their running and queued runs
+// GET    /api/concurrency-groups/runs/{id}  one run
+// DELETE /api/concurrency-groups/runs/{id}  cancel a queued run
+//
+// Users who are not admins only see runs of teams they can access.
+func (s *Server) HandleConcurrencyGroups(w http.ResponseWriter, r *http.Request) {
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	groups := s.goldenPathConcurrency()
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/concurrency-groups"), "/")
+
+	if path == "" {
+		if r.Method != "GET" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		statuses := groups.Status()
+		for i := range statuses {
+			statuses[i].Running = s.visibleGroupRuns(r, statuses[i].Running)
+			statuses[i].Queued = s.visibleGroupRuns(r, statuses[i].Queued)
+		}
+		statuses = withDeclaredGroups(statuses)
+		writeConcurrencyGroupsJSON(w, http.StatusOK, map[string]interface{}{
+			"groups": statuses,
+			"count":  len(statuses),
+		})
+		return
+	}
+
+	id, ok := strings.CutPrefix(path, "runs/")
+	if !ok || id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	run, found := groups.Get(id)
+	if !found || !s.canAccessTeam(user, run.Team) {
+		http.Error(w, "Run not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeConcurrencyGroupsJSON(w, http.StatusOK, run)
+	case "DELETE":
+		if run.RequestedBy != user.Username && !s.canManageApplication(user, run.Application) {
+			http.Error(w, "Forbidden: only the requester, the owning team or an admin can cancel a run", http.StatusForbidden)
+			return
+		}
+		if err := groups.Cancel(id); err != nil {
+			if errors.Is(err, goldenpaths.ErrRunNotQueued) {
+				http.Error(w, fmt.Sprintf("Run %s already started; cancel its workflow execution instead", id), http.StatusConflict)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		run.State = "cancelled"
+		writeConcurrencyGroupsJSON(w, http.StatusOK, run)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// visibleGroupRuns returns the runs of teams the user can access
+func (s *Server) visibleGroupRuns(r *http.Request, runs []goldenpaths.GroupRun) []goldenpaths.GroupRun {
+	user := s.getUserFromContext(r)
+	visible := make([]goldenpaths.GroupRun, 0, len(runs))
+	for _, run := range runs {
+		if s.canAccessTeam(user, run.Team) {
+			visible = append(visible, run)
+		}
+	}
+	return visible
+}
+
+// withDeclaredGroups adds the groups of goldenpaths.yaml that have no runs yet, and
+// reports the configured limit of every group
+func withDeclaredGroups(statuses []goldenpaths.GroupStatus) []goldenpaths.GroupStatus {
+	config, err := goldenpaths.LoadGoldenPaths()
+	if err != nil {
+		return statuses
+	}
+	seen := make(map[string]bool, len(statuses))
+	for i := range statuses {
+		seen[statuses[i].Name] = true
+		if group, ok := config.ConcurrencyGroups[statuses[i].Name]; ok {
+			statuses[i].MaxParallel = group.MaxParallel
+		}
+	}
+	for name, group := range config.ConcurrencyGroups {
+		if !seen[name] {
+			statuses = append(statuses, goldenpaths.GroupStatus{
+				Name:        name,
+				MaxParallel: group.MaxParallel,
+				Running:     []goldenpaths.GroupRun{},
+				Queued:      []goldenpaths.GroupRun{},
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// writeConcurrencyGroupsJSON writes body as JSON with status
+func writeConcurrencyGroupsJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
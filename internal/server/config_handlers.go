@@ -0,0 +1,44 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/config"
+	"net/http"
+	"os"
+)
+
+// EffectiveConfigResponse lists the resolved server settings and where each came from
+type EffectiveConfigResponse struct {
+	ConfigFile string           `json:"config_file,omitempty"`
+	Precedence []string         `json:"precedence"` // Lowest to highest
+	Settings   []config.Setting `json:"settings"`
+}
+
+// SetEffectiveConfig sets the resolved server settings reported by /api/admin/effective-config
+func (s *Server) SetEffectiveConfig(cfg *config.Config) {
+	s.effectiveConfig = cfg
+}
+
+// HandleEffectiveConfig handles GET /api/admin/effective-config. Admin only; secrets are masked.
+func (s *Server) HandleEffectiveConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.effectiveConfig == nil {
+		http.Error(w, "Effective configuration not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	response := EffectiveConfigResponse{
+		ConfigFile: s.effectiveConfig.File(),
+		Precedence: []string{config.SourceDefault, config.SourceFile, config.SourceEnv, config.SourceFlag},
+		Settings:   s.effectiveConfig.Settings(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/clock"
+	"net/http"
+	"os"
+	"time"
+)
+
+// timerLister is implemented by clocks that track their pending timers (the fake clock)
+type timerLister interface {
+	Timers() []clock.TimerInfo
+}
+
+// HandleDebugClock inspects and, with a fake clock, moves server time (admin only).
+//
+// GET  /api/admin/debug/clock                              current time and pending timers
+// POST /api/admin/debug/clock {"advance": "90m"}           move a fake clock forward
+// POST /api/admin/debug/clock {"set": "2025-01-01T03:00:00Z"} jump a fake clock to a time
+func (s *Server) HandleDebugClock(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+	case "POST":
+		fake, ok := s.Clock().(*clock.Fake)
+		if !ok {
+			http.Error(w, "Time travel requires the fake clock (start the server with INNOMINATUS_FAKE_CLOCK)", http.StatusConflict)
+			return
+		}
+
+		var req struct {
+			Advance string `json:"advance"`
+			Set     string `json:"set"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case req.Advance != "" && req.Set != "":
+			http.Error(w, "Specify either 'advance' or 'set', not both", http.StatusBadRequest)
+			return
+		case req.Advance != "":
+			d, err := time.ParseDuration(req.Advance)
+			if err != nil || d < 0 {
+				http.Error(w, fmt.Sprintf("Invalid advance duration: %s", req.Advance), http.StatusBadRequest)
+				return
+			}
+			fake.Advance(d)
+		case req.Set != "":
+			t, err := time.Parse(time.RFC3339, req.Set)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Invalid time (use RFC3339): %v", err), http.StatusBadRequest)
+				return
+			}
+			fake.Set(t)
+		default:
+			http.Error(w, "Specify 'advance' or 'set'", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	c := s.Clock()
+	_, fake := c.(*clock.Fake)
+	timers := []clock.TimerInfo{}
+	if lister, ok := c.(timerLister); ok {
+		timers = lister.Timers()
+	}
+
+	response := map[string]interface{}{
+		"now":        c.Now(),
+		"wall_clock": time.Now(),
+		"fake":       fake,
+		"timers":     timers,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/workflow"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// openDebugSessionRequest is the body of POST /api/workflows/{id}/steps/{stepID}/debug
+type openDebugSessionRequest struct {
+	Duration string `json:"duration,omitempty"` // Go duration such as "2h"; defaults to workflow.DefaultDebugSessionDuration
+}
+
+// handleStepDebug serves the debug session of a failed step:
+//
+//	GET    /api/workflows/{id}/steps/{stepID}/debug            retained workspace and session
+//	POST   /api/workflows/{id}/steps/{stepID}/debug            open or extend the session
+//	DELETE /api/workflows/{id}/steps/{stepID}/debug            close the session and remove the workspace
+//	GET    /api/workflows/{id}/steps/{stepID}/debug/workspace  download the workspace as tar.gz
+func (s *Server) handleStepDebug(w http.ResponseWriter, r *http.Request, workflowID int64, subPath string) {
+	var stepID int64
+	var action string
+	if _, err := fmt.Sscanf(subPath, "%d/", &stepID); err != nil {
+		http.Error(w, "Invalid step ID", http.StatusBadRequest)
+		return
+	}
+	switch {
+	case strings.HasSuffix(subPath, "/debug"):
+		action = "session"
+	case strings.HasSuffix(subPath, "/debug/workspace"):
+		action = "workspace"
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	workspaces := s.workflowExecutor.DebugWorkspaces()
+	if workspaces == nil {
+		http.Error(w, "Debug sessions are not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	execution, err := s.workflowExecutor.GetWorkflowExecution(workflowID)
+	if err != nil {
+		if err.Error() == "workflow execution not found" {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get workflow: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if _, ok := s.authorizeWorkflowRun(w, user, execution); !ok {
+		return
+	}
+	if !failedStep(execution, stepID) {
+		http.Error(w, "Debug sessions are only available for failed steps", http.StatusNotFound)
+		return
+	}
+
+	if action == "workspace" {
+		if r.Method != "GET" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleDebugWorkspaceDownload(w, r, workspaces, workflowID, stepID)
+		return
+	}
+
+	var workspace *workflow.DebugWorkspace
+	status := http.StatusOK
+	switch r.Method {
+	case "GET":
+		workspace, err = workspaces.Get(workflowID, stepID)
+	case "POST":
+		var req openDebugSessionRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		var duration time.Duration
+		if req.Duration != "" {
+			if duration, err = time.ParseDuration(req.Duration); err != nil || duration <= 0 {
+				http.Error(w, fmt.Sprintf("Invalid duration %q", req.Duration), http.StatusBadRequest)
+				return
+			}
+			if duration > workflow.MaxDebugSessionDuration {
+				http.Error(w, fmt.Sprintf("Debug sessions last at most %s", workflow.MaxDebugSessionDuration), http.StatusBadRequest)
+				return
+			}
+		}
+		workspace, err = workspaces.Open(workflowID, stepID, user.Username, duration)
+		status = http.StatusCreated
+	case "DELETE":
+		if err = workspaces.Close(workflowID, stepID); err == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if errors.Is(err, workflow.ErrDebugWorkspaceNotFound) {
+		http.Error(w, "No workspace retained for this step; it may have expired", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to access debug workspace: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(workspace); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+func (s *Server) handleDebugWorkspaceDownload(w http.ResponseWriter, r *http.Request, workspaces *workflow.DebugWorkspaces, workflowID, stepID int64) {
+	workspace, err := workspaces.Get(workflowID, stepID)
+	if errors.Is(err, workflow.ErrDebugWorkspaceNotFound) {
+		http.Error(w, "No workspace retained for this step; it may have expired", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to access debug workspace: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if !workspace.Session.Open(time.Now()) {
+		http.Error(w, "Open a debug session before downloading the workspace", http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"workflow-%d-step-%d-workspace.tar.gz\"", workflowID, stepID))
+	if err := workspaces.WriteArchive(w, workflowID, stepID); err != nil {
+		// Headers are already sent; the client sees a truncated archive
+		logging.FromContext(r.Context(), "server").Warnf("Failed to write workspace of workflow %d step %d: %v", workflowID, stepID, err)
+	}
+}
+
+// failedStep reports whether the execution has a failed step with the given ID
+func failedStep(execution *database.WorkflowExecution, stepID int64) bool {
+	for _, step := range execution.Steps {
+		if step.ID == stepID {
+			return step.Status == database.StepStatusFailed
+		}
+	}
+	return false
+}
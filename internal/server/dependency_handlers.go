@@ -0,0 +1,212 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/logging"
+	"innominatus/internal/orchestration"
+	"innominatus/internal/types"
+	"net/http"
+	"os"
+	"sort"
+
+	sdk "github.com/philipsahli/innominatus-graph/pkg/graph"
+)
+
+// ApplicationDependencyNode is an application in the cross-application dependency graph
+type ApplicationDependencyNode struct {
+	Application string `json:"application"`
+	Deployed    bool   `json:"deployed"`
+	Ready       bool   `json:"ready"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// ApplicationDependencyEdge points from a downstream application to an upstream it depends on
+type ApplicationDependencyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// ApplicationDependenciesResponse is the /api/graph/<app>/dependencies response
+type ApplicationDependenciesResponse struct {
+	Application     string                         `json:"application"`
+	Upstream        []orchestration.UpstreamStatus `json:"upstream"`
+	Downstream      []string                       `json:"downstream"`
+	DeploymentOrder []string                       `json:"deployment_order"`
+	Nodes           []ApplicationDependencyNode    `json:"nodes"`
+	Edges           []ApplicationDependencyEdge    `json:"edges"`
+}
+
+// upstreamChecker checks dependsOn upstreams against stored applications and their resources
+func (s *Server) upstreamChecker() *orchestration.UpstreamChecker {
+	if repo := s.GetResourceRepository(); repo != nil {
+		return orchestration.NewUpstreamChecker(s.db, repo)
+	}
+	return orchestration.NewUpstreamChecker(s.db, nil)
+}
+
+// validateApplicationDependencies checks the dependsOn names of a spec and rejects specs
+// that would close a dependency cycle with the applications already stored
+func (s *Server) validateApplicationDependencies(spec *types.ScoreSpec) error {
+	if spec == nil || len(spec.DependsOn) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(spec.DependsOn))
+	for _, upstream := range spec.DependsOn {
+		if err := s.validateDNSLabel(upstream); err != nil {
+			return fmt.Errorf("dependsOn: %w", err)
+		}
+		if upstream == spec.Metadata.Name {
+			return fmt.Errorf("dependsOn: application '%s' cannot depend on itself", upstream)
+		}
+		if seen[upstream] {
+			return fmt.Errorf("dependsOn: duplicate application '%s'", upstream)
+		}
+		seen[upstream] = true
+	}
+
+	if s.db == nil {
+		return nil
+	}
+	apps, err := s.db.ListApplications()
+	if err != nil {
+		return fmt.Errorf("failed to list applications: %w", err)
+	}
+	deps := orchestration.ApplicationDependencies(apps)
+	deps[spec.Metadata.Name] = spec.DependsOn
+	if _, err := orchestration.DeploymentOrder(deps); err != nil {
+		return err
+	}
+	return nil
+}
+
+// upsertDependencyGraphEdges adds the upstream applications of a spec to its graph with
+// depends-on edges from the application node
+func (s *Server) upsertDependencyGraphEdges(appName string, spec *types.ScoreSpec) {
+	logger := logging.NewStructuredLogger("server")
+
+	appNodeID := fmt.Sprintf("app:%s", appName)
+	for _, upstream := range spec.DependsOn {
+		upstreamNodeID := fmt.Sprintf("app:%s", upstream)
+		upstreamNode := &sdk.Node{
+			ID:    upstreamNodeID,
+			Type:  sdk.NodeTypeApplication,
+			Name:  upstream,
+			State: sdk.NodeStateSucceeded,
+			Properties: map[string]interface{}{
+				"app_name": upstream,
+				"upstream": true,
+			},
+		}
+		if err := s.upsertGraphNode(appName, upstreamNode); err != nil {
+			logger.Warnf("Failed to upsert upstream application node to graph: %v", err)
+			continue
+		}
+
+		edge := &sdk.Edge{
+			ID:         fmt.Sprintf("app-depends-on:%s:%s", appName, upstream),
+			FromNodeID: appNodeID,
+			ToNodeID:   upstreamNodeID,
+			Type:       sdk.EdgeTypeDependsOn,
+			Properties: map[string]interface{}{
+				"relationship": "application_depends_on_application",
+			},
+		}
+		if err := s.upsertGraphEdge(appName, edge); err != nil {
+			logger.Warnf("Failed to upsert app→upstream edge to graph: %v", err)
+		}
+	}
+}
+
+// handleApplicationDependencies handles /api/graph/<app>/dependencies requests. It returns
+// the dependency subgraph around an application: its upstreams with readiness, the
+// applications depending on it, and the order in which they deploy.
+func (s *Server) handleApplicationDependencies(w http.ResponseWriter, r *http.Request, appName string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.db == nil {
+		http.Error(w, "Application dependencies require a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	apps, err := s.db.ListApplications()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list applications: %v", err), http.StatusInternalServerError)
+		return
+	}
+	deps := orchestration.ApplicationDependencies(apps)
+	if _, ok := deps[appName]; !ok {
+		http.Error(w, fmt.Sprintf("Application '%s' not found", appName), http.StatusNotFound)
+		return
+	}
+
+	// Collect every application connected to appName in either direction
+	component := map[string]bool{appName: true}
+	queue := []string{appName}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		neighbours := append(append([]string{}, deps[name]...), orchestration.Downstreams(deps, name)...)
+		for _, neighbour := range neighbours {
+			if !component[neighbour] {
+				component[neighbour] = true
+				queue = append(queue, neighbour)
+			}
+		}
+	}
+
+	subgraph := make(map[string][]string, len(component))
+	for name := range component {
+		subgraph[name] = deps[name]
+	}
+	order, err := orchestration.DeploymentOrder(subgraph)
+	if err != nil {
+		// Cycles are rejected on deploy; stored specs from before that check may still have one
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	checker := s.upstreamChecker()
+	response := ApplicationDependenciesResponse{
+		Application:     appName,
+		Upstream:        checker.Check(deps[appName]),
+		Downstream:      orchestration.Downstreams(deps, appName),
+		DeploymentOrder: order,
+		Nodes:           []ApplicationDependencyNode{},
+		Edges:           []ApplicationDependencyEdge{},
+	}
+	if response.Downstream == nil {
+		response.Downstream = []string{}
+	}
+
+	for _, name := range order {
+		_, deployed := deps[name]
+		status := checker.Check([]string{name})[0]
+		response.Nodes = append(response.Nodes, ApplicationDependencyNode{
+			Application: name,
+			Deployed:    deployed,
+			Ready:       status.Ready,
+			Reason:      status.Reason,
+		})
+
+		upstreams := append([]string{}, deps[name]...)
+		sort.Strings(upstreams)
+		for _, upstream := range upstreams {
+			response.Edges = append(response.Edges, ApplicationDependencyEdge{
+				From: name,
+				To:   upstream,
+				Type: string(sdk.EdgeTypeDependsOn),
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
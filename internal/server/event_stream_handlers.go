@@ -0,0 +1,82 @@
+package server
+
+import (
+	"fmt"
+	"innominatus/internal/events"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// teamAppCacheTTL bounds how long an application's team ownership is cached per stream
+const teamAppCacheTTL = 30 * time.Second
+
+// HandleEventStream streams events over SSE. Admins see all events; other users only
+// receive events of their team's applications. Filters: app, topics, types, workflow_id,
+// resource_id (see events.ParseFilter).
+func (s *Server) HandleEventStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	broker := s.GetSSEBroker()
+	if broker == nil {
+		http.Error(w, "Event streaming not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filter, err := events.ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !user.IsAdmin() {
+		authorize := s.teamAppAuthorizer(user.Team)
+		for _, app := range filter.Apps {
+			if !authorize(app) {
+				http.Error(w, fmt.Sprintf("Access denied to events of application '%s'", app), http.StatusForbidden)
+				return
+			}
+		}
+		filter.Authorize = authorize
+	}
+
+	broker.Serve(w, r, filter)
+}
+
+// teamAppAuthorizer returns a check whether an application belongs to team. Results are
+// cached briefly because it runs for every event delivered to the stream.
+func (s *Server) teamAppAuthorizer(team string) func(appName string) bool {
+	type cached struct {
+		allowed bool
+		at      time.Time
+	}
+	var mu sync.Mutex
+	cache := make(map[string]cached)
+
+	return func(appName string) bool {
+		// Events without an application are platform-wide and not team-scoped
+		if appName == "" || s.db == nil {
+			return false
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if entry, ok := cache[appName]; ok && time.Since(entry.at) < teamAppCacheTTL {
+			return entry.allowed
+		}
+
+		app, err := s.db.GetApplication(appName)
+		allowed := err == nil && app.Team == team
+		cache[appName] = cached{allowed: allowed, at: time.Now()}
+		return allowed
+	}
+}
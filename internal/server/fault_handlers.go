@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/faults"
+	"innominatus/internal/logging"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// SetFaultInjector enables the fault injection admin API and injects its faults into
+// workflow steps. The orchestration engine takes the same injector for provisioner faults.
+func (s *Server) SetFaultInjector(injector *faults.Injector) {
+	s.faultInjector = injector
+	if s.workflowExecutor != nil {
+		s.workflowExecutor.SetFaultInjector(injector)
+	}
+}
+
+// HandleFaults manages injected faults (admin only).
+//
+// GET    /api/admin/faults        active faults
+// POST   /api/admin/faults        add a fault
+// DELETE /api/admin/faults        remove all faults
+// DELETE /api/admin/faults/{id}   remove one fault
+func (s *Server) HandleFaults(w http.ResponseWriter, r *http.Request) {
+	if s.faultInjector == nil {
+		http.Error(w, "Fault injection is disabled (start the server with INNOMINATUS_FAULT_INJECTION=true outside production)", http.StatusNotFound)
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/faults"), "/")
+	logger := logging.FromContext(r.Context(), "server")
+
+	switch {
+	case r.Method == "GET" && id == "":
+		writeFaultsJSON(w, http.StatusOK, map[string]interface{}{"faults": s.faultInjector.List()})
+
+	case r.Method == "POST" && id == "":
+		var fault faults.Fault
+		if err := json.NewDecoder(r.Body).Decode(&fault); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		if user := s.getUserFromContext(r); user != nil {
+			fault.CreatedBy = user.Username
+		}
+		added, err := s.faultInjector.Add(fault)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.WarnWithFields("Fault injection enabled", map[string]interface{}{
+			"fault_id": added.ID,
+			"scope":    string(added.Scope),
+			"kind":     string(added.Kind),
+		})
+		writeFaultsJSON(w, http.StatusCreated, added)
+
+	case r.Method == "DELETE" && id == "":
+		s.faultInjector.Clear()
+		logger.Info("All injected faults removed")
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "DELETE":
+		if !s.faultInjector.Remove(id) {
+			http.Error(w, fmt.Sprintf("Fault '%s' not found", id), http.StatusNotFound)
+			return
+		}
+		logger.InfoWithFields("Injected fault removed", map[string]interface{}{"fault_id": id})
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeFaultsJSON writes body as JSON with status
+func writeFaultsJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/giteateams"
+	"innominatus/internal/logging"
+	"innominatus/internal/teams"
+	"innominatus/internal/users"
+	"net/http"
+	"os"
+	"sort"
+)
+
+// giteaTeamProvisioner returns the provisioner of team organizations in Gitea; nil when
+// gitea.teamProvisioning is disabled or Gitea is not configured
+func (s *Server) giteaTeamProvisioner() *giteateams.Provisioner {
+	if s.giteaTeams != nil {
+		return s.giteaTeams
+	}
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil || adminConfig.Gitea.URL == "" || !adminConfig.Gitea.TeamProvisioning.Enabled {
+		return nil
+	}
+	return giteateams.NewProvisioner(adminConfig.Gitea.TeamProvisioning, adminConfig.Gitea.URL, adminConfig.Gitea.Username, adminConfig.Gitea.Password)
+}
+
+// giteaTeamConfig returns gitea.teamProvisioning of admin-config.yaml
+func (s *Server) giteaTeamConfig() giteateams.Config {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return giteateams.Config{}
+	}
+	return adminConfig.Gitea.TeamProvisioning
+}
+
+// giteaTeamMembers collects the innominatus teams with their members from users.yaml,
+// the team manager and, with SCIM, the directory. Members of users.yaml keep their role;
+// all others have the role user.
+func (s *Server) giteaTeamMembers() []giteateams.Team {
+	byID := map[string]*giteateams.Team{}
+	team := func(id, name string) *giteateams.Team {
+		if t, ok := byID[id]; ok {
+			return t
+		}
+		t := &giteateams.Team{ID: id, Name: name}
+		byID[id] = t
+		return t
+	}
+	add := func(t *giteateams.Team, member giteateams.Member) {
+		for _, m := range t.Members {
+			if m.Username == member.Username {
+				return
+			}
+		}
+		t.Members = append(t.Members, member)
+	}
+
+	if store, err := users.LoadUsers(); err == nil {
+		for _, u := range store.Users {
+			if u.Team != "" {
+				add(team(u.Team, u.Team), giteateams.Member{Username: u.Username, Role: u.Role})
+			}
+		}
+	}
+	if s.teamManager != nil {
+		for _, tm := range s.teamManager.ListTeams() {
+			t := team(tm.ID, tm.Name)
+			t.Name, t.Description = tm.Name, tm.Description
+			for _, member := range tm.Members {
+				add(t, giteateams.Member{Username: member, Role: "user"})
+			}
+		}
+	}
+	if store := s.scimStore(); store != nil && s.scimConfig().Enabled {
+		if directoryTeams, _, err := store.ListDirectoryTeams("", 0, 1000); err == nil {
+			for _, dt := range directoryTeams {
+				t := team(dt.Name, dt.Name)
+				for _, member := range dt.Members {
+					add(t, giteateams.Member{Username: member.Username, Role: "user"})
+				}
+			}
+		}
+	}
+
+	result := make([]giteateams.Team, 0, len(byID))
+	for _, t := range byID {
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// provisionGiteaTeam creates the Gitea organization of a new team in the background, so
+// a slow or unavailable Gitea does not fail team creation; the periodic sync retries
+func (s *Server) provisionGiteaTeam(created *teams.Team) {
+	provisioner := s.giteaTeamProvisioner()
+	if provisioner == nil {
+		return
+	}
+	go func() {
+		for _, t := range s.giteaTeamMembers() {
+			if t.ID == created.ID {
+				logGiteaTeamResult(provisioner.Sync(t))
+				return
+			}
+		}
+	}()
+}
+
+// StartGiteaTeamSync syncs the Gitea organizations of all teams at the configured interval
+func (s *Server) StartGiteaTeamSync(ctx context.Context) {
+	logger := logging.NewStructuredLogger("server")
+	if s.giteaTeamProvisioner() == nil {
+		return
+	}
+	interval, err := s.giteaTeamConfig().Interval()
+	if err != nil {
+		logger.Warnf("Gitea team provisioning disabled: %v", err)
+		return
+	}
+
+	go func() {
+		ticker := s.Clock().NewTicker("gitea-team-sync", interval)
+		defer ticker.Stop()
+
+		for {
+			// The provisioner is created per run, so configuration reloads apply
+			if provisioner := s.giteaTeamProvisioner(); provisioner != nil {
+				for _, result := range provisioner.SyncAll(s.giteaTeamMembers()) {
+					logGiteaTeamResult(result)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+		}
+	}()
+}
+
+// logGiteaTeamResult logs the changes and failures of a team sync
+func logGiteaTeamResult(result giteateams.Result) {
+	logger := logging.NewStructuredLogger("server")
+	fields := map[string]interface{}{"team": result.Team, "organization": result.Organization}
+	if result.Error != "" {
+		fields["error"] = result.Error
+		logger.WarnWithFields("Gitea team sync failed", fields)
+		return
+	}
+	for _, warning := range result.Warnings {
+		logger.WarnWithFields("Gitea team sync: "+warning, fields)
+	}
+	if result.CreatedOrg || len(result.CreatedTeams) > 0 || len(result.UpdatedTeams) > 0 || len(result.AddedMembers) > 0 || len(result.RemovedMembers) > 0 {
+		fields["created_org"] = result.CreatedOrg
+		fields["created_teams"] = result.CreatedTeams
+		fields["updated_teams"] = result.UpdatedTeams
+		fields["added_members"] = result.AddedMembers
+		fields["removed_members"] = result.RemovedMembers
+		logger.InfoWithFields("Synced Gitea organization of team", fields)
+	}
+}
+
+// HandleGiteaTeamSync syncs the Gitea organizations of all teams, or of ?team=<id>, now.
+//
+// POST /api/admin/gitea/team-sync
+func (s *Server) HandleGiteaTeamSync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	provisioner := s.giteaTeamProvisioner()
+	if provisioner == nil {
+		http.Error(w, "Gitea team provisioning is not enabled (gitea.teamProvisioning.enabled)", http.StatusNotFound)
+		return
+	}
+
+	all := s.giteaTeamMembers()
+	selected := all
+	if id := r.URL.Query().Get("team"); id != "" {
+		selected = nil
+		for _, t := range all {
+			if t.ID == id {
+				selected = append(selected, t)
+			}
+		}
+		if len(selected) == 0 {
+			http.Error(w, fmt.Sprintf("Team '%s' not found", id), http.StatusNotFound)
+			return
+		}
+	}
+
+	results := provisioner.SyncAll(selected)
+	failed := 0
+	for _, result := range results {
+		logGiteaTeamResult(result)
+		if result.Error != "" {
+			failed++
+		}
+	}
+
+	status := http.StatusOK
+	if failed > 0 {
+		status = http.StatusBadGateway
+	}
+	writeGiteaTeamsJSON(w, status, map[string]interface{}{
+		"results": results,
+		"synced":  len(results) - failed,
+		"failed":  failed,
+	})
+}
+
+// giteaRepoOwner returns the organization of the team owning an application when team
+// provisioning is enabled, so workflow repositories land where the team has access
+func (s *Server) giteaRepoOwner(appName, fallback string) string {
+	config := s.giteaTeamConfig()
+	if !config.Enabled || s.db == nil {
+		return fallback
+	}
+	app, err := s.db.GetApplication(appName)
+	if err != nil || app.Team == "" {
+		return fallback
+	}
+	return config.OrgName(app.Team)
+}
+
+// writeGiteaTeamsJSON writes body as JSON with status
+func writeGiteaTeamsJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,414 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/goldenpaths"
+	"innominatus/internal/logging"
+	"innominatus/internal/queue"
+	"innominatus/internal/types"
+	"innominatus/internal/users"
+	"innominatus/internal/workflow"
+	"net/http"
+	"sort"
+)
+
+// migrateRequest is the body of POST /api/golden-paths/{name}/migrate
+type migrateRequest struct {
+	Applications []string `json:"applications"` // Applications to migrate; all applications of the path when empty
+	Team         string   `json:"team"`         // Only migrate applications of this team
+	DryRun       bool     `json:"dry_run"`      // Report the successor parameters without running anything
+}
+
+// migrationPlan is the planned migration of one application
+type migrationPlan struct {
+	ApplicationName string            `json:"application_name"`
+	Team            string            `json:"team"`
+	Parameters      map[string]string `json:"parameters"`
+	Error           string            `json:"error,omitempty"`
+	MigrationID     int64             `json:"migration_id,omitempty"`
+}
+
+// teamMigrationProgress summarizes the migration away from a golden path for one team
+type teamMigrationProgress struct {
+	Team      string `json:"team"`
+	Remaining int    `json:"remaining"` // Applications still deployed with the deprecated path
+	Pending   int    `json:"pending"`
+	Running   int    `json:"running"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+}
+
+// goldenPathDeprecation returns the deprecation of a golden path in goldenpaths.yaml, or
+// nil if it is not deprecated or the golden paths cannot be loaded
+func goldenPathDeprecation(goldenPathName string) *goldenpaths.Deprecation {
+	config, err := goldenpaths.LoadGoldenPaths()
+	if err != nil {
+		return nil
+	}
+	return config.GetDeprecation(goldenPathName)
+}
+
+// handleGoldenPathApplications handles GET /api/golden-paths/{name}/applications: the
+// applications whose latest successful golden path run used the path. Users who are
+// not admins only see applications of teams they can access.
+func (s *Server) handleGoldenPathApplications(w http.ResponseWriter, r *http.Request, goldenPathName string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	applications, err := s.accessibleGoldenPathApplications(user, goldenPathName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list applications: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"golden_path":  goldenPathName,
+		"applications": applications,
+	}
+	if deprecation := goldenPathDeprecation(goldenPathName); deprecation != nil {
+		response["deprecation"] = deprecation
+	}
+	writeScheduleJSON(w, http.StatusOK, response)
+}
+
+// handleGoldenPathMigrate handles POST /api/golden-paths/{name}/migrate. Every selected
+// application of the deprecated path is re-run through the successor path with its
+// stored Score spec and the parameters of its last run, translated by the deprecation's
+// parameter map. Each application's migration is recorded and tracked separately.
+func (s *Server) handleGoldenPathMigrate(w http.ResponseWriter, r *http.Request, goldenPathName string) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.db == nil || s.workflowExecutor == nil {
+		http.Error(w, "Golden path migration requires a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req migrateRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	deprecation := goldenPathDeprecation(goldenPathName)
+	if deprecation == nil {
+		http.Error(w, fmt.Sprintf("Golden path '%s' is not deprecated", goldenPathName), http.StatusBadRequest)
+		return
+	}
+	if deprecation.Successor == "" {
+		http.Error(w, fmt.Sprintf("Golden path '%s' has no successor to migrate to", goldenPathName), http.StatusBadRequest)
+		return
+	}
+	successor, _, err := loadGoldenPathWorkflow(deprecation.Successor)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load successor: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	applications, err := s.accessibleGoldenPathApplications(user, goldenPathName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list applications: %v", err), http.StatusInternalServerError)
+		return
+	}
+	applications = selectMigrationApplications(applications, req)
+	if len(req.Applications) > 0 && len(applications) != len(req.Applications) {
+		http.Error(w, fmt.Sprintf("Not every application is deployed with golden path '%s' or accessible to you", goldenPathName), http.StatusBadRequest)
+		return
+	}
+
+	// Variables of the deprecated workflow were recorded with its parameters; they are
+	// not parameters of the successor
+	var deprecatedVariables map[string]string
+	if deprecated, _, err := loadGoldenPathWorkflow(goldenPathName); err == nil {
+		deprecatedVariables = deprecated.Spec.Variables
+	}
+
+	ctx := r.Context()
+	logger := logging.FromContext(ctx, "server")
+
+	plans := make([]*migrationPlan, 0, len(applications))
+	for _, app := range applications {
+		parameters := make(map[string]string, len(app.Parameters))
+		for name, value := range app.Parameters {
+			if _, isVariable := deprecatedVariables[name]; !isVariable {
+				parameters[name] = value
+			}
+		}
+		plan := &migrationPlan{
+			ApplicationName: app.ApplicationName,
+			Team:            app.Team,
+			Parameters:      deprecation.MapParameters(parameters),
+		}
+		if withDefaults, err := workflow.ApplyInputs(successor.Spec.Inputs, plan.Parameters); err != nil {
+			plan.Error = err.Error()
+		} else {
+			plan.Parameters = withDefaults
+		}
+		plans = append(plans, plan)
+	}
+
+	if !req.DryRun {
+		for _, plan := range plans {
+			migration := &database.GoldenPathMigration{
+				GoldenPath:      goldenPathName,
+				Successor:       deprecation.Successor,
+				ApplicationName: plan.ApplicationName,
+				Team:            plan.Team,
+				Parameters:      plan.Parameters,
+				StartedBy:       user.Username,
+			}
+			if plan.Error != "" {
+				migration.Status = database.MigrationStatusFailed
+			}
+			if err := s.db.CreateGoldenPathMigration(migration); err != nil {
+				http.Error(w, fmt.Sprintf("Failed to record migration: %v", err), http.StatusInternalServerError)
+				return
+			}
+			plan.MigrationID = migration.ID
+			if plan.Error != "" {
+				_ = s.db.UpdateGoldenPathMigration(migration.ID, database.MigrationStatusFailed, 0, &plan.Error)
+				continue
+			}
+			if err := s.startGoldenPathMigration(ctx, migration, successor, user); err != nil {
+				plan.Error = err.Error()
+				_ = s.db.UpdateGoldenPathMigration(migration.ID, database.MigrationStatusFailed, 0, &plan.Error)
+			}
+		}
+		logger.Infof("User %s started migration of %d application(s) from golden path '%s' to '%s'",
+			user.Username, len(plans), goldenPathName, deprecation.Successor)
+	}
+
+	status := http.StatusAccepted
+	if req.DryRun {
+		status = http.StatusOK
+	}
+	writeScheduleJSON(w, status, map[string]interface{}{
+		"golden_path":  goldenPathName,
+		"successor":    deprecation.Successor,
+		"dry_run":      req.DryRun,
+		"applications": plans,
+	})
+}
+
+// handleGoldenPathMigrations handles GET /api/golden-paths/{name}/migrations: the
+// migrations away from a golden path with the progress of every team, optionally
+// filtered by ?team=
+func (s *Server) handleGoldenPathMigrations(w http.ResponseWriter, r *http.Request, goldenPathName string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	team := r.URL.Query().Get("team")
+	if team != "" && !s.canAccessTeam(user, team) {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+
+	all, err := s.db.ListGoldenPathMigrations(goldenPathName, team)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list migrations: %v", err), http.StatusInternalServerError)
+		return
+	}
+	remaining, err := s.accessibleGoldenPathApplications(user, goldenPathName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list applications: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	progress := make(map[string]*teamMigrationProgress)
+	teamProgress := func(team string) *teamMigrationProgress {
+		if progress[team] == nil {
+			progress[team] = &teamMigrationProgress{Team: team}
+		}
+		return progress[team]
+	}
+
+	migrations := []*database.GoldenPathMigration{}
+	counted := make(map[string]bool)
+	for _, m := range all {
+		if !s.canAccessTeam(user, m.Team) {
+			continue
+		}
+		migrations = append(migrations, m)
+
+		// Retried applications count once, with their latest migration
+		if counted[m.ApplicationName] {
+			continue
+		}
+		counted[m.ApplicationName] = true
+		p := teamProgress(m.Team)
+		switch m.Status {
+		case database.MigrationStatusPending:
+			p.Pending++
+		case database.MigrationStatusRunning:
+			p.Running++
+		case database.MigrationStatusSucceeded:
+			p.Succeeded++
+		case database.MigrationStatusFailed:
+			p.Failed++
+		}
+	}
+	for _, app := range remaining {
+		if team == "" || app.Team == team {
+			teamProgress(app.Team).Remaining++
+		}
+	}
+
+	teams := make([]*teamMigrationProgress, 0, len(progress))
+	for _, p := range progress {
+		teams = append(teams, p)
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].Team < teams[j].Team })
+
+	response := map[string]interface{}{
+		"golden_path": goldenPathName,
+		"teams":       teams,
+		"migrations":  migrations,
+	}
+	if deprecation := goldenPathDeprecation(goldenPathName); deprecation != nil {
+		response["successor"] = deprecation.Successor
+	}
+	writeScheduleJSON(w, http.StatusOK, response)
+}
+
+// accessibleGoldenPathApplications lists the applications of a golden path the user can access
+func (s *Server) accessibleGoldenPathApplications(user *users.User, goldenPathName string) ([]*database.GoldenPathApplication, error) {
+	applications, err := s.db.ListGoldenPathApplications(goldenPathName)
+	if err != nil {
+		return nil, err
+	}
+	accessible := make([]*database.GoldenPathApplication, 0, len(applications))
+	for _, app := range applications {
+		if s.canAccessTeam(user, app.Team) {
+			accessible = append(accessible, app)
+		}
+	}
+	return accessible, nil
+}
+
+// selectMigrationApplications filters applications by the names and team of a migrate request
+func selectMigrationApplications(applications []*database.GoldenPathApplication, req migrateRequest) []*database.GoldenPathApplication {
+	names := make(map[string]bool, len(req.Applications))
+	for _, name := range req.Applications {
+		names[name] = true
+	}
+	selected := make([]*database.GoldenPathApplication, 0, len(applications))
+	for _, app := range applications {
+		if len(names) > 0 && !names[app.ApplicationName] {
+			continue
+		}
+		if req.Team != "" && app.Team != req.Team {
+			continue
+		}
+		selected = append(selected, app)
+	}
+	return selected
+}
+
+// startGoldenPathMigration runs the successor golden path for the application of a
+// migration, through the workflow queue when one is available, and records the
+// execution and outcome on the migration
+func (s *Server) startGoldenPathMigration(ctx context.Context, m *database.GoldenPathMigration, successor *types.WorkflowSpec, user *users.User) error {
+	parameters := make(map[string]string, len(m.Parameters))
+	for name, value := range m.Parameters {
+		parameters[name] = value
+	}
+	if len(successor.Metadata.ParameterSources) > 0 {
+		resolved, err := s.parameterResolver().Resolve(ctx, successor.Metadata.ParameterSources, parameters)
+		if err != nil {
+			return fmt.Errorf("failed to resolve workflow parameters: %w", err)
+		}
+		for name, value := range resolved {
+			parameters[name] = value
+		}
+	}
+
+	workflowCtx := logging.WithApp(context.WithoutCancel(ctx), m.ApplicationName)
+	logger := logging.FromContext(workflowCtx, "server")
+	workflowCtx = workflow.WithExecutionStarted(workflowCtx, func(executionID int64) {
+		if err := s.db.UpdateGoldenPathMigration(m.ID, database.MigrationStatusRunning, executionID, nil); err != nil {
+			logger.Warnf("Failed to update migration %d: %v", m.ID, err)
+		}
+	})
+
+	finish := func(ctx context.Context, executionID int64, runErr error) {
+		status := database.MigrationStatusSucceeded
+		var errorMessage *string
+		if runErr != nil {
+			status = database.MigrationStatusFailed
+			message := runErr.Error()
+			errorMessage = &message
+			logger.Errorf("Migration of %s from golden path '%s' to '%s' failed: %v", m.ApplicationName, m.GoldenPath, m.Successor, runErr)
+		} else if s.resourceManager != nil {
+			if err := s.provisionResourcesAfterWorkflow(ctx, m.ApplicationName, user.Username); err != nil {
+				logger.Warnf("Resource provisioning failed: %v", err)
+			}
+		}
+		if err := s.db.UpdateGoldenPathMigration(m.ID, status, executionID, errorMessage); err != nil {
+			logger.Warnf("Failed to update migration %d: %v", m.ID, err)
+		}
+	}
+
+	workflowName := fmt.Sprintf("golden-path-%s", m.Successor)
+	if s.workflowQueue != nil {
+		metadata := map[string]interface{}{
+			"user":         user.Username,
+			"golden_path":  m.Successor,
+			"source":       "migration",
+			"migration_id": m.ID,
+			"parameters":   parameters,
+		}
+		_, err := s.workflowQueue.EnqueueWithContext(workflowCtx, m.ApplicationName, workflowName, successor.Spec, metadata, func(result queue.TaskResult) {
+			finish(workflowCtx, result.ExecutionID, result.Err)
+		})
+		return err
+	}
+
+	executionID, done, err := startWorkflowRun(workflowCtx, func(ctx context.Context) error {
+		return s.workflowExecutor.ExecuteWorkflowWithContext(ctx, m.ApplicationName, workflowName, successor.Spec, parameters)
+	})
+	if err != nil {
+		return err
+	}
+	go func() {
+		finish(workflowCtx, executionID, <-done)
+	}()
+	return nil
+}
+
+// goldenPathWarningHeader formats a deprecation warning as HTTP Warning header value
+func goldenPathWarningHeader(warning string) string {
+	return fmt.Sprintf("299 innominatus %q", warning)
+}
@@ -0,0 +1,298 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/logging"
+	"innominatus/internal/types"
+	"innominatus/internal/users"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// sandboxDNSZone is the reserved TLD (RFC 2606) used for sandbox hostnames; it never
+// resolves publicly, so test runs cannot publish or hijack real DNS records
+const sandboxDNSZone = "sandbox.test"
+
+// goldenPathSandbox describes the isolated environment a golden path test run executes in
+type goldenPathSandbox struct {
+	ID        string `json:"id"`
+	AppName   string `json:"app_name"`
+	Namespace string `json:"namespace"`
+	GiteaOrg  string `json:"gitea_org"`
+	Domain    string `json:"domain"`
+}
+
+// newGoldenPathSandbox names a sandbox for the given application using a random suffix
+func newGoldenPathSandbox(appName string) (*goldenPathSandbox, error) {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate sandbox id: %w", err)
+	}
+	id := hex.EncodeToString(suffix)
+
+	// Keep the application name a valid DNS label (max 63 characters)
+	name := appName
+	if maxLen := 63 - len("-test-") - len(id); len(name) > maxLen {
+		name = strings.TrimRight(name[:maxLen], "-")
+	}
+
+	return &goldenPathSandbox{
+		ID:        id,
+		AppName:   fmt.Sprintf("%s-test-%s", name, id),
+		Namespace: fmt.Sprintf("gp-test-%s", id),
+		GiteaOrg:  fmt.Sprintf("gp-test-%s", id),
+		Domain:    fmt.Sprintf("%s.%s", id, sandboxDNSZone),
+	}, nil
+}
+
+// apply rewrites the Score spec, workflow and parameters so that every namespace, Gitea
+// owner and hostname points into the sandbox. The returned workflow has its own step slice.
+func (sb *goldenPathSandbox) apply(spec *types.ScoreSpec, workflow types.Workflow, params map[string]string) types.Workflow {
+	spec.Metadata.Name = sb.AppName
+
+	steps := make([]types.Step, len(workflow.Steps))
+	for i, step := range workflow.Steps {
+		step.Namespace = sb.Namespace
+		if step.Type == "gitea-repo" || step.Owner != "" {
+			step.Owner = sb.GiteaOrg
+		}
+		if step.Config != nil {
+			config := make(map[string]interface{}, len(step.Config))
+			for key, value := range step.Config {
+				config[key] = value
+			}
+			if _, ok := config["namespace"]; ok {
+				config["namespace"] = sb.Namespace
+			}
+			for _, key := range []string{"owner", "org", "org_name"} {
+				if _, ok := config[key]; ok {
+					config[key] = sb.GiteaOrg
+				}
+			}
+			step.Config = config
+		}
+		steps[i] = step
+	}
+	workflow.Steps = steps
+
+	params["namespace"] = sb.Namespace
+	params["org_name"] = sb.GiteaOrg
+	params["domain"] = sb.Domain
+	params["dns_provider"] = "mock"
+	params["sandbox"] = "true"
+
+	return workflow
+}
+
+// runGoldenPathTest executes a golden path inside a throwaway sandbox and tears it down
+// afterwards, regardless of the outcome
+func (s *Server) runGoldenPathTest(w http.ResponseWriter, r *http.Request, goldenPathName string, spec *types.ScoreSpec, workflow types.Workflow, params map[string]string, user *users.User) {
+	if s.workflowExecutor == nil {
+		http.Error(w, "Golden path test mode requires the workflow executor", http.StatusServiceUnavailable)
+		return
+	}
+
+	sandbox, err := newGoldenPathSandbox(spec.Metadata.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	workflow = sandbox.apply(spec, workflow, params)
+
+	// Teardown must run even if the client disconnects, so only the log fields are kept
+	ctx := logging.WithApp(context.WithoutCancel(r.Context()), sandbox.AppName)
+	logger := logging.FromContext(ctx, "server")
+	logger.Infof("Testing golden path '%s' in sandbox %s (namespace %s, Gitea org %s)", goldenPathName, sandbox.ID, sandbox.Namespace, sandbox.GiteaOrg)
+
+	startedAt := time.Now()
+	runErr := s.setupGoldenPathSandbox(sandbox)
+	if runErr == nil {
+		runErr = s.db.AddApplication(sandbox.AppName, spec, user.Team, user.Username)
+	}
+	if runErr == nil && s.resourceManager != nil {
+		if err := s.resourceManager.CreateResourceFromSpec(sandbox.AppName, spec, user.Username); err != nil {
+			logger.Warnf("Failed to create sandbox resource instances: %v", err)
+		}
+	}
+	if runErr == nil {
+		runErr = s.workflowExecutor.ExecuteWorkflowWithContext(ctx, sandbox.AppName, fmt.Sprintf("golden-path-%s", goldenPathName), workflow, params)
+	}
+	if runErr == nil && s.resourceManager != nil {
+		runErr = s.provisionResourcesAfterWorkflow(ctx, sandbox.AppName, user.Username)
+	}
+	duration := time.Since(startedAt)
+
+	teardownErrors := s.teardownGoldenPathSandbox(sandbox, user.Username)
+	if len(teardownErrors) == 0 {
+		logger.Infof("Sandbox %s torn down", sandbox.ID)
+	}
+
+	response := map[string]interface{}{
+		"application": sandbox.AppName,
+		"golden_path": goldenPathName,
+		"sandbox":     sandbox,
+		"duration_ms": duration.Milliseconds(),
+		"torn_down":   len(teardownErrors) == 0,
+	}
+	if len(teardownErrors) > 0 {
+		response["teardown_errors"] = teardownErrors
+	}
+
+	status := http.StatusOK
+	if runErr != nil {
+		status = http.StatusInternalServerError
+		response["status"] = "failed"
+		response["error"] = runErr.Error()
+		response["message"] = fmt.Sprintf("Golden path '%s' failed in sandbox %s: %v", goldenPathName, sandbox.ID, runErr)
+	} else {
+		response["status"] = "completed"
+		response["message"] = fmt.Sprintf("Golden path '%s' passed in sandbox %s", goldenPathName, sandbox.ID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// setupGoldenPathSandbox creates the sandbox namespace and Gitea organization
+func (s *Server) setupGoldenPathSandbox(sandbox *goldenPathSandbox) error {
+	logger := logging.NewStructuredLogger("server")
+
+	logBuffer := NewLogBuffer(nil, nil)
+	if err := s.executeCommand("kubectl", []string{"create", "namespace", sandbox.Namespace}, "", logBuffer); err != nil {
+		return fmt.Errorf("failed to create sandbox namespace %s: %w", sandbox.Namespace, err)
+	}
+
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil || adminConfig.Gitea.URL == "" {
+		logger.Warn("Gitea not configured, skipping sandbox organization")
+		return nil
+	}
+
+	orgJSON, err := json.Marshal(map[string]string{
+		"username":    sandbox.GiteaOrg,
+		"description": fmt.Sprintf("Golden path test sandbox %s", sandbox.ID),
+		"visibility":  "private",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal organization data: %w", err)
+	}
+	status, body, err := giteaRequest(adminConfig, "POST", "/api/v1/orgs", string(orgJSON))
+	if err != nil {
+		return fmt.Errorf("failed to create sandbox Gitea organization: %w", err)
+	}
+	if status != http.StatusCreated {
+		return fmt.Errorf("failed to create sandbox Gitea organization, status %d: %s", status, body)
+	}
+	return nil
+}
+
+// teardownGoldenPathSandbox removes everything the sandbox run created. It keeps going
+// after individual failures and returns all of them.
+func (s *Server) teardownGoldenPathSandbox(sandbox *goldenPathSandbox, username string) []string {
+	logger := logging.NewStructuredLogger("server")
+
+	var errs []string
+
+	if s.resourceManager != nil {
+		if err := s.resourceManager.DeleteApplication(sandbox.AppName, username); err != nil {
+			errs = append(errs, fmt.Sprintf("delete resources: %v", err))
+		}
+	}
+	if _, err := s.db.GetApplication(sandbox.AppName); err == nil {
+		if err := s.db.DeleteApplication(sandbox.AppName); err != nil {
+			errs = append(errs, fmt.Sprintf("delete application: %v", err))
+		}
+	}
+
+	logBuffer := NewLogBuffer(nil, nil)
+	if err := s.executeCommand("kubectl", []string{"delete", "namespace", sandbox.Namespace, "--ignore-not-found", "--wait=false"}, "", logBuffer); err != nil {
+		errs = append(errs, fmt.Sprintf("delete namespace %s: %v", sandbox.Namespace, err))
+	}
+
+	if err := deleteGiteaOrg(sandbox.GiteaOrg); err != nil {
+		errs = append(errs, fmt.Sprintf("delete Gitea organization %s: %v", sandbox.GiteaOrg, err))
+	}
+
+	for _, e := range errs {
+		logger.Warnf("Sandbox %s teardown: %s", sandbox.ID, e)
+	}
+	return errs
+}
+
+// deleteGiteaOrg deletes an organization and its repositories; Gitea refuses to delete
+// organizations that still own repositories
+func deleteGiteaOrg(org string) error {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil || adminConfig.Gitea.URL == "" {
+		return nil
+	}
+
+	status, body, err := giteaRequest(adminConfig, "GET", fmt.Sprintf("/api/v1/orgs/%s/repos?limit=50", org), "")
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		return nil
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("list repositories, status %d: %s", status, body)
+	}
+
+	var repos []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(body), &repos); err != nil {
+		return fmt.Errorf("parse repositories: %w", err)
+	}
+	for _, repo := range repos {
+		status, body, err := giteaRequest(adminConfig, "DELETE", fmt.Sprintf("/api/v1/repos/%s/%s", org, repo.Name), "")
+		if err != nil {
+			return err
+		}
+		if status != http.StatusNoContent && status != http.StatusNotFound {
+			return fmt.Errorf("delete repository %s, status %d: %s", repo.Name, status, body)
+		}
+	}
+
+	status, body, err = giteaRequest(adminConfig, "DELETE", fmt.Sprintf("/api/v1/orgs/%s", org), "")
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNoContent && status != http.StatusNotFound {
+		return fmt.Errorf("status %d: %s", status, body)
+	}
+	return nil
+}
+
+// giteaRequest calls the Gitea API with the admin credentials
+func giteaRequest(adminConfig *admin.AdminConfig, method, path, body string) (int, string, error) {
+	req, err := http.NewRequest(method, adminConfig.Gitea.URL+path, strings.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(adminConfig.Gitea.Username, adminConfig.Gitea.Password)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(respBody), nil
+}
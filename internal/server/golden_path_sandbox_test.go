@@ -0,0 +1,72 @@
+package server
+
+import (
+	"innominatus/internal/types"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGoldenPathSandbox(t *testing.T) {
+	sandbox, err := newGoldenPathSandbox("shop")
+	require.NoError(t, err)
+
+	assert.Len(t, sandbox.ID, 6)
+	assert.Equal(t, "shop-test-"+sandbox.ID, sandbox.AppName)
+	assert.Equal(t, "gp-test-"+sandbox.ID, sandbox.Namespace)
+	assert.Equal(t, "gp-test-"+sandbox.ID, sandbox.GiteaOrg)
+	assert.Equal(t, sandbox.ID+".sandbox.test", sandbox.Domain)
+
+	other, err := newGoldenPathSandbox("shop")
+	require.NoError(t, err)
+	assert.NotEqual(t, sandbox.ID, other.ID)
+
+	long, err := newGoldenPathSandbox(strings.Repeat("a", 50) + "-" + strings.Repeat("b", 20))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(long.AppName), 63)
+	assert.NotContains(t, long.AppName, "--")
+}
+
+func TestGoldenPathSandboxApply(t *testing.T) {
+	sandbox := &goldenPathSandbox{
+		ID:        "abc123",
+		AppName:   "shop-test-abc123",
+		Namespace: "gp-test-abc123",
+		GiteaOrg:  "gp-test-abc123",
+		Domain:    "abc123.sandbox.test",
+	}
+
+	spec := &types.ScoreSpec{Metadata: types.Metadata{Name: "shop"}}
+	workflow := types.Workflow{Steps: []types.Step{
+		{Name: "repo", Type: "gitea-repo", Owner: "platform-team"},
+		{Name: "namespace", Type: "kubernetes", Config: map[string]interface{}{"namespace": "shop-prod", "operation": "create-namespace"}},
+		{Name: "plan", Type: "terraform"},
+	}}
+	params := map[string]string{"environment": "production", "namespace": "shop-prod"}
+
+	rewritten := sandbox.apply(spec, workflow, params)
+
+	assert.Equal(t, "shop-test-abc123", spec.Metadata.Name)
+	assert.Equal(t, "gp-test-abc123", rewritten.Steps[0].Owner)
+	assert.Equal(t, "gp-test-abc123", rewritten.Steps[1].Config["namespace"])
+	assert.Equal(t, "create-namespace", rewritten.Steps[1].Config["operation"])
+	assert.Empty(t, rewritten.Steps[2].Owner)
+	for _, step := range rewritten.Steps {
+		assert.Equal(t, "gp-test-abc123", step.Namespace)
+	}
+
+	// The original workflow is left untouched
+	assert.Equal(t, "platform-team", workflow.Steps[0].Owner)
+	assert.Equal(t, "shop-prod", workflow.Steps[1].Config["namespace"])
+
+	assert.Equal(t, map[string]string{
+		"environment":  "production",
+		"namespace":    "gp-test-abc123",
+		"org_name":     "gp-test-abc123",
+		"domain":       "abc123.sandbox.test",
+		"dns_provider": "mock",
+		"sandbox":      "true",
+	}, params)
+}
@@ -8,28 +8,44 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"innominatus/internal/logging"
 	"io"
 	"io/fs"
 	"regexp"
 	"sort"
 
 	"innominatus/internal/admin"
+	"innominatus/internal/argoprojects"
 	"innominatus/internal/auth"
+	"innominatus/internal/changemgmt"
+	"innominatus/internal/clock"
+	"innominatus/internal/cloudcreds"
+	"innominatus/internal/config"
 	"innominatus/internal/database"
 	"innominatus/internal/demo"
 	"innominatus/internal/events"
+	"innominatus/internal/faults"
+	"innominatus/internal/giteateams"
 	"innominatus/internal/goldenpaths"
 	"innominatus/internal/graph"
 	"innominatus/internal/health"
+	"innominatus/internal/hibernation"
+	"innominatus/internal/loadtest"
 	"innominatus/internal/metrics"
+	"innominatus/internal/objectstore"
 	"innominatus/internal/orchestration"
+	"innominatus/internal/paramsources"
+	"innominatus/internal/provenance"
+	"innominatus/internal/providers"
 	"innominatus/internal/queue"
 	"innominatus/internal/resources"
+	"innominatus/internal/secrets"
 	"innominatus/internal/security"
 	"innominatus/internal/teams"
 	"innominatus/internal/types"
 	"innominatus/internal/users"
 	"innominatus/internal/workflow"
+	"innominatus/internal/workspaces"
 	providersdk "innominatus/pkg/sdk"
 	"net/http"
 	"os"
@@ -38,6 +54,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/philipsahli/innominatus-graph/pkg/export"
@@ -60,22 +77,59 @@ type AIService interface {
 type ProviderRegistry interface {
 	ListProviders() []*providersdk.Provider
 	GetProvider(name string) (*providersdk.Provider, error)
+	GetProviderVersion(name, version string) (*providersdk.Provider, error)
+	ProviderVersions(name string) []string
+	CompareVersions(name, from, to string) (*providers.VersionChanges, error)
 	Count() (providers int, provisioners int)
 }
 
+// Step log flush defaults. LogBuffer writes to the database when either is reached, so
+// chatty tools do not issue an UPDATE per line.
+const (
+	DefaultLogFlushBytes    = 16 * 1024
+	DefaultLogFlushInterval = time.Second
+)
+
+// stepLogStore appends output to a workflow step execution
+type stepLogStore interface {
+	AddWorkflowStepLogs(stepID int64, logs string) error
+}
+
 // LogBuffer captures command output for workflow step logging
 type LogBuffer struct {
-	buffer strings.Builder
-	stepID *int64
-	repo   *database.WorkflowRepository
-	mu     sync.Mutex
+	buffer        strings.Builder
+	stepID        *int64
+	repo          stepLogStore
+	mu            sync.Mutex
+	flushBytes    int           // Pending bytes that trigger a flush
+	flushInterval time.Duration // Longest time output stays pending
+	timer         *time.Timer   // Pending interval flush
 }
 
 // NewLogBuffer creates a new log buffer for a workflow step
 func NewLogBuffer(stepID *int64, repo *database.WorkflowRepository) *LogBuffer {
-	return &LogBuffer{
-		stepID: stepID,
-		repo:   repo,
+	lb := &LogBuffer{
+		stepID:        stepID,
+		flushBytes:    DefaultLogFlushBytes,
+		flushInterval: DefaultLogFlushInterval,
+	}
+	if repo != nil {
+		lb.repo = repo
+	}
+	return lb
+}
+
+// SetFlushThresholds changes when pending output is written to the database. Values
+// of zero or less keep the defaults.
+func (lb *LogBuffer) SetFlushThresholds(bytes int, interval time.Duration) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	if bytes > 0 {
+		lb.flushBytes = bytes
+	}
+	if interval > 0 {
+		lb.flushInterval = interval
 	}
 }
 
@@ -99,21 +153,50 @@ func (lb *LogBuffer) Write(p []byte) (n int, err error) {
 		}
 	}
 
-	// Store logs in database if step ID is available
-	if lb.stepID != nil && *lb.stepID > 0 && lb.repo != nil {
-		logContent := lb.buffer.String()
-		if logContent != "" {
-			if err := lb.repo.AddWorkflowStepLogs(*lb.stepID, logContent); err != nil {
-				// Log error but don't fail the write operation
-				fmt.Fprintf(os.Stderr, "failed to store workflow logs: %v\n", err)
-			}
-			lb.buffer.Reset() // Clear buffer after storing
+	// Store logs in database once enough output is pending, or after the flush interval
+	if lb.persistent() && lb.buffer.Len() > 0 {
+		if lb.buffer.Len() >= lb.flushBytes {
+			lb.flushLocked()
+		} else if lb.timer == nil {
+			lb.timer = time.AfterFunc(lb.flushInterval, lb.Flush)
 		}
 	}
 
 	return len(p), nil
 }
 
+// Flush writes pending output to the database
+func (lb *LogBuffer) Flush() {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.flushLocked()
+}
+
+// Close flushes pending output when the step completes
+func (lb *LogBuffer) Close() error {
+	lb.Flush()
+	return nil
+}
+
+func (lb *LogBuffer) persistent() bool {
+	return lb.stepID != nil && *lb.stepID > 0 && lb.repo != nil
+}
+
+func (lb *LogBuffer) flushLocked() {
+	if lb.timer != nil {
+		lb.timer.Stop()
+		lb.timer = nil
+	}
+	if !lb.persistent() || lb.buffer.Len() == 0 {
+		return
+	}
+	if err := lb.repo.AddWorkflowStepLogs(*lb.stepID, lb.buffer.String()); err != nil {
+		// Log error but don't fail the write operation
+		fmt.Fprintf(os.Stderr, "failed to store workflow logs: %v\n", err)
+	}
+	lb.buffer.Reset() // Clear buffer after storing
+}
+
 // GetLogs returns the accumulated logs
 func (lb *LogBuffer) GetLogs() string {
 	lb.mu.Lock()
@@ -135,35 +218,86 @@ type StepExecutionContext struct {
 type ProvidersReloadFunc func() error
 
 type Server struct {
-	db                  *database.Database
-	workflowRepo        *database.WorkflowRepository
-	workflowExecutor    *workflow.WorkflowExecutor
-	workflowAnalyzer    *workflow.WorkflowAnalyzer
-	workflowQueue       *queue.Queue // Async workflow execution queue
-	resourceManager     *resources.Manager
-	teamManager         *teams.TeamManager
-	sessionManager      auth.ISessionManager
-	oidcAuthenticator   *auth.OIDCAuthenticator
-	healthChecker       *health.HealthChecker
-	rateLimiter         *RateLimiter
-	graphAdapter        *graph.Adapter
-	wsHub               *GraphWebSocketHub      // WebSocket hub for real-time graph updates
-	sseBroker           *events.SSEBroker       // SSE broker for real-time event streaming
-	aiService           AIService               // AI assistant service (optional)
-	providerRegistry    ProviderRegistry        // Provider registry (optional)
-	providerResolver    *orchestration.Resolver // Resolver for matching resources to providers
-	providersReloadFunc ProvidersReloadFunc     // Callback to reload providers from admin-config.yaml
-	swaggerFS           fs.FS                   // Optional: embedded swagger files
-	webUIFS             fs.FS                   // Optional: embedded web-ui files
-	loginAttempts       map[string][]time.Time
-	loginMutex          sync.Mutex
+	db                    *database.Database
+	workflowRepo          *database.WorkflowRepository
+	workflowExecutor      *workflow.WorkflowExecutor
+	workflowAnalyzer      *workflow.WorkflowAnalyzer
+	workflowQueue         *queue.Queue // Async workflow execution queue
+	resourceManager       *resources.Manager
+	teamManager           *teams.TeamManager
+	sessionManager        auth.ISessionManager
+	oidcAuthenticator     *auth.OIDCAuthenticator
+	oidcIssuer            string // Issuer URL when OIDC is enabled, checked in /health
+	healthChecker         *health.HealthChecker
+	rateLimiter           *RateLimiter                    // Used until ApplyRuntimeConfig is called
+	runtime               atomic.Pointer[runtimeSettings] // Settings applied from admin-config.yaml; nil before ApplyRuntimeConfig
+	runtimeMu             sync.Mutex                      // Serializes ApplyRuntimeConfig
+	graphAdapter          *graph.Adapter
+	wsHub                 *GraphWebSocketHub                   // WebSocket hub for real-time graph updates
+	sseBroker             *events.SSEBroker                    // SSE broker for real-time event streaming
+	aiService             AIService                            // AI assistant service (optional)
+	providerRegistry      ProviderRegistry                     // Provider registry (optional)
+	providerResolver      *orchestration.Resolver              // Resolver for matching resources to providers
+	providerHealth        *orchestration.ProviderHealthTracker // Provisioning outcomes per provider (set when the engine runs)
+	providersReloadFunc   ProvidersReloadFunc                  // Callback to reload providers from admin-config.yaml
+	configuredProviders   int                                  // Enabled providers in admin-config.yaml at startup (readiness)
+	buildInfo             BuildInfo                            // Reported by /api/version and /health
+	cliPolicy             CLIVersionPolicy                     // Supported innominatus-ctl versions
+	effectiveConfig       *config.Config                       // Resolved server settings (nil when not started via main)
+	metricsPusher         *metrics.MetricsPusher               // Pushgateway pusher (nil when pushing is off)
+	swaggerFS             fs.FS                                // Optional: embedded swagger files
+	webUIFS               fs.FS                                // Optional: embedded web-ui files
+	paramResolver         *paramsources.Resolver               // External workflow parameter sources (lazily created)
+	paramResolverOnce     sync.Once
+	clock                 clock.Clock       // Time source for schedulers; nil means wall clock
+	loadTests             *loadtest.Manager // Synthetic load test runs (lazily created)
+	loadTestsOnce         sync.Once
+	provenanceSigner      *provenance.Signer // Signs deployment provenance; nil records unsigned documents
+	provenanceBuilder     provenance.Builder
+	directoryStore        directoryStore         // SCIM-provisioned users and teams; nil uses the database
+	appWorkspaces         *workspaces.Workspaces // Generated files per application (lazily created)
+	appWorkspacesOnce     sync.Once
+	logFlushBytes         int                            // Step log bytes buffered before a database write; 0 uses the default
+	logFlushInterval      time.Duration                  // Longest time step logs stay buffered; 0 uses the default
+	loginAttempts         auth.LoginAttemptStore         // Failed logins per username and client IP
+	hibernationScaler     hibernation.Scaler             // Scales workloads of hibernated applications; nil uses kubectl
+	giteaTeams            *giteateams.Provisioner        // Provisions team organizations in Gitea; nil uses admin-config.yaml
+	argoProjects          *argoprojects.Provisioner      // Maintains team projects in ArgoCD; nil uses admin-config.yaml
+	workloadTokens        auth.WorkloadTokenStore        // Tokens issued to Kubernetes service accounts
+	tokenReviewer         auth.TokenReviewer             // Validates service account tokens; nil uses the TokenReview API
+	faultInjector         *faults.Injector               // Resilience testing faults; nil when fault injection is disabled
+	providerDev           *providers.DevChannel          // Local providers of innominatus-ctl provider dev; nil when disabled
+	concurrencyGroups     *goldenpaths.ConcurrencyGroups // Golden path runs per concurrency group (lazily created)
+	concurrencyGroupsOnce sync.Once
 	// In-memory workflow tracking (when database is not available)
 	memoryWorkflows map[int64]*MemoryWorkflowExecution
 	workflowCounter int64
 	workflowMutex   sync.RWMutex
-	// Workflow scheduler for periodic execution
-	workflowTicker *time.Ticker  //nolint:unused // Reserved for workflow scheduling
-	stopScheduler  chan struct{} //nolint:unused // Reserved for workflow scheduling
+}
+
+// parameterResolver returns the shared resolver so cached values survive across executions
+func (s *Server) parameterResolver() *paramsources.Resolver {
+	s.paramResolverOnce.Do(func() {
+		s.paramResolver = paramsources.NewResolver()
+	})
+	return s.paramResolver
+}
+
+// SetClock replaces the wall clock for schedulers, the async queue and the execution
+// time snapshot of workflows. Call before starting schedulers.
+func (s *Server) SetClock(c clock.Clock) {
+	s.clock = c
+	if s.workflowExecutor != nil {
+		s.workflowExecutor.SetClock(c)
+	}
+	if s.workflowQueue != nil {
+		s.workflowQueue.SetClock(c)
+	}
+}
+
+// Clock returns the server's time source
+func (s *Server) Clock() clock.Clock {
+	return clock.OrReal(s.clock)
 }
 
 // SetAIService sets the AI service for the server
@@ -181,6 +315,11 @@ func (s *Server) SetProviderResolver(resolver *orchestration.Resolver) {
 	s.providerResolver = resolver
 }
 
+// SetProviderHealth exposes the orchestration engine's per-provider health tracking
+func (s *Server) SetProviderHealth(tracker *orchestration.ProviderHealthTracker) {
+	s.providerHealth = tracker
+}
+
 // SetProvidersReloadFunc sets the callback function for reloading providers
 func (s *Server) SetProvidersReloadFunc(reloadFunc ProvidersReloadFunc) {
 	s.providersReloadFunc = reloadFunc
@@ -275,14 +414,16 @@ type MemoryWorkflowStep struct {
 }
 
 func NewServer() *Server {
+	logger := logging.NewStructuredLogger("server")
+
 	// Initialize OIDC authenticator
 	oidcConfig := auth.LoadOIDCConfig()
 	oidcAuth, err := auth.NewOIDCAuthenticator(oidcConfig)
 	if err != nil && oidcConfig.Enabled {
-		fmt.Printf("Warning: Failed to initialize OIDC: %v\n", err)
-		fmt.Println("Continuing without OIDC authentication...")
+		logger.Warnf("Failed to initialize OIDC: %v", err)
+		logger.Info("Continuing without OIDC authentication...")
 	} else if oidcConfig.Enabled {
-		fmt.Println("OIDC authentication enabled")
+		logger.Info("OIDC authentication enabled")
 	}
 
 	healthChecker := health.NewHealthChecker()
@@ -298,9 +439,11 @@ func NewServer() *Server {
 		teamManager:       teams.NewTeamManager(),
 		sessionManager:    auth.NewSessionManager(),
 		oidcAuthenticator: oidcAuth,
+		oidcIssuer:        oidcIssuer(oidcConfig),
 		healthChecker:     healthChecker,
 		wsHub:             wsHub,
-		loginAttempts:     make(map[string][]time.Time),
+		loginAttempts:     auth.NewMemoryLoginAttemptStore(),
+		workloadTokens:    auth.NewMemoryWorkloadTokenStore(),
 		memoryWorkflows:   make(map[int64]*MemoryWorkflowExecution),
 		workflowCounter:   0,
 	}
@@ -316,17 +459,56 @@ func NewServerWithDB(db *database.Database) *Server {
 	return NewServerWithDBAndAdminConfig(db, nil)
 }
 
+// NewDevServer creates a server for local development whose workflow executions are
+// stored in repo, e.g. the SQLite dev database. Everything else that needs PostgreSQL
+// (applications, resources, approvals, API keys, graph) stays unavailable as in NewServer.
+func NewDevServer(repo workflow.WorkflowRepositoryInterface, adminCfg *admin.AdminConfig) *Server {
+	server := NewServer()
+
+	var workflowExecutor *workflow.WorkflowExecutor
+	if adminCfg != nil {
+		workflowExecutor = workflow.NewMultiTierWorkflowExecutor(repo, workflowResolver(adminCfg))
+		workflowExecutor.SetInheritedEnvironment(adminCfg.WorkflowPolicies.StepEnvironment.Inherit)
+		setSecretResolver(workflowExecutor, adminCfg)
+		workflowExecutor.SetStepLogLimits(stepLogLimits(adminCfg))
+		workflowExecutor.SetPolicySources(policySources(adminCfg))
+		server.setStepLogFlush(adminCfg.WorkflowPolicies.StepLogs.FlushBytes, adminCfg.WorkflowPolicies.StepLogs.FlushInterval)
+		for _, providerSrc := range adminCfg.Providers {
+			if providerSrc.Enabled {
+				server.configuredProviders++
+			}
+		}
+	} else {
+		workflowExecutor = workflow.NewWorkflowExecutor(repo)
+	}
+	workflowExecutor.SetStepCache(workflow.NewStepCache(filepath.Join("data", "step-cache")))
+	workflowExecutor.SetDebugWorkspaces(workflow.NewDebugWorkspaces(filepath.Join("data", "debug-workspaces"), workflow.DefaultDebugRetention))
+
+	// Queued tasks are only tracked in memory without a database
+	workflowQueue := queue.NewQueue(5, workflowExecutor, nil)
+	workflowQueue.Start()
+
+	server.workflowExecutor = workflowExecutor
+	server.workflowQueue = workflowQueue
+	server.healthChecker.RegisterReadiness(health.NewFuncChecker("providers", server.checkProvidersReady))
+	server.healthChecker.RegisterReadiness(health.NewFuncChecker("workflow_queue", server.checkQueueReady))
+
+	return server
+}
+
 // NewServerWithDBAndAdminConfig creates a new server with database and admin configuration support
 // If adminConfig is provided, enables multi-tier workflow executor with product workflows
 func NewServerWithDBAndAdminConfig(db *database.Database, adminConfig interface{}) *Server {
+	logger := logging.NewStructuredLogger("server")
+
 	// Initialize OIDC authenticator
 	oidcConfig := auth.LoadOIDCConfig()
 	oidcAuth, err := auth.NewOIDCAuthenticator(oidcConfig)
 	if err != nil && oidcConfig.Enabled {
-		fmt.Printf("Warning: Failed to initialize OIDC: %v\n", err)
-		fmt.Println("Continuing without OIDC authentication...")
+		logger.Warnf("Failed to initialize OIDC: %v", err)
+		logger.Info("Continuing without OIDC authentication...")
 	} else if oidcConfig.Enabled {
-		fmt.Println("OIDC authentication enabled")
+		logger.Info("OIDC authentication enabled")
 	}
 
 	// Create repositories
@@ -339,42 +521,56 @@ func NewServerWithDBAndAdminConfig(db *database.Database, adminConfig interface{
 	if adminConfig != nil {
 		// Multi-tier executor with product workflow support
 		if adminCfg, ok := adminConfig.(*admin.AdminConfig); ok && adminCfg != nil {
-			policies := workflow.WorkflowPolicies{
-				RequiredPlatformWorkflows: adminCfg.WorkflowPolicies.RequiredPlatformWorkflows,
-				AllowedProductWorkflows:   adminCfg.WorkflowPolicies.AllowedProductWorkflows,
-				WorkflowOverrides: struct {
-					Platform bool `yaml:"platform"`
-					Product  bool `yaml:"product"`
-				}{
-					Platform: adminCfg.WorkflowPolicies.WorkflowOverrides.Platform,
-					Product:  adminCfg.WorkflowPolicies.WorkflowOverrides.Product,
-				},
-				MaxWorkflowDuration: adminCfg.WorkflowPolicies.MaxWorkflowDuration,
-			}
-
-			workflowsRoot := adminCfg.WorkflowPolicies.WorkflowsRoot
-			if workflowsRoot == "" {
-				workflowsRoot = "./workflows" // Default
-			}
-
-			resolver := workflow.NewWorkflowResolver(workflowsRoot, policies)
+			resolver := workflowResolver(adminCfg)
 			workflowExecutor = workflow.NewMultiTierWorkflowExecutorWithResourceManager(workflowRepo, resolver, resourceManager)
-			fmt.Println("✅ Multi-tier workflow executor enabled (platform + product + application workflows)")
+			logger.Info("Multi-tier workflow executor enabled (platform + product + application workflows)")
 		} else {
 			// Fall back to single-tier if admin config type assertion fails
-			fmt.Println("⚠️  Admin config type mismatch, using single-tier executor")
+			logger.Warn("Admin config type mismatch, using single-tier executor")
 			workflowExecutor = workflow.NewWorkflowExecutorWithResourceManager(workflowRepo, resourceManager)
 		}
 	} else {
 		// Single-tier executor (backward compatible)
 		workflowExecutor = workflow.NewWorkflowExecutorWithResourceManager(workflowRepo, resourceManager)
-		fmt.Println("ℹ️  Single-tier workflow executor (use admin-config.yaml for product workflows)")
+		logger.Info("Single-tier workflow executor (use admin-config.yaml for product workflows)")
+	}
+
+	// Enable approval gates (terraform plan review, approval steps)
+	workflowExecutor.SetApprovalStore(db)
+
+	// Expose Score metadata.variables to every workflow step of the application
+	workflowExecutor.SetApplicationStore(db)
+
+	// Step processes only see the server environment variables the admin allows
+	if adminCfg, ok := adminConfig.(*admin.AdminConfig); ok && adminCfg != nil {
+		workflowExecutor.SetInheritedEnvironment(adminCfg.WorkflowPolicies.StepEnvironment.Inherit)
+		setSecretResolver(workflowExecutor, adminCfg)
+		workflowExecutor.SetStepLogLimits(stepLogLimits(adminCfg))
+		workflowExecutor.SetPolicySources(policySources(adminCfg))
+		identity := adminCfg.WorkflowPolicies.KubernetesIdentity
+		if err := workflowExecutor.SetKubernetesIdentity(workflow.KubernetesIdentity{Mode: identity.Mode, ClusterRole: identity.ClusterRole}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v, kubernetes steps use the server credentials\n", err)
+		}
+		if grants := adminCfg.WorkflowPolicies.CloudCredentials; len(grants.Grants) > 0 {
+			broker, err := cloudcreds.NewBroker(grants)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v, provisioning steps get no cloud credentials\n", err)
+			} else {
+				workflowExecutor.SetCloudCredentialBroker(broker)
+			}
+		}
 	}
 
+	// Steps with a cache block reuse results of identical earlier runs
+	workflowExecutor.SetStepCache(workflow.NewStepCache(filepath.Join("data", "step-cache")))
+
+	// Failed steps keep their workspace for a while so a debug session can be opened
+	workflowExecutor.SetDebugWorkspaces(workflow.NewDebugWorkspaces(filepath.Join("data", "debug-workspaces"), workflow.DefaultDebugRetention))
+
 	// Initialize async workflow queue (5 workers)
 	workflowQueue := queue.NewQueue(5, workflowExecutor, db)
 	workflowQueue.Start()
-	fmt.Println("Async workflow queue initialized with 5 workers")
+	logger.Info("Async workflow queue initialized with 5 workers")
 
 	// Initialize WebSocket hub for real-time graph updates (before graph adapter)
 	wsHub := NewGraphWebSocketHub()
@@ -383,15 +579,15 @@ func NewServerWithDBAndAdminConfig(db *database.Database, adminConfig interface{
 	// Initialize graph adapter
 	graphAdapter, err := graph.NewAdapter(db.DB())
 	if err != nil {
-		fmt.Printf("Warning: Failed to initialize graph adapter: %v\n", err)
-		fmt.Println("Continuing without graph tracking...")
+		logger.Warnf("Failed to initialize graph adapter: %v", err)
+		logger.Info("Continuing without graph tracking...")
 	} else {
-		fmt.Println("Graph adapter initialized successfully")
+		logger.Info("Graph adapter initialized successfully")
 
 		// Register graph observer for real-time WebSocket updates
 		graphObserver := orchestration.NewGraphObserver(graphAdapter, wsHub)
 		graphAdapter.AddObserver(graphObserver)
-		fmt.Println("Graph observer registered for real-time updates")
+		logger.Info("Graph observer registered for real-time updates")
 
 		// Set graph adapter on workflow executor
 		workflowExecutor.SetGraphAdapter(graphAdapter)
@@ -404,31 +600,74 @@ func NewServerWithDBAndAdminConfig(db *database.Database, adminConfig interface{
 	healthChecker.Register(health.NewAlwaysHealthyChecker("server"))
 	healthChecker.Register(health.NewDatabaseChecker(db.DB(), 5*time.Second))
 
-	server := &Server{
-		db:                db,
-		workflowRepo:      workflowRepo,
-		workflowExecutor:  workflowExecutor,
-		workflowAnalyzer:  workflow.NewWorkflowAnalyzer(),
-		workflowQueue:     workflowQueue,
-		resourceManager:   resourceManager,
-		teamManager:       teams.NewTeamManager(),
-		sessionManager:    auth.NewDBSessionManager(db),
-		oidcAuthenticator: oidcAuth,
-		healthChecker:     healthChecker,
-		wsHub:             wsHub,
-		graphAdapter:      graphAdapter,
-		loginAttempts:     make(map[string][]time.Time),
-		memoryWorkflows:   make(map[int64]*MemoryWorkflowExecution),
-		workflowCounter:   0,
+	configuredProviders := 0
+	if adminCfg, ok := adminConfig.(*admin.AdminConfig); ok && adminCfg != nil {
+		for _, providerSrc := range adminCfg.Providers {
+			if providerSrc.Enabled {
+				configuredProviders++
+			}
+		}
 	}
 
-	// Start the workflow scheduler only when database is available
-	// DISABLED: Dummy workflow scheduler (triggers test workflow every minute)
-	// server.startWorkflowScheduler()
+	server := &Server{
+		db:                  db,
+		workflowRepo:        workflowRepo,
+		workflowExecutor:    workflowExecutor,
+		workflowAnalyzer:    workflow.NewWorkflowAnalyzer(),
+		workflowQueue:       workflowQueue,
+		resourceManager:     resourceManager,
+		teamManager:         teams.NewTeamManager(),
+		sessionManager:      auth.NewDBSessionManager(db),
+		oidcAuthenticator:   oidcAuth,
+		oidcIssuer:          oidcIssuer(oidcConfig),
+		healthChecker:       healthChecker,
+		wsHub:               wsHub,
+		graphAdapter:        graphAdapter,
+		configuredProviders: configuredProviders,
+		memoryWorkflows:     make(map[int64]*MemoryWorkflowExecution),
+		workflowCounter:     0,
+	}
+
+	if adminCfg, ok := adminConfig.(*admin.AdminConfig); ok && adminCfg != nil {
+		server.setStepLogFlush(adminCfg.WorkflowPolicies.StepLogs.FlushBytes, adminCfg.WorkflowPolicies.StepLogs.FlushInterval)
+	}
+
+	server.loginAttempts = auth.NewMemoryLoginAttemptStore()
+	server.workloadTokens = auth.NewMemoryWorkloadTokenStore()
+	if db != nil {
+		server.loginAttempts = db
+		server.workloadTokens = db
+	}
+
+	// Startup dependencies gate /ready but not /health
+	server.registerReadinessChecks()
 
 	return server
 }
 
+// workflowResolver resolves platform and product workflows under the policies of admin-config.yaml
+func workflowResolver(adminCfg *admin.AdminConfig) *workflow.WorkflowResolver {
+	policies := workflow.WorkflowPolicies{
+		RequiredPlatformWorkflows: adminCfg.WorkflowPolicies.RequiredPlatformWorkflows,
+		AllowedProductWorkflows:   adminCfg.WorkflowPolicies.AllowedProductWorkflows,
+		WorkflowOverrides: struct {
+			Platform bool `yaml:"platform"`
+			Product  bool `yaml:"product"`
+		}{
+			Platform: adminCfg.WorkflowPolicies.WorkflowOverrides.Platform,
+			Product:  adminCfg.WorkflowPolicies.WorkflowOverrides.Product,
+		},
+		MaxWorkflowDuration: adminCfg.WorkflowPolicies.MaxWorkflowDuration,
+	}
+
+	workflowsRoot := adminCfg.WorkflowPolicies.WorkflowsRoot
+	if workflowsRoot == "" {
+		workflowsRoot = "./workflows" // Default
+	}
+
+	return workflow.NewWorkflowResolver(workflowsRoot, policies)
+}
+
 // HandleApplications is the preferred endpoint for application management
 func (s *Server) HandleApplications(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
@@ -445,6 +684,43 @@ func (s *Server) HandleApplications(w http.ResponseWriter, r *http.Request) {
 func (s *Server) HandleApplicationDetail(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Path[len("/api/applications/"):]
 
+	if appName, ok := strings.CutSuffix(name, "/provenance"); ok {
+		s.handleApplicationProvenance(w, r, appName)
+		return
+	}
+	if appName, ok := strings.CutSuffix(name, "/licenses"); ok {
+		s.handleApplicationLicenses(w, r, appName)
+		return
+	}
+	if appName, ok := strings.CutSuffix(name, "/workspace"); ok {
+		s.handleApplicationWorkspace(w, r, appName)
+		return
+	}
+	if appName, ok := strings.CutSuffix(name, "/status"); ok {
+		s.handleApplicationStatus(w, r, appName)
+		return
+	}
+	if appName, ok := strings.CutSuffix(name, "/preview"); ok {
+		s.handleApplicationPreview(w, r, appName)
+		return
+	}
+	if appName, ok := strings.CutSuffix(name, "/workspace/download"); ok {
+		s.handleApplicationWorkspaceDownload(w, r, appName)
+		return
+	}
+	if appName, ok := strings.CutSuffix(name, "/hibernate"); ok {
+		s.handleApplicationHibernation(w, r, appName)
+		return
+	}
+	if appName, ok := strings.CutSuffix(name, "/resume"); ok {
+		s.handleApplicationResume(w, r, appName)
+		return
+	}
+	if appName, ok := strings.CutSuffix(name, "/provider-pins"); ok {
+		s.handleApplicationProviderPins(w, r, appName)
+		return
+	}
+
 	switch r.Method {
 	case "GET":
 		s.handleGetSpec(w, r, name)
@@ -493,12 +769,9 @@ func (s *Server) handleListSpecs(w http.ResponseWriter, r *http.Request) {
 	var apps []*database.Application
 	var err error
 
-	// Admin users can see all specs, regular users only see their team's specs
-	if user.IsAdmin() {
-		apps, err = s.db.ListApplications()
-	} else {
-		apps, err = s.db.ListApplicationsByTeam(user.Team)
-	}
+	// Admin users can see all specs, regular users their team's and those of the teams
+	// in organizations they administer
+	apps, err = s.listVisibleApplications(user)
 
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to list applications: %v", err), http.StatusInternalServerError)
@@ -529,6 +802,8 @@ func (s *Server) handleListSpecs(w http.ResponseWriter, r *http.Request) {
 // upsertGraphNode adds a node to the graph if it doesn't exist, or updates it if it does.
 // This provides idempotent node creation for application updates.
 func (s *Server) upsertGraphNode(runID string, node *sdk.Node) error {
+	logger := logging.NewStructuredLogger("server")
+
 	if s.graphAdapter == nil {
 		return fmt.Errorf("graph adapter is nil")
 	}
@@ -543,7 +818,7 @@ func (s *Server) upsertGraphNode(runID string, node *sdk.Node) error {
 		if err := s.graphAdapter.AddNode(runID, node); err != nil {
 			return fmt.Errorf("failed to add new node: %w", err)
 		}
-		fmt.Printf("📊 Created %s node in graph: %s\n", node.Type, node.Name)
+		logger.Infof("Created %s node in graph: %s", node.Type, node.Name)
 		return nil
 	}
 
@@ -553,7 +828,7 @@ func (s *Server) upsertGraphNode(runID string, node *sdk.Node) error {
 		// Node exists - update its properties if needed
 		// For now, we'll skip updating since nodes are immutable in most cases
 		// The key is to avoid the duplicate key error
-		fmt.Printf("⏭️  Graph node %s already exists, skipping (state: %s)\n", node.ID, existingNode.State)
+		logger.Infof("Graph node %s already exists, skipping (state: %s)", node.ID, existingNode.State)
 		return nil
 	}
 
@@ -561,13 +836,15 @@ func (s *Server) upsertGraphNode(runID string, node *sdk.Node) error {
 	if err := s.graphAdapter.AddNode(runID, node); err != nil {
 		return fmt.Errorf("failed to add node: %w", err)
 	}
-	fmt.Printf("📊 Created %s node in graph: %s\n", node.Type, node.Name)
+	logger.Infof("Created %s node in graph: %s", node.Type, node.Name)
 	return nil
 }
 
 // upsertGraphEdge adds an edge to the graph if it doesn't exist, or skips if it does.
 // This provides idempotent edge creation for application updates.
 func (s *Server) upsertGraphEdge(runID string, edge *sdk.Edge) error {
+	logger := logging.NewStructuredLogger("server")
+
 	if s.graphAdapter == nil {
 		return fmt.Errorf("graph adapter is nil")
 	}
@@ -582,7 +859,7 @@ func (s *Server) upsertGraphEdge(runID string, edge *sdk.Edge) error {
 		if err := s.graphAdapter.AddEdge(runID, edge); err != nil {
 			return fmt.Errorf("failed to add new edge: %w", err)
 		}
-		fmt.Printf("📊 Created edge in graph: %s\n", edge.ID)
+		logger.Infof("Created edge in graph: %s", edge.ID)
 		return nil
 	}
 
@@ -590,7 +867,7 @@ func (s *Server) upsertGraphEdge(runID string, edge *sdk.Edge) error {
 	existingEdge, exists := graph.GetEdge(edge.ID)
 	if exists {
 		// Edge exists - skip to avoid duplicate
-		fmt.Printf("⏭️  Graph edge %s already exists, skipping (type: %s)\n", edge.ID, existingEdge.Type)
+		logger.Infof("Graph edge %s already exists, skipping (type: %s)", edge.ID, existingEdge.Type)
 		return nil
 	}
 
@@ -598,21 +875,24 @@ func (s *Server) upsertGraphEdge(runID string, edge *sdk.Edge) error {
 	if err := s.graphAdapter.AddEdge(runID, edge); err != nil {
 		return fmt.Errorf("failed to add edge: %w", err)
 	}
-	fmt.Printf("📊 Created edge in graph: %s\n", edge.ID)
+	logger.Infof("Created edge in graph: %s", edge.ID)
 	return nil
 }
 
 func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), "server")
+
 	// Get user from context (set by authentication middleware)
 	user := s.getUserFromContext(r)
 	if user == nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
+	startedAt := time.Now()
 
-	body, err := io.ReadAll(r.Body)
+	body, files, isMultipart, err := readDeployRequest(r)
 	if err != nil {
-		http.Error(w, "Error reading request body", http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Error: %v", err), http.StatusBadRequest)
 		return
 	}
 
@@ -628,6 +908,7 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Resource validation failed: %v", err), http.StatusBadRequest)
 		return
 	}
+	resourceWarnings := s.resourceTypeWarnings(&spec)
 
 	// Validate that metadata.name is not empty
 	name := spec.Metadata.Name
@@ -642,6 +923,10 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Error: metadata.name must be a valid DNS label: %v", err), http.StatusBadRequest)
 		return
 	}
+	logger = logging.FromContext(logging.WithApp(r.Context(), name), "server")
+	for _, warning := range resourceWarnings {
+		logger.Warnf("Deprecated: %s", warning)
+	}
 
 	// Validate that at least one container is defined
 	if len(spec.Containers) == 0 {
@@ -663,26 +948,107 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Validate application-level workflow variables against the admin allow-list
+	if err := s.validateApplicationVariables(&spec); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Enforce the organisation's lint profile
+	if !s.enforceLintProfile(w, r, &spec) {
+		return
+	}
+
+	// Validate dependsOn and reject specs that would close a dependency cycle
+	if err := s.validateApplicationDependencies(&spec); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Upstream applications must be deployed and healthy before this one deploys
+	if len(spec.DependsOn) > 0 && s.db != nil {
+		if blocking := orchestration.BlockingUpstreams(s.upstreamChecker().Check(spec.DependsOn)); len(blocking) > 0 {
+			logger.Warnf("Deployment of '%s' blocked by %d upstream application(s)", name, len(blocking))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			if err := json.NewEncoder(w).Encode(map[string]interface{}{
+				"message":    fmt.Sprintf("Deployment of '%s' is blocked until its upstream applications are ready", name),
+				"name":       name,
+				"status":     "blocked",
+				"blocked_by": blocking,
+			}); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+			}
+			return
+		}
+	}
+
+	// A dry run only reports what the deployment would change
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
 	// CRITICAL FIX: Check if application exists (UPDATE vs CREATE)
 	existingApp, err := s.db.GetApplication(name)
 	isUpdate := (err == nil && existingApp != nil)
 
-	if isUpdate {
-		fmt.Printf("📝 Updating existing application: %s\n", name)
+	if isUpdate && !dryRun {
+		logger.Infof("Updating existing application: %s", name)
+
+		// A deployment is activity, so a hibernated application is resumed first
+		if hibernated, err := s.db.GetApplicationHibernation(name); err == nil && hibernated != nil {
+			if _, err := s.resumeApplication(r.Context(), name, "resumed by deployment", user.Username); err != nil {
+				http.Error(w, fmt.Sprintf("Error resuming hibernated application: %v", err), http.StatusInternalServerError)
+				return
+			}
+		}
 	} else {
 		if err != nil {
-			fmt.Printf("🆕 Creating new application: %s (GetApplication error: %v)\n", name, err)
+			logger.Infof("Creating new application: %s (GetApplication error: %v)", name, err)
 		} else {
-			fmt.Printf("🆕 Creating new application: %s (app not found)\n", name)
+			logger.Infof("Creating new application: %s (app not found)", name)
 		}
 	}
 
+	// Apply the quotas and policies the team inherits from its organization
+	if err := s.checkOrganizationPolicies(user.Team, &spec); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %v", err), http.StatusForbidden)
+		return
+	}
+
+	// The hostname from the naming convention must not belong to another application
+	hostname, err := s.planApplicationHostname(name, user.Team, &spec, user.Username)
+	if err != nil {
+		writeHostnameError(w, err)
+		return
+	}
+
+	if dryRun {
+		s.writeDryRunPreview(w, r, user, &spec, "", nil, "")
+		return
+	}
+
 	// Store/update application spec (UPSERT)
 	err = s.db.AddApplication(name, &spec, user.Team, user.Username)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error storing application: %v", err), http.StatusInternalServerError)
 		return
 	}
+	if hostname != nil {
+		if err := s.db.ReserveHostname(hostname); err != nil {
+			writeHostnameError(w, err)
+			return
+		}
+		logger.Infof("Reserved hostname %s for %s", hostname.Hostname, name)
+	}
+
+	// Files uploaded with the spec replace those of the previous revision; a raw YAML
+	// deploy keeps them
+	if isMultipart {
+		if err := s.db.ReplaceApplicationFiles(name, applicationFiles(files, body, user.Username)); err != nil {
+			http.Error(w, fmt.Sprintf("Error storing application files: %v", err), http.StatusInternalServerError)
+			return
+		}
+		logger.Infof("Stored %d file(s) with the spec of '%s'", len(files), name)
+	}
 
 	// Create team, application, and spec nodes in graph with proper hierarchy
 	// CRITICAL FIX: Use upsert operations to handle both create and update scenarios
@@ -699,7 +1065,7 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 			},
 		}
 		if err := s.upsertGraphNode(name, teamNode); err != nil {
-			fmt.Printf("Warning: failed to upsert team node to graph: %v\n", err)
+			logger.Warnf("Failed to upsert team node to graph: %v", err)
 		}
 
 		// 2. Upsert application node (idempotent)
@@ -716,7 +1082,7 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 			},
 		}
 		if err := s.upsertGraphNode(name, appNode); err != nil {
-			fmt.Printf("Warning: failed to upsert application node to graph: %v\n", err)
+			logger.Warnf("Failed to upsert application node to graph: %v", err)
 		}
 
 		// 3. Upsert edge: team → owns → application (idempotent)
@@ -730,7 +1096,7 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 			},
 		}
 		if err := s.upsertGraphEdge(name, teamOwnsAppEdge); err != nil {
-			fmt.Printf("Warning: failed to upsert team→app edge to graph: %v\n", err)
+			logger.Warnf("Failed to upsert team→app edge to graph: %v", err)
 		}
 
 		// 4. Upsert spec node (idempotent)
@@ -748,7 +1114,7 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 			},
 		}
 		if err := s.upsertGraphNode(name, specNode); err != nil {
-			fmt.Printf("Warning: failed to upsert spec node to graph: %v\n", err)
+			logger.Warnf("Failed to upsert spec node to graph: %v", err)
 		}
 
 		// 5. Upsert edge: application → has-spec → spec (idempotent)
@@ -762,20 +1128,23 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 			},
 		}
 		if err := s.upsertGraphEdge(name, appHasSpecEdge); err != nil {
-			fmt.Printf("Warning: failed to upsert app→spec edge to graph: %v\n", err)
+			logger.Warnf("Failed to upsert app→spec edge to graph: %v", err)
 		}
+
+		// 6. Upsert edges: application → depends-on → upstream application
+		s.upsertDependencyGraphEdges(name, &spec)
 	}
 
 	// Create resource instances if database is available
 	// CRITICAL FIX: For updates, only create NEW resources that don't exist yet
 	if s.resourceManager != nil && s.db != nil {
 		if isUpdate {
-			fmt.Printf("Checking for new resources to add to app '%s'...\n", name)
+			logger.Infof("Checking for new resources to add to app '%s'...", name)
 
 			// Get existing resources
 			existingResources, err := s.resourceManager.GetResourcesByApplication(name)
 			if err != nil {
-				fmt.Printf("Warning: Failed to get existing resources: %v\n", err)
+				logger.Warnf("Failed to get existing resources: %v", err)
 				existingResources = []*database.ResourceInstance{} // Treat as empty
 			}
 
@@ -789,11 +1158,11 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 			newResourceCount := 0
 			for resourceName, resource := range spec.Resources {
 				if existingNames[resourceName] {
-					fmt.Printf("⏭️  Resource '%s' already exists, skipping\n", resourceName)
+					logger.Infof("Resource '%s' already exists, skipping", resourceName)
 					continue
 				}
 
-				fmt.Printf("➕ Creating new resource: %s (%s)\n", resourceName, resource.Type)
+				logger.Infof("Creating new resource: %s (%s)", resourceName, resource.Type)
 
 				// Build configuration
 				config := map[string]interface{}{
@@ -821,20 +1190,20 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 			}
 
 			if newResourceCount > 0 {
-				fmt.Printf("✅ Successfully created %d new resource(s) for app '%s'\n", newResourceCount, name)
+				logger.Infof("Successfully created %d new resource(s) for app '%s'", newResourceCount, name)
 			} else {
-				fmt.Printf("ℹ️  No new resources to create for app '%s'\n", name)
+				logger.Infof("No new resources to create for app '%s'", name)
 			}
 		} else {
 			// New application - create all resources
-			fmt.Printf("Creating resource instances for new app '%s'...\n", name)
+			logger.Infof("Creating resource instances for new app '%s'...", name)
 			err = s.resourceManager.CreateResourceFromSpec(name, &spec, user.Username)
 			if err != nil {
 				// CRITICAL FIX: Fail deployment if resources cannot be created
 				http.Error(w, fmt.Sprintf("Failed to create resource instances: %v", err), http.StatusInternalServerError)
 				return
 			}
-			fmt.Printf("✅ Successfully created resource instances for app '%s'\n", name)
+			logger.Infof("Successfully created resource instances for app '%s'", name)
 		}
 	}
 
@@ -852,13 +1221,13 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 		argocdExists, _ := s.resourceManager.GetResourceByName(name, argocdName)
 
 		if isUpdate && giteaExists != nil && k8sExists != nil && argocdExists != nil {
-			fmt.Printf("\nℹ️  GitOps pipeline resources already exist for '%s', skipping creation\n\n", name)
+			logger.Infof("GitOps pipeline resources already exist for '%s', skipping creation", name)
 		} else {
-			fmt.Printf("\n🚀 Creating GitOps pipeline resources for '%s' (will be auto-provisioned)...\n", name)
+			logger.Infof("Creating GitOps pipeline resources for '%s' (will be auto-provisioned)...", name)
 
 			// Step 1: Create Gitea repository resource (if not exists)
 			if giteaExists == nil {
-				fmt.Printf("\n📚 Step 1/3: Creating Gitea repository resource for '%s'...\n", name)
+				logger.Infof("Step 1/3: Creating Gitea repository resource for '%s'...", name)
 				_, err := s.resourceManager.CreateResourceInstance(
 					name,
 					giteaName,
@@ -873,14 +1242,14 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 					http.Error(w, fmt.Sprintf("Failed to create gitea-repo resource: %v", err), http.StatusInternalServerError)
 					return
 				}
-				fmt.Printf("✅ Created gitea-repo resource (state: requested)\n")
+				logger.Info("Created gitea-repo resource (state: requested)")
 			} else {
-				fmt.Printf("\n📚 Step 1/3: Gitea repository resource already exists\n")
+				logger.Info("Step 1/3: Gitea repository resource already exists")
 			}
 
 			// Step 2: Create Kubernetes deployment resource (if not exists)
 			if k8sExists == nil {
-				fmt.Printf("\n☸️  Step 2/3: Creating Kubernetes deployment resource for '%s'...\n", name)
+				logger.Infof("Step 2/3: Creating Kubernetes deployment resource for '%s'...", name)
 				_, err := s.resourceManager.CreateResourceInstance(
 					name,
 					k8sName,
@@ -894,14 +1263,14 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 					http.Error(w, fmt.Sprintf("Failed to create kubernetes resource: %v", err), http.StatusInternalServerError)
 					return
 				}
-				fmt.Printf("✅ Created kubernetes resource (state: requested)\n")
+				logger.Info("Created kubernetes resource (state: requested)")
 			} else {
-				fmt.Printf("\n☸️  Step 2/3: Kubernetes deployment resource already exists\n")
+				logger.Info("Step 2/3: Kubernetes deployment resource already exists")
 			}
 
 			// Step 3: Create ArgoCD Application resource (if not exists)
 			if argocdExists == nil {
-				fmt.Printf("\n🔄 Step 3/3: Creating ArgoCD Application resource for '%s'...\n", name)
+				logger.Infof("Step 3/3: Creating ArgoCD Application resource for '%s'...", name)
 				_, err := s.resourceManager.CreateResourceInstance(
 					name,
 					argocdName,
@@ -916,12 +1285,12 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 					http.Error(w, fmt.Sprintf("Failed to create argocd-app resource: %v", err), http.StatusInternalServerError)
 					return
 				}
-				fmt.Printf("✅ Created argocd-app resource (state: requested)\n")
+				logger.Info("Created argocd-app resource (state: requested)")
 			} else {
-				fmt.Printf("\n🔄 Step 3/3: ArgoCD Application resource already exists\n")
+				logger.Info("Step 3/3: ArgoCD Application resource already exists")
 			}
 
-			fmt.Printf("\n✅ GitOps pipeline resources ready - orchestration engine will provision new ones\n\n")
+			logger.Info("GitOps pipeline resources ready - orchestration engine will provision new ones")
 		}
 	}
 
@@ -933,14 +1302,14 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 	// Execute workflows if defined
 	if spec.Workflows != nil {
 		for workflowName, workflowDef := range spec.Workflows {
-			fmt.Printf("Executing workflow '%s' for app '%s'...\n", workflowName, name)
+			logger.Infof("Executing workflow '%s' for app '%s'...", workflowName, name)
 
 			// Track workflow execution in memory (for non-database mode) or database
 			var memoryExecution *MemoryWorkflowExecution
 			if s.workflowExecutor == nil {
 				// Use in-memory tracking when database is not available
 				memoryExecution = s.CreateMemoryWorkflowExecution(name, workflowName, len(workflowDef.Steps))
-				fmt.Printf("📝 Tracking workflow execution ID %d in memory\n", memoryExecution.ID)
+				logger.Infof("Tracking workflow execution ID %d in memory", memoryExecution.ID)
 			}
 
 			// Use enhanced workflow execution with appName and envType
@@ -958,13 +1327,13 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 				failedWorkflows = append(failedWorkflows, workflowName)
 				workflowErrors = append(workflowErrors, err.Error())
 
-				fmt.Printf("❌ Workflow '%s' execution failed for '%s': %v\n", workflowName, name, err)
+				logger.Errorf("Workflow '%s' execution failed for '%s': %v", workflowName, name, err)
 			} else {
 				// Update tracking with success
 				if memoryExecution != nil {
 					s.UpdateMemoryWorkflowExecutionStatus(memoryExecution.ID, "completed", nil)
 				}
-				fmt.Printf("✅ Workflow '%s' completed successfully for '%s'\n", workflowName, name)
+				logger.Infof("Workflow '%s' completed successfully for '%s'", workflowName, name)
 			}
 		}
 	}
@@ -991,12 +1360,33 @@ func (s *Server) handleDeploySpec(w http.ResponseWriter, r *http.Request) {
 			"status":  "success",
 		}
 		statusCode = http.StatusCreated
+
+		workflows, providers := s.specProvenance(&spec)
+		for workflowName, workflowDef := range spec.Workflows {
+			if data, err := yaml.Marshal(workflowDef); err == nil {
+				workflows = append(workflows, provenance.Workflow{Name: workflowName, Source: "score:workflows." + workflowName, Digest: provenance.Digest(data)})
+			}
+		}
+		s.recordDeploymentProvenance(&provenance.Document{
+			Application: name,
+			Team:        user.Team,
+			Kind:        "deploy",
+			SpecDigest:  provenance.Digest(body),
+			Workflows:   workflows,
+			Providers:   providers,
+			TriggeredBy: user.Username,
+			StartedAt:   startedAt,
+			FinishedAt:  time.Now(),
+		})
 	}
 
 	// Add environment creation message if applicable
 	if spec.Environment != nil && spec.Environment.Type == "ephemeral" {
 		response["environment"] = fmt.Sprintf("Creating ephemeral environment with TTL=%s", spec.Environment.TTL)
 	}
+	if len(resourceWarnings) > 0 {
+		response["warnings"] = resourceWarnings
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -1011,12 +1401,17 @@ func (s *Server) validateResourceTypes(spec *types.ScoreSpec) error {
 		return nil // Skip validation if no resolver (backward compatible)
 	}
 
-	var unknownTypes []string
+	var unknownTypes, unresolved []string
 	for resourceName, resource := range spec.Resources {
 		_, _, err := s.providerResolver.ResolveProviderForResource(resource.Type)
-		if err != nil {
+		if err == nil {
+			continue
+		}
+		if strings.HasPrefix(err.Error(), "no provider found") {
 			unknownTypes = append(unknownTypes, fmt.Sprintf(
 				"%s (type: %s)", resourceName, resource.Type))
+		} else {
+			unresolved = append(unresolved, fmt.Sprintf("%s: %v", resourceName, err))
 		}
 	}
 
@@ -1025,10 +1420,36 @@ func (s *Server) validateResourceTypes(spec *types.ScoreSpec) error {
 			"unknown resource types (no provider registered): %s",
 			strings.Join(unknownTypes, ", "))
 	}
+	if len(unresolved) > 0 {
+		sort.Strings(unresolved)
+		return fmt.Errorf("unresolvable resource types: %s", strings.Join(unresolved, "; "))
+	}
 
 	return nil
 }
 
+// resourceTypeWarnings returns the deprecation notices of the resource types of a spec
+// that still resolve during their grace period, sorted by resource name
+func (s *Server) resourceTypeWarnings(spec *types.ScoreSpec) []string {
+	if s.providerResolver == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(spec.Resources))
+	for name := range spec.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		if notice := s.providerResolver.DeprecationNotice(spec.Resources[name].Type); notice != "" {
+			warnings = append(warnings, fmt.Sprintf("resource '%s': %s", name, notice))
+		}
+	}
+	return warnings
+}
+
 // validateDNSLabel validates that a string is a valid RFC 1123 DNS label
 // Must be lowercase alphanumeric with hyphens, start/end with alphanumeric
 // Maximum 63 characters
@@ -1074,6 +1495,19 @@ func (s *Server) validateUniqueResourceNames(spec *types.ScoreSpec) error {
 	return nil
 }
 
+// validateApplicationVariables checks Score metadata.variables against the keys allowed in admin-config.yaml
+func (s *Server) validateApplicationVariables(spec *types.ScoreSpec) error {
+	if spec == nil || len(spec.Metadata.Variables) == 0 {
+		return nil
+	}
+
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return fmt.Errorf("metadata.variables requires workflowPolicies.applicationVariables in the admin config: %w", err)
+	}
+	return adminConfig.ValidateApplicationVariables(spec.Metadata.Variables)
+}
+
 func (s *Server) HandleSpecDetail(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Path[len("/api/specs/"):]
 
@@ -1102,7 +1536,7 @@ func (s *Server) handleGetSpec(w http.ResponseWriter, r *http.Request, name stri
 	}
 
 	// Check if user has access to this spec
-	if !user.IsAdmin() && app.Team != user.Team {
+	if !s.canAccessTeam(user, app.Team) {
 		http.Error(w, "Access denied", http.StatusForbidden)
 		return
 	}
@@ -1136,7 +1570,7 @@ func (s *Server) handleDeleteSpec(w http.ResponseWriter, r *http.Request, name s
 	}
 
 	// Check if user has access to this spec
-	if !user.IsAdmin() && app.Team != user.Team {
+	if !s.canAccessTeam(user, app.Team) {
 		http.Error(w, "Access denied", http.StatusForbidden)
 		return
 	}
@@ -1248,6 +1682,16 @@ func (s *Server) HandleGraph(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		// Check if it's a cross-application dependencies request
+		if strings.Contains(remainder, "/dependencies") {
+			parts := strings.Split(remainder, "/dependencies")
+			if len(parts) == 2 && parts[0] != "" {
+				appName := parts[0]
+				s.handleApplicationDependencies(w, r, appName)
+				return
+			}
+		}
+
 		// Check if it's a metrics request
 		if strings.Contains(remainder, "/metrics") {
 			parts := strings.Split(remainder, "/metrics")
@@ -1687,6 +2131,11 @@ func (s *Server) HandleWorkflows(w http.ResponseWriter, r *http.Request) {
 
 // HandleWorkflowDetail handles individual workflow execution requests
 func (s *Server) HandleWorkflowDetail(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/workflows/compare" {
+		s.handleCompareWorkflows(w, r)
+		return
+	}
+
 	if s.workflowExecutor == nil {
 		// Use in-memory workflow tracking when database is not available
 		s.handleGetMemoryWorkflow(w, r)
@@ -1707,6 +2156,12 @@ func (s *Server) HandleWorkflowDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check for step debug sub-routes: /api/workflows/{id}/steps/{stepID}/debug[/workspace]
+	if idx := strings.Index(path, "/steps/"); idx >= 0 {
+		s.handleStepDebug(w, r, workflowID, path[idx+len("/steps/"):])
+		return
+	}
+
 	// Check for retry sub-route: /api/workflows/{id}/retry
 	if strings.HasSuffix(path, "/retry") {
 		if r.Method == "POST" {
@@ -1717,6 +2172,28 @@ func (s *Server) HandleWorkflowDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check for replay sub-route: /api/workflows/{id}/replay
+	if strings.HasSuffix(path, "/replay") {
+		if r.Method == "POST" {
+			s.handleWorkflowReplay(w, r, workflowID)
+			return
+		}
+		http.Error(w, "Method not allowed - use POST for replay", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Check for rollback sub-route: /api/workflows/{id}/rollback
+	if strings.HasSuffix(path, "/rollback") {
+		s.handleWorkflowRollback(w, r, workflowID)
+		return
+	}
+
+	// Check for bundle sub-route: /api/workflows/{id}/bundle
+	if strings.HasSuffix(path, "/bundle") {
+		s.handleWorkflowBundle(w, r, workflowID)
+		return
+	}
+
 	switch r.Method {
 	case "GET":
 		s.handleGetWorkflow(w, r, workflowID)
@@ -1806,6 +2283,7 @@ func (s *Server) handleGetWorkflow(w http.ResponseWriter, r *http.Request, workf
 		http.Error(w, fmt.Sprintf("Failed to get workflow: %v", err), http.StatusInternalServerError)
 		return
 	}
+	workflow.Progress = s.workflowExecutor.Progress(workflow)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(workflow); err != nil {
@@ -1967,6 +2445,9 @@ func (s *Server) handleCreateTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Create the team's Gitea organization with the configured teams and permissions
+	s.provisionGiteaTeam(team)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(team); err != nil {
@@ -2006,48 +2487,15 @@ func (s *Server) handleDeleteTeam(w http.ResponseWriter, r *http.Request, teamID
 	}
 }
 
-// Rate limiting for login attempts
-const (
-	maxLoginAttempts = 5
-	lockoutDuration  = 15 * time.Minute
-)
-
-func (s *Server) isRateLimited(clientIP string) bool {
-	s.loginMutex.Lock()
-	defer s.loginMutex.Unlock()
-
-	now := time.Now()
-	attempts, exists := s.loginAttempts[clientIP]
-
-	if !exists {
-		return false
-	}
-
-	// Remove old attempts outside the lockout window
-	validAttempts := []time.Time{}
-	for _, attempt := range attempts {
-		if now.Sub(attempt) < lockoutDuration {
-			validAttempts = append(validAttempts, attempt)
-		}
+// loginThrottle returns the login throttle configured in admin-config.yaml. Failed
+// logins live in the database when one is connected, so lockouts hold across restarts
+// and replicas.
+func (s *Server) loginThrottle() (*auth.LoginThrottle, error) {
+	var config auth.ThrottleConfig
+	if adminConfig, err := admin.LoadAdminConfig("admin-config.yaml"); err == nil {
+		config = adminConfig.Authentication.LoginThrottle
 	}
-	s.loginAttempts[clientIP] = validAttempts
-
-	return len(validAttempts) >= maxLoginAttempts
-}
-
-func (s *Server) recordLoginAttempt(clientIP string) {
-	s.loginMutex.Lock()
-	defer s.loginMutex.Unlock()
-
-	now := time.Now()
-	s.loginAttempts[clientIP] = append(s.loginAttempts[clientIP], now)
-}
-
-func (s *Server) clearLoginAttempts(clientIP string) {
-	s.loginMutex.Lock()
-	defer s.loginMutex.Unlock()
-
-	delete(s.loginAttempts, clientIP)
+	return auth.NewLoginThrottle(config, s.loginAttempts, s.Clock().Now)
 }
 
 func getClientIP(r *http.Request) string {
@@ -2108,6 +2556,12 @@ func (s *Server) HandleReady(w http.ResponseWriter, r *http.Request) {
 
 // HandleMetrics returns Prometheus-format metrics
 func (s *Server) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	if s.workflowQueue != nil {
+		stats := s.workflowQueue.GetQueueStats()
+		queued, _ := stats["queue_size"].(int)
+		active, _ := stats["active_tasks"].(int)
+		metrics.GetGlobal().SetWorkflowQueueDepth(queued, active)
+	}
 	metricsData := metrics.GetGlobal().Export()
 
 	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
@@ -2274,9 +2728,9 @@ func (s *Server) ListMemoryWorkflowExecutions(appName string, limit, offset int)
 
 // runWorkflowWithTracking executes a workflow with step-by-step tracking
 func (s *Server) runWorkflowWithTracking(workflowDef types.Workflow, appName, envType string, memoryExecution *MemoryWorkflowExecution) error {
-	// If database is available, use the standard database-tracked execution
+	// Step processes inherit the server variables the admin allowed
 	if s.workflowExecutor != nil {
-		return workflow.RunWorkflow(workflowDef, appName, envType)
+		return s.workflowExecutor.RunWorkflow(workflowDef, appName, envType)
 	}
 
 	// Otherwise, use in-memory tracking - just delegate to the existing RunWorkflow for now
@@ -2348,9 +2802,11 @@ func (s *Server) handleGetMemoryWorkflow(w http.ResponseWriter, r *http.Request)
 
 // saveWorkflowsToDisk saves workflow executions to disk
 func (s *Server) saveWorkflowsToDisk() {
+	logger := logging.NewStructuredLogger("server")
+
 	// Create data directory if it doesn't exist
 	if err := os.MkdirAll("data", 0750); err != nil {
-		fmt.Printf("Warning: Failed to create data directory: %v\n", err)
+		logger.Warnf("Failed to create data directory: %v", err)
 		return
 	}
 
@@ -2365,18 +2821,20 @@ func (s *Server) saveWorkflowsToDisk() {
 
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		fmt.Printf("Warning: Failed to marshal workflow data: %v\n", err)
+		logger.Warnf("Failed to marshal workflow data: %v", err)
 		return
 	}
 
 	// Write to file
 	if err := os.WriteFile("data/workflows.json", jsonData, 0600); err != nil {
-		fmt.Printf("Warning: Failed to write workflow file: %v\n", err)
+		logger.Warnf("Failed to write workflow file: %v", err)
 	}
 }
 
 // loadWorkflowsFromDisk loads workflow executions from disk
 func (s *Server) loadWorkflowsFromDisk() {
+	logger := logging.NewStructuredLogger("server")
+
 	filePath := "data/workflows.json"
 
 	// Check if file exists
@@ -2387,7 +2845,7 @@ func (s *Server) loadWorkflowsFromDisk() {
 
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		fmt.Printf("Warning: Failed to read workflow file: %v\n", err)
+		logger.Warnf("Failed to read workflow file: %v", err)
 		return
 	}
 
@@ -2397,14 +2855,14 @@ func (s *Server) loadWorkflowsFromDisk() {
 	}
 
 	if err := json.Unmarshal(data, &workflowData); err != nil {
-		fmt.Printf("Warning: Failed to unmarshal workflow data: %v\n", err)
+		logger.Warnf("Failed to unmarshal workflow data: %v", err)
 		return
 	}
 
 	// Load data into memory
 	if workflowData.Workflows != nil {
 		s.memoryWorkflows = workflowData.Workflows
-		fmt.Printf("⚙️  Loaded %d workflow executions from disk\n", len(s.memoryWorkflows))
+		logger.Infof("Loaded %d workflow executions from disk", len(s.memoryWorkflows))
 	}
 
 	if workflowData.WorkflowCounter > 0 {
@@ -2619,42 +3077,45 @@ func (s *Server) HandleAdminConfig(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// HandleAdminReload handles POST /api/admin/reload - Reloads admin-config.yaml and providers
+// HandleAdminReload handles POST /api/admin/reload - Reloads admin-config.yaml. CORS
+// origins, rate limits, authentication providers and notification channels are swapped
+// in at once, and only when all of them are valid; providers are reloaded afterwards.
 func (s *Server) HandleAdminReload(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// This endpoint requires provider registry and reload function
-	if s.providerRegistry == nil || s.providersReloadFunc == nil {
-		http.Error(w, "Provider reload not configured", http.StatusServiceUnavailable)
-		return
-	}
-
-	// Verify admin config exists and is loadable before attempting reload
-	_, err := admin.LoadAdminConfig("admin-config.yaml")
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to load admin config: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	// Trigger provider reload
-	if err := s.providersReloadFunc(); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to reload providers: %v", err), http.StatusInternalServerError)
+	// Requests in flight keep the settings they started with
+	if err := s.ApplyRuntimeConfig(adminConfig); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid admin config, nothing was reloaded: %v", err), http.StatusBadRequest)
 		return
 	}
-
-	// Get updated provider counts
-	providerCount, provisionerCount := s.providerRegistry.Count()
+	reloaded := []string{"cors", "rateLimit", "authentication", "notifications"}
 
 	response := map[string]interface{}{
-		"success":      true,
-		"message":      "Providers reloaded successfully",
-		"providers":    providerCount,
-		"provisioners": provisionerCount,
-		"timestamp":    time.Now(),
+		"success":   true,
+		"message":   "Admin config reloaded successfully",
+		"timestamp": time.Now(),
+	}
+
+	if s.providerRegistry != nil && s.providersReloadFunc != nil {
+		if err := s.providersReloadFunc(); err != nil {
+			http.Error(w, fmt.Sprintf("Server settings reloaded, but failed to reload providers: %v", err), http.StatusInternalServerError)
+			return
+		}
+		reloaded = append(reloaded, "providers")
+		providerCount, provisionerCount := s.providerRegistry.Count()
+		response["providers"] = providerCount
+		response["provisioners"] = provisionerCount
 	}
+	response["reloaded"] = reloaded
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -2678,14 +3139,7 @@ func (s *Server) HandleStats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Count applications
-	var apps []*database.Application
-	var err error
-	if user.IsAdmin() {
-		apps, err = s.db.ListApplications()
-	} else {
-		apps, err = s.db.ListApplicationsByTeam(user.Team)
-	}
-
+	apps, err := s.listVisibleApplications(user)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to count applications: %v", err), http.StatusInternalServerError)
 		return
@@ -2728,6 +3182,9 @@ func (s *Server) HandleStats(w http.ResponseWriter, r *http.Request) {
 		"resources":    resourcesCount,
 		"users":        usersCount,
 	}
+	if rollup := organizationRollup(s.organizations(), apps); len(rollup) > 0 {
+		stats["organizations"] = rollup
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(stats); err != nil {
@@ -2735,98 +3192,15 @@ func (s *Server) HandleStats(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// startWorkflowScheduler starts a background goroutine that triggers dummy workflows every minute
-//
-//nolint:unused // Reserved for future workflow scheduling
-func (s *Server) startWorkflowScheduler() {
-	s.workflowTicker = time.NewTicker(1 * time.Minute)
-	s.stopScheduler = make(chan struct{})
-
-	go func() {
-		fmt.Println("Workflow scheduler started - triggering dummy workflow every minute")
-		for {
-			select {
-			case <-s.workflowTicker.C:
-				s.triggerDummyWorkflow()
-			case <-s.stopScheduler:
-				fmt.Println("Workflow scheduler stopped")
-				return
-			}
-		}
-	}()
-}
-
-// stopWorkflowScheduler stops the background workflow scheduler
-//
-//nolint:unused // Reserved for future graceful shutdown implementation
-func (s *Server) stopWorkflowScheduler() {
-	if s.workflowTicker != nil {
-		s.workflowTicker.Stop()
-	}
-	if s.stopScheduler != nil {
-		close(s.stopScheduler)
-	}
-}
-
-// triggerDummyWorkflow loads and executes the dummy workflow
-//
-//nolint:unused // Reserved for future workflow scheduling
-func (s *Server) triggerDummyWorkflow() {
-	// Only trigger if we have a workflow executor (database available)
-	if s.workflowExecutor == nil {
-		return
-	}
-
-	// Load the dummy workflow from file
-	dummyWorkflow, err := s.loadWorkflowFromFile("workflows/dummy.yaml")
-	if err != nil {
-		fmt.Printf("Failed to load dummy workflow: %v\n", err)
-		return
-	}
-
-	// Execute the dummy workflow
-	fmt.Println("Triggering scheduled dummy workflow execution...")
-	err = s.workflowExecutor.ExecuteWorkflowWithName("scheduled", "dummy", *dummyWorkflow)
-	if err != nil {
-		fmt.Printf("Failed to execute dummy workflow: %v\n", err)
-	} else {
-		fmt.Println("✅ Scheduled dummy workflow completed successfully")
-	}
-}
-
-// loadWorkflowFromFile loads a workflow definition from a YAML file
-//
-//nolint:unused // Reserved for future workflow scheduling
-func (s *Server) loadWorkflowFromFile(filePath string) (*types.Workflow, error) {
-	// Validate file path to prevent path traversal
-	cleanPath, err := security.SafeFilePath(filePath, "./workflows", "./data")
-	if err != nil {
-		return nil, fmt.Errorf("invalid workflow path: %w", err)
-	}
-
-	data, err := os.ReadFile(cleanPath) // #nosec G304 - path validated above
-	if err != nil {
-		return nil, fmt.Errorf("failed to read workflow file: %w", err)
-	}
-
-	var workflow types.Workflow
-	err = yaml.Unmarshal(data, &workflow)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse workflow YAML: %w", err)
-	}
-
-	return &workflow, nil
-}
-
-// HandleWorkflowAnalysis handles workflow analysis API requests
-func (s *Server) HandleWorkflowAnalysis(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case "POST":
-		s.handleAnalyzeWorkflow(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
+// HandleWorkflowAnalysis handles workflow analysis API requests
+func (s *Server) HandleWorkflowAnalysis(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "POST":
+		s.handleAnalyzeWorkflow(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
 
 // handleAnalyzeWorkflow analyzes a Score specification and returns workflow analysis
 func (s *Server) handleAnalyzeWorkflow(w http.ResponseWriter, r *http.Request) {
@@ -2862,6 +3236,7 @@ func (s *Server) handleAnalyzeWorkflow(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Failed to analyze workflow: %v", err), http.StatusInternalServerError)
 		return
 	}
+	analysis.ResourcePlans = s.specResourcePlans(r.Context(), &spec)
 
 	// Return analysis result
 	w.Header().Set("Content-Type", "application/json")
@@ -2991,7 +3366,7 @@ func (s *Server) handleDeleteApplication(w http.ResponseWriter, r *http.Request,
 	}
 
 	// Check if user has access to this application
-	if !user.IsAdmin() && app.Team != user.Team {
+	if !s.canAccessTeam(user, app.Team) {
 		http.Error(w, "Access denied", http.StatusForbidden)
 		return
 	}
@@ -3039,7 +3414,7 @@ func (s *Server) handleDeprovisionApplication(w http.ResponseWriter, r *http.Req
 	}
 
 	// Check if user has access to this application
-	if !user.IsAdmin() && app.Team != user.Team {
+	if !s.canAccessTeam(user, app.Team) {
 		http.Error(w, "Access denied", http.StatusForbidden)
 		return
 	}
@@ -3067,7 +3442,8 @@ func (s *Server) handleDeprovisionApplication(w http.ResponseWriter, r *http.Req
 	}
 }
 
-// HandleGoldenPaths handles listing and retrieving golden paths
+// HandleGoldenPaths handles listing and retrieving golden paths, and the applications
+// and migrations of deprecated golden paths under /api/golden-paths/{name}/...
 func (s *Server) HandleGoldenPaths(w http.ResponseWriter, r *http.Request) {
 	// Extract path to check if it's a specific golden path request
 	path := strings.TrimPrefix(r.URL.Path, "/api/golden-paths")
@@ -3076,10 +3452,22 @@ func (s *Server) HandleGoldenPaths(w http.ResponseWriter, r *http.Request) {
 	if path == "" {
 		// List all golden paths
 		s.handleListGoldenPaths(w, r)
-	} else {
+		return
+	}
+
+	goldenPathName, action, _ := strings.Cut(strings.TrimSuffix(path, "/"), "/")
+	switch action {
+	case "":
 		// Get specific golden path metadata
-		goldenPathName := strings.TrimSuffix(path, "/")
 		s.handleGetGoldenPath(w, r, goldenPathName)
+	case "applications":
+		s.handleGoldenPathApplications(w, r, goldenPathName)
+	case "migrate":
+		s.handleGoldenPathMigrate(w, r, goldenPathName)
+	case "migrations":
+		s.handleGoldenPathMigrations(w, r, goldenPathName)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
 	}
 }
 
@@ -3117,6 +3505,9 @@ func (s *Server) handleListGoldenPaths(w http.ResponseWriter, r *http.Request) {
 		if len(metadata.Parameters) > 0 {
 			pathInfo["parameters"] = metadata.Parameters
 		}
+		if metadata.Deprecated != nil {
+			pathInfo["deprecated"] = metadata.Deprecated
+		}
 
 		response[pathName] = pathInfo
 	}
@@ -3158,6 +3549,9 @@ func (s *Server) handleGetGoldenPath(w http.ResponseWriter, r *http.Request, pat
 	if len(metadata.Parameters) > 0 {
 		response["parameters"] = metadata.Parameters
 	}
+	if metadata.Deprecated != nil {
+		response["deprecated"] = metadata.Deprecated
+	}
 
 	// Add deprecated fields for backward compatibility
 	if len(metadata.RequiredParams) > 0 {
@@ -3175,6 +3569,8 @@ func (s *Server) handleGetGoldenPath(w http.ResponseWriter, r *http.Request, pat
 
 // HandleGoldenPathExecution handles golden path workflow execution with resource management integration
 func (s *Server) HandleGoldenPathExecution(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), "server")
+
 	if r.Method != "POST" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -3200,6 +3596,7 @@ func (s *Server) HandleGoldenPathExecution(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
+	startedAt := time.Now()
 
 	// Read Score spec from request body
 	body, err := io.ReadAll(r.Body)
@@ -3208,6 +3605,26 @@ func (s *Server) HandleGoldenPathExecution(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Admins may send {"spec": <Score spec>, "overrides": {...}} to change steps of this run
+	var overrides *types.StepOverrides
+	var envelope struct {
+		Spec      json.RawMessage `json:"spec"`
+		Overrides json.RawMessage `json:"overrides"`
+	}
+	if json.Unmarshal(body, &envelope) == nil && len(envelope.Overrides) > 0 {
+		if !user.IsAdmin() {
+			http.Error(w, "Forbidden: only admins can override steps", http.StatusForbidden)
+			return
+		}
+		overrides, err = workflow.ParseStepOverrides(envelope.Overrides)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		overrides.RequestedBy = user.Username
+		body = envelope.Spec
+	}
+
 	var spec types.ScoreSpec
 	err = yaml.Unmarshal(body, &spec)
 	if err != nil {
@@ -3220,7 +3637,17 @@ func (s *Server) HandleGoldenPathExecution(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	fmt.Printf("🚀 Executing golden path '%s' for application: %s\n", goldenPathName, spec.Metadata.Name)
+	if err := s.validateApplicationVariables(&spec); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if !s.enforceLintProfile(w, r, &spec) {
+		return
+	}
+
+	logger = logging.FromContext(logging.WithApp(r.Context(), spec.Metadata.Name), "server")
+	logger.Infof("Executing golden path '%s' for application: %s", goldenPathName, spec.Metadata.Name)
 
 	// Extract golden path parameters from query string (param.KEY=value)
 	goldenPathParams := make(map[string]string)
@@ -3235,7 +3662,7 @@ func (s *Server) HandleGoldenPathExecution(w http.ResponseWriter, r *http.Reques
 
 	// Log parameters if any were provided
 	if len(goldenPathParams) > 0 {
-		fmt.Printf("   📋 Golden path parameters: %v\n", goldenPathParams)
+		logger.Infof("Golden path parameters: %v", goldenPathParams)
 	}
 
 	// Load golden path workflow
@@ -3261,50 +3688,345 @@ func (s *Server) HandleGoldenPathExecution(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Deprecated golden paths still run, with a warning pointing to their successor
+	deprecationWarning := ""
+	if deprecation := goldenPathDeprecation(goldenPathName); deprecation != nil {
+		deprecationWarning = deprecation.Warning(goldenPathName)
+		logger.Warnf("Deprecated: %s", deprecationWarning)
+		w.Header().Set("Warning", goldenPathWarningHeader(deprecationWarning))
+	}
+
+	// Resolve parameters declared with external sources (vault, http, configmap)
+	if len(workflowSpec.Metadata.ParameterSources) > 0 {
+		resolved, err := s.parameterResolver().Resolve(r.Context(), workflowSpec.Metadata.ParameterSources, goldenPathParams)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to resolve workflow parameters: %v", err), http.StatusBadGateway)
+			return
+		}
+		resolvedSources := make(map[string]types.ParameterSource, len(resolved))
+		for name, value := range resolved {
+			goldenPathParams[name] = value
+			resolvedSources[name] = workflowSpec.Metadata.ParameterSources[name]
+		}
+		logger.Infof("Resolved %d parameter(s) from external sources", len(resolved))
+
+		// Externally sourced values may be secrets; replays resolve them again
+		r = r.WithContext(workflow.WithExternalParameters(r.Context(), resolvedSources))
+	}
+
+	// Step overrides are validated strictly and recorded on the execution
+	if overrides != nil {
+		workflowSpec.Spec, goldenPathParams, err = workflow.ApplyStepOverrides(workflowSpec.Spec, goldenPathParams, overrides)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.WarnWithFields("Golden path runs with admin step overrides", map[string]interface{}{
+			"golden_path":  goldenPathName,
+			"requested_by": overrides.RequestedBy,
+			"reason":       overrides.Reason,
+			"steps":        overrides.Steps,
+			"variables":    overrides.Variables,
+		})
+		r = r.WithContext(workflow.WithStepOverrides(r.Context(), overrides))
+	}
+
+	// Pre-flight checks report every problem before anything is created; preflight=true
+	// only runs the checks. Test runs use a sandbox the checks do not describe.
+	preflightOnly := r.URL.Query().Get("preflight") == "true"
+	if s.workflowExecutor != nil && r.URL.Query().Get("test") != "true" && (preflightOnly || len(workflowSpec.Spec.Preflight) > 0) {
+		report := s.workflowExecutor.Preflight(r.Context(), workflowSpec.Spec, spec.Metadata.Name, goldenPathParams, s.providerLookup())
+		if !report.Passed || preflightOnly {
+			s.writePreflightReport(w, goldenPathName, spec.Metadata.Name, report)
+			return
+		}
+		logger.Infof("Pre-flight checks passed for golden path '%s'", goldenPathName)
+	}
+
+	// Apply the defaults of declared inputs and reject runs that miss a required one
+	goldenPathParams, err = workflow.ApplyInputs(workflowSpec.Spec.Inputs, goldenPathParams)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Extract the actual workflow from the spec
 	workflow := workflowSpec.Spec
 
-	// Store the Score spec first
-	err = s.db.AddApplication(spec.Metadata.Name, &spec, user.Team, user.Username)
+	// A dry run only reports what the golden path would change and run
+	if r.URL.Query().Get("dry_run") == "true" {
+		if err := s.validateResourceTypes(&spec); err != nil {
+			http.Error(w, fmt.Sprintf("Resource validation failed: %v", err), http.StatusBadRequest)
+			return
+		}
+		environment := goldenPathParams["environment"]
+		if environment == "" && spec.Environment != nil {
+			environment = spec.Environment.Type
+		}
+		s.writeDryRunPreview(w, r, user, &spec, goldenPathName, &workflow, environment)
+		return
+	}
+
+	// Test mode runs against a throwaway sandbox that is torn down afterwards
+	if r.URL.Query().Get("test") == "true" {
+		s.runGoldenPathTest(w, r, goldenPathName, &spec, workflow, goldenPathParams, user)
+		return
+	}
+
+	// The hostname from the naming convention must not belong to another application
+	hostname, err := s.planApplicationHostname(spec.Metadata.Name, user.Team, &spec, user.Username)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Error storing application: %v", err), http.StatusInternalServerError)
+		writeHostnameError(w, err)
+		return
+	}
+
+	// Protected environments pause terraform apply until the plan is approved
+	environment := goldenPathParams["environment"]
+	if environment == "" && spec.Environment != nil {
+		environment = spec.Environment.Type
+	}
+	requiresApproval := s.workflowExecutor != nil && applyPlanApprovalPolicy(goldenPathName, environment, &workflow)
+
+	// Short runs can be waited for; by default the run is queued and callers poll its status
+	wait := r.URL.Query().Get("wait") == "true"
+
+	// Runs in ITIL-governed environments are tracked by a change ticket
+	changes, err := s.changeManager()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Change management is misconfigured: %v", err), http.StatusInternalServerError)
 		return
 	}
+	var ticket *changemgmt.Ticket
+	if s.workflowExecutor != nil && changes.Applies(environment) {
+		ticket, err = changes.Open(r.Context(), changemgmt.Change{
+			Application: spec.Metadata.Name,
+			Team:        user.Team,
+			GoldenPath:  goldenPathName,
+			Environment: environment,
+			RequestedBy: user.Username,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		logger.Infof("Opened change %s for golden path '%s' in '%s'", ticket.Number, goldenPathName, environment)
+	}
+	awaitsChangeApproval := ticket != nil && changes.RequiresApproval()
+
+	// Store the Score spec first; the SQLite dev database only keeps workflow history
+	if s.db != nil {
+		if err := s.db.AddApplication(spec.Metadata.Name, &spec, user.Team, user.Username); err != nil {
+			if ticket != nil {
+				s.closeChangeTicket(r.Context(), changes, ticket, changemgmt.Outcome{Err: err})
+			}
+			http.Error(w, fmt.Sprintf("Error storing application: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if hostname != nil {
+			if err := s.db.ReserveHostname(hostname); err != nil {
+				if ticket != nil {
+					s.closeChangeTicket(r.Context(), changes, ticket, changemgmt.Outcome{Err: err})
+				}
+				writeHostnameError(w, err)
+				return
+			}
+		}
+	}
 
 	// Create resource instances if database is available
 	if s.resourceManager != nil && s.db != nil {
-		fmt.Printf("📦 Creating resource instances for app '%s'...\n", spec.Metadata.Name)
+		logger.Infof("Creating resource instances for app '%s'...", spec.Metadata.Name)
 		err = s.resourceManager.CreateResourceFromSpec(spec.Metadata.Name, &spec, user.Username)
 		if err != nil {
-			fmt.Printf("Warning: Failed to create resource instances: %v\n", err)
+			logger.Warnf("Failed to create resource instances: %v", err)
 			// Continue with workflow execution even if resource creation fails
 		}
 	}
 
-	// TEMPORARY FIX: Force synchronous execution to ensure resources are provisioned after workflow completes
-	// Execute workflow synchronously (disabled async queue for golden paths)
-	var taskID string
-	_ = taskID // Unused for now
-	if s.workflowExecutor != nil {
-		// Execute workflow synchronously with golden path parameters
-		err = s.workflowExecutor.ExecuteWorkflowWithName(spec.Metadata.Name, fmt.Sprintf("golden-path-%s", goldenPathName), workflow, goldenPathParams)
+	// The workflow keeps the request's log fields but must outlive the request
+	workflowCtx := logging.WithApp(context.WithoutCancel(r.Context()), spec.Metadata.Name)
+	workflowCtx = withDeploymentEnvironment(workflowCtx, environment)
+	workflowName := fmt.Sprintf("golden-path-%s", goldenPathName)
+	appName := spec.Metadata.Name
+
+	// provisionAndRecord finishes a successful run: resources are provisioned and the
+	// deployment provenance is recorded
+	provisionAndRecord := func(ctx context.Context) {
+		if s.resourceManager != nil && s.db != nil {
+			if err := s.provisionResourcesAfterWorkflow(ctx, appName, user.Username); err != nil {
+				logger.Warnf("Resource provisioning failed: %v", err)
+				// Don't fail the entire golden path execution
+			}
+		}
+
+		workflows, providers := s.specProvenance(&spec)
+		workflows = append(workflows, provenance.WorkflowFromFile(goldenPathName, "", cleanPath))
+		s.recordDeploymentProvenance(&provenance.Document{
+			Application: appName,
+			Team:        user.Team,
+			Kind:        "golden-path",
+			GoldenPath:  goldenPathName,
+			SpecDigest:  provenance.Digest(body),
+			Workflows:   workflows,
+			Providers:   providers,
+			TriggeredBy: user.Username,
+			StartedAt:   startedAt,
+			FinishedAt:  time.Now(),
+		})
+	}
+
+	// Runs in a concurrency group wait for a free slot in the group, across applications
+	concurrencyGroup, maxParallel, grouped := goldenPathConcurrencyGroup(goldenPathName, environment)
+	grouped = grouped && s.workflowExecutor != nil
+	enqueueGroupRun := func() goldenpaths.GroupRun {
+		return s.goldenPathConcurrency().Enqueue(goldenpaths.GroupRun{
+			Group:       concurrencyGroup,
+			GoldenPath:  goldenPathName,
+			Application: appName,
+			Team:        user.Team,
+			Environment: environment,
+			RequestedBy: user.Username,
+		}, maxParallel)
+	}
+
+	response := map[string]interface{}{
+		"application": appName,
+		"golden_path": goldenPathName,
+	}
+	if ticket != nil {
+		response["change_ticket"] = ticket
+	}
+	if deprecationWarning != "" {
+		response["deprecation_warning"] = deprecationWarning
+	}
+	if overrides != nil {
+		response["overrides"] = overrides
+	}
+	statusCode := http.StatusAccepted
+
+	if awaitsChangeApproval {
+		// No execution exists until the change is approved
+		go func() {
+			if err := changes.WaitForApproval(workflowCtx, ticket); err != nil {
+				logger.Errorf("Golden path '%s' for %s not started: %v", goldenPathName, appName, err)
+				s.closeChangeTicket(workflowCtx, changes, ticket, changemgmt.Outcome{Err: err})
+				return
+			}
+			logger.Infof("Change %s approved", ticket.Number)
+			if grouped {
+				run := enqueueGroupRun()
+				if err := s.goldenPathConcurrency().Wait(workflowCtx, run.ID); err != nil {
+					logger.Errorf("Golden path '%s' for %s not started: %v", goldenPathName, appName, err)
+					s.closeChangeTicket(workflowCtx, changes, ticket, changemgmt.Outcome{Err: err})
+					return
+				}
+				defer s.goldenPathConcurrency().Release(run.ID)
+			}
+			if err := s.executeGoldenPathWorkflow(workflowCtx, changes, ticket, appName, workflowName, workflow, goldenPathParams); err != nil {
+				logger.Errorf("Golden path '%s' for %s failed: %v", goldenPathName, appName, err)
+				return
+			}
+			provisionAndRecord(workflowCtx)
+		}()
+
+		response["message"] = fmt.Sprintf("Golden path '%s' for application '%s' starts once change %s is approved", goldenPathName, appName, ticket.Number)
+		response["environment"] = environment
+		response["status"] = "awaiting_approval"
+	} else if grouped {
+		// No execution exists until the run gets a slot; queued runs can be cancelled
+		run := enqueueGroupRun()
+		done := make(chan error, 1)
+		go func() {
+			if err := s.goldenPathConcurrency().Wait(workflowCtx, run.ID); err != nil {
+				logger.Errorf("Golden path '%s' for %s not started: %v", goldenPathName, appName, err)
+				if ticket != nil {
+					s.closeChangeTicket(workflowCtx, changes, ticket, changemgmt.Outcome{Err: err})
+				}
+				done <- err
+				return
+			}
+			defer s.goldenPathConcurrency().Release(run.ID)
+			err := s.executeGoldenPathWorkflow(workflowCtx, changes, ticket, appName, workflowName, workflow, goldenPathParams)
+			if err != nil {
+				logger.Errorf("Golden path '%s' for %s failed: %v", goldenPathName, appName, err)
+			} else {
+				provisionAndRecord(workflowCtx)
+			}
+			done <- err
+		}()
+
+		w.Header().Set("Location", fmt.Sprintf("/api/concurrency-groups/runs/%s", run.ID))
+		response["concurrency_run"] = run
+		response["status"] = run.State
+		response["message"] = fmt.Sprintf("Golden path '%s' for application '%s' is %s in concurrency group '%s'", goldenPathName, appName, run.State, concurrencyGroup)
+		if wait {
+			if err := <-done; err != nil {
+				http.Error(w, fmt.Sprintf("Golden path '%s' for %s failed: %v", goldenPathName, appName, err), http.StatusInternalServerError)
+				return
+			}
+			response["message"] = fmt.Sprintf("Golden path '%s' executed successfully for application '%s'", goldenPathName, appName)
+			response["status"] = "completed"
+			statusCode = http.StatusOK
+		}
+	} else if s.workflowExecutor != nil && (wait || requiresApproval || s.workflowQueue == nil) {
+		// Runs waiting for plan approval bypass the queue so they don't hold a worker
+		executionID, done, err := startWorkflowRun(workflowCtx, func(ctx context.Context) error {
+			if err := s.executeGoldenPathWorkflow(ctx, changes, ticket, appName, workflowName, workflow, goldenPathParams); err != nil {
+				logger.Errorf("Golden path '%s' for %s failed: %v", goldenPathName, appName, err)
+				return err
+			}
+			provisionAndRecord(ctx)
+			return nil
+		})
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Workflow execution failed: %v", err), http.StatusInternalServerError)
 			return
 		}
+
+		w.Header().Set("Location", fmt.Sprintf("/api/workflows/%d", executionID))
+		response["execution_id"] = executionID
+		response["message"] = fmt.Sprintf("Golden path '%s' started for application '%s'", goldenPathName, appName)
+		response["status"] = "running"
+		if requiresApproval {
+			response["message"] = fmt.Sprintf("Golden path '%s' started for application '%s'; terraform apply awaits plan approval for environment '%s'", goldenPathName, appName, environment)
+			response["environment"] = environment
+			response["status"] = "awaiting_approval"
+		} else if wait {
+			if err := <-done; err != nil {
+				http.Error(w, fmt.Sprintf("Workflow execution %d failed: %v", executionID, err), http.StatusInternalServerError)
+				return
+			}
+			response["message"] = fmt.Sprintf("Golden path '%s' executed successfully for application '%s'", goldenPathName, appName)
+			response["status"] = "completed"
+			statusCode = http.StatusOK
+		}
 	} else if s.workflowQueue != nil {
-		// Fallback: Enqueue workflow for async execution with queue (not recommended for golden paths)
+		// Default: a queue worker runs the workflow, and resources are provisioned once it succeeds
 		metadata := map[string]interface{}{
 			"user":        user.Username,
 			"golden_path": goldenPathName,
 			"source":      "api",
 			"parameters":  goldenPathParams,
 		}
-		taskID, err = s.workflowQueue.Enqueue(spec.Metadata.Name, fmt.Sprintf("golden-path-%s", goldenPathName), workflow, metadata)
+		taskID, err := s.workflowQueue.EnqueueWithContext(workflowCtx, appName, workflowName, workflow, metadata, func(result queue.TaskResult) {
+			if ticket != nil {
+				s.closeChangeTicket(workflowCtx, changes, ticket, changemgmt.Outcome{ExecutionID: result.ExecutionID, Err: result.Err})
+			}
+			if result.Err != nil {
+				logger.Errorf("Golden path '%s' for %s failed: %v", goldenPathName, appName, result.Err)
+				return
+			}
+			provisionAndRecord(workflowCtx)
+		})
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Failed to enqueue workflow: %v", err), http.StatusInternalServerError)
 			return
 		}
+
+		w.Header().Set("Location", fmt.Sprintf("/api/queue/tasks/%s", taskID))
+		response["task_id"] = taskID
+		response["message"] = fmt.Sprintf("Golden path '%s' enqueued successfully for application '%s'", goldenPathName, appName)
+		response["status"] = "enqueued"
 	} else {
 		// Fallback to basic workflow execution without database tracking
 		err = s.executeBasicGoldenPathWorkflow(&workflow, &spec, user.Username)
@@ -3312,44 +4034,64 @@ func (s *Server) HandleGoldenPathExecution(w http.ResponseWriter, r *http.Reques
 			http.Error(w, fmt.Sprintf("Workflow execution failed: %v", err), http.StatusInternalServerError)
 			return
 		}
-	}
-
-	// Provision resources after successful workflow execution
-	if s.resourceManager != nil && s.db != nil {
-		err = s.provisionResourcesAfterWorkflow(spec.Metadata.Name, user.Username)
-		if err != nil {
-			fmt.Printf("Warning: Resource provisioning failed: %v\n", err)
-			// Don't fail the entire golden path execution
-		}
-	}
-
-	response := map[string]interface{}{
-		"message":     fmt.Sprintf("Golden path '%s' enqueued successfully for application '%s'", goldenPathName, spec.Metadata.Name),
-		"application": spec.Metadata.Name,
-		"golden_path": goldenPathName,
-		"task_id":     taskID,
-		"status":      "enqueued",
-	}
+		provisionAndRecord(workflowCtx)
 
-	if taskID != "" {
-		response["message"] = fmt.Sprintf("Golden path '%s' enqueued successfully for application '%s'", goldenPathName, spec.Metadata.Name)
-	} else {
-		response["message"] = fmt.Sprintf("Golden path '%s' executed successfully for application '%s'", goldenPathName, spec.Metadata.Name)
+		response["message"] = fmt.Sprintf("Golden path '%s' executed successfully for application '%s'", goldenPathName, appName)
 		response["status"] = "completed"
+		statusCode = http.StatusOK
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
 	}
 }
 
+// startWorkflowRun calls run in the background and returns the ID of the workflow
+// execution it starts as soon as that execution is recorded, together with a channel
+// receiving the result of run. If run fails before recording an execution, its error
+// is returned instead.
+func startWorkflowRun(ctx context.Context, run func(ctx context.Context) error) (int64, <-chan error, error) {
+	started := make(chan int64, 1)
+	ctx = workflow.WithExecutionStarted(ctx, func(executionID int64) {
+		select {
+		case started <- executionID:
+		default:
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx)
+	}()
+
+	select {
+	case executionID := <-started:
+		return executionID, done, nil
+	case err := <-done:
+		select {
+		case executionID := <-started:
+			result := make(chan error, 1)
+			result <- err
+			return executionID, result, nil
+		default:
+		}
+		if err == nil {
+			err = fmt.Errorf("workflow did not record an execution")
+		}
+		return 0, nil, err
+	}
+}
+
 // executeBasicGoldenPathWorkflow executes a workflow without database tracking (fallback)
 func (s *Server) executeBasicGoldenPathWorkflow(workflow *types.Workflow, spec *types.ScoreSpec, username string) error {
-	fmt.Printf("📋 Executing basic workflow with %d steps for %s\n", len(workflow.Steps), spec.Metadata.Name)
+	logger := logging.NewStructuredLogger("server")
+
+	logger.Infof("Executing basic workflow with %d steps for %s", len(workflow.Steps), spec.Metadata.Name)
 
 	for i, step := range workflow.Steps {
-		fmt.Printf("🔄 Step %d/%d: %s (%s)\n", i+1, len(workflow.Steps), step.Name, step.Type)
+		logger.Infof("Step %d/%d: %s (%s)", i+1, len(workflow.Steps), step.Name, step.Type)
 
 		// For basic workflow, create minimal context without database tracking
 		stepContext := &StepExecutionContext{
@@ -3361,7 +4103,7 @@ func (s *Server) executeBasicGoldenPathWorkflow(workflow *types.Workflow, spec *
 			return fmt.Errorf("step %s failed: %w", step.Name, err)
 		}
 
-		fmt.Printf("✅ Step %s completed successfully\n", step.Name)
+		logger.Infof("Step %s completed successfully", step.Name)
 	}
 
 	return nil
@@ -3398,20 +4140,62 @@ func substituteVariables(step *types.Step, appName string, envType string) {
 	}
 }
 
+// stepLogLimits returns the step output limits of admin-config.yaml and, with an
+// archive bucket, the MinIO archive full output of truncated steps is uploaded to
+// policySources returns the rego policies of policy steps (rego in admin-config.yaml)
+func policySources(adminCfg *admin.AdminConfig) workflow.PolicySources {
+	return workflow.PolicySources{Paths: adminCfg.Rego.Paths, Modules: adminCfg.Rego.Modules}
+}
+
+func stepLogLimits(adminCfg *admin.AdminConfig) (types.LogLimits, workflow.LogArchive) {
+	stepLogs := adminCfg.WorkflowPolicies.StepLogs
+	limits := types.LogLimits{MaxBytes: stepLogs.MaxBytes, HeadBytes: stepLogs.HeadBytes}
+	if stepLogs.Archive.Bucket == "" {
+		return limits, nil
+	}
+	prefix := stepLogs.Archive.Prefix
+	if prefix == "" {
+		prefix = "step-logs/"
+	}
+	client := objectstore.NewClient(adminCfg.Minio.URL, adminCfg.Minio.AccessKey, adminCfg.Minio.SecretKey)
+	return limits, &workflow.ObjectStoreLogArchive{Client: client, Bucket: stepLogs.Archive.Bucket, Prefix: prefix}
+}
+
+// setSecretResolver lets workflow steps reference the secret store of admin-config.yaml
+func setSecretResolver(executor *workflow.WorkflowExecutor, adminCfg *admin.AdminConfig) {
+	resolver, err := secrets.ResolverFor(adminCfg.Secrets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, ${secrets.*} references in workflows are not resolved\n", err)
+		return
+	}
+	executor.SetSecretResolver(resolver, adminCfg.Secrets.Workflows)
+}
+
+// setStepLogFlush applies the stepLogs thresholds of admin-config.yaml
+func (s *Server) setStepLogFlush(bytes int, interval string) {
+	s.logFlushBytes = bytes
+	if interval == "" {
+		return
+	}
+	duration, err := time.ParseDuration(interval)
+	if err != nil || duration <= 0 {
+		fmt.Fprintf(os.Stderr, "Warning: invalid stepLogs.flushInterval '%s', using %s\n", interval, DefaultLogFlushInterval)
+		return
+	}
+	s.logFlushInterval = duration
+}
+
 // runWorkflowStepWithTracking executes a single workflow step with real command execution and output capture
 func (s *Server) runWorkflowStepWithTracking(step types.Step, appName string, envType string, stepContext *StepExecutionContext) error {
+	logger := logging.NewStructuredLogger("server")
+
 	// Substitute variables in step fields
 	substituteVariables(&step, appName, envType)
 
-	// Create log buffer for this step
-	logBuffer := &LogBuffer{
-		repo: stepContext.WorkflowRepo,
-	}
-
-	// Only set stepID if we have database tracking enabled
-	if stepContext.StepID != nil {
-		logBuffer.stepID = stepContext.StepID
-	}
+	// Create log buffer for this step; pending output is flushed when the step returns
+	logBuffer := NewLogBuffer(stepContext.StepID, stepContext.WorkflowRepo)
+	logBuffer.SetFlushThresholds(s.logFlushBytes, s.logFlushInterval)
+	defer func() { _ = logBuffer.Close() }()
 
 	// Log step start
 	if _, err := fmt.Fprintf(logBuffer, "Starting step: %s (type: %s)", step.Name, step.Type); err != nil {
@@ -3421,34 +4205,34 @@ func (s *Server) runWorkflowStepWithTracking(step types.Step, appName string, en
 	// Execute the step based on its type
 	switch step.Type {
 	case "terraform-generate":
-		fmt.Printf("   📝 Executing Terraform Generate step: %s\n", step.Name)
+		logger.Infof("Executing Terraform Generate step: %s", step.Name)
 		return s.executeTerraformGenerateStep(step, appName, envType, logBuffer)
 	case "terraform":
-		fmt.Printf("   🏗️  Executing Terraform step: %s\n", step.Name)
+		logger.Infof("Executing Terraform step: %s", step.Name)
 		return s.executeTerraformStep(step, appName, envType, logBuffer)
 	case "kubernetes":
-		fmt.Printf("   ⚓ Executing Kubernetes step: %s\n", step.Name)
+		logger.Infof("Executing Kubernetes step: %s", step.Name)
 		return s.executeKubernetesStep(step, appName, envType, logBuffer)
 	case "gitea-repo":
-		fmt.Printf("   🗂️  Executing Gitea repository step: %s\n", step.Name)
+		logger.Infof("Executing Gitea repository step: %s", step.Name)
 		return s.executeGiteaRepoStep(step, appName, envType, logBuffer)
 	case "argocd-app":
-		fmt.Printf("   🔄 Executing ArgoCD application step: %s\n", step.Name)
+		logger.Infof("Executing ArgoCD application step: %s", step.Name)
 		return s.executeArgoCDStep(step, appName, envType, logBuffer)
 	case "git-commit-manifests":
-		fmt.Printf("   📝 Executing Git commit step: %s\n", step.Name)
+		logger.Infof("Executing Git commit step: %s", step.Name)
 		return s.executeGitCommitStep(step, appName, envType, logBuffer)
 	case "ansible":
-		fmt.Printf("   🔧 Executing Ansible step: %s\n", step.Name)
+		logger.Infof("Executing Ansible step: %s", step.Name)
 		return s.executeAnsibleStep(step, appName, envType, logBuffer)
 	case "policy":
-		fmt.Printf("   📋 Executing Policy step: %s\n", step.Name)
+		logger.Infof("Executing Policy step: %s", step.Name)
 		return s.executePolicyStep(step, appName, envType, logBuffer)
 	case "dummy":
-		fmt.Printf("   🎭 Executing Dummy step: %s\n", step.Name)
+		logger.Infof("Executing Dummy step: %s", step.Name)
 		return s.executeDummyStep(step, appName, envType, logBuffer)
 	default:
-		fmt.Printf("   ❓ Executing unknown step type: %s\n", step.Type)
+		logger.Infof("Executing unknown step type: %s", step.Type)
 		if _, err := fmt.Fprintf(logBuffer, "Warning: Unknown step type '%s', skipping execution", step.Type); err != nil {
 			fmt.Fprintf(os.Stderr, "failed to write log: %v\n", err)
 		}
@@ -3498,9 +4282,21 @@ func sanitizeAnsibleVars(vars map[string]string) string {
 	return string(jsonBytes)
 }
 
+// commandEnvironment is the environment of processes the server starts for golden path
+// steps: only the server variables steps may inherit, never its credentials
+func (s *Server) commandEnvironment() []string {
+	if s.workflowExecutor != nil {
+		return s.workflowExecutor.CommandEnvironment()
+	}
+	return workflow.DefaultCommandEnvironment()
+}
+
 // executeCommand runs a command and captures output to the log buffer
 func (s *Server) executeCommand(command string, args []string, workDir string, logBuffer *LogBuffer) error {
+	logger := logging.NewStructuredLogger("server")
+
 	cmd := exec.Command(command, args...)
+	cmd.Env = s.commandEnvironment()
 	if workDir != "" {
 		cmd.Dir = workDir
 	}
@@ -3513,7 +4309,7 @@ func (s *Server) executeCommand(command string, args []string, workDir string, l
 	if _, err := logBuffer.Write([]byte(execMsg)); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to write log: %v\n", err)
 	}
-	fmt.Println("   " + execMsg)
+	logger.Info(execMsg)
 
 	err := cmd.Run()
 	if err != nil {
@@ -3521,14 +4317,14 @@ func (s *Server) executeCommand(command string, args []string, workDir string, l
 		if _, writeErr := logBuffer.Write([]byte(errMsg)); writeErr != nil {
 			fmt.Fprintf(os.Stderr, "failed to write error log: %v\n", writeErr)
 		}
-		fmt.Println("   " + errMsg)
+		logger.Error(errMsg)
 		return err
 	}
 
 	if _, err := logBuffer.Write([]byte("Command completed successfully")); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to write log: %v\n", err)
 	}
-	fmt.Println("   Command completed successfully")
+	logger.Info("Command completed successfully")
 	return nil
 }
 
@@ -3607,9 +4403,8 @@ func (s *Server) generateS3BucketTerraform(outputDir, appName string, step types
 		minioUser = "minioadmin"
 	}
 
-	minioPassword, _ := variables["minio_password"].(string)
-	if minioPassword == "" {
-		minioPassword = "minioadmin"
+	if _, ok := variables["minio_password"]; ok {
+		_, _ = logBuffer.Write([]byte("Ignoring minio_password: the password is not written to main.tf, set TF_VAR_minio_password instead"))
 	}
 
 	// Strip protocol from endpoint for Minio provider (it expects just host:port)
@@ -3626,10 +4421,16 @@ func (s *Server) generateS3BucketTerraform(outputDir, appName string, step types
   }
 }
 
+variable "minio_password" {
+  type        = string
+  description = "Minio admin password, set through TF_VAR_minio_password"
+  sensitive   = true
+}
+
 provider "minio" {
   minio_server   = "%s"
   minio_user     = "%s"
-  minio_password = "%s"
+  minio_password = var.minio_password
   minio_ssl      = false
 }
 
@@ -3653,7 +4454,7 @@ output "endpoint" {
 output "bucket_arn" {
   value = "arn:aws:s3:::${minio_s3_bucket.bucket.bucket}"
 }
-`, minioServer, minioUser, minioPassword, bucketName, minioEndpoint, minioEndpoint)
+`, minioServer, minioUser, bucketName, minioEndpoint, minioEndpoint)
 
 	// Write main.tf
 	mainTfPath := filepath.Join(outputDir, "main.tf")
@@ -3765,6 +4566,7 @@ spec:
         - containerPort: 80
 `, appName, namespace, appName, appName)
 
+	s.trackTempAsset(manifestPath, appName, step.Name)
 	err = os.WriteFile(manifestPath, []byte(manifest), 0600)
 	if err != nil {
 		_, _ = fmt.Fprintf(logBuffer, "Failed to write manifest file: %v", err)
@@ -3792,7 +4594,7 @@ func (s *Server) executeGiteaRepoStep(step types.Step, appName string, envType s
 
 	owner := step.Owner
 	if owner == "" {
-		owner = adminConfig.Gitea.OrgName
+		owner = s.giteaRepoOwner(appName, adminConfig.Gitea.OrgName)
 	}
 
 	// Create repository using Gitea API
@@ -3868,6 +4670,7 @@ func (s *Server) executeGiteaRepoStep(step types.Step, appName string, envType s
 
 	// Remove existing directory if present
 	_ = s.executeCommand("rm", []string{"-rf", repoDir}, "", logBuffer)
+	s.trackTempAsset(repoDir, appName, step.Name)
 
 	// Clone repository
 	err = s.executeCommand("git", []string{"clone", repoURL, repoDir}, "", logBuffer)
@@ -3920,6 +4723,19 @@ func (s *Server) executeArgoCDStep(step types.Step, appName string, envType stri
 		namespace = fmt.Sprintf("%s-%s", appName, envType)
 	}
 
+	// Applications of a team are isolated in the team's project
+	project := step.Project
+	if project == "" {
+		project, err = s.teamArgoCDProject(appName)
+		if err != nil {
+			_, _ = fmt.Fprintf(logBuffer, "Failed to sync ArgoCD project: %v", err)
+			return err
+		}
+		if project == "" {
+			project = "default"
+		}
+	}
+
 	// Create ArgoCD application manifest
 	manifest := fmt.Sprintf(`apiVersion: argoproj.io/v1alpha1
 kind: Application
@@ -3927,7 +4743,7 @@ metadata:
   name: %s
   namespace: argocd
 spec:
-  project: default
+  project: %s
   source:
     repoURL: %s
     targetRevision: HEAD
@@ -3939,9 +4755,10 @@ spec:
     automated:
       prune: true
       selfHeal: true
-`, appNameArgo, repoURL, targetPath, namespace)
+`, appNameArgo, project, repoURL, targetPath, namespace)
 
 	manifestPath := fmt.Sprintf("/tmp/%s-argocd-app.yaml", appNameArgo)
+	s.trackTempAsset(manifestPath, appName, step.Name)
 	err = os.WriteFile(manifestPath, []byte(manifest), 0600)
 	if err != nil {
 		_, _ = fmt.Fprintf(logBuffer, "Failed to write ArgoCD manifest: %v", err)
@@ -4018,20 +4835,39 @@ func (s *Server) executeAnsibleStep(step types.Step, appName string, envType str
 	return s.executeCommand("ansible-playbook", []string{playbookPath, "-e", extraVars}, "", logBuffer)
 }
 
-// executePolicyStep executes a policy validation step
+// executePolicyStep evaluates the rego policies of admin-config.yaml and the step against
+// the application's Score spec and the manifests in config.manifests
 func (s *Server) executePolicyStep(step types.Step, appName string, envType string, logBuffer *LogBuffer) error {
-	_, _ = fmt.Fprintf(logBuffer, "Executing policy validation for %s in %s environment", appName, envType)
+	_, _ = fmt.Fprintf(logBuffer, "Executing policy validation for %s in %s environment\n", appName, envType)
 
-	// Simulate policy execution (would integrate with OPA, Gatekeeper, etc.)
-	_, _ = logBuffer.Write([]byte("Policy validation simulated - would require integration with policy engine"))
-	time.Sleep(1 * time.Second)
+	var sources workflow.PolicySources
+	if adminConfig, err := admin.LoadAdminConfig("admin-config.yaml"); err == nil {
+		sources = policySources(adminConfig)
+	}
 
-	return nil
+	input := workflow.PolicyInput{
+		Application: appName,
+		Spec:        map[string]interface{}{},
+		Parameters:  map[string]string{"environment": envType},
+	}
+	if s.db != nil {
+		if app, err := s.db.GetApplication(appName); err == nil && app.ScoreSpec != nil {
+			if data, err := yaml.Marshal(app.ScoreSpec); err == nil {
+				_ = yaml.Unmarshal(data, &input.Spec)
+			}
+		}
+	}
+
+	logs, err := workflow.CheckPolicies(context.Background(), step.Config, sources, input, workflow.CommandWithEnvironment(s.commandEnvironment()))
+	_, _ = logBuffer.Write([]byte(logs))
+	return err
 }
 
 // provisionResourcesAfterWorkflow provisions all resources for an application after successful workflow execution
-func (s *Server) provisionResourcesAfterWorkflow(appName, username string) error {
-	fmt.Printf("🔧 Provisioning resources for application: %s\n", appName)
+func (s *Server) provisionResourcesAfterWorkflow(ctx context.Context, appName, username string) error {
+	logger := logging.FromContext(logging.WithApp(ctx, appName), "server")
+
+	logger.Infof("Provisioning resources for application: %s", appName)
 
 	// Get all resources for the application
 	resources, err := s.resourceManager.GetResourcesByApplication(appName)
@@ -4040,27 +4876,34 @@ func (s *Server) provisionResourcesAfterWorkflow(appName, username string) error
 	}
 
 	if len(resources) == 0 {
-		fmt.Printf("No resources found for application: %s\n", appName)
+		logger.Infof("No resources found for application: %s", appName)
 		return nil
 	}
 
+	// Manifests and hints use the hostname from the naming convention
+	hostname := s.applicationHostname(appName)
+
 	// Provision each resource
 	for _, resource := range resources {
 		if resource.State == "provisioning" {
-			fmt.Printf("📦 Provisioning resource: %s (%s)\n", resource.ResourceName, resource.ResourceType)
+			logger.Infof("Provisioning resource: %s (%s)", resource.ResourceName, resource.ResourceType)
 
 			// Provision the resource using the resource manager
-			err := s.resourceManager.ProvisionResource(resource.ID, "golden-path-provisioner",
-				map[string]interface{}{
-					"provisioned_via": "golden_path_workflow",
-					"workflow_type":   "deploy-app",
-				}, username)
+			metadata := map[string]interface{}{
+				"provisioned_via": "golden_path_workflow",
+				"workflow_type":   "deploy-app",
+			}
+			if hostname != nil {
+				metadata["hostname"] = hostname.Hostname
+				metadata["url"] = hostname.URL
+			}
+			err := s.resourceManager.ProvisionResource(resource.ID, "golden-path-provisioner", metadata, username)
 			if err != nil {
-				fmt.Printf("❌ Failed to provision resource %s: %v\n", resource.ResourceName, err)
+				logger.Errorf("Failed to provision resource %s: %v", resource.ResourceName, err)
 				continue
 			}
 
-			fmt.Printf("✅ Successfully provisioned resource: %s\n", resource.ResourceName)
+			logger.Infof("Successfully provisioned resource: %s", resource.ResourceName)
 		}
 	}
 
@@ -4095,49 +4938,12 @@ func (s *Server) HandleGetAPIKeys(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user exists in users.yaml (local user) or is OIDC user
-	store, err := users.LoadUsers()
+	keys, err := s.userAPIKeys(user.Username)
 	if err != nil {
-		http.Error(w, "Failed to load users", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	_, err = store.GetUser(user.Username)
-	isOIDCUser := err != nil // User not found in yaml = OIDC user
-
-	var keys []users.APIKey
-
-	if isOIDCUser && s.db != nil {
-		// Get API keys from database for OIDC user
-		dbKeys, err := s.db.GetAPIKeys(user.Username)
-		if err != nil {
-			http.Error(w, "Failed to retrieve API keys", http.StatusInternalServerError)
-			return
-		}
-
-		// Convert database records to users.APIKey format
-		for _, dbKey := range dbKeys {
-			lastUsed := time.Time{}
-			if dbKey.LastUsedAt != nil {
-				lastUsed = *dbKey.LastUsedAt
-			}
-			keys = append(keys, users.APIKey{
-				Key:        dbKey.KeyHash, // Will be masked anyway
-				Name:       dbKey.KeyName,
-				CreatedAt:  dbKey.CreatedAt,
-				LastUsedAt: lastUsed,
-				ExpiresAt:  dbKey.ExpiresAt,
-			})
-		}
-	} else {
-		// Get API keys from users.yaml for local user
-		keys, err = store.ListAPIKeys(user.Username)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-	}
-
 	// Mask keys for security (show only last 8 characters)
 	masked := []map[string]interface{}{}
 	for _, key := range keys {
@@ -4161,6 +4967,49 @@ func (s *Server) HandleGetAPIKeys(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// userAPIKeys returns a user's API keys from users.yaml for local users or from the
+// database for OIDC users
+func (s *Server) userAPIKeys(username string) ([]users.APIKey, error) {
+	// Check if user exists in users.yaml (local user) or is OIDC user
+	store, err := users.LoadUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load users")
+	}
+
+	_, err = store.GetUser(username)
+	isOIDCUser := err != nil // User not found in yaml = OIDC user
+
+	if !isOIDCUser {
+		return store.ListAPIKeys(username)
+	}
+	if s.db == nil {
+		return nil, nil
+	}
+
+	// Get API keys from database for OIDC user
+	dbKeys, err := s.db.GetAPIKeys(username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve API keys")
+	}
+
+	// Convert database records to users.APIKey format
+	var keys []users.APIKey
+	for _, dbKey := range dbKeys {
+		lastUsed := time.Time{}
+		if dbKey.LastUsedAt != nil {
+			lastUsed = *dbKey.LastUsedAt
+		}
+		keys = append(keys, users.APIKey{
+			Key:        dbKey.KeyHash, // Will be masked anyway
+			Name:       dbKey.KeyName,
+			CreatedAt:  dbKey.CreatedAt,
+			LastUsedAt: lastUsed,
+			ExpiresAt:  dbKey.ExpiresAt,
+		})
+	}
+	return keys, nil
+}
+
 // HandleGenerateAPIKey creates a new API key for the current user
 func (s *Server) HandleGenerateAPIKey(w http.ResponseWriter, r *http.Request) {
 	user, ok := r.Context().Value(contextKeyUser).(*users.User)
@@ -1,18 +1,48 @@
 package server
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"innominatus/internal/admin"
+	"innominatus/internal/auth"
+	"innominatus/internal/clock"
+	"innominatus/internal/config"
+	"innominatus/internal/database"
+	"innominatus/internal/devdb"
+	"innominatus/internal/faults"
+	"innominatus/internal/goldenpaths"
+	"innominatus/internal/health"
+	"innominatus/internal/metrics"
+	"innominatus/internal/notifications"
+	"innominatus/internal/observability"
+	"innominatus/internal/orchestration"
+	"innominatus/internal/orgs"
+	"innominatus/internal/provenance"
+	"innominatus/internal/providers"
+	"innominatus/internal/queue"
+	"innominatus/internal/slack"
+	"innominatus/internal/types"
 	"innominatus/internal/users"
+	"innominatus/internal/workflow"
+	"innominatus/internal/workspaces"
+	"innominatus/pkg/sdk"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -451,8 +481,18 @@ func TestLoginRateLimit(t *testing.T) {
 		} else {
 			// 6th attempt should be rate limited
 			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+			assert.Equal(t, "60", w.Header().Get("Retry-After"))
+			assert.Contains(t, w.Body.String(), "Try again in 1m0s")
 		}
 	}
+
+	// The username stays locked from other addresses
+	bodyBytes, _ := json.Marshal(map[string]string{"username": "admin", "password": "admin123"})
+	req := httptest.NewRequest("POST", "/api/login", bytes.NewReader(bodyBytes))
+	req.RemoteAddr = "127.0.0.2:12345"
+	w := httptest.NewRecorder()
+	server.HandleAPILogin(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
 }
 
 func TestMemoryWorkflowTracking(t *testing.T) {
@@ -573,3 +613,2533 @@ workflows:
         type: kubernetes
         namespace: test-app`
 }
+
+func TestHandleDebugClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		fake       bool
+		method     string
+		body       string
+		wantStatus int
+		wantNow    time.Time
+	}{
+		{"inspect fake clock", true, "GET", "", http.StatusOK, start},
+		{"advance fake clock", true, "POST", `{"advance": "90m"}`, http.StatusOK, start.Add(90 * time.Minute)},
+		{"set fake clock", true, "POST", `{"set": "2025-03-01T12:00:00Z"}`, http.StatusOK, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{"reject negative advance", true, "POST", `{"advance": "-1h"}`, http.StatusBadRequest, time.Time{}},
+		{"reject empty request", true, "POST", `{}`, http.StatusBadRequest, time.Time{}},
+		{"no time travel on wall clock", false, "POST", `{"advance": "1h"}`, http.StatusConflict, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+			if tt.fake {
+				fakeClock := clock.NewFake(start)
+				fakeClock.NewTicker("maintenance-scheduler", time.Minute)
+				server.SetClock(fakeClock)
+			}
+
+			req := httptest.NewRequest(tt.method, "/api/admin/debug/clock", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			server.HandleDebugClock(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Now    time.Time         `json:"now"`
+				Fake   bool              `json:"fake"`
+				Timers []clock.TimerInfo `json:"timers"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.True(t, response.Fake)
+			assert.True(t, tt.wantNow.Equal(response.Now), "now = %s", response.Now)
+			require.Len(t, response.Timers, 1)
+			assert.Equal(t, "maintenance-scheduler", response.Timers[0].Name)
+		})
+	}
+}
+
+func TestHandleFaults(t *testing.T) {
+	server := &Server{}
+
+	w := httptest.NewRecorder()
+	server.HandleFaults(w, httptest.NewRequest("GET", "/api/admin/faults", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code, "disabled without an injector")
+
+	server.SetFaultInjector(faults.NewInjector())
+
+	w = httptest.NewRecorder()
+	server.HandleFaults(w, httptest.NewRequest("POST", "/api/admin/faults", strings.NewReader(`{"scope": "step", "kind": "delay"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "delay faults need a delay")
+
+	w = httptest.NewRecorder()
+	server.HandleFaults(w, httptest.NewRequest("POST", "/api/admin/faults",
+		strings.NewReader(`{"scope": "provisioner", "kind": "error", "provider": "database-team", "count": 1}`)))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var added faults.Fault
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &added))
+	assert.Equal(t, "database-team", added.Provider)
+
+	w = httptest.NewRecorder()
+	server.HandleFaults(w, httptest.NewRequest("GET", "/api/admin/faults", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Faults []faults.Fault `json:"faults"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Faults, 1)
+
+	w = httptest.NewRecorder()
+	server.HandleFaults(w, httptest.NewRequest("DELETE", "/api/admin/faults/"+added.ID, nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	server.HandleFaults(w, httptest.NewRequest("DELETE", "/api/admin/faults/"+added.ID, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleNotifications(t *testing.T) {
+	server := &Server{}
+
+	w := httptest.NewRecorder()
+	server.HandleNotifications(w, httptest.NewRequest("POST", "/api/admin/notifications/preview",
+		strings.NewReader(`{"type": "workflow.failed", "app": "shop", "data": {"step_name": "migrate"}}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var preview struct {
+		Message notifications.Message `json:"message"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(t, "[shop] Workflow deploy-app failed", preview.Message.Subject)
+	assert.Contains(t, preview.Message.Body, "at step migrate")
+
+	w = httptest.NewRecorder()
+	server.HandleNotifications(w, httptest.NewRequest("POST", "/api/admin/notifications/preview",
+		strings.NewReader(`{"type": "resource.active", "template": {"subject": "{{ .Resource.Name"}}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "invalid template")
+
+	w = httptest.NewRecorder()
+	server.HandleNotifications(w, httptest.NewRequest("POST", "/api/admin/notifications/preview", strings.NewReader(`{"type": "spec.created"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "not a notification type")
+
+	w = httptest.NewRecorder()
+	server.HandleNotifications(w, httptest.NewRequest("POST", "/api/admin/notifications/test", strings.NewReader(`{"type": "resource.active"}`)))
+	assert.Equal(t, http.StatusConflict, w.Code, "no webhook configured")
+
+	w = httptest.NewRecorder()
+	server.HandleNotifications(w, httptest.NewRequest("GET", "/api/admin/notifications/templates?team=payments", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"resource.hibernated"`)
+}
+
+func TestHandleSlackCommands(t *testing.T) {
+	t.Setenv("TEST_SLACK_SIGNING_SECRET", "s3cret")
+	config := slack.Config{SigningSecretEnv: "TEST_SLACK_SIGNING_SECRET", Users: map[string]string{"U0TEST": "testuser"}}
+	server := &Server{}
+
+	command := func(body, secret string, config slack.Config) *httptest.ResponseRecorder {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req := httptest.NewRequest("POST", "/api/integrations/slack/commands", strings.NewReader(body))
+		req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		req.Header.Set("X-Slack-Signature", slack.Sign(secret, timestamp, []byte(body)))
+		w := httptest.NewRecorder()
+		server.handleSlackCommand(w, req, config)
+		return w
+	}
+
+	w := command("user_id=U0TEST&text=help", "s3cret", config)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var message slack.Message
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &message))
+	assert.Equal(t, slack.Usage, message.Text)
+
+	w = command("user_id=U0OTHER&text=status+shop", "s3cret", config)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "not linked", "unmapped Slack user")
+
+	w = command("user_id=U0TEST&text=status+shop", "s3cret", config)
+	assert.Contains(t, w.Body.String(), "no database connection")
+
+	assert.Equal(t, http.StatusUnauthorized, command("user_id=U0TEST&text=help", "forged", config).Code)
+	assert.Equal(t, http.StatusNotFound, command("user_id=U0TEST&text=help", "s3cret", slack.Config{}).Code, "not configured")
+}
+
+func TestHandleSlackInteractions(t *testing.T) {
+	t.Setenv("TEST_SLACK_SIGNING_SECRET", "s3cret")
+	config := slack.Config{SigningSecretEnv: "TEST_SLACK_SIGNING_SECRET", Users: map[string]string{"U0TEST": "testuser"}}
+	server := &Server{}
+
+	responses := make(chan slack.Message, 1)
+	slackAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message slack.Message
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		responses <- message
+	}))
+	defer slackAPI.Close()
+
+	payload := fmt.Sprintf(`{"type": "block_actions", "user": {"id": "U0TEST"}, "response_url": %q,
+		"actions": [{"action_id": "approval_approve", "value": "42"}]}`, slackAPI.URL)
+	body := url.Values{"payload": {payload}}.Encode()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest("POST", "/api/integrations/slack/interactions", strings.NewReader(body))
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", slack.Sign("s3cret", timestamp, []byte(body)))
+	w := httptest.NewRecorder()
+	server.handleSlackInteraction(w, req, config)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	select {
+	case message := <-responses:
+		assert.Contains(t, message.Text, "no database connection")
+		assert.False(t, message.ReplaceOriginal, "undecided approvals keep their buttons")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no response sent to response_url")
+	}
+}
+
+func TestHandleConcurrencyGroups(t *testing.T) {
+	server := &Server{}
+	groups := server.goldenPathConcurrency()
+	running := groups.Enqueue(goldenpaths.GroupRun{Group: "prod-deploys", GoldenPath: "deploy-app", Application: "shop", Team: "engineering", RequestedBy: "testuser"}, 1)
+	queued := groups.Enqueue(goldenpaths.GroupRun{Group: "prod-deploys", GoldenPath: "deploy-app", Application: "cart", Team: "engineering", RequestedBy: "testuser"}, 1)
+	hidden := groups.Enqueue(goldenpaths.GroupRun{Group: "prod-deploys", GoldenPath: "deploy-app", Application: "ledger", Team: "finance", RequestedBy: "alice"}, 1)
+
+	w := httptest.NewRecorder()
+	server.HandleConcurrencyGroups(w, createAuthenticatedRequest("GET", "/api/concurrency-groups", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	var list struct {
+		Groups []goldenpaths.GroupStatus `json:"groups"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Groups, 1)
+	assert.Equal(t, 1, list.Groups[0].Active)
+	assert.Equal(t, 2, list.Groups[0].Waiting, "counts include runs of other teams")
+	require.Len(t, list.Groups[0].Queued, 1, "runs of other teams are hidden")
+	assert.Equal(t, "cart", list.Groups[0].Queued[0].Application)
+
+	w = httptest.NewRecorder()
+	server.HandleConcurrencyGroups(w, createAuthenticatedRequest("GET", "/api/concurrency-groups/runs/"+hidden.ID, ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	server.HandleConcurrencyGroups(w, createAuthenticatedRequest("DELETE", "/api/concurrency-groups/runs/"+running.ID, ""))
+	assert.Equal(t, http.StatusConflict, w.Code, "running runs cannot be cancelled")
+
+	w = httptest.NewRecorder()
+	server.HandleConcurrencyGroups(w, createAuthenticatedRequest("DELETE", "/api/concurrency-groups/runs/"+queued.ID, ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"state":"cancelled"`)
+	assert.ErrorIs(t, groups.Wait(context.Background(), queued.ID), goldenpaths.ErrRunNotQueued)
+}
+
+func TestHandleObservabilityBundle(t *testing.T) {
+	server := &Server{}
+
+	w := httptest.NewRecorder()
+	server.HandleObservabilityBundle(w, createAuthenticatedRequest("GET", "/api/admin/observability/bundle?job=innominatus", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var bundle observability.Bundle
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bundle))
+	assert.Contains(t, bundle.PrometheusRules, observability.RecordWorkflowFailureRatio)
+	assert.Contains(t, bundle.Dashboards, observability.PlatformDashboardFile)
+
+	w = httptest.NewRecorder()
+	server.HandleObservabilityBundle(w, createAuthenticatedRequest("GET", "/api/admin/observability/bundle?file=prometheus-rules.yaml", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "prometheus-rules.yaml")
+	assert.True(t, strings.HasPrefix(w.Body.String(), "groups:"))
+
+	w = httptest.NewRecorder()
+	server.HandleObservabilityBundle(w, createAuthenticatedRequest("GET", "/api/admin/observability/bundle?file=innominatus-api.json", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"title": "innominatus API"`)
+
+	w = httptest.NewRecorder()
+	server.HandleObservabilityBundle(w, createAuthenticatedRequest("GET", "/api/admin/observability/bundle?file=other.json", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	server.HandleObservabilityBundle(w, createAuthenticatedRequest("GET", "/api/admin/observability/bundle?job=a%22b", ""))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandleEffectiveConfig(t *testing.T) {
+	cfg, err := config.Load([]string{"--port", "9090"}, func(name string) (string, bool) {
+		values := map[string]string{"DB_PASSWORD": "secret", "DB_HOST": "db.internal"}
+		v, ok := values[name]
+		return v, ok
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		method     string
+		wantStatus int
+	}{
+		{"resolved settings", cfg, "GET", http.StatusOK},
+		{"not started via main", nil, "GET", http.StatusServiceUnavailable},
+		{"method not allowed", cfg, "POST", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+			server.SetEffectiveConfig(tt.cfg)
+
+			req := httptest.NewRequest(tt.method, "/api/admin/effective-config", nil)
+			w := httptest.NewRecorder()
+			server.HandleEffectiveConfig(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response EffectiveConfigResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, []string{"default", "file", "env", "flag"}, response.Precedence)
+			assert.NotContains(t, w.Body.String(), `"value":"secret"`)
+
+			settings := make(map[string]config.Setting)
+			for _, s := range response.Settings {
+				settings[s.Key] = s
+			}
+			assert.Equal(t, config.Setting{Key: "server.port", Value: "9090", Source: "flag", Origin: "--port", Env: "PORT", Flag: "--port"}, settings["server.port"])
+			assert.Equal(t, "env", settings["database.host"].Source)
+			assert.Equal(t, "****", settings["database.password"].Value)
+			assert.Equal(t, "default", settings["database.port"].Source)
+		})
+	}
+}
+
+func TestHandleProviderHealth(t *testing.T) {
+	registry := providers.NewRegistry()
+	require.NoError(t, registry.RegisterProvider(&sdk.Provider{
+		Metadata: sdk.ProviderMetadata{Name: "database-team", Version: "1.0.0"},
+	}))
+
+	tracker := orchestration.NewProviderHealthTracker()
+	for i := 0; i < 3; i++ {
+		tracker.Record("database-team", time.Now(), time.Second, fmt.Errorf("apply failed"))
+	}
+
+	server := &Server{}
+	server.SetProviderRegistry(registry)
+
+	tests := []struct {
+		name       string
+		path       string
+		tracker    *orchestration.ProviderHealthTracker
+		wantStatus int
+		wantHealth string
+	}{
+		{"failing provider", "/api/providers/database-team/health", tracker, http.StatusOK, orchestration.ProviderStatusFailing},
+		{"engine not running", "/api/providers/database-team/health", nil, http.StatusOK, orchestration.ProviderStatusUnknown},
+		{"unknown provider", "/api/providers/missing/health", tracker, http.StatusNotFound, ""},
+		{"unknown subresource", "/api/providers/database-team/status", tracker, http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.SetProviderHealth(tt.tracker)
+			w := httptest.NewRecorder()
+			server.HandleProviderDetail(w, httptest.NewRequest("GET", tt.path, nil))
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantHealth == "" {
+				return
+			}
+			var health orchestration.ProviderHealth
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &health))
+			assert.Equal(t, tt.wantHealth, health.Status)
+			assert.Equal(t, "database-team", health.Provider)
+		})
+	}
+}
+
+func TestHandleGetProvider(t *testing.T) {
+	provider, err := providers.NewLoader("dev").LoadFromFile("../../providers/database-team/provider.yaml")
+	require.NoError(t, err)
+	registry := providers.NewRegistry()
+	require.NoError(t, registry.RegisterProvider(provider))
+
+	server := &Server{}
+	server.SetProviderRegistry(registry)
+
+	w := httptest.NewRecorder()
+	server.HandleProviderDetail(w, httptest.NewRequest("GET", "/api/providers/database-team", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var detail struct {
+		Name          string                    `json:"name"`
+		Compatibility sdk.ProviderCompatibility `json:"compatibility"`
+		Workflows     []struct {
+			Name       string                        `json:"name"`
+			Operation  string                        `json:"operation"`
+			Parameters []providers.WorkflowParameter `json:"parameters"`
+		} `json:"workflows"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	assert.Equal(t, "database-team", detail.Name)
+	assert.Equal(t, "2.0.0", detail.Compatibility.MaxCoreVersion)
+	require.Len(t, detail.Workflows, len(provider.Workflows))
+	assert.Equal(t, "create", detail.Workflows[0].Operation)
+
+	params := map[string]providers.WorkflowParameter{}
+	for _, p := range detail.Workflows[0].Parameters {
+		params[p.Name] = p
+	}
+	assert.True(t, params["app_name"].Required)
+	assert.Equal(t, "small", params["size"].Default)
+
+	w = httptest.NewRecorder()
+	server.HandleProviderDetail(w, httptest.NewRequest("GET", "/api/providers/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleProviderDocs(t *testing.T) {
+	provider, err := providers.NewLoader("dev").LoadFromFile("../../providers/database-team/provider.yaml")
+	require.NoError(t, err)
+	registry := providers.NewRegistry()
+	require.NoError(t, registry.RegisterProvider(provider))
+
+	server := &Server{}
+	server.SetProviderRegistry(registry)
+
+	w := httptest.NewRecorder()
+	server.HandleProviderDetail(w, httptest.NewRequest("GET", "/api/providers/database-team/docs", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var docs providers.ProviderDocs
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &docs))
+	assert.Equal(t, "database-team", docs.Provider)
+	assert.Contains(t, docs.Markdown, "# Database Team Provider", "the readme is included")
+	require.Len(t, docs.Provisioners, len(provider.Workflows))
+
+	provision := docs.Provisioners[0]
+	assert.Equal(t, "provision-postgres", provision.Name)
+	assert.Equal(t, "create", provision.Operation)
+	assert.Contains(t, provision.Markdown, "### Parameters")
+	assert.Contains(t, provision.Markdown, "### Outputs")
+	assert.Contains(t, provision.Markdown, "#### score-postgres-app.yaml")
+
+	w = httptest.NewRecorder()
+	server.HandleProviderDetail(w, httptest.NewRequest("GET", "/api/providers/missing/docs", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	server.HandleProviderDetail(w, httptest.NewRequest("POST", "/api/providers/database-team/docs", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestHandleReadyReportsStartupDependencies(t *testing.T) {
+	server := &Server{healthChecker: health.NewHealthChecker()}
+	server.healthChecker.Register(health.NewAlwaysHealthyChecker("server"))
+	server.configuredProviders = 1
+	server.registerReadinessChecks()
+
+	w := httptest.NewRecorder()
+	server.HandleReady(w, httptest.NewRequest("GET", "/ready", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var response health.ReadinessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Ready)
+	assert.Equal(t, "Service is not ready - unhealthy: graph, migrations, providers, workflow_queue", response.Message)
+	assert.Equal(t, health.StatusHealthy, response.Checks["server"].Status)
+	assert.Equal(t, "Provider registry not loaded", response.Checks["providers"].Error)
+	assert.Equal(t, "Workflow queue not initialized", response.Checks["workflow_queue"].Error)
+}
+
+func TestHandleHealthReportsMetricsPush(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer gateway.Close()
+
+	pusher, err := metrics.NewMetricsPusherWithConfig(metrics.PushConfig{URL: gateway.URL, Interval: time.Hour}, "v1.0.0", "abc123")
+	require.NoError(t, err)
+
+	server := &Server{healthChecker: health.NewHealthChecker()}
+	server.healthChecker.Register(health.NewAlwaysHealthyChecker("server"))
+	server.SetMetricsPusher(pusher)
+
+	status, message := server.checkMetricsPush(context.Background())
+	assert.Equal(t, health.StatusHealthy, status)
+	assert.Equal(t, "No push attempted yet", message)
+
+	pusher.StartPushing()
+	defer pusher.Stop()
+	require.Eventually(t, func() bool { return pusher.Status().ConsecutiveFailures > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	w := httptest.NewRecorder()
+	server.HandleHealth(w, httptest.NewRequest("GET", "/health", nil))
+
+	require.Equal(t, http.StatusOK, w.Code, "failing pushes degrade but never fail liveness")
+	var response health.HealthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, health.StatusDegraded, response.Status)
+	assert.Equal(t, health.StatusDegraded, response.Checks["metrics_push"].Status)
+	assert.Contains(t, response.Checks["metrics_push"].Message, "1 consecutive pushes failed")
+}
+
+func TestCheckProvidersReady(t *testing.T) {
+	loaded := providers.NewRegistry()
+	require.NoError(t, loaded.RegisterProvider(&sdk.Provider{
+		Metadata: sdk.ProviderMetadata{Name: "database-team", Version: "1.0.0"},
+	}))
+
+	tests := []struct {
+		name       string
+		registry   ProviderRegistry
+		configured int
+		want       health.Status
+	}{
+		{"zero configured without registry", nil, 0, health.StatusHealthy},
+		{"zero configured with empty registry", providers.NewRegistry(), 0, health.StatusHealthy},
+		{"registry not set", nil, 1, health.StatusUnhealthy},
+		{"no configured provider loaded", providers.NewRegistry(), 2, health.StatusUnhealthy},
+		{"some configured providers loaded", loaded, 2, health.StatusDegraded},
+		{"all configured providers loaded", loaded, 1, health.StatusHealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{configuredProviders: tt.configured}
+			if tt.registry != nil {
+				server.SetProviderRegistry(tt.registry)
+			}
+			status, _ := server.checkProvidersReady(context.Background())
+			assert.Equal(t, tt.want, status)
+		})
+	}
+}
+
+func TestHandleVersion(t *testing.T) {
+	server := &Server{healthChecker: health.NewHealthChecker()}
+	server.SetBuildInfo("v1.4.0", "abc123", "2026-03-01")
+	server.SetCLIVersionPolicy(CLIVersionPolicy{MinVersion: "v1.2.0"})
+
+	req := httptest.NewRequest("GET", "/api/version", nil)
+	req.Header.Set(CLIVersionHeader, "v1.1.9")
+	w := httptest.NewRecorder()
+	server.HandleVersion(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response VersionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "v1.4.0", response.Version)
+	assert.Equal(t, "abc123", response.Commit)
+	assert.NotEmpty(t, response.GoVersion)
+	assert.False(t, response.CLI.Compatible)
+	assert.Equal(t, "v1.1.9", response.CLI.ClientVersion)
+	assert.Equal(t, "v1.2.0", response.CLI.MinVersion)
+}
+
+func TestCheckCLICompatibility(t *testing.T) {
+	policy := CLIVersionPolicy{MinVersion: "v1.2.0", RecommendedVersion: "v1.3.0"}
+
+	tests := []struct {
+		name           string
+		clientVersion  string
+		wantCompatible bool
+		wantWarnings   int
+	}{
+		{"current version", "v1.4.0", true, 0},
+		{"deprecated version", "v1.2.5", true, 1},
+		{"below minimum", "v1.1.0", false, 1},
+		{"newer than server", "v1.5.0", true, 1},
+		{"development build", "dev", true, 0},
+		{"no version sent", "", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checkCLICompatibility(policy, "v1.4.0", tt.clientVersion)
+			assert.Equal(t, tt.wantCompatible, result.Compatible)
+			assert.Len(t, result.Warnings, tt.wantWarnings)
+		})
+	}
+}
+
+func TestWriteWorkflowBundle(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	logs := "terraform apply\nError: quota exceeded\n"
+	errMsg := "quota exceeded"
+	bundle := &workflowBundle{
+		execution: &database.WorkflowExecution{
+			ID:              42,
+			ApplicationName: "checkout",
+			WorkflowName:    "golden-path-deploy-app",
+			Status:          database.WorkflowStatusFailed,
+			StartedAt:       started,
+			ErrorMessage:    &errMsg,
+			Steps: []*database.WorkflowStepExecution{
+				{StepNumber: 1, StepName: "provision db", StepType: "terraform", Status: database.StepStatusFailed, OutputLogs: &logs},
+			},
+		},
+		workflow:    &types.Workflow{Steps: []types.Step{{Name: "provision db", Type: "terraform"}}},
+		parameters:  map[string]string{"environment": "staging", "note": "it's red"},
+		app:         &database.Application{Name: "checkout", ScoreSpec: &types.ScoreSpec{APIVersion: "score.dev/v1b1"}, UpdatedAt: started.Add(-time.Hour)},
+		generatedBy: "alice",
+		now:         started.Add(2 * time.Hour),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeWorkflowBundle(&buf, bundle))
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	var order []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = data
+		order = append(order, header.Name)
+	}
+
+	assert.Equal(t, "workflow-42/manifest.json", order[len(order)-1], "manifest must come last")
+	assert.Contains(t, files, "workflow-42/workflow.yaml")
+	assert.Contains(t, files, "workflow-42/spec.yaml")
+	assert.Equal(t, logs, string(files["workflow-42/logs/01-provision-db.log"]))
+
+	var manifest WorkflowBundleManifest
+	require.NoError(t, json.Unmarshal(files["workflow-42/manifest.json"], &manifest))
+	assert.Equal(t, workflowBundleFormat, manifest.Format)
+	assert.Equal(t, "alice", manifest.GeneratedBy)
+	assert.Equal(t, int64(42), manifest.Execution.ID)
+	assert.Empty(t, manifest.Execution.Steps)
+	require.NotNil(t, manifest.SpecRevision)
+	assert.False(t, manifest.SpecRevision.ChangedSinceRun)
+	assert.Equal(t, `innominatus-ctl run deploy-app spec.yaml --test --param environment=staging --param 'note=it'\''s red'`, manifest.Replay)
+	for _, file := range manifest.Files {
+		assert.Equal(t, provenance.Digest(files["workflow-42/"+file.Path]), file.Digest, file.Path)
+	}
+
+	var steps []database.WorkflowStepExecution
+	require.NoError(t, json.Unmarshal(files["workflow-42/steps.json"], &steps))
+	require.Len(t, steps, 1)
+	assert.Nil(t, steps[0].OutputLogs, "logs are stored as separate files")
+}
+
+func TestRunProvenance(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	execution := &database.WorkflowExecution{StartedAt: started}
+	records := []*database.DeploymentProvenance{
+		{ID: 3, CreatedAt: started.Add(2 * time.Hour)},
+		{ID: 2, CreatedAt: started.Add(time.Minute)},
+		{ID: 1, CreatedAt: started.Add(-time.Hour)},
+	}
+
+	record := runProvenance(records, execution)
+	require.NotNil(t, record)
+	assert.Equal(t, int64(2), record.ID)
+
+	assert.Nil(t, runProvenance(records[2:], execution))
+}
+
+func TestHandleWorkflowReplay(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		auth       bool
+		wantStatus int
+	}{
+		{name: "unauthenticated", auth: false, wantStatus: http.StatusUnauthorized},
+		{name: "no database", auth: true, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/workflows/42/replay", nil)
+			if tt.auth {
+				req = createAuthenticatedRequest("POST", "/api/workflows/42/replay", "")
+			}
+			w := httptest.NewRecorder()
+
+			server.handleWorkflowReplay(w, req, 42)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandleWorkflowRollback(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		method     string
+		auth       bool
+		wantStatus int
+	}{
+		{name: "method not allowed", method: "DELETE", auth: true, wantStatus: http.StatusMethodNotAllowed},
+		{name: "unauthenticated", method: "POST", auth: false, wantStatus: http.StatusUnauthorized},
+		{name: "list without database", method: "GET", auth: true, wantStatus: http.StatusServiceUnavailable},
+		{name: "rollback without database", method: "POST", auth: true, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/workflows/42/rollback", nil)
+			if tt.auth {
+				req = createAuthenticatedRequest(tt.method, "/api/workflows/42/rollback", "")
+			}
+			w := httptest.NewRecorder()
+
+			server.handleWorkflowRollback(w, req, 42)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestNewWorkflowRollbackResponse(t *testing.T) {
+	execution := &database.WorkflowExecution{ID: 42, Status: database.WorkflowStatusFailed}
+
+	response := newWorkflowRollbackResponse(execution, []*database.WorkflowCompensation{
+		{ID: 1, Target: "gitea repository platform/shop", Status: database.CompensationStatusRolledBack},
+		{ID: 2, Target: "argocd application shop-default", Status: database.CompensationStatusFailed},
+		{ID: 3, Target: "helm release shop/web", Status: database.CompensationStatusPending},
+		{ID: 4, Target: "namespace shop", Status: database.CompensationStatusPending},
+	})
+
+	assert.Equal(t, int64(42), response.ExecutionID)
+	assert.Equal(t, database.WorkflowStatusFailed, response.Status)
+	assert.Equal(t, 2, response.Pending)
+	assert.Equal(t, 1, response.RolledBack)
+	assert.Equal(t, 1, response.Failed)
+
+	empty := newWorkflowRollbackResponse(execution, nil)
+	assert.NotNil(t, empty.Compensations)
+}
+
+func TestValidateApplicationDependencies(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name      string
+		dependsOn []string
+		wantErr   string
+	}{
+		{name: "no dependencies"},
+		{name: "valid upstreams", dependsOn: []string{"api", "database"}},
+		{name: "invalid name", dependsOn: []string{"Billing_API"}, wantErr: "not a valid DNS label"},
+		{name: "self", dependsOn: []string{"web"}, wantErr: "cannot depend on itself"},
+		{name: "duplicate", dependsOn: []string{"api", "api"}, wantErr: "duplicate application 'api'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &types.ScoreSpec{Metadata: types.Metadata{Name: "web"}, DependsOn: tt.dependsOn}
+
+			err := server.validateApplicationDependencies(spec)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestHandleClusters(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		handler    http.HandlerFunc
+		wantStatus int
+	}{
+		{name: "list method not allowed", method: "POST", path: "/api/clusters", handler: server.HandleClusters, wantStatus: http.StatusMethodNotAllowed},
+		{name: "list without database", method: "GET", path: "/api/clusters", handler: server.HandleClusters, wantStatus: http.StatusServiceUnavailable},
+		{name: "detail method not allowed", method: "DELETE", path: "/api/clusters/prod-eu-1", handler: server.HandleClusterDetail, wantStatus: http.StatusMethodNotAllowed},
+		{name: "detail without database", method: "GET", path: "/api/clusters/prod-eu-1", handler: server.HandleClusterDetail, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, createAuthenticatedRequest(tt.method, tt.path, ""))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandleMyOverview(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "method not allowed", req: createAuthenticatedRequest("POST", "/api/me/overview", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "unauthenticated", req: httptest.NewRequest("GET", "/api/me/overview", nil), wantStatus: http.StatusUnauthorized},
+		{name: "without database", req: createAuthenticatedRequest("GET", "/api/me/overview", ""), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.HandleMyOverview(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestBuildUserOverview(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	failure := "quota exceeded"
+
+	sources := &overviewSources{
+		applications: []*database.Application{
+			{Name: "shop", Team: "engineering"},
+			{Name: "billing", Team: "engineering"},
+		},
+		resources: map[string][]*database.ResourceInstance{
+			"shop": {
+				{ID: 1, ResourceName: "db", ResourceType: "postgres", State: database.ResourceStateActive, HealthStatus: "healthy"},
+				{ID: 2, ResourceName: "cache", ResourceType: "redis", State: database.ResourceStateFailed, ErrorMessage: &failure},
+				{ID: 3, ResourceName: "queue", ResourceType: "kafka", State: database.ResourceStateActive, HealthStatus: "unhealthy"},
+			},
+			"billing": {
+				{ID: 4, ResourceName: "bucket", ResourceType: "s3", State: database.ResourceStateDegraded},
+			},
+		},
+		running: []*database.WorkflowExecutionSummary{
+			{ID: 10, ApplicationName: "shop", WorkflowName: "deploy"},
+			{ID: 11, ApplicationName: "other-team-app", WorkflowName: "deploy"},
+		},
+		approvals: []*database.WorkflowApproval{
+			{ID: 20, ApplicationName: "billing", StepName: "approve-prod"},
+			{ID: 21, ApplicationName: "other-team-app", StepName: "approve-prod"},
+		},
+		apiKeys: []users.APIKey{
+			{Name: "ci", ExpiresAt: now.Add(3 * 24 * time.Hour)},
+			{Name: "old", ExpiresAt: now.Add(-time.Hour)},
+			{Name: "laptop", ExpiresAt: now.Add(60 * 24 * time.Hour)},
+			{Name: "forever"},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		user          *users.User
+		keyWindow     time.Duration
+		wantApprovals []int64
+		wantKeys      []string
+	}{
+		{
+			name:          "developer",
+			user:          &users.User{Username: "alice", Team: "engineering", Role: "developer"},
+			keyWindow:     defaultKeyExpiryWindow,
+			wantApprovals: []int64{20},
+			wantKeys:      []string{"old", "ci"},
+		},
+		{
+			name:          "admin sees every pending approval",
+			user:          &users.User{Username: "admin", Team: "engineering", Role: "admin"},
+			keyWindow:     defaultKeyExpiryWindow,
+			wantApprovals: []int64{20, 21},
+			wantKeys:      []string{"old", "ci"},
+		},
+		{
+			name:          "wider key window",
+			user:          &users.User{Username: "alice", Team: "engineering", Role: "developer"},
+			keyWindow:     90 * 24 * time.Hour,
+			wantApprovals: []int64{20},
+			wantKeys:      []string{"old", "ci", "laptop"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overview := buildUserOverview(tt.user, sources, now, tt.keyWindow)
+
+			assert.Equal(t, tt.user.Username, overview.Username)
+			assert.Equal(t, now, overview.GeneratedAt)
+
+			require.Len(t, overview.Applications, 2)
+			assert.Equal(t, "billing", overview.Applications[0].Name)
+			assert.Equal(t, 1, overview.Applications[0].FailingResources)
+			assert.Equal(t, "shop", overview.Applications[1].Name)
+			assert.Equal(t, 3, overview.Applications[1].Resources)
+			assert.Equal(t, 2, overview.Applications[1].FailingResources)
+
+			require.Len(t, overview.FailingResources, 3)
+			var names []string
+			for _, resource := range overview.FailingResources {
+				names = append(names, resource.Name)
+			}
+			assert.ElementsMatch(t, []string{"cache", "queue", "bucket"}, names)
+
+			require.Len(t, overview.RunningWorkflows, 1)
+			assert.Equal(t, int64(10), overview.RunningWorkflows[0].ID)
+
+			var approvals []int64
+			for _, approval := range overview.PendingApprovals {
+				approvals = append(approvals, approval.ID)
+			}
+			assert.Equal(t, tt.wantApprovals, approvals)
+
+			var keys []string
+			for _, key := range overview.ExpiringAPIKeys {
+				keys = append(keys, key.Name)
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+			assert.True(t, overview.ExpiringAPIKeys[0].Expired)
+			assert.Equal(t, 0, overview.ExpiringAPIKeys[0].DaysLeft)
+			assert.Equal(t, 3, overview.ExpiringAPIKeys[1].DaysLeft)
+
+			assert.Equal(t, OverviewCounts{
+				Applications:     2,
+				RunningWorkflows: 1,
+				FailingResources: 3,
+				PendingApprovals: len(tt.wantApprovals),
+				ExpiringAPIKeys:  len(tt.wantKeys),
+			}, overview.Counts)
+		})
+	}
+}
+
+func TestHandleValidate(t *testing.T) {
+	server := NewServer()
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte(`scoreLint:
+  defaultProfile: standard
+  profiles:
+    standard:
+      rules:
+        - name: labels
+          type: required-labels
+          labels: [team]
+        - name: no-latest
+          type: forbidden-images
+          images: ["*:latest"]
+          severity: warning
+    strict:
+      rules:
+        - name: no-latest
+          type: forbidden-images
+          images: ["*:latest"]
+`), 0600))
+
+	compliant := `apiVersion: score.dev/v1b1
+metadata:
+  name: shop
+  labels:
+    team: ecommerce
+containers:
+  web:
+    image: nginx:1.25
+`
+	latest := `apiVersion: score.dev/v1b1
+metadata:
+  name: shop
+  labels:
+    team: ecommerce
+containers:
+  web:
+    image: nginx:latest
+`
+	unlabelled := `apiVersion: score.dev/v1b1
+metadata:
+  name: Shop
+containers:
+  web:
+    image: nginx:1.25
+`
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		wantStatus   int
+		wantValid    bool
+		wantErrors   int
+		wantFindings []string
+	}{
+		{name: "method not allowed", method: "GET", path: "/api/validate", wantStatus: http.StatusMethodNotAllowed},
+		{name: "empty body", method: "POST", path: "/api/validate", wantStatus: http.StatusBadRequest},
+		{name: "unknown profile", method: "POST", path: "/api/validate?profile=lenient", body: compliant, wantStatus: http.StatusBadRequest},
+		{name: "compliant", method: "POST", path: "/api/validate", body: compliant, wantStatus: http.StatusOK, wantValid: true},
+		{name: "warning only", method: "POST", path: "/api/validate", body: latest, wantStatus: http.StatusOK, wantValid: true, wantFindings: []string{"warning"}},
+		{name: "profile selected", method: "POST", path: "/api/validate?profile=strict", body: latest, wantStatus: http.StatusOK, wantFindings: []string{"error"}},
+		{name: "deployment checks and lint errors", method: "POST", path: "/api/validate", body: unlabelled, wantStatus: http.StatusOK, wantErrors: 1, wantFindings: []string{"error"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.HandleValidate(w, createAuthenticatedRequest(tt.method, tt.path, tt.body))
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var response ValidateResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantValid, response.Valid)
+			assert.Len(t, response.Errors, tt.wantErrors)
+			require.NotNil(t, response.Lint)
+			var severities []string
+			for _, finding := range response.Lint.Findings {
+				severities = append(severities, finding.Severity)
+			}
+			assert.Equal(t, tt.wantFindings, severities)
+		})
+	}
+}
+
+func TestEnforceLintProfile(t *testing.T) {
+	server := NewServer()
+	t.Chdir(t.TempDir())
+
+	spec := &types.ScoreSpec{
+		Metadata:   types.Metadata{Name: "shop"},
+		Containers: map[string]types.Container{"web": {Image: "nginx:latest"}},
+	}
+
+	// Without an admin config nothing is enforced
+	w := httptest.NewRecorder()
+	assert.True(t, server.enforceLintProfile(w, httptest.NewRequest("POST", "/api/applications", nil), spec))
+
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte(`scoreLint:
+  defaultProfile: standard
+  profiles:
+    standard:
+      rules:
+        - type: forbidden-images
+          images: ["*:latest"]
+`), 0600))
+
+	w = httptest.NewRecorder()
+	assert.False(t, server.enforceLintProfile(w, httptest.NewRequest("POST", "/api/applications", nil), spec))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "violates lint profile 'standard': containers.web.image: image 'nginx:latest' is forbidden")
+}
+
+func TestStartWorkflowRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     func(ctx context.Context) error
+		wantErr string
+	}{
+		{
+			name:    "run fails before recording an execution",
+			run:     func(ctx context.Context) error { return fmt.Errorf("workflow validation failed") },
+			wantErr: "workflow validation failed",
+		},
+		{
+			name:    "run records no execution",
+			run:     func(ctx context.Context) error { return nil },
+			wantErr: "workflow did not record an execution",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executionID, done, err := startWorkflowRun(context.Background(), tt.run)
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Zero(t, executionID)
+			assert.Nil(t, done)
+		})
+	}
+}
+
+type noopWorkflowExecutor struct{}
+
+func (noopWorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string, workflow types.Workflow, goldenPathParams ...map[string]string) error {
+	return nil
+}
+
+func TestHandleQueueTask(t *testing.T) {
+	server := NewServer()
+
+	req := createAuthenticatedRequest("GET", "/api/queue/tasks/task-1", "")
+	w := httptest.NewRecorder()
+	server.HandleQueueTask(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	server.workflowQueue = queue.NewQueue(1, noopWorkflowExecutor{}, nil)
+	taskID, err := server.workflowQueue.Enqueue("shop", "golden-path-deploy-app", types.Workflow{}, map[string]interface{}{
+		"parameters": map[string]string{"db_password": "secret"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{name: "method not allowed", method: "POST", path: "/api/queue/tasks/" + taskID, wantStatus: http.StatusMethodNotAllowed},
+		{name: "missing ID", method: "GET", path: "/api/queue/tasks/", wantStatus: http.StatusBadRequest},
+		{name: "unknown task", method: "GET", path: "/api/queue/tasks/task-unknown", wantStatus: http.StatusNotFound},
+		{name: "pending task", method: "GET", path: "/api/queue/tasks/" + taskID, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createAuthenticatedRequest(tt.method, tt.path, "")
+			w := httptest.NewRecorder()
+
+			server.HandleQueueTask(w, req)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var task queue.TaskInfo
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &task))
+			assert.Equal(t, taskID, task.ID)
+			assert.Equal(t, "shop", task.AppName)
+			assert.Equal(t, queue.TaskStatusPending, task.Status)
+			assert.NotContains(t, w.Body.String(), "secret", "parameters are not exposed")
+		})
+	}
+}
+
+// fakeDirectoryStore serves user lookups; other store methods are not used by these tests
+type fakeDirectoryStore struct {
+	directoryStore
+	users map[string]*database.DirectoryUser
+}
+
+func (f fakeDirectoryStore) GetDirectoryUserByUsername(username string) (*database.DirectoryUser, error) {
+	user, ok := f.users[username]
+	if !ok {
+		return nil, database.ErrDirectoryNotFound
+	}
+	return user, nil
+}
+
+func (f fakeDirectoryStore) ListDirectoryUsers(username string, offset, limit int) ([]*database.DirectoryUser, int, error) {
+	var matches []*database.DirectoryUser
+	if user, ok := f.users[username]; ok {
+		matches = append(matches, user)
+	}
+	return matches, len(matches), nil
+}
+
+func TestHandleSCIM(t *testing.T) {
+	server := NewServer()
+	server.directoryStore = fakeDirectoryStore{users: map[string]*database.DirectoryUser{
+		"alice": {ID: 1, Username: "alice", Active: true},
+	}}
+	t.Chdir(t.TempDir())
+	t.Setenv("TEST_SCIM_TOKEN", "s3cret")
+
+	enabled := "scim:\n  enabled: true\n  tokenEnv: TEST_SCIM_TOKEN\n"
+	tests := []struct {
+		name        string
+		config      string
+		method      string
+		path        string
+		token       string
+		wantStatus  int
+		wantContain string
+	}{
+		{name: "disabled", config: "scim:\n  enabled: false\n", method: "GET", path: "/scim/v2/Users", token: "s3cret", wantStatus: http.StatusNotFound},
+		{name: "missing token", config: enabled, method: "GET", path: "/scim/v2/Users", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", config: enabled, method: "GET", path: "/scim/v2/Users", token: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "filter users", config: enabled, method: "GET", path: "/scim/v2/Users?filter=" + url.QueryEscape(`userName eq "alice"`), token: "s3cret", wantStatus: http.StatusOK, wantContain: `"userName":"alice"`},
+		{name: "invalid filter", config: enabled, method: "GET", path: "/scim/v2/Users?filter=" + url.QueryEscape(`title pr`), token: "s3cret", wantStatus: http.StatusBadRequest, wantContain: `"scimType":"invalidFilter"`},
+		{name: "service provider config", config: enabled, method: "GET", path: "/scim/v2/ServiceProviderConfig", token: "s3cret", wantStatus: http.StatusOK, wantContain: `"patch":{"supported":true}`},
+		{name: "unknown resource", config: enabled, method: "GET", path: "/scim/v2/Schemas", token: "s3cret", wantStatus: http.StatusNotFound},
+		{name: "method not allowed", config: enabled, method: "DELETE", path: "/scim/v2/Users", token: "s3cret", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile("admin-config.yaml", []byte(tt.config), 0600))
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+
+			server.HandleSCIM(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, "application/scim+json", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), tt.wantContain)
+		})
+	}
+}
+
+func TestApplyDirectory(t *testing.T) {
+	server := NewServer()
+	server.directoryStore = fakeDirectoryStore{users: map[string]*database.DirectoryUser{
+		"alice": {Username: "alice", Active: true, Teams: []string{"payments", "platform"}},
+		"bob":   {Username: "bob", Active: false},
+		"carol": {Username: "carol", Active: true, Teams: []string{}},
+	}}
+
+	tests := []struct {
+		name     string
+		user     *users.User
+		wantTeam string
+		wantErr  error
+	}{
+		{name: "provisioned team replaces provider team", user: &users.User{Username: "alice", Team: "oidc-users", Role: "user"}, wantTeam: "payments"},
+		{name: "deactivated user is rejected", user: &users.User{Username: "bob", Team: "oidc-users"}, wantErr: auth.ErrInvalidCredentials},
+		{name: "user without teams keeps provider team", user: &users.User{Username: "carol", Team: "developers"}, wantTeam: "developers"},
+		{name: "unprovisioned user is unchanged", user: &users.User{Username: "admin", Team: "platform"}, wantTeam: "platform"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := server.applyDirectory(tt.user)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTeam, user.Team)
+			assert.Equal(t, tt.user.Role, user.Role)
+		})
+	}
+}
+
+func TestCompareWorkflowExecutions(t *testing.T) {
+	started := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	ms := func(v int64) *int64 { return &v }
+	str := func(v string) *string { return &v }
+	completed := func(d time.Duration) *time.Time { t := started.Add(d); return &t }
+
+	good := &database.WorkflowExecution{
+		ID: 1, ApplicationName: "shop", WorkflowName: "deploy-app", Status: "completed",
+		StartedAt: started, CompletedAt: completed(30 * time.Second),
+		Steps: []*database.WorkflowStepExecution{
+			{StepName: "provision", StepType: "terraform", Status: "completed", DurationMs: ms(10000),
+				StepConfig: map[string]interface{}{"path": "./tf", "variables": map[string]interface{}{"region": "eu-west-1", "size": 2}}},
+			{StepName: "deploy", StepType: "kubernetes", Status: "completed", DurationMs: ms(20000),
+				OutputLogs: str("applying manifests\ndeployment ready\n")},
+			{StepName: "notify", StepType: "slack", Status: "completed", DurationMs: ms(100)},
+		},
+	}
+	bad := &database.WorkflowExecution{
+		ID: 2, ApplicationName: "shop", WorkflowName: "deploy-app", Status: "failed",
+		StartedAt: started, CompletedAt: completed(95 * time.Second), ErrorMessage: str("step deploy failed"),
+		Steps: []*database.WorkflowStepExecution{
+			{StepName: "provision", StepType: "terraform", Status: "completed", DurationMs: ms(15000),
+				StepConfig: map[string]interface{}{"path": "./tf", "variables": map[string]interface{}{"region": "us-east-1", "size": 2}}},
+			{StepName: "deploy", StepType: "kubernetes", Status: "failed", DurationMs: ms(80000), ErrorMessage: str("timeout"),
+				OutputLogs: str("applying manifests\nwaiting for rollout\nwaiting for rollout\n")},
+			{StepName: "smoke-test", StepType: "validation", Status: "pending"},
+		},
+	}
+
+	comparison := compareWorkflowExecutions(good, bad,
+		map[string]string{"version": "1.4.0", "replicas": "2"},
+		map[string]string{"version": "1.5.0", "replicas": "2", "canary": "true"})
+
+	assert.Equal(t, "deploy-app", comparison.WorkflowName)
+	assert.Equal(t, int64(65000), *comparison.DurationDeltaMs)
+	assert.Equal(t, []ValueChange{
+		{Key: "canary", A: nil, B: "true"},
+		{Key: "version", A: "1.4.0", B: "1.5.0"},
+	}, comparison.Parameters)
+
+	require.Len(t, comparison.Steps, 4)
+	provision, deploy, notify, smoke := comparison.Steps[0], comparison.Steps[1], comparison.Steps[2], comparison.Steps[3]
+
+	assert.Equal(t, presenceBoth, provision.Presence)
+	assert.False(t, provision.StatusChanged)
+	assert.Equal(t, int64(5000), *provision.DurationDeltaMs)
+	assert.Equal(t, []ValueChange{{Key: "variables.region", A: "eu-west-1", B: "us-east-1"}}, provision.ConfigChanges)
+	assert.Nil(t, provision.Logs)
+
+	assert.True(t, deploy.StatusChanged)
+	assert.Equal(t, "timeout", *deploy.ErrorB)
+	assert.Equal(t, &LogDiff{LinesA: 2, LinesB: 3, Added: 2, Removed: 1,
+		FirstDifference: &LogLine{Line: 2, A: "deployment ready", B: "waiting for rollout"}}, deploy.Logs)
+
+	assert.Equal(t, presenceOnlyA, notify.Presence)
+	assert.Equal(t, presenceOnlyB, smoke.Presence)
+	assert.Equal(t, "pending", smoke.StatusB)
+
+	assert.Equal(t, WorkflowComparisonSummary{
+		StepsChanged:       4,
+		StatusChanges:      1,
+		ConfigChanges:      1,
+		FirstDivergentStep: "provision",
+		SlowestRegression:  "deploy",
+	}, comparison.Summary)
+}
+
+func TestDiffLogs(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want *LogDiff
+	}{
+		{name: "identical", a: "one\ntwo\n", b: "one\ntwo", want: &LogDiff{LinesA: 2, LinesB: 2}},
+		{name: "reordered lines are unchanged but diverge", a: "one\ntwo", b: "two\none",
+			want: &LogDiff{LinesA: 2, LinesB: 2, FirstDifference: &LogLine{Line: 1, A: "one", B: "two"}}},
+		{name: "only in B", a: "", b: "new", want: &LogDiff{LinesB: 1, Added: 1, FirstDifference: &LogLine{Line: 1, B: "new"}}},
+		{name: "truncated in B", a: "one\ntwo", b: "one", want: &LogDiff{LinesA: 2, LinesB: 1, Removed: 1, FirstDifference: &LogLine{Line: 2, A: "two"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, diffLogs(tt.a, tt.b))
+		})
+	}
+}
+
+func TestHandleCompareWorkflowsWithoutDatabase(t *testing.T) {
+	server := NewServer()
+	w := httptest.NewRecorder()
+	server.HandleWorkflowDetail(w, createAuthenticatedRequest("GET", "/api/workflows/compare?a=1&b=2", ""))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestHandleApplicationWorkspace(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(root+"/shop/terraform", 0755))
+	require.NoError(t, os.WriteFile(root+"/shop/terraform/main.tf", []byte("resource {}"), 0644))
+	require.NoError(t, os.WriteFile(root+"/shop/terraform/terraform.tfstate", []byte("{}"), 0644))
+
+	server := NewServer()
+	server.appWorkspaces = workspaces.New(root, nil)
+	admin := &users.User{Username: "admin", Team: "platform", Role: "admin"}
+	asAdmin := func(method, target string) *http.Request {
+		req := httptest.NewRequest(method, target, nil)
+		return req.WithContext(context.WithValue(req.Context(), contextKeyUser, admin))
+	}
+
+	w := httptest.NewRecorder()
+	server.HandleApplicationDetail(w, asAdmin("GET", "/api/applications/shop/workspace"))
+	require.Equal(t, http.StatusOK, w.Code)
+	var listing struct {
+		Files     []workspaces.File `json:"files"`
+		TotalSize int64             `json:"total_size"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listing))
+	require.Len(t, listing.Files, 2)
+	assert.Equal(t, int64(13), listing.TotalSize)
+	assert.True(t, listing.Files[1].Restricted)
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "download", req: asAdmin("GET", "/api/applications/shop/workspace/download?path=terraform/main.tf"), wantStatus: http.StatusOK},
+		{name: "restricted file", req: asAdmin("GET", "/api/applications/shop/workspace/download?path=terraform/terraform.tfstate"), wantStatus: http.StatusForbidden},
+		{name: "missing path", req: asAdmin("GET", "/api/applications/shop/workspace/download"), wantStatus: http.StatusBadRequest},
+		{name: "missing file", req: asAdmin("GET", "/api/applications/shop/workspace/download?path=outputs.tf"), wantStatus: http.StatusNotFound},
+		{name: "method not allowed", req: asAdmin("DELETE", "/api/applications/shop/workspace"), wantStatus: http.StatusMethodNotAllowed},
+		{name: "unauthenticated", req: httptest.NewRequest("GET", "/api/applications/shop/workspace", nil), wantStatus: http.StatusUnauthorized},
+		{name: "non-admin without database", req: createAuthenticatedRequest("GET", "/api/applications/shop/workspace", ""), wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.HandleApplicationDetail(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	w = httptest.NewRecorder()
+	server.HandleApplicationDetail(w, asAdmin("GET", "/api/applications/shop/workspace/download?path=terraform/main.tf"))
+	assert.Equal(t, "resource {}", w.Body.String())
+	assert.Equal(t, `attachment; filename=main.tf`, w.Header().Get("Content-Disposition"))
+}
+
+func TestWritePreflightReport(t *testing.T) {
+	server := NewServer()
+
+	w := httptest.NewRecorder()
+	server.writePreflightReport(w, "deploy-app", "shop", &workflow.PreflightReport{
+		Passed: false,
+		Results: []workflow.PreflightResult{
+			{Name: "cluster", Type: workflow.PreflightClusterReachable, Message: "cluster is not reachable"},
+			{Name: "providers", Type: workflow.PreflightProvidersRegistered, Passed: true},
+			{Name: "namespace", Type: workflow.PreflightNamespaceFree, Message: "namespace shop already exists"},
+		},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "2 pre-flight check(s) failed: cluster, namespace", response["error"])
+
+	w = httptest.NewRecorder()
+	server.writePreflightReport(w, "deploy-app", "shop", &workflow.PreflightReport{Passed: true, Results: []workflow.PreflightResult{}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "error")
+}
+
+func TestHandleAdminUsageWithoutDatabase(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		target     string
+		wantStatus int
+	}{
+		{target: "/api/admin/usage", wantStatus: http.StatusBadRequest},
+		{target: "/api/admin/usage?month=2026-13", wantStatus: http.StatusBadRequest},
+		{target: "/api/admin/usage?month=2026-09&format=xml", wantStatus: http.StatusBadRequest},
+		{target: "/api/admin/usage?month=2026-09", wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.HandleAdminUsage(w, httptest.NewRequest("GET", tt.target, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandleAdminResourcesWithoutDatabase(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{name: "unknown action", method: "POST", target: "/api/admin/resources/17/health", body: `{}`, wantStatus: http.StatusNotFound},
+		{name: "method not allowed", method: "GET", target: "/api/admin/resources/17/state", wantStatus: http.StatusMethodNotAllowed},
+		{name: "invalid id", method: "POST", target: "/api/admin/resources/db/state", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", method: "POST", target: "/api/admin/resources/17/state", body: `state=failed`, wantStatus: http.StatusBadRequest},
+		{name: "unknown state", method: "POST", target: "/api/admin/resources/17/state", body: `{"state": "gone", "reason": "INC-42"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing reason", method: "POST", target: "/api/admin/resources/17/state", body: `{"state": "failed", "reason": " "}`, wantStatus: http.StatusBadRequest},
+		{name: "no database", method: "POST", target: "/api/admin/resources/17/state", body: `{"state": "failed", "reason": "INC-42"}`, wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.HandleAdminResources(w, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+		})
+	}
+
+	w := httptest.NewRecorder()
+	server.HandleAuditLog(w, httptest.NewRequest("GET", "/api/admin/audit?target_type=resource", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+// fakeStepLogStore records the step log writes of a LogBuffer
+type fakeStepLogStore struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (f *fakeStepLogStore) AddWorkflowStepLogs(stepID int64, logs string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writes = append(f.writes, logs)
+	return nil
+}
+
+func (f *fakeStepLogStore) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.writes)
+}
+
+func TestLogBufferCoalescesWrites(t *testing.T) {
+	stepID := int64(7)
+
+	t.Run("flushes on size and close", func(t *testing.T) {
+		store := &fakeStepLogStore{}
+		lb := NewLogBuffer(&stepID, nil)
+		lb.repo = store
+		lb.SetFlushThresholds(200, time.Hour)
+
+		for i := 0; i < 10; i++ {
+			_, err := fmt.Fprintf(lb, "line %d", i)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, store.count(), "the 7th of ten 29 byte lines reaches the 200 byte threshold")
+
+		require.NoError(t, lb.Close())
+		require.Equal(t, 2, store.count())
+		assert.Equal(t, 10, strings.Count(strings.Join(store.writes, ""), "\n"))
+		assert.Empty(t, lb.GetLogs())
+	})
+
+	t.Run("flushes on interval", func(t *testing.T) {
+		store := &fakeStepLogStore{}
+		lb := NewLogBuffer(&stepID, nil)
+		lb.repo = store
+		lb.SetFlushThresholds(0, 10*time.Millisecond)
+
+		_, err := lb.Write([]byte("Initializing provider plugins..."))
+		require.NoError(t, err)
+		assert.Equal(t, 0, store.count())
+		assert.Eventually(t, func() bool { return store.count() == 1 }, time.Second, 5*time.Millisecond)
+		assert.Contains(t, store.writes[0], "Initializing provider plugins...")
+	})
+
+	t.Run("keeps logs without step tracking", func(t *testing.T) {
+		lb := NewLogBuffer(nil, nil)
+		_, err := lb.Write([]byte("dry run"))
+		require.NoError(t, err)
+		require.NoError(t, lb.Close())
+		assert.Contains(t, lb.GetLogs(), "dry run")
+	})
+}
+
+func TestDevServerListsWorkflowsFromDevDatabase(t *testing.T) {
+	store, err := devdb.Open(filepath.Join(t.TempDir(), "dev.db"))
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	exec, err := store.CreateWorkflowExecution("demo-app", "golden-path-deploy-app", 1)
+	require.NoError(t, err)
+
+	srv := NewDevServer(store, nil)
+	defer srv.workflowQueue.Stop()
+	assert.False(t, srv.HasDatabase(), "dev mode has no PostgreSQL database")
+
+	w := httptest.NewRecorder()
+	srv.handleListWorkflows(w, httptest.NewRequest("GET", "/api/workflows?app=demo-app", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response PaginatedWorkflowsResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, int64(1), response.Total)
+
+	w = httptest.NewRecorder()
+	srv.handleGetWorkflow(w, httptest.NewRequest("GET", fmt.Sprintf("/api/workflows/%d", exec.ID), nil), exec.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestIntegrationHealthChecks(t *testing.T) {
+	integrations := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/version":
+			_, _ = w.Write([]byte(`{"version":"1.21.0"}`))
+		case "/v1/sys/health":
+			w.WriteHeader(http.StatusServiceUnavailable) // Sealed
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer integrations.Close()
+
+	adminCfg := &admin.AdminConfig{}
+	adminCfg.Gitea.URL = integrations.URL
+	adminCfg.Vault.URL = integrations.URL + "/"
+	adminCfg.ArgoCD.URL = integrations.URL
+	adminCfg.HealthChecks.Disabled = []string{"argocd"}
+
+	checkHealth := func(required ...string) (int, health.HealthResponse) {
+		adminCfg.HealthChecks.Required = required
+		server := NewServer()
+		require.NoError(t, server.RegisterIntegrationChecks(adminCfg))
+
+		w := httptest.NewRecorder()
+		server.HandleHealth(w, httptest.NewRequest("GET", "/health", nil))
+		var response health.HealthResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	code, response := checkHealth()
+	assert.Equal(t, http.StatusOK, code, "optional integrations never fail liveness")
+	assert.Equal(t, health.StatusDegraded, response.Status)
+	assert.Equal(t, health.StatusHealthy, response.Checks["gitea"].Status)
+	assert.Equal(t, health.StatusDegraded, response.Checks["vault"].Status)
+	assert.Contains(t, response.Checks["vault"].Error, "returned HTTP 503")
+	assert.NotContains(t, response.Checks, "argocd", "disabled integrations are not checked")
+	assert.NotContains(t, response.Checks, "object_storage", "unconfigured integrations are not checked")
+
+	code, response = checkHealth("vault")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusUnhealthy, response.Checks["vault"].Status)
+
+	adminCfg.HealthChecks.Required = []string{"jenkins"}
+	assert.ErrorContains(t, NewServer().RegisterIntegrationChecks(adminCfg), "unknown integration 'jenkins'")
+}
+
+func TestHealthCheckLatencyInMilliseconds(t *testing.T) {
+	data, err := json.Marshal(health.Check{Name: "gitea", Status: health.StatusHealthy, Latency: 1500 * time.Microsecond})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"latency_ms":1.5`)
+
+	var check health.Check
+	require.NoError(t, json.Unmarshal(data, &check))
+	assert.Equal(t, 1500*time.Microsecond, check.Latency)
+}
+
+func TestHandleApplicationPreview(t *testing.T) {
+	server := &Server{}
+	spec := `apiVersion: score.dev/v1b1
+metadata:
+  name: shop
+containers:
+  web:
+    image: nginx:latest
+resources:
+  db:
+    type: postgres
+  cache:
+    type: redis
+`
+
+	w := httptest.NewRecorder()
+	server.HandleApplicationDetail(w, createAuthenticatedRequest("POST", "/api/applications/shop/preview", spec))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var preview applicationPreview
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.Equal(t, "shop", preview.Application)
+	assert.False(t, preview.Exists)
+	require.Len(t, preview.Resources, 2)
+	assert.Equal(t, "cache", preview.Resources[0].Name)
+	assert.Equal(t, resourceActionCreate, preview.Resources[0].Action)
+	assert.Equal(t, 2, preview.Summary[resourceActionCreate])
+	assert.NotEmpty(t, preview.Steps)
+	assert.Greater(t, preview.EstimatedSeconds, 0)
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "name mismatch", req: createAuthenticatedRequest("POST", "/api/applications/other/preview", spec), wantStatus: http.StatusBadRequest},
+		{name: "empty body", req: createAuthenticatedRequest("POST", "/api/applications/shop/preview", ""), wantStatus: http.StatusBadRequest},
+		{name: "method not allowed", req: createAuthenticatedRequest("GET", "/api/applications/shop/preview", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "unauthenticated", req: httptest.NewRequest("POST", "/api/applications/shop/preview", strings.NewReader(spec)), wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.HandleApplicationDetail(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestDiffResources(t *testing.T) {
+	spec := &types.ScoreSpec{
+		Metadata:    types.Metadata{Name: "shop"},
+		Environment: &types.Environment{Type: "kubernetes"},
+		Resources: map[string]types.Resource{
+			"db":    {Type: "postgres", Params: map[string]interface{}{"version": 15}},
+			"cache": {Type: "redis", Params: map[string]interface{}{"size": "small"}},
+			"queue": {Type: "rabbitmq"},
+		},
+	}
+	current := []*database.ResourceInstance{
+		{ResourceName: "db", ResourceType: "postgres", State: database.ResourceStateActive,
+			Configuration: map[string]interface{}{"type": "postgres", "app_name": "shop", "version": float64(15)}},
+		{ResourceName: "cache", ResourceType: "redis", State: database.ResourceStateActive,
+			Configuration: map[string]interface{}{"type": "redis", "app_name": "shop", "size": "large"}},
+		{ResourceName: "bucket", ResourceType: "s3", State: database.ResourceStateActive},
+		{ResourceName: "old", ResourceType: "s3", State: database.ResourceStateTerminated},
+		{ResourceName: "shop-k8s", ResourceType: "kubernetes", State: database.ResourceStateActive},
+	}
+
+	actions := map[string]resourceChange{}
+	for _, change := range diffResources(spec, current) {
+		actions[change.Name] = change
+	}
+
+	require.Len(t, actions, 4)
+	assert.Equal(t, resourceActionUnchanged, actions["db"].Action, "numbers stored as JSON compare equal")
+	assert.Equal(t, resourceActionUpdate, actions["cache"].Action)
+	assert.Equal(t, []string{"size"}, actions["cache"].Changes)
+	assert.Equal(t, resourceActionCreate, actions["queue"].Action)
+	assert.Equal(t, resourceActionDelete, actions["bucket"].Action)
+}
+
+func TestHandleApplicationStatus(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "method not allowed", req: createAuthenticatedRequest("POST", "/api/applications/shop/status", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "unauthenticated", req: httptest.NewRequest("GET", "/api/applications/shop/status", nil), wantStatus: http.StatusUnauthorized},
+		{name: "without database", req: createAuthenticatedRequest("GET", "/api/applications/shop/status", ""), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.HandleApplicationDetail(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestBuildApplicationStatus(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	failure := "quota exceeded"
+	completed := now.Add(-50 * time.Minute)
+
+	sources := &applicationStatusSources{
+		application: &database.Application{Name: "shop", Team: "engineering", CreatedBy: "alice", UpdatedAt: now.Add(-2 * time.Hour)},
+		resources: []*database.ResourceInstance{
+			{ID: 1, ResourceName: "db", ResourceType: "postgres", State: database.ResourceStateActive, HealthStatus: "healthy",
+				Hints: []database.ResourceHint{{Type: "connection_string", Label: "Connection", Value: "postgres://db"}}},
+			{ID: 2, ResourceName: "cache", ResourceType: "redis", State: database.ResourceStateFailed, ErrorMessage: &failure},
+		},
+		transitions: map[int64][]*database.ResourceStateTransition{
+			2: {{FromState: database.ResourceStateProvisioning, ToState: database.ResourceStateFailed, Reason: failure, TransitionedAt: now.Add(-10 * time.Minute)}},
+		},
+		executions: []*database.WorkflowExecutionSummary{
+			{ID: 11, ApplicationName: "shop", WorkflowName: "deploy", Status: database.WorkflowStatusRunning, StartedAt: now.Add(-5 * time.Minute)},
+			{ID: 10, ApplicationName: "shop", WorkflowName: "deploy", Status: database.WorkflowStatusCompleted, StartedAt: now.Add(-time.Hour), CompletedAt: &completed},
+		},
+		approvals: []*database.WorkflowApproval{
+			{ID: 20, ApplicationName: "shop", StepName: "approve-prod", RequestedAt: now.Add(-time.Minute)},
+			{ID: 21, ApplicationName: "billing", StepName: "approve-prod", RequestedAt: now},
+		},
+		provenance: []*database.DeploymentProvenance{{ID: 5, ApplicationName: "shop", Digest: "sha256:abc"}},
+	}
+
+	status := buildApplicationStatus(sources, now)
+
+	assert.Equal(t, "shop", status.Name)
+	require.NotNil(t, status.LatestDeployment)
+	assert.Equal(t, database.WorkflowStatusRunning, status.LatestDeployment.Status)
+	assert.Equal(t, int64(11), status.LatestDeployment.Workflow.ID)
+	assert.Equal(t, "sha256:abc", status.LatestDeployment.Provenance.Digest)
+
+	require.Len(t, status.Resources, 2)
+	assert.Equal(t, "cache", status.Resources[0].Name, "resources are sorted by name")
+	assert.Equal(t, failure, status.Resources[0].ErrorMessage)
+	assert.Len(t, status.Resources[1].Hints, 1)
+	assert.Equal(t, ApplicationHealth{Status: "degraded", Resources: 2, Healthy: 1, FailingResources: 1}, status.Health)
+
+	require.Len(t, status.ActiveWorkflows, 1)
+	assert.Equal(t, int64(11), status.ActiveWorkflows[0].ID)
+	require.Len(t, status.PendingApprovals, 1)
+	assert.Equal(t, int64(20), status.PendingApprovals[0].ID)
+
+	var subjects []string
+	for i, event := range status.RecentEvents {
+		subjects = append(subjects, event.Type+":"+event.Subject)
+		if i > 0 {
+			assert.False(t, event.Time.After(status.RecentEvents[i-1].Time), "events are newest first")
+		}
+	}
+	assert.Equal(t, []string{"approval:approve-prod", "workflow:deploy", "resource:cache", "workflow:deploy", "workflow:deploy"}, subjects)
+	assert.Equal(t, "error", status.RecentEvents[2].Severity)
+
+	sources.hibernation = &database.ApplicationHibernation{ApplicationName: "shop", Reason: "no traffic for 72h0m0s"}
+	status = buildApplicationStatus(sources, now)
+	assert.Equal(t, "hibernated", status.Health.Status)
+	assert.Equal(t, sources.hibernation, status.Hibernation)
+}
+
+func TestReadDeployRequest(t *testing.T) {
+	spec := "apiVersion: score.dev/v1b1\nmetadata:\n  name: upload-app\n"
+
+	multipartRequest := func(t *testing.T, parts map[string]string, files [][2]string) *http.Request {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for field, value := range parts {
+			require.NoError(t, writer.WriteField(field, value))
+		}
+		for _, file := range files {
+			part, err := writer.CreateFormFile("files", file[0])
+			require.NoError(t, err)
+			_, err = part.Write([]byte(file[1]))
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+		req := httptest.NewRequest("POST", "/api/applications", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	t.Run("raw YAML body", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/applications", strings.NewReader(spec))
+		req.Header.Set("Content-Type", "application/yaml")
+
+		body, files, isMultipart, err := readDeployRequest(req)
+		require.NoError(t, err)
+		assert.Equal(t, spec, string(body))
+		assert.Empty(t, files)
+		assert.False(t, isMultipart)
+	})
+
+	t.Run("spec with files", func(t *testing.T) {
+		req := multipartRequest(t, map[string]string{"spec": spec}, [][2]string{
+			{"values.yaml", "replicas: 2\n"},
+			{".env.template", "LOG_LEVEL=debug\n"},
+		})
+
+		body, files, isMultipart, err := readDeployRequest(req)
+		require.NoError(t, err)
+		assert.True(t, isMultipart)
+		assert.Equal(t, spec, string(body))
+		require.Len(t, files, 2)
+		assert.Equal(t, "values.yaml", files[0].name)
+		assert.Equal(t, "replicas: 2\n", string(files[0].content))
+		assert.Equal(t, ".env.template", files[1].name)
+
+		records := applicationFiles(files, body, "alice")
+		require.Len(t, records, 2)
+		assert.Equal(t, provenance.Digest([]byte(spec)), records[0].SpecDigest)
+		assert.Equal(t, provenance.Digest([]byte("replicas: 2\n")), records[0].Digest)
+		assert.Equal(t, "alice", records[0].UploadedBy)
+	})
+
+	t.Run("missing spec", func(t *testing.T) {
+		req := multipartRequest(t, nil, [][2]string{{"values.yaml", "replicas: 2\n"}})
+
+		_, _, _, err := readDeployRequest(req)
+		assert.ErrorContains(t, err, "no 'spec' field")
+	})
+
+	t.Run("invalid file name", func(t *testing.T) {
+		req := multipartRequest(t, map[string]string{"spec": spec}, [][2]string{{"my values.yaml", "x"}})
+
+		_, _, _, err := readDeployRequest(req)
+		assert.ErrorContains(t, err, "invalid file name")
+	})
+
+	t.Run("duplicate file", func(t *testing.T) {
+		req := multipartRequest(t, map[string]string{"spec": spec}, [][2]string{
+			{"values.yaml", "a"},
+			{"values.yaml", "b"},
+		})
+
+		_, _, _, err := readDeployRequest(req)
+		assert.ErrorContains(t, err, "more than once")
+	})
+
+	t.Run("file too large", func(t *testing.T) {
+		req := multipartRequest(t, map[string]string{"spec": spec}, [][2]string{
+			{"values.yaml", strings.Repeat("x", maxUploadFileSize+1)},
+		})
+
+		_, _, _, err := readDeployRequest(req)
+		assert.ErrorContains(t, err, "exceeds")
+	})
+
+	t.Run("spec with overlay", func(t *testing.T) {
+		req := multipartRequest(t, map[string]string{"spec": spec, "overlay": "metadata:\n  labels:\n    env: prod\n"}, nil)
+
+		body, files, isMultipart, err := readDeployRequest(req)
+		require.NoError(t, err)
+		assert.True(t, isMultipart)
+		assert.Empty(t, files)
+		assert.Equal(t, "apiVersion: score.dev/v1b1\nmetadata:\n  name: upload-app\n  labels:\n    env: prod\n", string(body))
+	})
+
+	t.Run("invalid overlay", func(t *testing.T) {
+		req := multipartRequest(t, map[string]string{"spec": spec, "overlay": "metadata:\n  name: other-app\n"}, nil)
+
+		_, _, _, err := readDeployRequest(req)
+		assert.ErrorContains(t, err, "invalid overlay: overlay cannot change metadata.name")
+	})
+}
+
+func TestMergeSpecOverlay(t *testing.T) {
+	base := `apiVersion: score.dev/v1b1
+metadata:
+  name: shop
+containers:
+  web:
+    image: shop:1.0 # pinned per environment
+    variables:
+      LOG_LEVEL: info
+      REPLICAS: "1"
+resources:
+  db:
+    type: postgres
+    params:
+      size: small
+  cache:
+    type: redis
+dependsOn: [catalog, payments]
+`
+	overlay := `containers:
+  web:
+    image: shop:1.1
+    variables:
+      LOG_LEVEL: warn
+resources:
+  db:
+    params:
+      size: large
+      backups: true
+  cache: null
+dependsOn: [catalog]
+environment:
+  type: production
+`
+	merged, err := mergeSpecOverlay([]byte(base), []byte(overlay))
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: score.dev/v1b1
+metadata:
+  name: shop
+containers:
+  web:
+    image: shop:1.1
+    variables:
+      LOG_LEVEL: warn
+      REPLICAS: "1"
+resources:
+  db:
+    type: postgres
+    params:
+      size: large
+      backups: true
+dependsOn: [catalog]
+environment:
+  type: production
+`, string(merged))
+
+	merged, err = mergeSpecOverlay([]byte(base), []byte("# nothing for dev\n"))
+	require.NoError(t, err)
+	assert.Equal(t, base, string(merged))
+
+	_, err = mergeSpecOverlay([]byte(base), []byte("- not a mapping\n"))
+	assert.ErrorContains(t, err, "overlay must be a YAML mapping")
+
+	_, err = mergeSpecOverlay([]byte(base), []byte("metadata:\n  name: shop\n"))
+	assert.NoError(t, err, "repeating the name is allowed")
+}
+
+const testOrganizationsConfig = `organizations:
+  payments:
+    description: Payments business unit
+    admins: [testuser]
+    policies:
+      allowedEnvironments: [kubernetes]
+      allowedResourceTypes: [postgres]
+    teams:
+      checkout: {}
+      billing:
+        policies:
+          allowedResourceTypes: [postgres, redis]
+  retail:
+    teams:
+      engineering: {}
+`
+
+func TestOrganizationAccess(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte(testOrganizationsConfig), 0600))
+	server := NewServer()
+
+	orgAdmin := &users.User{Username: "testuser", Team: "engineering", Role: "user"}
+	member := &users.User{Username: "carol", Team: "checkout", Role: "user"}
+	admin := &users.User{Username: "admin", Team: "platform", Role: "admin"}
+
+	assert.True(t, server.canAccessTeam(orgAdmin, "engineering"), "own team")
+	assert.True(t, server.canAccessTeam(orgAdmin, "checkout"), "team of administered organization")
+	assert.False(t, server.canAccessTeam(member, "billing"), "other team of own organization")
+	assert.False(t, server.canAccessTeam(orgAdmin, "platform"), "team outside any organization")
+	assert.True(t, server.canAccessTeam(admin, "billing"))
+}
+
+func TestCheckOrganizationPolicies(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte(testOrganizationsConfig), 0600))
+	server := NewServer()
+
+	spec := func(environment string, resourceTypes ...string) *types.ScoreSpec {
+		s := &types.ScoreSpec{Resources: map[string]types.Resource{}}
+		s.Metadata.Name = "shop"
+		if environment != "" {
+			s.Environment = &types.Environment{Type: environment}
+		}
+		for _, resourceType := range resourceTypes {
+			s.Resources[resourceType] = types.Resource{Type: resourceType}
+		}
+		return s
+	}
+
+	assert.NoError(t, server.checkOrganizationPolicies("checkout", spec("kubernetes", "postgres")))
+	assert.ErrorContains(t, server.checkOrganizationPolicies("checkout", spec("kubernetes", "redis")), "resource type 'redis' is not allowed in organization 'payments'")
+	assert.NoError(t, server.checkOrganizationPolicies("billing", spec("kubernetes", "redis")), "team overrides inherited policy")
+	assert.ErrorContains(t, server.checkOrganizationPolicies("billing", spec("docker")), "environment 'docker' is not allowed")
+	assert.NoError(t, server.checkOrganizationPolicies("platform", spec("docker", "redis")), "teams outside organizations are unrestricted")
+}
+
+func TestHandleOrganizations(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte(testOrganizationsConfig), 0600))
+	server := NewServer()
+
+	w := httptest.NewRecorder()
+	server.HandleOrganizations(w, createAuthenticatedRequest("GET", "/api/organizations", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var summaries []OrganizationSummary
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&summaries))
+	require.Len(t, summaries, 2, "administered organization and organization of own team")
+	assert.Equal(t, "payments", summaries[0].Name)
+	require.Len(t, summaries[0].Teams, 2)
+	assert.Equal(t, "billing", summaries[0].Teams[0].Name)
+	assert.Equal(t, []string{"postgres", "redis"}, summaries[0].Teams[0].Settings.Policies.AllowedResourceTypes)
+	assert.Equal(t, []string{"kubernetes"}, summaries[0].Teams[0].Settings.Policies.AllowedEnvironments)
+	assert.Equal(t, "retail", summaries[1].Name)
+
+	w = httptest.NewRecorder()
+	server.HandleOrganizations(w, createAuthenticatedRequest("POST", "/api/organizations", ""))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestOrganizationRollup(t *testing.T) {
+	hierarchy, err := orgs.New(orgs.Config{
+		"payments": {Teams: map[string]orgs.TeamSettings{"checkout": {}, "billing": {}}},
+	})
+	require.NoError(t, err)
+
+	apps := []*database.Application{
+		{Name: "cart", Team: "checkout", ScoreSpec: &types.ScoreSpec{Resources: map[string]types.Resource{"db": {Type: "postgres"}}}},
+		{Name: "invoices", Team: "billing", ScoreSpec: &types.ScoreSpec{Resources: map[string]types.Resource{"db": {Type: "postgres"}, "cache": {Type: "redis"}}}},
+		{Name: "tools", Team: "platform", ScoreSpec: &types.ScoreSpec{}},
+	}
+
+	assert.Equal(t, map[string]map[string]int{"payments": {"applications": 2, "resources": 3}}, organizationRollup(hierarchy, apps))
+
+	summaries := buildOrganizationSummaries(hierarchy, []string{"payments"}, apps)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 2, summaries[0].Applications)
+	assert.Equal(t, 3, summaries[0].Resources)
+	assert.Equal(t, OrganizationTeam{Name: "billing", Settings: orgs.Settings{Organization: "payments"}, Applications: 1, Resources: 2}, summaries[0].Teams[0])
+}
+
+func TestHibernationHandlers(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("TEST_WAKE_TOKEN", "s3cret")
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte("hibernation:\n  webhookTokenEnv: TEST_WAKE_TOKEN\n"), 0600))
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		req        *http.Request
+		token      string
+		wantStatus int
+	}{
+		{name: "hibernate method not allowed", handler: server.HandleApplicationDetail, req: createAuthenticatedRequest("DELETE", "/api/applications/shop/hibernate", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "hibernate unauthenticated", handler: server.HandleApplicationDetail, req: httptest.NewRequest("POST", "/api/applications/shop/hibernate", nil), wantStatus: http.StatusUnauthorized},
+		{name: "hibernate without database", handler: server.HandleApplicationDetail, req: createAuthenticatedRequest("POST", "/api/applications/shop/hibernate", ""), wantStatus: http.StatusServiceUnavailable},
+		{name: "resume method not allowed", handler: server.HandleApplicationDetail, req: createAuthenticatedRequest("GET", "/api/applications/shop/resume", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "resume without database", handler: server.HandleApplicationDetail, req: createAuthenticatedRequest("POST", "/api/applications/shop/resume", ""), wantStatus: http.StatusServiceUnavailable},
+		{name: "wake without token", handler: server.HandleHibernationWake, req: httptest.NewRequest("POST", "/api/hibernation/wake/shop", nil), wantStatus: http.StatusUnauthorized},
+		{name: "wake with wrong token", handler: server.HandleHibernationWake, req: httptest.NewRequest("POST", "/api/hibernation/wake/shop", nil), token: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "wake without database", handler: server.HandleHibernationWake, req: httptest.NewRequest("POST", "/api/hibernation/wake/shop", nil), token: "s3cret", wantStatus: http.StatusServiceUnavailable},
+		{name: "wake method not allowed", handler: server.HandleHibernationWake, req: httptest.NewRequest("GET", "/api/hibernation/wake/shop", nil), token: "s3cret", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.token != "" {
+				tt.req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			tt.handler(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestApprovalHandlers(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "list method not allowed", handler: server.HandleApprovals, req: createAuthenticatedRequest("POST", "/api/approvals", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "list unauthenticated", handler: server.HandleApprovals, req: httptest.NewRequest("GET", "/api/approvals", nil), wantStatus: http.StatusUnauthorized},
+		{name: "list without database", handler: server.HandleApprovals, req: createAuthenticatedRequest("GET", "/api/approvals", ""), wantStatus: http.StatusServiceUnavailable},
+		{name: "detail unauthenticated", handler: server.HandleApprovalDetail, req: httptest.NewRequest("GET", "/api/approvals/7", nil), wantStatus: http.StatusUnauthorized},
+		{name: "detail without database", handler: server.HandleApprovalDetail, req: createAuthenticatedRequest("GET", "/api/approvals/7", ""), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestMaintenanceHandlers(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "windows without database", handler: server.HandleMaintenanceWindows, req: createAuthenticatedRequest("GET", "/api/maintenance-windows", ""), wantStatus: http.StatusServiceUnavailable},
+		{name: "upcoming method not allowed", handler: server.HandleUpcomingOperations, req: createAuthenticatedRequest("POST", "/api/operations/upcoming", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "upcoming unauthenticated", handler: server.HandleUpcomingOperations, req: httptest.NewRequest("GET", "/api/operations/upcoming", nil), wantStatus: http.StatusUnauthorized},
+		{name: "upcoming without database", handler: server.HandleUpcomingOperations, req: createAuthenticatedRequest("GET", "/api/operations/upcoming", ""), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestApprovalPage(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "method not allowed", req: createAuthenticatedRequest("DELETE", "/approvals/7", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "unauthenticated", req: httptest.NewRequest("GET", "/approvals/7?decision=approve", nil), wantStatus: http.StatusUnauthorized},
+		{name: "without database", req: createAuthenticatedRequest("GET", "/approvals/7?decision=approve", ""), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.HandleApprovalPage(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	// Stored values are escaped and only pending approvals get the form
+	comment := "<script>alert(1)</script>"
+	page := approvalPage{
+		Approval: &database.WorkflowApproval{ID: 7, ApplicationName: "shop", StepName: "terraform-apply", Status: database.ApprovalStatusRejected, Comment: &comment},
+		Decision: "approve",
+		Username: "testuser",
+	}
+	var buf bytes.Buffer
+	require.NoError(t, approvalPageTemplate.Execute(&buf, page))
+	assert.Contains(t, buf.String(), "&lt;script&gt;")
+	assert.NotContains(t, buf.String(), "<form")
+
+	page.Pending = true
+	buf.Reset()
+	require.NoError(t, approvalPageTemplate.Execute(&buf, page))
+	assert.Contains(t, buf.String(), `<form method="post" action="/approvals/7">`)
+	assert.Contains(t, buf.String(), `class="approve chosen"`)
+}
+
+func TestProviderPinHandlers(t *testing.T) {
+	server := NewServer()
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "application pins method not allowed", handler: server.HandleApplicationDetail, req: createAuthenticatedRequest("POST", "/api/applications/shop/provider-pins", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "application pins unauthenticated", handler: server.HandleApplicationDetail, req: httptest.NewRequest("GET", "/api/applications/shop/provider-pins", nil), wantStatus: http.StatusUnauthorized},
+		{name: "application pins without database", handler: server.HandleApplicationDetail, req: createAuthenticatedRequest("PUT", "/api/applications/shop/provider-pins", `{"provider":"database-team","version":"1.0.0"}`), wantStatus: http.StatusServiceUnavailable},
+		{name: "team pins of another team", handler: server.HandleTeamProviderPins, req: createAuthenticatedRequest("GET", "/api/teams/payments/provider-pins", ""), wantStatus: http.StatusForbidden},
+		{name: "team pins without database", handler: server.HandleTeamProviderPins, req: createAuthenticatedRequest("GET", "/api/teams/engineering/provider-pins", ""), wantStatus: http.StatusServiceUnavailable},
+		{name: "upgrade report method not allowed", handler: server.HandleProviderUpgradeReport, req: createAuthenticatedRequest("POST", "/api/providers/upgrade-report", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "upgrade report without database", handler: server.HandleProviderUpgradeReport, req: createAuthenticatedRequest("GET", "/api/providers/upgrade-report", ""), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestProviderUpgradeReport(t *testing.T) {
+	version := func(v string, resourceTypes ...string) *sdk.Provider {
+		return &sdk.Provider{
+			Metadata:     sdk.ProviderMetadata{Name: "database-team", Version: v},
+			Capabilities: sdk.ProviderCapabilities{ResourceTypes: resourceTypes},
+			Dir:          t.TempDir(),
+		}
+	}
+	registry := providers.NewRegistry()
+	require.NoError(t, registry.RegisterProvider(version("2.0.0", "postgres", "mysql")))
+	require.NoError(t, registry.RetainProviderVersion(version("1.0.0", "postgres")))
+
+	pins := []*database.ProviderVersionPin{
+		{Scope: database.ProviderPinScopeTeam, ScopeName: "engineering", Provider: "database-team", Version: "1.0.0"},
+		{Scope: database.ProviderPinScopeApplication, ScopeName: "billing", Provider: "database-team", Version: "2.0.0"},
+		{Scope: database.ProviderPinScopeApplication, ScopeName: "legacy", Provider: "database-team", Version: "0.9.0"},
+		{Scope: database.ProviderPinScopeApplication, ScopeName: "legacy", Provider: "removed-team", Version: "1.0.0"},
+	}
+	apps := []*database.Application{
+		{Name: "shop", Team: "engineering"},
+		{Name: "billing", Team: "engineering"},
+		{Name: "cart", Team: "engineering"},
+		{Name: "legacy", Team: "payments"},
+	}
+
+	entries := providerUpgradeReport(registry, pins, apps)
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, "billing", entries[0].ScopeName)
+	assert.Equal(t, PinStatusCurrent, entries[0].Status)
+	assert.Nil(t, entries[0].Changes)
+
+	assert.Equal(t, "legacy", entries[1].ScopeName)
+	assert.Equal(t, PinStatusUnavailable, entries[1].Status)
+	assert.Contains(t, entries[1].Error, "no version 0.9.0 loaded")
+
+	// The team pin covers the applications of the team without a pin of their own
+	assert.Equal(t, database.ProviderPinScopeTeam, entries[2].Scope)
+	assert.Equal(t, PinStatusBehind, entries[2].Status)
+	assert.Equal(t, "2.0.0", entries[2].CurrentVersion)
+	assert.Equal(t, []string{"cart", "shop"}, entries[2].Applications)
+	require.NotNil(t, entries[2].Changes)
+	assert.Equal(t, []string{"mysql"}, entries[2].Changes.ResourceTypesAdded)
+
+	assert.Equal(t, "removed-team", entries[3].Provider)
+	assert.Equal(t, PinStatusUnavailable, entries[3].Status)
+	assert.Equal(t, []string{"legacy"}, entries[3].Applications)
+}
+
+type fakeTokenReviewer map[string]string
+
+func (f fakeTokenReviewer) Review(ctx context.Context, token string, audiences []string) (string, error) {
+	if username, ok := f[token]; ok {
+		return username, nil
+	}
+	return "", auth.ErrInvalidCredentials
+}
+
+func TestKubernetesTokenExchange(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte(`authentication:
+  workloadIdentity:
+    enabled: true
+    tokenTTL: 10m
+    serviceAccounts:
+      - namespace: ci
+        team: platform
+`), 0600))
+
+	fakeClock := clock.NewFake(time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC))
+	server := NewServer()
+	server.SetClock(fakeClock)
+	server.tokenReviewer = fakeTokenReviewer{
+		"ci-token":     "system:serviceaccount:ci:runner",
+		"system-token": "system:serviceaccount:kube-system:default",
+	}
+
+	exchange := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		server.HandleKubernetesTokenExchange(w, httptest.NewRequest("POST", "/api/kubernetes/token", strings.NewReader(body)))
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadRequest, exchange(`{}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, exchange(`{"token":"forged"}`).Code)
+	assert.Equal(t, http.StatusForbidden, exchange(`{"token":"system-token"}`).Code, "unmapped service account")
+
+	w := exchange(`{"token":"ci-token"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Username    string `json:"username"`
+		Team        string `json:"team"`
+		Role        string `json:"role"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.Equal(t, 600, resp.ExpiresIn)
+	assert.Equal(t, "system:serviceaccount:ci:runner", resp.Username)
+	assert.Equal(t, "platform", resp.Team)
+	assert.Equal(t, "user", resp.Role)
+
+	var seen *users.User
+	protected := server.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		seen = server.getUserFromContext(r)
+	})
+	call := func() int {
+		req := httptest.NewRequest("GET", "/api/applications", nil)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Authorization", "Bearer "+resp.AccessToken)
+		w := httptest.NewRecorder()
+		protected(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, call())
+	require.NotNil(t, seen)
+	assert.Equal(t, "platform", seen.Team)
+
+	// Tokens are not extended on use
+	fakeClock.Advance(10 * time.Minute)
+	assert.Equal(t, http.StatusUnauthorized, call())
+}
+
+func TestKubernetesTokenExchangeDisabled(t *testing.T) {
+	t.Chdir(t.TempDir())
+	server := NewServer()
+
+	w := httptest.NewRecorder()
+	server.HandleKubernetesTokenExchange(w, httptest.NewRequest("POST", "/api/kubernetes/token", strings.NewReader(`{"token":"x"}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSchedulePreview(t *testing.T) {
+	server := NewServer()
+	server.SetClock(clock.NewFake(time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC)))
+
+	w := httptest.NewRecorder()
+	server.HandleScheduleDetail(w, createAuthenticatedRequest("POST", "/api/schedules/preview", `{"cron":"30 2 * * *","timezone":"Europe/Zurich","count":2}`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		NextRuns []time.Time `json:"next_runs"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.NextRuns, 2)
+	// 02:30 does not exist on the spring-forward day; the run happens at 03:00 CEST
+	assert.Equal(t, time.Date(2026, 3, 29, 1, 0, 0, 0, time.UTC), response.NextRuns[0].UTC())
+	assert.Equal(t, time.Date(2026, 3, 30, 0, 30, 0, 0, time.UTC), response.NextRuns[1].UTC())
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "preview without timezone", handler: server.HandleScheduleDetail, req: createAuthenticatedRequest("POST", "/api/schedules/preview", `{"cron":"@daily"}`), wantStatus: http.StatusBadRequest},
+		{name: "preview with invalid cron", handler: server.HandleScheduleDetail, req: createAuthenticatedRequest("POST", "/api/schedules/preview", `{"cron":"daily","timezone":"UTC"}`), wantStatus: http.StatusBadRequest},
+		{name: "preview method not allowed", handler: server.HandleScheduleDetail, req: createAuthenticatedRequest("GET", "/api/schedules/preview", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "list without database", handler: server.HandleSchedules, req: createAuthenticatedRequest("GET", "/api/schedules", ""), wantStatus: http.StatusServiceUnavailable},
+		{name: "detail without database", handler: server.HandleScheduleDetail, req: createAuthenticatedRequest("GET", "/api/schedules/1/next-runs", ""), wantStatus: http.StatusServiceUnavailable},
+		{name: "update without database", handler: server.HandleScheduleDetail, req: createAuthenticatedRequest("PATCH", "/api/schedules/1", `{"enabled":false}`), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestNamingAvailability(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte("naming:\n  pattern: \"{app}.{team}.{env}.example.com\"\n"), 0600))
+	server := NewServer()
+
+	w := httptest.NewRecorder()
+	server.HandleNamingAvailability(w, createAuthenticatedRequest("GET", "/api/naming/availability?app=shop&team=payments", ""))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	hostname, err := server.planApplicationHostname("shop", "payments", &types.ScoreSpec{Environment: &types.Environment{Type: "staging"}}, "alice")
+	require.NoError(t, err)
+	assert.Nil(t, hostname, "no reservation without a database")
+
+	w = httptest.NewRecorder()
+	writeHostnameError(w, fmt.Errorf("%w: needs {env}", errInvalidHostname))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	writeHostnameError(w, fmt.Errorf("%w: 'shop.example.com'", database.ErrHostnameTaken))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestGoldenPathExecutionOverrides(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.Mkdir("workflows", 0750))
+	require.NoError(t, os.WriteFile("workflows/deploy-app.yaml", []byte(`apiVersion: workflow.dev/v1
+kind: Workflow
+metadata:
+  name: deploy-app
+spec:
+  steps:
+    - name: deploy
+      type: kubernetes
+`), 0600))
+	server := NewServer()
+	body := `{"spec": {"apiVersion": "score.dev/v1b1", "metadata": {"name": "shop"}}, "overrides": {"reason": "INC-42", "steps": {"migrate": {"skip": true}}}}`
+
+	w := httptest.NewRecorder()
+	server.HandleGoldenPathExecution(w, createAuthenticatedRequest("POST", "/api/workflows/golden-paths/deploy-app/execute", body))
+	assert.Equal(t, http.StatusForbidden, w.Code, "only admins override steps")
+
+	admin := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/api/workflows/golden-paths/deploy-app/execute", strings.NewReader(body))
+		return req.WithContext(context.WithValue(req.Context(), contextKeyUser, &users.User{Username: "admin", Team: "platform", Role: "admin"}))
+	}
+
+	w = httptest.NewRecorder()
+	server.HandleGoldenPathExecution(w, admin(body))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "step 'migrate' does not exist")
+
+	w = httptest.NewRecorder()
+	server.HandleGoldenPathExecution(w, admin(strings.Replace(body, `"skip": true`, `"skip": true, "force": true`, 1)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown field")
+}
+
+func TestGoldenPathDryRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.Mkdir("workflows", 0750))
+	require.NoError(t, os.WriteFile("goldenpaths.yaml", []byte(`goldenpaths:
+  deploy-app:
+    workflow: ./workflows/deploy-app.yaml
+    approval_environments: [production]
+`), 0600))
+	require.NoError(t, os.WriteFile("workflows/deploy-app.yaml", []byte(`apiVersion: workflow.dev/v1
+kind: Workflow
+metadata:
+  name: deploy-app
+spec:
+  steps:
+    - name: infrastructure
+      type: terraform
+    - name: deploy
+      type: kubernetes
+`), 0600))
+	server := NewServer()
+	body := "apiVersion: score.dev/v1b1\nmetadata:\n  name: shop\n"
+
+	w := httptest.NewRecorder()
+	server.HandleGoldenPathExecution(w, createAuthenticatedRequest("POST", "/api/workflows/golden-paths/deploy-app/execute?dry_run=true&param.environment=production", body))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var preview applicationPreview
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.True(t, preview.DryRun)
+	assert.Equal(t, "shop", preview.Application)
+	assert.Equal(t, "deploy-app", preview.GoldenPath)
+	assert.True(t, preview.ApprovalRequired, "terraform applies in production need approval")
+	require.Len(t, preview.Steps, 2)
+	assert.Equal(t, "infrastructure", preview.Steps[0].Name)
+	assert.Equal(t, "deploy-app", preview.Steps[0].Phase)
+	assert.Greater(t, preview.EstimatedSeconds, 0)
+
+	w = httptest.NewRecorder()
+	server.HandleGoldenPathExecution(w, createAuthenticatedRequest("POST", "/api/workflows/golden-paths/deploy-app/execute?dry_run=true&param.environment=staging", body))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	preview = applicationPreview{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+	assert.False(t, preview.ApprovalRequired)
+}
+
+func TestGoldenPathDeprecation(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile("goldenpaths.yaml", []byte(`goldenpaths:
+  deploy-app:
+    workflow: ./workflows/deploy-app.yaml
+    deprecated:
+      successor: deploy-app-v2
+      sunset: "2026-12-31"
+  deploy-app-v2:
+    workflow: ./workflows/deploy-app-v2.yaml
+`), 0600))
+	server := NewServer()
+
+	w := httptest.NewRecorder()
+	server.HandleGoldenPaths(w, createAuthenticatedRequest("GET", "/api/golden-paths/deploy-app", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response struct {
+		Deprecated *goldenpaths.Deprecation `json:"deprecated"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotNil(t, response.Deprecated)
+	assert.Equal(t, "deploy-app-v2", response.Deprecated.Successor)
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "applications without database", req: createAuthenticatedRequest("GET", "/api/golden-paths/deploy-app/applications", ""), wantStatus: http.StatusServiceUnavailable},
+		{name: "migrations without database", req: createAuthenticatedRequest("GET", "/api/golden-paths/deploy-app/migrations", ""), wantStatus: http.StatusServiceUnavailable},
+		{name: "migrate method not allowed", req: createAuthenticatedRequest("GET", "/api/golden-paths/deploy-app/migrate", ""), wantStatus: http.StatusMethodNotAllowed},
+		{name: "unknown action", req: createAuthenticatedRequest("GET", "/api/golden-paths/deploy-app/owners", ""), wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.HandleGoldenPaths(w, tt.req)
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+		})
+	}
+
+	applications := []*database.GoldenPathApplication{
+		{ApplicationName: "shop", Team: "payments"},
+		{ApplicationName: "checkout", Team: "payments"},
+		{ApplicationName: "search", Team: "discovery"},
+	}
+	selected := selectMigrationApplications(applications, migrateRequest{Team: "payments"})
+	assert.Len(t, selected, 2)
+	selected = selectMigrationApplications(applications, migrateRequest{Applications: []string{"search"}})
+	require.Len(t, selected, 1)
+	assert.Equal(t, "search", selected[0].ApplicationName)
+}
+
+// TestExecuteCommandEnvironment verifies commands of the golden path handlers and sandboxes
+// only see the server variables steps may inherit
+func TestExecuteCommandEnvironment(t *testing.T) {
+	t.Setenv("SERVER_DB_PASSWORD", "server-secret")
+
+	server := NewServer()
+	logBuffer := NewLogBuffer(nil, nil)
+	require.NoError(t, server.executeCommand("env", nil, "", logBuffer))
+
+	logs := logBuffer.GetLogs()
+	assert.Contains(t, logs, "PATH=")
+	assert.NotContains(t, logs, "SERVER_DB_PASSWORD")
+}
+
+func TestExecutePolicyStepEnvironment(t *testing.T) {
+	t.Setenv("SERVER_DB_PASSWORD", "server-secret")
+	t.Chdir(t.TempDir())
+	binDir := t.TempDir()
+	envFile := filepath.Join(t.TempDir(), "opa-env")
+	fakeOPA := "#!/bin/sh\nenv > " + envFile + "\necho '{}'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "opa"), []byte(fakeOPA), 0755)) // #nosec G306 - test executable
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	server := NewServer()
+	logBuffer := NewLogBuffer(nil, nil)
+	step := types.Step{Name: "check", Type: "policy", Config: map[string]interface{}{"policies": "policies"}}
+	require.NoError(t, server.executePolicyStep(step, "shop", "kubernetes", logBuffer))
+
+	env, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(env), "PATH=")
+	assert.NotContains(t, string(env), "SERVER_DB_PASSWORD", "policies cannot read server credentials through opa.runtime()")
+}
@@ -0,0 +1,359 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/database"
+	"innominatus/internal/hibernation"
+	"innominatus/internal/logging"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	errAlreadyHibernated = errors.New("application is already hibernated")
+	errNotHibernated     = errors.New("application is not hibernated")
+)
+
+// hibernationSettings loads the hibernation section and the Prometheus URL from admin-config.yaml
+func (s *Server) hibernationSettings() (hibernation.Config, string) {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return hibernation.Config{}, ""
+	}
+	return adminConfig.Hibernation, adminConfig.Prometheus.URL
+}
+
+func (s *Server) workloadScaler() hibernation.Scaler {
+	if s.hibernationScaler != nil {
+		return s.hibernationScaler
+	}
+	return hibernation.KubectlScaler{}
+}
+
+// hibernateApplication scales the workloads of an application to zero and moves its active
+// resources to the hibernated state
+func (s *Server) hibernateApplication(ctx context.Context, appName, reason, hibernatedBy string) (*database.ApplicationHibernation, error) {
+	existing, err := s.db.GetApplicationHibernation(appName)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errAlreadyHibernated
+	}
+
+	config, _ := s.hibernationSettings()
+	record := &database.ApplicationHibernation{
+		ApplicationName: appName,
+		Reason:          reason,
+		HibernatedBy:    hibernatedBy,
+		Namespace:       config.NamespaceOf(appName),
+		ResourceIDs:     []int64{},
+	}
+
+	record.Replicas, err = s.workloadScaler().ScaleToZero(ctx, record.Namespace)
+	if err != nil {
+		// Bring back what was scaled down before the failure
+		if restoreErr := s.workloadScaler().Restore(ctx, record.Namespace, record.Replicas); restoreErr != nil {
+			err = fmt.Errorf("%w (restore failed: %v)", err, restoreErr)
+		}
+		return nil, fmt.Errorf("failed to scale workloads to zero: %w", err)
+	}
+
+	if s.resourceManager != nil {
+		instances, err := s.resourceManager.GetResourcesByApplication(appName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resources: %w", err)
+		}
+		for _, instance := range instances {
+			if instance.State != database.ResourceStateActive && instance.State != database.ResourceStateDegraded {
+				continue
+			}
+			if err := s.resourceManager.TransitionResourceState(instance.ID, database.ResourceStateHibernated, reason, hibernatedBy, nil); err != nil {
+				return nil, fmt.Errorf("failed to hibernate resource %s: %w", instance.ResourceName, err)
+			}
+			record.ResourceIDs = append(record.ResourceIDs, instance.ID)
+		}
+	}
+
+	if err := s.db.SaveApplicationHibernation(record); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx, "server").InfoWithFields("Hibernated application", map[string]interface{}{
+		"app_name":  appName,
+		"reason":    reason,
+		"workloads": len(record.Replicas),
+		"resources": len(record.ResourceIDs),
+	})
+	return record, nil
+}
+
+// resumeApplication scales the workloads of a hibernated application back up and
+// reactivates its resources
+func (s *Server) resumeApplication(ctx context.Context, appName, reason, resumedBy string) (*database.ApplicationHibernation, error) {
+	record, err := s.db.GetApplicationHibernation(appName)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, errNotHibernated
+	}
+
+	if err := s.workloadScaler().Restore(ctx, record.Namespace, record.Replicas); err != nil {
+		return nil, fmt.Errorf("failed to restore workloads: %w", err)
+	}
+
+	if s.resourceManager != nil {
+		for _, id := range record.ResourceIDs {
+			err := s.resourceManager.TransitionResourceState(id, database.ResourceStateActive, reason, resumedBy, nil)
+			if err != nil && !strings.Contains(err.Error(), "invalid state transition") {
+				return nil, fmt.Errorf("failed to resume resource %d: %w", id, err)
+			}
+		}
+	}
+
+	if err := s.db.DeleteApplicationHibernation(appName); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx, "server").InfoWithFields("Resumed application", map[string]interface{}{
+		"app_name": appName,
+		"reason":   reason,
+	})
+	return record, nil
+}
+
+// handleApplicationHibernation handles /api/applications/{name}/hibernate: GET returns the
+// hibernation of the application, POST hibernates it
+func (s *Server) handleApplicationHibernation(w http.ResponseWriter, r *http.Request, appName string) {
+	if r.Method != "GET" && r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorizeHibernation(w, r, appName) {
+		return
+	}
+
+	if r.Method == "GET" {
+		record, err := s.db.GetApplicationHibernation(appName)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get hibernation: %v", err), http.StatusInternalServerError)
+			return
+		}
+		writeHibernationResponse(w, appName, record)
+		return
+	}
+
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	if body.Reason == "" {
+		body.Reason = "hibernated manually"
+	}
+
+	user := s.getUserFromContext(r)
+	record, err := s.hibernateApplication(r.Context(), appName, body.Reason, user.Username)
+	if errors.Is(err, errAlreadyHibernated) {
+		http.Error(w, fmt.Sprintf("Application '%s' is already hibernated", appName), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to hibernate application: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeHibernationResponse(w, appName, record)
+}
+
+// handleApplicationResume handles POST /api/applications/{name}/resume
+func (s *Server) handleApplicationResume(w http.ResponseWriter, r *http.Request, appName string) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorizeHibernation(w, r, appName) {
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if _, err := s.resumeApplication(r.Context(), appName, "resumed manually", user.Username); err != nil {
+		if errors.Is(err, errNotHibernated) {
+			http.Error(w, fmt.Sprintf("Application '%s' is not hibernated", appName), http.StatusConflict)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to resume application: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeHibernationResponse(w, appName, nil)
+}
+
+// authorizeHibernation checks the user may manage the application; it writes the error response
+func (s *Server) authorizeHibernation(w http.ResponseWriter, r *http.Request, appName string) bool {
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	if s.db == nil {
+		http.Error(w, "Hibernation requires a database", http.StatusServiceUnavailable)
+		return false
+	}
+	app, err := s.db.GetApplication(appName)
+	if err != nil || app == nil {
+		http.Error(w, "Application not found", http.StatusNotFound)
+		return false
+	}
+	if !s.canAccessTeam(user, app.Team) {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// HandleHibernationWake handles POST /api/hibernation/wake/{name}, the inbound webhook that
+// resumes a hibernated application, e.g. from an ingress default backend or a git push hook.
+// It authenticates with the bearer token from hibernation.webhookTokenEnv.
+func (s *Server) HandleHibernationWake(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	config, _ := s.hibernationSettings()
+	if !config.AuthorizeWebhook(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.db == nil {
+		http.Error(w, "Hibernation requires a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	appName := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/hibernation/wake/"), "/")
+	if appName == "" {
+		http.Error(w, "Application name is required", http.StatusBadRequest)
+		return
+	}
+
+	_, err := s.resumeApplication(r.Context(), appName, "resumed by wake webhook", "webhook")
+	if err != nil && !errors.Is(err, errNotHibernated) {
+		http.Error(w, fmt.Sprintf("Failed to resume application: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeHibernationResponse(w, appName, nil)
+}
+
+func writeHibernationResponse(w http.ResponseWriter, appName string, record *database.ApplicationHibernation) {
+	response := map[string]interface{}{
+		"application": appName,
+		"hibernated":  record != nil,
+	}
+	if record != nil {
+		response["hibernation"] = record
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// StartHibernationScheduler periodically hibernates idle applications when
+// hibernation.enabled is set in admin-config.yaml
+func (s *Server) StartHibernationScheduler(ctx context.Context) {
+	logger := logging.NewStructuredLogger("server")
+	config, prometheusURL := s.hibernationSettings()
+	if s.db == nil || !config.Enabled {
+		return
+	}
+	idleAfter, interval, err := config.Durations()
+	if err != nil {
+		logger.Warnf("Hibernation disabled: %v", err)
+		return
+	}
+	if prometheusURL == "" {
+		logger.Warn("Hibernation disabled: prometheus.url is not configured")
+		return
+	}
+
+	go func() {
+		ticker := s.Clock().NewTicker("hibernation-scheduler", interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				s.hibernateIdleApplications(ctx, idleAfter)
+			}
+		}
+	}()
+}
+
+// hibernateIdleApplications hibernates every eligible application without traffic and
+// platform activity for idleAfter
+func (s *Server) hibernateIdleApplications(ctx context.Context, idleAfter time.Duration) {
+	logger := logging.NewStructuredLogger("server")
+	config, prometheusURL := s.hibernationSettings()
+
+	apps, err := s.db.ListApplications()
+	if err != nil {
+		logger.Warnf("Failed to list applications for hibernation: %v", err)
+		return
+	}
+	hibernations, err := s.db.ListApplicationHibernations()
+	if err != nil {
+		logger.Warnf("Failed to list hibernated applications: %v", err)
+		return
+	}
+	hibernated := make(map[string]bool, len(hibernations))
+	for _, h := range hibernations {
+		hibernated[h.ApplicationName] = true
+	}
+
+	now := s.Clock().Now()
+	for _, app := range apps {
+		if hibernated[app.Name] || app.ScoreSpec == nil || !config.Eligible(app.ScoreSpec.Metadata.Labels) {
+			continue
+		}
+		lastActivity := s.lastApplicationActivity(app)
+		if now.Sub(lastActivity) < idleAfter {
+			continue
+		}
+
+		traffic, err := hibernation.QueryActivity(ctx, prometheusURL, config.Query(app.Name, idleAfter))
+		if err != nil {
+			logger.Warnf("Failed to query activity of '%s': %v", app.Name, err)
+			continue
+		}
+		if !hibernation.IsIdle(traffic, lastActivity, now, idleAfter) {
+			continue
+		}
+
+		reason := fmt.Sprintf("no traffic for %s", idleAfter)
+		if _, err := s.hibernateApplication(ctx, app.Name, reason, "hibernation-scheduler"); err != nil {
+			logger.Warnf("Failed to hibernate '%s': %v", app.Name, err)
+		}
+	}
+}
+
+// lastApplicationActivity returns when an application was last deployed or ran a workflow
+func (s *Server) lastApplicationActivity(app *database.Application) time.Time {
+	last := app.UpdatedAt
+	if s.workflowRepo != nil {
+		executions, err := s.workflowRepo.ListWorkflowExecutions(app.Name, "", "", 1, 0)
+		if err == nil && len(executions) > 0 && executions[0].StartedAt.After(last) {
+			last = executions[0].StartedAt
+		}
+	}
+	return last
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"innominatus/internal/admin"
+	"innominatus/internal/ai"
+	"innominatus/internal/auth"
+	"innominatus/internal/health"
+)
+
+// integration is an external system the server depends on, checked in /health
+type integration struct {
+	name    string
+	url     string
+	healthy func(statusCode int) bool // nil expects a 2xx response
+}
+
+// integrationNames are the names used in /health and in healthChecks.required and disabled
+var integrationNames = []string{"gitea", "argocd", "vault", "oidc", "object_storage", "ai_provider"}
+
+// RegisterIntegrationChecks adds a /health check for every integration configured in
+// admin-config.yaml. Integrations are optional unless listed in healthChecks.required,
+// so an outage degrades the server instead of failing liveness probes.
+func (s *Server) RegisterIntegrationChecks(adminCfg *admin.AdminConfig) error {
+	if adminCfg == nil {
+		return nil
+	}
+	cfg := adminCfg.HealthChecks
+	for _, name := range append(slices.Clone(cfg.Required), cfg.Disabled...) {
+		if !slices.Contains(integrationNames, name) {
+			return fmt.Errorf("unknown integration '%s' in healthChecks (known: %s)", name, strings.Join(integrationNames, ", "))
+		}
+	}
+
+	timeout := health.DefaultIntegrationTimeout
+	if cfg.Timeout != "" {
+		parsed, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid healthChecks.timeout '%s'", cfg.Timeout)
+		}
+		timeout = parsed
+	}
+
+	for _, dep := range s.integrations(adminCfg) {
+		if slices.Contains(cfg.Disabled, dep.name) {
+			continue
+		}
+		checker := health.NewHTTPChecker(dep.name, dep.url, slices.Contains(cfg.Required, dep.name), timeout)
+		if dep.healthy != nil {
+			checker.WithHealthyStatus(dep.healthy)
+		}
+		s.healthChecker.Register(checker)
+	}
+	return nil
+}
+
+// integrations returns the configured integrations with the endpoint that reports their health
+func (s *Server) integrations(adminCfg *admin.AdminConfig) []integration {
+	var deps []integration
+	add := func(name, baseURL, path string, healthy func(int) bool) {
+		if baseURL != "" {
+			deps = append(deps, integration{name: name, url: strings.TrimSuffix(baseURL, "/") + path, healthy: healthy})
+		}
+	}
+
+	add("gitea", adminCfg.Gitea.URL, "/api/v1/version", nil)
+	add("argocd", adminCfg.ArgoCD.URL, "/healthz", nil)
+	// Standby nodes answer 429, 472 and 473; sealed or uninitialized vaults 503 and 501
+	add("vault", adminCfg.Vault.URL, "/v1/sys/health", func(code int) bool {
+		return code == 200 || code == 429 || code == 472 || code == 473
+	})
+	add("oidc", s.oidcIssuer, "/.well-known/openid-configuration", nil)
+	add("object_storage", adminCfg.Minio.URL, "/minio/health/live", nil)
+	if s.aiService != nil {
+		// Unauthenticated requests are refused, any answer below 500 means the API is up
+		add("ai_provider", ai.ProviderAPIURL, "/v1/models", func(code int) bool { return code < 500 })
+	}
+	return deps
+}
+
+// oidcIssuer returns the issuer to check, even if the authenticator failed to reach it at startup
+func oidcIssuer(cfg auth.OIDCConfig) string {
+	if !cfg.Enabled {
+		return ""
+	}
+	return cfg.IssuerURL
+}
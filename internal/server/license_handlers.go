@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/licenses"
+	"net/http"
+	"os"
+)
+
+// handleApplicationLicenses reports the licenses of an application version for legal and
+// compliance review: the components of every SBOM build and scan steps recorded for it.
+//
+// GET /api/applications/{name}/licenses?version=1.4.2 (default the latest version)
+func (s *Server) handleApplicationLicenses(w http.ResponseWriter, r *http.Request, appName string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.db == nil {
+		http.Error(w, "License reports require a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	app, err := s.db.GetApplication(appName)
+	if err != nil {
+		http.Error(w, "Application not found", http.StatusNotFound)
+		return
+	}
+	if !user.IsAdmin() && !s.canAccessTeam(user, app.Team) {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+
+	versions, err := s.db.ListDependencyReportVersions(appName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list versions: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	version := r.URL.Query().Get("version")
+	if version == "" && len(versions) > 0 {
+		version = versions[0]
+	}
+
+	records, err := s.db.ListDependencyReports(appName, version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list dependency reports: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if r.URL.Query().Get("version") != "" && len(records) == 0 {
+		http.Error(w, fmt.Sprintf("No dependency reports for version '%s'", version), http.StatusNotFound)
+		return
+	}
+
+	sources := make([]licenses.Source, 0, len(records))
+	for _, record := range records {
+		source := licenses.Source{
+			StepName:   record.StepName,
+			Format:     record.Format,
+			Image:      record.Image,
+			BaseImage:  record.BaseImage,
+			Components: record.Components,
+			RecordedAt: record.CreatedAt,
+		}
+		if record.ExecutionID != nil {
+			source.ExecutionID = *record.ExecutionID
+		}
+		sources = append(sources, source)
+	}
+
+	response := struct {
+		*licenses.Report
+		Versions []string `json:"versions"`
+	}{licenses.Aggregate(appName, version, sources), versions}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
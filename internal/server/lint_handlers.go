@@ -0,0 +1,145 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/logging"
+	"innominatus/internal/scorelint"
+	"innominatus/internal/types"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateResponse is the result of validating a Score spec against the platform's
+// deployment checks and lint profile
+type ValidateResponse struct {
+	Valid       bool              `json:"valid"`
+	Application string            `json:"application"`
+	Errors      []string          `json:"errors"`
+	Warnings    []string          `json:"warnings,omitempty"` // Deprecated resource types that still resolve
+	Lint        *scorelint.Report `json:"lint,omitempty"`
+}
+
+// lintSpec checks a spec against a lint profile from admin-config.yaml, or the default
+// profile when profile is empty. It returns nil when no profile applies.
+func (s *Server) lintSpec(spec *types.ScoreSpec, profile string) (*scorelint.Report, error) {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		if profile != "" {
+			return nil, fmt.Errorf("lint profile '%s' requires scoreLint in the admin config: %w", profile, err)
+		}
+		return nil, nil
+	}
+	return adminConfig.ScoreLint.Lint(spec, profile)
+}
+
+// enforceLintProfile rejects a deployment whose spec violates the default lint profile.
+// Warnings are logged. It returns false when a response was written.
+func (s *Server) enforceLintProfile(w http.ResponseWriter, r *http.Request, spec *types.ScoreSpec) bool {
+	report, err := s.lintSpec(spec, "")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %v", err), http.StatusInternalServerError)
+		return false
+	}
+	if report == nil {
+		return true
+	}
+
+	var problems []string
+	for _, finding := range report.Findings {
+		if finding.Severity == scorelint.SeverityWarning {
+			logging.FromContext(r.Context(), "server").Warnf("Lint warning for %s (%s): %s: %s", spec.Metadata.Name, finding.Rule, finding.Path, finding.Message)
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("%s: %s", finding.Path, finding.Message))
+	}
+	if len(problems) > 0 {
+		http.Error(w, fmt.Sprintf("Error: Score spec violates lint profile '%s': %s", report.Profile, strings.Join(problems, "; ")), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// specProblems runs the checks a deployment applies before any workflow starts
+func (s *Server) specProblems(spec *types.ScoreSpec) []string {
+	problems := []string{}
+	if err := s.validateResourceTypes(spec); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if spec.Metadata.Name == "" {
+		problems = append(problems, "metadata.name is required")
+	} else if err := s.validateDNSLabel(spec.Metadata.Name); err != nil {
+		problems = append(problems, fmt.Sprintf("metadata.name must be a valid DNS label: %v", err))
+	}
+	if len(spec.Containers) == 0 {
+		problems = append(problems, "Score specification must define at least one container")
+	}
+	names := make([]string, 0, len(spec.Containers))
+	for name := range spec.Containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if spec.Containers[name].Image == "" {
+			problems = append(problems, fmt.Sprintf("container '%s' must specify an image", name))
+		}
+	}
+	if err := s.validateUniqueResourceNames(spec); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if err := s.validateApplicationVariables(spec); err != nil {
+		problems = append(problems, err.Error())
+	}
+	return problems
+}
+
+// HandleValidate handles POST /api/validate. The body is a Score spec; it is checked like a
+// deployment and linted with the profile given by ?profile= or the default profile. The
+// response is 200 whether or not the spec is valid, so CI can report every finding.
+func (s *Server) HandleValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	if len(body) == 0 {
+		http.Error(w, "Request body cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	var spec types.ScoreSpec
+	if err := yaml.Unmarshal(body, &spec); err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing YAML: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	report, err := s.lintSpec(&spec, r.URL.Query().Get("profile"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	response := ValidateResponse{
+		Application: spec.Metadata.Name,
+		Errors:      s.specProblems(&spec),
+		Warnings:    s.resourceTypeWarnings(&spec),
+		Lint:        report,
+	}
+	response.Valid = len(response.Errors) == 0 && (report == nil || report.Passed())
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
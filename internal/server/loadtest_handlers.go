@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/loadtest"
+	"innominatus/internal/logging"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// loadTestManager returns the shared load test manager, or nil without a database and queue
+func (s *Server) loadTestManager() *loadtest.Manager {
+	if s.db == nil || s.workflowQueue == nil {
+		return nil
+	}
+	s.loadTestsOnce.Do(func() {
+		s.loadTests = loadtest.NewManager(s.workflowQueue, s.db)
+	})
+	return s.loadTests
+}
+
+// HandleLoadTests starts a synthetic load test (POST) or lists past runs (GET). Admin only.
+func (s *Server) HandleLoadTests(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), "server")
+
+	manager := s.loadTestManager()
+	if manager == nil {
+		http.Error(w, "Load tests require database connection and workflow queue", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		reports := manager.List()
+		response := map[string]interface{}{
+			"runs":  reports,
+			"count": len(reports),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+		}
+
+	case "POST":
+		user := s.getUserFromContext(r)
+		if user == nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var cfg loadtest.Config
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+				http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+				return
+			}
+		}
+
+		report, err := manager.Start(cfg, user.Team, user.Username)
+		if err != nil {
+			status := http.StatusBadRequest
+			if strings.Contains(err.Error(), "still running") {
+				status = http.StatusConflict
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		logger.Infof("Load test %s started by %s: %d application(s) x %d workflow(s), concurrency %d", report.ID, user.Username, report.Config.Applications, report.Config.WorkflowsPerApp, report.Config.Concurrency)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleLoadTestDetail returns the progress or final report of a run (GET /api/admin/loadtest/{id})
+func (s *Server) HandleLoadTestDetail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	manager := s.loadTestManager()
+	if manager == nil {
+		http.Error(w, "Load tests require database connection and workflow queue", http.StatusServiceUnavailable)
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/loadtest/"), "/")
+	report, ok := manager.Get(id)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Load test '%s' not found", id), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
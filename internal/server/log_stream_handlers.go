@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/logging"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// logStreamBuffer is how many log entries a slow client may fall behind before entries are dropped
+const logStreamBuffer = 500
+
+// logStreamKeepalive is how often an idle log stream sends an SSE comment
+const logStreamKeepalive = 30 * time.Second
+
+// parseLogStreamFilter reads ?level= (minimum level, default debug) and ?component=
+// (comma-separated or repeated) into a tail filter
+func parseLogStreamFilter(r *http.Request) (logging.TailFilter, error) {
+	query := r.URL.Query()
+	filter := logging.TailFilter{MinLevel: logging.DEBUG}
+	if level := query.Get("level"); level != "" {
+		parsed, err := logging.ParseLevel(level)
+		if err != nil {
+			return filter, err
+		}
+		filter.MinLevel = parsed
+	}
+	for _, value := range query["component"] {
+		for _, component := range strings.Split(value, ",") {
+			if component = strings.TrimSpace(component); component != "" {
+				filter.Components = append(filter.Components, component)
+			}
+		}
+	}
+	return filter, nil
+}
+
+// HandleLogStream handles GET /api/admin/logs/stream: a live SSE tail of the server's
+// structured logs. Admin only. Filters: level (minimum level) and component. Entries
+// below the process-wide log level are not logged and so never reach the stream; raise
+// it with PUT /api/admin/logging while debugging.
+func (s *Server) HandleLogStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := parseLogStreamFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	entries, dropped, cancel := logging.Subscribe(filter, logStreamBuffer)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	if _, err := fmt.Fprintf(w, "data: {\"type\":\"connected\",\"level\":%q,\"server_level\":%q}\n\n",
+		strings.ToLower(filter.MinLevel.String()), strings.ToLower(logging.CurrentLevel().String())); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(logStreamKeepalive)
+	defer keepalive.Stop()
+
+	var reportedDrops int64
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case entry := <-entries:
+			// Tell the client when it fell behind, so gaps in the tail are visible
+			if lost := dropped(); lost > reportedDrops {
+				if _, err := fmt.Fprintf(w, "event: dropped\ndata: {\"count\":%d}\n\n", lost-reportedDrops); err != nil {
+					return
+				}
+				reportedDrops = lost
+			}
+
+			data, err := json.Marshal(entry)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "failed to encode log entry: %v\n", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
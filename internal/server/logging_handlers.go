@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/logging"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// HandleLogLevel reports (GET) or changes (PUT {"level": "debug"}) the process-wide log
+// level without a restart. Admin only. The change is not persisted; a restart applies
+// LOG_LEVEL or admin-config.yaml again.
+func (s *Server) HandleLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+	case "PUT":
+		var req struct {
+			Level string `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+
+		level, err := logging.ParseLevel(req.Level)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		previous := logging.CurrentLevel()
+		logging.SetLevel(level)
+
+		logging.FromContext(r.Context(), "server").WarnWithFields("Log level changed at runtime", map[string]interface{}{
+			"previous_level": strings.ToLower(previous.String()),
+			"level":          strings.ToLower(level.String()),
+		})
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := map[string]interface{}{
+		"level":  strings.ToLower(logging.CurrentLevel().String()),
+		"format": logging.CurrentFormat(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,312 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/maintenance"
+	"innominatus/internal/users"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maintenanceSchedulerInterval controls how often deferred operations are checked
+const maintenanceSchedulerInterval = time.Minute
+
+// HandleMaintenanceWindows handles listing and creating maintenance windows
+func (s *Server) HandleMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		s.handleListMaintenanceWindows(w, r)
+	case "POST":
+		s.handleCreateMaintenanceWindow(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleMaintenanceWindowDetail handles DELETE /api/maintenance-windows/{id}
+func (s *Server) HandleMaintenanceWindowDetail(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	if r.Method != "DELETE" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/maintenance-windows/")
+	id, err := strconv.ParseInt(strings.Trim(idStr, "/"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid maintenance window ID", http.StatusBadRequest)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	windows, err := s.db.ListMaintenanceWindows("")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load maintenance windows: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var target *maintenance.Window
+	for _, window := range windows {
+		if window.ID == id {
+			target = window
+			break
+		}
+	}
+	if target == nil {
+		http.Error(w, "Maintenance window not found", http.StatusNotFound)
+		return
+	}
+
+	if !s.canManageApplication(user, target.ApplicationName) {
+		http.Error(w, "Forbidden: only the owning team or an admin can manage maintenance windows", http.StatusForbidden)
+		return
+	}
+
+	if err := s.db.DeleteMaintenanceWindow(id); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to delete maintenance window: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleUpcomingOperations returns the deferred operations of the applications the user can
+// manage that wait for a maintenance window
+func (s *Server) HandleUpcomingOperations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	appName := r.URL.Query().Get("app")
+	operations, err := s.db.ListUpcomingOperations(appName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list upcoming operations: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	visible := make([]*database.DeferredOperation, 0, len(operations))
+	for _, op := range operations {
+		if s.canManageApplication(user, op.ApplicationName) {
+			visible = append(visible, op)
+		}
+	}
+
+	response := map[string]interface{}{
+		"operations": visible,
+		"count":      len(visible),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+func (s *Server) handleListMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	appName := r.URL.Query().Get("app")
+
+	windows, err := s.db.ListMaintenanceWindows(appName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list maintenance windows: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	now := s.Clock().Now()
+	items := make([]map[string]interface{}, 0, len(windows))
+	for _, window := range windows {
+		if !s.canManageApplication(user, window.ApplicationName) {
+			continue
+		}
+		item := map[string]interface{}{
+			"window":  window,
+			"is_open": window.IsOpen(now),
+		}
+		if next, ok := window.NextOpening(now); ok {
+			item["next_opening"] = next
+		}
+		items = append(items, item)
+	}
+
+	response := map[string]interface{}{
+		"windows": items,
+		"count":   len(items),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+func (s *Server) handleCreateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var window maintenance.Window
+	if err := json.NewDecoder(r.Body).Decode(&window); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if window.Name == "" {
+		window.Name = "default"
+	}
+	if err := window.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid maintenance window: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if !s.canManageApplication(user, window.ApplicationName) {
+		http.Error(w, "Forbidden: only the owning team or an admin can manage maintenance windows", http.StatusForbidden)
+		return
+	}
+
+	window.CreatedBy = user.Username
+	if err := s.db.CreateMaintenanceWindow(&window); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create maintenance window: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(window); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// canManageApplication returns true if the user may manage the application's team, see
+// canAccessTeam
+func (s *Server) canManageApplication(user *users.User, appName string) bool {
+	if user.IsAdmin() {
+		return true
+	}
+	if s.db == nil {
+		return false
+	}
+	app, err := s.db.GetApplication(appName)
+	if err != nil || app == nil {
+		return false
+	}
+	return s.canAccessTeam(user, app.Team)
+}
+
+// operationForTransition maps a requested state transition to a maintenance operation name
+func operationForTransition(operation string, newState database.ResourceLifecycleState) string {
+	if operation != "" {
+		return strings.ToLower(operation)
+	}
+	switch newState {
+	case database.ResourceStateScaling:
+		return maintenance.OperationResize
+	case database.ResourceStateUpdating:
+		return maintenance.OperationUpgrade
+	default:
+		return ""
+	}
+}
+
+// evaluateMaintenanceWindow checks whether an operation on an application may run now
+func (s *Server) evaluateMaintenanceWindow(appName, operation string) (maintenance.Decision, error) {
+	if s.db == nil || !maintenance.IsDisruptive(operation) {
+		return maintenance.Decision{Allowed: true}, nil
+	}
+
+	windows, err := s.db.ListMaintenanceWindows(appName)
+	if err != nil {
+		return maintenance.Decision{}, err
+	}
+
+	return maintenance.Evaluate(windows, operation, s.Clock().Now()), nil
+}
+
+// StartMaintenanceScheduler periodically executes deferred operations whose window has opened
+func (s *Server) StartMaintenanceScheduler(ctx context.Context) {
+	if s.db == nil || s.resourceManager == nil {
+		return
+	}
+
+	go func() {
+		ticker := s.Clock().NewTicker("maintenance-scheduler", maintenanceSchedulerInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				s.runDueOperations()
+			}
+		}
+	}()
+}
+
+// runDueOperations applies all pending deferred operations that are due
+func (s *Server) runDueOperations() {
+	logger := logging.NewStructuredLogger("server")
+
+	operations, err := s.db.ListDueOperations(s.Clock().Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load due operations: %v\n", err)
+		return
+	}
+
+	for _, op := range operations {
+		reason := fmt.Sprintf("%s (deferred to maintenance window)", op.Reason)
+		err := s.resourceManager.TransitionResourceState(op.ResourceID, database.ResourceLifecycleState(op.TargetState),
+			reason, op.RequestedBy, map[string]interface{}{
+				"deferred_operation_id": op.ID,
+				"operation":             op.Operation,
+			})
+		if err != nil {
+			errMsg := err.Error()
+			_ = s.db.UpdateDeferredOperationStatus(op.ID, database.DeferredStatusFailed, &errMsg, s.Clock().Now())
+			logger.Warnf("Deferred operation %d (%s) failed: %v", op.ID, op.Operation, err)
+			continue
+		}
+
+		_ = s.db.UpdateDeferredOperationStatus(op.ID, database.DeferredStatusExecuted, nil, s.Clock().Now())
+		logger.Infof("Executed deferred operation %d (%s) for %s", op.ID, op.Operation, op.ApplicationName)
+	}
+}
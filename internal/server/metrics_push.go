@@ -0,0 +1,29 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/health"
+	"innominatus/internal/metrics"
+	"time"
+)
+
+// SetMetricsPusher reports the Pushgateway push outcome in /health as the metrics_push check
+func (s *Server) SetMetricsPusher(pusher *metrics.MetricsPusher) {
+	s.metricsPusher = pusher
+	s.healthChecker.Register(health.NewFuncChecker("metrics_push", s.checkMetricsPush))
+}
+
+// checkMetricsPush is degraded while pushes fail. Metrics are not needed to serve requests,
+// so failing pushes never make the server unhealthy.
+func (s *Server) checkMetricsPush(ctx context.Context) (health.Status, string) {
+	status := s.metricsPusher.Status()
+	switch {
+	case status.LastAttemptAt.IsZero():
+		return health.StatusHealthy, "No push attempted yet"
+	case status.ConsecutiveFailures > 0:
+		return health.StatusDegraded, fmt.Sprintf("%d consecutive pushes failed: %s", status.ConsecutiveFailures, status.LastError)
+	default:
+		return health.StatusHealthy, fmt.Sprintf("Last push at %s", status.LastSuccessAt.Format(time.RFC3339))
+	}
+}
@@ -24,19 +24,13 @@ const (
 	contextKeyTeamFilter contextKey = "team_filter"
 )
 
-// CorsMiddleware adds CORS headers to allow cross-origin requests from the frontend
+// CorsMiddleware adds CORS headers to allow cross-origin requests from the frontend.
+// Allowed origins come from server.cors in admin-config.yaml.
 func (s *Server) CorsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// SECURITY: Explicit origin whitelist - never use wildcard with credentials
 		origin := r.Header.Get("Origin")
-		allowedOrigins := map[string]bool{
-			"http://localhost:3000":           true, // Next.js dev server
-			"http://localhost:3001":           true, // Alternative dev port
-			"http://localhost:8081":           true, // Same-origin
-			"http://innominatus.localtest.me": true, // Demo environment
-		}
-
-		if allowedOrigins[origin] {
+		if s.corsOrigins()[origin] {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 		}
@@ -171,8 +165,12 @@ func (s *Server) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		// Extend session on activity
 		s.sessionManager.ExtendSession(session.ID)
 
-		// Add user to request context
+		// Add user to request context, and to the request-scoped log fields
 		ctx := context.WithValue(r.Context(), contextKeyUser, session.User)
+		if session.User != nil {
+			ctx = logging.WithUserID(ctx, session.User.Username)
+			ctx = logging.WithTeam(ctx, session.User.Team)
+		}
 		r = r.WithContext(ctx)
 
 		next(w, r)
@@ -268,6 +266,11 @@ func (s *Server) getSessionFromRequestWithToken(r *http.Request) (*auth.Session,
 				return session, true
 			}
 
+			// Then try tokens issued to Kubernetes service accounts
+			if user, expiresAt, ok := s.authenticateWithWorkloadToken(token); ok {
+				return &auth.Session{ID: token, User: user, CreatedAt: time.Now(), ExpiresAt: expiresAt}, true
+			}
+
 			// Then try API key authentication
 			if user, err := s.authenticateWithAPIKey(token); err == nil {
 				// Create a temporary session for the API key user
@@ -291,6 +294,11 @@ func (s *Server) getSessionFromRequestWithToken(r *http.Request) (*auth.Session,
 			return session, true
 		}
 
+		// Then try tokens issued to Kubernetes service accounts
+		if user, expiresAt, ok := s.authenticateWithWorkloadToken(queryToken); ok {
+			return &auth.Session{ID: queryToken, User: user, CreatedAt: time.Now(), ExpiresAt: expiresAt}, true
+		}
+
 		// Then try API key authentication
 		if user, err := s.authenticateWithAPIKey(queryToken); err == nil {
 			// Create a temporary session for the API key user
@@ -314,7 +322,7 @@ func (s *Server) authenticateWithAPIKey(apiKey string) (*users.User, error) {
 	store, err := users.LoadUsers()
 	if err == nil {
 		if user, err := store.AuthenticateWithAPIKey(apiKey); err == nil {
-			return user, nil
+			return s.applyDirectory(user)
 		}
 	}
 
@@ -327,11 +335,11 @@ func (s *Server) authenticateWithAPIKey(apiKey string) (*users.User, error) {
 			_ = s.db.UpdateAPIKeyLastUsed(keyHash)
 
 			// Return user object (OIDC user from database)
-			return &users.User{
+			return s.applyDirectory(&users.User{
 				Username: username,
 				Team:     team,
 				Role:     role,
-			}, nil
+			})
 		}
 	}
 
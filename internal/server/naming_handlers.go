@@ -0,0 +1,183 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/database"
+	"innominatus/internal/naming"
+	"innominatus/internal/types"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// errInvalidHostname marks hostnames the naming convention cannot generate for a spec
+var errInvalidHostname = errors.New("naming convention")
+
+// namingSettings loads the naming section from admin-config.yaml
+func (s *Server) namingSettings() naming.Config {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return naming.Config{}
+	}
+	return adminConfig.Naming
+}
+
+// clusterForEnvironment returns the cluster applications of an environment run on: the
+// first ready cluster registered for it, or "" when there is none
+func (s *Server) clusterForEnvironment(environment string) string {
+	if s.db == nil || environment == "" {
+		return ""
+	}
+	clusters, err := s.db.ListClusters(environment)
+	if err != nil {
+		return ""
+	}
+	for _, cluster := range clusters {
+		if cluster.Status == database.ClusterStatusReady {
+			return cluster.Name
+		}
+	}
+	return ""
+}
+
+// planApplicationHostname generates the hostname of an application from the naming
+// convention and checks that no other application holds it. It returns nil when no
+// naming convention is configured. The returned reservation is stored with
+// db.ReserveHostname once the application is.
+func (s *Server) planApplicationHostname(appName, team string, spec *types.ScoreSpec, reservedBy string) (*database.HostnameReservation, error) {
+	config := s.namingSettings()
+	if s.db == nil || !config.Enabled() {
+		return nil, nil
+	}
+
+	values := naming.Values{App: appName, Team: team}
+	if spec != nil && spec.Environment != nil {
+		values.Env = spec.Environment.Type
+	}
+	values.Cluster = s.clusterForEnvironment(values.Env)
+
+	hostname, err := config.Hostname(values)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidHostname, err)
+	}
+
+	existing, err := s.db.GetHostnameReservation(hostname)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && (existing.ApplicationName != appName || existing.Environment != values.Env) {
+		return nil, fmt.Errorf("%w: '%s' belongs to application '%s' in '%s'",
+			database.ErrHostnameTaken, hostname, existing.ApplicationName, existing.Environment)
+	}
+
+	return &database.HostnameReservation{
+		Hostname:        hostname,
+		URL:             config.URL(hostname),
+		ApplicationName: appName,
+		Environment:     values.Env,
+		Cluster:         values.Cluster,
+		ReservedBy:      reservedBy,
+	}, nil
+}
+
+// applicationHostname returns the hostname reserved for the environment of an
+// application's stored spec, or nil
+func (s *Server) applicationHostname(appName string) *database.HostnameReservation {
+	if s.db == nil {
+		return nil
+	}
+	app, err := s.db.GetApplication(appName)
+	if err != nil || app == nil || app.ScoreSpec == nil || app.ScoreSpec.Environment == nil {
+		return nil
+	}
+	reservations, err := s.db.ListHostnameReservations(appName)
+	if err != nil {
+		return nil
+	}
+	for _, reservation := range reservations {
+		if reservation.Environment == app.ScoreSpec.Environment.Type {
+			return reservation
+		}
+	}
+	return nil
+}
+
+// HandleNamingAvailability handles GET /api/naming/availability. With ?hostname= it checks
+// that hostname; with ?app=, ?team=, ?env= and optionally ?cluster= it first generates the
+// hostname from the naming convention. A hostname held by the given app in env is available to it.
+func (s *Server) HandleNamingAvailability(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	config := s.namingSettings()
+	query := r.URL.Query()
+	values := naming.Values{
+		App:     query.Get("app"),
+		Team:    query.Get("team"),
+		Env:     query.Get("env"),
+		Cluster: query.Get("cluster"),
+	}
+
+	hostname := strings.ToLower(strings.TrimSuffix(query.Get("hostname"), "."))
+	if hostname == "" {
+		if !config.Enabled() {
+			http.Error(w, "No naming convention is configured (naming.pattern in admin-config.yaml)", http.StatusNotFound)
+			return
+		}
+		if values.Cluster == "" {
+			values.Cluster = s.clusterForEnvironment(values.Env)
+		}
+		var err error
+		if hostname, err = config.Hostname(values); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else if err := naming.ValidateHostname(hostname); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reservation, err := s.db.GetHostnameReservation(hostname)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to check hostname: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"hostname":  hostname,
+		"url":       config.URL(hostname),
+		"available": reservation == nil || (reservation.ApplicationName == values.App && reservation.Environment == values.Env),
+	}
+	if reservation != nil {
+		response["reserved_by"] = map[string]string{
+			"application": reservation.ApplicationName,
+			"environment": reservation.Environment,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// writeHostnameError reports a hostname that cannot be generated, is taken or could not be checked
+func writeHostnameError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, database.ErrHostnameTaken):
+		http.Error(w, fmt.Sprintf("Error: %v", err), http.StatusConflict)
+	case errors.Is(err, errInvalidHostname):
+		http.Error(w, fmt.Sprintf("Error: %v", err), http.StatusBadRequest)
+	default:
+		http.Error(w, fmt.Sprintf("Error reserving hostname: %v", err), http.StatusInternalServerError)
+	}
+}
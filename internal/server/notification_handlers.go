@@ -0,0 +1,148 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/events"
+	"innominatus/internal/logging"
+	"innominatus/internal/notifications"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// notificationConfig returns the notifications section of admin-config.yaml, as of the
+// last reload once the config was applied
+func (s *Server) notificationConfig() notifications.Config {
+	if settings := s.runtime.Load(); settings != nil {
+		return settings.notifications
+	}
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return notifications.Config{}
+	}
+	return adminConfig.Notifications
+}
+
+// applicationTeam returns the team owning an application, or "" if it is unknown
+func (s *Server) applicationTeam(appName string) string {
+	if s.db == nil || appName == "" {
+		return ""
+	}
+	app, err := s.db.GetApplication(appName)
+	if err != nil {
+		return ""
+	}
+	return app.Team
+}
+
+// StartNotifier sends notifications for the workflow and resource events on bus
+func (s *Server) StartNotifier(bus events.EventBus) {
+	notifications.NewNotifier(s.notificationConfig, s.applicationTeam).Start(bus)
+}
+
+// notificationPreviewRequest selects the notification to render with sample data
+type notificationPreviewRequest struct {
+	Type     string                  `json:"type"`
+	Team     string                  `json:"team"`
+	App      string                  `json:"app"`      // Default sample-app
+	Template *notifications.Template `json:"template"` // Unsaved template to try; empty fields use the configured ones
+	Data     map[string]interface{}  `json:"data"`     // Replaces values of the sample event data
+}
+
+// HandleNotifications shows, previews and tests notification templates (admin only).
+//
+// GET  /api/admin/notifications/templates?team=   effective template of every type
+// POST /api/admin/notifications/preview           render a template with sample data
+// POST /api/admin/notifications/test              render and send to the team's webhook
+func (s *Server) HandleNotifications(w http.ResponseWriter, r *http.Request) {
+	action := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/notifications"), "/")
+	config := s.notificationConfig()
+
+	switch {
+	case action == "templates" && r.Method == "GET":
+		team := r.URL.Query().Get("team")
+		templates := make(map[string]notifications.Template, len(notifications.Types))
+		for _, t := range notifications.Types {
+			templates[string(t)] = config.Template(team, t)
+		}
+		writeNotificationsJSON(w, http.StatusOK, map[string]interface{}{
+			"team":      team,
+			"templates": templates,
+			"channel":   config.Channel(team).WebhookURL != "",
+		})
+
+	case (action == "preview" || action == "test") && r.Method == "POST":
+		var req notificationPreviewRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		if !notifications.IsType(req.Type) {
+			http.Error(w, fmt.Sprintf("Unknown notification type '%s'", req.Type), http.StatusBadRequest)
+			return
+		}
+		if req.App == "" {
+			req.App = "sample-app"
+		}
+
+		notificationType := events.EventType(req.Type)
+		tpl := config.Template(req.Team, notificationType)
+		if req.Template != nil {
+			if req.Template.Subject != "" {
+				tpl.Subject = req.Template.Subject
+			}
+			if req.Template.Body != "" {
+				tpl.Body = req.Template.Body
+			}
+		}
+		event := notifications.SampleEvent(notificationType, req.App)
+		for k, v := range req.Data {
+			event.Data[k] = v
+		}
+
+		message, err := notifications.Render(tpl, notifications.NewContext(event, req.Team))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if action == "test" {
+			channel := config.Channel(req.Team)
+			if channel.WebhookURL == "" {
+				http.Error(w, "No notification webhook configured for this team (notifications.webhookURL in admin-config.yaml)", http.StatusConflict)
+				return
+			}
+			if err := notifications.Send(r.Context(), channel, message); err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			logging.FromContext(r.Context(), "server").InfoWithFields("Sent test notification", map[string]interface{}{
+				"type": req.Type,
+				"team": req.Team,
+			})
+		}
+
+		writeNotificationsJSON(w, http.StatusOK, map[string]interface{}{
+			"template": tpl,
+			"message":  message,
+			"sent":     action == "test",
+		})
+
+	case action == "templates" || action == "preview" || action == "test":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// writeNotificationsJSON writes body as JSON with status
+func writeNotificationsJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/observability"
+	"net/http"
+	"os"
+)
+
+// HandleObservabilityBundle serves Prometheus rules and Grafana dashboards for the
+// server's metrics.
+//
+// GET /api/admin/observability/bundle                             rules and dashboards as JSON
+// GET /api/admin/observability/bundle?file=prometheus-rules.yaml  one file, ready to import
+//
+// job limits all queries to the Prometheus job that scrapes the server.
+func (s *Server) HandleObservabilityBundle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bundle, err := observability.Generate(observability.Options{Job: r.URL.Query().Get("job")})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file := r.URL.Query().Get("file")
+	if file == "" {
+		writeObservabilityJSON(w, bundle)
+		return
+	}
+	if file == observability.RulesFile {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file))
+		_, _ = w.Write([]byte(bundle.PrometheusRules))
+		return
+	}
+	dashboard, ok := bundle.Dashboards[file]
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown file '%s'", file), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file))
+	writeObservabilityJSON(w, dashboard)
+}
+
+// writeObservabilityJSON writes body as indented JSON, so downloaded files stay readable
+func writeObservabilityJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/orgs"
+	"innominatus/internal/types"
+	"innominatus/internal/users"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+)
+
+// OrganizationTeam is a team of an organization with its effective settings and usage
+type OrganizationTeam struct {
+	Name         string        `json:"name"`
+	Settings     orgs.Settings `json:"settings"`
+	Applications int           `json:"applications"`
+	Resources    int           `json:"resources"`
+}
+
+// OrganizationSummary is an organization with its teams and the rollup of their usage
+type OrganizationSummary struct {
+	Name         string             `json:"name"`
+	Description  string             `json:"description,omitempty"`
+	Admins       []string           `json:"admins"`
+	Teams        []OrganizationTeam `json:"teams"`
+	Applications int                `json:"applications"`
+	Resources    int                `json:"resources"`
+}
+
+// organizations loads the organization hierarchy from admin-config.yaml. Without an admin
+// config, or with an invalid hierarchy, no team belongs to an organization.
+func (s *Server) organizations() *orgs.Hierarchy {
+	var config orgs.Config
+	if adminConfig, err := admin.LoadAdminConfig("admin-config.yaml"); err == nil {
+		config = adminConfig.Organizations
+	}
+	hierarchy, err := orgs.New(config)
+	if err != nil {
+		logging.NewStructuredLogger("server").Warnf("Ignoring organizations in admin-config.yaml: %v", err)
+		hierarchy, _ = orgs.New(nil)
+	}
+	return hierarchy
+}
+
+// canAccessTeam reports whether a user may manage the applications of a team: admins and
+// members of the team, and admins of the team's organization
+func (s *Server) canAccessTeam(user *users.User, team string) bool {
+	if user.IsAdmin() || team == user.Team {
+		return true
+	}
+	return s.organizations().IsOrgAdmin(user.Username, team)
+}
+
+// listVisibleApplications returns the applications a user can see: all for admins,
+// otherwise those of the user's team and of the teams in organizations the user administers
+func (s *Server) listVisibleApplications(user *users.User) ([]*database.Application, error) {
+	if user.IsAdmin() {
+		return s.db.ListApplications()
+	}
+
+	apps, err := s.db.ListApplicationsByTeam(user.Team)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range s.organizations().ManagedTeams(user.Username) {
+		if team == user.Team {
+			continue
+		}
+		teamApps, err := s.db.ListApplicationsByTeam(team)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, teamApps...)
+	}
+	return apps, nil
+}
+
+// checkOrganizationPolicies applies the quotas and policies of a team's organization to a
+// deployment of spec by that team
+func (s *Server) checkOrganizationPolicies(team string, spec *types.ScoreSpec) error {
+	settings := s.organizations().Settings(team)
+	if settings.Organization == "" {
+		return nil
+	}
+
+	environment := ""
+	if spec.Environment != nil {
+		environment = spec.Environment.Type
+	}
+	resourceTypes := make([]string, 0, len(spec.Resources))
+	for _, resource := range spec.Resources {
+		resourceTypes = append(resourceTypes, resource.Type)
+	}
+	sort.Strings(resourceTypes)
+	if err := settings.CheckSpec(environment, resourceTypes); err != nil {
+		return err
+	}
+
+	if settings.Quotas.MaxApplications == 0 && settings.Quotas.MaxResources == 0 {
+		return nil
+	}
+	apps, err := s.db.ListApplicationsByTeam(team)
+	if err != nil {
+		return fmt.Errorf("failed to check quotas: %w", err)
+	}
+	applications, resources := 1, len(spec.Resources)
+	for _, app := range apps {
+		if app.Name == spec.Metadata.Name {
+			continue // A redeploy replaces the application
+		}
+		applications++
+		if app.ScoreSpec != nil {
+			resources += len(app.ScoreSpec.Resources)
+		}
+	}
+	return settings.CheckQuotas(team, applications, resources)
+}
+
+// organizationRollup sums applications and resources per organization. Applications of
+// teams outside any organization are left out.
+func organizationRollup(hierarchy *orgs.Hierarchy, apps []*database.Application) map[string]map[string]int {
+	rollup := make(map[string]map[string]int)
+	for _, app := range apps {
+		org := hierarchy.OrgOf(app.Team)
+		if org == "" {
+			continue
+		}
+		if rollup[org] == nil {
+			rollup[org] = map[string]int{"applications": 0, "resources": 0}
+		}
+		rollup[org]["applications"]++
+		if app.ScoreSpec != nil {
+			rollup[org]["resources"] += len(app.ScoreSpec.Resources)
+		}
+	}
+	return rollup
+}
+
+// buildOrganizationSummaries lists the named organizations with per-team settings and usage
+func buildOrganizationSummaries(hierarchy *orgs.Hierarchy, names []string, apps []*database.Application) []OrganizationSummary {
+	applications := make(map[string]int)
+	resources := make(map[string]int)
+	for _, app := range apps {
+		applications[app.Team]++
+		if app.ScoreSpec != nil {
+			resources[app.Team] += len(app.ScoreSpec.Resources)
+		}
+	}
+
+	summaries := make([]OrganizationSummary, 0, len(names))
+	for _, name := range names {
+		org, _ := hierarchy.Organization(name)
+		summary := OrganizationSummary{
+			Name:        name,
+			Description: org.Description,
+			Admins:      append([]string{}, org.Admins...),
+			Teams:       []OrganizationTeam{},
+		}
+		for _, team := range hierarchy.Teams(name) {
+			summary.Teams = append(summary.Teams, OrganizationTeam{
+				Name:         team,
+				Settings:     hierarchy.Settings(team),
+				Applications: applications[team],
+				Resources:    resources[team],
+			})
+			summary.Applications += applications[team]
+			summary.Resources += resources[team]
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// HandleOrganizations handles GET /api/organizations. Admins see every organization,
+// organization admins the ones they administer and other users the organization of their team.
+func (s *Server) HandleOrganizations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	hierarchy := s.organizations()
+	var names []string
+	if user.IsAdmin() {
+		names = hierarchy.Organizations()
+	} else {
+		names = hierarchy.AdminOrgs(user.Username)
+		if org := hierarchy.OrgOf(user.Team); org != "" && !slices.Contains(names, org) {
+			names = append(names, org)
+			sort.Strings(names)
+		}
+	}
+
+	var apps []*database.Application
+	if s.db != nil && len(names) > 0 {
+		var err error
+		apps, err = s.db.ListApplications()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to list applications: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(buildOrganizationSummaries(hierarchy, names, apps)); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
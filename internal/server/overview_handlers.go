@@ -0,0 +1,256 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/users"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultKeyExpiryWindow is how far ahead the overview looks for expiring API keys
+	defaultKeyExpiryWindow = 14 * 24 * time.Hour
+	// overviewWorkflowLimit caps the running executions scanned for the overview
+	overviewWorkflowLimit = 500
+)
+
+// OverviewApplication is an application owned by the user's team
+type OverviewApplication struct {
+	Name             string    `json:"name"`
+	Team             string    `json:"team"`
+	Resources        int       `json:"resources"`
+	FailingResources int       `json:"failing_resources"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// OverviewResource is a resource that failed or reports an unhealthy status
+type OverviewResource struct {
+	ID           int64                           `json:"id"`
+	Application  string                          `json:"application"`
+	Name         string                          `json:"name"`
+	Type         string                          `json:"type"`
+	State        database.ResourceLifecycleState `json:"state"`
+	HealthStatus string                          `json:"health_status,omitempty"`
+	ErrorMessage string                          `json:"error_message,omitempty"`
+}
+
+// OverviewAPIKey is an API key that expires within the expiry window or has expired
+type OverviewAPIKey struct {
+	Name      string    `json:"name"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Expired   bool      `json:"expired"`
+	DaysLeft  int       `json:"days_left"`
+}
+
+// OverviewCounts summarizes the overview for badges on the web UI home page
+type OverviewCounts struct {
+	Applications     int `json:"applications"`
+	RunningWorkflows int `json:"running_workflows"`
+	FailingResources int `json:"failing_resources"`
+	PendingApprovals int `json:"pending_approvals"`
+	ExpiringAPIKeys  int `json:"expiring_api_keys"`
+}
+
+// UserOverview is the response of GET /api/me/overview
+type UserOverview struct {
+	Username         string                               `json:"username"`
+	Team             string                               `json:"team"`
+	Counts           OverviewCounts                       `json:"counts"`
+	Applications     []OverviewApplication                `json:"applications"`
+	RunningWorkflows []*database.WorkflowExecutionSummary `json:"running_workflows"`
+	FailingResources []OverviewResource                   `json:"failing_resources"`
+	PendingApprovals []*database.WorkflowApproval         `json:"pending_approvals"`
+	ExpiringAPIKeys  []OverviewAPIKey                     `json:"expiring_api_keys"`
+	GeneratedAt      time.Time                            `json:"generated_at"`
+}
+
+// overviewSources holds the records an overview is built from
+type overviewSources struct {
+	applications []*database.Application
+	resources    map[string][]*database.ResourceInstance
+	running      []*database.WorkflowExecutionSummary
+	approvals    []*database.WorkflowApproval
+	apiKeys      []users.APIKey
+}
+
+// HandleMyOverview handles GET /api/me/overview, aggregating the caller's applications,
+// running workflows, failing resources, pending approvals and expiring API keys.
+// ?key_expiry_days=N widens or narrows the API key window (default 14).
+func (s *Server) HandleMyOverview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	window := defaultKeyExpiryWindow
+	if days := r.URL.Query().Get("key_expiry_days"); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			http.Error(w, "key_expiry_days must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	}
+
+	sources, err := s.loadOverviewSources(user)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to build overview: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	overview := buildUserOverview(user, sources, s.Clock().Now(), window)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(overview); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// loadOverviewSources reads everything the overview needs for the user's team
+func (s *Server) loadOverviewSources(user *users.User) (*overviewSources, error) {
+	apps, err := s.db.ListApplicationsByTeam(user.Team)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	sources := &overviewSources{
+		applications: apps,
+		resources:    make(map[string][]*database.ResourceInstance, len(apps)),
+	}
+
+	if repo := s.GetResourceRepository(); repo != nil {
+		for _, app := range apps {
+			resources, err := repo.ListResourceInstances(app.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list resources of %s: %w", app.Name, err)
+			}
+			sources.resources[app.Name] = resources
+		}
+	}
+
+	if s.workflowRepo != nil {
+		sources.running, err = s.workflowRepo.ListWorkflowExecutions("", "", database.WorkflowStatusRunning, overviewWorkflowLimit, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list running workflows: %w", err)
+		}
+	}
+
+	sources.approvals, err = s.db.ListWorkflowApprovals(database.ApprovalStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+
+	sources.apiKeys, err = s.userAPIKeys(user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+// buildUserOverview filters the sources down to the user's team. Admins also see every
+// pending approval, since they can decide all of them.
+func buildUserOverview(user *users.User, sources *overviewSources, now time.Time, keyWindow time.Duration) *UserOverview {
+	overview := &UserOverview{
+		Username:         user.Username,
+		Team:             user.Team,
+		Applications:     []OverviewApplication{},
+		RunningWorkflows: []*database.WorkflowExecutionSummary{},
+		FailingResources: []OverviewResource{},
+		PendingApprovals: []*database.WorkflowApproval{},
+		ExpiringAPIKeys:  []OverviewAPIKey{},
+		GeneratedAt:      now,
+	}
+
+	owned := make(map[string]bool, len(sources.applications))
+	for _, app := range sources.applications {
+		owned[app.Name] = true
+
+		summary := OverviewApplication{Name: app.Name, Team: app.Team, UpdatedAt: app.UpdatedAt}
+		for _, resource := range sources.resources[app.Name] {
+			summary.Resources++
+			if !resourceFailing(resource) {
+				continue
+			}
+			summary.FailingResources++
+
+			failing := OverviewResource{
+				ID:           resource.ID,
+				Application:  app.Name,
+				Name:         resource.ResourceName,
+				Type:         resource.ResourceType,
+				State:        resource.State,
+				HealthStatus: resource.HealthStatus,
+			}
+			if resource.ErrorMessage != nil {
+				failing.ErrorMessage = *resource.ErrorMessage
+			}
+			overview.FailingResources = append(overview.FailingResources, failing)
+		}
+		overview.Applications = append(overview.Applications, summary)
+	}
+	sort.Slice(overview.Applications, func(i, j int) bool {
+		return overview.Applications[i].Name < overview.Applications[j].Name
+	})
+
+	for _, execution := range sources.running {
+		if owned[execution.ApplicationName] {
+			overview.RunningWorkflows = append(overview.RunningWorkflows, execution)
+		}
+	}
+
+	for _, approval := range sources.approvals {
+		if owned[approval.ApplicationName] || user.IsAdmin() {
+			overview.PendingApprovals = append(overview.PendingApprovals, approval)
+		}
+	}
+
+	for _, key := range sources.apiKeys {
+		if key.ExpiresAt.IsZero() || key.ExpiresAt.After(now.Add(keyWindow)) {
+			continue
+		}
+		remaining := key.ExpiresAt.Sub(now)
+		overview.ExpiringAPIKeys = append(overview.ExpiringAPIKeys, OverviewAPIKey{
+			Name:      key.Name,
+			ExpiresAt: key.ExpiresAt,
+			Expired:   remaining <= 0,
+			DaysLeft:  max(0, int(remaining.Hours()/24)),
+		})
+	}
+	sort.Slice(overview.ExpiringAPIKeys, func(i, j int) bool {
+		return overview.ExpiringAPIKeys[i].ExpiresAt.Before(overview.ExpiringAPIKeys[j].ExpiresAt)
+	})
+
+	overview.Counts = OverviewCounts{
+		Applications:     len(overview.Applications),
+		RunningWorkflows: len(overview.RunningWorkflows),
+		FailingResources: len(overview.FailingResources),
+		PendingApprovals: len(overview.PendingApprovals),
+		ExpiringAPIKeys:  len(overview.ExpiringAPIKeys),
+	}
+
+	return overview
+}
+
+// resourceFailing reports whether a resource needs attention
+func resourceFailing(resource *database.ResourceInstance) bool {
+	return resource.State == database.ResourceStateFailed ||
+		resource.State == database.ResourceStateDegraded ||
+		resource.HealthStatus == "unhealthy"
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/workflow"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// providerLookup returns the provider registry for pre-flight checks, or nil without one
+func (s *Server) providerLookup() workflow.ProviderLookup {
+	if s.providerRegistry == nil {
+		return nil
+	}
+	return s.providerRegistry
+}
+
+// writePreflightReport answers a golden path request with its pre-flight results: 200 when
+// all checks passed, 422 listing every failed check otherwise
+func (s *Server) writePreflightReport(w http.ResponseWriter, goldenPath, appName string, report *workflow.PreflightReport) {
+	response := map[string]interface{}{
+		"application": appName,
+		"golden_path": goldenPath,
+		"preflight":   report,
+	}
+	statusCode := http.StatusOK
+	if failures := report.Failures(); len(failures) > 0 {
+		names := make([]string, 0, len(failures))
+		for _, failure := range failures {
+			names = append(names, failure.Name)
+		}
+		response["error"] = fmt.Sprintf("%d pre-flight check(s) failed: %s", len(failures), strings.Join(names, ", "))
+		statusCode = http.StatusUnprocessableEntity
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/provenance"
+	"innominatus/internal/types"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// providersDir is where provider workflow files live, relative to the server's working directory
+const providersDir = "providers"
+
+// SetProvenance configures the builder identity recorded in provenance documents and the
+// optional key used to sign them
+func (s *Server) SetProvenance(signer *provenance.Signer, builder provenance.Builder) {
+	s.provenanceSigner = signer
+	s.provenanceBuilder = builder
+}
+
+// recordDeploymentProvenance seals and stores a provenance document. Failures are logged
+// but do not fail the deployment.
+func (s *Server) recordDeploymentProvenance(doc *provenance.Document) {
+	logger := logging.NewStructuredLogger("server")
+
+	if s.db == nil {
+		return
+	}
+
+	doc.Builder = s.provenanceBuilder
+	if doc.Builder.ID == "" {
+		doc.Builder.ID = "innominatus"
+	}
+
+	envelope, err := provenance.Seal(doc, s.provenanceSigner)
+	if err != nil {
+		logger.Warnf("Failed to seal provenance for %s: %v", doc.Application, err)
+		return
+	}
+
+	record := &database.DeploymentProvenance{
+		ApplicationName: doc.Application,
+		Kind:            doc.Kind,
+		Document:        string(envelope.Payload),
+		Digest:          envelope.Digest,
+		Signature:       envelope.Signature,
+		KeyID:           envelope.KeyID,
+	}
+	if err := s.db.CreateDeploymentProvenance(record); err != nil {
+		logger.Warnf("Failed to record provenance for %s: %v", doc.Application, err)
+		return
+	}
+
+	logger.Infof("Recorded provenance %d for %s (%s)", record.ID, doc.Application, envelope.Digest)
+}
+
+// specProvenance returns the provider workflows and provider versions resolved for the
+// spec's resources, sorted by name
+func (s *Server) specProvenance(spec *types.ScoreSpec) ([]provenance.Workflow, []provenance.Provider) {
+	workflows := []provenance.Workflow{}
+	providers := []provenance.Provider{}
+	if s.providerResolver == nil {
+		return workflows, providers
+	}
+
+	seenWorkflows := make(map[string]bool)
+	seenProviders := make(map[string]bool)
+	for _, resource := range spec.Resources {
+		provider, workflowMeta, err := s.providerResolver.ResolveProviderForResource(resource.Type)
+		if err != nil {
+			continue
+		}
+		if !seenProviders[provider.Metadata.Name] {
+			seenProviders[provider.Metadata.Name] = true
+			providers = append(providers, provenance.Provider{Name: provider.Metadata.Name, Version: provider.Metadata.Version})
+		}
+		key := provider.Metadata.Name + "/" + workflowMeta.Name
+		if !seenWorkflows[key] {
+			seenWorkflows[key] = true
+			path := filepath.Join(providersDir, provider.Metadata.Name, workflowMeta.File)
+			workflows = append(workflows, provenance.WorkflowFromFile(workflowMeta.Name, provider.Metadata.Name, path))
+		}
+	}
+
+	sort.Slice(workflows, func(i, j int) bool {
+		return workflows[i].Provider+"/"+workflows[i].Name < workflows[j].Provider+"/"+workflows[j].Name
+	})
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name < providers[j].Name })
+	return workflows, providers
+}
+
+// provenanceResponse is one record as returned by the API
+type provenanceResponse struct {
+	ID        int64           `json:"id"`
+	Kind      string          `json:"kind"`
+	Digest    string          `json:"digest"`
+	Signature string          `json:"signature,omitempty"`
+	KeyID     string          `json:"key_id,omitempty"`
+	Verified  bool            `json:"verified"` // Digest matches and, when signed, the signature is valid
+	CreatedAt time.Time       `json:"created_at"`
+	Document  json.RawMessage `json:"document"`
+}
+
+// verifyProvenance converts a stored record for the API, checking its digest and signature
+func (s *Server) verifyProvenance(record *database.DeploymentProvenance) provenanceResponse {
+	var verifyErr error
+	if record.Signature != "" && (s.provenanceSigner == nil || record.KeyID != s.provenanceSigner.KeyID()) {
+		// Signed with a key this server no longer holds; only the digest can be checked
+		verifyErr = fmt.Errorf("unknown signing key %s", record.KeyID)
+	} else {
+		var pub []byte
+		if s.provenanceSigner != nil {
+			pub = s.provenanceSigner.PublicKey()
+		}
+		verifyErr = provenance.Verify([]byte(record.Document), record.Digest, record.Signature, pub)
+	}
+
+	return provenanceResponse{
+		ID:        record.ID,
+		Kind:      record.Kind,
+		Digest:    record.Digest,
+		Signature: record.Signature,
+		KeyID:     record.KeyID,
+		Verified:  verifyErr == nil,
+		CreatedAt: record.CreatedAt,
+		Document:  json.RawMessage(record.Document),
+	}
+}
+
+// handleApplicationProvenance returns the provenance records of an application, newest first.
+// Records outlive the application; after deletion only admins can read them.
+func (s *Server) handleApplicationProvenance(w http.ResponseWriter, r *http.Request, appName string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.db == nil {
+		http.Error(w, "Provenance requires a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !user.IsAdmin() {
+		app, err := s.db.GetApplication(appName)
+		if err != nil {
+			http.Error(w, "Application not found", http.StatusNotFound)
+			return
+		}
+		if !s.canAccessTeam(user, app.Team) {
+			http.Error(w, "Access denied", http.StatusForbidden)
+			return
+		}
+	}
+
+	records, err := s.db.ListDeploymentProvenance(appName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list provenance: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]provenanceResponse, 0, len(records))
+	for _, record := range records {
+		items = append(items, s.verifyProvenance(record))
+	}
+
+	response := map[string]interface{}{
+		"application": appName,
+		"records":     items,
+		"count":       len(items),
+	}
+	if s.provenanceSigner != nil {
+		response["signing_key"] = map[string]string{
+			"key_id":     s.provenanceSigner.KeyID(),
+			"algorithm":  "ed25519",
+			"public_key": base64.StdEncoding.EncodeToString(s.provenanceSigner.PublicKey()),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"innominatus/internal/logging"
+	"innominatus/internal/providers"
+	"net/http"
+	"strings"
+)
+
+// SetProviderDevChannel enables registering local providers through
+// /api/admin/providers/dev (innominatus-ctl provider dev)
+func (s *Server) SetProviderDevChannel(channel *providers.DevChannel) {
+	s.providerDev = channel
+}
+
+// ProviderDevRequest uploads the files of a provider by path relative to its directory
+type ProviderDevRequest struct {
+	Files map[string][]byte `json:"files"`
+}
+
+// HandleProviderDev manages providers registered for development (admin only).
+//
+// GET    /api/admin/providers/dev          registered development providers
+// PUT    /api/admin/providers/dev          register or replace a provider from its files
+// DELETE /api/admin/providers/dev/{name}   unregister a provider
+func (s *Server) HandleProviderDev(w http.ResponseWriter, r *http.Request) {
+	if s.providerDev == nil {
+		http.Error(w, "Provider development is disabled (start the server with INNOMINATUS_PROVIDER_DEV=true outside production)", http.StatusNotFound)
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/providers/dev"), "/")
+	logger := logging.FromContext(r.Context(), "server")
+
+	switch {
+	case r.Method == "GET" && name == "":
+		writeFaultsJSON(w, http.StatusOK, map[string]interface{}{"providers": s.providerDev.List()})
+
+	case r.Method == "PUT" && name == "":
+		var req ProviderDevRequest
+		body := http.MaxBytesReader(w, r.Body, 2*providers.MaxDevProviderBytes)
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "Upload too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		username := ""
+		if user := s.getUserFromContext(r); user != nil {
+			username = user.Username
+		}
+		registration, err := s.providerDev.Register(req.Files, username)
+		if err != nil {
+			// Validation errors go back to the provider author unchanged
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		logger.InfoWithFields("Development provider registered", map[string]interface{}{
+			"provider": registration.Name,
+			"version":  registration.Version,
+			"revision": registration.Revision,
+			"user":     username,
+		})
+		writeFaultsJSON(w, http.StatusOK, registration)
+
+	case r.Method == "DELETE" && name != "":
+		if err := s.providerDev.Unregister(name); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		logger.InfoWithFields("Development provider unregistered", map[string]interface{}{"provider": name})
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
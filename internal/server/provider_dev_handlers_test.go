@@ -0,0 +1,66 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"innominatus/internal/providers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func providerDevRequest(t *testing.T, s *Server, method, path string, files map[string][]byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	if files != nil {
+		require.NoError(t, json.NewEncoder(&body).Encode(ProviderDevRequest{Files: files}))
+	}
+	rec := httptest.NewRecorder()
+	s.HandleProviderDev(rec, httptest.NewRequest(method, path, &body))
+	return rec
+}
+
+func TestHandleProviderDev(t *testing.T) {
+	s := &Server{}
+	rec := providerDevRequest(t, s, "GET", "/api/admin/providers/dev", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "disabled without a development channel")
+
+	registry := providers.NewRegistry()
+	s.SetProviderDevChannel(providers.NewDevChannel(registry, "1.5.0", t.TempDir()))
+
+	files := map[string][]byte{}
+	for _, file := range []string{"provider.yaml", "workflows/provision-test-db.yaml"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "providers", "test-team", file))
+		require.NoError(t, err)
+		files[file] = data
+	}
+
+	rec = providerDevRequest(t, s, "PUT", "/api/admin/providers/dev", files)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var registration providers.DevProvider
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &registration))
+	assert.Equal(t, "test-team", registration.Name)
+	assert.Equal(t, 1, registration.Revision)
+	_, err := registry.GetProvider("test-team")
+	require.NoError(t, err)
+
+	broken := map[string][]byte{"provider.yaml": files["provider.yaml"]}
+	rec = providerDevRequest(t, s, "PUT", "/api/admin/providers/dev", broken)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "provision-test-db")
+
+	rec = providerDevRequest(t, s, "GET", "/api/admin/providers/dev", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"revision":1`)
+
+	rec = providerDevRequest(t, s, "DELETE", "/api/admin/providers/dev/test-team", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = providerDevRequest(t, s, "DELETE", "/api/admin/providers/dev/test-team", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
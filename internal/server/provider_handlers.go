@@ -3,9 +3,13 @@ package server
 import (
 	"encoding/json"
 	"fmt"
+	"innominatus/internal/orchestration"
+	"innominatus/internal/providers"
+	"innominatus/pkg/sdk"
 	"net/http"
 	"os"
 	"sort"
+	"strings"
 )
 
 // HandleListProviders returns a list of all loaded providers
@@ -95,3 +99,142 @@ func (s *Server) HandleProviderStats(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
 	}
 }
+
+// HandleProviderDetail routes /api/providers/{name}/... requests
+func (s *Server) HandleProviderDetail(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/providers/"), "/"), "/")
+	if len(parts) == 1 && parts[0] != "" {
+		s.handleGetProvider(w, r, parts[0])
+		return
+	}
+	if len(parts) == 2 && parts[1] == "health" {
+		s.handleProviderHealth(w, r, parts[0])
+		return
+	}
+	if len(parts) == 2 && parts[1] == "docs" {
+		s.handleProviderDocs(w, r, parts[0])
+		return
+	}
+	http.Error(w, "Not found", http.StatusNotFound)
+}
+
+// handleGetProvider returns a provider with its compatibility, dependencies and the
+// configuration parameters of each workflow (GET /api/providers/{name})
+func (s *Server) handleGetProvider(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.providerRegistry == nil {
+		http.Error(w, "Provider registry not available", http.StatusServiceUnavailable)
+		return
+	}
+	provider, err := s.providerRegistry.GetProvider(name)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Provider '%s' not found", name), http.StatusNotFound)
+		return
+	}
+
+	type WorkflowDetail struct {
+		sdk.WorkflowMetadata
+		Parameters []providers.WorkflowParameter `json:"parameters"`
+	}
+
+	type ProviderDetail struct {
+		Name          string                    `json:"name"`
+		Version       string                    `json:"version"`
+		Category      string                    `json:"category,omitempty"`
+		Description   string                    `json:"description,omitempty"`
+		Compatibility sdk.ProviderCompatibility `json:"compatibility"`
+		Dependencies  []sdk.ProviderDependency  `json:"dependencies,omitempty"`
+		Capabilities  sdk.ProviderCapabilities  `json:"capabilities"`
+		Workflows     []WorkflowDetail          `json:"workflows"`
+		Provisioners  []sdk.ProvisionerMetadata `json:"provisioners,omitempty"`
+		Configuration map[string]interface{}    `json:"configuration,omitempty"`
+	}
+
+	response := ProviderDetail{
+		Name:          provider.Metadata.Name,
+		Version:       provider.Metadata.Version,
+		Category:      provider.Metadata.Category,
+		Description:   provider.Metadata.Description,
+		Compatibility: provider.Compatibility,
+		Dependencies:  provider.Dependencies,
+		Capabilities:  provider.Capabilities,
+		Workflows:     make([]WorkflowDetail, 0, len(provider.Workflows)),
+		Provisioners:  provider.Provisioners,
+		Configuration: provider.Configuration,
+	}
+	for _, wf := range provider.Workflows {
+		params, err := providers.WorkflowParameters(provider, wf)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to read workflow '%s' of provider '%s': %v", wf.Name, name, err), http.StatusInternalServerError)
+			return
+		}
+		response.Workflows = append(response.Workflows, WorkflowDetail{WorkflowMetadata: wf, Parameters: params})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// handleProviderHealth returns provisioning health for a provider (GET /api/providers/{name}/health)
+func (s *Server) handleProviderHealth(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.providerRegistry == nil {
+		http.Error(w, "Provider registry not available", http.StatusServiceUnavailable)
+		return
+	}
+	if _, err := s.providerRegistry.GetProvider(name); err != nil {
+		http.Error(w, fmt.Sprintf("Provider '%s' not found", name), http.StatusNotFound)
+		return
+	}
+
+	// Without the orchestration engine no provisioning outcomes are collected
+	health := orchestration.ProviderHealth{Provider: name, Status: orchestration.ProviderStatusUnknown}
+	if s.providerHealth != nil {
+		health = s.providerHealth.Health(name)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// handleProviderDocs returns the rendered markdown documentation of a provider and each of
+// its provisioners (GET /api/providers/{name}/docs)
+func (s *Server) handleProviderDocs(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.providerRegistry == nil {
+		http.Error(w, "Provider registry not available", http.StatusServiceUnavailable)
+		return
+	}
+	provider, err := s.providerRegistry.GetProvider(name)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Provider '%s' not found", name), http.StatusNotFound)
+		return
+	}
+
+	docs, err := providers.RenderDocs(provider)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to render documentation of provider '%s': %v", name, err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(docs); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
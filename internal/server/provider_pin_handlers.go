@@ -0,0 +1,314 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/providers"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProviderPinRequest is the body of PUT requests to provider-pins endpoints
+type ProviderPinRequest struct {
+	Provider string `json:"provider"`
+	Version  string `json:"version"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Status of a pin in the upgrade report
+const (
+	PinStatusCurrent     = "current"     // The pinned version is the registered version
+	PinStatusBehind      = "behind"      // A newer version is registered
+	PinStatusAhead       = "ahead"       // The pinned version is newer than the registered one
+	PinStatusUnavailable = "unavailable" // The pinned version is not loaded; provisioning fails
+)
+
+// ProviderUpgradeEntry reports what an upgrade means for the applications behind a pin
+type ProviderUpgradeEntry struct {
+	database.ProviderVersionPin
+	CurrentVersion string                    `json:"current_version,omitempty"` // Registered version of the provider
+	Status         string                    `json:"status"`
+	Applications   []string                  `json:"applications"`      // Applications running the pinned version
+	Changes        *providers.VersionChanges `json:"changes,omitempty"` // What changes when the pin is removed
+	Error          string                    `json:"error,omitempty"`
+}
+
+// handleApplicationProviderPins handles /api/applications/{name}/provider-pins: GET
+// returns the pins in effect for the application, including those of its team, PUT pins
+// a provider version and DELETE ?provider=NAME removes a pin of the application
+func (s *Server) handleApplicationProviderPins(w http.ResponseWriter, r *http.Request, appName string) {
+	if r.Method != "GET" && r.Method != "PUT" && r.Method != "DELETE" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.db == nil {
+		http.Error(w, "Provider version pins require a database", http.StatusServiceUnavailable)
+		return
+	}
+	app, err := s.db.GetApplication(appName)
+	if err != nil || app == nil {
+		http.Error(w, "Application not found", http.StatusNotFound)
+		return
+	}
+	if !s.canAccessTeam(user, app.Team) {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return
+	}
+
+	if r.Method != "GET" {
+		s.changeProviderPin(w, r, database.ProviderPinScopeApplication, appName, user.Username)
+		return
+	}
+	effective, err := s.db.ApplicationProviderPins(appName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get provider version pins: %v", err), http.StatusInternalServerError)
+		return
+	}
+	pins := make([]*database.ProviderVersionPin, 0, len(effective))
+	for _, pin := range effective {
+		pins = append(pins, pin)
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].Provider < pins[j].Provider })
+	writeProviderPinsJSON(w, http.StatusOK, pins)
+}
+
+// HandleTeamProviderPins handles /api/teams/{id}/provider-pins like the provider-pins of
+// applications. Team pins apply to every application of the team without a pin of its
+// own for the provider.
+func (s *Server) HandleTeamProviderPins(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "PUT" && r.Method != "DELETE" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	team := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/teams/"), "/provider-pins")
+	if team == "" || strings.Contains(team, "/") {
+		http.Error(w, "Team ID required", http.StatusBadRequest)
+		return
+	}
+	if !s.canAccessTeam(user, team) {
+		http.Error(w, "Forbidden: not a member of this team", http.StatusForbidden)
+		return
+	}
+	if s.db == nil {
+		http.Error(w, "Provider version pins require a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	if r.Method != "GET" {
+		s.changeProviderPin(w, r, database.ProviderPinScopeTeam, team, user.Username)
+		return
+	}
+	pins, err := s.db.ListProviderVersionPins(database.ProviderPinScopeTeam, team)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get provider version pins: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeProviderPinsJSON(w, http.StatusOK, pins)
+}
+
+// changeProviderPin pins a provider version (PUT) or removes a pin (DELETE ?provider=NAME)
+// of an application or team. Only versions loaded in the registry can be pinned.
+func (s *Server) changeProviderPin(w http.ResponseWriter, r *http.Request, scope, scopeName, username string) {
+	if r.Method == "DELETE" {
+		provider := r.URL.Query().Get("provider")
+		if provider == "" {
+			http.Error(w, "provider query parameter is required", http.StatusBadRequest)
+			return
+		}
+		deleted, err := s.db.DeleteProviderVersionPin(scope, scopeName, provider)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to remove provider version pin: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if !deleted {
+			http.Error(w, fmt.Sprintf("%s '%s' has no pin for provider '%s'", scope, scopeName, provider), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var req ProviderPinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Provider == "" || req.Version == "" {
+		http.Error(w, "provider and version are required", http.StatusBadRequest)
+		return
+	}
+	if s.providerRegistry == nil {
+		http.Error(w, "Provider registry not available", http.StatusServiceUnavailable)
+		return
+	}
+	if _, err := s.providerRegistry.GetProviderVersion(req.Provider, req.Version); err != nil {
+		http.Error(w, fmt.Sprintf("Cannot pin %s %s: %v", req.Provider, req.Version, err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	pin := &database.ProviderVersionPin{
+		Scope:     scope,
+		ScopeName: scopeName,
+		Provider:  req.Provider,
+		Version:   req.Version,
+		Reason:    req.Reason,
+		PinnedBy:  username,
+	}
+	if err := s.db.SaveProviderVersionPin(pin); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to save provider version pin: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeProviderPinsJSON(w, http.StatusOK, pin)
+}
+
+// HandleProviderUpgradeReport lists the provider version pins the user can see with the
+// applications behind them and what changes when they are removed.
+//
+// GET /api/providers/upgrade-report?application=NAME
+func (s *Server) HandleProviderUpgradeReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.db == nil {
+		http.Error(w, "Provider version pins require a database", http.StatusServiceUnavailable)
+		return
+	}
+	if s.providerRegistry == nil {
+		http.Error(w, "Provider registry not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	apps, err := s.listVisibleApplications(user)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list applications: %v", err), http.StatusInternalServerError)
+		return
+	}
+	allPins, err := s.db.ListProviderVersionPins("", "")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get provider version pins: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	visibleApps := make(map[string]bool, len(apps))
+	for _, app := range apps {
+		visibleApps[app.Name] = true
+	}
+	var pins []*database.ProviderVersionPin
+	for _, pin := range allPins {
+		if (pin.Scope == database.ProviderPinScopeApplication && visibleApps[pin.ScopeName]) ||
+			(pin.Scope == database.ProviderPinScopeTeam && s.canAccessTeam(user, pin.ScopeName)) {
+			pins = append(pins, pin)
+		}
+	}
+
+	entries := providerUpgradeReport(s.providerRegistry, pins, apps)
+	if application := r.URL.Query().Get("application"); application != "" {
+		filtered := []ProviderUpgradeEntry{}
+		for _, entry := range entries {
+			for _, name := range entry.Applications {
+				if name == application {
+					filtered = append(filtered, entry)
+					break
+				}
+			}
+		}
+		entries = filtered
+	}
+
+	writeProviderPinsJSON(w, http.StatusOK, map[string]interface{}{
+		"generated_at": s.Clock().Now().UTC().Format(time.RFC3339),
+		"pins":         entries,
+	})
+}
+
+// providerUpgradeReport compares each pin with the registered version of its provider.
+// Team pins cover the applications of the team that do not pin the provider themselves.
+func providerUpgradeReport(registry ProviderRegistry, pins []*database.ProviderVersionPin, apps []*database.Application) []ProviderUpgradeEntry {
+	appPinned := make(map[string]bool) // application/provider
+	for _, pin := range pins {
+		if pin.Scope == database.ProviderPinScopeApplication {
+			appPinned[pin.ScopeName+"/"+pin.Provider] = true
+		}
+	}
+
+	entries := make([]ProviderUpgradeEntry, 0, len(pins))
+	for _, pin := range pins {
+		entry := ProviderUpgradeEntry{ProviderVersionPin: *pin, Applications: []string{}}
+		if pin.Scope == database.ProviderPinScopeApplication {
+			entry.Applications = append(entry.Applications, pin.ScopeName)
+		} else {
+			for _, app := range apps {
+				if app.Team == pin.ScopeName && !appPinned[app.Name+"/"+pin.Provider] {
+					entry.Applications = append(entry.Applications, app.Name)
+				}
+			}
+			sort.Strings(entry.Applications)
+		}
+
+		current, err := registry.GetProvider(pin.Provider)
+		if err != nil {
+			entry.Status = PinStatusUnavailable
+			entry.Error = err.Error()
+			entries = append(entries, entry)
+			continue
+		}
+		entry.CurrentVersion = current.Metadata.Version
+
+		changes, err := registry.CompareVersions(pin.Provider, pin.Version, current.Metadata.Version)
+		switch {
+		case err != nil:
+			entry.Status = PinStatusUnavailable
+			entry.Error = err.Error()
+		case pin.Version == current.Metadata.Version:
+			entry.Status = PinStatusCurrent
+		case providers.CompareVersionNumbers(pin.Version, current.Metadata.Version) < 0:
+			entry.Status = PinStatusBehind
+			entry.Changes = changes
+		default:
+			entry.Status = PinStatusAhead
+			entry.Changes = changes
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		return a.ScopeName < b.ScopeName
+	})
+	return entries
+}
+
+func writeProviderPinsJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"innominatus/internal/logging"
+	"innominatus/internal/providers"
+	"time"
+)
+
+// StartProviderRefresh checks the refs of Git providers every interval and reloads the
+// providers whose ref moved or whose clone disappeared. An interval of 0 disables it.
+func (s *Server) StartProviderRefresh(ctx context.Context, refresher *providers.GitRefresher, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := s.Clock().NewTicker("provider-refresh", interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+			for _, result := range refresher.RefreshAll() {
+				logProviderRefreshResult(result)
+			}
+		}
+	}()
+}
+
+// logProviderRefreshResult logs failed refreshes; reloads are logged by the refresher
+func logProviderRefreshResult(result providers.RefreshResult) {
+	if result.Error == "" {
+		return
+	}
+	logging.NewStructuredLogger("server").WarnWithFields("Provider refresh failed, keeping cached assets", map[string]interface{}{
+		"provider": result.Provider,
+		"error":    result.Error,
+	})
+}
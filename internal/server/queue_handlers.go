@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 )
 
 // HandleQueueStats returns queue statistics
@@ -51,3 +52,34 @@ func (s *Server) HandleActiveTasks(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(os.Stderr, "failed to encode active tasks: %v\n", err)
 	}
 }
+
+// HandleQueueTask returns the status of a queued workflow task and, once a worker has
+// started it, the ID of its workflow execution
+func (s *Server) HandleQueueTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if s.workflowQueue == nil {
+		http.Error(w, "Queue not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	taskID := strings.TrimPrefix(r.URL.Path, "/api/queue/tasks/")
+	if taskID == "" || strings.Contains(taskID, "/") {
+		http.Error(w, "Task ID required", http.StatusBadRequest)
+		return
+	}
+
+	task, ok := s.workflowQueue.GetTask(taskID)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Task '%s' not found", taskID), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(task); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode task: %v\n", err)
+	}
+}
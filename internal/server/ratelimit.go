@@ -17,6 +17,8 @@ type RateLimiter struct {
 	ipBuckets      map[string]*TokenBucket
 	endpointLimits map[string]int // custom limits per endpoint
 	mu             sync.RWMutex
+	stop           chan struct{}
+	stopOnce       sync.Once
 }
 
 // TokenBucket represents a token bucket for rate limiting
@@ -71,6 +73,7 @@ func NewRateLimiter(config RateLimitConfig) *RateLimiter {
 		userBuckets:    make(map[string]*TokenBucket),
 		ipBuckets:      make(map[string]*TokenBucket),
 		endpointLimits: config.EndpointLimits,
+		stop:           make(chan struct{}),
 	}
 
 	// Start cleanup goroutine
@@ -146,7 +149,13 @@ func (rl *RateLimiter) cleanup() {
 	ticker := time.NewTicker(rl.cleanupPeriod)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-rl.stop:
+			return
+		case <-ticker.C:
+		}
+
 		rl.mu.Lock()
 		now := time.Now()
 
@@ -172,6 +181,11 @@ func (rl *RateLimiter) cleanup() {
 	}
 }
 
+// Stop ends the cleanup of unused buckets; the limiter keeps limiting
+func (rl *RateLimiter) Stop() {
+	rl.stopOnce.Do(func() { close(rl.stop) })
+}
+
 // NewTokenBucket creates a new token bucket
 func NewTokenBucket(ratePerMinute, burst int) *TokenBucket {
 	return &TokenBucket{
@@ -217,7 +231,8 @@ func (tb *TokenBucket) refill() {
 func (s *Server) RateLimitMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Skip rate limiting if disabled
-		if s.rateLimiter == nil {
+		limiter := s.currentRateLimiter()
+		if limiter == nil {
 			next(w, r)
 			return
 		}
@@ -235,10 +250,10 @@ func (s *Server) RateLimitMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		endpoint := r.URL.Path
 
 		// Check rate limit
-		allowed, reason := s.rateLimiter.Allow(userID, clientIP, endpoint)
+		allowed, reason := limiter.Allow(userID, clientIP, endpoint)
 		if !allowed {
 			// Add rate limit headers
-			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", s.rateLimiter.perUserLimit))
+			w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%d", limiter.perUserLimit))
 			w.Header().Set("X-RateLimit-Remaining", "0")
 			w.Header().Set("Retry-After", "60")
 
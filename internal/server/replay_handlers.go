@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/workflow"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// handleWorkflowReplay re-executes a past run with the workflow definition and parameters
+// recorded for it, not the current workflow file on disk. The replay is a new execution
+// linked to the original through replay_of_id; it runs in the background so approval
+// gates in the pinned definition do not block the request.
+func (s *Server) handleWorkflowReplay(w http.ResponseWriter, r *http.Request, workflowID int64) {
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.workflowRepo == nil {
+		http.Error(w, "Workflow replay requires a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	original, err := s.workflowExecutor.GetWorkflowExecution(workflowID)
+	if err != nil {
+		if err.Error() == "workflow execution not found" {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get workflow: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if _, ok := s.authorizeWorkflowRun(w, user, original); !ok {
+		return
+	}
+
+	if original.Status == database.WorkflowStatusRunning {
+		http.Error(w, "Workflow is still running; replay it once it has finished", http.StatusConflict)
+		return
+	}
+
+	reconstructed, err := s.workflowRepo.ReconstructWorkflowFromExecution(workflowID)
+	if err != nil {
+		if strings.Contains(err.Error(), "no steps found") {
+			http.Error(w, "This workflow execution has no stored step configuration and cannot be replayed.", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to reconstruct workflow: %v", err), http.StatusInternalServerError)
+		return
+	}
+	pinned := workflowFromMap(reconstructed)
+	if pinned == nil {
+		http.Error(w, "Failed to decode stored workflow definition", http.StatusInternalServerError)
+		return
+	}
+
+	parameters, sources, err := s.workflowRepo.GetWorkflowExecutionParameters(workflowID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load workflow parameters: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// The replay outlives the request but keeps its log fields
+	ctx := logging.WithApp(context.WithoutCancel(r.Context()), original.ApplicationName)
+	logger := logging.FromContext(ctx, "server")
+
+	// Values from external sources were never stored; resolve them again
+	if len(sources) > 0 {
+		resolved, err := s.parameterResolver().Resolve(ctx, sources, parameters)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to resolve workflow parameters: %v", err), http.StatusBadGateway)
+			return
+		}
+		for name, value := range resolved {
+			parameters[name] = value
+		}
+		ctx = workflow.WithExternalParameters(ctx, sources)
+	}
+
+	replayID, done, err := s.workflowExecutor.StartReplay(ctx, workflowID, original.ApplicationName, original.WorkflowName, *pinned, parameters)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to replay workflow: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	logger.Infof("User %s replaying workflow execution %d as %d", user.Username, workflowID, replayID)
+	go func() {
+		if err := <-done; err != nil {
+			logger.Warnf("Replay %d of workflow execution %d failed: %v", replayID, workflowID, err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":               fmt.Sprintf("Replaying workflow execution %d as %d", workflowID, replayID),
+		"execution_id":          replayID,
+		"original_execution_id": workflowID,
+		"app_name":              original.ApplicationName,
+		"workflow_name":         original.WorkflowName,
+		"status":                database.WorkflowStatusRunning,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
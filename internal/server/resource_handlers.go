@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/maintenance"
 	"net/http"
 	"os"
 	"strconv"
@@ -44,6 +46,19 @@ func (s *Server) HandleResourceDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Dispatch sub-resources
+	if len(pathParts) >= 4 {
+		switch pathParts[3] {
+		case "transition":
+			s.HandleResourceTransition(w, r)
+		case "health":
+			s.HandleResourceHealth(w, r)
+		default:
+			http.Error(w, "Not found", http.StatusNotFound)
+		}
+		return
+	}
+
 	switch r.Method {
 	case "GET":
 		s.handleGetResource(w, r, resourceID)
@@ -85,9 +100,11 @@ func (s *Server) HandleResourceTransition(w http.ResponseWriter, r *http.Request
 
 	// Parse request body
 	var req struct {
-		NewState string                 `json:"new_state"`
-		Reason   string                 `json:"reason"`
-		Metadata map[string]interface{} `json:"metadata,omitempty"`
+		NewState  string                 `json:"new_state"`
+		Reason    string                 `json:"reason"`
+		Operation string                 `json:"operation,omitempty"` // resize, upgrade, certificate-rotation
+		Override  bool                   `json:"override,omitempty"`  // Bypass maintenance windows (admin only)
+		Metadata  map[string]interface{} `json:"metadata,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -105,6 +122,56 @@ func (s *Server) HandleResourceTransition(w http.ResponseWriter, r *http.Request
 	// Convert string state to ResourceLifecycleState
 	newState := database.ResourceLifecycleState(req.NewState)
 
+	// Disruptive operations outside a maintenance window are deferred
+	operation := operationForTransition(req.Operation, newState)
+	if maintenance.IsDisruptive(operation) {
+		resource, err := s.resourceManager.GetResource(resourceID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get resource: %v", err), http.StatusNotFound)
+			return
+		}
+
+		decision, err := s.evaluateMaintenanceWindow(resource.ApplicationName, operation)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to evaluate maintenance windows: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		if !decision.Allowed {
+			if req.Override && !user.IsAdmin() {
+				http.Error(w, "Forbidden: only admins can override maintenance windows", http.StatusForbidden)
+				return
+			}
+			if !req.Override {
+				deferred := &database.DeferredOperation{
+					ApplicationName: resource.ApplicationName,
+					ResourceID:      resourceID,
+					Operation:       operation,
+					TargetState:     req.NewState,
+					Reason:          req.Reason,
+					RequestedBy:     user.Username,
+					RequestedAt:     s.Clock().Now(),
+					ScheduledFor:    decision.ScheduledFor,
+				}
+				if err := s.db.CreateDeferredOperation(deferred); err != nil {
+					http.Error(w, fmt.Sprintf("Failed to defer operation: %v", err), http.StatusInternalServerError)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusAccepted)
+				if err := json.NewEncoder(w).Encode(map[string]interface{}{
+					"deferred":  true,
+					"decision":  decision,
+					"operation": deferred,
+				}); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+				}
+				return
+			}
+		}
+	}
+
 	// Perform state transition
 	err = s.resourceManager.TransitionResourceState(resourceID, newState, req.Reason, user.Username, req.Metadata)
 	if err != nil {
@@ -162,6 +229,7 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 	appName := r.URL.Query().Get("app")
 	resourceType := r.URL.Query().Get("type") // native, delegated, external
 	provider := r.URL.Query().Get("provider") // gitops, terraform-enterprise, etc.
+	driftedOnly := r.URL.Query().Get("drift") == "true"
 
 	if s.resourceManager == nil {
 		http.Error(w, "Resource management not available", http.StatusServiceUnavailable)
@@ -248,6 +316,10 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 				appResources = filtered
 			}
 
+			if driftedOnly {
+				appResources = driftedResources(appResources)
+			}
+
 			if len(appResources) > 0 {
 				allResources[app.Name] = appResources
 			}
@@ -260,6 +332,10 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if driftedOnly {
+		resources = driftedResources(resources)
+	}
+
 	// Return filtered resources for specific app and/or type
 	w.Header().Set("Content-Type", "application/json")
 	response := map[string]interface{}{
@@ -274,12 +350,27 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 	if provider != "" {
 		response["provider"] = provider
 	}
+	if driftedOnly {
+		response["drift"] = true
+	}
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
 	}
 }
 
+// driftedResources returns the resources the orchestration engine found drifted from their
+// desired configuration
+func driftedResources(resources []*database.ResourceInstance) []*database.ResourceInstance {
+	drifted := make([]*database.ResourceInstance, 0)
+	for _, res := range resources {
+		if res.DriftState == database.DriftStateDrifted {
+			drifted = append(drifted, res)
+		}
+	}
+	return drifted
+}
+
 // handleCreateResource creates a new resource instance
 func (s *Server) handleCreateResource(w http.ResponseWriter, r *http.Request) {
 	// Check if we have database and resource manager
@@ -344,6 +435,8 @@ func (s *Server) handleCreateResource(w http.ResponseWriter, r *http.Request) {
 
 // handleGetResource gets a specific resource by ID
 func (s *Server) handleGetResource(w http.ResponseWriter, r *http.Request, resourceID int64) {
+	logger := logging.FromContext(r.Context(), "server")
+
 	resource, err := s.resourceManager.GetResource(resourceID)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Resource not found: %v", err), http.StatusNotFound)
@@ -354,7 +447,7 @@ func (s *Server) handleGetResource(w http.ResponseWriter, r *http.Request, resou
 	transitions, err := s.resourceManager.GetResourceStateTransitions(resourceID, 10)
 	if err != nil {
 		// Don't fail the request, just log and continue
-		fmt.Printf("Warning: Failed to get state transitions for resource %d: %v\n", resourceID, err)
+		logger.Warnf("Failed to get state transitions for resource %d: %v", resourceID, err)
 	}
 
 	// Add transitions to resource
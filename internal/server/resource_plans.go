@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"sort"
+
+	"innominatus/internal/orchestration"
+	"innominatus/internal/resources"
+	"innominatus/internal/types"
+	"innominatus/internal/workflow"
+	"innominatus/pkg/sdk"
+)
+
+// specResourcePlans asks the provisioners of the spec's resources for dry-run plans. Resources
+// whose provisioner does not implement sdk.Planner are left out.
+func (s *Server) specResourcePlans(ctx context.Context, spec *types.ScoreSpec) []workflow.ResourcePlan {
+	if s.providerResolver == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(spec.Resources))
+	for name := range spec.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var plans []workflow.ResourcePlan
+	for _, name := range names {
+		resource := spec.Resources[name]
+		current := &sdk.Resource{
+			ApplicationName: spec.Metadata.Name,
+			ResourceName:    name,
+			ResourceType:    resource.Type,
+			Configuration:   sdk.NewMapConfig(nil),
+		}
+		operation := "create"
+		if s.resourceManager != nil {
+			// Lookup errors are treated as a resource that does not exist yet
+			if instance, err := s.resourceManager.GetResourceByName(spec.Metadata.Name, name); err == nil && instance != nil {
+				current = orchestration.SDKResource(instance)
+				operation = "update"
+			}
+		}
+
+		plan, err := s.providerResolver.PlanResource(ctx, current, resources.SpecResourceConfig(spec.Metadata.Name, resource))
+		if err == nil && plan == nil {
+			continue
+		}
+		resourcePlan := workflow.ResourcePlan{Resource: name, Type: resource.Type, Operation: operation, Plan: plan}
+		if err != nil {
+			resourcePlan.Error = err.Error()
+		} else {
+			resourcePlan.Diff = plan.String()
+		}
+		plans = append(plans, resourcePlan)
+	}
+	return plans
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"net/http"
+	"os"
+)
+
+// WorkflowRollbackResponse lists the resources an execution created and their rollback state
+type WorkflowRollbackResponse struct {
+	ExecutionID   int64                            `json:"execution_id"`
+	Status        string                           `json:"status"`
+	Compensations []*database.WorkflowCompensation `json:"compensations"`
+	Pending       int                              `json:"pending"`
+	RolledBack    int                              `json:"rolled_back"`
+	Failed        int                              `json:"failed"`
+	Error         string                           `json:"error,omitempty"`
+}
+
+func newWorkflowRollbackResponse(execution *database.WorkflowExecution, compensations []*database.WorkflowCompensation) *WorkflowRollbackResponse {
+	response := &WorkflowRollbackResponse{
+		ExecutionID:   execution.ID,
+		Status:        execution.Status,
+		Compensations: compensations,
+	}
+	if response.Compensations == nil {
+		response.Compensations = []*database.WorkflowCompensation{}
+	}
+	for _, compensation := range compensations {
+		switch compensation.Status {
+		case database.CompensationStatusPending:
+			response.Pending++
+		case database.CompensationStatusRolledBack:
+			response.RolledBack++
+		case database.CompensationStatusFailed:
+			response.Failed++
+		}
+	}
+	return response
+}
+
+// handleWorkflowRollback handles /api/workflows/{id}/rollback. GET lists the resources the
+// execution created; POST removes those of a failed execution that are not rolled back yet.
+// The rollback is added to the execution as compensation steps.
+func (s *Server) handleWorkflowRollback(w http.ResponseWriter, r *http.Request, workflowID int64) {
+	if r.Method != "GET" && r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if s.workflowRepo == nil {
+		http.Error(w, "Workflow rollback requires a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	execution, err := s.workflowExecutor.GetWorkflowExecution(workflowID)
+	if err != nil {
+		if err.Error() == "workflow execution not found" {
+			http.Error(w, "Workflow not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, fmt.Sprintf("Failed to get workflow: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if _, ok := s.authorizeWorkflowRun(w, user, execution); !ok {
+		return
+	}
+
+	status := http.StatusOK
+	var response *WorkflowRollbackResponse
+	if r.Method == "GET" {
+		compensations, err := s.workflowExecutor.ListCompensations(workflowID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to list created resources: %v", err), http.StatusInternalServerError)
+			return
+		}
+		response = newWorkflowRollbackResponse(execution, compensations)
+	} else {
+		if execution.Status != database.WorkflowStatusFailed {
+			http.Error(w, fmt.Sprintf("Only failed workflows can be rolled back (workflow is %s)", execution.Status), http.StatusConflict)
+			return
+		}
+
+		// The rollback finishes even if the client goes away
+		ctx := logging.WithWorkflowID(logging.WithApp(context.WithoutCancel(r.Context()), execution.ApplicationName), workflowID)
+		logging.FromContext(ctx, "server").Infof("User %s rolling back workflow execution %d", user.Username, workflowID)
+
+		compensations, rollbackErr := s.workflowExecutor.RollbackExecution(ctx, workflowID)
+		if compensations == nil && rollbackErr != nil {
+			http.Error(w, fmt.Sprintf("Failed to roll back workflow: %v", rollbackErr), http.StatusInternalServerError)
+			return
+		}
+		response = newWorkflowRollbackResponse(execution, compensations)
+		if rollbackErr != nil {
+			response.Error = rollbackErr.Error()
+			status = http.StatusBadGateway
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"innominatus/internal/metrics"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// routeTemplates lists the API routes with their path parameters in braces. Request
+// paths are matched against these segment by segment; literal segments win over
+// parameters, so /api/providers/stats is not reported as /api/providers/{name}.
+var routeTemplates = []string{
+	"/api/admin/argocd/project-sync",
+	"/api/admin/audit",
+	"/api/admin/config",
+	"/api/admin/debug/clock",
+	"/api/admin/demo/reset",
+	"/api/admin/effective-config",
+	"/api/admin/faults",
+	"/api/admin/faults/{id}",
+	"/api/admin/gitea/team-sync",
+	"/api/admin/loadtest",
+	"/api/admin/loadtest/{id}",
+	"/api/admin/logging",
+	"/api/admin/logs/stream",
+	"/api/admin/notifications/preview",
+	"/api/admin/notifications/templates",
+	"/api/admin/notifications/test",
+	"/api/admin/observability/bundle",
+	"/api/admin/reload",
+	"/api/admin/resources/{id}/state",
+	"/api/admin/step-cache",
+	"/api/admin/temp-assets",
+	"/api/admin/usage",
+	"/api/admin/users",
+	"/api/admin/users/{username}",
+	"/api/admin/users/{username}/api-keys",
+	"/api/admin/users/{username}/api-keys/{name}",
+	"/api/ai/chat",
+	"/api/ai/generate-spec",
+	"/api/ai/status",
+	"/api/applications",
+	"/api/applications/{name}",
+	"/api/applications/{name}/deprovision",
+	"/api/applications/{name}/hibernate",
+	"/api/applications/{name}/licenses",
+	"/api/applications/{name}/preview",
+	"/api/applications/{name}/provenance",
+	"/api/applications/{name}/provider-pins",
+	"/api/applications/{name}/resume",
+	"/api/applications/{name}/status",
+	"/api/applications/{name}/workspace",
+	"/api/applications/{name}/workspace/download",
+	"/api/approvals",
+	"/api/approvals/{id}",
+	"/api/approvals/{id}/{action}",
+	"/api/auth/config",
+	"/api/auth/whoami",
+	"/api/clusters",
+	"/api/clusters/{name}",
+	"/api/concurrency-groups",
+	"/api/concurrency-groups/runs/{id}",
+	"/api/demo/nuke",
+	"/api/demo/status",
+	"/api/demo/time",
+	"/api/environments",
+	"/api/events/stream",
+	"/api/golden-paths",
+	"/api/golden-paths/{name}",
+	"/api/golden-paths/{name}/applications",
+	"/api/golden-paths/{name}/migrate",
+	"/api/golden-paths/{name}/migrations",
+	"/api/graph",
+	"/api/graph/{app}",
+	"/api/graph/{app}/annotations",
+	"/api/graph/{app}/critical-path",
+	"/api/graph/{app}/dependencies",
+	"/api/graph/{app}/export",
+	"/api/graph/{app}/history",
+	"/api/graph/{app}/layout",
+	"/api/graph/{app}/metrics",
+	"/api/hibernation/wake/{name}",
+	"/api/impersonate",
+	"/api/integrations/slack/commands",
+	"/api/integrations/slack/interactions",
+	"/api/kubernetes/token",
+	"/api/login",
+	"/api/maintenance-windows",
+	"/api/maintenance-windows/{id}",
+	"/api/me/overview",
+	"/api/naming/availability",
+	"/api/oidc/config",
+	"/api/oidc/token",
+	"/api/operations/upcoming",
+	"/api/organizations",
+	"/api/profile",
+	"/api/profile/api-keys",
+	"/api/profile/api-keys/{name}",
+	"/api/providers",
+	"/api/providers/stats",
+	"/api/providers/upgrade-report",
+	"/api/providers/{name}",
+	"/api/providers/{name}/docs",
+	"/api/providers/{name}/health",
+	"/api/queue/tasks/{id}",
+	"/api/resources",
+	"/api/resources/{id}",
+	"/api/resources/{id}/health",
+	"/api/resources/{id}/transition",
+	"/api/schedules",
+	"/api/schedules/preview",
+	"/api/schedules/{id}",
+	"/api/schedules/{id}/next-runs",
+	"/api/specs",
+	"/api/specs/{name}",
+	"/api/stats",
+	"/api/teams",
+	"/api/teams/{id}",
+	"/api/teams/{id}/provider-pins",
+	"/api/teams/{id}/usage",
+	"/api/user-info",
+	"/api/users",
+	"/api/validate",
+	"/api/version",
+	"/api/workflow-analysis",
+	"/api/workflow-analysis/preview",
+	"/api/workflows",
+	"/api/workflows/compare",
+	"/api/workflows/golden-paths/{name}/execute",
+	"/api/workflows/{id}",
+	"/api/workflows/{id}/bundle",
+	"/api/workflows/{id}/replay",
+	"/api/workflows/{id}/retry",
+	"/api/workflows/{id}/rollback",
+	"/api/workflows/{id}/steps/{step}/debug",
+	"/api/workflows/{id}/steps/{step}/debug/workspace",
+	"/approvals/{id}",
+	"/auth/callback",
+	"/auth/login",
+	"/auth/oidc/login",
+	"/health",
+	"/logout",
+	"/metrics",
+	"/ready",
+	"/scim/v2/Groups",
+	"/scim/v2/Groups/{id}",
+	"/scim/v2/ServiceProviderConfig",
+	"/scim/v2/Users",
+	"/scim/v2/Users/{id}",
+	"/swagger",
+	"/swagger-admin",
+	"/swagger-admin.yaml",
+	"/swagger-user",
+	"/swagger-user.yaml",
+	"/swagger.yaml",
+}
+
+var splitRouteTemplates = func() [][]string {
+	split := make([][]string, len(routeTemplates))
+	for i, template := range routeTemplates {
+		split[i] = strings.Split(strings.Trim(template, "/"), "/")
+	}
+	return split
+}()
+
+// normalizeRoute maps a request path to its route template, e.g. /api/workflows/42
+// becomes /api/workflows/{id}. Unknown API paths collapse to "/api/other" and anything
+// else (web UI pages and assets) to "/static", so labels cannot grow with user input.
+func normalizeRoute(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	best, bestLiterals := -1, -1
+	for i, template := range splitRouteTemplates {
+		if len(template) != len(segments) {
+			continue
+		}
+		literals := 0
+		matched := true
+		for j, part := range template {
+			if strings.HasPrefix(part, "{") {
+				continue
+			}
+			if part != segments[j] {
+				matched = false
+				break
+			}
+			literals++
+		}
+		if matched && literals > bestLiterals {
+			best, bestLiterals = i, literals
+		}
+	}
+
+	if best >= 0 {
+		return routeTemplates[best]
+	}
+	if path == "/api" || strings.HasPrefix(path, "/api/") {
+		return "/api/other"
+	}
+	return "/static"
+}
+
+// MetricsMiddleware records request count, latency and in-flight requests per route
+// template for the Prometheus endpoint
+func (s *Server) MetricsMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := normalizeRoute(r.URL.Path)
+		m := metrics.GetGlobal()
+
+		m.IncHTTPInFlight(route)
+		defer m.DecHTTPInFlight(route)
+
+		rw := &responseWriter{
+			ResponseWriter: w,
+			statusCode:     200,
+		}
+
+		next(rw, r)
+
+		m.ObserveHTTPRequest(r.Method, route, rw.statusCode, time.Since(start))
+		m.RecordHTTPRequest(r.Method, route, rw.statusCode)
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"innominatus/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeRoute(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/workflows", "/api/workflows"},
+		{"/api/workflows/42", "/api/workflows/{id}"},
+		{"/api/workflows/42/retry", "/api/workflows/{id}/retry"},
+		{"/api/workflows/golden-paths/onboard-dev-team/execute", "/api/workflows/golden-paths/{name}/execute"},
+		{"/api/applications/my-app/", "/api/applications/{name}"},
+		{"/api/applications/my-app/provenance", "/api/applications/{name}/provenance"},
+		{"/api/applications/my-app/workspace/download", "/api/applications/{name}/workspace/download"},
+		{"/api/providers/stats", "/api/providers/stats"},
+		{"/api/providers/database-team", "/api/providers/{name}"},
+		{"/api/admin/users/alice/api-keys/ci", "/api/admin/users/{username}/api-keys/{name}"},
+		{"/api/approvals/7/approve", "/api/approvals/{id}/{action}"},
+		{"/health", "/health"},
+		{"/api/unknown/a/b/c", "/api/other"},
+		{"/dashboard/index.txt", "/static"},
+		{"/", "/static"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := normalizeRoute(tt.path); got != tt.want {
+				t.Errorf("normalizeRoute(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	srv := &Server{}
+	handler := srv.MetricsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest("GET", "/api/maintenance-windows/12345", nil)
+	handler(httptest.NewRecorder(), req)
+
+	output := metrics.GetGlobal().Export()
+	for _, want := range []string{
+		`innominatus_http_route_requests_total{method="GET",route="/api/maintenance-windows/{id}",status_class="4xx"}`,
+		`innominatus_http_request_duration_seconds_count{method="GET",route="/api/maintenance-windows/{id}"}`,
+		`innominatus_http_requests_in_flight{route="/api/maintenance-windows/{id}"} 0`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Export() missing %s", want)
+		}
+	}
+	if strings.Contains(output, "12345") {
+		t.Error("Export() contains the raw request path")
+	}
+}
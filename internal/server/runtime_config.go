@@ -0,0 +1,120 @@
+package server
+
+import (
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/auth"
+	"innominatus/internal/notifications"
+	"reflect"
+	"strings"
+)
+
+// defaultCORSOrigins are allowed when server.cors.allowedOrigins is not set
+var defaultCORSOrigins = map[string]bool{
+	"http://localhost:3000":           true, // Next.js dev server
+	"http://localhost:3001":           true, // Alternative dev port
+	"http://localhost:8081":           true, // Same-origin
+	"http://innominatus.localtest.me": true, // Demo environment
+}
+
+// runtimeSettings are the admin-config.yaml settings the server applies without a restart.
+// They are replaced as a whole: a request uses the settings it started with, so reloading
+// neither drops in-flight requests nor lets them see a mix of old and new settings.
+type runtimeSettings struct {
+	corsOrigins   map[string]bool
+	rateLimit     admin.RateLimitConfig
+	rateLimiter   *RateLimiter // nil when rate limiting is off
+	authProviders auth.ProviderChain
+	notifications notifications.Config
+}
+
+// newRuntimeSettings builds and validates the runtime settings of an admin config. The
+// rate limiter of previous is kept when its limits did not change, so reloading does not
+// reset the request counts.
+func newRuntimeSettings(cfg *admin.AdminConfig, previous *runtimeSettings) (*runtimeSettings, error) {
+	if err := cfg.Server.Validate(); err != nil {
+		return nil, err
+	}
+	providers, err := auth.NewProviderChain(cfg.Authentication)
+	if err != nil {
+		return nil, fmt.Errorf("authentication: %w", err)
+	}
+	if err := cfg.Notifications.Validate(); err != nil {
+		return nil, fmt.Errorf("notifications: %w", err)
+	}
+
+	settings := &runtimeSettings{
+		corsOrigins:   defaultCORSOrigins,
+		rateLimit:     cfg.Server.RateLimit,
+		authProviders: providers,
+		notifications: cfg.Notifications,
+	}
+	if len(cfg.Server.CORS.AllowedOrigins) > 0 {
+		settings.corsOrigins = make(map[string]bool, len(cfg.Server.CORS.AllowedOrigins))
+		for _, origin := range cfg.Server.CORS.AllowedOrigins {
+			settings.corsOrigins[strings.TrimSuffix(origin, "/")] = true
+		}
+	}
+
+	switch {
+	case !cfg.Server.RateLimit.Enabled:
+	case previous != nil && previous.rateLimiter != nil && reflect.DeepEqual(previous.rateLimit, cfg.Server.RateLimit):
+		settings.rateLimiter = previous.rateLimiter
+	default:
+		settings.rateLimiter = NewRateLimiter(rateLimitConfig(cfg.Server.RateLimit))
+	}
+	return settings, nil
+}
+
+// rateLimitConfig fills the limits left unset in admin-config.yaml with the defaults
+func rateLimitConfig(c admin.RateLimitConfig) RateLimitConfig {
+	config := DefaultRateLimitConfig()
+	if c.PerUserRPM > 0 {
+		config.PerUserRPM = c.PerUserRPM
+	}
+	if c.PerIPRPM > 0 {
+		config.PerIPRPM = c.PerIPRPM
+	}
+	if c.BurstSize > 0 {
+		config.BurstSize = c.BurstSize
+	}
+	if len(c.Endpoints) > 0 {
+		config.EndpointLimits = c.Endpoints
+	}
+	return config
+}
+
+// ApplyRuntimeConfig applies the CORS origins, rate limits, authentication providers and
+// notification channels of an admin config. Nothing is applied when any of them is invalid.
+func (s *Server) ApplyRuntimeConfig(cfg *admin.AdminConfig) error {
+	s.runtimeMu.Lock()
+	defer s.runtimeMu.Unlock()
+
+	previous := s.runtime.Load()
+	settings, err := newRuntimeSettings(cfg, previous)
+	if err != nil {
+		return err
+	}
+	s.runtime.Store(settings)
+
+	if previous != nil && previous.rateLimiter != nil && previous.rateLimiter != settings.rateLimiter {
+		previous.rateLimiter.Stop()
+	}
+	return nil
+}
+
+// corsOrigins returns the origins CorsMiddleware allows
+func (s *Server) corsOrigins() map[string]bool {
+	if settings := s.runtime.Load(); settings != nil {
+		return settings.corsOrigins
+	}
+	return defaultCORSOrigins
+}
+
+// currentRateLimiter returns the rate limiter of RateLimitMiddleware, nil when it is off
+func (s *Server) currentRateLimiter() *RateLimiter {
+	if settings := s.runtime.Load(); settings != nil {
+		return settings.rateLimiter
+	}
+	return s.rateLimiter
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"innominatus/internal/admin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func corsOriginAllowed(s *Server, origin string) bool {
+	handler := s.CorsMiddleware(func(w http.ResponseWriter, r *http.Request) {})
+	req := httptest.NewRequest("GET", "/api/specs", nil)
+	req.Header.Set("Origin", origin)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec.Header().Get("Access-Control-Allow-Origin") == origin
+}
+
+func TestApplyRuntimeConfig(t *testing.T) {
+	s := &Server{}
+	assert.True(t, corsOriginAllowed(s, "http://localhost:3000"), "development origins are allowed before a config is applied")
+
+	cfg := &admin.AdminConfig{}
+	cfg.Server.CORS.AllowedOrigins = []string{"https://idp.example.com/"}
+	cfg.Server.RateLimit = admin.RateLimitConfig{Enabled: true, PerIPRPM: 5, Endpoints: map[string]int{"/api/login": 2}}
+	require.NoError(t, s.ApplyRuntimeConfig(cfg))
+
+	assert.True(t, corsOriginAllowed(s, "https://idp.example.com"))
+	assert.False(t, corsOriginAllowed(s, "http://localhost:3000"), "configured origins replace the development origins")
+
+	limiter := s.currentRateLimiter()
+	require.NotNil(t, limiter)
+	assert.Equal(t, 5, limiter.perIPLimit)
+	assert.Equal(t, 100, limiter.perUserLimit, "unset limits use the defaults")
+	assert.Equal(t, map[string]int{"/api/login": 2}, limiter.endpointLimits)
+
+	t.Run("unchanged limits keep the request counts", func(t *testing.T) {
+		require.NoError(t, s.ApplyRuntimeConfig(cfg))
+		assert.Same(t, limiter, s.currentRateLimiter())
+	})
+
+	t.Run("invalid config changes nothing", func(t *testing.T) {
+		invalid := &admin.AdminConfig{}
+		invalid.Authentication.Providers = []string{"kerberos"}
+		err := s.ApplyRuntimeConfig(invalid)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "authentication")
+		assert.True(t, corsOriginAllowed(s, "https://idp.example.com"))
+		assert.Same(t, limiter, s.currentRateLimiter())
+
+		invalid = &admin.AdminConfig{}
+		invalid.Server.CORS.AllowedOrigins = []string{"*"}
+		require.Error(t, s.ApplyRuntimeConfig(invalid))
+	})
+
+	t.Run("disabling rate limiting", func(t *testing.T) {
+		require.NoError(t, s.ApplyRuntimeConfig(&admin.AdminConfig{}))
+		assert.Nil(t, s.currentRateLimiter())
+		assert.True(t, corsOriginAllowed(s, "http://localhost:3000"))
+	})
+}
+
+func TestHandleAdminReload(t *testing.T) {
+	server := NewServer()
+	t.Chdir(t.TempDir())
+
+	reload := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.HandleAdminReload(rec, httptest.NewRequest("POST", "/api/admin/reload", nil))
+		return rec
+	}
+
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte(`server:
+  cors:
+    allowedOrigins: [https://idp.example.com]
+  rateLimit:
+    enabled: true
+    perIPRPM: 1
+    burstSize: 1
+`), 0600))
+	rec := reload()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, []interface{}{"cors", "rateLimit", "authentication", "notifications"}, response["reloaded"])
+	assert.True(t, corsOriginAllowed(server, "https://idp.example.com"))
+
+	limited := server.RateLimitMiddleware(func(w http.ResponseWriter, r *http.Request) {})
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		req := httptest.NewRequest("GET", "/api/specs", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
+		rec := httptest.NewRecorder()
+		limited(rec, req)
+		assert.Equal(t, want, rec.Code, "request %d", i+1)
+	}
+
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte(`server:
+  cors:
+    allowedOrigins: [https://portal.example.com]
+authentication:
+  providers: [kerberos]
+`), 0600))
+	rec = reload()
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "nothing was reloaded")
+	assert.True(t, corsOriginAllowed(server, "https://idp.example.com"))
+	assert.False(t, corsOriginAllowed(server, "https://portal.example.com"))
+}
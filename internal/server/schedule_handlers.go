@@ -0,0 +1,386 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/logging"
+	"innominatus/internal/schedule"
+	"innominatus/internal/security"
+	"innominatus/internal/types"
+	"innominatus/internal/workflow"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// scheduleRunnerInterval controls how often due schedules are checked; cron has minute resolution
+const scheduleRunnerInterval = time.Minute
+
+// defaultPreviewRuns is the number of upcoming runs a next-run preview returns by default
+const defaultPreviewRuns = 5
+
+// HandleSchedules handles listing (GET, optionally ?app=) and creating (POST) workflow schedules
+func (s *Server) HandleSchedules(w http.ResponseWriter, r *http.Request) {
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		s.handleListSchedules(w, r)
+	case "POST":
+		s.handleCreateSchedule(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleScheduleDetail handles /api/schedules/{id} (GET, PATCH, DELETE),
+// /api/schedules/{id}/next-runs and POST /api/schedules/preview, which previews the runs of
+// a cron expression and timezone before a schedule is created
+func (s *Server) HandleScheduleDetail(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/schedules/"), "/")
+	if path == "preview" {
+		s.handleSchedulePreview(w, r)
+		return
+	}
+
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	idStr, nextRuns := strings.CutSuffix(path, "/next-runs")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sched, err := s.db.GetSchedule(id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to load schedule: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if sched == nil || !s.canManageApplication(user, sched.ApplicationName) {
+		http.Error(w, "Schedule not found", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case nextRuns && r.Method == "GET":
+		count, err := previewCount(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		runs, err := sched.NextRuns(s.Clock().Now(), count)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid schedule: %v", err), http.StatusUnprocessableEntity)
+			return
+		}
+		writeScheduleJSON(w, http.StatusOK, map[string]interface{}{
+			"schedule_id": sched.ID,
+			"cron":        sched.Cron,
+			"timezone":    sched.Timezone,
+			"next_runs":   runs,
+		})
+	case !nextRuns && r.Method == "GET":
+		writeScheduleJSON(w, http.StatusOK, sched)
+	case !nextRuns && r.Method == "PATCH":
+		var update schedule.Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := sched.Apply(update, s.Clock().Now()); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid schedule: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := s.db.UpdateSchedule(sched); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to update schedule: %v", err), http.StatusInternalServerError)
+			return
+		}
+		writeScheduleJSON(w, http.StatusOK, sched)
+	case !nextRuns && r.Method == "DELETE":
+		if err := s.db.DeleteSchedule(id); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to delete schedule: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleListSchedules(w http.ResponseWriter, r *http.Request) {
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	schedules, err := s.db.ListSchedules(r.URL.Query().Get("app"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to list schedules: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	visible := make([]*schedule.Schedule, 0, len(schedules))
+	for _, sched := range schedules {
+		if s.canManageApplication(user, sched.ApplicationName) {
+			visible = append(visible, sched)
+		}
+	}
+
+	writeScheduleJSON(w, http.StatusOK, map[string]interface{}{
+		"schedules": visible,
+		"count":     len(visible),
+	})
+}
+
+func (s *Server) handleCreateSchedule(w http.ResponseWriter, r *http.Request) {
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sched := schedule.Schedule{Enabled: true}
+	if err := json.NewDecoder(r.Body).Decode(&sched); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if sched.Name == "" {
+		sched.Name = sched.GoldenPath
+	}
+	if err := sched.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid schedule: %v", err), http.StatusBadRequest)
+		return
+	}
+	if _, _, err := loadGoldenPathWorkflow(sched.GoldenPath); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !s.canManageApplication(user, sched.ApplicationName) {
+		http.Error(w, "Forbidden: only the owning team or an admin can manage schedules", http.StatusForbidden)
+		return
+	}
+
+	next, err := sched.Next(s.Clock().Now())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid schedule: %v", err), http.StatusBadRequest)
+		return
+	}
+	sched.NextRunAt = &next
+	sched.LastRunAt = nil
+	sched.CreatedBy = user.Username
+	if err := s.db.CreateSchedule(&sched); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to create schedule: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeScheduleJSON(w, http.StatusCreated, sched)
+}
+
+// handleSchedulePreview returns the upcoming runs of {"cron": ..., "timezone": ..., "count": ...}
+func (s *Server) handleSchedulePreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body struct {
+		Cron     string `json:"cron"`
+		Timezone string `json:"timezone"`
+		Count    int    `json:"count"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if body.Count <= 0 {
+		body.Count = defaultPreviewRuns
+	}
+
+	sched := schedule.Schedule{Cron: body.Cron, Timezone: body.Timezone}
+	runs, err := sched.NextRuns(s.Clock().Now(), body.Count)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid schedule: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeScheduleJSON(w, http.StatusOK, map[string]interface{}{
+		"cron":      body.Cron,
+		"timezone":  body.Timezone,
+		"next_runs": runs,
+	})
+}
+
+// previewCount reads the ?count= query parameter of a next-run preview
+func previewCount(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("count")
+	if value == "" {
+		return defaultPreviewRuns, nil
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("invalid count '%s'", value)
+	}
+	return count, nil
+}
+
+func writeScheduleJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// loadGoldenPathWorkflow reads the workflow of a golden path from ./workflows
+func loadGoldenPathWorkflow(goldenPathName string) (*types.WorkflowSpec, string, error) {
+	cleanPath, err := security.SafeFilePath(fmt.Sprintf("./workflows/%s.yaml", goldenPathName), "./workflows")
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid golden path '%s'", goldenPathName)
+	}
+	data, err := os.ReadFile(cleanPath) // #nosec G304 - path validated above
+	if err != nil {
+		return nil, "", fmt.Errorf("golden path '%s' not found", goldenPathName)
+	}
+	var spec types.WorkflowSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, "", fmt.Errorf("failed to parse golden path '%s': %w", goldenPathName, err)
+	}
+	return &spec, cleanPath, nil
+}
+
+// StartScheduleRunner periodically starts the golden paths of due workflow schedules
+func (s *Server) StartScheduleRunner(ctx context.Context) {
+	if s.db == nil || s.workflowExecutor == nil {
+		return
+	}
+
+	go func() {
+		ticker := s.Clock().NewTicker("schedule-runner", scheduleRunnerInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				s.runDueSchedules(ctx)
+			}
+		}
+	}()
+}
+
+// runDueSchedules starts every schedule whose next run has passed. A schedule that missed
+// several runs, e.g. while the server was down, runs once and continues with its next run
+// after now.
+func (s *Server) runDueSchedules(ctx context.Context) {
+	logger := logging.NewStructuredLogger("server")
+	now := s.Clock().Now()
+
+	schedules, err := s.db.ListDueSchedules(now)
+	if err != nil {
+		logger.Warnf("Failed to load due schedules: %v", err)
+		return
+	}
+
+	for _, sched := range schedules {
+		var nextRun *time.Time
+		if next, err := sched.Next(now); err == nil {
+			nextRun = &next
+		} else {
+			logger.Warnf("Schedule %d has no further runs: %v", sched.ID, err)
+		}
+
+		claimed, err := s.db.ClaimScheduleRun(sched.ID, *sched.NextRunAt, nextRun)
+		if err != nil {
+			logger.Warnf("Failed to claim run of schedule %d: %v", sched.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.startScheduledRun(ctx, sched, *sched.NextRunAt); err != nil {
+			logger.Warnf("Scheduled run of golden path '%s' for %s failed to start: %v", sched.GoldenPath, sched.ApplicationName, err)
+		}
+	}
+}
+
+// startScheduledRun runs the golden path of a schedule, through the workflow queue when
+// one is available
+func (s *Server) startScheduledRun(ctx context.Context, sched *schedule.Schedule, scheduledFor time.Time) error {
+	spec, _, err := loadGoldenPathWorkflow(sched.GoldenPath)
+	if err != nil {
+		return err
+	}
+
+	parameters := make(map[string]string, len(sched.Parameters))
+	for key, value := range sched.Parameters {
+		parameters[key] = value
+	}
+	if len(spec.Metadata.ParameterSources) > 0 {
+		resolved, err := s.parameterResolver().Resolve(ctx, spec.Metadata.ParameterSources, parameters)
+		if err != nil {
+			return fmt.Errorf("failed to resolve workflow parameters: %w", err)
+		}
+		for name, value := range resolved {
+			parameters[name] = value
+		}
+	}
+	parameters, err = workflow.ApplyInputs(spec.Spec.Inputs, parameters)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(logging.WithApp(ctx, sched.ApplicationName), "server")
+	if deprecation := goldenPathDeprecation(sched.GoldenPath); deprecation != nil {
+		logger.Warnf("Schedule %d: %s", sched.ID, deprecation.Warning(sched.GoldenPath))
+	}
+	logger.InfoWithFields("Starting scheduled golden path", map[string]interface{}{
+		"schedule_id":   sched.ID,
+		"golden_path":   sched.GoldenPath,
+		"scheduled_for": scheduledFor.In(time.UTC).Format(time.RFC3339),
+		"timezone":      sched.Timezone,
+	})
+
+	workflowName := fmt.Sprintf("golden-path-%s", sched.GoldenPath)
+	workflowCtx := logging.WithApp(context.WithoutCancel(ctx), sched.ApplicationName)
+	if s.workflowQueue != nil {
+		metadata := map[string]interface{}{
+			"user":        sched.CreatedBy,
+			"golden_path": sched.GoldenPath,
+			"source":      "schedule",
+			"schedule_id": sched.ID,
+			"parameters":  parameters,
+		}
+		_, err := s.workflowQueue.EnqueueWithContext(workflowCtx, sched.ApplicationName, workflowName, spec.Spec, metadata, nil)
+		return err
+	}
+
+	go func() {
+		if err := s.workflowExecutor.ExecuteWorkflowWithContext(workflowCtx, sched.ApplicationName, workflowName, spec.Spec, parameters); err != nil {
+			logger.Errorf("Scheduled golden path '%s' for %s failed: %v", sched.GoldenPath, sched.ApplicationName, err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"innominatus/internal/admin"
+	"innominatus/internal/database"
+	"innominatus/internal/scim"
+)
+
+// directoryStore holds SCIM-provisioned users and teams; implemented by *database.Database
+type directoryStore interface {
+	scim.Store
+	GetDirectoryUserByUsername(username string) (*database.DirectoryUser, error)
+}
+
+// scimConfig returns the scim section of admin-config.yaml; SCIM is off without it
+func (s *Server) scimConfig() scim.Config {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return scim.Config{}
+	}
+	return adminConfig.SCIM
+}
+
+// scimStore returns the store SCIM provisions into; nil without a database
+func (s *Server) scimStore() directoryStore {
+	if s.directoryStore != nil {
+		return s.directoryStore
+	}
+	if s.db == nil {
+		return nil
+	}
+	return s.db
+}
+
+// HandleSCIM serves the SCIM 2.0 Users, Groups and ServiceProviderConfig resources
+// under /scim/v2. Requests authenticate with the bearer token from scim.tokenEnv.
+func (s *Server) HandleSCIM(w http.ResponseWriter, r *http.Request) {
+	config := s.scimConfig()
+	if !config.Enabled {
+		writeSCIMError(w, scim.NewError(http.StatusNotFound, "", "SCIM provisioning is not enabled"))
+		return
+	}
+	if !config.Authorize(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+		writeSCIMError(w, scim.NewError(http.StatusUnauthorized, "", "invalid or missing bearer token"))
+		return
+	}
+	store := s.scimStore()
+	if store == nil {
+		writeSCIMError(w, scim.NewError(http.StatusServiceUnavailable, "", "SCIM provisioning requires a database"))
+		return
+	}
+	service := scim.NewService(store)
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, scim.BasePath), "/"), "/")
+	resource, id := parts[0], ""
+	if len(parts) == 2 {
+		id = parts[1]
+	} else if len(parts) > 2 {
+		writeSCIMError(w, scim.NewError(http.StatusNotFound, "", "unknown SCIM endpoint %s", r.URL.Path))
+		return
+	}
+
+	switch resource {
+	case "Users":
+		s.handleSCIMUsers(w, r, service, id)
+	case "Groups":
+		s.handleSCIMGroups(w, r, service, id)
+	case "ServiceProviderConfig":
+		if r.Method != "GET" || id != "" {
+			writeSCIMError(w, scim.NewError(http.StatusMethodNotAllowed, "", "method %s not allowed", r.Method))
+			return
+		}
+		writeSCIM(w, http.StatusOK, scim.ServiceProviderConfig())
+	default:
+		writeSCIMError(w, scim.NewError(http.StatusNotFound, "", "unknown SCIM endpoint %s", r.URL.Path))
+	}
+}
+
+func (s *Server) handleSCIMUsers(w http.ResponseWriter, r *http.Request, service *scim.Service, id string) {
+	var (
+		result interface{}
+		status = http.StatusOK
+		err    error
+	)
+
+	switch {
+	case r.Method == "GET" && id == "":
+		startIndex, count := scimPaging(r)
+		result, err = service.ListUsers(r.URL.Query().Get("filter"), startIndex, count)
+	case r.Method == "GET":
+		result, err = service.GetUser(id)
+	case r.Method == "POST" && id == "":
+		var user scim.User
+		if err = decodeSCIM(r, &user); err == nil {
+			result, err = service.CreateUser(user)
+			status = http.StatusCreated
+		}
+	case r.Method == "PUT" && id != "":
+		var user scim.User
+		if err = decodeSCIM(r, &user); err == nil {
+			result, err = service.ReplaceUser(id, user)
+		}
+	case r.Method == "PATCH" && id != "":
+		var patch scim.PatchRequest
+		if err = decodeSCIM(r, &patch); err == nil {
+			result, err = service.PatchUser(id, patch)
+		}
+	case r.Method == "DELETE" && id != "":
+		if err = service.DeleteUser(id); err == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	default:
+		err = scim.NewError(http.StatusMethodNotAllowed, "", "method %s not allowed", r.Method)
+	}
+
+	if err != nil {
+		s.writeSCIMFailure(w, r, err)
+		return
+	}
+	writeSCIM(w, status, result)
+}
+
+func (s *Server) handleSCIMGroups(w http.ResponseWriter, r *http.Request, service *scim.Service, id string) {
+	var (
+		result interface{}
+		status = http.StatusOK
+		err    error
+	)
+
+	switch {
+	case r.Method == "GET" && id == "":
+		startIndex, count := scimPaging(r)
+		result, err = service.ListGroups(r.URL.Query().Get("filter"), startIndex, count)
+	case r.Method == "GET":
+		result, err = service.GetGroup(id)
+	case r.Method == "POST" && id == "":
+		var group scim.Group
+		if err = decodeSCIM(r, &group); err == nil {
+			result, err = service.CreateGroup(group)
+			status = http.StatusCreated
+		}
+	case r.Method == "PUT" && id != "":
+		var group scim.Group
+		if err = decodeSCIM(r, &group); err == nil {
+			result, err = service.ReplaceGroup(id, group)
+		}
+	case r.Method == "PATCH" && id != "":
+		var patch scim.PatchRequest
+		if err = decodeSCIM(r, &patch); err == nil {
+			result, err = service.PatchGroup(id, patch)
+		}
+	case r.Method == "DELETE" && id != "":
+		if err = service.DeleteGroup(id); err == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	default:
+		err = scim.NewError(http.StatusMethodNotAllowed, "", "method %s not allowed", r.Method)
+	}
+
+	if err != nil {
+		s.writeSCIMFailure(w, r, err)
+		return
+	}
+	writeSCIM(w, status, result)
+}
+
+// scimPaging reads startIndex and count; a missing count returns the maximum page size
+func scimPaging(r *http.Request) (int, int) {
+	startIndex, err := strconv.Atoi(r.URL.Query().Get("startIndex"))
+	if err != nil {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil {
+		count = -1
+	}
+	return startIndex, count
+}
+
+func decodeSCIM(r *http.Request, target interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(target); err != nil {
+		return scim.NewError(http.StatusBadRequest, "invalidSyntax", "invalid JSON: %v", err)
+	}
+	return nil
+}
+
+func (s *Server) writeSCIMFailure(w http.ResponseWriter, r *http.Request, err error) {
+	response := scim.ErrorResponse(err)
+	if response.StatusCode() == http.StatusInternalServerError {
+		fmt.Fprintf(os.Stderr, "SCIM %s %s failed: %v\n", r.Method, r.URL.Path, err)
+	}
+	writeSCIMError(w, response)
+}
+
+func writeSCIMError(w http.ResponseWriter, err *scim.Error) {
+	writeSCIM(w, err.StatusCode(), err)
+}
+
+func writeSCIM(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", scim.ContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
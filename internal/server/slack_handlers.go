@@ -0,0 +1,322 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/slack"
+	"innominatus/internal/users"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maxSlackRequestBytes caps the body of Slack requests, which is read before it is verified
+const maxSlackRequestBytes = 64 << 10
+
+// slackConfig returns the slack section of admin-config.yaml
+func (s *Server) slackConfig() slack.Config {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return slack.Config{}
+	}
+	return adminConfig.Slack
+}
+
+// HandleSlackCommands handles POST /api/integrations/slack/commands, the request URL of
+// the /innominatus slash command. Requests are authenticated by their Slack signature.
+func (s *Server) HandleSlackCommands(w http.ResponseWriter, r *http.Request) {
+	s.handleSlackCommand(w, r, s.slackConfig())
+}
+
+// HandleSlackInteractions handles POST /api/integrations/slack/interactions, the request
+// URL of the app's interactive components (the approval buttons)
+func (s *Server) HandleSlackInteractions(w http.ResponseWriter, r *http.Request) {
+	s.handleSlackInteraction(w, r, s.slackConfig())
+}
+
+// readSlackRequest verifies the signature of a Slack request and returns its form values.
+// It writes the error response and returns nil if the request is rejected.
+func (s *Server) readSlackRequest(w http.ResponseWriter, r *http.Request, config slack.Config) url.Values {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return nil
+	}
+	if !config.Enabled() {
+		http.Error(w, "Slack integration is not configured", http.StatusNotFound)
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSlackRequestBytes))
+	if err != nil {
+		http.Error(w, "Failed to read request", http.StatusBadRequest)
+		return nil
+	}
+	if err := slack.Verify(r.Header, body, config.SigningSecret(), s.Clock().Now()); err != nil {
+		logging.FromContext(r.Context(), "server").WarnWithFields("Rejected Slack request", map[string]interface{}{
+			"error": err.Error(),
+		})
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "Invalid form body", http.StatusBadRequest)
+		return nil
+	}
+	return form
+}
+
+// slackUser returns the innominatus user a Slack user is mapped to, or nil
+func (s *Server) slackUser(config slack.Config, slackUserID string) *users.User {
+	username, ok := config.Username(slackUserID)
+	if !ok {
+		return nil
+	}
+	store, err := users.LoadUsers()
+	if err != nil {
+		return nil
+	}
+	user, err := store.GetUser(username)
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+func (s *Server) handleSlackCommand(w http.ResponseWriter, r *http.Request, config slack.Config) {
+	form := s.readSlackRequest(w, r, config)
+	if form == nil {
+		return
+	}
+
+	slackUserID := form.Get("user_id")
+	user := s.slackUser(config, slackUserID)
+	if user == nil {
+		writeSlackJSON(w, slack.Reply(fmt.Sprintf(
+			"Your Slack account (%s) is not linked to an innominatus user. Ask a platform admin to add it to slack.users in admin-config.yaml.", slackUserID)))
+		return
+	}
+
+	command := slack.ParseCommand(form.Get("text"))
+	var message slack.Message
+	switch command.Name {
+	case "status":
+		if len(command.Args) != 1 {
+			message = slack.Reply("Usage: `/innominatus status <app>`")
+			break
+		}
+		message = s.slackApplicationStatus(user, command.Args[0])
+	case "approvals":
+		appName := ""
+		if len(command.Args) > 0 {
+			appName = command.Args[0]
+		}
+		message = s.slackPendingApprovals(user, appName)
+	case "help":
+		message = slack.Reply(slack.Usage)
+	default:
+		message = slack.Reply(fmt.Sprintf("Unknown command `%s`.\n\n%s", command.Name, slack.Usage))
+	}
+
+	logging.FromContext(r.Context(), "server").InfoWithFields("Handled Slack command", map[string]interface{}{
+		"command":  command.Name,
+		"username": user.Username,
+	})
+	writeSlackJSON(w, message)
+}
+
+// slackApplicationStatus replies to "status <app>" with the application status
+func (s *Server) slackApplicationStatus(user *users.User, appName string) slack.Message {
+	if s.db == nil {
+		return slack.Reply("innominatus has no database connection.")
+	}
+	app, err := s.db.GetApplication(appName)
+	if err != nil || app == nil || !s.canAccessTeam(user, app.Team) {
+		return slack.Reply(fmt.Sprintf("Application `%s` not found.", appName))
+	}
+	sources, err := s.loadApplicationStatusSources(app)
+	if err != nil {
+		return slack.Reply(fmt.Sprintf("Failed to build the status of `%s`: %v", appName, err))
+	}
+	status := buildApplicationStatus(sources, s.Clock().Now())
+
+	summary := fmt.Sprintf("*%s* (team %s) is *%s*", status.Name, status.Team, status.Health.Status)
+	blocks := []slack.Block{slack.Section(summary)}
+
+	if deployment := status.LatestDeployment; deployment != nil {
+		text := fmt.Sprintf("Last deployment: %s by %s, %s", deployment.DeployedAt.Format("2006-01-02 15:04 MST"), deployment.DeployedBy, deployment.Status)
+		if deployment.Workflow != nil {
+			text += fmt.Sprintf(" (workflow %s #%d)", deployment.Workflow.WorkflowName, deployment.Workflow.ID)
+		}
+		blocks = append(blocks, slack.Context(text))
+	}
+
+	if len(status.Resources) > 0 {
+		lines := make([]string, 0, len(status.Resources))
+		for _, resource := range status.Resources {
+			line := fmt.Sprintf("• `%s` (%s): %s", resource.Name, resource.Type, resource.State)
+			if resource.ErrorMessage != "" {
+				line += " – " + resource.ErrorMessage
+			}
+			lines = append(lines, line)
+		}
+		blocks = append(blocks, slack.Section(fmt.Sprintf("*Resources* (%d of %d healthy)\n%s",
+			status.Health.Healthy, status.Health.Resources, strings.Join(lines, "\n"))))
+	}
+
+	for _, workflow := range status.ActiveWorkflows {
+		blocks = append(blocks, slack.Context(fmt.Sprintf("Running: %s #%d, step %d of %d",
+			workflow.WorkflowName, workflow.ID, workflow.CompletedSteps+1, workflow.TotalSteps)))
+	}
+
+	for _, approval := range status.PendingApprovals {
+		blocks = append(blocks, slackApprovalBlocks(approval, s.canManageApplication(user, approval.ApplicationName))...)
+	}
+
+	return slack.Message{ResponseType: "ephemeral", Text: summary, Blocks: blocks}
+}
+
+// slackPendingApprovals replies to "approvals [app]" with the pending approvals the user can decide
+func (s *Server) slackPendingApprovals(user *users.User, appName string) slack.Message {
+	if s.db == nil {
+		return slack.Reply("innominatus has no database connection.")
+	}
+	approvals, err := s.db.ListWorkflowApprovals(database.ApprovalStatusPending)
+	if err != nil {
+		return slack.Reply(fmt.Sprintf("Failed to list approvals: %v", err))
+	}
+
+	var blocks []slack.Block
+	count := 0
+	for _, approval := range approvals {
+		if appName != "" && approval.ApplicationName != appName {
+			continue
+		}
+		if !s.canManageApplication(user, approval.ApplicationName) {
+			continue
+		}
+		blocks = append(blocks, slackApprovalBlocks(approval, true)...)
+		count++
+	}
+	if count == 0 {
+		return slack.Reply("No pending approvals.")
+	}
+
+	text := fmt.Sprintf("%d pending approval(s)", count)
+	return slack.Message{ResponseType: "ephemeral", Text: text, Blocks: append([]slack.Block{slack.Section("*" + text + "*")}, blocks...)}
+}
+
+// slackApprovalBlocks describes an approval, with buttons if the user can decide it
+func slackApprovalBlocks(approval *database.WorkflowApproval, decidable bool) []slack.Block {
+	text := fmt.Sprintf("*Approval #%d* – %s, step `%s`", approval.ID, approval.ApplicationName, approval.StepName)
+	if approval.Environment != "" {
+		text += fmt.Sprintf(" in %s", approval.Environment)
+	}
+	if approval.Summary != "" {
+		text += "\n" + approval.Summary
+	}
+	blocks := []slack.Block{slack.Section(text)}
+	if decidable {
+		blocks = append(blocks, slack.ApprovalActions(approval.ID))
+	}
+	return blocks
+}
+
+func (s *Server) handleSlackInteraction(w http.ResponseWriter, r *http.Request, config slack.Config) {
+	form := s.readSlackRequest(w, r, config)
+	if form == nil {
+		return
+	}
+	interaction, err := slack.ParseInteraction(form)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Slack only needs an acknowledgement; the outcome replaces the original message
+	// through the response_url
+	w.WriteHeader(http.StatusOK)
+	if interaction.Type != "block_actions" {
+		return
+	}
+
+	message := s.decideSlackApproval(r.Context(), config, interaction)
+	if interaction.ResponseURL == "" {
+		return
+	}
+	go func() {
+		if err := slack.Respond(context.Background(), interaction.ResponseURL, message); err != nil {
+			logging.NewStructuredLogger("server").WarnWithFields("Failed to update Slack message", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}()
+}
+
+// decideSlackApproval approves or rejects the approval of a clicked button as the mapped
+// innominatus user, with the same permission check as the approvals API
+func (s *Server) decideSlackApproval(ctx context.Context, config slack.Config, interaction *slack.Interaction) slack.Message {
+	reply := slack.Reply
+
+	user := s.slackUser(config, interaction.User.ID)
+	if user == nil {
+		return reply("Your Slack account is not linked to an innominatus user.")
+	}
+
+	for _, action := range interaction.Actions {
+		var status string
+		switch action.ActionID {
+		case slack.ActionApprove:
+			status = database.ApprovalStatusApproved
+		case slack.ActionReject:
+			status = database.ApprovalStatusRejected
+		default:
+			continue
+		}
+
+		id, err := strconv.ParseInt(action.Value, 10, 64)
+		if err != nil {
+			return reply("Invalid approval ID.")
+		}
+		if s.db == nil {
+			return reply("innominatus has no database connection.")
+		}
+		approval, err := s.db.GetWorkflowApproval(id)
+		if err != nil {
+			return reply(fmt.Sprintf("Approval #%d not found.", id))
+		}
+		if !s.canManageApplication(user, approval.ApplicationName) {
+			return reply(fmt.Sprintf("Only the team owning %s or an admin can decide approval #%d.", approval.ApplicationName, id))
+		}
+		if err := s.db.DecideWorkflowApproval(id, status, user.Username, "via Slack"); err != nil {
+			return reply(fmt.Sprintf("Approval #%d: %v", id, err))
+		}
+
+		logging.FromContext(ctx, "server").InfoWithFields("Decided approval from Slack", map[string]interface{}{
+			"approval_id": id,
+			"status":      status,
+			"username":    user.Username,
+		})
+		text := fmt.Sprintf("Approval #%d (%s, step `%s`) was *%s* by %s.", id, approval.ApplicationName, approval.StepName, status, user.Username)
+		message := slack.Reply(text)
+		message.ReplaceOriginal = true
+		return message
+	}
+	return reply("Unknown action.")
+}
+
+// writeSlackJSON writes a Slack message as the response of a command
+func writeSlackJSON(w http.ResponseWriter, message slack.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(message); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// overlayFormField is the multipart field holding an environment overlay of the Score spec
+const overlayFormField = "overlay"
+
+// mergeSpecOverlay merges an environment overlay into a base Score spec:
+//
+//   - mappings are merged key by key, recursively
+//   - scalars and lists of the overlay replace those of the base
+//   - a key set to null in the overlay removes the key from the base
+//   - metadata.name cannot be changed by an overlay
+//
+// Keys keep their order and comments from the base; keys only in the overlay are appended.
+func mergeSpecOverlay(base, overlay []byte) ([]byte, error) {
+	var baseDoc, overlayDoc yaml.Node
+	if err := yaml.Unmarshal(base, &baseDoc); err != nil {
+		return nil, fmt.Errorf("error parsing base spec: %w", err)
+	}
+	if err := yaml.Unmarshal(overlay, &overlayDoc); err != nil {
+		return nil, fmt.Errorf("error parsing overlay: %w", err)
+	}
+	baseRoot, overlayRoot := documentRoot(&baseDoc), documentRoot(&overlayDoc)
+	if baseRoot == nil || baseRoot.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("base spec must be a YAML mapping")
+	}
+	if overlayRoot == nil {
+		return base, nil // An empty overlay changes nothing
+	}
+	if overlayRoot.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("overlay must be a YAML mapping")
+	}
+
+	if name := mappingScalar(overlayRoot, "metadata", "name"); name != "" && name != mappingScalar(baseRoot, "metadata", "name") {
+		return nil, fmt.Errorf("overlay cannot change metadata.name to '%s'", name)
+	}
+
+	mergeNodes(baseRoot, overlayRoot)
+
+	var merged bytes.Buffer
+	encoder := yaml.NewEncoder(&merged)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&baseDoc); err != nil {
+		return nil, fmt.Errorf("error encoding merged spec: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("error encoding merged spec: %w", err)
+	}
+	return merged.Bytes(), nil
+}
+
+// mergeNodes merges the keys of the mapping overlay into the mapping base
+func mergeNodes(base, overlay *yaml.Node) {
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, value := overlay.Content[i], overlay.Content[i+1]
+		index := mappingIndex(base, key.Value)
+
+		switch {
+		case value.ShortTag() == "!!null":
+			if index >= 0 {
+				base.Content = append(base.Content[:index], base.Content[index+2:]...)
+			}
+		case index < 0:
+			base.Content = append(base.Content, key, value)
+		case value.Kind == yaml.MappingNode && base.Content[index+1].Kind == yaml.MappingNode:
+			mergeNodes(base.Content[index+1], value)
+		default:
+			base.Content[index+1] = value
+		}
+	}
+}
+
+// documentRoot returns the top-level node of a parsed document, nil when it is empty
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return nil
+}
+
+// mappingIndex returns the index of key in mapping, -1 when it is missing
+func mappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingScalar returns the scalar at path in mapping, empty when it is missing
+func mappingScalar(mapping *yaml.Node, path ...string) string {
+	node := mapping
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return ""
+		}
+		index := mappingIndex(node, key)
+		if index < 0 {
+			return ""
+		}
+		node = node.Content[index+1]
+	}
+	if node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"regexp"
+
+	"innominatus/internal/database"
+	"innominatus/internal/provenance"
+)
+
+const (
+	// maxUploadFileSize caps each file uploaded with a Score spec
+	maxUploadFileSize = 1 << 20
+	// maxUploadFiles caps the number of files uploaded with a Score spec
+	maxUploadFiles = 20
+	// specFormField is the multipart field holding the Score spec
+	specFormField = "spec"
+)
+
+// uploadFileNamePattern restricts uploaded file names to what workflows can reference as
+// ${files.<name>}, e.g. values.yaml or .env.template
+var uploadFileNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// uploadedFile is an auxiliary file of a multipart deploy request
+type uploadedFile struct {
+	name    string
+	content []byte
+}
+
+// readDeployRequest returns the Score spec of a deploy request. Multipart requests carry
+// the spec in the "spec" field and may add auxiliary files (values.yaml, .env templates)
+// as further file parts. An "overlay" field is merged into the spec, see mergeSpecOverlay.
+// isMultipart tells the caller to replace the stored files, even with none. Any other
+// request body is the raw spec YAML.
+func readDeployRequest(r *http.Request) (spec []byte, files []uploadedFile, isMultipart bool, err error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		spec, err = io.ReadAll(r.Body)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("error reading request body")
+		}
+		return spec, nil, false, nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, nil, true, fmt.Errorf("invalid multipart request: %w", err)
+	}
+
+	var overlay []byte
+	seen := map[string]bool{}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, true, fmt.Errorf("invalid multipart request: %w", err)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, maxUploadFileSize+1))
+		_ = part.Close()
+		if err != nil {
+			return nil, nil, true, fmt.Errorf("error reading part '%s': %w", part.FormName(), err)
+		}
+
+		if part.FormName() == specFormField {
+			spec = data
+			continue
+		}
+		if part.FormName() == overlayFormField {
+			overlay = data
+			continue
+		}
+		if part.FileName() == "" {
+			continue // Plain form fields other than the spec are ignored
+		}
+
+		name := filepath.Base(part.FileName())
+		switch {
+		case name == "." || name == ".." || !uploadFileNamePattern.MatchString(name):
+			return nil, nil, true, fmt.Errorf("invalid file name '%s': use letters, digits, '.', '_' and '-'", part.FileName())
+		case seen[name]:
+			return nil, nil, true, fmt.Errorf("file '%s' is uploaded more than once", name)
+		case len(data) > maxUploadFileSize:
+			return nil, nil, true, fmt.Errorf("file '%s' exceeds %d bytes", name, maxUploadFileSize)
+		case len(files) == maxUploadFiles:
+			return nil, nil, true, fmt.Errorf("at most %d files can be uploaded with a spec", maxUploadFiles)
+		}
+		seen[name] = true
+		files = append(files, uploadedFile{name: name, content: data})
+	}
+
+	if len(spec) == 0 {
+		return nil, nil, true, fmt.Errorf("multipart request has no '%s' field", specFormField)
+	}
+	if overlay != nil {
+		if spec, err = mergeSpecOverlay(spec, overlay); err != nil {
+			return nil, nil, true, fmt.Errorf("invalid overlay: %w", err)
+		}
+	}
+	return spec, files, true, nil
+}
+
+// applicationFiles converts uploaded files to the records stored with the spec revision
+func applicationFiles(files []uploadedFile, spec []byte, uploadedBy string) []*database.ApplicationFile {
+	specDigest := provenance.Digest(spec)
+	records := make([]*database.ApplicationFile, 0, len(files))
+	for _, file := range files {
+		records = append(records, &database.ApplicationFile{
+			Name:       file.name,
+			Content:    string(file.content),
+			Digest:     provenance.Digest(file.content),
+			SpecDigest: specDigest,
+			UploadedBy: uploadedBy,
+		})
+	}
+	return records
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/logging"
+	"net/http"
+	"os"
+)
+
+// HandleStepCache lists cached step results (GET) or invalidates them (DELETE ?app=&key=). Admin only.
+func (s *Server) HandleStepCache(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), "server")
+
+	if s.workflowExecutor == nil || s.workflowExecutor.StepCache() == nil {
+		http.Error(w, "Step cache is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+	cache := s.workflowExecutor.StepCache()
+
+	switch r.Method {
+	case "GET":
+		entries := cache.List()
+		if app := r.URL.Query().Get("app"); app != "" {
+			filtered := entries[:0]
+			for _, entry := range entries {
+				if entry.AppName == app {
+					filtered = append(filtered, entry)
+				}
+			}
+			entries = filtered
+		}
+
+		response := map[string]interface{}{
+			"entries": entries,
+			"count":   len(entries),
+			"stats":   cache.Stats(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+		}
+
+	case "DELETE":
+		app := r.URL.Query().Get("app")
+		key := r.URL.Query().Get("key")
+		removed, err := cache.Invalidate(app, key)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to invalidate step cache: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		logger.Infof("Invalidated %d step cache entries (app=%q key=%q)", removed, app, key)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{"removed": removed}); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/usage"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HandleTeamUsage serves the usage panel of a team: resources by type, running
+// workflows, storage, quotas and a daily trend.
+//
+// GET /api/teams/{id}/usage?days=14
+//
+// Members of the team, admins of its organization and platform admins may read it.
+func (s *Server) HandleTeamUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	team := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/teams/"), "/usage")
+	if team == "" || strings.Contains(team, "/") {
+		http.Error(w, "Team ID required", http.StatusBadRequest)
+		return
+	}
+	if !s.canAccessTeam(user, team) {
+		http.Error(w, "Forbidden: not a member of this team", http.StatusForbidden)
+		return
+	}
+
+	days := usage.DefaultTrendDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > usage.MaxTrendDays {
+			http.Error(w, fmt.Sprintf("days must be between 1 and %d", usage.MaxTrendDays), http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	if s.db == nil {
+		http.Error(w, "Database not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	now := s.Clock().Now()
+	sources, err := s.loadTeamUsageSources(team, usage.TrendStart(now, days))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to compute usage: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(usage.NewTeamUsage(sources, now, days)); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// loadTeamUsageSources reads the applications, resources, workflows and storage of a team
+func (s *Server) loadTeamUsageSources(team string, since time.Time) (*usage.TeamSources, error) {
+	apps, err := s.db.ListApplicationsByTeam(team)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+
+	sources := &usage.TeamSources{
+		Team:         team,
+		Applications: apps,
+		Settings:     s.organizations().Settings(team),
+	}
+	names := make([]string, 0, len(apps))
+	owned := make(map[string]bool, len(apps))
+	for _, app := range apps {
+		names = append(names, app.Name)
+		owned[app.Name] = true
+
+		files, err := s.db.ListApplicationFiles(app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files of %s: %w", app.Name, err)
+		}
+		for _, file := range files {
+			sources.Storage.FilesBytes += int64(file.Size)
+		}
+		if size, err := s.applicationWorkspaces().Size(app.Name); err == nil {
+			sources.Storage.WorkspaceBytes += size
+		}
+	}
+
+	if repo := s.GetResourceRepository(); repo != nil {
+		for _, app := range apps {
+			resources, err := repo.ListResourceInstances(app.Name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list resources of %s: %w", app.Name, err)
+			}
+			sources.Resources = append(sources.Resources, resources...)
+		}
+
+		lifecycles, err := repo.ListResourceLifecycles(s.Clock().Now())
+		if err != nil {
+			return nil, err
+		}
+		for _, lifecycle := range lifecycles {
+			if owned[lifecycle.Resource.ApplicationName] {
+				sources.Lifecycles = append(sources.Lifecycles, lifecycle)
+			}
+		}
+	}
+
+	if s.workflowRepo != nil {
+		running, err := s.workflowRepo.ListWorkflowExecutions("", "", database.WorkflowStatusRunning, overviewWorkflowLimit, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list running workflows: %w", err)
+		}
+		for _, execution := range running {
+			if owned[execution.ApplicationName] {
+				sources.ActiveWorkflows = append(sources.ActiveWorkflows, execution)
+			}
+		}
+
+		if sources.WorkflowRuns, err = s.workflowRepo.WorkflowRunsPerDay(names, since); err != nil {
+			return nil, err
+		}
+		if sources.Storage.LogsBytes, err = s.workflowRepo.StepLogBytes(names); err != nil {
+			return nil, err
+		}
+	}
+
+	return sources, nil
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/logging"
+	"innominatus/internal/tempassets"
+	"net/http"
+	"os"
+)
+
+// tempAssetsConfig returns the tempAssets settings of admin-config.yaml
+func (s *Server) tempAssetsConfig() tempassets.Config {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return tempassets.Config{}
+	}
+	return adminConfig.TempAssets
+}
+
+// tempAssetCollector returns a collector for the temp directory with the configured
+// retention. Without a database only untracked assets are found.
+func (s *Server) tempAssetCollector() (*tempassets.Collector, error) {
+	retention, _, err := s.tempAssetsConfig().Durations()
+	if err != nil {
+		return nil, err
+	}
+	var store tempassets.Store
+	if s.workflowRepo != nil {
+		store = s.workflowRepo
+	}
+	collector := tempassets.NewCollector(store, os.TempDir(), retention)
+	collector.SetClock(s.Clock())
+	return collector, nil
+}
+
+// trackTempAsset records a temp path written by a step that runs without a workflow
+// execution, so it is collected once its retention ends
+func (s *Server) trackTempAsset(path, appName, stepName string) {
+	if s.workflowRepo == nil {
+		return
+	}
+	if err := s.workflowRepo.RecordTempAsset(path, nil, appName, stepName); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to track temp asset %s: %v\n", path, err)
+	}
+}
+
+// HandleAdminTempAssets reports the temp assets workflow steps left behind and the space
+// that can be reclaimed (GET), or removes the reclaimable ones now (DELETE).
+func (s *Server) HandleAdminTempAssets(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context(), "server")
+
+	if r.Method != "GET" && r.Method != "DELETE" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	collector, err := s.tempAssetCollector()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var report *tempassets.Report
+	if r.Method == "GET" {
+		report, err = collector.Scan()
+	} else {
+		report, err = collector.Collect()
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to collect temp assets: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if r.Method == "DELETE" {
+		logger.Infof("Removed %d temp assets (%d bytes)", report.Removed, report.RemovedBytes)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// StartTempAssetGC removes temp assets whose retention ended every tempAssets.interval
+func (s *Server) StartTempAssetGC(ctx context.Context) {
+	logger := logging.NewStructuredLogger("server")
+	_, interval, err := s.tempAssetsConfig().Durations()
+	if err != nil {
+		logger.Warnf("Temp asset collection disabled: %v", err)
+		return
+	}
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := s.Clock().NewTicker("temp-asset-gc", interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
+			collector, err := s.tempAssetCollector()
+			if err != nil {
+				logger.Warnf("Temp asset collection skipped: %v", err)
+				continue
+			}
+			report, err := collector.Collect()
+			if err != nil {
+				logger.Warnf("Temp asset collection failed: %v", err)
+				continue
+			}
+			if report.Removed > 0 || len(report.Errors) > 0 {
+				logger.InfoWithFields("Collected temp assets", map[string]interface{}{
+					"removed":       report.Removed,
+					"removed_bytes": report.RemovedBytes,
+					"errors":        len(report.Errors),
+				})
+			}
+		}
+	}()
+}
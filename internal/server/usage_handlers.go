@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/logging"
+	"innominatus/internal/usage"
+	"net/http"
+	"os"
+)
+
+// HandleAdminUsage reports resource usage for chargeback. GET returns the records of
+// ?month=YYYY-MM as JSON, or as CSV with ?format=csv. POST pushes the report to the
+// billing webhook configured in admin-config.yaml.
+func (s *Server) HandleAdminUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	month := r.URL.Query().Get("month")
+	if month == "" {
+		http.Error(w, "month query parameter is required (YYYY-MM)", http.StatusBadRequest)
+		return
+	}
+	_, end, err := usage.ParseMonth(month)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, fmt.Sprintf("unsupported format '%s' (supported: json, csv)", format), http.StatusBadRequest)
+		return
+	}
+
+	repo := s.GetResourceRepository()
+	if repo == nil {
+		http.Error(w, "Usage reporting requires a database", http.StatusServiceUnavailable)
+		return
+	}
+
+	var config usage.Config
+	if adminConfig, err := admin.LoadAdminConfig("admin-config.yaml"); err == nil {
+		config = adminConfig.Usage
+	}
+
+	lifecycles, err := repo.ListResourceLifecycles(end)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	report, err := usage.NewReport(month, lifecycles, config.SizeKeys, s.Clock().Now().UTC())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	report.RollupByOrganization(s.organizations().OrgOf)
+
+	if r.Method == "POST" {
+		if config.WebhookURL == "" {
+			http.Error(w, "No usage webhook configured (usage.webhookURL in admin-config.yaml)", http.StatusConflict)
+			return
+		}
+		if err := usage.Push(r.Context(), config, report); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		logging.FromContext(r.Context(), "server").InfoWithFields("Pushed usage report", map[string]interface{}{
+			"month":   month,
+			"records": len(report.Records),
+		})
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=usage-%s.csv", month))
+		if err := report.WriteCSV(w); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write usage csv: %v\n", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
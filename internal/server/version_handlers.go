@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// CLIVersionHeader carries the innominatus-ctl version on every CLI request
+const CLIVersionHeader = "X-Innominatus-CLI-Version"
+
+// BuildInfo identifies the running server build
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+// CLIVersionPolicy defines which innominatus-ctl versions the server supports. Empty
+// versions disable the corresponding check.
+type CLIVersionPolicy struct {
+	MinVersion         string `json:"min_version,omitempty"`
+	RecommendedVersion string `json:"recommended_version,omitempty"`
+}
+
+// CLICompatibility is the server's verdict on the calling CLI version
+type CLICompatibility struct {
+	CLIVersionPolicy
+	ClientVersion string   `json:"client_version,omitempty"`
+	Compatible    bool     `json:"compatible"`
+	Warnings      []string `json:"warnings,omitempty"`
+}
+
+// VersionResponse is returned by /api/version
+type VersionResponse struct {
+	BuildInfo
+	CLI CLICompatibility `json:"cli"`
+}
+
+// SetBuildInfo sets the build information reported by /api/version and /health
+func (s *Server) SetBuildInfo(version, commit, buildDate string) {
+	s.buildInfo = BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+	}
+	s.healthChecker.SetVersion(version)
+}
+
+// SetCLIVersionPolicy sets the minimum and recommended innominatus-ctl versions
+func (s *Server) SetCLIVersionPolicy(policy CLIVersionPolicy) {
+	s.cliPolicy = policy
+}
+
+// HandleVersion reports the server build and negotiates CLI compatibility. The CLI
+// sends its version in the X-Innominatus-CLI-Version header (or ?cli_version=) and
+// refuses to run when compatible is false. Unauthenticated, so version checks work
+// before login.
+func (s *Server) HandleVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientVersion := r.Header.Get(CLIVersionHeader)
+	if clientVersion == "" {
+		clientVersion = r.URL.Query().Get("cli_version")
+	}
+
+	buildInfo := s.buildInfo
+	if buildInfo.Version == "" {
+		buildInfo.Version = "dev"
+		buildInfo.GoVersion = runtime.Version()
+	}
+
+	response := VersionResponse{
+		BuildInfo: buildInfo,
+		CLI:       checkCLICompatibility(s.cliPolicy, buildInfo.Version, clientVersion),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// checkCLICompatibility compares a CLI version against the policy and the server
+// version. Development builds and unparseable versions are always compatible, so
+// only release builds are ever refused.
+func checkCLICompatibility(policy CLIVersionPolicy, serverVersion, clientVersion string) CLICompatibility {
+	result := CLICompatibility{
+		CLIVersionPolicy: policy,
+		ClientVersion:    clientVersion,
+		Compatible:       true,
+	}
+
+	client := parseReleaseVersion(clientVersion)
+	if client == nil {
+		return result
+	}
+
+	if minVersion := parseReleaseVersion(policy.MinVersion); minVersion != nil && client.LessThan(minVersion) {
+		result.Compatible = false
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"innominatus-ctl %s is no longer supported by this server; upgrade to %s or later", clientVersion, policy.MinVersion))
+		return result
+	}
+
+	if recommended := parseReleaseVersion(policy.RecommendedVersion); recommended != nil && client.LessThan(recommended) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"innominatus-ctl %s is deprecated and will stop working in a future server release; upgrade to %s or later", clientVersion, policy.RecommendedVersion))
+	}
+
+	if server := parseReleaseVersion(serverVersion); server != nil &&
+		(client.Major() > server.Major() || (client.Major() == server.Major() && client.Minor() > server.Minor())) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"innominatus-ctl %s is newer than the server (%s); commands added since %s may fail", clientVersion, serverVersion, serverVersion))
+	}
+
+	return result
+}
+
+// parseReleaseVersion parses a semantic version, returning nil for development builds
+// such as "dev" and for invalid versions
+func parseReleaseVersion(version string) *semver.Version {
+	if version == "" || version == "dev" || version == "unknown" || strings.HasPrefix(version, "dev-") {
+		return nil
+	}
+	parsed, err := semver.NewVersion(version)
+	if err != nil {
+		return nil
+	}
+	return parsed
+}
@@ -0,0 +1,391 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCompareLogLine caps the log lines quoted in a comparison
+const maxCompareLogLine = 200
+
+// WorkflowComparison lists the differences between two executions of the same workflow
+type WorkflowComparison struct {
+	WorkflowName    string                    `json:"workflow_name"`
+	A               ComparedExecution         `json:"a"`
+	B               ComparedExecution         `json:"b"`
+	DurationDeltaMs *int64                    `json:"duration_delta_ms,omitempty"` // B minus A; nil while either run is unfinished
+	Parameters      []ValueChange             `json:"parameters"`                  // Stored parameters that differ
+	Steps           []StepComparison          `json:"steps"`
+	Summary         WorkflowComparisonSummary `json:"summary"`
+}
+
+// ComparedExecution identifies one side of a comparison
+type ComparedExecution struct {
+	ID              int64      `json:"id"`
+	ApplicationName string     `json:"application_name"`
+	Status          string     `json:"status"`
+	StartedAt       time.Time  `json:"started_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	DurationMs      *int64     `json:"duration_ms,omitempty"`
+	ErrorMessage    *string    `json:"error_message,omitempty"`
+}
+
+// ValueChange is a parameter or configuration key whose value differs; a side is nil
+// when the key is missing there
+type ValueChange struct {
+	Key string      `json:"key"`
+	A   interface{} `json:"a"`
+	B   interface{} `json:"b"`
+}
+
+// StepComparison compares a step by name across both executions
+type StepComparison struct {
+	StepName        string        `json:"step_name"`
+	StepType        string        `json:"step_type"`
+	Presence        string        `json:"presence"` // both, only_a or only_b
+	StatusA         string        `json:"status_a,omitempty"`
+	StatusB         string        `json:"status_b,omitempty"`
+	StatusChanged   bool          `json:"status_changed"`
+	DurationAMs     *int64        `json:"duration_a_ms,omitempty"`
+	DurationBMs     *int64        `json:"duration_b_ms,omitempty"`
+	DurationDeltaMs *int64        `json:"duration_delta_ms,omitempty"`
+	ErrorA          *string       `json:"error_a,omitempty"`
+	ErrorB          *string       `json:"error_b,omitempty"`
+	ConfigChanges   []ValueChange `json:"config_changes"`
+	Logs            *LogDiff      `json:"logs,omitempty"` // nil when neither step stored logs
+}
+
+// LogDiff summarizes how the output logs of a step differ. Lines are compared as
+// multisets, so reordered lines count as unchanged.
+type LogDiff struct {
+	LinesA          int      `json:"lines_a"`
+	LinesB          int      `json:"lines_b"`
+	Added           int      `json:"added"`   // Lines only in B
+	Removed         int      `json:"removed"` // Lines only in A
+	FirstDifference *LogLine `json:"first_difference,omitempty"`
+}
+
+// LogLine is the first line, counted from 1, at which the logs differ
+type LogLine struct {
+	Line int    `json:"line"`
+	A    string `json:"a"`
+	B    string `json:"b"`
+}
+
+// WorkflowComparisonSummary points at the likely cause of a regression
+type WorkflowComparisonSummary struct {
+	StepsChanged       int    `json:"steps_changed"`
+	StatusChanges      int    `json:"status_changes"`
+	ConfigChanges      int    `json:"config_changes"`
+	FirstDivergentStep string `json:"first_divergent_step,omitempty"` // First step whose status or configuration differs
+	SlowestRegression  string `json:"slowest_regression,omitempty"`   // Step that got slower by the most
+}
+
+// Step presence values
+const (
+	presenceBoth  = "both"
+	presenceOnlyA = "only_a"
+	presenceOnlyB = "only_b"
+)
+
+// handleCompareWorkflows handles GET /api/workflows/compare?a=<id>&b=<id>
+func (s *Server) handleCompareWorkflows(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.workflowExecutor == nil {
+		http.Error(w, "Workflow comparison requires database connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var ids [2]int64
+	for i, name := range []string{"a", "b"} {
+		id, err := strconv.ParseInt(r.URL.Query().Get(name), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Query parameter '%s' must be a workflow execution ID", name), http.StatusBadRequest)
+			return
+		}
+		ids[i] = id
+	}
+
+	var executions [2]*database.WorkflowExecution
+	for i, id := range ids {
+		execution, err := s.workflowExecutor.GetWorkflowExecution(id)
+		if err != nil {
+			if err.Error() == "workflow execution not found" {
+				http.Error(w, fmt.Sprintf("Workflow %d not found", id), http.StatusNotFound)
+				return
+			}
+			http.Error(w, fmt.Sprintf("Failed to get workflow: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if _, ok := s.authorizeWorkflowRun(w, user, execution); !ok {
+			return
+		}
+		executions[i] = execution
+	}
+
+	if executions[0].WorkflowName != executions[1].WorkflowName {
+		http.Error(w, fmt.Sprintf("Executions belong to different workflows (%s, %s)", executions[0].WorkflowName, executions[1].WorkflowName), http.StatusBadRequest)
+		return
+	}
+
+	var parameters [2]map[string]string
+	if s.workflowRepo != nil {
+		for i, id := range ids {
+			if stored, _, err := s.workflowRepo.GetWorkflowExecutionParameters(id); err == nil {
+				parameters[i] = stored
+			}
+		}
+	}
+
+	comparison := compareWorkflowExecutions(executions[0], executions[1], parameters[0], parameters[1])
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(comparison); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// compareWorkflowExecutions matches steps by name, in the order of execution A
+// followed by steps only B ran
+func compareWorkflowExecutions(a, b *database.WorkflowExecution, parametersA, parametersB map[string]string) WorkflowComparison {
+	comparison := WorkflowComparison{
+		WorkflowName: a.WorkflowName,
+		A:            comparedExecution(a),
+		B:            comparedExecution(b),
+		Parameters:   []ValueChange{},
+		Steps:        []StepComparison{},
+	}
+	comparison.DurationDeltaMs = deltaMs(comparison.A.DurationMs, comparison.B.DurationMs)
+
+	for _, key := range unionKeys(parametersA, parametersB) {
+		valueA, okA := parametersA[key]
+		valueB, okB := parametersB[key]
+		if okA != okB || valueA != valueB {
+			comparison.Parameters = append(comparison.Parameters, ValueChange{Key: key, A: optional(valueA, okA), B: optional(valueB, okB)})
+		}
+	}
+
+	stepsB := make(map[string]*database.WorkflowStepExecution, len(b.Steps))
+	for _, step := range b.Steps {
+		stepsB[step.StepName] = step
+	}
+	matched := make(map[string]bool, len(a.Steps))
+	for _, step := range a.Steps {
+		other := stepsB[step.StepName]
+		if other != nil {
+			matched[step.StepName] = true
+		}
+		comparison.Steps = append(comparison.Steps, compareSteps(step, other))
+	}
+	for _, step := range b.Steps {
+		if !matched[step.StepName] {
+			comparison.Steps = append(comparison.Steps, compareSteps(nil, step))
+		}
+	}
+
+	var slowest int64
+	for _, step := range comparison.Steps {
+		changed := step.Presence != presenceBoth || step.StatusChanged || len(step.ConfigChanges) > 0
+		if changed {
+			comparison.Summary.StepsChanged++
+			if comparison.Summary.FirstDivergentStep == "" {
+				comparison.Summary.FirstDivergentStep = step.StepName
+			}
+		}
+		if step.StatusChanged {
+			comparison.Summary.StatusChanges++
+		}
+		comparison.Summary.ConfigChanges += len(step.ConfigChanges)
+		if step.DurationDeltaMs != nil && *step.DurationDeltaMs > slowest {
+			slowest = *step.DurationDeltaMs
+			comparison.Summary.SlowestRegression = step.StepName
+		}
+	}
+	return comparison
+}
+
+func comparedExecution(execution *database.WorkflowExecution) ComparedExecution {
+	compared := ComparedExecution{
+		ID:              execution.ID,
+		ApplicationName: execution.ApplicationName,
+		Status:          execution.Status,
+		StartedAt:       execution.StartedAt,
+		CompletedAt:     execution.CompletedAt,
+		ErrorMessage:    execution.ErrorMessage,
+	}
+	if execution.CompletedAt != nil {
+		duration := execution.CompletedAt.Sub(execution.StartedAt).Milliseconds()
+		compared.DurationMs = &duration
+	}
+	return compared
+}
+
+// compareSteps compares a step present in A, B or both; a missing side is nil
+func compareSteps(a, b *database.WorkflowStepExecution) StepComparison {
+	switch {
+	case b == nil:
+		return StepComparison{StepName: a.StepName, StepType: a.StepType, Presence: presenceOnlyA, StatusA: a.Status,
+			DurationAMs: a.DurationMs, ErrorA: a.ErrorMessage, ConfigChanges: []ValueChange{}}
+	case a == nil:
+		return StepComparison{StepName: b.StepName, StepType: b.StepType, Presence: presenceOnlyB, StatusB: b.Status,
+			DurationBMs: b.DurationMs, ErrorB: b.ErrorMessage, ConfigChanges: []ValueChange{}}
+	}
+
+	comparison := StepComparison{
+		StepName:        a.StepName,
+		StepType:        b.StepType,
+		Presence:        presenceBoth,
+		StatusA:         a.Status,
+		StatusB:         b.Status,
+		StatusChanged:   a.Status != b.Status,
+		DurationAMs:     a.DurationMs,
+		DurationBMs:     b.DurationMs,
+		DurationDeltaMs: deltaMs(a.DurationMs, b.DurationMs),
+		ErrorA:          a.ErrorMessage,
+		ErrorB:          b.ErrorMessage,
+		ConfigChanges:   diffConfig(a.StepConfig, b.StepConfig),
+	}
+	if a.OutputLogs != nil || b.OutputLogs != nil {
+		comparison.Logs = diffLogs(derefString(a.OutputLogs), derefString(b.OutputLogs))
+	}
+	return comparison
+}
+
+// diffConfig compares step configurations by dotted key, e.g. env.REGION
+func diffConfig(a, b map[string]interface{}) []ValueChange {
+	flatA, flatB := map[string]interface{}{}, map[string]interface{}{}
+	flattenConfig("", a, flatA)
+	flattenConfig("", b, flatB)
+
+	changes := []ValueChange{}
+	for _, key := range unionKeys(flatA, flatB) {
+		valueA, okA := flatA[key]
+		valueB, okB := flatB[key]
+		if okA != okB || !sameJSON(valueA, valueB) {
+			changes = append(changes, ValueChange{Key: key, A: valueA, B: valueB})
+		}
+	}
+	return changes
+}
+
+func flattenConfig(prefix string, config map[string]interface{}, into map[string]interface{}) {
+	for key, value := range config {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenConfig(key, nested, into)
+			continue
+		}
+		into[key] = value
+	}
+}
+
+func sameJSON(a, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+func diffLogs(a, b string) *LogDiff {
+	linesA, linesB := logLines(a), logLines(b)
+	diff := &LogDiff{LinesA: len(linesA), LinesB: len(linesB)}
+
+	counts := make(map[string]int, len(linesA))
+	for _, line := range linesA {
+		counts[line]++
+	}
+	for _, line := range linesB {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			diff.Added++
+		}
+	}
+	for _, n := range counts {
+		diff.Removed += n
+	}
+
+	for i := 0; i < len(linesA) || i < len(linesB); i++ {
+		lineA, lineB := lineAt(linesA, i), lineAt(linesB, i)
+		if i >= len(linesA) || i >= len(linesB) || lineA != lineB {
+			diff.FirstDifference = &LogLine{Line: i + 1, A: truncateLine(lineA), B: truncateLine(lineB)}
+			break
+		}
+	}
+	return diff
+}
+
+func logLines(logs string) []string {
+	logs = strings.TrimRight(logs, "\n")
+	if logs == "" {
+		return nil
+	}
+	return strings.Split(logs, "\n")
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+func truncateLine(line string) string {
+	if len(line) > maxCompareLogLine {
+		return line[:maxCompareLogLine] + "..."
+	}
+	return line
+}
+
+func deltaMs(a, b *int64) *int64 {
+	if a == nil || b == nil {
+		return nil
+	}
+	delta := *b - *a
+	return &delta
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func optional(value string, ok bool) interface{} {
+	if !ok {
+		return nil
+	}
+	return value
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
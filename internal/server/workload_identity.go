@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/auth"
+	"innominatus/internal/logging"
+	"innominatus/internal/users"
+	"net/http"
+	"os"
+	"time"
+)
+
+// workloadIdentityConfig loads authentication.workloadIdentity from admin-config.yaml
+func (s *Server) workloadIdentityConfig() auth.WorkloadIdentityConfig {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return auth.WorkloadIdentityConfig{}
+	}
+	return adminConfig.Authentication.WorkloadIdentity
+}
+
+func (s *Server) serviceAccountTokenReviewer(config auth.WorkloadIdentityConfig) (auth.TokenReviewer, error) {
+	if s.tokenReviewer != nil {
+		return s.tokenReviewer, nil
+	}
+	return auth.NewKubernetesTokenReviewer(config.Kubeconfig)
+}
+
+// HandleKubernetesTokenExchange handles POST /api/kubernetes/token. An in-cluster workload
+// sends its projected service account token and gets a short-lived innominatus token for
+// the team its service account is mapped to.
+func (s *Server) HandleKubernetesTokenExchange(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	config := s.workloadIdentityConfig()
+	if !config.Enabled {
+		http.Error(w, "Kubernetes workload identity is not enabled", http.StatusNotFound)
+		return
+	}
+	ttl, err := config.TTL()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil || req.Token == "" {
+		http.Error(w, "Request body must be JSON with a service account token", http.StatusBadRequest)
+		return
+	}
+
+	logger := logging.FromContext(r.Context(), "server")
+	reviewer, err := s.serviceAccountTokenReviewer(config)
+	if err != nil {
+		logger.Warnf("Kubernetes token exchange unavailable: %v", err)
+		http.Error(w, "Token review is unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	username, err := reviewer.Review(r.Context(), req.Token, config.Audiences)
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		http.Error(w, "Invalid service account token", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		logger.Warnf("Kubernetes token review failed: %v", err)
+		http.Error(w, "Token review is unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	user, err := config.MapServiceAccount(username)
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		logger.Warnf("Rejected token exchange: %v", err)
+		http.Error(w, "Service account is not allowed to exchange tokens", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	token, err := generateAPIKeyString()
+	if err != nil {
+		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+		return
+	}
+	now := s.Clock().Now()
+	expiresAt := now.Add(ttl)
+	if err := s.workloadTokens.SaveWorkloadToken(hashAPIKey(token), user, expiresAt, now); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to store token: %v", err), http.StatusInternalServerError)
+		return
+	}
+	logger.InfoWithFields("Issued token to service account", map[string]interface{}{
+		"service_account": user.Username,
+		"team":            user.Team,
+		"role":            user.Role,
+		"expires_at":      expiresAt,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   int(ttl.Seconds()),
+		"expires_at":   expiresAt,
+		"username":     user.Username,
+		"team":         user.Team,
+		"role":         user.Role,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// authenticateWithWorkloadToken returns the service account user of an unexpired token
+// issued by HandleKubernetesTokenExchange
+func (s *Server) authenticateWithWorkloadToken(token string) (*users.User, time.Time, bool) {
+	if s.workloadTokens == nil {
+		return nil, time.Time{}, false
+	}
+	user, expiresAt, err := s.workloadTokens.GetWorkloadToken(hashAPIKey(token), s.Clock().Now())
+	if err != nil || user == nil {
+		return nil, time.Time{}, false
+	}
+	return user, expiresAt, true
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"innominatus/internal/workspaces"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+)
+
+// applicationWorkspaces returns the application workspaces the server exposes
+func (s *Server) applicationWorkspaces() *workspaces.Workspaces {
+	s.appWorkspacesOnce.Do(func() {
+		if s.appWorkspaces == nil {
+			s.appWorkspaces = workspaces.New(workspaces.DefaultRoot, nil)
+		}
+	})
+	return s.appWorkspaces
+}
+
+// authorizeApplication checks the user may read an application: admins always, others
+// when the application belongs to their team
+func (s *Server) authorizeApplication(w http.ResponseWriter, r *http.Request, appName string) bool {
+	user := s.getUserFromContext(r)
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	if user.IsAdmin() {
+		return true
+	}
+
+	if s.db == nil {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return false
+	}
+	app, err := s.db.GetApplication(appName)
+	if err != nil {
+		http.Error(w, "Application not found", http.StatusNotFound)
+		return false
+	}
+	if !s.canAccessTeam(user, app.Team) {
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// handleApplicationWorkspace lists the generated files of an application with their sizes
+// and hashes. Files matching a deny rule are listed as restricted.
+func (s *Server) handleApplicationWorkspace(w http.ResponseWriter, r *http.Request, appName string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorizeApplication(w, r, appName) {
+		return
+	}
+
+	files, err := s.applicationWorkspaces().List(appName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var totalSize int64
+	for _, file := range files {
+		totalSize += file.Size
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"application": appName,
+		"files":       files,
+		"count":       len(files),
+		"total_size":  totalSize,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode response: %v\n", err)
+	}
+}
+
+// handleApplicationWorkspaceDownload serves one workspace file given by the path query
+// parameter. Restricted files are refused.
+func (s *Server) handleApplicationWorkspaceDownload(w http.ResponseWriter, r *http.Request, appName string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.authorizeApplication(w, r, appName) {
+		return
+	}
+
+	filePath := r.URL.Query().Get("path")
+	if filePath == "" {
+		http.Error(w, "path query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	file, info, err := s.applicationWorkspaces().Open(appName, filePath)
+	switch {
+	case errors.Is(err, workspaces.ErrDenied):
+		http.Error(w, "File is restricted and cannot be downloaded", http.StatusForbidden)
+		return
+	case errors.Is(err, workspaces.ErrNotFound):
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(filePath)}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
@@ -0,0 +1,254 @@
+// Package slack implements the innominatus Slack app: slash commands such as
+// "/innominatus status my-app" and buttons to decide deployment approvals. Requests are
+// authenticated with Slack's request signatures, and Slack users are mapped to
+// innominatus users in admin-config.yaml.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxRequestAge is how old a signed request may be before it is rejected as a replay
+	MaxRequestAge = 5 * time.Minute
+
+	// Action IDs of the approval buttons; the button value is the approval ID
+	ActionApprove = "approval_approve"
+	ActionReject  = "approval_reject"
+
+	requestTimeout = 10 * time.Second
+)
+
+// Config is the slack section of admin-config.yaml
+type Config struct {
+	SigningSecretEnv string            `yaml:"signingSecretEnv" json:"signingSecretEnv"` // Environment variable holding the app's signing secret
+	Users            map[string]string `yaml:"users" json:"users"`                       // Slack user ID → innominatus username
+}
+
+// Enabled reports whether the Slack endpoints accept requests
+func (c Config) Enabled() bool {
+	return c.SigningSecretEnv != ""
+}
+
+// SigningSecret returns the signing secret from the configured environment variable
+func (c Config) SigningSecret() string {
+	if c.SigningSecretEnv == "" {
+		return ""
+	}
+	return os.Getenv(c.SigningSecretEnv)
+}
+
+// Username returns the innominatus user a Slack user is mapped to
+func (c Config) Username(slackUserID string) (string, bool) {
+	username, ok := c.Users[slackUserID]
+	return username, ok && username != ""
+}
+
+// Validate checks the user mapping
+func (c Config) Validate() error {
+	if len(c.Users) > 0 && c.SigningSecretEnv == "" {
+		return fmt.Errorf("slack.signingSecretEnv is required when slack.users is set")
+	}
+	ids := make([]string, 0, len(c.Users))
+	for id := range c.Users {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !strings.HasPrefix(id, "U") && !strings.HasPrefix(id, "W") {
+			return fmt.Errorf("slack.users: '%s' is not a Slack user ID (e.g. U012AB3CD)", id)
+		}
+		if c.Users[id] == "" {
+			return fmt.Errorf("slack.users.%s: username is required", id)
+		}
+	}
+	return nil
+}
+
+// Verify checks the v0 signature Slack sends with every request: an HMAC-SHA256 of
+// "v0:<timestamp>:<body>" with the signing secret, in the X-Slack-Signature header.
+// Requests older than MaxRequestAge are rejected.
+func Verify(header http.Header, body []byte, secret string, now time.Time) error {
+	if secret == "" {
+		return fmt.Errorf("signing secret is not set")
+	}
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid request timestamp")
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > MaxRequestAge || age < -MaxRequestAge {
+		return fmt.Errorf("request timestamp is too old")
+	}
+
+	signature := header.Get("X-Slack-Signature")
+	if !strings.HasPrefix(signature, "v0=") {
+		return fmt.Errorf("missing request signature")
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "v0="))
+	if err != nil {
+		return fmt.Errorf("invalid request signature")
+	}
+	if !hmac.Equal(got, sign(secret, timestamp, body)) {
+		return fmt.Errorf("request signature does not match")
+	}
+	return nil
+}
+
+// Sign returns the X-Slack-Signature value of a request body, as Slack computes it
+func Sign(secret, timestamp string, body []byte) string {
+	return "v0=" + hex.EncodeToString(sign(secret, timestamp, body))
+}
+
+func sign(secret, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Command is a parsed slash command, e.g. "status my-app"
+type Command struct {
+	Name string
+	Args []string
+}
+
+// ParseCommand splits the text of a slash command. Empty text is the help command.
+func ParseCommand(text string) Command {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return Command{Name: "help"}
+	}
+	return Command{Name: strings.ToLower(fields[0]), Args: fields[1:]}
+}
+
+// Usage is the reply to the help command
+const Usage = "*innominatus commands*\n" +
+	"`/innominatus status <app>` – deployment, health and resources of an application\n" +
+	"`/innominatus approvals [app]` – pending deployment approvals you can decide\n" +
+	"`/innominatus help` – this help"
+
+// Message is a Slack message, as the response of a command or sent to a response_url
+type Message struct {
+	ResponseType    string  `json:"response_type,omitempty"` // ephemeral (default) or in_channel
+	Text            string  `json:"text"`                    // Fallback for notifications
+	Blocks          []Block `json:"blocks,omitempty"`
+	ReplaceOriginal bool    `json:"replace_original,omitempty"`
+}
+
+// Block is a Block Kit layout block
+type Block map[string]interface{}
+
+// Reply returns an ephemeral message with a single section
+func Reply(text string) Message {
+	return Message{ResponseType: "ephemeral", Text: text, Blocks: []Block{Section(text)}}
+}
+
+// Section returns a section block with markdown text
+func Section(text string) Block {
+	return Block{"type": "section", "text": Block{"type": "mrkdwn", "text": text}}
+}
+
+// Context returns a context block with markdown text in small print
+func Context(text string) Block {
+	return Block{"type": "context", "elements": []Block{{"type": "mrkdwn", "text": text}}}
+}
+
+// ApprovalActions returns approve and reject buttons for an approval
+func ApprovalActions(approvalID int64) Block {
+	value := strconv.FormatInt(approvalID, 10)
+	return Block{
+		"type":     "actions",
+		"block_id": "approval_" + value,
+		"elements": []Block{
+			button(ActionApprove, "Approve", "primary", value),
+			button(ActionReject, "Reject", "danger", value),
+		},
+	}
+}
+
+func button(actionID, label, style, value string) Block {
+	return Block{
+		"type":      "button",
+		"action_id": actionID,
+		"style":     style,
+		"value":     value,
+		"text":      Block{"type": "plain_text", "text": label},
+	}
+}
+
+// Interaction is the payload Slack posts when a user clicks a button
+type Interaction struct {
+	Type string `json:"type"` // block_actions
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	ResponseURL string   `json:"response_url"`
+	Actions     []Action `json:"actions"`
+}
+
+// Action is a clicked button
+type Action struct {
+	ActionID string `json:"action_id"`
+	Value    string `json:"value"`
+}
+
+// ParseInteraction reads the payload form field of an interaction request
+func ParseInteraction(form url.Values) (*Interaction, error) {
+	payload := form.Get("payload")
+	if payload == "" {
+		return nil, fmt.Errorf("missing payload")
+	}
+	var interaction Interaction
+	if err := json.Unmarshal([]byte(payload), &interaction); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	return &interaction, nil
+}
+
+// Respond sends a message to the response_url of a command or interaction
+func Respond(ctx context.Context, responseURL string, message Message) error {
+	target, err := url.Parse(responseURL)
+	if err != nil || target.Scheme != "https" && target.Scheme != "http" {
+		return fmt.Errorf("invalid response_url")
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req) // #nosec G107 - response_url comes from a signed Slack request
+	if err != nil {
+		return fmt.Errorf("failed to send Slack response: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("slack returned %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedHeader(secret string, ts time.Time, body []byte) http.Header {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", Sign(secret, timestamp, body))
+	return header
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte("command=%2Finnominatus&text=status+shop&user_id=U123")
+
+	assert.NoError(t, Verify(signedHeader("s3cret", now, body), body, "s3cret", now))
+
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		secret string
+	}{
+		{"wrong secret", signedHeader("other", now, body), body, "s3cret"},
+		{"tampered body", signedHeader("s3cret", now, body), []byte("text=approvals"), "s3cret"},
+		{"replayed", signedHeader("s3cret", now.Add(-6*time.Minute), body), body, "s3cret"},
+		{"unsigned", http.Header{"X-Slack-Request-Timestamp": {"1700000000"}}, body, "s3cret"},
+		{"no secret configured", signedHeader("", now, body), body, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, Verify(tt.header, tt.body, tt.secret, now))
+		})
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	assert.Equal(t, Command{Name: "help"}, ParseCommand("  "))
+	assert.Equal(t, Command{Name: "status", Args: []string{"my-app"}}, ParseCommand("Status  my-app"))
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{SigningSecretEnv: "SLACK_SIGNING_SECRET", Users: map[string]string{"U012AB3CD": "alice"}}.Validate())
+	assert.Error(t, Config{Users: map[string]string{"U012AB3CD": "alice"}}.Validate(), "users without signing secret")
+	assert.Error(t, Config{SigningSecretEnv: "S", Users: map[string]string{"alice@example.com": "alice"}}.Validate(), "not a user ID")
+	assert.Error(t, Config{SigningSecretEnv: "S", Users: map[string]string{"U012AB3CD": ""}}.Validate(), "empty username")
+}
+
+func TestParseInteraction(t *testing.T) {
+	_, err := ParseInteraction(url.Values{})
+	assert.Error(t, err)
+
+	payload := `{"type": "block_actions", "user": {"id": "U123"}, "response_url": "https://hooks.slack.com/actions/x",
+		"actions": [{"action_id": "approval_approve", "value": "42"}]}`
+	interaction, err := ParseInteraction(url.Values{"payload": {payload}})
+	require.NoError(t, err)
+	assert.Equal(t, "U123", interaction.User.ID)
+	assert.Equal(t, []Action{{ActionID: ActionApprove, Value: "42"}}, interaction.Actions)
+}
+
+func TestApprovalActions(t *testing.T) {
+	data, err := json.Marshal(ApprovalActions(42))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "actions", "block_id": "approval_42", "elements": [
+		{"type": "button", "action_id": "approval_approve", "style": "primary", "value": "42", "text": {"type": "plain_text", "text": "Approve"}},
+		{"type": "button", "action_id": "approval_reject", "style": "danger", "value": "42", "text": {"type": "plain_text", "text": "Reject"}}
+	]}`, string(data))
+}
+
+func TestRespond(t *testing.T) {
+	var got Message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer server.Close()
+
+	message := Reply("Approved")
+	message.ReplaceOriginal = true
+	require.NoError(t, Respond(context.Background(), server.URL, message))
+	assert.Equal(t, "Approved", got.Text)
+	assert.True(t, got.ReplaceOriginal)
+
+	assert.Error(t, Respond(context.Background(), "file:///etc/passwd", message))
+}
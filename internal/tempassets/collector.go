@@ -0,0 +1,249 @@
+// Package tempassets garbage collects the repository clones and manifest files workflow
+// steps leave in the temp directory.
+package tempassets
+
+import (
+	"errors"
+	"fmt"
+	"innominatus/internal/clock"
+	"innominatus/internal/database"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultRetention is how long temp assets are kept after their last use
+const DefaultRetention = 24 * time.Hour
+
+// untrackedPatterns match the names of temp assets steps create in the temp directory.
+// Matching entries that are not tracked, e.g. written before tracking existed or by
+// steps without a database, are collected once they are older than the retention.
+var untrackedPatterns = []string{
+	"*-*-repo",                 // Clones of gitea-repo steps
+	"*-*-manifests.yaml",       // Manifests of kubernetes steps
+	"*-argocd-app.yaml",        // Applications of argocd-app steps
+	"score-repo-*",             // Clones of git-commit-manifests steps
+	"render-repo-*",            // Clones of render steps
+	"render-kustomize-*",       // Kustomize overlays of render steps
+	"innominatus-git-*",        // Clones of the kubernetes provisioner
+	"innominatus-kubeconfig-*", // Kubeconfigs of application identities
+	"helm-values-*.yaml",
+	"policy-*.sh",
+}
+
+// Store tracks the temp assets of workflow executions
+type Store interface {
+	ListTempAssets() ([]*database.TempAsset, error)
+	DeleteTempAsset(path string) error
+}
+
+// Asset is a temp file or directory with its size and why it is kept
+type Asset struct {
+	Path        string    `json:"path"`
+	Size        int64     `json:"size"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+	Tracked     bool      `json:"tracked"`
+	ExecutionID *int64    `json:"execution_id,omitempty"`
+	Application string    `json:"application,omitempty"`
+	Step        string    `json:"step,omitempty"`
+	Reclaimable bool      `json:"reclaimable"`
+	KeptBecause string    `json:"kept_because,omitempty"`
+}
+
+// Report lists the temp assets and the space that can be reclaimed, or was by a collection
+type Report struct {
+	Dir              string    `json:"dir"`
+	Retention        string    `json:"retention"`
+	Assets           []Asset   `json:"assets"`
+	TotalBytes       int64     `json:"total_bytes"`
+	ReclaimableBytes int64     `json:"reclaimable_bytes"`
+	Removed          int       `json:"removed"`
+	RemovedBytes     int64     `json:"removed_bytes"`
+	Errors           []string  `json:"errors,omitempty"`
+	GeneratedAt      time.Time `json:"generated_at"`
+}
+
+// Collector finds and removes temp assets whose retention ended
+type Collector struct {
+	store     Store // nil when there is no database; only untracked assets are found
+	dir       string
+	retention time.Duration
+	clock     clock.Clock
+	mu        sync.Mutex
+}
+
+// NewCollector creates a collector for the temp directory dir that keeps assets for
+// retention after their last use
+func NewCollector(store Store, dir string, retention time.Duration) *Collector {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Collector{store: store, dir: dir, retention: retention, clock: clock.Real()}
+}
+
+// SetClock replaces the wall clock, e.g. with a fake clock in tests
+func (c *Collector) SetClock(clk clock.Clock) {
+	c.clock = clock.OrReal(clk)
+}
+
+// Scan reports the temp assets without removing any
+func (c *Collector) Scan() (*Report, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	report, _, err := c.scan()
+	return report, err
+}
+
+// Collect removes the reclaimable temp assets and stops tracking them, as well as
+// tracked paths that no longer exist
+func (c *Collector) Collect() (*Report, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report, missing, err := c.scan()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range missing {
+		if err := c.store.DeleteTempAsset(path); err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		}
+	}
+
+	kept := report.Assets[:0]
+	for _, asset := range report.Assets {
+		if !asset.Reclaimable {
+			kept = append(kept, asset)
+			continue
+		}
+		if err := os.RemoveAll(asset.Path); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to remove %s: %v", asset.Path, err))
+			kept = append(kept, asset)
+			continue
+		}
+		if asset.Tracked {
+			if err := c.store.DeleteTempAsset(asset.Path); err != nil {
+				report.Errors = append(report.Errors, err.Error())
+			}
+		}
+		report.Removed++
+		report.RemovedBytes += asset.Size
+		report.TotalBytes -= asset.Size
+		report.ReclaimableBytes -= asset.Size
+	}
+	report.Assets = kept
+	return report, nil
+}
+
+// scan measures the tracked and untracked assets. It returns tracked paths that no longer
+// exist separately. Callers hold c.mu.
+func (c *Collector) scan() (*Report, []string, error) {
+	now := c.clock.Now()
+	report := &Report{Dir: c.dir, Retention: c.retention.String(), Assets: []Asset{}, GeneratedAt: now}
+
+	var missing []string
+	tracked := make(map[string]bool)
+	if c.store != nil {
+		records, err := c.store.ListTempAssets()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, record := range records {
+			tracked[filepath.Clean(record.Path)] = true
+			size, modified, err := measure(record.Path)
+			if errors.Is(err, fs.ErrNotExist) {
+				missing = append(missing, record.Path)
+				continue
+			} else if err != nil {
+				report.Errors = append(report.Errors, err.Error())
+				continue
+			}
+			lastUsed := record.LastUsedAt
+			if modified.After(lastUsed) {
+				lastUsed = modified
+			}
+			asset := Asset{
+				Path:        record.Path,
+				Size:        size,
+				LastUsedAt:  lastUsed,
+				Tracked:     true,
+				ExecutionID: record.ExecutionID,
+				Application: record.ApplicationName,
+				Step:        record.StepName,
+			}
+			if record.ExecutionStatus == database.WorkflowStatusRunning {
+				asset.KeptBecause = "execution is running"
+			}
+			c.add(report, asset, now)
+		}
+	}
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, fmt.Errorf("failed to read temp directory: %w", err)
+	}
+	for _, entry := range entries {
+		path := filepath.Join(c.dir, entry.Name())
+		if tracked[path] || !matchesUntracked(entry.Name()) {
+			continue
+		}
+		size, modified, err := measure(path)
+		if err != nil {
+			continue // Removed meanwhile or not readable by the server
+		}
+		c.add(report, Asset{Path: path, Size: size, LastUsedAt: modified}, now)
+	}
+
+	sort.Slice(report.Assets, func(i, j int) bool {
+		return report.Assets[i].LastUsedAt.Before(report.Assets[j].LastUsedAt)
+	})
+	return report, missing, nil
+}
+
+// add decides whether an asset is reclaimable and adds it to the report
+func (c *Collector) add(report *Report, asset Asset, now time.Time) {
+	if asset.KeptBecause == "" && now.Sub(asset.LastUsedAt) < c.retention {
+		asset.KeptBecause = "within retention"
+	}
+	asset.Reclaimable = asset.KeptBecause == ""
+	report.Assets = append(report.Assets, asset)
+	report.TotalBytes += asset.Size
+	if asset.Reclaimable {
+		report.ReclaimableBytes += asset.Size
+	}
+}
+
+func matchesUntracked(name string) bool {
+	for _, pattern := range untrackedPatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// measure returns the size of the regular files below path and when any of them last changed
+func measure(path string) (int64, time.Time, error) {
+	var size int64
+	var modified time.Time
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, modified, err
+}
@@ -0,0 +1,124 @@
+package tempassets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"innominatus/internal/clock"
+	"innominatus/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	assets  []*database.TempAsset
+	deleted []string
+}
+
+func (f *fakeStore) ListTempAssets() ([]*database.TempAsset, error) {
+	return f.assets, nil
+}
+
+func (f *fakeStore) DeleteTempAsset(path string) error {
+	f.deleted = append(f.deleted, path)
+	return nil
+}
+
+// writeAsset creates a file of size bytes at path; the file and its directory were last
+// modified at modified
+func writeAsset(t *testing.T, path string, size int, modified time.Time) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0600))
+	require.NoError(t, os.Chtimes(path, modified, modified))
+	require.NoError(t, os.Chtimes(filepath.Dir(path), modified, modified))
+}
+
+func TestCollector(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	dir := t.TempDir()
+
+	// Tracked clone of a finished execution and of a running one
+	finished := filepath.Join(dir, "score-repo-shop")
+	writeAsset(t, filepath.Join(finished, "deployment.yaml"), 100, old)
+	running := filepath.Join(dir, "score-repo-billing")
+	writeAsset(t, filepath.Join(running, "deployment.yaml"), 50, old)
+	// Untracked leftovers, one recent, and a file that is not a temp asset
+	writeAsset(t, filepath.Join(dir, "shop-default-manifests.yaml"), 10, old)
+	writeAsset(t, filepath.Join(dir, "shop-argocd-app.yaml"), 5, now.Add(-time.Hour))
+	writeAsset(t, filepath.Join(dir, "notes.txt"), 1, old)
+
+	execID := int64(7)
+	store := &fakeStore{assets: []*database.TempAsset{
+		{Path: finished, ExecutionID: &execID, ExecutionStatus: database.WorkflowStatusCompleted, ApplicationName: "shop", StepName: "commit", LastUsedAt: old},
+		{Path: running, ExecutionStatus: database.WorkflowStatusRunning, LastUsedAt: old},
+		{Path: filepath.Join(dir, "score-repo-gone"), LastUsedAt: old},
+	}}
+	collector := NewCollector(store, dir, 24*time.Hour)
+	collector.SetClock(clock.NewFake(now))
+
+	report, err := collector.Scan()
+	require.NoError(t, err)
+	require.Len(t, report.Assets, 4)
+	assert.Equal(t, int64(165), report.TotalBytes)
+	assert.Equal(t, int64(110), report.ReclaimableBytes)
+	kept := map[string]string{}
+	for _, asset := range report.Assets {
+		kept[filepath.Base(asset.Path)] = asset.KeptBecause
+	}
+	assert.Equal(t, map[string]string{
+		"score-repo-shop":             "",
+		"score-repo-billing":          "execution is running",
+		"shop-default-manifests.yaml": "",
+		"shop-argocd-app.yaml":        "within retention",
+	}, kept)
+	assert.Empty(t, store.deleted, "scanning removes nothing")
+	assert.DirExists(t, finished)
+
+	report, err = collector.Collect()
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Removed)
+	assert.Equal(t, int64(110), report.RemovedBytes)
+	assert.Equal(t, int64(0), report.ReclaimableBytes)
+	assert.Len(t, report.Assets, 2)
+	assert.NoDirExists(t, finished)
+	assert.NoFileExists(t, filepath.Join(dir, "shop-default-manifests.yaml"))
+	assert.DirExists(t, running)
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+	assert.ElementsMatch(t, []string{filepath.Join(dir, "score-repo-gone"), finished}, store.deleted)
+}
+
+func TestCollectorWithoutStore(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	writeAsset(t, filepath.Join(dir, "shop-dev-repo", "README.md"), 20, now.Add(-25*time.Hour))
+
+	collector := NewCollector(nil, dir, 0)
+	collector.SetClock(clock.NewFake(now))
+
+	report, err := collector.Collect()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRetention.String(), report.Retention)
+	assert.Equal(t, 1, report.Removed)
+	assert.NoDirExists(t, filepath.Join(dir, "shop-dev-repo"))
+}
+
+func TestConfigDurations(t *testing.T) {
+	retention, interval, err := Config{}.Durations()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRetention, retention)
+	assert.Equal(t, DefaultInterval, interval)
+
+	retention, interval, err = Config{Retention: "6h", Interval: "0"}.Durations()
+	require.NoError(t, err)
+	assert.Equal(t, 6*time.Hour, retention)
+	assert.Equal(t, time.Duration(0), interval)
+
+	for _, config := range []Config{{Retention: "-1h"}, {Retention: "soon"}, {Interval: "10s"}} {
+		assert.Error(t, config.Validate(), config)
+	}
+}
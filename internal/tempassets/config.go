@@ -0,0 +1,42 @@
+package tempassets
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultInterval is how often temp assets are collected unless tempAssets.interval is set
+const DefaultInterval = time.Hour
+
+// Config configures the garbage collection of temp assets in admin-config.yaml
+type Config struct {
+	Retention string `yaml:"retention" json:"retention"` // Time after the last use before an asset is removed (default 24h)
+	Interval  string `yaml:"interval" json:"interval"`   // How often assets are collected (default 1h); 0 disables collection
+}
+
+// Durations returns the retention and collection interval, applying the defaults. An
+// interval of 0 means collection is disabled.
+func (c Config) Durations() (retention, interval time.Duration, err error) {
+	retention, interval = DefaultRetention, DefaultInterval
+	if c.Retention != "" {
+		if retention, err = time.ParseDuration(c.Retention); err != nil || retention <= 0 {
+			return 0, 0, fmt.Errorf("invalid tempAssets.retention '%s'", c.Retention)
+		}
+	}
+	switch c.Interval {
+	case "":
+	case "0":
+		interval = 0
+	default:
+		if interval, err = time.ParseDuration(c.Interval); err != nil || interval < time.Minute {
+			return 0, 0, fmt.Errorf("invalid tempAssets.interval '%s': must be at least 1m, or 0 to disable", c.Interval)
+		}
+	}
+	return retention, interval, nil
+}
+
+// Validate checks the durations
+func (c Config) Validate() error {
+	_, _, err := c.Durations()
+	return err
+}
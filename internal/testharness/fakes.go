@@ -0,0 +1,128 @@
+package testharness
+
+import (
+	"context"
+	"innominatus/internal/types"
+	"sync"
+	"time"
+)
+
+// DefaultFakeStepTypes are the step types that reach real infrastructure
+// (terraform, kubectl, Gitea, ArgoCD) and are replaced by fakes by default
+var DefaultFakeStepTypes = []string{
+	"terraform",
+	"terraform-generate",
+	"kubernetes",
+	"ansible",
+	"gitea-repo",
+	"argocd-app",
+}
+
+// StepCall records a single invocation of a faked step
+type StepCall struct {
+	StepName    string
+	StepType    string
+	Operation   string
+	AppName     string
+	ExecutionID int64
+	StepID      int64
+	Config      map[string]interface{}
+	At          time.Time
+}
+
+// FakeProvisioner is an in-memory stand-in for step types that would provision
+// real infrastructure. It records every call and can be told to fail steps.
+type FakeProvisioner struct {
+	mu       sync.Mutex
+	calls    []StepCall
+	failures map[string]error // step name -> error to return
+	delay    time.Duration
+}
+
+// NewFakeProvisioner creates a fake provisioner that succeeds for every step
+func NewFakeProvisioner() *FakeProvisioner {
+	return &FakeProvisioner{failures: make(map[string]error)}
+}
+
+// FailStep makes the named step return err. Pass nil to let it succeed again.
+func (f *FakeProvisioner) FailStep(stepName string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err == nil {
+		delete(f.failures, stepName)
+		return
+	}
+	f.failures[stepName] = err
+}
+
+// SetDelay makes every step take d, e.g. to exercise timeouts or concurrency
+func (f *FakeProvisioner) SetDelay(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delay = d
+}
+
+// Execute implements workflow.StepExecutorFunc
+func (f *FakeProvisioner) Execute(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, StepCall{
+		StepName:    step.Name,
+		StepType:    step.Type,
+		Operation:   step.Operation,
+		AppName:     appName,
+		ExecutionID: execID,
+		StepID:      stepID,
+		Config:      step.Config,
+		At:          time.Now(),
+	})
+	err := f.failures[step.Name]
+	delay := f.delay
+	f.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// Calls returns a copy of all recorded calls in invocation order
+func (f *FakeProvisioner) Calls() []StepCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([]StepCall, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+// CallsFor returns the recorded calls for one application
+func (f *FakeProvisioner) CallsFor(appName string) []StepCall {
+	var calls []StepCall
+	for _, call := range f.Calls() {
+		if call.AppName == appName {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// StepNames returns the names of the steps executed for an application, in order
+func (f *FakeProvisioner) StepNames(appName string) []string {
+	var names []string
+	for _, call := range f.CallsFor(appName) {
+		names = append(names, call.StepName)
+	}
+	return names
+}
+
+// Reset forgets all recorded calls and configured failures
+func (f *FakeProvisioner) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+	f.failures = make(map[string]error)
+	f.delay = 0
+}
@@ -0,0 +1,373 @@
+// Package testharness runs the innominatus server end to end for tests: a throwaway
+// Postgres container, fixture providers, and in-memory fakes for every step type that
+// would otherwise need a cluster, Terraform, Gitea or ArgoCD.
+//
+// A test drives the server only through its HTTP API:
+//
+//	h := testharness.New(t)
+//	status, body := h.ExecuteGoldenPath("fixture-deploy", score, nil)
+//	require.Equal(t, http.StatusOK, status)
+//	require.Equal(t, []string{"create-repository", ...}, h.Fakes.StepNames("my-app"))
+package testharness
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/clock"
+	"innominatus/internal/database"
+	"innominatus/internal/providers"
+	"innominatus/internal/server"
+	"innominatus/internal/types"
+	"innominatus/internal/users"
+	"innominatus/pkg/sdk"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fixtureCoreVersion is the core version fixture providers are checked against
+const fixtureCoreVersion = "1.0.0"
+
+// Options customises a harness. The zero value uses the bundled fixtures.
+type Options struct {
+	// ProvidersDir holds one sub-directory per provider (provider.yaml + workflows/).
+	// Defaults to the fixture providers shipped with this package.
+	ProvidersDir string
+
+	// FakeStepTypes are replaced by the harness FakeProvisioner.
+	// Defaults to DefaultFakeStepTypes.
+	FakeStepTypes []string
+
+	// User is the identity behind Token. Defaults to an admin on team "platform".
+	User *users.User
+
+	// Clock drives server schedulers and the queue, e.g. a clock.Fake for TTL tests.
+	// Defaults to the wall clock.
+	Clock clock.Clock
+}
+
+// Harness is a running server backed by a test database
+type Harness struct {
+	t *testing.T
+
+	DB        *database.Database
+	Server    *server.Server
+	HTTP      *httptest.Server
+	Providers *providers.Registry
+	Fakes     *FakeProvisioner
+
+	// Token authenticates requests made through the harness helpers
+	Token string
+	// User is the identity behind Token
+	User *users.User
+	// WorkDir is the server working directory (workflows/, data/)
+	WorkDir string
+}
+
+// New starts Postgres, runs all migrations, loads fixture providers and serves the
+// API on a local httptest server. Everything is torn down when the test ends.
+// Skips the test when Docker is unavailable.
+//
+// The harness changes the working directory of the test process, so tests using it
+// cannot run in parallel.
+func New(t *testing.T, opts ...Options) *Harness {
+	t.Helper()
+
+	var options Options
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	packageDir := harnessPackageDir(t)
+	if options.ProvidersDir == "" {
+		options.ProvidersDir = filepath.Join(packageDir, "testdata", "providers")
+	}
+	providersDir, err := filepath.Abs(options.ProvidersDir)
+	if err != nil {
+		t.Fatalf("Failed to resolve providers directory: %v", err)
+	}
+	if options.FakeStepTypes == nil {
+		options.FakeStepTypes = DefaultFakeStepTypes
+	}
+	if options.User == nil {
+		options.User = &users.User{Username: "harness-admin", Team: "platform", Role: "admin"}
+	}
+
+	testDB := database.SetupTestDatabaseWithoutSchema(t)
+	db := testDB.DB
+	t.Cleanup(func() { _ = db.Close() })
+
+	// The server resolves workflows/ and data/ relative to its working directory
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+
+	db.SetMigrationsFS(os.DirFS(filepath.Join(packageDir, "..", "..", "migrations")))
+	if err := db.InitSchema(); err != nil {
+		t.Fatalf("Failed to initialize schema: %v", err)
+	}
+
+	registry, err := loadFixtureProviders(providersDir, filepath.Join(workDir, "workflows"))
+	if err != nil {
+		t.Fatalf("Failed to load fixture providers: %v", err)
+	}
+
+	srv := server.NewServerWithDB(db)
+	srv.SetProviderRegistry(registry)
+	if options.Clock != nil {
+		srv.SetClock(options.Clock)
+	}
+
+	fakes := NewFakeProvisioner()
+	executor := srv.GetWorkflowExecutor()
+	for _, stepType := range options.FakeStepTypes {
+		executor.RegisterStepExecutor(stepType, fakes.Execute)
+	}
+
+	token, err := srv.CreateSessionToken(options.User)
+	if err != nil {
+		t.Fatalf("Failed to create harness session: %v", err)
+	}
+
+	httpServer := httptest.NewServer(newMux(srv))
+	t.Cleanup(httpServer.Close)
+
+	return &Harness{
+		t:         t,
+		DB:        db,
+		Server:    srv,
+		HTTP:      httpServer,
+		Providers: registry,
+		Fakes:     fakes,
+		Token:     token,
+		User:      options.User,
+		WorkDir:   workDir,
+	}
+}
+
+// As returns a copy of the harness whose helpers authenticate as user
+func (h *Harness) As(user *users.User) *Harness {
+	h.t.Helper()
+	token, err := h.Server.CreateSessionToken(user)
+	if err != nil {
+		h.t.Fatalf("Failed to create session for %s: %v", user.Username, err)
+	}
+	clone := *h
+	clone.Token = token
+	clone.User = user
+	return &clone
+}
+
+// Do sends an authenticated API request and returns the response.
+// The caller must close the response body.
+func (h *Harness) Do(method, path, contentType string, body io.Reader) *http.Response {
+	h.t.Helper()
+
+	req, err := http.NewRequest(method, h.HTTP.URL+path, body)
+	if err != nil {
+		h.t.Fatalf("Failed to create request %s %s: %v", method, path, err)
+	}
+	// Without an explicit Accept header the auth middleware treats requests as browser traffic
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+h.Token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := h.HTTP.Client().Do(req)
+	if err != nil {
+		h.t.Fatalf("Request %s %s failed: %v", method, path, err)
+	}
+	return resp
+}
+
+// GetJSON sends a GET request, decodes a successful JSON response into out and
+// returns the status code
+func (h *Harness) GetJSON(path string, out interface{}) int {
+	h.t.Helper()
+	return h.decode(h.Do(http.MethodGet, path, "", nil), out)
+}
+
+// PostJSON sends in as a JSON body, decodes a successful JSON response into out and
+// returns the status code. in and out may be nil.
+func (h *Harness) PostJSON(path string, in, out interface{}) int {
+	h.t.Helper()
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			h.t.Fatalf("Failed to encode request body: %v", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	return h.decode(h.Do(http.MethodPost, path, "application/json", body), out)
+}
+
+// ExecuteGoldenPath runs a golden path for a Score spec (YAML), waiting for the run to
+// finish, and returns the status code and the decoded response (or {"error": ...} on failure)
+func (h *Harness) ExecuteGoldenPath(name, scoreYAML string, params map[string]string) (int, map[string]interface{}) {
+	h.t.Helper()
+
+	query := url.Values{"wait": {"true"}}
+	for key, value := range params {
+		query.Set("param."+key, value)
+	}
+	path := fmt.Sprintf("/api/workflows/golden-paths/%s/execute?%s", name, query.Encode())
+
+	resp := h.Do(http.MethodPost, path, "application/yaml", bytes.NewBufferString(scoreYAML))
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.t.Fatalf("Failed to read golden path response: %v", err)
+	}
+
+	result := make(map[string]interface{})
+	if resp.StatusCode >= 400 || json.Unmarshal(data, &result) != nil {
+		result["error"] = string(bytes.TrimSpace(data))
+	}
+	return resp.StatusCode, result
+}
+
+func (h *Harness) decode(resp *http.Response, out interface{}) int {
+	h.t.Helper()
+	defer func() { _ = resp.Body.Close() }()
+
+	if out != nil && resp.StatusCode < 400 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			h.t.Fatalf("Failed to decode response from %s: %v", resp.Request.URL.Path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// newMux registers the API routes exercised by integration tests, using the same
+// handlers and authentication as cmd/server
+func newMux(srv *server.Server) *http.ServeMux {
+	withAuth := func(h http.HandlerFunc) http.HandlerFunc {
+		return srv.TraceIDMiddleware(srv.AuthMiddleware(h))
+	}
+	withAdmin := func(h http.HandlerFunc) http.HandlerFunc {
+		return srv.TraceIDMiddleware(srv.AdminOnlyMiddleware(h))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/profile", withAuth(srv.HandleGetProfile))
+	mux.HandleFunc("/api/me/overview", withAuth(srv.HandleMyOverview))
+	mux.HandleFunc("/api/applications", withAuth(srv.HandleApplications))
+	mux.HandleFunc("/api/validate", withAuth(srv.HandleValidate))
+	mux.HandleFunc("/api/applications/", withAuth(srv.HandleApplicationDetail))
+	mux.HandleFunc("/api/workflows", withAuth(srv.HandleWorkflows))
+	mux.HandleFunc("/api/workflows/", withAuth(srv.HandleWorkflowDetail))
+	mux.HandleFunc("/api/workflows/golden-paths/", withAuth(srv.HandleGoldenPathExecution))
+	mux.HandleFunc("/api/golden-paths", withAuth(srv.HandleGoldenPaths))
+	mux.HandleFunc("/api/golden-paths/", withAuth(srv.HandleGoldenPaths))
+	mux.HandleFunc("/api/providers", withAuth(srv.HandleListProviders))
+	mux.HandleFunc("/api/providers/stats", withAuth(srv.HandleProviderStats))
+	mux.HandleFunc("/api/resources", withAuth(srv.HandleResources))
+	mux.HandleFunc("/api/resources/", withAuth(srv.HandleResourceDetail))
+	mux.HandleFunc("/api/approvals", withAuth(srv.HandleApprovals))
+	mux.HandleFunc("/api/approvals/", withAuth(srv.HandleApprovalDetail))
+	mux.HandleFunc("/api/clusters", withAuth(srv.HandleClusters))
+	mux.HandleFunc("/api/clusters/", withAuth(srv.HandleClusterDetail))
+	mux.HandleFunc("/api/maintenance-windows", withAuth(srv.HandleMaintenanceWindows))
+	mux.HandleFunc("/api/maintenance-windows/", withAuth(srv.HandleMaintenanceWindowDetail))
+	mux.HandleFunc("/api/operations/upcoming", withAuth(srv.HandleUpcomingOperations))
+	mux.HandleFunc("/api/stats", withAuth(srv.HandleStats))
+	mux.HandleFunc("/api/teams", withAdmin(srv.HandleTeams))
+	mux.HandleFunc("/api/admin/debug/clock", withAdmin(srv.HandleDebugClock))
+	return mux
+}
+
+// loadFixtureProviders registers every provider below dir and writes its golden path
+// workflows to workflowsDir, where the golden path execution endpoint looks them up
+func loadFixtureProviders(dir, workflowsDir string) (*providers.Registry, error) {
+	manifests, err := filepath.Glob(filepath.Join(dir, "*", "provider.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(workflowsDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create workflows directory: %w", err)
+	}
+
+	loader := providers.NewLoader(fixtureCoreVersion)
+	loaded := make([]*sdk.Provider, 0, len(manifests))
+	dirs := make(map[*sdk.Provider]string, len(manifests))
+	for _, manifest := range manifests {
+		provider, err := loader.LoadFromFile(manifest)
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, provider)
+		dirs[provider] = filepath.Dir(manifest)
+	}
+
+	ordered, err := providers.OrderByDependencies(loaded)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := providers.NewRegistry()
+	for _, provider := range ordered {
+		if err := registry.RegisterProvider(provider); err != nil {
+			return nil, err
+		}
+
+		for _, wf := range provider.Workflows {
+			if wf.Category != "goldenpath" {
+				continue
+			}
+			if err := exportGoldenPath(filepath.Join(dirs[provider], wf.File), wf.Name, wf.Description, workflowsDir); err != nil {
+				return nil, fmt.Errorf("provider %s: %w", provider.Metadata.Name, err)
+			}
+		}
+	}
+
+	return registry, nil
+}
+
+// exportGoldenPath wraps a provider workflow (top-level steps) in the WorkflowSpec
+// envelope (metadata + spec) read by the golden path execution endpoint
+func exportGoldenPath(source, name, description, workflowsDir string) error {
+	data, err := os.ReadFile(source) // #nosec G304 - fixture path from provider manifest
+	if err != nil {
+		return fmt.Errorf("failed to read golden path %s: %w", name, err)
+	}
+
+	var wf types.Workflow
+	if err := yaml.Unmarshal(data, &wf); err != nil {
+		return fmt.Errorf("failed to parse golden path %s: %w", name, err)
+	}
+
+	spec := types.WorkflowSpec{
+		APIVersion: "workflow.dev/v1",
+		Kind:       "Workflow",
+		Metadata:   types.WorkflowMetadata{Name: name, Description: description},
+		Spec:       wf,
+	}
+	out, err := yaml.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode golden path %s: %w", name, err)
+	}
+
+	return os.WriteFile(filepath.Join(workflowsDir, name+".yaml"), out, 0600)
+}
+
+// harnessPackageDir locates this package's source directory so fixtures and
+// migrations resolve no matter which package's tests use the harness
+func harnessPackageDir(t *testing.T) string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("Failed to locate testharness package directory")
+	}
+	return filepath.Dir(file)
+}
@@ -0,0 +1,109 @@
+package testharness
+
+import (
+	"context"
+	"errors"
+	"innominatus/internal/server"
+	"innominatus/internal/types"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const fixtureScore = `apiVersion: score.dev/v1b1
+metadata:
+  name: harness-app
+containers:
+  web:
+    image: nginx:latest
+environment:
+  type: development
+`
+
+func TestFakeProvisioner(t *testing.T) {
+	fake := NewFakeProvisioner()
+	step := types.Step{Name: "provision", Type: "terraform", Operation: "apply"}
+
+	require.NoError(t, fake.Execute(context.Background(), step, "app-a", 1, 10))
+
+	fake.FailStep("provision", errors.New("quota exceeded"))
+	assert.EqualError(t, fake.Execute(context.Background(), step, "app-b", 2, 20), "quota exceeded")
+
+	fake.FailStep("provision", nil)
+	require.NoError(t, fake.Execute(context.Background(), step, "app-b", 3, 30))
+
+	calls := fake.Calls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, "apply", calls[0].Operation)
+	assert.Equal(t, int64(20), calls[1].StepID)
+	assert.Equal(t, []string{"provision", "provision"}, fake.StepNames("app-b"))
+
+	fake.Reset()
+	assert.Empty(t, fake.Calls())
+}
+
+func TestLoadFixtureProviders(t *testing.T) {
+	workflowsDir := filepath.Join(t.TempDir(), "workflows")
+
+	registry, err := loadFixtureProviders(filepath.Join("testdata", "providers"), workflowsDir)
+	require.NoError(t, err)
+
+	provider, err := registry.GetProvider("fixture")
+	require.NoError(t, err)
+	assert.Len(t, provider.Workflows, 2)
+
+	// Only golden paths are exported for the execution endpoint
+	entries, err := os.ReadDir(workflowsDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	data, err := os.ReadFile(filepath.Join(workflowsDir, "fixture-deploy.yaml"))
+	require.NoError(t, err)
+
+	var spec types.WorkflowSpec
+	require.NoError(t, yaml.Unmarshal(data, &spec))
+	assert.Equal(t, "fixture-deploy", spec.Metadata.Name)
+	require.Len(t, spec.Spec.Steps, 4)
+	assert.Equal(t, "gitea-repo", spec.Spec.Steps[0].Type)
+	assert.Equal(t, "fixture-app", spec.Spec.Steps[0].RepoName)
+}
+
+func TestGoldenPathExecution(t *testing.T) {
+	h := New(t)
+
+	t.Run("runs every step through the fakes", func(t *testing.T) {
+		status, body := h.ExecuteGoldenPath("fixture-deploy", fixtureScore, nil)
+		require.Equal(t, http.StatusOK, status, body["error"])
+		assert.Equal(t, "completed", body["status"])
+
+		assert.Equal(t,
+			[]string{"create-repository", "provision-infrastructure", "deploy-application", "onboard-argocd"},
+			h.Fakes.StepNames("harness-app"))
+
+		var workflows server.PaginatedWorkflowsResponse
+		require.Equal(t, http.StatusOK, h.GetJSON("/api/workflows?app=harness-app", &workflows))
+		require.NotEmpty(t, workflows.Data)
+		assert.Equal(t, "golden-path-fixture-deploy", workflows.Data[0].WorkflowName)
+		assert.Equal(t, "completed", workflows.Data[0].Status)
+	})
+
+	t.Run("surfaces provisioner failures", func(t *testing.T) {
+		h.Fakes.Reset()
+		h.Fakes.FailStep("provision-infrastructure", errors.New("terraform apply failed"))
+
+		status, body := h.ExecuteGoldenPath("fixture-deploy", fixtureScore, nil)
+		assert.Equal(t, http.StatusInternalServerError, status)
+		assert.Contains(t, body["error"], "terraform apply failed")
+		assert.Equal(t, []string{"create-repository", "provision-infrastructure"}, h.Fakes.StepNames("harness-app"))
+	})
+
+	t.Run("rejects unknown golden paths", func(t *testing.T) {
+		status, _ := h.ExecuteGoldenPath("does-not-exist", fixtureScore, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
@@ -0,0 +1,141 @@
+package testharness
+
+import (
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/maintenance"
+	"innominatus/internal/types"
+	"innominatus/internal/users"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addTeamApplication stores an application owned by team
+func addTeamApplication(t *testing.T, h *Harness, name, team string) {
+	t.Helper()
+	require.NoError(t, h.DB.AddApplication(name, &types.ScoreSpec{}, team, "harness-admin"))
+}
+
+// TestApprovalsTeamScoping verifies approvals, including their plan summaries, are only
+// visible to the owning team and admins
+func TestApprovalsTeamScoping(t *testing.T) {
+	h := New(t)
+	addTeamApplication(t, h, "shop", "engineering")
+	addTeamApplication(t, h, "ledger", "payments")
+
+	repo := database.NewWorkflowRepository(h.DB)
+	approvals := make(map[string]int64)
+	for _, app := range []string{"shop", "ledger"} {
+		execution, err := repo.CreateWorkflowExecution(app, "deploy", 1)
+		require.NoError(t, err)
+		approval := &database.WorkflowApproval{ExecutionID: execution.ID, StepID: 1, StepName: "apply", ApplicationName: app, Summary: "Plan: 1 to add"}
+		require.NoError(t, h.DB.CreateWorkflowApproval(approval))
+		approvals[app] = approval.ID
+	}
+
+	engineer := h.As(&users.User{Username: "erin", Team: "engineering", Role: "user"})
+
+	var list struct {
+		Approvals []database.WorkflowApproval `json:"approvals"`
+	}
+	require.Equal(t, http.StatusOK, engineer.GetJSON("/api/approvals", &list))
+	require.Len(t, list.Approvals, 1)
+	assert.Equal(t, "shop", list.Approvals[0].ApplicationName)
+
+	assert.Equal(t, http.StatusOK, engineer.GetJSON(fmt.Sprintf("/api/approvals/%d", approvals["shop"]), nil))
+	assert.Equal(t, http.StatusForbidden, engineer.GetJSON(fmt.Sprintf("/api/approvals/%d", approvals["ledger"]), nil))
+	assert.Equal(t, http.StatusForbidden, engineer.PostJSON(fmt.Sprintf("/api/approvals/%d/approve", approvals["ledger"]), nil, nil))
+
+	require.Equal(t, http.StatusOK, h.GetJSON("/api/approvals", &list))
+	assert.Len(t, list.Approvals, 2, "admins see every team's approvals")
+}
+
+// TestMaintenanceTeamScoping verifies maintenance windows and deferred operations are only
+// listed for the owning team and admins
+func TestMaintenanceTeamScoping(t *testing.T) {
+	h := New(t)
+	addTeamApplication(t, h, "shop", "engineering")
+	addTeamApplication(t, h, "ledger", "payments")
+
+	resources := database.NewResourceRepository(h.DB)
+	for _, app := range []string{"shop", "ledger"} {
+		resource, err := resources.CreateResourceInstance(app, "db", "postgres", nil)
+		require.NoError(t, err)
+		window := &maintenance.Window{ApplicationName: app, Name: "weekend", Days: []string{"sat"}, StartTime: "02:00", DurationMinutes: 60, Timezone: "UTC", CreatedBy: "harness-admin"}
+		require.NoError(t, h.DB.CreateMaintenanceWindow(window))
+		op := &database.DeferredOperation{ApplicationName: app, ResourceID: resource.ID, Operation: "transition", TargetState: "terminated", RequestedBy: "harness-admin", ScheduledFor: time.Now().Add(24 * time.Hour)}
+		require.NoError(t, h.DB.CreateDeferredOperation(op))
+	}
+
+	engineer := h.As(&users.User{Username: "erin", Team: "engineering", Role: "user"})
+
+	var windows struct {
+		Windows []struct {
+			Window maintenance.Window `json:"window"`
+		} `json:"windows"`
+	}
+	require.Equal(t, http.StatusOK, engineer.GetJSON("/api/maintenance-windows", &windows))
+	require.Len(t, windows.Windows, 1)
+	assert.Equal(t, "shop", windows.Windows[0].Window.ApplicationName)
+
+	var operations struct {
+		Operations []database.DeferredOperation `json:"operations"`
+	}
+	require.Equal(t, http.StatusOK, engineer.GetJSON("/api/operations/upcoming", &operations))
+	require.Len(t, operations.Operations, 1)
+	assert.Equal(t, "shop", operations.Operations[0].ApplicationName)
+
+	require.Equal(t, http.StatusOK, engineer.GetJSON("/api/operations/upcoming?app=ledger", &operations))
+	assert.Empty(t, operations.Operations)
+
+	require.Equal(t, http.StatusOK, h.GetJSON("/api/operations/upcoming", &operations))
+	assert.Len(t, operations.Operations, 2, "admins see every team's operations")
+}
+
+// TestOrganizationAdminManagesApplications verifies admins of an organization can manage
+// the maintenance windows and approvals of its teams' applications, like the applications
+func TestOrganizationAdminManagesApplications(t *testing.T) {
+	h := New(t)
+	require.NoError(t, os.WriteFile("admin-config.yaml", []byte(`organizations:
+  commerce:
+    admins: [olga]
+    teams:
+      engineering: {}
+`), 0600))
+	addTeamApplication(t, h, "shop", "engineering")
+	addTeamApplication(t, h, "ledger", "payments")
+
+	repo := database.NewWorkflowRepository(h.DB)
+	execution, err := repo.CreateWorkflowExecution("shop", "deploy", 1)
+	require.NoError(t, err)
+	approval := &database.WorkflowApproval{ExecutionID: execution.ID, StepID: 1, StepName: "apply", ApplicationName: "shop", Summary: "Plan: 1 to add"}
+	require.NoError(t, h.DB.CreateWorkflowApproval(approval))
+
+	orgAdmin := h.As(&users.User{Username: "olga", Team: "platform-ops", Role: "user"})
+
+	window := map[string]interface{}{"application_name": "shop", "name": "weekend", "days": []string{"sat"}, "start_time": "02:00", "duration_minutes": 60}
+	assert.Equal(t, http.StatusCreated, orgAdmin.PostJSON("/api/maintenance-windows", window, nil))
+	window["application_name"] = "ledger"
+	assert.Equal(t, http.StatusForbidden, orgAdmin.PostJSON("/api/maintenance-windows", window, nil), "team outside the organization")
+
+	var windows struct {
+		Windows []struct {
+			Window maintenance.Window `json:"window"`
+		} `json:"windows"`
+	}
+	require.Equal(t, http.StatusOK, orgAdmin.GetJSON("/api/maintenance-windows", &windows))
+	require.Len(t, windows.Windows, 1)
+	assert.Equal(t, "shop", windows.Windows[0].Window.ApplicationName)
+
+	var list struct {
+		Approvals []database.WorkflowApproval `json:"approvals"`
+	}
+	require.Equal(t, http.StatusOK, orgAdmin.GetJSON("/api/approvals", &list))
+	require.Len(t, list.Approvals, 1)
+	assert.Equal(t, http.StatusOK, orgAdmin.GetJSON(fmt.Sprintf("/api/approvals/%d", approval.ID), nil))
+}
@@ -1,21 +1,49 @@
 package types
 
 type ScoreSpec struct {
-	APIVersion  string               `yaml:"apiVersion"`
-	Metadata    Metadata             `yaml:"metadata"`
-	Containers  map[string]Container `yaml:"containers"`
-	Resources   map[string]Resource  `yaml:"resources"`
-	Environment *Environment         `yaml:"environment,omitempty"`
-	Workflows   map[string]Workflow  `yaml:"workflows,omitempty"`
+	APIVersion     string               `yaml:"apiVersion"`
+	Metadata       Metadata             `yaml:"metadata"`
+	Containers     map[string]Container `yaml:"containers"`
+	InitContainers map[string]Container `yaml:"initContainers,omitempty"` // Run to completion in name order before the containers start, e.g. migrations
+	Resources      map[string]Resource  `yaml:"resources"`
+	Environment    *Environment         `yaml:"environment,omitempty"`
+	Workflows      map[string]Workflow  `yaml:"workflows,omitempty"`
+	DependsOn      []string             `yaml:"dependsOn,omitempty"` // Applications that must be deployed and healthy before this one
 }
 
 type Metadata struct {
-	Name string `yaml:"name"`
+	Name      string            `yaml:"name"`
+	Labels    map[string]string `yaml:"labels,omitempty"`    // Organisational labels (team, cost center, ...), checked by lint profiles
+	Variables map[string]string `yaml:"variables,omitempty"` // Application-level workflow variables (keys must be allowed by admin config)
 }
 
 type Container struct {
 	Image     string            `yaml:"image"`
+	Command   []string          `yaml:"command,omitempty"` // Overrides the image entrypoint
+	Args      []string          `yaml:"args,omitempty"`
 	Variables map[string]string `yaml:"variables"`
+	Volumes   []VolumeMount     `yaml:"volumes,omitempty"`
+	Files     []ContainerFile   `yaml:"files,omitempty"`
+}
+
+// ContainerFile is a file rendered into a container. Placeholders in the content such as
+// ${resources.db.host} are resolved from resource outputs unless NoExpand is set.
+type ContainerFile struct {
+	Target   string `yaml:"target"`             // Absolute path of the file in the container
+	Mode     string `yaml:"mode,omitempty"`     // Octal file mode, e.g. "0644"
+	Source   string `yaml:"source,omitempty"`   // Local file with the content; not supported by the server
+	Content  string `yaml:"content,omitempty"`  // Inline file content
+	NoExpand bool   `yaml:"noExpand,omitempty"` // Keep placeholders in the content as they are
+}
+
+// VolumeMount mounts a volume or storage resource into a container. Volume resources
+// are emptyDir volumes that live as long as the pod; storage resources are persistent
+// volume claims.
+type VolumeMount struct {
+	Source   string `yaml:"source"`             // ${resources.NAME} or NAME of a volume or storage resource
+	Target   string `yaml:"target"`             // Absolute mount path in the container
+	Path     string `yaml:"path,omitempty"`     // Sub path of the volume to mount
+	ReadOnly bool   `yaml:"readOnly,omitempty"` // Mount read-only
 }
 
 type Resource struct {
@@ -30,9 +58,62 @@ type Environment struct {
 }
 
 type Workflow struct {
-	Steps     []Step            `yaml:"steps"`
-	Variables map[string]string `yaml:"variables,omitempty"` // Workflow-level variables
-	Outputs   map[string]string `yaml:"outputs,omitempty"`   // Workflow outputs (bucket_name, endpoint, etc.)
+	Steps     []Step                    `yaml:"steps"`
+	Variables map[string]string         `yaml:"variables,omitempty"` // Workflow-level variables
+	Inputs    []WorkflowInput           `yaml:"inputs,omitempty"`    // Parameters callers pass, checked before the run starts
+	Outputs   map[string]WorkflowOutput `yaml:"outputs,omitempty"`   // Values the run produces (endpoint, namespace, etc.)
+	OnFailure string                    `yaml:"onFailure,omitempty"` // Rollback of created resources on failure: prompt (default) or rollback
+	Env       map[string]string         `yaml:"env,omitempty"`       // Environment variables of every step process
+	Preflight []PreflightCheck          `yaml:"preflight,omitempty"` // Checks run before the run starts; all failures are reported at once
+	Logs      *LogLimits                `yaml:"logs,omitempty"`      // Step output limits; unset fields use workflowPolicies.stepLogs of admin-config.yaml
+}
+
+// LogLimits caps the output each step of a workflow stores. Longer output keeps its
+// first headBytes and its last maxBytes-headBytes; the middle is dropped.
+type LogLimits struct {
+	MaxBytes  int `yaml:"maxBytes,omitempty"`
+	HeadBytes int `yaml:"headBytes,omitempty"` // Default: half of maxBytes
+}
+
+// PreflightCheck declares a condition that must hold before a workflow starts. String
+// fields may reference parameters as ${workflow.NAME}.
+type PreflightCheck struct {
+	Name       string            `yaml:"name,omitempty"`
+	Type       string            `yaml:"type"`                 // cluster-reachable, quota-available, providers-registered, parameters-valid, namespace-free
+	Context    string            `yaml:"context,omitempty"`    // Kubeconfig context of the cluster checks
+	Namespace  string            `yaml:"namespace,omitempty"`  // quota-available, namespace-free
+	Quota      map[string]string `yaml:"quota,omitempty"`      // quota-available: amount needed per quota resource, e.g. requests.cpu: "2"
+	Providers  []string          `yaml:"providers,omitempty"`  // providers-registered
+	Parameters map[string]string `yaml:"parameters,omitempty"` // parameters-valid: regular expression each parameter must match
+}
+
+// WorkflowInput declares a parameter of a workflow
+type WorkflowInput struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Required    bool   `yaml:"required,omitempty"`
+	Default     string `yaml:"default,omitempty"` // Used when the caller does not pass the parameter
+}
+
+// WorkflowOutput declares a value a workflow produces. Value references step outputs
+// and variables (${step.key}, ${workflow.VAR}) and is resolved when the run succeeds.
+type WorkflowOutput struct {
+	Value       string `yaml:"value"`
+	Type        string `yaml:"type,omitempty"`  // url, dashboard, connection_string, secret_ref, namespace, grafana_panel, log_query, metrics_query, text (default)
+	Query       string `yaml:"query,omitempty"` // Log or metrics query of log_query and metrics_query outputs
+	Description string `yaml:"description,omitempty"`
+}
+
+// UnmarshalYAML also accepts an output given as its value only: `endpoint: ${deploy.url}`
+func (o *WorkflowOutput) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err == nil {
+		*o = WorkflowOutput{Value: value}
+		return nil
+	}
+
+	type plain WorkflowOutput
+	return unmarshal((*plain)(o))
 }
 
 // WorkflowSpec represents a complete workflow document with metadata
@@ -44,8 +125,22 @@ type WorkflowSpec struct {
 }
 
 type WorkflowMetadata struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
+	Name             string                     `yaml:"name"`
+	Description      string                     `yaml:"description"`
+	ParameterSources map[string]ParameterSource `yaml:"parameterSources,omitempty"` // Parameters resolved server-side at execution time
+}
+
+// ParameterSource declares an external source for a workflow parameter
+type ParameterSource struct {
+	Type      string `yaml:"type"`                // vault, http, configmap
+	Path      string `yaml:"path,omitempty"`      // vault: KV v2 API path (e.g. secret/data/platform/db)
+	URL       string `yaml:"url,omitempty"`       // http: JSON endpoint
+	Namespace string `yaml:"namespace,omitempty"` // configmap: Kubernetes namespace
+	Name      string `yaml:"name,omitempty"`      // configmap: ConfigMap name
+	Key       string `yaml:"key"`                 // Secret field, ConfigMap key, or dotted JSON path
+	TTL       string `yaml:"ttl,omitempty"`       // Cache duration (default 5m, "0s" disables caching)
+	OnFailure string `yaml:"onFailure,omitempty"` // fail (default), default, skip
+	Default   string `yaml:"default,omitempty"`   // Value used when onFailure is "default"
 }
 
 type Step struct {
@@ -86,7 +181,7 @@ type Step struct {
 	When   string            `yaml:"when,omitempty"`   // Condition expression (e.g., "always", "on_success", "on_failure")
 	If     string            `yaml:"if,omitempty"`     // Condition that must be true to run
 	Unless string            `yaml:"unless,omitempty"` // Condition that must be false to run
-	Env    map[string]string `yaml:"env,omitempty"`    // Environment variables for condition evaluation
+	Env    map[string]string `yaml:"env,omitempty"`    // Environment variables of the step process, also used for condition evaluation
 	// New fields for output capture and passing
 	Outputs      []string          `yaml:"outputs,omitempty"`      // List of output variable names to capture
 	OutputFile   string            `yaml:"outputFile,omitempty"`   // File to read outputs from (JSON or key=value format)
@@ -96,4 +191,32 @@ type Step struct {
 	WorkingDir string                 `yaml:"workingDir,omitempty"` // Working directory for terraform
 	Variables  map[string]interface{} `yaml:"variables,omitempty"`  // Terraform variables
 	Config     map[string]interface{} `yaml:"config,omitempty"`     // Generic config map for flexible step configuration
+	// Reuse the result of a previous successful run with identical inputs
+	Cache *StepCacheConfig `yaml:"cache,omitempty"`
+	// Set by admin step overrides of a run; the step is skipped with this reason
+	SkipReason string `yaml:"-" json:"-"`
+}
+
+// StepOverrides are the changes an admin applied to the steps of one golden path run,
+// e.g. for an emergency fix. They are recorded on the workflow execution.
+type StepOverrides struct {
+	Reason      string                  `json:"reason"`
+	RequestedBy string                  `json:"requested_by,omitempty"` // Set by the server
+	Steps       map[string]StepOverride `json:"steps,omitempty"`        // By step name
+	Variables   map[string]string       `json:"variables,omitempty"`    // Workflow variables and inputs
+}
+
+// StepOverride changes one step of a run
+type StepOverride struct {
+	Skip  bool              `json:"skip,omitempty"`
+	Image string            `json:"image,omitempty"` // Replaces the tag of references to the same image repository
+	Env   map[string]string `json:"env,omitempty"`   // Set on the step process
+}
+
+// StepCacheConfig configures step-level result caching. The cache key covers the
+// interpolated step definition, the application's Score spec and Key entries.
+type StepCacheConfig struct {
+	Key   []string `yaml:"key,omitempty"`   // Extra key inputs, interpolated (e.g. "${workflow.version}")
+	Paths []string `yaml:"paths,omitempty"` // Artifacts restored on a hit (outputDir and outputFile are always included)
+	TTL   string   `yaml:"ttl,omitempty"`   // Entry lifetime (default 24h)
 }
@@ -0,0 +1,180 @@
+package usage
+
+import (
+	"innominatus/internal/database"
+	"innominatus/internal/orgs"
+	"sort"
+	"time"
+)
+
+// Trend lengths of the team usage in days
+const (
+	DefaultTrendDays = 14
+	MaxTrendDays     = 90
+)
+
+// TeamSources are the data the usage of a team is computed from
+type TeamSources struct {
+	Team            string
+	Applications    []*database.Application
+	Resources       []*database.ResourceInstance         // Resources of the team's applications
+	Lifecycles      []*database.ResourceLifecycle        // Resources of the team with their transitions
+	ActiveWorkflows []*database.WorkflowExecutionSummary // Running workflows of the team's applications
+	WorkflowRuns    []database.DailyWorkflowRuns         // Runs per day within the trend
+	Storage         Storage
+	Settings        orgs.Settings // Quotas in effect for the team
+}
+
+// TeamUsage is what a team currently uses, against its quotas, and how that developed
+type TeamUsage struct {
+	Team            string                               `json:"team"`
+	Organization    string                               `json:"organization,omitempty"`
+	Applications    int                                  `json:"applications"`
+	Resources       int                                  `json:"resources"` // Resources that are not terminated
+	ResourcesByType []ResourceTypeUsage                  `json:"resources_by_type"`
+	ActiveWorkflows []*database.WorkflowExecutionSummary `json:"active_workflows"`
+	Storage         Storage                              `json:"storage"`
+	Quotas          []QuotaUsage                         `json:"quotas"`
+	Trend           []TrendPoint                         `json:"trend"` // One point per UTC day, oldest first
+	GeneratedAt     time.Time                            `json:"generated_at"`
+}
+
+// ResourceTypeUsage counts the resources of one type
+type ResourceTypeUsage struct {
+	Type   string `json:"type"`
+	Total  int    `json:"total"`
+	Active int    `json:"active"`
+	Failed int    `json:"failed"`
+}
+
+// Storage is the space taken by the team's workflow logs, workspaces and uploaded files
+type Storage struct {
+	LogsBytes      int64 `json:"logs_bytes"`
+	WorkspaceBytes int64 `json:"workspace_bytes"`
+	FilesBytes     int64 `json:"files_bytes"`
+	TotalBytes     int64 `json:"total_bytes"`
+}
+
+// QuotaUsage compares the use of a quota with its limit
+type QuotaUsage struct {
+	Name  string `json:"name"`
+	Used  int    `json:"used"`
+	Limit int    `json:"limit"` // 0 means unlimited
+}
+
+// TrendPoint is the usage of one UTC day
+type TrendPoint struct {
+	Date               string  `json:"date"`             // YYYY-MM-DD
+	ActiveResources    int     `json:"active_resources"` // At the end of the day, or now for today
+	ResourceHours      float64 `json:"resource_hours"`
+	WorkflowRuns       int     `json:"workflow_runs"`
+	FailedWorkflowRuns int     `json:"failed_workflow_runs"`
+}
+
+// NewTeamUsage computes the usage of a team with a trend over the last days
+func NewTeamUsage(sources *TeamSources, now time.Time, days int) *TeamUsage {
+	now = now.UTC()
+	usage := &TeamUsage{
+		Team:            sources.Team,
+		Organization:    sources.Settings.Organization,
+		Applications:    len(sources.Applications),
+		ResourcesByType: []ResourceTypeUsage{},
+		ActiveWorkflows: sources.ActiveWorkflows,
+		Storage:         sources.Storage,
+		Trend:           []TrendPoint{},
+		GeneratedAt:     now,
+	}
+	if usage.ActiveWorkflows == nil {
+		usage.ActiveWorkflows = []*database.WorkflowExecutionSummary{}
+	}
+	usage.Storage.TotalBytes = usage.Storage.LogsBytes + usage.Storage.WorkspaceBytes + usage.Storage.FilesBytes
+
+	byType := make(map[string]*ResourceTypeUsage)
+	for _, resource := range sources.Resources {
+		if resource.State == database.ResourceStateTerminated {
+			continue
+		}
+		counts := byType[resource.ResourceType]
+		if counts == nil {
+			counts = &ResourceTypeUsage{Type: resource.ResourceType}
+			byType[resource.ResourceType] = counts
+		}
+		counts.Total++
+		usage.Resources++
+		switch {
+		case activeStates[resource.State]:
+			counts.Active++
+		case resource.State == database.ResourceStateFailed:
+			counts.Failed++
+		}
+	}
+	for _, counts := range byType {
+		usage.ResourcesByType = append(usage.ResourcesByType, *counts)
+	}
+	sort.Slice(usage.ResourcesByType, func(i, j int) bool {
+		return usage.ResourcesByType[i].Type < usage.ResourcesByType[j].Type
+	})
+
+	// Quotas count Score resources, as they are checked at deploy time
+	specResources := 0
+	for _, app := range sources.Applications {
+		if app.ScoreSpec != nil {
+			specResources += len(app.ScoreSpec.Resources)
+		}
+	}
+	usage.Quotas = []QuotaUsage{
+		{Name: "applications", Used: len(sources.Applications), Limit: sources.Settings.Quotas.MaxApplications},
+		{Name: "resources", Used: specResources, Limit: sources.Settings.Quotas.MaxResources},
+	}
+
+	runs := make(map[string]database.DailyWorkflowRuns, len(sources.WorkflowRuns))
+	for _, day := range sources.WorkflowRuns {
+		runs[day.Date] = day
+	}
+	today := now.Truncate(24 * time.Hour)
+	for i := days - 1; i >= 0; i-- {
+		start := today.AddDate(0, 0, -i)
+		end := start.Add(24 * time.Hour)
+		if end.After(now) {
+			end = now
+		}
+
+		point := TrendPoint{Date: start.Format("2006-01-02")}
+		var hours float64
+		for _, lifecycle := range sources.Lifecycles {
+			if activeAt(lifecycle, end) {
+				point.ActiveResources++
+			}
+			hours += activeHours(lifecycle, start, end)
+		}
+		point.ResourceHours = roundHours(hours)
+		point.WorkflowRuns = runs[point.Date].Runs
+		point.FailedWorkflowRuns = runs[point.Date].Failed
+		usage.Trend = append(usage.Trend, point)
+	}
+	return usage
+}
+
+// TrendStart returns the start of the oldest day of a trend over days
+func TrendStart(now time.Time, days int) time.Time {
+	return now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+}
+
+// activeAt reports whether a resource was in an active state at t
+func activeAt(lifecycle *database.ResourceLifecycle, t time.Time) bool {
+	resource := lifecycle.Resource
+	if resource.CreatedAt.After(t) {
+		return false
+	}
+	state := resource.State
+	if len(lifecycle.Transitions) > 0 {
+		state = lifecycle.Transitions[0].FromState
+	}
+	for _, transition := range lifecycle.Transitions {
+		if transition.TransitionedAt.After(t) {
+			break
+		}
+		state = transition.ToState
+	}
+	return activeStates[state]
+}
@@ -0,0 +1,58 @@
+package usage
+
+import (
+	"testing"
+
+	"innominatus/internal/database"
+	"innominatus/internal/orgs"
+	"innominatus/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTeamUsage(t *testing.T) {
+	lifecycles := testLifecycles()
+	sources := &TeamSources{
+		Team: "ecommerce",
+		Applications: []*database.Application{
+			{Name: "shop", ScoreSpec: &types.ScoreSpec{Resources: map[string]types.Resource{"db": {Type: "postgres"}, "queue": {Type: "rabbitmq"}}}},
+			{Name: "billing"},
+		},
+		Lifecycles: lifecycles,
+		WorkflowRuns: []database.DailyWorkflowRuns{
+			{Date: "2026-09-30", Runs: 4, Failed: 1},
+		},
+		Storage:  Storage{LogsBytes: 100, WorkspaceBytes: 2048, FilesBytes: 10},
+		Settings: orgs.Settings{Organization: "retail", Quotas: orgs.Quotas{MaxResources: 5}},
+	}
+	for _, lifecycle := range lifecycles {
+		sources.Resources = append(sources.Resources, lifecycle.Resource)
+	}
+
+	usage := NewTeamUsage(sources, at("2026-10-01T06:00:00Z"), 3)
+
+	assert.Equal(t, "retail", usage.Organization)
+	assert.Equal(t, 2, usage.Applications)
+	assert.Equal(t, 2, usage.Resources, "terminated resources are not counted")
+	assert.Equal(t, []ResourceTypeUsage{
+		{Type: "rabbitmq", Total: 1, Failed: 1},
+		{Type: "redis", Total: 1, Active: 1},
+	}, usage.ResourcesByType)
+	assert.NotNil(t, usage.ActiveWorkflows)
+	assert.Equal(t, int64(2158), usage.Storage.TotalBytes)
+	assert.Equal(t, []QuotaUsage{
+		{Name: "applications", Used: 2, Limit: 0},
+		{Name: "resources", Used: 2, Limit: 5},
+	}, usage.Quotas)
+
+	require.Len(t, usage.Trend, 3)
+	assert.Equal(t, TrendPoint{Date: "2026-09-29"}, usage.Trend[0])
+	assert.Equal(t, TrendPoint{Date: "2026-09-30", ActiveResources: 1, ResourceHours: 12, WorkflowRuns: 4, FailedWorkflowRuns: 1}, usage.Trend[1])
+	assert.Equal(t, TrendPoint{Date: "2026-10-01", ActiveResources: 1, ResourceHours: 6}, usage.Trend[2])
+}
+
+func TestTrendStart(t *testing.T) {
+	assert.Equal(t, at("2026-09-29T00:00:00Z"), TrendStart(at("2026-10-01T06:00:00Z"), 3))
+	assert.Equal(t, at("2026-10-01T00:00:00Z"), TrendStart(at("2026-10-01T06:00:00Z"), 1))
+}
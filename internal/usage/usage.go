@@ -0,0 +1,281 @@
+// Package usage derives chargeback records from resource instance lifecycles: how many
+// hours each resource was active in a month, attributed to the team owning its application.
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/database"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second
+
+// DefaultSizeKeys are the resource configuration keys read as the resource size
+var DefaultSizeKeys = []string{"size", "tier", "instance_type", "storage"}
+
+// activeStates are the lifecycle states a resource is billed in
+var activeStates = map[database.ResourceLifecycleState]bool{
+	database.ResourceStateActive:   true,
+	database.ResourceStateScaling:  true,
+	database.ResourceStateUpdating: true,
+	database.ResourceStateDegraded: true,
+}
+
+// csvHeader names the CSV columns in Record field order
+var csvHeader = []string{"month", "resource_id", "application", "team", "resource_name", "resource_type", "provider", "size", "hours_active"}
+
+// Config is the usage section of admin-config.yaml
+type Config struct {
+	WebhookURL string   `yaml:"webhookURL" json:"webhookURL"` // Billing endpoint usage reports are posted to
+	TokenEnv   string   `yaml:"tokenEnv" json:"tokenEnv"`     // Environment variable holding the webhook bearer token
+	SizeKeys   []string `yaml:"sizeKeys" json:"sizeKeys"`     // Configuration keys read as the size, first match wins (default: size, tier, instance_type, storage)
+}
+
+// Record is the usage of one resource instance in one month
+type Record struct {
+	Month        string  `json:"month"`
+	ResourceID   int64   `json:"resource_id"`
+	Application  string  `json:"application"`
+	Team         string  `json:"team"`
+	Organization string  `json:"organization,omitempty"`
+	ResourceName string  `json:"resource_name"`
+	ResourceType string  `json:"resource_type"`
+	Provider     string  `json:"provider,omitempty"`
+	Size         string  `json:"size,omitempty"`
+	HoursActive  float64 `json:"hours_active"`
+}
+
+// Report is the usage of all resources in a month, as returned by the API and posted to
+// the webhook
+type Report struct {
+	Month         string              `json:"month"`
+	GeneratedAt   time.Time           `json:"generated_at"`
+	Records       []Record            `json:"records"`
+	TotalHours    float64             `json:"total_hours"`
+	Organizations []OrganizationUsage `json:"organizations,omitempty"`
+}
+
+// OrganizationUsage is the usage of the teams of one organization in a month
+type OrganizationUsage struct {
+	Organization string   `json:"organization"`
+	Teams        []string `json:"teams"`
+	HoursActive  float64  `json:"hours_active"`
+}
+
+// ParseMonth returns the UTC bounds of a YYYY-MM month
+func ParseMonth(month string) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid month '%s': expected YYYY-MM", month)
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// NewReport computes the usage of a month. Hours after now are not counted, so the
+// current month reports usage so far. Resources that were not active in the month are
+// left out.
+func NewReport(month string, lifecycles []*database.ResourceLifecycle, sizeKeys []string, now time.Time) (*Report, error) {
+	start, end, err := ParseMonth(month)
+	if err != nil {
+		return nil, err
+	}
+	if now.Before(end) {
+		end = now
+	}
+	if len(sizeKeys) == 0 {
+		sizeKeys = DefaultSizeKeys
+	}
+
+	report := &Report{Month: month, GeneratedAt: now, Records: []Record{}}
+	for _, lifecycle := range lifecycles {
+		hours := activeHours(lifecycle, start, end)
+		if hours == 0 {
+			continue
+		}
+
+		resource := lifecycle.Resource
+		record := Record{
+			Month:        month,
+			ResourceID:   resource.ID,
+			Application:  resource.ApplicationName,
+			Team:         lifecycle.Team,
+			ResourceName: resource.ResourceName,
+			ResourceType: resource.ResourceType,
+			Size:         resourceSize(resource.Configuration, sizeKeys),
+			HoursActive:  hours,
+		}
+		if resource.Provider != nil {
+			record.Provider = *resource.Provider
+		}
+		report.Records = append(report.Records, record)
+		report.TotalHours += hours
+	}
+
+	sort.Slice(report.Records, func(i, j int) bool {
+		a, b := report.Records[i], report.Records[j]
+		if a.Team != b.Team {
+			return a.Team < b.Team
+		}
+		if a.Application != b.Application {
+			return a.Application < b.Application
+		}
+		return a.ResourceName < b.ResourceName
+	})
+	report.TotalHours = roundHours(report.TotalHours)
+	return report, nil
+}
+
+// RollupByOrganization sets the organization of each record and sums the hours per
+// organization. Records of teams outside any organization are not rolled up.
+func (r *Report) RollupByOrganization(orgOf func(team string) string) {
+	rollup := make(map[string]*OrganizationUsage)
+	for i := range r.Records {
+		record := &r.Records[i]
+		record.Organization = orgOf(record.Team)
+		if record.Organization == "" {
+			continue
+		}
+		org := rollup[record.Organization]
+		if org == nil {
+			org = &OrganizationUsage{Organization: record.Organization, Teams: []string{}}
+			rollup[record.Organization] = org
+		}
+		if len(org.Teams) == 0 || org.Teams[len(org.Teams)-1] != record.Team {
+			org.Teams = append(org.Teams, record.Team) // Records are sorted by team
+		}
+		org.HoursActive += record.HoursActive
+	}
+
+	r.Organizations = make([]OrganizationUsage, 0, len(rollup))
+	for _, org := range rollup {
+		org.HoursActive = roundHours(org.HoursActive)
+		r.Organizations = append(r.Organizations, *org)
+	}
+	sort.Slice(r.Organizations, func(i, j int) bool {
+		return r.Organizations[i].Organization < r.Organizations[j].Organization
+	})
+}
+
+// activeHours sums the time a resource spent in an active state between start and end.
+// The resource is in the from state of its first transition since creation.
+func activeHours(lifecycle *database.ResourceLifecycle, start, end time.Time) float64 {
+	resource := lifecycle.Resource
+	state := resource.State
+	if len(lifecycle.Transitions) > 0 {
+		state = lifecycle.Transitions[0].FromState
+	}
+
+	var active time.Duration
+	since := resource.CreatedAt
+	for _, transition := range lifecycle.Transitions {
+		if activeStates[state] {
+			active += overlap(since, transition.TransitionedAt, start, end)
+		}
+		state, since = transition.ToState, transition.TransitionedAt
+	}
+	if activeStates[state] {
+		active += overlap(since, end, start, end)
+	}
+	return roundHours(active.Hours())
+}
+
+// overlap returns how much of [from, to) lies within [start, end)
+func overlap(from, to, start, end time.Time) time.Duration {
+	if from.Before(start) {
+		from = start
+	}
+	if to.After(end) {
+		to = end
+	}
+	if !to.After(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+// resourceSize returns the first configured size key of a resource configuration
+func resourceSize(configuration map[string]interface{}, sizeKeys []string) string {
+	for _, key := range sizeKeys {
+		if value, ok := configuration[key]; ok && value != nil && value != "" {
+			return fmt.Sprint(value)
+		}
+	}
+	return ""
+}
+
+// WriteCSV writes the records of a report as CSV with a header row
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, record := range r.Records {
+		row := []string{
+			record.Month,
+			strconv.FormatInt(record.ResourceID, 10),
+			record.Application,
+			record.Team,
+			record.ResourceName,
+			record.ResourceType,
+			record.Provider,
+			record.Size,
+			strconv.FormatFloat(record.HoursActive, 'f', 2, 64),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Push posts a report as JSON to the configured billing webhook
+func Push(ctx context.Context, config Config, report *Report) error {
+	if config.WebhookURL == "" {
+		return fmt.Errorf("usage.webhookURL is not configured")
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage report: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create usage webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if config.TokenEnv != "" {
+		if token := os.Getenv(config.TokenEnv); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push usage report: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("usage webhook returned %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"innominatus/internal/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func transition(from, to database.ResourceLifecycleState, when string) *database.ResourceStateTransition {
+	return &database.ResourceStateTransition{FromState: from, ToState: to, TransitionedAt: at(when)}
+}
+
+func testLifecycles() []*database.ResourceLifecycle {
+	provider := "database-team"
+	return []*database.ResourceLifecycle{
+		{
+			// Active since August, terminated on September 11th
+			Resource: &database.ResourceInstance{ID: 1, ApplicationName: "shop", ResourceName: "db", ResourceType: "postgres",
+				State: database.ResourceStateTerminated, Provider: &provider, CreatedAt: at("2026-08-20T00:00:00Z"),
+				Configuration: map[string]interface{}{"size": "small", "storage": "10Gi"}},
+			Team: "ecommerce",
+			Transitions: []*database.ResourceStateTransition{
+				transition(database.ResourceStateRequested, database.ResourceStateProvisioning, "2026-08-20T00:00:00Z"),
+				transition(database.ResourceStateProvisioning, database.ResourceStateActive, "2026-08-20T01:00:00Z"),
+				transition(database.ResourceStateActive, database.ResourceStateTerminating, "2026-09-11T00:00:00Z"),
+				transition(database.ResourceStateTerminating, database.ResourceStateTerminated, "2026-09-11T00:30:00Z"),
+			},
+		},
+		{
+			// Active from September 30th 12:00, degraded counts as active
+			Resource: &database.ResourceInstance{ID: 2, ApplicationName: "billing", ResourceName: "cache", ResourceType: "redis",
+				State: database.ResourceStateDegraded, CreatedAt: at("2026-09-30T00:00:00Z"),
+				Configuration: map[string]interface{}{"tier": "premium"}},
+			Team: "finance",
+			Transitions: []*database.ResourceStateTransition{
+				transition(database.ResourceStateRequested, database.ResourceStateActive, "2026-09-30T12:00:00Z"),
+				transition(database.ResourceStateActive, database.ResourceStateDegraded, "2026-09-30T18:00:00Z"),
+			},
+		},
+		{
+			// Never became active
+			Resource: &database.ResourceInstance{ID: 3, ApplicationName: "shop", ResourceName: "queue", ResourceType: "rabbitmq",
+				State: database.ResourceStateFailed, CreatedAt: at("2026-09-02T00:00:00Z")},
+			Team: "ecommerce",
+			Transitions: []*database.ResourceStateTransition{
+				transition(database.ResourceStateRequested, database.ResourceStateFailed, "2026-09-02T00:10:00Z"),
+			},
+		},
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	report, err := NewReport("2026-09", testLifecycles(), nil, at("2026-10-15T00:00:00Z"))
+	require.NoError(t, err)
+
+	require.Len(t, report.Records, 2)
+	assert.Equal(t, Record{Month: "2026-09", ResourceID: 1, Application: "shop", Team: "ecommerce", ResourceName: "db",
+		ResourceType: "postgres", Provider: "database-team", Size: "small", HoursActive: 240}, report.Records[0])
+	assert.Equal(t, "billing", report.Records[1].Application)
+	assert.Equal(t, "premium", report.Records[1].Size)
+	assert.Equal(t, float64(12), report.Records[1].HoursActive)
+	assert.Equal(t, float64(252), report.TotalHours)
+}
+
+func TestNewReportCurrentMonth(t *testing.T) {
+	// Only hours up to now count
+	report, err := NewReport("2026-09", testLifecycles(), []string{"storage"}, at("2026-09-05T00:00:00Z"))
+	require.NoError(t, err)
+
+	require.Len(t, report.Records, 1)
+	assert.Equal(t, float64(96), report.Records[0].HoursActive)
+	assert.Equal(t, "10Gi", report.Records[0].Size)
+}
+
+func TestParseMonth(t *testing.T) {
+	start, end, err := ParseMonth("2026-12")
+	require.NoError(t, err)
+	assert.Equal(t, at("2026-12-01T00:00:00Z"), start)
+	assert.Equal(t, at("2027-01-01T00:00:00Z"), end)
+
+	for _, month := range []string{"", "2026-13", "2026-9-1", "September"} {
+		_, _, err := ParseMonth(month)
+		assert.Error(t, err, month)
+	}
+}
+
+func TestRollupByOrganization(t *testing.T) {
+	report, err := NewReport("2026-09", testLifecycles(), nil, at("2026-10-15T00:00:00Z"))
+	require.NoError(t, err)
+
+	report.RollupByOrganization(func(team string) string {
+		if team == "ecommerce" {
+			return "retail"
+		}
+		return ""
+	})
+
+	assert.Equal(t, "retail", report.Records[0].Organization)
+	assert.Equal(t, "", report.Records[1].Organization)
+	assert.Equal(t, []OrganizationUsage{{Organization: "retail", Teams: []string{"ecommerce"}, HoursActive: 240}}, report.Organizations)
+}
+
+func TestWriteCSV(t *testing.T) {
+	report, err := NewReport("2026-09", testLifecycles(), nil, at("2026-10-15T00:00:00Z"))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+	assert.Equal(t, "month,resource_id,application,team,resource_name,resource_type,provider,size,hours_active\n"+
+		"2026-09,1,shop,ecommerce,db,postgres,database-team,small,240.00\n"+
+		"2026-09,2,billing,finance,cache,redis,,premium,12.00\n", buf.String())
+}
+
+func TestPush(t *testing.T) {
+	t.Setenv("USAGE_TOKEN", "secret")
+	var received Report
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	report, err := NewReport("2026-09", testLifecycles(), nil, at("2026-10-15T00:00:00Z"))
+	require.NoError(t, err)
+
+	require.NoError(t, Push(context.Background(), Config{WebhookURL: server.URL, TokenEnv: "USAGE_TOKEN"}, report))
+	assert.Equal(t, "Bearer secret", auth)
+	assert.Equal(t, "2026-09", received.Month)
+	assert.Len(t, received.Records, 2)
+
+	t.Run("rejected", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unknown cost center", http.StatusUnprocessableEntity)
+		}))
+		defer failing.Close()
+
+		err := Push(context.Background(), Config{WebhookURL: failing.URL}, report)
+		assert.ErrorContains(t, err, "422 Unprocessable Entity: unknown cost center")
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		assert.Error(t, Push(context.Background(), Config{}, report))
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/Masterminds/semver/v3"
 )
 
 // AdminConfigValidator validates admin configuration
@@ -47,6 +49,66 @@ func (v *AdminConfigValidator) Validate() *ValidationResult {
 	// Validate ArgoCD configuration
 	v.validateArgoCDConfig(result)
 
+	// Validate logging configuration
+	v.validateLoggingConfig(result)
+
+	// Validate CLI version policy
+	v.validateCLIConfig(result)
+
+	// Validate provisioning concurrency limits
+	v.validateProvisioningConfig(result)
+
+	// Validate the clone directory and refresh of Git providers
+	if err := v.config.ProviderAssets.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	// Validate the garbage collection of temp assets
+	if err := v.config.TempAssets.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	// Validate the identity of kubernetes steps
+	v.validateKubernetesIdentity(result)
+
+	// Validate cloud credential grants
+	if err := v.config.WorkflowPolicies.CloudCredentials.Validate(); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("workflowPolicies.%v", err))
+	}
+
+	// Validate notification channels and templates
+	if err := v.config.Notifications.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	// Validate the incident alerting channel
+	if err := v.config.Alerting.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	// Validate the CMDB webhooks
+	if err := v.config.CMDB.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	// Validate the Slack user mapping
+	if err := v.config.Slack.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	// Validate the secret store and point out credentials kept in the file
+	if err := v.config.Secrets.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	for _, name := range v.config.PlaintextCredentials() {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s is stored in plaintext - consider referencing a secret, e.g. ${secrets.<path>.<key>}", name))
+	}
+
+	// Validate Score lint profiles
+	if err := v.config.ScoreLint.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
 	// Overall validity
 	result.Valid = len(result.Errors) == 0
 
@@ -137,6 +199,77 @@ func (v *AdminConfigValidator) validatePolicies(result *ValidationResult) {
 	}
 }
 
+func (v *AdminConfigValidator) validateLoggingConfig(result *ValidationResult) {
+	logging := v.config.Logging
+
+	// Both settings are optional; the server falls back to LOG_LEVEL/LOG_FORMAT or defaults
+	if logging.Level != "" {
+		if err := ValidateEnum("logging.level", strings.ToLower(logging.Level), []string{"debug", "info", "warn", "warning", "error", "fatal"}); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+	if logging.Format != "" {
+		if err := ValidateEnum("logging.format", strings.ToLower(logging.Format), []string{"json", "console", "pretty"}); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+	}
+}
+
+func (v *AdminConfigValidator) validateCLIConfig(result *ValidationResult) {
+	cli := v.config.CLI
+
+	var minVersion, recommended *semver.Version
+	var err error
+	if cli.MinVersion != "" {
+		if minVersion, err = semver.NewVersion(cli.MinVersion); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("cli.minVersion: invalid version %q", cli.MinVersion))
+		}
+	}
+	if cli.RecommendedVersion != "" {
+		if recommended, err = semver.NewVersion(cli.RecommendedVersion); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("cli.recommendedVersion: invalid version %q", cli.RecommendedVersion))
+		}
+	}
+	if minVersion != nil && recommended != nil && recommended.LessThan(minVersion) {
+		result.Warnings = append(result.Warnings, "cli.recommendedVersion is lower than cli.minVersion and has no effect")
+	}
+}
+
+func (v *AdminConfigValidator) validateProvisioningConfig(result *ValidationResult) {
+	provisioning := v.config.Provisioning
+
+	if provisioning.MaxConcurrent < 0 {
+		result.Errors = append(result.Errors, "provisioning.maxConcurrent must not be negative")
+	}
+	for resourceType, limit := range provisioning.ResourceTypes {
+		if limit < 1 {
+			result.Errors = append(result.Errors, fmt.Sprintf("provisioning.resourceTypes.%s must be at least 1", resourceType))
+		}
+	}
+	for provider, limit := range provisioning.Providers {
+		if limit < 1 {
+			result.Errors = append(result.Errors, fmt.Sprintf("provisioning.providers.%s must be at least 1", provider))
+		}
+	}
+	if provisioning.MaxConcurrent <= 1 && (len(provisioning.ResourceTypes) > 0 || len(provisioning.Providers) > 0) {
+		result.Warnings = append(result.Warnings, "provisioning limits per resource type or provider have no effect while provisioning.maxConcurrent is 1")
+	}
+}
+
+func (v *AdminConfigValidator) validateKubernetesIdentity(result *ValidationResult) {
+	identity := v.config.WorkflowPolicies.KubernetesIdentity
+
+	switch identity.Mode {
+	case "", "server":
+	case "impersonate", "kubeconfig":
+		if identity.ClusterRole == "cluster-admin" {
+			result.Warnings = append(result.Warnings, "workflowPolicies.kubernetesIdentity.clusterRole cluster-admin lets applications manage RBAC in their namespaces")
+		}
+	default:
+		result.Errors = append(result.Errors, fmt.Sprintf("workflowPolicies.kubernetesIdentity.mode '%s' is invalid (supported: server, impersonate, kubeconfig)", identity.Mode))
+	}
+}
+
 func (v *AdminConfigValidator) validateGiteaConfig(result *ValidationResult) {
 	gitea := v.config.Gitea
 
@@ -173,6 +306,10 @@ func (v *AdminConfigValidator) validateGiteaConfig(result *ValidationResult) {
 			result.Warnings = append(result.Warnings, err.Error())
 		}
 	}
+
+	if err := gitea.TeamProvisioning.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
 }
 
 func (v *AdminConfigValidator) validateArgoCDConfig(result *ValidationResult) {
@@ -203,6 +340,10 @@ func (v *AdminConfigValidator) validateArgoCDConfig(result *ValidationResult) {
 	} else if argocd.Password == "admin" || argocd.Password == "password" || len(argocd.Password) < 8 {
 		result.Warnings = append(result.Warnings, "ArgoCD password appears to be weak - consider using a stronger password (8+ characters)")
 	}
+
+	if err := argocd.TeamProjects.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
 }
 
 func (v *AdminConfigValidator) testGiteaConnectivity() error {
@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"fmt"
 	"innominatus/internal/database"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -19,17 +18,7 @@ type DatabaseValidator struct {
 
 // NewDatabaseValidator creates a new database validator
 func NewDatabaseValidator() *DatabaseValidator {
-	// Use the same environment variable logic as the database package
-	config := database.Config{
-		Host:     getEnvWithDefault("DB_HOST", "localhost"),
-		Port:     getEnvWithDefault("DB_PORT", "5432"),
-		User:     getEnvWithDefault("DB_USER", "postgres"),
-		Password: getEnvWithDefault("DB_PASSWORD", ""),
-		DBName:   getEnvWithDefault("DB_NAME", "idp_orchestrator"),
-		SSLMode:  getEnvWithDefault("DB_SSLMODE", "disable"),
-	}
-
-	return &DatabaseValidator{config: config}
+	return &DatabaseValidator{config: database.ConfigFromEnv()}
 }
 
 // NewDatabaseValidatorWithConfig creates a validator with custom config
@@ -301,15 +290,3 @@ func isLocalDatabase(host string) bool {
 	}
 	return false
 }
-
-func getEnvWithDefault(key, defaultValue string) string {
-	if value := getEnv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-// Simple environment variable getter for validation package
-func getEnv(key string) string {
-	return os.Getenv(key)
-}
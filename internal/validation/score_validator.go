@@ -155,6 +155,29 @@ func (sv *ScoreValidator) validateFieldFormats() []*errors.RichError {
 		}
 	}
 
+	// Validate dependsOn application names
+	seen := make(map[string]bool)
+	for _, upstream := range sv.spec.DependsOn {
+		var message string
+		switch {
+		case !isValidKubernetesName(upstream):
+			message = fmt.Sprintf("Invalid application name in dependsOn: %s", upstream)
+		case upstream == sv.spec.Metadata.Name:
+			message = fmt.Sprintf("Application %s cannot depend on itself", upstream)
+		case seen[upstream]:
+			message = fmt.Sprintf("Duplicate application in dependsOn: %s", upstream)
+		}
+		seen[upstream] = true
+		if message == "" {
+			continue
+		}
+		lineNum := sv.findFieldLine("dependsOn")
+		err := errors.NewRichError(errors.CategoryValidation, errors.SeverityError, message).
+			WithLocation(sv.filePath, lineNum, 0, sv.getLine(lineNum))
+		_ = err.WithSuggestion("List the metadata.name of each application that must be deployed first")
+		errs = append(errs, err)
+	}
+
 	return errs
 }
 
@@ -243,6 +266,15 @@ func (sv *ScoreValidator) validateContainers() []*errors.RichError {
 		}
 	}
 
+	for containerName, container := range sv.spec.InitContainers {
+		if container.Image == "" {
+			lineNum := sv.findFieldLineInSection("initContainers", containerName)
+			err := errors.NewRichError(errors.CategoryValidation, errors.SeverityError, fmt.Sprintf("Init container '%s' missing image", containerName)).WithLocation(sv.filePath, lineNum, 0, sv.getLine(lineNum))
+			_ = err.WithSuggestion("Add an image to the init container definition")
+			errs = append(errs, err)
+		}
+	}
+
 	return errs
 }
 
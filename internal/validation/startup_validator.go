@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"innominatus/internal/database"
 	"os"
 )
 
@@ -12,6 +13,12 @@ type StartupValidator struct {
 
 // NewStartupValidator creates a comprehensive startup validator
 func NewStartupValidator() (*StartupValidator, error) {
+	return NewStartupValidatorWithDatabase(database.ConfigFromEnv())
+}
+
+// NewStartupValidatorWithDatabase creates a startup validator that checks the given
+// database connection settings instead of the DB_* environment variables
+func NewStartupValidatorWithDatabase(dbConfig database.Config) (*StartupValidator, error) {
 	suite := NewValidationSuite("Application Startup")
 
 	// Add admin configuration validator if config exists
@@ -32,8 +39,8 @@ func NewStartupValidator() (*StartupValidator, error) {
 		suite.AddValidator(goldenPathsValidator)
 	}
 
-	// Add database validator (always present as it uses environment variables)
-	dbValidator := NewDatabaseValidator()
+	// Add database validator (always present)
+	dbValidator := NewDatabaseValidatorWithConfig(dbConfig)
 	suite.AddValidator(dbValidator)
 
 	// Add users validator if users file exists
@@ -86,7 +93,13 @@ func ValidateConfiguration() *ValidationSummary {
 
 // ValidateConfigurationWithExit is a convenience function that exits on validation failure
 func ValidateConfigurationWithExit() {
-	validator, err := NewStartupValidator()
+	ValidateConfigurationWithDatabaseOrExit(database.ConfigFromEnv())
+}
+
+// ValidateConfigurationWithDatabaseOrExit validates the configuration with the given database
+// connection settings and exits on validation failure
+func ValidateConfigurationWithDatabaseOrExit(dbConfig database.Config) {
+	validator, err := NewStartupValidatorWithDatabase(dbConfig)
 	if err != nil {
 		fmt.Printf("❌ Failed to create startup validator: %v\n", err)
 		os.Exit(1)
@@ -171,6 +171,22 @@ func (c *Client) GetSecret(appNamespace, secretName string) (map[string]interfac
 	return nil, fmt.Errorf("invalid secret response format")
 }
 
+// ReadKV reads the data of a KV v2 secret at an arbitrary API path (e.g. secret/data/platform/db)
+func (c *Client) ReadKV(path string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := c.makeRequest("GET", "/v1/"+strings.TrimPrefix(path, "/"), nil, &result); err != nil {
+		return nil, err
+	}
+
+	if data, ok := result["data"].(map[string]interface{}); ok {
+		if secretData, ok := data["data"].(map[string]interface{}); ok {
+			return secretData, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid secret response format")
+}
+
 // DeleteSecret deletes a secret from the application's Vault space
 func (c *Client) DeleteSecret(appNamespace, secretName string) error {
 	fmt.Printf("🔐 Deleting secret: %s from app namespace: %s\n", secretName, appNamespace)
@@ -3,6 +3,8 @@ package workflow
 import (
 	"fmt"
 	"innominatus/internal/types"
+	"innominatus/pkg/sdk"
+	"strings"
 	"time"
 )
 
@@ -16,6 +18,17 @@ type WorkflowAnalysis struct {
 	Warnings        []string             `json:"warnings"`
 	Recommendations []string             `json:"recommendations"`
 	Summary         AnalysisSummary      `json:"summary"`
+	ResourcePlans   []ResourcePlan       `json:"resourcePlans,omitempty"` // Dry-run diffs of provisioners that support planning
+}
+
+// ResourcePlan is the dry-run diff of a spec resource from its provisioner (see sdk.Planner)
+type ResourcePlan struct {
+	Resource  string    `json:"resource"`
+	Type      string    `json:"type"`
+	Operation string    `json:"operation"` // create or update
+	Plan      *sdk.Plan `json:"plan,omitempty"`
+	Diff      string    `json:"diff,omitempty"`
+	Error     string    `json:"error,omitempty"`
 }
 
 // DependencyAnalysis represents dependencies between workflow steps
@@ -116,6 +129,13 @@ func NewWorkflowAnalyzer() *WorkflowAnalyzer {
 
 // AnalyzeSpec analyzes a Score specification and returns detailed workflow analysis
 func (a *WorkflowAnalyzer) AnalyzeSpec(spec *types.ScoreSpec) (*WorkflowAnalysis, error) {
+	return a.AnalyzeSpecChanges(spec, nil)
+}
+
+// AnalyzeSpecChanges analyzes a Score specification that is deployed on top of existing
+// state. The provisioning steps of resources in unchanged are left out of the plan, as a
+// deployment does not touch them.
+func (a *WorkflowAnalyzer) AnalyzeSpecChanges(spec *types.ScoreSpec, unchanged map[string]bool) (*WorkflowAnalysis, error) {
 	analysis := &WorkflowAnalysis{
 		Spec:            spec,
 		Dependencies:    []DependencyAnalysis{},
@@ -135,6 +155,9 @@ func (a *WorkflowAnalyzer) AnalyzeSpec(spec *types.ScoreSpec) (*WorkflowAnalysis
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze dependencies: %w", err)
 	}
+	if len(unchanged) > 0 {
+		dependencies = skipProvisioning(dependencies, unchanged)
+	}
 	analysis.Dependencies = dependencies
 
 	// Create execution plan
@@ -375,6 +398,33 @@ func (a *WorkflowAnalyzer) generateImpliedWorkflow(spec *types.ScoreSpec, resour
 	return dependencies
 }
 
+// skipProvisioning removes the provisioning steps of the given resources and the
+// dependencies other steps have on them
+func skipProvisioning(dependencies []DependencyAnalysis, resources map[string]bool) []DependencyAnalysis {
+	skipped := make(map[string]bool)
+	for _, dep := range dependencies {
+		if dep.StepType == "resource-provisioning" && resources[strings.TrimPrefix(dep.StepName, "provision-")] {
+			skipped[dep.StepName] = true
+		}
+	}
+
+	kept := make([]DependencyAnalysis, 0, len(dependencies)-len(skipped))
+	for _, dep := range dependencies {
+		if skipped[dep.StepName] {
+			continue
+		}
+		dependsOn := make([]string, 0, len(dep.DependsOn))
+		for _, name := range dep.DependsOn {
+			if !skipped[name] {
+				dependsOn = append(dependsOn, name)
+			}
+		}
+		dep.DependsOn = dependsOn
+		kept = append(kept, dep)
+	}
+	return kept
+}
+
 // createExecutionPlan creates an optimized execution plan with parallelization
 func (a *WorkflowAnalyzer) createExecutionPlan(dependencies []DependencyAnalysis) (ExecutionPlan, error) {
 	plan := ExecutionPlan{
@@ -511,6 +561,11 @@ func (a *WorkflowAnalyzer) buildParallelGroups(dependencies []DependencyAnalysis
 	return groups
 }
 
+// StepDuration returns the estimated duration of a step of the given type
+func (a *WorkflowAnalyzer) StepDuration(stepType string) time.Duration {
+	return a.getStepDuration(stepType)
+}
+
 // Helper methods
 
 func (a *WorkflowAnalyzer) getStepDuration(stepType string) time.Duration {
@@ -760,11 +815,18 @@ func getDefaultStepDurations() map[string]time.Duration {
 		"health-check":          1 * time.Minute,
 		"gitea-repo":            30 * time.Second,
 		"git-commit-manifests":  1 * time.Minute,
+		"render":                30 * time.Second,
 		"argocd-app":            2 * time.Minute,
 		"vault-setup":           2 * time.Minute,
 		"database-migration":    3 * time.Minute,
+		"db-migrate":            3 * time.Minute,
 		"cost-analysis":         2 * time.Minute,
 		"tagging":               1 * time.Minute,
+		"helm":                  5 * time.Minute,
+		"cluster-readiness":     2 * time.Minute,
+		"register-cluster":      10 * time.Second,
+		"http-check":            30 * time.Second,
+		"teardown":              1 * time.Minute,
 	}
 }
 
@@ -101,6 +101,34 @@ func TestAnalyzeSpec_WithWorkflows(t *testing.T) {
 	assert.Contains(t, stepNames, "run-tests")
 }
 
+func TestAnalyzeSpecChanges_SkipsUnchangedResources(t *testing.T) {
+	analyzer := NewWorkflowAnalyzer()
+
+	spec := &types.ScoreSpec{
+		Metadata:   types.Metadata{Name: "test-app"},
+		Containers: map[string]types.Container{"web": {Image: "nginx:latest"}},
+		Resources: map[string]types.Resource{
+			"db":    {Type: "postgres"},
+			"cache": {Type: "redis"},
+		},
+	}
+
+	full, err := analyzer.AnalyzeSpec(spec)
+	require.NoError(t, err)
+	changed, err := analyzer.AnalyzeSpecChanges(spec, map[string]bool{"db": true})
+	require.NoError(t, err)
+
+	var steps []string
+	for _, dep := range changed.Dependencies {
+		steps = append(steps, dep.StepName)
+		assert.NotContains(t, dep.DependsOn, "provision-db")
+	}
+	assert.Contains(t, steps, "provision-cache")
+	assert.NotContains(t, steps, "provision-db")
+	assert.Len(t, changed.Dependencies, len(full.Dependencies)-1)
+	assert.Less(t, changed.EstimatedTime, full.EstimatedTime)
+}
+
 func TestAnalyzeResources(t *testing.T) {
 	analyzer := NewWorkflowAnalyzer()
 
@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"innominatus/internal/database"
+)
+
+// ApplicationStore provides the stored Score spec of an application
+type ApplicationStore interface {
+	GetApplication(name string) (*database.Application, error)
+}
+
+// ApplicationFileStore provides the files uploaded with an application's Score spec.
+// Application stores that implement it also make those files available to workflows.
+type ApplicationFileStore interface {
+	ListApplicationFiles(appName string) ([]*database.ApplicationFile, error)
+}
+
+// ApplicationHostnameStore provides the hostnames reserved for an application by the naming
+// convention. Application stores that implement it also make those hostnames available to
+// workflows.
+type ApplicationHostnameStore interface {
+	ListHostnameReservations(appName string) ([]*database.HostnameReservation, error)
+}
+
+// applicationFilePrefix prefixes the variables holding uploaded files: ${files.values.yaml}
+const applicationFilePrefix = "files."
+
+// namingPrefix prefixes the variables holding reserved hostnames: ${naming.hostname}
+const namingPrefix = "naming."
+
+// SetApplicationStore makes Score metadata.variables available to every workflow of an application
+func (e *WorkflowExecutor) SetApplicationStore(store ApplicationStore) {
+	e.applications = store
+}
+
+// applicationVariables returns the metadata.variables of the application's stored Score spec,
+// the files uploaded with it and its reserved hostnames. Variables were validated against the admin allow-list
+// when the spec was deployed.
+func (e *WorkflowExecutor) applicationVariables(appName string) map[string]string {
+	if e.applications == nil || appName == "" {
+		return nil
+	}
+
+	variables := map[string]string{}
+	environment := ""
+	app, err := e.applications.GetApplication(appName)
+	if err == nil && app != nil && app.ScoreSpec != nil {
+		for key, value := range app.ScoreSpec.Metadata.Variables {
+			variables[key] = value
+		}
+		if app.ScoreSpec.Environment != nil {
+			environment = app.ScoreSpec.Environment.Type
+		}
+	}
+
+	// Files uploaded with the spec are read by their name, e.g. ${files.values.yaml}
+	if store, ok := e.applications.(ApplicationFileStore); ok {
+		files, err := store.ListApplicationFiles(appName)
+		if err != nil {
+			e.logger.WarnWithFields("Failed to load application files", map[string]interface{}{
+				"app_name": appName,
+				"error":    err.Error(),
+			})
+		}
+		for _, file := range files {
+			variables[applicationFilePrefix+file.Name] = file.Content
+		}
+	}
+
+	// Hostnames per environment, e.g. ${naming.staging.hostname}; ${naming.hostname} is the
+	// one of the environment in the spec
+	if store, ok := e.applications.(ApplicationHostnameStore); ok {
+		reservations, err := store.ListHostnameReservations(appName)
+		if err != nil {
+			e.logger.WarnWithFields("Failed to load application hostnames", map[string]interface{}{
+				"app_name": appName,
+				"error":    err.Error(),
+			})
+		}
+		for _, reservation := range reservations {
+			variables[namingPrefix+reservation.Environment+".hostname"] = reservation.Hostname
+			variables[namingPrefix+reservation.Environment+".url"] = reservation.URL
+			if reservation.Environment == environment || len(reservations) == 1 {
+				variables[namingPrefix+"hostname"] = reservation.Hostname
+				variables[namingPrefix+"url"] = reservation.URL
+			}
+		}
+	}
+
+	if len(variables) == 0 {
+		return nil
+	}
+	return variables
+}
+
+// initApplicationVariables seeds the execution context with application variables.
+// They have the lowest precedence: golden path parameters and workflow variables override them.
+func (e *WorkflowExecutor) initApplicationVariables(appName, workflowName string) {
+	variables := e.applicationVariables(appName)
+	if len(variables) == 0 {
+		return
+	}
+
+	e.execContext.SetWorkflowVariables(variables)
+	e.logger.InfoWithFields("Initialized application variables", map[string]interface{}{
+		"app_name":       appName,
+		"workflow_name":  workflowName,
+		"variable_count": len(variables),
+	})
+}
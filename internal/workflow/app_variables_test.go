@@ -0,0 +1,127 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeApplicationStore map[string]*types.ScoreSpec
+
+func (f fakeApplicationStore) GetApplication(name string) (*database.Application, error) {
+	spec, ok := f[name]
+	if !ok {
+		return nil, fmt.Errorf("application not found: %s", name)
+	}
+	return &database.Application{Name: name, ScoreSpec: spec}, nil
+}
+
+func TestApplicationVariables(t *testing.T) {
+	store := fakeApplicationStore{
+		"shop": {Metadata: types.Metadata{Name: "shop", Variables: map[string]string{
+			"cost_center":   "cc-1234",
+			"domain_suffix": "shop.example.com",
+		}}},
+	}
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetApplicationStore(store)
+
+	seen := map[string]string{}
+	executor.RegisterStepExecutor("capture", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+		for _, key := range []string{"cost_center", "domain_suffix"} {
+			seen[key], _ = executor.execContext.GetVariable(key)
+		}
+		return nil
+	})
+
+	workflow := types.Workflow{
+		Variables: map[string]string{"domain_suffix": "override.example.com"},
+		Steps:     []types.Step{{Name: "capture", Type: "capture"}},
+	}
+
+	require.NoError(t, executor.ExecuteWorkflowWithName("shop", "deploy", workflow))
+	assert.Equal(t, "cc-1234", seen["cost_center"])
+	// Workflow variables take precedence over application variables
+	assert.Equal(t, "override.example.com", seen["domain_suffix"])
+
+	t.Run("unknown application", func(t *testing.T) {
+		assert.Nil(t, executor.applicationVariables("missing"))
+	})
+
+	t.Run("no store", func(t *testing.T) {
+		assert.Nil(t, NewWorkflowExecutor(NewMockWorkflowRepository()).applicationVariables("shop"))
+	})
+}
+
+type fakeApplicationFileStore struct {
+	fakeApplicationStore
+	files map[string][]*database.ApplicationFile
+}
+
+func (f fakeApplicationFileStore) ListApplicationFiles(appName string) ([]*database.ApplicationFile, error) {
+	return f.files[appName], nil
+}
+
+func TestApplicationFiles(t *testing.T) {
+	store := fakeApplicationFileStore{
+		fakeApplicationStore: fakeApplicationStore{"shop": {Metadata: types.Metadata{Name: "shop"}}},
+		files: map[string][]*database.ApplicationFile{
+			"shop": {{Name: "values.yaml", Content: "replicas: 3\n"}},
+		},
+	}
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetApplicationStore(store)
+
+	var rendered string
+	executor.RegisterStepExecutor("capture", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+		rendered = executor.execContext.replaceVariables("${files.values.yaml}", nil)
+		return nil
+	})
+
+	workflow := types.Workflow{Steps: []types.Step{{Name: "capture", Type: "capture"}}}
+	require.NoError(t, executor.ExecuteWorkflowWithName("shop", "deploy", workflow))
+	assert.Equal(t, "replicas: 3\n", rendered)
+}
+
+type fakeApplicationHostnameStore struct {
+	fakeApplicationStore
+	reservations []*database.HostnameReservation
+}
+
+func (f fakeApplicationHostnameStore) ListHostnameReservations(appName string) ([]*database.HostnameReservation, error) {
+	return f.reservations, nil
+}
+
+func TestApplicationHostnames(t *testing.T) {
+	store := fakeApplicationHostnameStore{
+		fakeApplicationStore: fakeApplicationStore{"shop": {
+			Metadata:    types.Metadata{Name: "shop"},
+			Environment: &types.Environment{Type: "staging"},
+		}},
+		reservations: []*database.HostnameReservation{
+			{Hostname: "shop.payments.dev.example.com", URL: "https://shop.payments.dev.example.com", Environment: "dev"},
+			{Hostname: "shop.payments.staging.example.com", URL: "https://shop.payments.staging.example.com", Environment: "staging"},
+		},
+	}
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetApplicationStore(store)
+
+	var rendered string
+	executor.RegisterStepExecutor("capture", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+		rendered = executor.execContext.replaceVariables("${naming.url} ${naming.dev.hostname}", nil)
+		return nil
+	})
+
+	workflow := types.Workflow{Steps: []types.Step{{Name: "capture", Type: "capture"}}}
+	require.NoError(t, executor.ExecuteWorkflowWithName("shop", "deploy", workflow))
+	// ${naming.url} is the hostname of the environment in the spec
+	assert.Equal(t, "https://shop.payments.staging.example.com shop.payments.dev.example.com", rendered)
+}
@@ -0,0 +1,131 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/events"
+	"innominatus/internal/logging"
+	"innominatus/internal/types"
+	"time"
+)
+
+// approvalPollInterval controls how often a waiting step checks for a decision
+var approvalPollInterval = 5 * time.Second
+
+// defaultApprovalTimeout is used when the step does not specify a timeout
+const defaultApprovalTimeout = 24 * time.Hour
+
+// ApprovalStore defines the persistence needed for approval gates
+type ApprovalStore interface {
+	CreateWorkflowApproval(approval *database.WorkflowApproval) error
+	GetWorkflowApproval(id int64) (*database.WorkflowApproval, error)
+	DecideWorkflowApproval(id int64, status, decidedBy, comment string) error
+}
+
+// SetApprovalStore enables approval gates on the executor
+func (e *WorkflowExecutor) SetApprovalStore(store ApprovalStore) {
+	e.approvals = store
+}
+
+// stepRequiresApproval reports whether a step is configured to wait for approval
+func stepRequiresApproval(step types.Step) bool {
+	if step.Config == nil {
+		return false
+	}
+	required, _ := step.Config["require_approval"].(bool)
+	return required
+}
+
+// waitForApproval creates an approval gate and blocks until it is approved, rejected or times out.
+// planHash binds the approval to the reviewed terraform plan, if any.
+func (e *WorkflowExecutor) waitForApproval(ctx context.Context, step types.Step, appName string, execID, stepID int64, summary, artifactPath, planHash string) error {
+	logger := logging.FromContext(ctx, "workflow")
+
+	if e.approvals == nil {
+		return fmt.Errorf("step '%s' requires approval but approval gates are not configured", step.Name)
+	}
+
+	environment := ""
+	if step.Config != nil {
+		environment, _ = step.Config["environment"].(string)
+	}
+
+	approval := &database.WorkflowApproval{
+		ExecutionID:     execID,
+		StepID:          stepID,
+		StepName:        step.Name,
+		ApplicationName: appName,
+		Environment:     environment,
+		Summary:         summary,
+		ArtifactPath:    artifactPath,
+		PlanHash:        planHash,
+	}
+	if err := e.approvals.CreateWorkflowApproval(approval); err != nil {
+		return fmt.Errorf("failed to create approval gate: %w", err)
+	}
+
+	logger.Infof("Waiting for approval #%d (step '%s')", approval.ID, step.Name)
+	if e.eventBus != nil {
+		e.eventBus.Publish(events.NewEvent(
+			events.EventTypeApprovalRequested,
+			appName,
+			"workflow-executor",
+			map[string]interface{}{
+				"approval_id":  approval.ID,
+				"execution_id": execID,
+				"step_name":    step.Name,
+				"environment":  environment,
+				"summary":      summary,
+			},
+		))
+	}
+	if e.repo != nil && stepID > 0 {
+		_ = e.addStepLogs(stepID, fmt.Sprintf("Waiting for approval #%d\n", approval.ID))
+	}
+
+	timeout := defaultApprovalTimeout
+	if step.Timeout > 0 {
+		timeout = time.Duration(step.Timeout) * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+
+	ticker := time.NewTicker(approvalPollInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := e.approvals.GetWorkflowApproval(approval.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check approval #%d: %w", approval.ID, err)
+		}
+
+		switch current.Status {
+		case database.ApprovalStatusApproved:
+			decidedBy := ""
+			if current.DecidedBy != nil {
+				decidedBy = *current.DecidedBy
+			}
+			logger.Infof("Approval #%d granted by %s", approval.ID, decidedBy)
+			return nil
+		case database.ApprovalStatusRejected:
+			reason := ""
+			if current.Comment != nil {
+				reason = ": " + *current.Comment
+			}
+			return fmt.Errorf("approval #%d was rejected%s", approval.ID, reason)
+		case database.ApprovalStatusExpired:
+			return fmt.Errorf("approval #%d expired", approval.ID)
+		}
+
+		if time.Now().After(deadline) {
+			_ = e.approvals.DecideWorkflowApproval(approval.ID, database.ApprovalStatusExpired, "system", "approval timed out")
+			return fmt.Errorf("approval #%d timed out after %s", approval.ID, timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/types"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeApprovalStore keeps approvals in memory and optionally decides them on creation
+type fakeApprovalStore struct {
+	mu        sync.Mutex
+	approvals map[int64]*database.WorkflowApproval
+	decision  string
+}
+
+func newFakeApprovalStore(decision string) *fakeApprovalStore {
+	return &fakeApprovalStore{approvals: make(map[int64]*database.WorkflowApproval), decision: decision}
+}
+
+func (f *fakeApprovalStore) CreateWorkflowApproval(approval *database.WorkflowApproval) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	approval.ID = int64(len(f.approvals) + 1)
+	approval.Status = database.ApprovalStatusPending
+	stored := *approval
+	f.approvals[approval.ID] = &stored
+	if f.decision != "" {
+		go func(id int64) {
+			time.Sleep(5 * time.Millisecond)
+			_ = f.DecideWorkflowApproval(id, f.decision, "reviewer", "looks good")
+		}(approval.ID)
+	}
+	return nil
+}
+
+func (f *fakeApprovalStore) GetWorkflowApproval(id int64) (*database.WorkflowApproval, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	approval, ok := f.approvals[id]
+	if !ok {
+		return nil, fmt.Errorf("workflow approval not found")
+	}
+	copied := *approval
+	return &copied, nil
+}
+
+func (f *fakeApprovalStore) DecideWorkflowApproval(id int64, status, decidedBy, comment string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	approval, ok := f.approvals[id]
+	if !ok || approval.Status != database.ApprovalStatusPending {
+		return fmt.Errorf("workflow approval %d not found or already decided", id)
+	}
+	approval.Status = status
+	approval.DecidedBy = &decidedBy
+	approval.Comment = &comment
+	return nil
+}
+
+func TestWaitForApproval(t *testing.T) {
+	original := approvalPollInterval
+	approvalPollInterval = time.Millisecond
+	t.Cleanup(func() { approvalPollInterval = original })
+
+	step := types.Step{Name: "apply", Type: "approval", Config: map[string]interface{}{"environment": "production"}}
+
+	t.Run("approved", func(t *testing.T) {
+		executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+		store := newFakeApprovalStore(database.ApprovalStatusApproved)
+		executor.SetApprovalStore(store)
+
+		err := executor.waitForApproval(context.Background(), step, "my-app", 1, 0, "Plan: 1 to add", "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "production", store.approvals[1].Environment)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+		executor.SetApprovalStore(newFakeApprovalStore(database.ApprovalStatusRejected))
+
+		err := executor.waitForApproval(context.Background(), step, "my-app", 1, 0, "", "", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rejected")
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+		executor.SetApprovalStore(newFakeApprovalStore(""))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := executor.waitForApproval(ctx, step, "my-app", 1, 0, "", "", "")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+
+		err := executor.waitForApproval(context.Background(), step, "my-app", 1, 0, "", "", "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not configured")
+	})
+}
@@ -0,0 +1,22 @@
+package workflow
+
+import (
+	"innominatus/internal/admin"
+	"innominatus/internal/argoprojects"
+)
+
+// teamArgoCDProject returns the ArgoCD project of the application's team when
+// argocd.teamProjects is enabled, creating or updating the project first. It returns ""
+// when the Application keeps the default project.
+func (e *WorkflowExecutor) teamArgoCDProject(appName string) (string, error) {
+	apps, ok := e.applications.(argoprojects.Applications)
+	if !ok || appName == "" {
+		return "", nil
+	}
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil || !adminConfig.ArgoCD.TeamProjects.Enabled || adminConfig.ArgoCD.URL == "" {
+		return "", nil
+	}
+	provisioner := argoprojects.NewProvisioner(adminConfig.ArgoCD.TeamProjects, adminConfig.ArgoCD.URL, adminConfig.ArgoCD.Username, adminConfig.ArgoCD.Password)
+	return provisioner.ProjectFor(apps, appName)
+}
@@ -0,0 +1,72 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/cloudcreds"
+	"innominatus/internal/logging"
+	"innominatus/internal/types"
+	"sort"
+	"time"
+)
+
+type provisioningKey struct{}
+
+// provisioningTarget is the resource a workflow run provisions
+type provisioningTarget struct {
+	provider     string
+	resourceType string
+}
+
+// WithProvisioning returns a context for a workflow run that provisions a resource of
+// resourceType through provider. Its steps get the cloud credentials granted to them.
+func WithProvisioning(ctx context.Context, provider, resourceType string) context.Context {
+	return context.WithValue(ctx, provisioningKey{}, provisioningTarget{provider: provider, resourceType: resourceType})
+}
+
+// SetCloudCredentialBroker enables short-lived cloud credentials for the steps of
+// provisioning workflows (workflowPolicies.cloudCredentials). nil turns them off.
+func (e *WorkflowExecutor) SetCloudCredentialBroker(broker *cloudcreds.Broker) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cloudCredentials = broker
+}
+
+// stepCloudCredentials issues the cloud credentials granted to a step and returns them
+// as environment variables
+func (e *WorkflowExecutor) stepCloudCredentials(ctx context.Context, step types.Step, appName string) ([]string, error) {
+	e.mu.RLock()
+	broker := e.cloudCredentials
+	e.mu.RUnlock()
+
+	target, ok := ctx.Value(provisioningKey{}).(provisioningTarget)
+	if broker == nil || !ok {
+		return nil, nil
+	}
+
+	issued, err := broker.Issue(ctx, cloudcreds.Target{
+		App:          appName,
+		Provider:     target.provider,
+		ResourceType: target.resourceType,
+		StepType:     step.Type,
+		StepName:     step.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var env []string
+	for _, creds := range issued {
+		logging.FromContext(ctx, "workflow").InfoWithFields("Issued cloud credentials for step", map[string]interface{}{
+			"step_name":  step.Name,
+			"grant":      creds.Grant,
+			"cloud":      creds.Cloud,
+			"expires_at": creds.ExpiresAt.Format(time.RFC3339),
+		})
+		for k, v := range creds.Env {
+			env = append(env, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
+	sort.Strings(env)
+	return env, nil
+}
@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/cloudcreds"
+	"innominatus/internal/types"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStepCloudCredentials verifies steps of provisioning workflows get the credentials
+// granted to their provider, and other workflows get none
+func TestStepCloudCredentials(t *testing.T) {
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse><AssumeRoleWithWebIdentityResult><Credentials>
+<AccessKeyId>ASIATEST</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>session</SessionToken>
+<Expiration>2030-01-01T00:00:00Z</Expiration></Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`))
+	}))
+	defer sts.Close()
+
+	token := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(token, []byte("irsa-token"), 0600))
+
+	broker, err := cloudcreds.NewBroker(cloudcreds.Config{Grants: []cloudcreds.Grant{{
+		Name: "rds", Cloud: cloudcreds.CloudAWS, Providers: []string{"database-team"}, RoleARN: "arn:aws:iam::123456789012:role/rds",
+	}}})
+	require.NoError(t, err)
+	broker.SetEndpoints(cloudcreds.Endpoints{AWSSTS: sts.URL})
+	broker.SetTokenFiles(token, "")
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetCloudCredentialBroker(broker)
+	var output string
+	executor.RegisterStepExecutor("print-env", func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		out, err := executor.stepCommand(ctx, "env").Output()
+		output = string(out)
+		return err
+	})
+	workflow := types.Workflow{Steps: []types.Step{{Name: "print", Type: "print-env"}}}
+
+	ctx := WithProvisioning(context.Background(), "database-team", "postgres")
+	require.NoError(t, executor.ExecuteWorkflowWithContext(ctx, "shop", "provision-postgres", workflow))
+	env := envMap(strings.Split(strings.TrimSpace(output), "\n"))
+	assert.Equal(t, "ASIATEST", env["AWS_ACCESS_KEY_ID"])
+	assert.Equal(t, "session", env["AWS_SESSION_TOKEN"])
+
+	require.NoError(t, executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", workflow))
+	env = envMap(strings.Split(strings.TrimSpace(output), "\n"))
+	assert.NotContains(t, env, "AWS_ACCESS_KEY_ID")
+}
@@ -0,0 +1,303 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/types"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// clusterRegistry is implemented by repositories that keep the multi-cluster registry
+type clusterRegistry interface {
+	RegisterCluster(cluster *database.Cluster) error
+}
+
+const (
+	defaultHelmTimeout      = "10m"
+	defaultReadinessTimeout = "5m"
+)
+
+// configString returns a string value from a step config, or "" when unset
+func configString(config map[string]interface{}, key string) string {
+	if value, ok := config[key]; ok && value != nil {
+		return strings.TrimSpace(fmt.Sprintf("%v", value))
+	}
+	return ""
+}
+
+// configBool returns a boolean step config value, or def when unset
+func configBool(config map[string]interface{}, key string, def bool) bool {
+	switch v := config[key].(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return b
+		}
+		return def
+	default:
+		return def
+	}
+}
+
+// configStrings returns a list step config value; a comma-separated string is also accepted
+func configStrings(config map[string]interface{}, key string) []string {
+	var values []string
+	switch v := config[key].(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s := strings.TrimSpace(fmt.Sprintf("%v", item)); s != "" {
+				values = append(values, s)
+			}
+		}
+	case []string:
+		for _, item := range v {
+			if s := strings.TrimSpace(item); s != "" {
+				values = append(values, s)
+			}
+		}
+	case string:
+		for _, item := range strings.Split(v, ",") {
+			if s := strings.TrimSpace(item); s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
+
+// configStringMap returns a map step config value with string values
+func configStringMap(config map[string]interface{}, key string) map[string]string {
+	values := map[string]string{}
+	if m, ok := config[key].(map[string]interface{}); ok {
+		for k, v := range m {
+			values[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return values
+}
+
+// helmNamespace returns the namespace of a helm step's release, which defaults to the release name
+func helmNamespace(step types.Step, config map[string]interface{}) string {
+	if step.Namespace != "" {
+		return step.Namespace
+	}
+	if namespace := configString(config, "namespace"); namespace != "" {
+		return namespace
+	}
+	return configString(config, "release")
+}
+
+// helmArgs builds the arguments of an idempotent "helm upgrade --install" for a helm step.
+// valuesFile is passed with -f when not empty.
+func helmArgs(step types.Step, config map[string]interface{}, valuesFile string) ([]string, error) {
+	release := configString(config, "release")
+	chart := configString(config, "chart")
+	if release == "" || chart == "" {
+		return nil, fmt.Errorf("helm step requires 'release' and 'chart' in config")
+	}
+
+	args := []string{"upgrade", "--install", release, chart, "--namespace", helmNamespace(step, config)}
+	if configBool(config, "create_namespace", true) {
+		args = append(args, "--create-namespace")
+	}
+	if repo := configString(config, "repo"); repo != "" {
+		args = append(args, "--repo", repo)
+	}
+	if version := configString(config, "version"); version != "" {
+		args = append(args, "--version", version)
+	}
+	if kubeContext := configString(config, "kube_context"); kubeContext != "" {
+		args = append(args, "--kube-context", kubeContext)
+	}
+	if valuesFile != "" {
+		args = append(args, "-f", valuesFile)
+	}
+
+	set := configStringMap(config, "set")
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "--set", fmt.Sprintf("%s=%s", k, set[k]))
+	}
+
+	if configBool(config, "wait", true) {
+		timeout := configString(config, "timeout")
+		if timeout == "" {
+			timeout = defaultHelmTimeout
+		}
+		args = append(args, "--wait", "--timeout", timeout)
+	}
+
+	return args, nil
+}
+
+// rolloutStatusArgs builds the kubectl arguments that wait for a "namespace/name" deployment
+func rolloutStatusArgs(deployment, kubeContext, timeout string) ([]string, error) {
+	namespace, name, ok := strings.Cut(deployment, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid deployment '%s' (expected namespace/name)", deployment)
+	}
+	args := []string{"rollout", "status", "deployment/" + name, "-n", namespace, "--timeout=" + timeout}
+	if kubeContext != "" {
+		args = append(args, "--context", kubeContext)
+	}
+	return args, nil
+}
+
+// helmInstall installs or upgrades a Helm release and returns the helm output. A release
+// that did not exist before is tracked for rollback.
+func (e *WorkflowExecutor) helmInstall(ctx context.Context, step types.Step, execID int64) (string, error) {
+	logger := logging.FromContext(ctx, "workflow")
+	config := e.execContext.InterpolateResourceParams(step.Config, step.Env)
+
+	valuesFile := ""
+	if values, ok := config["values"].(map[string]interface{}); ok && len(values) > 0 {
+		data, err := yaml.Marshal(values)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal helm values: %w", err)
+		}
+		tmpFile, err := os.CreateTemp("", "helm-values-*.yaml")
+		if err != nil {
+			return "", fmt.Errorf("failed to create values file: %w", err)
+		}
+		defer func() { _ = os.Remove(tmpFile.Name()) }()
+		if _, err := tmpFile.Write(data); err != nil {
+			_ = tmpFile.Close()
+			return "", fmt.Errorf("failed to write values file: %w", err)
+		}
+		_ = tmpFile.Close()
+		valuesFile = tmpFile.Name()
+	}
+
+	args, err := helmArgs(step, config, valuesFile)
+	if err != nil {
+		return "", err
+	}
+
+	release, namespace := args[2], helmNamespace(step, config)
+	kubeContext := configString(config, "kube_context")
+	existed := helmReleaseExists(ctx, release, namespace, kubeContext)
+
+	logger.Infof("Installing Helm release %s (%s)", args[2], args[3])
+
+	// #nosec G204 - arguments come from the workflow definition
+	cmd := e.stepCommand(ctx, "helm", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("helm upgrade --install %s failed: %w, output: %s", args[2], err, string(output))
+	}
+
+	if !existed {
+		e.trackCreated(ctx, execID, step.Name, compensationHelmRelease, fmt.Sprintf("helm release %s/%s", namespace, release),
+			map[string]string{"release": release, "namespace": namespace, "kube_context": kubeContext})
+	}
+
+	logger.Infof("Helm release %s installed", release)
+	return string(output), nil
+}
+
+// clusterReadiness checks that the API server is ready and waits for the listed
+// deployments to roll out
+func (e *WorkflowExecutor) clusterReadiness(ctx context.Context, step types.Step) (string, error) {
+	logger := logging.FromContext(ctx, "workflow")
+	config := e.execContext.InterpolateResourceParams(step.Config, step.Env)
+
+	kubeContext := configString(config, "kube_context")
+	timeout := configString(config, "timeout")
+	if timeout == "" {
+		timeout = defaultReadinessTimeout
+	}
+
+	var logs strings.Builder
+
+	readyz := []string{"get", "--raw", "/readyz"}
+	if kubeContext != "" {
+		readyz = append(readyz, "--context", kubeContext)
+	}
+	// #nosec G204 - arguments come from the workflow definition
+	output, err := e.stepCommand(ctx, "kubectl", readyz...).CombinedOutput()
+	fmt.Fprintf(&logs, "$ kubectl %s\n%s\n", strings.Join(readyz, " "), output)
+	if err != nil {
+		return logs.String(), fmt.Errorf("cluster API server is not ready: %w, output: %s", err, string(output))
+	}
+
+	for _, deployment := range configStrings(config, "deployments") {
+		args, err := rolloutStatusArgs(deployment, kubeContext, timeout)
+		if err != nil {
+			return logs.String(), err
+		}
+
+		logger.Infof("Waiting for deployment %s", deployment)
+		// #nosec G204 - arguments come from the workflow definition
+		output, err := e.stepCommand(ctx, "kubectl", args...).CombinedOutput()
+		fmt.Fprintf(&logs, "$ kubectl %s\n%s\n", strings.Join(args, " "), output)
+		if err != nil {
+			return logs.String(), fmt.Errorf("deployment %s is not ready: %w, output: %s", deployment, err, string(output))
+		}
+	}
+
+	logger.Info("Cluster is ready")
+	return logs.String(), nil
+}
+
+// registerCluster adds the bootstrapped cluster to the multi-cluster registry. The
+// cluster name defaults to the application name the golden path runs for.
+func (e *WorkflowExecutor) registerCluster(ctx context.Context, step types.Step, appName string, execID int64) error {
+	logger := logging.FromContext(ctx, "workflow")
+	config := e.execContext.InterpolateResourceParams(step.Config, step.Env)
+
+	registry, ok := e.repo.(clusterRegistry)
+	if !ok {
+		return fmt.Errorf("register-cluster step requires a database-backed workflow repository")
+	}
+
+	cluster := &database.Cluster{
+		Name:        configString(config, "name"),
+		Environment: configString(config, "environment"),
+		APIServer:   configString(config, "api_server"),
+		KubeContext: configString(config, "kube_context"),
+		Labels:      configStringMap(config, "labels"),
+		Components:  configStrings(config, "components"),
+		ExecutionID: &execID,
+	}
+	if cluster.Name == "" {
+		cluster.Name = appName
+	}
+	if cluster.Environment == "" {
+		return fmt.Errorf("register-cluster step requires 'environment' in config")
+	}
+
+	if cluster.APIServer == "" {
+		args := []string{"config", "view", "--minify", "-o", "jsonpath={.clusters[0].cluster.server}"}
+		if cluster.KubeContext != "" {
+			args = append(args, "--context", cluster.KubeContext)
+		}
+		// #nosec G204 - arguments come from the workflow definition
+		output, err := e.stepCommand(ctx, "kubectl", args...).Output()
+		if err != nil {
+			return fmt.Errorf("failed to discover API server of cluster %s: %w", cluster.Name, err)
+		}
+		cluster.APIServer = strings.TrimSpace(string(output))
+	}
+
+	if err := registry.RegisterCluster(cluster); err != nil {
+		return err
+	}
+
+	e.execContext.SetStepOutput(step.Name, "cluster_name", cluster.Name)
+	e.execContext.SetStepOutput(step.Name, "api_server", cluster.APIServer)
+
+	logger.Infof("Registered cluster %s (%s) for environment %s", cluster.Name, cluster.APIServer, cluster.Environment)
+	return nil
+}
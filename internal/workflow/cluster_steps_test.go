@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"innominatus/internal/database"
+	"innominatus/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelmArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		step       types.Step
+		valuesFile string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name: "defaults",
+			step: types.Step{Config: map[string]interface{}{"release": "argocd", "chart": "argo-cd"}},
+			want: []string{"upgrade", "--install", "argocd", "argo-cd", "--namespace", "argocd", "--create-namespace", "--wait", "--timeout", "10m"},
+		},
+		{
+			name: "repo, version, context and sorted set values",
+			step: types.Step{Config: map[string]interface{}{
+				"release":      "cert-manager",
+				"chart":        "cert-manager",
+				"repo":         "https://charts.jetstack.io",
+				"version":      "v1.16.1",
+				"namespace":    "cert-manager",
+				"kube_context": "kind-prod",
+				"set":          map[string]interface{}{"replicaCount": 2, "crds.enabled": "true"},
+				"timeout":      "15m",
+			}},
+			valuesFile: "values.yaml",
+			want: []string{"upgrade", "--install", "cert-manager", "cert-manager", "--namespace", "cert-manager", "--create-namespace",
+				"--repo", "https://charts.jetstack.io", "--version", "v1.16.1", "--kube-context", "kind-prod", "-f", "values.yaml",
+				"--set", "crds.enabled=true", "--set", "replicaCount=2", "--wait", "--timeout", "15m"},
+		},
+		{
+			name: "step namespace without wait",
+			step: types.Step{Namespace: "ingress", Config: map[string]interface{}{
+				"release": "ingress-nginx", "chart": "ingress-nginx", "wait": false, "create_namespace": "false",
+			}},
+			want: []string{"upgrade", "--install", "ingress-nginx", "ingress-nginx", "--namespace", "ingress"},
+		},
+		{
+			name:    "missing chart",
+			step:    types.Step{Config: map[string]interface{}{"release": "argocd"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := helmArgs(tt.step, tt.step.Config, tt.valuesFile)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, args)
+		})
+	}
+}
+
+func TestRolloutStatusArgs(t *testing.T) {
+	args, err := rolloutStatusArgs("argocd/argocd-server", "kind-prod", "5m")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rollout", "status", "deployment/argocd-server", "-n", "argocd", "--timeout=5m", "--context", "kind-prod"}, args)
+
+	_, err = rolloutStatusArgs("argocd-server", "", "5m")
+	assert.Error(t, err)
+}
+
+// clusterRegistryRepository records clusters registered by workflow steps
+type clusterRegistryRepository struct {
+	*MockWorkflowRepository
+	clusters []*database.Cluster
+}
+
+func (r *clusterRegistryRepository) RegisterCluster(cluster *database.Cluster) error {
+	r.clusters = append(r.clusters, cluster)
+	return nil
+}
+
+func TestRegisterClusterStep(t *testing.T) {
+	repo := &clusterRegistryRepository{MockWorkflowRepository: NewMockWorkflowRepository()}
+	executor := NewWorkflowExecutor(repo)
+
+	workflow := types.Workflow{Steps: []types.Step{{
+		Name: "register",
+		Type: "register-cluster",
+		Config: map[string]interface{}{
+			"environment":  "${workflow.environment}",
+			"api_server":   "https://10.0.0.1:6443",
+			"kube_context": "kind-prod",
+			"components":   []interface{}{"argocd", "cert-manager"},
+			"labels":       map[string]interface{}{"region": "eu-west-1"},
+		},
+	}}}
+
+	err := executor.ExecuteWorkflowWithContext(context.Background(), "prod-eu-1", "golden-path-cluster-bootstrap", workflow,
+		map[string]string{"environment": "production"})
+	require.NoError(t, err)
+
+	require.Len(t, repo.clusters, 1)
+	cluster := repo.clusters[0]
+	assert.Equal(t, "prod-eu-1", cluster.Name)
+	assert.Equal(t, "production", cluster.Environment)
+	assert.Equal(t, "https://10.0.0.1:6443", cluster.APIServer)
+	assert.Equal(t, []string{"argocd", "cert-manager"}, cluster.Components)
+	assert.Equal(t, map[string]string{"region": "eu-west-1"}, cluster.Labels)
+	require.NotNil(t, cluster.ExecutionID)
+	assert.Equal(t, int64(1), *cluster.ExecutionID)
+}
+
+func TestRegisterClusterStepWithoutRegistry(t *testing.T) {
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+
+	workflow := types.Workflow{Steps: []types.Step{{
+		Name:   "register",
+		Type:   "register-cluster",
+		Config: map[string]interface{}{"environment": "staging", "api_server": "https://10.0.0.2:6443"},
+	}}}
+
+	err := executor.ExecuteWorkflowWithContext(context.Background(), "staging-1", "golden-path-cluster-bootstrap", workflow)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database-backed workflow repository")
+}
+
+func TestValidateClusterBootstrapSteps(t *testing.T) {
+	validator := NewWorkflowValidator()
+
+	errs := validator.ValidateWorkflow(&types.Workflow{Steps: []types.Step{
+		{Name: "install", Type: "helm", Config: map[string]interface{}{"release": "argocd"}},
+		{Name: "ready", Type: "cluster-readiness", Config: map[string]interface{}{"deployments": []interface{}{"argocd/argocd-server"}}},
+		{Name: "register", Type: "register-cluster", Config: map[string]interface{}{"name": "prod-eu-1"}},
+	}})
+
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "helm step requires 'chart'")
+	assert.Contains(t, errs[1].Error(), "register-cluster step requires 'environment'")
+}
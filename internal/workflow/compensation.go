@@ -0,0 +1,203 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"strings"
+)
+
+// On-failure modes of a workflow (the workflow's onFailure field)
+const (
+	// OnFailurePrompt keeps the resources a failed run created until a rollback is requested
+	OnFailurePrompt = "prompt"
+	// OnFailureRollback removes the resources a failed run created right away
+	OnFailureRollback = "rollback"
+)
+
+// compensationStore is implemented by repositories that track the resources an execution
+// created, so a failed run can be rolled back
+type compensationStore interface {
+	RecordCompensation(compensation *database.WorkflowCompensation) error
+	ListCompensations(executionID int64) ([]*database.WorkflowCompensation, error)
+	UpdateCompensationStatus(id int64, status string, errorMessage *string) error
+}
+
+// CompensatorFunc removes a resource recorded by a workflow step and returns its output
+type CompensatorFunc func(ctx context.Context, params map[string]string) (string, error)
+
+// RegisterCompensator adds or replaces the compensator for a kind of created resource
+func (e *WorkflowExecutor) RegisterCompensator(kind string, compensator CompensatorFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.compensators[kind] = compensator
+}
+
+// trackCreated records a resource a step created. Failures are logged; the step itself succeeded.
+func (e *WorkflowExecutor) trackCreated(ctx context.Context, execID int64, stepName, kind, target string, params map[string]string) {
+	store, ok := e.repo.(compensationStore)
+	if !ok {
+		return
+	}
+
+	compensation := &database.WorkflowCompensation{
+		ExecutionID: execID,
+		StepName:    stepName,
+		Kind:        kind,
+		Target:      target,
+		Params:      params,
+	}
+	if err := store.RecordCompensation(compensation); err != nil {
+		logging.FromContext(ctx, "workflow").Warnf("Failed to track %s for rollback: %v", target, err)
+	}
+}
+
+// compensateFailure handles the resources a failed run created. With onFailure: rollback
+// they are removed right away; otherwise they stay pending until a rollback is requested.
+func (e *WorkflowExecutor) compensateFailure(ctx context.Context, execID int64, onFailure string) {
+	store, ok := e.repo.(compensationStore)
+	if !ok {
+		return
+	}
+	logger := logging.FromContext(ctx, "workflow")
+
+	compensations, err := store.ListCompensations(execID)
+	if err != nil {
+		logger.Warnf("Failed to list resources created by execution %d: %v", execID, err)
+		return
+	}
+	if len(compensations) == 0 {
+		return
+	}
+
+	if onFailure != OnFailureRollback {
+		logger.Warnf("%d resource(s) created before the failure remain; roll them back with 'innominatus-ctl workflow rollback %d'", len(compensations), execID)
+		return
+	}
+
+	logger.Infof("Rolling back %d resource(s) created before the failure", len(compensations))
+	if _, err := e.RollbackExecution(ctx, execID); err != nil {
+		logger.Errorf("%v", err)
+	}
+}
+
+// RollbackExecution removes, newest first, the resources a failed execution created that
+// are not rolled back yet. Each removal is added to the execution as a compensation step.
+func (e *WorkflowExecutor) RollbackExecution(ctx context.Context, execID int64) ([]*database.WorkflowCompensation, error) {
+	store, ok := e.repo.(compensationStore)
+	if !ok {
+		return nil, fmt.Errorf("rollback requires a database-backed workflow repository")
+	}
+
+	execution, err := e.repo.GetWorkflowExecution(execID)
+	if err != nil {
+		return nil, err
+	}
+	if execution.Status != database.WorkflowStatusFailed {
+		return nil, fmt.Errorf("only failed executions can be rolled back (execution %d is %s)", execID, execution.Status)
+	}
+
+	compensations, err := store.ListCompensations(execID)
+	if err != nil {
+		return nil, err
+	}
+
+	stepNumber := execution.TotalSteps
+	for _, step := range execution.Steps {
+		if step.StepNumber > stepNumber {
+			stepNumber = step.StepNumber
+		}
+	}
+
+	var failures []string
+	for i := len(compensations) - 1; i >= 0; i-- {
+		compensation := compensations[i]
+		if compensation.Status == database.CompensationStatusRolledBack {
+			continue
+		}
+		stepNumber++
+		if err := e.compensate(ctx, store, compensation, stepNumber); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return compensations, errors.New("rollback incomplete: " + strings.Join(failures, "; "))
+	}
+	return compensations, nil
+}
+
+// compensate removes a single resource and records the outcome as a compensation step
+func (e *WorkflowExecutor) compensate(ctx context.Context, store compensationStore, compensation *database.WorkflowCompensation, stepNumber int) error {
+	logger := logging.FromContext(ctx, "workflow")
+
+	stepRecord, err := e.repo.CreateWorkflowStep(compensation.ExecutionID, stepNumber, "rollback-"+compensation.StepName,
+		database.StepTypeCompensation, map[string]interface{}{"kind": compensation.Kind, "target": compensation.Target})
+	if err != nil {
+		return fmt.Errorf("failed to record rollback of %s: %w", compensation.Target, err)
+	}
+	if err := e.setStepStatus(stepRecord.ID, database.StepStatusRunning, nil); err != nil {
+		logger.Warnf("Failed to update step status: %v", err)
+	}
+
+	logs, err := e.removeCreated(ctx, store, compensation)
+	if logs != "" {
+		if logErr := e.addStepLogs(stepRecord.ID, logs); logErr != nil {
+			logger.Warnf("Failed to store step logs: %v", logErr)
+		}
+	}
+
+	if err != nil {
+		_ = e.setStepStatus(stepRecord.ID, database.StepStatusFailed, compensation.ErrorMessage)
+		return err
+	}
+	_ = e.setStepStatus(stepRecord.ID, database.StepStatusCompleted, nil)
+	return nil
+}
+
+// removeCreated runs the compensator of a tracked resource and records whether it was removed
+func (e *WorkflowExecutor) removeCreated(ctx context.Context, store compensationStore, compensation *database.WorkflowCompensation) (string, error) {
+	logger := logging.FromContext(ctx, "workflow")
+
+	e.mu.RLock()
+	compensator, exists := e.compensators[compensation.Kind]
+	e.mu.RUnlock()
+
+	var logs string
+	var err error
+	if !exists {
+		err = fmt.Errorf("no compensator for %s", compensation.Kind)
+	} else {
+		logs, err = compensator(e.withCommandEnvironment(ctx), compensation.Params)
+	}
+
+	if err != nil {
+		errorMsg := fmt.Sprintf("failed to roll back %s: %v", compensation.Target, err)
+		if updateErr := store.UpdateCompensationStatus(compensation.ID, database.CompensationStatusFailed, &errorMsg); updateErr != nil {
+			logger.Warnf("Failed to update compensation: %v", updateErr)
+		}
+		compensation.Status = database.CompensationStatusFailed
+		compensation.ErrorMessage = &errorMsg
+		logger.Warnf("%s", errorMsg)
+		return logs, errors.New(errorMsg)
+	}
+
+	if err := store.UpdateCompensationStatus(compensation.ID, database.CompensationStatusRolledBack, nil); err != nil {
+		logger.Warnf("Failed to update compensation: %v", err)
+	}
+	compensation.Status = database.CompensationStatusRolledBack
+	compensation.ErrorMessage = nil
+	logger.Infof("Rolled back %s", compensation.Target)
+	return logs, nil
+}
+
+// ListCompensations returns the resources an execution created and their rollback state
+func (e *WorkflowExecutor) ListCompensations(execID int64) ([]*database.WorkflowCompensation, error) {
+	store, ok := e.repo.(compensationStore)
+	if !ok {
+		return nil, fmt.Errorf("rollback requires a database-backed workflow repository")
+	}
+	return store.ListCompensations(execID)
+}
@@ -0,0 +1,304 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/types"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compensationRepository keeps the compensations of executions in memory
+type compensationRepository struct {
+	*MockWorkflowRepository
+	compensations []*database.WorkflowCompensation
+}
+
+func newCompensationRepository() *compensationRepository {
+	return &compensationRepository{MockWorkflowRepository: NewMockWorkflowRepository()}
+}
+
+func (r *compensationRepository) RecordCompensation(compensation *database.WorkflowCompensation) error {
+	compensation.ID = int64(len(r.compensations) + 1)
+	compensation.Status = database.CompensationStatusPending
+	r.compensations = append(r.compensations, compensation)
+	return nil
+}
+
+func (r *compensationRepository) ListCompensations(executionID int64) ([]*database.WorkflowCompensation, error) {
+	var compensations []*database.WorkflowCompensation
+	for _, c := range r.compensations {
+		if c.ExecutionID == executionID {
+			copied := *c
+			compensations = append(compensations, &copied)
+		}
+	}
+	return compensations, nil
+}
+
+func (r *compensationRepository) UpdateCompensationStatus(id int64, status string, errorMessage *string) error {
+	for _, c := range r.compensations {
+		if c.ID == id {
+			c.Status = status
+			c.ErrorMessage = errorMessage
+			return nil
+		}
+	}
+	return fmt.Errorf("compensation not found: %d", id)
+}
+
+func (r *compensationRepository) statuses() []string {
+	var statuses []string
+	for _, c := range r.compensations {
+		statuses = append(statuses, c.Target+"="+c.Status)
+	}
+	return statuses
+}
+
+// compensationSteps returns the rollback step records in step order
+func (r *compensationRepository) compensationSteps() []*database.WorkflowStepExecution {
+	var steps []*database.WorkflowStepExecution
+	for _, step := range r.steps {
+		if step.StepType == database.StepTypeCompensation {
+			steps = append(steps, step)
+		}
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].StepNumber < steps[j].StepNumber })
+	return steps
+}
+
+// newCompensatingExecutor returns an executor whose "create" steps track a fake resource
+// and whose "fail" steps fail. Rolled back resources are appended to removed.
+func newCompensatingExecutor(repo WorkflowRepositoryInterface, removed *[]string, failFor string) *WorkflowExecutor {
+	executor := NewWorkflowExecutor(repo)
+	executor.RegisterStepExecutor("create", func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		executor.trackCreated(ctx, execID, step.Name, "fake", step.Name, map[string]string{"name": step.Name})
+		return nil
+	})
+	executor.RegisterStepExecutor("fail", func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		return fmt.Errorf("provisioning failed")
+	})
+	executor.RegisterCompensator("fake", func(ctx context.Context, params map[string]string) (string, error) {
+		if params["name"] == failFor {
+			return "still in use", fmt.Errorf("resource busy")
+		}
+		*removed = append(*removed, params["name"])
+		return "deleted " + params["name"], nil
+	})
+	return executor
+}
+
+func partialWorkflow(onFailure string) types.Workflow {
+	return types.Workflow{
+		OnFailure: onFailure,
+		Steps: []types.Step{
+			{Name: "repo", Type: "create"},
+			{Name: "app", Type: "create"},
+			{Name: "sync", Type: "fail"},
+		},
+	}
+}
+
+// TestCompensateOnFailure verifies resources created before a failed step are rolled back
+// automatically with onFailure: rollback and kept pending otherwise
+func TestCompensateOnFailure(t *testing.T) {
+	tests := []struct {
+		name         string
+		onFailure    string
+		wantRemoved  []string
+		wantStatuses []string
+		wantSteps    int
+	}{
+		{
+			name:         "automatic rollback",
+			onFailure:    OnFailureRollback,
+			wantRemoved:  []string{"app", "repo"},
+			wantStatuses: []string{"repo=rolled_back", "app=rolled_back"},
+			wantSteps:    2,
+		},
+		{
+			name:         "prompt",
+			onFailure:    OnFailurePrompt,
+			wantStatuses: []string{"repo=pending", "app=pending"},
+		},
+		{
+			name:         "default prompts",
+			wantStatuses: []string{"repo=pending", "app=pending"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newCompensationRepository()
+			var removed []string
+			executor := newCompensatingExecutor(repo, &removed, "")
+
+			err := executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", partialWorkflow(tt.onFailure))
+			require.Error(t, err)
+
+			assert.Equal(t, tt.wantRemoved, removed)
+			assert.Equal(t, tt.wantStatuses, repo.statuses())
+
+			steps := repo.compensationSteps()
+			require.Len(t, steps, tt.wantSteps)
+			for i, step := range steps {
+				assert.Equal(t, 4+i, step.StepNumber)
+				assert.Equal(t, database.StepStatusCompleted, step.Status)
+			}
+		})
+	}
+}
+
+// TestRollbackExecution verifies a prompted rollback, including retrying a resource that
+// could not be removed the first time
+func TestRollbackExecution(t *testing.T) {
+	repo := newCompensationRepository()
+	var removed []string
+	executor := newCompensatingExecutor(repo, &removed, "repo")
+
+	require.Error(t, executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", partialWorkflow(OnFailurePrompt)))
+	assert.Empty(t, removed)
+
+	compensations, err := executor.RollbackExecution(context.Background(), 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to roll back repo: resource busy")
+	require.Len(t, compensations, 2)
+	assert.Equal(t, database.CompensationStatusFailed, compensations[0].Status)
+	assert.Equal(t, database.CompensationStatusRolledBack, compensations[1].Status)
+	assert.Equal(t, []string{"app"}, removed)
+	assert.Equal(t, []string{"repo=failed", "app=rolled_back"}, repo.statuses())
+
+	steps := repo.compensationSteps()
+	require.Len(t, steps, 2)
+	assert.Equal(t, "rollback-app", steps[0].StepName)
+	assert.Equal(t, database.StepStatusCompleted, steps[0].Status)
+	assert.Equal(t, "rollback-repo", steps[1].StepName)
+	assert.Equal(t, database.StepStatusFailed, steps[1].Status)
+
+	// Once the resource is free, only what is left is rolled back
+	executor.RegisterCompensator("fake", func(ctx context.Context, params map[string]string) (string, error) {
+		removed = append(removed, params["name"])
+		return "", nil
+	})
+	_, err = executor.RollbackExecution(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app", "repo"}, removed)
+	assert.Equal(t, []string{"repo=rolled_back", "app=rolled_back"}, repo.statuses())
+	assert.Len(t, repo.compensationSteps(), 3)
+}
+
+func TestRollbackExecutionRejected(t *testing.T) {
+	t.Run("execution did not fail", func(t *testing.T) {
+		repo := newCompensationRepository()
+		var removed []string
+		executor := newCompensatingExecutor(repo, &removed, "")
+		workflow := types.Workflow{Steps: []types.Step{{Name: "repo", Type: "create"}}}
+		require.NoError(t, executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", workflow))
+
+		_, err := executor.RollbackExecution(context.Background(), 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only failed executions can be rolled back")
+		assert.Equal(t, []string{"repo=pending"}, repo.statuses())
+	})
+
+	t.Run("repository without compensation tracking", func(t *testing.T) {
+		var removed []string
+		executor := newCompensatingExecutor(NewMockWorkflowRepository(), &removed, "")
+		require.Error(t, executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", partialWorkflow(OnFailureRollback)))
+
+		_, err := executor.RollbackExecution(context.Background(), 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "database-backed")
+		assert.Empty(t, removed)
+	})
+}
+
+func TestCreatedKubernetesObjects(t *testing.T) {
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: shop
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+`
+
+	tests := []struct {
+		name   string
+		output string
+		want   []kubernetesObject
+	}{
+		{
+			name:   "created and configured objects",
+			output: "namespace/shop unchanged\ndeployment.apps/web created\nservice/web configured\ningress.networking.k8s.io/web created\n",
+			want: []kubernetesObject{
+				{Ref: "deployment.apps/web", Namespace: "shop"},
+				{Ref: "ingress.networking.k8s.io/web"},
+			},
+		},
+		{
+			name:   "cluster-scoped object",
+			output: "namespace/shop created\n",
+			want:   []kubernetesObject{{Ref: "namespace/shop"}},
+		},
+		{
+			name:   "nothing created",
+			output: "deployment.apps/web unchanged\nservice/web unchanged\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, createdKubernetesObjects(manifest, tt.output))
+		})
+	}
+}
+
+func TestValidateOnFailure(t *testing.T) {
+	tests := []struct {
+		onFailure string
+		wantErr   bool
+	}{
+		{onFailure: ""},
+		{onFailure: OnFailurePrompt},
+		{onFailure: OnFailureRollback},
+		{onFailure: "ignore", wantErr: true},
+	}
+
+	validator := NewWorkflowValidator()
+	for _, tt := range tests {
+		t.Run(tt.onFailure, func(t *testing.T) {
+			workflow := &types.Workflow{
+				OnFailure: tt.onFailure,
+				Steps: []types.Step{{Name: "install", Type: "helm", Config: map[string]interface{}{
+					"release": "web", "chart": "nginx",
+				}}},
+			}
+			errs := validator.ValidateWorkflow(workflow)
+			if tt.wantErr {
+				require.Len(t, errs, 1)
+				assert.Contains(t, errs[0].Error(), "onFailure")
+			} else {
+				assert.Empty(t, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/types"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of resources the built-in steps track for rollback
+const (
+	compensationHelmRelease         = "helm-release"
+	compensationKubernetesNamespace = "kubernetes-namespace"
+	compensationKubernetesObject    = "kubernetes-object"
+	compensationGiteaRepo           = "gitea-repo"
+	compensationArgoCDApp           = "argocd-app"
+)
+
+// defaultCompensators returns the compensators for resources the built-in steps create
+func defaultCompensators() map[string]CompensatorFunc {
+	return map[string]CompensatorFunc{
+		compensationHelmRelease:         uninstallHelmRelease,
+		compensationKubernetesNamespace: deleteKubernetesNamespace,
+		compensationKubernetesObject:    deleteKubernetesObject,
+		compensationGiteaRepo:           deleteGiteaRepo,
+		compensationArgoCDApp:           deleteArgoCDApp,
+	}
+}
+
+// helmReleaseExists reports whether a Helm release is already installed
+func helmReleaseExists(ctx context.Context, release, namespace, kubeContext string) bool {
+	args := []string{"status", release, "--namespace", namespace}
+	if kubeContext != "" {
+		args = append(args, "--kube-context", kubeContext)
+	}
+	// #nosec G204 - arguments come from the workflow definition
+	return stepProcess(ctx, "helm", args...).Run() == nil
+}
+
+// uninstallHelmRelease removes a Helm release installed by a helm step
+func uninstallHelmRelease(ctx context.Context, params map[string]string) (string, error) {
+	args := []string{"uninstall", params["release"], "--namespace", params["namespace"]}
+	if params["kube_context"] != "" {
+		args = append(args, "--kube-context", params["kube_context"])
+	}
+	// #nosec G204 - arguments were recorded by the helm step
+	output, err := stepProcess(ctx, "helm", args...).CombinedOutput()
+	if err != nil && !strings.Contains(string(output), "not found") {
+		return string(output), fmt.Errorf("helm uninstall %s failed: %w", params["release"], err)
+	}
+	return string(output), nil
+}
+
+// deleteKubernetesNamespace removes a namespace created by a kubernetes step
+func deleteKubernetesNamespace(ctx context.Context, params map[string]string) (string, error) {
+	// #nosec G204 - namespace was recorded by the kubernetes step
+	output, err := stepProcess(ctx, "kubectl", "delete", "namespace", params["namespace"], "--ignore-not-found").CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("failed to delete namespace %s: %w", params["namespace"], err)
+	}
+	return string(output), nil
+}
+
+// deleteKubernetesObject removes an object created by a kubernetes apply step
+func deleteKubernetesObject(ctx context.Context, params map[string]string) (string, error) {
+	args := []string{"delete", params["object"], "--ignore-not-found"}
+	if params["namespace"] != "" {
+		args = append(args, "-n", params["namespace"])
+	}
+	// #nosec G204 - object was recorded by the kubernetes step
+	output, err := stepProcess(ctx, "kubectl", args...).CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("failed to delete %s: %w", params["object"], err)
+	}
+	return string(output), nil
+}
+
+// kubernetesObject is an object reported by kubectl, e.g. "deployment.apps/web"
+type kubernetesObject struct {
+	Ref       string
+	Namespace string
+}
+
+// createdKubernetesObjects returns the objects "kubectl apply" reports as created, with the
+// namespace the manifest puts them in. Objects that were only configured existed before.
+func createdKubernetesObjects(manifest, output string) []kubernetesObject {
+	namespaces := map[string]string{}
+	decoder := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		var doc struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name      string `yaml:"name"`
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+		}
+		if err := decoder.Decode(&doc); err != nil {
+			break
+		}
+		namespaces[strings.ToLower(doc.Kind)+"/"+doc.Metadata.Name] = doc.Metadata.Namespace
+	}
+
+	var objects []kubernetesObject
+	for _, line := range strings.Split(output, "\n") {
+		ref, ok := strings.CutSuffix(strings.TrimSpace(line), " created")
+		if !ok {
+			continue
+		}
+		resource, name, ok := strings.Cut(ref, "/")
+		if !ok {
+			continue
+		}
+		kind, _, _ := strings.Cut(resource, ".")
+		objects = append(objects, kubernetesObject{Ref: ref, Namespace: namespaces[kind+"/"+name]})
+	}
+	return objects
+}
+
+// giteaDo sends an authenticated request to the Gitea API configured in admin-config.yaml
+func giteaDo(ctx context.Context, adminConfig *admin.AdminConfig, method, path string) (*http.Response, error) {
+	if adminConfig.Gitea.URL == "" {
+		return nil, fmt.Errorf("gitea configuration not found in admin-config.yaml")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, adminConfig.Gitea.URL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(adminConfig.Gitea.Username, adminConfig.Gitea.Password)
+	client := &http.Client{Timeout: 30 * time.Second}
+	return client.Do(req)
+}
+
+// giteaRepoExists returns the owner of the repository a gitea-repo step creates and
+// whether it already exists
+func giteaRepoExists(ctx context.Context, step types.Step) (string, bool, error) {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to load admin config: %w", err)
+	}
+	owner := step.Owner
+	if owner == "" {
+		owner = adminConfig.Gitea.Username
+	}
+
+	resp, err := giteaDo(ctx, adminConfig, "GET", fmt.Sprintf("/api/v1/repos/%s/%s", url.PathEscape(owner), url.PathEscape(step.RepoName)))
+	if err != nil {
+		return owner, false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return owner, true, nil
+	case http.StatusNotFound:
+		return owner, false, nil
+	default:
+		return owner, false, fmt.Errorf("unexpected status %d checking repository %s/%s", resp.StatusCode, owner, step.RepoName)
+	}
+}
+
+// deleteGiteaRepo removes a repository created by a gitea-repo step
+func deleteGiteaRepo(ctx context.Context, params map[string]string) (string, error) {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return "", fmt.Errorf("failed to load admin config: %w", err)
+	}
+
+	resp, err := giteaDo(ctx, adminConfig, "DELETE", fmt.Sprintf("/api/v1/repos/%s/%s", url.PathEscape(params["owner"]), url.PathEscape(params["repo"])))
+	if err != nil {
+		return "", fmt.Errorf("failed to delete repository: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return string(body), fmt.Errorf("failed to delete repository, status %d", resp.StatusCode)
+	}
+	return fmt.Sprintf("Deleted repository %s/%s\n", params["owner"], params["repo"]), nil
+}
+
+// argoCDAppName returns the name of the Application an argocd-app step creates
+func argoCDAppName(step types.Step, appName string) string {
+	if step.AppName != "" {
+		return step.AppName
+	}
+	return fmt.Sprintf("%s-%s", appName, "default")
+}
+
+// argoCDDo sends an authenticated request to the ArgoCD API configured in admin-config.yaml
+func argoCDDo(ctx context.Context, method, path string) (*http.Response, error) {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load admin config: %w", err)
+	}
+	if adminConfig.ArgoCD.URL == "" {
+		return nil, fmt.Errorf("argocd configuration not found in admin-config.yaml")
+	}
+
+	token, err := authenticateArgoCD(adminConfig.ArgoCD.URL, adminConfig.ArgoCD.Username, adminConfig.ArgoCD.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with ArgoCD: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, adminConfig.ArgoCD.URL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	client := &http.Client{Timeout: 30 * time.Second}
+	return client.Do(req)
+}
+
+// argoCDAppExists reports whether an ArgoCD Application already exists
+func argoCDAppExists(ctx context.Context, name string) (bool, error) {
+	resp, err := argoCDDo(ctx, "GET", "/api/v1/applications/"+url.PathEscape(name))
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound, http.StatusForbidden:
+		// ArgoCD answers 403 for applications that do not exist
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status %d checking application %s", resp.StatusCode, name)
+	}
+}
+
+// deleteArgoCDApp removes an Application created by an argocd-app step, including the
+// resources it synced
+func deleteArgoCDApp(ctx context.Context, params map[string]string) (string, error) {
+	resp, err := argoCDDo(ctx, "DELETE", "/api/v1/applications/"+url.PathEscape(params["name"])+"?cascade=true")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return string(body), fmt.Errorf("failed to delete application, status %d", resp.StatusCode)
+	}
+	return fmt.Sprintf("Deleted application %s\n", params["name"]), nil
+}
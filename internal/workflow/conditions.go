@@ -1,7 +1,9 @@
 package workflow
 
 import (
+	"context"
 	"fmt"
+	"innominatus/internal/secrets"
 	"innominatus/internal/types"
 	"os"
 	"regexp"
@@ -19,6 +21,9 @@ type ExecutionContext struct {
 	Environment         map[string]string            // Environment variables
 	WorkflowVariables   map[string]string            // Workflow-level variables
 	WorkflowStatus      string                       // Overall workflow status
+	secrets             *secrets.Resolver            // Resolves ${secrets.path.key}; nil leaves references as they are
+	secretAccess        secrets.WorkflowAccess       // Paths references may point to
+	secretScope         secrets.Scope                // Application and team of the current run
 }
 
 // NewExecutionContext creates a new execution context
@@ -45,6 +50,45 @@ func (ctx *ExecutionContext) SetVariable(key, value string) {
 	ctx.WorkflowVariables[key] = value
 }
 
+// SetSecretResolver sets the resolver of ${secrets.path.key} references and the paths they
+// may point to
+func (ctx *ExecutionContext) SetSecretResolver(resolver *secrets.Resolver, access secrets.WorkflowAccess) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.secrets = resolver
+	ctx.secretAccess = access
+}
+
+// setSecretScope sets the application and team whose secrets the current run may read
+func (ctx *ExecutionContext) setSecretScope(scope secrets.Scope) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	ctx.secretScope = scope
+}
+
+// resolveSecret returns the value of a ${secrets.path.key} reference, given path.key
+func (ctx *ExecutionContext) resolveSecret(name string) (string, error) {
+	ctx.mu.RLock()
+	resolver, access, scope := ctx.secrets, ctx.secretAccess, ctx.secretScope
+	ctx.mu.RUnlock()
+	return resolveSecretReference(resolver, access, scope, name)
+}
+
+// resolveSecretReference resolves path.key through resolver if scope may read it
+func resolveSecretReference(resolver *secrets.Resolver, access secrets.WorkflowAccess, scope secrets.Scope, name string) (string, error) {
+	ref, ok := secrets.ParseReference(name)
+	if !ok {
+		return "", fmt.Errorf("invalid secret reference, use ${secrets.<path>.<key>}")
+	}
+	if resolver == nil {
+		return "", fmt.Errorf("no secret store is configured")
+	}
+	if err := access.Check(ref, scope); err != nil {
+		return "", err
+	}
+	return resolver.Resolve(context.Background(), ref)
+}
+
 // GetVariable gets a workflow variable
 func (ctx *ExecutionContext) GetVariable(key string) (string, bool) {
 	value, exists := ctx.WorkflowVariables[key]
@@ -107,6 +151,21 @@ func (ctx *ExecutionContext) GetAllStepOutputs(stepName string) (map[string]stri
 	return outputs, exists
 }
 
+// StepOutputs returns a copy of the outputs of all previous steps
+func (ctx *ExecutionContext) StepOutputs() map[string]map[string]string {
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+	outputs := make(map[string]map[string]string, len(ctx.PreviousStepOutputs))
+	for stepName, values := range ctx.PreviousStepOutputs {
+		copied := make(map[string]string, len(values))
+		for k, v := range values {
+			copied[k] = v
+		}
+		outputs[stepName] = copied
+	}
+	return outputs
+}
+
 // SetResourceOutputs records outputs for a provisioned resource
 func (ctx *ExecutionContext) SetResourceOutputs(resourceName string, outputs map[string]string) {
 	ctx.mu.Lock()
@@ -148,8 +207,27 @@ func (ctx *ExecutionContext) GetAllResourceOutputs(resourceName string) (map[str
 	return outputs, exists
 }
 
+// AllResourceOutputs returns a copy of the outputs of all provisioned resources
+func (ctx *ExecutionContext) AllResourceOutputs() map[string]map[string]string {
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+	outputs := make(map[string]map[string]string, len(ctx.ResourceOutputs))
+	for resourceName, values := range ctx.ResourceOutputs {
+		copied := make(map[string]string, len(values))
+		for k, v := range values {
+			copied[k] = v
+		}
+		outputs[resourceName] = copied
+	}
+	return outputs
+}
+
 // ShouldExecuteStep determines if a step should be executed based on its conditions
 func (ctx *ExecutionContext) ShouldExecuteStep(step types.Step) (bool, string) {
+	if step.SkipReason != "" {
+		return false, step.SkipReason
+	}
+
 	// Merge all variable sources (priority: step env > workflow vars > context env)
 	mergedEnv := make(map[string]string)
 
@@ -325,8 +403,14 @@ func (ctx *ExecutionContext) evaluateCondition(condition string, env map[string]
 }
 
 // replaceVariables replaces ${VAR} and $VAR with their values
-// Supports: $VAR, ${VAR}, ${step.output}, ${workflow.VAR}, ${resources.name.attr}
+// Supports: $VAR, ${VAR}, ${step.output}, ${workflow.VAR}, ${resources.name.attr}, ${secrets.path.key}
 func (ctx *ExecutionContext) replaceVariables(str string, env map[string]string) string {
+	return ctx.replaceVariablesWith(str, env, os.Getenv)
+}
+
+// replaceVariablesWith replaces variable references, looking up names that are not workflow
+// or step variables with systemEnv
+func (ctx *ExecutionContext) replaceVariablesWith(str string, env map[string]string, systemEnv func(string) string) string {
 	// Replace ${VAR} style (including step.output, workflow.VAR, and resources.name.attr)
 	re := regexp.MustCompile(`\$\{([^}]+)\}`)
 	str = re.ReplaceAllStringFunc(str, func(match string) string {
@@ -345,6 +429,12 @@ func (ctx *ExecutionContext) replaceVariables(str string, env map[string]string)
 					if val, exists := ctx.WorkflowVariables[suffix]; exists {
 						return val
 					}
+				} else if prefix == "secrets" {
+					// Check for ${secrets.path.key}; validation reports unresolvable references
+					if val, err := ctx.resolveSecret(suffix); err == nil {
+						return val
+					}
+					return match
 				} else if prefix == "resources" {
 					// Check for ${resources.name.attr}
 					if strings.Contains(suffix, ".") {
@@ -374,7 +464,7 @@ func (ctx *ExecutionContext) replaceVariables(str string, env map[string]string)
 			return val
 		}
 		// Check system environment
-		if val := os.Getenv(varName); val != "" {
+		if val := systemEnv(varName); val != "" {
 			return val
 		}
 		return match // Return original if not found
@@ -429,7 +519,7 @@ func (ctx *ExecutionContext) replaceVariables(str string, env map[string]string)
 			return val
 		}
 		// Check system environment
-		if val := os.Getenv(varName); val != "" {
+		if val := systemEnv(varName); val != "" {
 			return val
 		}
 		return match // Return original if not found
@@ -0,0 +1,124 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"innominatus/internal/database"
+	"innominatus/internal/types"
+	"innominatus/pkg/sdk"
+)
+
+// OutputTypes are the types a declared workflow output may have
+var OutputTypes = []string{"url", "dashboard", "connection_string", "secret_ref", "namespace", "text", "grafana_panel", "log_query", "metrics_query"}
+
+// outputRecorder is implemented by repositories that persist the declared outputs of an execution
+type outputRecorder interface {
+	SetWorkflowExecutionOutputs(execID int64, outputs []database.WorkflowOutput) error
+}
+
+// ApplyInputs checks parameters against the inputs a workflow declares. Missing inputs
+// get their default; the returned error lists required inputs that have neither.
+// Parameters that are not declared are passed through unchanged.
+func ApplyInputs(inputs []types.WorkflowInput, parameters map[string]string) (map[string]string, error) {
+	result := make(map[string]string, len(parameters)+len(inputs))
+	for k, v := range parameters {
+		result[k] = v
+	}
+
+	var missing []string
+	for _, input := range inputs {
+		if _, ok := result[input.Name]; ok {
+			continue
+		}
+		if input.Default != "" {
+			result[input.Name] = input.Default
+		} else if input.Required {
+			missing = append(missing, input.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required workflow inputs: %s", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// resolveOutputs resolves the declared outputs of a workflow from the step outputs and
+// variables of the execution context. Outputs that still reference unknown values are
+// returned by name in unresolved instead of being recorded with a partial value.
+func (e *WorkflowExecutor) resolveOutputs(outputs map[string]types.WorkflowOutput) (resolved []database.WorkflowOutput, unresolved []string) {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	noSystemEnv := func(string) string { return "" }
+	values := make(map[string]string, len(outputs))
+	for _, name := range names {
+		values[name] = e.execContext.replaceVariablesWith(outputs[name].Value, nil, noSystemEnv)
+	}
+	// Only fully resolved outputs can be referenced by other outputs
+	referenceable := make(map[string]string, len(values))
+	for name, value := range values {
+		if !unresolvedValue(value) {
+			referenceable[name] = value
+		}
+	}
+
+	for _, name := range names {
+		output := outputs[name]
+		// Dashboard and query outputs are templates that may reference other outputs
+		// with ${outputs.<name>}, e.g. the namespace a panel is filtered by
+		hint, err := sdk.Hint{
+			Type:  output.Type,
+			Label: name,
+			Value: values[name],
+			Query: e.execContext.replaceVariablesWith(output.Query, nil, noSystemEnv),
+		}.Render(referenceable)
+		if err != nil || unresolvedValue(hint.Value) || (hint.Query != "" && unresolvedValue(hint.Query)) {
+			unresolved = append(unresolved, name)
+			continue
+		}
+
+		outputType := output.Type
+		if outputType == "" {
+			outputType = "text"
+		}
+		resolved = append(resolved, database.WorkflowOutput{
+			Name:        name,
+			Type:        outputType,
+			Value:       hint.Value,
+			Query:       hint.Query,
+			Description: output.Description,
+		})
+	}
+
+	return resolved, unresolved
+}
+
+// unresolvedValue reports whether an output value is empty or still contains a reference
+func unresolvedValue(value string) bool {
+	return value == "" || strings.Contains(value, "${") || strings.Contains(value, "{{")
+}
+
+// recordOutputs stores the resolved outputs of a successful execution
+func (e *WorkflowExecutor) recordOutputs(execID int64, workflow types.Workflow, warnf func(format string, args ...interface{})) {
+	if len(workflow.Outputs) == 0 {
+		return
+	}
+	recorder, ok := e.repo.(outputRecorder)
+	if !ok {
+		return
+	}
+
+	outputs, unresolved := e.resolveOutputs(workflow.Outputs)
+	if len(unresolved) > 0 {
+		warnf("Workflow outputs could not be resolved: %s", strings.Join(unresolved, ", "))
+	}
+	if err := recorder.SetWorkflowExecutionOutputs(execID, outputs); err != nil {
+		warnf("Failed to record workflow outputs: %v", err)
+	}
+}
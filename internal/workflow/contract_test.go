@@ -0,0 +1,182 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/database"
+	"innominatus/internal/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWorkflowContractYAML(t *testing.T) {
+	data := `
+inputs:
+  - name: environment
+    required: true
+  - name: replicas
+    default: "2"
+outputs:
+  namespace: ${deploy.namespace}
+  endpoint:
+    value: https://${deploy.host}
+    type: url
+    description: Public URL of the application
+steps:
+  - name: deploy
+    type: kubernetes
+`
+	var workflow types.Workflow
+	require.NoError(t, yaml.Unmarshal([]byte(data), &workflow))
+
+	assert.Equal(t, []types.WorkflowInput{
+		{Name: "environment", Required: true},
+		{Name: "replicas", Default: "2"},
+	}, workflow.Inputs)
+	assert.Equal(t, types.WorkflowOutput{Value: "${deploy.namespace}"}, workflow.Outputs["namespace"])
+	assert.Equal(t, types.WorkflowOutput{Value: "https://${deploy.host}", Type: "url", Description: "Public URL of the application"}, workflow.Outputs["endpoint"])
+}
+
+func TestApplyInputs(t *testing.T) {
+	inputs := []types.WorkflowInput{
+		{Name: "environment", Required: true},
+		{Name: "replicas", Default: "2"},
+		{Name: "team"},
+	}
+
+	tests := []struct {
+		name       string
+		parameters map[string]string
+		want       map[string]string
+		wantErr    string
+	}{
+		{
+			name:       "defaults applied",
+			parameters: map[string]string{"environment": "staging"},
+			want:       map[string]string{"environment": "staging", "replicas": "2"},
+		},
+		{
+			name:       "caller values and undeclared parameters kept",
+			parameters: map[string]string{"environment": "production", "replicas": "5", "region": "eu"},
+			want:       map[string]string{"environment": "production", "replicas": "5", "region": "eu"},
+		},
+		{
+			name:    "missing required input",
+			wantErr: "missing required workflow inputs: environment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyInputs(inputs, tt.parameters)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type outputRecordingRepository struct {
+	*MockWorkflowRepository
+	outputs map[int64][]database.WorkflowOutput
+}
+
+func (r *outputRecordingRepository) SetWorkflowExecutionOutputs(execID int64, outputs []database.WorkflowOutput) error {
+	r.outputs[execID] = outputs
+	return nil
+}
+
+// TestExecutionOutputsRecorded verifies declared outputs are resolved from step outputs
+// and recorded with the execution
+func TestExecutionOutputsRecorded(t *testing.T) {
+	repo := &outputRecordingRepository{MockWorkflowRepository: NewMockWorkflowRepository(), outputs: map[int64][]database.WorkflowOutput{}}
+	executor := NewWorkflowExecutor(repo)
+	executor.RegisterStepExecutor("deploy", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+		executor.execContext.SetStepOutput(step.Name, "host", "shop.example.com")
+		return nil
+	})
+
+	workflow := types.Workflow{
+		Inputs: []types.WorkflowInput{{Name: "environment", Default: "staging"}},
+		Outputs: map[string]types.WorkflowOutput{
+			"endpoint":  {Value: "https://${deploy.host}", Type: "url"},
+			"namespace": {Value: "shop-${workflow.environment}", Type: "namespace", Description: "Kubernetes namespace"},
+			"password":  {Value: "${deploy.password}", Type: "secret_ref"},
+		},
+		Steps: []types.Step{{Name: "deploy", Type: "deploy"}},
+	}
+
+	require.NoError(t, executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy-app", workflow))
+
+	assert.Equal(t, []database.WorkflowOutput{
+		{Name: "endpoint", Type: "url", Value: "https://shop.example.com"},
+		{Name: "namespace", Type: "namespace", Value: "shop-staging", Description: "Kubernetes namespace"},
+	}, repo.outputs[1])
+}
+
+// TestObservabilityOutputs verifies dashboard and query outputs are templated against the
+// other outputs of the execution
+func TestObservabilityOutputs(t *testing.T) {
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.execContext.SetStepOutput("deploy", "namespace", "shop-staging")
+
+	resolved, unresolved := executor.resolveOutputs(map[string]types.WorkflowOutput{
+		"namespace": {Value: "${deploy.namespace}", Type: "namespace"},
+		"pods":      {Value: "https://grafana.example.com/d-solo/k8s?panelId=2&var-namespace=${outputs.namespace}", Type: "grafana_panel"},
+		"errors": {
+			Value: "https://grafana.example.com/explore",
+			Query: `{namespace="${outputs.namespace}"} |= "error"`,
+			Type:  "log_query",
+		},
+		"latency": {Value: "https://grafana.example.com/d-solo/k8s?panelId=3&var-pod=${outputs.pod}", Type: "grafana_panel"},
+	})
+
+	assert.Equal(t, []string{"latency"}, unresolved)
+	assert.Equal(t, []database.WorkflowOutput{
+		{Name: "errors", Type: "log_query", Value: "https://grafana.example.com/explore", Query: `{namespace="shop-staging"} |= "error"`},
+		{Name: "namespace", Type: "namespace", Value: "shop-staging"},
+		{Name: "pods", Type: "grafana_panel", Value: "https://grafana.example.com/d-solo/k8s?panelId=2&var-namespace=shop-staging"},
+	}, resolved)
+}
+
+func TestExecutionMissingRequiredInput(t *testing.T) {
+	repo := NewMockWorkflowRepository()
+	executor := NewWorkflowExecutor(repo)
+
+	workflow := types.Workflow{
+		Inputs: []types.WorkflowInput{{Name: "environment", Required: true}},
+		Steps:  []types.Step{{Name: "deploy", Type: "synthetic"}},
+	}
+
+	err := executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy-app", workflow)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required workflow inputs: environment")
+	count, _ := repo.CountWorkflowExecutions("shop", "deploy-app", "")
+	assert.Zero(t, count)
+}
+
+func TestValidateWorkflowContract(t *testing.T) {
+	validator := NewWorkflowValidator()
+	workflow := &types.Workflow{
+		Inputs: []types.WorkflowInput{{Name: "environment"}, {Name: "environment"}, {}},
+		Outputs: map[string]types.WorkflowOutput{
+			"endpoint": {Value: "${deploy.url}", Type: "link"},
+			"empty":    {Type: "text"},
+		},
+		Steps: []types.Step{{Name: "deploy", Type: "synthetic"}},
+	}
+
+	var messages []string
+	for _, err := range validator.ValidateWorkflow(workflow) {
+		messages = append(messages, err.Error())
+	}
+	assert.Contains(t, messages, "inputs[1]: duplicate input 'environment'")
+	assert.Contains(t, messages, "inputs[2]: name is required")
+	assert.Contains(t, messages, "outputs.empty: value is required")
+	assert.Contains(t, messages, "outputs.endpoint: type must be one of [url dashboard connection_string secret_ref namespace text grafana_panel log_query metrics_query], got 'link'")
+}
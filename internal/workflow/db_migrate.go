@@ -0,0 +1,649 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/types"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Migration tools of db-migrate steps
+const (
+	migrateToolFlyway    = "flyway"
+	migrateToolLiquibase = "liquibase"
+	migrateToolCustom    = "custom"
+)
+
+const (
+	defaultMigrateTimeout     = 10 * time.Minute
+	defaultMigrateLockTimeout = 10 * time.Minute
+	defaultLiquibaseChangelog = "changelog.xml"
+	maxMigrationFilesBytes    = 900 * 1024 // ConfigMaps are limited to 1MiB
+	maxMigrationSummaryBytes  = 4096
+)
+
+// MigrationLabel marks the Kubernetes objects of a db-migrate step with the migrated database
+const MigrationLabel = "innominatus.io/migration"
+
+// defaultMigrateImages are the images of the migration tools when a step sets none
+var defaultMigrateImages = map[string]string{
+	migrateToolFlyway:    "flyway/flyway:10",
+	migrateToolLiquibase: "liquibase/liquibase:4.29",
+}
+
+// migrationMountPaths are where each tool reads the migration files from
+var migrationMountPaths = map[string]string{
+	migrateToolFlyway:    "/flyway/sql",
+	migrateToolLiquibase: "/liquibase/changelog",
+	migrateToolCustom:    "/migrations",
+}
+
+// migrationJobPollInterval controls how often a running migration job is checked
+var migrationJobPollInterval = 2 * time.Second
+
+// invalidJobNameChars matches everything a Kubernetes object name may not contain
+var invalidJobNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// dbMigration is the validated configuration of a db-migrate step
+type dbMigration struct {
+	Tool           string
+	Image          string
+	Database       string // Score resource of the database; empty when url is given
+	Namespace      string
+	Migrations     string // Local directory of migration files; optional for custom images
+	Changelog      string // Liquibase changelog file, relative to the migrations
+	Command        []string
+	DryRunCommand  []string
+	DryRun         bool // Only preview the SQL, never migrate
+	Timeout        time.Duration
+	LockTimeout    time.Duration
+	URL            string
+	Username       string
+	Password       string
+	ApprovalNeeded bool
+}
+
+// dbConnection is how a migration reaches its database
+type dbConnection struct {
+	Engine   string // postgresql or mysql
+	Host     string
+	Port     string
+	Database string
+	Username string
+	Password string
+	URL      string // Connection string of the provider, if any
+}
+
+// parseDBMigration validates the config of a db-migrate step
+func parseDBMigration(step types.Step, config map[string]interface{}, appName string) (*dbMigration, error) {
+	migration := &dbMigration{
+		Tool:           configString(config, "tool"),
+		Image:          configString(config, "image"),
+		Database:       configString(config, "database"),
+		Namespace:      step.Namespace,
+		Migrations:     configString(config, "migrations"),
+		Changelog:      configString(config, "changelog"),
+		Command:        configStrings(config, "command"),
+		DryRunCommand:  configStrings(config, "dry_run_command"),
+		DryRun:         configBool(config, "dry_run", false),
+		URL:            configString(config, "url"),
+		Username:       configString(config, "username"),
+		Password:       configString(config, "password"),
+		ApprovalNeeded: stepRequiresApproval(step),
+	}
+	if migration.Tool == "" {
+		migration.Tool = migrateToolFlyway
+	}
+	if migration.Namespace == "" {
+		migration.Namespace = configString(config, "namespace")
+	}
+	if migration.Namespace == "" {
+		migration.Namespace = appName
+	}
+
+	switch migration.Tool {
+	case migrateToolFlyway, migrateToolLiquibase:
+		if migration.Image == "" {
+			migration.Image = defaultMigrateImages[migration.Tool]
+		}
+		if migration.Migrations == "" {
+			return nil, fmt.Errorf("db-migrate step with tool %s requires 'migrations' in config", migration.Tool)
+		}
+	case migrateToolCustom:
+		if migration.Image == "" || len(migration.Command) == 0 {
+			return nil, fmt.Errorf("db-migrate step with tool custom requires 'image' and 'command' in config")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported db-migrate tool '%s' (supported: flyway, liquibase, custom)", migration.Tool)
+	}
+	if migration.Tool == migrateToolLiquibase && migration.Changelog == "" {
+		migration.Changelog = defaultLiquibaseChangelog
+	}
+
+	if migration.Database == "" && migration.URL == "" {
+		return nil, fmt.Errorf("db-migrate step requires 'database' (a resource of the application) or 'url' in config")
+	}
+
+	var err error
+	if migration.Timeout, err = configDuration(config, "timeout", defaultMigrateTimeout); err != nil {
+		return nil, err
+	}
+	if migration.LockTimeout, err = configDuration(config, "lock_timeout", defaultMigrateLockTimeout); err != nil {
+		return nil, err
+	}
+	return migration, nil
+}
+
+// configDuration returns a positive duration step config value, or def when unset
+func configDuration(config map[string]interface{}, key string, def time.Duration) (time.Duration, error) {
+	value := configString(config, key)
+	if value == "" {
+		return def, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("'%s' must be a positive duration, got '%s'", key, value)
+	}
+	return duration, nil
+}
+
+// target names the migrated database in logs, locks and Kubernetes objects
+func (m *dbMigration) target() string {
+	if m.Database != "" {
+		return m.Database
+	}
+	return "db"
+}
+
+// jobName returns the name of the migration job, which doubles as a cluster-wide lock
+func (m *dbMigration) jobName(appName string) string {
+	name := strings.Trim(invalidJobNameChars.ReplaceAllString(strings.ToLower(appName+"-"+m.target()+"-migrate"), "-"), "-")
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name
+}
+
+// canPreview reports whether the tool can render the SQL of a migration without running it
+func (m *dbMigration) canPreview() bool {
+	return m.Tool != migrateToolCustom || len(m.DryRunCommand) > 0
+}
+
+// args returns the command and arguments of the migration container
+func (m *dbMigration) args(preview bool) (command, args []string) {
+	switch m.Tool {
+	case migrateToolFlyway:
+		if preview {
+			return nil, []string{"migrate", "-dryRunOutput=/dev/stdout"}
+		}
+		return nil, []string{"migrate"}
+	case migrateToolLiquibase:
+		if preview {
+			return nil, []string{"update-sql"}
+		}
+		return nil, []string{"update"}
+	default:
+		if preview {
+			return m.DryRunCommand, nil
+		}
+		return m.Command, nil
+	}
+}
+
+// databaseConnection returns the connection details of the database a migration runs
+// against: the provider metadata of the application's database resource, overridden by
+// url, username and password of the step.
+func databaseConnection(migration *dbMigration, resources []*database.ResourceInstance) (*dbConnection, error) {
+	conn := &dbConnection{Engine: "postgresql", Port: "5432"}
+	if migration.Database != "" {
+		var resource *database.ResourceInstance
+		for _, candidate := range resources {
+			if candidate.ResourceName == migration.Database {
+				resource = candidate
+				break
+			}
+		}
+		if resource == nil {
+			return nil, fmt.Errorf("database '%s' is not a resource of the application", migration.Database)
+		}
+		if resource.State != database.ResourceStateActive {
+			return nil, fmt.Errorf("database '%s' is not active (state: %s)", migration.Database, resource.State)
+		}
+
+		switch resource.ResourceType {
+		case "postgres", "postgresql":
+		case "mysql":
+			conn.Engine, conn.Port = "mysql", "3306"
+		default:
+			if migration.URL == "" {
+				return nil, fmt.Errorf("resource '%s' of type %s is not a supported database (postgres, postgresql, mysql); set 'url' instead", migration.Database, resource.ResourceType)
+			}
+		}
+
+		metadata := resource.ProviderMetadata
+		conn.Host = metadataString(metadata, "host")
+		if port := metadataString(metadata, "port"); port != "" {
+			conn.Port = port
+		}
+		conn.Database = metadataString(metadata, "database_name", "database")
+		conn.Username = metadataString(metadata, "username")
+		conn.Password = metadataString(metadata, "password")
+		conn.URL = metadataString(metadata, "connection_string")
+	}
+
+	if migration.Username != "" {
+		conn.Username = migration.Username
+	}
+	if migration.Password != "" {
+		conn.Password = migration.Password
+	}
+	if migration.URL != "" {
+		conn.URL = migration.URL
+	} else if conn.Host == "" || conn.Database == "" {
+		return nil, fmt.Errorf("database '%s' has no host or database name in its provider metadata; set 'url' instead", migration.Database)
+	}
+	return conn, nil
+}
+
+// metadataString returns the first of keys set in provider metadata
+func metadataString(metadata map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value := configString(metadata, key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// jdbcURL returns the JDBC URL of the connection, for flyway and liquibase
+func (c *dbConnection) jdbcURL() string {
+	if strings.HasPrefix(c.URL, "jdbc:") {
+		return c.URL
+	}
+	return fmt.Sprintf("jdbc:%s://%s:%s/%s", c.Engine, c.Host, c.Port, c.Database)
+}
+
+// connectionString returns the URL of the connection with credentials, for custom images
+func (c *dbConnection) connectionString() string {
+	if c.URL != "" && !strings.HasPrefix(c.URL, "jdbc:") {
+		return c.URL
+	}
+	u := url.URL{Scheme: c.Engine, Host: c.Host + ":" + c.Port, Path: "/" + c.Database}
+	if c.Username != "" {
+		u.User = url.UserPassword(c.Username, c.Password)
+	}
+	return u.String()
+}
+
+// migrationEnv returns the environment of the migration container, which is kept in a Secret
+func migrationEnv(migration *dbMigration, conn *dbConnection) map[string]string {
+	mountPath := migrationMountPaths[migration.Tool]
+	switch migration.Tool {
+	case migrateToolFlyway:
+		return map[string]string{
+			"FLYWAY_URL":       conn.jdbcURL(),
+			"FLYWAY_USER":      conn.Username,
+			"FLYWAY_PASSWORD":  conn.Password,
+			"FLYWAY_LOCATIONS": "filesystem:" + mountPath,
+		}
+	case migrateToolLiquibase:
+		return map[string]string{
+			"LIQUIBASE_COMMAND_URL":            conn.jdbcURL(),
+			"LIQUIBASE_COMMAND_USERNAME":       conn.Username,
+			"LIQUIBASE_COMMAND_PASSWORD":       conn.Password,
+			"LIQUIBASE_COMMAND_CHANGELOG_FILE": migration.Changelog,
+			"LIQUIBASE_SEARCH_PATH":            mountPath,
+		}
+	default:
+		return map[string]string{
+			"DATABASE_URL":      conn.connectionString(),
+			"DATABASE_HOST":     conn.Host,
+			"DATABASE_PORT":     conn.Port,
+			"DATABASE_NAME":     conn.Database,
+			"DATABASE_USER":     conn.Username,
+			"DATABASE_PASSWORD": conn.Password,
+			"MIGRATIONS_DIR":    mountPath,
+		}
+	}
+}
+
+// readMigrationFiles reads the migration files of a directory into ConfigMap data.
+// Subdirectories are not read.
+func readMigrationFiles(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	files := make(map[string]string)
+	total := 0
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name())) // #nosec G304 - directory from the workflow definition
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		total += len(data)
+		if total > maxMigrationFilesBytes {
+			return nil, fmt.Errorf("migrations in %s exceed %d KiB; build them into a custom image instead", dir, maxMigrationFilesBytes/1024)
+		}
+		files[entry.Name()] = string(data)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no migration files in %s", dir)
+	}
+	return files, nil
+}
+
+// migrationManifests returns the Secret, ConfigMap and Job that run a migration.
+// files is nil when the image contains the migrations.
+func migrationManifests(migration *dbMigration, appName string, env, files map[string]string, preview bool) (setup, job string, err error) {
+	name := migration.jobName(appName)
+	labels := map[string]string{
+		ApplicationLabel: appName,
+		MigrationLabel:   migration.target(),
+	}
+	metadata := map[string]interface{}{"name": name, "namespace": migration.Namespace, "labels": labels}
+
+	container := map[string]interface{}{
+		"name":    "migrate",
+		"image":   migration.Image,
+		"envFrom": []interface{}{map[string]interface{}{"secretRef": map[string]interface{}{"name": name}}},
+	}
+	command, args := migration.args(preview)
+	if len(command) > 0 {
+		container["command"] = command
+	}
+	if len(args) > 0 {
+		container["args"] = args
+	}
+
+	podSpec := map[string]interface{}{
+		"restartPolicy": "Never",
+		"containers":    []interface{}{container},
+	}
+	objects := []interface{}{map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   metadata,
+		"type":       "Opaque",
+		"stringData": env,
+	}}
+	if files != nil {
+		objects = append(objects, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   metadata,
+			"data":       files,
+		})
+		container["volumeMounts"] = []interface{}{map[string]interface{}{
+			"name": "migrations", "mountPath": migrationMountPaths[migration.Tool], "readOnly": true,
+		}}
+		podSpec["volumes"] = []interface{}{map[string]interface{}{
+			"name": "migrations", "configMap": map[string]interface{}{"name": name},
+		}}
+	}
+
+	var docs []string
+	for _, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to render migration manifest: %w", err)
+		}
+		docs = append(docs, string(data))
+	}
+
+	jobData, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata":   metadata,
+		"spec": map[string]interface{}{
+			"backoffLimit":            0,
+			"activeDeadlineSeconds":   int(migration.Timeout.Seconds()),
+			"ttlSecondsAfterFinished": 3600, // Cleans up after a crashed server
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": labels},
+				"spec":     podSpec,
+			},
+		},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render migration job: %w", err)
+	}
+	return strings.Join(docs, "---\n"), string(jobData), nil
+}
+
+// migrationLocks serializes the migrations of a database within the server
+type migrationLocks struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+// dbMigrationLocks are shared by all executors of the process
+var dbMigrationLocks = &migrationLocks{locks: make(map[string]chan struct{})}
+
+// acquire waits up to timeout for the lock of key and returns the function releasing it
+func (l *migrationLocks) acquire(ctx context.Context, key string, timeout time.Duration) (func(), error) {
+	l.mu.Lock()
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		l.locks[key] = lock
+	}
+	l.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("timed out after %s waiting for another migration of %s", timeout, key)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// migrationPreviewPath returns where the SQL preview of a step is kept
+func migrationPreviewPath(appName, stepName string) string {
+	name := invalidJobNameChars.ReplaceAllString(strings.ToLower(stepName), "-")
+	return filepath.Join("workspaces", appName, "db-migrate", name+".preview.sql")
+}
+
+// migrationSummary describes a migration awaiting approval, with the start of its preview
+func migrationSummary(migration *dbMigration, appName, preview string) string {
+	summary := fmt.Sprintf("Migrate database '%s' of %s with %s (%s)\n", migration.target(), appName, migration.Tool, migration.Image)
+	if preview == "" {
+		return summary + "No SQL preview is available for this migration.\n"
+	}
+	if len(preview) > maxMigrationSummaryBytes {
+		preview = preview[:maxMigrationSummaryBytes] + "\n... (truncated, see the preview artifact)\n"
+	}
+	return summary + "\n" + preview
+}
+
+// dbMigrate runs the migrations of a db-migrate step as a Kubernetes job in the
+// application's namespace and returns the output of the migration tool. Migrations of
+// the same database never run concurrently. With dry_run or an approval gate, the SQL
+// the migration would run is previewed first and stored as an artifact.
+func (e *WorkflowExecutor) dbMigrate(ctx context.Context, step types.Step, appName string, execID, stepID int64) (string, error) {
+	logger := logging.FromContext(ctx, "workflow")
+	config := e.execContext.InterpolateResourceParams(step.Config, step.Env)
+
+	migration, err := parseDBMigration(step, config, appName)
+	if err != nil {
+		return "", err
+	}
+
+	var resources []*database.ResourceInstance
+	if migration.Database != "" {
+		if e.resourceManager == nil {
+			return "", fmt.Errorf("db-migrate step cannot look up database '%s' without a resource manager; set 'url' instead", migration.Database)
+		}
+		if resources, err = e.resourceManager.GetResourcesByApplication(appName); err != nil {
+			return "", fmt.Errorf("failed to look up database '%s': %w", migration.Database, err)
+		}
+	}
+	conn, err := databaseConnection(migration, resources)
+	if err != nil {
+		return "", err
+	}
+
+	var files map[string]string
+	if migration.Migrations != "" {
+		if files, err = readMigrationFiles(migration.Migrations); err != nil {
+			return "", err
+		}
+	}
+	env := migrationEnv(migration, conn)
+
+	release, err := dbMigrationLocks.acquire(ctx, migration.Namespace+"/"+migration.target(), migration.LockTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	ctx, releaseIdentity, err := e.withApplicationIdentity(ctx, appName, migration.Namespace)
+	if err != nil {
+		return "", err
+	}
+	defer releaseIdentity()
+
+	var logs strings.Builder
+	if migration.DryRun || migration.ApprovalNeeded {
+		if migration.DryRun && !migration.canPreview() {
+			return "", fmt.Errorf("db-migrate step with tool custom requires 'dry_run_command' for a dry run")
+		}
+
+		preview, previewPath := "", ""
+		if migration.canPreview() {
+			logger.Infof("Previewing migration of database '%s' with %s", migration.target(), migration.Tool)
+			if preview, err = e.runMigrationJob(ctx, migration, appName, env, files, true); err != nil {
+				return preview, fmt.Errorf("migration preview failed: %w", err)
+			}
+			previewPath = migrationPreviewPath(appName, step.Name)
+			if err := os.MkdirAll(filepath.Dir(previewPath), 0700); err != nil {
+				return preview, fmt.Errorf("failed to create preview directory: %w", err)
+			}
+			if err := os.WriteFile(previewPath, []byte(preview), 0600); err != nil {
+				return preview, fmt.Errorf("failed to write migration preview: %w", err)
+			}
+			e.execContext.SetStepOutput(step.Name, "preview_file", previewPath)
+			fmt.Fprintf(&logs, "SQL preview stored in %s\n", previewPath)
+		}
+		if migration.DryRun {
+			logs.WriteString(preview)
+			return logs.String(), nil
+		}
+
+		if err := e.waitForApproval(ctx, step, appName, execID, stepID, migrationSummary(migration, appName, preview), previewPath, ""); err != nil {
+			return logs.String(), err
+		}
+	}
+
+	logger.Infof("Migrating database '%s' with %s", migration.target(), migration.Tool)
+	output, err := e.runMigrationJob(ctx, migration, appName, env, files, false)
+	logs.WriteString(output)
+	if err != nil {
+		return logs.String(), fmt.Errorf("migration of database '%s' failed: %w", migration.target(), err)
+	}
+	return logs.String(), nil
+}
+
+// runMigrationJob runs the migration tool as a Kubernetes job, waits for it and returns its
+// logs. The job is named after the database, so a migration started by another server
+// fails to create it while this one runs.
+func (e *WorkflowExecutor) runMigrationJob(ctx context.Context, migration *dbMigration, appName string, env, files map[string]string, preview bool) (string, error) {
+	setup, job, err := migrationManifests(migration, appName, env, files, preview)
+	if err != nil {
+		return "", err
+	}
+	name, namespace := migration.jobName(appName), migration.Namespace
+
+	cmd := e.kubectl(ctx, "apply", "-f", "-")
+	cmd.Stdin = strings.NewReader(setup)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return string(output), fmt.Errorf("failed to create migration secret: %w", err)
+	}
+	cleanup := func() {
+		// A cancelled step must still remove the job, or it keeps the database locked
+		_ = e.kubectl(context.WithoutCancel(ctx), "delete", "-n", namespace, "job/"+name, "secret/"+name, "configmap/"+name,
+			"--ignore-not-found", "--cascade=foreground").Run()
+	}
+
+	cmd = e.kubectl(ctx, "create", "-f", "-")
+	cmd.Stdin = strings.NewReader(job)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if strings.Contains(string(output), "AlreadyExists") {
+			// Not ours: keep the other migration's objects
+			return "", fmt.Errorf("another migration of database '%s' is running (job %s/%s)", migration.target(), namespace, name)
+		}
+		cleanup()
+		return string(output), fmt.Errorf("failed to create migration job: %w", err)
+	}
+	defer cleanup()
+
+	waitErr := e.waitForMigrationJob(ctx, namespace, name, migration.Timeout)
+	logs, err := e.kubectl(ctx, "logs", "-n", namespace, "job/"+name).CombinedOutput()
+	if waitErr != nil {
+		return string(logs), waitErr
+	}
+	if err != nil {
+		return string(logs), fmt.Errorf("failed to read migration logs: %w", err)
+	}
+	return string(logs), nil
+}
+
+// waitForMigrationJob waits until a job succeeded, failed or timeout passed
+func (e *WorkflowExecutor) waitForMigrationJob(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(migrationJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		output, err := e.kubectl(ctx, "get", "job", name, "-n", namespace,
+			"-o", "jsonpath={.status.succeeded},{.status.failed}").Output()
+		if err != nil {
+			return fmt.Errorf("failed to check migration job %s/%s: %w", namespace, name, err)
+		}
+		succeeded, failed := jobCounts(string(output))
+		switch {
+		case succeeded > 0:
+			return nil
+		case failed > 0:
+			return fmt.Errorf("migration job %s/%s failed", namespace, name)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("migration job %s/%s did not finish within %s", namespace, name, timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// jobCounts parses the "succeeded,failed" counts of a job status
+func jobCounts(output string) (succeeded, failed int) {
+	parts := strings.SplitN(strings.TrimSpace(output), ",", 2)
+	succeeded, _ = strconv.Atoi(parts[0])
+	if len(parts) == 2 {
+		failed, _ = strconv.Atoi(parts[1])
+	}
+	return succeeded, failed
+}
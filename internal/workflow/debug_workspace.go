@@ -0,0 +1,389 @@
+package workflow
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"innominatus/internal/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Debug workspace limits
+const (
+	DefaultDebugRetention       = time.Hour     // How long a failed step's workspace is kept for a debug session to be opened
+	DefaultDebugSessionDuration = 2 * time.Hour // Session length when the request names none
+	MaxDebugSessionDuration     = 8 * time.Hour
+	MaxDebugWorkspaceBytes      = 200 << 20 // Larger paths are not retained
+)
+
+// Debug workspace errors
+var (
+	ErrDebugWorkspaceNotFound = errors.New("no workspace retained for this step")
+	ErrDebugSessionNotOpen    = errors.New("no open debug session for this step")
+)
+
+// DebugWorkspace is the retained workspace of a failed step. It is removed when the
+// retention ends, unless a debug session keeps it until the session expires.
+type DebugWorkspace struct {
+	ExecutionID int64         `json:"execution_id"`
+	StepID      int64         `json:"step_id"`
+	StepName    string        `json:"step_name"`
+	StepType    string        `json:"step_type"`
+	AppName     string        `json:"app_name"`
+	Paths       []string      `json:"paths"`             // Original paths, stored under workspace/<index>
+	Skipped     []string      `json:"skipped,omitempty"` // Paths over MaxDebugWorkspaceBytes
+	Size        int64         `json:"size"`
+	FailedAt    time.Time     `json:"failed_at"`
+	RetainUntil time.Time     `json:"retain_until"`
+	Session     *DebugSession `json:"session,omitempty"`
+}
+
+// DebugSession is a time-boxed hold on a retained workspace
+type DebugSession struct {
+	OpenedBy  string    `json:"opened_by"`
+	OpenedAt  time.Time `json:"opened_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Open reports whether the session is active at now
+func (s *DebugSession) Open(now time.Time) bool {
+	return s != nil && now.Before(s.ExpiresAt)
+}
+
+// expiresAt is when the workspace may be removed
+func (w *DebugWorkspace) expiresAt() time.Time {
+	if w.Session != nil && w.Session.ExpiresAt.After(w.RetainUntil) {
+		return w.Session.ExpiresAt
+	}
+	return w.RetainUntil
+}
+
+// DebugWorkspaces keeps copies of failed step workspaces on disk, one directory per step
+type DebugWorkspaces struct {
+	root      string
+	retention time.Duration
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// NewDebugWorkspaces creates a store rooted at dir that keeps failed workspaces for retention
+func NewDebugWorkspaces(dir string, retention time.Duration) *DebugWorkspaces {
+	if retention <= 0 {
+		retention = DefaultDebugRetention
+	}
+	return &DebugWorkspaces{root: dir, retention: retention, now: time.Now}
+}
+
+// SetDebugWorkspaces enables retaining the workspaces of failed steps
+func (e *WorkflowExecutor) SetDebugWorkspaces(workspaces *DebugWorkspaces) {
+	e.debugWorkspaces = workspaces
+}
+
+// DebugWorkspaces returns the executor's debug workspace store, or nil when disabled
+func (e *WorkflowExecutor) DebugWorkspaces() *DebugWorkspaces {
+	return e.debugWorkspaces
+}
+
+// Retain copies the existing paths of a failed step and removes expired workspaces
+func (d *DebugWorkspaces) Retain(workspace *DebugWorkspace, paths []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sweep()
+
+	dir := d.dir(workspace.ExecutionID, workspace.StepID)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clear debug workspace: %w", err)
+	}
+
+	now := d.now()
+	workspace.FailedAt = now
+	workspace.RetainUntil = now.Add(d.retention)
+	workspace.Paths = nil
+	workspace.Size = 0
+	for _, path := range paths {
+		size, err := pathSize(path)
+		if err != nil {
+			continue
+		}
+		if workspace.Size+size > MaxDebugWorkspaceBytes {
+			workspace.Skipped = append(workspace.Skipped, path)
+			continue
+		}
+		dst := filepath.Join(dir, "workspace", strconv.Itoa(len(workspace.Paths)))
+		if err := copyPath(path, dst); err != nil {
+			_ = os.RemoveAll(dir)
+			return fmt.Errorf("failed to retain %s: %w", path, err)
+		}
+		workspace.Paths = append(workspace.Paths, path)
+		workspace.Size += size
+	}
+	if len(workspace.Paths) == 0 && len(workspace.Skipped) == 0 {
+		return nil
+	}
+	return d.write(workspace)
+}
+
+// Get returns the retained workspace of a step
+func (d *DebugWorkspaces) Get(executionID, stepID int64) (*DebugWorkspace, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sweep()
+	return d.read(executionID, stepID)
+}
+
+// List returns the retained workspaces of an execution
+func (d *DebugWorkspaces) List(executionID int64) []DebugWorkspace {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sweep()
+
+	workspaces := []DebugWorkspace{}
+	for _, workspace := range d.all() {
+		if workspace.ExecutionID == executionID {
+			workspaces = append(workspaces, *workspace)
+		}
+	}
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].StepID < workspaces[j].StepID })
+	return workspaces
+}
+
+// Open starts or extends a debug session; the workspace is kept until it expires
+func (d *DebugWorkspaces) Open(executionID, stepID int64, user string, duration time.Duration) (*DebugWorkspace, error) {
+	if duration <= 0 {
+		duration = DefaultDebugSessionDuration
+	}
+	if duration > MaxDebugSessionDuration {
+		return nil, fmt.Errorf("debug sessions last at most %s", MaxDebugSessionDuration)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sweep()
+
+	workspace, err := d.read(executionID, stepID)
+	if err != nil {
+		return nil, err
+	}
+	now := d.now()
+	if workspace.Session.Open(now) {
+		workspace.Session.ExpiresAt = now.Add(duration)
+	} else {
+		workspace.Session = &DebugSession{OpenedBy: user, OpenedAt: now, ExpiresAt: now.Add(duration)}
+	}
+	if err := d.write(workspace); err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// Close ends the session and removes the workspace
+func (d *DebugWorkspaces) Close(executionID, stepID int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.read(executionID, stepID); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(d.dir(executionID, stepID)); err != nil {
+		return fmt.Errorf("failed to remove debug workspace: %w", err)
+	}
+	return nil
+}
+
+// WriteArchive writes the workspace of an open session as a gzipped tar archive. Each
+// retained path is stored below its original location.
+func (d *DebugWorkspaces) WriteArchive(w io.Writer, executionID, stepID int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	workspace, err := d.read(executionID, stepID)
+	if err != nil {
+		return err
+	}
+	if !workspace.Session.Open(d.now()) {
+		return ErrDebugSessionNotOpen
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for i, path := range workspace.Paths {
+		src := filepath.Join(d.dir(executionID, stepID), "workspace", strconv.Itoa(i))
+		name := filepath.ToSlash(filepath.Clean(path))
+		if filepath.IsAbs(path) {
+			name = name[1:]
+		}
+		if err := addTree(tw, src, name); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", path, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// sweep removes workspaces whose retention and session have both ended. Callers hold d.mu.
+func (d *DebugWorkspaces) sweep() {
+	now := d.now()
+	for _, workspace := range d.all() {
+		if now.After(workspace.expiresAt()) {
+			_ = os.RemoveAll(d.dir(workspace.ExecutionID, workspace.StepID))
+		}
+	}
+}
+
+func (d *DebugWorkspaces) all() []*DebugWorkspace {
+	dirs, err := os.ReadDir(d.root)
+	if err != nil {
+		return nil
+	}
+	workspaces := make([]*DebugWorkspace, 0, len(dirs))
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(d.root, dir.Name(), "workspace.json")) // #nosec G304 - directory we created
+		if err != nil {
+			continue
+		}
+		var workspace DebugWorkspace
+		if json.Unmarshal(data, &workspace) == nil {
+			workspaces = append(workspaces, &workspace)
+		}
+	}
+	return workspaces
+}
+
+func (d *DebugWorkspaces) dir(executionID, stepID int64) string {
+	return filepath.Join(d.root, fmt.Sprintf("%d-%d", executionID, stepID))
+}
+
+func (d *DebugWorkspaces) read(executionID, stepID int64) (*DebugWorkspace, error) {
+	data, err := os.ReadFile(filepath.Join(d.dir(executionID, stepID), "workspace.json")) // #nosec G304 - IDs are numbers
+	if os.IsNotExist(err) {
+		return nil, ErrDebugWorkspaceNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var workspace DebugWorkspace
+	if err := json.Unmarshal(data, &workspace); err != nil {
+		return nil, fmt.Errorf("failed to read debug workspace: %w", err)
+	}
+	return &workspace, nil
+}
+
+func (d *DebugWorkspaces) write(workspace *DebugWorkspace) error {
+	dir := d.dir(workspace.ExecutionID, workspace.StepID)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create debug workspace: %w", err)
+	}
+	data, err := json.MarshalIndent(workspace, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal debug workspace: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, "workspace.json"), data, 0600)
+}
+
+// retainFailedWorkspace keeps the workspace of a failed step for a debug session
+func (e *WorkflowExecutor) retainFailedWorkspace(step types.Step, appName string, execID, stepID int64) {
+	if e.debugWorkspaces == nil {
+		return
+	}
+	workspace := &DebugWorkspace{
+		ExecutionID: execID,
+		StepID:      stepID,
+		StepName:    step.Name,
+		StepType:    step.Type,
+		AppName:     appName,
+	}
+	if err := e.debugWorkspaces.Retain(workspace, stepWorkspacePaths(step, appName)); err != nil {
+		e.logger.Warnf("Failed to retain workspace of step %s: %v", step.Name, err)
+	}
+}
+
+// stepWorkspacePaths returns the directories and files a step works in
+func stepWorkspacePaths(step types.Step, appName string) []string {
+	var paths []string
+	switch step.Type {
+	case "terraform":
+		paths = append(paths, fmt.Sprintf("workspaces/%s/terraform", appName))
+	case "git-commit-manifests":
+		paths = append(paths, fmt.Sprintf("/tmp/score-repo-%s", step.RepoName), step.ManifestPath)
+	case "render":
+		if step.RepoName == "" {
+			if dir, err := renderOutputDir(appName, step.Name, configString(step.Config, "output")); err == nil {
+				paths = append(paths, dir)
+			}
+		}
+	}
+	paths = append(paths, step.OutputDir, step.OutputFile)
+	if step.Cache != nil {
+		paths = append(paths, step.Cache.Paths...)
+	}
+
+	seen := make(map[string]bool, len(paths))
+	unique := paths[:0]
+	for _, path := range paths {
+		if path == "" || seen[filepath.Clean(path)] {
+			continue
+		}
+		seen[filepath.Clean(path)] = true
+		unique = append(unique, path)
+	}
+	return unique
+}
+
+func pathSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode().IsRegular() {
+			size += fi.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// addTree writes the file or directory src into the archive as name
+func addTree(tw *tar.Writer, src, name string) error {
+	return filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(name, rel))
+		if fi.IsDir() {
+			header.Name += "/"
+		} else if !fi.Mode().IsRegular() {
+			return nil
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path) // #nosec G304 - path inside the debug workspace
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
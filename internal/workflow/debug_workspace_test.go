@@ -0,0 +1,134 @@
+package workflow
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"innominatus/internal/types"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugWorkspaces(t *testing.T) {
+	dir := t.TempDir()
+	outputDir := filepath.Join(dir, "generated")
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	workspaces := NewDebugWorkspaces(filepath.Join(dir, "debug"), time.Hour)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	workspaces.now = func() time.Time { return now }
+	executor.SetDebugWorkspaces(workspaces)
+
+	executor.RegisterStepExecutor("generate", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+		require.NoError(t, os.MkdirAll(outputDir, 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(outputDir, "main.tf"), []byte("# broken"), 0600))
+		return errors.New("terraform validate failed")
+	})
+	step := types.Step{Name: "generate", Type: "generate", OutputDir: outputDir}
+	require.Error(t, executor.executeStepWithExecutor(context.Background(), step, "shop", 7, 3))
+
+	// The workspace outlives the step's own cleanup
+	require.NoError(t, os.RemoveAll(outputDir))
+	workspace, err := workspaces.Get(7, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "generate", workspace.StepName)
+	assert.Equal(t, []string{outputDir}, workspace.Paths)
+	assert.Equal(t, now.Add(time.Hour), workspace.RetainUntil)
+	assert.Nil(t, workspace.Session)
+	assert.Len(t, workspaces.List(7), 1)
+
+	var archive bytes.Buffer
+	assert.ErrorIs(t, workspaces.WriteArchive(&archive, 7, 3), ErrDebugSessionNotOpen)
+
+	_, err = workspaces.Open(7, 3, "alice", MaxDebugSessionDuration+time.Minute)
+	assert.Error(t, err)
+	workspace, err = workspaces.Open(7, 3, "alice", 3*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", workspace.Session.OpenedBy)
+	assert.Equal(t, now.Add(3*time.Hour), workspace.Session.ExpiresAt)
+
+	require.NoError(t, workspaces.WriteArchive(&archive, 7, 3))
+	files := readArchive(t, &archive)
+	assert.Equal(t, "# broken", files[filepath.ToSlash(outputDir)[1:]+"/main.tf"])
+
+	// The open session keeps the workspace past its retention
+	now = now.Add(2 * time.Hour)
+	_, err = workspaces.Get(7, 3)
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Hour)
+	_, err = workspaces.Get(7, 3)
+	assert.ErrorIs(t, err, ErrDebugWorkspaceNotFound)
+
+	t.Run("expires without session", func(t *testing.T) {
+		require.Error(t, executor.executeStepWithExecutor(context.Background(), step, "shop", 8, 1))
+		now = now.Add(time.Hour + time.Minute)
+		_, err := workspaces.Open(8, 1, "alice", 0)
+		assert.ErrorIs(t, err, ErrDebugWorkspaceNotFound)
+	})
+
+	t.Run("close removes workspace", func(t *testing.T) {
+		require.Error(t, executor.executeStepWithExecutor(context.Background(), step, "shop", 9, 1))
+		require.NoError(t, workspaces.Close(9, 1))
+		_, err := workspaces.Get(9, 1)
+		assert.ErrorIs(t, err, ErrDebugWorkspaceNotFound)
+		assert.ErrorIs(t, workspaces.Close(9, 1), ErrDebugWorkspaceNotFound)
+	})
+}
+
+func TestStepWorkspacePaths(t *testing.T) {
+	tests := []struct {
+		name string
+		step types.Step
+		want []string
+	}{
+		{
+			name: "terraform",
+			step: types.Step{Type: "terraform", OutputDir: "workspaces/shop/terraform"},
+			want: []string{"workspaces/shop/terraform"},
+		},
+		{
+			name: "git commit",
+			step: types.Step{Type: "git-commit-manifests", RepoName: "shop-gitops"},
+			want: []string{"/tmp/score-repo-shop-gitops"},
+		},
+		{
+			name: "outputs and cache paths",
+			step: types.Step{Type: "policy", OutputFile: "out.json", Cache: &types.StepCacheConfig{Paths: []string{"build/", "out.json"}}},
+			want: []string{"out.json", "build/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, stepWorkspacePaths(tt.step, "shop"))
+		})
+	}
+}
+
+func readArchive(t *testing.T, r io.Reader) map[string]string {
+	gz, err := gzip.NewReader(r)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		if header.Typeflag == tar.TypeReg {
+			data, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			files[header.Name] = string(data)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package workflow
+
+import (
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/licenses"
+	"innominatus/internal/types"
+	"os"
+)
+
+// DependencyReportStore records the software bills of materials of build and scan steps.
+// Application stores that implement it also record the SBOMs steps declare in config.sbom.
+type DependencyReportStore interface {
+	CreateDependencyReport(report *database.DependencyReport) error
+}
+
+// sbomConfig is the sbom block of a step's config. A plain string is the file.
+type sbomConfig struct {
+	file      string // CycloneDX or SPDX JSON written by the step
+	image     string // Image the SBOM describes; default the image named in the SBOM
+	baseImage string // Base image; default the operating system found in the SBOM
+	version   string // Application version; default the image tag
+}
+
+// stepSBOMConfig returns the interpolated sbom block of a step, if it has one
+func (e *WorkflowExecutor) stepSBOMConfig(step types.Step) (sbomConfig, bool) {
+	raw, ok := step.Config["sbom"]
+	if !ok {
+		return sbomConfig{}, false
+	}
+	value := e.execContext.InterpolateResourceParams(map[string]interface{}{"sbom": raw}, step.Env)["sbom"]
+
+	var config sbomConfig
+	switch v := value.(type) {
+	case string:
+		config.file = v
+	case map[string]interface{}:
+		config.file, _ = v["file"].(string)
+		config.image, _ = v["image"].(string)
+		config.baseImage, _ = v["baseImage"].(string)
+		config.version, _ = v["version"].(string)
+	}
+	return config, true
+}
+
+// recordDependencyReport reads the SBOM a completed step declares and records its components
+// for the application version. Failures are logged and do not fail the step.
+func (e *WorkflowExecutor) recordDependencyReport(step types.Step, appName string, execID int64) {
+	config, ok := e.stepSBOMConfig(step)
+	store, isStore := e.applications.(DependencyReportStore)
+	if !ok || !isStore || appName == "" {
+		return
+	}
+
+	report, err := readDependencyReport(config, step, appName, execID)
+	if err == nil {
+		err = store.CreateDependencyReport(report)
+	}
+	if err != nil {
+		e.logger.WarnWithFields("Failed to record dependency report", map[string]interface{}{
+			"app_name":  appName,
+			"step_name": step.Name,
+			"error":     err.Error(),
+		})
+		return
+	}
+
+	e.logger.InfoWithFields("Recorded dependency report", map[string]interface{}{
+		"app_name":   appName,
+		"step_name":  step.Name,
+		"version":    report.Version,
+		"components": len(report.Components),
+	})
+}
+
+// readDependencyReport parses the SBOM file of a step
+func readDependencyReport(config sbomConfig, step types.Step, appName string, execID int64) (*database.DependencyReport, error) {
+	if config.file == "" {
+		return nil, fmt.Errorf("config.sbom.file is required")
+	}
+	// #nosec G304 - the SBOM path is part of the workflow definition
+	data, err := os.ReadFile(config.file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SBOM: %w", err)
+	}
+	sbom, err := licenses.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &database.DependencyReport{
+		ApplicationName: appName,
+		Version:         config.version,
+		StepName:        step.Name,
+		Format:          sbom.Format,
+		Image:           config.image,
+		BaseImage:       config.baseImage,
+		Components:      sbom.Components,
+	}
+	if execID != 0 {
+		report.ExecutionID = &execID
+	}
+	if report.Image == "" {
+		report.Image = sbom.Image
+	}
+	if report.BaseImage == "" {
+		report.BaseImage = sbom.BaseImage
+	}
+	if report.Version == "" {
+		report.Version = licenses.Tag(report.Image)
+	}
+	if report.Version == "" {
+		return nil, fmt.Errorf("config.sbom.version is required when the image has no tag")
+	}
+	if report.Components == nil {
+		report.Components = []licenses.Component{}
+	}
+	return report, nil
+}
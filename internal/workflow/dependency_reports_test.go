@@ -0,0 +1,58 @@
+package workflow
+
+import (
+	"innominatus/internal/database"
+	"innominatus/internal/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDependencyReportStore struct {
+	fakeApplicationStore
+	reports []*database.DependencyReport
+}
+
+func (f *fakeDependencyReportStore) CreateDependencyReport(report *database.DependencyReport) error {
+	f.reports = append(f.reports, report)
+	return nil
+}
+
+func TestRecordDependencyReport(t *testing.T) {
+	sbom := filepath.Join(t.TempDir(), "sbom.json")
+	require.NoError(t, os.WriteFile(sbom, []byte(`{
+		"bomFormat": "CycloneDX",
+		"components": [
+			{"type": "operating-system", "name": "debian", "version": "12"},
+			{"type": "library", "name": "express", "version": "4.18.2", "licenses": [{"license": {"id": "MIT"}}]}
+		]
+	}`), 0600))
+
+	store := &fakeDependencyReportStore{fakeApplicationStore: fakeApplicationStore{"shop": {Metadata: types.Metadata{Name: "shop"}}}}
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetApplicationStore(store)
+	executor.execContext.SetVariable("image", "registry.example.com/shop:1.4.2")
+
+	executor.recordDependencyReport(types.Step{Name: "scan", Config: map[string]interface{}{
+		"sbom": map[string]interface{}{"file": sbom, "image": "${workflow.image}"},
+	}}, "shop", 7)
+
+	require.Len(t, store.reports, 1)
+	report := store.reports[0]
+	assert.Equal(t, "1.4.2", report.Version, "version defaults to the image tag")
+	assert.Equal(t, "registry.example.com/shop:1.4.2", report.Image)
+	assert.Equal(t, "debian 12", report.BaseImage)
+	assert.Equal(t, int64(7), *report.ExecutionID)
+	assert.Len(t, report.Components, 2)
+
+	// Without a version or tagged image nothing is recorded
+	executor.recordDependencyReport(types.Step{Name: "scan", Config: map[string]interface{}{"sbom": sbom}}, "shop", 8)
+	assert.Len(t, store.reports, 1)
+
+	// Steps without an sbom block are ignored
+	executor.recordDependencyReport(types.Step{Name: "build"}, "shop", 9)
+	assert.Len(t, store.reports, 1)
+}
@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"innominatus/pkg/sdk"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// labelVariablePrefix prefixes the variables holding the deployment labels of a run: ${labels.team}
+const labelVariablePrefix = "labels."
+
+// Environment variables that hand the deployment labels of a run to step processes as a
+// JSON object. Terraform reads the second as var.innominatus_labels when a module
+// declares it.
+const (
+	LabelsEnvVariable          = "INNOMINATUS_LABELS"
+	TerraformLabelsEnvVariable = "TF_VAR_innominatus_labels"
+)
+
+// initDeploymentLabels stores the deployment labels of an execution in the context. The
+// environment is the 'environment' variable of the run, falling back to the environment
+// type of the Score spec.
+func (e *WorkflowExecutor) initDeploymentLabels(appName string, executionID int64) {
+	team, environment := "", e.execContext.WorkflowVariables["environment"]
+	if e.applications != nil {
+		if app, err := e.applications.GetApplication(appName); err == nil && app != nil {
+			team = app.Team
+			if environment == "" && app.ScoreSpec != nil && app.ScoreSpec.Environment != nil {
+				environment = app.ScoreSpec.Environment.Type
+			}
+		}
+	}
+
+	variables := make(map[string]string, len(sdk.DeploymentLabelKeys))
+	for key, value := range sdk.DeploymentLabels(appName, team, environment, executionID) {
+		variables[labelVariablePrefix+key] = value
+	}
+	e.execContext.SetWorkflowVariables(variables)
+}
+
+// deploymentLabels returns the deployment labels of the current run; nil outside a run
+func (e *WorkflowExecutor) deploymentLabels() map[string]string {
+	e.execContext.mu.RLock()
+	defer e.execContext.mu.RUnlock()
+
+	var labels map[string]string
+	for _, key := range sdk.DeploymentLabelKeys {
+		if value, ok := e.execContext.WorkflowVariables[labelVariablePrefix+key]; ok {
+			if labels == nil {
+				labels = make(map[string]string, len(sdk.DeploymentLabelKeys))
+			}
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// deploymentLabelsEnvironment returns the step process variables holding labels
+func deploymentLabelsEnvironment(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(labels)
+	if err != nil {
+		return nil
+	}
+	return map[string]string{
+		LabelsEnvVariable:          string(data),
+		TerraformLabelsEnvVariable: string(data),
+	}
+}
+
+// LabelManifests adds labels to the metadata of every Kubernetes object in a manifest,
+// including the items of List objects. Labels an object already sets are kept, so
+// selectors and team conventions keep working. Pod templates are left alone: a new
+// execution ID there would restart every workload on each deployment.
+func LabelManifests(manifest string, labels map[string]string) (string, error) {
+	if len(labels) == 0 {
+		return manifest, nil
+	}
+
+	var documents []string
+	decoder := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return "", fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if len(object) == 0 {
+			continue
+		}
+		labelObject(object, labels)
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode manifest: %w", err)
+		}
+		documents = append(documents, string(data))
+	}
+	return strings.Join(documents, "---\n"), nil
+}
+
+// labelObject adds the labels the Kubernetes object does not set yet
+func labelObject(object map[string]interface{}, labels map[string]string) {
+	if _, ok := object["kind"]; !ok {
+		return
+	}
+	if items, ok := object["items"].([]interface{}); ok {
+		for _, item := range items {
+			if itemObject, ok := item.(map[string]interface{}); ok {
+				labelObject(itemObject, labels)
+			}
+		}
+	}
+
+	metadata, ok := object["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		object["metadata"] = metadata
+	}
+	existing, ok := metadata["labels"].(map[string]interface{})
+	if !ok {
+		existing = map[string]interface{}{}
+		metadata["labels"] = existing
+	}
+	for key, value := range labels {
+		if _, set := existing[key]; !set {
+			existing[key] = value
+		}
+	}
+}
+
+// labelArgs returns labels as key=value arguments of kubectl label
+func labelArgs(labels map[string]string) []string {
+	args := make([]string, 0, len(labels))
+	for key, value := range labels {
+		args = append(args, key+"="+value)
+	}
+	sort.Strings(args)
+	return args
+}
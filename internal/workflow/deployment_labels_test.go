@@ -0,0 +1,87 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"innominatus/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// fakeLabelKubectl records its arguments, stdin and labels environment next to itself
+const fakeLabelKubectl = `#!/bin/sh
+dir=$(dirname "$0")
+echo "$*" >> "$dir/args.txt"
+echo "$INNOMINATUS_LABELS" > "$dir/env.txt"
+if [ "$1" = "apply" ]; then
+  cat > "$dir/applied.yaml"
+  echo "deployment.apps/web created"
+fi
+if [ "$1" = "create" ]; then
+  echo "namespace/$3 created"
+fi
+`
+
+func TestDeploymentLabelsOnKubernetesSteps(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubectl"), []byte(fakeLabelKubectl), 0755)) // #nosec G306 - test executable
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Chdir(t.TempDir())
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetApplicationStore(fakeApplicationStore{"shop": {Metadata: types.Metadata{Name: "shop"}}})
+
+	manifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n  labels:\n    app: web\nspec:\n  template:\n    metadata:\n      labels:\n        app: web\n"
+	workflow := types.Workflow{Steps: []types.Step{
+		{Name: "namespace", Type: "kubernetes", Config: map[string]interface{}{"operation": "create-namespace", "namespace": "shop"}},
+		{Name: "deploy", Type: "kubernetes", Config: map[string]interface{}{"manifest": manifest, "namespace": "shop"}},
+	}}
+	require.NoError(t, executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", workflow, map[string]string{"environment": "production"}))
+
+	args, err := os.ReadFile(filepath.Join(dir, "args.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(args), "label namespace shop --overwrite app=shop environment=production managed-by=innominatus team=unassigned workflow-execution-id=1")
+
+	applied, err := os.ReadFile(filepath.Join(dir, "applied.yaml"))
+	require.NoError(t, err)
+	var object map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(applied, &object))
+	labels := object["metadata"].(map[string]interface{})["labels"].(map[string]interface{})
+	assert.Equal(t, "web", labels["app"], "labels set by the manifest are kept")
+	assert.Equal(t, "production", labels["environment"])
+	assert.Equal(t, "1", labels["workflow-execution-id"])
+	template := object["spec"].(map[string]interface{})["template"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"app": "web"}, template["metadata"].(map[string]interface{})["labels"], "pod templates are not labeled")
+
+	env, err := os.ReadFile(filepath.Join(dir, "env.txt"))
+	require.NoError(t, err)
+	var envLabels map[string]string
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(string(env))), &envLabels))
+	assert.Equal(t, "shop", envLabels["app"])
+	assert.Equal(t, "innominatus", envLabels["managed-by"])
+}
+
+func TestLabelManifests(t *testing.T) {
+	labels := map[string]string{"app": "shop", "managed-by": "innominatus"}
+
+	labeled, err := LabelManifests("kind: List\nitems:\n  - kind: Service\n    metadata:\n      name: web\n---\n# empty\n---\nkind: ConfigMap\nmetadata:\n  name: cfg\n", labels)
+	require.NoError(t, err)
+	documents := strings.Split(labeled, "---\n")
+	require.Len(t, documents, 2)
+	assert.Contains(t, documents[0], "managed-by: innominatus")
+	assert.Contains(t, documents[1], "app: shop")
+
+	unchanged, err := LabelManifests("kind: Service\n", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Service\n", unchanged)
+
+	_, err = LabelManifests("kind: [", labels)
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package workflow
+
+import (
+	"strconv"
+	"time"
+
+	"innominatus/internal/clock"
+)
+
+// Variables holding the time an execution started. Every step of the run sees the same
+// value, so names and tags derived from it stay consistent: ${execution.now}
+const (
+	ExecutionNowVariable       = "execution.now"       // RFC 3339 in UTC
+	ExecutionDateVariable      = "execution.date"      // YYYY-MM-DD in UTC
+	ExecutionTimestampVariable = "execution.timestamp" // Unix seconds
+)
+
+// SetClock replaces the wall clock the execution time snapshot is taken from
+func (e *WorkflowExecutor) SetClock(c clock.Clock) {
+	e.clock = c
+}
+
+// ExecutionTimeVariables returns the execution time variables of a run started at now
+func ExecutionTimeVariables(now time.Time) map[string]string {
+	now = now.UTC()
+	return map[string]string{
+		ExecutionNowVariable:       now.Format(time.RFC3339),
+		ExecutionDateVariable:      now.Format(time.DateOnly),
+		ExecutionTimestampVariable: strconv.FormatInt(now.Unix(), 10),
+	}
+}
+
+// initExecutionTime snapshots the start time of the execution into the context. Runs
+// without a recorded start time use the current time.
+func (e *WorkflowExecutor) initExecutionTime(startedAt time.Time) {
+	if startedAt.IsZero() {
+		startedAt = clock.OrReal(e.clock).Now()
+	}
+	e.execContext.SetWorkflowVariables(ExecutionTimeVariables(startedAt))
+}
@@ -0,0 +1,40 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/clock"
+	"innominatus/internal/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionTimeSnapshot(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 10, 15, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60)))
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetClock(fake)
+
+	var seen []string
+	executor.RegisterStepExecutor("capture", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+		now, _ := executor.execContext.GetVariable(ExecutionNowVariable)
+		seen = append(seen, now)
+		// Time passing during the run does not change the snapshot
+		fake.Advance(time.Hour)
+		return nil
+	})
+
+	workflow := types.Workflow{
+		Steps: []types.Step{{Name: "first", Type: "capture"}, {Name: "second", Type: "capture"}},
+	}
+	require.NoError(t, executor.ExecuteWorkflowWithName("shop", "deploy", workflow))
+	assert.Equal(t, []string{"2026-10-15T21:30:00Z", "2026-10-15T21:30:00Z"}, seen)
+}
+
+func TestExecutionTimeVariables(t *testing.T) {
+	vars := ExecutionTimeVariables(time.Date(2026, 1, 2, 0, 30, 0, 0, time.FixedZone("CET", 60*60)))
+	assert.Equal(t, "2026-01-01T23:30:00Z", vars[ExecutionNowVariable])
+	assert.Equal(t, "2026-01-01", vars[ExecutionDateVariable])
+	assert.Equal(t, "1767310200", vars[ExecutionTimestampVariable])
+}
@@ -4,14 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"innominatus/internal/clock"
+	"innominatus/internal/cloudcreds"
 	"innominatus/internal/database"
 	"innominatus/internal/events"
+	"innominatus/internal/faults"
 	"innominatus/internal/graph"
 	"innominatus/internal/logging"
 	"innominatus/internal/types"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -44,6 +46,82 @@ type WorkflowRepositoryInterface interface {
 	AddWorkflowStepLogs(stepID int64, logs string) error
 }
 
+// parameterRecorder is implemented by repositories that persist the parameters an
+// execution started with, for run bundles and replays
+type parameterRecorder interface {
+	SetWorkflowExecutionParameters(execID int64, parameters map[string]string, sources map[string]types.ParameterSource) error
+}
+
+// replayMarker is implemented by repositories that link replays to the original run
+type replayMarker interface {
+	MarkWorkflowExecutionReplay(execID, originalID int64) error
+}
+
+// overrideRecorder is implemented by repositories that record the admin step overrides
+// an execution ran with
+type overrideRecorder interface {
+	SetWorkflowExecutionOverrides(execID int64, overrides *types.StepOverrides) error
+}
+
+type externalParametersKey struct{}
+
+// WithExternalParameters returns a context whose workflow executions store the sources
+// of the given parameters instead of their values, which may be secrets resolved from
+// Vault. Replays resolve them again from the same sources.
+func WithExternalParameters(ctx context.Context, sources map[string]types.ParameterSource) context.Context {
+	return context.WithValue(ctx, externalParametersKey{}, sources)
+}
+
+type stepOverridesKey struct{}
+
+// WithStepOverrides returns a context whose workflow executions record the admin step
+// overrides applied to the workflow with ApplyStepOverrides
+func WithStepOverrides(ctx context.Context, overrides *types.StepOverrides) context.Context {
+	return context.WithValue(ctx, stepOverridesKey{}, overrides)
+}
+
+type executionStartedKey struct{}
+
+// WithExecutionStarted returns a context whose workflow execution calls fn with the
+// execution ID once the execution is recorded, e.g. to link it from a change ticket.
+// Functions registered on a parent context are still called.
+func WithExecutionStarted(ctx context.Context, fn func(executionID int64)) context.Context {
+	if outer, ok := ctx.Value(executionStartedKey{}).(func(int64)); ok {
+		inner := fn
+		fn = func(executionID int64) {
+			outer(executionID)
+			inner(executionID)
+		}
+	}
+	return context.WithValue(ctx, executionStartedKey{}, fn)
+}
+
+type deploymentEnvironmentKey struct{}
+
+// WithDeploymentEnvironment returns a context whose workflow executions report the target
+// environment in their workflow.completed and workflow.failed events, for alerting on
+// production runs. Without it, the "environment" parameter of the run is reported.
+func WithDeploymentEnvironment(ctx context.Context, environment string) context.Context {
+	return context.WithValue(ctx, deploymentEnvironmentKey{}, environment)
+}
+
+// deploymentEnvironment returns the target environment of the run started with ctx
+func (e *WorkflowExecutor) deploymentEnvironment(ctx context.Context) string {
+	if environment, ok := ctx.Value(deploymentEnvironmentKey{}).(string); ok && environment != "" {
+		return environment
+	}
+	environment, _ := e.execContext.GetVariable("environment")
+	return environment
+}
+
+type replayKey struct{}
+
+// replayRun links an execution started by StartReplay to the original run
+type replayRun struct {
+	originalID int64
+	started    chan int64 // Receives the replay's execution ID once it is recorded
+}
+
 // ResourceManager interface defines the methods needed for resource management
 type ResourceManager interface {
 	GetResourcesByApplication(appName string) ([]*database.ResourceInstance, error)
@@ -59,11 +137,23 @@ type WorkflowExecutor struct {
 	resourceManager  ResourceManager
 	graphAdapter     *graph.Adapter
 	eventBus         events.EventBus
+	approvals        ApprovalStore
+	applications     ApplicationStore
+	stepCache        *StepCache
+	debugWorkspaces  *DebugWorkspaces
 	maxConcurrent    int
 	executionTimeout time.Duration
 	stepExecutors    map[string]StepExecutorFunc
+	compensators     map[string]CompensatorFunc
+	inheritedEnv     []string
+	policySources    PolicySources
+	kubeIdentity     KubernetesIdentity
+	stepLogs         stepLogLimiter
 	execContext      *ExecutionContext
 	outputParser     *OutputParser
+	clock            clock.Clock // Source of the execution time snapshot; nil means wall clock
+	faults           *faults.Injector
+	cloudCredentials *cloudcreds.Broker
 	logger           *logging.ZerologAdapter
 	mu               sync.RWMutex
 }
@@ -75,6 +165,7 @@ func NewWorkflowExecutor(repo WorkflowRepositoryInterface) *WorkflowExecutor {
 		maxConcurrent:    5,
 		executionTimeout: 30 * time.Minute,
 		stepExecutors:    make(map[string]StepExecutorFunc),
+		compensators:     defaultCompensators(),
 		execContext:      NewExecutionContext(),
 		outputParser:     NewOutputParser(),
 		logger:           logging.NewStructuredLogger("workflow"),
@@ -91,6 +182,7 @@ func NewWorkflowExecutorWithResourceManager(repo WorkflowRepositoryInterface, re
 		maxConcurrent:    5,
 		executionTimeout: 30 * time.Minute,
 		stepExecutors:    make(map[string]StepExecutorFunc),
+		compensators:     defaultCompensators(),
 		execContext:      NewExecutionContext(),
 		outputParser:     NewOutputParser(),
 		logger:           logging.NewStructuredLogger("workflow"),
@@ -107,6 +199,7 @@ func NewMultiTierWorkflowExecutor(repo WorkflowRepositoryInterface, resolver *Wo
 		maxConcurrent:    5,
 		executionTimeout: 30 * time.Minute,
 		stepExecutors:    make(map[string]StepExecutorFunc),
+		compensators:     defaultCompensators(),
 		execContext:      NewExecutionContext(),
 		outputParser:     NewOutputParser(),
 		logger:           logging.NewStructuredLogger("workflow"),
@@ -124,6 +217,7 @@ func NewMultiTierWorkflowExecutorWithResourceManager(repo WorkflowRepositoryInte
 		maxConcurrent:    5,
 		executionTimeout: 30 * time.Minute,
 		stepExecutors:    make(map[string]StepExecutorFunc),
+		compensators:     defaultCompensators(),
 		execContext:      NewExecutionContext(),
 		outputParser:     NewOutputParser(),
 		logger:           logging.NewStructuredLogger("workflow"),
@@ -143,6 +237,13 @@ func (e *WorkflowExecutor) SetEventBus(bus events.EventBus) {
 	e.logger.Info("Event bus configured for workflow executor")
 }
 
+// RegisterStepExecutor adds or replaces the executor for a step type
+func (e *WorkflowExecutor) RegisterStepExecutor(stepType string, executor StepExecutorFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stepExecutors[stepType] = executor
+}
+
 // stepToConfig converts a Step struct to a map for storage in the database
 // This ensures all step fields are preserved when storing workflow executions
 func stepToConfig(step types.Step) (map[string]interface{}, error) {
@@ -167,6 +268,7 @@ func (e *WorkflowExecutor) ExecuteMultiTierWorkflows(ctx context.Context, app *A
 	if e.logger == nil {
 		e.logger = logging.NewStructuredLogger("workflow")
 	}
+	ctx = logging.WithApp(ctx, app.Name)
 
 	if e.resolver == nil {
 		return fmt.Errorf("resolver not configured - use NewMultiTierWorkflowExecutor")
@@ -189,8 +291,11 @@ func (e *WorkflowExecutor) ExecuteMultiTierWorkflows(ctx context.Context, app *A
 		return fmt.Errorf("failed to create workflow execution: %w", err)
 	}
 
+	ctx = logging.WithWorkflowID(ctx, execution.ID)
+	logger := logging.FromContext(ctx, "workflow")
+
 	summary := e.resolver.GetWorkflowSummary(resolvedWorkflows)
-	e.logger.InfoWithFields("Starting multi-tier workflow execution", map[string]interface{}{
+	logger.InfoWithFields("Starting multi-tier workflow execution", map[string]interface{}{
 		"app_name":        app.Name,
 		"execution_id":    execution.ID,
 		"total_workflows": summary["total_workflows"],
@@ -206,7 +311,7 @@ func (e *WorkflowExecutor) ExecuteMultiTierWorkflows(ctx context.Context, app *A
 			continue
 		}
 
-		e.logger.InfoWithFields("Executing workflow phase", map[string]interface{}{
+		logger.InfoWithFields("Executing workflow phase", map[string]interface{}{
 			"app_name":       app.Name,
 			"execution_id":   execution.ID,
 			"phase":          string(phase),
@@ -217,12 +322,12 @@ func (e *WorkflowExecutor) ExecuteMultiTierWorkflows(ctx context.Context, app *A
 			// Mark execution as failed
 			errorMsg := err.Error()
 			if updateErr := e.repo.UpdateWorkflowExecution(execution.ID, database.WorkflowStatusFailed, &errorMsg); updateErr != nil {
-				e.logger.WarnWithFields("Failed to update workflow status", map[string]interface{}{
+				logger.WarnWithFields("Failed to update workflow status", map[string]interface{}{
 					"execution_id": execution.ID,
 					"error":        updateErr.Error(),
 				})
 			}
-			e.logger.ErrorWithFields("Phase execution failed", map[string]interface{}{
+			logger.ErrorWithFields("Phase execution failed", map[string]interface{}{
 				"app_name":     app.Name,
 				"execution_id": execution.ID,
 				"phase":        string(phase),
@@ -231,7 +336,7 @@ func (e *WorkflowExecutor) ExecuteMultiTierWorkflows(ctx context.Context, app *A
 			return fmt.Errorf("failed executing %s workflows: %w", phase, err)
 		}
 
-		e.logger.InfoWithFields("Phase completed successfully", map[string]interface{}{
+		logger.InfoWithFields("Phase completed successfully", map[string]interface{}{
 			"app_name":     app.Name,
 			"execution_id": execution.ID,
 			"phase":        string(phase),
@@ -241,13 +346,13 @@ func (e *WorkflowExecutor) ExecuteMultiTierWorkflows(ctx context.Context, app *A
 	// Mark execution as completed
 	err = e.repo.UpdateWorkflowExecution(execution.ID, database.WorkflowStatusCompleted, nil)
 	if err != nil {
-		e.logger.WarnWithFields("Failed to update workflow completion", map[string]interface{}{
+		logger.WarnWithFields("Failed to update workflow completion", map[string]interface{}{
 			"execution_id": execution.ID,
 			"error":        err.Error(),
 		})
 	}
 
-	e.logger.InfoWithFields("Multi-tier workflow execution completed successfully", map[string]interface{}{
+	logger.InfoWithFields("Multi-tier workflow execution completed successfully", map[string]interface{}{
 		"app_name":     app.Name,
 		"execution_id": execution.ID,
 	})
@@ -261,14 +366,19 @@ func (e *WorkflowExecutor) ExecuteWorkflow(appName string, workflow types.Workfl
 
 // ExecuteWorkflowWithName executes a named workflow with database persistence
 func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string, workflow types.Workflow, goldenPathParams ...map[string]string) error {
-	// Ensure logger is initialized (defensive programming)
-	if e.logger == nil {
-		e.logger = logging.NewStructuredLogger("workflow")
-	}
+	return e.ExecuteWorkflowWithContext(context.Background(), appName, workflowName, workflow, goldenPathParams...)
+}
+
+// ExecuteWorkflowWithContext executes a named workflow with database persistence. The
+// request-scoped log fields of ctx (trace ID, user, team) are carried into every step
+// executor, together with the application and the workflow execution ID.
+func (e *WorkflowExecutor) ExecuteWorkflowWithContext(ctx context.Context, appName, workflowName string, workflow types.Workflow, goldenPathParams ...map[string]string) error {
+	ctx = logging.WithApp(ctx, appName)
+	logger := logging.FromContext(ctx, "workflow")
 
 	// Create OpenTelemetry span for workflow execution
 	tracer := otel.Tracer("innominatus/workflow")
-	_, span := tracer.Start(context.Background(), "workflow.execute",
+	ctx, span := tracer.Start(ctx, "workflow.execute",
 		trace.WithAttributes(
 			attribute.String("app.name", appName),
 			attribute.String("workflow.name", workflowName),
@@ -277,10 +387,31 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 	)
 	defer span.End()
 
+	ctx = withWorkflowEnvironment(ctx, workflow.Env)
+
+	// Application variables from the Score spec come first so everything below can override them
+	e.initApplicationVariables(appName, workflowName)
+	e.initSecretScope(appName)
+	e.initExecutionTime(clock.OrReal(e.clock).Now())
+
+	// Declared inputs get their defaults; a run without a required input never starts
+	if len(workflow.Inputs) > 0 {
+		var parameters map[string]string
+		if len(goldenPathParams) > 0 {
+			parameters = goldenPathParams[0]
+		}
+		parameters, err := ApplyInputs(workflow.Inputs, parameters)
+		if err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("workflow validation failed: %w", err)
+		}
+		goldenPathParams = []map[string]string{parameters}
+	}
+
 	// Initialize golden path parameters first (if provided) - they take precedence
 	if len(goldenPathParams) > 0 && len(goldenPathParams[0]) > 0 {
 		e.execContext.SetWorkflowVariables(goldenPathParams[0])
-		e.logger.InfoWithFields("Initialized golden path parameters", map[string]interface{}{
+		logger.InfoWithFields("Initialized golden path parameters", map[string]interface{}{
 			"app_name":        appName,
 			"workflow_name":   workflowName,
 			"parameter_count": len(goldenPathParams[0]),
@@ -290,7 +421,7 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 	// Initialize workflow variables in execution context (may override golden path params if same keys exist)
 	if len(workflow.Variables) > 0 {
 		e.execContext.SetWorkflowVariables(workflow.Variables)
-		e.logger.InfoWithFields("Initialized workflow variables", map[string]interface{}{
+		logger.InfoWithFields("Initialized workflow variables", map[string]interface{}{
 			"app_name":       appName,
 			"workflow_name":  workflowName,
 			"variable_count": len(workflow.Variables),
@@ -301,7 +432,7 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 	if err := e.execContext.ValidateWorkflowVariables(workflow); err != nil {
 		if IsStrictMode() {
 			span.RecordError(err)
-			e.logger.ErrorWithFields("Workflow validation failed", map[string]interface{}{
+			logger.ErrorWithFields("Workflow validation failed", map[string]interface{}{
 				"app_name":      appName,
 				"workflow_name": workflowName,
 				"error":         err.Error(),
@@ -309,7 +440,7 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 			return fmt.Errorf("workflow validation failed: %w", err)
 		}
 		// Lenient mode: log warning but continue
-		e.logger.WarnWithFields("Workflow validation warnings (lenient mode)", map[string]interface{}{
+		logger.WarnWithFields("Workflow validation warnings (lenient mode)", map[string]interface{}{
 			"app_name":      appName,
 			"workflow_name": workflowName,
 			"warning":       err.Error(),
@@ -320,7 +451,7 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 	execution, err := e.repo.CreateWorkflowExecution(appName, workflowName, len(workflow.Steps))
 	if err != nil {
 		span.RecordError(err)
-		e.logger.ErrorWithFields("Failed to create workflow execution", map[string]interface{}{
+		logger.ErrorWithFields("Failed to create workflow execution", map[string]interface{}{
 			"app_name":      appName,
 			"workflow_name": workflowName,
 			"error":         err.Error(),
@@ -330,8 +461,59 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 
 	// Add execution ID to span
 	span.SetAttributes(attribute.Int64("workflow.execution_id", execution.ID))
+	ctx = logging.WithWorkflowID(ctx, execution.ID)
+	logger = logging.FromContext(ctx, "workflow")
+	e.initDeploymentLabels(appName, execution.ID)
+
+	// Record the parameters with the same precedence as the execution context
+	if recorder, ok := e.repo.(parameterRecorder); ok {
+		parameters := make(map[string]string)
+		if len(goldenPathParams) > 0 {
+			for k, v := range goldenPathParams[0] {
+				parameters[k] = v
+			}
+		}
+		for k, v := range workflow.Variables {
+			parameters[k] = v
+		}
+		sources, _ := ctx.Value(externalParametersKey{}).(map[string]types.ParameterSource)
+		for name := range sources {
+			delete(parameters, name)
+		}
+		if err := recorder.SetWorkflowExecutionParameters(execution.ID, parameters, sources); err != nil {
+			logger.Warnf("Failed to record workflow parameters: %v", err)
+		}
+	}
+
+	if overrides, ok := ctx.Value(stepOverridesKey{}).(*types.StepOverrides); ok && overrides != nil {
+		logger.WarnWithFields("Workflow runs with admin step overrides", map[string]interface{}{
+			"app_name":      appName,
+			"workflow_name": workflowName,
+			"execution_id":  execution.ID,
+			"requested_by":  overrides.RequestedBy,
+			"reason":        overrides.Reason,
+		})
+		if recorder, ok := e.repo.(overrideRecorder); ok {
+			if err := recorder.SetWorkflowExecutionOverrides(execution.ID, overrides); err != nil {
+				logger.Warnf("Failed to record step overrides: %v", err)
+			}
+		}
+	}
+
+	if started, ok := ctx.Value(executionStartedKey{}).(func(int64)); ok {
+		started(execution.ID)
+	}
+
+	if run, ok := ctx.Value(replayKey{}).(*replayRun); ok {
+		run.started <- execution.ID
+		if marker, ok := e.repo.(replayMarker); ok {
+			if err := marker.MarkWorkflowExecutionReplay(execution.ID, run.originalID); err != nil {
+				logger.Warnf("Failed to link replay to execution %d: %v", run.originalID, err)
+			}
+		}
+	}
 
-	e.logger.InfoWithFields("Starting workflow execution", map[string]interface{}{
+	logger.InfoWithFields("Starting workflow execution", map[string]interface{}{
 		"app_name":      appName,
 		"workflow_name": workflowName,
 		"execution_id":  execution.ID,
@@ -367,7 +549,7 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 			},
 		}
 		if err := e.graphAdapter.AddNode(appName, workflowNode); err != nil {
-			fmt.Printf("Warning: failed to add workflow node to graph: %v\n", err)
+			logger.Warnf("Failed to add workflow node to graph: %v", err)
 		} else {
 			// Create edge: spec triggers workflow
 			specNodeID := fmt.Sprintf("spec:%s", appName)
@@ -381,7 +563,7 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 				},
 			}
 			if err := e.graphAdapter.AddEdge(appName, specToWorkflowEdge); err != nil {
-				fmt.Printf("Warning: failed to add spec→workflow edge to graph: %v\n", err)
+				logger.Warnf("Failed to add spec→workflow edge to graph: %v", err)
 			}
 		}
 	}
@@ -402,6 +584,7 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 			return fmt.Errorf("failed to create workflow step: %w", err)
 		}
 		stepRecords[i] = stepRecord
+		e.stepLogs.register(stepRecord.ID, workflow.Logs)
 
 		// Create step node in graph (if graph adapter is available)
 		stepNodeID := fmt.Sprintf("step-%d", stepRecord.ID)
@@ -419,7 +602,7 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 				},
 			}
 			if err := e.graphAdapter.AddNode(appName, stepNode); err != nil {
-				fmt.Printf("Warning: failed to add step node to graph: %v\n", err)
+				logger.Warnf("Failed to add step node to graph: %v", err)
 			}
 
 			// Create edge: workflow contains step
@@ -430,17 +613,20 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 				Type:       sdk.EdgeTypeContains,
 			}
 			if err := e.graphAdapter.AddEdge(appName, edge); err != nil {
-				fmt.Printf("Warning: failed to add workflow→step edge to graph: %v\n", err)
+				logger.Warnf("Failed to add workflow→step edge to graph: %v", err)
 			}
 		}
 	}
 
+	// Progress and ETA are published on every step transition and while steps run
+	progress := e.newProgressTracker(appName, workflowName, execution, workflow.Steps)
+
 	// Execute steps
 	for i, step := range workflow.Steps {
 		stepRecord := stepRecords[i]
 		stepNodeID := stepNodeIDs[i]
 
-		e.logger.InfoWithFields("Executing workflow step", map[string]interface{}{
+		logger.InfoWithFields("Executing workflow step", map[string]interface{}{
 			"app_name":      appName,
 			"workflow_name": workflowName,
 			"execution_id":  execution.ID,
@@ -450,10 +636,20 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 			"step_type":     step.Type,
 		})
 
+		// Steps skipped by admin step overrides are recorded, but not run
+		if step.SkipReason != "" {
+			logger.Warnf("%s (%s) - SKIPPED: %s", step.Name, step.Type, step.SkipReason)
+			skippedMsg := fmt.Sprintf("skipped: %s", step.SkipReason)
+			_ = e.setStepStatus(stepRecord.ID, "skipped", &skippedMsg)
+			e.execContext.SetStepStatus(step.Name, "skipped")
+			progress.setStatus(i, "skipped")
+			continue
+		}
+
 		// Update step to running
-		err := e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusRunning, nil)
+		err := e.setStepStatus(stepRecord.ID, database.StepStatusRunning, nil)
 		if err != nil {
-			e.logger.WarnWithFields("Failed to update step status", map[string]interface{}{
+			logger.WarnWithFields("Failed to update step status", map[string]interface{}{
 				"step_id": stepRecord.ID,
 				"error":   err.Error(),
 			})
@@ -462,33 +658,35 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 		// Update step node state to running in graph
 		if e.graphAdapter != nil {
 			if err := e.graphAdapter.UpdateNodeState(appName, stepNodeID, sdk.NodeStateRunning); err != nil {
-				fmt.Printf("Warning: failed to update step state in graph: %v\n", err)
+				logger.Warnf("Failed to update step state in graph: %v", err)
 			}
 		}
 
-		spinner := NewSpinner(fmt.Sprintf("Initializing %s step...", step.Type))
-		spinner.Start()
+		progress.setStatus(i, database.StepStatusRunning)
 
 		// Use the modern stepExecutors registry instead of old runStepWithSpinner
 		executor, exists := e.stepExecutors[step.Type]
 		if !exists {
-			spinner.Stop(false, fmt.Sprintf("Unsupported step type: %s", step.Type))
 			err = fmt.Errorf("unsupported step type: %s", step.Type)
 		} else {
-			// Execute step with context, passing stepID for log persistence
-			ctx := context.Background()
-			err = executor(ctx, step, appName, execution.ID, stepRecord.ID)
-			if err != nil {
-				spinner.Stop(false, fmt.Sprintf("Step '%s' failed", step.Name))
-			} else {
-				spinner.Stop(true, fmt.Sprintf("✅ Step '%s' completed successfully", step.Name))
-			}
+			// Execute step with the workflow context, passing stepID for log persistence
+			e.trackTempAssets(step, appName, execution.ID)
+			stopProgress := progress.whileRunning(ctx)
+			err = e.runWithFaults(ctx, step, appName, func() error {
+				stepCtx, err := e.withStepEnvironment(ctx, step, appName)
+				if err != nil {
+					return err
+				}
+				return executor(stepCtx, step, appName, execution.ID, stepRecord.ID)
+			})
+			stopProgress()
 		}
 
 		if err != nil {
 			// Update step as failed
 			errorMsg := err.Error()
-			_ = e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusFailed, &errorMsg)
+			_ = e.setStepStatus(stepRecord.ID, database.StepStatusFailed, &errorMsg)
+			e.retainFailedWorkspace(step, appName, execution.ID, stepRecord.ID)
 
 			// Update workflow as failed
 			workflowErrorMsg := fmt.Sprintf("workflow failed at step '%s': %v", step.Name, err)
@@ -497,38 +695,70 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 			// Update any linked resources to failed state
 			e.updateLinkedResourcesOnFailure(execution.ID, appName, workflowErrorMsg)
 
+			// Roll back, or offer to roll back, what the run created before the failure
+			e.compensateFailure(ctx, execution.ID, workflow.OnFailure)
+
+			// Publish workflow failed event
+			if e.eventBus != nil {
+				e.eventBus.Publish(events.NewEvent(
+					events.EventTypeWorkflowFailed,
+					appName,
+					"workflow-executor",
+					map[string]interface{}{
+						"workflow_name": workflowName,
+						"execution_id":  execution.ID,
+						"step_name":     step.Name,
+						"error":         err.Error(),
+						"environment":   e.deploymentEnvironment(ctx),
+					},
+				))
+			}
+
 			// Update step node state to failed in graph (triggers automatic propagation to workflow)
 			if e.graphAdapter != nil {
 				if err := e.graphAdapter.UpdateNodeState(appName, stepNodeID, sdk.NodeStateFailed); err != nil {
-					fmt.Printf("Warning: failed to update step state in graph: %v\n", err)
+					logger.Warnf("Failed to update step state in graph: %v", err)
 				}
 			}
 
-			spinner.Stop(false, fmt.Sprintf("Step '%s' failed: %v", step.Name, err))
+			logger.ErrorWithFields("Workflow step failed", map[string]interface{}{
+				"step_name": step.Name,
+				"step_type": step.Type,
+				"error":     err.Error(),
+			})
 			return fmt.Errorf("workflow failed at step '%s': %w", step.Name, err)
 		}
 
 		// Update step as completed
-		err = e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusCompleted, nil)
+		err = e.setStepStatus(stepRecord.ID, database.StepStatusCompleted, nil)
 		if err != nil {
-			fmt.Printf("Warning: failed to update step completion: %v\n", err)
+			logger.Warnf("Failed to update step completion: %v", err)
 		}
+		progress.setStatus(i, database.StepStatusCompleted)
 
 		// Update step node state to succeeded in graph
 		if e.graphAdapter != nil {
 			if err := e.graphAdapter.UpdateNodeState(appName, stepNodeID, sdk.NodeStateSucceeded); err != nil {
-				fmt.Printf("Warning: failed to update step state in graph: %v\n", err)
+				logger.Warnf("Failed to update step state in graph: %v", err)
 			}
 		}
 
-		spinner.Stop(true, fmt.Sprintf("Step '%s' completed successfully", step.Name))
-		fmt.Println()
+		logger.InfoWithFields("Workflow step completed", map[string]interface{}{
+			"step_name": step.Name,
+			"step_type": step.Type,
+		})
+
+		// Record the SBOM of build and scan steps for license review
+		e.recordDependencyReport(step, appName, execution.ID)
 	}
 
+	// Record declared outputs before the execution is reported as completed
+	e.recordOutputs(execution.ID, workflow, logger.Warnf)
+
 	// Update workflow as completed
 	err = e.repo.UpdateWorkflowExecution(execution.ID, database.WorkflowStatusCompleted, nil)
 	if err != nil {
-		fmt.Printf("Warning: failed to update workflow completion: %v\n", err)
+		logger.Warnf("Failed to update workflow completion: %v", err)
 	}
 
 	// Publish workflow completed event
@@ -541,6 +771,7 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 				"workflow_name": workflowName,
 				"execution_id":  execution.ID,
 				"total_steps":   len(workflow.Steps),
+				"environment":   e.deploymentEnvironment(ctx),
 			},
 		))
 	}
@@ -548,14 +779,14 @@ func (e *WorkflowExecutor) ExecuteWorkflowWithName(appName, workflowName string,
 	// Update workflow node state to succeeded in graph
 	if e.graphAdapter != nil {
 		if err := e.graphAdapter.UpdateNodeState(appName, workflowNodeID, sdk.NodeStateSucceeded); err != nil {
-			fmt.Printf("Warning: failed to update workflow state in graph: %v\n", err)
+			logger.Warnf("Failed to update workflow state in graph: %v", err)
 		}
 	}
 
 	// Update any linked resources to active state
 	e.updateLinkedResourcesOnCompletion(execution.ID, appName)
 
-	fmt.Println("🎉 Workflow completed successfully!")
+	logger.Info("Workflow completed successfully!")
 	return nil
 }
 
@@ -738,6 +969,36 @@ func (e *WorkflowExecutor) ListWorkflowExecutions(appName, workflowName, status
 	return e.repo.ListWorkflowExecutions(appName, workflowName, status, limit, offset)
 }
 
+// StartReplay re-executes a past run in the background as a new execution linked to the
+// original, using the pinned workflow definition and parameters of that run. It returns
+// once the new execution is recorded, with its ID and a channel receiving the result.
+func (e *WorkflowExecutor) StartReplay(ctx context.Context, originalID int64, appName, workflowName string, workflow types.Workflow, parameters map[string]string) (int64, <-chan error, error) {
+	run := &replayRun{originalID: originalID, started: make(chan int64, 1)}
+	done := make(chan error, 1)
+	go func() {
+		done <- e.ExecuteWorkflowWithContext(context.WithValue(ctx, replayKey{}, run), appName, workflowName, workflow, parameters)
+	}()
+
+	select {
+	case executionID := <-run.started:
+		return executionID, done, nil
+	case err := <-done:
+		// Finished before we saw the ID: either it failed before recording the execution,
+		// or the whole replay completed already
+		select {
+		case executionID := <-run.started:
+			result := make(chan error, 1)
+			result <- err
+			return executionID, result, nil
+		default:
+			if err == nil {
+				err = fmt.Errorf("replay did not record an execution")
+			}
+			return 0, nil, err
+		}
+	}
+}
+
 // RetryWorkflowFromFailedStep retries a failed workflow execution from the first failed step
 func (e *WorkflowExecutor) RetryWorkflowFromFailedStep(appName, workflowName string, workflow types.Workflow, parentExecutionID int64) error {
 	// Ensure logger is initialized
@@ -771,14 +1032,17 @@ func (e *WorkflowExecutor) RetryWorkflowFromFailedStep(appName, workflowName str
 	})
 
 	// Execute workflow starting from the failed step
-	return e.executeWorkflowFromStep(appName, workflowName, workflow, execution, failedStepNumber)
+	ctx := logging.WithWorkflowID(logging.WithApp(context.Background(), appName), execution.ID)
+	return e.executeWorkflowFromStep(ctx, appName, workflowName, workflow, execution, failedStepNumber)
 }
 
 // executeWorkflowFromStep executes a workflow starting from a specific step number
-func (e *WorkflowExecutor) executeWorkflowFromStep(appName, workflowName string, workflow types.Workflow, execution *database.WorkflowExecution, startFromStep int) error {
+func (e *WorkflowExecutor) executeWorkflowFromStep(ctx context.Context, appName, workflowName string, workflow types.Workflow, execution *database.WorkflowExecution, startFromStep int) error {
+	logger := logging.FromContext(ctx, "workflow")
+
 	// Create OpenTelemetry span
 	tracer := otel.Tracer("innominatus/workflow")
-	_, span := tracer.Start(context.Background(), "workflow.retry",
+	_, span := tracer.Start(ctx, "workflow.retry",
 		trace.WithAttributes(
 			attribute.String("app.name", appName),
 			attribute.String("workflow.name", workflowName),
@@ -788,10 +1052,16 @@ func (e *WorkflowExecutor) executeWorkflowFromStep(appName, workflowName string,
 	)
 	defer span.End()
 
-	// Initialize workflow variables
+	ctx = withWorkflowEnvironment(ctx, workflow.Env)
+
+	// Initialize application and workflow variables, and the time snapshot of this execution
+	e.initApplicationVariables(appName, workflowName)
+	e.initSecretScope(appName)
+	e.initExecutionTime(execution.StartedAt)
 	if len(workflow.Variables) > 0 {
 		e.execContext.SetWorkflowVariables(workflow.Variables)
 	}
+	e.initDeploymentLabels(appName, execution.ID)
 
 	// Create workflow node in graph
 	workflowNodeID := fmt.Sprintf("workflow-%d", execution.ID)
@@ -811,7 +1081,7 @@ func (e *WorkflowExecutor) executeWorkflowFromStep(appName, workflowName string,
 			},
 		}
 		if err := e.graphAdapter.AddNode(appName, workflowNode); err != nil {
-			fmt.Printf("Warning: failed to add workflow node to graph: %v\n", err)
+			logger.Warnf("Failed to add workflow node to graph: %v", err)
 		}
 	}
 
@@ -833,8 +1103,9 @@ func (e *WorkflowExecutor) executeWorkflowFromStep(appName, workflowName string,
 			return fmt.Errorf("failed to create workflow step: %w", err)
 		}
 		stepRecords[stepNumber] = stepRecord
+		e.stepLogs.register(stepRecord.ID, workflow.Logs)
 
-		e.logger.InfoWithFields("Executing step (retry)", map[string]interface{}{
+		logger.InfoWithFields("Executing step (retry)", map[string]interface{}{
 			"step_number": stepNumber,
 			"step_name":   step.Name,
 			"step_type":   step.Type,
@@ -855,7 +1126,7 @@ func (e *WorkflowExecutor) executeWorkflowFromStep(appName, workflowName string,
 				},
 			}
 			if err := e.graphAdapter.AddNode(appName, stepNode); err != nil {
-				fmt.Printf("Warning: failed to add step node to graph: %v\n", err)
+				logger.Warnf("Failed to add step node to graph: %v", err)
 			}
 
 			// Create edge from workflow to step
@@ -866,51 +1137,54 @@ func (e *WorkflowExecutor) executeWorkflowFromStep(appName, workflowName string,
 				Type:       sdk.EdgeTypeContains,
 			}
 			if err := e.graphAdapter.AddEdge(appName, edge); err != nil {
-				fmt.Printf("Warning: failed to add workflow-step edge to graph: %v\n", err)
+				logger.Warnf("Failed to add workflow-step edge to graph: %v", err)
 			}
 		}
 
 		// Update step to running
-		if err := e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusRunning, nil); err != nil {
-			fmt.Printf("Warning: failed to update step status: %v\n", err)
+		if err := e.setStepStatus(stepRecord.ID, database.StepStatusRunning, nil); err != nil {
+			logger.Warnf("Failed to update step status: %v", err)
 		}
 
 		if e.graphAdapter != nil {
 			if err := e.graphAdapter.UpdateNodeState(appName, stepNodeID, sdk.NodeStateRunning); err != nil {
-				fmt.Printf("Warning: failed to update step state in graph: %v\n", err)
+				logger.Warnf("Failed to update step state in graph: %v", err)
 			}
 		}
 
 		// Execute the step with spinner
-		spinner := NewSpinner(fmt.Sprintf("Executing step '%s' (%s)...", step.Name, step.Type))
+		spinner := NewSpinner(fmt.Sprintf("Executing step '%s' (%s)...", step.Name, step.Type)).WithLogger(logger)
 		spinner.Start()
 
 		// Store spinner reference for step execution
-		stepErr := runStepWithSpinner(step, appName, "default", spinner)
+		stepCtx, stepErr := e.withStepEnvironment(ctx, step, appName)
+		if stepErr == nil {
+			stepErr = runStepWithSpinner(stepCtx, step, appName, "default", spinner)
+		}
 
 		if stepErr != nil {
 			spinner.Stop(false, fmt.Sprintf("Step '%s' failed", step.Name))
 
 			errMsg := stepErr.Error()
-			if updateErr := e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusFailed, &errMsg); updateErr != nil {
-				fmt.Printf("Warning: failed to update step status: %v\n", updateErr)
+			if updateErr := e.setStepStatus(stepRecord.ID, database.StepStatusFailed, &errMsg); updateErr != nil {
+				logger.Warnf("Failed to update step status: %v", updateErr)
 			}
 
 			if e.graphAdapter != nil {
 				if updateErr := e.graphAdapter.UpdateNodeState(appName, stepNodeID, sdk.NodeStateFailed); updateErr != nil {
-					fmt.Printf("Warning: failed to update step state in graph: %v\n", updateErr)
+					logger.Warnf("Failed to update step state in graph: %v", updateErr)
 				}
 			}
 
 			// Update workflow as failed
 			workflowErr := e.repo.UpdateWorkflowExecution(execution.ID, database.WorkflowStatusFailed, &errMsg)
 			if workflowErr != nil {
-				fmt.Printf("Warning: failed to update workflow status: %v\n", workflowErr)
+				logger.Warnf("Failed to update workflow status: %v", workflowErr)
 			}
 
 			if e.graphAdapter != nil {
 				if updateErr := e.graphAdapter.UpdateNodeState(appName, workflowNodeID, sdk.NodeStateFailed); updateErr != nil {
-					fmt.Printf("Warning: failed to update workflow state in graph: %v\n", updateErr)
+					logger.Warnf("Failed to update workflow state in graph: %v", updateErr)
 				}
 			}
 
@@ -918,32 +1192,31 @@ func (e *WorkflowExecutor) executeWorkflowFromStep(appName, workflowName string,
 		}
 
 		// Update step as completed
-		if err := e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusCompleted, nil); err != nil {
-			fmt.Printf("Warning: failed to update step status: %v\n", err)
+		if err := e.setStepStatus(stepRecord.ID, database.StepStatusCompleted, nil); err != nil {
+			logger.Warnf("Failed to update step status: %v", err)
 		}
 
 		if e.graphAdapter != nil {
 			if err := e.graphAdapter.UpdateNodeState(appName, stepNodeID, sdk.NodeStateSucceeded); err != nil {
-				fmt.Printf("Warning: failed to update step state in graph: %v\n", err)
+				logger.Warnf("Failed to update step state in graph: %v", err)
 			}
 		}
 
 		spinner.Stop(true, fmt.Sprintf("Step '%s' completed successfully", step.Name))
-		fmt.Println()
 	}
 
 	// Update workflow as completed
 	if err := e.repo.UpdateWorkflowExecution(execution.ID, database.WorkflowStatusCompleted, nil); err != nil {
-		fmt.Printf("Warning: failed to update workflow completion: %v\n", err)
+		logger.Warnf("Failed to update workflow completion: %v", err)
 	}
 
 	if e.graphAdapter != nil {
 		if err := e.graphAdapter.UpdateNodeState(appName, workflowNodeID, sdk.NodeStateSucceeded); err != nil {
-			fmt.Printf("Warning: failed to update workflow state in graph: %v\n", err)
+			logger.Warnf("Failed to update workflow state in graph: %v", err)
 		}
 	}
 
-	fmt.Println("🎉 Workflow retry completed successfully!")
+	logger.Info("Workflow retry completed successfully!")
 	return nil
 }
 
@@ -974,6 +1247,8 @@ func (e *WorkflowExecutor) createMultiTierExecution(appName string, workflows ma
 
 // executePhaseWorkflows executes all workflows for a specific phase
 func (e *WorkflowExecutor) executePhaseWorkflows(ctx context.Context, appName string, phase WorkflowPhase, workflows []ResolvedWorkflow, execID int64) error {
+	logger := logging.FromContext(ctx, "workflow")
+
 	// Create a semaphore to limit concurrent workflow execution
 	semaphore := make(chan struct{}, e.maxConcurrent)
 	var wg sync.WaitGroup
@@ -989,7 +1264,7 @@ func (e *WorkflowExecutor) executePhaseWorkflows(ctx context.Context, appName st
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			fmt.Printf("  📋 Executing workflow: %s (%d steps)\n", w.Name, len(w.Steps))
+			logger.Infof("Executing workflow: %s (%d steps)", w.Name, len(w.Steps))
 
 			if err := e.executeResolvedWorkflow(ctx, appName, w, execID); err != nil {
 				errorMu.Lock()
@@ -1000,7 +1275,7 @@ func (e *WorkflowExecutor) executePhaseWorkflows(ctx context.Context, appName st
 				return
 			}
 
-			fmt.Printf("  ✅ Workflow %s completed successfully\n", w.Name)
+			logger.Infof("Workflow %s completed successfully", w.Name)
 		}(workflow)
 	}
 
@@ -1010,6 +1285,8 @@ func (e *WorkflowExecutor) executePhaseWorkflows(ctx context.Context, appName st
 
 // executeResolvedWorkflow executes a single resolved workflow with support for parallel steps
 func (e *WorkflowExecutor) executeResolvedWorkflow(ctx context.Context, appName string, workflow ResolvedWorkflow, execID int64) error {
+	logger := logging.FromContext(ctx, "workflow")
+
 	// Check if any steps are marked for parallel execution
 	hasParallelSteps := false
 	for _, step := range workflow.Steps {
@@ -1029,7 +1306,7 @@ func (e *WorkflowExecutor) executeResolvedWorkflow(ctx context.Context, appName
 
 	// Execute step groups in order, steps within a group run in parallel
 	for groupIdx, group := range stepGroups {
-		fmt.Printf("    📦 Executing step group %d/%d (%d steps)\n", groupIdx+1, len(stepGroups), len(group))
+		logger.Infof("Executing step group %d/%d (%d steps)", groupIdx+1, len(stepGroups), len(group))
 
 		if err := e.executeStepGroupParallel(ctx, appName, group, execID); err != nil {
 			return fmt.Errorf("step group %d failed: %w", groupIdx+1, err)
@@ -1041,8 +1318,10 @@ func (e *WorkflowExecutor) executeResolvedWorkflow(ctx context.Context, appName
 
 // executeStepsSequentially executes steps one by one (original behavior)
 func (e *WorkflowExecutor) executeStepsSequentially(ctx context.Context, appName string, steps []types.Step, execID int64) error {
+	logger := logging.FromContext(ctx, "workflow")
+
 	for i, step := range steps {
-		fmt.Printf("    🔄 Step %d/%d: %s (%s)\n", i+1, len(steps), step.Name, step.Type)
+		logger.Infof("Step %d/%d: %s (%s)", i+1, len(steps), step.Name, step.Type)
 
 		// Create step execution record
 		stepConfig, err := stepToConfig(step)
@@ -1056,9 +1335,9 @@ func (e *WorkflowExecutor) executeStepsSequentially(ctx context.Context, appName
 		}
 
 		// Update step to running
-		err = e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusRunning, nil)
+		err = e.setStepStatus(stepRecord.ID, database.StepStatusRunning, nil)
 		if err != nil {
-			fmt.Printf("Warning: failed to update step status: %v\n", err)
+			logger.Warnf("Failed to update step status: %v", err)
 		}
 
 		// Execute the step
@@ -1066,18 +1345,18 @@ func (e *WorkflowExecutor) executeStepsSequentially(ctx context.Context, appName
 		if err := e.executeStepWithExecutor(ctx, step, appName, execID, stepRecord.ID); err != nil {
 			// Mark step as failed
 			errorMsg := err.Error()
-			_ = e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusFailed, &errorMsg)
+			_ = e.setStepStatus(stepRecord.ID, database.StepStatusFailed, &errorMsg)
 			return fmt.Errorf("step %s failed: %w", step.Name, err)
 		}
 
 		// Mark step as completed
-		err = e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusCompleted, nil)
+		err = e.setStepStatus(stepRecord.ID, database.StepStatusCompleted, nil)
 		if err != nil {
-			fmt.Printf("Warning: failed to update step completion: %v\n", err)
+			logger.Warnf("Failed to update step completion: %v", err)
 		}
 
 		duration := time.Since(stepStartTime)
-		fmt.Printf("    ✅ Step %s completed (took %v)\n", step.Name, duration.Round(time.Millisecond))
+		logger.Infof("Step %s completed (took %v)", step.Name, duration.Round(time.Millisecond))
 	}
 
 	return nil
@@ -1179,6 +1458,8 @@ func (e *WorkflowExecutor) executeStepGroupParallel(ctx context.Context, appName
 
 // executeSingleStep executes a single step with full database tracking
 func (e *WorkflowExecutor) executeSingleStep(ctx context.Context, appName string, step types.Step, execID int64, stepNumber int) error {
+	logger := logging.FromContext(ctx, "workflow")
+
 	// Check dependencies before executing
 	if len(step.DependsOn) > 0 {
 		for _, depStepName := range step.DependsOn {
@@ -1190,7 +1471,7 @@ func (e *WorkflowExecutor) executeSingleStep(ctx context.Context, appName string
 				return fmt.Errorf("dependency %s did not complete successfully (status: %s) for step %s", depStepName, depStatus, step.Name)
 			}
 		}
-		fmt.Printf("      ✓ All dependencies satisfied for %s\n", step.Name)
+		logger.Infof("All dependencies satisfied for %s", step.Name)
 	}
 
 	// Per-step validation: Check all variable references in step configuration
@@ -1215,7 +1496,7 @@ func (e *WorkflowExecutor) executeSingleStep(ctx context.Context, appName string
 	// Check if step should be executed based on conditions
 	shouldExecute, skipReason := e.execContext.ShouldExecuteStep(step)
 	if !shouldExecute {
-		fmt.Printf("      ⏭️  %s (%s) - SKIPPED: %s\n", step.Name, step.Type, skipReason)
+		logger.Infof("%s (%s) - SKIPPED: %s", step.Name, step.Type, skipReason)
 
 		// Create step execution record as skipped
 		stepConfig, err := stepToConfig(step)
@@ -1232,7 +1513,7 @@ func (e *WorkflowExecutor) executeSingleStep(ctx context.Context, appName string
 
 		// Mark step as skipped
 		skippedMsg := fmt.Sprintf("skipped: %s", skipReason)
-		_ = e.repo.UpdateWorkflowStepStatus(stepRecord.ID, "skipped", &skippedMsg)
+		_ = e.setStepStatus(stepRecord.ID, "skipped", &skippedMsg)
 
 		// Record in execution context
 		e.execContext.SetStepStatus(step.Name, "skipped")
@@ -1240,7 +1521,7 @@ func (e *WorkflowExecutor) executeSingleStep(ctx context.Context, appName string
 		return nil
 	}
 
-	fmt.Printf("      🔄 %s (%s)\n", step.Name, step.Type)
+	logger.Infof("%s (%s)", step.Name, step.Type)
 
 	// Create step execution record
 	stepConfig, err := stepToConfig(step)
@@ -1254,9 +1535,9 @@ func (e *WorkflowExecutor) executeSingleStep(ctx context.Context, appName string
 	}
 
 	// Update step to running
-	err = e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusRunning, nil)
+	err = e.setStepStatus(stepRecord.ID, database.StepStatusRunning, nil)
 	if err != nil {
-		fmt.Printf("      ⚠️  Warning: failed to update step status: %v\n", err)
+		logger.Warnf("Failed to update step status: %v", err)
 	}
 
 	// Execute the step
@@ -1264,7 +1545,7 @@ func (e *WorkflowExecutor) executeSingleStep(ctx context.Context, appName string
 	if err := e.executeStepWithExecutor(ctx, step, appName, execID, stepRecord.ID); err != nil {
 		// Mark step as failed
 		errorMsg := err.Error()
-		_ = e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusFailed, &errorMsg)
+		_ = e.setStepStatus(stepRecord.ID, database.StepStatusFailed, &errorMsg)
 
 		// Record failure in execution context
 		e.execContext.SetStepStatus(step.Name, "failed")
@@ -1273,16 +1554,17 @@ func (e *WorkflowExecutor) executeSingleStep(ctx context.Context, appName string
 	}
 
 	// Mark step as completed
-	err = e.repo.UpdateWorkflowStepStatus(stepRecord.ID, database.StepStatusCompleted, nil)
+	err = e.setStepStatus(stepRecord.ID, database.StepStatusCompleted, nil)
 	if err != nil {
-		fmt.Printf("      ⚠️  Warning: failed to update step completion: %v\n", err)
+		logger.Warnf("Failed to update step completion: %v", err)
 	}
 
 	duration := time.Since(stepStartTime)
-	fmt.Printf("      ✅ %s completed (took %v)\n", step.Name, duration.Round(time.Millisecond))
+	logger.Infof("%s completed (took %v)", step.Name, duration.Round(time.Millisecond))
 
 	// Capture step outputs
 	e.captureStepOutputs(step)
+	e.recordDependencyReport(step, appName, execID)
 
 	// Record success in execution context
 	e.execContext.SetStepStatus(step.Name, "success")
@@ -1300,20 +1582,20 @@ func (e *WorkflowExecutor) captureStepOutputs(step types.Step) {
 			e.execContext.SetVariable(k, v)
 			outputs[k] = v
 		}
-		fmt.Printf("      📤 Set %d workflow variables\n", len(step.SetVariables))
+		e.logger.Infof("Set %d workflow variables", len(step.SetVariables))
 	}
 
 	// Read output file if specified
 	if step.OutputFile != "" {
 		fileOutputs, err := e.outputParser.ParseOutputFile(step.OutputFile)
 		if err != nil {
-			fmt.Printf("      ⚠️  Warning: failed to parse output file %s: %v\n", step.OutputFile, err)
+			e.logger.Warnf("Failed to parse output file %s: %v", step.OutputFile, err)
 		} else {
 			for k, v := range fileOutputs {
 				outputs[k] = v
 			}
 			if len(fileOutputs) > 0 {
-				fmt.Printf("      📄 Captured %d outputs from file: %s\n", len(fileOutputs), step.OutputFile)
+				e.logger.Infof("Captured %d outputs from file: %s", len(fileOutputs), step.OutputFile)
 			}
 		}
 	}
@@ -1329,26 +1611,44 @@ func (e *WorkflowExecutor) captureStepOutputs(step types.Step) {
 			if len(displayValue) > 50 {
 				displayValue = displayValue[:47] + "..."
 			}
-			fmt.Printf("      💾 %s = %s\n", k, displayValue)
+			e.logger.Infof("%s = %s", k, displayValue)
 		}
 	}
 }
 
 // executeStepWithExecutor executes a step using registered executors
 func (e *WorkflowExecutor) executeStepWithExecutor(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+	e.trackTempAssets(step, appName, execID)
+	err := e.runWithFaults(ctx, step, appName, func() error {
+		if step.Cache != nil && e.stepCache != nil {
+			return e.executeCachedStep(ctx, step, appName, execID, stepID)
+		}
+		return e.runStepExecutor(ctx, step, appName, execID, stepID)
+	})
+	if err != nil {
+		e.retainFailedWorkspace(step, appName, execID, stepID)
+	}
+	return err
+}
+
+// runStepExecutor runs the registered executor for the step type
+func (e *WorkflowExecutor) runStepExecutor(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
 	e.mu.RLock()
 	executor, exists := e.stepExecutors[step.Type]
 	e.mu.RUnlock()
 
-	if !exists {
-		// Fallback to existing step execution logic
-		return runStepWithSpinner(step, appName, "default", nil)
-	}
-
 	// Create a timeout context for the step
 	stepCtx, cancel := context.WithTimeout(ctx, e.executionTimeout)
 	defer cancel()
 
+	stepCtx, err := e.withStepEnvironment(stepCtx, step, appName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		// Fallback to existing step execution logic
+		return runStepWithSpinner(stepCtx, step, appName, "default", nil)
+	}
 	return executor(stepCtx, step, appName, execID, stepID)
 }
 
@@ -1356,14 +1656,16 @@ func (e *WorkflowExecutor) executeStepWithExecutor(ctx context.Context, step typ
 func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 	// Resource provisioning executor
 	e.stepExecutors["resource-provisioning"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logger := logging.FromContext(ctx, "workflow")
+
 		if e.resourceManager == nil {
 			// Fallback to simulation if no resource manager
 			time.Sleep(2 * time.Second)
-			fmt.Printf("      🔧 Simulated resource provisioning for step: %s\n", step.Name)
+			logger.Infof("Simulated resource provisioning for step: %s", step.Name)
 			return nil
 		}
 
-		fmt.Printf("      🔧 Provisioning resources for application: %s\n", appName)
+		logger.Infof("Provisioning resources for application: %s", appName)
 
 		// Get all resources for the application
 		resources, err := e.resourceManager.GetResourcesByApplication(appName)
@@ -1372,7 +1674,7 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 		}
 
 		if len(resources) == 0 {
-			fmt.Printf("      ℹ️  No resources found for application: %s\n", appName)
+			logger.Infof("No resources found for application: %s", appName)
 			return nil
 		}
 
@@ -1384,25 +1686,26 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 					resource.ID,
 					"workflow-provisioner",
 					map[string]interface{}{
-						"provisioned_via": "workflow_step",
-						"step_name":       step.Name,
-						"execution_id":    execID,
+						"provisioned_via":  "workflow_step",
+						"step_name":        step.Name,
+						"execution_id":     execID,
+						"resource_outputs": e.execContext.AllResourceOutputs(), // For placeholders in container files
 					},
 					"workflow-executor",
 				)
 				if err != nil {
-					fmt.Printf("      ❌ Failed to provision resource %s (ID: %d): %v\n", resource.ResourceName, resource.ID, err)
+					logger.Errorf("Failed to provision resource %s (ID: %d): %v", resource.ResourceName, resource.ID, err)
 					return fmt.Errorf("failed to provision resource %s: %w", resource.ResourceName, err)
 				}
-				fmt.Printf("      ✅ Provisioned resource: %s (%s)\n", resource.ResourceName, resource.ResourceType)
+				logger.Infof("Provisioned resource: %s (%s)", resource.ResourceName, resource.ResourceType)
 				provisionedCount++
 			}
 		}
 
 		if provisionedCount > 0 {
-			fmt.Printf("      🎉 Successfully provisioned %d resources for %s\n", provisionedCount, appName)
+			logger.Infof("Successfully provisioned %d resources for %s", provisionedCount, appName)
 		} else {
-			fmt.Printf("      ℹ️  All resources already provisioned for %s\n", appName)
+			logger.Infof("All resources already provisioned for %s", appName)
 		}
 
 		return nil
@@ -1410,14 +1713,27 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 
 	// Security scanning executor
 	e.stepExecutors["security"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logger := logging.FromContext(ctx, "workflow")
+
 		time.Sleep(4 * time.Second)
-		fmt.Printf("      🔒 Security scan completed\n")
+		logger.Info("Security scan completed")
 		return nil
 	}
 
 	// Policy validation executor
 	e.stepExecutors["policy"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
-		fmt.Printf("      📋 Executing policy script: %s\n", step.Name)
+		logger := logging.FromContext(ctx, "workflow")
+
+		// Without a script, the step evaluates rego policies
+		if _, hasScript := step.Config["script"]; !hasScript {
+			logs, err := e.policyStep(ctx, step, appName)
+			if logErr := e.addStepLogs(stepID, logs); logErr != nil {
+				logger.Warnf("Failed to store step logs: %v", logErr)
+			}
+			return err
+		}
+
+		logger.Infof("Executing policy script: %s", step.Name)
 
 		// Get script from config
 		script, ok := step.Config["script"].(string)
@@ -1438,7 +1754,7 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 			"parameters": workflowVars,
 		}
 
-		fmt.Printf("      🔍 DEBUG: Template parameters for policy script: %v\n", workflowVars)
+		logger.Debugf("Template parameters for policy script: %v", workflowVars)
 
 		// Render script template with parameters
 		renderedScript, err := e.renderTemplate(script, templateData)
@@ -1446,7 +1762,7 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 			return fmt.Errorf("failed to render policy script template: %w", err)
 		}
 
-		fmt.Printf("      📝 DEBUG: Rendered script (first 300 chars):\n%s\n", func() string {
+		logger.Debugf("Rendered script (first 300 chars):\n%s", func() string {
 			if len(renderedScript) > 300 {
 				return renderedScript[:300] + "..."
 			}
@@ -1474,78 +1790,115 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 
 		// Execute script and capture output
 		// #nosec G204 -- tmpFile.Name() is a controlled temporary file path
-		cmd := exec.Command("/bin/bash", tmpFile.Name())
+		cmd := e.stepCommand(ctx, "/bin/bash", tmpFile.Name())
 
 		// Capture output for log persistence
 		var outputBuf strings.Builder
 		cmd.Stdout = io.MultiWriter(os.Stdout, &outputBuf)
 		cmd.Stderr = io.MultiWriter(os.Stderr, &outputBuf)
 
-		// Set environment variables
-		cmd.Env = append(os.Environ(),
-			fmt.Sprintf("APP_NAME=%s", appName),
-		)
-
 		if err := cmd.Run(); err != nil {
 			// Store logs even on failure
-			_ = e.repo.AddWorkflowStepLogs(stepID, outputBuf.String())
+			_ = e.addStepLogs(stepID, outputBuf.String())
 			return fmt.Errorf("policy script failed: %w", err)
 		}
 
 		// Store captured logs in database
-		if err := e.repo.AddWorkflowStepLogs(stepID, outputBuf.String()); err != nil {
-			fmt.Printf("      ⚠️  Warning: failed to store step logs: %v\n", err)
+		if err := e.addStepLogs(stepID, outputBuf.String()); err != nil {
+			logger.Warnf("Failed to store step logs: %v", err)
 		}
 
-		fmt.Printf("      ✅ Policy script completed successfully\n")
+		logger.Info("Policy script completed successfully")
 		return nil
 	}
 
 	// Cost analysis executor
 	e.stepExecutors["cost-analysis"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logger := logging.FromContext(ctx, "workflow")
+
 		time.Sleep(2 * time.Second)
-		fmt.Printf("      💰 Cost analysis completed\n")
+		logger.Info("Cost analysis completed")
 		return nil
 	}
 
 	// Tagging executor
 	e.stepExecutors["tagging"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logger := logging.FromContext(ctx, "workflow")
+
 		time.Sleep(1 * time.Second)
-		fmt.Printf("      🏷️  Resource tagging completed\n")
+		logger.Info("Resource tagging completed")
 		return nil
 	}
 
 	// Database migration executor
 	e.stepExecutors["database-migration"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logger := logging.FromContext(ctx, "workflow")
+
 		time.Sleep(3 * time.Second)
-		fmt.Printf("      🗃️  Database migration completed\n")
+		logger.Info("Database migration completed")
 		return nil
 	}
 
 	// Vault setup executor
 	e.stepExecutors["vault-setup"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logger := logging.FromContext(ctx, "workflow")
+
 		time.Sleep(2 * time.Second)
-		fmt.Printf("      🔐 Vault configuration completed\n")
+		logger.Info("Vault configuration completed")
 		return nil
 	}
 
 	// Monitoring setup executor
 	e.stepExecutors["monitoring"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logger := logging.FromContext(ctx, "workflow")
+
 		time.Sleep(2 * time.Second)
-		fmt.Printf("      📊 Monitoring setup completed\n")
+		logger.Info("Monitoring setup completed")
 		return nil
 	}
 
 	// Validation executor
 	e.stepExecutors["validation"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logger := logging.FromContext(ctx, "workflow")
+
 		time.Sleep(1 * time.Second)
-		fmt.Printf("      ✅ Validation completed\n")
+		logger.Info("Validation completed")
 		return nil
 	}
 
+	// Synthetic executor - sleeps for config.duration (default 100ms); used by load tests
+	e.stepExecutors["synthetic"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		duration := 100 * time.Millisecond
+		if value, ok := step.Config["duration"].(string); ok && value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("synthetic step has invalid duration '%s': %w", value, err)
+			}
+			duration = parsed
+		}
+
+		select {
+		case <-time.After(duration):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	// Approval gate executor - pauses the workflow until a reviewer decides
+	e.stepExecutors["approval"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		summary := ""
+		if step.Config != nil {
+			summary, _ = step.Config["summary"].(string)
+		}
+		return e.waitForApproval(ctx, step, appName, execID, stepID, summary, "", "")
+	}
+
 	// Terraform executor
 	e.stepExecutors["terraform"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
-		fmt.Printf("      🏗️  Executing Terraform step: %s\n", step.Name)
+		logger := logging.FromContext(ctx, "workflow")
+
+		logger.Infof("Executing Terraform step: %s", step.Name)
 
 		// Get operation (default: apply)
 		operation := step.Operation
@@ -1598,7 +1951,7 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 		}
 
 		// Copy terraform files to workspace
-		fmt.Printf("      📁 Preparing Terraform workspace: %s\n", workspaceDir)
+		logger.Infof("Preparing Terraform workspace: %s", workspaceDir)
 		if err := e.copyTerraformFiles(workingDir, workspaceDir); err != nil {
 			return fmt.Errorf("failed to copy terraform files: %w", err)
 		}
@@ -1608,14 +1961,70 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 		case "init":
 			return e.terraformInit(ctx, workspaceDir)
 		case "plan":
-			return e.terraformPlan(ctx, workspaceDir, variables)
+			if err := e.terraformInit(ctx, workspaceDir); err != nil {
+				return err
+			}
+			planFiles := planFilesFor(execID)
+			summary, err := e.terraformPlan(ctx, workspaceDir, planFiles, variables)
+			if err != nil {
+				return err
+			}
+			if stepID > 0 {
+				_ = e.addStepLogs(stepID, summary.String())
+			}
+			if err := e.checkTerraformPolicies(ctx, step, filepath.Join(workspaceDir, planFiles.JSON), stepID); err != nil {
+				// A rejected plan must never be applied
+				planFiles.remove(workspaceDir)
+				return err
+			}
+			e.execContext.SetResourceOutput(step.Name, "plan_file", filepath.Join(workspaceDir, planFiles.Plan))
+			e.execContext.SetResourceOutput(step.Name, "plan_summary", filepath.Join(workspaceDir, planFiles.Summary))
+			return nil
 		case "apply":
 			if err := e.terraformInit(ctx, workspaceDir); err != nil {
 				return err
 			}
-			if err := e.terraformApply(ctx, workspaceDir, variables); err != nil {
+			// Only a plan saved by a plan step of this execution is applied
+			planFiles := planFilesFor(execID)
+			planPath := filepath.Join(workspaceDir, planFiles.Plan)
+			_, statErr := os.Stat(planPath)
+			planFile := ""
+			if statErr == nil {
+				planFile = planFiles.Plan
+			}
+			// Protected environments require a reviewer to approve the saved plan first
+			if stepRequiresApproval(step) {
+				if planFile == "" {
+					if _, err := e.terraformPlan(ctx, workspaceDir, planFiles, variables); err != nil {
+						return err
+					}
+					if err := e.checkTerraformPolicies(ctx, step, filepath.Join(workspaceDir, planFiles.JSON), stepID); err != nil {
+						planFiles.remove(workspaceDir)
+						return err
+					}
+					planFile = planFiles.Plan
+				}
+				summary, err := os.ReadFile(filepath.Join(workspaceDir, planFiles.Summary)) // #nosec G304 - workspace path controlled by executor
+				if err != nil {
+					return fmt.Errorf("failed to read terraform plan summary: %w", err)
+				}
+				approvedHash, err := hashPlanFile(planPath)
+				if err != nil {
+					return err
+				}
+				if err := e.waitForApproval(ctx, step, appName, execID, stepID, string(summary), filepath.Join(workspaceDir, planFiles.JSON), approvedHash); err != nil {
+					return err
+				}
+				// The approval covers exactly the reviewed plan
+				if currentHash, err := hashPlanFile(planPath); err != nil || currentHash != approvedHash {
+					planFiles.remove(workspaceDir)
+					return fmt.Errorf("terraform plan changed after it was approved; plan again and request a new approval")
+				}
+			}
+			if err := e.terraformApply(ctx, workspaceDir, planFile, variables); err != nil {
 				return err
 			}
+			planFiles.remove(workspaceDir)
 			// Capture outputs if specified
 			if len(outputNames) > 0 {
 				return e.terraformCaptureOutputs(ctx, workspaceDir, outputNames, step)
@@ -1635,7 +2044,9 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 
 	// Terraform-generate executor - generates Terraform code from Score resources
 	e.stepExecutors["terraform-generate"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
-		fmt.Printf("      📝 Generating Terraform code for: %s\n", step.Name)
+		logger := logging.FromContext(ctx, "workflow")
+
+		logger.Infof("Generating Terraform code for: %s", step.Name)
 
 		// Get output directory (default: workspaces/{app}/terraform)
 		outputDir := step.OutputDir
@@ -1660,8 +2071,8 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 			return fmt.Errorf("failed to create output directory: %w", err)
 		}
 
-		fmt.Printf("      📁 Output directory: %s\n", outputDir)
-		fmt.Printf("      🔧 Resource type: %s\n", resourceType)
+		logger.Infof("Output directory: %s", outputDir)
+		logger.Infof("Resource type: %s", resourceType)
 
 		// Generate Terraform code based on resource type
 		switch resourceType {
@@ -1676,7 +2087,9 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 
 	// Kubernetes executor - applies Kubernetes manifests
 	e.stepExecutors["kubernetes"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
-		fmt.Printf("      ☸️  Executing Kubernetes step: %s\n", step.Name)
+		logger := logging.FromContext(ctx, "workflow")
+
+		logger.Infof("Executing Kubernetes step: %s", step.Name)
 
 		// Get namespace (default to app name if not specified)
 		namespace := step.Namespace
@@ -1700,27 +2113,55 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 			operation = "apply"
 		}
 
-		fmt.Printf("      📋 Operation: %s\n", operation)
-		fmt.Printf("      🏷️  Namespace: %s\n", namespace)
+		logger.Infof("Operation: %s", operation)
+		logger.Infof("Namespace: %s", namespace)
 
 		// Handle different kubernetes operations
 		var logs string
 		var err error
 
+		// Namespaces are created with the server's credentials, everything else runs as the
+		// application's service account when an identity mode is configured
+		if operation != "create-namespace" {
+			var release func()
+			ctx, release, err = e.withApplicationIdentity(ctx, appName, namespace)
+			if err != nil {
+				return err
+			}
+			defer release()
+		}
+
 		switch operation {
 		case "create-namespace":
 			logs, err = e.kubernetesCreateNamespace(ctx, namespace)
 			if err != nil {
 				// Store logs even on failure
-				_ = e.repo.AddWorkflowStepLogs(stepID, logs)
+				_ = e.addStepLogs(stepID, logs)
+				return err
+			}
+			created := !strings.Contains(logs, "AlreadyExists")
+			if created {
+				e.trackCreated(ctx, execID, step.Name, compensationKubernetesNamespace, "namespace "+namespace,
+					map[string]string{"namespace": namespace})
+			}
+			if err := e.provisionApplicationIdentity(ctx, appName, namespace, created); err != nil {
+				_ = e.addStepLogs(stepID, logs)
 				return err
 			}
 
 		case "apply":
-			// Get manifest from config (inline YAML or file path)
-			manifest, ok := step.Config["manifest"].(string)
-			if !ok || manifest == "" {
-				return fmt.Errorf("kubernetes apply step requires 'manifest' in config")
+			// Get manifest from config (inline YAML), or from a directory of rendered manifests
+			manifest, _ := step.Config["manifest"].(string)
+			source, _ := step.Config["source"].(string)
+			fromSource := manifest == "" && source != ""
+			if fromSource {
+				manifest, err = readManifestDir(e.execContext.replaceVariables(source, step.Env))
+				if err != nil {
+					return err
+				}
+			}
+			if manifest == "" {
+				return fmt.Errorf("kubernetes apply step requires 'manifest' or 'source' in config")
 			}
 
 			// Get workflow variables from execution context
@@ -1736,27 +2177,38 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 				"parameters": workflowVars,
 			}
 
-			fmt.Printf("      🔍 DEBUG: Template parameters from workflow variables: %v\n", workflowVars)
+			logger.Debugf("Template parameters from workflow variables: %v", workflowVars)
 
-			// Render template with parameters
-			rendered, err := e.renderTemplate(manifest, templateData)
-			if err != nil {
+			// Render template with parameters; manifests read from a directory are already rendered
+			rendered := manifest
+			if fromSource {
+				logger.Debugf("Applying rendered manifests from %s", step.Config["source"])
+			} else if rendered, err = e.renderTemplate(manifest, templateData); err != nil {
 				return fmt.Errorf("failed to render manifest template: %w", err)
 			}
 
-			fmt.Printf("      📝 DEBUG: Rendered manifest (first 500 chars):\n%s\n", func() string {
+			logger.Debugf("Rendered manifest (first 500 chars):\n%s", func() string {
 				if len(rendered) > 500 {
 					return rendered[:500] + "..."
 				}
 				return rendered
 			}())
 
+			// Every object carries the deployment labels for cost allocation and drift checks
+			if rendered, err = LabelManifests(rendered, e.deploymentLabels()); err != nil {
+				return err
+			}
+
 			logs, err = e.kubernetesApply(ctx, namespace, rendered)
 			if err != nil {
 				// Store logs even on failure
-				_ = e.repo.AddWorkflowStepLogs(stepID, logs)
+				_ = e.addStepLogs(stepID, logs)
 				return err
 			}
+			for _, object := range createdKubernetesObjects(rendered, logs) {
+				e.trackCreated(ctx, execID, step.Name, compensationKubernetesObject, object.Ref,
+					map[string]string{"object": object.Ref, "namespace": object.Namespace})
+			}
 
 		case "delete":
 			// Get manifest or resource identifier
@@ -1789,16 +2241,69 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 		}
 
 		// Store captured logs in database
-		if err := e.repo.AddWorkflowStepLogs(stepID, logs); err != nil {
-			fmt.Printf("      ⚠️  Warning: failed to store step logs: %v\n", err)
+		if err := e.addStepLogs(stepID, logs); err != nil {
+			logger.Warnf("Failed to store step logs: %v", err)
 		}
 
 		return nil
 	}
 
+	// Helm executor - installs or upgrades a chart release
+	e.stepExecutors["helm"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logs, err := e.helmInstall(ctx, step, execID)
+		if logErr := e.addStepLogs(stepID, logs); logErr != nil {
+			logging.FromContext(ctx, "workflow").Warnf("Failed to store step logs: %v", logErr)
+		}
+		return err
+	}
+
+	// Cluster readiness executor - waits for the API server and platform deployments
+	e.stepExecutors["cluster-readiness"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logs, err := e.clusterReadiness(ctx, step)
+		if logErr := e.addStepLogs(stepID, logs); logErr != nil {
+			logging.FromContext(ctx, "workflow").Warnf("Failed to store step logs: %v", logErr)
+		}
+		return err
+	}
+
+	// Render executor - renders templates into the workspace or a GitOps repository
+	e.stepExecutors["render"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logs, err := e.renderStep(ctx, step, appName)
+		if logErr := e.addStepLogs(stepID, logs); logErr != nil {
+			logging.FromContext(ctx, "workflow").Warnf("Failed to store step logs: %v", logErr)
+		}
+		return err
+	}
+
+	// Database migration executor - runs flyway, liquibase or a custom image as a job
+	e.stepExecutors["db-migrate"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		logs, err := e.dbMigrate(ctx, step, appName, execID, stepID)
+		if logErr := e.addStepLogs(stepID, logs); logErr != nil {
+			logging.FromContext(ctx, "workflow").Warnf("Failed to store step logs: %v", logErr)
+		}
+		return err
+	}
+
+	// Cluster registration executor - adds the cluster to the multi-cluster registry
+	e.stepExecutors["register-cluster"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		return e.registerCluster(ctx, step, appName, execID)
+	}
+
+	// HTTP check executor - polls a URL until it answers as expected
+	e.stepExecutors["http-check"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		return e.executeHTTPCheckStep(ctx, step, stepID)
+	}
+
+	// Teardown executor - removes the resources earlier steps of the run created
+	e.stepExecutors["teardown"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		return e.executeTeardownStep(ctx, execID, stepID)
+	}
+
 	// Ansible executor - runs Ansible playbooks
 	e.stepExecutors["ansible"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
-		fmt.Printf("      🤖 Executing Ansible step: %s\n", step.Name)
+		logger := logging.FromContext(ctx, "workflow")
+
+		logger.Infof("Executing Ansible step: %s", step.Name)
 
 		// Get playbook from config
 		playbook, ok := step.Config["playbook"].(string)
@@ -1815,11 +2320,11 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 			return fmt.Errorf("ansible playbook does not exist: %s", playbook)
 		}
 
-		fmt.Printf("      📝 Playbook: %s\n", playbook)
+		logger.Infof("Playbook: %s", playbook)
 
 		// Run ansible-playbook
 		// #nosec G204 - playbook from validated workflow definition
-		cmd := exec.CommandContext(ctx, "ansible-playbook", playbook)
+		cmd := e.stepCommand(ctx, "ansible-playbook", playbook)
 
 		// Set working directory if specified
 		workingDir := step.WorkingDir
@@ -1839,26 +2344,66 @@ func (e *WorkflowExecutor) registerDefaultStepExecutors() {
 			return fmt.Errorf("ansible-playbook failed: %w", err)
 		}
 
-		fmt.Printf("      ✅ Ansible playbook completed successfully\n")
+		logger.Info("Ansible playbook completed successfully")
 		return nil
 	}
 
 	// Gitea repository executor - creates/manages Gitea repositories
 	e.stepExecutors["gitea-repo"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
-		fmt.Printf("      🗂️  Executing Gitea repository step: %s\n", step.Name)
+		logger := logging.FromContext(ctx, "workflow")
+
+		logger.Infof("Executing Gitea repository step: %s", step.Name)
+
+		// Only a repository this run creates is removed on rollback
+		owner, existed, checkErr := giteaRepoExists(ctx, step)
 
 		// This is a simplified version - full implementation would use Gitea API
 		// For now, we delegate to the legacy implementation for compatibility
-		return runStepWithSpinner(step, appName, "default", nil)
+		if err := runStepWithSpinner(ctx, step, appName, "default", nil); err != nil {
+			return err
+		}
+
+		if checkErr != nil {
+			logger.Warnf("Repository %s is not tracked for rollback: %v", step.RepoName, checkErr)
+		} else if !existed {
+			e.trackCreated(ctx, execID, step.Name, compensationGiteaRepo, fmt.Sprintf("gitea repository %s/%s", owner, step.RepoName),
+				map[string]string{"owner": owner, "repo": step.RepoName})
+		}
+		return nil
 	}
 
 	// ArgoCD application executor - creates/manages ArgoCD applications
 	e.stepExecutors["argocd-app"] = func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
-		fmt.Printf("      🚀 Executing ArgoCD application step: %s\n", step.Name)
+		logger := logging.FromContext(ctx, "workflow")
+
+		logger.Infof("Executing ArgoCD application step: %s", step.Name)
+
+		// Applications of a team are isolated in the team's project
+		if step.Project == "" {
+			project, err := e.teamArgoCDProject(appName)
+			if err != nil {
+				return err
+			}
+			step.Project = project
+		}
+
+		// Only an application this run creates is removed on rollback
+		name := argoCDAppName(step, appName)
+		existed, checkErr := argoCDAppExists(ctx, name)
 
 		// This is a simplified version - full implementation would use ArgoCD API
 		// For now, we delegate to the legacy implementation for compatibility
-		return runStepWithSpinner(step, appName, "default", nil)
+		if err := runStepWithSpinner(ctx, step, appName, "default", nil); err != nil {
+			return err
+		}
+
+		if checkErr != nil {
+			logger.Warnf("Application %s is not tracked for rollback: %v", name, checkErr)
+		} else if !existed {
+			e.trackCreated(ctx, execID, step.Name, compensationArgoCDApp, "argocd application "+name,
+				map[string]string{"name": name})
+		}
+		return nil
 	}
 }
 
@@ -1910,8 +2455,10 @@ func (e *WorkflowExecutor) copyFile(src, dest string) error {
 
 // terraformInit initializes terraform in the workspace
 func (e *WorkflowExecutor) terraformInit(ctx context.Context, workspaceDir string) error {
-	fmt.Printf("      🔧 Terraform init\n")
-	cmd := exec.CommandContext(ctx, "terraform", "init", "-no-color")
+	logger := logging.FromContext(ctx, "workflow")
+
+	logger.Info("Terraform init")
+	cmd := e.stepCommand(ctx, "terraform", "init", "-no-color")
 	cmd.Dir = workspaceDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -1920,63 +2467,98 @@ func (e *WorkflowExecutor) terraformInit(ctx context.Context, workspaceDir strin
 	return nil
 }
 
-// terraformPlan runs terraform plan
-func (e *WorkflowExecutor) terraformPlan(ctx context.Context, workspaceDir string, variables map[string]string) error {
-	fmt.Printf("      📋 Terraform plan\n")
-	args := []string{"plan", "-no-color"}
+// terraformPlan runs terraform plan and stores the binary plan, its JSON rendering
+// and a human-readable summary in the workspace for review and a later apply
+func (e *WorkflowExecutor) terraformPlan(ctx context.Context, workspaceDir string, files terraformPlanFiles, variables map[string]string) (*PlanSummary, error) {
+	logger := logging.FromContext(ctx, "workflow")
+
+	logger.Info("Terraform plan")
+	removeStalePlans(workspaceDir, files)
+	args := []string{"plan", "-no-color", "-out=" + files.Plan}
 	for k, v := range variables {
 		args = append(args, "-var", fmt.Sprintf("%s=%s", k, v))
 	}
 
-	cmd := exec.CommandContext(ctx, "terraform", args...)
+	cmd := e.stepCommand(ctx, "terraform", args...)
 	cmd.Dir = workspaceDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("terraform plan failed: %w\nOutput: %s", err, string(output))
+		return nil, fmt.Errorf("terraform plan failed: %w\nOutput: %s", err, string(output))
 	}
-	fmt.Printf("%s\n", string(output))
-	return nil
+
+	cmd = e.stepCommand(ctx, "terraform", "show", "-json", files.Plan)
+	cmd.Dir = workspaceDir
+	planJSON, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("terraform show failed: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(workspaceDir, files.JSON), planJSON, 0600); err != nil {
+		return nil, fmt.Errorf("failed to store terraform plan artifact: %w", err)
+	}
+
+	summary, err := summarizeTerraformPlan(planJSON)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(workspaceDir, files.Summary), []byte(summary.String()), 0600); err != nil {
+		return nil, fmt.Errorf("failed to store terraform plan summary: %w", err)
+	}
+
+	logger.Info(summary.String())
+	return summary, nil
 }
 
-// terraformApply runs terraform apply
-func (e *WorkflowExecutor) terraformApply(ctx context.Context, workspaceDir string, variables map[string]string) error {
-	fmt.Printf("      ✅ Terraform apply\n")
+// terraformApply runs terraform apply. A saved plan (planFile, relative to the workspace)
+// is applied as-is so that exactly the reviewed changes are made; without one the
+// configuration is applied with variables.
+func (e *WorkflowExecutor) terraformApply(ctx context.Context, workspaceDir, planFile string, variables map[string]string) error {
+	logger := logging.FromContext(ctx, "workflow")
+
+	logger.Info("Terraform apply")
 	args := []string{"apply", "-auto-approve", "-no-color"}
-	for k, v := range variables {
-		args = append(args, "-var", fmt.Sprintf("%s=%s", k, v))
+	if planFile != "" {
+		args = append(args, planFile)
+	} else {
+		for k, v := range variables {
+			args = append(args, "-var", fmt.Sprintf("%s=%s", k, v))
+		}
 	}
 
-	cmd := exec.CommandContext(ctx, "terraform", args...)
+	cmd := e.stepCommand(ctx, "terraform", args...)
 	cmd.Dir = workspaceDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("terraform apply failed: %w\nOutput: %s", err, string(output))
 	}
-	fmt.Printf("      🎉 Terraform apply completed successfully\n")
+	logger.Info("Terraform apply completed successfully")
 	return nil
 }
 
 // terraformDestroy runs terraform destroy
 func (e *WorkflowExecutor) terraformDestroy(ctx context.Context, workspaceDir string, variables map[string]string) error {
-	fmt.Printf("      🗑️  Terraform destroy\n")
+	logger := logging.FromContext(ctx, "workflow")
+
+	logger.Info("Terraform destroy")
 	args := []string{"destroy", "-auto-approve", "-no-color"}
 	for k, v := range variables {
 		args = append(args, "-var", fmt.Sprintf("%s=%s", k, v))
 	}
 
-	cmd := exec.CommandContext(ctx, "terraform", args...)
+	cmd := e.stepCommand(ctx, "terraform", args...)
 	cmd.Dir = workspaceDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("terraform destroy failed: %w\nOutput: %s", err, string(output))
 	}
-	fmt.Printf("      ✅ Terraform destroy completed successfully\n")
+	logger.Info("Terraform destroy completed successfully")
 	return nil
 }
 
 // terraformCaptureOutputs captures terraform outputs and stores them
 func (e *WorkflowExecutor) terraformCaptureOutputs(ctx context.Context, workspaceDir string, outputNames []string, step types.Step) error {
-	fmt.Printf("      📤 Capturing Terraform outputs\n")
+	logger := logging.FromContext(ctx, "workflow")
+
+	logger.Info("Capturing Terraform outputs")
 
 	// Determine resource name for storing outputs
 	// Priority: step.Resource > step.Name
@@ -1986,7 +2568,7 @@ func (e *WorkflowExecutor) terraformCaptureOutputs(ctx context.Context, workspac
 	}
 
 	// Run terraform output -json
-	cmd := exec.CommandContext(ctx, "terraform", "output", "-json")
+	cmd := e.stepCommand(ctx, "terraform", "output", "-json")
 	cmd.Dir = workspaceDir
 	output, err := cmd.Output()
 	if err != nil {
@@ -2005,15 +2587,15 @@ func (e *WorkflowExecutor) terraformCaptureOutputs(ctx context.Context, workspac
 			if outputMap, ok := outputValue.(map[string]interface{}); ok {
 				if value, ok := outputMap["value"]; ok {
 					valueStr := fmt.Sprintf("%v", value)
-					fmt.Printf("      📊 Output '%s': %s\n", outputName, valueStr)
+					logger.Infof("Output '%s': %s", outputName, valueStr)
 
 					// Store output in execution context for interpolation in subsequent steps
 					e.execContext.SetResourceOutput(resourceName, outputName, valueStr)
-					fmt.Printf("      ✓ Stored as ${resources.%s.%s}\n", resourceName, outputName)
+					logger.Infof("Stored as ${resources.%s.%s}", resourceName, outputName)
 				}
 			}
 		} else {
-			fmt.Printf("      ⚠️  Output '%s' not found in terraform outputs\n", outputName)
+			logger.Warnf("Output '%s' not found in terraform outputs", outputName)
 		}
 	}
 
@@ -2027,7 +2609,6 @@ func (e *WorkflowExecutor) generateS3BucketTerraform(outputDir, appName string,
 	bucketName := fmt.Sprintf("%s-storage", appName)
 	minioEndpoint := "http://minio.minio-system.svc.cluster.local:9000"
 	minioUser := "minioadmin"
-	minioPassword := "minioadmin"
 
 	// Override from step variables if provided
 	if step.Variables != nil {
@@ -2040,8 +2621,8 @@ func (e *WorkflowExecutor) generateS3BucketTerraform(outputDir, appName string,
 		if usr, ok := step.Variables["minio_user"].(string); ok {
 			minioUser = usr
 		}
-		if pwd, ok := step.Variables["minio_password"].(string); ok {
-			minioPassword = pwd
+		if _, ok := step.Variables["minio_password"]; ok {
+			e.logger.Warnf("Ignoring minio_password of step %s: the password is not written to main.tf, set TF_VAR_minio_password in the env of the terraform step instead", step.Name)
 		}
 	}
 
@@ -2058,10 +2639,16 @@ terraform {
   }
 }
 
+variable "minio_password" {
+  type        = string
+  description = "Minio admin password, set through TF_VAR_minio_password"
+  sensitive   = true
+}
+
 provider "minio" {
   minio_server   = "%s"
   minio_user     = "%s"
-  minio_password = "%s"
+  minio_password = var.minio_password
   minio_ssl      = false
 }
 
@@ -2089,7 +2676,7 @@ output "bucket_arn" {
   value       = "arn:aws:s3:::${minio_s3_bucket.app_bucket.bucket}"
   description = "ARN-style identifier for the bucket"
 }
-`, appName, time.Now().Format(time.RFC3339), minioEndpoint, minioUser, minioPassword, bucketName, minioEndpoint)
+`, appName, time.Now().Format(time.RFC3339), minioEndpoint, minioUser, bucketName, minioEndpoint)
 
 	// Write main.tf
 	mainTfPath := filepath.Join(outputDir, "main.tf")
@@ -2097,8 +2684,8 @@ output "bucket_arn" {
 		return fmt.Errorf("failed to write main.tf: %w", err)
 	}
 
-	fmt.Printf("      ✅ Generated: %s\n", mainTfPath)
-	fmt.Printf("      📦 Bucket name: %s\n", bucketName)
+	e.logger.Infof("Generated: %s", mainTfPath)
+	e.logger.Infof("Bucket name: %s", bucketName)
 
 	return nil
 }
@@ -2113,10 +2700,12 @@ func (e *WorkflowExecutor) generatePostgresTerraform(outputDir, appName string,
 
 // kubernetesCreateNamespace creates a Kubernetes namespace and returns output logs
 func (e *WorkflowExecutor) kubernetesCreateNamespace(ctx context.Context, namespace string) (string, error) {
-	fmt.Printf("      🏗️  Creating namespace: %s\n", namespace)
+	logger := logging.FromContext(ctx, "workflow")
+
+	logger.Infof("Creating namespace: %s", namespace)
 
 	// #nosec G204 - namespace is validated input from workflow config
-	cmd := exec.CommandContext(ctx, "kubectl", "create", "namespace", namespace)
+	cmd := e.stepCommand(ctx, "kubectl", "create", "namespace", namespace)
 	output, err := cmd.CombinedOutput()
 	outputStr := string(output)
 
@@ -2125,9 +2714,19 @@ func (e *WorkflowExecutor) kubernetesCreateNamespace(ctx context.Context, namesp
 	}
 
 	if strings.Contains(outputStr, "AlreadyExists") {
-		fmt.Printf("      ℹ️  Namespace already exists: %s\n", namespace)
+		logger.Infof("Namespace already exists: %s", namespace)
 	} else {
-		fmt.Printf("      ✅ Namespace created: %s\n", namespace)
+		logger.Infof("Namespace created: %s", namespace)
+		// Existing namespaces may belong to someone else; only label the ones created here
+		if labels := e.deploymentLabels(); len(labels) > 0 {
+			args := append([]string{"label", "namespace", namespace, "--overwrite"}, labelArgs(labels)...)
+			// #nosec G204 - namespace is validated input from workflow config
+			labelOutput, err := e.stepCommand(ctx, "kubectl", args...).CombinedOutput()
+			outputStr += string(labelOutput)
+			if err != nil {
+				return outputStr, fmt.Errorf("failed to label namespace: %w, output: %s", err, string(labelOutput))
+			}
+		}
 	}
 
 	return outputStr, nil
@@ -2135,12 +2734,14 @@ func (e *WorkflowExecutor) kubernetesCreateNamespace(ctx context.Context, namesp
 
 // kubernetesApply applies a Kubernetes manifest and returns output logs
 func (e *WorkflowExecutor) kubernetesApply(ctx context.Context, namespace, manifest string) (string, error) {
-	fmt.Printf("      📝 Applying Kubernetes manifest (workflow context namespace: %s)\n", namespace)
+	logger := logging.FromContext(ctx, "workflow")
+
+	logger.Infof("Applying Kubernetes manifest (workflow context namespace: %s)", namespace)
 
 	// Don't pass -n flag to kubectl - let the manifest specify its own namespace
 	// This avoids conflicts when the manifest has a namespace field in metadata
 	// #nosec G204 - validated inputs from workflow config
-	cmd := exec.CommandContext(ctx, "kubectl", "apply", "-f", "-")
+	cmd := e.kubectl(ctx, "apply", "-f", "-")
 	cmd.Stdin = strings.NewReader(manifest)
 
 	output, err := cmd.CombinedOutput()
@@ -2150,18 +2751,20 @@ func (e *WorkflowExecutor) kubernetesApply(ctx context.Context, namespace, manif
 		return outputStr, fmt.Errorf("failed to apply manifest: %w, output: %s", err, outputStr)
 	}
 
-	fmt.Printf("      ✅ Manifest applied successfully\n")
-	fmt.Printf("      📋 Output: %s\n", outputStr)
+	logger.Info("Manifest applied successfully")
+	logger.Infof("Output: %s", outputStr)
 
 	return outputStr, nil
 }
 
 // kubernetesDelete deletes a Kubernetes resource
 func (e *WorkflowExecutor) kubernetesDelete(ctx context.Context, namespace, manifest string) error {
-	fmt.Printf("      🗑️  Deleting Kubernetes resources from namespace: %s\n", namespace)
+	logger := logging.FromContext(ctx, "workflow")
+
+	logger.Infof("Deleting Kubernetes resources from namespace: %s", namespace)
 
 	// #nosec G204 - namespace is validated input from workflow config
-	cmd := exec.CommandContext(ctx, "kubectl", "delete", "-f", "-", "-n", namespace)
+	cmd := e.kubectl(ctx, "delete", "-f", "-", "-n", namespace)
 	cmd.Stdin = strings.NewReader(manifest)
 
 	output, err := cmd.CombinedOutput()
@@ -2169,15 +2772,17 @@ func (e *WorkflowExecutor) kubernetesDelete(ctx context.Context, namespace, mani
 		return fmt.Errorf("failed to delete resources: %w, output: %s", err, string(output))
 	}
 
-	fmt.Printf("      ✅ Resources deleted successfully\n")
-	fmt.Printf("      📋 Output: %s\n", string(output))
+	logger.Info("Resources deleted successfully")
+	logger.Infof("Output: %s", string(output))
 
 	return nil
 }
 
 // kubernetesGet retrieves Kubernetes resource information
 func (e *WorkflowExecutor) kubernetesGet(ctx context.Context, namespace, resourceType, resourceName string) error {
-	fmt.Printf("      🔍 Getting Kubernetes resource: %s/%s\n", resourceType, resourceName)
+	logger := logging.FromContext(ctx, "workflow")
+
+	logger.Infof("Getting Kubernetes resource: %s/%s", resourceType, resourceName)
 
 	args := []string{"get", resourceType}
 	if resourceName != "" {
@@ -2186,15 +2791,15 @@ func (e *WorkflowExecutor) kubernetesGet(ctx context.Context, namespace, resourc
 	args = append(args, "-n", namespace, "-o", "yaml")
 
 	// #nosec G204 - args are validated inputs from workflow config
-	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd := e.kubectl(ctx, args...)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to get resource: %w, output: %s", err, string(output))
 	}
 
-	fmt.Printf("      ✅ Resource retrieved successfully\n")
-	fmt.Printf("      📋 Output:\n%s\n", string(output))
+	logger.Info("Resource retrieved successfully")
+	logger.Infof("Output:\n%s", string(output))
 
 	return nil
 }
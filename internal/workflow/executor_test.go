@@ -651,6 +651,92 @@ func TestGoldenPathParameterPrecedence(t *testing.T) {
 	assert.Equal(t, "workflow-value-2", value2, "Workflow variable should be used when no golden path param")
 }
 
+// parameterRecordingRepository records the parameters executions start with
+type parameterRecordingRepository struct {
+	*MockWorkflowRepository
+	parameters map[int64]map[string]string
+	sources    map[int64]map[string]types.ParameterSource
+	replayOf   map[int64]int64
+}
+
+func newParameterRecordingRepository() *parameterRecordingRepository {
+	return &parameterRecordingRepository{
+		MockWorkflowRepository: NewMockWorkflowRepository(),
+		parameters:             map[int64]map[string]string{},
+		sources:                map[int64]map[string]types.ParameterSource{},
+		replayOf:               map[int64]int64{},
+	}
+}
+
+func (r *parameterRecordingRepository) SetWorkflowExecutionParameters(execID int64, parameters map[string]string, sources map[string]types.ParameterSource) error {
+	r.parameters[execID] = parameters
+	r.sources[execID] = sources
+	return nil
+}
+
+func (r *parameterRecordingRepository) MarkWorkflowExecutionReplay(execID, originalID int64) error {
+	r.replayOf[execID] = originalID
+	return nil
+}
+
+// TestExecutionParametersRecorded verifies parameters are persisted for bundles and
+// replays, with externally sourced values replaced by their sources
+func TestExecutionParametersRecorded(t *testing.T) {
+	repo := newParameterRecordingRepository()
+	executor := NewWorkflowExecutor(repo)
+
+	workflow := types.Workflow{
+		Variables: map[string]string{"PARAM1": "workflow-value"},
+		Steps:     []types.Step{},
+	}
+	params := map[string]string{"PARAM1": "golden-path-value", "environment": "staging", "db_password": "s3cret"}
+	sources := map[string]types.ParameterSource{"db_password": {Type: "vault", Path: "secret/data/db", Key: "password"}}
+
+	ctx := WithExternalParameters(context.Background(), sources)
+	err := executor.ExecuteWorkflowWithContext(ctx, "test-app", "test-workflow", workflow, params)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"PARAM1": "workflow-value", "environment": "staging"}, repo.parameters[1])
+	assert.Equal(t, sources, repo.sources[1])
+}
+
+// TestReplayWorkflow verifies a replay is a new execution linked to the original
+func TestReplayWorkflow(t *testing.T) {
+	repo := newParameterRecordingRepository()
+	executor := NewWorkflowExecutor(repo)
+
+	workflow := types.Workflow{Steps: []types.Step{}}
+	require.NoError(t, executor.ExecuteWorkflowWithContext(context.Background(), "test-app", "test-workflow", workflow, map[string]string{"environment": "staging"}))
+
+	replayID, done, err := executor.StartReplay(context.Background(), 1, "test-app", "test-workflow", workflow, repo.parameters[1])
+	require.NoError(t, err)
+	require.NoError(t, <-done)
+
+	assert.Equal(t, int64(2), replayID)
+	assert.Equal(t, int64(1), repo.replayOf[2])
+	assert.Equal(t, repo.parameters[1], repo.parameters[2])
+	_, isReplay := repo.replayOf[1]
+	assert.False(t, isReplay)
+}
+
+// TestExecutionStartedCallback verifies the callback receives the recorded execution ID
+func TestExecutionStartedCallback(t *testing.T) {
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+
+	var started, nested []int64
+	ctx := WithExecutionStarted(context.Background(), func(id int64) { started = append(started, id) })
+	require.NoError(t, executor.ExecuteWorkflowWithContext(ctx, "test-app", "test-workflow", types.Workflow{Steps: []types.Step{}}))
+
+	assert.Equal(t, []int64{1}, started)
+
+	// A callback registered on a derived context does not replace the parent's
+	ctx = WithExecutionStarted(ctx, func(id int64) { nested = append(nested, id) })
+	require.NoError(t, executor.ExecuteWorkflowWithContext(ctx, "test-app", "test-workflow", types.Workflow{Steps: []types.Step{}}))
+
+	assert.Equal(t, []int64{1, 2}, started)
+	assert.Equal(t, []int64{2}, nested)
+}
+
 // TestGoldenPathParameterWithoutParameters tests backward compatibility (no parameters)
 func TestGoldenPathParameterWithoutParameters(t *testing.T) {
 	repo := NewMockWorkflowRepository()
@@ -0,0 +1,33 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/faults"
+	"innominatus/internal/logging"
+	"innominatus/internal/types"
+)
+
+// SetFaultInjector enables injecting faults into workflow steps for resilience testing.
+// nil turns injection off.
+func (e *WorkflowExecutor) SetFaultInjector(injector *faults.Injector) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.faults = injector
+}
+
+// runWithFaults runs a step through the fault injector, if one is set
+func (e *WorkflowExecutor) runWithFaults(ctx context.Context, step types.Step, appName string, run func() error) error {
+	e.mu.RLock()
+	injector := e.faults
+	e.mu.RUnlock()
+
+	target := faults.Target{Scope: faults.ScopeStep, App: appName, StepType: step.Type, StepName: step.Name}
+	return injector.Run(ctx, target, func(f faults.Fault) {
+		logging.FromContext(ctx, "workflow").WarnWithFields("Injecting fault into step", map[string]interface{}{
+			"fault_id":  f.ID,
+			"kind":      string(f.Kind),
+			"step_name": step.Name,
+			"step_type": step.Type,
+		})
+	}, run)
+}
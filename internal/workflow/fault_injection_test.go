@@ -0,0 +1,39 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/faults"
+	"innominatus/internal/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFaultInjection_CrashTriggersCompensation verifies that a step crashing after it
+// created its resource is rolled back together with the resources created before it
+func TestFaultInjection_CrashTriggersCompensation(t *testing.T) {
+	repo := newCompensationRepository()
+	var removed []string
+	executor := newCompensatingExecutor(repo, &removed, "")
+
+	injector := faults.NewInjector()
+	_, err := injector.Add(faults.Fault{Scope: faults.ScopeStep, Kind: faults.KindCrash, StepName: "app", Message: "node lost"})
+	require.NoError(t, err)
+	executor.SetFaultInjector(injector)
+
+	workflow := types.Workflow{
+		OnFailure: OnFailureRollback,
+		Steps: []types.Step{
+			{Name: "repo", Type: "create"},
+			{Name: "app", Type: "create"},
+		},
+	}
+	err = executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", workflow)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "injected crash (fault 1): node lost")
+
+	assert.Equal(t, []string{"app", "repo"}, removed)
+	assert.Equal(t, []string{"repo=rolled_back", "app=rolled_back"}, repo.statuses())
+	assert.Equal(t, 1, injector.List()[0].Hits)
+}
@@ -0,0 +1,262 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/logging"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Identities kubernetes steps run kubectl with
+const (
+	KubernetesIdentityServer      = "server"      // The server's own credentials
+	KubernetesIdentityImpersonate = "impersonate" // The server's credentials impersonating the application's service account
+	KubernetesIdentityKubeconfig  = "kubeconfig"  // A kubeconfig holding a short-lived token of the application's service account
+)
+
+// ApplicationLabel marks the namespaces an application owns
+const ApplicationLabel = "innominatus.io/application"
+
+const (
+	defaultKubernetesClusterRole = "edit"
+	kubernetesTokenDuration      = time.Hour
+)
+
+// KubernetesIdentity configures which identity kubernetes steps use (workflowPolicies.kubernetesIdentity)
+type KubernetesIdentity struct {
+	Mode        string // server (default), impersonate or kubeconfig
+	ClusterRole string // Bound to the application's service account in each namespace it owns (default edit)
+}
+
+type kubeIdentityKey struct{}
+
+// kubeIdentity is how kubectl authenticates within a step
+type kubeIdentity struct {
+	impersonate string // --as user
+	kubeconfig  string // KUBECONFIG of the step
+}
+
+// SetKubernetesIdentity sets the identity kubernetes steps run kubectl with
+func (e *WorkflowExecutor) SetKubernetesIdentity(identity KubernetesIdentity) error {
+	switch identity.Mode {
+	case "", KubernetesIdentityServer:
+		identity.Mode = KubernetesIdentityServer
+	case KubernetesIdentityImpersonate, KubernetesIdentityKubeconfig:
+	default:
+		return fmt.Errorf("unknown kubernetes identity mode %q (supported: server, impersonate, kubeconfig)", identity.Mode)
+	}
+	if identity.ClusterRole == "" {
+		identity.ClusterRole = defaultKubernetesClusterRole
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.kubeIdentity = identity
+	return nil
+}
+
+func (e *WorkflowExecutor) kubernetesIdentity() KubernetesIdentity {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.kubeIdentity
+}
+
+// serviceAccountName is the service account of an application in each namespace it owns
+func serviceAccountName(appName string) string {
+	return "innominatus-" + appName
+}
+
+// kubectl returns a kubectl command that runs with the step's kubernetes identity
+func (e *WorkflowExecutor) kubectl(ctx context.Context, args ...string) *exec.Cmd {
+	identity, _ := ctx.Value(kubeIdentityKey{}).(*kubeIdentity)
+	if identity != nil && identity.impersonate != "" {
+		args = append([]string{"--as=" + identity.impersonate}, args...)
+	}
+	cmd := e.stepCommand(ctx, "kubectl", args...)
+	if identity != nil && identity.kubeconfig != "" {
+		// exec keeps the last of duplicate variables
+		cmd.Env = append(cmd.Env, "KUBECONFIG="+identity.kubeconfig)
+	}
+	return cmd
+}
+
+// withApplicationIdentity returns a context whose kubectl commands act as the application's
+// service account in namespace, so a step cannot touch namespaces of other applications.
+// The returned function removes the step's kubeconfig.
+func (e *WorkflowExecutor) withApplicationIdentity(ctx context.Context, appName, namespace string) (context.Context, func(), error) {
+	identity := e.kubernetesIdentity()
+	if identity.Mode == "" || identity.Mode == KubernetesIdentityServer {
+		return ctx, func() {}, nil
+	}
+
+	if err := e.provisionApplicationIdentity(ctx, appName, namespace, false); err != nil {
+		return ctx, func() {}, err
+	}
+	account := serviceAccountName(appName)
+
+	if identity.Mode == KubernetesIdentityImpersonate {
+		user := fmt.Sprintf("system:serviceaccount:%s:%s", namespace, account)
+		return context.WithValue(ctx, kubeIdentityKey{}, &kubeIdentity{impersonate: user}), func() {}, nil
+	}
+
+	path, err := e.writeScopedKubeconfig(ctx, namespace, account)
+	if err != nil {
+		return ctx, func() {}, err
+	}
+	release := func() { _ = os.RemoveAll(filepath.Dir(path)) }
+	return context.WithValue(ctx, kubeIdentityKey{}, &kubeIdentity{kubeconfig: path}), release, nil
+}
+
+// provisionApplicationIdentity makes sure the application owns namespace and has a service
+// account bound to the configured cluster role there. Unlabeled namespaces are only claimed
+// when just created or named after the application. Runs with the server's credentials.
+func (e *WorkflowExecutor) provisionApplicationIdentity(ctx context.Context, appName, namespace string, created bool) error {
+	identity := e.kubernetesIdentity()
+	if identity.Mode == "" || identity.Mode == KubernetesIdentityServer {
+		return nil
+	}
+	logger := logging.FromContext(ctx, "workflow")
+
+	output, err := e.stepCommand(ctx, "kubectl", "get", "namespace", namespace, "-o", "json").CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(output), "NotFound") {
+			return fmt.Errorf("namespace %s does not exist; create it with a kubernetes step using operation create-namespace", namespace)
+		}
+		return fmt.Errorf("failed to get namespace %s: %w, output: %s", namespace, err, output)
+	}
+	var ns struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(output, &ns); err != nil {
+		return fmt.Errorf("failed to parse namespace %s: %w", namespace, err)
+	}
+
+	claim, err := namespaceClaim(namespace, ns.Metadata.Labels[ApplicationLabel], appName, created)
+	if err != nil {
+		return err
+	}
+	if claim {
+		// Without --overwrite this fails if another application labeled the namespace meanwhile
+		label := fmt.Sprintf("%s=%s", ApplicationLabel, appName)
+		if output, err := e.stepCommand(ctx, "kubectl", "label", "namespace", namespace, label).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to label namespace %s: %w, output: %s", namespace, err, output)
+		}
+		logger.Infof("Namespace %s now belongs to application %s", namespace, appName)
+	}
+
+	cmd := e.stepCommand(ctx, "kubectl", "apply", "-f", "-")
+	cmd.Stdin = strings.NewReader(applicationIdentityManifest(appName, namespace, identity.ClusterRole))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create service account for %s in %s: %w, output: %s", appName, namespace, err, output)
+	}
+	return nil
+}
+
+// namespaceClaim decides whether appName may use a namespace owned by owner, and whether it
+// has to claim it first
+func namespaceClaim(namespace, owner, appName string, created bool) (bool, error) {
+	switch {
+	case owner == appName:
+		return false, nil
+	case owner != "":
+		return false, fmt.Errorf("namespace %s belongs to application %s", namespace, owner)
+	case created || namespace == appName:
+		return true, nil
+	default:
+		return false, fmt.Errorf("namespace %s is not owned by application %s; label it %s=%s to allow its workflows", namespace, appName, ApplicationLabel, appName)
+	}
+}
+
+// applicationIdentityManifest returns the service account of an application and its role
+// binding in namespace
+func applicationIdentityManifest(appName, namespace, clusterRole string) string {
+	account := serviceAccountName(appName)
+	return fmt.Sprintf(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+  labels:
+    %[3]s: %[4]s
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+  labels:
+    %[3]s: %[4]s
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: %[5]s
+subjects:
+  - kind: ServiceAccount
+    name: %[1]s
+    namespace: %[2]s
+`, account, namespace, ApplicationLabel, appName, clusterRole)
+}
+
+// writeScopedKubeconfig writes a kubeconfig for the server's current cluster that
+// authenticates with a short-lived token of the service account
+func (e *WorkflowExecutor) writeScopedKubeconfig(ctx context.Context, namespace, account string) (string, error) {
+	current, err := e.stepCommand(ctx, "kubectl", "config", "view", "--minify", "--raw", "-o", "json").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the server kubeconfig: %w", err)
+	}
+	duration := fmt.Sprintf("--duration=%s", kubernetesTokenDuration)
+	token, err := e.stepCommand(ctx, "kubectl", "create", "token", account, "-n", namespace, duration).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to create token for service account %s/%s: %w", namespace, account, err)
+	}
+	config, err := scopedKubeconfig(current, strings.TrimSpace(string(token)), namespace)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "innominatus-kubeconfig-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create kubeconfig directory: %w", err)
+	}
+	path := filepath.Join(dir, "config")
+	if err := os.WriteFile(path, config, 0600); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return path, nil
+}
+
+// scopedKubeconfig builds a kubeconfig from the cluster of a minified kubeconfig (as printed
+// by kubectl config view --minify --raw -o json) with token as the only credential
+func scopedKubeconfig(current []byte, token, namespace string) ([]byte, error) {
+	var source struct {
+		Clusters []struct {
+			Cluster map[string]interface{} `json:"cluster"`
+		} `json:"clusters"`
+	}
+	if err := json.Unmarshal(current, &source); err != nil {
+		return nil, fmt.Errorf("failed to parse the server kubeconfig: %w", err)
+	}
+	if len(source.Clusters) == 0 {
+		return nil, fmt.Errorf("the server kubeconfig has no current cluster; use kubernetesIdentity mode impersonate")
+	}
+
+	config := map[string]interface{}{
+		"apiVersion":      "v1",
+		"kind":            "Config",
+		"current-context": "step",
+		"clusters":        []interface{}{map[string]interface{}{"name": "cluster", "cluster": source.Clusters[0].Cluster}},
+		"users":           []interface{}{map[string]interface{}{"name": "step", "user": map[string]string{"token": token}}},
+		"contexts": []interface{}{map[string]interface{}{"name": "step", "context": map[string]string{
+			"cluster": "cluster", "user": "step", "namespace": namespace,
+		}}},
+	}
+	return json.MarshalIndent(config, "", "  ")
+}
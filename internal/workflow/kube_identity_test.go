@@ -0,0 +1,108 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNamespaceClaim(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		owner     string
+		created   bool
+		wantClaim bool
+		wantErr   string
+	}{
+		{name: "owned", namespace: "shop-dev", owner: "shop"},
+		{name: "owned by another application", namespace: "billing", owner: "billing", wantErr: "belongs to application billing"},
+		{name: "just created", namespace: "shop-dev", created: true, wantClaim: true},
+		{name: "named after the application", namespace: "shop", wantClaim: true},
+		{name: "unlabeled", namespace: "kube-system", wantErr: "label it innominatus.io/application=shop"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claim, err := namespaceClaim(tt.namespace, tt.owner, "shop", tt.created)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantClaim, claim)
+		})
+	}
+}
+
+func TestApplicationIdentityManifest(t *testing.T) {
+	decoder := yaml.NewDecoder(strings.NewReader(applicationIdentityManifest("shop", "shop-dev", "edit")))
+	var objects []map[string]interface{}
+	for {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			break
+		}
+		objects = append(objects, object)
+	}
+	require.Len(t, objects, 2)
+	assert.Equal(t, "ServiceAccount", objects[0]["kind"])
+	assert.Equal(t, "RoleBinding", objects[1]["kind"])
+	assert.Equal(t, map[string]interface{}{"apiGroup": "rbac.authorization.k8s.io", "kind": "ClusterRole", "name": "edit"}, objects[1]["roleRef"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"kind": "ServiceAccount", "name": "innominatus-shop", "namespace": "shop-dev"}}, objects[1]["subjects"])
+}
+
+func TestScopedKubeconfig(t *testing.T) {
+	current := []byte(`{
+		"clusters": [{"name": "kind", "cluster": {"server": "https://127.0.0.1:6443", "certificate-authority-data": "Q0E="}}],
+		"users": [{"name": "admin", "user": {"client-key-data": "c2VjcmV0"}}]
+	}`)
+	config, err := scopedKubeconfig(current, "token-123", "shop-dev")
+	require.NoError(t, err)
+	assert.NotContains(t, string(config), "c2VjcmV0", "server credentials must not leak into the step kubeconfig")
+
+	var parsed struct {
+		Clusters []struct {
+			Cluster map[string]string `json:"cluster"`
+		} `json:"clusters"`
+		Users []struct {
+			User map[string]string `json:"user"`
+		} `json:"users"`
+		Contexts []struct {
+			Context map[string]string `json:"context"`
+		} `json:"contexts"`
+	}
+	require.NoError(t, json.Unmarshal(config, &parsed))
+	assert.Equal(t, "https://127.0.0.1:6443", parsed.Clusters[0].Cluster["server"])
+	assert.Equal(t, map[string]string{"token": "token-123"}, parsed.Users[0].User)
+	assert.Equal(t, "shop-dev", parsed.Contexts[0].Context["namespace"])
+
+	_, err = scopedKubeconfig([]byte(`{"clusters": []}`), "token", "shop")
+	assert.Error(t, err)
+}
+
+func TestKubectlIdentity(t *testing.T) {
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	assert.Error(t, executor.SetKubernetesIdentity(KubernetesIdentity{Mode: "root"}))
+	require.NoError(t, executor.SetKubernetesIdentity(KubernetesIdentity{}))
+	assert.Equal(t, KubernetesIdentity{Mode: KubernetesIdentityServer, ClusterRole: "edit"}, executor.kubernetesIdentity())
+
+	// The server identity needs no provisioning
+	ctx, release, err := executor.withApplicationIdentity(context.Background(), "shop", "shop")
+	require.NoError(t, err)
+	release()
+	assert.Equal(t, []string{"kubectl", "get", "pods"}, executor.kubectl(ctx, "get", "pods").Args)
+
+	impersonated := context.WithValue(context.Background(), kubeIdentityKey{}, &kubeIdentity{impersonate: "system:serviceaccount:shop:innominatus-shop"})
+	assert.Equal(t, []string{"kubectl", "--as=system:serviceaccount:shop:innominatus-shop", "get", "pods"}, executor.kubectl(impersonated, "get", "pods").Args)
+
+	scoped := context.WithValue(context.Background(), kubeIdentityKey{}, &kubeIdentity{kubeconfig: "/tmp/step/config"})
+	cmd := executor.kubectl(scoped, "get", "pods")
+	assert.Equal(t, "KUBECONFIG=/tmp/step/config", cmd.Env[len(cmd.Env)-1])
+}
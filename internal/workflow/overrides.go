@@ -0,0 +1,198 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"innominatus/internal/types"
+)
+
+// imageRefPattern matches an image reference with a tag or digest, e.g. registry.example.com/shop/api:1.4.2
+var imageRefPattern = regexp.MustCompile(`^([a-z0-9]+(?:[._/-][a-z0-9]+)*(?::[0-9]+)?(?:/[a-z0-9]+(?:[._-][a-z0-9]+)*)*)(:[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}|@sha256:[a-f0-9]{64})$`)
+
+// ParseStepOverrides decodes the overrides of a golden path run. Unknown fields are
+// rejected, so a typo cannot silently turn into a run without the intended change.
+func ParseStepOverrides(data []byte) (*types.StepOverrides, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var overrides types.StepOverrides
+	if err := decoder.Decode(&overrides); err != nil {
+		return nil, fmt.Errorf("invalid overrides: %w", err)
+	}
+	return &overrides, nil
+}
+
+// ApplyStepOverrides validates overrides against a workflow and returns the workflow and
+// parameters of the run with the overrides applied. Every step must exist, skipped steps
+// take no other change, an image must replace at least one reference in the step, and
+// variables must be declared as workflow variables or inputs. The workflow passed in is
+// not modified.
+func ApplyStepOverrides(workflow types.Workflow, parameters map[string]string, overrides *types.StepOverrides) (types.Workflow, map[string]string, error) {
+	if strings.TrimSpace(overrides.Reason) == "" {
+		return workflow, parameters, fmt.Errorf("overrides need a reason")
+	}
+	if len(overrides.Steps) == 0 && len(overrides.Variables) == 0 {
+		return workflow, parameters, fmt.Errorf("overrides change no step or variable")
+	}
+
+	result := workflow
+	result.Steps = append([]types.Step(nil), workflow.Steps...)
+	var problems []string
+
+	for _, name := range sortedKeys(overrides.Steps) {
+		override := overrides.Steps[name]
+		index := -1
+		for i := range result.Steps {
+			if result.Steps[i].Name == name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			problems = append(problems, fmt.Sprintf("step '%s' does not exist", name))
+			continue
+		}
+		step, err := overrideStep(result.Steps[index], override, overrides.Reason)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("step '%s': %v", name, err))
+			continue
+		}
+		result.Steps[index] = step
+	}
+
+	result.Variables = make(map[string]string, len(workflow.Variables))
+	for k, v := range workflow.Variables {
+		result.Variables[k] = v
+	}
+	params := make(map[string]string, len(parameters)+len(overrides.Variables))
+	for k, v := range parameters {
+		params[k] = v
+	}
+	inputs := make(map[string]bool, len(workflow.Inputs))
+	for _, input := range workflow.Inputs {
+		inputs[input.Name] = true
+	}
+	for _, name := range sortedKeys(overrides.Variables) {
+		value := overrides.Variables[name]
+		_, isVariable := workflow.Variables[name]
+		if !isVariable && !inputs[name] {
+			problems = append(problems, fmt.Sprintf("variable '%s' is not declared by the workflow", name))
+			continue
+		}
+		if isVariable {
+			result.Variables[name] = value
+		}
+		if inputs[name] {
+			params[name] = value
+		}
+	}
+
+	if len(problems) > 0 {
+		return workflow, parameters, fmt.Errorf("invalid overrides: %s", strings.Join(problems, "; "))
+	}
+	return result, params, nil
+}
+
+// overrideStep returns a copy of step with override applied
+func overrideStep(step types.Step, override types.StepOverride, reason string) (types.Step, error) {
+	if override.Skip {
+		if override.Image != "" || len(override.Env) > 0 {
+			return step, fmt.Errorf("a skipped step takes no other override")
+		}
+		step.SkipReason = fmt.Sprintf("skipped by admin override: %s", reason)
+		return step, nil
+	}
+	if override.Image == "" && len(override.Env) == 0 {
+		return step, fmt.Errorf("override changes nothing")
+	}
+
+	if override.Image != "" {
+		match := imageRefPattern.FindStringSubmatch(override.Image)
+		if match == nil {
+			return step, fmt.Errorf("image '%s' needs a repository and a tag or digest", override.Image)
+		}
+		repository := match[1]
+		// Only whole references to the same repository are replaced, not e.g. mirror/shop/api
+		pattern := regexp.MustCompile(`(^|[^A-Za-z0-9._/-])` + regexp.QuoteMeta(repository) + `(?::[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}|@sha256:[a-f0-9]{64})`)
+		replaced := 0
+		replace := func(s string) string {
+			return pattern.ReplaceAllStringFunc(s, func(ref string) string {
+				replaced++
+				prefix := pattern.FindStringSubmatch(ref)[1]
+				return prefix + override.Image
+			})
+		}
+		step.Path = replace(step.Path)
+		step.Env = replaceStrings(step.Env, replace)
+		step.Config = replaceValues(step.Config, replace).(map[string]interface{})
+		step.Variables = replaceValues(step.Variables, replace).(map[string]interface{})
+		if replaced == 0 {
+			return step, fmt.Errorf("step references no image of repository '%s'", repository)
+		}
+	}
+
+	if len(override.Env) > 0 {
+		env := make(map[string]string, len(step.Env)+len(override.Env))
+		for k, v := range step.Env {
+			env[k] = v
+		}
+		for k, v := range override.Env {
+			if k == "" {
+				return step, fmt.Errorf("env names must not be empty")
+			}
+			env[k] = v
+		}
+		step.Env = env
+	}
+	return step, nil
+}
+
+// replaceStrings returns a copy of m with replace applied to every value
+func replaceStrings(m map[string]string, replace func(string) string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = replace(v)
+	}
+	return result
+}
+
+// replaceValues returns a copy of v with replace applied to every string it contains
+func replaceValues(v interface{}, replace func(string) string) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if value == nil {
+			return value
+		}
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			result[k] = replaceValues(item, replace)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = replaceValues(item, replace)
+		}
+		return result
+	case string:
+		return replace(value)
+	default:
+		return v
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
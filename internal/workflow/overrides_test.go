@@ -0,0 +1,130 @@
+package workflow
+
+import (
+	"innominatus/internal/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func overridesTestWorkflow() types.Workflow {
+	return types.Workflow{
+		Variables: map[string]string{"replicas": "2"},
+		Inputs:    []types.WorkflowInput{{Name: "environment", Required: true}},
+		Steps: []types.Step{
+			{Name: "scan", Type: "policy"},
+			{
+				Name: "deploy",
+				Type: "kubernetes",
+				Env:  map[string]string{"IMAGE": "registry.example.com/shop/api:1.4.1"},
+				Config: map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"image": "registry.example.com/shop/api:1.4.1"},
+						map[string]interface{}{"image": "mirror/registry.example.com/shop/api:1.4.1"},
+						map[string]interface{}{"image": "registry.example.com/shop/api-worker:1.4.1"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestParseStepOverrides(t *testing.T) {
+	overrides, err := ParseStepOverrides([]byte(`{"reason":"INC-42","steps":{"scan":{"skip":true}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "INC-42", overrides.Reason)
+	assert.True(t, overrides.Steps["scan"].Skip)
+
+	_, err = ParseStepOverrides([]byte(`{"reason":"INC-42","steps":{"scan":{"skipped":true}}}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown field")
+}
+
+func TestApplyStepOverrides(t *testing.T) {
+	original := overridesTestWorkflow()
+	overrides := &types.StepOverrides{
+		Reason: "INC-42 hotfix",
+		Steps: map[string]types.StepOverride{
+			"scan":   {Skip: true},
+			"deploy": {Image: "registry.example.com/shop/api:1.4.2", Env: map[string]string{"LOG_LEVEL": "debug"}},
+		},
+		Variables: map[string]string{"replicas": "4", "environment": "production"},
+	}
+
+	result, params, err := ApplyStepOverrides(original, map[string]string{"environment": "staging"}, overrides)
+	require.NoError(t, err)
+
+	assert.Equal(t, "skipped by admin override: INC-42 hotfix", result.Steps[0].SkipReason)
+	deploy := result.Steps[1]
+	assert.Equal(t, "registry.example.com/shop/api:1.4.2", deploy.Env["IMAGE"])
+	assert.Equal(t, "debug", deploy.Env["LOG_LEVEL"])
+	containers := deploy.Config["containers"].([]interface{})
+	assert.Equal(t, "registry.example.com/shop/api:1.4.2", containers[0].(map[string]interface{})["image"])
+	assert.Equal(t, "mirror/registry.example.com/shop/api:1.4.1", containers[1].(map[string]interface{})["image"], "other repositories are kept")
+	assert.Equal(t, "registry.example.com/shop/api-worker:1.4.1", containers[2].(map[string]interface{})["image"], "other repositories are kept")
+	assert.Equal(t, "4", result.Variables["replicas"])
+	assert.Equal(t, "production", params["environment"])
+
+	// The original workflow is not modified
+	assert.Equal(t, overridesTestWorkflow(), original)
+}
+
+func TestApplyStepOverrides_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides types.StepOverrides
+		errorMsg  string
+	}{
+		{
+			name:      "no reason",
+			overrides: types.StepOverrides{Steps: map[string]types.StepOverride{"scan": {Skip: true}}},
+			errorMsg:  "overrides need a reason",
+		},
+		{
+			name:      "no change",
+			overrides: types.StepOverrides{Reason: "INC-42"},
+			errorMsg:  "overrides change no step or variable",
+		},
+		{
+			name:      "unknown step",
+			overrides: types.StepOverrides{Reason: "INC-42", Steps: map[string]types.StepOverride{"migrate": {Skip: true}}},
+			errorMsg:  "step 'migrate' does not exist",
+		},
+		{
+			name:      "skip with other changes",
+			overrides: types.StepOverrides{Reason: "INC-42", Steps: map[string]types.StepOverride{"deploy": {Skip: true, Image: "registry.example.com/shop/api:1.4.2"}}},
+			errorMsg:  "a skipped step takes no other override",
+		},
+		{
+			name:      "image without tag",
+			overrides: types.StepOverrides{Reason: "INC-42", Steps: map[string]types.StepOverride{"deploy": {Image: "registry.example.com/shop/api"}}},
+			errorMsg:  "needs a repository and a tag or digest",
+		},
+		{
+			name:      "image not referenced by the step",
+			overrides: types.StepOverrides{Reason: "INC-42", Steps: map[string]types.StepOverride{"deploy": {Image: "nginx:1.27"}}},
+			errorMsg:  "step references no image of repository 'nginx'",
+		},
+		{
+			name:      "undeclared variable",
+			overrides: types.StepOverrides{Reason: "INC-42", Variables: map[string]string{"region": "eu"}},
+			errorMsg:  "variable 'region' is not declared by the workflow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ApplyStepOverrides(overridesTestWorkflow(), nil, &tt.overrides)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
+
+func TestShouldExecuteStep_SkipReason(t *testing.T) {
+	ctx := NewExecutionContext()
+	shouldRun, reason := ctx.ShouldExecuteStep(types.Step{Name: "scan", When: "always", SkipReason: "skipped by admin override: INC-42"})
+	assert.False(t, shouldRun)
+	assert.Equal(t, "skipped by admin override: INC-42", reason)
+}
@@ -0,0 +1,217 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"innominatus/internal/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// policyStepQuery is the rego query of policy steps. Each policy is a package below
+// "workload" defining a "deny" set, like the Terraform plan policies below "terraform".
+const policyStepQuery = "data.workload"
+
+// DefaultPolicyDir holds the platform policies of policy steps when admin-config.yaml
+// lists none
+const DefaultPolicyDir = "policies/workload"
+
+// PolicySources are the rego policies every policy step evaluates (rego in admin-config.yaml)
+type PolicySources struct {
+	Paths   []string          // Rego files or directories
+	Modules map[string]string // Inline rego by module name, e.g. no-latest.rego
+}
+
+// PolicyInput is the document policy steps evaluate as rego input
+type PolicyInput struct {
+	Application string                   `json:"application"`
+	Spec        map[string]interface{}   `json:"spec"`      // Score spec with the keys of the Score file
+	Manifests   []map[string]interface{} `json:"manifests"` // Rendered Kubernetes objects
+	Parameters  map[string]string        `json:"parameters"`
+}
+
+// OrDefault returns the sources, or DefaultPolicyDir when none are configured and it exists
+func (s PolicySources) OrDefault() PolicySources {
+	if len(s.Paths) > 0 || len(s.Modules) > 0 {
+		return s
+	}
+	if _, err := os.Stat(DefaultPolicyDir); err != nil {
+		return s
+	}
+	return PolicySources{Paths: []string{DefaultPolicyDir}}
+}
+
+// SetPolicySources sets the rego policies policy steps evaluate in addition to the
+// policies of the step itself
+func (e *WorkflowExecutor) SetPolicySources(sources PolicySources) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.policySources = sources
+}
+
+// policyStep evaluates the rego policies of the platform and the step against the
+// application's Score spec and rendered manifests and returns the step logs
+func (e *WorkflowExecutor) policyStep(ctx context.Context, step types.Step, appName string) (string, error) {
+	e.mu.RLock()
+	sources := e.policySources
+	e.mu.RUnlock()
+
+	input := PolicyInput{
+		Application: appName,
+		Spec:        e.renderSpec(appName),
+		Parameters:  make(map[string]string, len(e.execContext.WorkflowVariables)),
+	}
+	for k, v := range e.execContext.WorkflowVariables {
+		input.Parameters[k] = v
+	}
+	return CheckPolicies(ctx, e.execContext.InterpolateResourceParams(step.Config, step.Env), sources, input, e.stepCommand)
+}
+
+// CheckPolicies runs a policy step: the platform sources (or DefaultPolicyDir) and the
+// step's 'policies' are evaluated against input, with the manifests of the step's
+// 'manifests' file or directory added. It fails with every violation and returns the step
+// logs. command creates the opa process and sets its environment, e.g. CommandWithEnvironment,
+// so that policies cannot read the server's credentials through opa.runtime().
+func CheckPolicies(ctx context.Context, config map[string]interface{}, sources PolicySources, input PolicyInput, command func(ctx context.Context, name string, args ...string) *exec.Cmd) (string, error) {
+	if command == nil {
+		return "", fmt.Errorf("policy check needs a command with the step environment")
+	}
+	sources = sources.OrDefault()
+	sources.Paths = append(append([]string{}, sources.Paths...), terraformPolicyPaths(types.Step{Config: config})...)
+
+	input.Manifests = []map[string]interface{}{}
+	if source := configString(config, "manifests"); source != "" {
+		manifests, err := loadPolicyManifests(source)
+		if err != nil {
+			return "", err
+		}
+		input.Manifests = manifests
+	}
+	if input.Spec == nil {
+		input.Spec = map[string]interface{}{}
+	}
+
+	violations, err := evaluatePolicies(ctx, sources, input, command)
+	if err != nil {
+		return "", err
+	}
+	return policyReport(violations, len(input.Manifests))
+}
+
+// evaluatePolicies evaluates rego policies against input with the opa CLI and returns
+// their violations
+func evaluatePolicies(ctx context.Context, sources PolicySources, input PolicyInput, command func(ctx context.Context, name string, args ...string) *exec.Cmd) ([]PolicyViolation, error) {
+	dir, err := os.MkdirTemp("", "policy-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create policy input directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	inputPath := filepath.Join(dir, "input.json")
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal policy input: %w", err)
+	}
+	if err := os.WriteFile(inputPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write policy input: %w", err)
+	}
+
+	paths := append([]string{}, sources.Paths...)
+	if len(sources.Modules) > 0 {
+		modulesDir := filepath.Join(dir, "modules")
+		if err := os.Mkdir(modulesDir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create policy module directory: %w", err)
+		}
+		for name, module := range sources.Modules {
+			file := filepath.Base(name)
+			if !strings.HasSuffix(file, ".rego") {
+				file += ".rego"
+			}
+			if err := os.WriteFile(filepath.Join(modulesDir, file), []byte(module), 0600); err != nil {
+				return nil, fmt.Errorf("failed to write policy module %s: %w", name, err)
+			}
+		}
+		paths = append(paths, modulesDir)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no policies configured: set rego in admin-config.yaml, add %s or set 'policies' on the step", DefaultPolicyDir)
+	}
+
+	args := []string{"eval", "--format", "json", "--input", inputPath}
+	for _, path := range paths {
+		args = append(args, "--data", path)
+	}
+	args = append(args, policyStepQuery)
+
+	// #nosec G204 -- policy paths come from admin-config.yaml and the workflow definition
+	cmd := command(ctx, "opa", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("opa eval failed: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("opa eval failed: %w", err)
+	}
+	return parseOPAViolations(output)
+}
+
+// loadPolicyManifests reads the Kubernetes objects of a manifest file or directory, for
+// example the output of a render step
+func loadPolicyManifests(source string) ([]map[string]interface{}, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+
+	var manifest string
+	if info.IsDir() {
+		if manifest, err = readManifestDir(source); err != nil {
+			return nil, err
+		}
+	} else {
+		content, err := os.ReadFile(source) // #nosec G304 - path comes from the workflow definition
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifests: %w", err)
+		}
+		manifest = string(content)
+	}
+
+	objects := []map[string]interface{}{}
+	decoder := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		var object map[string]interface{}
+		if err := decoder.Decode(&object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse manifests from %s: %w", source, err)
+		}
+		if len(object) > 0 {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+// policyReport returns the logs of a policy step and fails it when there are violations
+func policyReport(violations []PolicyViolation, manifests int) (string, error) {
+	if len(violations) == 0 {
+		return fmt.Sprintf("Score spec and %d manifest(s) passed all policies\n", manifests), nil
+	}
+
+	lines := make([]string, 0, len(violations))
+	for _, v := range violations {
+		lines = append(lines, v.String())
+	}
+	logs := fmt.Sprintf("Policies deny the deployment, %d violation(s):\n  %s\n", len(violations), strings.Join(lines, "\n  "))
+	return logs, fmt.Errorf("policies deny the deployment: %s", strings.Join(lines, "; "))
+}
@@ -0,0 +1,140 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"innominatus/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOPA records its input and data arguments next to itself and denies ':latest' images
+const fakeOPA = `#!/bin/sh
+[ "$1" = "eval" ] || { echo "unexpected: $*" >&2; exit 2; }
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --input) input="$2"; shift ;;
+    --data) data="$data $2"; shift ;;
+  esac
+  shift
+done
+dir=$(dirname "$0")
+cp "$input" "$dir/input.json"
+echo "$data" > "$dir/data.txt"
+if grep -q ':latest' "$input"; then
+  echo '{"result":[{"expressions":[{"value":{"image_tags":{"deny":[{"address":"Deployment/web","path":"containers.web.image","msg":"image nginx:latest is not pinned to a version"}]},"resource_limits":{"deny":[]}}}]}]}'
+else
+  echo '{"result":[{"expressions":[{"value":{"image_tags":{"deny":[]}}}]}]}'
+fi
+`
+
+func installFakeOPA(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "opa"), []byte(fakeOPA), 0755)) // #nosec G306 - test executable
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func writeManifest(t *testing.T, image string) string {
+	t.Helper()
+	dir := filepath.Join("workspaces", "shop", "rendered", "manifests")
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	manifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  template:\n    spec:\n      containers:\n        - name: web\n          image: " + image + "\n" +
+		"---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "web.yaml"), []byte(manifest), 0600))
+	return dir
+}
+
+func TestPolicyStep(t *testing.T) {
+	opaDir := installFakeOPA(t)
+	t.Chdir(t.TempDir())
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetApplicationStore(fakeApplicationStore{"shop": {Metadata: types.Metadata{Name: "shop"}}})
+	executor.SetPolicySources(PolicySources{Paths: []string{"platform/policies"}, Modules: map[string]string{"no-root": "package workload.no_root"}})
+
+	workflow := func(manifests string) types.Workflow {
+		return types.Workflow{Steps: []types.Step{{Name: "check", Type: "policy", Config: map[string]interface{}{
+			"manifests": manifests,
+			"policies":  "./team/policies",
+		}}}}
+	}
+
+	t.Run("violations fail the step", func(t *testing.T) {
+		dir := writeManifest(t, "nginx:latest")
+		err := executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", workflow(dir), map[string]string{"environment": "production"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "policies deny the deployment: [image_tags] Deployment/web.containers.web.image: image nginx:latest is not pinned to a version")
+
+		data, err := os.ReadFile(filepath.Join(opaDir, "input.json"))
+		require.NoError(t, err)
+		var input struct {
+			Application string                   `json:"application"`
+			Spec        map[string]interface{}   `json:"spec"`
+			Manifests   []map[string]interface{} `json:"manifests"`
+			Parameters  map[string]string        `json:"parameters"`
+		}
+		require.NoError(t, json.Unmarshal(data, &input))
+		assert.Equal(t, "shop", input.Application)
+		assert.Equal(t, map[string]interface{}{"name": "shop"}, input.Spec["metadata"])
+		require.Len(t, input.Manifests, 2)
+		assert.Equal(t, "Deployment", input.Manifests[0]["kind"])
+		assert.Equal(t, "production", input.Parameters["environment"])
+
+		args, err := os.ReadFile(filepath.Join(opaDir, "data.txt"))
+		require.NoError(t, err)
+		assert.Contains(t, string(args), "platform/policies ./team/policies ")
+		assert.Contains(t, string(args), "/modules")
+	})
+
+	t.Run("compliant manifests pass", func(t *testing.T) {
+		dir := writeManifest(t, "nginx:1.27")
+		require.NoError(t, executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", workflow(dir), nil))
+	})
+}
+
+func TestCheckPoliciesReport(t *testing.T) {
+	installFakeOPA(t)
+	t.Chdir(t.TempDir())
+	dir := writeManifest(t, "nginx:latest")
+
+	logs, err := CheckPolicies(context.Background(), map[string]interface{}{"manifests": dir}, PolicySources{Paths: []string{"p"}}, PolicyInput{Application: "shop"}, stepProcess)
+	require.Error(t, err)
+	assert.Equal(t, "Policies deny the deployment, 1 violation(s):\n  [image_tags] Deployment/web.containers.web.image: image nginx:latest is not pinned to a version\n", logs)
+
+	logs, err = CheckPolicies(context.Background(), nil, PolicySources{Paths: []string{"p"}}, PolicyInput{Application: "shop"}, stepProcess)
+	require.NoError(t, err)
+	assert.Equal(t, "Score spec and 0 manifest(s) passed all policies\n", logs)
+
+	_, err = CheckPolicies(context.Background(), nil, PolicySources{Paths: []string{"p"}}, PolicyInput{Application: "shop"}, nil)
+	assert.ErrorContains(t, err, "needs a command with the step environment")
+}
+
+func TestPolicySourcesOrDefault(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	_, err := CheckPolicies(context.Background(), nil, PolicySources{}, PolicyInput{}, stepProcess)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no policies configured")
+
+	require.NoError(t, os.MkdirAll(DefaultPolicyDir, 0700))
+	assert.Equal(t, PolicySources{Paths: []string{DefaultPolicyDir}}, PolicySources{}.OrDefault())
+
+	configured := PolicySources{Modules: map[string]string{"a": "package workload.a"}}
+	assert.Equal(t, configured, configured.OrDefault())
+}
+
+func TestValidatePolicyStep(t *testing.T) {
+	validator := NewWorkflowValidator()
+
+	assert.Empty(t, validator.validatePolicyStep(0, types.Step{Name: "rego", Config: map[string]interface{}{"manifests": "./out"}}))
+	assert.Len(t, validator.validatePolicyStep(0, types.Step{Name: "rego", Config: map[string]interface{}{"manifests": ""}}), 1)
+	assert.Len(t, validator.validatePolicyStep(0, types.Step{Name: "old", Config: map[string]interface{}{"command": "echo"}}), 1)
+	assert.Len(t, validator.validatePolicyStep(0, types.Step{Name: "script", Config: map[string]interface{}{"script": ""}}), 1)
+}
@@ -0,0 +1,342 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"innominatus/internal/types"
+	"innominatus/pkg/sdk"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Pre-flight check types
+const (
+	PreflightClusterReachable    = "cluster-reachable"
+	PreflightQuotaAvailable      = "quota-available"
+	PreflightProvidersRegistered = "providers-registered"
+	PreflightParametersValid     = "parameters-valid"
+	PreflightNamespaceFree       = "namespace-free"
+)
+
+const (
+	// preflightCheckTimeout bounds each check, so an unreachable cluster cannot stall a request
+	preflightCheckTimeout   = 20 * time.Second
+	preflightKubectlTimeout = "10s"
+)
+
+// PreflightTypes are the check types a workflow may declare
+var PreflightTypes = []string{
+	PreflightClusterReachable,
+	PreflightQuotaAvailable,
+	PreflightProvidersRegistered,
+	PreflightParametersValid,
+	PreflightNamespaceFree,
+}
+
+// ProviderLookup finds registered providers by name
+type ProviderLookup interface {
+	GetProvider(name string) (*sdk.Provider, error)
+}
+
+// PreflightResult is the outcome of one pre-flight check
+type PreflightResult struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// PreflightReport holds the outcome of every pre-flight check of a run
+type PreflightReport struct {
+	Passed  bool              `json:"passed"`
+	Results []PreflightResult `json:"results"`
+}
+
+// Failures returns the checks that did not pass
+func (r *PreflightReport) Failures() []PreflightResult {
+	var failures []PreflightResult
+	for _, result := range r.Results {
+		if !result.Passed {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// Preflight runs the pre-flight checks a workflow declares and reports every result, so a
+// run that would fail midway is rejected with all its problems at once. Required inputs
+// are always checked. Parameters are applied the way the run applies them.
+func (e *WorkflowExecutor) Preflight(ctx context.Context, wf types.Workflow, appName string, parameters map[string]string, providers ProviderLookup) *PreflightReport {
+	report := &PreflightReport{Passed: true, Results: []PreflightResult{}}
+	add := func(result PreflightResult) {
+		report.Passed = report.Passed && result.Passed
+		report.Results = append(report.Results, result)
+	}
+
+	applied, err := ApplyInputs(wf.Inputs, parameters)
+	if err != nil {
+		add(PreflightResult{Name: "inputs", Type: PreflightParametersValid, Message: err.Error()})
+		// Keep checking with what was passed; the run cannot start anyway
+		applied = parameters
+	} else if len(wf.Inputs) > 0 {
+		add(PreflightResult{Name: "inputs", Type: PreflightParametersValid, Passed: true, Message: "required inputs set"})
+	}
+
+	variables := NewExecutionContext()
+	variables.SetWorkflowVariables(wf.Variables)
+	variables.SetWorkflowVariables(applied)
+	noSystemEnv := func(string) string { return "" }
+	expand := func(value string) string {
+		return variables.replaceVariablesWith(value, nil, noSystemEnv)
+	}
+
+	for i, check := range wf.Preflight {
+		name := check.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-%d", check.Type, i+1)
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, preflightCheckTimeout)
+		message, err := e.runPreflightCheck(checkCtx, check, appName, applied, providers, expand)
+		cancel()
+
+		result := PreflightResult{Name: name, Type: check.Type, Passed: err == nil, Message: message}
+		if err != nil {
+			result.Message = err.Error()
+		}
+		add(result)
+	}
+
+	return report
+}
+
+func (e *WorkflowExecutor) runPreflightCheck(ctx context.Context, check types.PreflightCheck, appName string, parameters map[string]string, providers ProviderLookup, expand func(string) string) (string, error) {
+	kubeContext := expand(check.Context)
+	namespace := expand(check.Namespace)
+	if strings.Contains(kubeContext, "${") || strings.Contains(namespace, "${") {
+		return "", fmt.Errorf("unresolved parameter in context or namespace")
+	}
+
+	switch check.Type {
+	case PreflightClusterReachable:
+		if _, err := e.preflightKubectl(ctx, kubeContext, "get", "--raw", "/readyz"); err != nil {
+			return "", fmt.Errorf("cluster is not reachable: %w", err)
+		}
+		return "cluster API server is ready", nil
+	case PreflightQuotaAvailable:
+		needed := make(map[string]string, len(check.Quota))
+		for name, amount := range check.Quota {
+			needed[name] = expand(amount)
+		}
+		return e.checkQuotaAvailable(ctx, kubeContext, namespace, needed)
+	case PreflightProvidersRegistered:
+		return checkProvidersRegistered(check.Providers, providers)
+	case PreflightParametersValid:
+		return checkParameterPatterns(check.Parameters, parameters)
+	case PreflightNamespaceFree:
+		return e.checkNamespaceFree(ctx, kubeContext, namespace, appName)
+	default:
+		return "", fmt.Errorf("unknown pre-flight check type '%s'", check.Type)
+	}
+}
+
+// preflightKubectl runs a read-only kubectl command with a short request timeout
+func (e *WorkflowExecutor) preflightKubectl(ctx context.Context, kubeContext string, args ...string) ([]byte, error) {
+	args = append(args, "--request-timeout="+preflightKubectlTimeout)
+	if kubeContext != "" {
+		args = append(args, "--context", kubeContext)
+	}
+	// #nosec G204 - arguments come from the workflow definition
+	cmd := e.stepCommand(ctx, "kubectl", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// checkQuotaAvailable checks every resource quota of the namespace leaves the needed amounts
+func (e *WorkflowExecutor) checkQuotaAvailable(ctx context.Context, kubeContext, namespace string, needed map[string]string) (string, error) {
+	if namespace == "" {
+		return "", fmt.Errorf("namespace is required")
+	}
+
+	output, err := e.preflightKubectl(ctx, kubeContext, "get", "resourcequota", "-n", namespace, "-o", "json")
+	if err != nil {
+		return "", fmt.Errorf("failed to read resource quotas of %s: %w", namespace, err)
+	}
+	var quotas struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Status struct {
+				Hard map[string]string `json:"hard"`
+				Used map[string]string `json:"used"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &quotas); err != nil {
+		return "", fmt.Errorf("failed to parse resource quotas of %s: %w", namespace, err)
+	}
+
+	names := make([]string, 0, len(needed))
+	for name := range needed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		need, err := resource.ParseQuantity(needed[name])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid amount %q", name, needed[name]))
+			continue
+		}
+		for _, quota := range quotas.Items {
+			hard, limited := quota.Status.Hard[name]
+			if !limited {
+				continue
+			}
+			available, err := resource.ParseQuantity(hard)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: quota %s has invalid limit %q", name, quota.Metadata.Name, hard))
+				continue
+			}
+			if used, err := resource.ParseQuantity(quota.Status.Used[name]); err == nil {
+				available.Sub(used)
+			}
+			if available.Cmp(need) < 0 {
+				problems = append(problems, fmt.Sprintf("%s: needs %s, quota %s has %s left", name, need.String(), quota.Metadata.Name, available.String()))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return "", fmt.Errorf("insufficient quota in namespace %s: %s", namespace, strings.Join(problems, "; "))
+	}
+	return fmt.Sprintf("quota available in namespace %s", namespace), nil
+}
+
+// checkNamespaceFree checks the namespace does not exist or already belongs to the application
+func (e *WorkflowExecutor) checkNamespaceFree(ctx context.Context, kubeContext, namespace, appName string) (string, error) {
+	if namespace == "" {
+		return "", fmt.Errorf("namespace is required")
+	}
+
+	output, err := e.preflightKubectl(ctx, kubeContext, "get", "namespace", namespace, "-o", "json", "--ignore-not-found")
+	if err != nil {
+		return "", fmt.Errorf("failed to look up namespace %s: %w", namespace, err)
+	}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return fmt.Sprintf("namespace %s does not exist yet", namespace), nil
+	}
+
+	var ns struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(output, &ns); err != nil {
+		return "", fmt.Errorf("failed to parse namespace %s: %w", namespace, err)
+	}
+	if owner := ns.Metadata.Labels[ApplicationLabel]; owner != "" && owner == appName {
+		return fmt.Sprintf("namespace %s already belongs to %s", namespace, appName), nil
+	}
+	return "", fmt.Errorf("namespace %s already exists and does not belong to %s", namespace, appName)
+}
+
+func checkProvidersRegistered(names []string, providers ProviderLookup) (string, error) {
+	if len(names) == 0 {
+		return "", fmt.Errorf("providers are required")
+	}
+	if providers == nil {
+		return "", fmt.Errorf("provider registry is not available")
+	}
+
+	var missing []string
+	for _, name := range names {
+		if _, err := providers.GetProvider(name); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("providers not registered: %s", strings.Join(missing, ", "))
+	}
+	return fmt.Sprintf("providers registered: %s", strings.Join(names, ", ")), nil
+}
+
+// checkParameterPatterns checks each listed parameter is set and fully matches its pattern
+func checkParameterPatterns(patterns map[string]string, parameters map[string]string) (string, error) {
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		value, ok := parameters[name]
+		if !ok || value == "" {
+			problems = append(problems, fmt.Sprintf("%s is not set", name))
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + patterns[name] + ")$")
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid pattern: %v", name, err))
+			continue
+		}
+		if !re.MatchString(value) {
+			problems = append(problems, fmt.Sprintf("%s=%q does not match %s", name, value, patterns[name]))
+		}
+	}
+
+	if len(problems) > 0 {
+		return "", fmt.Errorf("invalid parameters: %s", strings.Join(problems, "; "))
+	}
+	return "parameters valid", nil
+}
+
+// validatePreflight validates the declared pre-flight checks of a workflow
+func validatePreflight(workflow *types.Workflow) []error {
+	var errors []error
+	for i, check := range workflow.Preflight {
+		switch check.Type {
+		case PreflightClusterReachable:
+		case PreflightQuotaAvailable:
+			if check.Namespace == "" || len(check.Quota) == 0 {
+				errors = append(errors, fmt.Errorf("preflight[%d]: quota-available requires namespace and quota", i))
+			}
+			for name, amount := range check.Quota {
+				if _, err := resource.ParseQuantity(amount); err != nil && !strings.Contains(amount, "${") {
+					errors = append(errors, fmt.Errorf("preflight[%d]: invalid quota amount %q for %s", i, amount, name))
+				}
+			}
+		case PreflightProvidersRegistered:
+			if len(check.Providers) == 0 {
+				errors = append(errors, fmt.Errorf("preflight[%d]: providers-registered requires providers", i))
+			}
+		case PreflightParametersValid:
+			for name, pattern := range check.Parameters {
+				if _, err := regexp.Compile(pattern); err != nil {
+					errors = append(errors, fmt.Errorf("preflight[%d]: invalid pattern for %s: %v", i, name, err))
+				}
+			}
+		case PreflightNamespaceFree:
+			if check.Namespace == "" {
+				errors = append(errors, fmt.Errorf("preflight[%d]: namespace-free requires namespace", i))
+			}
+		default:
+			errors = append(errors, fmt.Errorf("preflight[%d]: type must be one of %v, got '%s'", i, PreflightTypes, check.Type))
+		}
+	}
+	return errors
+}
@@ -0,0 +1,135 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"innominatus/internal/types"
+	"innominatus/pkg/sdk"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKubectl answers the read-only commands pre-flight checks run
+const fakeKubectl = `#!/bin/sh
+case "$*" in
+  "get --raw /readyz --request-timeout=10s --context broken") echo "connection refused" >&2; exit 1 ;;
+  "get --raw /readyz"*) echo ok ;;
+  "get resourcequota -n team-a"*) echo '{"items":[{"metadata":{"name":"compute"},"status":{"hard":{"requests.cpu":"4","pods":"10"},"used":{"requests.cpu":"3500m","pods":"2"}}}]}' ;;
+  "get resourcequota"*) echo '{"items":[]}' ;;
+  "get namespace shop -o json"*) echo '{"metadata":{"labels":{"innominatus.io/application":"shop"}}}' ;;
+  "get namespace kube-system -o json"*) echo '{"metadata":{"labels":{}}}' ;;
+  "get namespace"*) ;;
+  *) echo "unexpected: $*" >&2; exit 2 ;;
+esac
+`
+
+type fakeProviders map[string]bool
+
+func (p fakeProviders) GetProvider(name string) (*sdk.Provider, error) {
+	if !p[name] {
+		return nil, fmt.Errorf("provider '%s' not found", name)
+	}
+	return &sdk.Provider{Metadata: sdk.ProviderMetadata{Name: name}}, nil
+}
+
+func installFakeKubectl(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubectl"), []byte(fakeKubectl), 0755)) // #nosec G306 - test executable
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestPreflight(t *testing.T) {
+	installFakeKubectl(t)
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	providers := fakeProviders{"database-team": true}
+
+	tests := []struct {
+		name    string
+		check   types.PreflightCheck
+		params  map[string]string
+		wantErr string
+	}{
+		{name: "cluster reachable", check: types.PreflightCheck{Type: PreflightClusterReachable, Context: "${workflow.kube_context}"}, params: map[string]string{"kube_context": "dev"}},
+		{name: "cluster unreachable", check: types.PreflightCheck{Type: PreflightClusterReachable, Context: "${workflow.kube_context}"}, params: map[string]string{"kube_context": "broken"}, wantErr: "connection refused"},
+		{name: "quota available", check: types.PreflightCheck{Type: PreflightQuotaAvailable, Namespace: "team-a", Quota: map[string]string{"requests.cpu": "500m", "pods": "8", "requests.memory": "64Gi"}}},
+		{name: "quota exceeded", check: types.PreflightCheck{Type: PreflightQuotaAvailable, Namespace: "team-a", Quota: map[string]string{"requests.cpu": "1"}}, wantErr: "requests.cpu: needs 1, quota compute has 500m left"},
+		{name: "namespace without quota", check: types.PreflightCheck{Type: PreflightQuotaAvailable, Namespace: "team-b", Quota: map[string]string{"requests.cpu": "64"}}},
+		{name: "providers registered", check: types.PreflightCheck{Type: PreflightProvidersRegistered, Providers: []string{"database-team"}}},
+		{name: "provider missing", check: types.PreflightCheck{Type: PreflightProvidersRegistered, Providers: []string{"database-team", "gitops"}}, wantErr: "providers not registered: gitops"},
+		{name: "parameters valid", check: types.PreflightCheck{Type: PreflightParametersValid, Parameters: map[string]string{"environment": "dev|staging"}}, params: map[string]string{"environment": "staging"}},
+		{name: "parameter invalid", check: types.PreflightCheck{Type: PreflightParametersValid, Parameters: map[string]string{"environment": "dev|staging"}}, params: map[string]string{"environment": "staging-2"}, wantErr: `environment="staging-2" does not match dev|staging`},
+		{name: "namespace does not exist", check: types.PreflightCheck{Type: PreflightNamespaceFree, Namespace: "shop-new"}},
+		{name: "namespace owned by the application", check: types.PreflightCheck{Type: PreflightNamespaceFree, Namespace: "shop"}},
+		{name: "namespace taken", check: types.PreflightCheck{Type: PreflightNamespaceFree, Namespace: "kube-system"}, wantErr: "already exists and does not belong to shop"},
+		{name: "unresolved namespace", check: types.PreflightCheck{Type: PreflightNamespaceFree, Namespace: "${workflow.namespace}"}, wantErr: "unresolved parameter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := types.Workflow{Preflight: []types.PreflightCheck{tt.check}}
+			report := executor.Preflight(context.Background(), wf, "shop", tt.params, providers)
+			require.Len(t, report.Results, 1)
+			result := report.Results[0]
+			assert.Equal(t, tt.wantErr == "", report.Passed)
+			assert.Equal(t, tt.wantErr == "", result.Passed, result.Message)
+			if tt.wantErr != "" {
+				assert.Contains(t, result.Message, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPreflightReportsAllFailures(t *testing.T) {
+	installFakeKubectl(t)
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+
+	wf := types.Workflow{
+		Inputs: []types.WorkflowInput{
+			{Name: "kube_context", Required: true},
+			{Name: "namespace", Default: "kube-system"},
+		},
+		Preflight: []types.PreflightCheck{
+			{Name: "cluster", Type: PreflightClusterReachable},
+			{Name: "providers", Type: PreflightProvidersRegistered, Providers: []string{"gitops"}},
+			{Type: PreflightNamespaceFree, Namespace: "${workflow.namespace}"},
+		},
+	}
+
+	report := executor.Preflight(context.Background(), wf, "shop", nil, nil)
+	assert.False(t, report.Passed)
+
+	var failed []string
+	for _, failure := range report.Failures() {
+		failed = append(failed, failure.Name)
+	}
+	assert.Equal(t, []string{"inputs", "providers", "namespace-free-3"}, failed)
+	assert.Contains(t, report.Results[0].Message, "kube_context")
+	assert.True(t, report.Results[1].Passed)
+}
+
+func TestValidatePreflight(t *testing.T) {
+	wf := &types.Workflow{Preflight: []types.PreflightCheck{
+		{Type: PreflightClusterReachable},
+		{Type: PreflightQuotaAvailable, Namespace: "team-a"},
+		{Type: PreflightQuotaAvailable, Namespace: "team-a", Quota: map[string]string{"requests.cpu": "lots", "pods": "${workflow.pods}"}},
+		{Type: PreflightProvidersRegistered},
+		{Type: PreflightParametersValid, Parameters: map[string]string{"env": "("}},
+		{Type: PreflightNamespaceFree},
+		{Type: "disk-free"},
+	}}
+
+	errs := validatePreflight(wf)
+	require.Len(t, errs, 6)
+	assert.Contains(t, errs[0].Error(), "preflight[1]: quota-available requires namespace and quota")
+	assert.Contains(t, errs[1].Error(), `preflight[2]: invalid quota amount "lots"`)
+	assert.Contains(t, errs[2].Error(), "preflight[3]: providers-registered requires providers")
+	assert.Contains(t, errs[3].Error(), "preflight[4]: invalid pattern for env")
+	assert.Contains(t, errs[4].Error(), "preflight[5]: namespace-free requires namespace")
+	assert.Contains(t, errs[5].Error(), "preflight[6]: type must be one of")
+}
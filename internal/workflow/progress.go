@@ -0,0 +1,252 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/clock"
+	"innominatus/internal/database"
+	"innominatus/internal/events"
+	"innominatus/internal/types"
+	"sync"
+	"time"
+)
+
+const (
+	// progressHistoryRuns is how many recent successful runs of the same workflow step
+	// estimates come from
+	progressHistoryRuns = 20
+	// progressTypeHistoryRuns is how many recent successful runs of any workflow estimates
+	// by step type come from, for steps the workflow has not completed before
+	progressTypeHistoryRuns = 200
+	// progressInterval is how often progress is published while a step runs
+	progressInterval = 15 * time.Second
+	// defaultStepEstimate is the expected duration of a step without history or default
+	defaultStepEstimate = time.Minute
+	// overrunFraction is how far a step running longer than expected counts as done
+	overrunFraction = 0.95
+)
+
+// Progress basis: where the step estimates of a progress come from
+const (
+	ProgressBasisHistory  = "history"
+	ProgressBasisPartial  = "partial"
+	ProgressBasisDefaults = "defaults"
+)
+
+// stepDurationStatter is implemented by repositories that know the durations of past steps
+type stepDurationStatter interface {
+	StepDurationStats(workflowName string, workflowRuns, allRuns int) (*database.StepDurationStats, error)
+}
+
+// stepEstimates are the expected durations of the steps of a workflow
+type stepEstimates struct {
+	byName map[string]time.Duration
+	byType map[string]time.Duration
+}
+
+// loadStepEstimates reads the step durations of recent runs; without them, estimates
+// fall back to the analyzer's defaults per step type
+func (e *WorkflowExecutor) loadStepEstimates(workflowName string) stepEstimates {
+	estimates := stepEstimates{byName: map[string]time.Duration{}, byType: map[string]time.Duration{}}
+	statter, ok := e.repo.(stepDurationStatter)
+	if !ok {
+		return estimates
+	}
+	stats, err := statter.StepDurationStats(workflowName, progressHistoryRuns, progressTypeHistoryRuns)
+	if err != nil {
+		e.logger.WarnWithFields("Failed to load step durations for progress estimates", map[string]interface{}{
+			"workflow_name": workflowName,
+			"error":         err.Error(),
+		})
+		return estimates
+	}
+	for name, ms := range stats.ByName {
+		estimates.byName[name] = time.Duration(ms) * time.Millisecond
+	}
+	for stepType, ms := range stats.ByType {
+		estimates.byType[stepType] = time.Duration(ms) * time.Millisecond
+	}
+	return estimates
+}
+
+// expected returns the expected duration of a step and whether it comes from past runs
+func (s stepEstimates) expected(name, stepType string) (time.Duration, bool) {
+	if d, ok := s.byName[name]; ok && d > 0 {
+		return d, true
+	}
+	if d, ok := s.byType[stepType]; ok && d > 0 {
+		return d, true
+	}
+	if d, ok := getDefaultStepDurations()[stepType]; ok {
+		return d, false
+	}
+	return defaultStepEstimate, false
+}
+
+// estimateProgress weights every step by its expected duration. Finished steps count
+// fully, the running step by its elapsed time, up to overrunFraction once it takes
+// longer than expected. Running executions stay below 100%.
+func estimateProgress(status string, startedAt time.Time, steps []*database.WorkflowStepExecution, estimates stepEstimates, now time.Time) *database.WorkflowProgress {
+	progress := &database.WorkflowProgress{
+		TotalSteps:     len(steps),
+		ElapsedSeconds: int64(now.Sub(startedAt).Seconds()),
+	}
+
+	var total, done, remaining time.Duration
+	fromHistory := 0
+	for _, step := range steps {
+		expected, historic := estimates.expected(step.StepName, step.StepType)
+		if historic {
+			fromHistory++
+		}
+		total += expected
+
+		switch step.Status {
+		case database.StepStatusCompleted, "skipped":
+			progress.CompletedSteps++
+			done += expected
+		case database.StepStatusFailed:
+			done += expected
+		case database.StepStatusRunning:
+			progress.CurrentStep = step.StepName
+			elapsed := time.Duration(0)
+			if step.StartedAt != nil {
+				elapsed = now.Sub(*step.StartedAt)
+			}
+			if elapsed < expected {
+				done += elapsed
+				remaining += expected - elapsed
+			} else {
+				done += time.Duration(float64(expected) * overrunFraction)
+				remaining += time.Duration(float64(expected) * (1 - overrunFraction))
+			}
+		default:
+			remaining += expected
+		}
+	}
+
+	switch {
+	case fromHistory == len(steps) && len(steps) > 0:
+		progress.Basis = ProgressBasisHistory
+	case fromHistory > 0:
+		progress.Basis = ProgressBasisPartial
+	default:
+		progress.Basis = ProgressBasisDefaults
+	}
+
+	if total > 0 {
+		progress.Percent = int(float64(done) / float64(total) * 100)
+	}
+	switch status {
+	case database.WorkflowStatusCompleted:
+		progress.Percent = 100
+	case database.WorkflowStatusRunning:
+		if progress.Percent > 99 {
+			progress.Percent = 99
+		}
+		seconds := int64(remaining.Round(time.Second).Seconds())
+		eta := now.Add(remaining).UTC().Truncate(time.Second)
+		progress.RemainingSeconds = &seconds
+		progress.ETA = &eta
+	}
+	return progress
+}
+
+// Progress estimates the progress of an execution and, while it runs, its remaining
+// time. The execution must include its steps.
+func (e *WorkflowExecutor) Progress(execution *database.WorkflowExecution) *database.WorkflowProgress {
+	estimates := stepEstimates{}
+	if execution.Status == database.WorkflowStatusRunning {
+		estimates = e.loadStepEstimates(execution.WorkflowName)
+	}
+	return estimateProgress(execution.Status, execution.StartedAt, execution.Steps, estimates, clock.OrReal(e.clock).Now())
+}
+
+// progressTracker follows the steps of a running execution and publishes its progress
+type progressTracker struct {
+	mu           sync.Mutex // Guards steps; the ticker of whileRunning publishes concurrently
+	executor     *WorkflowExecutor
+	appName      string
+	workflowName string
+	executionID  int64
+	startedAt    time.Time
+	steps        []*database.WorkflowStepExecution
+	estimates    stepEstimates
+}
+
+// newProgressTracker tracks the steps of an execution, all pending
+func (e *WorkflowExecutor) newProgressTracker(appName, workflowName string, execution *database.WorkflowExecution, steps []types.Step) *progressTracker {
+	tracker := &progressTracker{
+		executor:     e,
+		appName:      appName,
+		workflowName: workflowName,
+		executionID:  execution.ID,
+		startedAt:    execution.StartedAt,
+		estimates:    e.loadStepEstimates(workflowName),
+	}
+	for _, step := range steps {
+		tracker.steps = append(tracker.steps, &database.WorkflowStepExecution{StepName: step.Name, StepType: step.Type, Status: database.StepStatusPending})
+	}
+	return tracker
+}
+
+// setStatus records the status of the step at index and publishes the new progress
+func (t *progressTracker) setStatus(index int, status string) {
+	t.mu.Lock()
+	step := t.steps[index]
+	step.Status = status
+	if status == database.StepStatusRunning {
+		now := clock.OrReal(t.executor.clock).Now()
+		step.StartedAt = &now
+	}
+	t.mu.Unlock()
+	t.publish()
+}
+
+// publish sends a workflow.progress event for the running execution
+func (t *progressTracker) publish() {
+	if t.executor.eventBus == nil {
+		return
+	}
+	t.mu.Lock()
+	progress := estimateProgress(database.WorkflowStatusRunning, t.startedAt, t.steps, t.estimates, clock.OrReal(t.executor.clock).Now())
+	t.mu.Unlock()
+	data := map[string]interface{}{
+		"workflow_name":   t.workflowName,
+		"execution_id":    t.executionID,
+		"percent":         progress.Percent,
+		"completed_steps": progress.CompletedSteps,
+		"total_steps":     progress.TotalSteps,
+		"current_step":    progress.CurrentStep,
+		"elapsed_seconds": progress.ElapsedSeconds,
+		"basis":           progress.Basis,
+	}
+	if progress.RemainingSeconds != nil {
+		data["remaining_seconds"] = *progress.RemainingSeconds
+		data["eta"] = progress.ETA.Format(time.RFC3339)
+	}
+	t.executor.eventBus.Publish(events.NewEvent(events.EventTypeWorkflowProgress, t.appName, "workflow-executor", data))
+}
+
+// whileRunning publishes the progress every progressInterval until the returned stop
+// function is called, so long steps keep the estimate moving
+func (t *progressTracker) whileRunning(ctx context.Context) func() {
+	if t.executor.eventBus == nil {
+		return func() {}
+	}
+	ticker := clock.OrReal(t.executor.clock).NewTicker("workflow-progress", progressInterval)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C():
+				t.publish()
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,137 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/database"
+	"innominatus/internal/events"
+	"innominatus/internal/types"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateProgress(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	stepStarted := now.Add(-30 * time.Second)
+	estimates := stepEstimates{
+		byName: map[string]time.Duration{"plan": time.Minute, "apply": 2 * time.Minute},
+		byType: map[string]time.Duration{},
+	}
+	steps := []*database.WorkflowStepExecution{
+		{StepName: "plan", StepType: "terraform", Status: database.StepStatusCompleted},
+		{StepName: "apply", StepType: "terraform", Status: database.StepStatusRunning, StartedAt: &stepStarted},
+		{StepName: "notify", StepType: "unknown-type", Status: database.StepStatusPending},
+	}
+
+	progress := estimateProgress(database.WorkflowStatusRunning, now.Add(-90*time.Second), steps, estimates, now)
+
+	// 1m done + 30s of 2m running, out of 1m + 2m + 1m default
+	assert.Equal(t, 37, progress.Percent)
+	assert.Equal(t, 1, progress.CompletedSteps)
+	assert.Equal(t, 3, progress.TotalSteps)
+	assert.Equal(t, "apply", progress.CurrentStep)
+	assert.Equal(t, int64(90), progress.ElapsedSeconds)
+	require.NotNil(t, progress.RemainingSeconds)
+	assert.Equal(t, int64(150), *progress.RemainingSeconds)
+	assert.Equal(t, now.Add(150*time.Second), *progress.ETA)
+	assert.Equal(t, ProgressBasisPartial, progress.Basis)
+}
+
+func TestEstimateProgress_Overrun(t *testing.T) {
+	now := time.Now()
+	started := now.Add(-10 * time.Minute)
+	steps := []*database.WorkflowStepExecution{
+		{StepName: "apply", StepType: "terraform", Status: database.StepStatusRunning, StartedAt: &started},
+	}
+	estimates := stepEstimates{byName: map[string]time.Duration{"apply": time.Minute}}
+
+	progress := estimateProgress(database.WorkflowStatusRunning, started, steps, estimates, now)
+	assert.Equal(t, 95, progress.Percent, "a step over its estimate does not reach 100%")
+	assert.Equal(t, int64(3), *progress.RemainingSeconds)
+	assert.Equal(t, ProgressBasisHistory, progress.Basis)
+}
+
+func TestEstimateProgress_Ended(t *testing.T) {
+	now := time.Now()
+	steps := []*database.WorkflowStepExecution{
+		{StepName: "a", StepType: "validation", Status: database.StepStatusCompleted},
+		{StepName: "b", StepType: "validation", Status: database.StepStatusFailed},
+		{StepName: "c", StepType: "validation", Status: database.StepStatusPending},
+	}
+
+	failed := estimateProgress(database.WorkflowStatusFailed, now, steps, stepEstimates{}, now)
+	assert.Equal(t, 66, failed.Percent)
+	assert.Nil(t, failed.RemainingSeconds)
+	assert.Nil(t, failed.ETA)
+	assert.Equal(t, ProgressBasisDefaults, failed.Basis)
+
+	completed := estimateProgress(database.WorkflowStatusCompleted, now, steps[:1], stepEstimates{}, now)
+	assert.Equal(t, 100, completed.Percent)
+}
+
+// statsRepository returns fixed step durations
+type statsRepository struct {
+	*MockWorkflowRepository
+	stats *database.StepDurationStats
+}
+
+func (r *statsRepository) StepDurationStats(workflowName string, workflowRuns, allRuns int) (*database.StepDurationStats, error) {
+	return r.stats, nil
+}
+
+// recordingBus keeps published events
+type recordingBus struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (b *recordingBus) Publish(event events.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, event)
+}
+func (b *recordingBus) Subscribe(string, []events.EventType, events.EventHandler) string { return "" }
+func (b *recordingBus) Unsubscribe(string)                                               {}
+func (b *recordingBus) Close()                                                           {}
+
+func (b *recordingBus) ofType(eventType events.EventType) []events.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var matching []events.Event
+	for _, e := range b.events {
+		if e.Type == eventType {
+			matching = append(matching, e)
+		}
+	}
+	return matching
+}
+
+func TestExecuteWorkflow_PublishesProgress(t *testing.T) {
+	repo := &statsRepository{
+		MockWorkflowRepository: NewMockWorkflowRepository(),
+		stats:                  &database.StepDurationStats{ByName: map[string]int64{"first": 1000, "second": 3000}},
+	}
+	executor := NewWorkflowExecutor(repo)
+	bus := &recordingBus{}
+	executor.SetEventBus(bus)
+	executor.RegisterStepExecutor("noop", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+		return nil
+	})
+
+	err := executor.ExecuteWorkflowWithName("shop", "deploy", types.Workflow{Steps: []types.Step{
+		{Name: "first", Type: "noop"},
+		{Name: "second", Type: "noop"},
+	}})
+	require.NoError(t, err)
+
+	progress := bus.ofType(events.EventTypeWorkflowProgress)
+	require.Len(t, progress, 4, "one event per step start and completion")
+	assert.Equal(t, "first", progress[0].Data["current_step"])
+	assert.Equal(t, 25, progress[1].Data["percent"], "first step is 1s of 4s")
+	assert.Equal(t, ProgressBasisHistory, progress[1].Data["basis"])
+	assert.Equal(t, 99, progress[3].Data["percent"], "the run is not done before workflow.completed")
+	assert.Contains(t, progress[1].Data, "eta")
+}
@@ -0,0 +1,482 @@
+package workflow
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/admin"
+	"innominatus/internal/logging"
+	"innominatus/internal/types"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Render engines supported by the render step
+const (
+	RenderEngineGoTemplate = "go-template"
+	RenderEngineJsonnet    = "jsonnet"
+	RenderEngineKustomize  = "kustomize"
+)
+
+// builtinSourcePrefix selects a template set shipped with the server instead of a directory
+const builtinSourcePrefix = "builtin:"
+
+// builtinTemplates are the default manifests used when a workflow brings no templates
+//
+//go:embed templates
+var builtinTemplates embed.FS
+
+// renderFuncs are the functions available to Go templates
+var renderFuncs = template.FuncMap{
+	"default": func(def, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return def
+		}
+		return value
+	},
+	"quote": func(value interface{}) string { return fmt.Sprintf("%q", fmt.Sprint(value)) },
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"indent": func(spaces int, value string) string {
+		pad := strings.Repeat(" ", spaces)
+		return pad + strings.ReplaceAll(value, "\n", "\n"+pad)
+	},
+	"toYaml": func(value interface{}) (string, error) {
+		data, err := yaml.Marshal(value)
+		return strings.TrimSuffix(string(data), "\n"), err
+	},
+	"toJson": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+// renderData returns the inputs templates are rendered with: the application name, its
+// Score spec, the workflow parameters and the outputs of previous steps
+func (e *WorkflowExecutor) renderData(appName string) map[string]interface{} {
+	params := make(map[string]string, len(e.execContext.WorkflowVariables))
+	for k, v := range e.execContext.WorkflowVariables {
+		params[k] = v
+	}
+
+	return map[string]interface{}{
+		"app":     appName,
+		"spec":    e.renderSpec(appName),
+		"params":  params,
+		"outputs": e.execContext.StepOutputs(),
+	}
+}
+
+// renderSpec returns the stored Score spec of an application with the keys of the Score
+// file, or an empty map when the application has none
+func (e *WorkflowExecutor) renderSpec(appName string) map[string]interface{} {
+	spec := map[string]interface{}{}
+	if e.applications == nil {
+		return spec
+	}
+	app, err := e.applications.GetApplication(appName)
+	if err != nil || app == nil || app.ScoreSpec == nil {
+		return spec
+	}
+
+	data, err := yaml.Marshal(app.ScoreSpec)
+	if err != nil {
+		return spec
+	}
+	_ = yaml.Unmarshal(data, &spec)
+	return spec
+}
+
+// renderStep renders the templates of a render step and writes them to the application
+// workspace, or commits them to a GitOps repository when repoName is set. It returns the
+// step logs.
+func (e *WorkflowExecutor) renderStep(ctx context.Context, step types.Step, appName string) (string, error) {
+	logger := logging.FromContext(ctx, "workflow")
+	config := e.execContext.InterpolateResourceParams(step.Config, step.Env)
+
+	engine := configString(config, "engine")
+	if engine == "" {
+		engine = RenderEngineGoTemplate
+	}
+	source := configString(config, "source")
+	if source == "" {
+		return "", fmt.Errorf("render step requires 'source' in config")
+	}
+
+	logger.Infof("Rendering %s templates from %s", engine, source)
+	files, err := e.renderSource(ctx, engine, source, configString(config, "overlay"), e.renderData(appName))
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var logs strings.Builder
+	fmt.Fprintf(&logs, "Rendered %d file(s) with %s from %s:\n", len(names), engine, source)
+	for _, name := range names {
+		fmt.Fprintf(&logs, "  %s (%d bytes)\n", name, len(files[name]))
+	}
+
+	if step.RepoName != "" {
+		committed, err := publishRenderedFiles(ctx, step, appName, files)
+		if err != nil {
+			return logs.String(), err
+		}
+		if committed {
+			fmt.Fprintf(&logs, "Committed to repository %s\n", step.RepoName)
+		} else {
+			fmt.Fprintf(&logs, "Repository %s is up to date\n", step.RepoName)
+		}
+		e.execContext.SetStepOutput(step.Name, "repository", step.RepoName)
+	} else {
+		outputDir, err := renderOutputDir(appName, step.Name, configString(config, "output"))
+		if err != nil {
+			return logs.String(), err
+		}
+		// Files of a previous render must not survive a template that was removed
+		if err := os.RemoveAll(outputDir); err != nil {
+			return logs.String(), fmt.Errorf("failed to clear %s: %w", outputDir, err)
+		}
+		if err := writeRenderedFiles(outputDir, files); err != nil {
+			return logs.String(), err
+		}
+		fmt.Fprintf(&logs, "Written to %s\n", outputDir)
+		e.execContext.SetStepOutput(step.Name, "output_dir", outputDir)
+	}
+
+	e.execContext.SetStepOutput(step.Name, "files", strings.Join(names, ","))
+	e.execContext.SetStepOutput(step.Name, "file_count", fmt.Sprint(len(names)))
+	return logs.String(), nil
+}
+
+// renderOutputDir returns where a render step writes into the application workspace. The
+// output must stay inside the workspace because it is cleared before each render.
+func renderOutputDir(appName, stepName, output string) (string, error) {
+	if output == "" {
+		output = path.Join("rendered", stepName)
+	}
+	cleaned := filepath.Clean(output)
+	if filepath.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("render output '%s' must be a directory inside the application workspace", output)
+	}
+	return filepath.Join("workspaces", appName, cleaned), nil
+}
+
+// renderSource renders the templates of source with the given engine and returns the
+// rendered files by path
+func (e *WorkflowExecutor) renderSource(ctx context.Context, engine, source, overlay string, data map[string]interface{}) (map[string][]byte, error) {
+	switch engine {
+	case RenderEngineGoTemplate:
+		fsys, err := renderSourceFS(source)
+		if err != nil {
+			return nil, err
+		}
+		return renderGoTemplates(fsys, data)
+
+	case RenderEngineJsonnet:
+		return e.renderJsonnet(ctx, source, data)
+
+	case RenderEngineKustomize:
+		fsys, err := renderSourceFS(source)
+		if err != nil {
+			return nil, err
+		}
+		// Bases and overlays are templates too; render the whole tree so relative
+		// references between them keep working
+		files, err := renderGoTemplates(fsys, data)
+		if err != nil {
+			return nil, err
+		}
+		tmpDir, err := os.MkdirTemp("", "render-kustomize-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kustomize directory: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		if err := writeRenderedFiles(tmpDir, files); err != nil {
+			return nil, err
+		}
+
+		output, err := e.stepCommand(ctx, "kubectl", "kustomize", filepath.Join(tmpDir, filepath.Clean("/"+overlay))).Output()
+		if err != nil {
+			return nil, fmt.Errorf("kubectl kustomize failed: %w%s", err, commandStderr(err))
+		}
+		return map[string][]byte{"manifests.yaml": output}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown render engine '%s' (valid engines: %s, %s, %s)",
+			engine, RenderEngineGoTemplate, RenderEngineJsonnet, RenderEngineKustomize)
+	}
+}
+
+// renderSourceFS returns the template directory of a source: a builtin template set or a
+// directory on disk
+func renderSourceFS(source string) (fs.FS, error) {
+	if name, ok := strings.CutPrefix(source, builtinSourcePrefix); ok {
+		fsys, err := fs.Sub(builtinTemplates, path.Join("templates", name))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fs.Stat(fsys, "."); err != nil {
+			return nil, fmt.Errorf("unknown builtin templates '%s'", name)
+		}
+		return fsys, nil
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("render source: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("render source '%s' must be a directory", source)
+	}
+	return os.DirFS(source), nil
+}
+
+// renderGoTemplates renders every file of fsys as a Go template. A ".tmpl" suffix is
+// dropped from the rendered file name.
+func renderGoTemplates(fsys fs.FS, data map[string]interface{}) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(name).Funcs(renderFuncs).Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", name, err)
+		}
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("failed to render template %s: %w", name, err)
+		}
+		files[strings.TrimSuffix(name, ".tmpl")] = []byte(buf.String())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// renderBuiltin renders a builtin template set and joins the files into one manifest
+func renderBuiltin(name string, data map[string]interface{}) (string, error) {
+	fsys, err := renderSourceFS(builtinSourcePrefix + name)
+	if err != nil {
+		return "", err
+	}
+	files, err := renderGoTemplates(fsys, data)
+	if err != nil {
+		return "", err
+	}
+	return joinManifests(files), nil
+}
+
+// renderJsonnet evaluates every .jsonnet file of source with the jsonnet CLI. The spec,
+// params and outputs are passed as external code, the application name as std.extVar("app").
+func (e *WorkflowExecutor) renderJsonnet(ctx context.Context, source string, data map[string]interface{}) (map[string][]byte, error) {
+	if strings.HasPrefix(source, builtinSourcePrefix) {
+		return nil, fmt.Errorf("jsonnet sources must be directories on disk")
+	}
+
+	args := []string{"--ext-str", fmt.Sprintf("app=%s", data["app"]), "-J", source}
+	for _, key := range []string{"spec", "params", "outputs"} {
+		value, err := json.Marshal(data[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s for jsonnet: %w", key, err)
+		}
+		args = append(args, "--ext-code", fmt.Sprintf("%s=%s", key, value))
+	}
+
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(source, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(p) != ".jsonnet" {
+			return err
+		}
+		rel, err := filepath.Rel(source, p)
+		if err != nil {
+			return err
+		}
+
+		output, err := e.stepCommand(ctx, "jsonnet", append(args, p)...).Output()
+		if err != nil {
+			return fmt.Errorf("jsonnet %s failed: %w%s", rel, err, commandStderr(err))
+		}
+		files[strings.TrimSuffix(filepath.ToSlash(rel), ".jsonnet")+".json"] = output
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .jsonnet files found in %s", source)
+	}
+	return files, nil
+}
+
+// writeRenderedFiles writes rendered files below dir
+func writeRenderedFiles(dir string, files map[string][]byte) error {
+	for name, content := range files {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", name, err)
+		}
+		if err := os.WriteFile(target, content, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// joinManifests concatenates rendered files in path order into one multi-document manifest
+func joinManifests(files map[string][]byte) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var manifest strings.Builder
+	for _, name := range names {
+		content := strings.TrimSpace(strings.TrimPrefix(string(files[name]), "---"))
+		if content == "" {
+			continue
+		}
+		manifest.WriteString("---\n")
+		manifest.WriteString(content)
+		manifest.WriteString("\n")
+	}
+	return manifest.String()
+}
+
+// readManifestDir reads the YAML and JSON manifests of a directory, for example the
+// output of a render step, into one multi-document manifest
+func readManifestDir(dir string) (string, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch filepath.Ext(p) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		content, err := os.ReadFile(p) // #nosec G304 - directory comes from the workflow definition
+		if err != nil {
+			return err
+		}
+		files[p] = content
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read manifests from %s: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no manifests found in %s", dir)
+	}
+	return joinManifests(files), nil
+}
+
+// publishRenderedFiles commits rendered files to the step's Gitea repository below
+// manifestPath. It reports whether anything changed.
+func publishRenderedFiles(ctx context.Context, step types.Step, appName string, files map[string][]byte) (bool, error) {
+	adminConfig, err := admin.LoadAdminConfig("admin-config.yaml")
+	if err != nil {
+		return false, fmt.Errorf("failed to load admin config: %w", err)
+	}
+	if adminConfig.Gitea.URL == "" {
+		return false, fmt.Errorf("gitea configuration not found in admin-config.yaml")
+	}
+
+	owner := step.Owner
+	if owner == "" {
+		owner = adminConfig.Gitea.Username
+	}
+	branch := step.GitBranch
+	if branch == "" {
+		branch = "main"
+	}
+	message := step.CommitMessage
+	if message == "" {
+		message = fmt.Sprintf("Render manifests for %s", appName)
+	}
+
+	cloneDir, err := os.MkdirTemp("", "render-repo-*")
+	if err != nil {
+		return false, fmt.Errorf("failed to create clone directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(cloneDir) }()
+
+	repoURL := fmt.Sprintf("%s/%s/%s.git", adminConfig.Gitea.URL, owner, step.RepoName)
+	return commitFilesToRepo(ctx, filepath.Join(cloneDir, step.RepoName), repoURL, branch, step.ManifestPath, files, message)
+}
+
+// commitFilesToRepo clones a repository into cloneDir, writes files below subdir and
+// commits and pushes them. It reports whether anything changed.
+func commitFilesToRepo(ctx context.Context, cloneDir, repoURL, branch, subdir string, files map[string][]byte, message string) (bool, error) {
+	cloneCmd := stepProcess(ctx, "git", "clone", repoURL, cloneDir) // #nosec G204 - repo URL from admin config and workflow step
+	if output, err := cloneCmd.CombinedOutput(); err != nil {
+		return false, fmt.Errorf("failed to clone repository: %w, output: %s", err, string(output))
+	}
+
+	target := cloneDir
+	if subdir != "" {
+		cleaned := filepath.Clean("/" + subdir)
+		target = filepath.Join(cloneDir, cleaned)
+	}
+	if err := writeRenderedFiles(target, files); err != nil {
+		return false, err
+	}
+
+	if err := runGitCommand(ctx, cloneDir, "config", "user.name", "Score Orchestrator"); err != nil {
+		return false, err
+	}
+	if err := runGitCommand(ctx, cloneDir, "config", "user.email", "orchestrator@score.dev"); err != nil {
+		return false, err
+	}
+	if err := runGitCommand(ctx, cloneDir, "add", "."); err != nil {
+		return false, err
+	}
+
+	statusCmd := stepProcess(ctx, "git", "status", "--porcelain")
+	statusCmd.Dir = cloneDir
+	output, err := statusCmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to check git status: %w", err)
+	}
+	if len(strings.TrimSpace(string(output))) == 0 {
+		return false, nil
+	}
+
+	if err := runGitCommand(ctx, cloneDir, "commit", "-m", message); err != nil {
+		return false, err
+	}
+	if err := runGitCommand(ctx, cloneDir, "push", "origin", branch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// commandStderr returns the stderr of a failed command for error messages
+func commandStderr(err error) string {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return ", output: " + strings.TrimSpace(string(exitErr.Stderr))
+	}
+	return ""
+}
@@ -0,0 +1,165 @@
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"innominatus/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJsonnet and fakeKustomize stand in for the jsonnet and kubectl binaries of the render engines
+const (
+	fakeJsonnet = `#!/bin/sh
+for last; do :; done
+case "$*" in
+  *"--ext-str app=shop"*"--ext-code params="*) echo "{\"file\": \"$(basename "$last")\"}" ;;
+  *) echo "unexpected: $*" >&2; exit 2 ;;
+esac
+`
+	fakeKustomize = `#!/bin/sh
+[ "$1" = "kustomize" ] || { echo "unexpected: $*" >&2; exit 2; }
+cat "$2/kustomization.yaml"
+`
+)
+
+func installRenderTools(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "jsonnet"), []byte(fakeJsonnet), 0755))   // #nosec G306 - test executable
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubectl"), []byte(fakeKustomize), 0755)) // #nosec G306 - test executable
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestRenderStep(t *testing.T) {
+	installRenderTools(t)
+	t.Chdir(t.TempDir())
+
+	source := writeTemplates(t, map[string]string{
+		"deployment.yaml.tmpl": "name: {{ .spec.metadata.name }}\nreplicas: {{ .params.replicas }}\nimage: {{ default \"nginx\" .params.image }}\n",
+		"config/env.yaml":      "url: {{ .outputs.provision.url }}\n",
+	})
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetApplicationStore(fakeApplicationStore{"shop": {Metadata: types.Metadata{Name: "shop"}}})
+	executor.RegisterStepExecutor("provision", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+		executor.execContext.SetStepOutput(step.Name, "url", "https://shop.example.com")
+		return nil
+	})
+
+	workflow := types.Workflow{Steps: []types.Step{
+		{Name: "provision", Type: "provision"},
+		{Name: "manifests", Type: "render", Config: map[string]interface{}{"source": source}},
+	}}
+	require.NoError(t, executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", workflow,
+		map[string]string{"replicas": "3"}))
+
+	outputDir := filepath.Join("workspaces", "shop", "rendered", "manifests")
+	deployment, err := os.ReadFile(filepath.Join(outputDir, "deployment.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "name: shop\nreplicas: 3\nimage: nginx\n", string(deployment))
+
+	env, err := os.ReadFile(filepath.Join(outputDir, "config", "env.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "url: https://shop.example.com\n", string(env))
+
+	dir, _ := executor.execContext.GetStepOutput("manifests", "output_dir")
+	assert.Equal(t, outputDir, dir)
+	files, _ := executor.execContext.GetStepOutput("manifests", "files")
+	assert.Equal(t, "config/env.yaml,deployment.yaml", files)
+
+	manifest, err := readManifestDir(outputDir)
+	require.NoError(t, err)
+	assert.Equal(t, "---\nurl: https://shop.example.com\n---\nname: shop\nreplicas: 3\nimage: nginx\n", manifest)
+}
+
+func TestRenderSourceEngines(t *testing.T) {
+	installRenderTools(t)
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	data := map[string]interface{}{"app": "shop", "params": map[string]string{"namespace": "shop-dev"}}
+
+	t.Run("jsonnet", func(t *testing.T) {
+		source := writeTemplates(t, map[string]string{"main.jsonnet": "{}", "lib.libsonnet": "{}"})
+		files, err := executor.renderSource(context.Background(), RenderEngineJsonnet, source, "", data)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]byte{"main.json": []byte("{\"file\": \"main.jsonnet\"}\n")}, files)
+	})
+
+	t.Run("kustomize renders the whole tree before building the overlay", func(t *testing.T) {
+		source := writeTemplates(t, map[string]string{
+			"base/kustomization.yaml":         "resources: []\n",
+			"overlays/dev/kustomization.yaml": "namespace: {{ .params.namespace }}\nresources: [../../base]\n",
+		})
+		files, err := executor.renderSource(context.Background(), RenderEngineKustomize, source, "overlays/dev", data)
+		require.NoError(t, err)
+		assert.Equal(t, "namespace: shop-dev\nresources: [../../base]\n", string(files["manifests.yaml"]))
+	})
+
+	t.Run("builtin templates", func(t *testing.T) {
+		files, err := executor.renderSource(context.Background(), RenderEngineGoTemplate, "builtin:kubernetes-deployment", "", data)
+		require.NoError(t, err)
+		assert.Contains(t, string(files["deployment.yaml"]), "namespace: shop-dev")
+		assert.Contains(t, string(files["deployment.yaml"]), "image: nginx:1.25")
+	})
+
+	t.Run("unknown builtin templates", func(t *testing.T) {
+		_, err := executor.renderSource(context.Background(), RenderEngineGoTemplate, "builtin:missing", "", data)
+		assert.ErrorContains(t, err, "unknown builtin templates 'missing'")
+	})
+
+	t.Run("unknown engine", func(t *testing.T) {
+		_, err := executor.renderSource(context.Background(), "helmfile", t.TempDir(), "", data)
+		assert.ErrorContains(t, err, "unknown render engine 'helmfile'")
+	})
+}
+
+func TestGenerateKubernetesManifests(t *testing.T) {
+	manifests, err := generateKubernetesManifests("shop", "shop-dev", types.Step{})
+	require.NoError(t, err)
+	assert.Contains(t, manifests, "kind: Deployment\nmetadata:\n  name: shop\n  namespace: shop-dev\n")
+	assert.Contains(t, manifests, "kind: Service\n")
+}
+
+func TestRenderOutputDir(t *testing.T) {
+	dir, err := renderOutputDir("shop", "manifests", "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("workspaces", "shop", "rendered", "manifests"), dir)
+
+	dir, err = renderOutputDir("shop", "manifests", "k8s/dev")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("workspaces", "shop", "k8s", "dev"), dir)
+
+	for _, output := range []string{"..", "../other", "/etc", "."} {
+		_, err := renderOutputDir("shop", "manifests", output)
+		assert.Error(t, err, output)
+	}
+}
+
+func TestValidateRenderStep(t *testing.T) {
+	validator := NewWorkflowValidator()
+
+	errs := validator.ValidateWorkflow(&types.Workflow{Steps: []types.Step{
+		{Name: "ok", Type: "render", Config: map[string]interface{}{"engine": "kustomize", "source": "templates", "overlay": "dev"}},
+		{Name: "bad", Type: "render", Config: map[string]interface{}{"engine": "helmfile", "output": "../escape"}},
+	}})
+
+	require.Len(t, errs, 3)
+	assert.Contains(t, errs[0].Error(), "unknown engine 'helmfile'")
+	assert.Contains(t, errs[1].Error(), "render step requires 'source'")
+	assert.Contains(t, errs[2].Error(), "must be a directory inside the application workspace")
+}
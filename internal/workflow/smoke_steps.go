@@ -0,0 +1,166 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"innominatus/internal/database"
+	"innominatus/internal/logging"
+	"innominatus/internal/types"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHTTPCheckTimeout  = 2 * time.Minute
+	defaultHTTPCheckInterval = 5 * time.Second
+)
+
+// httpCheck is the configuration of an http-check step
+type httpCheck struct {
+	url          string
+	expectStatus int
+	contains     string
+	timeout      time.Duration
+	interval     time.Duration
+}
+
+// parseHTTPCheck reads an http-check step config
+func parseHTTPCheck(config map[string]interface{}) (*httpCheck, error) {
+	check := &httpCheck{url: configString(config, "url"), contains: configString(config, "contains"), expectStatus: http.StatusOK}
+	if check.url == "" {
+		return nil, fmt.Errorf("http-check step requires 'url' in config")
+	}
+	// A url with variables is checked once it is interpolated
+	if !strings.Contains(check.url, "${") && !strings.HasPrefix(check.url, "http://") && !strings.HasPrefix(check.url, "https://") {
+		return nil, fmt.Errorf("http-check url '%s' must start with http:// or https://", check.url)
+	}
+
+	if status := configString(config, "expect_status"); status != "" {
+		code, err := strconv.Atoi(status)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("http-check expect_status '%s' is not an HTTP status code", status)
+		}
+		check.expectStatus = code
+	}
+
+	var err error
+	if check.timeout, err = configDuration(config, "timeout", defaultHTTPCheckTimeout); err != nil {
+		return nil, err
+	}
+	if check.interval, err = configDuration(config, "interval", defaultHTTPCheckInterval); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// executeHTTPCheckStep polls a URL until it answers with the expected status (and body
+// content), or fails when the timeout is reached
+func (e *WorkflowExecutor) executeHTTPCheckStep(ctx context.Context, step types.Step, stepID int64) error {
+	logger := logging.FromContext(ctx, "workflow")
+	check, err := parseHTTPCheck(e.execContext.InterpolateResourceParams(step.Config, step.Env))
+	if err != nil {
+		return err
+	}
+	if strings.Contains(check.url, "${") {
+		return fmt.Errorf("http-check url '%s' references an unknown variable", check.url)
+	}
+
+	logger.Infof("Checking %s (expecting status %d, timeout %s)", check.url, check.expectStatus, check.timeout)
+	ctx, cancel := context.WithTimeout(ctx, check.timeout)
+	defer cancel()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	var logs strings.Builder
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		status, err := check.probe(ctx, client)
+		if err == nil {
+			fmt.Fprintf(&logs, "attempt %d: %s answered %d\n", attempt, check.url, status)
+			_ = e.addStepLogs(stepID, logs.String())
+			e.execContext.SetStepOutput(step.Name, "status_code", strconv.Itoa(status))
+			return nil
+		}
+		lastErr = err
+		fmt.Fprintf(&logs, "attempt %d: %v\n", attempt, err)
+
+		select {
+		case <-ctx.Done():
+			_ = e.addStepLogs(stepID, logs.String())
+			return fmt.Errorf("http-check of %s did not pass within %s: %w", check.url, check.timeout, lastErr)
+		case <-time.After(check.interval):
+		}
+	}
+}
+
+// probe sends one request and returns the status code if the response is as expected
+func (c *httpCheck) probe(ctx context.Context, client *http.Client) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != c.expectStatus {
+		return resp.StatusCode, fmt.Errorf("status %d, expected %d", resp.StatusCode, c.expectStatus)
+	}
+	if c.contains != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+		}
+		if !strings.Contains(string(body), c.contains) {
+			return resp.StatusCode, fmt.Errorf("response does not contain '%s'", c.contains)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// executeTeardownStep removes, newest first, the resources earlier steps of this run
+// created, so a run such as the smoke test leaves nothing behind when it succeeds
+func (e *WorkflowExecutor) executeTeardownStep(ctx context.Context, execID int64, stepID int64) error {
+	logger := logging.FromContext(ctx, "workflow")
+	store, ok := e.repo.(compensationStore)
+	if !ok {
+		return fmt.Errorf("teardown step requires a database-backed workflow repository")
+	}
+
+	compensations, err := store.ListCompensations(execID)
+	if err != nil {
+		return fmt.Errorf("failed to list resources created by execution %d: %w", execID, err)
+	}
+
+	var logs strings.Builder
+	var failures []string
+	for i := len(compensations) - 1; i >= 0; i-- {
+		compensation := compensations[i]
+		if compensation.Status == database.CompensationStatusRolledBack {
+			continue
+		}
+		output, err := e.removeCreated(ctx, store, compensation)
+		logs.WriteString(output)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		fmt.Fprintf(&logs, "Removed %s\n", compensation.Target)
+	}
+	if logs.Len() == 0 {
+		logs.WriteString("No resources to remove\n")
+	}
+	if err := e.addStepLogs(stepID, logs.String()); err != nil {
+		logger.Warnf("Failed to store step logs: %v", err)
+	}
+
+	if len(failures) > 0 {
+		return errors.New("teardown incomplete: " + strings.Join(failures, "; "))
+	}
+	return nil
+}
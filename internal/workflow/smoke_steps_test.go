@@ -0,0 +1,124 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/types"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestHTTPCheckStep verifies the check retries until the URL answers as expected
+func TestHTTPCheckStep(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("<h1>Welcome to nginx!</h1>"))
+	}))
+	defer server.Close()
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.execContext.SetVariable("host", server.URL)
+	step := types.Step{Name: "verify", Type: "http-check", Config: map[string]interface{}{
+		"url": "${workflow.host}/", "contains": "nginx", "interval": "10ms", "timeout": "5s",
+	}}
+
+	require.NoError(t, executor.executeHTTPCheckStep(context.Background(), step, 1))
+	assert.Equal(t, int32(3), requests.Load())
+	status, _ := executor.execContext.GetStepOutput("verify", "status_code")
+	assert.Equal(t, "200", status)
+
+	step.Config["contains"] = "apache"
+	step.Config["timeout"] = "50ms"
+	err := executor.executeHTTPCheckStep(context.Background(), step, 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did not pass within 50ms: response does not contain 'apache'")
+}
+
+func TestValidateHTTPCheckStep(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{"valid", map[string]interface{}{"url": "https://shop.example.com/health", "expect_status": 204}, ""},
+		{"variables", map[string]interface{}{"url": "http://${workflow.host}"}, ""},
+		{"missing url", map[string]interface{}{}, "requires 'url'"},
+		{"no scheme", map[string]interface{}{"url": "shop.example.com"}, "must start with http:// or https://"},
+		{"bad status", map[string]interface{}{"url": "http://shop", "expect_status": "ok"}, "is not an HTTP status code"},
+		{"bad timeout", map[string]interface{}{"url": "http://shop", "timeout": "soon"}, "'timeout' must be a positive duration"},
+	}
+
+	validator := NewWorkflowValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validator.ValidateWorkflow(&types.Workflow{Steps: []types.Step{{Name: "verify", Type: "http-check", Config: tt.config}}})
+			if tt.wantErr == "" {
+				assert.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), tt.wantErr)
+		})
+	}
+}
+
+// TestTeardownStep verifies a teardown step removes what earlier steps of the run created,
+// newest first, and leaves the run successful
+func TestTeardownStep(t *testing.T) {
+	repo := newCompensationRepository()
+	var removed []string
+	executor := newCompensatingExecutor(repo, &removed, "")
+
+	workflow := types.Workflow{Steps: []types.Step{
+		{Name: "repo", Type: "create"},
+		{Name: "app", Type: "create"},
+		{Name: "deprovision", Type: "teardown"},
+	}}
+	require.NoError(t, executor.ExecuteWorkflowWithContext(context.Background(), "smoke", "smoke-test", workflow))
+
+	assert.Equal(t, []string{"app", "repo"}, removed)
+	assert.Equal(t, []string{"repo=rolled_back", "app=rolled_back"}, repo.statuses())
+	assert.Empty(t, repo.compensationSteps(), "teardown runs within its own step")
+}
+
+func TestTeardownStepIncomplete(t *testing.T) {
+	repo := newCompensationRepository()
+	var removed []string
+	executor := newCompensatingExecutor(repo, &removed, "repo")
+
+	workflow := types.Workflow{Steps: []types.Step{
+		{Name: "repo", Type: "create"},
+		{Name: "app", Type: "create"},
+		{Name: "deprovision", Type: "teardown"},
+	}}
+	err := executor.ExecuteWorkflowWithContext(context.Background(), "smoke", "smoke-test", workflow)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "teardown incomplete: failed to roll back repo: resource busy")
+	assert.Equal(t, []string{"app"}, removed)
+}
+
+// TestSmokeTestWorkflow verifies the bundled smoke-test golden path is valid and ends
+// with its teardown
+func TestSmokeTestWorkflow(t *testing.T) {
+	data, err := os.ReadFile("../../workflows/smoke-test.yaml")
+	require.NoError(t, err)
+
+	var spec types.WorkflowSpec
+	require.NoError(t, yaml.Unmarshal(data, &spec))
+	assert.Empty(t, NewWorkflowValidator().ValidateWorkflow(&spec.Spec))
+	assert.Equal(t, OnFailureRollback, spec.Spec.OnFailure)
+
+	steps := spec.Spec.Steps
+	require.NotEmpty(t, steps)
+	assert.Equal(t, "teardown", steps[len(steps)-1].Type)
+}
@@ -0,0 +1,374 @@
+package workflow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/logging"
+	"innominatus/internal/metrics"
+	"innominatus/internal/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultStepCacheTTL is used when a step's cache block has no ttl
+const DefaultStepCacheTTL = 24 * time.Hour
+
+// StepCacheEntry is the stored result of a successful cached step
+type StepCacheEntry struct {
+	Key       string            `json:"key"`
+	AppName   string            `json:"app_name"`
+	StepName  string            `json:"step_name"`
+	StepType  string            `json:"step_type"`
+	Outputs   map[string]string `json:"outputs,omitempty"`
+	Artifacts []string          `json:"artifacts,omitempty"` // Original paths, stored under artifacts/<index>
+	CreatedAt time.Time         `json:"created_at"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// StepCacheStats counts lookups since the cache was created
+type StepCacheStats struct {
+	Entries int   `json:"entries"`
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+}
+
+// StepCache stores step outputs and artifacts on disk, one directory per cache key
+type StepCache struct {
+	root   string
+	mu     sync.Mutex
+	hits   int64
+	misses int64
+}
+
+// NewStepCache creates a cache rooted at dir (created on first write)
+func NewStepCache(dir string) *StepCache {
+	return &StepCache{root: dir}
+}
+
+// SetStepCache enables step-level caching for steps with a cache block
+func (e *WorkflowExecutor) SetStepCache(cache *StepCache) {
+	e.stepCache = cache
+}
+
+// StepCache returns the executor's step cache, or nil when caching is disabled
+func (e *WorkflowExecutor) StepCache() *StepCache {
+	return e.stepCache
+}
+
+// Get returns the unexpired entry for key and records a hit or miss for stepType
+func (c *StepCache) Get(key, stepType string) (*StepCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, err := c.readEntry(key)
+	if err == nil && time.Now().After(entry.ExpiresAt) {
+		_ = os.RemoveAll(c.entryDir(key))
+		entry, err = nil, os.ErrNotExist
+	}
+
+	hit := err == nil
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	metrics.GetGlobal().RecordStepCacheLookup(stepType, hit)
+	return entry, hit
+}
+
+// Put stores entry, copying the listed artifact paths that exist into the cache
+func (c *StepCache) Put(entry *StepCacheEntry, paths []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dir := c.entryDir(entry.Key)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clear cache entry: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+
+	entry.Artifacts = nil
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		dst := filepath.Join(dir, "artifacts", fmt.Sprintf("%d", len(entry.Artifacts)))
+		if err := copyPath(path, dst); err != nil {
+			_ = os.RemoveAll(dir)
+			return fmt.Errorf("failed to cache artifact %s: %w", path, err)
+		}
+		entry.Artifacts = append(entry.Artifacts, path)
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, "entry.json"), data, 0600)
+}
+
+// Restore copies the entry's artifacts back to their original paths
+func (c *StepCache) Restore(entry *StepCacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, path := range entry.Artifacts {
+		src := filepath.Join(c.entryDir(entry.Key), "artifacts", fmt.Sprintf("%d", i))
+		if err := copyPath(src, path); err != nil {
+			return fmt.Errorf("failed to restore artifact %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// List returns all entries, newest first
+func (c *StepCache) List() []StepCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dirs, err := os.ReadDir(c.root)
+	if err != nil {
+		return []StepCacheEntry{}
+	}
+
+	entries := make([]StepCacheEntry, 0, len(dirs))
+	for _, d := range dirs {
+		if entry, err := c.readEntry(d.Name()); err == nil {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAt.After(entries[j].CreatedAt) })
+	return entries
+}
+
+// Invalidate removes entries matching appName and key (empty matches all) and returns how many were removed
+func (c *StepCache) Invalidate(appName, key string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dirs, err := os.ReadDir(c.root)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to read step cache: %w", err)
+	}
+
+	removed := 0
+	for _, d := range dirs {
+		entry, err := c.readEntry(d.Name())
+		if err != nil {
+			continue
+		}
+		if (appName != "" && entry.AppName != appName) || (key != "" && entry.Key != key) {
+			continue
+		}
+		if err := os.RemoveAll(c.entryDir(entry.Key)); err != nil {
+			return removed, fmt.Errorf("failed to remove cache entry %s: %w", entry.Key, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Stats returns the number of stored entries and lookup counters
+func (c *StepCache) Stats() StepCacheStats {
+	entries := len(c.List())
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return StepCacheStats{Entries: entries, Hits: c.hits, Misses: c.misses}
+}
+
+func (c *StepCache) entryDir(key string) string {
+	return filepath.Join(c.root, key)
+}
+
+func (c *StepCache) readEntry(key string) (*StepCacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(c.entryDir(key), "entry.json")) // #nosec G304 - key is a hex digest or a directory we created
+	if err != nil {
+		return nil, err
+	}
+	var entry StepCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// stepCacheKey hashes the interpolated step definition, the Score spec and the step's extra key inputs
+func (e *WorkflowExecutor) stepCacheKey(step types.Step, appName string) (string, error) {
+	definition := step
+	definition.Cache = nil
+	config, err := stepToConfig(definition)
+	if err != nil {
+		return "", err
+	}
+
+	extra := make([]string, 0, len(step.Cache.Key))
+	for _, k := range step.Cache.Key {
+		extra = append(extra, e.execContext.replaceVariables(k, step.Env))
+	}
+
+	var spec *types.ScoreSpec
+	if e.applications != nil {
+		if app, err := e.applications.GetApplication(appName); err == nil && app != nil {
+			spec = app.ScoreSpec
+		}
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"app":  appName,
+		"step": e.execContext.InterpolateResourceParams(config, step.Env),
+		"spec": spec,
+		"key":  extra,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to compute cache key: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// stepCachePaths returns the artifacts cached for step
+func stepCachePaths(step types.Step) []string {
+	paths := append([]string{}, step.Cache.Paths...)
+	if step.OutputDir != "" {
+		paths = append(paths, step.OutputDir)
+	}
+	if step.OutputFile != "" {
+		paths = append(paths, step.OutputFile)
+	}
+	return paths
+}
+
+// executeCachedStep reuses a previous successful result for identical inputs, or runs the step and stores its result
+func (e *WorkflowExecutor) executeCachedStep(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+	logger := logging.FromContext(ctx, "workflow")
+
+	key, err := e.stepCacheKey(step, appName)
+	if err != nil {
+		logger.Warnf("Step cache disabled for %s: %v", step.Name, err)
+		return e.runStepExecutor(ctx, step, appName, execID, stepID)
+	}
+
+	if entry, hit := e.stepCache.Get(key, step.Type); hit {
+		err := e.stepCache.Restore(entry)
+		if err == nil {
+			if len(entry.Outputs) > 0 {
+				e.execContext.SetStepOutputs(step.Name, entry.Outputs)
+			}
+			logger.Infof("Cache hit for %s (cached %s)", step.Name, entry.CreatedAt.Format(time.RFC3339))
+			e.logger.InfoWithFields("Step result restored from cache", map[string]interface{}{
+				"app_name":     appName,
+				"step_name":    step.Name,
+				"step_type":    step.Type,
+				"execution_id": execID,
+				"step_id":      stepID,
+				"cache_key":    key,
+			})
+			return nil
+		}
+		logger.Warnf("Failed to restore cached result for %s, re-running: %v", step.Name, err)
+	}
+
+	if err := e.runStepExecutor(ctx, step, appName, execID, stepID); err != nil {
+		return err
+	}
+
+	ttl := DefaultStepCacheTTL
+	if step.Cache.TTL != "" {
+		if parsed, err := time.ParseDuration(step.Cache.TTL); err == nil {
+			ttl = parsed
+		}
+	}
+
+	now := time.Now()
+	entry := &StepCacheEntry{
+		Key:       key,
+		AppName:   appName,
+		StepName:  step.Name,
+		StepType:  step.Type,
+		Outputs:   e.stepResultOutputs(step),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := e.stepCache.Put(entry, stepCachePaths(step)); err != nil {
+		logger.Warnf("Failed to cache result of %s: %v", step.Name, err)
+	}
+	return nil
+}
+
+// stepResultOutputs collects the outputs a step declares through setVariables and its output file
+func (e *WorkflowExecutor) stepResultOutputs(step types.Step) map[string]string {
+	outputs := make(map[string]string, len(step.SetVariables))
+	for k, v := range step.SetVariables {
+		outputs[k] = v
+	}
+	if step.OutputFile != "" {
+		if fileOutputs, err := e.outputParser.ParseOutputFile(step.OutputFile); err == nil {
+			for k, v := range fileOutputs {
+				outputs[k] = v
+			}
+		}
+	}
+	return outputs
+}
+
+// copyPath copies a file or directory tree from src to dst
+func copyPath(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return copyFile(src, dst, info.Mode())
+	}
+
+	return filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if fi.IsDir() {
+			return os.MkdirAll(target, 0750)
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target, fi.Mode())
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return err
+	}
+	in, err := os.Open(src) // #nosec G304 - paths come from the workflow definition or the cache directory
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()) // #nosec G304
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
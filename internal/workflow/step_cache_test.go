@@ -0,0 +1,104 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/types"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepCache(t *testing.T) {
+	dir := t.TempDir()
+	outputDir := filepath.Join(dir, "generated")
+	outputFile := filepath.Join(dir, "outputs.json")
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	cache := NewStepCache(filepath.Join(dir, "cache"))
+	executor.SetStepCache(cache)
+
+	runs := 0
+	executor.RegisterStepExecutor("generate", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+		runs++
+		require.NoError(t, os.MkdirAll(outputDir, 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(outputDir, "main.tf"), []byte("# generated"), 0600))
+		return os.WriteFile(outputFile, []byte(`{"module":"postgres"}`), 0600)
+	})
+
+	step := types.Step{
+		Name:       "generate",
+		Type:       "generate",
+		OutputDir:  outputDir,
+		OutputFile: outputFile,
+		Cache:      &types.StepCacheConfig{Key: []string{"${workflow.version}"}},
+	}
+	run := func(version string) {
+		executor.execContext.SetVariable("version", version)
+		require.NoError(t, executor.executeStepWithExecutor(context.Background(), step, "shop", 1, 1))
+	}
+
+	run("1.0")
+	assert.Equal(t, 1, runs)
+
+	// A hit restores artifacts and outputs without running the step
+	require.NoError(t, os.RemoveAll(outputDir))
+	require.NoError(t, os.Remove(outputFile))
+	run("1.0")
+	assert.Equal(t, 1, runs)
+	assert.FileExists(t, filepath.Join(outputDir, "main.tf"))
+	assert.FileExists(t, outputFile)
+	value, ok := executor.execContext.GetStepOutput("generate", "module")
+	assert.True(t, ok)
+	assert.Equal(t, "postgres", value)
+
+	// Changing a key input misses
+	run("2.0")
+	assert.Equal(t, 2, runs)
+
+	stats := cache.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(2), stats.Misses)
+
+	entries := cache.List()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "shop", entries[0].AppName)
+	assert.Equal(t, []string{outputDir, outputFile}, entries[0].Artifacts)
+
+	t.Run("invalidate", func(t *testing.T) {
+		removed, err := cache.Invalidate("other-app", "")
+		require.NoError(t, err)
+		assert.Equal(t, 0, removed)
+
+		removed, err = cache.Invalidate("", entries[0].Key)
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+
+		removed, err = cache.Invalidate("shop", "")
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+		assert.Empty(t, cache.List())
+	})
+
+	t.Run("expired entries miss", func(t *testing.T) {
+		entry := &StepCacheEntry{Key: "expired", StepType: "generate", CreatedAt: time.Now().Add(-2 * time.Hour), ExpiresAt: time.Now().Add(-time.Hour)}
+		require.NoError(t, cache.Put(entry, nil))
+
+		_, hit := cache.Get("expired", "generate")
+		assert.False(t, hit)
+		assert.Empty(t, cache.List())
+	})
+
+	t.Run("failed steps are not cached", func(t *testing.T) {
+		executor.RegisterStepExecutor("broken", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+			return assert.AnError
+		})
+		broken := types.Step{Name: "broken", Type: "broken", Cache: &types.StepCacheConfig{}}
+		assert.Error(t, executor.executeStepWithExecutor(context.Background(), broken, "shop", 1, 2))
+		assert.Empty(t, cache.List())
+	})
+}
@@ -0,0 +1,190 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"innominatus/internal/secrets"
+	"innominatus/internal/types"
+	"os"
+	"os/exec"
+	"sort"
+)
+
+// defaultStepEnvironment lists the server environment variables every step process inherits.
+// Everything else, in particular credentials, must be allowed by the admin or passed
+// explicitly through env maps.
+var defaultStepEnvironment = []string{
+	"PATH", "HOME", "TMPDIR", "LANG", "LC_ALL", "TZ",
+	"KUBECONFIG", "KUBERNETES_SERVICE_HOST", "KUBERNETES_SERVICE_PORT",
+}
+
+type workflowEnvKey struct{}
+
+type stepEnvKey struct{}
+
+// withWorkflowEnvironment returns a context whose step processes get the workflow's env map
+func withWorkflowEnvironment(ctx context.Context, env map[string]string) context.Context {
+	if len(env) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, workflowEnvKey{}, env)
+}
+
+// SetInheritedEnvironment sets the server environment variables step processes inherit in
+// addition to the defaults (workflowPolicies.stepEnvironment.inherit in admin-config.yaml)
+func (e *WorkflowExecutor) SetInheritedEnvironment(names []string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.inheritedEnv = names
+}
+
+// SetSecretResolver lets steps reference ${secrets.path.key} in their config, variables and
+// env maps (secrets in admin-config.yaml). A run only reads secrets below the paths access
+// allows for its application and team. Pass secrets to processes through env maps so they
+// do not end up in generated files or command lines.
+func (e *WorkflowExecutor) SetSecretResolver(resolver *secrets.Resolver, access secrets.WorkflowAccess) {
+	e.execContext.SetSecretResolver(resolver, access)
+}
+
+// initSecretScope limits the secret references of a run to those of the application and
+// its team
+func (e *WorkflowExecutor) initSecretScope(appName string) {
+	scope := secrets.Scope{Application: appName}
+	if e.applications != nil {
+		if app, err := e.applications.GetApplication(appName); err == nil && app != nil {
+			scope.Team = app.Team
+		}
+	}
+	e.execContext.setSecretScope(scope)
+}
+
+// inheritedEnvironment returns the server environment variables step processes may see
+func (e *WorkflowExecutor) inheritedEnvironment() map[string]string {
+	e.mu.RLock()
+	names := append(append([]string{}, defaultStepEnvironment...), e.inheritedEnv...)
+	e.mu.RUnlock()
+	return lookupEnvironment(names)
+}
+
+// lookupEnvironment returns the values of the named server environment variables that are set
+func lookupEnvironment(names []string) map[string]string {
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// stepEnvironment builds the environment of a step's processes: the inherited server
+// variables, then the workflow's env map, then the step's env map. Values are interpolated
+// like the rest of the step, but only inherited server variables can be referenced, so an
+// env map cannot copy credentials out of the server environment.
+func (e *WorkflowExecutor) stepEnvironment(ctx context.Context, step types.Step, appName string) []string {
+	inherited := e.inheritedEnvironment()
+	systemEnv := func(name string) string { return inherited[name] }
+
+	env := make(map[string]string, len(inherited)+len(step.Env)+1)
+	for k, v := range inherited {
+		env[k] = v
+	}
+	env["APP_NAME"] = appName
+	for k, v := range deploymentLabelsEnvironment(e.deploymentLabels()) {
+		env[k] = v
+	}
+
+	workflowEnv, _ := ctx.Value(workflowEnvKey{}).(map[string]string)
+	for k, v := range workflowEnv {
+		env[k] = e.execContext.replaceVariablesWith(v, workflowEnv, systemEnv)
+	}
+	for k, v := range step.Env {
+		env[k] = e.execContext.replaceVariablesWith(v, step.Env, systemEnv)
+	}
+
+	return environmentList(env)
+}
+
+// environmentList turns variables into sorted KEY=value entries for exec
+func environmentList(env map[string]string) []string {
+	result := make([]string, 0, len(env))
+	for k, v := range env {
+		result = append(result, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// withStepEnvironment returns a context whose commands run with the step's environment,
+// including the cloud credentials granted to the step. Credentials come last so env maps
+// cannot replace them.
+func (e *WorkflowExecutor) withStepEnvironment(ctx context.Context, step types.Step, appName string) (context.Context, error) {
+	env := e.stepEnvironment(ctx, step, appName)
+	credentials, err := e.stepCloudCredentials(ctx, step, appName)
+	if err != nil {
+		return ctx, err
+	}
+	// exec keeps the last of duplicate variables
+	env = append(env, credentials...)
+	return context.WithValue(ctx, stepEnvKey{}, env), nil
+}
+
+// stepCommand returns a command that runs with the environment of the current step. Outside
+// a step it only gets the default inherited variables.
+func (e *WorkflowExecutor) stepCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	// #nosec G204 - callers pass fixed executables; arguments come from the workflow definition
+	cmd := exec.CommandContext(ctx, name, args...)
+	if env, ok := ctx.Value(stepEnvKey{}).([]string); ok {
+		cmd.Env = env
+	} else {
+		cmd.Env = e.stepEnvironment(ctx, types.Step{}, "")
+	}
+	return cmd
+}
+
+// withCommandEnvironment returns a context whose commands run with the inherited server
+// variables, for work outside a step such as compensators. A step environment in ctx is kept.
+func (e *WorkflowExecutor) withCommandEnvironment(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(stepEnvKey{}).([]string); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, stepEnvKey{}, e.stepEnvironment(ctx, types.Step{}, ""))
+}
+
+// stepProcess returns a command for code that runs steps without the executor at hand: the
+// built-in step types of RunWorkflow and the compensators. It gets the step environment in
+// ctx, and otherwise only the default inherited variables, never the full server environment.
+func stepProcess(ctx context.Context, name string, args ...string) *exec.Cmd {
+	// #nosec G204 - callers pass fixed executables; arguments come from the workflow definition
+	cmd := exec.CommandContext(ctx, name, args...)
+	if env, ok := ctx.Value(stepEnvKey{}).([]string); ok {
+		cmd.Env = env
+	} else {
+		cmd.Env = DefaultCommandEnvironment()
+	}
+	return cmd
+}
+
+// CommandWithEnvironment returns a constructor of commands that run with env, for callers
+// of CheckPolicies outside a workflow run
+func CommandWithEnvironment(env []string) func(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		// #nosec G204 - callers pass fixed executables
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Env = env
+		return cmd
+	}
+}
+
+// DefaultCommandEnvironment returns the environment of processes started for steps when no
+// executor configuration applies: the default inherited server variables only
+func DefaultCommandEnvironment() []string {
+	return environmentList(lookupEnvironment(defaultStepEnvironment))
+}
+
+// CommandEnvironment returns the environment of processes started on behalf of steps
+// outside a workflow run, e.g. by the server's golden path handlers: the inherited server
+// variables, including those allowed by the admin
+func (e *WorkflowExecutor) CommandEnvironment() []string {
+	return environmentList(e.inheritedEnvironment())
+}
@@ -0,0 +1,215 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/database"
+	"innominatus/internal/secrets"
+	"innominatus/internal/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepEnvironment(t *testing.T) {
+	t.Setenv("SERVER_DB_PASSWORD", "server-secret")
+	t.Setenv("AWS_REGION", "eu-central-1")
+	t.Setenv("PATH", "/usr/bin:/bin")
+
+	tests := []struct {
+		name        string
+		inherit     []string
+		workflowEnv map[string]string
+		stepEnv     map[string]string
+		want        map[string]string
+		absent      []string
+	}{
+		{
+			name:   "default deny",
+			want:   map[string]string{"PATH": "/usr/bin:/bin", "APP_NAME": "shop"},
+			absent: []string{"SERVER_DB_PASSWORD", "AWS_REGION"},
+		},
+		{
+			name:    "allowed by admin",
+			inherit: []string{"AWS_REGION"},
+			want:    map[string]string{"AWS_REGION": "eu-central-1"},
+			absent:  []string{"SERVER_DB_PASSWORD"},
+		},
+		{
+			name:        "explicit env maps with interpolated secrets",
+			workflowEnv: map[string]string{"TF_LOG": "INFO", "DB_USER": "admin"},
+			stepEnv:     map[string]string{"DB_USER": "app", "DB_PASSWORD": "${workflow.db_password}"},
+			want:        map[string]string{"TF_LOG": "INFO", "DB_USER": "app", "DB_PASSWORD": "vault-secret"},
+			absent:      []string{"SERVER_DB_PASSWORD"},
+		},
+		{
+			name:    "env maps cannot copy server variables",
+			inherit: []string{"AWS_REGION"},
+			stepEnv: map[string]string{"LEAK": "${SERVER_DB_PASSWORD}", "REGION": "$AWS_REGION"},
+			want:    map[string]string{"LEAK": "${SERVER_DB_PASSWORD}", "REGION": "eu-central-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+			executor.SetInheritedEnvironment(tt.inherit)
+			executor.execContext.SetVariable("db_password", "vault-secret")
+
+			ctx := withWorkflowEnvironment(context.Background(), tt.workflowEnv)
+			env := envMap(executor.stepEnvironment(ctx, types.Step{Name: "apply", Env: tt.stepEnv}, "shop"))
+
+			for k, v := range tt.want {
+				assert.Equal(t, v, env[k], k)
+			}
+			for _, k := range tt.absent {
+				assert.NotContains(t, env, k)
+			}
+		})
+	}
+}
+
+// TestStepCommandEnvironment verifies step processes started during a workflow run only see
+// the step environment
+func TestStepCommandEnvironment(t *testing.T) {
+	t.Setenv("SERVER_DB_PASSWORD", "server-secret")
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	var output string
+	executor.RegisterStepExecutor("print-env", func(ctx context.Context, step types.Step, appName string, execID int64, stepID int64) error {
+		out, err := executor.stepCommand(ctx, "env").Output()
+		output = string(out)
+		return err
+	})
+
+	workflow := types.Workflow{
+		Env:   map[string]string{"STAGE": "staging"},
+		Steps: []types.Step{{Name: "print", Type: "print-env", Env: map[string]string{"REPLICAS": "2"}}},
+	}
+	require.NoError(t, executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", workflow))
+
+	env := envMap(strings.Split(strings.TrimSpace(output), "\n"))
+	assert.Equal(t, "staging", env["STAGE"])
+	assert.Equal(t, "2", env["REPLICAS"])
+	assert.Equal(t, "shop", env["APP_NAME"])
+	assert.NotContains(t, env, "SERVER_DB_PASSWORD")
+}
+
+// TestRunWorkflowStepEnvironment verifies the built-in step types of RunWorkflow, e.g. a
+// policy script, do not see the server's credentials either
+func TestRunWorkflowStepEnvironment(t *testing.T) {
+	t.Setenv("SERVER_DB_PASSWORD", "server-secret")
+	t.Setenv("AWS_REGION", "eu-central-1")
+
+	output := filepath.Join(t.TempDir(), "env.txt")
+	workflow := types.Workflow{
+		Env: map[string]string{"STAGE": "staging"},
+		Steps: []types.Step{{Name: "check", Type: "policy", Env: map[string]string{"REPLICAS": "2"}, Config: map[string]interface{}{
+			"script": "env > " + output,
+		}}},
+	}
+
+	require.NoError(t, RunWorkflow(workflow, "shop", "production"))
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	env := envMap(strings.Split(strings.TrimSpace(string(data)), "\n"))
+	assert.Equal(t, "staging", env["STAGE"])
+	assert.Equal(t, "2", env["REPLICAS"])
+	assert.Equal(t, "shop", env["APP_NAME"])
+	assert.Equal(t, "production", env["ENVIRONMENT_TYPE"])
+	assert.NotContains(t, env, "SERVER_DB_PASSWORD")
+	assert.NotContains(t, env, "AWS_REGION")
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetInheritedEnvironment([]string{"AWS_REGION"})
+	require.NoError(t, executor.RunWorkflow(workflow, "shop", "production"))
+	data, err = os.ReadFile(output)
+	require.NoError(t, err)
+	env = envMap(strings.Split(strings.TrimSpace(string(data)), "\n"))
+	assert.Equal(t, "eu-central-1", env["AWS_REGION"], "allowed by the admin")
+	assert.NotContains(t, env, "SERVER_DB_PASSWORD")
+}
+
+// TestStepSecrets verifies ${secrets.path.key} references resolve from the secret store and
+// fail validation when the secret is missing
+func TestStepSecrets(t *testing.T) {
+	t.Setenv("TEST_STEP_SECRET_PLATFORM_MINIO_PASSWORD", "minio-pw")
+	resolver, err := secrets.ResolverFor(secrets.Config{Env: secrets.EnvConfig{Prefix: "TEST_STEP_SECRET_"}})
+	require.NoError(t, err)
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	step := types.Step{Name: "apply", Env: map[string]string{"TF_VAR_minio_password": "${secrets.platform/minio.password}"}}
+
+	// Without a secret store the reference stays as it is
+	assert.Equal(t, "${secrets.platform/minio.password}", envMap(executor.stepEnvironment(context.Background(), step, "shop"))["TF_VAR_minio_password"])
+	assert.ErrorContains(t, executor.execContext.ValidateStepVariables(step, step.Env), "no secret store is configured")
+
+	executor.SetSecretResolver(resolver, secrets.WorkflowAccess{Paths: []string{"platform/minio"}})
+	assert.Equal(t, "minio-pw", envMap(executor.stepEnvironment(context.Background(), step, "shop"))["TF_VAR_minio_password"])
+	assert.NoError(t, executor.execContext.ValidateStepVariables(step, step.Env))
+
+	step.Env["TF_VAR_minio_user"] = "${secrets.platform/minio.user}"
+	assert.ErrorContains(t, executor.execContext.ValidateStepVariables(step, step.Env), "TEST_STEP_SECRET_PLATFORM_MINIO_USER is not set")
+}
+
+// teamApplicationStore serves applications that only have a team, by name
+type teamApplicationStore map[string]string
+
+func (f teamApplicationStore) GetApplication(name string) (*database.Application, error) {
+	return &database.Application{Name: name, Team: f[name]}, nil
+}
+
+// TestStepSecretsScope verifies a run only reads the secrets of its own application and team
+func TestStepSecretsScope(t *testing.T) {
+	t.Setenv("TEST_SCOPE_SECRET_TEAMS_PAYMENTS_DB_PASSWORD", "payments-pw")
+	t.Setenv("TEST_SCOPE_SECRET_TEAMS_ENGINEERING_DB_PASSWORD", "engineering-pw")
+	t.Setenv("TEST_SCOPE_SECRET_APPLICATIONS_SHOP_API_TOKEN", "shop-token")
+	resolver, err := secrets.ResolverFor(secrets.Config{Env: secrets.EnvConfig{Prefix: "TEST_SCOPE_SECRET_"}})
+	require.NoError(t, err)
+
+	executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+	executor.SetApplicationStore(teamApplicationStore{"shop": "engineering"})
+	executor.SetSecretResolver(resolver, secrets.WorkflowAccess{})
+	executor.initSecretScope("shop")
+
+	step := types.Step{Name: "deploy", Env: map[string]string{
+		"TEAM_PASSWORD":  "${secrets.teams/engineering/db.password}",
+		"APP_TOKEN":      "${secrets.applications/shop/api.token}",
+		"OTHER_PASSWORD": "${secrets.teams/payments/db.password}",
+	}}
+	env := envMap(executor.stepEnvironment(context.Background(), step, "shop"))
+	assert.Equal(t, "engineering-pw", env["TEAM_PASSWORD"])
+	assert.Equal(t, "shop-token", env["APP_TOKEN"])
+	assert.Equal(t, "${secrets.teams/payments/db.password}", env["OTHER_PASSWORD"], "secrets of another team are not read")
+
+	err = executor.execContext.ValidateStepVariables(step, step.Env)
+	assert.ErrorContains(t, err, "${secrets.teams/payments/db.password} is outside the secret paths of application 'shop'")
+}
+
+func TestValidateEnvNames(t *testing.T) {
+	validator := NewWorkflowValidator()
+	workflow := &types.Workflow{
+		Env: map[string]string{"TF_LOG": "INFO", "bad-name": "x"},
+		Steps: []types.Step{{Name: "install", Type: "helm", Env: map[string]string{"1ST": "x", "OK": "y"}, Config: map[string]interface{}{
+			"release": "web", "chart": "nginx",
+		}}},
+	}
+
+	errs := validator.ValidateWorkflow(workflow)
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "'bad-name'")
+	assert.Contains(t, errs[1].Error(), "step 1 (install): env: '1ST'")
+}
+
+func envMap(env []string) map[string]string {
+	result := make(map[string]string, len(env))
+	for _, entry := range env {
+		if k, v, ok := strings.Cut(entry, "="); ok {
+			result[k] = v
+		}
+	}
+	return result
+}
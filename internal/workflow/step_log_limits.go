@@ -0,0 +1,250 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"innominatus/internal/database"
+	"innominatus/internal/objectstore"
+	"innominatus/internal/types"
+)
+
+// DefaultStepLogMaxBytes is the output a step stores when no limit is configured
+const DefaultStepLogMaxBytes = 10 * 1024 * 1024
+
+// archiveTimeout bounds the upload of a full step log
+const archiveTimeout = 5 * time.Minute
+
+// LogArchive stores the full output of steps whose stored logs were truncated
+type LogArchive interface {
+	// StoreStepLog uploads size bytes of log and returns where the log can be found
+	StoreStepLog(ctx context.Context, stepID int64, log io.Reader, size int64) (string, error)
+}
+
+// ObjectStoreLogArchive keeps full step logs in an S3-compatible bucket
+type ObjectStoreLogArchive struct {
+	Client *objectstore.Client
+	Bucket string
+	Prefix string // Key prefix, e.g. step-logs/
+}
+
+// StoreStepLog uploads a step log as <prefix><stepID>.log
+func (a *ObjectStoreLogArchive) StoreStepLog(ctx context.Context, stepID int64, log io.Reader, size int64) (string, error) {
+	key := fmt.Sprintf("%s%d.log", a.Prefix, stepID)
+	if err := a.Client.PutObject(ctx, a.Bucket, key, log, size, "text/plain; charset=utf-8"); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("s3://%s/%s", a.Bucket, key), nil
+}
+
+// SetStepLogLimits sets the output limits of steps whose workflow has no logs block, and
+// the archive full output of truncated steps is uploaded to. Without an archive the
+// dropped output is lost.
+func (e *WorkflowExecutor) SetStepLogLimits(limits types.LogLimits, archive LogArchive) {
+	e.stepLogs.mu.Lock()
+	defer e.stepLogs.mu.Unlock()
+	e.stepLogs.defaults = limits
+	e.stepLogs.archive = archive
+}
+
+// stepLogLimiter keeps the stored output of each step within its limits: the head is
+// written through, later output is held back and only its tail is stored when the step
+// finishes.
+type stepLogLimiter struct {
+	mu       sync.Mutex
+	defaults types.LogLimits
+	archive  LogArchive
+	steps    map[int64]*stepLog
+}
+
+type stepLog struct {
+	maxBytes  int
+	headBytes int
+	stored    int    // Head bytes written to the database
+	tail      []byte // Output past the head; only the last maxBytes-headBytes are kept
+	overflow  int64  // Bytes of output past the head
+	spill     *os.File
+	spillSize int64
+}
+
+// resolveLogLimits applies the admin defaults to the limits of a workflow
+func resolveLogLimits(workflow *types.LogLimits, defaults types.LogLimits) (int, int) {
+	limits := defaults
+	if workflow != nil && workflow.MaxBytes > 0 {
+		limits = *workflow
+	}
+	maxBytes := limits.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultStepLogMaxBytes
+	}
+	headBytes := limits.HeadBytes
+	if workflow != nil && workflow.HeadBytes > 0 {
+		headBytes = workflow.HeadBytes
+	}
+	if headBytes <= 0 || headBytes > maxBytes {
+		headBytes = maxBytes / 2
+	}
+	return maxBytes, headBytes
+}
+
+// register applies the limits of a workflow to one of its steps
+func (l *stepLogLimiter) register(stepID int64, limits *types.LogLimits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.step(stepID, limits)
+}
+
+func (l *stepLogLimiter) step(stepID int64, limits *types.LogLimits) *stepLog {
+	if l.steps == nil {
+		l.steps = make(map[int64]*stepLog)
+	}
+	log, ok := l.steps[stepID]
+	if !ok {
+		log = &stepLog{}
+		log.maxBytes, log.headBytes = resolveLogLimits(limits, l.defaults)
+		l.steps[stepID] = log
+	}
+	return log
+}
+
+// add returns the part of logs to write to the database now
+func (l *stepLogLimiter) add(stepID int64, logs string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	log := l.step(stepID, nil)
+	if l.archive != nil {
+		log.archive(logs)
+	}
+
+	head := ""
+	if room := log.headBytes - log.stored; room > 0 {
+		if len(logs) <= room {
+			log.stored += len(logs)
+			return logs
+		}
+		// Postgres rejects invalid UTF-8, so never cut inside a character
+		cut := room
+		for cut > 0 && !utf8.RuneStart(logs[cut]) {
+			cut--
+		}
+		head, logs = logs[:cut], logs[cut:]
+		log.stored = log.headBytes
+	}
+
+	tailBytes := log.maxBytes - log.headBytes
+	log.overflow += int64(len(logs))
+	if tailBytes > 0 {
+		log.tail = append(log.tail, logs...)
+		// Compact once twice the tail is buffered, so appends stay cheap
+		if len(log.tail) > 2*tailBytes {
+			log.tail = append(log.tail[:0], log.tail[len(log.tail)-tailBytes:]...)
+		}
+	}
+	return head
+}
+
+// archive copies output to the spill file the full log is uploaded from
+func (s *stepLog) archive(logs string) {
+	if s.spill == nil {
+		file, err := os.CreateTemp("", "step-log-*.log")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create step log spill file: %v\n", err)
+			return
+		}
+		s.spill = file
+	}
+	n, err := s.spill.WriteString(logs)
+	s.spillSize += int64(n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write step log spill file: %v\n", err)
+	}
+}
+
+// finish returns the held back tail of a step, the bytes dropped from the middle and
+// the spill file holding the full output (nil when nothing was archived)
+func (l *stepLogLimiter) finish(stepID int64) (string, int64, *os.File, int64, LogArchive) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	log, ok := l.steps[stepID]
+	if !ok {
+		return "", 0, nil, 0, nil
+	}
+	delete(l.steps, stepID)
+
+	tail := log.tail
+	if tailBytes := log.maxBytes - log.headBytes; len(tail) > tailBytes {
+		tail = tail[len(tail)-tailBytes:]
+	}
+	for len(tail) > 0 && !utf8.RuneStart(tail[0]) {
+		tail = tail[1:]
+	}
+	return string(tail), log.overflow - int64(len(tail)), log.spill, log.spillSize, l.archive
+}
+
+// addStepLogs stores step output in the database within the step's log limits
+func (e *WorkflowExecutor) addStepLogs(stepID int64, logs string) error {
+	if logs = e.stepLogs.add(stepID, logs); logs == "" {
+		return nil
+	}
+	return e.repo.AddWorkflowStepLogs(stepID, logs)
+}
+
+// setStepStatus updates the status of a step. Leaving running stores the held back
+// tail of truncated output behind a marker with the amount dropped.
+func (e *WorkflowExecutor) setStepStatus(stepID int64, status string, errorMessage *string) error {
+	if status != database.StepStatusRunning {
+		e.finishStepLogs(stepID)
+	}
+	return e.repo.UpdateWorkflowStepStatus(stepID, status, errorMessage)
+}
+
+func (e *WorkflowExecutor) finishStepLogs(stepID int64) {
+	tail, dropped, spill, spillSize, archive := e.stepLogs.finish(stepID)
+	if spill != nil {
+		defer func() {
+			_ = spill.Close()
+			_ = os.Remove(spill.Name())
+		}()
+	}
+
+	if dropped > 0 {
+		marker := fmt.Sprintf("\n[... %d bytes of output truncated ...]\n", dropped)
+		if location, ok := e.archiveStepLog(stepID, archive, spill, spillSize); ok {
+			marker = fmt.Sprintf("\n[... %d bytes of output truncated, full log: %s ...]\n", dropped, location)
+		}
+		tail = marker + tail
+	}
+	if tail == "" {
+		return
+	}
+	if err := e.repo.AddWorkflowStepLogs(stepID, tail); err != nil {
+		e.logger.Warnf("Failed to store step logs: %v", err)
+	}
+}
+
+// archiveStepLog uploads the full output of a truncated step
+func (e *WorkflowExecutor) archiveStepLog(stepID int64, archive LogArchive, spill *os.File, size int64) (string, bool) {
+	if archive == nil || spill == nil {
+		return "", false
+	}
+	if _, err := spill.Seek(0, io.SeekStart); err != nil {
+		e.logger.Warnf("Failed to read step log spill file: %v", err)
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), archiveTimeout)
+	defer cancel()
+	location, err := archive.StoreStepLog(ctx, stepID, spill, size)
+	if err != nil {
+		e.logger.Warnf("Failed to archive full log of step %d: %v", stepID, err)
+		return "", false
+	}
+	return location, true
+}
@@ -0,0 +1,141 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"innominatus/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLogArchive keeps uploaded step logs in memory
+type fakeLogArchive struct {
+	logs map[int64]string
+}
+
+func (a *fakeLogArchive) StoreStepLog(ctx context.Context, stepID int64, log io.Reader, size int64) (string, error) {
+	content, err := io.ReadAll(log)
+	if err != nil {
+		return "", err
+	}
+	if int64(len(content)) != size {
+		return "", fmt.Errorf("read %d bytes, expected %d", len(content), size)
+	}
+	a.logs[stepID] = string(content)
+	return fmt.Sprintf("memory://%d", stepID), nil
+}
+
+// runChattyWorkflow runs a single step writing lines 0..count-1 as separate log writes
+func runChattyWorkflow(t *testing.T, executor *WorkflowExecutor, repo *MockWorkflowRepository, logs *types.LogLimits, count int) string {
+	t.Helper()
+	executor.RegisterStepExecutor("chatty", func(ctx context.Context, step types.Step, appName string, execID, stepID int64) error {
+		for i := 0; i < count; i++ {
+			if err := executor.addStepLogs(stepID, fmt.Sprintf("line %03d\n", i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	workflow := types.Workflow{Logs: logs, Steps: []types.Step{{Name: "plan", Type: "chatty"}}}
+	require.NoError(t, executor.ExecuteWorkflowWithContext(context.Background(), "shop", "deploy", workflow))
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, step := range repo.steps {
+		if step.StepName == "plan" {
+			require.NotNil(t, step.OutputLogs)
+			return *step.OutputLogs
+		}
+	}
+	t.Fatal("step not recorded")
+	return ""
+}
+
+func TestStepLogLimitsTruncateMiddle(t *testing.T) {
+	repo := NewMockWorkflowRepository()
+	executor := NewWorkflowExecutor(repo)
+
+	// 100 lines of 9 bytes; keep 3 lines from the start and 2 from the end
+	logs := runChattyWorkflow(t, executor, repo, &types.LogLimits{MaxBytes: 45, HeadBytes: 27}, 100)
+
+	assert.Equal(t, "line 000\nline 001\nline 002\n\n[... 855 bytes of output truncated ...]\nline 098\nline 099\n", logs)
+	assert.Empty(t, executor.stepLogs.steps, "finished steps are forgotten")
+}
+
+func TestStepLogLimitsWithinLimit(t *testing.T) {
+	repo := NewMockWorkflowRepository()
+	executor := NewWorkflowExecutor(repo)
+	executor.SetStepLogLimits(types.LogLimits{MaxBytes: 1000}, nil)
+
+	logs := runChattyWorkflow(t, executor, repo, nil, 10)
+
+	assert.Equal(t, 90, len(logs))
+	assert.NotContains(t, logs, "truncated")
+}
+
+func TestStepLogLimitsArchiveFullLog(t *testing.T) {
+	repo := NewMockWorkflowRepository()
+	executor := NewWorkflowExecutor(repo)
+	archive := &fakeLogArchive{logs: map[int64]string{}}
+	executor.SetStepLogLimits(types.LogLimits{MaxBytes: 18}, archive)
+
+	logs := runChattyWorkflow(t, executor, repo, nil, 20)
+
+	require.Len(t, archive.logs, 1)
+	for stepID, full := range archive.logs {
+		assert.Equal(t, 20, strings.Count(full, "\n"))
+		assert.Contains(t, logs, fmt.Sprintf("[... 162 bytes of output truncated, full log: memory://%d ...]", stepID))
+	}
+	assert.True(t, strings.HasPrefix(logs, "line 000\n"))
+	assert.True(t, strings.HasSuffix(logs, "line 019\n"))
+}
+
+func TestStepLogLimiterKeepsCharactersWhole(t *testing.T) {
+	var limiter stepLogLimiter
+	limiter.register(1, &types.LogLimits{MaxBytes: 6, HeadBytes: 3})
+
+	// "ü" is two bytes: the head cannot end and the tail cannot start inside it
+	head := limiter.add(1, "aaüüüü")
+	tail, dropped, _, _, _ := limiter.finish(1)
+
+	assert.Equal(t, "aa", head)
+	assert.Equal(t, "ü", tail)
+	assert.Equal(t, int64(6), dropped)
+}
+
+func TestResolveLogLimits(t *testing.T) {
+	maxBytes, headBytes := resolveLogLimits(nil, types.LogLimits{})
+	assert.Equal(t, DefaultStepLogMaxBytes, maxBytes)
+	assert.Equal(t, DefaultStepLogMaxBytes/2, headBytes)
+
+	maxBytes, headBytes = resolveLogLimits(nil, types.LogLimits{MaxBytes: 100, HeadBytes: 80})
+	assert.Equal(t, []int{100, 80}, []int{maxBytes, headBytes})
+
+	// A workflow maxBytes does not inherit the admin head
+	maxBytes, headBytes = resolveLogLimits(&types.LogLimits{MaxBytes: 50}, types.LogLimits{MaxBytes: 100, HeadBytes: 80})
+	assert.Equal(t, []int{50, 25}, []int{maxBytes, headBytes})
+
+	maxBytes, headBytes = resolveLogLimits(&types.LogLimits{HeadBytes: 10}, types.LogLimits{MaxBytes: 100})
+	assert.Equal(t, []int{100, 10}, []int{maxBytes, headBytes})
+}
+
+func TestValidateLogLimits(t *testing.T) {
+	validator := NewWorkflowValidator()
+	steps := []types.Step{{Name: "plan", Type: "render", Config: map[string]interface{}{"source": "templates"}}}
+
+	assert.Empty(t, validator.ValidateWorkflow(&types.Workflow{Steps: steps, Logs: &types.LogLimits{MaxBytes: 100, HeadBytes: 100}}))
+
+	errs := validator.ValidateWorkflow(&types.Workflow{Steps: steps, Logs: &types.LogLimits{MaxBytes: 100, HeadBytes: 200}})
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "headBytes (200) must not exceed maxBytes (100)")
+
+	errs = validator.ValidateWorkflow(&types.Workflow{Steps: steps, Logs: &types.LogLimits{MaxBytes: -1}})
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "must not be negative")
+}
@@ -0,0 +1,37 @@
+package workflow
+
+import (
+	"fmt"
+	"innominatus/internal/types"
+)
+
+// tempAssetRecorder is implemented by repositories that track the temp files and
+// directories steps leave behind, so they can be garbage collected
+type tempAssetRecorder interface {
+	RecordTempAsset(path string, executionID *int64, appName, stepName string) error
+}
+
+// stepTempAssets returns the temp paths a step writes outside of its workspace
+func stepTempAssets(step types.Step) []string {
+	switch step.Type {
+	case "git-commit-manifests":
+		if step.RepoName != "" {
+			return []string{fmt.Sprintf("/tmp/score-repo-%s", step.RepoName)}
+		}
+	}
+	return nil
+}
+
+// trackTempAssets records the temp paths of a step before it runs. Steps remove most of
+// them when they finish, but a crash or a killed server leaves them behind.
+func (e *WorkflowExecutor) trackTempAssets(step types.Step, appName string, execID int64) {
+	recorder, ok := e.repo.(tempAssetRecorder)
+	if !ok {
+		return
+	}
+	for _, path := range stepTempAssets(step) {
+		if err := recorder.RecordTempAsset(path, &execID, appName, step.Name); err != nil {
+			e.logger.Warnf("Failed to track temp asset %s of step %s: %v", path, step.Name, err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// terraformPlanPrefix starts the names of plan artifacts in the workspace
+const terraformPlanPrefix = "tfplan-"
+
+// terraformPlanFiles are the plan artifacts of one workflow execution in the workspace.
+// They are named after the execution so an apply never picks up a plan that an earlier
+// run left behind.
+type terraformPlanFiles struct {
+	Plan    string // binary plan consumed by the apply phase
+	JSON    string // JSON rendering (terraform show -json)
+	Summary string // human-readable summary
+}
+
+// planFilesFor returns the plan artifact names of a workflow execution
+func planFilesFor(execID int64) terraformPlanFiles {
+	base := fmt.Sprintf("%s%d", terraformPlanPrefix, execID)
+	return terraformPlanFiles{Plan: base, JSON: base + ".json", Summary: base + ".summary.txt"}
+}
+
+// remove deletes the plan artifacts below workspaceDir
+func (f terraformPlanFiles) remove(workspaceDir string) {
+	for _, name := range []string{f.Plan, f.JSON, f.Summary} {
+		_ = os.Remove(filepath.Join(workspaceDir, name))
+	}
+}
+
+// removeStalePlans deletes plan artifacts of other executions from the workspace
+func removeStalePlans(workspaceDir string, current terraformPlanFiles) {
+	matches, _ := filepath.Glob(filepath.Join(workspaceDir, terraformPlanPrefix+"*"))
+	for _, path := range matches {
+		if name := filepath.Base(path); name != current.Plan && name != current.JSON && name != current.Summary {
+			_ = os.Remove(path)
+		}
+	}
+}
+
+// hashPlanFile returns the SHA-256 digest of a binary plan, which an approval is bound to
+func hashPlanFile(path string) (string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - workspace path controlled by executor
+	if err != nil {
+		return "", fmt.Errorf("failed to read terraform plan: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// PlanChange is a single resource change from a terraform plan
+type PlanChange struct {
+	Address string `json:"address"`
+	Action  string `json:"action"` // create, update, delete, replace
+}
+
+// PlanSummary is a human-readable digest of a terraform plan
+type PlanSummary struct {
+	Create  int          `json:"create"`
+	Update  int          `json:"update"`
+	Delete  int          `json:"delete"`
+	Replace int          `json:"replace"`
+	Changes []PlanChange `json:"changes"`
+}
+
+// HasChanges reports whether the plan changes any resource
+func (p *PlanSummary) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// String renders the summary for reviewers
+func (p *PlanSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan: %d to add, %d to change, %d to replace, %d to destroy.\n",
+		p.Create, p.Update, p.Replace, p.Delete)
+	for _, change := range p.Changes {
+		symbol := map[string]string{"create": "+", "update": "~", "delete": "-", "replace": "-/+"}[change.Action]
+		fmt.Fprintf(&b, "  %-3s %s\n", symbol, change.Address)
+	}
+	return b.String()
+}
+
+// terraformJSONPlan is the subset of `terraform show -json` output we need
+type terraformJSONPlan struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// summarizeTerraformPlan builds a PlanSummary from `terraform show -json` output
+func summarizeTerraformPlan(planJSON []byte) (*PlanSummary, error) {
+	var plan terraformJSONPlan
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse terraform plan JSON: %w", err)
+	}
+
+	summary := &PlanSummary{Changes: []PlanChange{}}
+	for _, rc := range plan.ResourceChanges {
+		action := classifyPlanActions(rc.Change.Actions)
+		switch action {
+		case "create":
+			summary.Create++
+		case "update":
+			summary.Update++
+		case "delete":
+			summary.Delete++
+		case "replace":
+			summary.Replace++
+		default:
+			continue
+		}
+		summary.Changes = append(summary.Changes, PlanChange{Address: rc.Address, Action: action})
+	}
+
+	sort.Slice(summary.Changes, func(i, j int) bool {
+		return summary.Changes[i].Address < summary.Changes[j].Address
+	})
+
+	return summary, nil
+}
+
+// classifyPlanActions maps terraform's action list to a single action name
+func classifyPlanActions(actions []string) string {
+	if len(actions) == 2 {
+		return "replace"
+	}
+	if len(actions) == 1 {
+		switch actions[0] {
+		case "create", "update", "delete":
+			return actions[0]
+		}
+	}
+	return "" // no-op, read
+}
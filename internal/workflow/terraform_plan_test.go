@@ -0,0 +1,137 @@
+package workflow
+
+import (
+	"context"
+	"innominatus/internal/database"
+	"innominatus/internal/types"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeTerraformPlan(t *testing.T) {
+	planJSON := []byte(`{
+		"format_version": "1.2",
+		"resource_changes": [
+			{"address": "aws_s3_bucket.data", "change": {"actions": ["create"]}},
+			{"address": "aws_db_instance.main", "change": {"actions": ["update"]}},
+			{"address": "aws_instance.web", "change": {"actions": ["delete", "create"]}},
+			{"address": "aws_iam_role.old", "change": {"actions": ["delete"]}},
+			{"address": "data.aws_caller_identity.current", "change": {"actions": ["read"]}},
+			{"address": "aws_vpc.main", "change": {"actions": ["no-op"]}}
+		]
+	}`)
+
+	summary, err := summarizeTerraformPlan(planJSON)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, summary.Create)
+	assert.Equal(t, 1, summary.Update)
+	assert.Equal(t, 1, summary.Replace)
+	assert.Equal(t, 1, summary.Delete)
+	require.Len(t, summary.Changes, 4)
+	assert.Equal(t, "aws_db_instance.main", summary.Changes[0].Address)
+	assert.True(t, summary.HasChanges())
+
+	text := summary.String()
+	assert.Contains(t, text, "Plan: 1 to add, 1 to change, 1 to replace, 1 to destroy.")
+	assert.Contains(t, text, "-/+ aws_instance.web")
+	assert.NotContains(t, text, "aws_vpc.main")
+}
+
+func TestSummarizeTerraformPlanNoChanges(t *testing.T) {
+	summary, err := summarizeTerraformPlan([]byte(`{"resource_changes": []}`))
+	require.NoError(t, err)
+	assert.False(t, summary.HasChanges())
+}
+
+func TestSummarizeTerraformPlanInvalidJSON(t *testing.T) {
+	_, err := summarizeTerraformPlan([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+// fakeTerraform writes a new plan on every plan run and records what apply applied
+const fakeTerraform = `#!/bin/sh
+dir=$(dirname "$0")
+case "$1" in
+plan)
+	for arg in "$@"; do
+		case "$arg" in -out=*) date +%s%N > "${arg#-out=}" ;; esac
+	done ;;
+show)
+	echo '{"resource_changes":[{"address":"aws_s3_bucket.data","change":{"actions":["create"]}}]}' ;;
+apply)
+	for arg in "$@"; do last="$arg"; done
+	echo "$last" >> "$dir/applied" ;;
+esac
+`
+
+// approvalStoreFunc decides approvals on creation by calling decide
+type approvalStoreFunc struct {
+	*fakeApprovalStore
+	decide func(approval *database.WorkflowApproval)
+}
+
+func (s approvalStoreFunc) CreateWorkflowApproval(approval *database.WorkflowApproval) error {
+	if err := s.fakeApprovalStore.CreateWorkflowApproval(approval); err != nil {
+		return err
+	}
+	s.decide(approval)
+	return s.DecideWorkflowApproval(approval.ID, database.ApprovalStatusApproved, "reviewer", "")
+}
+
+func TestTerraformApplyApprovedPlan(t *testing.T) {
+	original := approvalPollInterval
+	approvalPollInterval = time.Millisecond
+	t.Cleanup(func() { approvalPollInterval = original })
+
+	bin := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "terraform"), []byte(fakeTerraform), 0755)) // #nosec G306 - test executable
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Chdir(t.TempDir())
+
+	module := filepath.Join(t.TempDir(), "module")
+	require.NoError(t, os.MkdirAll(module, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(module, "main.tf"), []byte(`resource "aws_s3_bucket" "data" {}`), 0600))
+	workspace := filepath.Join("workspaces", "shop", "terraform")
+	require.NoError(t, os.MkdirAll(workspace, 0700))
+
+	step := types.Step{Name: "apply", Type: "terraform", WorkingDir: module, Config: map[string]interface{}{"require_approval": true}}
+	readApplied := func() string {
+		data, _ := os.ReadFile(filepath.Join(bin, "applied"))
+		return string(data)
+	}
+
+	t.Run("stale plan of an earlier run is not applied", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(workspace, "tfplan-1"), []byte("stale"), 0600))
+
+		var approved *database.WorkflowApproval
+		executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+		executor.SetApprovalStore(approvalStoreFunc{newFakeApprovalStore(""), func(a *database.WorkflowApproval) { approved = a }})
+
+		require.NoError(t, executor.stepExecutors["terraform"](context.Background(), step, "shop", 2, 0))
+		assert.Equal(t, "tfplan-2\n", readApplied())
+		assert.NoFileExists(t, filepath.Join(workspace, "tfplan-1"))
+		assert.NoFileExists(t, filepath.Join(workspace, "tfplan-2"), "applied plans are removed")
+		require.NotNil(t, approved)
+		assert.Len(t, approved.PlanHash, 64)
+	})
+
+	t.Run("plan changed after approval", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(bin, "applied")))
+
+		executor := NewWorkflowExecutor(NewMockWorkflowRepository())
+		executor.SetApprovalStore(approvalStoreFunc{newFakeApprovalStore(""), func(a *database.WorkflowApproval) {
+			require.NoError(t, os.WriteFile(filepath.Join(workspace, "tfplan-3"), []byte("tampered"), 0600))
+		}})
+
+		err := executor.stepExecutors["terraform"](context.Background(), step, "shop", 3, 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "changed after it was approved")
+		assert.Empty(t, readApplied())
+	})
+}
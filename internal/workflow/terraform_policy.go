@@ -0,0 +1,201 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"innominatus/internal/logging"
+	"innominatus/internal/types"
+	"sort"
+	"strings"
+)
+
+// terraformPolicyQuery is the rego query evaluated against the JSON plan.
+// Each policy lives in a package below "terraform" and defines a "deny" set.
+const terraformPolicyQuery = "data.terraform"
+
+// PolicyViolation is a single policy failure located in a terraform plan
+type PolicyViolation struct {
+	Policy  string `json:"policy"`            // Rego package below "terraform", e.g. mandatory_tags
+	Address string `json:"address,omitempty"` // Resource address, e.g. aws_s3_bucket.data
+	Path    string `json:"path,omitempty"`    // Attribute path within the resource, e.g. tags.team
+	Message string `json:"message"`
+}
+
+// String renders the violation with its location
+func (v PolicyViolation) String() string {
+	location := v.Address
+	if v.Path != "" {
+		location = fmt.Sprintf("%s.%s", v.Address, v.Path)
+	}
+	if location == "" {
+		return fmt.Sprintf("[%s] %s", v.Policy, v.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", v.Policy, location, v.Message)
+}
+
+// terraformPolicyPaths returns the rego files or directories configured on a terraform step
+func terraformPolicyPaths(step types.Step) []string {
+	if step.Config == nil {
+		return nil
+	}
+
+	switch policies := step.Config["policies"].(type) {
+	case string:
+		if policies != "" {
+			return []string{policies}
+		}
+	case []interface{}:
+		paths := make([]string, 0, len(policies))
+		for _, p := range policies {
+			if path, ok := p.(string); ok && path != "" {
+				paths = append(paths, path)
+			}
+		}
+		return paths
+	case []string:
+		return policies
+	}
+	return nil
+}
+
+// checkTerraformPolicies evaluates the saved JSON plan against the step's rego policies
+// and fails with every violation before anything is applied
+func (e *WorkflowExecutor) checkTerraformPolicies(ctx context.Context, step types.Step, planJSONPath string, stepID int64) error {
+	logger := logging.FromContext(ctx, "workflow")
+
+	policyPaths := terraformPolicyPaths(step)
+	if len(policyPaths) == 0 {
+		return nil
+	}
+
+	logger.Infof("Evaluating %d Terraform policy source(s)", len(policyPaths))
+
+	args := []string{"eval", "--format", "json", "--input", planJSONPath}
+	for _, path := range policyPaths {
+		args = append(args, "--data", path)
+	}
+	args = append(args, terraformPolicyQuery)
+
+	// #nosec G204 -- policy paths come from the workflow definition
+	cmd := e.stepCommand(ctx, "opa", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("opa eval failed: %w", err)
+	}
+
+	violations, err := parseOPAViolations(output)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) == 0 {
+		logger.Info("Terraform plan passed all policies")
+		return nil
+	}
+
+	lines := make([]string, 0, len(violations))
+	for _, v := range violations {
+		lines = append(lines, v.String())
+	}
+	report := fmt.Sprintf("Terraform plan violates %d policy rule(s):\n  %s\n", len(violations), strings.Join(lines, "\n  "))
+	logger.Error(report)
+	if stepID > 0 {
+		_ = e.addStepLogs(stepID, report)
+	}
+
+	return fmt.Errorf("terraform plan violates %d policy rule(s): %s", len(violations), strings.Join(lines, "; "))
+}
+
+// opaEvalOutput is the subset of `opa eval --format json` output we need
+type opaEvalOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value map[string]interface{} `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// parseOPAViolations extracts deny results from `opa eval --format json data.terraform`.
+// Policies may be nested packages such as terraform.aws.s3; they are named by their path
+// below terraform. Deny entries may be plain messages or objects with msg, address and path
+// fields. A deny rule that is not a set fails the check rather than being ignored.
+func parseOPAViolations(output []byte) ([]PolicyViolation, error) {
+	var result opaEvalOutput
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+
+	violations := []PolicyViolation{}
+	for _, r := range result.Result {
+		for _, expr := range r.Expressions {
+			if err := collectOPAViolations("", expr.Value, &violations); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Address != violations[j].Address {
+			return violations[i].Address < violations[j].Address
+		}
+		return violations[i].Policy < violations[j].Policy
+	})
+
+	return violations, nil
+}
+
+// collectOPAViolations appends the deny entries of the package at policy and of all packages
+// below it
+func collectOPAViolations(policy string, rules map[string]interface{}, violations *[]PolicyViolation) error {
+	for name, value := range rules {
+		if name == "deny" {
+			denies, ok := value.([]interface{})
+			if !ok {
+				return fmt.Errorf("policy '%s': deny must be a set, got %T", policyName(policy), value)
+			}
+			for _, deny := range denies {
+				*violations = append(*violations, toPolicyViolation(policyName(policy), deny))
+			}
+			continue
+		}
+
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := name
+		if policy != "" {
+			path = policy + "." + name
+		}
+		if err := collectOPAViolations(path, nested, violations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// policyName names deny rules of the terraform package itself
+func policyName(policy string) string {
+	if policy == "" {
+		return "terraform"
+	}
+	return policy
+}
+
+func toPolicyViolation(policy string, deny interface{}) PolicyViolation {
+	violation := PolicyViolation{Policy: policy}
+
+	switch d := deny.(type) {
+	case string:
+		violation.Message = d
+	case map[string]interface{}:
+		violation.Message, _ = d["msg"].(string)
+		violation.Address, _ = d["address"].(string)
+		violation.Path, _ = d["path"].(string)
+	default:
+		violation.Message = fmt.Sprintf("%v", d)
+	}
+
+	return violation
+}
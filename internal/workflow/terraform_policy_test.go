@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"innominatus/internal/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOPAViolations(t *testing.T) {
+	output := []byte(`{
+		"result": [{
+			"expressions": [{
+				"value": {
+					"mandatory_tags": {
+						"deny": [
+							{"address": "aws_s3_bucket.data", "path": "tags.team", "msg": "missing mandatory tag 'team'"}
+						],
+						"required_tags": ["team"]
+					},
+					"allowed_instance_types": {
+						"deny": [
+							{"address": "aws_instance.web", "path": "instance_type", "msg": "instance type 'x1.32xlarge' is not allowed"}
+						]
+					},
+					"legacy": {
+						"deny": ["plain message violation"]
+					},
+					"s3_public_access": {
+						"deny": []
+					}
+				},
+				"text": "data.terraform"
+			}]
+		}]
+	}`)
+
+	violations, err := parseOPAViolations(output)
+	require.NoError(t, err)
+	require.Len(t, violations, 3)
+
+	assert.Equal(t, "legacy", violations[0].Policy)
+	assert.Equal(t, "[legacy] plain message violation", violations[0].String())
+
+	assert.Equal(t, "aws_instance.web", violations[1].Address)
+	assert.Equal(t, "[allowed_instance_types] aws_instance.web.instance_type: instance type 'x1.32xlarge' is not allowed", violations[1].String())
+
+	assert.Equal(t, "mandatory_tags", violations[2].Policy)
+	assert.Equal(t, "tags.team", violations[2].Path)
+}
+
+func TestParseOPAViolationsNestedPackages(t *testing.T) {
+	output := []byte(`{
+		"result": [{
+			"expressions": [{
+				"value": {
+					"aws": {
+						"s3": {
+							"deny": [
+								{"address": "aws_s3_bucket_acl.data", "path": "acl", "msg": "ACL 'public-read' makes the bucket public"}
+							]
+						},
+						"ec2": {
+							"instances": {
+								"deny": ["instance type 'x1.32xlarge' is not allowed"]
+							}
+						}
+					},
+					"deny": ["root package violation"]
+				},
+				"text": "data.terraform"
+			}]
+		}]
+	}`)
+
+	violations, err := parseOPAViolations(output)
+	require.NoError(t, err)
+	require.Len(t, violations, 3)
+
+	policies := []string{violations[0].Policy, violations[1].Policy, violations[2].Policy}
+	assert.ElementsMatch(t, []string{"aws.s3", "aws.ec2.instances", "terraform"}, policies)
+	assert.Equal(t, "[aws.s3] aws_s3_bucket_acl.data.acl: ACL 'public-read' makes the bucket public", violations[2].String())
+}
+
+func TestParseOPAViolationsInvalidDeny(t *testing.T) {
+	output := []byte(`{"result": [{"expressions": [{"value": {"aws": {"s3": {"deny": {"bucket": "public"}}}}}]}]}`)
+
+	_, err := parseOPAViolations(output)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "aws.s3")
+}
+
+func TestParseOPAViolationsNoResult(t *testing.T) {
+	violations, err := parseOPAViolations([]byte(`{}`))
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	_, err = parseOPAViolations([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestTerraformPolicyPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected []string
+	}{
+		{name: "no config", config: nil, expected: nil},
+		{name: "single path", config: map[string]interface{}{"policies": "./policies/terraform"}, expected: []string{"./policies/terraform"}},
+		{name: "list of paths", config: map[string]interface{}{"policies": []interface{}{"a.rego", "", "b.rego"}}, expected: []string{"a.rego", "b.rego"}},
+		{name: "empty string", config: map[string]interface{}{"policies": ""}, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := terraformPolicyPaths(types.Step{Config: tt.config})
+			assert.Equal(t, tt.expected, paths)
+		})
+	}
+}
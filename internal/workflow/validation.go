@@ -70,6 +70,25 @@ func (e *ExecutionContext) ValidateVariableExists(varRef string, env map[string]
 		return nil
 	}
 
+	// Secrets from the secret store (secrets.path.key)
+	if strings.HasPrefix(varName, "secrets.") {
+		_, resolveErr := resolveSecretReference(e.secrets, e.secretAccess, e.secretScope, strings.TrimPrefix(varName, "secrets."))
+		if resolveErr == nil {
+			return nil
+		}
+		err := fmt.Errorf("undefined variable: %s (%v)", varRef, resolveErr)
+		if IsStrictMode() {
+			return err
+		}
+		logrus.Warnf("Validation warning: %v", err)
+		return nil
+	}
+
+	// Dotted workflow variables, e.g. ${execution.now} or ${files.values.yaml}
+	if _, found := e.WorkflowVariables[varName]; found {
+		return nil
+	}
+
 	// Try step outputs (step.output)
 	if strings.Contains(varName, ".") && !strings.HasPrefix(varName, "resources.") {
 		parts := strings.SplitN(varName, ".", 2)
@@ -3,8 +3,15 @@ package workflow
 import (
 	"fmt"
 	"innominatus/internal/types"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
 )
 
+// envNamePattern matches valid environment variable names
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // WorkflowValidator validates workflow definitions
 type WorkflowValidator struct {
 	registeredExecutors map[string]bool
@@ -14,12 +21,21 @@ type WorkflowValidator struct {
 func NewWorkflowValidator() *WorkflowValidator {
 	return &WorkflowValidator{
 		registeredExecutors: map[string]bool{
-			"terraform":  true,
-			"kubernetes": true,
-			"ansible":    true,
-			"policy":     true,
-			"gitea-repo": true,
-			"argocd-app": true,
+			"terraform":         true,
+			"kubernetes":        true,
+			"ansible":           true,
+			"policy":            true,
+			"gitea-repo":        true,
+			"argocd-app":        true,
+			"approval":          true,
+			"synthetic":         true,
+			"helm":              true,
+			"cluster-readiness": true,
+			"register-cluster":  true,
+			"render":            true,
+			"db-migrate":        true,
+			"http-check":        true,
+			"teardown":          true,
 		},
 	}
 }
@@ -34,6 +50,27 @@ func (v *WorkflowValidator) ValidateWorkflow(workflow *types.Workflow) []error {
 		return errors // No point checking steps if there are none
 	}
 
+	switch workflow.OnFailure {
+	case "", OnFailurePrompt, OnFailureRollback:
+	default:
+		errors = append(errors, fmt.Errorf("onFailure must be '%s' or '%s', got '%s'", OnFailurePrompt, OnFailureRollback, workflow.OnFailure))
+	}
+
+	for _, name := range invalidEnvNames(workflow.Env) {
+		errors = append(errors, fmt.Errorf("env: '%s' is not a valid environment variable name", name))
+	}
+
+	if logs := workflow.Logs; logs != nil {
+		if logs.MaxBytes < 0 || logs.HeadBytes < 0 {
+			errors = append(errors, fmt.Errorf("logs: maxBytes and headBytes must not be negative"))
+		} else if logs.MaxBytes > 0 && logs.HeadBytes > logs.MaxBytes {
+			errors = append(errors, fmt.Errorf("logs: headBytes (%d) must not exceed maxBytes (%d)", logs.HeadBytes, logs.MaxBytes))
+		}
+	}
+
+	errors = append(errors, validateContract(workflow)...)
+	errors = append(errors, validatePreflight(workflow)...)
+
 	// Validate each step
 	for i, step := range workflow.Steps {
 		stepErrors := v.validateStep(i, step)
@@ -61,11 +98,22 @@ func (v *WorkflowValidator) validateStep(index int, step types.Step) []error {
 	// Validate step type is registered
 	if !v.registeredExecutors[step.Type] {
 		errors = append(errors, fmt.Errorf(
-			"step %d (%s): unknown step type '%s' (valid types: terraform, kubernetes, ansible, policy, gitea-repo, argocd-app)",
+			"step %d (%s): unknown step type '%s' (valid types: terraform, kubernetes, ansible, policy, gitea-repo, argocd-app, approval, synthetic, helm, cluster-readiness, register-cluster, render, db-migrate, http-check, teardown)",
 			index+1, step.Name, step.Type))
 		// Continue validation to catch other errors
 	}
 
+	// Validate cache settings
+	if step.Cache != nil && step.Cache.TTL != "" {
+		if ttl, err := time.ParseDuration(step.Cache.TTL); err != nil || ttl <= 0 {
+			errors = append(errors, fmt.Errorf("step %d (%s): cache ttl '%s' must be a positive duration", index+1, step.Name, step.Cache.TTL))
+		}
+	}
+
+	for _, name := range invalidEnvNames(step.Env) {
+		errors = append(errors, fmt.Errorf("step %d (%s): env: '%s' is not a valid environment variable name", index+1, step.Name, name))
+	}
+
 	// Validate step has config
 	if step.Config == nil {
 		errors = append(errors, fmt.Errorf("step %d (%s): step must have a config", index+1, step.Name))
@@ -82,6 +130,20 @@ func (v *WorkflowValidator) validateStep(index int, step types.Step) []error {
 		errors = append(errors, v.validateKubernetesStep(index, step)...)
 	case "ansible":
 		errors = append(errors, v.validateAnsibleStep(index, step)...)
+	case "helm":
+		errors = append(errors, v.validateHelmStep(index, step)...)
+	case "register-cluster":
+		errors = append(errors, v.validateRegisterClusterStep(index, step)...)
+	case "render":
+		errors = append(errors, v.validateRenderStep(index, step)...)
+	case "db-migrate":
+		if _, err := parseDBMigration(step, step.Config, "app"); err != nil {
+			errors = append(errors, fmt.Errorf("step %d (%s): %w", index+1, step.Name, err))
+		}
+	case "http-check":
+		if _, err := parseHTTPCheck(step.Config); err != nil {
+			errors = append(errors, fmt.Errorf("step %d (%s): %w", index+1, step.Name, err))
+		}
 	}
 
 	return errors
@@ -91,7 +153,7 @@ func (v *WorkflowValidator) validateStep(index int, step types.Step) []error {
 func (v *WorkflowValidator) validatePolicyStep(index int, step types.Step) []error {
 	var errors []error
 
-	// Policy steps must have 'script' field, not 'command'
+	// Policy steps run a 'script', or evaluate rego policies when they have none
 	scriptValue, hasScript := step.Config["script"]
 	_, hasCommand := step.Config["command"]
 
@@ -100,10 +162,13 @@ func (v *WorkflowValidator) validatePolicyStep(index int, step types.Step) []err
 			errors = append(errors, fmt.Errorf(
 				"step %d (%s): policy step requires 'script' in config (found 'command' instead - please rename to 'script')",
 				index+1, step.Name))
-		} else {
-			errors = append(errors, fmt.Errorf(
-				"step %d (%s): policy step requires 'script' in config",
-				index+1, step.Name))
+		}
+		if manifests, ok := step.Config["manifests"]; ok {
+			if s, isString := manifests.(string); !isString || s == "" {
+				errors = append(errors, fmt.Errorf(
+					"step %d (%s): policy step 'manifests' must be a non-empty path",
+					index+1, step.Name))
+			}
 		}
 	} else {
 		// Validate script is not empty
@@ -162,19 +227,45 @@ func (v *WorkflowValidator) validateKubernetesStep(index int, step types.Step) [
 			index+1, step.Name))
 	}
 
-	// Kubernetes steps must have a manifest or namespace
+	// Kubernetes steps must have a manifest, a directory of manifests or a namespace
 	hasManifest := step.Config["manifest"] != nil && step.Config["manifest"] != ""
+	hasSource := step.Config["source"] != nil && step.Config["source"] != ""
 	hasNamespace := step.Config["namespace"] != nil && step.Config["namespace"] != ""
 
-	if !hasManifest && !hasNamespace {
+	if !hasManifest && !hasSource && !hasNamespace {
 		errors = append(errors, fmt.Errorf(
-			"step %d (%s): kubernetes step requires 'manifest' or 'namespace' in config",
+			"step %d (%s): kubernetes step requires 'manifest', 'source' or 'namespace' in config",
 			index+1, step.Name))
 	}
 
 	return errors
 }
 
+// validateRenderStep validates a render step configuration
+func (v *WorkflowValidator) validateRenderStep(index int, step types.Step) []error {
+	var errors []error
+
+	switch engine := configString(step.Config, "engine"); engine {
+	case "", RenderEngineGoTemplate, RenderEngineJsonnet, RenderEngineKustomize:
+	default:
+		errors = append(errors, fmt.Errorf(
+			"step %d (%s): render step has unknown engine '%s' (valid engines: %s, %s, %s)",
+			index+1, step.Name, engine, RenderEngineGoTemplate, RenderEngineJsonnet, RenderEngineKustomize))
+	}
+
+	if configString(step.Config, "source") == "" {
+		errors = append(errors, fmt.Errorf("step %d (%s): render step requires 'source' in config", index+1, step.Name))
+	}
+
+	if output := configString(step.Config, "output"); output != "" {
+		if _, err := renderOutputDir("app", step.Name, output); err != nil {
+			errors = append(errors, fmt.Errorf("step %d (%s): %w", index+1, step.Name, err))
+		}
+	}
+
+	return errors
+}
+
 // validateAnsibleStep validates an ansible step configuration
 func (v *WorkflowValidator) validateAnsibleStep(index int, step types.Step) []error {
 	var errors []error
@@ -189,6 +280,67 @@ func (v *WorkflowValidator) validateAnsibleStep(index int, step types.Step) []er
 	return errors
 }
 
+// validateHelmStep validates a helm step configuration
+func (v *WorkflowValidator) validateHelmStep(index int, step types.Step) []error {
+	var errors []error
+
+	for _, field := range []string{"release", "chart"} {
+		if value, ok := step.Config[field]; !ok || value == nil || value == "" {
+			errors = append(errors, fmt.Errorf(
+				"step %d (%s): helm step requires '%s' in config",
+				index+1, step.Name, field))
+		}
+	}
+
+	return errors
+}
+
+// validateRegisterClusterStep validates a register-cluster step configuration
+func (v *WorkflowValidator) validateRegisterClusterStep(index int, step types.Step) []error {
+	var errors []error
+
+	if environment, ok := step.Config["environment"]; !ok || environment == nil || environment == "" {
+		errors = append(errors, fmt.Errorf(
+			"step %d (%s): register-cluster step requires 'environment' in config",
+			index+1, step.Name))
+	}
+
+	return errors
+}
+
+// validateContract validates the declared inputs and outputs of a workflow
+func validateContract(workflow *types.Workflow) []error {
+	var errors []error
+
+	seen := make(map[string]bool)
+	for i, input := range workflow.Inputs {
+		switch {
+		case input.Name == "":
+			errors = append(errors, fmt.Errorf("inputs[%d]: name is required", i))
+		case seen[input.Name]:
+			errors = append(errors, fmt.Errorf("inputs[%d]: duplicate input '%s'", i, input.Name))
+		}
+		seen[input.Name] = true
+	}
+
+	names := make([]string, 0, len(workflow.Outputs))
+	for name := range workflow.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		output := workflow.Outputs[name]
+		if output.Value == "" {
+			errors = append(errors, fmt.Errorf("outputs.%s: value is required", name))
+		}
+		if output.Type != "" && !slices.Contains(OutputTypes, output.Type) {
+			errors = append(errors, fmt.Errorf("outputs.%s: type must be one of %v, got '%s'", name, OutputTypes, output.Type))
+		}
+	}
+
+	return errors
+}
+
 // FormatValidationErrors formats validation errors into a human-readable string
 func FormatValidationErrors(workflowName string, errors []error) string {
 	if len(errors) == 0 {
@@ -202,3 +354,15 @@ func FormatValidationErrors(workflowName string, errors []error) string {
 
 	return result
 }
+
+// invalidEnvNames returns the keys of an env map that are not valid variable names, sorted
+func invalidEnvNames(env map[string]string) []string {
+	var names []string
+	for name := range env {
+		if !envNamePattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
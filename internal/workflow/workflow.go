@@ -1,206 +1,138 @@
 package workflow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"innominatus/internal/admin"
+	"innominatus/internal/logging"
 	"innominatus/internal/types"
 	"io"
 	"net/http"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 )
 
+// Spinner reports the progress of a running step. Each state change is written to the
+// structured log instead of animating the terminal, so progress lines stay correlated
+// with the rest of the server output.
 type Spinner struct {
 	message string
 	active  bool
 	mu      sync.Mutex
-	done    chan bool
+	logger  *logging.ZerologAdapter
 }
 
 func NewSpinner(message string) *Spinner {
 	return &Spinner{
 		message: message,
-		done:    make(chan bool),
+		logger:  logging.NewStructuredLogger("workflow"),
 	}
 }
 
+// WithLogger makes the spinner report through logger, e.g. one carrying request context
+func (s *Spinner) WithLogger(logger *logging.ZerologAdapter) *Spinner {
+	s.logger = logger
+	return s
+}
+
 func (s *Spinner) Start() {
 	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.active {
-		s.mu.Unlock()
 		return
 	}
 	s.active = true
-	s.mu.Unlock()
-
-	go func() {
-		chars := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-		i := 0
-		for {
-			select {
-			case <-s.done:
-				return
-			default:
-				s.mu.Lock()
-				if s.active {
-					fmt.Printf("\r%s %s", chars[i%len(chars)], s.message)
-				}
-				s.mu.Unlock()
-				time.Sleep(100 * time.Millisecond)
-				i++
-			}
-		}
-	}()
+	s.logger.Info(s.message)
 }
 
 func (s *Spinner) Stop(success bool, resultMessage string) {
 	s.mu.Lock()
+	defer s.mu.Unlock()
 	if !s.active {
-		s.mu.Unlock()
 		return
 	}
 	s.active = false
-	s.mu.Unlock()
-
-	s.done <- true
 
 	if success {
-		fmt.Printf("\r✅ %s\n", resultMessage)
+		s.logger.Info(resultMessage)
 	} else {
-		fmt.Printf("\r❌ %s\n", resultMessage)
+		s.logger.Error(resultMessage)
 	}
 }
 
 func (s *Spinner) Update(message string) {
 	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.message = message
-	s.mu.Unlock()
+	s.logger.Debug(message)
 }
 
+// RunWorkflow runs a workflow with the built-in step types. Step processes only inherit the
+// default server variables; use WorkflowExecutor.RunWorkflow to apply the admin's allowlist.
 func RunWorkflow(w types.Workflow, appName string, envType string) error {
-	fmt.Printf("Starting workflow with %d steps for app '%s' (env: %s)\n\n", len(w.Steps), appName, envType)
+	return NewWorkflowExecutor(nil).RunWorkflow(w, appName, envType)
+}
+
+// RunWorkflow runs a workflow with the built-in step types, without recording an execution.
+// Step processes get the step environment like registered step executors.
+func (e *WorkflowExecutor) RunWorkflow(w types.Workflow, appName string, envType string) error {
+	logger := logging.NewStructuredLogger("workflow")
+	ctx := withWorkflowEnvironment(context.Background(), w.Env)
+	e.initSecretScope(appName)
+
+	logger.Infof("Starting workflow with %d steps for app '%s' (env: %s)", len(w.Steps), appName, envType)
 
 	for i, step := range w.Steps {
-		fmt.Printf("Step %d/%d: %s (%s)\n", i+1, len(w.Steps), step.Name, step.Type)
+		logger.Infof("Step %d/%d: %s (%s)", i+1, len(w.Steps), step.Name, step.Type)
 
 		spinner := NewSpinner(fmt.Sprintf("Initializing %s step...", step.Type))
 		spinner.Start()
 
-		err := runStepWithSpinner(step, appName, envType, spinner)
+		stepCtx := context.WithValue(ctx, stepEnvKey{}, e.stepEnvironment(ctx, step, appName))
+		err := runStepWithSpinner(stepCtx, step, appName, envType, spinner)
 		if err != nil {
 			spinner.Stop(false, fmt.Sprintf("Step '%s' failed: %v", step.Name, err))
 			return fmt.Errorf("workflow failed at step '%s': %w", step.Name, err)
 		}
 
 		spinner.Stop(true, fmt.Sprintf("Step '%s' completed successfully", step.Name))
-		fmt.Println()
+
 	}
 
-	fmt.Println("🎉 Workflow completed successfully!")
+	logger.Info("Workflow completed successfully!")
 	return nil
 }
 
-//nolint:unused // Legacy implementation kept for reference
-func runStep(step types.Step) error {
-	switch step.Type {
-	case "terraform":
-		return runTerraformStep(step)
-	case "ansible":
-		return runAnsibleStep(step)
-	case "kubernetes":
-		return runKubernetesStep(step)
-	default:
-		return fmt.Errorf("unsupported step type: %s", step.Type)
-	}
-}
-
-func runStepWithSpinner(step types.Step, appName string, envType string, spinner *Spinner) error {
+// runStepWithSpinner runs a built-in step type. Its processes get the step environment in ctx.
+func runStepWithSpinner(ctx context.Context, step types.Step, appName string, envType string, spinner *Spinner) error {
 	switch step.Type {
 	case "terraform":
-		return runTerraformStepWithSpinner(step, appName, envType, spinner)
+		return runTerraformStepWithSpinner(ctx, step, appName, envType, spinner)
 	case "ansible":
-		return runAnsibleStepWithSpinner(step, appName, envType, spinner)
+		return runAnsibleStepWithSpinner(ctx, step, appName, envType, spinner)
 	case "kubernetes":
-		return runKubernetesStepWithSpinner(step, appName, envType, spinner)
+		return runKubernetesStepWithSpinner(ctx, step, appName, envType, spinner)
 	case "gitea-repo":
-		return runGiteaRepoStepWithSpinner(step, appName, envType, spinner)
+		return runGiteaRepoStepWithSpinner(ctx, step, appName, envType, spinner)
 	case "argocd-app":
-		return runArgoCDAppStepWithSpinner(step, appName, envType, spinner)
+		return runArgoCDAppStepWithSpinner(ctx, step, appName, envType, spinner)
 	case "git-commit-manifests":
-		return runGitCommitManifestsStepWithSpinner(step, appName, envType, spinner)
+		return runGitCommitManifestsStepWithSpinner(ctx, step, appName, envType, spinner)
 	case "policy":
-		return runPolicyStepWithSpinner(step, appName, envType, spinner)
+		return runPolicyStepWithSpinner(ctx, step, appName, envType, spinner)
 	case "dummy":
-		return runDummyStepWithSpinner(step, appName, envType, spinner)
+		return runDummyStepWithSpinner(ctx, step, appName, envType, spinner)
 	default:
 		return fmt.Errorf("unsupported step type: %s", step.Type)
 	}
 }
 
-//nolint:unused // Legacy implementation kept for reference
-func runTerraformStep(step types.Step) error {
-	fmt.Printf("Running Terraform in path: %s\n", step.Path)
-
-	// Check if directory exists
-	if _, err := os.Stat(step.Path); os.IsNotExist(err) {
-		return fmt.Errorf("terraform path does not exist: %s", step.Path)
-	}
-
-	// Run terraform init
-	fmt.Println("Running: terraform init")
-	initCmd := exec.Command("terraform", "init")
-	initCmd.Dir = step.Path
-	initCmd.Stdout = os.Stdout
-	initCmd.Stderr = os.Stderr
-
-	if err := initCmd.Run(); err != nil {
-		return fmt.Errorf("terraform init failed: %w", err)
-	}
-
-	// Run terraform apply
-	fmt.Println("Running: terraform apply -auto-approve")
-	applyCmd := exec.Command("terraform", "apply", "-auto-approve")
-	applyCmd.Dir = step.Path
-	applyCmd.Stdout = os.Stdout
-	applyCmd.Stderr = os.Stderr
-
-	if err := applyCmd.Run(); err != nil {
-		return fmt.Errorf("terraform apply failed: %w", err)
-	}
-
-	// Get terraform outputs
-	fmt.Println("Getting terraform outputs...")
-	outputCmd := exec.Command("terraform", "output", "-json")
-	outputCmd.Dir = step.Path
-	outputCmd.Stderr = os.Stderr
-
-	output, err := outputCmd.Output()
-	if err != nil {
-		fmt.Printf("Warning: could not get terraform outputs: %v\n", err)
-		return nil // Don't fail the step for output errors
-	}
-
-	if len(output) > 0 {
-		fmt.Println("Terraform outputs:")
-		var outputs map[string]interface{}
-		if err := json.Unmarshal(output, &outputs); err == nil {
-			for key, value := range outputs {
-				fmt.Printf("  %s: %v\n", key, value)
-			}
-		} else {
-			fmt.Printf("  %s\n", string(output))
-		}
-	}
-
-	return nil
-}
+func runTerraformStepWithSpinner(ctx context.Context, step types.Step, appName string, envType string, spinner *Spinner) error {
+	logger := logging.NewStructuredLogger("workflow")
 
-func runTerraformStepWithSpinner(step types.Step, appName string, envType string, spinner *Spinner) error {
 	spinner.Update("Checking Terraform path...")
 
 	// Check if directory exists
@@ -210,7 +142,7 @@ func runTerraformStepWithSpinner(step types.Step, appName string, envType string
 
 	// Run terraform init
 	spinner.Update("Running terraform init...")
-	initCmd := exec.Command("terraform", "init")
+	initCmd := stepProcess(ctx, "terraform", "init")
 	initCmd.Dir = step.Path
 	if spinner == nil {
 		initCmd.Stdout = os.Stdout
@@ -223,7 +155,7 @@ func runTerraformStepWithSpinner(step types.Step, appName string, envType string
 
 	// Run terraform apply
 	spinner.Update("Applying terraform configuration...")
-	applyCmd := exec.Command("terraform", "apply", "-auto-approve")
+	applyCmd := stepProcess(ctx, "terraform", "apply", "-auto-approve")
 	applyCmd.Dir = step.Path
 	if spinner == nil {
 		applyCmd.Stdout = os.Stdout
@@ -236,7 +168,7 @@ func runTerraformStepWithSpinner(step types.Step, appName string, envType string
 
 	// Get terraform outputs
 	spinner.Update("Retrieving terraform outputs...")
-	outputCmd := exec.Command("terraform", "output", "-json")
+	outputCmd := stepProcess(ctx, "terraform", "output", "-json")
 	outputCmd.Dir = step.Path
 	if spinner == nil {
 		outputCmd.Stderr = os.Stderr
@@ -249,50 +181,21 @@ func runTerraformStepWithSpinner(step types.Step, appName string, envType string
 	}
 
 	if len(output) > 0 && spinner == nil {
-		fmt.Println("Terraform outputs:")
+		logger.Info("Terraform outputs:")
 		var outputs map[string]interface{}
 		if err := json.Unmarshal(output, &outputs); err == nil {
 			for key, value := range outputs {
-				fmt.Printf("  %s: %v\n", key, value)
+				logger.Infof("%s: %v", key, value)
 			}
 		} else {
-			fmt.Printf("  %s\n", string(output))
+			logger.Info(string(output))
 		}
 	}
 
 	return nil
 }
 
-//nolint:unused // Legacy implementation kept for reference
-func runAnsibleStep(step types.Step) error {
-	if step.Playbook == "" {
-		return fmt.Errorf("ansible step requires playbook field")
-	}
-
-	fmt.Printf("Running Ansible playbook: %s\n", step.Playbook)
-
-	// Check if playbook exists
-	if _, err := os.Stat(step.Playbook); os.IsNotExist(err) {
-		return fmt.Errorf("ansible playbook does not exist: %s", step.Playbook)
-	}
-
-	// Run ansible-playbook
-	fmt.Printf("Running: ansible-playbook %s\n", step.Playbook)
-	cmd := exec.Command("ansible-playbook", step.Playbook) // #nosec G204 - ansible playbook from validated workflow definition
-	if step.Path != "" {
-		cmd.Dir = step.Path
-	}
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("ansible-playbook failed: %w", err)
-	}
-
-	return nil
-}
-
-func runAnsibleStepWithSpinner(step types.Step, appName string, envType string, spinner *Spinner) error {
+func runAnsibleStepWithSpinner(ctx context.Context, step types.Step, appName string, envType string, spinner *Spinner) error {
 	if step.Playbook == "" {
 		return fmt.Errorf("ansible step requires playbook field")
 	}
@@ -306,7 +209,7 @@ func runAnsibleStepWithSpinner(step types.Step, appName string, envType string,
 
 	// Run ansible-playbook
 	spinner.Update("Running ansible-playbook...")
-	cmd := exec.Command("ansible-playbook", step.Playbook) // #nosec G204 - playbook from validated workflow definition
+	cmd := stepProcess(ctx, "ansible-playbook", step.Playbook) // #nosec G204 - playbook from validated workflow definition
 	if step.Path != "" {
 		cmd.Dir = step.Path
 	}
@@ -322,70 +225,17 @@ func runAnsibleStepWithSpinner(step types.Step, appName string, envType string,
 	return nil
 }
 
-//nolint:unused // Legacy implementation kept for reference
-func runKubernetesStep(step types.Step) error {
-	fmt.Printf("Running Kubernetes deployment")
-	if step.Namespace != "" {
-		fmt.Printf(" in namespace: %s", step.Namespace)
-	}
-	fmt.Println()
-
-	// Placeholder for actual kubernetes deployment logic
-	fmt.Println("✅ Kubernetes deployment completed successfully")
-	return nil
+// generateKubernetesManifests renders the builtin deployment templates for an application
+func generateKubernetesManifests(appName string, namespace string, step types.Step) (string, error) {
+	return renderBuiltin("kubernetes-deployment", map[string]interface{}{
+		"app":    appName,
+		"params": map[string]string{"namespace": namespace},
+	})
 }
 
-// generateKubernetesManifests generates Kubernetes manifests from Score spec information
-func generateKubernetesManifests(appName string, namespace string, step types.Step) string {
-	// For now, generate a basic nginx deployment
-	// In a full implementation, this should parse the Score spec and generate appropriate manifests
-
-	manifests := fmt.Sprintf(`---
-apiVersion: apps/v1
-kind: Deployment
-metadata:
-  name: %s
-  namespace: %s
-  labels:
-    app: %s
-spec:
-  replicas: 1
-  selector:
-    matchLabels:
-      app: %s
-  template:
-    metadata:
-      labels:
-        app: %s
-    spec:
-      containers:
-      - name: web
-        image: nginx:1.25
-        ports:
-        - containerPort: 80
-          protocol: TCP
----
-apiVersion: v1
-kind: Service
-metadata:
-  name: %s
-  namespace: %s
-  labels:
-    app: %s
-spec:
-  selector:
-    app: %s
-  ports:
-  - port: 80
-    targetPort: 80
-    protocol: TCP
-  type: ClusterIP
-`, appName, namespace, appName, appName, appName, appName, namespace, appName, appName)
-
-	return manifests
-}
+func runKubernetesStepWithSpinner(ctx context.Context, step types.Step, appName string, envType string, spinner *Spinner) error {
+	logger := logging.NewStructuredLogger("workflow")
 
-func runKubernetesStepWithSpinner(step types.Step, appName string, envType string, spinner *Spinner) error {
 	spinner.Update("Setting up Kubernetes deployment...")
 
 	namespace := step.Namespace
@@ -396,7 +246,7 @@ func runKubernetesStepWithSpinner(step types.Step, appName string, envType strin
 	spinner.Update(fmt.Sprintf("Creating namespace: %s", namespace))
 
 	// Create namespace if it doesn't exist
-	createNsCmd := exec.Command("kubectl", "create", "namespace", namespace) // #nosec G204 - namespace from workflow config
+	createNsCmd := stepProcess(ctx, "kubectl", "create", "namespace", namespace) // #nosec G204 - namespace from workflow config
 	output, err := createNsCmd.CombinedOutput()
 	if err != nil && !strings.Contains(string(output), "AlreadyExists") {
 		return fmt.Errorf("failed to create namespace: %w, output: %s", err, string(output))
@@ -405,12 +255,15 @@ func runKubernetesStepWithSpinner(step types.Step, appName string, envType strin
 	spinner.Update(fmt.Sprintf("Deploying to namespace: %s", namespace))
 
 	// Generate Kubernetes manifests
-	manifests := generateKubernetesManifests(appName, namespace, step)
+	manifests, err := generateKubernetesManifests(appName, namespace, step)
+	if err != nil {
+		return err
+	}
 
 	spinner.Update("Applying Kubernetes manifests...")
 
 	// Apply manifests using kubectl
-	applyCmd := exec.Command("kubectl", "apply", "-f", "-", "-n", namespace) // #nosec G204 - namespace from workflow config
+	applyCmd := stepProcess(ctx, "kubectl", "apply", "-f", "-", "-n", namespace) // #nosec G204 - namespace from workflow config
 	applyCmd.Stdin = strings.NewReader(manifests)
 	output, err = applyCmd.CombinedOutput()
 	if err != nil {
@@ -421,32 +274,34 @@ func runKubernetesStepWithSpinner(step types.Step, appName string, envType strin
 
 	// Wait for deployment to be ready (with timeout)
 	// #nosec G204 - appName and namespace from validated workflow definition
-	waitCmd := exec.Command("kubectl", "wait", "--for=condition=available",
+	waitCmd := stepProcess(ctx, "kubectl", "wait", "--for=condition=available",
 		"--timeout=120s",
 		fmt.Sprintf("deployment/%s", appName),
 		"-n", namespace)
 	output, err = waitCmd.CombinedOutput()
 	if err != nil {
 		// Don't fail if wait times out, just log it
-		fmt.Printf("\nWarning: Deployment readiness check: %s\n", string(output))
+		logger.Warnf("Deployment readiness check: %s", string(output))
 	}
 
 	spinner.Update("Checking deployment status...")
 
 	// Verify pods are running
-	getPodsCmd := exec.Command("kubectl", "get", "pods", "-n", namespace) // #nosec G204 - namespace from workflow config
+	getPodsCmd := stepProcess(ctx, "kubectl", "get", "pods", "-n", namespace) // #nosec G204 - namespace from workflow config
 	output, err = getPodsCmd.CombinedOutput()
 	if err != nil {
-		fmt.Printf("\nWarning: Could not get pods: %v\n", err)
+		logger.Warnf("Could not get pods: %v", err)
 	} else {
-		fmt.Printf("\nPods in namespace %s:\n%s\n", namespace, string(output))
+		logger.Infof("Pods in namespace %s:\n%s", namespace, string(output))
 	}
 
 	return nil
 }
 
 // runGiteaRepoStepWithSpinner creates a repository in Gitea
-func runGiteaRepoStepWithSpinner(step types.Step, appName string, envType string, spinner *Spinner) error {
+func runGiteaRepoStepWithSpinner(_ context.Context, step types.Step, appName string, envType string, spinner *Spinner) error {
+	logger := logging.NewStructuredLogger("workflow")
+
 	if step.RepoName == "" {
 		return fmt.Errorf("gitea-repo step requires repoName field")
 	}
@@ -508,18 +363,20 @@ func runGiteaRepoStepWithSpinner(step types.Step, appName string, envType string
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode == 409 {
-		fmt.Printf("Repository %s/%s already exists, skipping creation\n", owner, step.RepoName)
+		logger.Infof("Repository %s/%s already exists, skipping creation", owner, step.RepoName)
 	} else if resp.StatusCode != 201 {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("failed to create repository, status %d: %s", resp.StatusCode, string(body))
 	}
 
-	fmt.Printf("Gitea repository available at: %s/%s/%s\n", adminConfig.Gitea.URL, owner, step.RepoName)
+	logger.Infof("Gitea repository available at: %s/%s/%s", adminConfig.Gitea.URL, owner, step.RepoName)
 	return nil
 }
 
 // runArgoCDAppStepWithSpinner creates an ArgoCD Application with sync waiting
-func runArgoCDAppStepWithSpinner(step types.Step, appName string, envType string, spinner *Spinner) error {
+func runArgoCDAppStepWithSpinner(_ context.Context, step types.Step, appName string, envType string, spinner *Spinner) error {
+	logger := logging.NewStructuredLogger("workflow")
+
 	if step.AppName == "" {
 		step.AppName = fmt.Sprintf("%s-%s", appName, envType)
 	}
@@ -648,8 +505,8 @@ func runArgoCDAppStepWithSpinner(step types.Step, appName string, envType string
 	}
 
 	appURL := fmt.Sprintf("%s/applications/%s", adminConfig.ArgoCD.URL, step.AppName)
-	fmt.Printf("ArgoCD Application available at: %s\n", appURL)
-	fmt.Printf("Repository: %s\n", repoURL)
+	logger.Infof("ArgoCD Application available at: %s", appURL)
+	logger.Infof("Repository: %s", repoURL)
 
 	// Check if we should wait for sync completion
 	waitForSync := step.WaitForSync == nil || *step.WaitForSync
@@ -669,7 +526,9 @@ func runArgoCDAppStepWithSpinner(step types.Step, appName string, envType string
 }
 
 // runGitCommitManifestsStepWithSpinner generates and commits Kubernetes manifests
-func runGitCommitManifestsStepWithSpinner(step types.Step, appName string, envType string, spinner *Spinner) error {
+func runGitCommitManifestsStepWithSpinner(ctx context.Context, step types.Step, appName string, envType string, spinner *Spinner) error {
+	logger := logging.NewStructuredLogger("workflow")
+
 	if step.RepoName == "" {
 		return fmt.Errorf("git-commit-manifests step requires repoName field")
 	}
@@ -698,68 +557,14 @@ func runGitCommitManifestsStepWithSpinner(step types.Step, appName string, envTy
 	}
 	namespace := fmt.Sprintf("%s-%s", appName, envType)
 
-	// Generate Kubernetes manifests (simplified template)
-	manifestTemplate := `apiVersion: apps/v1
-kind: Deployment
-metadata:
-  name: %s
-  namespace: %s
-  labels:
-    app: %s
-    environment: %s
-    managed-by: innominatus
-spec:
-  replicas: 2
-  selector:
-    matchLabels:
-      app: %s
-      component: web
-  template:
-    metadata:
-      labels:
-        app: %s
-        component: web
-        environment: %s
-    spec:
-      containers:
-      - name: web
-        image: nginx:latest
-        ports:
-        - containerPort: 80
-          name: http
-        env:
-        - name: APP_NAME
-          value: "platform-config"
-        resources:
-          requests:
-            memory: "64Mi"
-            cpu: "50m"
-          limits:
-            memory: "128Mi"
-            cpu: "100m"
----
-apiVersion: v1
-kind: Service
-metadata:
-  name: %s-service
-  namespace: %s
-  labels:
-    app: %s
-    component: web
-spec:
-  selector:
-    app: %s
-    component: web
-  ports:
-  - protocol: TCP
-    port: 80
-    targetPort: 80
-  type: ClusterIP`
-
-	manifestContent := fmt.Sprintf(manifestTemplate,
-		appName, namespace, appName, envType,
-		appName, appName, envType,
-		appName, namespace, appName, appName)
+	// Render the builtin GitOps manifests
+	manifests, err := renderBuiltin("gitops-deployment", map[string]interface{}{
+		"app":    appName,
+		"params": map[string]string{"namespace": namespace, "environment": envType},
+	})
+	if err != nil {
+		return err
+	}
 
 	// Set defaults
 	gitBranch := step.GitBranch
@@ -780,78 +585,25 @@ spec:
 	// Create temporary directory for git operations
 	tmpDir := fmt.Sprintf("/tmp/score-repo-%s", step.RepoName)
 	_ = os.RemoveAll(tmpDir) // Clean up any existing directory
-
-	// Clone repository
-	repoURL := fmt.Sprintf("%s/%s/%s.git", adminConfig.Gitea.URL, owner, step.RepoName)
-	cloneCmd := exec.Command("git", "clone", repoURL, tmpDir) // #nosec G204 - repo URL from admin config and workflow step
-	if err := cloneCmd.Run(); err != nil {
-		return fmt.Errorf("failed to clone repository: %w", err)
-	}
-
-	// Write manifest file
-	manifestFilePath := filepath.Join(tmpDir, "deployment.yaml")
-	if err := os.WriteFile(manifestFilePath, []byte(manifestContent), 0600); err != nil {
-		return fmt.Errorf("failed to write manifest file: %w", err)
-	}
-
-	if spinner != nil {
-		spinner.Update("Configuring git...")
-	}
-
-	// Configure git
-	if err := runGitCommand(tmpDir, "config", "user.name", "Score Orchestrator"); err != nil {
-		return err
-	}
-	if err := runGitCommand(tmpDir, "config", "user.email", "orchestrator@score.dev"); err != nil {
-		return err
-	}
-
-	if spinner != nil {
-		spinner.Update("Committing manifests...")
-	}
-
-	// Add and commit files
-	if err := runGitCommand(tmpDir, "add", "."); err != nil {
-		return err
-	}
-
-	// Check if there are changes to commit
-	statusCmd := exec.Command("git", "status", "--porcelain")
-	statusCmd.Dir = tmpDir
-	output, err := statusCmd.Output()
-	if err != nil {
-		return fmt.Errorf("failed to check git status: %w", err)
-	}
-
-	if len(strings.TrimSpace(string(output))) == 0 {
-		fmt.Printf("No changes to commit - manifests are up to date\n")
-		_ = os.RemoveAll(tmpDir)
-		return nil
-	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
 
 	commitMessage := step.CommitMessage
 	if commitMessage == "" {
 		commitMessage = fmt.Sprintf("Add Kubernetes manifests for %s\n\nGenerated from Score specification", appName)
 	}
 
-	if err := runGitCommand(tmpDir, "commit", "-m", commitMessage); err != nil {
+	repoURL := fmt.Sprintf("%s/%s/%s.git", adminConfig.Gitea.URL, owner, step.RepoName)
+	committed, err := commitFilesToRepo(ctx, tmpDir, repoURL, gitBranch, "", map[string][]byte{"deployment.yaml": []byte(manifests)}, commitMessage)
+	if err != nil {
 		return err
 	}
-
-	if spinner != nil {
-		spinner.Update("Pushing to repository...")
-	}
-
-	// Push changes
-	if err := runGitCommand(tmpDir, "push", "origin", gitBranch); err != nil {
-		return err
+	if !committed {
+		logger.Info("No changes to commit - manifests are up to date")
+		return nil
 	}
 
-	fmt.Printf("Successfully generated and committed Kubernetes manifests to repository\n")
-	fmt.Printf("Repository: %s/%s/%s\n", adminConfig.Gitea.URL, owner, step.RepoName)
-
-	// Clean up temporary directory
-	_ = os.RemoveAll(tmpDir)
+	logger.Info("Successfully generated and committed Kubernetes manifests to repository")
+	logger.Infof("Repository: %s/%s/%s", adminConfig.Gitea.URL, owner, step.RepoName)
 	return nil
 }
 
@@ -907,6 +659,8 @@ func authenticateArgoCD(argoCDURL, username, password string) (string, error) {
 
 // waitForArgoCDSync waits for an ArgoCD application to sync and become healthy
 func waitForArgoCDSync(appName, argoCDURL, token string, timeoutSeconds int, spinner *Spinner) error {
+	logger := logging.NewStructuredLogger("workflow")
+
 	client := &http.Client{Timeout: 30 * time.Second}
 	statusURL := fmt.Sprintf("%s/api/v1/applications/%s", argoCDURL, appName)
 
@@ -980,7 +734,7 @@ func waitForArgoCDSync(appName, argoCDURL, token string, timeoutSeconds int, spi
 			if spinner != nil {
 				spinner.Update("ArgoCD Application synced successfully (Status: Synced, Health: Healthy)")
 			}
-			fmt.Printf("✅ ArgoCD Application synced successfully (Status: %s, Health: %s)\n", syncStatus, healthStatus)
+			logger.Infof("ArgoCD Application synced successfully (Status: %s, Health: %s)", syncStatus, healthStatus)
 			return nil
 		}
 
@@ -995,8 +749,8 @@ func waitForArgoCDSync(appName, argoCDURL, token string, timeoutSeconds int, spi
 }
 
 // runGitCommand executes a git command in the specified directory
-func runGitCommand(dir string, args ...string) error {
-	cmd := exec.Command("git", args...)
+func runGitCommand(ctx context.Context, dir string, args ...string) error {
+	cmd := stepProcess(ctx, "git", args...)
 	cmd.Dir = dir
 
 	output, err := cmd.CombinedOutput()
@@ -1007,7 +761,7 @@ func runGitCommand(dir string, args ...string) error {
 	return nil
 }
 
-func runPolicyStepWithSpinner(step types.Step, appName string, envType string, spinner *Spinner) error {
+func runPolicyStepWithSpinner(ctx context.Context, step types.Step, appName string, envType string, spinner *Spinner) error {
 	if spinner != nil {
 		spinner.Update(fmt.Sprintf("Executing policy script: %s", step.Name))
 	}
@@ -1039,12 +793,10 @@ func runPolicyStepWithSpinner(step types.Step, appName string, envType string, s
 
 	// Execute script
 	// #nosec G204 -- tmpFile.Name() is a controlled temporary file path
-	cmd := exec.Command("/bin/bash", tmpFile.Name())
+	cmd := stepProcess(ctx, "/bin/bash", tmpFile.Name())
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-
-	// Set environment variables
-	cmd.Env = append(os.Environ(),
+	cmd.Env = append(cmd.Env,
 		fmt.Sprintf("APP_NAME=%s", appName),
 		fmt.Sprintf("ENVIRONMENT_TYPE=%s", envType),
 	)
@@ -1064,7 +816,7 @@ func runPolicyStepWithSpinner(step types.Step, appName string, envType string, s
 	return nil
 }
 
-func runDummyStepWithSpinner(step types.Step, appName string, envType string, spinner *Spinner) error {
+func runDummyStepWithSpinner(_ context.Context, step types.Step, appName string, envType string, spinner *Spinner) error {
 	spinner.Update("Running dummy step...")
 	time.Sleep(1 * time.Second)
 	spinner.Update("Dummy step processing...")
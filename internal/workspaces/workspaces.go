@@ -0,0 +1,236 @@
+// Package workspaces lists and serves the files workflow steps generate per application
+// under workspaces/<app>: terraform directories, rendered manifests and cloned repositories.
+package workspaces
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultRoot is where workflow steps write application workspaces, relative to the
+// server's working directory
+const DefaultRoot = "workspaces"
+
+// DefaultDenyPatterns match files that may hold secrets. They are listed as restricted,
+// without a hash, and cannot be downloaded.
+var DefaultDenyPatterns = []string{
+	"*.tfstate",
+	"*.tfstate.*",
+	"*.tfvars",
+	"*.tfvars.json",
+	"*.tfplan",
+	".env",
+	".env.*",
+	"*.pem",
+	"*.key",
+	"*.p12",
+	"kubeconfig*",
+	".git-credentials",
+	"id_rsa*",
+	"id_ed25519*",
+}
+
+// skippedDirs are not descended into; they hold plugin binaries and repository history
+var skippedDirs = map[string]bool{".git": true, ".terraform": true}
+
+var (
+	// ErrNotFound is returned for paths that do not exist or are not regular files
+	ErrNotFound = errors.New("workspace file not found")
+
+	// ErrDenied is returned for paths matching a deny pattern
+	ErrDenied = errors.New("workspace file is restricted")
+)
+
+// File is one file in an application workspace
+type File struct {
+	Path       string    `json:"path"` // Slash-separated, relative to the workspace
+	Size       int64     `json:"size"`
+	SHA256     string    `json:"sha256,omitempty"`
+	ModifiedAt time.Time `json:"modified_at"`
+	Restricted bool      `json:"restricted,omitempty"`
+}
+
+// Workspaces gives access to application workspaces under a root directory
+type Workspaces struct {
+	root string
+	deny []string
+}
+
+// New returns workspaces under root. Nil deny uses DefaultDenyPatterns.
+func New(root string, deny []string) *Workspaces {
+	if deny == nil {
+		deny = DefaultDenyPatterns
+	}
+	return &Workspaces{root: root, deny: deny}
+}
+
+// Dir returns the workspace directory of an application
+func (w *Workspaces) Dir(appName string) (string, error) {
+	if appName == "" || appName == "." || appName == ".." || strings.ContainsAny(appName, `/\`) {
+		return "", fmt.Errorf("invalid application name %q", appName)
+	}
+	return filepath.Join(w.root, appName), nil
+}
+
+// Denied reports whether a workspace path matches a deny pattern. Every path segment is
+// checked, so files inside a denied directory are denied too.
+func (w *Workspaces) Denied(relPath string) bool {
+	for _, segment := range strings.Split(relPath, "/") {
+		for _, pattern := range w.deny {
+			if matched, _ := path.Match(pattern, segment); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// List returns the files of an application workspace sorted by path. A workspace that
+// does not exist yet has no files.
+func (w *Workspaces) List(appName string) ([]File, error) {
+	dir, err := w.Dir(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []File{}
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && skippedDirs[d.Name()] {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		// Symlinks are skipped; they may point outside the workspace
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		file := File{
+			Path:       filepath.ToSlash(rel),
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+			Restricted: w.Denied(filepath.ToSlash(rel)),
+		}
+		if !file.Restricted {
+			if file.SHA256, err = hashFile(p); err != nil {
+				return err
+			}
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace of %s: %w", appName, err)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// Size returns the bytes used by the files of an application workspace. Unlike List,
+// it does not hash the files.
+func (w *Workspaces) Size(appName string) (int64, error) {
+	dir, err := w.Dir(appName)
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == dir && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to size workspace of %s: %w", appName, err)
+	}
+	return size, nil
+}
+
+// Open opens a file of an application workspace for download. The path is resolved
+// inside the workspace, so neither ".." nor symlinks can escape it.
+func (w *Workspaces) Open(appName, relPath string) (*os.File, fs.FileInfo, error) {
+	dir, err := w.Dir(appName)
+	if err != nil {
+		return nil, nil, err
+	}
+	relPath = strings.TrimPrefix(path.Clean("/"+relPath), "/")
+	if relPath == "" {
+		return nil, nil, ErrNotFound
+	}
+	for _, segment := range strings.Split(relPath, "/") {
+		if skippedDirs[segment] {
+			return nil, nil, ErrNotFound
+		}
+	}
+	if w.Denied(relPath) {
+		return nil, nil, ErrDenied
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, nil, ErrNotFound
+	}
+	defer func() { _ = root.Close() }()
+
+	info, err := root.Lstat(relPath)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, nil, ErrNotFound
+	}
+	file, err := root.Open(relPath)
+	if err != nil {
+		return nil, nil, ErrNotFound
+	}
+	return file, info, nil
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p) // #nosec G304 - path comes from walking the workspace
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,120 @@
+package workspaces
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeWorkspaceFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+	require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+}
+
+func TestList(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceFile(t, root, "shop/terraform/main.tf", "resource {}")
+	writeWorkspaceFile(t, root, "shop/terraform/terraform.tfstate", "{}")
+	writeWorkspaceFile(t, root, "shop/terraform/prod.tfvars", "password = \"x\"")
+	writeWorkspaceFile(t, root, "shop/terraform/.terraform/providers/plugin", "binary")
+	writeWorkspaceFile(t, root, "shop/manifests/deployment.yaml", "kind: Deployment")
+	writeWorkspaceFile(t, root, "other/secret.yaml", "kind: Secret")
+	require.NoError(t, os.Symlink(filepath.Join(root, "other/secret.yaml"), filepath.Join(root, "shop/manifests/link.yaml")))
+
+	files, err := New(root, nil).List("shop")
+	require.NoError(t, err)
+
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	assert.Equal(t, []string{
+		"manifests/deployment.yaml",
+		"terraform/main.tf",
+		"terraform/prod.tfvars",
+		"terraform/terraform.tfstate",
+	}, paths)
+
+	assert.Equal(t, int64(16), files[0].Size)
+	assert.Equal(t, "ab78925c8f78d4cdd6eeb94fe3b474afabce46b2fd691715acc06b4141e6e0e5", files[0].SHA256)
+	assert.False(t, files[1].Restricted)
+	assert.True(t, files[2].Restricted)
+	assert.Empty(t, files[2].SHA256)
+	assert.True(t, files[3].Restricted)
+
+	missing, err := New(root, nil).List("new-app")
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	_, err = New(root, nil).List("..")
+	assert.Error(t, err)
+}
+
+func TestSize(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceFile(t, root, "shop/terraform/main.tf", "resource {}")
+	writeWorkspaceFile(t, root, "shop/terraform/.terraform/providers/plugin", "binary")
+	writeWorkspaceFile(t, root, "other/secret.yaml", "kind: Secret")
+	require.NoError(t, os.Symlink(filepath.Join(root, "other/secret.yaml"), filepath.Join(root, "shop/link.yaml")))
+
+	size, err := New(root, nil).Size("shop")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("resource {}")+len("binary")), size, "provider caches count, symlinks do not")
+
+	size, err = New(root, nil).Size("new-app")
+	require.NoError(t, err)
+	assert.Zero(t, size)
+}
+
+func TestOpen(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceFile(t, root, "shop/terraform/main.tf", "resource {}")
+	writeWorkspaceFile(t, root, "shop/terraform/terraform.tfstate", "{}")
+	writeWorkspaceFile(t, root, "other/secret.yaml", "kind: Secret")
+	require.NoError(t, os.Symlink(filepath.Join(root, "other"), filepath.Join(root, "shop/escape")))
+	ws := New(root, nil)
+
+	file, info, err := ws.Open("shop", "/terraform/../terraform/main.tf")
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	require.NoError(t, file.Close())
+	require.NoError(t, err)
+	assert.Equal(t, "resource {}", string(content))
+	assert.Equal(t, int64(11), info.Size())
+
+	tests := []struct {
+		name    string
+		relPath string
+		wantErr error
+	}{
+		{"denied file", "terraform/terraform.tfstate", ErrDenied},
+		{"parent traversal stays in workspace", "../other/secret.yaml", ErrNotFound},
+		{"symlink out of workspace", "escape/secret.yaml", ErrNotFound},
+		{"directory", "terraform", ErrNotFound},
+		{"missing file", "terraform/outputs.tf", ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ws.Open("shop", tt.relPath)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDenied(t *testing.T) {
+	ws := New(t.TempDir(), nil)
+	assert.True(t, ws.Denied("terraform/terraform.tfstate.backup"))
+	assert.True(t, ws.Denied("terraform/secrets.tfvars.json"))
+	assert.True(t, ws.Denied("certs/tls.key"))
+	assert.False(t, ws.Denied("terraform/variables.tf"))
+
+	custom := New(t.TempDir(), []string{"*.yaml"})
+	assert.True(t, custom.Denied("manifests/deployment.yaml"))
+	assert.False(t, custom.Denied("terraform/terraform.tfstate"))
+}
@@ -0,0 +1,38 @@
+-- Migration: Create maintenance windows and deferred operations tables
+-- Description: Per-application maintenance windows gate disruptive resource operations
+
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id SERIAL PRIMARY KEY,
+    application_name VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    days TEXT[] NOT NULL,
+    start_time VARCHAR(5) NOT NULL,
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0 AND duration_minutes <= 1440),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_maintenance_window_name UNIQUE (application_name, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_app ON maintenance_windows(application_name);
+
+-- Disruptive operations requested outside a window are queued here until it opens
+CREATE TABLE IF NOT EXISTS deferred_operations (
+    id SERIAL PRIMARY KEY,
+    application_name VARCHAR(255) NOT NULL,
+    resource_id INTEGER NOT NULL REFERENCES resource_instances(id) ON DELETE CASCADE,
+    operation VARCHAR(50) NOT NULL,
+    target_state VARCHAR(50) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    requested_by VARCHAR(255) NOT NULL,
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    scheduled_for TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'executed', 'failed', 'cancelled')),
+    executed_at TIMESTAMP WITH TIME ZONE NULL,
+    error_message TEXT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_deferred_operations_due ON deferred_operations(status, scheduled_for);
+CREATE INDEX IF NOT EXISTS idx_deferred_operations_app ON deferred_operations(application_name);
+
+COMMENT ON TABLE deferred_operations IS 'Disruptive operations queued until the next maintenance window opens';
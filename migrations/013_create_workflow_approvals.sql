@@ -0,0 +1,23 @@
+-- Migration: Create workflow approvals table
+-- Description: Approval gates pause a workflow step until a reviewer approves or rejects it
+
+CREATE TABLE IF NOT EXISTS workflow_approvals (
+    id SERIAL PRIMARY KEY,
+    execution_id BIGINT NOT NULL REFERENCES workflow_executions(id) ON DELETE CASCADE,
+    step_id BIGINT NOT NULL,
+    step_name VARCHAR(255) NOT NULL,
+    application_name VARCHAR(255) NOT NULL,
+    environment VARCHAR(100) NOT NULL DEFAULT '',
+    summary TEXT NOT NULL DEFAULT '',
+    artifact_path TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'expired')),
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    decided_by VARCHAR(255) NULL,
+    decided_at TIMESTAMP WITH TIME ZONE NULL,
+    comment TEXT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_approvals_status ON workflow_approvals(status);
+CREATE INDEX IF NOT EXISTS idx_workflow_approvals_execution ON workflow_approvals(execution_id);
+
+COMMENT ON TABLE workflow_approvals IS 'Pending and decided approval gates for workflow steps (e.g. terraform plan review)';
@@ -0,0 +1,30 @@
+-- Migration: Create deployment provenance table
+-- Description: Append-only provenance documents per deployment for compliance and supply-chain audits
+
+CREATE TABLE IF NOT EXISTS deployment_provenance (
+    id SERIAL PRIMARY KEY,
+    application_name VARCHAR(255) NOT NULL,
+    kind VARCHAR(50) NOT NULL,
+    -- Stored as TEXT, not JSONB, so the signed bytes are returned unchanged
+    document TEXT NOT NULL,
+    digest VARCHAR(100) NOT NULL,
+    signature TEXT NOT NULL DEFAULT '',
+    key_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_deployment_provenance_app ON deployment_provenance(application_name, created_at DESC);
+
+-- Records are immutable: reject updates and deletes
+CREATE OR REPLACE FUNCTION prevent_deployment_provenance_mutation() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'deployment_provenance records are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS deployment_provenance_immutable ON deployment_provenance;
+CREATE TRIGGER deployment_provenance_immutable
+    BEFORE UPDATE OR DELETE ON deployment_provenance
+    FOR EACH ROW EXECUTE FUNCTION prevent_deployment_provenance_mutation();
+
+COMMENT ON TABLE deployment_provenance IS 'Immutable, optionally signed provenance documents per deployment';
@@ -0,0 +1,6 @@
+-- Migration: Add workflow execution parameters
+-- Description: Stores the parameters a workflow ran with so runs can be exported and replayed
+
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS parameters JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN workflow_executions.parameters IS 'Golden path parameters and workflow variables the execution started with';
@@ -0,0 +1,11 @@
+-- Migration: Add workflow replay support
+-- Description: Links replayed executions to the original run and stores the external
+-- parameter sources so replays can resolve those values again
+
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS replay_of_id BIGINT REFERENCES workflow_executions(id) ON DELETE SET NULL;
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS parameter_sources JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+CREATE INDEX IF NOT EXISTS idx_workflow_executions_replay_of_id ON workflow_executions(replay_of_id);
+
+COMMENT ON COLUMN workflow_executions.replay_of_id IS 'References the original execution when this is a replay';
+COMMENT ON COLUMN workflow_executions.parameter_sources IS 'External sources (vault, http, configmap) of parameters that are resolved at execution time and never stored';
@@ -0,0 +1,22 @@
+-- Migration: Create cluster registry
+-- Description: Kubernetes clusters bootstrapped for innominatus, registered by the cluster-bootstrap golden path
+
+CREATE TABLE IF NOT EXISTS clusters (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    environment VARCHAR(100) NOT NULL,
+    api_server TEXT NOT NULL DEFAULT '',
+    kube_context VARCHAR(255) NOT NULL DEFAULT '',
+    labels JSONB NOT NULL DEFAULT '{}'::jsonb,
+    components TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'ready' CHECK (status IN ('ready', 'degraded', 'decommissioned')),
+    execution_id BIGINT REFERENCES workflow_executions(id) ON DELETE SET NULL,
+    registered_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_clusters_environment ON clusters(environment);
+
+COMMENT ON TABLE clusters IS 'Multi-cluster registry of Kubernetes clusters prepared for innominatus';
+COMMENT ON COLUMN clusters.components IS 'Platform components installed by the bootstrap (e.g. argocd, ingress-nginx, cert-manager)';
+COMMENT ON COLUMN clusters.execution_id IS 'Workflow execution that last registered the cluster';
@@ -0,0 +1,21 @@
+-- Migration: Create workflow compensations
+-- Description: Resources created by a workflow execution, kept so a failed run can roll back the partial set
+
+CREATE TABLE IF NOT EXISTS workflow_compensations (
+    id SERIAL PRIMARY KEY,
+    execution_id INTEGER NOT NULL REFERENCES workflow_executions(id) ON DELETE CASCADE,
+    step_name VARCHAR(255) NOT NULL,
+    kind VARCHAR(100) NOT NULL,
+    target TEXT NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}'::jsonb,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'rolled_back', 'failed')),
+    error_message TEXT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    compensated_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_compensations_execution ON workflow_compensations(execution_id);
+
+COMMENT ON TABLE workflow_compensations IS 'Resources created by workflow steps and how to remove them when the run fails';
+COMMENT ON COLUMN workflow_compensations.kind IS 'Compensator that undoes the resource (e.g. helm-release, kubernetes-object, gitea-repo, argocd-app)';
+COMMENT ON COLUMN workflow_compensations.params IS 'Parameters the compensator needs to remove the resource';
@@ -0,0 +1,6 @@
+-- Migration: Add workflow execution outputs
+-- Description: Stores the outputs a workflow declares, resolved from its step outputs when the run succeeds
+
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS outputs JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+COMMENT ON COLUMN workflow_executions.outputs IS 'Declared workflow outputs (name, type, value, description) resolved when the execution completed';
@@ -0,0 +1,33 @@
+-- Migration: Create user directory
+-- Description: Users and teams provisioned by an enterprise identity provider through SCIM 2.0
+
+CREATE TABLE IF NOT EXISTS directory_users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(255) NOT NULL UNIQUE,
+    external_id VARCHAR(255) NOT NULL DEFAULT '',
+    display_name VARCHAR(255) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS directory_teams (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    external_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS directory_team_members (
+    team_id INTEGER NOT NULL REFERENCES directory_teams(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES directory_users(id) ON DELETE CASCADE,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_directory_team_members_user ON directory_team_members(user_id);
+
+COMMENT ON TABLE directory_users IS 'Users provisioned by the identity provider; inactive users cannot log in';
+COMMENT ON COLUMN directory_users.external_id IS 'Identifier of the user in the identity provider';
+COMMENT ON TABLE directory_teams IS 'Teams provisioned as SCIM groups; membership sets the team of logged-in users';
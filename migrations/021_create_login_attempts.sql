@@ -0,0 +1,13 @@
+-- Migration: Create login attempts
+-- Description: Failed login counts per username and client IP, shared by all server replicas
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key VARCHAR(512) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_last_failure_at ON login_attempts(last_failure_at);
+
+COMMENT ON TABLE login_attempts IS 'Failed logins counted by the login throttle';
+COMMENT ON COLUMN login_attempts.key IS 'user:<username> or ip:<client address>';
@@ -0,0 +1,19 @@
+-- Migration: Create application files table
+-- Description: Auxiliary files (values.yaml, .env templates) uploaded together with a Score spec
+
+CREATE TABLE IF NOT EXISTS application_files (
+    id SERIAL PRIMARY KEY,
+    application_name VARCHAR(255) NOT NULL REFERENCES applications(name) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    content TEXT NOT NULL,
+    digest VARCHAR(100) NOT NULL,
+    spec_digest VARCHAR(100) NOT NULL,
+    uploaded_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (application_name, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_application_files_app ON application_files(application_name);
+
+COMMENT ON TABLE application_files IS 'Files uploaded with the current Score spec revision of an application';
+COMMENT ON COLUMN application_files.spec_digest IS 'Digest of the Score spec revision the files were uploaded with';
@@ -0,0 +1,16 @@
+-- Migration: Create application hibernations table
+-- Description: Applications whose workloads are scaled to zero and resources paused while idle
+
+CREATE TABLE IF NOT EXISTS application_hibernations (
+    application_name VARCHAR(255) PRIMARY KEY REFERENCES applications(name) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    hibernated_by VARCHAR(255) NOT NULL,
+    namespace VARCHAR(255) NOT NULL,
+    replicas JSONB NOT NULL DEFAULT '{}'::jsonb,
+    resource_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+    hibernated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE application_hibernations IS 'Currently hibernated applications; the row is removed on resume';
+COMMENT ON COLUMN application_hibernations.replicas IS 'Replicas of each workload (kind/name) before it was scaled to zero';
+COMMENT ON COLUMN application_hibernations.resource_ids IS 'Resource instances moved to the hibernated state';
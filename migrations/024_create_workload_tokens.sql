@@ -0,0 +1,17 @@
+-- Migration: Create workload tokens
+-- Description: Short-lived tokens issued to Kubernetes service accounts, shared by all server replicas
+
+CREATE TABLE IF NOT EXISTS workload_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    username VARCHAR(255) NOT NULL,
+    team VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_workload_tokens_expires_at ON workload_tokens(expires_at);
+
+COMMENT ON TABLE workload_tokens IS 'Tokens exchanged for Kubernetes service account tokens';
+COMMENT ON COLUMN workload_tokens.token_hash IS 'HMAC-SHA256 of the token; the token itself is not stored';
+COMMENT ON COLUMN workload_tokens.username IS 'system:serviceaccount:<namespace>:<name>';
@@ -0,0 +1,24 @@
+-- Migration: Create workflow schedules table
+-- Description: Golden paths run for an application on a cron schedule in an explicit IANA timezone
+
+CREATE TABLE IF NOT EXISTS workflow_schedules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    application_name VARCHAR(255) NOT NULL REFERENCES applications(name) ON DELETE CASCADE,
+    golden_path VARCHAR(255) NOT NULL,
+    cron VARCHAR(255) NOT NULL,
+    timezone VARCHAR(64) NOT NULL,
+    parameters JSONB NOT NULL DEFAULT '{}'::jsonb,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_run_at TIMESTAMP WITH TIME ZONE NULL,
+    next_run_at TIMESTAMP WITH TIME ZONE NULL,
+    CONSTRAINT unique_workflow_schedule_name UNIQUE (application_name, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_schedules_due ON workflow_schedules(enabled, next_run_at);
+
+COMMENT ON TABLE workflow_schedules IS 'Golden paths run on a cron schedule';
+COMMENT ON COLUMN workflow_schedules.timezone IS 'IANA timezone the cron expression is evaluated in';
+COMMENT ON COLUMN workflow_schedules.last_run_at IS 'Scheduled time of the last run, not when it finished';
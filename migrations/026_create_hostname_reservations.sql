@@ -0,0 +1,16 @@
+-- Migration: Create hostname reservations table
+-- Description: Hostnames generated from the naming convention, reserved per application and environment
+
+CREATE TABLE IF NOT EXISTS hostname_reservations (
+    hostname VARCHAR(253) PRIMARY KEY,
+    url TEXT NOT NULL,
+    application_name VARCHAR(255) NOT NULL REFERENCES applications(name) ON DELETE CASCADE,
+    environment VARCHAR(255) NOT NULL,
+    cluster VARCHAR(255) NOT NULL DEFAULT '',
+    reserved_by VARCHAR(255) NOT NULL,
+    reserved_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT unique_hostname_reservation_environment UNIQUE (application_name, environment)
+);
+
+COMMENT ON TABLE hostname_reservations IS 'Hostnames owned by applications; the primary key prevents two applications from sharing one';
+COMMENT ON COLUMN hostname_reservations.cluster IS 'Cluster whose naming pattern generated the hostname; empty for the default pattern';
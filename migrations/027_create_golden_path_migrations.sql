@@ -0,0 +1,23 @@
+-- Migration: Create golden path migrations table
+-- Description: Applications re-run from a deprecated golden path through its successor
+
+CREATE TABLE IF NOT EXISTS golden_path_migrations (
+    id SERIAL PRIMARY KEY,
+    golden_path VARCHAR(255) NOT NULL,
+    successor VARCHAR(255) NOT NULL,
+    application_name VARCHAR(255) NOT NULL REFERENCES applications(name) ON DELETE CASCADE,
+    team VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    execution_id BIGINT NULL REFERENCES workflow_executions(id) ON DELETE SET NULL,
+    parameters JSONB NOT NULL DEFAULT '{}'::jsonb,
+    error_message TEXT NULL,
+    started_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT valid_golden_path_migration_status CHECK (status IN ('pending', 'running', 'succeeded', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_golden_path_migrations_path ON golden_path_migrations(golden_path, team);
+
+COMMENT ON TABLE golden_path_migrations IS 'Applications migrated from a deprecated golden path to its successor';
+COMMENT ON COLUMN golden_path_migrations.parameters IS 'Parameters the successor ran with, after the compatibility parameter map';
@@ -0,0 +1,21 @@
+-- Migration: Create dependency reports table
+-- Description: Software bills of materials recorded by build and scan steps, per application version
+
+CREATE TABLE IF NOT EXISTS dependency_reports (
+    id SERIAL PRIMARY KEY,
+    application_name VARCHAR(255) NOT NULL REFERENCES applications(name) ON DELETE CASCADE,
+    version VARCHAR(255) NOT NULL,
+    execution_id BIGINT NULL REFERENCES workflow_executions(id) ON DELETE SET NULL,
+    step_name VARCHAR(255) NOT NULL,
+    format VARCHAR(50) NOT NULL,
+    image TEXT NOT NULL DEFAULT '',
+    base_image TEXT NOT NULL DEFAULT '',
+    components JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dependency_reports_version ON dependency_reports(application_name, version);
+
+COMMENT ON TABLE dependency_reports IS 'Components and licenses from SBOMs of build and scan steps';
+COMMENT ON COLUMN dependency_reports.base_image IS 'Operating system of the image base layers, e.g. alpine 3.19.1';
+COMMENT ON COLUMN dependency_reports.components IS 'Components with name, version, type, purl and licenses';
@@ -0,0 +1,8 @@
+-- Migration: Add workflow execution overrides
+-- Description: Records the step overrides an admin applied to a golden path run, so emergency fixes stay traceable
+
+ALTER TABLE workflow_executions ADD COLUMN IF NOT EXISTS overrides JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_workflow_executions_overridden ON workflow_executions(id) WHERE overrides IS NOT NULL;
+
+COMMENT ON COLUMN workflow_executions.overrides IS 'Admin step overrides (reason, requested_by, steps, variables) the execution ran with; NULL for regular runs';
@@ -0,0 +1,18 @@
+-- Migration: Create workflow temp assets table
+-- Description: Repository clones and manifest files steps leave in the temp directory, for garbage collection
+
+CREATE TABLE IF NOT EXISTS workflow_temp_assets (
+    id SERIAL PRIMARY KEY,
+    path TEXT NOT NULL UNIQUE,
+    execution_id BIGINT NULL REFERENCES workflow_executions(id) ON DELETE SET NULL,
+    application_name VARCHAR(255) NOT NULL DEFAULT '',
+    step_name VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_temp_assets_execution ON workflow_temp_assets(execution_id);
+
+COMMENT ON TABLE workflow_temp_assets IS 'Temp files and directories written by workflow steps, removed once their retention ends';
+COMMENT ON COLUMN workflow_temp_assets.execution_id IS 'Execution that last used the path; assets of running executions are kept';
+COMMENT ON COLUMN workflow_temp_assets.last_used_at IS 'When a step last used the path; paths are reused across executions';
@@ -0,0 +1,20 @@
+-- Migration: Create audit log table
+-- Description: Administrative actions that bypass the regular workflows, with who did them and why
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id SERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(100) NOT NULL,
+    target_id VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at DESC);
+
+COMMENT ON TABLE audit_log IS 'Administrative actions that bypass the regular workflows, e.g. forced resource state transitions';
+COMMENT ON COLUMN audit_log.action IS 'What was done, e.g. resource.force_state';
+COMMENT ON COLUMN audit_log.details IS 'Action specific data, e.g. the previous and new state';
@@ -0,0 +1,11 @@
+-- Migration: Add resource drift state
+-- Description: Whether the actual state of an active resource still matches its desired configuration
+
+ALTER TABLE resource_instances ADD COLUMN IF NOT EXISTS drift_state VARCHAR(20) NOT NULL DEFAULT 'unknown';
+ALTER TABLE resource_instances ADD COLUMN IF NOT EXISTS drift_message TEXT;
+ALTER TABLE resource_instances ADD COLUMN IF NOT EXISTS drift_checked_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_resource_instances_drifted ON resource_instances(id) WHERE drift_state = 'drifted';
+
+COMMENT ON COLUMN resource_instances.drift_state IS 'unknown (not checked or status unavailable), in_sync or drifted';
+COMMENT ON COLUMN resource_instances.drift_message IS 'What differs from the desired configuration, or why the status could not be read';
@@ -0,0 +1,16 @@
+-- Migration: Create provider version pins table
+-- Description: Provider versions applications or teams keep running after a platform-wide provider upgrade
+
+CREATE TABLE IF NOT EXISTS provider_version_pins (
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('application', 'team')),
+    scope_name VARCHAR(255) NOT NULL,
+    provider_name VARCHAR(255) NOT NULL,
+    version VARCHAR(100) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    pinned_by VARCHAR(255) NOT NULL,
+    pinned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scope, scope_name, provider_name)
+);
+
+COMMENT ON TABLE provider_version_pins IS 'Pinned provider versions; application pins override the pins of the application team';
+COMMENT ON COLUMN provider_version_pins.scope_name IS 'Application or team name, depending on scope';
@@ -0,0 +1,6 @@
+-- Migration: Bind approvals to the reviewed terraform plan
+-- Description: SHA-256 digest of the binary plan an approval gate was requested for; the apply fails when the plan changed
+
+ALTER TABLE workflow_approvals ADD COLUMN IF NOT EXISTS plan_hash VARCHAR(64) NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN workflow_approvals.plan_hash IS 'SHA-256 of the binary terraform plan that was reviewed, empty for approvals without a plan';
@@ -1,10 +1,20 @@
 package sdk
 
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 // Hint provides contextual information about a resource
 // Hints are displayed in the UI as quick-access cards with links, commands, and connection strings
 type Hint struct {
 	// Type identifies the kind of hint
-	// Valid types: "url", "dashboard", "command", "connection_string", "git_clone", "api_endpoint", "docs"
+	// Valid types: "url", "dashboard", "command", "connection_string", "git_clone", "api_endpoint", "docs",
+	// "grafana_panel", "log_query", "metrics_query"
 	Type string `json:"type" yaml:"type"`
 
 	// Label is the display name shown in the UI
@@ -18,6 +28,13 @@ type Hint struct {
 	// Icon is the optional icon identifier for UI display
 	// Example: "git-branch", "dashboard", "terminal", "database", "lock", "external-link"
 	Icon string `json:"icon,omitempty" yaml:"icon,omitempty"`
+
+	// Query is the log or metrics query of log_query and metrics_query hints, shown next to the link
+	// Example: "{namespace=\"shop\"} |= \"error\"", "rate(http_requests_total{app=\"shop\"}[5m])"
+	Query string `json:"query,omitempty" yaml:"query,omitempty"`
+
+	// Height is the height in pixels of an embedded grafana_panel (0 uses the UI default)
+	Height int `json:"height,omitempty" yaml:"height,omitempty"`
 }
 
 // HintType constants for common hint types
@@ -42,8 +59,20 @@ const (
 
 	// HintTypeDocs represents a documentation link
 	HintTypeDocs = "docs"
+
+	// HintTypeGrafanaPanel represents a Grafana panel URL (embedded inline in the UI)
+	HintTypeGrafanaPanel = "grafana_panel"
+
+	// HintTypeLogQuery represents a link to a log query in Grafana Explore, Loki or similar
+	HintTypeLogQuery = "log_query"
+
+	// HintTypeMetricsQuery represents a link to a metrics query in Prometheus or similar
+	HintTypeMetricsQuery = "metrics_query"
 )
 
+// DefaultPanelHeight is the height in pixels of embedded Grafana panels created by NewGrafanaPanelHint
+const DefaultPanelHeight = 300
+
 // IconType constants for common icons
 const (
 	IconGitBranch    = "git-branch"
@@ -57,6 +86,8 @@ const (
 	IconGlobe        = "globe"
 	IconKey          = "key"
 	IconBook         = "book"
+	IconChart        = "chart"
+	IconLogs         = "logs"
 )
 
 // NewURLHint creates a hint for a clickable URL
@@ -98,3 +129,103 @@ func NewDashboardHint(label, url string) Hint {
 		Icon:  IconExternalLink,
 	}
 }
+
+// NewGrafanaPanelHint creates a hint embedding a single Grafana panel. vars become var-<name>
+// dashboard variables, e.g. {"namespace": "shop"} adds var-namespace=shop.
+func NewGrafanaPanelHint(label, grafanaURL, dashboardUID string, panelID int, vars map[string]string) Hint {
+	params := url.Values{}
+	params.Set("panelId", strconv.Itoa(panelID))
+	for name, value := range vars {
+		params.Set("var-"+name, value)
+	}
+	return Hint{
+		Type:   HintTypeGrafanaPanel,
+		Label:  label,
+		Value:  fmt.Sprintf("%s/d-solo/%s?%s", strings.TrimRight(grafanaURL, "/"), url.PathEscape(dashboardUID), params.Encode()),
+		Icon:   IconChart,
+		Height: DefaultPanelHeight,
+	}
+}
+
+// NewLogQueryHint creates a hint linking to a log query. url opens the query, query is shown in the UI.
+func NewLogQueryHint(label, url, query string) Hint {
+	return Hint{
+		Type:  HintTypeLogQuery,
+		Label: label,
+		Value: url,
+		Icon:  IconLogs,
+		Query: query,
+	}
+}
+
+// NewMetricsQueryHint creates a hint opening a PromQL query in the Prometheus graph view
+func NewMetricsQueryHint(label, prometheusURL, query string) Hint {
+	params := url.Values{}
+	params.Set("g0.expr", query)
+	params.Set("g0.tab", "0")
+	return Hint{
+		Type:  HintTypeMetricsQuery,
+		Label: label,
+		Value: strings.TrimRight(prometheusURL, "/") + "/graph?" + params.Encode(),
+		Icon:  IconChart,
+		Query: query,
+	}
+}
+
+// outputPattern matches ${outputs.<name>} in hint templates, also in its query-escaped form
+// %24%7Boutputs.<name>%7D so templated queries passed to NewMetricsQueryHint still render
+var outputPattern = regexp.MustCompile(`(?:\$\{|%24%7B)outputs\.([A-Za-z0-9_\-]+)(?:\}|%7D)`)
+
+// Render returns a copy of the hint with ${outputs.<name>} in Label, Value and Query replaced by
+// the resource outputs. It fails if the template references an output the resource does not have.
+// Replacements in a URL Value are query-escaped, so a namespace output can safely go into a query string.
+func (h Hint) Render(outputs map[string]string) (Hint, error) {
+	var missing []string
+	replace := func(s string, escape bool) string {
+		return outputPattern.ReplaceAllStringFunc(s, func(match string) string {
+			name := outputPattern.FindStringSubmatch(match)[1]
+			value, ok := outputs[name]
+			if !ok {
+				missing = append(missing, name)
+				return match
+			}
+			if escape {
+				return url.QueryEscape(value)
+			}
+			return value
+		})
+	}
+
+	rendered := h
+	rendered.Label = replace(h.Label, false)
+	rendered.Value = replace(h.Value, h.isURL())
+	rendered.Query = replace(h.Query, false)
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return h, fmt.Errorf("hint '%s' references unknown outputs: %v", h.Label, missing)
+	}
+	return rendered, nil
+}
+
+// isURL reports whether the hint value is a URL opened by the UI
+func (h Hint) isURL() bool {
+	switch h.Type {
+	case HintTypeURL, HintTypeDashboard, HintTypeAPIEndpoint, HintTypeDocs,
+		HintTypeGrafanaPanel, HintTypeLogQuery, HintTypeMetricsQuery:
+		return true
+	}
+	return false
+}
+
+// RenderHints renders hint templates against the resource outputs, see Hint.Render
+func RenderHints(hints []Hint, outputs map[string]string) ([]Hint, error) {
+	rendered := make([]Hint, 0, len(hints))
+	for _, hint := range hints {
+		r, err := hint.Render(outputs)
+		if err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, r)
+	}
+	return rendered, nil
+}
@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Deployment labels innominatus applies to the Kubernetes objects and Terraform resources
+// workflow steps create, for cost allocation and incident response
+const (
+	LabelApp                 = "app"
+	LabelTeam                = "team"
+	LabelEnvironment         = "environment"
+	LabelManagedBy           = "managed-by"
+	LabelWorkflowExecutionID = "workflow-execution-id"
+)
+
+// ManagedByInnominatus is the value of the managed-by label
+const ManagedByInnominatus = "innominatus"
+
+// UnassignedLabelValue is the team or environment label of applications without one
+const UnassignedLabelValue = "unassigned"
+
+// maxLabelValueLength is the longest Kubernetes label value
+const maxLabelValueLength = 63
+
+// DeploymentLabelKeys lists the deployment labels in a stable order
+var DeploymentLabelKeys = []string{LabelApp, LabelTeam, LabelEnvironment, LabelManagedBy, LabelWorkflowExecutionID}
+
+// DeploymentLabels returns the deployment labels of a workflow execution. An empty team
+// or environment is labeled unassigned.
+func DeploymentLabels(app, team, environment string, executionID int64) map[string]string {
+	if team == "" {
+		team = UnassignedLabelValue
+	}
+	if environment == "" {
+		environment = UnassignedLabelValue
+	}
+	return map[string]string{
+		LabelApp:                 LabelValue(app),
+		LabelTeam:                LabelValue(team),
+		LabelEnvironment:         LabelValue(environment),
+		LabelManagedBy:           ManagedByInnominatus,
+		LabelWorkflowExecutionID: strconv.FormatInt(executionID, 10),
+	}
+}
+
+// MissingDeploymentLabels returns the deployment labels that labels lacks or has empty
+func MissingDeploymentLabels(labels map[string]string) []string {
+	var missing []string
+	for _, key := range DeploymentLabelKeys {
+		if labels[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// LabelValue makes value a valid Kubernetes label value, which is also accepted as a tag
+// by the major clouds: characters other than letters, digits, '-', '_' and '.' become
+// '-', and the value is cut to 63 characters and begins and ends alphanumeric.
+func LabelValue(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+	result := b.String()
+	if len(result) > maxLabelValueLength {
+		result = result[:maxLabelValueLength]
+	}
+	return strings.Trim(result, "-_.")
+}
@@ -0,0 +1,45 @@
+package sdk_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"innominatus/pkg/sdk"
+)
+
+func TestDeploymentLabels(t *testing.T) {
+	want := map[string]string{
+		"app":                   "shop",
+		"team":                  "payments",
+		"environment":           "unassigned",
+		"managed-by":            "innominatus",
+		"workflow-execution-id": "42",
+	}
+	labels := sdk.DeploymentLabels("shop", "payments", "", 42)
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("DeploymentLabels() = %v, want %v", labels, want)
+	}
+	if missing := sdk.MissingDeploymentLabels(labels); len(missing) != 0 {
+		t.Errorf("Expected no missing labels, got %v", missing)
+	}
+
+	missing := sdk.MissingDeploymentLabels(map[string]string{"app": "shop", "team": "", "managed-by": "innominatus"})
+	if !reflect.DeepEqual(missing, []string{"team", "environment", "workflow-execution-id"}) {
+		t.Errorf("MissingDeploymentLabels() = %v", missing)
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	tests := map[string]string{
+		"shop":                  "shop",
+		"Team Payments/EU":      "Team-Payments-EU",
+		"-platform-":            "platform",
+		strings.Repeat("a", 70): strings.Repeat("a", 63),
+	}
+	for value, want := range tests {
+		if got := sdk.LabelValue(value); got != want {
+			t.Errorf("LabelValue(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
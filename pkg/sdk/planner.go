@@ -0,0 +1,199 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Planner is an optional interface for provisioners that can preview an operation without
+// applying it. The orchestration engine calls Plan for dry runs and before updates.
+//
+// Example:
+//
+//	func (p *RDSProvisioner) Plan(ctx context.Context, resource *sdk.Resource, config sdk.Config) (*sdk.Plan, error) {
+//		return sdk.NewPlan(resource.Configuration, config), nil
+//	}
+type Planner interface {
+	// Plan returns the changes applying config to resource would make, without changing
+	// anything. resource is the resource as innominatus knows it; its Configuration is empty
+	// for resources that do not exist yet. Planners that can read the live state should
+	// diff against it instead.
+	Plan(ctx context.Context, resource *Resource, config Config) (*Plan, error)
+}
+
+// PlanAction summarizes what applying a plan does to a resource
+type PlanAction string
+
+const (
+	PlanActionCreate PlanAction = "create"
+	PlanActionUpdate PlanAction = "update"
+	PlanActionDelete PlanAction = "delete"
+	PlanActionNoop   PlanAction = "no-op"
+)
+
+// ChangeAction is what happens to a single attribute
+type ChangeAction string
+
+const (
+	ChangeActionAdd    ChangeAction = "add"
+	ChangeActionChange ChangeAction = "change"
+	ChangeActionRemove ChangeAction = "remove"
+)
+
+// Plan is the structured diff of an operation on a resource
+type Plan struct {
+	// Action summarizes the plan
+	Action PlanAction `json:"action"`
+
+	// Changes lists attribute changes sorted by path
+	Changes []PlannedChange `json:"changes,omitempty"`
+
+	// Replace is set when the change cannot be applied in place and the resource is recreated
+	Replace bool `json:"replace,omitempty"`
+
+	// Notes are provisioner remarks such as expected downtime
+	Notes []string `json:"notes,omitempty"`
+}
+
+// PlannedChange is the change of one attribute. Path uses dots for nested keys, e.g. "storage.size".
+type PlannedChange struct {
+	Path      string       `json:"path"`
+	Action    ChangeAction `json:"action"`
+	Before    interface{}  `json:"before,omitempty"`
+	After     interface{}  `json:"after,omitempty"`
+	Sensitive bool         `json:"sensitive,omitempty"` // Values are hidden in String
+}
+
+// NewPlan diffs the current configuration against the desired one. A nil or empty current
+// configuration plans a create.
+func NewPlan(current, desired Config) *Plan {
+	before, after := configMap(current), configMap(desired)
+	plan := &Plan{Changes: DiffConfig(before, after)}
+	switch {
+	case len(before) == 0:
+		plan.Action = PlanActionCreate
+	case len(plan.Changes) == 0:
+		plan.Action = PlanActionNoop
+	default:
+		plan.Action = PlanActionUpdate
+	}
+	return plan
+}
+
+// HasChanges reports whether applying the plan changes anything
+func (p *Plan) HasChanges() bool {
+	return p != nil && p.Action != PlanActionNoop
+}
+
+// String renders the plan as a human-readable diff:
+//
+//	~ update
+//	  + backup.enabled = true
+//	  ~ storage.size: "10Gi" -> "20Gi"
+//	  - replicas = 2
+func (p *Plan) String() string {
+	if p == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	symbol := map[PlanAction]string{PlanActionCreate: "+", PlanActionUpdate: "~", PlanActionDelete: "-", PlanActionNoop: "="}[p.Action]
+	fmt.Fprintf(&b, "%s %s", symbol, p.Action)
+	if p.Replace {
+		b.WriteString(" (replace)")
+	}
+	b.WriteString("\n")
+
+	for _, change := range p.Changes {
+		before, after := formatPlanValue(change.Before, change.Sensitive), formatPlanValue(change.After, change.Sensitive)
+		switch change.Action {
+		case ChangeActionAdd:
+			fmt.Fprintf(&b, "  + %s = %s\n", change.Path, after)
+		case ChangeActionRemove:
+			fmt.Fprintf(&b, "  - %s = %s\n", change.Path, before)
+		default:
+			fmt.Fprintf(&b, "  ~ %s: %s -> %s\n", change.Path, before, after)
+		}
+	}
+	for _, note := range p.Notes {
+		fmt.Fprintf(&b, "  # %s\n", note)
+	}
+	return b.String()
+}
+
+// DiffConfig compares two configurations key by key. Nested maps are compared per key;
+// other values, including lists, are compared as a whole.
+func DiffConfig(before, after map[string]interface{}) []PlannedChange {
+	var changes []PlannedChange
+	diffConfig("", before, after, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func diffConfig(prefix string, before, after map[string]interface{}, changes *[]PlannedChange) {
+	for key, old := range before {
+		path := prefix + key
+		value, exists := after[key]
+		if !exists {
+			*changes = append(*changes, PlannedChange{Path: path, Action: ChangeActionRemove, Before: old})
+			continue
+		}
+		oldMap, oldIsMap := old.(map[string]interface{})
+		newMap, newIsMap := value.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			diffConfig(path+".", oldMap, newMap, changes)
+			continue
+		}
+		if !reflect.DeepEqual(normalizePlanValue(old), normalizePlanValue(value)) {
+			*changes = append(*changes, PlannedChange{Path: path, Action: ChangeActionChange, Before: old, After: value})
+		}
+	}
+	for key, value := range after {
+		if _, exists := before[key]; !exists {
+			*changes = append(*changes, PlannedChange{Path: prefix + key, Action: ChangeActionAdd, After: value})
+		}
+	}
+}
+
+// normalizePlanValue makes values decoded from YAML and JSON comparable, e.g. int 2 and float64 2
+func normalizePlanValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+func formatPlanValue(value interface{}, sensitive bool) string {
+	if sensitive {
+		return "(sensitive)"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// configMap returns the values of a Config as a map
+func configMap(config Config) map[string]interface{} {
+	values := make(map[string]interface{})
+	if config == nil {
+		return values
+	}
+	if v := reflect.ValueOf(config); v.Kind() == reflect.Ptr && v.IsNil() {
+		return values
+	}
+	for _, key := range config.Keys() {
+		values[key] = config.Get(key)
+	}
+	return values
+}
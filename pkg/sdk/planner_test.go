@@ -0,0 +1,95 @@
+package sdk_test
+
+import (
+	"reflect"
+	"testing"
+
+	"innominatus/pkg/sdk"
+)
+
+func TestDiffConfig(t *testing.T) {
+	before := map[string]interface{}{
+		"replicas": 2,
+		"version":  "15",
+		"storage":  map[string]interface{}{"size": "10Gi", "class": "standard"},
+		"tags":     []interface{}{"prod"},
+	}
+	after := map[string]interface{}{
+		"replicas": float64(2), // decoded from JSON
+		"version":  "16",
+		"storage":  map[string]interface{}{"size": "20Gi", "class": "standard"},
+		"tags":     []interface{}{"prod"},
+		"backup":   true,
+	}
+
+	want := []sdk.PlannedChange{
+		{Path: "backup", Action: sdk.ChangeActionAdd, After: true},
+		{Path: "storage.size", Action: sdk.ChangeActionChange, Before: "10Gi", After: "20Gi"},
+		{Path: "version", Action: sdk.ChangeActionChange, Before: "15", After: "16"},
+	}
+	if got := sdk.DiffConfig(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffConfig() = %+v, want %+v", got, want)
+	}
+
+	removed := sdk.DiffConfig(before, map[string]interface{}{"replicas": 2, "version": "15", "tags": []interface{}{"prod"}})
+	if len(removed) != 1 || removed[0].Path != "storage" || removed[0].Action != sdk.ChangeActionRemove {
+		t.Errorf("Expected storage to be removed, got %+v", removed)
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	current := sdk.NewMapConfig(map[string]interface{}{"size": "10Gi"})
+
+	tests := []struct {
+		name    string
+		current sdk.Config
+		desired sdk.Config
+		action  sdk.PlanAction
+		changes int
+	}{
+		{"create without configuration", nil, sdk.NewMapConfig(map[string]interface{}{"size": "10Gi"}), sdk.PlanActionCreate, 1},
+		{"create with empty configuration", sdk.NewMapConfig(nil), sdk.NewMapConfig(map[string]interface{}{"size": "10Gi"}), sdk.PlanActionCreate, 1},
+		{"update", current, sdk.NewMapConfig(map[string]interface{}{"size": "20Gi"}), sdk.PlanActionUpdate, 1},
+		{"no-op", current, sdk.NewMapConfig(map[string]interface{}{"size": "10Gi"}), sdk.PlanActionNoop, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := sdk.NewPlan(tt.current, tt.desired)
+			if plan.Action != tt.action {
+				t.Errorf("Action = %s, want %s", plan.Action, tt.action)
+			}
+			if len(plan.Changes) != tt.changes {
+				t.Errorf("Changes = %+v, want %d", plan.Changes, tt.changes)
+			}
+			if plan.HasChanges() != (tt.action != sdk.PlanActionNoop) {
+				t.Errorf("HasChanges() = %v for %s", plan.HasChanges(), tt.action)
+			}
+		})
+	}
+}
+
+func TestPlanString(t *testing.T) {
+	plan := &sdk.Plan{
+		Action:  sdk.PlanActionUpdate,
+		Replace: true,
+		Changes: []sdk.PlannedChange{
+			{Path: "backup.enabled", Action: sdk.ChangeActionAdd, After: true},
+			{Path: "password", Action: sdk.ChangeActionChange, Before: "old", After: "new", Sensitive: true},
+			{Path: "replicas", Action: sdk.ChangeActionRemove, Before: 2},
+			{Path: "storage.size", Action: sdk.ChangeActionChange, Before: "10Gi", After: "20Gi"},
+		},
+		Notes: []string{"the instance restarts"},
+	}
+
+	want := `~ update (replace)
+  + backup.enabled = true
+  ~ password: (sensitive) -> (sensitive)
+  - replicas = 2
+  ~ storage.size: "10Gi" -> "20Gi"
+  # the instance restarts
+`
+	if got := plan.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -1,5 +1,11 @@
 package sdk
 
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
 // Provider represents a provider implementation with its metadata and capabilities
 // Providers are defined via provider.yaml manifests (or legacy platform.yaml)
 type Provider struct {
@@ -15,6 +21,9 @@ type Provider struct {
 	// Compatibility defines core version requirements
 	Compatibility ProviderCompatibility `yaml:"compatibility" json:"compatibility"`
 
+	// Dependencies lists providers that must be registered before this one
+	Dependencies []ProviderDependency `yaml:"dependencies,omitempty" json:"dependencies,omitempty"`
+
 	// Capabilities declares what resource types this provider can handle
 	Capabilities ProviderCapabilities `yaml:"capabilities,omitempty" json:"capabilities,omitempty"`
 
@@ -31,6 +40,13 @@ type Provider struct {
 
 	// Configuration contains provider-specific configuration
 	Configuration map[string]interface{} `yaml:"configuration,omitempty" json:"configuration,omitempty"`
+
+	// Readme is a markdown file introducing the provider, relative to provider.yaml
+	// Example: "./README.md"
+	Readme string `yaml:"readme,omitempty" json:"readme,omitempty"`
+
+	// Dir is the directory provider.yaml was loaded from; docs and examples are read from it
+	Dir string `yaml:"-" json:"-"`
 }
 
 // ProviderMetadata contains identification and versioning information
@@ -78,6 +94,17 @@ type ProviderCompatibility struct {
 	MaxCoreVersion string `yaml:"maxCoreVersion" json:"maxCoreVersion"`
 }
 
+// ProviderDependency is another provider this provider needs, e.g. a "gitops" provider
+// whose workflows use the repositories created by the "gitea" provider
+type ProviderDependency struct {
+	// Name is the metadata.name of the required provider
+	Name string `yaml:"name" json:"name"`
+
+	// Version is a semantic version range the required provider must satisfy
+	// Example: ">= 1.2.0, < 2.0.0", "~1.4"; empty accepts any version
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+}
+
 // ProviderCapabilities declares what resource types this provider can handle
 // Used for automatic resource-to-provider matching during orchestration
 type ProviderCapabilities struct {
@@ -105,6 +132,53 @@ type ResourceTypeCapability struct {
 	// AliasFor indicates this is an alias for another resource type
 	// Example: "postgresql" is an alias for "postgres"
 	AliasFor string `yaml:"aliasFor,omitempty" json:"aliasFor,omitempty"`
+
+	// Deprecated marks the resource type name as deprecated. Specs using it still resolve
+	// with a warning until Deprecated.RemoveAfter, and are rejected afterwards.
+	Deprecated *ResourceTypeDeprecation `yaml:"deprecated,omitempty" json:"deprecated,omitempty"`
+}
+
+// ResourceTypeDeprecation is the deprecation notice of a resource type name
+type ResourceTypeDeprecation struct {
+	// Message explains the deprecation
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+
+	// Replacement is the resource type to use instead; defaults to aliasFor for aliases
+	Replacement string `yaml:"replacement,omitempty" json:"replacement,omitempty"`
+
+	// RemoveAfter ends the grace period (YYYY-MM-DD, UTC). The name resolves until the end
+	// of that day. Empty keeps the name resolving indefinitely.
+	RemoveAfter string `yaml:"removeAfter,omitempty" json:"removeAfter,omitempty"`
+}
+
+// removeAfterLayout is the date format of ResourceTypeDeprecation.RemoveAfter
+const removeAfterLayout = "2006-01-02"
+
+// Expired reports whether the grace period of the deprecation ended before now
+func (d *ResourceTypeDeprecation) Expired(now time.Time) bool {
+	if d.RemoveAfter == "" {
+		return false
+	}
+	removeAfter, err := time.Parse(removeAfterLayout, d.RemoveAfter)
+	if err != nil {
+		return false
+	}
+	return !now.Before(removeAfter.AddDate(0, 0, 1))
+}
+
+// Notice describes the deprecation of resourceType for validation output
+func (d *ResourceTypeDeprecation) Notice(resourceType string) string {
+	notice := fmt.Sprintf("resource type '%s' is deprecated", resourceType)
+	if d.RemoveAfter != "" {
+		notice += fmt.Sprintf(" and stops resolving after %s", d.RemoveAfter)
+	}
+	if d.Replacement != "" {
+		notice += fmt.Sprintf("; use '%s' instead", d.Replacement)
+	}
+	if d.Message != "" {
+		notice += fmt.Sprintf(": %s", d.Message)
+	}
+	return notice
 }
 
 // OperationWorkflow defines which workflow(s) handle a specific operation
@@ -155,6 +229,13 @@ type WorkflowMetadata struct {
 
 	// Tags are searchable keywords
 	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+
+	// Docs is a markdown file describing the workflow, relative to provider.yaml
+	// Example: "./docs/provision-postgres.md"
+	Docs string `yaml:"docs,omitempty" json:"docs,omitempty"`
+
+	// Examples are files showing how to use the workflow, e.g. Score specs, relative to provider.yaml
+	Examples []string `yaml:"examples,omitempty" json:"examples,omitempty"`
 }
 
 // GoldenPathMetadata is deprecated. Use WorkflowMetadata with category="goldenpath" instead.
@@ -202,6 +283,19 @@ func (p *Provider) Validate() error {
 		if wf.Operation != "" && wf.Operation != "create" && wf.Operation != "read" && wf.Operation != "update" && wf.Operation != "delete" {
 			return ErrInvalidProvider("workflows[%d].operation must be 'create', 'read', 'update', or 'delete', got '%s'", i, wf.Operation)
 		}
+		// Docs and examples are served by the API, so they must stay inside the provider directory
+		if wf.Docs != "" && !filepath.IsLocal(wf.Docs) {
+			return ErrInvalidProvider("workflows[%d].docs must be a path inside the provider directory, got '%s'", i, wf.Docs)
+		}
+		for j, example := range wf.Examples {
+			if !filepath.IsLocal(example) {
+				return ErrInvalidProvider("workflows[%d].examples[%d] must be a path inside the provider directory, got '%s'", i, j, example)
+			}
+		}
+	}
+
+	if p.Readme != "" && !filepath.IsLocal(p.Readme) {
+		return ErrInvalidProvider("readme must be a path inside the provider directory, got '%s'", p.Readme)
 	}
 
 	// Validate provisioners (deprecated but still supported)
@@ -217,11 +311,35 @@ func (p *Provider) Validate() error {
 		}
 	}
 
+	// Validate dependencies
+	seen := make(map[string]bool, len(p.Dependencies))
+	for i, dep := range p.Dependencies {
+		if dep.Name == "" {
+			return ErrInvalidProvider("dependencies[%d].name is required", i)
+		}
+		if dep.Name == p.Metadata.Name {
+			return ErrInvalidProvider("dependencies[%d]: provider cannot depend on itself", i)
+		}
+		if seen[dep.Name] {
+			return ErrInvalidProvider("dependencies[%d]: duplicate dependency on %s", i, dep.Name)
+		}
+		seen[dep.Name] = true
+	}
+
 	// Validate resource type capabilities for circular references
 	if err := p.validateAliasReferences(); err != nil {
 		return err
 	}
 
+	for i, rtc := range p.Capabilities.ResourceTypeCapabilities {
+		if rtc.Deprecated == nil || rtc.Deprecated.RemoveAfter == "" {
+			continue
+		}
+		if _, err := time.Parse(removeAfterLayout, rtc.Deprecated.RemoveAfter); err != nil {
+			return ErrInvalidProvider("resourceTypeCapabilities[%d].deprecated.removeAfter must be a date (YYYY-MM-DD), got '%s'", i, rtc.Deprecated.RemoveAfter)
+		}
+	}
+
 	return nil
 }
 
@@ -307,6 +425,24 @@ func (p *Provider) CanProvisionResourceType(resourceType string) bool {
 	return false
 }
 
+// ResourceTypeDeprecation returns the deprecation notice declared for a resource type
+// name, with Replacement defaulted to the type an alias points to, or nil when the name
+// is not deprecated
+func (p *Provider) ResourceTypeDeprecation(resourceType string) *ResourceTypeDeprecation {
+	for i := range p.Capabilities.ResourceTypeCapabilities {
+		rtc := &p.Capabilities.ResourceTypeCapabilities[i]
+		if rtc.Type != resourceType || rtc.Deprecated == nil {
+			continue
+		}
+		deprecation := *rtc.Deprecated
+		if deprecation.Replacement == "" {
+			deprecation.Replacement = rtc.AliasFor
+		}
+		return &deprecation
+	}
+	return nil
+}
+
 // GetProvisionerWorkflow finds the provisioner workflow for automatic resource provisioning
 // Returns the first workflow with category="provisioner"
 func (p *Provider) GetProvisionerWorkflow() *WorkflowMetadata {
@@ -2,6 +2,7 @@ package sdk_test
 
 import (
 	"testing"
+	"time"
 
 	"innominatus/pkg/sdk"
 )
@@ -188,6 +189,77 @@ func TestHintHelpers(t *testing.T) {
 	}
 }
 
+func TestObservabilityHints(t *testing.T) {
+	// Test NewGrafanaPanelHint
+	panel := sdk.NewGrafanaPanelHint("CPU", "https://grafana.example.com/", "k8s-pods", 4, map[string]string{"namespace": "shop"})
+	if panel.Type != sdk.HintTypeGrafanaPanel {
+		t.Errorf("Expected type='%s', got '%s'", sdk.HintTypeGrafanaPanel, panel.Type)
+	}
+	if want := "https://grafana.example.com/d-solo/k8s-pods?panelId=4&var-namespace=shop"; panel.Value != want {
+		t.Errorf("Expected value='%s', got '%s'", want, panel.Value)
+	}
+	if panel.Height != sdk.DefaultPanelHeight {
+		t.Errorf("Expected height=%d, got %d", sdk.DefaultPanelHeight, panel.Height)
+	}
+
+	// Test NewLogQueryHint
+	logs := sdk.NewLogQueryHint("Errors", "https://grafana.example.com/explore", `{namespace="shop"} |= "error"`)
+	if logs.Type != sdk.HintTypeLogQuery || logs.Query != `{namespace="shop"} |= "error"` {
+		t.Errorf("Unexpected log query hint: %+v", logs)
+	}
+
+	// Test NewMetricsQueryHint
+	metrics := sdk.NewMetricsQueryHint("Requests", "https://prometheus.example.com", `rate(http_requests_total[5m])`)
+	if want := "https://prometheus.example.com/graph?g0.expr=rate%28http_requests_total%5B5m%5D%29&g0.tab=0"; metrics.Value != want {
+		t.Errorf("Expected value='%s', got '%s'", want, metrics.Value)
+	}
+	if metrics.Icon != sdk.IconChart {
+		t.Errorf("Expected icon='%s', got '%s'", sdk.IconChart, metrics.Icon)
+	}
+}
+
+func TestHintRender(t *testing.T) {
+	outputs := map[string]string{"namespace": "shop prod", "bucket": "assets"}
+
+	// Panel variables are escaped in URLs, labels are not
+	panel := sdk.NewGrafanaPanelHint("Pods in ${outputs.namespace}", "https://grafana.example.com", "k8s", 2, map[string]string{"namespace": "${outputs.namespace}"})
+	rendered, err := panel.Render(outputs)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if rendered.Label != "Pods in shop prod" {
+		t.Errorf("Expected label='Pods in shop prod', got '%s'", rendered.Label)
+	}
+	if want := "https://grafana.example.com/d-solo/k8s?panelId=2&var-namespace=shop+prod"; rendered.Value != want {
+		t.Errorf("Expected value='%s', got '%s'", want, rendered.Value)
+	}
+
+	// Templated queries render in both the query and the link
+	metrics := sdk.NewMetricsQueryHint("Bucket size", "https://prometheus.example.com", `minio_bucket_usage_total_bytes{bucket="${outputs.bucket}"}`)
+	rendered, err = metrics.Render(outputs)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if rendered.Query != `minio_bucket_usage_total_bytes{bucket="assets"}` {
+		t.Errorf("Unexpected query '%s'", rendered.Query)
+	}
+	if want := "https://prometheus.example.com/graph?g0.expr=minio_bucket_usage_total_bytes%7Bbucket%3D%22assets%22%7D&g0.tab=0"; rendered.Value != want {
+		t.Errorf("Expected value='%s', got '%s'", want, rendered.Value)
+	}
+
+	// Commands are not escaped
+	cmd := sdk.NewCommandHint("Pods", "kubectl get pods -n ${outputs.namespace}", sdk.IconTerminal)
+	rendered, _ = cmd.Render(outputs)
+	if rendered.Value != "kubectl get pods -n shop prod" {
+		t.Errorf("Unexpected command '%s'", rendered.Value)
+	}
+
+	// Unknown outputs fail
+	if _, err := sdk.RenderHints([]sdk.Hint{cmd, sdk.NewURLHint("Console", "${outputs.console_url}", sdk.IconGlobe)}, outputs); err == nil {
+		t.Error("Expected error for unknown output")
+	}
+}
+
 func TestPlatformValidation(t *testing.T) {
 	// Valid platform
 	validPlatform := &sdk.Platform{
@@ -232,6 +304,73 @@ func TestPlatformValidation(t *testing.T) {
 	if err := invalidPlatform.Validate(); err == nil {
 		t.Error("Expected invalid platform to fail validation")
 	}
+
+	// Invalid dependencies
+	for _, deps := range [][]sdk.ProviderDependency{
+		{{Name: ""}},
+		{{Name: "test-platform"}},
+		{{Name: "gitea"}, {Name: "gitea", Version: ">= 1.0.0"}},
+	} {
+		validPlatform.Dependencies = deps
+		if err := validPlatform.Validate(); err == nil {
+			t.Errorf("Expected dependencies %+v to fail validation", deps)
+		}
+	}
+	validPlatform.Dependencies = nil
+
+	// Docs must stay inside the provider directory
+	validPlatform.Readme = "../README.md"
+	if err := validPlatform.Validate(); err == nil {
+		t.Error("Expected readme outside the provider directory to fail validation")
+	}
+	validPlatform.Readme = "./README.md"
+	validPlatform.Workflows = []sdk.WorkflowMetadata{{Name: "provision", File: "./provision.yaml", Docs: "/etc/passwd"}}
+	if err := validPlatform.Validate(); err == nil {
+		t.Error("Expected absolute workflow docs path to fail validation")
+	}
+	validPlatform.Workflows[0].Docs = "./docs/provision.md"
+	validPlatform.Workflows[0].Examples = []string{"./examples/app.yaml"}
+	if err := validPlatform.Validate(); err != nil {
+		t.Errorf("Expected local docs paths to pass validation, got error: %v", err)
+	}
+}
+
+func TestResourceTypeDeprecation(t *testing.T) {
+	provider := &sdk.Provider{
+		APIVersion:    "v1",
+		Kind:          "Provider",
+		Metadata:      sdk.ProviderMetadata{Name: "database-team", Version: "1.0.0"},
+		Compatibility: sdk.ProviderCompatibility{MinCoreVersion: "1.0.0"},
+		Workflows:     []sdk.WorkflowMetadata{{Name: "provision-postgres", File: "./workflows/provision-postgres.yaml"}},
+		Capabilities: sdk.ProviderCapabilities{
+			ResourceTypeCapabilities: []sdk.ResourceTypeCapability{
+				{Type: "postgres"},
+				{Type: "postgresql", AliasFor: "postgres", Deprecated: &sdk.ResourceTypeDeprecation{RemoveAfter: "2026-12-31"}},
+			},
+		},
+	}
+
+	deprecation := provider.ResourceTypeDeprecation("postgresql")
+	if deprecation == nil || deprecation.Replacement != "postgres" {
+		t.Fatalf("Expected deprecation with replacement 'postgres', got %+v", deprecation)
+	}
+	if provider.ResourceTypeDeprecation("postgres") != nil {
+		t.Error("Expected canonical type not to be deprecated")
+	}
+	if deprecation.Expired(time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC)) {
+		t.Error("Expected deprecation not to expire on the removeAfter date")
+	}
+	if !deprecation.Expired(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Expected deprecation to expire the day after removeAfter")
+	}
+
+	if err := provider.Validate(); err != nil {
+		t.Errorf("Expected deprecated alias to pass validation, got error: %v", err)
+	}
+	provider.Capabilities.ResourceTypeCapabilities[1].Deprecated.RemoveAfter = "end of year"
+	if err := provider.Validate(); err == nil {
+		t.Error("Expected invalid removeAfter date to fail validation")
+	}
 }
 
 func TestPlatformProvisionerLookup(t *testing.T) {
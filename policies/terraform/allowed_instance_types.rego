@@ -0,0 +1,32 @@
+# Restrict compute and database sizes to the approved catalogue.
+package terraform.allowed_instance_types
+
+import rego.v1
+
+allowed_instance_types := {"t3.micro", "t3.small", "t3.medium", "m5.large"}
+
+allowed_db_instance_classes := {"db.t3.micro", "db.t3.small", "db.t3.medium"}
+
+deny contains violation if {
+	some rc in input.resource_changes
+	rc.type == "aws_instance"
+	instance_type := rc.change.after.instance_type
+	not instance_type in allowed_instance_types
+	violation := {
+		"address": rc.address,
+		"path": "instance_type",
+		"msg": sprintf("instance type '%s' is not allowed (allowed: %s)", [instance_type, concat(", ", sort(allowed_instance_types))]),
+	}
+}
+
+deny contains violation if {
+	some rc in input.resource_changes
+	rc.type == "aws_db_instance"
+	instance_class := rc.change.after.instance_class
+	not instance_class in allowed_db_instance_classes
+	violation := {
+		"address": rc.address,
+		"path": "instance_class",
+		"msg": sprintf("instance class '%s' is not allowed (allowed: %s)", [instance_class, concat(", ", sort(allowed_db_instance_classes))]),
+	}
+}
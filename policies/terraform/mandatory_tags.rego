@@ -0,0 +1,18 @@
+# Require ownership tags on every taggable resource that is created or updated.
+package terraform.mandatory_tags
+
+import rego.v1
+
+required_tags := {"team", "application", "environment"}
+
+deny contains violation if {
+	some rc in input.resource_changes
+	"tags" in object.keys(rc.change.after)
+	some tag in required_tags
+	not rc.change.after.tags[tag]
+	violation := {
+		"address": rc.address,
+		"path": sprintf("tags.%s", [tag]),
+		"msg": sprintf("missing mandatory tag '%s'", [tag]),
+	}
+}
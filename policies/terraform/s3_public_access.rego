@@ -0,0 +1,31 @@
+# Deny S3 buckets that are readable or writable by the public.
+package terraform.s3_public_access
+
+import rego.v1
+
+public_acls := {"public-read", "public-read-write", "authenticated-read"}
+
+public_access_settings := ["block_public_acls", "block_public_policy", "ignore_public_acls", "restrict_public_buckets"]
+
+deny contains violation if {
+	some rc in input.resource_changes
+	rc.type in {"aws_s3_bucket", "aws_s3_bucket_acl"}
+	rc.change.after.acl in public_acls
+	violation := {
+		"address": rc.address,
+		"path": "acl",
+		"msg": sprintf("ACL '%s' makes the bucket public", [rc.change.after.acl]),
+	}
+}
+
+deny contains violation if {
+	some rc in input.resource_changes
+	rc.type == "aws_s3_bucket_public_access_block"
+	some setting in public_access_settings
+	rc.change.after[setting] == false
+	violation := {
+		"address": rc.address,
+		"path": setting,
+		"msg": sprintf("%s must be true", [setting]),
+	}
+}
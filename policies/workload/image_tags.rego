@@ -0,0 +1,41 @@
+# Require images pinned to a tag or digest other than 'latest', in the Score spec and in
+# the rendered manifests.
+package workload.image_tags
+
+import rego.v1
+
+unpinned(image) if endswith(image, ":latest")
+
+unpinned(image) if {
+	not contains(image, "@")
+	parts := split(image, "/")
+	not contains(parts[count(parts) - 1], ":")
+}
+
+pod_spec(object) := object.spec.template.spec if object.kind in {"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job"}
+
+pod_spec(object) := object.spec.jobTemplate.spec.template.spec if object.kind == "CronJob"
+
+pod_spec(object) := object.spec if object.kind == "Pod"
+
+deny contains violation if {
+	some name, container in input.spec.containers
+	container.image != "."
+	unpinned(container.image)
+	violation := {
+		"address": sprintf("score/%s", [input.application]),
+		"path": sprintf("containers.%s.image", [name]),
+		"msg": sprintf("image '%s' is not pinned to a version", [container.image]),
+	}
+}
+
+deny contains violation if {
+	some object in input.manifests
+	some container in pod_spec(object).containers
+	unpinned(container.image)
+	violation := {
+		"address": sprintf("%s/%s", [object.kind, object.metadata.name]),
+		"path": sprintf("containers.%s.image", [container.name]),
+		"msg": sprintf("image '%s' is not pinned to a version", [container.image]),
+	}
+}
@@ -0,0 +1,32 @@
+# Deny privileged containers and host namespaces in the rendered workloads.
+package workload.privileged
+
+import rego.v1
+
+pod_spec(object) := object.spec.template.spec if object.kind in {"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job"}
+
+pod_spec(object) := object.spec.jobTemplate.spec.template.spec if object.kind == "CronJob"
+
+pod_spec(object) := object.spec if object.kind == "Pod"
+
+deny contains violation if {
+	some object in input.manifests
+	some container in pod_spec(object).containers
+	container.securityContext.privileged == true
+	violation := {
+		"address": sprintf("%s/%s", [object.kind, object.metadata.name]),
+		"path": sprintf("containers.%s.securityContext.privileged", [container.name]),
+		"msg": sprintf("container '%s' must not run privileged", [container.name]),
+	}
+}
+
+deny contains violation if {
+	some object in input.manifests
+	some field in ["hostNetwork", "hostPID", "hostIPC"]
+	pod_spec(object)[field] == true
+	violation := {
+		"address": sprintf("%s/%s", [object.kind, object.metadata.name]),
+		"path": field,
+		"msg": sprintf("%s is not allowed", [field]),
+	}
+}
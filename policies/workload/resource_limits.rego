@@ -0,0 +1,24 @@
+# Require CPU and memory limits on every container of the rendered workloads.
+package workload.resource_limits
+
+import rego.v1
+
+required_limits := {"cpu", "memory"}
+
+pod_spec(object) := object.spec.template.spec if object.kind in {"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job"}
+
+pod_spec(object) := object.spec.jobTemplate.spec.template.spec if object.kind == "CronJob"
+
+pod_spec(object) := object.spec if object.kind == "Pod"
+
+deny contains violation if {
+	some object in input.manifests
+	some container in pod_spec(object).containers
+	some limit in required_limits
+	not container.resources.limits[limit]
+	violation := {
+		"address": sprintf("%s/%s", [object.kind, object.metadata.name]),
+		"path": sprintf("containers.%s.resources.limits.%s", [container.name, limit]),
+		"msg": sprintf("container '%s' has no %s limit", [container.name, limit]),
+	}
+}
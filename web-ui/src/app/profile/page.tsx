@@ -8,6 +8,7 @@ import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card';
 import { Badge } from '@/components/ui/badge';
 import { Users, User, Shield } from 'lucide-react';
 import SecurityTab from '@/components/profile/security-tab';
+import UsageTab from '@/components/profile/usage-tab';
 
 export default function ProfilePage() {
   const [profile, setProfile] = useState<UserProfile | null>(null);
@@ -73,6 +74,7 @@ export default function ProfilePage() {
         <Tabs defaultValue="overview" className="space-y-4">
           <TabsList>
             <TabsTrigger value="overview">Overview</TabsTrigger>
+            <TabsTrigger value="usage">Team Usage</TabsTrigger>
             <TabsTrigger value="security">Security</TabsTrigger>
           </TabsList>
 
@@ -159,6 +161,8 @@ export default function ProfilePage() {
             </Card>
           </TabsContent>
 
+          <TabsContent value="usage">{profile?.team && <UsageTab team={profile.team} />}</TabsContent>
+
           <TabsContent value="security">
             <SecurityTab />
           </TabsContent>
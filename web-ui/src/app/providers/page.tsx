@@ -21,8 +21,11 @@ import {
   ChevronDown,
   ChevronRight,
   Tag,
+  BookOpen,
 } from 'lucide-react';
-import { api, type ProviderSummary, type ProviderStats } from '@/lib/api';
+import ReactMarkdown from 'react-markdown';
+import remarkGfm from 'remark-gfm';
+import { api, type ProviderSummary, type ProviderStats, type ProviderDocs } from '@/lib/api';
 
 export default function ProvidersPage() {
   const [providers, setProviders] = useState<ProviderSummary[]>([]);
@@ -30,6 +33,8 @@ export default function ProvidersPage() {
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [selectedProvider, setSelectedProvider] = useState<ProviderSummary | null>(null);
+  const [docs, setDocs] = useState<ProviderDocs | null>(null);
+  const [docsError, setDocsError] = useState<string | null>(null);
 
   const fetchProviders = async () => {
     setLoading(true);
@@ -61,6 +66,20 @@ export default function ProvidersPage() {
     fetchProviders();
   }, []);
 
+  useEffect(() => {
+    setDocs(null);
+    setDocsError(null);
+    if (!selectedProvider) return;
+
+    api.getProviderDocs(selectedProvider.name).then((response) => {
+      if (response.success && response.data) {
+        setDocs(response.data);
+      } else {
+        setDocsError(response.error || 'Failed to fetch provider documentation');
+      }
+    });
+  }, [selectedProvider]);
+
   return (
     <ProtectedRoute>
       <div className="container mx-auto py-8 px-4">
@@ -461,6 +480,32 @@ export default function ProvidersPage() {
           )}
         </div>
 
+        {/* Provider Documentation */}
+        {selectedProvider && (
+          <Card className="mt-6">
+            <CardHeader>
+              <CardTitle className="flex items-center gap-2">
+                <BookOpen className="w-5 h-5 text-purple-500" />
+                {selectedProvider.name} Documentation
+              </CardTitle>
+              <CardDescription>
+                Configuration parameters, outputs and examples of each provisioner
+              </CardDescription>
+            </CardHeader>
+            <CardContent>
+              {docsError && <p className="text-sm text-red-600 dark:text-red-400">{docsError}</p>}
+              {!docs && !docsError && (
+                <p className="text-sm text-gray-500 dark:text-gray-400">Loading documentation...</p>
+              )}
+              {docs && (
+                <div className="prose prose-sm dark:prose-invert max-w-none">
+                  <ReactMarkdown remarkPlugins={[remarkGfm]}>{docs.markdown}</ReactMarkdown>
+                </div>
+              )}
+            </CardContent>
+          </Card>
+        )}
+
         {/* Information Card */}
         <Card className="mt-6">
           <CardHeader>
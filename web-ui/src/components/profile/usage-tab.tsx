@@ -0,0 +1,225 @@
+'use client';
+
+import { useEffect, useState } from 'react';
+import { api, TeamUsage } from '@/lib/api';
+import { Card, CardContent, CardHeader, CardTitle } from '@/components/ui/card';
+import { Badge } from '@/components/ui/badge';
+import { Activity, Database, Gauge, HardDrive, TrendingUp } from 'lucide-react';
+
+const TREND_DAYS = [7, 14, 30, 90];
+
+function formatBytes(bytes: number): string {
+  const units = ['B', 'KiB', 'MiB', 'GiB', 'TiB'];
+  let value = bytes;
+  let unit = 0;
+  while (value >= 1024 && unit < units.length - 1) {
+    value /= 1024;
+    unit++;
+  }
+  return unit === 0 ? `${value} B` : `${value.toFixed(1)} ${units[unit]}`;
+}
+
+export default function UsageTab({ team }: { team: string }) {
+  const [usage, setUsage] = useState<TeamUsage | null>(null);
+  const [days, setDays] = useState(14);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    const loadUsage = async () => {
+      setLoading(true);
+      setError(null);
+
+      const response = await api.getTeamUsage(team, days);
+      if (response.success && response.data) {
+        setUsage(response.data);
+      } else {
+        setError(response.error || 'Failed to load team usage');
+      }
+
+      setLoading(false);
+    };
+    loadUsage();
+  }, [team, days]);
+
+  if (loading && !usage) {
+    return (
+      <div className="text-center py-8 text-gray-600 dark:text-gray-400">Loading usage...</div>
+    );
+  }
+
+  if (error || !usage) {
+    return <div className="text-center py-8 text-red-600 dark:text-red-400">{error}</div>;
+  }
+
+  const maxRuns = Math.max(1, ...usage.trend.map((point) => point.workflow_runs));
+
+  return (
+    <div className="space-y-6">
+      {/* Quotas */}
+      <Card>
+        <CardHeader>
+          <CardTitle className="text-xl flex items-center gap-2">
+            <Gauge className="w-5 h-5" />
+            Quotas
+            {usage.organization && (
+              <Badge variant="outline" className="ml-2">
+                {usage.organization}
+              </Badge>
+            )}
+          </CardTitle>
+        </CardHeader>
+        <CardContent className="space-y-4">
+          {usage.quotas.map((quota) => {
+            const percent = quota.limit > 0 ? Math.min(100, (quota.used * 100) / quota.limit) : 0;
+            return (
+              <div key={quota.name}>
+                <div className="flex justify-between text-sm mb-1">
+                  <span className="capitalize text-gray-900 dark:text-gray-100">{quota.name}</span>
+                  <span className="text-muted-foreground">
+                    {quota.used} of {quota.limit > 0 ? quota.limit : 'unlimited'}
+                  </span>
+                </div>
+                {quota.limit > 0 && (
+                  <div className="h-2 rounded bg-gray-200 dark:bg-gray-700">
+                    <div
+                      className={`h-2 rounded ${percent >= 90 ? 'bg-red-600' : 'bg-blue-600'}`}
+                      style={{ width: `${percent}%` }}
+                    />
+                  </div>
+                )}
+              </div>
+            );
+          })}
+        </CardContent>
+      </Card>
+
+      <div className="grid grid-cols-2 gap-6">
+        {/* Resources */}
+        <Card>
+          <CardHeader>
+            <CardTitle className="text-xl flex items-center gap-2">
+              <Database className="w-5 h-5" />
+              Resources ({usage.resources})
+            </CardTitle>
+          </CardHeader>
+          <CardContent className="space-y-2">
+            {usage.resources_by_type.length === 0 && (
+              <p className="text-sm text-muted-foreground">No resources</p>
+            )}
+            {usage.resources_by_type.map((resourceType) => (
+              <div key={resourceType.type} className="flex justify-between text-sm">
+                <span className="font-medium text-gray-900 dark:text-gray-100">
+                  {resourceType.type}
+                </span>
+                <span className="text-muted-foreground">
+                  {resourceType.total} ({resourceType.active} active
+                  {resourceType.failed > 0 && `, ${resourceType.failed} failed`})
+                </span>
+              </div>
+            ))}
+          </CardContent>
+        </Card>
+
+        {/* Storage */}
+        <Card>
+          <CardHeader>
+            <CardTitle className="text-xl flex items-center gap-2">
+              <HardDrive className="w-5 h-5" />
+              Storage ({formatBytes(usage.storage.total_bytes)})
+            </CardTitle>
+          </CardHeader>
+          <CardContent className="space-y-2 text-sm">
+            <div className="flex justify-between">
+              <span>Workflow logs</span>
+              <span className="text-muted-foreground">{formatBytes(usage.storage.logs_bytes)}</span>
+            </div>
+            <div className="flex justify-between">
+              <span>Workspaces</span>
+              <span className="text-muted-foreground">
+                {formatBytes(usage.storage.workspace_bytes)}
+              </span>
+            </div>
+            <div className="flex justify-between">
+              <span>Uploaded files</span>
+              <span className="text-muted-foreground">{formatBytes(usage.storage.files_bytes)}</span>
+            </div>
+          </CardContent>
+        </Card>
+      </div>
+
+      {/* Running workflows */}
+      <Card>
+        <CardHeader>
+          <CardTitle className="text-xl flex items-center gap-2">
+            <Activity className="w-5 h-5" />
+            Running Workflows ({usage.active_workflows.length})
+          </CardTitle>
+        </CardHeader>
+        <CardContent className="space-y-2">
+          {usage.active_workflows.length === 0 && (
+            <p className="text-sm text-muted-foreground">No workflows running</p>
+          )}
+          {usage.active_workflows.map((workflow) => (
+            <div key={workflow.id} className="flex justify-between text-sm">
+              <span className="text-gray-900 dark:text-gray-100">
+                #{workflow.id} {workflow.application_name}/{workflow.workflow_name}
+              </span>
+              <span className="text-muted-foreground">
+                {workflow.completed_steps}/{workflow.total_steps} steps
+              </span>
+            </div>
+          ))}
+        </CardContent>
+      </Card>
+
+      {/* Trend */}
+      <Card>
+        <CardHeader>
+          <CardTitle className="text-xl flex items-center justify-between">
+            <span className="flex items-center gap-2">
+              <TrendingUp className="w-5 h-5" />
+              Trend
+            </span>
+            <select
+              value={days}
+              onChange={(e) => setDays(Number(e.target.value))}
+              className="text-sm font-normal border rounded px-2 py-1 bg-white dark:bg-gray-800"
+            >
+              {TREND_DAYS.map((n) => (
+                <option key={n} value={n}>
+                  Last {n} days
+                </option>
+              ))}
+            </select>
+          </CardTitle>
+        </CardHeader>
+        <CardContent>
+          <div className="flex items-end gap-1 h-32">
+            {usage.trend.map((point) => (
+              <div
+                key={point.date}
+                className="flex-1 flex flex-col justify-end h-full"
+                title={`${point.date}: ${point.workflow_runs} workflow runs (${point.failed_workflow_runs} failed), ${point.active_resources} active resources, ${point.resource_hours} resource hours`}
+              >
+                <div
+                  className="bg-red-500"
+                  style={{ height: `${(point.failed_workflow_runs * 100) / maxRuns}%` }}
+                />
+                <div
+                  className="bg-blue-600"
+                  style={{
+                    height: `${((point.workflow_runs - point.failed_workflow_runs) * 100) / maxRuns}%`,
+                  }}
+                />
+              </div>
+            ))}
+          </div>
+          <p className="text-xs text-muted-foreground mt-2">
+            Workflow runs per day, failed runs in red. Hover a day for resource hours.
+          </p>
+        </CardContent>
+      </Card>
+    </div>
+  );
+}
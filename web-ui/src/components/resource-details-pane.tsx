@@ -26,6 +26,8 @@ import {
   Activity,
   AlertCircle,
   CheckCircle2,
+  ChartColumn,
+  ScrollText,
 } from 'lucide-react';
 import { ResourceInstance } from '@/lib/api';
 import { formatAsYAML } from '@/lib/formatters';
@@ -46,8 +48,17 @@ interface ResourceHint {
   value: string;
   type?: string;
   icon?: string;
+  query?: string;
+  height?: number;
 }
 
+// Hint types whose value is opened in a new tab instead of copied
+const LINK_HINT_TYPES = ['url', 'dashboard', 'grafana_panel', 'log_query', 'metrics_query'];
+
+const isLinkHint = (hint: ResourceHint) => LINK_HINT_TYPES.includes(hint.type || '');
+
+const DEFAULT_PANEL_HEIGHT = 300;
+
 const STATE_CONFIG = {
   active: {
     badgeVariant: 'default' as BadgeVariant,
@@ -112,6 +123,8 @@ const getHintIcon = (icon?: string) => {
     lock: <Lock className={iconClass} />,
     'external-link': <ExternalLink className={iconClass} />,
     globe: <Globe className={iconClass} />,
+    chart: <ChartColumn className={iconClass} />,
+    logs: <ScrollText className={iconClass} />,
   };
   return icons[icon || ''] || <Link2 className={iconClass} />;
 };
@@ -159,7 +172,7 @@ interface QuickAccessCardProps {
 }
 
 function QuickAccessCard({ hint, onHintClick, isCopied }: QuickAccessCardProps) {
-  const isUrl = hint.type === 'url' || hint.type === 'dashboard';
+  const isUrl = isLinkHint(hint);
 
   return (
     <Card
@@ -173,9 +186,15 @@ function QuickAccessCard({ hint, onHintClick, isCopied }: QuickAccessCardProps)
         {getHintIcon(hint.icon)}
       </CardHeader>
       <CardContent className="relative z-10">
-        <div className="text-xs font-mono text-gray-600 dark:text-gray-400 break-all pr-8">
-          {hint.value}
-        </div>
+        {hint.query ? (
+          <pre className="text-xs font-mono text-gray-600 dark:text-gray-400 whitespace-pre-wrap break-all pr-8">
+            {hint.query}
+          </pre>
+        ) : (
+          <div className="text-xs font-mono text-gray-600 dark:text-gray-400 break-all pr-8">
+            {hint.value}
+          </div>
+        )}
         {isCopied && (
           <div className="absolute top-2 right-2 flex items-center gap-1 bg-green-100 dark:bg-green-900 text-green-800 dark:text-green-200 px-2 py-1 rounded text-xs">
             <CheckCheck className="w-3 h-3" />
@@ -194,6 +213,41 @@ function QuickAccessCard({ hint, onHintClick, isCopied }: QuickAccessCardProps)
   );
 }
 
+interface DashboardPanelCardProps {
+  hint: ResourceHint;
+}
+
+// DashboardPanelCard embeds a Grafana panel (d-solo URL) inline
+function DashboardPanelCard({ hint }: DashboardPanelCardProps) {
+  return (
+    <Card className="overflow-hidden border shadow-sm bg-white dark:bg-gray-800">
+      <CardHeader className="flex flex-row items-center justify-between space-y-0 pb-2">
+        <CardTitle className="text-sm font-medium text-gray-700 dark:text-gray-300">
+          {hint.label}
+        </CardTitle>
+        <a
+          href={hint.value}
+          target="_blank"
+          rel="noopener noreferrer"
+          className="text-gray-400 hover:text-gray-600 dark:hover:text-gray-200"
+          title="Open in Grafana"
+        >
+          <ExternalLink className="w-4 h-4" />
+        </a>
+      </CardHeader>
+      <CardContent className="p-0">
+        <iframe
+          src={hint.value}
+          title={hint.label}
+          className="w-full border-0"
+          style={{ height: hint.height || DEFAULT_PANEL_HEIGHT }}
+          loading="lazy"
+        />
+      </CardContent>
+    </Card>
+  );
+}
+
 interface ResourceInfoCardProps {
   resource: ResourceInstance;
 }
@@ -415,7 +469,7 @@ export function ResourceDetailsPane({ resource, onClose }: ResourceDetailsPanePr
   }
 
   const handleHintClick = (hint: ResourceHint) => {
-    if (hint.type === 'url' || hint.type === 'dashboard') {
+    if (isLinkHint(hint)) {
       window.open(hint.value, '_blank', 'noopener,noreferrer');
     } else {
       copyToClipboard(hint.value, hint.label);
@@ -468,14 +522,18 @@ export function ResourceDetailsPane({ resource, onClose }: ResourceDetailsPanePr
                   Quick Access
                 </h3>
                 <div className="grid grid-cols-1 gap-3">
-                  {resource.hints.map((hint, index) => (
-                    <QuickAccessCard
-                      key={index}
-                      hint={hint}
-                      onHintClick={handleHintClick}
-                      isCopied={copiedHint === hint.label}
-                    />
-                  ))}
+                  {resource.hints.map((hint, index) =>
+                    hint.type === 'grafana_panel' ? (
+                      <DashboardPanelCard key={index} hint={hint} />
+                    ) : (
+                      <QuickAccessCard
+                        key={index}
+                        hint={hint}
+                        onHintClick={handleHintClick}
+                        isCopied={copiedHint === hint.label}
+                      />
+                    )
+                  )}
                 </div>
               </div>
             )}
@@ -127,10 +127,12 @@ export interface DemoStatusResponse {
 }
 
 export interface ResourceHint {
-  type: string; // "url", "connection_string", "dashboard", "docs", "api_endpoint", "git_clone", "command"
+  type: string; // "url", "connection_string", "dashboard", "docs", "api_endpoint", "git_clone", "command", "grafana_panel", "log_query", "metrics_query"
   label: string; // Display name: "Repository URL", "Admin Dashboard", etc.
   value: string; // Actual value: URL, connection string, command, etc.
-  icon?: string; // Optional icon: "external-link", "database", "lock", "terminal", "git-branch"
+  icon?: string; // Optional icon: "external-link", "database", "lock", "terminal", "git-branch", "chart", "logs"
+  query?: string; // Log or metrics query of log_query and metrics_query hints
+  height?: number; // Height in pixels of embedded grafana_panel hints
 }
 
 export interface ResourceInstance {
@@ -366,6 +368,15 @@ class ApiClient {
     return this.request<Application>(`/applications/${name}`);
   }
 
+  async getApplicationStatus(name: string): Promise<ApiResponse<ApplicationStatus>> {
+    return this.request<ApplicationStatus>(`/applications/${encodeURIComponent(name)}/status`);
+  }
+
+  async getTeamUsage(team: string, days?: number): Promise<ApiResponse<TeamUsage>> {
+    const query = days ? `?days=${days}` : '';
+    return this.request<TeamUsage>(`/teams/${encodeURIComponent(team)}/usage${query}`);
+  }
+
   async deployApplication(scoreSpec: string): Promise<ApiResponse<{ message: string }>> {
     return this.request('/applications', {
       method: 'POST',
@@ -376,6 +387,36 @@ class ApiClient {
     });
   }
 
+  // Deploys a Score spec with auxiliary files (values.yaml, .env templates) that workflows
+  // can reference as ${files.<name>}. The browser sets the multipart boundary header.
+  async deployApplicationWithFiles(
+    scoreSpec: string | File,
+    files: File[]
+  ): Promise<ApiResponse<{ message: string }>> {
+    const form = new FormData();
+    form.append('spec', scoreSpec);
+    files.forEach((file) => form.append('files', file, file.name));
+
+    try {
+      const token = this.getAuthToken();
+      const response = await fetch(`${API_BASE_URL}/applications`, {
+        method: 'POST',
+        headers: token ? { Authorization: `Bearer ${token}` } : {},
+        credentials: 'include',
+        body: form,
+      });
+      if (!response.ok) {
+        return { success: false, error: (await response.text()) || `HTTP ${response.status}` };
+      }
+      return { success: true, data: await response.json() };
+    } catch (error) {
+      return {
+        success: false,
+        error: error instanceof Error ? error.message : 'Unknown error occurred',
+      };
+    }
+  }
+
   async deleteApplication(name: string): Promise<ApiResponse<{ message: string }>> {
     return this.request(`/applications/${name}`, {
       method: 'DELETE',
@@ -388,6 +429,24 @@ class ApiClient {
     });
   }
 
+  async hibernateApplication(
+    name: string,
+    reason?: string
+  ): Promise<ApiResponse<{ application: string; hibernated: boolean; hibernation?: any }>> {
+    return this.request(`/applications/${name}/hibernate`, {
+      method: 'POST',
+      body: JSON.stringify({ reason: reason || '' }),
+    });
+  }
+
+  async resumeApplication(
+    name: string
+  ): Promise<ApiResponse<{ application: string; hibernated: boolean; hibernation?: any }>> {
+    return this.request(`/applications/${name}/resume`, {
+      method: 'POST',
+    });
+  }
+
   // Environments
   async getEnvironments(): Promise<ApiResponse<Record<string, any>>> {
     return this.request<Record<string, any>>('/environments');
@@ -702,6 +761,10 @@ class ApiClient {
     return this.request('/providers/stats');
   }
 
+  async getProviderDocs(name: string): Promise<ApiResponse<ProviderDocs>> {
+    return this.request(`/providers/${encodeURIComponent(name)}/docs`);
+  }
+
   // Alias for deployApplication - used by deploy wizard
   async submitSpec(scoreSpec: string): Promise<ApiResponse<{ message: string }>> {
     return this.deployApplication(scoreSpec);
@@ -777,6 +840,102 @@ export interface ProviderStats {
   provisioners: number;
 }
 
+export interface ProvisionerDocs {
+  name: string;
+  category: string;
+  operation?: string;
+  markdown: string;
+}
+
+export interface ProviderDocs {
+  provider: string;
+  version: string;
+  markdown: string;
+  provisioners: ProvisionerDocs[];
+}
+
+export interface WorkflowExecutionSummary {
+  id: number;
+  application_name: string;
+  workflow_name: string;
+  status: string;
+  started_at: string;
+  completed_at?: string;
+  total_steps: number;
+  completed_steps: number;
+  failed_steps: number;
+  duration_ms?: number;
+}
+
+export interface ApplicationStatus {
+  name: string;
+  team: string;
+  latest_deployment: {
+    deployed_at: string;
+    deployed_by: string;
+    status: string;
+    workflow?: WorkflowExecutionSummary;
+    provenance?: { id: number; kind: string; digest: string; created_at: string };
+  };
+  resources: {
+    id: number;
+    name: string;
+    type: string;
+    state: string;
+    health_status?: string;
+    error_message?: string;
+    hints?: ResourceHint[];
+    updated_at: string;
+  }[];
+  active_workflows: WorkflowExecutionSummary[];
+  health: {
+    status: 'healthy' | 'degraded' | 'unknown';
+    resources: number;
+    healthy: number;
+    failing_resources: number;
+  };
+  recent_events: {
+    time: string;
+    type: 'workflow' | 'resource' | 'approval';
+    subject: string;
+    message: string;
+    severity: 'info' | 'warning' | 'error';
+  }[];
+  pending_approvals: {
+    id: number;
+    execution_id: number;
+    step_name: string;
+    application_name: string;
+    status: string;
+    requested_at: string;
+  }[];
+  generated_at: string;
+}
+
+export interface TeamUsage {
+  team: string;
+  organization?: string;
+  applications: number;
+  resources: number;
+  resources_by_type: { type: string; total: number; active: number; failed: number }[];
+  active_workflows: WorkflowExecutionSummary[];
+  storage: {
+    logs_bytes: number;
+    workspace_bytes: number;
+    files_bytes: number;
+    total_bytes: number;
+  };
+  quotas: { name: string; used: number; limit: number }[];
+  trend: {
+    date: string;
+    active_resources: number;
+    resource_hours: number;
+    workflow_runs: number;
+    failed_workflow_runs: number;
+  }[];
+  generated_at: string;
+}
+
 export const api = new ApiClient();
 
 /**